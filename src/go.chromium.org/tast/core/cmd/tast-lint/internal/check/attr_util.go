@@ -28,7 +28,7 @@ func checkAttr(fs *token.FileSet, f *ast.File, checker testAttrChecker) []*Issue
 				continue
 			}
 			call := estmt.X.(*ast.CallExpr)
-			if len(call.Args) != 1 {
+			if len(call.Args) == 0 {
 				// This should be checked by a compiler, so skipped.
 				continue
 			}
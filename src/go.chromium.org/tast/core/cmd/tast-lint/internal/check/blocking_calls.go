@@ -0,0 +1,116 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package check
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+const blockingCallsURL = "https://chromium.googlesource.com/chromiumos/platform/tast/+/HEAD/docs/code_review_comments.md#Context-timeout"
+
+// blockingCallAlternatives maps functions that may block without honoring a
+// context deadline to the format of their replacements. The format receives
+// the name of the context.Context parameter.
+var blockingCallAlternatives = map[string]string{
+	"exec.Command":                "testexec.CommandContext(%s, ...)",
+	"http.Get":                    "http.NewRequestWithContext(%s, ...)",
+	"http.Head":                   "http.NewRequestWithContext(%s, ...)",
+	"http.Post":                   "http.NewRequestWithContext(%s, ...)",
+	"http.PostForm":               "http.NewRequestWithContext(%s, ...)",
+	"http.NewRequest":             "http.NewRequestWithContext(%s, ...)",
+	"http.DefaultClient.Get":      "http.NewRequestWithContext(%s, ...)",
+	"http.DefaultClient.Head":     "http.NewRequestWithContext(%s, ...)",
+	"http.DefaultClient.Post":     "http.NewRequestWithContext(%s, ...)",
+	"http.DefaultClient.PostForm": "http.NewRequestWithContext(%s, ...)",
+}
+
+// BlockingCalls checks if functions receiving a context.Context call functions
+// that may block without honoring its deadline, e.g. running commands, making
+// HTTP requests or calling D-Bus methods. Such calls hang until the global
+// timeout of a test without telling where it got stuck.
+func BlockingCalls(fs *token.FileSet, f *ast.File) []*Issue {
+	if isUnitTestFile(fs.Position(f.Package).Filename) {
+		return nil
+	}
+
+	var issues []*Issue
+	var funcs []*ast.FuncType // stack of enclosing functions
+
+	astutil.Apply(f, func(c *astutil.Cursor) bool {
+		switch n := c.Node().(type) {
+		case *ast.FuncDecl:
+			funcs = append(funcs, n.Type)
+		case *ast.FuncLit:
+			funcs = append(funcs, n.Type)
+		case *ast.CallExpr:
+			ctx := contextParam(funcs)
+			if ctx == "" {
+				return true
+			}
+			call := toQualifiedName(n.Fun)
+			alt, ok := blockingCallAlternatives[call]
+			if !ok && isDBusCall(n) {
+				call = "D-Bus method call " + dbusMethodName(n)
+				alt, ok = "CallWithContext(%s, ...)", true
+			}
+			if !ok {
+				return true
+			}
+			issues = append(issues, &Issue{
+				Pos:  fs.Position(n.Pos()),
+				Msg:  fmt.Sprintf("%s may block beyond the deadline of %s; use %s instead", call, ctx, fmt.Sprintf(alt, ctx)),
+				Link: blockingCallsURL,
+			})
+		}
+		return true
+	}, func(c *astutil.Cursor) bool {
+		switch c.Node().(type) {
+		case *ast.FuncDecl, *ast.FuncLit:
+			funcs = funcs[:len(funcs)-1]
+		}
+		return true
+	})
+
+	return issues
+}
+
+// isDBusCall returns true if call looks like a call of the Call method of
+// dbus.BusObject, e.g. obj.Call("org.chromium.Foo.Bar", 0, args...).
+func isDBusCall(call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Call" || len(call.Args) < 2 {
+		return false
+	}
+	if dbusMethodName(call) == "" {
+		return false
+	}
+	switch flags := call.Args[1].(type) {
+	case *ast.BasicLit:
+		return flags.Kind == token.INT
+	default:
+		return strings.HasPrefix(toQualifiedName(flags), "dbus.Flag")
+	}
+}
+
+// dbusMethodName returns the method name passed to a D-Bus call as a string
+// literal. If the name is not a string literal of a qualified name, it returns
+// an empty string.
+func dbusMethodName(call *ast.CallExpr) string {
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return ""
+	}
+	name, err := strconv.Unquote(lit.Value)
+	if err != nil || !strings.Contains(name, ".") {
+		return ""
+	}
+	return name
+}
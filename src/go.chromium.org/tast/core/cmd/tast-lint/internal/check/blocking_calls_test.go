@@ -0,0 +1,72 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package check
+
+import (
+	"testing"
+)
+
+func TestBlockingCalls(t *testing.T) {
+	const code = `package pkg
+
+import (
+	"context"
+	"net/http"
+	"os/exec"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func Run(ctx context.Context, obj dbus.BusObject) error {
+	if err := exec.Command("true").Run(); err != nil {
+		return err
+	}
+	if _, err := http.Get("http://localhost/"); err != nil {
+		return err
+	}
+	if _, err := http.DefaultClient.Post("http://localhost/", "text/plain", nil); err != nil {
+		return err
+	}
+	go func() {
+		obj.Call("org.chromium.Foo.Bar", 0, "arg")
+	}()
+	obj.Call("org.chromium.Foo.Bar", dbus.FlagNoAutoStart)
+	obj.CallWithContext(ctx, "org.chromium.Foo.Bar", 0)
+	cache.Call("key", 0)
+	return nil
+}
+
+func helper() {
+	exec.Command("true").Run()
+}
+`
+	expects := []string{
+		"testfile.go:12:12: exec.Command may block beyond the deadline of ctx; use testexec.CommandContext(ctx, ...) instead",
+		"testfile.go:15:15: http.Get may block beyond the deadline of ctx; use http.NewRequestWithContext(ctx, ...) instead",
+		"testfile.go:18:15: http.DefaultClient.Post may block beyond the deadline of ctx; use http.NewRequestWithContext(ctx, ...) instead",
+		"testfile.go:24:2: D-Bus method call org.chromium.Foo.Bar may block beyond the deadline of ctx; use CallWithContext(ctx, ...) instead",
+	}
+
+	f, fs := parse(code, "testfile.go")
+	issues := BlockingCalls(fs, f)
+	verifyIssues(t, issues, expects)
+}
+
+func TestBlockingCallsUnitTest(t *testing.T) {
+	const code = `package pkg
+
+import (
+	"context"
+	"os/exec"
+)
+
+func foo(ctx context.Context) {
+	exec.Command("true").Run()
+}
+`
+	f, fs := parse(code, "foo_test.go")
+	issues := BlockingCalls(fs, f)
+	verifyIssues(t, issues, nil)
+}
@@ -0,0 +1,44 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package check
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"go.chromium.org/tast/core/internal/testing"
+)
+
+// VerifyKnownAttrs checks if a test's Attr and ExtraAttr only contain groups
+// and attributes registered in internal/testing/attr.go, so that typos are
+// caught before the test silently stops being scheduled.
+func VerifyKnownAttrs(fs *token.FileSet, f *ast.File) []*Issue {
+	return checkAttr(fs, f, knownAttrsChecker)
+}
+
+func knownAttrsChecker(attrs []string, attrPos token.Position, requirements []string, requirementPos token.Position) []*Issue {
+	var issues []*Issue
+	seen := make(map[string]struct{})
+	for _, attr := range attrs {
+		if _, ok := seen[attr]; ok || testing.IsKnownAttr(attr) {
+			continue
+		}
+		seen[attr] = struct{}{}
+		var msg string
+		if strings.HasPrefix(attr, "group:") {
+			msg = fmt.Sprintf("Group %q is unknown; see go.chromium.org/tast/core/internal/testing/attr.go for the full list of valid groups", strings.TrimPrefix(attr, "group:"))
+		} else {
+			msg = fmt.Sprintf("Attribute %q is unknown; see go.chromium.org/tast/core/internal/testing/attr.go for the full list of valid attributes", attr)
+		}
+		issues = append(issues, &Issue{
+			Pos:  attrPos,
+			Msg:  msg,
+			Link: testAttrDocURL,
+		})
+	}
+	return issues
+}
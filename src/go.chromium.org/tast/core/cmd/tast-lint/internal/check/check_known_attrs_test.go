@@ -0,0 +1,66 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package check
+
+import (
+	"testing"
+)
+
+func TestKnownAttrs(t *testing.T) {
+	const code = `package main
+func init() {
+	testing.AddTest(&testing.Test{
+		Func: Pass,
+		Attr: []string{"group:mainline", "informational"},
+	})
+}
+`
+	const path = "/src/go.chromium.org/tast-tests/cros/local/bundles/cros/example/pass.go"
+	f, fs := parse(code, path)
+	issues := VerifyKnownAttrs(fs, f)
+	verifyIssues(t, issues, nil)
+}
+
+func TestKnownAttrsTypos(t *testing.T) {
+	const code = `package main
+func init() {
+	testing.AddTest(&testing.Test{
+		Func: Pass,
+		Attr: []string{"group:graphics", "graphics_nighty", "group:mainlin"},
+	})
+}
+`
+	const path = "/src/go.chromium.org/tast-tests/cros/local/bundles/cros/example/pass.go"
+	f, fs := parse(code, path)
+	issues := VerifyKnownAttrs(fs, f)
+	verifyIssues(t, issues, []string{
+		path + `:5:3: Attribute "graphics_nighty" is unknown; see go.chromium.org/tast/core/internal/testing/attr.go for the full list of valid attributes`,
+		path + `:5:3: Group "mainlin" is unknown; see go.chromium.org/tast/core/internal/testing/attr.go for the full list of valid groups`,
+	})
+}
+
+func TestKnownAttrsParams(t *testing.T) {
+	const code = `package main
+func init() {
+	testing.AddTest(&testing.Test{
+		Func: Pass,
+		Attr: []string{"group:graphics"},
+		Params: []testing.Param{{
+			Name:      "nightly",
+			ExtraAttr: []string{"graphics_nightly"},
+		}, {
+			Name:      "weekly",
+			ExtraAttr: []string{"graphics_weekl"},
+		}},
+	})
+}
+`
+	const path = "/src/go.chromium.org/tast-tests/cros/local/bundles/cros/example/pass.go"
+	f, fs := parse(code, path)
+	issues := VerifyKnownAttrs(fs, f)
+	verifyIssues(t, issues, []string{
+		path + `:11:4: Attribute "graphics_weekl" is unknown; see go.chromium.org/tast/core/internal/testing/attr.go for the full list of valid attributes`,
+	})
+}
@@ -0,0 +1,167 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package check
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"time"
+
+	"golang.org/x/exp/slices"
+)
+
+const (
+	promotionTooEarlyMsg = `Test %s was registered %d day(s) ago; informational tests should be stabilized for at least %d day(s) before being promoted to critical.`
+	promotionUnknownMsg  = `Test %s is promoted to critical, but its registration time could not be determined: %v`
+)
+
+// RegistrationTimeFunc returns the time when the line of a file containing a
+// test registration was added, e.g. as reported by git blame.
+type RegistrationTimeFunc func(line int) (time.Time, error)
+
+// mainlineVariant describes attributes of a test or a parameterized test
+// variant relevant to promotion to critical.
+type mainlineVariant struct {
+	informational bool           // true if it is an informational mainline test
+	critical      bool           // true if it is a critical mainline test
+	attrPos       token.Position // position of Attr or ExtraAttr
+	line          int            // line of the testing.AddTest call
+}
+
+// VerifyPromotionPeriod checks that tests promoted from informational to
+// critical in f, compared with oldF which is the same file before the change,
+// have been registered for at least minDays days as of now. registeredAt is
+// called with a line of oldF to look up when a test registration was added.
+func VerifyPromotionPeriod(fs *token.FileSet, f *ast.File, oldFS *token.FileSet, oldF *ast.File,
+	registeredAt RegistrationTimeFunc, now time.Time, minDays int) []*Issue {
+	oldVariants := mainlineVariants(oldFS, oldF)
+
+	var issues []*Issue
+	for name, v := range mainlineVariants(fs, f) {
+		old, ok := oldVariants[name]
+		if !ok || !v.critical || !old.informational {
+			continue
+		}
+		t, err := registeredAt(old.line)
+		if err != nil {
+			issues = append(issues, &Issue{
+				Pos:  v.attrPos,
+				Msg:  fmt.Sprintf(promotionUnknownMsg, name, err),
+				Link: testAttrDocURL,
+			})
+			continue
+		}
+		if days := int(now.Sub(t) / (24 * time.Hour)); days < minDays {
+			issues = append(issues, &Issue{
+				Pos:  v.attrPos,
+				Msg:  fmt.Sprintf(promotionTooEarlyMsg, name, days, minDays),
+				Link: testAttrDocURL,
+			})
+		}
+	}
+	SortIssues(issues)
+	return issues
+}
+
+// mainlineVariants returns attributes of tests registered in f, keyed by the
+// test function name, followed by "." and the parameter name for
+// parameterized tests.
+func mainlineVariants(fs *token.FileSet, f *ast.File) map[string]*mainlineVariant {
+	variants := make(map[string]*mainlineVariant)
+	newVariant := func(attrs []string, attrPos token.Pos, line int) *mainlineVariant {
+		mainline := slices.Contains(attrs, "group:mainline")
+		informational := slices.Contains(attrs, "informational")
+		return &mainlineVariant{
+			informational: mainline && informational,
+			critical:      mainline && !informational,
+			attrPos:       fs.Position(attrPos),
+			line:          line,
+		}
+	}
+
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || fn.Name.Name != "init" {
+			continue
+		}
+		for _, stmt := range fn.Body.List {
+			estmt, ok := stmt.(*ast.ExprStmt)
+			if !ok || !isTestingAddTestCall(estmt.X) {
+				continue
+			}
+			call := estmt.X.(*ast.CallExpr)
+			if len(call.Args) == 0 {
+				continue
+			}
+			arg, ok := call.Args[0].(*ast.UnaryExpr)
+			if !ok || arg.Op != token.AND {
+				continue
+			}
+			comp, ok := arg.X.(*ast.CompositeLit)
+			if !ok {
+				continue
+			}
+			line := fs.Position(call.Pos()).Line
+
+			var funcName string
+			var attrs []string
+			attrPos := comp.Pos()
+			var params *ast.CompositeLit
+			for _, el := range comp.Elts {
+				identName, value, pos, err := decomposeKVNode(el)
+				if err != nil {
+					continue
+				}
+				switch identName {
+				case "Func":
+					if ident, ok := value.(*ast.Ident); ok {
+						funcName = ident.Name
+					}
+				case "Attr":
+					attrs = makeStringSlice(value)
+					attrPos = pos
+				case "Params":
+					params, _ = value.(*ast.CompositeLit)
+				}
+			}
+			if funcName == "" {
+				continue
+			}
+			if params == nil {
+				variants[funcName] = newVariant(attrs, attrPos, line)
+				continue
+			}
+			for _, el := range params.Elts {
+				comp, ok := el.(*ast.CompositeLit)
+				if !ok {
+					continue
+				}
+				var paramName string
+				exAttrs := append([]string(nil), attrs...)
+				exAttrPos := comp.Pos()
+				for _, el := range comp.Elts {
+					identName, value, pos, err := decomposeKVNode(el)
+					if err != nil {
+						continue
+					}
+					switch identName {
+					case "Name":
+						paramName, _ = toString(value)
+					case "ExtraAttr":
+						exAttrs = append(exAttrs, makeStringSlice(value)...)
+						exAttrPos = pos
+					}
+				}
+				name := funcName
+				if paramName != "" {
+					name += "." + paramName
+				}
+				variants[name] = newVariant(exAttrs, exAttrPos, line)
+			}
+		}
+	}
+	return variants
+}
@@ -0,0 +1,145 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package check
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestVerifyPromotionPeriod(t *testing.T) {
+	const path = "/src/go.chromium.org/tast-tests/cros/local/bundles/cros/example/keyboard.go"
+	const oldCode = `package example
+func init() {
+	testing.AddTest(&testing.Test{
+		Func:     Keyboard,
+		Desc:     "Demonstrates injecting keyboard events",
+		Contacts: []string{"tast-owners@google.com"},
+		Attr:     []string{"group:mainline", "informational"},
+	})
+	testing.AddTest(&testing.Test{
+		Func:     Mouse,
+		Desc:     "Demonstrates injecting mouse events",
+		Contacts: []string{"tast-owners@google.com"},
+		Attr:     []string{"group:mainline"},
+		Params: []testing.Param{{
+			Name:      "stable",
+		}, {
+			Name:      "unstable",
+			ExtraAttr: []string{"informational"},
+		}},
+	})
+}
+`
+	const newCode = `package example
+func init() {
+	testing.AddTest(&testing.Test{
+		Func:     Keyboard,
+		Desc:     "Demonstrates injecting keyboard events",
+		Contacts: []string{"tast-owners@google.com"},
+		Attr:     []string{"group:mainline"},
+	})
+	testing.AddTest(&testing.Test{
+		Func:     Mouse,
+		Desc:     "Demonstrates injecting mouse events",
+		Contacts: []string{"tast-owners@google.com"},
+		Attr:     []string{"group:mainline"},
+		Params: []testing.Param{{
+			Name:      "stable",
+		}, {
+			Name:      "unstable",
+		}},
+	})
+}
+`
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name       string
+		registered map[int]time.Time // keyed by line of AddTest in oldCode
+		want       []string
+	}{
+		{
+			name: "old enough",
+			registered: map[int]time.Time{
+				3: now.Add(-30 * 24 * time.Hour),
+				9: now.Add(-14 * 24 * time.Hour),
+			},
+		},
+		{
+			name: "too early",
+			registered: map[int]time.Time{
+				3: now.Add(-30 * 24 * time.Hour),
+				9: now.Add(-3 * 24 * time.Hour),
+			},
+			want: []string{
+				path + ":16:6: Test Mouse.unstable was registered 3 day(s) ago; informational tests should be stabilized for at least 14 day(s) before being promoted to critical.",
+			},
+		},
+		{
+			name: "unknown",
+			registered: map[int]time.Time{
+				9: now.Add(-30 * 24 * time.Hour),
+			},
+			want: []string{
+				path + ":7:3: Test Keyboard is promoted to critical, but its registration time could not be determined: not found",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			oldF, oldFS := parse(oldCode, path)
+			f, fs := parse(newCode, path)
+			registeredAt := func(line int) (time.Time, error) {
+				if t, ok := tc.registered[line]; ok {
+					return t, nil
+				}
+				return time.Time{}, errors.New("not found")
+			}
+			issues := VerifyPromotionPeriod(fs, f, oldFS, oldF, registeredAt, now, 14)
+			verifyIssues(t, issues, tc.want)
+		})
+	}
+}
+
+func TestVerifyPromotionPeriodNoPromotion(t *testing.T) {
+	const path = "/src/go.chromium.org/tast-tests/cros/local/bundles/cros/example/keyboard.go"
+	const oldCode = `package example
+func init() {
+	testing.AddTest(&testing.Test{
+		Func: Keyboard,
+		Attr: []string{"group:crosbolt"},
+	})
+	testing.AddTest(&testing.Test{
+		Func: Mouse,
+		Attr: []string{"group:mainline", "informational"},
+	})
+}
+`
+	const newCode = `package example
+func init() {
+	testing.AddTest(&testing.Test{
+		Func: Keyboard,
+		Attr: []string{"group:mainline"},
+	})
+	testing.AddTest(&testing.Test{
+		Func: Mouse,
+		Attr: []string{"group:mainline", "informational"},
+	})
+	testing.AddTest(&testing.Test{
+		Func: Touch,
+		Attr: []string{"group:mainline"},
+	})
+}
+`
+	oldF, oldFS := parse(oldCode, path)
+	f, fs := parse(newCode, path)
+	registeredAt := func(line int) (time.Time, error) {
+		t.Errorf("registeredAt(%d) called unexpectedly", line)
+		return time.Time{}, errors.New("unexpected call")
+	}
+	issues := VerifyPromotionPeriod(fs, f, oldFS, oldF, registeredAt, time.Now(), 14)
+	verifyIssues(t, issues, nil)
+}
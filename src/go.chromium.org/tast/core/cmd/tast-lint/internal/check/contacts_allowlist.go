@@ -0,0 +1,114 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package check
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strings"
+)
+
+const (
+	noTeamAliasContactMsg = `Contacts field should include at least one team alias listed in %s, not only individuals`
+
+	testOwnershipURL = `https://chromium.googlesource.com/chromiumos/platform/tast/+/HEAD/docs/writing_tests.md#Test-ownership`
+)
+
+// ContactsAllowlist is a list of patterns matching email addresses of team
+// aliases that may own tests.
+type ContactsAllowlist struct {
+	path     string
+	patterns []*regexp.Regexp
+}
+
+// ParseContactsAllowlist parses data read from the allowlist file at path.
+//
+// Each line of the file is a regular expression that should match an entire
+// email address of a team alias, e.g. "chromeos-foo@google\.com" or
+// ".*-eng@google\.com". Empty lines and lines starting with "#" are ignored.
+func ParseContactsAllowlist(path string, data []byte) (*ContactsAllowlist, error) {
+	a := &ContactsAllowlist{path: path}
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for lineNum := 1; sc.Scan(); lineNum++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		re, err := regexp.Compile("^(?:" + line + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid pattern: %v", path, lineNum, err)
+		}
+		a.patterns = append(a.patterns, re)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// isTeamAlias returns whether addr matches any pattern in the allowlist.
+func (a *ContactsAllowlist) isTeamAlias(addr string) bool {
+	for _, re := range a.patterns {
+		if re.MatchString(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ContactsTeamAlias checks that Contacts fields of tests and fixtures
+// registered in f include at least one team alias in allowlist. It does
+// nothing if allowlist is nil.
+func ContactsTeamAlias(fs *token.FileSet, f *ast.File, allowlist *ContactsAllowlist) []*Issue {
+	if allowlist == nil {
+		return nil
+	}
+
+	var issues []*Issue
+	for _, decl := range f.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Recv != nil || fd.Name.Name != "init" {
+			continue
+		}
+		ast.Inspect(fd.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			if name := toQualifiedName(call.Fun); name != "testing.AddTest" && name != "testing.AddFixture" {
+				return true
+			}
+			// Malformed registrations are reported by other checks.
+			fields, is := registeredEntityFields(fs, call)
+			if len(is) > 0 {
+				return false
+			}
+			kv, ok := fields["Contacts"]
+			if !ok {
+				return false
+			}
+			comp, ok := kv.Value.(*ast.CompositeLit)
+			if !ok || len(comp.Elts) == 0 {
+				return false
+			}
+			for _, el := range comp.Elts {
+				if s, ok := toString(el); ok && allowlist.isTeamAlias(s) {
+					return false
+				}
+			}
+			issues = append(issues, &Issue{
+				Pos:  fs.Position(comp.Pos()),
+				Msg:  fmt.Sprintf(noTeamAliasContactMsg, allowlist.path),
+				Link: testOwnershipURL,
+			})
+			return false
+		})
+	}
+	return issues
+}
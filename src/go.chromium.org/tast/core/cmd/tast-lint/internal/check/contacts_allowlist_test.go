@@ -0,0 +1,75 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package check
+
+import (
+	"fmt"
+	"testing"
+)
+
+const contactsAllowlistPath = "contacts_allowlist.txt"
+
+const contactsAllowlistData = `# Team aliases allowed to own tests.
+chromeos-foo@google\.com
+
+.*-eng@google\.com
+`
+
+func TestContactsTeamAlias(t *testing.T) {
+	allowlist, err := ParseContactsAllowlist(contactsAllowlistPath, []byte(contactsAllowlistData))
+	if err != nil {
+		t.Fatal("ParseContactsAllowlist failed: ", err)
+	}
+
+	const code = `package pkg
+
+func init() {
+	testing.AddTest(&testing.Test{
+		Func:     Listed,
+		Contacts: []string{"chromeos-foo@google.com", "me@chromium.org"},
+	})
+	testing.AddTest(&testing.Test{
+		Func:     Pattern,
+		Contacts: []string{"me@chromium.org", "bar-eng@google.com"},
+	})
+	testing.AddTest(&testing.Test{
+		Func:     Individuals,
+		Contacts: []string{"me@chromium.org", "you@google.com"},
+	})
+	testing.AddFixture(&testing.Fixture{
+		Name:     "fixt",
+		Contacts: []string{"chromeos-foo@google.com.evil"},
+	})
+}
+`
+	f, fs := parse(code, declTestPath)
+	issues := ContactsTeamAlias(fs, f, allowlist)
+	msg := fmt.Sprintf(noTeamAliasContactMsg, contactsAllowlistPath)
+	verifyIssues(t, issues, []string{
+		declTestPath + ":14:13: " + msg,
+		declTestPath + ":18:13: " + msg,
+	})
+}
+
+func TestContactsTeamAliasNoAllowlist(t *testing.T) {
+	const code = `package pkg
+
+func init() {
+	testing.AddTest(&testing.Test{
+		Func:     Individuals,
+		Contacts: []string{"me@chromium.org"},
+	})
+}
+`
+	f, fs := parse(code, declTestPath)
+	issues := ContactsTeamAlias(fs, f, nil)
+	verifyIssues(t, issues, nil)
+}
+
+func TestParseContactsAllowlistInvalid(t *testing.T) {
+	if _, err := ParseContactsAllowlist(contactsAllowlistPath, []byte("foo@google.com\n(\n")); err == nil {
+		t.Error("ParseContactsAllowlist succeeded for an invalid pattern")
+	}
+}
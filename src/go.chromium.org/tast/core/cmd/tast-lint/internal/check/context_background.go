@@ -0,0 +1,117 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package check
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+const contextBackgroundURL = "https://chromium.googlesource.com/chromiumos/platform/tast/+/HEAD/docs/code_review_comments.md#Context-propagation"
+
+// ContextBackground checks if context.Background or context.TODO is called in
+// functions receiving a context.Context. Contexts created by them have no
+// deadline, so operations using them ignore timeouts and cancellation of
+// tests. The incoming context should be passed down instead.
+func ContextBackground(fs *token.FileSet, f *ast.File, fix bool) []*Issue {
+	if isUnitTestFile(fs.Position(f.Package).Filename) {
+		return nil
+	}
+
+	var issues []*Issue
+	var funcs []*ast.FuncType // stack of enclosing functions
+
+	astutil.Apply(f, func(c *astutil.Cursor) bool {
+		switch n := c.Node().(type) {
+		case *ast.FuncDecl:
+			funcs = append(funcs, n.Type)
+		case *ast.FuncLit:
+			funcs = append(funcs, n.Type)
+		case *ast.CallExpr:
+			call := toQualifiedName(n.Fun)
+			if call != "context.Background" && call != "context.TODO" {
+				return true
+			}
+			ctx := contextParam(funcs)
+			if ctx == "" {
+				return true
+			}
+			// Replacing "ctx := context.Background()" with "ctx := ctx"
+			// does not compile, so leave such code to humans.
+			fixable := !definesName(c.Parent(), ctx)
+			if !fix || !fixable {
+				issues = append(issues, &Issue{
+					Pos:     fs.Position(n.Pos()),
+					Msg:     fmt.Sprintf("%s ignores timeouts and cancellation; use %s instead", call, ctx),
+					Link:    contextBackgroundURL,
+					Fixable: fixable,
+				})
+				return true
+			}
+			c.Replace(ast.NewIdent(ctx))
+		}
+		return true
+	}, func(c *astutil.Cursor) bool {
+		switch c.Node().(type) {
+		case *ast.FuncDecl, *ast.FuncLit:
+			funcs = funcs[:len(funcs)-1]
+		}
+		return true
+	})
+
+	return issues
+}
+
+// contextParam returns the name of a context.Context parameter of the
+// innermost function of funcs. If there is no such parameter, it returns an
+// empty string.
+func contextParam(funcs []*ast.FuncType) string {
+	if len(funcs) == 0 {
+		return ""
+	}
+	params := funcs[len(funcs)-1].Params
+	if params == nil {
+		return ""
+	}
+	for _, field := range params.List {
+		if toQualifiedName(field.Type) != "context.Context" {
+			continue
+		}
+		for _, name := range field.Names {
+			if name.Name != "_" {
+				return name.Name
+			}
+		}
+	}
+	return ""
+}
+
+// definesName returns true if node is a short variable declaration or a var
+// declaration defining name.
+func definesName(node ast.Node, name string) bool {
+	var idents []*ast.Ident
+	switch n := node.(type) {
+	case *ast.AssignStmt:
+		if n.Tok != token.DEFINE {
+			return false
+		}
+		for _, e := range n.Lhs {
+			if id, ok := e.(*ast.Ident); ok {
+				idents = append(idents, id)
+			}
+		}
+	case *ast.ValueSpec:
+		idents = n.Names
+	}
+	for _, id := range idents {
+		if id.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package check
+
+import (
+	"testing"
+)
+
+func TestContextBackground(t *testing.T) {
+	const code = `package pkg
+
+import (
+	"context"
+
+	"go.chromium.org/tast/core/testing"
+)
+
+func Test(ctx context.Context, s *testing.State) {
+	foo(context.Background())
+	go func() {
+		foo(context.TODO())
+	}()
+	func(c context.Context) {
+		foo(context.TODO())
+	}(ctx)
+	ctx := context.Background()
+}
+
+func helper(_ context.Context, x int) {
+	foo(context.Background())
+}
+
+func init() {
+	foo(context.Background())
+}
+`
+	expects := []string{
+		"testfile.go:10:6: context.Background ignores timeouts and cancellation; use ctx instead",
+		"testfile.go:15:7: context.TODO ignores timeouts and cancellation; use c instead",
+		"testfile.go:17:9: context.Background ignores timeouts and cancellation; use ctx instead",
+	}
+
+	f, fs := parse(code, "testfile.go")
+	issues := ContextBackground(fs, f, false)
+	verifyIssues(t, issues, expects)
+}
+
+func TestContextBackgroundUnitTest(t *testing.T) {
+	const code = `package pkg
+
+import "context"
+
+func foo(ctx context.Context) {
+	bar(context.Background())
+}
+`
+	f, fs := parse(code, "foo_test.go")
+	issues := ContextBackground(fs, f, false)
+	verifyIssues(t, issues, nil)
+}
+
+func TestContextBackgroundAutoFix(t *testing.T) {
+	files := make(map[string]string)
+	expects := make(map[string]string)
+	const filename = "foo.go"
+	files[filename] = `package pkg
+
+import (
+	"context"
+	"time"
+)
+
+func Foo(ctx context.Context, d time.Duration) error {
+	tctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	return bar(tctx, context.TODO())
+}
+`
+	expects[filename] = `package pkg
+
+import (
+	"context"
+	"time"
+)
+
+func Foo(ctx context.Context, d time.Duration) error {
+	tctx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+	return bar(tctx, ctx)
+}
+`
+	verifyAutoFix(t, ContextBackground, files, expects)
+}
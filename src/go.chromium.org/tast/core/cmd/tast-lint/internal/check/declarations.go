@@ -128,7 +128,7 @@ type entityFields map[string]*ast.KeyValueExpr
 // call must be a registration of an entity, e.g. testing.AddTest or
 // testing.AddFixture.
 func registeredEntityFields(fs *token.FileSet, call *ast.CallExpr) (entityFields, []*Issue) {
-	if len(call.Args) != 1 {
+	if len(call.Args) == 0 {
 		// This should be checked by a compiler, so skipped.
 		return nil, nil
 	}
@@ -499,13 +499,15 @@ func verifyParamElement(fs *token.FileSet, node ast.Node) []*Issue {
 }
 
 // isTestingAddTestCall returns true if the call is an expression
-// to invoke testing.AddTest().
+// to invoke testing.AddTest() or testing.AddScenario(), which takes a
+// testing.Test as the first argument too.
 func isTestingAddTestCall(node ast.Node) bool {
 	call, ok := node.(*ast.CallExpr)
 	if !ok {
 		return false
 	}
-	return toQualifiedName(call.Fun) == "testing.AddTest"
+	name := toQualifiedName(call.Fun)
+	return name == "testing.AddTest" || name == "testing.AddScenario"
 }
 
 // toString converts the given node representing a string literal
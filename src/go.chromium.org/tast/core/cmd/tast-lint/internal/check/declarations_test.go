@@ -39,6 +39,22 @@ func init() {
 	verifyIssues(t, issues, nil)
 }
 
+func TestDeclarationsScenario(t *testing.T) {
+	const code = `package pkg
+func init() {
+	testing.AddScenario(&testing.Test{
+		Func:     DoStuff,
+		Desc:     "this description is bad",
+		Contacts: []string{"me@chromium.org"},
+		BugComponent: "b:1034625",
+	}, testing.ScenarioStep[state]{Name: "first", Func: first})
+}
+`
+	f, fs := parse(code, declTestPath)
+	issues := TestDeclarations(fs, f, git.CommitFile{}, false)
+	verifyIssues(t, issues, []string{declTestPath + ":5:13: " + badDescMsg})
+}
+
 const initTmpl = `package pkg
 
 func init() {%v
@@ -26,8 +26,22 @@ type deprecatedAPI struct {
 	exclusion   map[string]struct{}
 	alternative string // alternative to use displayed in the error message
 	link        string // bug link
+	// severity is the severity of issues reported for uses of the API.
+	// New entries usually start with severityWarning to give test authors
+	// time to migrate, and are changed to severityError after a flag day.
+	severity severity
 }
 
+// severity is the severity of issues reported for uses of deprecated APIs.
+type severity int
+
+const (
+	// severityError reports uses of deprecated APIs as errors.
+	severityError severity = iota
+	// severityWarning reports uses of deprecated APIs as warnings.
+	severityWarning
+)
+
 // DeprecatedAPIs checks if deprecated APIs are used.
 func DeprecatedAPIs(fs *token.FileSet, f *ast.File) []*Issue {
 	return deprecatedAPIs(fs, f, []*deprecatedAPI{
@@ -58,6 +72,20 @@ func DeprecatedAPIs(fs *token.FileSet, f *ast.File) []*Issue {
 			},
 			link: "https://buganizer.corp.google.com/issues/187787902",
 		},
+		{
+			pkg:         "go.chromium.org/tast/core/testing/hwdep",
+			ident:       "Platform",
+			alternative: `hwdep.Model or "board:*" software dependency`,
+			link:        "https://chromium.googlesource.com/chromiumos/platform/tast/+/HEAD/docs/test_dependencies.md",
+			severity:    severityWarning,
+		},
+		{
+			pkg:         "go.chromium.org/tast/core/testing/hwdep",
+			ident:       "SkipOnPlatform",
+			alternative: `hwdep.SkipOnModel or "board:*" software dependency`,
+			link:        "https://chromium.googlesource.com/chromiumos/platform/tast/+/HEAD/docs/test_dependencies.md",
+			severity:    severityWarning,
+		},
 	})
 }
 
@@ -97,9 +125,10 @@ func deprecatedAPIs(fs *token.FileSet, f *ast.File, ds []*deprecatedAPI) []*Issu
 			continue
 		}
 		issues = append(issues, &Issue{
-			Pos:  fs.Position(i.Pos()),
-			Msg:  fmt.Sprintf("package %v is deprecated; use %v instead", d.pkg, d.alternative),
-			Link: d.link,
+			Pos:     fs.Position(i.Pos()),
+			Msg:     fmt.Sprintf("package %v is deprecated; use %v instead", d.pkg, d.alternative),
+			Link:    d.link,
+			Warning: d.severity == severityWarning,
 		})
 	}
 
@@ -129,9 +158,10 @@ func deprecatedAPIs(fs *token.FileSet, f *ast.File, ds []*deprecatedAPI) []*Issu
 		if d, ok := deprecatedPkgWithExclusion[path]; ok {
 			if _, excluded := d.exclusion[sel.Sel.Name]; !excluded {
 				issues = append(issues, &Issue{
-					Pos:  fs.Position(x.Pos()),
-					Msg:  fmt.Sprintf("%v.%v is from a deprecated package; use corresponding API in %v instead", d.pkg, sel.Sel.Name, d.alternative),
-					Link: d.link,
+					Pos:     fs.Position(x.Pos()),
+					Msg:     fmt.Sprintf("%v.%v is from a deprecated package; use corresponding API in %v instead", d.pkg, sel.Sel.Name, d.alternative),
+					Link:    d.link,
+					Warning: d.severity == severityWarning,
 				})
 				return true
 			}
@@ -146,9 +176,10 @@ func deprecatedAPIs(fs *token.FileSet, f *ast.File, ds []*deprecatedAPI) []*Issu
 		}
 
 		issues = append(issues, &Issue{
-			Pos:  fs.Position(x.Pos()),
-			Msg:  fmt.Sprintf("%v.%v is deprecated; use %v instead", d.pkg, d.ident, d.alternative),
-			Link: d.link,
+			Pos:     fs.Position(x.Pos()),
+			Msg:     fmt.Sprintf("%v.%v is deprecated; use %v instead", d.pkg, d.ident, d.alternative),
+			Link:    d.link,
+			Warning: d.severity == severityWarning,
 		})
 		return true
 	}, nil)
@@ -56,6 +56,72 @@ func main() {
 	verifyIssues(t, issues, want)
 }
 
+func TestDeprecatedAPIsHwdepPlatform(t *testing.T) {
+	const code = `package main
+
+import (
+	"go.chromium.org/tast/core/testing/hwdep"
+)
+
+var deps = []hwdep.Condition{
+	hwdep.Platform("eve"),       // not ok
+	hwdep.SkipOnPlatform("eve"), // not ok
+	hwdep.Model("eve"),          // ok
+}
+`
+	want := []string{
+		`testfile.go:8:2: go.chromium.org/tast/core/testing/hwdep.Platform is deprecated; use hwdep.Model or "board:*" software dependency instead`,
+		`testfile.go:9:2: go.chromium.org/tast/core/testing/hwdep.SkipOnPlatform is deprecated; use hwdep.SkipOnModel or "board:*" software dependency instead`,
+	}
+
+	f, fs := parse(code, "testfile.go")
+	issues := DeprecatedAPIs(fs, f)
+	verifyIssues(t, issues, want)
+	for _, i := range issues {
+		if !i.Warning {
+			t.Errorf("%v is not reported as a warning", i)
+		}
+	}
+}
+
+func TestDeprecatedAPIsSeverity(t *testing.T) {
+	deprecated := []*deprecatedAPI{{
+		pkg:         "example.com/a",
+		ident:       "Old",
+		alternative: "New",
+		severity:    severityWarning,
+	}, {
+		pkg:         "example.com/b",
+		ident:       "Old",
+		alternative: "New",
+		severity:    severityError,
+	}}
+	const code = `package main
+
+import (
+	"example.com/a"
+	"example.com/b"
+)
+
+func main() {
+	a.Old()
+	b.Old()
+}
+`
+	f, fs := parse(code, "testfile.go")
+	issues := deprecatedAPIs(fs, f, deprecated)
+	SortIssues(issues)
+	if len(issues) != 2 {
+		t.Fatalf("Got %d issue(s); want 2", len(issues))
+	}
+	if !issues[0].Warning {
+		t.Errorf("%v is not reported as a warning", issues[0])
+	}
+	if issues[1].Warning {
+		t.Errorf("%v is reported as a warning", issues[1])
+	}
+}
+
 func TestDeprecatedAPIsInternal(t *testing.T) {
 	deprecated := []*deprecatedAPI{{
 		pkg:         "go.chromium.org/tast-tests/cros/local/testexec",
@@ -0,0 +1,161 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package check
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxDescLen is the maximum number of characters in Desc.
+const maxDescLen = 120
+
+// Exposed here for unit tests.
+const (
+	longDescMsg        = `Desc should be at most %d characters long; move details to comments`
+	titleCaseDescMsg   = `Desc should be in sentence case, e.g. "Checks that foo is bar", not "Checks That Foo Is Bar"`
+	boilerplateDescMsg = `Desc should describe what is checked without boilerplate like "this test", e.g. "Checks that foo is bar"`
+	deniedWordDescMsg  = `Desc should not contain %q listed in %s; use a public name instead`
+
+	descStyleURL = `https://chromium.googlesource.com/chromiumos/platform/tast/+/HEAD/docs/writing_tests.md#Test-descriptions`
+)
+
+// boilerplateDescRe matches boilerplate phrases in Desc which carry no
+// information.
+var boilerplateDescRe = regexp.MustCompile(`(?i)\b(?:this|the) (?:test|fixture)\b`)
+
+// DescDenylist is a list of words, such as internal codenames, which should
+// not appear in Desc.
+type DescDenylist struct {
+	path  string
+	words []string
+	res   []*regexp.Regexp
+}
+
+// ParseDescDenylist parses data read from the denylist file at path.
+//
+// Each line of the file is a word which should not appear in Desc. Words are
+// matched case-insensitively at word boundaries. Empty lines and lines
+// starting with "#" are ignored.
+func ParseDescDenylist(path string, data []byte) (*DescDenylist, error) {
+	d := &DescDenylist{path: path}
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		word := strings.TrimSpace(sc.Text())
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+		d.words = append(d.words, word)
+		d.res = append(d.res, regexp.MustCompile(`(?i)\b`+regexp.QuoteMeta(word)+`\b`))
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// deniedWord returns a word in the denylist contained in s.
+func (d *DescDenylist) deniedWord(s string) (word string, ok bool) {
+	for i, re := range d.res {
+		if re.MatchString(s) {
+			return d.words[i], true
+		}
+	}
+	return "", false
+}
+
+// DescStyle checks that Desc fields of tests and fixtures registered in f are
+// concise and consistent with each other. Words in denylist are disallowed if
+// denylist is not nil.
+//
+// Capitalization and trailing punctuation are checked by TestDeclarations.
+func DescStyle(fs *token.FileSet, f *ast.File, denylist *DescDenylist) []*Issue {
+	var issues []*Issue
+	for _, decl := range f.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Recv != nil || fd.Name.Name != "init" {
+			continue
+		}
+		ast.Inspect(fd.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			if name := toQualifiedName(call.Fun); name != "testing.AddTest" && name != "testing.AddFixture" {
+				return true
+			}
+			// Malformed registrations are reported by other checks.
+			fields, is := registeredEntityFields(fs, call)
+			if len(is) > 0 {
+				return false
+			}
+			kv, ok := fields["Desc"]
+			if !ok {
+				return false
+			}
+			desc, ok := toString(kv.Value)
+			if !ok {
+				return false
+			}
+			issues = append(issues, verifyDescStyle(fs.Position(kv.Value.Pos()), desc, denylist)...)
+			return false
+		})
+	}
+	return issues
+}
+
+func verifyDescStyle(pos token.Position, desc string, denylist *DescDenylist) []*Issue {
+	var issues []*Issue
+	add := func(msg string) {
+		issues = append(issues, &Issue{Pos: pos, Msg: msg, Link: descStyleURL})
+	}
+
+	if n := utf8.RuneCountInString(desc); n > maxDescLen {
+		add(fmt.Sprintf(longDescMsg, maxDescLen))
+	}
+	if isTitleCase(desc) {
+		add(titleCaseDescMsg)
+	}
+	if boilerplateDescRe.MatchString(desc) {
+		add(boilerplateDescMsg)
+	}
+	if denylist != nil {
+		if word, ok := denylist.deniedWord(desc); ok {
+			add(fmt.Sprintf(deniedWordDescMsg, word, denylist.path))
+		}
+	}
+	return issues
+}
+
+// isTitleCase returns whether s looks like a title with every word
+// capitalized, e.g. "Checks That Foo Is Bar". Words in all capitals are
+// considered to be acronyms and ignored.
+func isTitleCase(s string) bool {
+	words := strings.Fields(s)
+	if len(words) < 3 {
+		return false
+	}
+	capitalized := 0
+	for _, w := range words[1:] {
+		r, _ := utf8.DecodeRuneInString(w)
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		if !unicode.IsUpper(r) {
+			return false
+		}
+		if strings.ToUpper(w) != w {
+			capitalized++
+		}
+	}
+	return capitalized >= 2
+}
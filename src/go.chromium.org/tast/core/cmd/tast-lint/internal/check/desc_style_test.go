@@ -0,0 +1,78 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package check
+
+import (
+	"fmt"
+	"testing"
+)
+
+const descDenylistPath = "desc_denylist.txt"
+
+const descDenylistData = `# Internal codenames.
+frobnicator
+
+Zork
+`
+
+func TestDescStyle(t *testing.T) {
+	denylist, err := ParseDescDenylist(descDenylistPath, []byte(descDenylistData))
+	if err != nil {
+		t.Fatal("ParseDescDenylist failed: ", err)
+	}
+
+	const code = `package pkg
+
+func init() {
+	testing.AddTest(&testing.Test{
+		Func: Good,
+		Desc: "Checks that USB and HDMI work on Chrome OS",
+	})
+	testing.AddTest(&testing.Test{
+		Func: TitleCase,
+		Desc: "Checks That Foo Is Bar",
+	})
+	testing.AddTest(&testing.Test{
+		Func: Boilerplate,
+		Desc: "This test checks that foo is bar",
+	})
+	testing.AddTest(&testing.Test{
+		Func: Long,
+		Desc: "Checks that foo is bar, baz is qux, quux is corge, grault is garply, waldo is fred, plugh is xyzzy, thud is foo, and bar is baz",
+	})
+	testing.AddFixture(&testing.Fixture{
+		Name: "fixt",
+		Desc: "Logs in to the ZORK server",
+	})
+	testing.AddTest(&testing.Test{
+		Func: Substring,
+		Desc: "Checks that zorkmids are counted",
+	})
+}
+`
+	f, fs := parse(code, declTestPath)
+	issues := DescStyle(fs, f, denylist)
+	verifyIssues(t, issues, []string{
+		declTestPath + ":10:9: " + titleCaseDescMsg,
+		declTestPath + ":14:9: " + boilerplateDescMsg,
+		declTestPath + ":18:9: " + fmt.Sprintf(longDescMsg, maxDescLen),
+		declTestPath + ":22:9: " + fmt.Sprintf(deniedWordDescMsg, "Zork", descDenylistPath),
+	})
+}
+
+func TestDescStyleNoDenylist(t *testing.T) {
+	const code = `package pkg
+
+func init() {
+	testing.AddTest(&testing.Test{
+		Func: Codename,
+		Desc: "Checks that frobnicator works",
+	})
+}
+`
+	f, fs := parse(code, declTestPath)
+	issues := DescStyle(fs, f, nil)
+	verifyIssues(t, issues, nil)
+}
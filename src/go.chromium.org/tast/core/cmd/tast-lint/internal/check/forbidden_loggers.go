@@ -0,0 +1,167 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package check
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+const forbiddenLoggersURL = "https://chromium.googlesource.com/chromiumos/platform/tast/+/HEAD/docs/writing_tests.md#Errors-and-logging"
+
+// forbiddenLoggers maps print functions that bypass test logs to whether they
+// take a format string.
+var forbiddenLoggers = map[string]bool{
+	"fmt.Print":   false,
+	"fmt.Printf":  true,
+	"fmt.Println": false,
+	"log.Print":   false,
+	"log.Printf":  true,
+	"log.Println": false,
+}
+
+// ForbiddenLoggers checks if functions printing to stdout or stderr are
+// called. Messages printed by them bypass the control message stream between
+// test bundles and the tast command and get lost, so s.Log or
+// testing.ContextLog should be used instead.
+func ForbiddenLoggers(fs *token.FileSet, f *ast.File, fix bool) []*Issue {
+	if isUnitTestFile(fs.Position(f.Package).Filename) {
+		return nil
+	}
+
+	// Being able to run goimports is a precondition to being able to make any fixes.
+	fixable := false
+	if src, err := formatASTNode(fs, f); err == nil {
+		fixable = goimportApplicable(src)
+	}
+
+	var issues []*Issue
+	var funcs []*ast.FuncType // stack of enclosing functions
+	needTestingImport := false
+	fixed := false
+
+	astutil.Apply(f, func(c *astutil.Cursor) bool {
+		switch n := c.Node().(type) {
+		case *ast.FuncDecl:
+			funcs = append(funcs, n.Type)
+		case *ast.FuncLit:
+			funcs = append(funcs, n.Type)
+		case *ast.CallExpr:
+			call := toQualifiedName(n.Fun)
+			hasFormat, ok := forbiddenLoggers[call]
+			if !ok {
+				return true
+			}
+
+			logger, alt := replacementLogger(funcs)
+			if logger == nil {
+				issues = append(issues, &Issue{
+					Pos:  fs.Position(n.Pos()),
+					Msg:  call + " output is lost in test results; use s.Log or testing.ContextLog instead",
+					Link: forbiddenLoggersURL,
+				})
+				return true
+			}
+			if hasFormat {
+				alt += "f"
+			}
+			if !fix {
+				issues = append(issues, &Issue{
+					Pos:     fs.Position(n.Pos()),
+					Msg:     fmt.Sprintf("%s output is lost in test results; use %s instead", call, alt),
+					Link:    forbiddenLoggersURL,
+					Fixable: fixable,
+				})
+				return true
+			}
+			if !fixable {
+				return true
+			}
+
+			sel := strings.SplitN(alt, ".", 2)
+			n.Fun = &ast.SelectorExpr{X: ast.NewIdent(sel[0]), Sel: ast.NewIdent(sel[1])}
+			if hasFormat && len(n.Args) > 0 {
+				trimTrailingNewline(n.Args[0])
+			}
+			if sel[0] == "testing" {
+				n.Args = append([]ast.Expr{logger}, n.Args...)
+				needTestingImport = true
+			}
+			fixed = true
+		}
+		return true
+	}, func(c *astutil.Cursor) bool {
+		switch c.Node().(type) {
+		case *ast.FuncDecl, *ast.FuncLit:
+			funcs = funcs[:len(funcs)-1]
+		}
+		return true
+	})
+
+	if !fixed {
+		return issues
+	}
+	if needTestingImport {
+		astutil.AddImport(fs, f, "go.chromium.org/tast/core/testing")
+	}
+	// Remove imports of fmt and log if they are no longer used.
+	if newf, err := ImportOrderAutoFix(fs, f); err == nil {
+		*f = *newf
+	}
+	return issues
+}
+
+// replacementLogger finds a logger usable in the innermost function of funcs
+// in place of a forbidden print function. It returns an expression to be
+// passed to the logger (a *testing.State or a context.Context) and the name
+// of the logging function. If no logger is found, it returns nil.
+func replacementLogger(funcs []*ast.FuncType) (arg ast.Expr, name string) {
+	if len(funcs) == 0 {
+		return nil, ""
+	}
+	params := funcs[len(funcs)-1].Params
+	if params == nil {
+		return nil, ""
+	}
+	var ctx *ast.Ident
+	for _, field := range params.List {
+		if len(field.Names) == 0 || field.Names[0].Name == "_" {
+			continue
+		}
+		switch typ := field.Type.(type) {
+		case *ast.StarExpr:
+			if toQualifiedName(typ.X) == "testing.State" {
+				return field.Names[0], field.Names[0].Name + ".Log"
+			}
+		case *ast.SelectorExpr:
+			if toQualifiedName(typ) == "context.Context" && ctx == nil {
+				ctx = field.Names[0]
+			}
+		}
+	}
+	if ctx != nil {
+		return ast.NewIdent(ctx.Name), "testing.ContextLog"
+	}
+	return nil, ""
+}
+
+// trimTrailingNewline removes a trailing newline from a format string literal
+// since loggers terminate messages with a newline.
+func trimTrailingNewline(e ast.Expr) {
+	lit, ok := e.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return
+	}
+	s, err := strconv.Unquote(lit.Value)
+	if err != nil || !strings.HasSuffix(s, "\n") {
+		return
+	}
+	lit.Value = strconv.Quote(strings.TrimSuffix(s, "\n"))
+}
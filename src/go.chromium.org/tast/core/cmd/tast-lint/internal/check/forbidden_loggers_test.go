@@ -0,0 +1,124 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package check
+
+import (
+	"testing"
+)
+
+func TestForbiddenLoggers(t *testing.T) {
+	const code = `package pkg
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"go.chromium.org/tast/core/testing"
+)
+
+func Test(ctx context.Context, s *testing.State) {
+	fmt.Printf("foo %d\n", 1)
+	log.Println("bar")
+	func() {
+		fmt.Print("baz")
+	}()
+}
+
+func helper(ctx context.Context) {
+	log.Printf("foo")
+}
+
+func init() {
+	fmt.Println("foo")
+}
+`
+	expects := []string{
+		"testfile.go:12:2: fmt.Printf output is lost in test results; use s.Logf instead",
+		"testfile.go:13:2: log.Println output is lost in test results; use s.Log instead",
+		"testfile.go:15:3: fmt.Print output is lost in test results; use s.Log or testing.ContextLog instead",
+		"testfile.go:20:2: log.Printf output is lost in test results; use testing.ContextLogf instead",
+		"testfile.go:24:2: fmt.Println output is lost in test results; use s.Log or testing.ContextLog instead",
+	}
+
+	f, fs := parse(code, "testfile.go")
+	issues := ForbiddenLoggers(fs, f, false)
+	verifyIssues(t, issues, expects)
+}
+
+func TestForbiddenLoggersUnitTest(t *testing.T) {
+	const code = `package pkg
+
+import "fmt"
+
+func TestFoo(t *testing.T) {
+	fmt.Println("foo")
+}
+`
+	f, fs := parse(code, "foo_test.go")
+	issues := ForbiddenLoggers(fs, f, false)
+	verifyIssues(t, issues, nil)
+}
+
+func TestForbiddenLoggersAutoFix(t *testing.T) {
+	files := make(map[string]string)
+	expects := make(map[string]string)
+	const filename1, filename2 = "foo.go", "bar.go"
+	files[filename1] = `package pkg
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"go.chromium.org/tast/core/testing"
+)
+
+func Foo(ctx context.Context, s *testing.State) {
+	fmt.Printf("foo %d\n", 1)
+	log.Println("bar")
+}
+`
+	expects[filename1] = `package pkg
+
+import (
+	"context"
+
+	"go.chromium.org/tast/core/testing"
+)
+
+func Foo(ctx context.Context, s *testing.State) {
+	s.Logf("foo %d", 1)
+	s.Log("bar")
+}
+`
+	files[filename2] = `package pkg
+
+import (
+	"context"
+	"fmt"
+)
+
+func bar(ctx context.Context, x int) error {
+	fmt.Printf("x = %d\n", x)
+	return fmt.Errorf("bar")
+}
+`
+	expects[filename2] = `package pkg
+
+import (
+	"context"
+	"fmt"
+
+	"go.chromium.org/tast/core/testing"
+)
+
+func bar(ctx context.Context, x int) error {
+	testing.ContextLogf(ctx, "x = %d", x)
+	return fmt.Errorf("bar")
+}
+`
+	verifyAutoFix(t, ForbiddenLoggers, files, expects)
+}
@@ -0,0 +1,300 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package check
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strings"
+)
+
+// Exposed here for unit tests.
+const (
+	missingParamDepsMsg = `Param %q looks like it requires %q, but none of %s is declared in its ExtraSoftwareDeps/ExtraHardwareDeps or the test's SoftwareDeps/HardwareDeps`
+
+	testParamDepsURL = `https://chromium.googlesource.com/chromiumos/platform/tast/+/HEAD/docs/test_dependencies.md`
+)
+
+// defaultParamDepsMapData is the mapping table used when no table file is
+// given. See ParseParamDepsMap for its format.
+const defaultParamDepsMapData = `# Param keyword, followed by dependencies any of which is required.
+vulkan swdep:vulkan
+arc swdep:arc swdep:android_p swdep:android_vm
+lacros swdep:lacros
+`
+
+// paramDepsRule is a rule that a parameterized test whose Param contains
+// keyword must declare one of dependencies in swDeps or hwDeps.
+type paramDepsRule struct {
+	keyword string
+	swDeps  []string // software feature names
+	hwDeps  []string // names of functions in the hwdep package
+}
+
+// deps returns the dependencies of the rule in the table file format.
+func (r *paramDepsRule) deps() []string {
+	var deps []string
+	for _, d := range r.swDeps {
+		deps = append(deps, "swdep:"+d)
+	}
+	for _, d := range r.hwDeps {
+		deps = append(deps, "hwdep:"+d)
+	}
+	return deps
+}
+
+// ParamDepsMap is a mapping table from keywords in Param names and values to
+// dependencies implied by them.
+type ParamDepsMap struct {
+	rules []*paramDepsRule
+}
+
+// ParseParamDepsMap parses data read from the mapping table file at path.
+//
+// Each line of the file consists of a keyword followed by one or more
+// dependencies separated by spaces. A dependency is either "swdep:<feature>"
+// for a software feature name or "hwdep:<func>" for a function in the hwdep
+// package, e.g. "vulkan swdep:vulkan hwdep:SupportsVulkan". A Param whose name
+// or string value contains the keyword as a word delimited by non-alphanumeric
+// characters must declare at least one of the dependencies. Empty lines and
+// lines starting with "#" are ignored.
+func ParseParamDepsMap(path string, data []byte) (*ParamDepsMap, error) {
+	m := &ParamDepsMap{}
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for lineNo := 1; sc.Scan(); lineNo++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("%s:%d: no dependency for %q", path, lineNo, fields[0])
+		}
+		r := &paramDepsRule{keyword: strings.ToLower(fields[0])}
+		for _, d := range fields[1:] {
+			switch {
+			case strings.HasPrefix(d, "swdep:"):
+				r.swDeps = append(r.swDeps, strings.TrimPrefix(d, "swdep:"))
+			case strings.HasPrefix(d, "hwdep:"):
+				r.hwDeps = append(r.hwDeps, strings.TrimPrefix(d, "hwdep:"))
+			default:
+				return nil, fmt.Errorf("%s:%d: malformed dependency %q; want swdep:<feature> or hwdep:<func>", path, lineNo, d)
+			}
+		}
+		m.rules = append(m.rules, r)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// DefaultParamDepsMap returns the mapping table used by default.
+func DefaultParamDepsMap() *ParamDepsMap {
+	m, err := ParseParamDepsMap("default", []byte(defaultParamDepsMapData))
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// wordDelimiterRe matches delimiters of words in Param names and values.
+var wordDelimiterRe = regexp.MustCompile(`[^a-z0-9]+`)
+
+// paramWords returns lower-cased words in s.
+func paramWords(s string) map[string]bool {
+	words := make(map[string]bool)
+	for _, w := range wordDelimiterRe.Split(strings.ToLower(s), -1) {
+		if w != "" {
+			words[w] = true
+		}
+	}
+	return words
+}
+
+// declaredDeps holds dependencies declared for a test or a Param.
+type declaredDeps struct {
+	swDeps map[string]bool
+	hwDeps map[string]bool
+	// unknown is set if some dependencies can not be determined statically,
+	// e.g. software features are given as constants.
+	unknown bool
+}
+
+func newDeclaredDeps() *declaredDeps {
+	return &declaredDeps{swDeps: make(map[string]bool), hwDeps: make(map[string]bool)}
+}
+
+// addSoftwareDeps adds software features listed in expr.
+func (d *declaredDeps) addSoftwareDeps(expr ast.Expr) {
+	comp, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		d.unknown = true
+		return
+	}
+	for _, el := range comp.Elts {
+		s, ok := toString(el)
+		if !ok {
+			d.unknown = true
+			continue
+		}
+		d.swDeps[s] = true
+	}
+}
+
+// addHardwareDeps adds hwdep functions called in expr.
+func (d *declaredDeps) addHardwareDeps(expr ast.Expr) {
+	found := false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if name := toQualifiedName(call.Fun); strings.HasPrefix(name, "hwdep.") {
+			d.hwDeps[strings.TrimPrefix(name, "hwdep.")] = true
+			found = true
+		}
+		return true
+	})
+	if !found {
+		// Hardware dependencies are given as a variable or a helper.
+		d.unknown = true
+	}
+}
+
+// merge returns dependencies declared in either d or o.
+func (d *declaredDeps) merge(o *declaredDeps) *declaredDeps {
+	return &declaredDeps{
+		swDeps:  union(d.swDeps, o.swDeps),
+		hwDeps:  union(d.hwDeps, o.hwDeps),
+		unknown: d.unknown || o.unknown,
+	}
+}
+
+// satisfies returns whether d contains any dependency of r.
+func (d *declaredDeps) satisfies(r *paramDepsRule) bool {
+	for _, dep := range r.swDeps {
+		if d.swDeps[dep] {
+			return true
+		}
+	}
+	for _, dep := range r.hwDeps {
+		if d.hwDeps[dep] {
+			return true
+		}
+	}
+	return false
+}
+
+// entityDeps returns dependencies declared in fields of a test or a Param.
+// prefix is prepended to field names, e.g. "Extra" for a Param.
+func entityDeps(fields map[string]*ast.KeyValueExpr, prefix string) *declaredDeps {
+	d := newDeclaredDeps()
+	if kv, ok := fields[prefix+"SoftwareDeps"]; ok {
+		d.addSoftwareDeps(kv.Value)
+	}
+	if kv, ok := fields[prefix+"HardwareDeps"]; ok {
+		d.addHardwareDeps(kv.Value)
+	}
+	return d
+}
+
+// compositeFields returns a mapping from field name to value in comp.
+func compositeFields(comp *ast.CompositeLit) map[string]*ast.KeyValueExpr {
+	res := make(map[string]*ast.KeyValueExpr)
+	for _, el := range comp.Elts {
+		kv, ok := el.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		ident, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		res[ident.Name] = kv
+	}
+	return res
+}
+
+// ParamDeps checks that Params of parameterized tests registered in f declare
+// dependencies implied by their names and string values according to m.
+func ParamDeps(fs *token.FileSet, f *ast.File, m *ParamDepsMap) []*Issue {
+	if m == nil {
+		return nil
+	}
+	var issues []*Issue
+	for _, decl := range f.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Recv != nil || fd.Name.Name != "init" {
+			continue
+		}
+		ast.Inspect(fd.Body, func(n ast.Node) bool {
+			if !isTestingAddTestCall(n) {
+				return true
+			}
+			// Malformed registrations are reported by other checks.
+			fields, is := registeredEntityFields(fs, n.(*ast.CallExpr))
+			if len(is) > 0 {
+				return false
+			}
+			kv, ok := fields["Params"]
+			if !ok {
+				return false
+			}
+			params, ok := kv.Value.(*ast.CompositeLit)
+			if !ok {
+				return false
+			}
+			testDeps := entityDeps(fields, "")
+			for _, el := range params.Elts {
+				comp, ok := el.(*ast.CompositeLit)
+				if !ok {
+					continue
+				}
+				issues = append(issues, verifyParamDeps(fs, comp, testDeps, m)...)
+			}
+			return false
+		})
+	}
+	return issues
+}
+
+func verifyParamDeps(fs *token.FileSet, param *ast.CompositeLit, testDeps *declaredDeps, m *ParamDepsMap) []*Issue {
+	fields := compositeFields(param)
+	var name string
+	pos := param.Pos()
+	if kv, ok := fields["Name"]; ok {
+		name, _ = toString(kv.Value)
+		pos = kv.Value.Pos()
+	}
+	words := paramWords(name)
+	if kv, ok := fields["Val"]; ok {
+		if val, ok := toString(kv.Value); ok {
+			words = union(words, paramWords(val))
+		}
+	}
+
+	deps := testDeps.merge(entityDeps(fields, "Extra"))
+	if deps.unknown {
+		return nil
+	}
+
+	var issues []*Issue
+	for _, r := range m.rules {
+		if !words[r.keyword] || deps.satisfies(r) {
+			continue
+		}
+		issues = append(issues, &Issue{
+			Pos:  fs.Position(pos),
+			Msg:  fmt.Sprintf(missingParamDepsMsg, name, r.keyword, strings.Join(r.deps(), ", ")),
+			Link: testParamDepsURL,
+		})
+	}
+	return issues
+}
@@ -0,0 +1,91 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package check
+
+import (
+	"fmt"
+	"testing"
+)
+
+const paramDepsMapPath = "param_deps.txt"
+
+const paramDepsMapData = `# Graphics.
+vulkan swdep:vulkan hwdep:SupportsVulkan
+
+arc swdep:android_p swdep:android_vm
+`
+
+func TestParamDeps(t *testing.T) {
+	m, err := ParseParamDepsMap(paramDepsMapPath, []byte(paramDepsMapData))
+	if err != nil {
+		t.Fatal("ParseParamDepsMap failed: ", err)
+	}
+
+	const code = `package pkg
+
+func init() {
+	testing.AddTest(&testing.Test{
+		Func: Graphics,
+		Params: []testing.Param{{
+			Name:              "vulkan",
+			ExtraSoftwareDeps: []string{"vulkan"},
+		}, {
+			Name:              "vulkan_composite",
+			ExtraHardwareDeps: hwdep.D(hwdep.SupportsVulkan()),
+		}, {
+			Name: "vulkan_missing",
+		}, {
+			Name: "gl",
+			Val:  "vulkan",
+		}, {
+			Name: "vulkanish",
+		}},
+	})
+	testing.AddTest(&testing.Test{
+		Func:         Android,
+		SoftwareDeps: []string{"chrome"},
+		Params: []testing.Param{{
+			ExtraSoftwareDeps: []string{"android_p"},
+		}, {
+			Name: "arc_vm",
+		}, {
+			Name:              "arc_constant",
+			ExtraSoftwareDeps: []string{"android_vm", someDep},
+		}},
+	})
+	testing.AddTest(&testing.Test{
+		Func:         AndroidVM,
+		SoftwareDeps: []string{"android_vm"},
+		Params: []testing.Param{{
+			Name: "arc",
+		}},
+	})
+}
+`
+	f, fs := parse(code, declTestPath)
+	issues := ParamDeps(fs, f, m)
+	verifyIssues(t, issues, []string{
+		declTestPath + ":13:10: " + fmt.Sprintf(missingParamDepsMsg, "vulkan_missing", "vulkan", "swdep:vulkan, hwdep:SupportsVulkan"),
+		declTestPath + ":15:10: " + fmt.Sprintf(missingParamDepsMsg, "gl", "vulkan", "swdep:vulkan, hwdep:SupportsVulkan"),
+		declTestPath + ":27:10: " + fmt.Sprintf(missingParamDepsMsg, "arc_vm", "arc", "swdep:android_p, swdep:android_vm"),
+	})
+}
+
+func TestParseParamDepsMapMalformed(t *testing.T) {
+	for _, data := range []string{
+		"vulkan\n",
+		"vulkan vulkan\n",
+	} {
+		if _, err := ParseParamDepsMap(paramDepsMapPath, []byte(data)); err == nil {
+			t.Errorf("ParseParamDepsMap(%q) succeeded unexpectedly", data)
+		}
+	}
+}
+
+func TestDefaultParamDepsMap(t *testing.T) {
+	if m := DefaultParamDepsMap(); len(m.rules) == 0 {
+		t.Error("DefaultParamDepsMap returned no rule")
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package check
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"regexp"
+)
+
+// Exposed here for unit tests.
+const (
+	badParamNameMsg    = `Param name %q should be lowercase snake case, e.g. "foo_bar"`
+	duplicateParamMsg  = `Test name %q is generated by more than one Param`
+	tooLongTestNameMsg = `Test name %q is %d characters long, exceeding the maximum of %d`
+)
+
+// maxGeneratedNameSize is the maximum length of test names accepted by infra
+// systems storing test results.
+const maxGeneratedNameSize = 100
+
+// paramNameRe matches valid Param names. It is stricter than the regular
+// expression used on registration so that names do not start or end with
+// underscores nor contain consecutive ones.
+var paramNameRe = regexp.MustCompile(`^[a-z0-9]+(?:_[a-z0-9]+)*$`)
+
+// ParamNames checks that the names of tests generated from Params of
+// parameterized tests registered in f are valid, unique and not too long.
+func ParamNames(fs *token.FileSet, f *ast.File) []*Issue {
+	var issues []*Issue
+	for _, decl := range f.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Recv != nil || fd.Name.Name != "init" {
+			continue
+		}
+		ast.Inspect(fd.Body, func(n ast.Node) bool {
+			if !isTestingAddTestCall(n) {
+				return true
+			}
+			// Malformed registrations are reported by other checks.
+			fields, is := registeredEntityFields(fs, n.(*ast.CallExpr))
+			if len(is) > 0 {
+				return false
+			}
+			kv, ok := fields["Params"]
+			if !ok {
+				return false
+			}
+			params, ok := kv.Value.(*ast.CompositeLit)
+			if !ok {
+				return false
+			}
+			base := ""
+			if fkv, ok := fields["Func"]; ok {
+				if ident, ok := fkv.Value.(*ast.Ident); ok {
+					base = f.Name.Name + "." + ident.Name
+				}
+			}
+			issues = append(issues, verifyParamNames(fs, params, base)...)
+			return false
+		})
+	}
+	return issues
+}
+
+// verifyParamNames verifies the names of tests generated from params. base is
+// the name of the test without a Param name, or an empty string if it can not
+// be determined statically.
+func verifyParamNames(fs *token.FileSet, params *ast.CompositeLit, base string) []*Issue {
+	var issues []*Issue
+	seen := make(map[string]bool)
+	for _, el := range params.Elts {
+		comp, ok := el.(*ast.CompositeLit)
+		if !ok {
+			continue
+		}
+		var name string
+		pos := comp.Pos()
+		if kv, ok := compositeFields(comp)["Name"]; ok {
+			// Non-literal names are reported by TestDeclarations.
+			if name, ok = toString(kv.Value); !ok {
+				continue
+			}
+			pos = kv.Value.Pos()
+		}
+
+		if name != "" && !paramNameRe.MatchString(name) {
+			issues = append(issues, &Issue{
+				Pos:  fs.Position(pos),
+				Msg:  fmt.Sprintf(badParamNameMsg, name),
+				Link: testParamTestURL,
+			})
+		}
+
+		full := name
+		if base != "" {
+			full = base
+			if name != "" {
+				full += "." + name
+			}
+		}
+		if seen[name] {
+			issues = append(issues, &Issue{
+				Pos:  fs.Position(pos),
+				Msg:  fmt.Sprintf(duplicateParamMsg, full),
+				Link: testParamTestURL,
+			})
+		}
+		seen[name] = true
+
+		if base != "" && len(full) > maxGeneratedNameSize {
+			issues = append(issues, &Issue{
+				Pos:  fs.Position(pos),
+				Msg:  fmt.Sprintf(tooLongTestNameMsg, full, len(full), maxGeneratedNameSize),
+				Link: testParamTestURL,
+			})
+		}
+	}
+	return issues
+}
@@ -0,0 +1,66 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package check
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParamNames(t *testing.T) {
+	long := strings.Repeat("a", maxGeneratedNameSize)
+	code := `package pkg
+
+func init() {
+	testing.AddTest(&testing.Test{
+		Func: Foo,
+		Params: []testing.Param{{
+			Val: 1,
+		}, {
+			Name: "ok_name2",
+		}, {
+			Name: "CamelCase",
+		}, {
+			Name: "trailing_",
+		}, {
+			Name: "double__underscore",
+		}, {
+			Name: "ok_name2",
+		}, {
+			Val: 2,
+		}, {
+			Name: "` + long + `",
+		}, {
+			Name: nonLiteral,
+		}},
+	})
+}
+`
+	f, fs := parse(code, declTestPath)
+	issues := ParamNames(fs, f)
+	verifyIssues(t, issues, []string{
+		declTestPath + ":11:10: " + fmt.Sprintf(badParamNameMsg, "CamelCase"),
+		declTestPath + ":13:10: " + fmt.Sprintf(badParamNameMsg, "trailing_"),
+		declTestPath + ":15:10: " + fmt.Sprintf(badParamNameMsg, "double__underscore"),
+		declTestPath + ":17:10: " + fmt.Sprintf(duplicateParamMsg, "pkg.Foo.ok_name2"),
+		declTestPath + ":18:6: " + fmt.Sprintf(duplicateParamMsg, "pkg.Foo"),
+		declTestPath + ":21:10: " + fmt.Sprintf(tooLongTestNameMsg, "pkg.Foo."+long, len("pkg.Foo.")+len(long), maxGeneratedNameSize),
+	})
+}
+
+func TestParamNamesNoParams(t *testing.T) {
+	const code = `package pkg
+
+func init() {
+	testing.AddTest(&testing.Test{
+		Func: Foo,
+	})
+}
+`
+	f, fs := parse(code, declTestPath)
+	issues := ParamNames(fs, f)
+	verifyIssues(t, issues, nil)
+}
@@ -0,0 +1,179 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package check
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"path"
+	"strconv"
+)
+
+const sharedStateURL = "https://chromium.googlesource.com/chromiumos/platform/tast/+/HEAD/docs/code_review_comments.md#Shared-state"
+
+// stateChangingCalls maps functions changing process-wide state that persists
+// across tests to descriptions of the state.
+var stateChangingCalls = map[string]string{
+	"os.Setenv":     "environment variables",
+	"os.Unsetenv":   "environment variables",
+	"os.Clearenv":   "environment variables",
+	"rand.Seed":     "the global random number generator",
+	"syscall.Umask": "the file mode creation mask",
+	"unix.Umask":    "the file mode creation mask",
+}
+
+// SharedState checks if entry files have state shared across tests, i.e.
+// package-level variables modified by tests and calls of functions changing
+// process-wide state. Tests depending on such state pass or fail depending on
+// which tests ran before them in the same process, and break once tests are
+// reordered or sharded differently. Such state should be set up and torn down
+// by a fixture instead.
+func SharedState(fs *token.FileSet, f *ast.File) []*Issue {
+	if !isEntryFile(fs.Position(f.Package).Filename) {
+		return nil
+	}
+
+	globals := make(map[*ast.ValueSpec]bool)
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			globals[spec.(*ast.ValueSpec)] = true
+		}
+	}
+	pkgs := importedPackageNames(f)
+
+	var issues []*Issue
+	checkWrite := func(expr ast.Expr) {
+		id := rootIdent(expr)
+		if id == nil {
+			return
+		}
+		if id.Obj != nil {
+			if spec, ok := id.Obj.Decl.(*ast.ValueSpec); ok && globals[spec] {
+				issues = append(issues, &Issue{
+					Pos:  fs.Position(expr.Pos()),
+					Msg:  fmt.Sprintf("Package-level variable %s is modified by a test; state shared across tests makes them depend on their order, so keep it local to the test or use a fixture", id.Name),
+					Link: sharedStateURL,
+				})
+			}
+			return
+		}
+		// A selector on an imported package, e.g. http.DefaultClient.Timeout.
+		if _, ok := expr.(*ast.Ident); !ok && pkgs[id.Name] {
+			issues = append(issues, &Issue{
+				Pos:  fs.Position(expr.Pos()),
+				Msg:  fmt.Sprintf("Assigning to %s changes state shared across tests; use a fixture to set it up and restore it", packageVarName(expr)),
+				Link: sharedStateURL,
+			})
+		}
+	}
+
+	for _, decl := range f.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Body == nil || (fd.Recv == nil && fd.Name.Name == "init") {
+			continue
+		}
+		ast.Inspect(fd.Body, func(node ast.Node) bool {
+			switch n := node.(type) {
+			case *ast.AssignStmt:
+				if n.Tok == token.DEFINE {
+					return true
+				}
+				for _, lhs := range n.Lhs {
+					checkWrite(lhs)
+				}
+			case *ast.IncDecStmt:
+				checkWrite(n.X)
+			case *ast.CallExpr:
+				call := toQualifiedName(n.Fun)
+				if state, ok := stateChangingCalls[call]; ok {
+					issues = append(issues, &Issue{
+						Pos:  fs.Position(n.Pos()),
+						Msg:  fmt.Sprintf("%s changes %s shared across tests; use a fixture to set it up and restore it", call, state),
+						Link: sharedStateURL,
+					})
+				}
+			}
+			return true
+		})
+	}
+	return issues
+}
+
+// rootIdent returns the identifier at the root of expr, e.g. x for x.f[i].g.
+// It returns nil if expr is not rooted at an identifier, e.g. f().x.
+func rootIdent(expr ast.Expr) *ast.Ident {
+	for {
+		switch e := expr.(type) {
+		case *ast.Ident:
+			if e.Name == "_" {
+				return nil
+			}
+			return e
+		case *ast.SelectorExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.TypeAssertExpr:
+			expr = e.X
+		default:
+			return nil
+		}
+	}
+}
+
+// packageVarName returns the qualified name of the package-level variable
+// at the root of expr, e.g. http.DefaultClient for
+// http.DefaultClient.Transport.(*http.Transport).Proxy.
+func packageVarName(expr ast.Expr) string {
+	var sel *ast.SelectorExpr
+	for {
+		switch e := expr.(type) {
+		case *ast.Ident:
+			if sel == nil {
+				return e.Name
+			}
+			return toQualifiedName(sel)
+		case *ast.SelectorExpr:
+			sel = e
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.TypeAssertExpr:
+			expr = e.X
+		default:
+			return ""
+		}
+	}
+}
+
+// importedPackageNames returns names of packages imported by f.
+func importedPackageNames(f *ast.File) map[string]bool {
+	names := make(map[string]bool)
+	for _, imp := range f.Imports {
+		if imp.Name != nil {
+			names[imp.Name.Name] = true
+			continue
+		}
+		p, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+		names[path.Base(p)] = true
+	}
+	return names
+}
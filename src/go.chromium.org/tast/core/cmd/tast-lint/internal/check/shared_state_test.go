@@ -0,0 +1,87 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package check
+
+import (
+	"testing"
+)
+
+const sharedStateTestPath = "src/go.chromium.org/tast-tests/cros/local/bundles/cros/example/shared_state.go"
+
+func TestSharedState(t *testing.T) {
+	const code = `package example
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"os"
+	"regexp"
+
+	"go.chromium.org/tast/core/testing"
+)
+
+var (
+	count    int
+	seen     = make(map[string]bool)
+	loggedIn bool
+	nameRE   = regexp.MustCompile("^foo$")
+)
+
+func init() {
+	count = 0
+	testing.AddTest(&testing.Test{
+		Func: SharedState,
+	})
+}
+
+func SharedState(ctx context.Context, s *testing.State) {
+	count++
+	seen["foo"] = true
+	if !loggedIn {
+		loggedIn = true
+	}
+	os.Setenv("FOO", "bar")
+	rand.Seed(1)
+	http.DefaultClient.Timeout = 0
+
+	local := 0
+	local++
+	var m map[string]bool
+	m["foo"] = true
+	_ = nameRE.MatchString("foo")
+	s.Log(count, local)
+}
+`
+	expects := []string{
+		sharedStateTestPath + ":28:2: Package-level variable count is modified by a test; state shared across tests makes them depend on their order, so keep it local to the test or use a fixture",
+		sharedStateTestPath + ":29:2: Package-level variable seen is modified by a test; state shared across tests makes them depend on their order, so keep it local to the test or use a fixture",
+		sharedStateTestPath + ":31:3: Package-level variable loggedIn is modified by a test; state shared across tests makes them depend on their order, so keep it local to the test or use a fixture",
+		sharedStateTestPath + ":33:2: os.Setenv changes environment variables shared across tests; use a fixture to set it up and restore it",
+		sharedStateTestPath + ":34:2: rand.Seed changes the global random number generator shared across tests; use a fixture to set it up and restore it",
+		sharedStateTestPath + ":35:2: Assigning to http.DefaultClient changes state shared across tests; use a fixture to set it up and restore it",
+	}
+
+	f, fs := parse(code, sharedStateTestPath)
+	issues := SharedState(fs, f)
+	verifyIssues(t, issues, expects)
+}
+
+func TestSharedStateSupportPackage(t *testing.T) {
+	const code = `package chrome
+
+import "os"
+
+var instance int
+
+func New() {
+	instance++
+	os.Setenv("FOO", "bar")
+}
+`
+	f, fs := parse(code, "src/go.chromium.org/tast-tests/cros/local/chrome/chrome.go")
+	issues := SharedState(fs, f)
+	verifyIssues(t, issues, nil)
+}
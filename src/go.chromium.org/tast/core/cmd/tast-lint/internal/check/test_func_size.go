@@ -0,0 +1,133 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package check
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+)
+
+// Exposed here for unit tests.
+const (
+	complexTestFuncMsg = `Test function %s has cyclomatic complexity %d, exceeding the budget of %d; move logic into helper functions or support packages`
+	longTestFuncMsg    = `Test function %s has %d lines, exceeding the budget of %d; move logic into helper functions or support packages`
+
+	testFuncSizeURL = `https://chromium.googlesource.com/chromiumos/platform/tast/+/HEAD/docs/writing_tests.md#Test-function-size`
+)
+
+// TestFuncBudget limits the size of test functions.
+type TestFuncBudget struct {
+	// MaxComplexity is the maximum cyclomatic complexity of a test function.
+	// It is not checked if zero or negative.
+	MaxComplexity int
+	// MaxLines is the maximum number of lines in the body of a test
+	// function. It is not checked if zero or negative.
+	MaxLines int
+}
+
+// TestFuncSize checks that test functions registered in f fit in budget.
+// Huge test functions are hard to review, so their logic should be moved
+// into helper functions or support packages.
+func TestFuncSize(fs *token.FileSet, f *ast.File, budget TestFuncBudget) []*Issue {
+	if budget.MaxComplexity <= 0 && budget.MaxLines <= 0 {
+		return nil
+	}
+
+	funcs := make(map[string]*ast.FuncDecl)
+	for _, decl := range f.Decls {
+		if fd, ok := decl.(*ast.FuncDecl); ok && fd.Recv == nil && fd.Body != nil {
+			funcs[fd.Name.Name] = fd
+		}
+	}
+
+	var issues []*Issue
+	for _, name := range registeredTestFuncs(f) {
+		fd, ok := funcs[name]
+		if !ok {
+			continue
+		}
+		pos := fs.Position(fd.Name.Pos())
+		if budget.MaxComplexity > 0 {
+			if c := cyclomaticComplexity(fd.Body); c > budget.MaxComplexity {
+				issues = append(issues, &Issue{Pos: pos, Msg: fmt.Sprintf(complexTestFuncMsg, name, c, budget.MaxComplexity), Link: testFuncSizeURL})
+			}
+		}
+		if budget.MaxLines > 0 {
+			lines := fs.Position(fd.Body.Rbrace).Line - fs.Position(fd.Body.Lbrace).Line - 1
+			if lines > budget.MaxLines {
+				issues = append(issues, &Issue{Pos: pos, Msg: fmt.Sprintf(longTestFuncMsg, name, lines, budget.MaxLines), Link: testFuncSizeURL})
+			}
+		}
+	}
+	return issues
+}
+
+// registeredTestFuncs returns names of functions set to Func of tests
+// registered by testing.AddTest in init functions in f.
+func registeredTestFuncs(f *ast.File) []string {
+	var names []string
+	for _, decl := range f.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Recv != nil || fd.Name.Name != "init" {
+			continue
+		}
+		ast.Inspect(fd.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || toQualifiedName(call.Fun) != "testing.AddTest" || len(call.Args) != 1 {
+				return true
+			}
+			arg, ok := call.Args[0].(*ast.UnaryExpr)
+			if !ok {
+				return false
+			}
+			comp, ok := arg.X.(*ast.CompositeLit)
+			if !ok {
+				return false
+			}
+			for _, el := range comp.Elts {
+				kv, ok := el.(*ast.KeyValueExpr)
+				if !ok {
+					continue
+				}
+				if key, ok := kv.Key.(*ast.Ident); !ok || key.Name != "Func" {
+					continue
+				}
+				if id, ok := kv.Value.(*ast.Ident); ok {
+					names = append(names, id.Name)
+				}
+			}
+			return false
+		})
+	}
+	return names
+}
+
+// cyclomaticComplexity returns the cyclomatic complexity of a function body,
+// i.e. one plus the number of branch points. Function literals in body are
+// counted as part of it.
+func cyclomaticComplexity(body *ast.BlockStmt) int {
+	c := 1
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			c++
+		case *ast.CaseClause:
+			if n.List != nil {
+				c++
+			}
+		case *ast.CommClause:
+			if n.Comm != nil {
+				c++
+			}
+		case *ast.BinaryExpr:
+			if n.Op == token.LAND || n.Op == token.LOR {
+				c++
+			}
+		}
+		return true
+	})
+	return c
+}
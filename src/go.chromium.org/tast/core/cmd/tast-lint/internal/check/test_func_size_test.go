@@ -0,0 +1,68 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package check
+
+import (
+	"fmt"
+	"testing"
+)
+
+const testFuncSizeCode = `package pkg
+
+func init() {
+	testing.AddTest(&testing.Test{
+		Func: Simple,
+		Desc: "Checks that foo is bar",
+	})
+	testing.AddTest(&testing.Test{
+		Func: Complex,
+		Desc: "Checks that baz is qux",
+	})
+}
+
+func Simple(ctx context.Context, s *testing.State) {
+	if err := foo(ctx); err != nil {
+		s.Fatal("Failed: ", err)
+	}
+}
+
+func Complex(ctx context.Context, s *testing.State) {
+	for _, x := range xs {
+		if x > 0 && x < 10 {
+			continue
+		}
+		switch x {
+		case 1:
+		case 2, 3:
+		default:
+		}
+	}
+	select {
+	case <-ctx.Done():
+	default:
+	}
+}
+
+// helper is not a test function, so it is not checked.
+func helper() {
+	if a || b || c || d {
+	}
+}
+`
+
+func TestTestFuncSize(t *testing.T) {
+	f, fs := parse(testFuncSizeCode, declTestPath)
+	issues := TestFuncSize(fs, f, TestFuncBudget{MaxComplexity: 5, MaxLines: 10})
+	verifyIssues(t, issues, []string{
+		declTestPath + ":20:6: " + fmt.Sprintf(complexTestFuncMsg, "Complex", 7, 5),
+		declTestPath + ":20:6: " + fmt.Sprintf(longTestFuncMsg, "Complex", 14, 10),
+	})
+}
+
+func TestTestFuncSizeDisabled(t *testing.T) {
+	f, fs := parse(testFuncSizeCode, declTestPath)
+	issues := TestFuncSize(fs, f, TestFuncBudget{})
+	verifyIssues(t, issues, nil)
+}
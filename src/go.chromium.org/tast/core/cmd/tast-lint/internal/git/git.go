@@ -12,7 +12,9 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // Git is a thin wrapper of git command line tool allowing to access files in Git history.
@@ -163,3 +165,47 @@ func (g *Git) ListDir(dir string) ([]string, error) {
 	}
 	return strings.Split(strings.TrimRight(string(out), "\n"), "\n"), nil
 }
+
+// CommitTime returns the committer time of the commit.
+func (g *Git) CommitTime() (time.Time, error) {
+	if g.Commit == "" {
+		return time.Time{}, errors.New("CommitTime needs explicit commit")
+	}
+	cmd := exec.Command("git", "show", "-s", "--format=%ct", g.Commit)
+	cmd.Dir = g.Dir
+	out, err := cmd.Output()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get commit time of %q: %v", g.Commit, err)
+	}
+	sec, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse commit time of %q: %v", g.Commit, err)
+	}
+	return time.Unix(sec, 0), nil
+}
+
+// BlameTime returns the committer time of the commit which last modified the
+// given line (1-based) of a file at the commit.
+func (g *Git) BlameTime(path string, line int) (time.Time, error) {
+	if g.Commit == "" {
+		return time.Time{}, errors.New("BlameTime needs explicit commit")
+	}
+	cmd := exec.Command("git", "blame", "--porcelain", "-L", fmt.Sprintf("%d,%d", line, line), g.Commit, "--", path)
+	cmd.Dir = g.Dir
+	out, err := cmd.Output()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to blame %s:%d at %q: %v", path, line, g.Commit, err)
+	}
+	const prefix = "committer-time "
+	for _, l := range strings.Split(string(out), "\n") {
+		if !strings.HasPrefix(l, prefix) {
+			continue
+		}
+		sec, err := strconv.ParseInt(strings.TrimPrefix(l, prefix), 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to parse blame of %s:%d: %v", path, line, err)
+		}
+		return time.Unix(sec, 0), nil
+	}
+	return time.Time{}, fmt.Errorf("no committer time in blame of %s:%d", path, line)
+}
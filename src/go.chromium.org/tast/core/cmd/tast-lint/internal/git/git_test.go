@@ -274,3 +274,26 @@ func testIsSymlink(t *testing.T, commit string) {
 		}
 	}
 }
+
+func TestBlameTime(t *testing.T) {
+	t.Parallel()
+	repoDir := newTestRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	g := git.New(repoDir, "HEAD")
+	want, err := g.CommitTime()
+	if err != nil {
+		t.Fatal("CommitTime failed: ", err)
+	}
+	if got, err := g.BlameTime(testName, 1); err != nil {
+		t.Errorf("BlameTime(%q, 1) failed: %v", testName, err)
+	} else if !got.Equal(want) {
+		t.Errorf("BlameTime(%q, 1) = %v; want %v", testName, got, want)
+	}
+	if _, err := g.BlameTime(untrackedName, 1); err == nil {
+		t.Errorf("BlameTime(%q, 1) unexpectedly succeeded", untrackedName)
+	}
+	if _, err := git.New(repoDir, "").BlameTime(testName, 1); err == nil {
+		t.Error("BlameTime unexpectedly succeeded in work tree")
+	}
+}
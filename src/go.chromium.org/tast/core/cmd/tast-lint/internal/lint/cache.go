@@ -0,0 +1,198 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package lint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"go.chromium.org/tast/core/cmd/tast-lint/internal/check"
+	"go.chromium.org/tast/core/cmd/tast-lint/internal/git"
+)
+
+// cacheFormatVersion should be incremented when the format of cache files or
+// the way issues are found changes incompatibly.
+const cacheFormatVersion = 1
+
+// issueCache is a persistent cache of issues found in Go files. It allows
+// repeated runs to skip checking packages none of whose Go files changed since
+// the last run. Since some checks inspect other files in the same package, a
+// change to any file in a package invalidates issues of all files in it.
+type issueCache struct {
+	path string
+
+	mu   sync.Mutex // guards data
+	data cacheData
+}
+
+// cacheData is the content of a cache file.
+type cacheData struct {
+	// Key identifies the tast-lint executable and options issues were found
+	// with. Issues found with a different key are discarded.
+	Key string `json:"key"`
+	// Packages maps directories to results of packages in them.
+	Packages map[string]*cachedPackage `json:"packages"`
+}
+
+// cachedPackage holds issues found in files of a package.
+type cachedPackage struct {
+	// Hashes maps paths of all Go files in the package to SHA-256 hashes of
+	// their content at the time issues were found.
+	Hashes map[string]string `json:"hashes"`
+	// Files maps paths of checked files to their results.
+	Files map[string]*cachedFile `json:"files"`
+}
+
+// cachedFile holds issues found in a file.
+type cachedFile struct {
+	// Status is the status of the file the issues were found with. Some
+	// checks are applied to newly added files only.
+	Status git.CommitStatus `json:"status"`
+	Issues []*check.Issue   `json:"issues"`
+}
+
+// loadIssueCache loads a cache file at path. Issues in the file are ignored
+// unless they were found with key. A missing or broken cache file results in
+// an empty cache.
+func loadIssueCache(path, key string) *issueCache {
+	c := &issueCache{path: path}
+	if b, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(b, &c.data); err != nil || c.data.Key != key {
+			c.data = cacheData{}
+		}
+	}
+	c.data.Key = key
+	if c.data.Packages == nil {
+		c.data.Packages = make(map[string]*cachedPackage)
+	}
+	return c
+}
+
+// lookup returns issues of files in the package in dir found before. hashes
+// should be the current hashes of Go files in the package as returned by
+// packageHashes. It returns false if the package changed or any of files
+// have not been checked with the same status.
+func (c *issueCache) lookup(dir string, hashes map[string]string, files []git.CommitFile) (map[string][]*check.Issue, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pkg, ok := c.data.Packages[dir]
+	if !ok || !sameHashes(pkg.Hashes, hashes) {
+		return nil, false
+	}
+	issues := make(map[string][]*check.Issue)
+	for _, f := range files {
+		cf, ok := pkg.Files[f.Path]
+		if !ok || cf.Status != f.Status {
+			return nil, false
+		}
+		issues[f.Path] = cf.Issues
+	}
+	return issues, true
+}
+
+// store records issues found in a file of the package in dir. hashes should be
+// the hashes of Go files in the package the issues were found with. Issues of
+// other files in the package are discarded if the package changed.
+func (c *issueCache) store(dir string, hashes map[string]string, file git.CommitFile, issues []*check.Issue) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pkg, ok := c.data.Packages[dir]
+	if !ok || !sameHashes(pkg.Hashes, hashes) {
+		pkg = &cachedPackage{Hashes: hashes, Files: make(map[string]*cachedFile)}
+		c.data.Packages[dir] = pkg
+	}
+	pkg.Files[file.Path] = &cachedFile{Status: file.Status, Issues: issues}
+}
+
+// save writes the cache to the cache file.
+func (c *issueCache) save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b, err := json.Marshal(&c.data)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+	// Write to a temporary file first so that concurrent runs never see a
+	// partially written cache file.
+	f, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), c.path)
+}
+
+// packageHashes returns SHA-256 hashes of the content of Go files in dir,
+// keyed by their paths.
+func packageHashes(g *git.Git, dir string) (map[string]string, error) {
+	fns, err := g.ListDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files at %s: %v", dir, err)
+	}
+	hashes := make(map[string]string)
+	for _, fn := range fns {
+		if !strings.HasSuffix(fn, ".go") {
+			continue
+		}
+		path := filepath.Join(dir, fn)
+		b, err := g.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", path, err)
+		}
+		sum := sha256.Sum256(b)
+		hashes[path] = hex.EncodeToString(sum[:])
+	}
+	return hashes, nil
+}
+
+// sameHashes returns true if a and b contain the same files with the same
+// hashes.
+func sameHashes(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for path, h := range a {
+		if b[path] != h {
+			return false
+		}
+	}
+	return true
+}
+
+// cacheKey returns a key identifying the running executable and options
+// affecting issues found. Options are given as data of files and values.
+func cacheKey(options ...interface{}) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "version:%d\n", cacheFormatVersion)
+	// Issues found by a different build of tast-lint may differ.
+	if exe, err := os.Executable(); err == nil {
+		if fi, err := os.Stat(exe); err == nil {
+			fmt.Fprintf(h, "exe:%s:%d:%d\n", exe, fi.Size(), fi.ModTime().UnixNano())
+		}
+	}
+	for _, o := range options {
+		fmt.Fprintf(h, "%#v\n", o)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
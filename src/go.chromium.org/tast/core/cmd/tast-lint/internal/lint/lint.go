@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"go/ast"
 	"go/format"
+	"go/parser"
 	"go/token"
 	"os"
 	"os/exec"
@@ -18,6 +19,7 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 
@@ -155,8 +157,22 @@ func isNewlyAddedPackage(g *git.Git, dir string, paths []git.CommitFile) (bool,
 	return true, nil
 }
 
-// checkAll runs all checks against paths.
-func checkAll(g *git.Git, paths []git.CommitFile, debug, fix bool) ([]*check.Issue, error) {
+// checkOptions contains options of checks parsed from Options.
+type checkOptions struct {
+	allowlist        *check.ContactsAllowlist // nil if Contacts are not checked against team aliases
+	denylist         *check.DescDenylist      // nil if Desc is not checked against denied words
+	depsMap          *check.ParamDepsMap
+	minPromotionDays int
+	budget           check.TestFuncBudget
+	cache            *issueCache // nil if issues are not cached
+}
+
+// checkAll runs all checks against paths. If opts.cache is not nil, issues of
+// Go files in packages unchanged since they were cached are taken from the
+// cache instead of checking the files again.
+func checkAll(g *git.Git, paths []git.CommitFile, debug, fix bool, opts *checkOptions) ([]*check.Issue, error) {
+	cache := opts.cache
+
 	cp := newCachedParser(g)
 	fs := cp.fs
 
@@ -221,23 +237,47 @@ func checkAll(g *git.Git, paths []git.CommitFile, debug, fix bool) ([]*check.Iss
 			allIssues = append(allIssues, check.PackageComment(fs, pkg)...)
 		}
 
+		var hashes map[string]string
+		var cached map[string][]*check.Issue
+		hit := false
+		if cache != nil {
+			if hashes, err = packageHashes(g, dir); err != nil {
+				return nil, err
+			}
+			cached, hit = cache.lookup(dir, hashes, cfs)
+			if hit && debug {
+				fmt.Printf("Using cached issues for: %s\n", dir)
+			}
+		}
+		dir := dir
+		// skip records path as having no issues, so that the package can be
+		// found in cache next time.
+		skip := func(path git.CommitFile) {
+			if cache != nil && !hit {
+				cache.store(dir, hashes, path, nil)
+			}
+		}
+
 		for _, path := range cfs {
 			// Exempt protoc-generated Go files from lint checks.
 			if strings.HasSuffix(path.Path, ".pb.go") {
+				skip(path)
 				continue
 			}
 			if s, err := g.IsSymlink(path.Path); err != nil {
 				return nil, err
 			} else if s {
+				skip(path)
 				continue
 			}
 
 			f, ok := pkg.Files[path.Path] // take ast.File from parsed package
 			if !ok {
+				skip(path)
 				continue
 			}
 
-			if debug {
+			if debug && !hit {
 				fmt.Printf("Checking file: %s\n", path.Path)
 			}
 			mux.Lock()
@@ -245,14 +285,30 @@ func checkAll(g *git.Git, paths []git.CommitFile, debug, fix bool) ([]*check.Iss
 			fileIssues = append(fileIssues, nil)
 			mux.Unlock()
 			path := path
+			cachedIssues := cached[path.Path]
 			eg.Go(func() error {
-				data, err := g.ReadFile(path.Path)
-				if err != nil {
-					return err
+				is := cachedIssues
+				if !hit {
+					data, err := g.ReadFile(path.Path)
+					if err != nil {
+						return err
+					}
+					is, err = checkFile(path, data, debug, fs, f, fix, opts)
+					if err != nil {
+						return err
+					}
+					if cache != nil {
+						cache.store(dir, hashes, path, is)
+					}
 				}
-				is, err := checkFile(path, data, debug, fs, f, fix)
-				if err != nil {
-					return err
+				// Promotions are checked against the history rather than
+				// the content of the file, so they are never cached.
+				if g.Commit != "" && opts.minPromotionDays > 0 && path.Status == git.Modified && isUserFile(path.Path) {
+					pis, err := checkPromotionPeriod(g, path.Path, fs, f, opts.minPromotionDays)
+					if err != nil {
+						return err
+					}
+					is = append(is, pis...)
 				}
 				mux.Lock()
 				fileIssues[i] = is
@@ -273,7 +329,7 @@ func checkAll(g *git.Git, paths []git.CommitFile, debug, fix bool) ([]*check.Iss
 }
 
 // checkFile checks all the issues in the Go file in the given path. If fix is true, it automatically fixes f.
-func checkFile(path git.CommitFile, data []byte, debug bool, fs *token.FileSet, f *ast.File, fix bool) ([]*check.Issue, error) {
+func checkFile(path git.CommitFile, data []byte, debug bool, fs *token.FileSet, f *ast.File, fix bool, opts *checkOptions) ([]*check.Issue, error) {
 	var issues []*check.Issue
 	issues = append(issues, check.Golint(path.Path, data, debug)...)
 	issues = append(issues, check.Comments(fs, f)...)
@@ -298,12 +354,20 @@ func checkFile(path git.CommitFile, data []byte, debug bool, fs *token.FileSet,
 
 	if isUserFile(path.Path) {
 		issues = append(issues, check.TestDeclarations(fs, f, path, fix)...)
+		issues = append(issues, check.ContactsTeamAlias(fs, f, opts.allowlist)...)
+		issues = append(issues, check.DescStyle(fs, f, opts.denylist)...)
+		issues = append(issues, check.ParamDeps(fs, f, opts.depsMap)...)
+		issues = append(issues, check.ParamNames(fs, f)...)
+		issues = append(issues, check.TestFuncSize(fs, f, opts.budget)...)
 		issues = append(issues, check.Exports(fs, f)...)
 		issues = append(issues, check.ForbiddenBundleImports(fs, f)...)
 		issues = append(issues, check.ForbiddenCalls(fs, f, fix)...)
 		issues = append(issues, check.ForbiddenImports(fs, f)...)
+		issues = append(issues, check.ForbiddenLoggers(fs, f, fix)...)
+		issues = append(issues, check.ContextBackground(fs, f, fix)...)
 		issues = append(issues, check.WarningCalls(fs, f, fix)...)
 		issues = append(issues, check.InterFileRefs(fs, f)...)
+		issues = append(issues, check.SharedState(fs, f)...)
 		issues = append(issues, check.Messages(fs, f, fix)...)
 		issues = append(issues, check.VerifyTestingStateStruct(fs, f)...)
 		issues = append(issues, check.NoHardcodedUserDirs(fs, f)...)
@@ -312,10 +376,12 @@ func checkFile(path git.CommitFile, data []byte, debug bool, fs *token.FileSet,
 		issues = append(issues, check.VerifyMainlineAttrs(fs, f)...)
 		issues = append(issues, check.VerifyVMStableAttrs(fs, f)...)
 		issues = append(issues, check.VerifyFirmwareAttrs(fs, f)...)
+		issues = append(issues, check.VerifyKnownAttrs(fs, f)...)
 	}
 
 	if isSupportPackageFile(path.Path) {
 		issues = append(issues, check.VerifyTestingStateParam(fs, f)...)
+		issues = append(issues, check.BlockingCalls(fs, f)...)
 	}
 
 	if path.Status == git.Added {
@@ -355,6 +421,32 @@ func checkFile(path git.CommitFile, data []byte, debug bool, fs *token.FileSet,
 	return issues, nil
 }
 
+// checkPromotionPeriod checks that tests in the Go file in the given path
+// promoted from informational to critical in the commit have been registered
+// for at least minDays days, based on git blame of the file before the commit.
+func checkPromotionPeriod(g *git.Git, path string, fs *token.FileSet, f *ast.File, minDays int) ([]*check.Issue, error) {
+	parent := git.New(g.Dir, g.Commit+"^")
+	oldData, err := parent.ReadFile(path)
+	if err != nil {
+		// The file did not exist before the commit, e.g. it was renamed.
+		return nil, nil
+	}
+	oldFS := token.NewFileSet()
+	oldF, err := parser.ParseFile(oldFS, path, oldData, parser.ParseComments)
+	if err != nil {
+		return nil, nil
+	}
+	now, err := g.CommitTime()
+	if err != nil {
+		return nil, err
+	}
+	registeredAt := func(line int) (time.Time, error) {
+		return parent.BlameTime(path, line)
+	}
+	issues := check.VerifyPromotionPeriod(fs, f, oldFS, oldF, registeredAt, now, minDays)
+	return check.DropIgnoredIssues(issues, fs, f), nil
+}
+
 // navigateGitRoot detects as well as change current directory to git root directory
 // and returns the path difference between these two directories with error (if any).
 func navigateGitRoot() (string, error) {
@@ -387,8 +479,94 @@ func navigateGitRoot() (string, error) {
 // ErrNoTarget is returned by Run when there was no target to check.
 var ErrNoTarget = errors.New("no target to check")
 
+// Options contains options for Run.
+type Options struct {
+	// ContactsAllowlist, if not empty, is a path to a file listing team
+	// aliases (see check.ParseContactsAllowlist). Tests and fixtures are
+	// required to list at least one of them in Contacts.
+	ContactsAllowlist string
+
+	// DescDenylist, if not empty, is a path to a file listing words which
+	// should not appear in Desc (see check.ParseDescDenylist).
+	DescDenylist string
+
+	// ParamDepsMap, if not empty, is a path to a file mapping keywords in
+	// Params to dependencies they imply (see check.ParseParamDepsMap).
+	// check.DefaultParamDepsMap is used if it is empty.
+	ParamDepsMap string
+
+	// MinPromotionDays, if positive, is the minimum number of days tests
+	// promoted from informational to critical in the checked commit are
+	// required to have been registered before the commit.
+	MinPromotionDays int
+
+	// Budget is the budget test functions are required to fit in (see
+	// check.TestFuncSize).
+	Budget check.TestFuncBudget
+
+	// CacheFile, if not empty, is a path to a file where issues are cached
+	// across runs, so that only packages changed since the last run are
+	// checked again. The cache is not used if fix is true.
+	CacheFile string
+}
+
 // Run runs lint checks and returns found issues without printing them to users.
-func Run(commit string, debug, fix bool, args []string) ([]*check.Issue, error) {
+// opts can be nil to use the default options.
+func Run(commit string, debug, fix bool, opts *Options, args []string) ([]*check.Issue, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	// Options affecting issues are part of the key of cached issues.
+	cacheOptions := []interface{}{opts.Budget}
+
+	var allowlist *check.ContactsAllowlist
+	if opts.ContactsAllowlist != "" {
+		data, err := os.ReadFile(opts.ContactsAllowlist)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read contacts allowlist")
+		}
+		cacheOptions = append(cacheOptions, data)
+		allowlist, err = check.ParseContactsAllowlist(opts.ContactsAllowlist, data)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse contacts allowlist")
+		}
+	}
+	var denylist *check.DescDenylist
+	if opts.DescDenylist != "" {
+		data, err := os.ReadFile(opts.DescDenylist)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read Desc denylist")
+		}
+		cacheOptions = append(cacheOptions, data)
+		denylist, err = check.ParseDescDenylist(opts.DescDenylist, data)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse Desc denylist")
+		}
+	}
+	depsMap := check.DefaultParamDepsMap()
+	if opts.ParamDepsMap != "" {
+		data, err := os.ReadFile(opts.ParamDepsMap)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read Params deps map")
+		}
+		cacheOptions = append(cacheOptions, data)
+		depsMap, err = check.ParseParamDepsMap(opts.ParamDepsMap, data)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse Params deps map")
+		}
+	}
+
+	// Resolve the cache file path before changing the current directory.
+	var cache *issueCache
+	if opts.CacheFile != "" && !fix {
+		p, err := filepath.Abs(opts.CacheFile)
+		if err != nil {
+			return nil, err
+		}
+		cache = loadIssueCache(p, cacheKey(cacheOptions...))
+	}
+
 	// Changing current directory to the Git root directory to aid the operations of git.go
 	deltaPath, err := navigateGitRoot()
 	if err != nil {
@@ -405,5 +583,21 @@ func Run(commit string, debug, fix bool, args []string) ([]*check.Issue, error)
 		return nil, ErrNoTarget
 	}
 
-	return checkAll(g, files, debug, fix)
+	issues, err := checkAll(g, files, debug, fix, &checkOptions{
+		allowlist:        allowlist,
+		denylist:         denylist,
+		depsMap:          depsMap,
+		minPromotionDays: opts.MinPromotionDays,
+		budget:           opts.Budget,
+		cache:            cache,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if cache != nil {
+		if err := cache.save(); err != nil {
+			return nil, errors.Wrap(err, "failed to save cache")
+		}
+	}
+	return issues, nil
 }
@@ -5,8 +5,10 @@
 package lint_test
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -133,7 +135,7 @@ func TestRun_TargetSelection(t *testing.T) {
 			},
 		},
 	} {
-		issues, err := lint.Run(tc.commit, false, false, tc.args)
+		issues, err := lint.Run(tc.commit, false, false, nil, tc.args)
 		if err == lint.ErrNoTarget {
 			issues = nil
 		} else if err != nil {
@@ -212,7 +214,7 @@ func f() error { return errors.New("hello") }
 			t.Fatalf("Failed to write files: %v", err)
 		}
 
-		issues, err := lint.Run("", false, false, nil)
+		issues, err := lint.Run("", false, false, nil, nil)
 		if err != nil {
 			t.Errorf("Run failed for %s: %v", tc.check, err)
 			continue
@@ -227,3 +229,125 @@ func f() error { return errors.New("hello") }
 		}
 	}
 }
+
+// TestRun_PromotionPeriod checks that promotions of informational tests are
+// checked against git blame of the parent commit.
+func TestRun_PromotionPeriod(t *testing.T) {
+	setUpGitRepo(t)
+
+	const path = "src/go.chromium.org/tast-tests/cros/local/bundles/cros/example/pass.go"
+	const codeTmpl = `// Package example is an example.
+package example
+
+import (
+	"context"
+
+	"go.chromium.org/tast/core/testing"
+)
+
+func init() {
+	testing.AddTest(&testing.Test{
+		Func:     Pass,
+		Desc:     "Always passes",
+		Contacts: []string{"tast-owners@google.com"},
+		Attr:     []string{%s},
+	})
+}
+
+// Pass always passes.
+func Pass(ctx context.Context, s *testing.State) {}
+`
+	for _, attrs := range []string{`"group:mainline", "informational"`, `"group:mainline"`} {
+		if err := testutil.WriteFiles(".", map[string]string{path: fmt.Sprintf(codeTmpl, attrs)}); err != nil {
+			t.Fatalf("Failed to write files: %v", err)
+		}
+		if err := exec.Command("git", "add", path).Run(); err != nil {
+			t.Fatalf("git add failed: %v", err)
+		}
+		if err := exec.Command("git", "commit", "-m", "commit").Run(); err != nil {
+			t.Fatalf("git commit failed: %v", err)
+		}
+	}
+
+	for _, tc := range []struct {
+		minDays int
+		want    bool
+	}{
+		{0, false},
+		{14, true},
+	} {
+		issues, err := lint.Run("HEAD", false, false, &lint.Options{MinPromotionDays: tc.minDays}, nil)
+		if err != nil {
+			t.Fatalf("Run(minPromotionDays=%d) failed: %v", tc.minDays, err)
+		}
+		got := false
+		for _, issue := range issues {
+			if strings.Contains(issue.Msg, "promoted to critical") {
+				got = true
+			}
+		}
+		if got != tc.want {
+			t.Errorf("Run(minPromotionDays=%d) reported promotion issue = %v; want %v; issues: %v", tc.minDays, got, tc.want, issues)
+		}
+	}
+}
+
+// TestRun_Cache checks that issues are taken from the cache file only for
+// packages none of whose files changed.
+func TestRun_Cache(t *testing.T) {
+	setUpGitRepo(t)
+
+	const (
+		cacheFile = ".tast-lint-cache"
+		badCode   = "package pkg\n// This is bad comment\nfunc init() {}\n"
+		pathA     = "src/go.chromium.org/tast/core/testing/aaa.go"
+		pathB     = "src/go.chromium.org/tast/core/errors/bbb.go"
+	)
+	if err := testutil.WriteFiles(".", map[string]string{
+		pathA: badCode,
+		pathB: badCode,
+	}); err != nil {
+		t.Fatalf("Failed to write files: %v", err)
+	}
+
+	// cachedFiles runs lint and returns whether issues of each file were
+	// taken from the cache.
+	cachedFiles := func() map[string]bool {
+		t.Helper()
+		issues, err := lint.Run("", false, false, &lint.Options{CacheFile: cacheFile}, nil)
+		if err != nil {
+			t.Fatal("Run failed: ", err)
+		}
+		got := make(map[string]bool)
+		for _, issue := range issues {
+			got[issue.Pos.Filename] = strings.HasPrefix(issue.Msg, "cached: ")
+		}
+		// Mark issues in the cache file to tell them from fresh ones.
+		b, err := os.ReadFile(cacheFile)
+		if err != nil {
+			t.Fatal("Failed to read cache: ", err)
+		}
+		b = []byte(strings.ReplaceAll(strings.ReplaceAll(string(b), `"Msg":"cached: `, `"Msg":"`), `"Msg":"`, `"Msg":"cached: `))
+		if err := os.WriteFile(cacheFile, b, 0644); err != nil {
+			t.Fatal("Failed to write cache: ", err)
+		}
+		return got
+	}
+
+	if diff := cmp.Diff(cachedFiles(), map[string]bool{pathA: false, pathB: false}); diff != "" {
+		t.Errorf("First run mismatch (-got +want):\n%s", diff)
+	}
+	if diff := cmp.Diff(cachedFiles(), map[string]bool{pathA: true, pathB: true}); diff != "" {
+		t.Errorf("Second run mismatch (-got +want):\n%s", diff)
+	}
+
+	// Adding a file to the package of aaa.go invalidates its issues.
+	if err := testutil.WriteFiles(".", map[string]string{
+		"src/go.chromium.org/tast/core/testing/ccc.go": "package pkg\n",
+	}); err != nil {
+		t.Fatalf("Failed to write files: %v", err)
+	}
+	if diff := cmp.Diff(cachedFiles(), map[string]bool{pathA: false, pathB: true}); diff != "" {
+		t.Errorf("Run after change mismatch (-got +want):\n%s", diff)
+	}
+}
@@ -65,9 +65,23 @@ func main() {
 	commit := flag.String("commit", "", "if set, checks files in the specified Git commit")
 	debug := flag.Bool("debug", false, "enables debug outputs")
 	fix := flag.Bool("fix", false, "modifies auto-fixable errors automatically")
+	contactsAllowlist := flag.String("contactsallowlist", "", "if set, requires Contacts to include a team alias matching a pattern in the specified file")
+	descDenylist := flag.String("descdenylist", "", "if set, disallows words listed in the specified file in Desc")
+	paramDepsMap := flag.String("paramdepsmap", "", "if set, requires Params to declare dependencies implied by keywords as listed in the specified file instead of the default table")
+	minPromotionDays := flag.Int("minpromotiondays", 14, "with -commit, requires tests promoted from informational to critical to have been registered at least this many days before (0 to disable)")
+	maxTestComplexity := flag.Int("maxtestcomplexity", 30, "maximum cyclomatic complexity of test functions (0 to disable)")
+	maxTestLines := flag.Int("maxtestlines", 300, "maximum number of lines in test functions (0 to disable)")
+	cacheFile := flag.String("cache", "", "if set, caches issues in the specified file (e.g. .tast-lint-cache) to check only packages changed since the last run")
 	flag.Parse()
 
-	issues, err := lint.Run(*commit, *debug, *fix, flag.Args())
+	issues, err := lint.Run(*commit, *debug, *fix, &lint.Options{
+		ContactsAllowlist: *contactsAllowlist,
+		DescDenylist:      *descDenylist,
+		ParamDepsMap:      *paramDepsMap,
+		MinPromotionDays:  *minPromotionDays,
+		Budget:            check.TestFuncBudget{MaxComplexity: *maxTestComplexity, MaxLines: *maxTestLines},
+		CacheFile:         *cacheFile,
+	}, flag.Args())
 	if err == lint.ErrNoTarget {
 		flag.Usage()
 		return
@@ -0,0 +1,84 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/google/subcommands"
+
+	"go.chromium.org/tast/core/cmd/tast/internal/clean"
+	"go.chromium.org/tast/core/cmd/tast/internal/run/config"
+	"go.chromium.org/tast/core/internal/logging"
+)
+
+// cleanCmd implements subcommands.Command to remove Tast files and processes
+// left on the DUT.
+type cleanCmd struct {
+	cfg      clean.Config
+	stdout   io.Writer
+	trunkDir string
+}
+
+var _ = subcommands.Command(&cleanCmd{})
+
+// newCleanCmd returns a new cleanCmd that will write results to stdout.
+func newCleanCmd(stdout io.Writer, trunkDir string) *cleanCmd {
+	return &cleanCmd{stdout: stdout, trunkDir: trunkDir}
+}
+
+func (*cleanCmd) Name() string     { return "clean" }
+func (*cleanCmd) Synopsis() string { return "remove Tast files and processes left on the DUT" }
+func (*cleanCmd) Usage() string {
+	return `Usage: clean [flag]... <target>
+
+Description:
+    Remove files left on the DUT by Tast, i.e. test bundles and data files
+    pushed with -build=true, stale output and temporary directories and the
+    stamp file of downloaded private bundles, and kill stale test runner and
+    bundle processes. Reclaimed space is printed.
+
+    Do not run this while tests are running on the DUT.
+
+Target:
+    The target is an SSH connection spec of the form "[user@]host[:port]".
+
+Flag:
+`
+}
+
+func (c *cleanCmd) SetFlags(f *flag.FlagSet) {
+	home := os.Getenv("HOME")
+	f.StringVar(&c.cfg.KeyFile, "keyfile", config.DefaultKeyFile(c.trunkDir), "path to private SSH key")
+	f.StringVar(&c.cfg.KeyDir, "keydir", filepath.Join(home, ".ssh"), "directory containing SSH keys")
+	f.StringVar(&c.cfg.ProxyCommand, "proxycommand", "", "command to use to connect to the DUT")
+	f.BoolVar(&c.cfg.DryRun, "dryrun", false, "print what would be removed without removing it")
+}
+
+func (c *cleanCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	if len(f.Args()) != 1 {
+		logging.Info(ctx, "Missing target.\n\n"+c.Usage())
+		return subcommands.ExitUsageError
+	}
+	c.cfg.Target = f.Args()[0]
+
+	results, err := clean.Run(ctx, &c.cfg)
+	if err != nil {
+		logging.Info(ctx, "Failed to clean the DUT: ", err)
+		return subcommands.ExitFailure
+	}
+	if err := clean.Write(c.stdout, results, c.cfg.DryRun); err != nil {
+		logging.Info(ctx, "Failed to write results: ", err)
+		return subcommands.ExitFailure
+	}
+	if clean.HasError(results) {
+		return subcommands.ExitFailure
+	}
+	return subcommands.ExitSuccess
+}
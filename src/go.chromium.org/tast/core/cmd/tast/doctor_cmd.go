@@ -0,0 +1,86 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/google/subcommands"
+
+	"go.chromium.org/tast/core/cmd/tast/internal/doctor"
+	"go.chromium.org/tast/core/cmd/tast/internal/run/config"
+	"go.chromium.org/tast/core/internal/logging"
+)
+
+// doctorCmd implements subcommands.Command to diagnose the environment to
+// run tests.
+type doctorCmd struct {
+	cfg    doctor.Config
+	stdout io.Writer
+}
+
+var _ = subcommands.Command(&doctorCmd{})
+
+// newDoctorCmd returns a new doctorCmd that will write results to stdout.
+func newDoctorCmd(stdout io.Writer, trunkDir string) *doctorCmd {
+	return &doctorCmd{
+		cfg: doctor.Config{
+			TrunkDir: trunkDir,
+			// Check files installed to test images, which are used with -build=false.
+			LocalRunner:    "/usr/local/bin/local_test_runner",
+			LocalBundleDir: "/usr/local/libexec/tast/bundles/local",
+		},
+		stdout: stdout,
+	}
+}
+
+func (*doctorCmd) Name() string     { return "doctor" }
+func (*doctorCmd) Synopsis() string { return "diagnose the environment to run tests" }
+func (*doctorCmd) Usage() string {
+	return `Usage: doctor [flag]... <target>
+
+Description:
+    Check the environment to run tests against the DUT for common problems,
+    such as SSH reachability and authentication, SSH key setup, clock skew,
+    chroot setup, test bundles on the DUT, port forwards and stale known_hosts
+    entries, and print actions to fix them.
+
+Target:
+    The target is an SSH connection spec of the form "[user@]host[:port]".
+
+Flag:
+`
+}
+
+func (d *doctorCmd) SetFlags(f *flag.FlagSet) {
+	home := os.Getenv("HOME")
+	f.StringVar(&d.cfg.KeyFile, "keyfile", config.DefaultKeyFile(d.cfg.TrunkDir), "path to private SSH key")
+	f.StringVar(&d.cfg.KeyDir, "keydir", filepath.Join(home, ".ssh"), "directory containing SSH keys")
+	f.StringVar(&d.cfg.KnownHostsFile, "knownhosts", filepath.Join(home, ".ssh", "known_hosts"), "path to OpenSSH known_hosts file")
+	f.StringVar(&d.cfg.ProxyCommand, "proxycommand", "", "command to use to connect to the DUT")
+}
+
+func (d *doctorCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	if len(f.Args()) != 1 {
+		logging.Info(ctx, "Missing target.\n\n"+d.Usage())
+		return subcommands.ExitUsageError
+	}
+	d.cfg.Target = f.Args()[0]
+	d.cfg.InChroot = config.InChroot()
+
+	results := doctor.Run(ctx, &d.cfg)
+	if err := doctor.Write(d.stdout, results); err != nil {
+		logging.Info(ctx, "Failed to write results: ", err)
+		return subcommands.ExitFailure
+	}
+	if doctor.HasError(results) {
+		return subcommands.ExitFailure
+	}
+	return subcommands.ExitSuccess
+}
@@ -0,0 +1,73 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+
+	"github.com/google/subcommands"
+
+	"go.chromium.org/tast/core/cmd/tast/internal/flakes"
+	"go.chromium.org/tast/core/internal/logging"
+)
+
+// flakesCmd implements subcommands.Command to summarize flaky tests recorded
+// by "tast run -flakehistory".
+type flakesCmd struct {
+	runs   int
+	top    int
+	stdout io.Writer
+}
+
+var _ = subcommands.Command(&flakesCmd{})
+
+// newFlakesCmd returns a new flakesCmd that will write a summary to stdout.
+func newFlakesCmd(stdout io.Writer) *flakesCmd {
+	return &flakesCmd{stdout: stdout}
+}
+
+func (*flakesCmd) Name() string     { return "flakes" }
+func (*flakesCmd) Synopsis() string { return "summarize flaky tests across recent runs" }
+func (*flakesCmd) Usage() string {
+	return `Usage: flakes [flag]... <file>
+
+Description:
+    Summarize tests that both passed and failed in a flake history CSV file
+    written by "tast run -flakehistory=<file>", including tests that passed
+    after being retried with -retries. Tests are listed in descending order of
+    the ratio of failed attempts.
+
+Flag:
+`
+}
+
+func (c *flakesCmd) SetFlags(f *flag.FlagSet) {
+	f.IntVar(&c.runs, "runs", 20, "number of most recent runs to consider (0 for all runs)")
+	f.IntVar(&c.top, "top", 10, "maximum number of tests to list (0 for all tests)")
+}
+
+func (c *flakesCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	if len(f.Args()) != 1 {
+		logging.Info(ctx, "Missing flake history file.\n\n"+c.Usage())
+		return subcommands.ExitUsageError
+	}
+	if flakes.IsRemote(f.Args()[0]) {
+		logging.Info(ctx, "Flake history on remote endpoints can not be read; specify a local file")
+		return subcommands.ExitUsageError
+	}
+
+	recs, err := flakes.ReadFile(f.Args()[0])
+	if err != nil {
+		logging.Info(ctx, "Failed to read flake history: ", err)
+		return subcommands.ExitFailure
+	}
+	if err := flakes.WriteSummary(c.stdout, flakes.Summarize(recs, c.runs), c.top); err != nil {
+		logging.Info(ctx, "Failed to write summary: ", err)
+		return subcommands.ExitFailure
+	}
+	return subcommands.ExitSuccess
+}
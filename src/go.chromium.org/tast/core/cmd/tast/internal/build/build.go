@@ -13,6 +13,9 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
 
 	"go.chromium.org/tast/core/internal/logging"
 	"go.chromium.org/tast/core/internal/timing"
@@ -56,16 +59,31 @@ func Build(ctx context.Context, cfg *Config, tgts []*Target) error {
 		}
 	}
 
+	eg, ctx := errgroup.WithContext(ctx)
 	for _, tgt := range tgts {
-		if err := buildOne(ctx, tgt); err != nil {
-			return fmt.Errorf("failed to build %s: %v", tgt.Pkg, err)
+		tgt := tgt
+		eg.Go(func() error {
+			if err := buildOne(ctx, cfg, tgt); err != nil {
+				return fmt.Errorf("failed to build %s: %v", tgt.Pkg, err)
+			}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+
+	if cfg.CacheDir != "" {
+		if err := pruneCache(cfg.CacheDir); err != nil {
+			logging.Debugf(ctx, "Failed to prune build cache: %v", err)
 		}
 	}
 	return nil
 }
 
-// buildOne builds one executable.
-func buildOne(ctx context.Context, tgt *Target) error {
+// buildOne builds one executable. If cfg.CacheDir is set, an executable
+// built from the same sources is reused from the cache if any.
+func buildOne(ctx context.Context, cfg *Config, tgt *Target) error {
 	ctx, st := timing.Start(ctx, filepath.Base(tgt.Pkg))
 	defer st.End()
 
@@ -83,12 +101,7 @@ func buildOne(ctx context.Context, tgt *Target) error {
 		return fmt.Errorf("unknown arch %q", tgt.Arch)
 	}
 
-	flags := "-ldflags=-s -w"
-	if tgt.Debug {
-		flags = "-gcflags=all=-N -l"
-	}
-	cmd := exec.Command("go", "build", flags, "-o", tgt.Out, tgt.Pkg)
-	cmd.Env = append(os.Environ(),
+	env := append(os.Environ(),
 		"GOPATH="+strings.Join(tgt.Workspaces, ":"),
 		// Disable cgo and PIE on building Tast binaries. See:
 		// https://crbug.com/976196
@@ -97,12 +110,58 @@ func buildOne(ctx context.Context, tgt *Target) error {
 		// Tast in ChromeOS is built in GOPATH mode.
 		"GO111MODULE=off",
 		"GOPIE=0")
-	cmd.Env = append(cmd.Env, archEnvs...)
+	env = append(env, archEnvs...)
+
+	var cached string
+	if cfg.CacheDir != "" {
+		key, err := cacheKey(ctx, tgt, env)
+		if err != nil {
+			logging.Debugf(ctx, "Not using build cache for %s: %v", tgt.Pkg, err)
+		} else {
+			cached = cachePath(cfg.CacheDir, key)
+			if _, err := os.Stat(cached); err == nil {
+				logging.Debugf(ctx, "Using cached %s", filepath.Base(tgt.Pkg))
+				now := time.Now()
+				os.Chtimes(cached, now, now)
+				return copyExecutable(cached, tgt.Out)
+			}
+		}
+	}
 
+	ldflags := "-s -w"
+	args := []string{"build"}
+	if tgt.Debug {
+		ldflags = ""
+		args = append(args, "-gcflags=all=-N -l")
+	}
+	if tgt.Static {
+		// Use pure Go implementations of the resolver and user lookups,
+		// and ask the linker to never link libc dynamically.
+		ldflags = strings.TrimSpace(ldflags + " -extldflags=-static")
+		args = append(args, "-tags=netgo,osusergo")
+	}
+	if ldflags != "" {
+		args = append(args, "-ldflags="+ldflags)
+	}
+	args = append(args, "-o", tgt.Out, tgt.Pkg)
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Env = env
 	if out, err := cmd.CombinedOutput(); err != nil {
 		writeMultiline(ctx, string(out))
 		return err
 	}
+
+	if tgt.Static {
+		if err := VerifyStatic(tgt.Out, tgt.Arch); err != nil {
+			return err
+		}
+	}
+
+	if cached != "" {
+		if err := copyExecutable(tgt.Out, cached); err != nil {
+			logging.Debugf(ctx, "Failed to save %s to build cache: %v", filepath.Base(tgt.Pkg), err)
+		}
+	}
 	return nil
 }
 
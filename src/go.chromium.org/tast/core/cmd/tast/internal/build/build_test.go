@@ -129,6 +129,66 @@ func main() {
 	}
 }
 
+func TestBuildCache(t *testing.T) {
+	td := testutil.TempDir(t)
+	defer os.RemoveAll(td)
+
+	writeMain := func(msg string) {
+		code := fmt.Sprintf("package main\nfunc main() { print(%q) }\n", msg)
+		if err := testutil.WriteFiles(td, map[string]string{"ws/src/foo/main.go": code}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cacheDir := filepath.Join(td, "cache")
+	cfg := &build.Config{CacheDir: cacheDir}
+	tgt := &build.Target{
+		Pkg:        "foo",
+		Arch:       build.ArchHost,
+		Workspaces: []string{filepath.Join(td, "ws")},
+		Out:        filepath.Join(td, "out/foo"),
+	}
+
+	buildAndRun := func(want string) {
+		t.Helper()
+		os.Remove(tgt.Out)
+		if err := build.Build(context.Background(), cfg, []*build.Target{tgt}); err != nil {
+			t.Fatal("Failed to build: ", err)
+		}
+		if out, err := exec.Command(tgt.Out).CombinedOutput(); err != nil {
+			t.Errorf("Failed to run %s: %v", tgt.Out, err)
+		} else if string(out) != want {
+			t.Errorf("%s printed %q; want %q", tgt.Out, string(out), want)
+		}
+	}
+	numCached := func() int {
+		t.Helper()
+		ents, err := os.ReadDir(cacheDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(ents)
+	}
+
+	writeMain("foo")
+	buildAndRun("foo")
+	if n := numCached(); n != 1 {
+		t.Errorf("Got %d cache entries after the first build; want 1", n)
+	}
+
+	// Building again from the same sources should reuse the cache.
+	buildAndRun("foo")
+	if n := numCached(); n != 1 {
+		t.Errorf("Got %d cache entries after rebuilding unchanged sources; want 1", n)
+	}
+
+	writeMain("bar")
+	buildAndRun("bar")
+	if n := numCached(); n != 2 {
+		t.Errorf("Got %d cache entries after changing sources; want 2", n)
+	}
+}
+
 func TestBuildBadWorkspace(t *testing.T) {
 	td := testutil.TempDir(t)
 	defer os.RemoveAll(td)
@@ -0,0 +1,154 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package build
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// maxCacheEntries is the maximum number of executables kept in a build cache.
+// Older entries are removed when the cache grows beyond this.
+const maxCacheEntries = 30
+
+// listSourcesFormat is a template passed to "go list -f" to list source files
+// of non-standard packages an executable depends on.
+const listSourcesFormat = `{{if not .Standard}}` +
+	`{{range .GoFiles}}{{$.Dir}}/{{.}}{{"\n"}}{{end}}` +
+	`{{range .EmbedFiles}}{{$.Dir}}/{{.}}{{"\n"}}{{end}}` +
+	`{{end}}`
+
+// cacheKey computes a content-addressed key of an executable built for tgt
+// with the Go command environment env. The key covers the Go toolchain
+// version, the target architecture, build flags and contents of all
+// non-standard source files the executable depends on.
+func cacheKey(ctx context.Context, tgt *Target, env []string) (string, error) {
+	goCmd := func(args ...string) ([]byte, error) {
+		cmd := exec.CommandContext(ctx, "go", args...)
+		cmd.Env = env
+		out, err := cmd.Output()
+		if err != nil {
+			if ee, ok := err.(*exec.ExitError); ok {
+				return nil, fmt.Errorf("go %s: %v: %s", args[0], err, strings.TrimSpace(string(ee.Stderr)))
+			}
+			return nil, fmt.Errorf("go %s: %v", args[0], err)
+		}
+		return out, nil
+	}
+
+	ver, err := goCmd("env", "GOVERSION", "GOOS", "GOARCH", "GOARM")
+	if err != nil {
+		return "", err
+	}
+	out, err := goCmd("list", "-deps", "-f", listSourcesFormat, tgt.Pkg)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "pkg %s\ndebug %v\n%s", tgt.Pkg, tgt.Debug, ver)
+	if tgt.Static {
+		// Keep keys of non-static executables unchanged.
+		fmt.Fprint(h, "static\n")
+	}
+	for _, p := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if p == "" {
+			continue
+		}
+		if err := hashFile(h, p); err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashFile writes the path and the contents of the file at p to w.
+func hashFile(w io.Writer, p string) error {
+	f, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fmt.Fprintf(w, "file %s\n", p)
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// cachePath returns the path where an executable with key is cached in dir.
+func cachePath(dir, key string) string {
+	return filepath.Join(dir, key)
+}
+
+// copyExecutable copies an executable file at src to dst atomically.
+func copyExecutable(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	sf, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer sf.Close()
+
+	df, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(df.Name())
+	if _, err := io.Copy(df, sf); err != nil {
+		df.Close()
+		return err
+	}
+	if err := df.Chmod(0755); err != nil {
+		df.Close()
+		return err
+	}
+	if err := df.Close(); err != nil {
+		return err
+	}
+	return os.Rename(df.Name(), dst)
+}
+
+// pruneCache removes the least recently used entries in the cache dir so that
+// at most maxCacheEntries entries remain.
+func pruneCache(dir string) error {
+	ents, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	type entry struct {
+		path  string
+		mtime int64
+	}
+	var es []entry
+	for _, ent := range ents {
+		if strings.HasPrefix(ent.Name(), ".") {
+			continue
+		}
+		fi, err := ent.Info()
+		if err != nil {
+			continue
+		}
+		es = append(es, entry{filepath.Join(dir, ent.Name()), fi.ModTime().UnixNano()})
+	}
+	if len(es) <= maxCacheEntries {
+		return nil
+	}
+	sort.Slice(es, func(i, j int) bool { return es[i].mtime > es[j].mtime })
+	for _, e := range es[maxCacheEntries:] {
+		if err := os.Remove(e.path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
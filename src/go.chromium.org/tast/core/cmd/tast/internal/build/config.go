@@ -26,6 +26,9 @@ type Config struct {
 	// TastWorkspace is the path to the Go workspace containing Tast framework. This path is used to perform
 	// source compatibility version checks. If it is empty, no check is performed.
 	TastWorkspace string
+	// CacheDir is the path to a directory where built executables are cached
+	// by the contents of their sources. If it is empty, no cache is used.
+	CacheDir string
 }
 
 // Target describes a Go executable package to build and configurations needed to built it.
@@ -43,6 +46,9 @@ type Target struct {
 	Out string
 	// Debug is a flag indicating whether the binary should be built with debug symbols.
 	Debug bool
+	// Static is a flag indicating whether the binary should be fully static, so that it runs
+	// on any Linux system of Arch regardless of its libc.
+	Static bool
 }
 
 // LocalBundlePrefix returns the local bundle prefix for a particular bundle.
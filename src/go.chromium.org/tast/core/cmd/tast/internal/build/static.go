@@ -0,0 +1,56 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package build
+
+import (
+	"debug/elf"
+	"fmt"
+)
+
+// archToELF maps from a userland architecture name to the ELF class and
+// machine of executables running on it.
+var archToELF = map[string]struct {
+	class   elf.Class
+	machine elf.Machine
+}{
+	"x86_64":  {elf.ELFCLASS64, elf.EM_X86_64},
+	"armv7l":  {elf.ELFCLASS32, elf.EM_ARM},
+	"aarch64": {elf.ELFCLASS64, elf.EM_AARCH64},
+}
+
+// VerifyStatic checks that the executable at path is statically linked and
+// built for arch, so that it runs on any Linux system of arch regardless of
+// its libc. If arch is ArchHost, only static linking is checked.
+func VerifyStatic(path, arch string) error {
+	f, err := elf.Open(path)
+	if err != nil {
+		return fmt.Errorf("%s is not an ELF executable: %v", path, err)
+	}
+	defer f.Close()
+
+	if arch != ArchHost {
+		want, ok := archToELF[arch]
+		if !ok {
+			return fmt.Errorf("unknown arch %q", arch)
+		}
+		if f.Class != want.class || f.Machine != want.machine {
+			return fmt.Errorf("%s is built for %v %v; want %v %v for %s", path, f.Class, f.Machine, want.class, want.machine, arch)
+		}
+	}
+
+	for _, p := range f.Progs {
+		if p.Type == elf.PT_INTERP {
+			return fmt.Errorf("%s is dynamically linked", path)
+		}
+	}
+	libs, err := f.ImportedLibraries()
+	if err != nil {
+		return fmt.Errorf("failed to read dynamic section of %s: %v", path, err)
+	}
+	if len(libs) > 0 {
+		return fmt.Errorf("%s depends on shared libraries %v", path, libs)
+	}
+	return nil
+}
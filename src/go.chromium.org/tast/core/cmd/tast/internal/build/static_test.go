@@ -0,0 +1,58 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package build_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.chromium.org/tast/core/cmd/tast/internal/build"
+	"go.chromium.org/tast/core/testutil"
+)
+
+func TestBuildStatic(t *testing.T) {
+	td := testutil.TempDir(t)
+	defer os.RemoveAll(td)
+
+	// The net and os/user packages use libc by default when cgo is available.
+	const mainCode = `package main
+import (
+	"net"
+	"os/user"
+)
+func main() {
+	net.LookupHost("localhost")
+	user.Current()
+}`
+	if err := testutil.WriteFiles(td, map[string]string{
+		"src/foo/main.go": mainCode,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	out := filepath.Join(td, "out", "foo")
+	tgt := &build.Target{
+		Pkg:        "foo",
+		Arch:       "x86_64",
+		Workspaces: []string{td},
+		Out:        out,
+		Static:     true,
+	}
+	if err := build.Build(context.Background(), &build.Config{}, []*build.Target{tgt}); err != nil {
+		t.Fatal("Failed to build: ", err)
+	}
+
+	if err := build.VerifyStatic(out, "x86_64"); err != nil {
+		t.Error("VerifyStatic failed: ", err)
+	}
+	if err := build.VerifyStatic(out, "aarch64"); err == nil {
+		t.Error("VerifyStatic succeeded for a mismatched arch")
+	}
+	if err := build.VerifyStatic(filepath.Join(td, "src/foo/main.go"), build.ArchHost); err == nil {
+		t.Error("VerifyStatic succeeded for a non-ELF file")
+	}
+}
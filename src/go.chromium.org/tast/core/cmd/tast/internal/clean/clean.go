@@ -0,0 +1,290 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package clean removes files and processes left on DUTs by Tast, such as
+// pushed test bundles and stale temporary directories.
+package clean
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.chromium.org/tast/core/errors"
+	"go.chromium.org/tast/core/shutil"
+	"go.chromium.org/tast/core/ssh"
+)
+
+// connectTimeout is the timeout for connecting to the DUT.
+const connectTimeout = 10 * time.Second
+
+// Item describes a set of files on the DUT to be removed together.
+type Item struct {
+	// Name is a human-readable name of the item.
+	Name string
+	// Patterns are shell glob patterns of files and directories to remove.
+	// They must not contain whitespaces or shell metacharacters other than
+	// glob ones.
+	Patterns []string
+}
+
+// DefaultItems are files left on DUTs by Tast that can be safely removed when
+// no test is running. Note that files installed to test images by Portage,
+// e.g. /usr/local/libexec/tast/bundles/local, are not included.
+var DefaultItems = []*Item{
+	{
+		Name: "Pushed bundles",
+		Patterns: []string{
+			"/usr/local/libexec/tast/bin_pushed",
+			"/usr/local/libexec/tast/bundles/local_pushed",
+		},
+	},
+	{
+		// Data files are orphaned once the pushed bundles using them are
+		// removed.
+		Name:     "Pushed data files",
+		Patterns: []string{"/usr/local/share/tast/data_pushed"},
+	},
+	{
+		Name: "Stale temporary directories",
+		Patterns: []string{
+			"/usr/local/tmp/tast_out.*",
+			"/usr/local/tmp/tast/run_tmp",
+			"/tmp/rpc-outdir.*",
+			"/var/tmp/tast_btmon.*",
+		},
+	},
+	{
+		// Private bundles are extracted over directories managed by
+		// Portage, so only the stamp file is removed to download them
+		// again on the next run with -downloadprivatebundles.
+		Name:     "Downloaded private bundles",
+		Patterns: []string{"/usr/local/share/tast/.private-bundles-downloaded"},
+	},
+}
+
+// DefaultProcessPattern is an extended regular expression matching command
+// lines of Tast processes on DUTs, i.e. the local test runner and bundles.
+const DefaultProcessPattern = `^(/usr/local/bin/local_test_runner|/usr/local/libexec/tast/)`
+
+// Config contains parameters of cleaning.
+type Config struct {
+	// Target is the DUT to clean, in the form of "[<user>@]host[:<port>]".
+	Target string
+	// KeyFile is the path to the private SSH key to connect to the DUT.
+	KeyFile string
+	// KeyDir is a directory containing private SSH keys.
+	KeyDir string
+	// ProxyCommand is the command to connect to the DUT, if any.
+	ProxyCommand string
+	// DryRun is whether to only report what would be removed.
+	DryRun bool
+	// Items are the files to remove. DefaultItems are removed if it is nil.
+	Items []*Item
+	// ProcessPattern is an extended regular expression matching command
+	// lines of stale processes to kill. DefaultProcessPattern is used if it
+	// is empty.
+	ProcessPattern string
+}
+
+// Result is a result of cleaning an item.
+type Result struct {
+	// Name is the name of the cleaned item.
+	Name string
+	// Paths are the files and directories found on the DUT.
+	Paths []string
+	// Bytes is the disk space used by Paths in bytes.
+	Bytes int64
+	// PIDs are the IDs of processes found on the DUT. It is set only for
+	// stale processes.
+	PIDs []int
+	// Err is set if cleaning failed.
+	Err error
+}
+
+// Run connects to the DUT and cleans it. Errors on individual items are
+// reported in results instead of an error.
+func Run(ctx context.Context, cfg *Config) ([]*Result, error) {
+	var o ssh.Options
+	if err := ssh.ParseTarget(cfg.Target, &o); err != nil {
+		return nil, err
+	}
+	o.KeyFile = cfg.KeyFile
+	o.KeyDir = cfg.KeyDir
+	o.ProxyCommand = cfg.ProxyCommand
+	o.ConnectTimeout = connectTimeout
+
+	conn, err := ssh.New(ctx, &o)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to %s", cfg.Target)
+	}
+	defer conn.Close(ctx)
+
+	return Clean(ctx, conn, cfg), nil
+}
+
+// Clean kills stale processes and removes files on the DUT connected with
+// conn. Processes are killed first so that they do not recreate files.
+func Clean(ctx context.Context, conn *ssh.Conn, cfg *Config) []*Result {
+	pattern := cfg.ProcessPattern
+	if pattern == "" {
+		pattern = DefaultProcessPattern
+	}
+	items := cfg.Items
+	if items == nil {
+		items = DefaultItems
+	}
+
+	results := []*Result{killProcesses(ctx, conn, pattern, cfg.DryRun)}
+	for _, item := range items {
+		results = append(results, removeItem(ctx, conn, item, cfg.DryRun))
+	}
+	return results
+}
+
+// killProcesses kills processes whose command lines match pattern.
+func killProcesses(ctx context.Context, conn *ssh.Conn, pattern string, dryRun bool) *Result {
+	res := &Result{Name: "Stale runner processes"}
+	// pgrep exits with 1 if no process matches.
+	out, err := conn.CommandContext(ctx, "sh", "-c", "pgrep -f "+shutil.Escape(pattern)+" || true").Output()
+	if err != nil {
+		res.Err = errors.Wrap(err, "failed to list processes")
+		return res
+	}
+	for _, f := range strings.Fields(string(out)) {
+		pid, err := strconv.Atoi(f)
+		if err != nil {
+			res.Err = errors.Errorf("unexpected pgrep output %q", out)
+			return res
+		}
+		res.PIDs = append(res.PIDs, pid)
+	}
+	if dryRun || len(res.PIDs) == 0 {
+		return res
+	}
+
+	args := []string{"kill", "-KILL"}
+	for _, pid := range res.PIDs {
+		args = append(args, strconv.Itoa(pid))
+	}
+	// Processes may have exited in the meantime, so errors are ignored.
+	conn.CommandContext(ctx, args[0], args[1:]...).Run()
+	return res
+}
+
+// removeItem removes files of item.
+func removeItem(ctx context.Context, conn *ssh.Conn, item *Item, dryRun bool) *Result {
+	res := &Result{Name: item.Name}
+
+	script := fmt.Sprintf(`for p in %s; do [ -e "$p" ] && printf '%%s\n' "$p"; done; true`, strings.Join(item.Patterns, " "))
+	out, err := conn.CommandContext(ctx, "sh", "-c", script).Output()
+	if err != nil {
+		res.Err = errors.Wrap(err, "failed to find files")
+		return res
+	}
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		res.Paths = append(res.Paths, sc.Text())
+	}
+	if len(res.Paths) == 0 {
+		return res
+	}
+
+	out, err = conn.CommandContext(ctx, "du", append([]string{"-skc", "--"}, res.Paths...)...).Output()
+	if err != nil {
+		res.Err = errors.Wrap(err, "failed to compute disk usage")
+		return res
+	}
+	if res.Bytes, err = parseDUTotal(out); err != nil {
+		res.Err = err
+		return res
+	}
+	if dryRun {
+		return res
+	}
+
+	if err := conn.CommandContext(ctx, "rm", append([]string{"-rf", "--"}, res.Paths...)...).Run(ssh.DumpLogOnError); err != nil {
+		res.Err = errors.Wrap(err, "failed to remove files")
+	}
+	return res
+}
+
+// parseDUTotal parses the output of "du -skc" and returns the total size in
+// bytes.
+func parseDUTotal(out []byte) (int64, error) {
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(fields) != 2 || fields[1] != "total" {
+		return 0, errors.Errorf("unexpected du output %q", out)
+	}
+	kb, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return 0, errors.Errorf("unexpected du output %q", out)
+	}
+	return kb * 1024, nil
+}
+
+// formatBytes returns a human-readable representation of n bytes.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// Write writes results to w in a human-readable format. dryRun should be the
+// same as the one used to clean.
+func Write(w io.Writer, results []*Result, dryRun bool) error {
+	removed, killed := "Removed", "Killed"
+	if dryRun {
+		removed, killed = "Would remove", "Would kill"
+	}
+
+	var total int64
+	for _, r := range results {
+		var msg string
+		switch {
+		case r.Err != nil:
+			msg = fmt.Sprintf("Failed: %v", r.Err)
+		case r.PIDs != nil:
+			msg = fmt.Sprintf("%s %d process(es)", killed, len(r.PIDs))
+		case len(r.Paths) == 0:
+			msg = "Nothing to clean"
+		default:
+			msg = fmt.Sprintf("%s %s (%s)", removed, strings.Join(r.Paths, ", "), formatBytes(r.Bytes))
+			total += r.Bytes
+		}
+		if _, err := fmt.Fprintf(w, "%s: %s\n", r.Name, msg); err != nil {
+			return err
+		}
+	}
+
+	reclaimed := "Reclaimed"
+	if dryRun {
+		reclaimed = "Would reclaim"
+	}
+	_, err := fmt.Fprintf(w, "%s %s in total\n", reclaimed, formatBytes(total))
+	return err
+}
+
+// HasError returns whether any of results has an error.
+func HasError(results []*Result) bool {
+	for _, r := range results {
+		if r.Err != nil {
+			return true
+		}
+	}
+	return false
+}
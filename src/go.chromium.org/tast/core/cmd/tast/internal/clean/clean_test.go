@@ -0,0 +1,160 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package clean
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go.chromium.org/tast/core/internal/sshtest"
+	"go.chromium.org/tast/core/testutil"
+)
+
+func TestParseDUTotal(t *testing.T) {
+	for _, tc := range []struct {
+		out     string
+		want    int64
+		wantErr bool
+	}{
+		{"4\t/a\n8\t/b\n12\ttotal\n", 12 * 1024, false},
+		{"0\ttotal\n", 0, false},
+		{"", 0, true},
+		{"12\t/a\n", 0, true},
+		{"x\ttotal\n", 0, true},
+	} {
+		got, err := parseDUTotal([]byte(tc.out))
+		if err != nil && !tc.wantErr {
+			t.Errorf("parseDUTotal(%q) failed: %v", tc.out, err)
+		} else if err == nil && tc.wantErr {
+			t.Errorf("parseDUTotal(%q) unexpectedly succeeded", tc.out)
+		} else if got != tc.want {
+			t.Errorf("parseDUTotal(%q) = %d; want %d", tc.out, got, tc.want)
+		}
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	for _, tc := range []struct {
+		n    int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{1536 * 1024, "1.5 MiB"},
+		{3 << 30, "3.0 GiB"},
+	} {
+		if got := formatBytes(tc.n); got != tc.want {
+			t.Errorf("formatBytes(%d) = %q; want %q", tc.n, got, tc.want)
+		}
+	}
+}
+
+func TestClean(t *testing.T) {
+	// Commands are run on the local machine by the test SSH server.
+	td := sshtest.NewTestDataConn(t)
+	defer td.Close()
+	ctx := context.Background()
+
+	dir := testutil.TempDir(t)
+	defer os.RemoveAll(dir)
+	if err := testutil.WriteFiles(dir, map[string]string{
+		"pushed/bundle":    "bundle",
+		"tmp/out.1/log":    "log",
+		"tmp/out.2/log":    "log",
+		"tmp/keep/file":    "keep",
+		"stamp":            "url",
+		"unrelated/bundle": "bundle",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Start a process to be killed as a stale process.
+	proc := exec.Command("sleep", "3141")
+	if err := proc.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		proc.Process.Kill()
+		proc.Wait()
+	}()
+
+	cfg := &Config{
+		Items: []*Item{
+			{Name: "Pushed", Patterns: []string{filepath.Join(dir, "pushed")}},
+			{Name: "Temp", Patterns: []string{filepath.Join(dir, "tmp/out.*")}},
+			{Name: "Stamp", Patterns: []string{filepath.Join(dir, "stamp")}},
+			{Name: "Missing", Patterns: []string{filepath.Join(dir, "missing.*")}},
+		},
+		ProcessPattern: "^sleep 3141$",
+	}
+
+	paths := func(results []*Result) map[string][]string {
+		got := make(map[string][]string)
+		for _, r := range results {
+			if r.Err != nil {
+				t.Errorf("%s: %v", r.Name, r.Err)
+			}
+			got[r.Name] = r.Paths
+		}
+		return got
+	}
+	wantPaths := map[string][]string{
+		"Stale runner processes": nil,
+		"Pushed":                 {filepath.Join(dir, "pushed")},
+		"Temp":                   {filepath.Join(dir, "tmp/out.1"), filepath.Join(dir, "tmp/out.2")},
+		"Stamp":                  {filepath.Join(dir, "stamp")},
+		"Missing":                nil,
+	}
+
+	// Nothing is removed on dry run.
+	cfg.DryRun = true
+	results := Clean(ctx, td.Hst, cfg)
+	if diff := cmp.Diff(paths(results), wantPaths); diff != "" {
+		t.Errorf("Clean with dry run returned unexpected paths (-got +want):\n%s", diff)
+	}
+	if pids := results[0].PIDs; len(pids) != 1 || pids[0] != proc.Process.Pid {
+		t.Errorf("Clean with dry run found processes %v; want [%d]", pids, proc.Process.Pid)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "pushed")); err != nil {
+		t.Error("Clean with dry run removed files: ", err)
+	}
+
+	cfg.DryRun = false
+	results = Clean(ctx, td.Hst, cfg)
+	if diff := cmp.Diff(paths(results), wantPaths); diff != "" {
+		t.Errorf("Clean returned unexpected paths (-got +want):\n%s", diff)
+	}
+	if err := proc.Wait(); err == nil {
+		t.Error("Stale process was not killed")
+	}
+
+	got, err := testutil.ReadFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"tmp/keep/file":    "keep",
+		"unrelated/bundle": "bundle",
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Files after Clean mismatch (-got +want):\n%s", diff)
+	}
+
+	var b bytes.Buffer
+	if err := Write(&b, results, false); err != nil {
+		t.Fatal(err)
+	}
+	if out := b.String(); !strings.Contains(out, "Killed 1 process(es)") || !strings.Contains(out, "Reclaimed ") {
+		t.Errorf("Write wrote unexpected output:\n%s", out)
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package depgraph builds dependency graphs of tests, consisting of fixtures,
+// services, data files and runtime variables the tests depend on.
+package depgraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+
+	"go.chromium.org/tast/core/internal/protocol"
+	"go.chromium.org/tast/core/internal/run/resultsjson"
+)
+
+// NodeKind represents a kind of a node in a dependency graph.
+type NodeKind string
+
+const (
+	// TestNode is a test.
+	TestNode NodeKind = "test"
+	// FixtureNode is a fixture.
+	FixtureNode NodeKind = "fixture"
+	// ServiceNode is a gRPC service declared in ServiceDeps.
+	ServiceNode NodeKind = "service"
+	// DataNode is an external data file.
+	DataNode NodeKind = "data"
+	// VarNode is a runtime variable.
+	VarNode NodeKind = "var"
+)
+
+// dotShapes maps node kinds to their shapes in DOT.
+var dotShapes = map[NodeKind]string{
+	TestNode:    "box",
+	FixtureNode: "ellipse",
+	ServiceNode: "hexagon",
+	DataNode:    "note",
+	VarNode:     "diamond",
+}
+
+// Node is a node in a dependency graph.
+type Node struct {
+	// ID uniquely identifies the node in the graph, e.g. "fixture:chromeLoggedIn".
+	ID string `json:"id"`
+	// Kind is the kind of the node.
+	Kind NodeKind `json:"kind"`
+	// Name is the name of the node, e.g. "chromeLoggedIn". Data files are
+	// named by their paths relative to the source root.
+	Name string `json:"name"`
+}
+
+// Edge is a directed edge in a dependency graph. From depends on To.
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Graph is a dependency graph of tests.
+type Graph struct {
+	// Nodes contains nodes in the order they were first seen.
+	Nodes []*Node `json:"nodes"`
+	// Edges contains edges in the order they were first seen.
+	Edges []*Edge `json:"edges"`
+
+	nodes map[string]struct{}
+	edges map[Edge]struct{}
+}
+
+// New builds a dependency graph of tests. fixtures should contain all fixtures
+// available, out of which fixtures tests depend on directly or indirectly are
+// included in the graph.
+func New(tests []*resultsjson.Test, fixtures []*protocol.Entity) *Graph {
+	fixtMap := make(map[string]*protocol.Entity)
+	for _, f := range fixtures {
+		if _, ok := fixtMap[f.GetName()]; !ok {
+			fixtMap[f.GetName()] = f
+		}
+	}
+
+	g := &Graph{
+		nodes: make(map[string]struct{}),
+		edges: make(map[Edge]struct{}),
+	}
+	for _, t := range tests {
+		id := g.addNode(TestNode, t.Name)
+		g.addDeps(id, t.Pkg, t.ServiceDeps, t.Data, append(append([]string(nil), t.Vars...), t.VarDeps...))
+		if t.Fixture != "" {
+			g.addEdge(id, g.addFixture(t.Fixture, fixtMap))
+		}
+	}
+	return g
+}
+
+// addFixture adds the named fixture and its ancestors to g, and returns the
+// ID of the fixture node.
+func (g *Graph) addFixture(name string, fixtures map[string]*protocol.Entity) string {
+	id := nodeID(FixtureNode, name)
+	if _, ok := g.nodes[id]; ok {
+		return id
+	}
+	g.addNode(FixtureNode, name)
+
+	f, ok := fixtures[name]
+	if !ok {
+		// The fixture is unknown, e.g. it is not available in the bundles.
+		return id
+	}
+	g.addDeps(id, f.GetPackage(), f.GetDependencies().GetServices(), f.GetDependencies().GetDataFiles(), f.GetLegacyData().GetVariables())
+	if parent := f.GetFixture(); parent != "" {
+		g.addEdge(id, g.addFixture(parent, fixtures))
+	}
+	return id
+}
+
+// addDeps adds edges from the node from to services, data files and vars.
+// Data files are relative to the data directory of the package pkg.
+func (g *Graph) addDeps(from, pkg string, services, data, vars []string) {
+	for _, s := range services {
+		g.addEdge(from, g.addNode(ServiceNode, s))
+	}
+	for _, d := range data {
+		g.addEdge(from, g.addNode(DataNode, path.Join(pkg, "data", d)))
+	}
+	for _, v := range vars {
+		g.addEdge(from, g.addNode(VarNode, v))
+	}
+}
+
+// addNode adds a node to g if it does not exist yet, and returns its ID.
+func (g *Graph) addNode(kind NodeKind, name string) string {
+	id := nodeID(kind, name)
+	if _, ok := g.nodes[id]; !ok {
+		g.nodes[id] = struct{}{}
+		g.Nodes = append(g.Nodes, &Node{ID: id, Kind: kind, Name: name})
+	}
+	return id
+}
+
+// addEdge adds an edge to g if it does not exist yet.
+func (g *Graph) addEdge(from, to string) {
+	e := Edge{From: from, To: to}
+	if _, ok := g.edges[e]; !ok {
+		g.edges[e] = struct{}{}
+		g.Edges = append(g.Edges, &e)
+	}
+}
+
+func nodeID(kind NodeKind, name string) string {
+	return string(kind) + ":" + name
+}
+
+// WriteJSON writes g to w as JSON.
+func (g *Graph) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(g)
+}
+
+// WriteDOT writes g to w in the DOT language of Graphviz.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	if _, err := fmt.Fprintln(w, "digraph deps {\n  rankdir=LR;"); err != nil {
+		return err
+	}
+	for _, n := range g.Nodes {
+		if _, err := fmt.Fprintf(w, "  %s [label=%s, shape=%s];\n", strconv.Quote(n.ID), strconv.Quote(n.Name), dotShapes[n.Kind]); err != nil {
+			return err
+		}
+	}
+	for _, e := range g.Edges {
+		if _, err := fmt.Fprintf(w, "  %s -> %s;\n", strconv.Quote(e.From), strconv.Quote(e.To)); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
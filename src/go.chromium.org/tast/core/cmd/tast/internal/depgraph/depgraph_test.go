@@ -0,0 +1,132 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package depgraph_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"go.chromium.org/tast/core/cmd/tast/internal/depgraph"
+	"go.chromium.org/tast/core/internal/protocol"
+	"go.chromium.org/tast/core/internal/run/resultsjson"
+)
+
+var (
+	testTests = []*resultsjson.Test{
+		{
+			Name:        "pkg.Test1",
+			Pkg:         "example/pkg",
+			Fixture:     "child",
+			ServiceDeps: []string{"tast.pkg.Service"},
+			Data:        []string{"file.txt"},
+			VarDeps:     []string{"pkg.var"},
+		},
+		{
+			Name:    "pkg.Test2",
+			Pkg:     "example/pkg",
+			Fixture: "parent",
+		},
+	}
+	testFixtures = []*protocol.Entity{
+		{
+			Type:    protocol.EntityType_FIXTURE,
+			Name:    "child",
+			Package: "example/fixt",
+			Fixture: "parent",
+			Dependencies: &protocol.EntityDependencies{
+				Services: []string{"tast.pkg.Service"},
+			},
+		},
+		{
+			Type:    protocol.EntityType_FIXTURE,
+			Name:    "parent",
+			Package: "example/fixt",
+			Fixture: "missing",
+			LegacyData: &protocol.EntityLegacyData{
+				Variables: []string{"fixt.var"},
+			},
+		},
+		{
+			Type: protocol.EntityType_FIXTURE,
+			Name: "unused",
+		},
+	}
+)
+
+func TestNew(t *testing.T) {
+	g := depgraph.New(testTests, testFixtures)
+
+	wantNodes := []*depgraph.Node{
+		{ID: "test:pkg.Test1", Kind: depgraph.TestNode, Name: "pkg.Test1"},
+		{ID: "service:tast.pkg.Service", Kind: depgraph.ServiceNode, Name: "tast.pkg.Service"},
+		{ID: "data:example/pkg/data/file.txt", Kind: depgraph.DataNode, Name: "example/pkg/data/file.txt"},
+		{ID: "var:pkg.var", Kind: depgraph.VarNode, Name: "pkg.var"},
+		{ID: "fixture:child", Kind: depgraph.FixtureNode, Name: "child"},
+		{ID: "fixture:parent", Kind: depgraph.FixtureNode, Name: "parent"},
+		{ID: "var:fixt.var", Kind: depgraph.VarNode, Name: "fixt.var"},
+		{ID: "fixture:missing", Kind: depgraph.FixtureNode, Name: "missing"},
+		{ID: "test:pkg.Test2", Kind: depgraph.TestNode, Name: "pkg.Test2"},
+	}
+	wantEdges := []*depgraph.Edge{
+		{From: "test:pkg.Test1", To: "service:tast.pkg.Service"},
+		{From: "test:pkg.Test1", To: "data:example/pkg/data/file.txt"},
+		{From: "test:pkg.Test1", To: "var:pkg.var"},
+		{From: "fixture:child", To: "service:tast.pkg.Service"},
+		{From: "fixture:parent", To: "var:fixt.var"},
+		{From: "fixture:parent", To: "fixture:missing"},
+		{From: "fixture:child", To: "fixture:parent"},
+		{From: "test:pkg.Test1", To: "fixture:child"},
+		{From: "test:pkg.Test2", To: "fixture:parent"},
+	}
+	if diff := cmp.Diff(g.Nodes, wantNodes); diff != "" {
+		t.Errorf("Nodes mismatch (-got +want):\n%s", diff)
+	}
+	if diff := cmp.Diff(g.Edges, wantEdges); diff != "" {
+		t.Errorf("Edges mismatch (-got +want):\n%s", diff)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	g := depgraph.New(testTests[1:], testFixtures)
+
+	var buf bytes.Buffer
+	if err := g.WriteJSON(&buf); err != nil {
+		t.Fatal("WriteJSON failed: ", err)
+	}
+	var got depgraph.Graph
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal("Failed to unmarshal graph: ", err)
+	}
+	if diff := cmp.Diff(&got, g, cmpopts.IgnoreUnexported(depgraph.Graph{})); diff != "" {
+		t.Errorf("Graph mismatch after round trip (-got +want):\n%s", diff)
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	g := depgraph.New(testTests[1:], testFixtures)
+
+	var buf bytes.Buffer
+	if err := g.WriteDOT(&buf); err != nil {
+		t.Fatal("WriteDOT failed: ", err)
+	}
+	const want = `digraph deps {
+  rankdir=LR;
+  "test:pkg.Test2" [label="pkg.Test2", shape=box];
+  "fixture:parent" [label="parent", shape=ellipse];
+  "var:fixt.var" [label="fixt.var", shape=diamond];
+  "fixture:missing" [label="missing", shape=ellipse];
+  "fixture:parent" -> "var:fixt.var";
+  "fixture:parent" -> "fixture:missing";
+  "test:pkg.Test2" -> "fixture:parent";
+}
+`
+	if diff := cmp.Diff(buf.String(), want); diff != "" {
+		t.Errorf("WriteDOT output mismatch (-got +want):\n%s", diff)
+	}
+}
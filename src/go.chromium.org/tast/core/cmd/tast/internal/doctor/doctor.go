@@ -0,0 +1,396 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package doctor diagnoses common problems of the environment to run Tast
+// tests, such as SSH setup and DUT configuration.
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	cryptossh "golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"go.chromium.org/tast/core/errors"
+	"go.chromium.org/tast/core/ssh"
+)
+
+const (
+	// connectTimeout is the timeout for connecting to the DUT.
+	connectTimeout = 10 * time.Second
+
+	// maxClockSkew is the maximum clock skew between the host and the DUT
+	// that is not reported.
+	maxClockSkew = 30 * time.Second
+
+	// sshKeysSetupURL describes how to set up SSH keys to connect to DUTs.
+	sshKeysSetupURL = "https://www.chromium.org/chromium-os/testing/autotest-developer-faq/ssh-test-keys-setup"
+)
+
+// Status is the outcome of a check.
+type Status int
+
+const (
+	// StatusOK indicates that no problem was found.
+	StatusOK Status = iota
+	// StatusWarning indicates a problem that may cause trouble.
+	StatusWarning
+	// StatusError indicates a problem that prevents running tests.
+	StatusError
+	// StatusSkipped indicates that the check could not be performed.
+	StatusSkipped
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusOK:
+		return "OK"
+	case StatusWarning:
+		return "WARN"
+	case StatusError:
+		return "FAIL"
+	case StatusSkipped:
+		return "SKIP"
+	default:
+		return fmt.Sprintf("Status(%d)", int(s))
+	}
+}
+
+// Result is a result of a check.
+type Result struct {
+	// Name is a human-readable name of the check.
+	Name string
+	// Status is the outcome of the check.
+	Status Status
+	// Message describes what was found.
+	Message string
+	// Fix describes an action to fix the problem, if any.
+	Fix string
+}
+
+// Config contains parameters of checks.
+type Config struct {
+	// Target is the DUT to check, in the form of "[<user>@]host[:<port>]".
+	Target string
+	// KeyFile is the path to the private SSH key to connect to the DUT.
+	KeyFile string
+	// KeyDir is a directory containing private SSH keys.
+	KeyDir string
+	// ProxyCommand is the command to connect to the DUT, if any.
+	ProxyCommand string
+	// KnownHostsFile is the path to the OpenSSH known_hosts file.
+	KnownHostsFile string
+	// TrunkDir is the path to the ChromiumOS checkout.
+	TrunkDir string
+	// InChroot is whether tast is run inside the ChromiumOS chroot.
+	InChroot bool
+	// LocalRunner is the path to the local test runner on the DUT.
+	LocalRunner string
+	// LocalBundleDir is the path to the directory containing local test
+	// bundles on the DUT.
+	LocalBundleDir string
+}
+
+// Run performs all checks and returns their results. Checks requiring an SSH
+// connection to the DUT are skipped if the connection fails.
+func Run(ctx context.Context, cfg *Config) []*Result {
+	results := []*Result{
+		checkChroot(cfg),
+		checkKeyFile(cfg),
+	}
+
+	var o ssh.Options
+	if err := ssh.ParseTarget(cfg.Target, &o); err != nil {
+		return append(results, &Result{
+			Name:    "Target",
+			Status:  StatusError,
+			Message: err.Error(),
+			Fix:     `Specify the DUT as "[<user>@]host[:<port>]"`,
+		})
+	}
+	o.KeyFile = cfg.KeyFile
+	o.KeyDir = cfg.KeyDir
+	o.ProxyCommand = cfg.ProxyCommand
+	o.ConnectTimeout = connectTimeout
+
+	reachable := checkReachability(cfg, o.Hostname)
+	results = append(results, reachable, checkKnownHosts(cfg, o.Hostname, reachable.Status == StatusOK))
+
+	conn, auth := checkAuth(ctx, &o)
+	results = append(results, auth)
+	if conn == nil {
+		const msg = "SSH connection to the DUT failed"
+		return append(results,
+			&Result{Name: "Clock skew", Status: StatusSkipped, Message: msg},
+			&Result{Name: "Test bundles", Status: StatusSkipped, Message: msg},
+		)
+	}
+	defer conn.Close(ctx)
+
+	return append(results, checkClockSkew(ctx, conn), checkBundles(ctx, cfg, conn))
+}
+
+// Write writes results to w in a human-readable format.
+func Write(w io.Writer, results []*Result) error {
+	for _, r := range results {
+		if _, err := fmt.Fprintf(w, "[%-4s] %s: %s\n", r.Status, r.Name, r.Message); err != nil {
+			return err
+		}
+		if r.Fix != "" {
+			if _, err := fmt.Fprintf(w, "       Fix: %s\n", r.Fix); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// HasError returns whether any of results has StatusError.
+func HasError(results []*Result) bool {
+	for _, r := range results {
+		if r.Status == StatusError {
+			return true
+		}
+	}
+	return false
+}
+
+func checkChroot(cfg *Config) *Result {
+	const name = "Chroot"
+	if !cfg.InChroot {
+		return &Result{
+			Name:    name,
+			Status:  StatusWarning,
+			Message: "Running outside the ChromiumOS chroot; tests can't be built",
+			Fix:     "Run tast inside the chroot (cros_sdk), or pass -build=false to use test bundles installed on the DUT",
+		}
+	}
+	if _, err := os.Stat(cfg.TrunkDir); err != nil {
+		return &Result{
+			Name:    name,
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("ChromiumOS checkout not found at %s", cfg.TrunkDir),
+			Fix:     fmt.Sprintf("Make sure $HOME/%s is the ChromiumOS checkout", filepath.Base(cfg.TrunkDir)),
+		}
+	}
+	return &Result{Name: name, Status: StatusOK, Message: fmt.Sprintf("Running inside the chroot with the checkout at %s", cfg.TrunkDir)}
+}
+
+func checkKeyFile(cfg *Config) *Result {
+	const name = "SSH key"
+	if cfg.KeyFile == "" {
+		return &Result{Name: name, Status: StatusSkipped, Message: "No private key is specified"}
+	}
+	fi, err := os.Stat(cfg.KeyFile)
+	if err != nil {
+		return &Result{
+			Name:    name,
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("Private key %s is not found; only keys in %s and ssh-agent are tried", cfg.KeyFile, cfg.KeyDir),
+			Fix:     fmt.Sprintf("Install testing_rsa to %s (see %s) or pass -keyfile", cfg.KeyFile, sshKeysSetupURL),
+		}
+	}
+	if perm := fi.Mode().Perm(); perm&0077 != 0 {
+		return &Result{
+			Name:    name,
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("Private key %s has too open permissions %#o; OpenSSH ignores it", cfg.KeyFile, perm),
+			Fix:     "chmod 600 " + cfg.KeyFile,
+		}
+	}
+	b, err := os.ReadFile(cfg.KeyFile)
+	if err != nil {
+		return &Result{Name: name, Status: StatusError, Message: err.Error(), Fix: "Make " + cfg.KeyFile + " readable"}
+	}
+	if _, err := cryptossh.ParsePrivateKey(b); err != nil {
+		return &Result{
+			Name:    name,
+			Status:  StatusError,
+			Message: fmt.Sprintf("Failed to parse private key %s: %v", cfg.KeyFile, err),
+			Fix:     fmt.Sprintf("Use an unencrypted private key (see %s)", sshKeysSetupURL),
+		}
+	}
+	return &Result{Name: name, Status: StatusOK, Message: fmt.Sprintf("Private key %s is usable", cfg.KeyFile)}
+}
+
+// isLoopback returns whether hostPort points to the local machine, which is
+// typically the case when connecting to a DUT via a port forward.
+func isLoopback(hostPort string) bool {
+	host, _, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func checkReachability(cfg *Config, hostPort string) *Result {
+	const name = "Reachability"
+	if cfg.ProxyCommand != "" {
+		return &Result{Name: name, Status: StatusSkipped, Message: "Connecting via proxy command " + cfg.ProxyCommand}
+	}
+	c, err := net.DialTimeout("tcp", hostPort, connectTimeout)
+	if err != nil {
+		r := &Result{
+			Name:    name,
+			Status:  StatusError,
+			Message: fmt.Sprintf("Failed to connect to %s: %v", hostPort, err),
+			Fix:     "Make sure the DUT is powered on and connected to the network",
+		}
+		if isLoopback(hostPort) {
+			_, port, _ := net.SplitHostPort(hostPort)
+			r.Fix = fmt.Sprintf("The target is a local port; make sure the port forward to the DUT is running, e.g. ssh -N -L %s:<dut>:22 <jump-host>", port)
+		}
+		return r
+	}
+	c.Close()
+	return &Result{Name: name, Status: StatusOK, Message: fmt.Sprintf("Port %s is reachable", hostPort)}
+}
+
+// errHostKeyReceived is returned by the host key callback in
+// fetchHostKey to abort the SSH handshake.
+var errHostKeyReceived = errors.New("host key received")
+
+// fetchHostKey returns the SSH host key of the server at hostPort.
+func fetchHostKey(hostPort string) (cryptossh.PublicKey, error) {
+	c, err := net.DialTimeout("tcp", hostPort, connectTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+	c.SetDeadline(time.Now().Add(connectTimeout))
+
+	var key cryptossh.PublicKey
+	_, _, _, err = cryptossh.NewClientConn(c, hostPort, &cryptossh.ClientConfig{
+		HostKeyCallback: func(_ string, _ net.Addr, k cryptossh.PublicKey) error {
+			key = k
+			return errHostKeyReceived
+		},
+	})
+	if key == nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// knownHostsName returns the host name of hostPort as written in known_hosts.
+func knownHostsName(hostPort string) string {
+	host, port, err := net.SplitHostPort(hostPort)
+	if err != nil || port == strconv.Itoa(22) {
+		return host
+	}
+	return fmt.Sprintf("[%s]:%s", host, port)
+}
+
+func checkKnownHosts(cfg *Config, hostPort string, reachable bool) *Result {
+	const name = "known_hosts"
+	if !reachable {
+		return &Result{Name: name, Status: StatusSkipped, Message: "The DUT is not directly reachable"}
+	}
+	if _, err := os.Stat(cfg.KnownHostsFile); err != nil {
+		return &Result{Name: name, Status: StatusSkipped, Message: fmt.Sprintf("%s is not found", cfg.KnownHostsFile)}
+	}
+	callback, err := knownhosts.New(cfg.KnownHostsFile)
+	if err != nil {
+		return &Result{Name: name, Status: StatusWarning, Message: fmt.Sprintf("Failed to parse %s: %v", cfg.KnownHostsFile, err)}
+	}
+	key, err := fetchHostKey(hostPort)
+	if err != nil {
+		return &Result{Name: name, Status: StatusSkipped, Message: fmt.Sprintf("Failed to get the host key: %v", err)}
+	}
+	addr, err := net.ResolveTCPAddr("tcp", hostPort)
+	if err != nil {
+		return &Result{Name: name, Status: StatusSkipped, Message: err.Error()}
+	}
+	var keyErr *knownhosts.KeyError
+	if err := callback(hostPort, addr, key); errors.As(err, &keyErr) && len(keyErr.Want) > 0 {
+		return &Result{
+			Name:   name,
+			Status: StatusWarning,
+			Message: fmt.Sprintf("Host key of %s differs from the one in %s, e.g. because the DUT was reimaged; "+
+				"tast ignores it, but ssh will refuse to connect", hostPort, cfg.KnownHostsFile),
+			Fix: fmt.Sprintf("ssh-keygen -f %s -R '%s'", cfg.KnownHostsFile, knownHostsName(hostPort)),
+		}
+	}
+	return &Result{Name: name, Status: StatusOK, Message: fmt.Sprintf("No conflicting host key in %s", cfg.KnownHostsFile)}
+}
+
+func checkAuth(ctx context.Context, o *ssh.Options) (*ssh.Conn, *Result) {
+	const name = "SSH connection"
+	conn, err := ssh.New(ctx, o)
+	if err != nil {
+		return nil, &Result{
+			Name:    name,
+			Status:  StatusError,
+			Message: fmt.Sprintf("Failed to connect to %s@%s: %v", o.User, o.Hostname, err),
+			Fix:     fmt.Sprintf("Make sure the DUT runs a test image and the SSH key is set up (see %s)", sshKeysSetupURL),
+		}
+	}
+	return conn, &Result{Name: name, Status: StatusOK, Message: fmt.Sprintf("Logged in to %s@%s", o.User, o.Hostname)}
+}
+
+// clockSkewResult returns a result of the clock skew check from times of the
+// host and the DUT.
+func clockSkewResult(host, dut time.Time) *Result {
+	const name = "Clock skew"
+	skew := dut.Sub(host).Round(time.Second)
+	if skew > maxClockSkew || skew < -maxClockSkew {
+		return &Result{
+			Name:    name,
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("DUT clock differs from the host by %v, which breaks TLS and makes logs confusing", skew),
+			Fix:     fmt.Sprintf("Set the DUT clock, e.g. run date -s @%d on the DUT", host.Unix()),
+		}
+	}
+	return &Result{Name: name, Status: StatusOK, Message: fmt.Sprintf("DUT clock differs from the host by %v", skew)}
+}
+
+func checkClockSkew(ctx context.Context, conn *ssh.Conn) *Result {
+	before := time.Now()
+	out, err := conn.CommandContext(ctx, "date", "+%s").Output()
+	if err != nil {
+		return &Result{Name: "Clock skew", Status: StatusSkipped, Message: fmt.Sprintf("Failed to get DUT time: %v", err)}
+	}
+	sec, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+	if err != nil {
+		return &Result{Name: "Clock skew", Status: StatusSkipped, Message: fmt.Sprintf("Failed to parse DUT time %q", out)}
+	}
+	// Compare with the middle of the command execution.
+	host := before.Add(time.Since(before) / 2)
+	return clockSkewResult(host, time.Unix(sec, 0))
+}
+
+func checkBundles(ctx context.Context, cfg *Config, conn *ssh.Conn) *Result {
+	const name = "Test bundles"
+	var missing []string
+	for _, p := range []string{cfg.LocalRunner, cfg.LocalBundleDir} {
+		if err := conn.CommandContext(ctx, "test", "-e", p).Run(); err != nil {
+			missing = append(missing, p)
+		}
+	}
+	if len(missing) == 0 {
+		return &Result{Name: name, Status: StatusOK, Message: fmt.Sprintf("Local test bundles are installed in %s", cfg.LocalBundleDir)}
+	}
+	msg := fmt.Sprintf("Not found on the DUT: %s", strings.Join(missing, ", "))
+	if cfg.InChroot {
+		return &Result{Name: name, Status: StatusOK, Message: msg + "; tast run will build and push them"}
+	}
+	return &Result{
+		Name:    name,
+		Status:  StatusError,
+		Message: msg,
+		Fix:     "Flash a test image to the DUT, or run tast inside the chroot to build and push test bundles",
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	cryptossh "golang.org/x/crypto/ssh"
+
+	"go.chromium.org/tast/core/internal/sshtest"
+	"go.chromium.org/tast/core/testutil"
+)
+
+func TestCheckKeyFile(t *testing.T) {
+	td := testutil.TempDir(t)
+	defer os.RemoveAll(td)
+
+	userKey, _ := sshtest.MustGenerateKeys()
+	goodKey, err := sshtest.WriteKey(userKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(goodKey)
+
+	openKey := filepath.Join(td, "open")
+	badKey := filepath.Join(td, "bad")
+	if err := testutil.WriteFiles(td, map[string]string{"open": "key", "bad": "key"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(openKey, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(badKey, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		keyFile string
+		want    Status
+	}{
+		{"", StatusSkipped},
+		{filepath.Join(td, "missing"), StatusWarning},
+		{openKey, StatusWarning},
+		{badKey, StatusError},
+		{goodKey, StatusOK},
+	} {
+		if r := checkKeyFile(&Config{KeyFile: tc.keyFile}); r.Status != tc.want {
+			t.Errorf("checkKeyFile(%q) = %v (%s); want %v", tc.keyFile, r.Status, r.Message, tc.want)
+		}
+	}
+}
+
+func TestCheckReachabilityPortForward(t *testing.T) {
+	// Get a local port that nobody listens on.
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := lis.Addr().String()
+	lis.Close()
+
+	r := checkReachability(&Config{}, addr)
+	if r.Status != StatusError {
+		t.Fatalf("checkReachability(%q) = %v; want %v", addr, r.Status, StatusError)
+	}
+	if !strings.Contains(r.Fix, "port forward") {
+		t.Errorf("checkReachability(%q) suggested %q; want a fix about port forwards", addr, r.Fix)
+	}
+}
+
+func TestClockSkewResult(t *testing.T) {
+	host := time.Unix(1700000000, 0)
+	for _, tc := range []struct {
+		skew time.Duration
+		want Status
+	}{
+		{0, StatusOK},
+		{maxClockSkew, StatusOK},
+		{-maxClockSkew, StatusOK},
+		{maxClockSkew + time.Second, StatusWarning},
+		{-time.Hour, StatusWarning},
+	} {
+		if r := clockSkewResult(host, host.Add(tc.skew)); r.Status != tc.want {
+			t.Errorf("clockSkewResult with skew %v = %v (%s); want %v", tc.skew, r.Status, r.Message, tc.want)
+		}
+	}
+}
+
+func TestKnownHostsName(t *testing.T) {
+	for _, tc := range []struct{ hostPort, want string }{
+		{"dut:22", "dut"},
+		{"localhost:2222", "[localhost]:2222"},
+	} {
+		if got := knownHostsName(tc.hostPort); got != tc.want {
+			t.Errorf("knownHostsName(%q) = %q; want %q", tc.hostPort, got, tc.want)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	td := sshtest.NewTestData(func(req *sshtest.ExecReq) {
+		req.Start(true)
+		req.End(req.RunRealCmd())
+	})
+	defer td.Close()
+
+	dir := testutil.TempDir(t)
+	defer os.RemoveAll(dir)
+
+	// Record a different host key for the server in known_hosts.
+	_, otherHostKey := sshtest.MustGenerateKeys()
+	otherPub, err := cryptossh.NewPublicKey(&otherHostKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := td.Srvs[0].Addr().String()
+	knownHosts := fmt.Sprintf("%s %s", knownHostsName(addr), cryptossh.MarshalAuthorizedKey(otherPub))
+	if err := testutil.WriteFiles(dir, map[string]string{
+		"known_hosts":       knownHosts,
+		"local_test_runner": "",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Commands are run on the local machine by the test SSH server.
+	results := Run(context.Background(), &Config{
+		Target:         addr,
+		KeyFile:        td.UserKeyFile,
+		KnownHostsFile: filepath.Join(dir, "known_hosts"),
+		InChroot:       false,
+		LocalRunner:    filepath.Join(dir, "local_test_runner"),
+		LocalBundleDir: filepath.Join(dir, "missing_bundles"),
+	})
+
+	got := make(map[string]Status)
+	for _, r := range results {
+		got[r.Name] = r.Status
+	}
+	want := map[string]Status{
+		"Chroot":         StatusWarning,
+		"SSH key":        StatusOK,
+		"Reachability":   StatusOK,
+		"known_hosts":    StatusWarning,
+		"SSH connection": StatusOK,
+		"Clock skew":     StatusOK,
+		"Test bundles":   StatusError,
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		var b bytes.Buffer
+		Write(&b, results)
+		t.Errorf("Run returned unexpected statuses (-got +want):\n%s\nResults:\n%s", diff, b.String())
+	}
+	if !HasError(results) {
+		t.Error("HasError = false; want true")
+	}
+}
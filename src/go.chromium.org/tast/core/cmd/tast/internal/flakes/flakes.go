@@ -0,0 +1,436 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package flakes records per-attempt test outcomes to a flake history and
+// summarizes flaky tests from it.
+//
+// A flake history is either a local CSV file, to which records are appended
+// as tests finish, or an HTTP(S) endpoint, to which records of a run are
+// POSTed as a JSON array when the run finishes.
+package flakes
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.chromium.org/tast/core/errors"
+	"go.chromium.org/tast/core/internal/run/reporting"
+	"go.chromium.org/tast/core/internal/run/resultsjson"
+)
+
+// Status is the outcome of a test attempt.
+type Status string
+
+const (
+	// StatusPass means that the attempt passed.
+	StatusPass Status = "PASS"
+	// StatusFail means that the attempt reported errors.
+	StatusFail Status = "FAIL"
+	// StatusSkip means that the test was skipped.
+	StatusSkip Status = "SKIP"
+)
+
+// Record is an outcome of a test attempt in a flake history.
+type Record struct {
+	// Time is when the attempt finished.
+	Time time.Time `json:"time"`
+	// Run identifies the run the attempt belongs to. It is the base name of
+	// the results directory.
+	Run string `json:"run"`
+	// Test is the name of the test.
+	Test string `json:"test"`
+	// Attempt is the 1-based count of the attempt of the test within the run.
+	Attempt int `json:"attempt"`
+	// Status is the outcome of the attempt.
+	Status Status `json:"status"`
+	// Duration is how long the attempt took.
+	Duration time.Duration `json:"duration"`
+	// Board is the board name of the DUT, or empty if unknown.
+	Board string `json:"board"`
+	// Image is the OS version of the DUT, or empty if unknown.
+	Image string `json:"image"`
+}
+
+// csvHeader is the first row of a flake history CSV file.
+var csvHeader = []string{"time", "run", "test", "attempt", "status", "duration_sec", "board", "image"}
+
+func (r *Record) csvRow() []string {
+	return []string{
+		r.Time.UTC().Format(time.RFC3339),
+		r.Run,
+		r.Test,
+		strconv.Itoa(r.Attempt),
+		string(r.Status),
+		strconv.FormatFloat(r.Duration.Seconds(), 'f', 3, 64),
+		r.Board,
+		r.Image,
+	}
+}
+
+func parseCSVRow(row []string) (*Record, error) {
+	if len(row) != len(csvHeader) {
+		return nil, errors.Errorf("got %d columns; want %d", len(row), len(csvHeader))
+	}
+	t, err := time.Parse(time.RFC3339, row[0])
+	if err != nil {
+		return nil, err
+	}
+	attempt, err := strconv.Atoi(row[3])
+	if err != nil {
+		return nil, err
+	}
+	sec, err := strconv.ParseFloat(row[5], 64)
+	if err != nil {
+		return nil, err
+	}
+	return &Record{
+		Time:     t,
+		Run:      row[1],
+		Test:     row[2],
+		Attempt:  attempt,
+		Status:   Status(row[4]),
+		Duration: time.Duration(sec * float64(time.Second)),
+		Board:    row[6],
+		Image:    row[7],
+	}, nil
+}
+
+// IsRemote returns whether dest is an HTTP(S) endpoint rather than a file.
+func IsRemote(dest string) bool {
+	return strings.HasPrefix(dest, "http://") || strings.HasPrefix(dest, "https://")
+}
+
+// BoardFromImage returns the board name found in image, an OS version of the
+// form "<board>-<build type>/R<milestone>-<version>" reported by DUTs. It
+// returns an empty string if image is not of the form.
+func BoardFromImage(image string) string {
+	i := strings.Index(image, "/")
+	if i < 0 {
+		return ""
+	}
+	builder := image[:i]
+	j := strings.LastIndex(builder, "-")
+	if j <= 0 {
+		return ""
+	}
+	return builder[:j]
+}
+
+// Writer is a reporting.Reporter writing outcomes of all test attempts,
+// including retried ones, to a flake history.
+type Writer struct {
+	reporting.BaseReporter
+
+	dest   string
+	board  string
+	image  string
+	client *http.Client
+
+	run      string
+	attempts map[string]int
+	pending  []*Record // records not sent yet to a remote endpoint
+}
+
+var _ reporting.Reporter = &Writer{}
+
+// NewWriter returns a Writer writing to dest, a CSV file path or an HTTP(S)
+// URL. board and image are recorded as the DUT the tests run on.
+func NewWriter(dest, board, image string) *Writer {
+	return &Writer{
+		dest:     dest,
+		board:    board,
+		image:    image,
+		client:   http.DefaultClient,
+		attempts: make(map[string]int),
+	}
+}
+
+// RunStarted is called before any test starts.
+func (w *Writer) RunStarted(ctx context.Context, run *reporting.RunInfo) error {
+	w.run = filepath.Base(run.ResDir)
+	return nil
+}
+
+// TestFinished is called when an attempt of a test finishes.
+func (w *Writer) TestFinished(ctx context.Context, result *resultsjson.Result) error {
+	w.attempts[result.Name]++
+	status := StatusPass
+	if len(result.Errors) > 0 {
+		status = StatusFail
+	} else if result.SkipReason != "" {
+		status = StatusSkip
+	}
+	var duration time.Duration
+	if !result.Start.IsZero() && result.End.After(result.Start) {
+		duration = result.End.Sub(result.Start)
+	}
+	end := result.End
+	if end.IsZero() {
+		end = time.Now()
+	}
+	r := &Record{
+		Time:     end,
+		Run:      w.run,
+		Test:     result.Name,
+		Attempt:  w.attempts[result.Name],
+		Status:   status,
+		Duration: duration,
+		Board:    w.board,
+		Image:    w.image,
+	}
+	if IsRemote(w.dest) {
+		w.pending = append(w.pending, r)
+		return nil
+	}
+	// Append records as tests finish so that they are kept even if the run
+	// is aborted.
+	return appendCSV(w.dest, r)
+}
+
+// RunFinished is called after all tests finish, or the run is aborted.
+func (w *Writer) RunFinished(ctx context.Context, run *reporting.RunInfo, summary *reporting.RunSummary) error {
+	if !IsRemote(w.dest) || len(w.pending) == 0 {
+		return nil
+	}
+	b, err := json.Marshal(w.pending)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.dest, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return errors.Errorf("%s: %s", res.Status, strings.TrimSpace(string(msg)))
+	}
+	w.pending = nil
+	return nil
+}
+
+// appendCSV appends r to the CSV file at path, writing a header first if the
+// file is new.
+func appendCSV(path string, r *Record) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(f)
+	if fi.Size() == 0 {
+		cw.Write(csvHeader)
+	}
+	cw.Write(r.csvRow())
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// ReadFile reads records from the flake history CSV file at path.
+func ReadFile(path string) ([]*Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readCSV(f)
+}
+
+func readCSV(r io.Reader) ([]*Record, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	var recs []*Record
+	for i, row := range rows {
+		if i == 0 && len(row) > 0 && row[0] == csvHeader[0] {
+			continue
+		}
+		rec, err := parseCSVRow(row)
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", i+1)
+		}
+		recs = append(recs, rec)
+	}
+	return recs, nil
+}
+
+// Stats summarizes outcomes of a test in a flake history.
+type Stats struct {
+	// Test is the name of the test.
+	Test string
+	// Runs is the number of runs the test ran in.
+	Runs int
+	// Attempts is the number of attempts of the test, excluding skipped ones.
+	Attempts int
+	// Failures is the number of failed attempts.
+	Failures int
+	// RetryPasses is the number of runs in which the test failed and then
+	// passed on a retry.
+	RetryPasses int
+	// Boards contains sorted names of boards the test failed on.
+	Boards []string
+}
+
+// FlakeRate returns the ratio of failed attempts to all attempts.
+func (s *Stats) FlakeRate() float64 {
+	if s.Attempts == 0 {
+		return 0
+	}
+	return float64(s.Failures) / float64(s.Attempts)
+}
+
+// Summarize returns statistics of flaky tests found in recs, considering only
+// the last runs runs if runs is positive. A test is flaky if it both passed
+// and failed. Tests are sorted by descending flake rate, and then by name.
+func Summarize(recs []*Record, runs int) []*Stats {
+	if runs > 0 {
+		recs = lastRuns(recs, runs)
+	}
+
+	type runKey struct{ run, test string }
+	failedInRun := make(map[runKey]bool)
+	retryPassed := make(map[runKey]bool)
+	stats := make(map[string]*Stats)
+	boards := make(map[string]map[string]bool)
+	passed := make(map[string]bool)
+
+	// Records of a run are in the order of attempts, so passes after
+	// failures in the same run can be found in a single pass.
+	runsOfTest := make(map[runKey]bool)
+	for _, r := range recs {
+		if r.Status == StatusSkip {
+			continue
+		}
+		s, ok := stats[r.Test]
+		if !ok {
+			s = &Stats{Test: r.Test}
+			stats[r.Test] = s
+			boards[r.Test] = make(map[string]bool)
+		}
+		k := runKey{r.Run, r.Test}
+		if !runsOfTest[k] {
+			runsOfTest[k] = true
+			s.Runs++
+		}
+		s.Attempts++
+		switch r.Status {
+		case StatusFail:
+			s.Failures++
+			failedInRun[k] = true
+			if r.Board != "" {
+				boards[r.Test][r.Board] = true
+			}
+		case StatusPass:
+			passed[r.Test] = true
+			if failedInRun[k] && !retryPassed[k] {
+				retryPassed[k] = true
+				s.RetryPasses++
+			}
+		}
+	}
+
+	var res []*Stats
+	for name, s := range stats {
+		if s.Failures == 0 || !passed[name] {
+			continue
+		}
+		for b := range boards[name] {
+			s.Boards = append(s.Boards, b)
+		}
+		sort.Strings(s.Boards)
+		res = append(res, s)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if ri, rj := res[i].FlakeRate(), res[j].FlakeRate(); ri != rj {
+			return ri > rj
+		}
+		return res[i].Test < res[j].Test
+	})
+	return res
+}
+
+// lastRuns returns records belonging to the last n runs in recs. Runs are
+// ordered by the time of their first records.
+func lastRuns(recs []*Record, n int) []*Record {
+	first := make(map[string]time.Time)
+	for _, r := range recs {
+		if t, ok := first[r.Run]; !ok || r.Time.Before(t) {
+			first[r.Run] = r.Time
+		}
+	}
+	var runs []string
+	for run := range first {
+		runs = append(runs, run)
+	}
+	sort.Slice(runs, func(i, j int) bool {
+		if ti, tj := first[runs[i]], first[runs[j]]; !ti.Equal(tj) {
+			return ti.After(tj)
+		}
+		return runs[i] > runs[j]
+	})
+	if len(runs) <= n {
+		return recs
+	}
+	keep := make(map[string]bool)
+	for _, run := range runs[:n] {
+		keep[run] = true
+	}
+	var res []*Record
+	for _, r := range recs {
+		if keep[r.Run] {
+			res = append(res, r)
+		}
+	}
+	return res
+}
+
+// WriteSummary writes stats to w as a table, listing at most top tests if top
+// is positive.
+func WriteSummary(w io.Writer, stats []*Stats, top int) error {
+	if top > 0 && len(stats) > top {
+		stats = stats[:top]
+	}
+	if len(stats) == 0 {
+		_, err := io.WriteString(w, "No flaky tests found\n")
+		return err
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%-7s %-13s %-10s %-5s %s\n", "RATE", "FAIL/ATTEMPT", "RETRY-PASS", "RUNS", "TEST")
+	for _, s := range stats {
+		fmt.Fprintf(&buf, "%-7s %-13s %-10d %-5d %s",
+			fmt.Sprintf("%.1f%%", s.FlakeRate()*100), fmt.Sprintf("%d/%d", s.Failures, s.Attempts), s.RetryPasses, s.Runs, s.Test)
+		if len(s.Boards) > 0 {
+			fmt.Fprintf(&buf, " (failed on %s)", strings.Join(s.Boards, ", "))
+		}
+		buf.WriteString("\n")
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,198 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package flakes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go.chromium.org/tast/core/internal/run/reporting"
+	"go.chromium.org/tast/core/internal/run/resultsjson"
+)
+
+var baseTime = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+// runAttempts reports results of attempts to w as a run saving results to
+// resDir. Each element of outcomes is a test name optionally followed by
+// ":fail" or ":skip".
+func runAttempts(t *testing.T, w *Writer, resDir string, outcomes []string) {
+	ctx := context.Background()
+	run := &reporting.RunInfo{ResDir: resDir}
+	if err := w.RunStarted(ctx, run); err != nil {
+		t.Fatal("RunStarted failed: ", err)
+	}
+	for i, o := range outcomes {
+		name, status, _ := strings.Cut(o, ":")
+		res := &resultsjson.Result{
+			Test:  resultsjson.Test{Name: name},
+			Start: baseTime.Add(time.Duration(i) * time.Minute),
+			End:   baseTime.Add(time.Duration(i)*time.Minute + 2*time.Second),
+		}
+		switch status {
+		case "fail":
+			res.Errors = []resultsjson.Error{{Reason: "failed"}}
+		case "skip":
+			res.SkipReason = "missing deps"
+		}
+		if err := w.TestFinished(ctx, res); err != nil {
+			t.Fatal("TestFinished failed: ", err)
+		}
+	}
+	if err := w.RunFinished(ctx, run, &reporting.RunSummary{Complete: true}); err != nil {
+		t.Fatal("RunFinished failed: ", err)
+	}
+}
+
+func TestWriterCSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flakes.csv")
+	const image = "eve-release/R120-15633.0.0"
+
+	runAttempts(t, NewWriter(path, BoardFromImage(image), image), "/tmp/tast/results/run1",
+		[]string{"pkg.A:fail", "pkg.B:skip", "pkg.A"})
+	// Records are appended to the existing file.
+	runAttempts(t, NewWriter(path, "", ""), "/tmp/tast/results/run2",
+		[]string{"pkg.A"})
+
+	recs, err := ReadFile(path)
+	if err != nil {
+		t.Fatal("ReadFile failed: ", err)
+	}
+	want := []*Record{
+		{Time: baseTime.Add(2 * time.Second), Run: "run1", Test: "pkg.A", Attempt: 1, Status: StatusFail, Duration: 2 * time.Second, Board: "eve", Image: image},
+		{Time: baseTime.Add(time.Minute + 2*time.Second), Run: "run1", Test: "pkg.B", Attempt: 1, Status: StatusSkip, Duration: 2 * time.Second, Board: "eve", Image: image},
+		{Time: baseTime.Add(2*time.Minute + 2*time.Second), Run: "run1", Test: "pkg.A", Attempt: 2, Status: StatusPass, Duration: 2 * time.Second, Board: "eve", Image: image},
+		{Time: baseTime.Add(2 * time.Second), Run: "run2", Test: "pkg.A", Attempt: 1, Status: StatusPass, Duration: 2 * time.Second},
+	}
+	if diff := cmp.Diff(recs, want); diff != "" {
+		t.Errorf("Records mismatch (-got +want):\n%s", diff)
+	}
+}
+
+func TestWriterRemote(t *testing.T) {
+	var got []*Record
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	runAttempts(t, NewWriter(srv.URL, "eve", "eve-release/R120-15633.0.0"), "/tmp/tast/results/run1",
+		[]string{"pkg.A:fail", "pkg.A"})
+
+	if len(got) != 2 {
+		t.Fatalf("Endpoint got %d records; want 2", len(got))
+	}
+	if got[0].Status != StatusFail || got[1].Status != StatusPass || got[1].Attempt != 2 {
+		t.Errorf("Endpoint got records %+v, %+v; want a failure and a pass on the second attempt", got[0], got[1])
+	}
+}
+
+func TestWriterRemoteError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	w := NewWriter(srv.URL, "", "")
+	if err := w.TestFinished(ctx, &resultsjson.Result{Test: resultsjson.Test{Name: "pkg.A"}}); err != nil {
+		t.Fatal("TestFinished failed: ", err)
+	}
+	if err := w.RunFinished(ctx, &reporting.RunInfo{}, &reporting.RunSummary{}); err == nil {
+		t.Error("RunFinished unexpectedly succeeded for a failing endpoint")
+	}
+}
+
+func TestBoardFromImage(t *testing.T) {
+	for _, tc := range []struct {
+		image, want string
+	}{
+		{"eve-release/R120-15633.0.0", "eve"},
+		{"hatch-arc-r-postsubmit/R121-15700.0.0-1234", "hatch-arc-r"},
+		{"eveR120-15633.0.0 (Test Build)", ""},
+		{"NotAvail", ""},
+	} {
+		if got := BoardFromImage(tc.image); got != tc.want {
+			t.Errorf("BoardFromImage(%q) = %q; want %q", tc.image, got, tc.want)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	rec := func(run, test string, attempt int, status Status, board string) *Record {
+		return &Record{Time: baseTime, Run: run, Test: test, Attempt: attempt, Status: status, Board: board}
+	}
+	recs := []*Record{
+		rec("run1", "pkg.Flaky", 1, StatusFail, "eve"),
+		rec("run1", "pkg.Flaky", 2, StatusPass, "eve"),
+		rec("run1", "pkg.Broken", 1, StatusFail, "eve"),
+		rec("run1", "pkg.Stable", 1, StatusPass, "eve"),
+		rec("run1", "pkg.Skipped", 1, StatusSkip, "eve"),
+		rec("run2", "pkg.Flaky", 1, StatusPass, "kevin"),
+		rec("run2", "pkg.Broken", 1, StatusFail, "kevin"),
+		rec("run2", "pkg.Rare", 1, StatusPass, "kevin"),
+		rec("run3", "pkg.Rare", 1, StatusFail, "kevin"),
+		rec("run3", "pkg.Rare", 2, StatusFail, "kevin"),
+		rec("run4", "pkg.Rare", 1, StatusPass, "kevin"),
+	}
+	recs[len(recs)-4].Time = baseTime.Add(time.Hour)
+	recs[len(recs)-3].Time = baseTime.Add(2 * time.Hour)
+	recs[len(recs)-2].Time = baseTime.Add(2 * time.Hour)
+	recs[len(recs)-1].Time = baseTime.Add(3 * time.Hour)
+
+	got := Summarize(recs, 0)
+	want := []*Stats{
+		{Test: "pkg.Rare", Runs: 3, Attempts: 4, Failures: 2, Boards: []string{"kevin"}},
+		{Test: "pkg.Flaky", Runs: 2, Attempts: 3, Failures: 1, RetryPasses: 1, Boards: []string{"eve"}},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Summarize(recs, 0) mismatch (-got +want):\n%s", diff)
+	}
+
+	// Only pkg.Rare ran in the last two runs.
+	got = Summarize(recs, 2)
+	want = []*Stats{
+		{Test: "pkg.Rare", Runs: 2, Attempts: 3, Failures: 2, Boards: []string{"kevin"}},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Summarize(recs, 2) mismatch (-got +want):\n%s", diff)
+	}
+}
+
+func TestWriteSummary(t *testing.T) {
+	stats := []*Stats{
+		{Test: "pkg.Rare", Runs: 3, Attempts: 4, Failures: 2, Boards: []string{"kevin"}},
+		{Test: "pkg.Flaky", Runs: 2, Attempts: 3, Failures: 1, RetryPasses: 1},
+	}
+	var buf bytes.Buffer
+	if err := WriteSummary(&buf, stats, 1); err != nil {
+		t.Fatal("WriteSummary failed: ", err)
+	}
+	const want = `RATE    FAIL/ATTEMPT  RETRY-PASS RUNS  TEST
+50.0%   2/4           0          3     pkg.Rare (failed on kevin)
+`
+	if got := buf.String(); got != want {
+		t.Errorf("WriteSummary wrote:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestReadCSVMalformed(t *testing.T) {
+	if _, err := readCSV(strings.NewReader("2024-01-02T03:04:05Z,run1,pkg.A,one,PASS,1.000,eve,image\n")); err == nil {
+		t.Error("readCSV unexpectedly succeeded for a malformed attempt")
+	}
+	if _, err := ReadFile(filepath.Join(t.TempDir(), "missing.csv")); err == nil {
+		t.Error("ReadFile unexpectedly succeeded for a missing file")
+	}
+}
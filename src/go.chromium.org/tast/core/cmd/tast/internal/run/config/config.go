@@ -10,6 +10,7 @@ import (
 	"bufio"
 	"flag"
 	"fmt"
+	"math/rand"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -19,12 +20,19 @@ import (
 	"time"
 
 	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 
 	"go.chromium.org/tast/core/cmd/tast/internal/build"
+	"go.chromium.org/tast/core/cmd/tast/internal/run/resultsupload"
 	"go.chromium.org/tast/core/errors"
 	"go.chromium.org/tast/core/internal/command"
 	"go.chromium.org/tast/core/internal/debugger"
+	"go.chromium.org/tast/core/internal/logging"
 	"go.chromium.org/tast/core/internal/protocol"
+	"go.chromium.org/tast/core/internal/run/reporting"
+	"go.chromium.org/tast/core/internal/run/resultsjson"
+	"go.chromium.org/tast/core/internal/testing"
+	"go.chromium.org/tast/core/ssh"
 
 	frameworkprotocol "go.chromium.org/tast/core/framework/protocol"
 )
@@ -52,14 +60,45 @@ const (
 	ProxyNone
 )
 
+// TargetMode describes where local tests are run.
+type TargetMode int
+
+const (
+	// TargetDUT indicates that local tests are run on the target DUT over SSH.
+	TargetDUT TargetMode = iota
+	// TargetLocal indicates that local tests are run directly on the machine
+	// running the tast command without SSH, i.e. the host serves as the DUT.
+	// No target is given on the command line in this mode.
+	TargetLocal
+)
+
+// TestOrder describes the order in which tests are run.
+type TestOrder int
+
+const (
+	// TestOrderFixture indicates that tests are grouped by fixtures to
+	// minimize fixture setups.
+	TestOrderFixture TestOrder = iota
+	// TestOrderAlphabetical indicates that tests are run in alphabetical order.
+	TestOrderAlphabetical
+	// TestOrderRandom indicates that tests are run in a random order derived
+	// from the run-level seed.
+	TestOrderRandom
+	// TestOrderFailedFirst indicates that tests which failed in a previous run
+	// are run before other tests.
+	TestOrderFailedFirst
+)
+
 const (
 	defaultKeyFile               = "chromite/ssh_keys/testing_rsa" // default private SSH key within ChromeOS checkout
 	checkDepsCacheFile           = "check_deps_cache.v2.json"      // file in BuildOutDir where dependency-checking results are cached
+	buildCacheDir                = "cache"                         // directory in BuildOutDir where built executables are cached
 	defaultSystemServicesTimeout = 120 * time.Second               // default timeout for waiting for system services to be ready in seconds
 	defaultMsgTimeout            = 120 * time.Second               // default timeout for grpc connection.
 	defaultWaitUntilReadyTimeout = 120 * time.Second               // default timeout for the entire ready.Wait function
 	dutNotToConnect              = "-"                             // Target for dutless scenarios
 	defaultMaxSysMsgLogSize      = 20 * 1024 * 1024                // default Max System Message Log Size 20MB
+	defaultFailureSyslogPreRoll  = 30 * time.Second                // default time before a failed test whose system log is saved
 )
 
 // MutableConfig is similar to Config, but its fields are mutable.
@@ -67,11 +106,12 @@ const (
 type MutableConfig struct {
 	// See Config for descriptions of these fields.
 
-	KeyFile  string
-	KeyDir   string
-	Target   string
-	Patterns []string
-	ResDir   string
+	KeyFile    string
+	KeyDir     string
+	Target     string
+	TargetMode TargetMode
+	Patterns   []string
+	ResDir     string
 
 	Mode     Mode
 	TastDir  string
@@ -82,27 +122,34 @@ type MutableConfig struct {
 	BuildBundle        string
 	BuildWorkspace     string
 	BuildOutDir        string
+	BuildStatic        bool
+	PushBWLimit        int
 	CheckPortageDeps   bool
 	InstallPortageDeps bool
 
 	UseEphemeralDevserver     bool
 	ExtraAllowedBuckets       []string
 	Devservers                []string
+	DataMirrors               []string
 	BuildArtifactsURLOverride string
 	DownloadPrivateBundles    bool
 	DownloadMode              protocol.DownloadMode
 	TLWServer                 string
 	ReportsServer             string
 	CompanionDUTs             map[string]string
+	Phones                    map[string]string
+	RunMetadata               map[string]string
 	SwarmingTaskID            string
 	BuildBucketID             string
 	DUTLabConfig              *frameworkprotocol.DUTLabConfig
 
-	LocalRunner    string
-	LocalBundleDir string
-	LocalDataDir   string
-	LocalOutDir    string
-	LocalTempDir   string
+	LocalRunner       string
+	LocalRunnerDaemon bool
+	CompressControl   bool
+	LocalBundleDir    string
+	LocalDataDir      string
+	LocalOutDir       string
+	LocalTempDir      string
 
 	RemoteRunner    string
 	RemoteBundleDir string
@@ -111,36 +158,82 @@ type MutableConfig struct {
 	RemoteOutDir    string
 	RemoteTempDir   string
 
-	TotalShards int
-	ShardIndex  int
-	ShardMethod string
+	Drone        string
+	DroneWorkDir string
+
+	TotalShards      int
+	ShardIndex       int
+	ReportShardedOut bool
+	ShardMethod      string
 
 	SSHRetries           int
 	ContinueAfterFailure bool
 	CheckTestDeps        bool
 	WaitUntilReady       bool
 	ExtraUSEFlags        []string
+	ForcedFeatures       map[string]bool
 	Proxy                ProxyMode
 	CollectSysInfo       bool
+	SysInfoCollectors    []string
 	MaxTestFailures      int
+	Parallel             int
+	TestLogLevel         logging.Level
+	ScopedTestLogLevels  []string
+	TestLogRateLimit     int
+	TestLogBurst         int
+	Seed                 int64
+	TestOrder            TestOrder
+	PrevResultsDir       string
+	QuarantineThreshold  int
+	Repro                bool
+	UploadResults        string
+	DedupResults         bool
+	HTMLReport           bool
+	FlakeHistory         string
+	SymbolDir            string
+	UpdateGolden         bool
+	Reporters            []string
+	TastVersion          string
+	Args                 []string
+	Resume               bool
+	PreviousResults      []*resultsjson.Result
+	DUTProvider          string
+	DUTReplacements      int
+	PowerPreRunCommands  []string
+	PowerPostRunCommands []string
+	MinBatteryPercent    int
+	PowerSensitiveAttrs  []string
+	QuiescePolicy        *protocol.QuiescePolicy
 	ExcludeSkipped       bool
+	TestList             []string
+	ExcludeList          []string
+	ListFixtures         bool
 	ProxyCommand         string
-
-	TestVars         map[string]string
-	VarsFiles        []string
-	DefaultVarsDirs  []string
-	MaybeMissingVars string
+	Via                  string
+	Servo                string
+
+	TestVars             map[string]string
+	ScopedTestVars       []string
+	VarsFiles            []string
+	DefaultVarsDirs      []string
+	MaybeMissingVars     string
+	VarsProviders        []string
+	VarsProviderCacheTTL time.Duration
+	SecretVarNames       []string
 
 	DebuggerPorts          map[debugger.DebugTarget]int
 	DebuggerPortForwarding bool
 
-	Retries int
-	Repeats int
+	Retries         int
+	Repeats         int
+	Repeat          int
+	RepeatUntilFail bool
 
 	SystemServicesTimeout time.Duration
 	MsgTimeout            time.Duration
 	WaitUntilReadyTimeout time.Duration
 	MaxSysMsgLogSize      int64
+	FailureSyslogPreRoll  time.Duration
 
 	// ForceSkips is a mapping from a test name to the filter file name which specified
 	// the test should be disabled.
@@ -166,6 +259,9 @@ func (c *Config) KeyDir() string { return c.m.KeyDir }
 // Target is the target device for testing, in the form "[<user>@]host[:<port>]".
 func (c *Config) Target() string { return c.m.Target }
 
+// TargetMode is where local tests are run.
+func (c *Config) TargetMode() TargetMode { return c.m.TargetMode }
+
 // ProtoSSHConfig returns an SSHConfig proto.
 func (c *Config) ProtoSSHConfig() *protocol.SSHConfig {
 	return &protocol.SSHConfig{
@@ -203,6 +299,15 @@ func (c *Config) BuildWorkspace() string { return c.m.BuildWorkspace }
 // BuildOutDir is path to base directory under which executables are stored.
 func (c *Config) BuildOutDir() string { return c.m.BuildOutDir }
 
+// BuildStatic is whether to build fully static local executables and verify
+// that they are compatible with the target before pushing them. It allows
+// running tests on targets other than ChromeOS, e.g. generic Linux machines.
+func (c *Config) BuildStatic() bool { return c.m.BuildStatic }
+
+// PushBWLimit is the maximum bandwidth in KiB/s for pushing executables and
+// data files to the DUT, or 0 for unlimited.
+func (c *Config) PushBWLimit() int { return c.m.PushBWLimit }
+
 // CheckPortageDeps is check whether test bundle's dependencies are installed before building.
 func (c *Config) CheckPortageDeps() bool { return c.m.CheckPortageDeps }
 
@@ -220,6 +325,10 @@ func (c *Config) ExtraAllowedBuckets() []string {
 // Devservers is list of devserver URLs; set by -devservers but may be dynamically modified.
 func (c *Config) Devservers() []string { return append([]string(nil), c.m.Devservers...) }
 
+// DataMirrors is an ordered list of HTTP(S) mirrors of Google Cloud Storage to
+// fall back to when downloading external data files.
+func (c *Config) DataMirrors() []string { return append([]string(nil), c.m.DataMirrors...) }
+
 // BuildArtifactsURLOverride is Google Cloud Storage URL of build artifacts
 // specified in the command line. If it is empty, it should be detected from
 // the DUT.
@@ -246,6 +355,26 @@ func (c *Config) CompanionDUTs() map[string]string {
 	return duts
 }
 
+// Phones is role to adb address mapping of Android phones attached to the run.
+func (c *Config) Phones() map[string]string {
+	phones := make(map[string]string)
+	for k, v := range c.m.Phones {
+		phones[k] = v
+	}
+	return phones
+}
+
+// RunMetadata is run-level metadata, e.g. the name of the CI builder and the
+// CL under test, recorded in results and available to tests via
+// testing.ContextRunMetadata.
+func (c *Config) RunMetadata() map[string]string {
+	md := make(map[string]string)
+	for k, v := range c.m.RunMetadata {
+		md[k] = v
+	}
+	return md
+}
+
 // DUTLabConfig specifies the DUT lab configuration of the DUTs
 // used for the running Tast.
 func (c *Config) DUTLabConfig() *frameworkprotocol.DUTLabConfig {
@@ -263,6 +392,14 @@ func (c *Config) BuildBucketID() string { return c.m.BuildBucketID }
 // LocalRunner is path to executable that runs local test bundles.
 func (c *Config) LocalRunner() string { return c.m.LocalRunner }
 
+// LocalRunnerDaemon is whether to talk to a persistent local test runner
+// daemon on the DUT instead of starting a new runner process for each request.
+func (c *Config) LocalRunnerDaemon() bool { return c.m.LocalRunnerDaemon }
+
+// CompressControl is whether to ask the local test runner on the DUT to
+// compress control messages sent to the tast command with zstd.
+func (c *Config) CompressControl() bool { return c.m.CompressControl }
+
 // LocalBundleDir is dir where packaged local test bundles are installed.
 func (c *Config) LocalBundleDir() string { return c.m.LocalBundleDir }
 
@@ -294,12 +431,24 @@ func (c *Config) RemoteOutDir() string { return c.m.RemoteOutDir }
 // RemoteTempDir is dir where temporary files of remote tests are written.
 func (c *Config) RemoteTempDir() string { return c.m.RemoteTempDir }
 
+// Drone is an SSH connection spec of a jump host to run remote test bundles
+// on instead of this machine. It is empty if remote test bundles run locally.
+func (c *Config) Drone() string { return c.m.Drone }
+
+// DroneWorkDir is dir on the drone where remote test bundles, their data
+// files and outputs are saved.
+func (c *Config) DroneWorkDir() string { return c.m.DroneWorkDir }
+
 // TotalShards is total number of shards to be used in a test run.
 func (c *Config) TotalShards() int { return c.m.TotalShards }
 
 // ShardIndex specifies the index of shard to used in the current run.
 func (c *Config) ShardIndex() int { return c.m.ShardIndex }
 
+// ReportShardedOut specifies whether to report tests assigned to other shards
+// as skipped with the SHARDED_OUT code.
+func (c *Config) ReportShardedOut() bool { return c.m.ReportShardedOut }
+
 // ShardMethod specifies which sharding method we should use.
 func (c *Config) ShardMethod() string { return c.m.ShardMethod }
 
@@ -315,9 +464,31 @@ func (c *Config) CheckTestDeps() bool { return c.m.CheckTestDeps }
 // ExcludeSkipped is whether tests which would be skipped are excluded.
 func (c *Config) ExcludeSkipped() bool { return c.m.ExcludeSkipped }
 
+// TestList is names or globs of tests read from test list files. If it is
+// non-empty, only tests matching any of them are listed or run, in addition to
+// being selected by Patterns.
+func (c *Config) TestList() []string { return append([]string(nil), c.m.TestList...) }
+
+// ExcludeList is names or globs of tests read from exclude list files. Tests
+// matching any of them are not listed or run even if they are selected by
+// Patterns and TestList.
+func (c *Config) ExcludeList() []string { return append([]string(nil), c.m.ExcludeList...) }
+
+// ListFixtures is whether fixtures are also listed in ListTestsMode. Listed
+// fixtures are stored in DeprecatedState.Fixtures.
+func (c *Config) ListFixtures() bool { return c.m.ListFixtures }
+
 // ProxyCommand specifies the command to use to connect to the DUT.
 func (c *Config) ProxyCommand() string { return c.m.ProxyCommand }
 
+// Via is the proxy host to run the tast command on. If it is set, the tast
+// command deploys itself and remote test bundles to the proxy host and runs
+// tests from there, relaying logs and results back.
+func (c *Config) Via() string { return c.m.Via }
+
+// Servo is the address of servod for the primary DUT as "host:port".
+func (c *Config) Servo() string { return c.m.Servo }
+
 // WaitUntilReady is whether to wait for DUT to be ready before running tests.
 func (c *Config) WaitUntilReady() bool { return c.m.WaitUntilReady }
 
@@ -341,15 +512,176 @@ func (c *Config) DebuggerPortForwarding() bool { return c.m.DebuggerPortForwardi
 // ExtraUSEFlags is additional USE flags to inject when determining features.
 func (c *Config) ExtraUSEFlags() []string { return append([]string(nil), c.m.ExtraUSEFlags...) }
 
+// ForcedFeatures maps names of software features to whether to consider them
+// available, overriding the features evaluated on the DUT.
+func (c *Config) ForcedFeatures() map[string]bool {
+	ff := make(map[string]bool)
+	for k, v := range c.m.ForcedFeatures {
+		ff[k] = v
+	}
+	return ff
+}
+
 // Proxy is how proxies should be used.
 func (c *Config) Proxy() ProxyMode { return c.m.Proxy }
 
 // CollectSysInfo is collect system info (logs, crashes, etc.) generated during testing.
 func (c *Config) CollectSysInfo() bool { return c.m.CollectSysInfo }
 
+// SysInfoCollectors is names of optional collectors of system info (e.g.
+// "ec_console") to run in addition to the default ones.
+func (c *Config) SysInfoCollectors() []string {
+	return append([]string(nil), c.m.SysInfoCollectors...)
+}
+
 // MaxTestFailures is maximum number of test failures.
 func (c *Config) MaxTestFailures() int { return c.m.MaxTestFailures }
 
+// Parallel is the maximum number of tests to run concurrently in a test
+// bundle. Only tests declaring disjoint resources are run concurrently.
+func (c *Config) Parallel() int { return c.m.Parallel }
+
+// TestLogLevel is the minimum level of logs reported by tests. Logs of lower
+// levels, e.g. ones reported with State.VLog, are discarded.
+func (c *Config) TestLogLevel() logging.Level { return c.m.TestLogLevel }
+
+// ScopedTestLogLevels is minimum levels of logs reported by tests matching
+// patterns, as "<pattern>:<level>". They override TestLogLevel for matching
+// tests, and later ones take precedence over earlier ones.
+func (c *Config) ScopedTestLogLevels() []string {
+	return append([]string(nil), c.m.ScopedTestLogLevels...)
+}
+
+// TestLogRateLimit is the maximum number of log lines per second each test may
+// report. Excess lines are dropped by test bundles and replaced with truncation
+// markers. If it is 0, logs are not rate limited.
+func (c *Config) TestLogRateLimit() int { return c.m.TestLogRateLimit }
+
+// TestLogBurst is the maximum number of log lines each test may report at once
+// without being limited by TestLogRateLimit.
+func (c *Config) TestLogBurst() int { return c.m.TestLogBurst }
+
+// Seed is the run-level seed from which random number generators returned by
+// s.Rand are seeded deterministically for each test.
+func (c *Config) Seed() int64 { return c.m.Seed }
+
+// TestOrder is the order in which tests are run.
+func (c *Config) TestOrder() TestOrder { return c.m.TestOrder }
+
+// PrevResultsDir is the results directory of a previous run whose failed
+// tests are run first if TestOrder is TestOrderFailedFirst.
+func (c *Config) PrevResultsDir() string { return c.m.PrevResultsDir }
+
+// QuarantineThreshold is the number of times a test may crash the test bundle
+// or the DUT before it is quarantined for the rest of the run.
+func (c *Config) QuarantineThreshold() int { return c.m.QuarantineThreshold }
+
+// Repro is whether to package files needed to reproduce a failed run into a
+// tarball under ResDir.
+func (c *Config) Repro() bool { return c.m.Repro }
+
+// SymbolDir is a directory containing Breakpad symbol files used to symbolize
+// minidumps of crashes found after tests. Crashes are not symbolized if it is
+// empty.
+func (c *Config) SymbolDir() string { return c.m.SymbolDir }
+
+// UploadResults is a Cloud Storage URL in the form of "gs://bucket/prefix" to
+// upload the results directory to at the end of the run.
+func (c *Config) UploadResults() string { return c.m.UploadResults }
+
+// DedupResults is whether to store identical test artifacts in ResDir only
+// once at the end of the run. See the dedup package.
+func (c *Config) DedupResults() bool { return c.m.DedupResults }
+
+// HTMLReport is whether to write a standalone HTML report of results to
+// ResDir at the end of the run. It implies the "html" reporter.
+func (c *Config) HTMLReport() bool { return c.m.HTMLReport }
+
+// FlakeHistory is a path of a CSV file or an HTTP(S) URL to record outcomes of
+// all test attempts to. Outcomes are not recorded if it is empty.
+func (c *Config) FlakeHistory() string { return c.m.FlakeHistory }
+
+// UpdateGolden is whether to copy actual outputs mismatching golden files back
+// to the data directories under BuildWorkspace.
+func (c *Config) UpdateGolden() bool { return c.m.UpdateGolden }
+
+// Reporters is names of results reporters to enable. See
+// reporting.RegisterReporter.
+func (c *Config) Reporters() []string { return append([]string(nil), c.m.Reporters...) }
+
+// TastVersion is the version of the tast command.
+func (c *Config) TastVersion() string { return c.m.TastVersion }
+
+// Args is the command-line flags given to the run subcommand, excluding the
+// target and patterns. It is recorded in the run manifest so that an
+// interrupted run can be resumed later.
+func (c *Config) Args() []string { return append([]string(nil), c.m.Args...) }
+
+// Resume is whether the run resumes an interrupted run whose results are in
+// ResDir.
+func (c *Config) Resume() bool { return c.m.Resume }
+
+// PreviousResults is results of tests that already completed in an
+// interrupted run being resumed. They are included in results written to
+// ResDir along with results of this run.
+func (c *Config) PreviousResults() []*resultsjson.Result {
+	return append([]*resultsjson.Result(nil), c.m.PreviousResults...)
+}
+
+// DUTProvider is a spec of a provider to lease the target from, e.g.
+// "file:<path>". See dutprovider.New. If it is non-empty, Target is set to the
+// leased DUT for the duration of the run.
+func (c *Config) DUTProvider() string { return c.m.DUTProvider }
+
+// DUTReplacements is the maximum number of times to replace an unhealthy DUT
+// leased from DUTProvider with another one in the middle of a run.
+func (c *Config) DUTReplacements() int { return c.m.DUTReplacements }
+
+// WithTarget returns a copy of the configuration to run tests on target.
+func (c *Config) WithTarget(target string) *Config {
+	m := *c.m
+	m.Target = target
+	return m.Freeze()
+}
+
+// WithResume returns a copy of the configuration to resume the run by running
+// tests whose names are given as patterns. prev is results of tests that
+// already completed. Tests to run are already sharded, so sharding is
+// disabled.
+func (c *Config) WithResume(patterns []string, prev []*resultsjson.Result) *Config {
+	m := *c.m
+	m.Patterns = append([]string(nil), patterns...)
+	m.Resume = true
+	m.PreviousResults = append([]*resultsjson.Result(nil), prev...)
+	m.ShardIndex = 0
+	m.TotalShards = 1
+	return m.Freeze()
+}
+
+// PowerPolicy returns the power management policy to be applied by the local
+// test runner around test runs. It returns nil if no policy is configured.
+func (c *Config) PowerPolicy() *protocol.PowerPolicy {
+	if len(c.m.PowerPreRunCommands) == 0 && len(c.m.PowerPostRunCommands) == 0 && c.m.MinBatteryPercent <= 0 {
+		return nil
+	}
+	return &protocol.PowerPolicy{
+		PreRunCommands:      append([]string(nil), c.m.PowerPreRunCommands...),
+		PostRunCommands:     append([]string(nil), c.m.PowerPostRunCommands...),
+		MinBatteryPercent:   int32(c.m.MinBatteryPercent),
+		SensitiveAttributes: append([]string(nil), c.m.PowerSensitiveAttrs...),
+	}
+}
+
+// QuiescePolicy returns actions to be taken by the local test runner to
+// quiesce the DUT before running tests. It returns nil if no action is
+// configured.
+func (c *Config) QuiescePolicy() *protocol.QuiescePolicy {
+	if c.m.QuiescePolicy == nil {
+		return nil
+	}
+	return proto.Clone(c.m.QuiescePolicy).(*protocol.QuiescePolicy)
+}
+
 // TestVars is names and values of variables used to pass out-of-band data to tests.
 func (c *Config) TestVars() map[string]string {
 	vars := make(map[string]string)
@@ -359,12 +691,23 @@ func (c *Config) TestVars() map[string]string {
 	return vars
 }
 
+// ScopedTestVars is variables given only to tests matching patterns, as
+// "<pattern>:<name>=<value>". Later ones take precedence over earlier ones.
+func (c *Config) ScopedTestVars() []string { return append([]string(nil), c.m.ScopedTestVars...) }
+
 // VarsFiles is paths to variable files.
 func (c *Config) VarsFiles() []string { return append([]string(nil), c.m.VarsFiles...) }
 
 // DefaultVarsDirs is dirs containing default variable files.
 func (c *Config) DefaultVarsDirs() []string { return append([]string(nil), c.m.DefaultVarsDirs...) }
 
+// VarsProviders is specs of providers of runtime variables.
+func (c *Config) VarsProviders() []string { return append([]string(nil), c.m.VarsProviders...) }
+
+// SecretVarNames is names of runtime variables obtained from vars providers,
+// whose values must not appear in logs or results.
+func (c *Config) SecretVarNames() []string { return append([]string(nil), c.m.SecretVarNames...) }
+
 // MaybeMissingVars is regex matching with variables which may be missing.
 func (c *Config) MaybeMissingVars() string { return c.m.MaybeMissingVars }
 
@@ -380,6 +723,23 @@ func (c *Config) Retries() int { return c.m.Retries }
 // Repeats is the number of times each subsequent test should execute.
 func (c *Config) Repeats() int { return c.m.Repeats }
 
+// Repeat is the number of iterations to run tests for. If it is positive,
+// results of each iteration are written to a separate directory under ResDir.
+// If RepeatUntilFail is also set, it is the maximum number of iterations.
+func (c *Config) Repeat() int { return c.m.Repeat }
+
+// RepeatUntilFail is whether to run tests repeatedly until any of them fails.
+func (c *Config) RepeatUntilFail() bool { return c.m.RepeatUntilFail }
+
+// RepeatIterations returns whether tests are run in iterations with separate
+// result directories, i.e. either -repeat or -repeat-until-fail is set.
+func (c *Config) RepeatIterations() bool { return c.m.Repeat > 0 || c.m.RepeatUntilFail }
+
+// FailureSyslogPreRoll is the extra time before the start of a failed test
+// whose system log entries are saved to the test's output directory. If it is
+// negative, system log entries are not saved.
+func (c *Config) FailureSyslogPreRoll() time.Duration { return c.m.FailureSyslogPreRoll }
+
 // SystemServicesTimeout for waiting for system services to be ready in seconds. (Default: 120 seconds)
 func (c *Config) SystemServicesTimeout() time.Duration {
 	return c.m.SystemServicesTimeout
@@ -402,8 +762,9 @@ func (c *Config) ForceSkips() map[string]*protocol.ForceSkip {
 // difficult to reason about function contracts. Pass arguments explicitly
 // instead. This struct will be removed eventually (b/191230756).
 type DeprecatedState struct {
-	RemoteDevservers []string // list of devserver URLs used by remote tests.
-	TestNamesToSkip  []string // tests that match patterns but are not sent to runners to run
+	RemoteDevservers []string           // list of devserver URLs used by remote tests.
+	TestNamesToSkip  []string           // tests that match patterns but are not sent to runners to run
+	Fixtures         []*protocol.Entity // fixtures listed in ListTestsMode if ListFixtures is set
 }
 
 // NewMutableConfig returns a new configuration for executing test runners in the supplied mode.
@@ -412,12 +773,16 @@ type DeprecatedState struct {
 // trunkDir is the path to the ChromeOS checkout (within the chroot).
 func NewMutableConfig(mode Mode, tastDir, trunkDir string) *MutableConfig {
 	return &MutableConfig{
-		Mode:          mode,
-		TastDir:       tastDir,
-		TrunkDir:      trunkDir,
-		TestVars:      make(map[string]string),
-		CompanionDUTs: make(map[string]string),
-		ForceSkips:    make(map[string]*protocol.ForceSkip),
+		Mode:           mode,
+		TastDir:        tastDir,
+		TrunkDir:       trunkDir,
+		TestVars:       make(map[string]string),
+		CompanionDUTs:  make(map[string]string),
+		Phones:         make(map[string]string),
+		RunMetadata:    make(map[string]string),
+		ForcedFeatures: make(map[string]bool),
+		ForceSkips:     make(map[string]*protocol.ForceSkip),
+		Reporters:      append([]string(nil), reporting.DefaultReporters...),
 	}
 }
 
@@ -428,7 +793,7 @@ func ShouldConnect(target string) bool {
 
 // SetFlags adds common run-related flags to f that store values in Config.
 func (c *MutableConfig) SetFlags(f *flag.FlagSet) {
-	kf := filepath.Join(c.TrunkDir, defaultKeyFile)
+	kf := DefaultKeyFile(c.TrunkDir)
 	if _, err := os.Stat(kf); err != nil {
 		kf = ""
 	}
@@ -444,9 +809,12 @@ func (c *MutableConfig) SetFlags(f *flag.FlagSet) {
 	f.StringVar(&c.BuildBundle, "buildbundle", "cros", "name of test bundle to build")
 	f.StringVar(&c.BuildWorkspace, "buildworkspace", "", "path to Go workspace containing test bundle source code, inferred if empty")
 	f.StringVar(&c.BuildOutDir, "buildoutdir", filepath.Join(c.TastDir, "build"), "directory where compiled executables are saved")
+	f.BoolVar(&c.BuildStatic, "buildstatic", false, "build fully static local executables and verify their compatibility with the target before pushing (for non-ChromeOS targets)")
+	f.IntVar(&c.PushBWLimit, "pushbwlimit", 0, "maximum bandwidth in KiB/s for pushing executables and data files to the DUT (0 for unlimited)")
 	f.BoolVar(&c.CheckPortageDeps, "checkbuilddeps", true, "check test bundle's dependencies before building")
 	f.BoolVar(&c.InstallPortageDeps, "installbuilddeps", true, "automatically install/upgrade test bundle dependencies (requires -checkbuilddeps)")
 	f.Var(command.NewListFlag(",", func(v []string) { c.Devservers = v }, nil), "devservers", "comma-separated list of devserver URLs")
+	f.Var(command.NewListFlag(",", func(v []string) { c.DataMirrors = v }, nil), "datamirrors", "comma-separated list of HTTP(S) mirrors of Google Cloud Storage to fall back to when downloading external data files")
 	f.BoolVar(&c.UseEphemeralDevserver, "ephemeraldevserver", true, "start an ephemeral devserver if no devserver is specified")
 	f.Var(command.NewListFlag(",", func(v []string) { c.ExtraAllowedBuckets = v }, nil), "extraallowedbuckets", "comma-separated list of extra Google Cloud Storage buckets ephemeral devserver is allowed to access")
 	f.StringVar(&c.BuildArtifactsURLOverride, "buildartifactsurl", "", "override Google Cloud Storage URL of build artifacts (implies -extraallowedbuckets)")
@@ -461,18 +829,84 @@ func (c *MutableConfig) SetFlags(f *flag.FlagSet) {
 	f.IntVar(&c.SSHRetries, "sshretries", 0, "number of SSH connect retries")
 	f.StringVar(&c.TLWServer, "tlwserver", "", "TLW server address")
 	f.StringVar(&c.ReportsServer, "reports_server", "", "Reports server address")
-	f.IntVar(&c.MaxTestFailures, "maxtestfailures", 0, "the maximum number test failures allowed (default to 0 which means no limit)")
+	f.IntVar(&c.MaxTestFailures, "maxfailures", 0, "abort the run and mark remaining tests as not run once this many tests have failed (default to 0 which means no limit)")
+	f.IntVar(&c.MaxTestFailures, "maxtestfailures", 0, "deprecated alias of -maxfailures")
+	f.IntVar(&c.Parallel, "parallel", 1, "the maximum number of tests declaring disjoint resources to run concurrently in a test bundle")
+	tllf := command.RepeatedFlag(func(v string) error {
+		// A level scoped to tests matching a pattern looks like
+		// "ui.*:info". Test patterns can not contain colons.
+		if strings.Contains(v, ":") {
+			if _, err := testing.ParseScopedLogLevel(v); err != nil {
+				return err
+			}
+			c.ScopedTestLogLevels = append(c.ScopedTestLogLevels, v)
+			return nil
+		}
+		level, err := testing.ParseLogLevel(v)
+		if err != nil {
+			return err
+		}
+		c.TestLogLevel = level
+		return nil
+	})
+	f.Var(&tllf, "testloglevel", `minimum level of logs reported by tests, "debug" or "info" to discard s.VLog logs, or "pattern:level" to apply it only to tests matching pattern (can be repeated; default "debug")`)
+	f.IntVar(&c.TestLogRateLimit, "testlogratelimit", 0, "maximum number of log lines per second each test may report; excess lines are truncated (default to 0 which means no limit)")
+	f.IntVar(&c.TestLogBurst, "testlogburst", 0, "maximum number of log lines each test may report at once without being rate limited (default to -testlogratelimit)")
+	f.Int64Var(&c.Seed, "seed", 0, "seed of random number generators returned by s.Rand; the seed of a previous run is recorded in results.json (default to 0 which means a random seed)")
+	tos := map[string]int{
+		"fixture":      int(TestOrderFixture),
+		"alphabetical": int(TestOrderAlphabetical),
+		"random":       int(TestOrderRandom),
+		"failedfirst":  int(TestOrderFailedFirst),
+	}
+	tof := command.NewEnumFlag(tos, func(v int) { c.TestOrder = TestOrder(v) }, "fixture")
+	f.Var(tof, "order", fmt.Sprintf("order in which tests are run; \"random\" shuffles tests with -seed (%s; default %q)", tof.QuotedValues(), tof.Default()))
+	f.StringVar(&c.PrevResultsDir, "prevresultsdir", "", "results directory of a previous run whose failed tests are run first with -order=failedfirst (default to the latest results directory)")
+	f.IntVar(&c.QuarantineThreshold, "quarantinethreshold", 0, "number of crashes of the test bundle or DUT after which a test is not run again (default to 0 which means no quarantine)")
 	f.StringVar(&c.ProxyCommand, "proxycommand", "", "command to use to connect to the DUT.")
+	f.StringVar(&c.Servo, "servo", "", `servod address for the primary DUT as "host:port", available to remote tests via s.Servo()`)
+	f.BoolVar(&c.Repro, "repro", false, "package files needed to reproduce a failed run into repro.tar.gz in the result directory")
+	f.BoolVar(&c.UpdateGolden, "updategolden", false, "copy actual outputs mismatching golden files back to test data directories in the source tree")
+
+	powerPreRun := command.RepeatedFlag(func(v string) error {
+		c.PowerPreRunCommands = append(c.PowerPreRunCommands, v)
+		return nil
+	})
+	f.Var(&powerPreRun, "powerpreruncmd", "shell command to run on the DUT before running tests, e.g. to force battery discharge (can be repeated)")
+	powerPostRun := command.RepeatedFlag(func(v string) error {
+		c.PowerPostRunCommands = append(c.PowerPostRunCommands, v)
+		return nil
+	})
+	f.Var(&powerPostRun, "powerpostruncmd", "shell command to run on the DUT after running tests, e.g. to restore charging (can be repeated)")
+	f.IntVar(&c.MinBatteryPercent, "minbatterypercent", 0, "skip power-sensitive tests if the DUT battery level is below this percentage (default to 0 which means no check)")
+	f.Var(command.NewListFlag(",", func(v []string) { c.PowerSensitiveAttrs = v }, []string{"graphics_power"}), "powersensitiveattrs", "comma-separated list of attributes of power-sensitive tests")
+	quiesce := command.RepeatedFlag(func(v string) error {
+		if c.QuiescePolicy == nil {
+			c.QuiescePolicy = &protocol.QuiescePolicy{}
+		}
+		return parseQuiesceActions(v, c.QuiescePolicy)
+	})
+	f.Var(&quiesce, "quiesce", `comma-separated actions to quiesce the DUT before running tests, reverted after running tests: "updateengine" (stop update_engine), "arcprovisioning" (disable ARC provisioning), "cpugovernor=<governor>" (pin CPU frequency governors) and "crashspool" (clear crash spool) (can be repeated)`)
 
 	f.IntVar(&c.TotalShards, "totalshards", 1, "total number of shards to be used in a test run")
 	f.IntVar(&c.ShardIndex, "shardindex", 0, "the index of shard to used in the current run")
+	f.BoolVar(&c.ReportShardedOut, "reportshardedout", false, "report tests assigned to other shards as skipped; do not set it if results of shards are merged")
 	f.StringVar(&c.ShardMethod, "shardmethod", "alpha", "the method used to split the shards (one of \"hash\" or \"alpha\")")
 
 	f.StringVar(&c.LocalRunner, "localrunner", "", "executable that runs local test bundles")
+	f.BoolVar(&c.LocalRunnerDaemon, "localrunnerdaemon", false, "keep the local test runner running on the DUT across runs to reduce startup overhead")
+	f.BoolVar(&c.CompressControl, "compresscontrol", false, "compress control messages from the local test runner on the DUT with zstd to reduce SSH bandwidth")
 	f.StringVar(&c.LocalBundleDir, "localbundledir", "", "directory containing builtin local test bundles")
 	f.StringVar(&c.LocalDataDir, "localdatadir", "", "directory containing builtin local test data")
 	f.StringVar(&c.LocalOutDir, "localoutdir", "", "directory where intermediate test outputs are written")
-	f.StringVar(&c.LocalTempDir, "localtempdir", "/usr/local/tmp/tast/run_tmp", "directory where local test temporary files are written")
+	f.StringVar(&c.LocalTempDir, "localtempdir", "", "directory where local test temporary files are written")
+
+	tms := map[string]int{
+		"dut":   int(TargetDUT),
+		"local": int(TargetLocal),
+	}
+	tmf := command.NewEnumFlag(tms, func(v int) { c.TargetMode = TargetMode(v) }, "dut")
+	f.Var(tmf, "target", fmt.Sprintf("where to run local tests; \"local\" runs them on this machine without a DUT (%s; default %q)", tmf.QuotedValues(), tmf.Default()))
 
 	// These are configurable since files may be installed elsewhere when running in the lab.
 	f.StringVar(&c.RemoteRunner, "remoterunner", "", "executable that runs remote test bundles")
@@ -490,6 +924,7 @@ func (c *MutableConfig) SetFlags(f *flag.FlagSet) {
 
 	f.Var(command.NewDurationFlag(time.Second, &c.SystemServicesTimeout, defaultSystemServicesTimeout), "systemservicestimeout", "timeout for waiting for system services to be ready in seconds")
 	f.Var(command.NewDurationFlag(time.Second, &c.MsgTimeout, defaultMsgTimeout), "connectiontimeout", "the value time interval in seconds for tast to check if the connection to target is alive (default to 60 which means 1 mins)")
+	f.Var(command.NewDurationFlag(time.Second, &c.FailureSyslogPreRoll, defaultFailureSyslogPreRoll), "failuresyslogpreroll", "seconds of system log before the start of a failed test to save in its output directory, or -1 to not save system log of failed tests")
 	f.Int64Var(&c.MaxSysMsgLogSize, "maxsysmsglogsize", defaultMaxSysMsgLogSize, "max size for the downloaded system message log after each test (default to 20MB)")
 
 	c.DebuggerPorts = map[debugger.DebugTarget]int{
@@ -523,15 +958,43 @@ func (c *MutableConfig) SetFlags(f *flag.FlagSet) {
 	})
 	f.Var(&filterFile, "testfilterfile", `a file indicates which tests to be disable (can be repeated)`)
 
+	testList := command.RepeatedFlag(func(fileName string) error {
+		pats, err := readTestList(fileName)
+		if err != nil {
+			return err
+		}
+		c.TestList = append(c.TestList, pats...)
+		return nil
+	})
+	f.Var(&testList, "testlist", "a file listing names or globs of tests to list or run among those matched by patterns, one per line (can be repeated)")
+	excludeList := command.RepeatedFlag(func(fileName string) error {
+		pats, err := readTestList(fileName)
+		if err != nil {
+			return err
+		}
+		c.ExcludeList = append(c.ExcludeList, pats...)
+		return nil
+	})
+	f.Var(&excludeList, "excludelist", "a file listing names or globs of tests not to list or run, one per line (can be repeated)")
+
 	vf := command.RepeatedFlag(func(v string) error {
 		parts := strings.SplitN(v, "=", 2)
 		if len(parts) != 2 {
-			return errors.New(`want "name=value"`)
+			return errors.New(`want "name=value" or "pattern:name=value"`)
+		}
+		// A variable scoped to tests matching a pattern looks like
+		// "ui.*:timeout=30s". Test patterns can not contain colons.
+		if strings.Contains(parts[0], ":") {
+			if _, err := testing.ParseScopedVar(v); err != nil {
+				return err
+			}
+			c.ScopedTestVars = append(c.ScopedTestVars, v)
+			return nil
 		}
 		c.TestVars[parts[0]] = parts[1]
 		return nil
 	})
-	f.Var(&vf, "var", `runtime variable to pass to tests, as "name=value" (can be repeated)`)
+	f.Var(&vf, "var", `runtime variable to pass to tests, as "name=value", or as "pattern:name=value" to pass it only to tests matching pattern (can be repeated)`)
 	dvd := command.RepeatedFlag(func(path string) error {
 		c.DefaultVarsDirs = append(c.DefaultVarsDirs, path)
 		return nil
@@ -542,6 +1005,12 @@ func (c *MutableConfig) SetFlags(f *flag.FlagSet) {
 		return nil
 	})
 	f.Var(&vff, "varsfile", "YAML file containing variables (can be repeated)")
+	vpf := command.RepeatedFlag(func(spec string) error {
+		c.VarsProviders = append(c.VarsProviders, spec)
+		return nil
+	})
+	f.Var(&vpf, "varsprovider", `provider of secret variables, e.g. "gsm:<project>" for Google Secret Manager (can be repeated)`)
+	f.DurationVar(&c.VarsProviderCacheTTL, "varsprovidercachettl", time.Hour, "duration to cache variables from -varsprovider locally; 0 to disable caching")
 	// TODO(oka): Use flag.Func once it's available.
 	f.Var(funcValue(func(s string) error {
 		c.MaybeMissingVars = s
@@ -553,11 +1022,38 @@ func (c *MutableConfig) SetFlags(f *flag.FlagSet) {
 	if c.Mode == RunTestsMode {
 		f.StringVar(&c.ResDir, "resultsdir", "", "directory for test results")
 		f.BoolVar(&c.CollectSysInfo, "sysinfo", true, "collect system information (logs, crashes, etc.)")
+		f.Var(command.NewListFlag(",", func(v []string) { c.SysInfoCollectors = v }, nil), "sysinfocollectors",
+			"comma-separated list of optional system info collectors to run (ec_console, ish, bt_hci)")
+		f.Var(command.NewListFlag(",", func(v []string) { c.Reporters = v }, reporting.DefaultReporters), "reporters",
+			fmt.Sprintf("comma-separated list of results reporters to enable (%s)", strings.Join(reporting.ReporterNames(), ", ")))
+		f.StringVar(&c.SymbolDir, "symboldir", "", "directory containing Breakpad symbol files to symbolize minidumps of crashes found after tests (empty to skip)")
+		f.StringVar(&c.UploadResults, "uploadresults", "", `Google Cloud Storage URL ("gs://bucket/prefix") to upload the results directory to at the end of the run`)
+		f.BoolVar(&c.DedupResults, "dedupresults", false, "store identical test artifacts in the results directory only once under blobs/ and replace copies with symlinks")
+		f.BoolVar(&c.HTMLReport, "htmlreport", false, "write a standalone HTML report of results to results.html in the results directory at the end of the run")
+		f.StringVar(&c.DUTProvider, "dutprovider", "", `provider to lease the target from, e.g. "file:<path>" for DUTs listed in a file or "cmd:<command>" for a lab scheduler (the target argument is omitted)`)
+		f.IntVar(&c.DUTReplacements, "dutreplacements", 1, "maximum number of unhealthy DUTs from -dutprovider to replace with other ones in the middle of a run")
+		f.StringVar(&c.Drone, "drone", "", `jump host ("[<user>@]host[:<port>]") to run remote test bundles on instead of this machine (empty to run them locally)`)
+		f.StringVar(&c.DroneWorkDir, "droneworkdir", "/tmp/tast_drone", "directory on the -drone host where remote test bundles, data files and outputs are saved")
+		f.StringVar(&c.Via, "via", "", `proxy host ("[<user>@]host[:<port>]") to run this command on, for DUTs reachable only from there (requires -build=false)`)
+		f.StringVar(&c.FlakeHistory, "flakehistory", "", `CSV file path or HTTP(S) URL to record outcomes of all test attempts to, for use with "tast flakes"`)
 		f.BoolVar(&c.WaitUntilReady, "waituntilready", true, "wait until DUT is ready before running tests")
 		f.Var(command.NewDurationFlag(time.Second, &c.WaitUntilReadyTimeout, defaultWaitUntilReadyTimeout), "waituntilreadytimeout", "timeout for the entire ready.Wait function")
 
 		f.Var(command.NewListFlag(",", func(v []string) { c.ExtraUSEFlags = v }, nil), "extrauseflags",
 			"comma-separated list of additional USE flags to inject when checking test dependencies")
+		forceFeature := command.RepeatedFlag(func(kv string) error {
+			parts := strings.SplitN(kv, "=", 2)
+			if len(parts) != 2 || parts[0] == "" {
+				return errors.New(`want "name=true|false"`)
+			}
+			avail, err := strconv.ParseBool(parts[1])
+			if err != nil {
+				return errors.New(`want "name=true|false"`)
+			}
+			c.ForcedFeatures[parts[0]] = avail
+			return nil
+		})
+		f.Var(&forceFeature, "forcefeature", `software feature to force available or unavailable when checking test dependencies, as "name=true|false" (can be repeated)`)
 
 		vals := map[string]int{
 			"env":  int(ProxyEnv),
@@ -577,6 +1073,26 @@ func (c *MutableConfig) SetFlags(f *flag.FlagSet) {
 		})
 		f.Var(&compDUTs, "companiondut", `role to companion DUT, as "role:address" (can be repeated)`)
 
+		phones := command.RepeatedFlag(func(roleToPhone string) error {
+			parts := strings.SplitN(roleToPhone, ":", 2)
+			if len(parts) != 2 {
+				return errors.New(`want "role:host:port"`)
+			}
+			c.Phones[parts[0]] = parts[1]
+			return nil
+		})
+		f.Var(&phones, "phone", `role to Android phone reachable with adb over the network, as "role:host:port" (can be repeated)`)
+
+		metadata := command.RepeatedFlag(func(kv string) error {
+			parts := strings.SplitN(kv, "=", 2)
+			if len(parts) != 2 || parts[0] == "" {
+				return errors.New(`want "key=value"`)
+			}
+			c.RunMetadata[parts[0]] = parts[1]
+			return nil
+		})
+		f.Var(&metadata, "metadata", `run-level metadata to record in results and pass to tests, e.g. the builder name or the CL under test, as "key=value" (can be repeated)`)
+
 		readLabConfig := func(filename string) error {
 			labConfig := &frameworkprotocol.DUTLabConfig{}
 			data, err := os.ReadFile(filename)
@@ -594,6 +1110,8 @@ func (c *MutableConfig) SetFlags(f *flag.FlagSet) {
 
 		f.IntVar(&c.Retries, "retries", 0, `number of times to retry a failing test`)
 		f.IntVar(&c.Repeats, "repeats", 0, `number of times to execute a set of tests after the initial execution`)
+		f.IntVar(&c.Repeat, "repeat", 0, `number of iterations to run tests for, writing results of each iteration to a separate directory`)
+		f.BoolVar(&c.RepeatUntilFail, "repeat-until-fail", false, `run tests repeatedly until any of them fails (at most -repeat iterations if set)`)
 	}
 }
 
@@ -624,10 +1142,26 @@ func (c *MutableConfig) DeriveDefaults() error {
 
 	// Generate a timestamped directory path to always create a new one.
 	ts := time.Now().Format("20060102-150405.000000000")
-	setIfEmpty(&c.LocalOutDir, fmt.Sprintf("/usr/local/tmp/tast_out.%s", ts))
 	// RemoteOutDir should be under ResDir so that we can move files with os.Rename (crbug.com/813282).
 	setIfEmpty(&c.RemoteOutDir, fmt.Sprintf("%s/tast_out.%s", c.ResDir, ts))
 
+	if c.TargetMode == TargetLocal {
+		// Local tests run on this machine, so their outputs can be moved
+		// with os.Rename as well. The temporary directory is left empty
+		// for local bundles to choose one under os.TempDir.
+		setIfEmpty(&c.LocalOutDir, fmt.Sprintf("%s/tast_local_out.%s", c.ResDir, ts))
+		if c.Build {
+			// Executables are built for the host and run in place, and
+			// data files are read from the source checkout directly.
+			setIfEmpty(&c.LocalRunner, filepath.Join(c.BuildOutDir, build.ArchHost, "local_test_runner"))
+			setIfEmpty(&c.LocalBundleDir, filepath.Join(c.BuildOutDir, build.ArchHost, build.LocalBundleBuildSubdir))
+			setIfEmpty(&c.LocalDataDir, filepath.Join(c.BuildWorkspace, "src"))
+		}
+	} else {
+		setIfEmpty(&c.LocalOutDir, fmt.Sprintf("/usr/local/tmp/tast_out.%s", ts))
+		setIfEmpty(&c.LocalTempDir, "/usr/local/tmp/tast/run_tmp")
+	}
+
 	if c.Build {
 		// If -build=true, use different paths than -build=false to avoid overwriting
 		// Portage-managed files.
@@ -663,6 +1197,41 @@ func (c *MutableConfig) DeriveDefaults() error {
 		}
 	}
 
+	// Apply -varsprovider. Variables given by -var and -varsfile take
+	// precedence.
+	if err := c.applyVarsProviders(); err != nil {
+		return err
+	}
+
+	// -servo also serves as the servo variable used by the framework.
+	if c.Servo != "" {
+		if _, ok := c.TestVars["servo"]; !ok {
+			c.TestVars["servo"] = c.Servo
+		}
+	}
+
+	if c.DUTProvider != "" {
+		if c.TargetMode == TargetLocal {
+			return errors.New("-dutprovider and -target=local are mutually exclusive")
+		}
+		if c.DUTReplacements < 0 {
+			return errors.New("-dutreplacements must not be negative")
+		}
+	}
+
+	// -htmlreport is a shorthand to enable the html reporter.
+	if c.HTMLReport {
+		enabled := false
+		for _, name := range c.Reporters {
+			if name == "html" {
+				enabled = true
+			}
+		}
+		if !enabled {
+			c.Reporters = append(c.Reporters, "html")
+		}
+	}
+
 	// Apply variables from default configurations.
 	if len(c.DefaultVarsDirs) == 0 {
 		// TODO: b/324133828 -- Use only src/* after /etc/tast/vars are removed
@@ -698,7 +1267,7 @@ func (c *MutableConfig) DeriveDefaults() error {
 			return fmt.Errorf("failed to apply vars from %s: %v", path, err)
 		}
 	}
-	mergeVars(c.TestVars, defaultVars, skipOnDuplicate) // -var and -varsfile override defaults
+	mergeVars(c.TestVars, defaultVars, skipOnDuplicate) // -var, -varsfile and -varsprovider override defaults
 
 	if c.BuildArtifactsURLOverride != "" {
 		if !strings.HasSuffix(c.BuildArtifactsURLOverride, "/") {
@@ -715,6 +1284,37 @@ func (c *MutableConfig) DeriveDefaults() error {
 		}
 		c.ExtraAllowedBuckets = append(c.ExtraAllowedBuckets, u.Host)
 	}
+	if c.PushBWLimit < 0 {
+		return fmt.Errorf("-pushbwlimit must not be negative: %d", c.PushBWLimit)
+	}
+	// Pick a random non-zero seed if unspecified. It is recorded in results
+	// so that the run can be reproduced with -seed.
+	for c.Seed == 0 {
+		c.Seed = rand.Int63()
+	}
+	if c.TestOrder == TestOrderFailedFirst && c.PrevResultsDir == "" {
+		// Resolve the symlink now since it is updated to point to ResDir
+		// before tests are run.
+		dir, err := filepath.EvalSymlinks(filepath.Join(c.TastDir, "results", "latest"))
+		if err != nil {
+			return fmt.Errorf("-order=failedfirst: no previous results found; please set -prevresultsdir: %v", err)
+		}
+		c.PrevResultsDir = dir
+	}
+	if c.Drone != "" {
+		var o ssh.Options
+		if err := ssh.ParseTarget(c.Drone, &o); err != nil {
+			return fmt.Errorf("invalid -drone: %v", err)
+		}
+		if !filepath.IsAbs(c.DroneWorkDir) {
+			return fmt.Errorf("-droneworkdir must be an absolute path: %q", c.DroneWorkDir)
+		}
+	}
+	if c.UploadResults != "" {
+		if _, _, err := resultsupload.ParseURL(c.UploadResults); err != nil {
+			return fmt.Errorf("invalid -uploadresults: %v", err)
+		}
+	}
 	if c.TotalShards < 1 {
 		return fmt.Errorf("%v is an invalid number of shards", c.ShardIndex)
 	}
@@ -725,6 +1325,12 @@ func (c *MutableConfig) DeriveDefaults() error {
 		return fmt.Errorf("-shardmethod must be either 'hash' or 'alpha'")
 	}
 
+	if c.Via != "" {
+		if err := c.checkVia(); err != nil {
+			return err
+		}
+	}
+
 	if !c.Build {
 		for _, port := range c.DebuggerPorts {
 			if port != 0 {
@@ -739,6 +1345,62 @@ func (c *MutableConfig) DeriveDefaults() error {
 	return nil
 }
 
+// viaRegexp matches values of -via in the form "[<user>@]host[:<port>]".
+// Characters are restricted to ones in user names, host names and addresses.
+var viaRegexp = regexp.MustCompile(`^([A-Za-z0-9_][A-Za-z0-9._-]*@)?([A-Za-z0-9][A-Za-z0-9.-]*|\[[0-9A-Fa-f:.]+\])(:[0-9]+)?$`)
+
+// checkVia returns an error if -via is invalid or used with flags that refer
+// to resources available only on this machine.
+func (c *MutableConfig) checkVia() error {
+	if !viaRegexp.MatchString(c.Via) {
+		return fmt.Errorf("invalid -via: %q is not in the form \"[<user>@]host[:<port>]\"", c.Via)
+	}
+	// The tast command on the proxy host can not build test bundles, so
+	// prebuilt ones are deployed there.
+	if c.Build {
+		return errors.New("-via requires -build=false")
+	}
+	for _, f := range []struct {
+		name string
+		set  bool
+	}{
+		{"-proxycommand", c.ProxyCommand != ""},
+		{"-drone", c.Drone != ""},
+		{"-dutprovider", c.DUTProvider != ""},
+		{"-target=local", c.TargetMode == TargetLocal},
+		{"-order=failedfirst", c.TestOrder == TestOrderFailedFirst},
+		{"-flakehistory", c.FlakeHistory != ""},
+		{"-symboldir", c.SymbolDir != ""},
+	} {
+		if f.set {
+			return fmt.Errorf("-via and %s are mutually exclusive", f.name)
+		}
+	}
+	return nil
+}
+
+// parseQuiesceActions parses comma-separated quiesce actions given to the
+// -quiesce flag, e.g. "updateengine,cpugovernor=performance", and sets them
+// to policy.
+func parseQuiesceActions(v string, policy *protocol.QuiescePolicy) error {
+	for _, a := range strings.Split(v, ",") {
+		name, arg, hasArg := strings.Cut(a, "=")
+		switch {
+		case name == "updateengine" && !hasArg:
+			policy.StopUpdateEngine = true
+		case name == "arcprovisioning" && !hasArg:
+			policy.DisableArcProvisioning = true
+		case name == "cpugovernor" && arg != "":
+			policy.CpuGovernor = arg
+		case name == "crashspool" && !hasArg:
+			policy.ClearCrashSpool = true
+		default:
+			return fmt.Errorf("unknown quiesce action %q", a)
+		}
+	}
+	return nil
+}
+
 // Freeze returns a frozen configuration object.
 func (c *MutableConfig) Freeze() *Config {
 	return &Config{m: c}
@@ -775,6 +1437,43 @@ func (c *MutableConfig) addForceSkippedTests(fileName string) error {
 	return nil
 }
 
+// readTestList reads names or globs of tests from a test list file. Blank
+// lines and comments starting with "#" are ignored. An error is returned if the
+// file lists no tests, so that a broken file does not silently select no
+// tests.
+// Test list file example:
+//
+//	# Tests curated for the suite.
+//	meta.LocalPass
+//	example.* # all example tests
+func readTestList(fileName string) ([]string, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read test list file %v", fileName)
+	}
+	defer file.Close()
+
+	var pats []string
+	sc := bufio.NewScanner(file)
+	for lineNo := 1; sc.Scan(); lineNo++ {
+		pat := strings.TrimSpace(strings.SplitN(sc.Text(), "#", 2)[0])
+		if pat == "" {
+			continue
+		}
+		if _, err := testing.ValidateGlob(pat); err != nil {
+			return nil, errors.Wrapf(err, "test list file %v has syntax error at line %d", fileName, lineNo)
+		}
+		pats = append(pats, pat)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to read test list file %v", fileName)
+	}
+	if len(pats) == 0 {
+		return nil, errors.Errorf("test list file %v lists no tests", fileName)
+	}
+	return pats, nil
+}
+
 // BuildCfg returns a build.Config.
 func (c *Config) BuildCfg() *build.Config {
 	return &build.Config{
@@ -782,6 +1481,7 @@ func (c *Config) BuildCfg() *build.Config {
 		CheckDepsCachePath: filepath.Join(c.BuildOutDir(), checkDepsCacheFile),
 		InstallPortageDeps: c.InstallPortageDeps(),
 		TastWorkspace:      c.tastWorkspace(),
+		CacheDir:           filepath.Join(c.BuildOutDir(), buildCacheDir),
 	}
 }
 
@@ -816,6 +1516,16 @@ func (c *Config) BundleWorkspaces() []string {
 	return ws
 }
 
+// BundleSourceWorkspaces returns Go workspaces that may contain source code of
+// tests in the bundle named bundle, in the order of preference.
+func (c *Config) BundleSourceWorkspaces(bundle string) []string {
+	var ws []string
+	if b := getKnownBundleInfo(bundle); b != nil {
+		ws = append(ws, filepath.Join(c.TrunkDir(), b.workspace))
+	}
+	return append(ws, c.BundleWorkspaces()...)
+}
+
 // LocalBundleGlob returns a file path glob that matches local test bundle executables.
 func (c *Config) LocalBundleGlob() string {
 	return c.bundleGlob(c.LocalBundleDir())
@@ -833,6 +1543,7 @@ func (c *Config) Features(dut *frameworkprotocol.DUTFeatures,
 		CheckDeps: c.CheckTestDeps(),
 		Infra: &protocol.InfraFeatures{
 			Vars:             c.TestVars(),
+			ScopedVars:       c.ScopedTestVars(),
 			MaybeMissingVars: c.MaybeMissingVars(),
 			DUTLabConfig:     c.DUTLabConfig(),
 		},
@@ -850,6 +1561,12 @@ func (c *Config) bundleGlob(dir string) string {
 	return filepath.Join(dir, last)
 }
 
+// DefaultKeyFile returns the path to the default private SSH key within the
+// ChromeOS checkout at trunkDir.
+func DefaultKeyFile(trunkDir string) string {
+	return filepath.Join(trunkDir, defaultKeyFile)
+}
+
 // InChroot checks if the current session is running inside chroot.
 func InChroot() bool {
 	if _, err := os.Stat("/etc/cros_chroot_version"); err == nil {
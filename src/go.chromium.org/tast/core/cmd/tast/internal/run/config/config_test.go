@@ -14,10 +14,13 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/protobuf/proto"
 
 	"go.chromium.org/tast/core/cmd/tast/internal/run/config"
 	"go.chromium.org/tast/core/internal/debugger"
+	"go.chromium.org/tast/core/internal/logging"
 	"go.chromium.org/tast/core/internal/protocol"
+	"go.chromium.org/tast/core/internal/run/resultsjson"
 	"go.chromium.org/tast/core/testutil"
 )
 
@@ -49,6 +52,129 @@ func TestMutableConfigListDefaults(t *testing.T) {
 	}
 }
 
+func TestMutableConfigScopedVars(t *testing.T) {
+	cfg := config.NewMutableConfig(config.RunTestsMode, "", "")
+	flags := flag.NewFlagSet("", flag.ContinueOnError)
+	cfg.SetFlags(flags)
+
+	if err := flags.Parse([]string{"-var=a=1", "-var=ui.*:b=x:y=z", "-var=ui.Foo:b=2"}); err != nil {
+		t.Fatal("Parse failed: ", err)
+	}
+	if diff := cmp.Diff(cfg.TestVars, map[string]string{"a": "1"}); diff != "" {
+		t.Errorf("TestVars mismatch (-got +want):\n%s", diff)
+	}
+	if diff := cmp.Diff(cfg.ScopedTestVars, []string{"ui.*:b=x:y=z", "ui.Foo:b=2"}); diff != "" {
+		t.Errorf("ScopedTestVars mismatch (-got +want):\n%s", diff)
+	}
+
+	for _, v := range []string{"ui.*:b", "ui[0]:b=1", ":b=1", "ui.*:=1"} {
+		if err := flags.Parse([]string{"-var=" + v}); err == nil {
+			t.Errorf("Parse unexpectedly succeeded for -var=%s", v)
+		}
+	}
+}
+
+func TestMutableConfigTestLogLevels(t *testing.T) {
+	cfg := config.NewMutableConfig(config.RunTestsMode, "", "")
+	flags := flag.NewFlagSet("", flag.ContinueOnError)
+	cfg.SetFlags(flags)
+
+	if cfg.TestLogLevel != logging.LevelDebug || cfg.TestLogRateLimit != 0 {
+		t.Errorf("SetFlags set TestLogLevel to %v and TestLogRateLimit to %d; want debug level and no rate limit", cfg.TestLogLevel, cfg.TestLogRateLimit)
+	}
+
+	if err := flags.Parse([]string{"-testloglevel=info", "-testloglevel=ui.*:debug", "-testloglevel=ui.Chatty:info"}); err != nil {
+		t.Fatal("Parse failed: ", err)
+	}
+	if cfg.TestLogLevel != logging.LevelInfo {
+		t.Errorf("TestLogLevel = %v; want %v", cfg.TestLogLevel, logging.LevelInfo)
+	}
+	if diff := cmp.Diff(cfg.ScopedTestLogLevels, []string{"ui.*:debug", "ui.Chatty:info"}); diff != "" {
+		t.Errorf("ScopedTestLogLevels mismatch (-got +want):\n%s", diff)
+	}
+
+	for _, v := range []string{"verbose", "ui.*:verbose", ":info", "ui[0]:info"} {
+		if err := flags.Parse([]string{"-testloglevel=" + v}); err == nil {
+			t.Errorf("Parse unexpectedly succeeded for -testloglevel=%s", v)
+		}
+	}
+}
+
+func TestMutableConfigQuiesce(t *testing.T) {
+	cfg := config.NewMutableConfig(config.RunTestsMode, "", "")
+	flags := flag.NewFlagSet("", flag.ContinueOnError)
+	cfg.SetFlags(flags)
+
+	if got := cfg.Freeze().QuiescePolicy(); got != nil {
+		t.Errorf("QuiescePolicy() = %v; want nil", got)
+	}
+
+	if err := flags.Parse([]string{"-quiesce=updateengine,cpugovernor=performance", "-quiesce=crashspool"}); err != nil {
+		t.Fatal("Parse failed: ", err)
+	}
+	want := &protocol.QuiescePolicy{
+		StopUpdateEngine: true,
+		CpuGovernor:      "performance",
+		ClearCrashSpool:  true,
+	}
+	if got := cfg.Freeze().QuiescePolicy(); !proto.Equal(got, want) {
+		t.Errorf("QuiescePolicy() = %v; want %v", got, want)
+	}
+
+	for _, v := range []string{"foo", "cpugovernor", "cpugovernor=", "updateengine=1", "updateengine,"} {
+		if err := flags.Parse([]string{"-quiesce=" + v}); err == nil {
+			t.Errorf("Parse unexpectedly succeeded for -quiesce=%s", v)
+		}
+	}
+}
+
+func TestMutableConfigRunMetadata(t *testing.T) {
+	cfg := config.NewMutableConfig(config.RunTestsMode, "", "")
+	flags := flag.NewFlagSet("", flag.ContinueOnError)
+	cfg.SetFlags(flags)
+
+	if err := flags.Parse([]string{"-metadata=builder=amd64-generic-cq", "-metadata=cl=crrev.com/c/123", "-metadata=empty="}); err != nil {
+		t.Fatal("Parse failed: ", err)
+	}
+	want := map[string]string{
+		"builder": "amd64-generic-cq",
+		"cl":      "crrev.com/c/123",
+		"empty":   "",
+	}
+	if diff := cmp.Diff(cfg.Freeze().RunMetadata(), want); diff != "" {
+		t.Errorf("RunMetadata() mismatch (-got +want):\n%s", diff)
+	}
+
+	for _, v := range []string{"foo", "=bar"} {
+		if err := flags.Parse([]string{"-metadata=" + v}); err == nil {
+			t.Errorf("Parse unexpectedly succeeded for -metadata=%s", v)
+		}
+	}
+}
+
+func TestMutableConfigForcedFeatures(t *testing.T) {
+	cfg := config.NewMutableConfig(config.RunTestsMode, "", "")
+	flags := flag.NewFlagSet("", flag.ContinueOnError)
+	cfg.SetFlags(flags)
+
+	if err := flags.Parse([]string{"-forcefeature=arc=false", "-forcefeature=chrome=true"}); err != nil {
+		t.Fatal("Parse failed: ", err)
+	}
+	want := map[string]bool{
+		"arc":    false,
+		"chrome": true,
+	}
+	if diff := cmp.Diff(cfg.Freeze().ForcedFeatures(), want); diff != "" {
+		t.Errorf("ForcedFeatures() mismatch (-got +want):\n%s", diff)
+	}
+
+	for _, v := range []string{"arc", "=true", "arc=", "arc=maybe"} {
+		if err := flags.Parse([]string{"-forcefeature=" + v}); err == nil {
+			t.Errorf("Parse unexpectedly succeeded for -forcefeature=%s", v)
+		}
+	}
+}
+
 func TestMutableConfigDeriveDefaultsNoBuild(t *testing.T) {
 	cfg := config.NewMutableConfig(config.RunTestsMode, "", "")
 	flags := flag.NewFlagSet("", flag.ContinueOnError)
@@ -91,6 +217,19 @@ func TestMutableConfigDeriveDefaultsWaitUntilReadyTimeout(t *testing.T) {
 	}
 }
 
+func TestMutableConfigDeriveDefaultsInvalidVarsProvider(t *testing.T) {
+	cfg := config.NewMutableConfig(config.RunTestsMode, "", "")
+	flags := flag.NewFlagSet("", flag.ContinueOnError)
+	cfg.SetFlags(flags)
+	if err := flags.Parse([]string{"-build=false", "-varsprovider=foo:bar"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cfg.DeriveDefaults(); err == nil {
+		t.Error("DeriveDefaults unexpectedly succeeded with an unknown vars provider")
+	}
+}
+
 func TestMutableConfigDeriveDefaultsBuild(t *testing.T) {
 	const buildBundle = "cros"
 
@@ -117,6 +256,41 @@ func TestMutableConfigDeriveDefaultsBuild(t *testing.T) {
 	}
 }
 
+func TestMutableConfigDeriveDefaultsTargetLocal(t *testing.T) {
+	td := testutil.TempDir(t)
+	defer os.RemoveAll(td)
+
+	cfg := config.NewMutableConfig(config.RunTestsMode, "", td)
+	flags := flag.NewFlagSet("", flag.ContinueOnError)
+	cfg.SetFlags(flags)
+	if err := flags.Parse([]string{"-target=local", "-resultsdir=" + td}); err != nil {
+		t.Fatal("Parse failed: ", err)
+	}
+	if cfg.TargetMode != config.TargetLocal {
+		t.Fatalf("TargetMode = %v; want %v", cfg.TargetMode, config.TargetLocal)
+	}
+
+	if err := cfg.DeriveDefaults(); err != nil {
+		t.Fatal("DeriveDefaults failed: ", err)
+	}
+	buildDir := filepath.Join(cfg.BuildOutDir, "host")
+	for _, tc := range []struct {
+		name, got, want string
+	}{
+		{"LocalRunner", cfg.LocalRunner, filepath.Join(buildDir, "local_test_runner")},
+		{"LocalBundleDir", cfg.LocalBundleDir, filepath.Join(buildDir, "local_bundles")},
+		{"LocalDataDir", cfg.LocalDataDir, filepath.Join(cfg.BuildWorkspace, "src")},
+		{"LocalTempDir", cfg.LocalTempDir, ""},
+	} {
+		if tc.got != tc.want {
+			t.Errorf("%s = %q; want %q", tc.name, tc.got, tc.want)
+		}
+	}
+	if dir := filepath.Dir(cfg.LocalOutDir); dir != td {
+		t.Errorf("LocalOutDir %q is not under the results directory %q", cfg.LocalOutDir, td)
+	}
+}
+
 func TestMutableConfigDeriveDefaultsBuildNonStandardBundle(t *testing.T) {
 	const buildBundle = "nonstandardbundle"
 
@@ -319,6 +493,229 @@ func TestMutableConfigDeriveExtraAllowedBuckets(t *testing.T) {
 	}
 }
 
+func TestMutableConfigDeriveDefaultsSeed(t *testing.T) {
+	for _, tc := range []struct {
+		seed int64
+		want int64 // 0 means any non-zero value
+	}{
+		{0, 0},
+		{12345, 12345},
+	} {
+		cfg := config.NewMutableConfig(config.RunTestsMode, "", "")
+		flags := flag.NewFlagSet("", flag.ContinueOnError)
+		cfg.SetFlags(flags)
+
+		cfg.Build = false
+		cfg.Seed = tc.seed
+
+		if err := cfg.DeriveDefaults(); err != nil {
+			t.Fatal("DeriveDefaults failed: ", err)
+		}
+		if tc.want == 0 {
+			if cfg.Seed == 0 {
+				t.Error("cfg.Seed = 0 after DeriveDefaults; want non-zero")
+			}
+		} else if cfg.Seed != tc.want {
+			t.Errorf("cfg.Seed = %d; want %d", cfg.Seed, tc.want)
+		}
+	}
+}
+
+func TestMutableConfigDeriveDefaultsPrevResultsDir(t *testing.T) {
+	td := t.TempDir()
+	prevDir := filepath.Join(td, "results", "20240301-120000")
+	if err := os.MkdirAll(prevDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	newConfig := func() *config.MutableConfig {
+		cfg := config.NewMutableConfig(config.RunTestsMode, "", "")
+		flags := flag.NewFlagSet("", flag.ContinueOnError)
+		cfg.SetFlags(flags)
+		if err := flags.Parse([]string{"-order=failedfirst"}); err != nil {
+			t.Fatal("Failed to parse flags: ", err)
+		}
+		cfg.Build = false
+		cfg.TastDir = td
+		return cfg
+	}
+
+	if err := newConfig().DeriveDefaults(); err == nil {
+		t.Error("DeriveDefaults succeeded unexpectedly without previous results")
+	}
+
+	if err := os.Symlink(filepath.Base(prevDir), filepath.Join(td, "results", "latest")); err != nil {
+		t.Fatal(err)
+	}
+	cfg := newConfig()
+	if err := cfg.DeriveDefaults(); err != nil {
+		t.Fatal("DeriveDefaults failed: ", err)
+	}
+	if cfg.PrevResultsDir != prevDir {
+		t.Errorf("cfg.PrevResultsDir = %q; want %q", cfg.PrevResultsDir, prevDir)
+	}
+}
+
+func TestMutableConfigDeriveDefaultsDrone(t *testing.T) {
+	for _, tc := range []struct {
+		args []string
+		ok   bool
+	}{
+		{[]string{"-drone=user@drone:2222"}, true},
+		{[]string{"-drone=user@drone", "-droneworkdir=/var/tmp/tast"}, true},
+		{[]string{"-drone=user@@drone"}, false},
+		{[]string{"-drone=drone", "-droneworkdir=tast_drone"}, false},
+	} {
+		cfg := config.NewMutableConfig(config.RunTestsMode, "", "")
+		flags := flag.NewFlagSet("", flag.ContinueOnError)
+		cfg.SetFlags(flags)
+		if err := flags.Parse(tc.args); err != nil {
+			t.Fatal("Failed to parse flags: ", err)
+		}
+		cfg.Build = false
+		if err := cfg.DeriveDefaults(); err != nil && tc.ok {
+			t.Errorf("DeriveDefaults failed for %q: %v", tc.args, err)
+		} else if err == nil && !tc.ok {
+			t.Errorf("DeriveDefaults succeeded unexpectedly for %q", tc.args)
+		}
+	}
+}
+
+func TestMutableConfigDeriveDefaultsVia(t *testing.T) {
+	for _, tc := range []struct {
+		args []string
+		ok   bool
+	}{
+		{[]string{"-via=proxy", "-build=false"}, true},
+		{[]string{"-via=user@proxy:2222", "-build=false"}, true},
+		{[]string{"-via=[::1]:2222", "-build=false"}, true},
+		{[]string{"-via=proxy"}, false},
+		{[]string{"-via=proxy", "-build=false", "-proxycommand=nc %h %p"}, false},
+		{[]string{"-via=proxy", "-build=false", "-drone=drone"}, false},
+		{[]string{"-via=proxy", "-build=false", "-dutprovider=provider"}, false},
+		{[]string{"-via=proxy", "-build=false", "-target=local"}, false},
+		{[]string{"-via=proxy", "-build=false", "-order=failedfirst", "-prevresultsdir=/tmp"}, false},
+	} {
+		cfg := config.NewMutableConfig(config.RunTestsMode, "", "")
+		flags := flag.NewFlagSet("", flag.ContinueOnError)
+		cfg.SetFlags(flags)
+		if err := flags.Parse(tc.args); err != nil {
+			t.Fatal("Failed to parse flags: ", err)
+		}
+
+		if err := cfg.DeriveDefaults(); err != nil && tc.ok {
+			t.Errorf("DeriveDefaults failed for %q: %v", tc.args, err)
+		} else if err == nil && !tc.ok {
+			t.Errorf("DeriveDefaults succeeded unexpectedly for %q", tc.args)
+		} else if err == nil && cfg.ProxyCommand != "" {
+			t.Errorf("DeriveDefaults set ProxyCommand = %q for %q; want none", cfg.ProxyCommand, tc.args)
+		}
+	}
+}
+
+func TestMutableConfigDeriveDefaultsViaInvalid(t *testing.T) {
+	for _, via := range []string{
+		"@",
+		"user@",
+		"proxy:port",
+		"proxy other",
+		"proxy;rm",
+		"$(id)",
+		"`id`",
+		"proxy|nc",
+		"-oProxyCommand=id",
+		"-oLocalCommand",
+	} {
+		cfg := config.NewMutableConfig(config.RunTestsMode, "", "")
+		flags := flag.NewFlagSet("", flag.ContinueOnError)
+		cfg.SetFlags(flags)
+
+		cfg.Build = false
+		cfg.Via = via
+
+		if err := cfg.DeriveDefaults(); err == nil {
+			t.Errorf("DeriveDefaults unexpectedly succeeded for -via=%s", via)
+		}
+	}
+}
+
+func TestMutableConfigDeriveDefaultsHTMLReport(t *testing.T) {
+	for _, tc := range []struct {
+		args []string
+		want []string
+	}{
+		{nil, []string{"json", "junit", "owners", "text"}},
+		{[]string{"-htmlreport"}, []string{"json", "junit", "owners", "text", "html"}},
+		{[]string{"-htmlreport", "-reporters=html,json"}, []string{"html", "json"}},
+	} {
+		cfg := config.NewMutableConfig(config.RunTestsMode, "", "")
+		flags := flag.NewFlagSet("", flag.ContinueOnError)
+		cfg.SetFlags(flags)
+		if err := flags.Parse(tc.args); err != nil {
+			t.Fatal("Failed to parse flags: ", err)
+		}
+		cfg.Build = false
+		if err := cfg.DeriveDefaults(); err != nil {
+			t.Errorf("DeriveDefaults failed for %q: %v", tc.args, err)
+			continue
+		}
+		if diff := cmp.Diff(cfg.Reporters, tc.want); diff != "" {
+			t.Errorf("Reporters for %q mismatch (-got +want):\n%s", tc.args, diff)
+		}
+	}
+}
+
+func TestMutableConfigDeriveDefaultsDUTProvider(t *testing.T) {
+	for _, tc := range []struct {
+		args    []string
+		wantErr bool
+	}{
+		{[]string{"-dutprovider=file:/pool"}, false},
+		{[]string{"-dutprovider=file:/pool", "-dutreplacements=-1"}, true},
+		{[]string{"-dutprovider=file:/pool", "-target=local"}, true},
+	} {
+		cfg := config.NewMutableConfig(config.RunTestsMode, "", "")
+		flags := flag.NewFlagSet("", flag.ContinueOnError)
+		cfg.SetFlags(flags)
+		if err := flags.Parse(tc.args); err != nil {
+			t.Fatal("Failed to parse flags: ", err)
+		}
+		cfg.Build = false
+		if err := cfg.DeriveDefaults(); err != nil && !tc.wantErr {
+			t.Errorf("DeriveDefaults failed for %q: %v", tc.args, err)
+		} else if err == nil && tc.wantErr {
+			t.Errorf("DeriveDefaults unexpectedly succeeded for %q", tc.args)
+		}
+	}
+}
+
+func TestConfigWithResume(t *testing.T) {
+	cfg := config.NewMutableConfig(config.RunTestsMode, "", "")
+	cfg.Target = "dut1"
+	cfg.Patterns = []string{"pkg.*"}
+	cfg.ShardIndex = 1
+	cfg.TotalShards = 3
+	prev := []*resultsjson.Result{{Test: resultsjson.Test{Name: "pkg.A"}}}
+
+	c := cfg.Freeze().WithTarget("dut2").WithResume([]string{"pkg.B"}, prev)
+	if c.Target() != "dut2" {
+		t.Errorf("Target() = %q; want %q", c.Target(), "dut2")
+	}
+	if diff := cmp.Diff(c.Patterns(), []string{"pkg.B"}); diff != "" {
+		t.Errorf("Patterns mismatch (-got +want):\n%s", diff)
+	}
+	if !c.Resume() || len(c.PreviousResults()) != 1 {
+		t.Errorf("Resume() = %v, PreviousResults() = %v; want true and %v", c.Resume(), c.PreviousResults(), prev)
+	}
+	if c.ShardIndex() != 0 || c.TotalShards() != 1 {
+		t.Errorf("Got shard %d/%d; want 0/1", c.ShardIndex(), c.TotalShards())
+	}
+	// The original configuration should be unchanged.
+	if cfg.Target != "dut1" || cfg.Resume || cfg.TotalShards != 3 {
+		t.Errorf("Original configuration was modified: %+v", cfg)
+	}
+}
+
 func TestConfigLocalBundleGlob(t *testing.T) {
 	cfg := config.NewMutableConfig(config.RunTestsMode, "", "")
 	cfg.LocalBundleDir = "/mock/local_bundle_dir"
@@ -476,3 +873,56 @@ func TestConfigTestFilterFileFail(t *testing.T) {
 		t.Fatal("Failed to identify error in filter file ", filterFile)
 	}
 }
+
+func TestConfigTestList(t *testing.T) {
+	td := testutil.TempDir(t)
+	defer os.RemoveAll(td)
+
+	testList := filepath.Join(td, "testlist.txt")
+	excludeList := filepath.Join(td, "excludelist.txt")
+	if err := testutil.WriteFiles(td, map[string]string{
+		"testlist.txt": `
+	# Curated tests.
+	pkg.A
+	other.* # comment
+	`,
+		"excludelist.txt": "other.Flaky\n",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.NewMutableConfig(config.RunTestsMode, "", "")
+	flags := flag.NewFlagSet("", flag.ContinueOnError)
+	cfg.SetFlags(flags)
+	if err := flags.Parse([]string{"-testlist=" + testList, "-excludelist=" + excludeList}); err != nil {
+		t.Fatal("Failed to parse flags: ", err)
+	}
+	c := cfg.Freeze()
+	if diff := cmp.Diff(c.TestList(), []string{"pkg.A", "other.*"}); diff != "" {
+		t.Errorf("TestList mismatch (-got +want):\n%s", diff)
+	}
+	if diff := cmp.Diff(c.ExcludeList(), []string{"other.Flaky"}); diff != "" {
+		t.Errorf("ExcludeList mismatch (-got +want):\n%s", diff)
+	}
+}
+
+func TestConfigTestListFail(t *testing.T) {
+	td := testutil.TempDir(t)
+	defer os.RemoveAll(td)
+
+	for name, content := range map[string]string{
+		"expr.txt":  "(\"group:mainline\")\n",
+		"empty.txt": "# no tests\n",
+	} {
+		path := filepath.Join(td, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cfg := config.NewMutableConfig(config.RunTestsMode, "", "")
+		flags := flag.NewFlagSet("", flag.ContinueOnError)
+		cfg.SetFlags(flags)
+		if err := flags.Parse([]string{"-testlist=" + path}); err == nil {
+			t.Errorf("Parsing %s unexpectedly succeeded", name)
+		}
+	}
+}
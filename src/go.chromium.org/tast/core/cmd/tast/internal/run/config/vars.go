@@ -5,11 +5,16 @@
 package config
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"time"
 
 	"gopkg.in/yaml.v2"
+
+	"go.chromium.org/tast/core/cmd/tast/internal/run/varsprovider"
 )
 
 // findVarsFiles returns a list of paths to vars files under dir. The returned
@@ -80,3 +85,44 @@ func readAndMergeVarsFile(vars map[string]string, path string, mode mergeVarsMod
 	}
 	return nil
 }
+
+// varsProviderTimeout is the timeout for fetching variables from a vars
+// provider.
+const varsProviderTimeout = time.Minute
+
+// applyVarsProviders fetches variables from c.VarsProviders and merges them
+// into c.TestVars. Names of fetched variables are recorded in
+// c.SecretVarNames.
+func (c *MutableConfig) applyVarsProviders() error {
+	if len(c.VarsProviders) == 0 {
+		return nil
+	}
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		cacheDir = os.TempDir()
+	}
+	cacheDir = filepath.Join(cacheDir, "tast", "vars")
+
+	providerVars := make(map[string]string)
+	for _, spec := range c.VarsProviders {
+		p, err := varsprovider.New(spec, cacheDir, c.VarsProviderCacheTTL)
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), varsProviderTimeout)
+		vars, err := p.Vars(ctx)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("failed to get vars from %s: %v", p, err)
+		}
+		if err := mergeVars(providerVars, vars, errorOnDuplicate); err != nil {
+			return fmt.Errorf("failed to merge vars from %s: %v", p, err)
+		}
+	}
+	for k := range providerVars {
+		c.SecretVarNames = append(c.SecretVarNames, k)
+	}
+	sort.Strings(c.SecretVarNames)
+	mergeVars(c.TestVars, providerVars, skipOnDuplicate) // -var and -varsfile override providers
+	return nil
+}
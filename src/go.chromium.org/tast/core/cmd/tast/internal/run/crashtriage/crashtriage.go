@@ -0,0 +1,277 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package crashtriage attributes crash dumps collected from the DUT to tests
+// and summarizes them in test output directories.
+package crashtriage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"go.chromium.org/tast/core/cmd/tast/internal/symbolize/breakpad"
+	"go.chromium.org/tast/core/errors"
+	"go.chromium.org/tast/core/internal/crash"
+	"go.chromium.org/tast/core/internal/logging"
+	"go.chromium.org/tast/core/internal/run/resultsjson"
+)
+
+const (
+	// FileName is the name of the file written to test output directories
+	// to summarize crashes attributed to tests.
+	FileName = "crashes.json"
+
+	// symbolizedSuffix is appended to the name of a minidump file to
+	// name its symbolized stack trace written to a test output directory.
+	symbolizedSuffix = ".txt"
+
+	// attributionSlack is the duration after the end of a test during which
+	// crashes are still attributed to the test, since crash_reporter may
+	// take some time to write crash files.
+	attributionSlack = 10 * time.Second
+)
+
+// Class is a coarse classification of a crash.
+type Class string
+
+const (
+	// ClassChrome is a crash of Chrome or Lacros.
+	ClassChrome Class = "chrome"
+	// ClassKernel is a kernel crash or warning.
+	ClassKernel Class = "kernel"
+	// ClassEC is a crash of the embedded controller.
+	ClassEC Class = "ec"
+	// ClassOther is a crash of any other program.
+	ClassOther Class = "other"
+)
+
+// Crash describes a crash attributed to a test. A list of Crash is written
+// to FileName in the test output directory.
+type Crash struct {
+	// Name is the base name shared by files of the crash,
+	// e.g. "chrome.20240102.030405.12345.6789".
+	Name string `json:"name"`
+	// Executable is the name of the crashed executable, e.g. "chrome".
+	Executable string `json:"executable"`
+	// Class is the classification of the crash.
+	Class Class `json:"class"`
+	// Time is the time at which the earliest file of the crash was written.
+	Time time.Time `json:"time"`
+	// Files contains paths to files of the crash relative to the results
+	// directory.
+	Files []string `json:"files"`
+	// Symbolized is the path to the symbolized stack trace relative to the
+	// test output directory. It is empty if the crash was not symbolized.
+	Symbolized string `json:"symbolized,omitempty"`
+}
+
+// crashExts is a list of extensions of crash files, longest first so that
+// e.g. ".log.gz" is stripped instead of ".gz".
+var crashExts = []string{
+	crash.GPUStateExt,
+	crash.CompressedTxtExt,
+	crash.CompressedLogExt,
+	crash.ProclogExt,
+	crash.MetadataExt,
+	crash.BIOSExt,
+	crash.KCrashExt,
+	crash.MinidumpExt,
+	crash.CoreExt,
+	crash.InfoExt,
+	crash.LogExt,
+}
+
+// crashNameRegexp matches base names of crash files written by crash_reporter,
+// e.g. "chrome.20240102.030405.12345.6789".
+var crashNameRegexp = regexp.MustCompile(`^(.+?)\.\d{8}\.\d{6}(\.\d+)*$`)
+
+// splitCrashFileName splits a crash file name into the base name shared by
+// files of the same crash and the name of the crashed executable.
+func splitCrashFileName(fn string) (name, exec string) {
+	name = fn
+	for _, ext := range crashExts {
+		if strings.HasSuffix(fn, ext) {
+			name = strings.TrimSuffix(fn, ext)
+			break
+		}
+	}
+	if m := crashNameRegexp.FindStringSubmatch(name); m != nil {
+		return name, m[1]
+	}
+	return name, strings.SplitN(name, ".", 2)[0]
+}
+
+// classify returns the classification of a crash of exec consisting of
+// files.
+func classify(exec string, files []string) Class {
+	for _, f := range files {
+		if strings.HasSuffix(f, crash.KCrashExt) {
+			return ClassKernel
+		}
+	}
+	switch {
+	case strings.HasPrefix(exec, "kernel"):
+		return ClassKernel
+	case strings.HasPrefix(exec, "embedded-controller"):
+		return ClassEC
+	case strings.HasPrefix(exec, "chrome"), strings.HasPrefix(exec, "lacros"):
+		return ClassChrome
+	default:
+		return ClassOther
+	}
+}
+
+// readCrashes reads crash files in crashDir and groups them by crashes.
+// Returned crashes are sorted by time.
+func readCrashes(resDir, crashDir string) ([]*Crash, error) {
+	fis, err := os.ReadDir(crashDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*Crash)
+	for _, fi := range fis {
+		info, err := fi.Info()
+		if err != nil {
+			return nil, err
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		name, exec := splitCrashFileName(fi.Name())
+		c, ok := byName[name]
+		if !ok {
+			c = &Crash{Name: name, Executable: exec, Time: info.ModTime()}
+			byName[name] = c
+		}
+		rel, err := filepath.Rel(resDir, filepath.Join(crashDir, fi.Name()))
+		if err != nil {
+			return nil, err
+		}
+		c.Files = append(c.Files, rel)
+		if info.ModTime().Before(c.Time) {
+			c.Time = info.ModTime()
+		}
+	}
+
+	var crashes []*Crash
+	for _, c := range byName {
+		sort.Strings(c.Files)
+		c.Class = classify(c.Executable, c.Files)
+		crashes = append(crashes, c)
+	}
+	sort.Slice(crashes, func(i, j int) bool {
+		if !crashes[i].Time.Equal(crashes[j].Time) {
+			return crashes[i].Time.Before(crashes[j].Time)
+		}
+		return crashes[i].Name < crashes[j].Name
+	})
+	return crashes, nil
+}
+
+// attribute returns the test in results that was running at t, or nil if no
+// test was running.
+func attribute(results []*resultsjson.Result, t time.Time) *resultsjson.Result {
+	var found *resultsjson.Result
+	for _, r := range results {
+		if r.Start.IsZero() || r.OutDir == "" || r.Start.After(t) {
+			continue
+		}
+		// A test without an end time did not complete, typically because
+		// the DUT crashed in the middle of the test.
+		if !r.End.IsZero() && t.After(r.End.Add(attributionSlack)) {
+			continue
+		}
+		if found == nil || r.Start.After(found.Start) {
+			found = r
+		}
+	}
+	return found
+}
+
+// symbolize writes the symbolized stack trace of the minidump at path to
+// outPath using symbol files in symDir. It returns false if path is not a
+// minidump.
+func symbolize(path, symDir, outPath string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	isDump, err := breakpad.IsMinidump(f)
+	f.Close()
+	if err != nil || !isDump {
+		return false, err
+	}
+
+	var b bytes.Buffer
+	if _, err := breakpad.WalkMinidump(path, symDir, &b); err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(outPath, b.Bytes(), 0644)
+}
+
+// Triage attributes crashes whose files are saved in crashDir to tests in
+// results that were running when the crash files were written, and writes
+// FileName to output directories of tests having crashes. If symDir is not
+// empty, minidumps are symbolized with Breakpad symbol files in symDir and
+// stack traces are saved to the test output directories.
+func Triage(ctx context.Context, resDir, crashDir string, results []*resultsjson.Result, symDir string) error {
+	crashes, err := readCrashes(resDir, crashDir)
+	if err != nil {
+		return errors.Wrap(err, "failed to read crashes")
+	}
+
+	byTest := make(map[*resultsjson.Result][]*Crash)
+	var tests []*resultsjson.Result
+	for _, c := range crashes {
+		r := attribute(results, c.Time)
+		if r == nil {
+			logging.Debugf(ctx, "Crash %s at %v was not attributed to any test", c.Name, c.Time)
+			continue
+		}
+		if _, ok := byTest[r]; !ok {
+			if err := os.MkdirAll(r.OutDir, 0755); err != nil {
+				return err
+			}
+			tests = append(tests, r)
+		}
+		byTest[r] = append(byTest[r], c)
+
+		if symDir == "" {
+			continue
+		}
+		for _, f := range c.Files {
+			if !strings.HasSuffix(f, crash.MinidumpExt) {
+				continue
+			}
+			out := filepath.Base(f) + symbolizedSuffix
+			if ok, err := symbolize(filepath.Join(resDir, f), symDir, filepath.Join(r.OutDir, out)); err != nil {
+				logging.Infof(ctx, "Failed to symbolize %s: %v", f, err)
+			} else if ok {
+				c.Symbolized = out
+			}
+		}
+	}
+
+	for _, r := range tests {
+		b, err := json.MarshalIndent(byTest[r], "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(r.OutDir, FileName), b, 0644); err != nil {
+			return errors.Wrapf(err, "failed to write %s for %s", FileName, r.Name)
+		}
+		logging.Infof(ctx, "%s: %d crash(es) found", r.Name, len(byTest[r]))
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package crashtriage
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go.chromium.org/tast/core/internal/run/resultsjson"
+	"go.chromium.org/tast/core/testutil"
+)
+
+func TestSplitCrashFileName(t *testing.T) {
+	for _, tc := range []struct {
+		fn, name, exec string
+		class          Class
+	}{
+		{"chrome.20240102.030405.12345.6789.dmp", "chrome.20240102.030405.12345.6789", "chrome", ClassChrome},
+		{"lacros_chrome.20240102.030405.12345.6789.meta", "lacros_chrome.20240102.030405.12345.6789", "lacros_chrome", ClassChrome},
+		{"kernel.20240102.030405.12345.0.kcrash", "kernel.20240102.030405.12345.0", "kernel", ClassKernel},
+		{"kernel_warning.20240102.030405.12345.0.meta", "kernel_warning.20240102.030405.12345.0", "kernel_warning", ClassKernel},
+		{"embedded-controller.20240102.030405.12345.0.log", "embedded-controller.20240102.030405.12345.0", "embedded-controller", ClassEC},
+		{"shill.20240102.030405.12345.6789.i915_error_state.log.xz", "shill.20240102.030405.12345.6789", "shill", ClassOther},
+		{"update_engine.20240102.030405.12345.6789.log.gz", "update_engine.20240102.030405.12345.6789", "update_engine", ClassOther},
+		{"foo.bar.dmp", "foo.bar", "foo", ClassOther},
+	} {
+		name, exec := splitCrashFileName(tc.fn)
+		if name != tc.name || exec != tc.exec {
+			t.Errorf("splitCrashFileName(%q) = (%q, %q); want (%q, %q)", tc.fn, name, exec, tc.name, tc.exec)
+		}
+		if class := classify(exec, []string{tc.fn}); class != tc.class {
+			t.Errorf("classify(%q, %q) = %q; want %q", exec, tc.fn, class, tc.class)
+		}
+	}
+}
+
+func TestTriage(t *testing.T) {
+	resDir := testutil.TempDir(t)
+	defer os.RemoveAll(resDir)
+
+	base := time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC)
+	crashDir := filepath.Join(resDir, "crashes")
+	files := map[string]time.Duration{
+		// Crashed during test1.
+		"chrome.20240102.030105.1.2.dmp":  65 * time.Second,
+		"chrome.20240102.030105.1.2.meta": 66 * time.Second,
+		// Written shortly after test1 ended.
+		"kernel_warning.20240102.030205.1.0.kcrash": 125 * time.Second,
+		// Crashed between tests.
+		"shill.20240102.030300.1.2.dmp": 180 * time.Second,
+		// Crashed during test2, which did not complete.
+		"embedded-controller.20240102.030500.1.0.log": 300 * time.Second,
+		// Crashed before any test.
+		"debugd.20240102.025900.1.2.dmp": -60 * time.Second,
+	}
+	for fn, d := range files {
+		if err := testutil.WriteFiles(crashDir, map[string]string{fn: "data"}); err != nil {
+			t.Fatal(err)
+		}
+		mt := base.Add(d)
+		if err := os.Chtimes(filepath.Join(crashDir, fn), mt, mt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	test1 := &resultsjson.Result{
+		Test:   resultsjson.Test{Name: "pkg.Test1"},
+		Start:  base,
+		End:    base.Add(2 * time.Minute),
+		OutDir: filepath.Join(resDir, "tests/pkg.Test1"),
+	}
+	test2 := &resultsjson.Result{
+		Test:   resultsjson.Test{Name: "pkg.Test2"},
+		Start:  base.Add(4 * time.Minute),
+		OutDir: filepath.Join(resDir, "tests/pkg.Test2"),
+	}
+	test3 := &resultsjson.Result{
+		Test:       resultsjson.Test{Name: "pkg.Test3"},
+		OutDir:     filepath.Join(resDir, "tests/pkg.Test3"),
+		SkipReason: "missing deps",
+	}
+	if err := Triage(context.Background(), resDir, crashDir, []*resultsjson.Result{test1, test2, test3}, ""); err != nil {
+		t.Fatal("Triage failed: ", err)
+	}
+
+	for _, tc := range []struct {
+		r    *resultsjson.Result
+		want []*Crash
+	}{
+		{test1, []*Crash{
+			{
+				Name:       "chrome.20240102.030105.1.2",
+				Executable: "chrome",
+				Class:      ClassChrome,
+				Time:       base.Add(65 * time.Second),
+				Files:      []string{"crashes/chrome.20240102.030105.1.2.dmp", "crashes/chrome.20240102.030105.1.2.meta"},
+			},
+			{
+				Name:       "kernel_warning.20240102.030205.1.0",
+				Executable: "kernel_warning",
+				Class:      ClassKernel,
+				Time:       base.Add(125 * time.Second),
+				Files:      []string{"crashes/kernel_warning.20240102.030205.1.0.kcrash"},
+			},
+		}},
+		{test2, []*Crash{
+			{
+				Name:       "embedded-controller.20240102.030500.1.0",
+				Executable: "embedded-controller",
+				Class:      ClassEC,
+				Time:       base.Add(300 * time.Second),
+				Files:      []string{"crashes/embedded-controller.20240102.030500.1.0.log"},
+			},
+		}},
+	} {
+		b, err := os.ReadFile(filepath.Join(tc.r.OutDir, FileName))
+		if err != nil {
+			t.Errorf("Failed to read %s of %s: %v", FileName, tc.r.Name, err)
+			continue
+		}
+		var got []*Crash
+		if err := json.Unmarshal(b, &got); err != nil {
+			t.Errorf("Failed to parse %s of %s: %v", FileName, tc.r.Name, err)
+			continue
+		}
+		if diff := cmp.Diff(got, tc.want); diff != "" {
+			t.Errorf("%s of %s mismatch (-got +want):\n%s", FileName, tc.r.Name, diff)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(test3.OutDir, FileName)); !os.IsNotExist(err) {
+		t.Errorf("%s was written for skipped test %s", FileName, test3.Name)
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package dedup stores identical artifacts in a results directory only once.
+//
+// Tests often save identical large files, e.g. the same Chrome logs or
+// screenshots. Dedup moves the content of such files to BlobsDir, named after
+// its SHA-256 hash, and replaces every copy with a relative symlink to the
+// blob. The original paths and sizes of replaced files are recorded in
+// IndexFile under BlobsDir.
+package dedup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"go.chromium.org/tast/core/errors"
+)
+
+const (
+	// BlobsDir is the name of the subdirectory of a results directory where
+	// the content of duplicated artifacts is stored.
+	BlobsDir = "blobs"
+
+	// IndexFile is the name of the file under BlobsDir listing the replaced
+	// artifacts.
+	IndexFile = "index.json"
+
+	// DefaultMinSize is the default minimum size of artifacts to deduplicate.
+	// Smaller files are not worth replacing with symlinks.
+	DefaultMinSize = 64 * 1024
+)
+
+// Entry describes an artifact replaced with a symlink to a blob.
+type Entry struct {
+	// Path is the path of the artifact relative to the results directory.
+	Path string `json:"path"`
+	// Blob is the name of the blob under BlobsDir, i.e. the hex-encoded
+	// SHA-256 hash of the content.
+	Blob string `json:"blob"`
+	// Size is the size of the artifact in bytes.
+	Size int64 `json:"size"`
+}
+
+// Stats summarizes the result of Dedup.
+type Stats struct {
+	// Replaced is the number of artifacts replaced with symlinks.
+	Replaced int
+	// Blobs is the number of blobs newly stored under BlobsDir.
+	Blobs int
+	// SavedBytes is the number of bytes freed by Dedup.
+	SavedBytes int64
+}
+
+// Dedup deduplicates regular files of at least minSize bytes under resDir.
+// Files whose content is not shared with any other file are left untouched.
+// Running Dedup again on the same directory, e.g. after a resumed run, reuses
+// existing blobs and appends to the existing index.
+func Dedup(resDir string, minSize int64) (*Stats, error) {
+	blobsDir := filepath.Join(resDir, BlobsDir)
+
+	// Group candidates by size first so that only files possibly sharing
+	// content with others are hashed.
+	bySize := make(map[int64][]string)
+	if err := filepath.Walk(resDir, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if fi.IsDir() && p == blobsDir {
+			return filepath.SkipDir
+		}
+		if fi.Mode().IsRegular() && fi.Size() >= minSize {
+			bySize[fi.Size()] = append(bySize[fi.Size()], p)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	byHash := make(map[string][]string)
+	sizes := make(map[string]int64)
+	for size, paths := range bySize {
+		if len(paths) < 2 {
+			continue
+		}
+		for _, p := range paths {
+			h, err := hashFile(p)
+			if err != nil {
+				return nil, err
+			}
+			byHash[h] = append(byHash[h], p)
+			sizes[h] = size
+		}
+	}
+
+	var hashes []string
+	for h, paths := range byHash {
+		if len(paths) >= 2 {
+			hashes = append(hashes, h)
+		}
+	}
+	if len(hashes) == 0 {
+		return &Stats{}, nil
+	}
+	sort.Strings(hashes)
+
+	if err := os.MkdirAll(blobsDir, 0755); err != nil {
+		return nil, err
+	}
+	index, err := readIndex(blobsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &Stats{}
+	for _, h := range hashes {
+		paths := byHash[h]
+		sort.Strings(paths)
+		blob := filepath.Join(blobsDir, h)
+		size := sizes[h]
+
+		if _, err := os.Stat(blob); os.IsNotExist(err) {
+			// Keep the content of the first copy as the blob.
+			if err := os.Rename(paths[0], blob); err != nil {
+				return nil, err
+			}
+			stats.Blobs++
+			stats.SavedBytes -= size
+		} else if err != nil {
+			return nil, err
+		}
+
+		for _, p := range paths {
+			if err := replaceWithLink(p, blob); err != nil {
+				return nil, err
+			}
+			rel, err := filepath.Rel(resDir, p)
+			if err != nil {
+				return nil, err
+			}
+			index = append(index, &Entry{Path: filepath.ToSlash(rel), Blob: h, Size: size})
+			stats.Replaced++
+			stats.SavedBytes += size
+		}
+	}
+
+	if err := writeIndex(blobsDir, index); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// hashFile returns the hex-encoded SHA-256 hash of the content of the file at
+// p.
+func hashFile(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", errors.Wrapf(err, "failed to hash %s", p)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// replaceWithLink replaces the file at p, if any, with a relative symlink to
+// blob.
+func replaceWithLink(p, blob string) error {
+	target, err := filepath.Rel(filepath.Dir(p), blob)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.Symlink(target, p)
+}
+
+// readIndex reads IndexFile under blobsDir. It returns an empty index if the
+// file does not exist.
+func readIndex(blobsDir string) ([]*Entry, error) {
+	b, err := os.ReadFile(filepath.Join(blobsDir, IndexFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var index []*Entry
+	if err := json.Unmarshal(b, &index); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", IndexFile)
+	}
+	return index, nil
+}
+
+// writeIndex writes index to IndexFile under blobsDir.
+func writeIndex(blobsDir string, index []*Entry) error {
+	b, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(blobsDir, IndexFile), b, 0644)
+}
@@ -0,0 +1,79 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package dedup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go.chromium.org/tast/core/testutil"
+)
+
+func TestDedup(t *testing.T) {
+	resDir := t.TempDir()
+	const (
+		logs  = "identical chrome logs"
+		other = "different chrome logs"
+	)
+	files := map[string]string{
+		"tests/pkg.A/chrome.log":  logs,
+		"tests/pkg.B/chrome.log":  logs,
+		"tests/pkg.C/chrome.log":  other, // same size but different content
+		"tests/pkg.C/small.txt":   "x",
+		"tests/pkg.D/small.txt":   "x", // smaller than minSize
+		"system_logs/chrome.log":  logs,
+		"tests/pkg.D/unique.json": "unique content of large size",
+	}
+	if err := testutil.WriteFiles(resDir, files); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := Dedup(resDir, 10)
+	if err != nil {
+		t.Fatal("Dedup failed: ", err)
+	}
+	if want := (&Stats{Replaced: 3, Blobs: 1, SavedBytes: 2 * int64(len(logs))}); *stats != *want {
+		t.Errorf("Dedup returned %+v; want %+v", stats, want)
+	}
+
+	// Contents are kept as seen through symlinks.
+	for p, want := range files {
+		if b, err := os.ReadFile(filepath.Join(resDir, p)); err != nil {
+			t.Errorf("Failed to read %s: %v", p, err)
+		} else if got := string(b); got != want {
+			t.Errorf("%s has content %q; want %q", p, got, want)
+		}
+	}
+
+	for _, p := range []string{"tests/pkg.A/chrome.log", "system_logs/chrome.log"} {
+		fi, err := os.Lstat(filepath.Join(resDir, p))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fi.Mode()&os.ModeSymlink == 0 {
+			t.Errorf("%s is not a symlink", p)
+		}
+	}
+	if fi, err := os.Lstat(filepath.Join(resDir, "tests/pkg.C/chrome.log")); err != nil {
+		t.Fatal(err)
+	} else if !fi.Mode().IsRegular() {
+		t.Error("tests/pkg.C/chrome.log is not a regular file")
+	}
+
+	index, err := readIndex(filepath.Join(resDir, BlobsDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, e := range index {
+		paths = append(paths, e.Path)
+	}
+	if diff := cmp.Diff(paths, []string{"system_logs/chrome.log", "tests/pkg.A/chrome.log", "tests/pkg.B/chrome.log"}); diff != "" {
+		t.Errorf("Index paths mismatch (-got +want):\n%s", diff)
+	}
+}
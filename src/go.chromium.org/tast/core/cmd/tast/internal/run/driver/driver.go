@@ -28,6 +28,7 @@ import (
 	"go.chromium.org/tast/core/internal/minidriver/target"
 	"go.chromium.org/tast/core/internal/protocol"
 	"go.chromium.org/tast/core/internal/run/genericexec"
+	"go.chromium.org/tast/core/internal/runner"
 )
 
 const (
@@ -55,6 +56,7 @@ type Driver struct {
 	role             string
 	servoHostInfo    *servo.HostInfo
 	remoteDevservers []string
+	dr               *drone // nil until remote tests run on -drone
 }
 
 // New establishes a new connection to the target device and returns a Driver.
@@ -89,11 +91,23 @@ func New(ctx context.Context, cfg *config.Config, rawTarget, role string, remote
 			role:      role,
 		}, nil
 	}
+	// With -target=local, local executables run on the host without SSH.
+	if cfg.TargetMode() == config.TargetLocal {
+		return &Driver{
+			cfg:              cfg,
+			rawTarget:        rawTarget,
+			role:             role,
+			remoteDevservers: remoteDevservers,
+		}, nil
+	}
 
 	resolvedTarget, resolvedProxyCommand := resolveSSHConfig(ctx, rawTarget)
 	proxyCommand := cfg.ProtoSSHConfig().GetProxyCommand()
 	if proxyCommand == "" {
 		proxyCommand = resolvedProxyCommand
+		if cmd := controlProxyCommand(ctx, rawTarget, cfg.ProtoSSHConfig().GetKeyFile()); cmd != "" {
+			proxyCommand = cmd
+		}
 	}
 	var debuggerPorts []int
 	for _, dt := range []debugger.DebugTarget{debugger.LocalTestRunner, debugger.LocalBundle} {
@@ -140,6 +154,12 @@ func (d *Driver) Duplicate(ctx context.Context) (*Driver, error) {
 
 // Close closes the current connection to the target device.
 func (d *Driver) Close(ctx context.Context) error {
+	if d.dr != nil {
+		if err := d.dr.close(ctx); err != nil {
+			logging.Infof(ctx, "Failed to close connection to drone: %v", err)
+		}
+		d.dr = nil
+	}
 	// Check we have the connection to close.
 	if d.cc == nil {
 		return nil
@@ -210,22 +230,53 @@ func (d *Driver) localRunnerClient() *runnerclient.Client {
 	if !config.ShouldConnect(d.cfg.Target()) {
 		return nil
 	}
+	if d.cfg.TargetMode() == config.TargetLocal {
+		cmd := genericexec.CommandExec(d.cfg.LocalRunner())
+		return runnerclient.New(cmd, d.LocalRunnerInitParams(), d.cfg.MsgTimeout(), 1)
+	}
 	cmd := bundleclient.LocalCommand(d.cfg.LocalRunner(), d.cfg.Proxy() == config.ProxyEnv, d.cc)
 
-	params := &protocol.RunnerInitParams{BundleGlob: d.cfg.LocalBundleGlob()}
-	return runnerclient.New(cmd, params, d.cfg.MsgTimeout(), 1)
+	params := d.LocalRunnerInitParams()
+	var cl *runnerclient.Client
+	if d.cfg.LocalRunnerDaemon() {
+		daemon := &runnerclient.DaemonParams{
+			SocketPath: runner.DefaultDaemonSocketPath,
+			Dial:       d.SSHConn().Dial,
+		}
+		cl = runnerclient.NewDaemon(cmd, daemon, params, d.cfg.MsgTimeout(), 1)
+	} else {
+		cl = runnerclient.New(cmd, params, d.cfg.MsgTimeout(), 1)
+	}
+	if d.cfg.CompressControl() {
+		cl.EnableCompression()
+	}
+	return cl
 }
 
 func (d *Driver) remoteRunnerClient() *runnerclient.Client {
 	cmd := genericexec.CommandExec(d.cfg.RemoteRunner())
-	params := &protocol.RunnerInitParams{BundleGlob: d.cfg.RemoteBundleGlob()}
-	return runnerclient.New(cmd, params, d.cfg.MsgTimeout(), 0)
+	return runnerclient.New(cmd, d.RemoteRunnerInitParams(), d.cfg.MsgTimeout(), 0)
+}
+
+// LocalRunnerInitParams returns parameters to initialize the local test runner.
+func (d *Driver) LocalRunnerInitParams() *protocol.RunnerInitParams {
+	return &protocol.RunnerInitParams{
+		BundleGlob:    d.cfg.LocalBundleGlob(),
+		PowerPolicy:   d.cfg.PowerPolicy(),
+		QuiescePolicy: d.cfg.QuiescePolicy(),
+	}
+}
+
+// RemoteRunnerInitParams returns parameters to initialize the remote test
+// runner.
+func (d *Driver) RemoteRunnerInitParams() *protocol.RunnerInitParams {
+	return &protocol.RunnerInitParams{BundleGlob: d.cfg.RemoteBundleGlob()}
 }
 
 func (d *Driver) remoteBundleClient(bundle string) *bundleclient.Client {
 	bundlePath := filepath.Join(d.cfg.RemoteBundleDir(), bundle)
 	cmd := genericexec.CommandExec(bundlePath)
-	return bundleclient.New(cmd, d.cfg.MsgTimeout(), bundlePath)
+	return bundleclient.New(cmd, genericexec.CommandExec("pkill"), d.cfg.MsgTimeout(), bundlePath)
 }
 
 func resolveSSHConfig(ctx context.Context, target string) (alternateTarget, proxyCommand string) {
@@ -241,6 +292,27 @@ func resolveSSHConfig(ctx context.Context, target string) (alternateTarget, prox
 	return alternateTarget, proxyCommand
 }
 
+// controlProxyCommand returns a proxy command to connect to target through a
+// multiplexed connection if ControlMaster or ControlPath is set for target in
+// SSH configuration files. It returns an empty string otherwise.
+func controlProxyCommand(ctx context.Context, target, keyFile string) string {
+	ctl, err := sshconfig.ResolveControl(target)
+	if err != nil {
+		logging.Infof(ctx, "Error in reading SSH configuaration files: %v", err)
+		return ""
+	}
+	cmd := ctl.ProxyCommand(keyFile)
+	if cmd == "" {
+		return ""
+	}
+	if ctl.Alive() {
+		logging.Infof(ctx, "Reusing SSH master connection at %s", ctl.Path)
+	} else {
+		logging.Infof(ctx, "Connecting through a new SSH master connection according to SSH configuration files")
+	}
+	return cmd
+}
+
 // ConnCacheForTesting returns target.ConnCache the driver owns for testing.
 func (d *Driver) ConnCacheForTesting() *target.ConnCache {
 	return d.cc
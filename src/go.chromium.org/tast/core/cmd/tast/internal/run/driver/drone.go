@@ -0,0 +1,164 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package driver
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"go.chromium.org/tast/core/cmd/tast/internal/run/config"
+	"go.chromium.org/tast/core/errors"
+	"go.chromium.org/tast/core/internal/linuxssh"
+	"go.chromium.org/tast/core/internal/logging"
+	"go.chromium.org/tast/core/internal/minidriver/bundleclient"
+	"go.chromium.org/tast/core/internal/protocol"
+	"go.chromium.org/tast/core/internal/run/genericexec"
+	"go.chromium.org/tast/core/ssh"
+)
+
+// Subdirectories of -droneworkdir.
+const (
+	droneBundleSubdir = "bundles"
+	droneDataSubdir   = "data"
+	droneRunsSubdir   = "runs"
+)
+
+// drone is a jump host remote test bundles run on instead of the host running
+// the tast command. Remote test bundles are pushed to the drone and executed
+// over SSH, so that tests transferring much data with the DUT run close to it.
+type drone struct {
+	conn    *ssh.Conn
+	workDir string
+	runDir  string // directory for outputs of the current run
+	pushed  map[string]bool
+}
+
+// connectDrone connects to the drone specified by -drone.
+func connectDrone(ctx context.Context, cfg *config.Config) (*drone, error) {
+	var o ssh.Options
+	if err := ssh.ParseTarget(cfg.Drone(), &o); err != nil {
+		return nil, err
+	}
+	o.KeyFile = cfg.KeyFile()
+	o.KeyDir = cfg.KeyDir()
+	o.ConnectRetries = cfg.SSHRetries()
+	o.WarnFunc = func(msg string) { logging.Info(ctx, msg) }
+	conn, err := ssh.New(ctx, &o)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to drone %s", cfg.Drone())
+	}
+	// Remote tests connect to the DUT from the drone. Rather than copying the
+	// SSH key there, it is served to them by an agent forwarded from here.
+	keyring, err := ssh.NewKeyring(cfg.KeyFile())
+	if err != nil {
+		conn.Close(ctx)
+		return nil, err
+	}
+	if err := conn.ForwardAgent(keyring); err != nil {
+		conn.Close(ctx)
+		return nil, errors.Wrap(err, "failed to forward SSH agent to drone")
+	}
+	workDir := cfg.DroneWorkDir()
+	return &drone{
+		conn:    conn,
+		workDir: workDir,
+		runDir:  path.Join(workDir, droneRunsSubdir, filepath.Base(cfg.ResDir())+"."+time.Now().Format("150405.000000000")),
+		pushed:  make(map[string]bool),
+	}, nil
+}
+
+// drone returns a connection to the drone, connecting to it and pushing
+// bundle with its data files if needed.
+func (d *Driver) drone(ctx context.Context, bundle string) (*drone, error) {
+	if d.dr == nil {
+		dr, err := connectDrone(ctx, d.cfg)
+		if err != nil {
+			return nil, err
+		}
+		d.dr = dr
+	}
+	if err := d.dr.push(ctx, d.cfg, bundle); err != nil {
+		return nil, errors.Wrapf(err, "failed to push %s to drone", bundle)
+	}
+	return d.dr, nil
+}
+
+// push copies bundle and data files needed to run it to the drone. Files
+// already on the drone from earlier runs are not sent again.
+func (dr *drone) push(ctx context.Context, cfg *config.Config, bundle string) error {
+	if dr.pushed[bundle] {
+		return nil
+	}
+	files := map[string]string{
+		filepath.Join(cfg.RemoteBundleDir(), bundle): path.Join(dr.workDir, droneBundleSubdir, bundle),
+	}
+	if len(dr.pushed) == 0 {
+		if _, err := os.Stat(cfg.RemoteDataDir()); err == nil {
+			files[cfg.RemoteDataDir()] = path.Join(dr.workDir, droneDataSubdir)
+		}
+		if err := dr.conn.CommandContext(ctx, "mkdir", "-p", dr.workDir, dr.runDir).Run(); err != nil {
+			return err
+		}
+	}
+	logging.Infof(ctx, "Pushing %s to drone", bundle)
+	bytes, err := linuxssh.PutFiles(ctx, dr.conn, files, linuxssh.DereferenceSymlinks)
+	if err != nil {
+		return err
+	}
+	logging.Debugf(ctx, "Pushed %d bytes to drone", bytes)
+	dr.pushed[bundle] = true
+	return nil
+}
+
+// bundleClient returns a client to run bundle on the drone. Arguments and
+// results are relayed over SSH.
+func (dr *drone) bundleClient(bundle string, msgTimeout time.Duration) *bundleclient.Client {
+	bundlePath := path.Join(dr.workDir, droneBundleSubdir, bundle)
+	cmd := genericexec.CommandSSH(dr.conn, bundlePath).WithRetry(genericexec.DefaultRetryPolicy)
+	pkillCmd := genericexec.CommandSSH(dr.conn, "pkill").WithRetry(genericexec.DefaultRetryPolicy)
+	return bundleclient.New(cmd, pkillCmd, msgTimeout, bundlePath)
+}
+
+// updateConfigs updates bcfg and rcfg to use paths on the drone.
+func (dr *drone) updateConfigs(bcfg *protocol.BundleConfig, rcfg *protocol.RunConfig) {
+	rcfg.Dirs = &protocol.RunDirectories{
+		DataDir: path.Join(dr.workDir, droneDataSubdir),
+		OutDir:  path.Join(dr.runDir, "out"),
+		TempDir: path.Join(dr.runDir, "tmp"),
+	}
+	sshConfigs := []*protocol.SSHConfig{bcfg.GetPrimaryTarget().GetDutConfig().GetSshConfig()}
+	for _, dut := range bcfg.GetCompanionDuts() {
+		sshConfigs = append(sshConfigs, dut.GetSshConfig())
+	}
+	for _, c := range sshConfigs {
+		if c == nil {
+			continue
+		}
+		// Keys on the host are not available on the drone; the forwarded
+		// agent is used instead.
+		c.KeyFile = ""
+		c.KeyDir = ""
+	}
+}
+
+// pull returns a function to move a test output file or directory at src on
+// the drone to dst on the host.
+func (dr *drone) pull(ctx context.Context) func(src, dst string) error {
+	return func(src, dst string) error {
+		return linuxssh.GetAndDeleteFile(ctx, dr.conn, src, dst, linuxssh.PreserveSymlinks)
+	}
+}
+
+// close removes outputs of the current run from the drone and closes the
+// connection. Bundles and data files are kept to be reused by later runs.
+func (dr *drone) close(ctx context.Context) error {
+	if err := dr.conn.CommandContext(ctx, "rm", "-rf", "--", dr.runDir).Run(); err != nil {
+		logging.Infof(ctx, "Failed to clean up %s on drone: %v", dr.runDir, err)
+	}
+	return dr.conn.Close(ctx)
+}
@@ -6,12 +6,15 @@ package driver
 
 import (
 	"context"
+	"sort"
 
 	"go.chromium.org/tast/core/cmd/tast/internal/run/config"
 	"go.chromium.org/tast/core/errors"
 	"go.chromium.org/tast/core/internal/logging"
 	"go.chromium.org/tast/core/internal/protocol"
 	"go.chromium.org/tast/core/internal/timing"
+
+	frameworkprotocol "go.chromium.org/tast/core/framework/protocol"
 )
 
 // GetDUTInfo retrieves various DUT information needed for test execution.
@@ -37,5 +40,52 @@ func (d *Driver) GetDUTInfo(ctx context.Context) (*protocol.DUTInfo, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to retrieve DUT info")
 	}
-	return res.GetDutInfo(), nil
+	dutInfo := res.GetDutInfo()
+	if needFeatures {
+		if err := forceFeatures(ctx, dutInfo.GetFeatures().GetSoftware(), d.cfg.ForcedFeatures()); err != nil {
+			return nil, err
+		}
+	}
+	return dutInfo, nil
+}
+
+// forceFeatures overrides the availability of software features in sw as
+// specified by forced, which maps feature names to whether they are
+// available.
+func forceFeatures(ctx context.Context, sw *frameworkprotocol.SoftwareFeatures, forced map[string]bool) error {
+	if len(forced) == 0 {
+		return nil
+	}
+	if sw == nil {
+		return errors.New("cannot force software features: DUT did not report them")
+	}
+
+	known := make(map[string]bool)
+	for _, f := range sw.GetAvailable() {
+		known[f] = true
+	}
+	for _, f := range sw.GetUnavailable() {
+		known[f] = false
+	}
+	for name, avail := range forced {
+		if _, ok := known[name]; !ok {
+			return errors.Errorf("cannot force unknown software feature %q", name)
+		}
+		if known[name] != avail {
+			logging.Infof(ctx, "Forcing software feature %s to be available=%v", name, avail)
+		}
+		known[name] = avail
+	}
+
+	sw.Available, sw.Unavailable = nil, nil
+	for name, avail := range known {
+		if avail {
+			sw.Available = append(sw.Available, name)
+		} else {
+			sw.Unavailable = append(sw.Unavailable, name)
+		}
+	}
+	sort.Strings(sw.Available)
+	sort.Strings(sw.Unavailable)
+	return nil
 }
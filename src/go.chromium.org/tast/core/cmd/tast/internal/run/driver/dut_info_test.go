@@ -82,6 +82,68 @@ func TestDriver_GetDUTInfo(t *testing.T) {
 	}
 }
 
+func TestDriver_GetDUTInfo_ForcedFeatures(t *testing.T) {
+	env := runtest.SetUp(t, runtest.WithGetDUTInfo(func(req *protocol.GetDUTInfoRequest) (*protocol.GetDUTInfoResponse, error) {
+		return &protocol.GetDUTInfoResponse{DutInfo: &protocol.DUTInfo{
+			Features: &frameworkprotocol.DUTFeatures{
+				Software: &frameworkprotocol.SoftwareFeatures{
+					Available:   []string{"arc", "chrome"},
+					Unavailable: []string{"lacros"},
+				},
+			},
+		}}, nil
+	}))
+	ctx := env.Context()
+
+	for _, tc := range []struct {
+		name    string
+		forced  map[string]bool
+		want    *frameworkprotocol.SoftwareFeatures
+		wantErr bool
+	}{
+		{
+			name:   "override",
+			forced: map[string]bool{"arc": false, "lacros": true, "chrome": true},
+			want: &frameworkprotocol.SoftwareFeatures{
+				Available:   []string{"chrome", "lacros"},
+				Unavailable: []string{"arc"},
+			},
+		},
+		{
+			name:    "unknown",
+			forced:  map[string]bool{"foo": true},
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := env.Config(func(cfg *config.MutableConfig) {
+				cfg.CheckTestDeps = true
+				cfg.ForcedFeatures = tc.forced
+			})
+
+			drv, err := driver.New(ctx, cfg, cfg.Target(), "", nil)
+			if err != nil {
+				t.Fatalf("driver.New failed: %v", err)
+			}
+			defer drv.Close(ctx)
+
+			got, err := drv.GetDUTInfo(ctx)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("GetDUTInfo unexpectedly succeeded")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetDUTInfo failed: %v", err)
+			}
+			if diff := cmp.Diff(got.GetFeatures().GetSoftware(), tc.want, cmp.Comparer(proto.Equal)); diff != "" {
+				t.Errorf("Software features mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestDriver_GetDUTInfo_NoCheckTestDepsForRun(t *testing.T) {
 	env := runtest.SetUp(t, runtest.WithGetDUTInfo(func(req *protocol.GetDUTInfoRequest) (*protocol.GetDUTInfoResponse, error) {
 		if req.GetFeatures() {
@@ -6,6 +6,10 @@
 package drivercore
 
 import (
+	"sort"
+	"strings"
+
+	"go.chromium.org/tast/core/errors"
 	"go.chromium.org/tast/core/internal/protocol"
 )
 
@@ -14,3 +18,24 @@ type BundleEntity struct {
 	Bundle   string
 	Resolved *protocol.ResolvedEntity
 }
+
+// CheckDuplicateTests returns an error if tests contain multiple tests with
+// the same name. Such tests would otherwise shadow each other silently.
+func CheckDuplicateTests(tests []*BundleEntity) error {
+	bundles := make(map[string][]string)
+	for _, t := range tests {
+		name := t.Resolved.GetEntity().GetName()
+		bundles[name] = append(bundles[name], t.Bundle)
+	}
+	var dups []string
+	for name, bs := range bundles {
+		if len(bs) > 1 {
+			dups = append(dups, name+" (in bundles "+strings.Join(bs, ", ")+")")
+		}
+	}
+	if len(dups) == 0 {
+		return nil
+	}
+	sort.Strings(dups)
+	return errors.Errorf("tests registered in multiple bundles: %s; set Namespaced in the bundle's Delegate to avoid collisions", strings.Join(dups, "; "))
+}
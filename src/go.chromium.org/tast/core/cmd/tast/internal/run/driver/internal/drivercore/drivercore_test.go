@@ -0,0 +1,40 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package drivercore_test
+
+import (
+	"strings"
+	"testing"
+
+	"go.chromium.org/tast/core/cmd/tast/internal/run/driver/internal/drivercore"
+	"go.chromium.org/tast/core/internal/protocol"
+)
+
+func newBundleEntity(bundle, name string) *drivercore.BundleEntity {
+	return &drivercore.BundleEntity{
+		Bundle:   bundle,
+		Resolved: &protocol.ResolvedEntity{Entity: &protocol.Entity{Name: name}},
+	}
+}
+
+func TestCheckDuplicateTests(t *testing.T) {
+	tests := []*drivercore.BundleEntity{
+		newBundleEntity("cros", "pkg.Foo"),
+		newBundleEntity("cros", "pkg.Bar"),
+		newBundleEntity("partner", "partner/pkg.Foo"),
+	}
+	if err := drivercore.CheckDuplicateTests(tests); err != nil {
+		t.Error("CheckDuplicateTests failed for unique tests: ", err)
+	}
+
+	tests = append(tests, newBundleEntity("private", "pkg.Foo"))
+	err := drivercore.CheckDuplicateTests(tests)
+	if err == nil {
+		t.Fatal("CheckDuplicateTests succeeded for duplicate tests")
+	}
+	if want := "pkg.Foo (in bundles cros, private)"; !strings.Contains(err.Error(), want) {
+		t.Errorf("CheckDuplicateTests returned %q; want it to contain %q", err.Error(), want)
+	}
+}
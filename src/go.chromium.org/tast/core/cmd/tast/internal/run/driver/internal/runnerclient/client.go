@@ -32,6 +32,7 @@ type RunTestsOutput interface {
 	EntityLog(ctx context.Context, ev *protocol.EntityLogEvent) error
 	EntityError(ctx context.Context, ev *protocol.EntityErrorEvent) error
 	EntityEnd(ctx context.Context, ev *protocol.EntityEndEvent) error
+	EntityQuarantine(ctx context.Context, ev *protocol.EntityQuarantineEvent) error
 	RunLog(ctx context.Context, ev *protocol.RunLogEvent) error
 
 	// RunEnd is called exactly once at the end of an overall test execution.
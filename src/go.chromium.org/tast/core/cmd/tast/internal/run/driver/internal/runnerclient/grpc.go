@@ -5,8 +5,10 @@
 package runnerclient
 
 import (
+	"bytes"
 	"context"
 	"io"
+	"net"
 	"os"
 	"path/filepath"
 	"sort"
@@ -27,9 +29,19 @@ import (
 // Client is a GRPC-protocol client to test_runner.
 type Client struct {
 	cmd        genericexec.Cmd
+	daemon     *DaemonParams
 	params     *protocol.RunnerInitParams
 	msgTimeout time.Duration
 	hops       int
+	compress   bool
+}
+
+// DaemonParams specifies how to reach a test runner running in daemon mode.
+type DaemonParams struct {
+	// SocketPath is the path of the Unix domain socket the daemon listens on.
+	SocketPath string
+	// Dial connects to addr on network from the host running the daemon.
+	Dial func(network, addr string) (net.Conn, error)
 }
 
 // New creates a new Client.
@@ -42,9 +54,27 @@ func New(cmd genericexec.Cmd, params *protocol.RunnerInitParams, msgTimeout time
 	}
 }
 
+// NewDaemon creates a new Client that talks to a test runner daemon described
+// by daemon. The daemon is started with cmd if it is not running yet. If the
+// daemon is unavailable, the client falls back to running cmd for each
+// request.
+func NewDaemon(cmd genericexec.Cmd, daemon *DaemonParams, params *protocol.RunnerInitParams, msgTimeout time.Duration, hops int) *Client {
+	c := New(cmd, params, msgTimeout, hops)
+	c.daemon = daemon
+	return c
+}
+
+// EnableCompression makes the client ask the test runner to compress messages
+// sent to the client with zstd. Test runners not supporting compression
+// ignore the request.
+func (c *Client) EnableCompression() {
+	c.compress = true
+}
+
 // rpcConn represents a gRPC connection to a test runner.
 type rpcConn struct {
-	proc genericexec.Process
+	proc genericexec.Process // nil if connected to a daemon
+	raw  net.Conn            // non-nil if connected to a daemon
 	conn *rpc.GenericClient
 }
 
@@ -54,6 +84,12 @@ func (c *rpcConn) Close(ctx context.Context) error {
 	if err := c.conn.Close(); err != nil && firstErr == nil {
 		firstErr = err
 	}
+	if c.proc == nil {
+		if err := c.raw.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		return firstErr
+	}
 	if err := c.proc.Stdin().Close(); err != nil && firstErr == nil {
 		firstErr = err
 	}
@@ -70,6 +106,18 @@ func (c *rpcConn) Conn() *grpc.ClientConn {
 
 // dial connects to the test runner and returned an established gRPC connection.
 func (c *Client) dial(ctx context.Context, req *protocol.HandshakeRequest) (_ *rpcConn, retErr error) {
+	if c.compress {
+		req.AcceptZstdCompression = true
+	}
+
+	if c.daemon != nil {
+		conn, err := c.dialDaemon(ctx, req)
+		if err == nil {
+			return conn, nil
+		}
+		logging.Infof(ctx, "Failed to connect to test runner daemon; starting a new test runner instead: %v", err)
+	}
+
 	proc, err := c.cmd.Interact(ctx, []string{"-rpc"})
 	if err != nil {
 		return nil, err
@@ -96,6 +144,42 @@ func (c *Client) dial(ctx context.Context, req *protocol.HandshakeRequest) (_ *r
 	}, nil
 }
 
+// dialDaemon connects to the test runner daemon, starting it if needed.
+func (c *Client) dialDaemon(ctx context.Context, req *protocol.HandshakeRequest) (*rpcConn, error) {
+	var lastErr error
+	// The first attempt may fail if the daemon is not running or it exits
+	// because the test runner was updated. Start the daemon and retry once.
+	for i := 0; i < 2; i++ {
+		if i > 0 {
+			if err := c.startDaemon(ctx); err != nil {
+				return nil, err
+			}
+		}
+		raw, err := c.daemon.Dial("unix", c.daemon.SocketPath)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		conn, err := rpc.NewClient(ctx, raw, raw, req)
+		if err != nil {
+			raw.Close()
+			lastErr = err
+			continue
+		}
+		return &rpcConn{raw: raw, conn: conn}, nil
+	}
+	return nil, lastErr
+}
+
+// startDaemon starts the test runner daemon if it is not running yet.
+func (c *Client) startDaemon(ctx context.Context) error {
+	var stderr bytes.Buffer
+	if err := c.cmd.Run(ctx, []string{"-daemon", "-daemonsocket=" + c.daemon.SocketPath}, nil, io.Discard, &stderr); err != nil {
+		return errors.Wrapf(err, "failed to start test runner daemon: %s", bytes.TrimSpace(stderr.Bytes()))
+	}
+	return nil
+}
+
 // GetDUTInfo retrieves various DUT information needed for test execution.
 func (c *Client) GetDUTInfo(ctx context.Context, req *protocol.GetDUTInfoRequest) (res *protocol.GetDUTInfoResponse, retErr error) {
 	defer func() {
@@ -228,6 +312,11 @@ func (c *Client) ListTests(ctx context.Context, patterns []string, features *pro
 			Resolved: e,
 		})
 	}
+	// Only tests to run are checked so that a collision of unrelated tests
+	// does not prevent running others.
+	if err := drivercore.CheckDuplicateTests(tests); err != nil {
+		return nil, err
+	}
 	return tests, nil
 }
 
@@ -0,0 +1,242 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sshconfig
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultUser is the user to log in to the DUT as if it is specified
+	// neither in the target nor in SSH configuration files.
+	defaultUser = "root"
+
+	// defaultControlPersist is the duration for which a master connection
+	// started by Tast stays alive after the last connection through it is
+	// closed, unless ControlPersist is set in SSH configuration files.
+	defaultControlPersist = "10m"
+
+	// dutSSHAddr is the address of the SSH server on the DUT as seen from the
+	// DUT itself. Connections through a master connection are forwarded to it.
+	dutSSHAddr = "127.0.0.1:22"
+)
+
+// Control describes connection multiplexing settings for a host, i.e. the
+// ControlMaster, ControlPath and ControlPersist parameters in SSH
+// configuration files.
+type Control struct {
+	// Master is the lowercased value of ControlMaster, e.g. "auto".
+	// It is empty if ControlMaster is not set.
+	Master string
+	// Path is the path to the control socket with tokens expanded.
+	// It is empty if ControlPath is not set or "none".
+	Path string
+	// Persist is the value of ControlPersist.
+	// It is empty if ControlPersist is not set.
+	Persist string
+
+	// alias is the host name given by the user.
+	alias string
+	// port is the port given by the user. It is empty if unspecified.
+	port string
+	// user is the user to log in to the host as.
+	user string
+	// tokens contains values of tokens to expand in control socket paths.
+	tokens map[byte]string
+}
+
+// ResolveControl returns connection multiplexing settings for target in the
+// form "[<user>@]host[:<port>]" based on ~/.ssh/config and /etc/ssh/ssh_config.
+func ResolveControl(target string) (*Control, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	userConfigDir := filepath.Join(homeDir, ".ssh")
+	return ResolveControlFromFiles(target, []FileParam{
+		{
+			Path:    filepath.Join(userConfigDir, "config"),
+			BaseDir: userConfigDir,
+		},
+		{
+			Path:    "/etc/ssh/ssh_config",
+			BaseDir: "/etc/ssh",
+		},
+	})
+}
+
+// ResolveControlFromFiles returns connection multiplexing settings for target
+// based on a list of SSH configuration files.
+func ResolveControlFromFiles(target string, configFiles []FileParam) (*Control, error) {
+	user := ""
+	if i := strings.LastIndex(target, "@"); i >= 0 {
+		user, target = target[:i], target[i+1:]
+	}
+	host, port, err := splitHostPort(target)
+	if err != nil {
+		return nil, err
+	}
+	sc, err := readFiles(configFiles)
+	if err != nil {
+		return nil, err
+	}
+	si := sc.resolveSSHInfo(host)
+
+	c := &Control{
+		Master:  si.controlMaster,
+		Persist: si.controlPersist,
+		alias:   host,
+		port:    port,
+		user:    user,
+	}
+	if c.user == "" {
+		c.user = si.user
+	}
+	if c.user == "" {
+		c.user = defaultUser
+	}
+	hostName := si.hostName
+	if hostName == "" {
+		hostName = host
+	}
+	if port == "" {
+		port = si.port
+	}
+	if port == "" {
+		port = "22"
+	}
+	c.tokens = controlTokens(host, hostName, port, c.user)
+	if si.controlPath != "" && !strings.EqualFold(si.controlPath, "none") {
+		if c.Path, err = c.expandPath(si.controlPath); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// controlTokens returns values of tokens used in control socket paths.
+// See TOKENS in ssh_config(5).
+func controlTokens(alias, hostName, port, user string) map[byte]string {
+	localHost, _ := os.Hostname()
+	shortLocalHost := localHost
+	if i := strings.Index(shortLocalHost, "."); i >= 0 {
+		shortLocalHost = shortLocalHost[:i]
+	}
+	localUser := os.Getenv("USER")
+	homeDir, _ := os.UserHomeDir()
+	hash := sha1.Sum([]byte(localHost + hostName + port + user))
+	return map[byte]string{
+		'%': "%",
+		'C': hex.EncodeToString(hash[:]),
+		'd': homeDir,
+		'h': hostName,
+		'i': strconv.Itoa(os.Getuid()),
+		'L': shortLocalHost,
+		'l': localHost,
+		'n': alias,
+		'p': port,
+		'r': user,
+		'u': localUser,
+	}
+}
+
+// expandPath expands a leading tilde and tokens in a control socket path.
+// Unknown tokens are left as they are.
+func (c *Control) expandPath(path string) (string, error) {
+	path, err := expandLeadingTilde(path)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	for i := 0; i < len(path); i++ {
+		if path[i] == '%' && i+1 < len(path) {
+			if v, ok := c.tokens[path[i+1]]; ok {
+				sb.WriteString(v)
+				i++
+				continue
+			}
+		}
+		sb.WriteByte(path[i])
+	}
+	return sb.String(), nil
+}
+
+// Alive returns whether a master connection is listening on Path.
+func (c *Control) Alive() bool {
+	if c.Path == "" {
+		return false
+	}
+	conn, err := net.DialTimeout("unix", c.Path, time.Second)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// canStartMaster returns whether ControlMaster allows starting a new master
+// connection without asking the user.
+func (c *Control) canStartMaster() bool {
+	return c.Master == "yes" || c.Master == "auto"
+}
+
+// ProxyCommand returns a proxy command which tunnels connections to the SSH
+// server on the DUT through a multiplexed connection to the host, or an empty
+// string if connection multiplexing is disabled.
+//
+// An existing master connection listening on Path is reused if available.
+// Otherwise a new master connection is started if ControlMaster is "yes" or
+// "auto". If ControlPath is not set, a control socket managed by Tast under
+// the user cache directory is used. New master connections persist after
+// Tast exits for ControlPersist, or for a while if it is not set, so that
+// later Tast invocations can skip authentication. keyFile is a path to the
+// SSH private key to authenticate with when starting a master connection.
+func (c *Control) ProxyCommand(keyFile string) string {
+	alive := c.Alive()
+	if !alive && !c.canStartMaster() {
+		return ""
+	}
+	path := c.Path
+	if path == "" {
+		dir, err := os.UserCacheDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(dir, "tast", "ssh")
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return ""
+		}
+		path = filepath.Join(dir, c.tokens['C'])
+	}
+	persist := c.Persist
+	if persist == "" {
+		persist = defaultControlPersist
+	}
+
+	args := []string{"ssh",
+		"-o", "ControlMaster=auto",
+		"-o", "ControlPath=" + path,
+		"-o", "ControlPersist=" + persist,
+		"-l", c.user,
+	}
+	if c.port != "" {
+		args = append(args, "-p", c.port)
+	}
+	if keyFile != "" {
+		if _, err := os.Stat(keyFile); err == nil {
+			args = append(args, "-i", keyFile)
+		}
+	}
+	// Escape "%" since the proxy command is subject to token expansion.
+	args = append(args, "-W", dutSSHAddr, c.alias)
+	return strings.ReplaceAll(strings.Join(args, " "), "%", "%%")
+}
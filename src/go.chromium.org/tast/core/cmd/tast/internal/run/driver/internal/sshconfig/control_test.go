@@ -0,0 +1,117 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sshconfig_test
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"go.chromium.org/tast/core/cmd/tast/internal/run/driver/internal/sshconfig"
+	"go.chromium.org/tast/core/testutil"
+)
+
+// writeControlConfig writes an SSH configuration file for tests of
+// ResolveControlFromFiles. "<td>" in content is replaced with the directory.
+func writeControlConfig(t *testing.T, content string) (td string, fileParams []sshconfig.FileParam) {
+	td = testutil.TempDir(t)
+	if err := testutil.WriteFiles(td, map[string]string{
+		"config": strings.ReplaceAll(content, "<td>", td),
+	}); err != nil {
+		os.RemoveAll(td)
+		t.Fatal(err)
+	}
+	return td, []sshconfig.FileParam{{Path: filepath.Join(td, "config"), BaseDir: td}}
+}
+
+func TestResolveControlFromFiles(t *testing.T) {
+	td, fileParams := writeControlConfig(t, `
+Host dut1
+	HostName 10.0.0.1
+	ControlMaster auto
+	ControlPath <td>/cm-%r@%h:%p
+	ControlPersist 1h
+Host dut2
+	ControlMaster yes
+	ControlPath none
+Host dut3
+	User alice
+	ControlPath <td>/cm-%n-%%-%C
+`)
+	defer os.RemoveAll(td)
+
+	for _, tc := range []struct {
+		target  string
+		master  string
+		path    string
+		persist string
+	}{
+		{"dut1", "auto", td + "/cm-root@10.0.0.1:22", "1h"},
+		{"me@dut1:2222", "auto", td + "/cm-me@10.0.0.1:2222", "1h"},
+		{"dut2", "yes", "", ""},
+		{"dut3", "", td + "/cm-dut3-%-[0-9a-f]{40}", ""},
+		{"other", "", "", ""},
+	} {
+		ctl, err := sshconfig.ResolveControlFromFiles(tc.target, fileParams)
+		if err != nil {
+			t.Errorf("ResolveControlFromFiles(%q) failed: %v", tc.target, err)
+			continue
+		}
+		if ctl.Master != tc.master {
+			t.Errorf("ResolveControlFromFiles(%q).Master = %q; want %q", tc.target, ctl.Master, tc.master)
+		}
+		if !regexp.MustCompile("^" + tc.path + "$").MatchString(ctl.Path) {
+			t.Errorf("ResolveControlFromFiles(%q).Path = %q; want %q", tc.target, ctl.Path, tc.path)
+		}
+		if ctl.Persist != tc.persist {
+			t.Errorf("ResolveControlFromFiles(%q).Persist = %q; want %q", tc.target, ctl.Persist, tc.persist)
+		}
+	}
+}
+
+func TestControlProxyCommand(t *testing.T) {
+	td, fileParams := writeControlConfig(t, `
+Host reuse
+	ControlPath <td>/sock
+Host start
+	ControlMaster auto
+	ControlPath <td>/cm-%h
+Host managed
+	ControlMaster yes
+Host disabled
+	ControlPath <td>/dead
+`)
+	defer os.RemoveAll(td)
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(td, "cache"))
+
+	lis, err := net.Listen("unix", filepath.Join(td, "sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+
+	for _, tc := range []struct {
+		target string
+		want   string
+	}{
+		{"reuse", "ssh -o ControlMaster=auto -o ControlPath=" + td + "/sock -o ControlPersist=10m -l root -W 127.0.0.1:22 reuse"},
+		{"start:2222", "ssh -o ControlMaster=auto -o ControlPath=" + td + "/cm-start -o ControlPersist=10m -l root -p 2222 -W 127.0.0.1:22 start"},
+		{"me@managed", "ssh -o ControlMaster=auto -o ControlPath=" + td + "/cache/tast/ssh/[0-9a-f]{40} -o ControlPersist=10m -l me -W 127.0.0.1:22 managed"},
+		{"disabled", ""},
+		{"other", ""},
+	} {
+		ctl, err := sshconfig.ResolveControlFromFiles(tc.target, fileParams)
+		if err != nil {
+			t.Errorf("ResolveControlFromFiles(%q) failed: %v", tc.target, err)
+			continue
+		}
+		if got := ctl.ProxyCommand(""); !regexp.MustCompile("^" + tc.want + "$").MatchString(got) {
+			t.Errorf("ProxyCommand for %q = %q; want %q", tc.target, got, tc.want)
+		}
+	}
+}
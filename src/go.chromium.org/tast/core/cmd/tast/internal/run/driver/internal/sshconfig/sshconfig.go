@@ -127,17 +127,9 @@ func ResolveHostFromFiles(addr string, configFiles []FileParam) (resolvedHost, r
 	if host == "" {
 		return addr, "", nil
 	}
-	sc := block{
-		blockType:  notInBlock,            // top of the hierarchy so it is not in any block.
-		parameters: map[string][]string{}, // initialized as an empty map.
-	}
-	// openedFile is used to maintain current opened files prevent
-	// the same file is included recursively.
-	openedFiles := map[string]struct{}{}
-	for _, fp := range configFiles {
-		if err := readFile(fp.Path, fp.BaseDir, openedFiles, &sc); err != nil {
-			return "", "", err
-		}
+	sc, err := readFiles(configFiles)
+	if err != nil {
+		return "", "", err
 	}
 
 	si := sc.resolveSSHInfo(host)
@@ -157,6 +149,24 @@ func ResolveHostFromFiles(addr string, configFiles []FileParam) (resolvedHost, r
 	return joinHostAndPort(si.hostName, si.port), si.proxyCommand, nil
 }
 
+// readFiles reads SSH configuration files in order and returns the top of
+// the configuration hierarchy.
+func readFiles(configFiles []FileParam) (*block, error) {
+	sc := &block{
+		blockType:  notInBlock,            // top of the hierarchy so it is not in any block.
+		parameters: map[string][]string{}, // initialized as an empty map.
+	}
+	// openedFile is used to maintain current opened files prevent
+	// the same file is included recursively.
+	openedFiles := map[string]struct{}{}
+	for _, fp := range configFiles {
+		if err := readFile(fp.Path, fp.BaseDir, openedFiles, sc); err != nil {
+			return nil, err
+		}
+	}
+	return sc, nil
+}
+
 // readFilesMatchingPattern reads one or more files that match the argument fileParam.Path.
 // The argument pathPattern can have wildcards or tildes.
 // The baseDir is used for Include statement without absolute path.
@@ -350,13 +360,18 @@ func matchHost(inputHostName string, patterns []string) bool {
 }
 
 type resolvedSSHInfo struct {
-	hostName     string
-	port         string
-	proxyCommand string
+	hostName       string
+	port           string
+	proxyCommand   string
+	user           string
+	controlMaster  string
+	controlPath    string
+	controlPersist string
 }
 
 func (si *resolvedSSHInfo) filled() bool {
-	return si.hostName != "" && si.port != "" && si.proxyCommand != ""
+	return si.hostName != "" && si.port != "" && si.proxyCommand != "" &&
+		si.user != "" && si.controlMaster != "" && si.controlPath != "" && si.controlPersist != ""
 }
 
 func (si *resolvedSSHInfo) setUnfilled(src resolvedSSHInfo) {
@@ -369,6 +384,18 @@ func (si *resolvedSSHInfo) setUnfilled(src resolvedSSHInfo) {
 	if si.proxyCommand == "" {
 		si.proxyCommand = src.proxyCommand
 	}
+	if si.user == "" {
+		si.user = src.user
+	}
+	if si.controlMaster == "" {
+		si.controlMaster = src.controlMaster
+	}
+	if si.controlPath == "" {
+		si.controlPath = src.controlPath
+	}
+	if si.controlPersist == "" {
+		si.controlPersist = src.controlPersist
+	}
 }
 
 // resolveSSHInfo resolves SSH info for the given host name.
@@ -404,6 +431,22 @@ func (sc *block) resolveSSHInfo(inputHostName string) resolvedSSHInfo {
 	if len(values) > 0 {
 		si.proxyCommand = strings.Join(values, " ")
 	}
+	values = sc.parameters["user"]
+	if len(values) == 1 {
+		si.user = values[0]
+	}
+	values = sc.parameters["controlmaster"]
+	if len(values) == 1 {
+		si.controlMaster = strings.ToLower(values[0])
+	}
+	values = sc.parameters["controlpath"]
+	if len(values) > 0 {
+		si.controlPath = strings.Join(values, " ")
+	}
+	values = sc.parameters["controlpersist"]
+	if len(values) == 1 {
+		si.controlPersist = values[0]
+	}
 	values = sc.parameters["proxyjump"]
 	if len(values) == 1 {
 		if strings.ToLower(values[0]) == "none" {
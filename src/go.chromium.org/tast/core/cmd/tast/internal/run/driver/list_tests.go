@@ -7,6 +7,7 @@ package driver
 import (
 	"context"
 
+	"go.chromium.org/tast/core/cmd/tast/internal/run/driver/internal/drivercore"
 	"go.chromium.org/tast/core/errors"
 	"go.chromium.org/tast/core/internal/logging"
 	"go.chromium.org/tast/core/internal/protocol"
@@ -22,7 +23,11 @@ func (d *Driver) ListMatchedTests(ctx context.Context, features *protocol.Featur
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to list remote tests")
 	}
-	return append(local, remote...), nil
+	tests := append(local, remote...)
+	if err := drivercore.CheckDuplicateTests(tests); err != nil {
+		return nil, errors.Wrap(err, "local and remote tests collide")
+	}
+	return tests, nil
 }
 
 // ListMatchedLocalTests enumerates local tests matched with the user-supplied
@@ -159,9 +159,17 @@ func TestDriver_ListMatchedTestsWithDisabled(t *gotesting.T) {
 	}
 	want[0].Resolved.Skip = &protocol.Skip{
 		Reasons: []string{"Test pkg.Local1 is disabled by test filter file filter.txt"},
+		TypedReasons: []*protocol.SkipReason{{
+			Code:    protocol.SkipReason_MANUAL,
+			Details: []string{"Test pkg.Local1 is disabled by test filter file filter.txt"},
+		}},
 	}
 	want[3].Resolved.Skip = &protocol.Skip{
 		Reasons: []string{"Test pkg.Remote4 is disabled by test filter file filter.txt"},
+		TypedReasons: []*protocol.SkipReason{{
+			Code:    protocol.SkipReason_MANUAL,
+			Details: []string{"Test pkg.Remote4 is disabled by test filter file filter.txt"},
+		}},
 	}
 	if diff := cmp.Diff(got, want, protocmp.Transform()); diff != "" {
 		t.Errorf("Unexpected list of tests (-got +want):\n%v", diff)
@@ -185,6 +193,10 @@ func TestDriver_ListMatchedLocalTestsDisabled(t *gotesting.T) {
 	}
 	want[1].Resolved.Skip = &protocol.Skip{
 		Reasons: []string{"Test pkg.Local3 is disabled by test filter file filter.txt"},
+		TypedReasons: []*protocol.SkipReason{{
+			Code:    protocol.SkipReason_MANUAL,
+			Details: []string{"Test pkg.Local3 is disabled by test filter file filter.txt"},
+		}},
 	}
 	if diff := cmp.Diff(got, want, protocmp.Transform()); diff != "" {
 		t.Errorf("Unexpected list of tests (-got +want):\n%v", diff)
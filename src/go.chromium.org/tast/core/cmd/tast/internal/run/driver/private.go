@@ -32,20 +32,20 @@ func (d *Driver) DownloadPrivateLocalBundles(ctx context.Context, dutInfo *proto
 	logging.Debug(ctx, "Downloading private local bundles")
 
 	devservers := append([]string(nil), d.cfg.Devservers()...)
-	if url, ok := d.cc.Conn().Services().EphemeralDevserverURL(); ok {
-		devservers = append(devservers, url)
-	}
-
-	var tlwServer, tlwSelfName string
-	if addr, ok := d.cc.Conn().Services().TLWAddr(); ok {
-		tlwServer = addr.String()
-		// TODO: Fix TLW name. Connection spec is not a right choice.
-		tlwSelfName = d.cc.ConnectionSpec()
-	}
-
-	var dutServer string
-	if addr, ok := d.cc.Conn().Services().DUTServerAddr(); ok {
-		dutServer = addr.String()
+	var tlwServer, tlwSelfName, dutServer string
+	// There are no services forwarded to the target with -target=local.
+	if d.cc != nil {
+		if url, ok := d.cc.Conn().Services().EphemeralDevserverURL(); ok {
+			devservers = append(devservers, url)
+		}
+		if addr, ok := d.cc.Conn().Services().TLWAddr(); ok {
+			tlwServer = addr.String()
+			// TODO: Fix TLW name. Connection spec is not a right choice.
+			tlwSelfName = d.cc.ConnectionSpec()
+		}
+		if addr, ok := d.cc.Conn().Services().DUTServerAddr(); ok {
+			dutServer = addr.String()
+		}
 	}
 
 	buildArtifactsURL := d.cfg.BuildArtifactsURLOverride()
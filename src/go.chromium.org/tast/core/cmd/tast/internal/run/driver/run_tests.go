@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
@@ -21,8 +22,10 @@ import (
 	"go.chromium.org/tast/core/internal/debugger"
 	"go.chromium.org/tast/core/internal/logging"
 	"go.chromium.org/tast/core/internal/minidriver"
+	"go.chromium.org/tast/core/internal/minidriver/diagnose"
 	"go.chromium.org/tast/core/internal/minidriver/failfast"
 	"go.chromium.org/tast/core/internal/minidriver/processor"
+	"go.chromium.org/tast/core/internal/minidriver/progress"
 	"go.chromium.org/tast/core/internal/protocol"
 	"go.chromium.org/tast/core/internal/run/reporting"
 	"go.chromium.org/tast/core/internal/run/resultsjson"
@@ -43,12 +46,16 @@ func ShouldRunTestsRecursively() bool {
 
 // runTestsArgs holds arguments common to private methods called by RunTests.
 type runTestsArgs struct {
+	ResDir           string
 	DUTInfo          map[string]*protocol.DUTInfo
 	Counter          *failfast.Counter
+	Progress         *progress.Tracker
 	Client           *reporting.RPCClient
+	Reporter         reporting.Reporter
 	RemoteDevservers []string
 	SwarmingTaskID   string
 	BuildBucketID    string
+	TestOrder        []string
 }
 
 // RunTests runs specified tests per bundle.
@@ -56,6 +63,7 @@ func (d *Driver) RunTests(ctx context.Context,
 	tests []*BundleEntity,
 	dutInfos map[string]*protocol.DUTInfo,
 	client *reporting.RPCClient,
+	reporter reporting.Reporter,
 	remoteDevservers []string,
 	pushedFilesInfo []*protocol.PushedFilesInfoForDUT) ([]*resultsjson.Result, error) {
 	testsPerBundle := make(map[string][]*protocol.ResolvedEntity)
@@ -70,7 +78,14 @@ func (d *Driver) RunTests(ctx context.Context,
 	var results []*resultsjson.Result
 
 	maxFailureCounter := failfast.NewCounter(d.cfg.MaxTestFailures())
+
+	if d.cfg.RepeatIterations() {
+		progressTracker := newProgressTracker(tests, d.cfg.Repeat())
+		return d.runTestIterations(ctx, bundles, testsPerBundle, dutInfos, client, reporter, remoteDevservers, pushedFilesInfo, maxFailureCounter, progressTracker)
+	}
+
 	totalExecutionCount := d.cfg.Repeats() + 1
+	progressTracker := newProgressTracker(tests, totalExecutionCount)
 
 	if totalExecutionCount > 1 {
 		logging.Infof(ctx, "Running tests repeatedly for %v times.", totalExecutionCount)
@@ -78,7 +93,7 @@ func (d *Driver) RunTests(ctx context.Context,
 
 	for i := 0; i < totalExecutionCount; i++ {
 		for _, bundle := range bundles {
-			res, err := d.runTests(ctx, bundle, testsPerBundle[bundle], dutInfos, client, remoteDevservers, pushedFilesInfo, maxFailureCounter)
+			res, err := d.runTests(ctx, bundle, testsPerBundle[bundle], dutInfos, client, reporter, remoteDevservers, pushedFilesInfo, maxFailureCounter, progressTracker, d.cfg.ResDir())
 			results = append(results, res...)
 			if err != nil {
 				return results, err
@@ -89,19 +104,87 @@ func (d *Driver) RunTests(ctx context.Context,
 	return results, nil
 }
 
+// newProgressTracker creates a progress tracker for running tests for
+// iterations times. It returns nil if the number of iterations is unbounded.
+func newProgressTracker(tests []*BundleEntity, iterations int) *progress.Tracker {
+	if iterations <= 0 {
+		return nil
+	}
+	var expected []time.Duration
+	for i := 0; i < iterations; i++ {
+		for _, t := range tests {
+			expected = append(expected, t.Resolved.GetEntity().GetLegacyData().GetExpectedDuration().AsDuration())
+		}
+	}
+	return progress.NewTracker(expected)
+}
+
+// IterationDir returns the name of a directory under ResDir where results of
+// the i-th (1-based) iteration are written with -repeat or -repeat-until-fail.
+func IterationDir(i int) string {
+	return fmt.Sprintf("iteration_%d", i)
+}
+
+// runTestIterations runs tests repeatedly per -repeat and -repeat-until-fail,
+// writing results of each iteration to a separate directory under ResDir.
+func (d *Driver) runTestIterations(ctx context.Context, bundles []string,
+	testsPerBundle map[string][]*protocol.ResolvedEntity, dutInfos map[string]*protocol.DUTInfo,
+	client *reporting.RPCClient, reporter reporting.Reporter, remoteDevservers []string,
+	pushedFilesInfo []*protocol.PushedFilesInfoForDUT, maxFailureCounter *failfast.Counter,
+	progressTracker *progress.Tracker) ([]*resultsjson.Result, error) {
+	var results []*resultsjson.Result
+	for i := 1; d.cfg.Repeat() <= 0 || i <= d.cfg.Repeat(); i++ {
+		if ctx.Err() != nil {
+			return results, ctx.Err()
+		}
+		logging.Infof(ctx, "Starting iteration %d", i)
+		resDir := filepath.Join(d.cfg.ResDir(), IterationDir(i))
+		if err := os.MkdirAll(resDir, 0755); err != nil {
+			return results, err
+		}
+		failed := false
+		for _, bundle := range bundles {
+			res, err := d.runTests(ctx, bundle, testsPerBundle[bundle], dutInfos, client, reporter, remoteDevservers, pushedFilesInfo, maxFailureCounter, progressTracker, resDir)
+			results = append(results, res...)
+			if err != nil {
+				return results, err
+			}
+			for _, r := range res {
+				if len(r.Errors) > 0 {
+					failed = true
+				}
+			}
+		}
+		if failed && d.cfg.RepeatUntilFail() {
+			logging.Infof(ctx, "Stopping after iteration %d since a test failed", i)
+			break
+		}
+	}
+	return results, nil
+}
+
 // runTests runs specified tests. It can return non-nil results even on errors.
 func (d *Driver) runTests(ctx context.Context, bundle string,
 	tests []*protocol.ResolvedEntity, dutInfos map[string]*protocol.DUTInfo,
-	client *reporting.RPCClient, remoteDevservers []string,
-	pushedFilesInfo []*protocol.PushedFilesInfoForDUT, maxFailureCounter *failfast.Counter) ([]*resultsjson.Result, error) {
+	client *reporting.RPCClient, reporter reporting.Reporter, remoteDevservers []string,
+	pushedFilesInfo []*protocol.PushedFilesInfoForDUT, maxFailureCounter *failfast.Counter,
+	progressTracker *progress.Tracker, resDir string) ([]*resultsjson.Result, error) {
+	testOrder, err := d.testOrder(tests)
+	if err != nil {
+		return nil, err
+	}
 
 	args := &runTestsArgs{
+		ResDir:           resDir,
 		DUTInfo:          dutInfos,
 		Counter:          maxFailureCounter,
+		Progress:         progressTracker,
 		Client:           client,
+		Reporter:         reporter,
 		RemoteDevservers: remoteDevservers,
 		SwarmingTaskID:   d.cfg.SwarmingTaskID(),
 		BuildBucketID:    d.cfg.BuildBucketID(),
+		TestOrder:        testOrder,
 	}
 
 	if !ShouldRunTestsRecursively() {
@@ -114,6 +197,11 @@ func (d *Driver) runTests(ctx context.Context, bundle string,
 		if err != nil {
 			return localResults, err
 		}
+		if d.cfg.TargetMode() == config.TargetLocal && len(remoteTests) > 0 {
+			// Remote tests need a DUT to connect to.
+			logging.Infof(ctx, "Skipping %d remote test(s) since there is no DUT", len(remoteTests))
+			return localResults, nil
+		}
 		var remoteTestNames []string
 		for _, t := range remoteTests {
 			remoteTestNames = append(remoteTestNames, t.GetEntity().GetName())
@@ -196,15 +284,18 @@ func (d *Driver) runLocalTestsWithRemoteFixture(ctx context.Context, bundle stri
 	// Create a processor for the remote fixture. This will run in parallel
 	// with the processor for local entities.
 	hs := []processor.Handler{
-		processor.NewLoggingHandler(d.cfg.ResDir(), multiplexer, args.Client),
+		processor.NewLoggingHandler(args.ResDir, multiplexer, args.Client),
 		processor.NewTimingHandler(),
-		processor.NewStreamedResultsHandler(d.cfg.ResDir()),
+		processor.NewStreamedResultsHandler(args.ResDir),
+		processor.NewFixtureResultsHandler(args.ResDir),
 		processor.NewRPCResultsHandler(args.Client),
+		processor.NewReporterHandler(args.Reporter),
 		processor.NewFailFastHandler(args.Counter),
+		processor.NewProgressHandler(args.Progress),
 		// copyOutputHandler should come last as it can block RunEnd for a while.
 		processor.NewCopyOutputHandler(os.Rename),
 	}
-	proc := processor.New(d.cfg.ResDir(), nopDiagnose, hs, bundle)
+	proc := processor.New(args.ResDir, nopDiagnose, hs, bundle)
 	defer func() {
 		proc.RunEnd(ctx, retErr)
 	}()
@@ -266,8 +357,9 @@ func (d *Driver) runLocalTestsWithRetry(ctx context.Context, bundle string, test
 
 	cfg := &minidriver.Config{
 		Retries:               d.cfg.Retries(),
-		ResDir:                d.cfg.ResDir(),
+		ResDir:                args.ResDir,
 		Devservers:            d.cfg.Devservers(),
+		DataMirrors:           d.cfg.DataMirrors(),
 		Target:                d.cfg.Target(),
 		LocalDataDir:          d.cfg.LocalDataDir(),
 		LocalOutDir:           d.cfg.LocalOutDir(),
@@ -278,15 +370,25 @@ func (d *Driver) runLocalTestsWithRetry(ctx context.Context, bundle string, test
 		SystemServicesTimeout: d.cfg.SystemServicesTimeout(),
 		WaitUntilReadyTimeout: d.cfg.WaitUntilReadyTimeout(),
 		MsgTimeout:            d.cfg.MsgTimeout(),
+		Parallel:              d.cfg.Parallel(),
+		LogRateLimit:          d.cfg.TestLogRateLimit(),
+		LogBurst:              d.cfg.TestLogBurst(),
+		DiscardDebugLogs:      d.cfg.TestLogLevel() > logging.LevelDebug,
+		ScopedLogLevels:       d.cfg.ScopedTestLogLevels(),
+		Seed:                  d.cfg.Seed(),
+		TestOrder:             args.TestOrder,
+		RunMetadata:           d.cfg.RunMetadata(),
+		QuarantineThreshold:   d.cfg.QuarantineThreshold(),
 		CheckTestDeps:         d.cfg.CheckTestDeps(),
 		TestVars:              d.cfg.TestVars(),
 		MaybeMissingVars:      d.cfg.MaybeMissingVars(),
 		DUTLabConfig:          d.cfg.DUTLabConfig(),
 		DebuggerPort:          d.cfg.DebuggerPorts()[debugger.LocalBundle],
 		Proxy:                 d.cfg.Proxy() == config.ProxyEnv,
+		RunOnHost:             d.cfg.TargetMode() == config.TargetLocal,
 		DUTFeatures:           dutFeature,
 		ForceSkips:            d.cfg.ForceSkips(),
-		Factory:               minidriver.NewRootHandlersFactory(d.cfg.ResDir(), args.Counter, args.Client),
+		Factory:               minidriver.NewRootHandlersFactory(args.ResDir, args.Counter, args.Progress, d.cfg.FailureSyslogPreRoll(), args.Client, args.Reporter),
 		BuildArtifactsURL:     buildArtifactsURL,
 		SwarmingTaskID:        d.cfg.SwarmingTaskID(),
 		BuildBucketID:         d.cfg.BuildBucketID(),
@@ -313,29 +415,80 @@ func (d *Driver) runRemoteTests(ctx context.Context, bundle string, tests []stri
 
 func (d *Driver) runRemoteTestsOnce(ctx context.Context, bundle string, tests []string, args *runTestsArgs,
 	pushedFilesInfo []*protocol.PushedFilesInfoForDUT) ([]*resultsjson.Result, error) {
-	bcfg, rcfg, err := d.newConfigsForRemoteTests(ctx, tests, args.DUTInfo, args.RemoteDevservers,
+	bcfg, rcfg, err := d.newConfigsForRemoteTests(ctx, tests, args.TestOrder, args.DUTInfo, args.RemoteDevservers,
 		args.SwarmingTaskID, args.BuildBucketID, pushedFilesInfo)
 	if err != nil {
 		return nil, err
 	}
+
+	cl := d.remoteBundleClient(bundle)
+	var pull processor.PullFunc = os.Rename
+	if d.cfg.Drone() != "" {
+		dr, err := d.drone(ctx, bundle)
+		if err != nil {
+			return nil, err
+		}
+		dr.updateConfigs(bcfg, rcfg)
+		cl = dr.bundleClient(bundle, d.cfg.MsgTimeout())
+		pull = dr.pull(ctx)
+	}
+
 	multiplexer := logging.NewMultiLogger()
 	ctx = logging.AttachLogger(ctx, multiplexer)
 
 	hs := []processor.Handler{
-		processor.NewLoggingHandler(d.cfg.ResDir(), multiplexer, args.Client),
+		processor.NewLoggingHandler(args.ResDir, multiplexer, args.Client),
 		processor.NewTimingHandler(),
-		processor.NewStreamedResultsHandler(d.cfg.ResDir()),
+		processor.NewStreamedResultsHandler(args.ResDir),
+		processor.NewFixtureResultsHandler(args.ResDir),
 		processor.NewRPCResultsHandler(args.Client),
+		processor.NewReporterHandler(args.Reporter),
 		processor.NewFailFastHandler(args.Counter),
+		processor.NewProgressHandler(args.Progress),
+		processor.NewFailureSyslogHandler(d.failureSyslogPreRoll(), d.fetchSyslog),
 		// copyOutputHandler should come last as it can block RunEnd for a while.
-		processor.NewCopyOutputHandler(os.Rename),
+		processor.NewCopyOutputHandler(pull),
 	}
-	proc := processor.New(d.cfg.ResDir(), nopDiagnose, hs, bundle)
-	d.remoteBundleClient(bundle).RunTests(ctx, bcfg, rcfg, proc, ShouldRunTestsRecursively())
+	proc := processor.New(args.ResDir, nopDiagnose, hs, bundle)
+	cl.RunTests(ctx, bcfg, rcfg, proc, ShouldRunTestsRecursively())
 	return proc.Results(), proc.FatalError()
 }
 
-func (d *Driver) newConfigsForRemoteTests(ctx context.Context, tests []string,
+// failureSyslogPreRoll returns the pre-roll of system log entries to save for
+// failed tests, or a negative duration if they should not be saved.
+func (d *Driver) failureSyslogPreRoll() time.Duration {
+	if d.cc == nil {
+		return -1
+	}
+	return d.cfg.FailureSyslogPreRoll()
+}
+
+// fetchSyslog saves system log entries of the target between since and until
+// to dst.
+func (d *Driver) fetchSyslog(ctx context.Context, since, until time.Time, dst string) error {
+	return diagnose.SyslogWindow(ctx, d.cc, since, until, dst)
+}
+
+// ResolvedConfigs returns the bundle and run configs resolved to run tests, as
+// RunTests sends them to remote test bundles. Configs for local test bundles
+// are derived from them in RunConfig.Target. They are used to reproduce a run.
+func (d *Driver) ResolvedConfigs(ctx context.Context, tests []*BundleEntity, dutInfos map[string]*protocol.DUTInfo,
+	remoteDevservers []string, pushedFilesInfo []*protocol.PushedFilesInfoForDUT) (*protocol.BundleConfig, *protocol.RunConfig, error) {
+	var resolved []*protocol.ResolvedEntity
+	var names []string
+	for _, t := range tests {
+		resolved = append(resolved, t.Resolved)
+		names = append(names, t.Resolved.GetEntity().GetName())
+	}
+	testOrder, err := d.testOrder(resolved)
+	if err != nil {
+		return nil, nil, err
+	}
+	return d.newConfigsForRemoteTests(ctx, names, testOrder, dutInfos, remoteDevservers,
+		d.cfg.SwarmingTaskID(), d.cfg.BuildBucketID(), pushedFilesInfo)
+}
+
+func (d *Driver) newConfigsForRemoteTests(ctx context.Context, tests, testOrder []string,
 	dutInfos map[string]*protocol.DUTInfo,
 	remoteDevservers []string, swarmingTaskID,
 	buildBucketID string,
@@ -404,6 +557,8 @@ func (d *Driver) newConfigsForRemoteTests(ctx context.Context, tests []string,
 			RunFlags:  d.runFlags(buildArtifactsURL),
 			ListFlags: d.listFlags(buildArtifactsURL),
 		},
+		ServoSpec: d.cfg.Servo(),
+		Phones:    d.cfg.Phones(),
 	}
 	CompanionFeatures := make(map[string]*frameworkprotocol.DUTFeatures)
 	for role, dutInfo := range dutInfos {
@@ -420,8 +575,9 @@ func (d *Driver) newConfigsForRemoteTests(ctx context.Context, tests []string,
 		},
 		Features: d.cfg.Features(dutInfos[""].GetFeatures(), CompanionFeatures),
 		ServiceConfig: &protocol.ServiceConfig{
-			Devservers: remoteDevservers,
-			TlwServer:  d.cfg.TLWServer(),
+			Devservers:  remoteDevservers,
+			DataMirrors: d.cfg.DataMirrors(),
+			TlwServer:   d.cfg.TLWServer(),
 			// DutServer is intentally left blank here because this config is only used by
 			// remote fixture which will use devserver or ephemeral server to download files.
 
@@ -442,6 +598,15 @@ func (d *Driver) newConfigsForRemoteTests(ctx context.Context, tests []string,
 		MsgTimeout:            durationpb.New(d.cfg.MsgTimeout()),
 		MaxSysMsgLogSize:      d.cfg.MaxSysMsgLogSize(),
 		PushedFilesInfo:       pushedFilesInfo,
+		Parallel:              int32(d.cfg.Parallel()),
+		LogRateLimit:          int32(d.cfg.TestLogRateLimit()),
+		LogBurst:              int32(d.cfg.TestLogBurst()),
+		DiscardDebugLogs:      d.cfg.TestLogLevel() > logging.LevelDebug,
+		ScopedLogLevels:       d.cfg.ScopedTestLogLevels(),
+		Seed:                  d.cfg.Seed(),
+		TestOrder:             testOrder,
+		RunMetadata:           protocol.MapToStringPairs(d.cfg.RunMetadata()),
+		QuarantineThreshold:   int32(d.cfg.QuarantineThreshold()),
 		Target: &protocol.RunTargetConfig{
 			Devservers: d.cfg.Devservers(),
 			Dirs: &protocol.RunDirectories{
@@ -459,6 +624,15 @@ func (d *Driver) newConfigsForRemoteTests(ctx context.Context, tests []string,
 			WaitUntilReadyTimeout: durationpb.New(d.cfg.WaitUntilReadyTimeout()),
 			SwarmingTaskID:        d.cfg.SwarmingTaskID(),
 			BuildBucketID:         d.cfg.BuildBucketID(),
+			Parallel:              int32(d.cfg.Parallel()),
+			LogRateLimit:          int32(d.cfg.TestLogRateLimit()),
+			LogBurst:              int32(d.cfg.TestLogBurst()),
+			DiscardDebugLogs:      d.cfg.TestLogLevel() > logging.LevelDebug,
+			ScopedLogLevels:       d.cfg.ScopedTestLogLevels(),
+			Seed:                  d.cfg.Seed(),
+			TestOrder:             testOrder,
+			RunMetadata:           protocol.MapToStringPairs(d.cfg.RunMetadata()),
+			QuarantineThreshold:   int32(d.cfg.QuarantineThreshold()),
 		},
 	}
 	return bcfg, rcfg, nil
@@ -103,7 +103,7 @@ func TestDriver_RunTests(t *gotesting.T) {
 		t.Fatalf("ListMatchedTests failed: %v", err)
 	}
 
-	got, err := drv.RunTests(ctx, tests, nil, nil, nil, nil)
+	got, err := drv.RunTests(ctx, tests, nil, nil, nil, nil, nil)
 	if err != nil {
 		t.Errorf("RunTests failed: %v", err)
 	}
@@ -228,7 +228,7 @@ func TestDriver_RunTests_RemoteFixture(t *gotesting.T) {
 		t.Fatalf("ListMatchedTests failed: %v", err)
 	}
 
-	got, err := drv.RunTests(ctx, tests, nil, nil, nil, nil)
+	got, err := drv.RunTests(ctx, tests, nil, nil, nil, nil, nil)
 	if err != nil {
 		t.Errorf("RunTests failed: %v", err)
 	}
@@ -295,7 +295,7 @@ func TestDriver_RunTests_RetryTests(t *gotesting.T) {
 		t.Fatalf("ListMatchedTests failed: %v", err)
 	}
 
-	got, err := drv.RunTests(ctx, tests, nil, nil, nil, nil)
+	got, err := drv.RunTests(ctx, tests, nil, nil, nil, nil, nil)
 	if err != nil {
 		t.Errorf("RunTests failed: %v", err)
 	}
@@ -306,21 +306,21 @@ func TestDriver_RunTests_RetryTests(t *gotesting.T) {
 				Name:   "test.Local1",
 				Bundle: "bundle",
 			},
-			Errors: []resultsjson.Error{{Reason: "intentional crash (see log for goroutine dump)"}},
+			Errors: []resultsjson.Error{{Reason: "intentional crash (see goroutines.txt for goroutine dump)"}},
 		},
 		{
 			Test: resultsjson.Test{
 				Name:   "test.Local2",
 				Bundle: "bundle",
 			},
-			Errors: []resultsjson.Error{{Reason: "intentional crash (see log for goroutine dump)"}},
+			Errors: []resultsjson.Error{{Reason: "intentional crash (see goroutines.txt for goroutine dump)"}},
 		},
 		{
 			Test: resultsjson.Test{
 				Name:   "test.Local3",
 				Bundle: "bundle",
 			},
-			Errors: []resultsjson.Error{{Reason: "intentional crash (see log for goroutine dump)"}},
+			Errors: []resultsjson.Error{{Reason: "intentional crash (see goroutines.txt for goroutine dump)"}},
 		},
 	}
 	if diff := cmp.Diff(got, want, resultsCmpOpts...); diff != "" {
@@ -328,6 +328,74 @@ func TestDriver_RunTests_RetryTests(t *gotesting.T) {
 	}
 }
 
+func TestDriver_RunTests_FailedFirst(t *gotesting.T) {
+	bundleLocal := testing.NewRegistry("bundle")
+	for _, name := range []string{"test.Local1", "test.Local2", "test.Local3"} {
+		bundleLocal.AddTestInstance(&testing.TestInstance{
+			Name:    name,
+			Timeout: time.Minute,
+			Func:    func(ctx context.Context, s *testing.State) {},
+		})
+	}
+	bundleRemote := testing.NewRegistry("bundle")
+
+	env := runtest.SetUp(
+		t,
+		runtest.WithLocalBundles(bundleLocal),
+		runtest.WithRemoteBundles(bundleRemote),
+	)
+	ctx := env.Context()
+
+	// Write results of a previous run where test.Local3 failed.
+	prevDir := filepath.Join(env.TempDir(), "prev")
+	if err := os.MkdirAll(prevDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	for _, r := range []*resultsjson.Result{
+		{Test: resultsjson.Test{Name: "test.Local1"}},
+		{Test: resultsjson.Test{Name: "test.Local3"}, Errors: []resultsjson.Error{{Reason: "Failed"}}},
+	} {
+		if err := enc.Encode(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(prevDir, reporting.StreamedResultsFilename), b.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := env.Config(func(cfg *config.MutableConfig) {
+		cfg.TestOrder = config.TestOrderFailedFirst
+		cfg.PrevResultsDir = prevDir
+	})
+
+	drv, err := driver.New(ctx, cfg, cfg.Target(), "", nil)
+	if err != nil {
+		t.Fatalf("driver.New failed: %v", err)
+	}
+	defer drv.Close(ctx)
+
+	tests, err := drv.ListMatchedTests(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListMatchedTests failed: %v", err)
+	}
+
+	got, err := drv.RunTests(ctx, tests, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Errorf("RunTests failed: %v", err)
+	}
+
+	want := []*resultsjson.Result{
+		{Test: resultsjson.Test{Name: "test.Local3", Bundle: "bundle"}},
+		{Test: resultsjson.Test{Name: "test.Local1", Bundle: "bundle"}},
+		{Test: resultsjson.Test{Name: "test.Local2", Bundle: "bundle"}},
+	}
+	if diff := cmp.Diff(got, want, resultsCmpOpts...); diff != "" {
+		t.Errorf("Results mismatch (-got +want):\n%s", diff)
+	}
+}
+
 func TestDriver_RunTests_MaxTestFailures(t *gotesting.T) {
 	bundleLocal := testing.NewRegistry("bundle")
 	for _, name := range []string{"test.Local1", "test.Local2", "test.Local3"} {
@@ -362,7 +430,7 @@ func TestDriver_RunTests_MaxTestFailures(t *gotesting.T) {
 		t.Fatalf("ListMatchedTests failed: %v", err)
 	}
 
-	got, err := drv.RunTests(ctx, tests, nil, nil, nil, nil)
+	got, err := drv.RunTests(ctx, tests, nil, nil, nil, nil, nil)
 	if err == nil {
 		t.Error("RunTests unexpectedly succeeded")
 	}
@@ -454,7 +522,7 @@ func TestDriver_RunTests_WithRepeats(t *gotesting.T) {
 		t.Fatalf("driver.ListMatchedTests Failed: %v", err)
 	}
 
-	got, err := drv.RunTests(ctx, tests, nil, nil, nil, nil)
+	got, err := drv.RunTests(ctx, tests, nil, nil, nil, nil, nil)
 	if err != nil {
 		t.Fatalf("driver.RunTests failed: %v", err)
 	}
@@ -564,7 +632,7 @@ func TestDriver_RunTests_RepeatsWithMaxFailures(t *gotesting.T) {
 		t.Fatalf("driver.ListMatchedTests Failed: %v", err)
 	}
 
-	got, err := drv.RunTests(ctx, tests, nil, nil, nil, nil)
+	got, err := drv.RunTests(ctx, tests, nil, nil, nil, nil, nil)
 	// Expects error here.
 	if err == nil {
 		t.Error("RunTests unexpectedly succeeded")
@@ -651,7 +719,7 @@ func TestDriver_RunTests_WithRetries(t *gotesting.T) {
 		t.Fatalf("ListMatchedTests failed: %v", err)
 	}
 
-	got, err := drv.RunTests(ctx, tests, nil, nil, nil, nil)
+	got, err := drv.RunTests(ctx, tests, nil, nil, nil, nil, nil)
 	if err != nil {
 		t.Errorf("RunTests failed: %v", err)
 	}
@@ -794,7 +862,7 @@ func TestDriver_RunTests_TempDirs(t *gotesting.T) {
 		t.Fatalf("ListMatchedTests failed: %v", err)
 	}
 
-	if _, err := drv.RunTests(ctx, tests, nil, nil, nil, nil); err != nil {
+	if _, err := drv.RunTests(ctx, tests, nil, nil, nil, nil, nil); err != nil {
 		t.Errorf("RunTests failed: %v", err)
 	}
 }
@@ -844,7 +912,7 @@ func TestDriver_RunTests_PushedFilesPaths(t *gotesting.T) {
 		t.Fatalf("ListMatchedTests failed: %v", err)
 	}
 
-	if _, err := drv.RunTests(ctx, tests, nil, nil, nil, pushedFilesInfo); err != nil {
+	if _, err := drv.RunTests(ctx, tests, nil, nil, nil, nil, pushedFilesInfo); err != nil {
 		t.Errorf("RunTests failed: %v", err)
 	}
 	if diff := cmp.Diff(got, wanted); diff != "" {
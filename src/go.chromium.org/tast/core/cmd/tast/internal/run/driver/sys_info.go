@@ -6,9 +6,12 @@ package driver
 
 import (
 	"context"
+	"os"
 	"path/filepath"
 
+	"go.chromium.org/tast/core/cmd/tast/internal/run/config"
 	"go.chromium.org/tast/core/errors"
+	"go.chromium.org/tast/core/fsutil"
 	"go.chromium.org/tast/core/internal/linuxssh"
 	"go.chromium.org/tast/core/internal/logging"
 	"go.chromium.org/tast/core/internal/protocol"
@@ -41,7 +44,9 @@ func (d *Driver) GetSysInfoState(ctx context.Context) (*protocol.SysInfoState, e
 	defer st.End()
 	logging.Debug(ctx, "Getting initial system state")
 
-	req := &protocol.GetSysInfoStateRequest{}
+	req := &protocol.GetSysInfoStateRequest{
+		Collectors: d.cfg.SysInfoCollectors(),
+	}
 	res, err := client.GetSysInfoState(ctx, req)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get system info state")
@@ -68,6 +73,7 @@ func (d *Driver) CollectSysInfo(ctx context.Context, initialSysInfo *protocol.Sy
 
 	req := &protocol.CollectSysInfoRequest{
 		InitialState: initialSysInfo,
+		Collectors:   d.cfg.SysInfoCollectors(),
 	}
 	res, err := client.CollectSysInfo(ctx, req)
 	if err != nil {
@@ -75,14 +81,31 @@ func (d *Driver) CollectSysInfo(ctx context.Context, initialSysInfo *protocol.Sy
 	}
 
 	if logDir := res.GetLogDir(); logDir != "" {
-		if err := linuxssh.GetAndDeleteFile(ctx, d.SSHConn(), logDir, filepath.Join(d.cfg.ResDir(), SystemLogsDir), linuxssh.PreserveSymlinks); err != nil {
+		if err := d.getAndDeleteDir(ctx, logDir, filepath.Join(d.cfg.ResDir(), SystemLogsDir)); err != nil {
 			return errors.Wrap(err, "failed to copy system logs")
 		}
 	}
 	if crashDir := res.GetCrashDir(); crashDir != "" {
-		if err := linuxssh.GetAndDeleteFile(ctx, d.SSHConn(), crashDir, filepath.Join(d.cfg.ResDir(), CrashesDir), linuxssh.PreserveSymlinks); err != nil {
+		if err := d.getAndDeleteDir(ctx, crashDir, filepath.Join(d.cfg.ResDir(), CrashesDir)); err != nil {
 			return errors.Wrap(err, "failed to copy crashes")
 		}
 	}
 	return nil
 }
+
+// getAndDeleteDir copies a directory at src on the target to dst on the host,
+// and deletes src. With -target=local, src is on the host and is moved
+// locally instead.
+func (d *Driver) getAndDeleteDir(ctx context.Context, src, dst string) error {
+	if d.cfg.TargetMode() != config.TargetLocal {
+		return linuxssh.GetAndDeleteFile(ctx, d.SSHConn(), src, dst, linuxssh.PreserveSymlinks)
+	}
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+	// src may be on a different file system.
+	if err := fsutil.CopyDir(src, dst); err != nil {
+		return err
+	}
+	return os.RemoveAll(src)
+}
@@ -92,6 +92,46 @@ func TestDriver_CollectSysInfo(t *testing.T) {
 	}
 }
 
+func TestDriver_SysInfoCollectors(t *testing.T) {
+	collectors := []string{"ec_console", "bt_hci"}
+	var gotStart, gotCollect []string
+	env := runtest.SetUp(t,
+		runtest.WithGetSysInfoState(func(req *protocol.GetSysInfoStateRequest) (*protocol.GetSysInfoStateResponse, error) {
+			gotStart = req.GetCollectors()
+			return &protocol.GetSysInfoStateResponse{State: &protocol.SysInfoState{}}, nil
+		}),
+		runtest.WithCollectSysInfo(func(req *protocol.CollectSysInfoRequest) (*protocol.CollectSysInfoResponse, error) {
+			gotCollect = req.GetCollectors()
+			return &protocol.CollectSysInfoResponse{}, nil
+		}),
+	)
+	ctx := env.Context()
+	cfg := env.Config(func(cfg *config.MutableConfig) {
+		cfg.SysInfoCollectors = collectors
+	})
+
+	drv, err := driver.New(ctx, cfg, cfg.Target(), "", nil)
+	if err != nil {
+		t.Fatalf("driver.New failed: %v", err)
+	}
+	defer drv.Close(ctx)
+
+	state, err := drv.GetSysInfoState(ctx)
+	if err != nil {
+		t.Fatalf("GetSysInfoState failed: %v", err)
+	}
+	if err := drv.CollectSysInfo(ctx, state); err != nil {
+		t.Fatalf("CollectSysInfo failed: %v", err)
+	}
+
+	if diff := cmp.Diff(gotStart, collectors); diff != "" {
+		t.Errorf("GetSysInfoState: Collectors mismatch (-got +want):\n%s", diff)
+	}
+	if diff := cmp.Diff(gotCollect, collectors); diff != "" {
+		t.Errorf("CollectSysInfo: Collectors mismatch (-got +want):\n%s", diff)
+	}
+}
+
 func TestCollectSysInfoNoHost(t *testing.T) {
 	env := runtest.SetUp(t)
 	ctx := env.Context()
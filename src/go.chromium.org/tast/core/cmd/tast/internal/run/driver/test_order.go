@@ -0,0 +1,83 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package driver
+
+import (
+	"bufio"
+	"encoding/json"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"go.chromium.org/tast/core/cmd/tast/internal/run/config"
+	"go.chromium.org/tast/core/errors"
+	"go.chromium.org/tast/core/internal/protocol"
+	"go.chromium.org/tast/core/internal/run/reporting"
+	"go.chromium.org/tast/core/internal/run/resultsjson"
+)
+
+// testOrder returns names of tests in the order test bundles should run them
+// per -order. It returns nil if test bundles should group tests by fixtures.
+func (d *Driver) testOrder(tests []*protocol.ResolvedEntity) ([]string, error) {
+	if d.cfg.TestOrder() == config.TestOrderFixture {
+		return nil, nil
+	}
+
+	sorted := append([]*protocol.ResolvedEntity(nil), tests...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].GetEntity().GetName() < sorted[j].GetEntity().GetName()
+	})
+
+	switch d.cfg.TestOrder() {
+	case config.TestOrderRandom:
+		r := rand.New(rand.NewSource(d.cfg.Seed()))
+		r.Shuffle(len(sorted), func(i, j int) { sorted[i], sorted[j] = sorted[j], sorted[i] })
+	case config.TestOrderFailedFirst:
+		failed, err := readFailedTests(d.cfg.PrevResultsDir())
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read previous results")
+		}
+		// Group tests by fixtures within failed and other tests to avoid
+		// setting up fixtures repeatedly.
+		sort.SliceStable(sorted, func(i, j int) bool {
+			ei, ej := sorted[i].GetEntity(), sorted[j].GetEntity()
+			if fi, fj := failed[ei.GetName()], failed[ej.GetName()]; fi != fj {
+				return fi
+			}
+			return ei.GetFixture() < ej.GetFixture()
+		})
+	}
+
+	names := make([]string, len(sorted))
+	for i, t := range sorted {
+		names[i] = t.GetEntity().GetName()
+	}
+	return names, nil
+}
+
+// readFailedTests returns names of tests that failed in a run whose results
+// are in resDir. Streamed results are read so that tests that failed before
+// an abrupt termination of the run are also found.
+func readFailedTests(resDir string) (map[string]bool, error) {
+	f, err := os.Open(filepath.Join(resDir, reporting.StreamedResultsFilename))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// Later records of the same test take precedence over earlier ones.
+	failed := make(map[string]bool)
+	dec := json.NewDecoder(bufio.NewReader(f))
+	for dec.More() {
+		var r resultsjson.Result
+		if err := dec.Decode(&r); err != nil {
+			// The last record may be truncated if the run was aborted.
+			break
+		}
+		failed[r.Name] = len(r.Errors) > 0
+	}
+	return failed, nil
+}
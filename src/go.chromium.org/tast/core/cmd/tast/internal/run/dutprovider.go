@@ -0,0 +1,111 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package run
+
+import (
+	"context"
+	"os"
+
+	"go.chromium.org/tast/core/errors"
+
+	"go.chromium.org/tast/core/cmd/tast/internal/run/config"
+	"go.chromium.org/tast/core/cmd/tast/internal/run/driver"
+	"go.chromium.org/tast/core/cmd/tast/internal/run/dutprovider"
+	"go.chromium.org/tast/core/internal/logging"
+	"go.chromium.org/tast/core/internal/run/resultsjson"
+)
+
+// runWithDUTProvider executes or lists tests on a DUT leased from
+// cfg.DUTProvider, and releases the DUT at the end of the run.
+//
+// If the run fails and the leased DUT turns out to be unhealthy, the DUT is
+// released as unhealthy and another DUT is leased to resume the run, up to
+// cfg.DUTReplacements times. Tests that already completed without errors are
+// not run again, as is the case with "tast resume".
+func runWithDUTProvider(ctx context.Context, cfg *config.Config, state *config.DeprecatedState) ([]*resultsjson.Result, error) {
+	p, err := dutprovider.New(cfg.DUTProvider())
+	if err != nil {
+		return nil, err
+	}
+
+	for replacements := 0; ; replacements++ {
+		target, err := p.Lease(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to lease DUT from %v", p)
+		}
+		logging.Infof(ctx, "Leased DUT %s from %v", target, p)
+
+		results, runErr := runOnTarget(ctx, cfg.WithTarget(target), state)
+
+		healthy := true
+		if runErr != nil && ctx.Err() == nil {
+			if err := checkTargetHealth(ctx, cfg.WithTarget(target)); err != nil {
+				logging.Infof(ctx, "DUT %s is unhealthy: %v", target, err)
+				healthy = false
+			}
+		}
+		if err := p.Release(ctx, target, healthy); err != nil {
+			logging.Infof(ctx, "Failed to release DUT %s: %v", target, err)
+		} else {
+			logging.Infof(ctx, "Released DUT %s to %v", target, p)
+		}
+
+		if healthy || cfg.Mode() != config.RunTestsMode || replacements >= cfg.DUTReplacements() {
+			return results, runErr
+		}
+
+		logging.Infof(ctx, "Replacing unhealthy DUT %s after error: %v", target, runErr)
+		next, err := resumeConfig(cfg)
+		if err != nil {
+			return results, errors.Wrapf(runErr, "failed to resume run on another DUT (%v)", err)
+		}
+		if next == nil {
+			return results, runErr
+		}
+		cfg = next
+	}
+}
+
+// checkTargetHealth returns an error if the DUT specified by cfg.Target can
+// not be connected to.
+func checkTargetHealth(ctx context.Context, cfg *config.Config) error {
+	drv, err := driver.New(ctx, cfg, cfg.Target(), "", nil)
+	if err != nil {
+		return err
+	}
+	return drv.Close(ctx)
+}
+
+// resumeConfig returns a configuration to resume the run per cfg whose
+// results are in cfg.ResDir on another DUT. It returns cfg as is if the run
+// failed before tests were planned, and nil if all planned tests have already
+// completed.
+func resumeConfig(cfg *config.Config) (*config.Config, error) {
+	m, err := ReadManifest(cfg.ResDir())
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	prev, err := CompletedResults(cfg.ResDir(), m)
+	if err != nil {
+		return nil, err
+	}
+	completed := make(map[string]struct{})
+	for _, r := range prev {
+		completed[r.Name] = struct{}{}
+	}
+	var remaining []string
+	for _, name := range m.Planned {
+		if _, ok := completed[name]; !ok {
+			remaining = append(remaining, name)
+		}
+	}
+	if len(remaining) == 0 {
+		return nil, nil
+	}
+	return cfg.WithResume(remaining, prev), nil
+}
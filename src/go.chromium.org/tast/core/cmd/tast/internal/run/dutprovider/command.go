@@ -0,0 +1,72 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package dutprovider
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"strings"
+
+	"go.chromium.org/tast/core/errors"
+)
+
+// Command is a Provider delegating leases to an external command, e.g. a
+// client of a lab scheduler. The command is run as
+//
+//	<command> lease
+//
+// to lease a DUT, and it should print the target of the DUT to stdout. The
+// DUT is released by running
+//
+//	<command> release <target> healthy|unhealthy
+type Command struct {
+	path string
+}
+
+// NewCommand creates a Command running the executable at path.
+func NewCommand(path string) *Command {
+	return &Command{path: path}
+}
+
+// String returns a description of the provider.
+func (c *Command) String() string {
+	return "cmd:" + c.path
+}
+
+// Lease runs the command to lease a DUT.
+func (c *Command) Lease(ctx context.Context) (string, error) {
+	out, err := c.run(ctx, "lease")
+	if err != nil {
+		return "", err
+	}
+	target := strings.TrimSpace(out)
+	if target == "" || strings.ContainsAny(target, " \t\n") {
+		return "", errors.Errorf("%s lease printed invalid target %q", c.path, target)
+	}
+	return target, nil
+}
+
+// Release runs the command to release target.
+func (c *Command) Release(ctx context.Context, target string, healthy bool) error {
+	status := "healthy"
+	if !healthy {
+		status = "unhealthy"
+	}
+	_, err := c.run(ctx, "release", target, status)
+	return err
+}
+
+// run runs the command with args and returns its stdout.
+func (c *Command) run(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, c.path, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", errors.Wrapf(err, "%s %s failed: %s", c.path, strings.Join(args, " "), strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package dutprovider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	td := t.TempDir()
+	logPath := filepath.Join(td, "log")
+	script := filepath.Join(td, "scheduler")
+	if err := os.WriteFile(script, []byte(`#!/bin/sh
+echo "$@" >> `+logPath+`
+if [ "$1" = lease ]; then
+  echo dut1:2222
+fi
+`), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	c := NewCommand(script)
+	target, err := c.Lease(ctx)
+	if err != nil {
+		t.Fatal("Lease failed: ", err)
+	}
+	if target != "dut1:2222" {
+		t.Errorf("Lease returned %q; want %q", target, "dut1:2222")
+	}
+	if err := c.Release(ctx, target, false); err != nil {
+		t.Fatal("Release failed: ", err)
+	}
+
+	b, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.TrimSpace(string(b)), "lease\nrelease dut1:2222 unhealthy"; got != want {
+		t.Errorf("Command was run with:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestCommandFailure(t *testing.T) {
+	script := filepath.Join(t.TempDir(), "scheduler")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho 'no DUT available' >&2\nexit 1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	_, err := NewCommand(script).Lease(context.Background())
+	if err == nil {
+		t.Fatal("Lease unexpectedly succeeded")
+	}
+	if !strings.Contains(err.Error(), "no DUT available") {
+		t.Errorf("Lease returned %q; want it to contain stderr of the command", err)
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, spec := range []string{"file:/pool", "cmd:/bin/scheduler"} {
+		p, err := New(spec)
+		if err != nil {
+			t.Errorf("New(%q) failed: %v", spec, err)
+			continue
+		}
+		if p.String() != spec {
+			t.Errorf("New(%q).String() = %q", spec, p.String())
+		}
+	}
+	for _, spec := range []string{"", "file", "file:", "foo:bar"} {
+		if _, err := New(spec); err == nil {
+			t.Errorf("New(%q) unexpectedly succeeded", spec)
+		}
+	}
+}
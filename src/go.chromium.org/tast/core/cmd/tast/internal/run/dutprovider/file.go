@@ -0,0 +1,145 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package dutprovider
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.chromium.org/tast/core/errors"
+	"go.chromium.org/tast/core/internal/logging"
+)
+
+const (
+	// leaseDirSuffix is appended to the path of a pool file to get the
+	// directory holding lease files of DUTs in the pool.
+	leaseDirSuffix = ".leases"
+
+	// unhealthySuffix is appended to the name of a lease file to mark the DUT
+	// unhealthy.
+	unhealthySuffix = ".unhealthy"
+
+	// defaultPollInterval is the interval to check for a DUT released by
+	// another run.
+	defaultPollInterval = 10 * time.Second
+)
+
+// FilePool is a Provider leasing DUTs listed in a local file. The file lists
+// targets one per line; empty lines and lines starting with "#" are ignored.
+//
+// A DUT is leased by creating a lease file under the directory named after
+// the pool file with ".leases" appended, so that concurrent runs sharing the
+// pool file never lease the same DUT. A DUT released as unhealthy keeps its
+// lease file renamed with ".unhealthy" appended, and is not leased again until
+// the file is removed manually.
+type FilePool struct {
+	path         string
+	pollInterval time.Duration
+}
+
+// NewFilePool creates a FilePool leasing DUTs listed in the file at path.
+func NewFilePool(path string) *FilePool {
+	return &FilePool{path: path, pollInterval: defaultPollInterval}
+}
+
+// String returns a description of the provider.
+func (p *FilePool) String() string {
+	return "file:" + p.path
+}
+
+// Lease leases a DUT that is neither leased by others nor unhealthy. It waits
+// for a DUT to be released if all healthy DUTs are leased.
+func (p *FilePool) Lease(ctx context.Context) (string, error) {
+	if err := os.MkdirAll(p.leaseDir(), 0755); err != nil {
+		return "", err
+	}
+	waiting := false
+	for {
+		targets, err := p.targets()
+		if err != nil {
+			return "", err
+		}
+		healthy := 0
+		for _, t := range targets {
+			if _, err := os.Stat(p.leasePath(t) + unhealthySuffix); err == nil {
+				continue
+			}
+			healthy++
+			f, err := os.OpenFile(p.leasePath(t), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+			if os.IsExist(err) {
+				continue
+			}
+			if err != nil {
+				return "", err
+			}
+			fmt.Fprintf(f, "pid=%d\nstart=%s\n", os.Getpid(), time.Now().Format(time.RFC3339))
+			if err := f.Close(); err != nil {
+				return "", err
+			}
+			return t, nil
+		}
+		if healthy == 0 {
+			return "", errors.Errorf("no healthy DUT in %s", p.path)
+		}
+		if !waiting {
+			logging.Infof(ctx, "All %d healthy DUT(s) in %s are leased; waiting for one to be released", healthy, p.path)
+			waiting = true
+		}
+		select {
+		case <-time.After(p.pollInterval):
+		case <-ctx.Done():
+			return "", errors.Wrapf(ctx.Err(), "no DUT in %s became available", p.path)
+		}
+	}
+}
+
+// Release removes the lease file of target, or marks target unhealthy.
+func (p *FilePool) Release(ctx context.Context, target string, healthy bool) error {
+	if healthy {
+		return os.Remove(p.leasePath(target))
+	}
+	return os.Rename(p.leasePath(target), p.leasePath(target)+unhealthySuffix)
+}
+
+// targets reads targets listed in the pool file.
+func (p *FilePool) targets() ([]string, error) {
+	f, err := os.Open(p.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var targets []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		targets = append(targets, line)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if len(targets) == 0 {
+		return nil, errors.Errorf("no DUT listed in %s", p.path)
+	}
+	return targets, nil
+}
+
+func (p *FilePool) leaseDir() string {
+	return p.path + leaseDirSuffix
+}
+
+// leasePath returns the path of the lease file of target.
+func (p *FilePool) leasePath(target string) string {
+	return filepath.Join(p.leaseDir(), url.PathEscape(target))
+}
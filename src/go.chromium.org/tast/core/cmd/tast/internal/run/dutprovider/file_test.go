@@ -0,0 +1,72 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package dutprovider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFilePool(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pool")
+	if err := os.WriteFile(path, []byte("# comment\ndut1\n\ndut2:2222\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	p := NewFilePool(path)
+	p.pollInterval = time.Millisecond
+	ctx := context.Background()
+
+	dut1, err := p.Lease(ctx)
+	if err != nil {
+		t.Fatal("Lease failed: ", err)
+	}
+	dut2, err := p.Lease(ctx)
+	if err != nil {
+		t.Fatal("Lease failed: ", err)
+	}
+	if dut1 != "dut1" || dut2 != "dut2:2222" {
+		t.Fatalf("Lease returned %q and %q; want %q and %q", dut1, dut2, "dut1", "dut2:2222")
+	}
+
+	// All DUTs are leased, so Lease should wait until the context expires.
+	wctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if target, err := NewFilePool(path).Lease(wctx); err == nil {
+		t.Errorf("Lease unexpectedly succeeded with all DUTs leased: %q", target)
+	}
+
+	// A DUT released as healthy can be leased again.
+	if err := p.Release(ctx, dut1, true); err != nil {
+		t.Fatal("Release failed: ", err)
+	}
+	if target, err := p.Lease(ctx); err != nil {
+		t.Fatal("Lease failed: ", err)
+	} else if target != dut1 {
+		t.Errorf("Lease returned %q; want %q", target, dut1)
+	}
+
+	// DUTs released as unhealthy are never leased again.
+	for _, target := range []string{dut1, dut2} {
+		if err := p.Release(ctx, target, false); err != nil {
+			t.Fatal("Release failed: ", err)
+		}
+	}
+	if target, err := p.Lease(ctx); err == nil {
+		t.Errorf("Lease unexpectedly succeeded with all DUTs unhealthy: %q", target)
+	}
+}
+
+func TestFilePoolEmpty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pool")
+	if err := os.WriteFile(path, []byte("# no DUTs\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if target, err := NewFilePool(path).Lease(context.Background()); err == nil {
+		t.Errorf("Lease unexpectedly succeeded for an empty pool: %q", target)
+	}
+}
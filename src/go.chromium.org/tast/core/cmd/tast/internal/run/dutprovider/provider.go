@@ -0,0 +1,47 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package dutprovider leases DUTs to run tests on from pools of
+// interchangeable DUTs.
+package dutprovider
+
+import (
+	"context"
+	"strings"
+
+	"go.chromium.org/tast/core/errors"
+)
+
+// Provider leases DUTs from a pool of interchangeable DUTs.
+type Provider interface {
+	// String returns a description of the provider used in logs and errors.
+	String() string
+	// Lease acquires a DUT from the pool and returns its target, i.e. an SSH
+	// connection spec. It may block until a DUT becomes available.
+	Lease(ctx context.Context) (string, error)
+	// Release returns the DUT leased as target to the pool. healthy is false
+	// if the DUT was found broken, in which case the pool should not lease it
+	// again until it is repaired.
+	Release(ctx context.Context, target string, healthy bool) error
+}
+
+// New creates a Provider from spec of the form "<kind>:<arg>". Supported
+// kinds are:
+//
+//	file:<path>     targets listed in a local file, one per line
+//	cmd:<command>   an external command, e.g. a client of a lab scheduler
+func New(spec string) (Provider, error) {
+	parts := strings.SplitN(spec, ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return nil, errors.Errorf("invalid DUT provider %q: want \"<kind>:<arg>\"", spec)
+	}
+	switch kind, arg := parts[0], parts[1]; kind {
+	case "file":
+		return NewFilePool(arg), nil
+	case "cmd":
+		return NewCommand(arg), nil
+	default:
+		return nil, errors.Errorf("invalid DUT provider %q: unknown kind %q", spec, kind)
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package run
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"go.chromium.org/tast/core/cmd/tast/internal/run/config"
+	"go.chromium.org/tast/core/errors"
+	"go.chromium.org/tast/core/internal/logging"
+	"go.chromium.org/tast/core/internal/run/resultsjson"
+	"go.chromium.org/tast/core/testing/golden"
+)
+
+// updateGoldens copies actual outputs saved by golden.Compare in test output
+// directories of results back to data directories of the tests in the source
+// tree. It returns paths of updated golden files.
+func updateGoldens(ctx context.Context, cfg *config.Config, results []*resultsjson.Result) ([]string, error) {
+	var updated []string
+	for _, r := range results {
+		if r.OutDir == "" || r.Pkg == "" {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(r.OutDir, golden.OutSubdir)); os.IsNotExist(err) {
+			continue
+		}
+		dataDir, err := sourceDataDir(cfg, r)
+		if err != nil {
+			return updated, err
+		}
+		paths, err := golden.Update(r.OutDir, dataDir)
+		for _, p := range paths {
+			logging.Infof(ctx, "Updated golden file %s from %s", p, r.Name)
+		}
+		updated = append(updated, paths...)
+		if err != nil {
+			return updated, errors.Wrapf(err, "failed to update golden files of %s", r.Name)
+		}
+	}
+	return updated, nil
+}
+
+// sourceDataDir returns the data directory of the test of r in the source
+// tree. The package of the test is looked up in the workspace of its bundle,
+// e.g. tast-tests-private for "crosint", before other workspaces.
+func sourceDataDir(cfg *config.Config, r *resultsjson.Result) (string, error) {
+	for _, ws := range cfg.BundleSourceWorkspaces(r.Bundle) {
+		pkgDir := filepath.Join(ws, "src", r.Pkg)
+		if _, err := os.Stat(pkgDir); err == nil {
+			return filepath.Join(pkgDir, "data"), nil
+		}
+	}
+	return "", errors.Errorf("source of %s not found in workspaces of bundle %q", r.Pkg, r.Bundle)
+}
@@ -0,0 +1,172 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package run
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"go.chromium.org/tast/core/errors"
+
+	"go.chromium.org/tast/core/cmd/tast/internal/run/config"
+	"go.chromium.org/tast/core/cmd/tast/internal/run/driver"
+	"go.chromium.org/tast/core/internal/run/reporting"
+	"go.chromium.org/tast/core/internal/run/resultsjson"
+)
+
+// ManifestFile is a file name of the execution manifest of a run, which is
+// directly under ResDir. It is used to resume an interrupted run.
+const ManifestFile = "run_manifest.json"
+
+// Manifest describes an execution plan of a run and its progress.
+type Manifest struct {
+	// TastVersion is the version of the tast command that started the run.
+	TastVersion string `json:"tastVersion"`
+	// Start is the time at which the run started.
+	Start time.Time `json:"start"`
+	// Args is the command-line flags given to "tast run", excluding the
+	// target and patterns.
+	Args []string `json:"args"`
+	// Target is the target device of the run. It is empty if the run was
+	// started with -target=local or -dutprovider.
+	Target string `json:"target"`
+	// Patterns is the test patterns given to "tast run".
+	Patterns []string `json:"patterns"`
+	// ShardIndex, TotalShards and ShardMethod describe the shard of tests
+	// that was selected for the run.
+	ShardIndex  int    `json:"shardIndex"`
+	TotalShards int    `json:"totalShards"`
+	ShardMethod string `json:"shardMethod"`
+	// ForcedFeatures maps names of software features forced available or
+	// unavailable with -forcefeature to their forced availability.
+	ForcedFeatures map[string]bool `json:"forcedFeatures,omitempty"`
+	// Planned is names of tests planned to run, in order.
+	Planned []string `json:"planned"`
+	// Completed is names of tests that have completed without errors.
+	Completed []string `json:"completed"`
+}
+
+// ReadManifest reads the execution manifest in resDir.
+func ReadManifest(resDir string) (*Manifest, error) {
+	b, err := os.ReadFile(filepath.Join(resDir, ManifestFile))
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", ManifestFile)
+	}
+	return &m, nil
+}
+
+// writeManifest writes m to resDir.
+func writeManifest(resDir string, m *Manifest) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(resDir, ManifestFile), b, 0644)
+}
+
+// startManifest writes the execution manifest for a run of tests at the start
+// of the run. If the run resumes an interrupted run, the existing manifest is
+// kept so that the original plan is preserved.
+func startManifest(cfg *config.Config, tests []*driver.BundleEntity) (*Manifest, error) {
+	if cfg.Resume() {
+		return ReadManifest(cfg.ResDir())
+	}
+	m := &Manifest{
+		TastVersion:    cfg.TastVersion(),
+		Start:          time.Now(),
+		Args:           cfg.Args(),
+		Patterns:       cfg.Patterns(),
+		ShardIndex:     cfg.ShardIndex(),
+		TotalShards:    cfg.TotalShards(),
+		ShardMethod:    cfg.ShardMethod(),
+		ForcedFeatures: cfg.ForcedFeatures(),
+		Planned:        []string{},
+		Completed:      []string{},
+	}
+	// A run with a DUT provider leases a DUT again on resuming it.
+	if cfg.DUTProvider() == "" {
+		m.Target = cfg.Target()
+	}
+	for _, t := range tests {
+		m.Planned = append(m.Planned, t.Resolved.GetEntity().GetName())
+	}
+	if err := writeManifest(cfg.ResDir(), m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// finishManifest records tests that completed without errors in results to
+// the manifest m.
+func finishManifest(resDir string, m *Manifest, results []*resultsjson.Result) error {
+	completed := make(map[string]struct{})
+	for _, name := range m.Completed {
+		completed[name] = struct{}{}
+	}
+	for _, r := range results {
+		if len(r.Errors) == 0 {
+			completed[r.Name] = struct{}{}
+		}
+	}
+	m.Completed = make([]string, 0, len(completed))
+	for name := range completed {
+		m.Completed = append(m.Completed, name)
+	}
+	sort.Strings(m.Completed)
+	return writeManifest(resDir, m)
+}
+
+// CompletedResults returns results of tests that completed without errors in
+// an earlier run whose results are in resDir. Streamed results are consulted
+// rather than the list of completed tests in the manifest m so that tests that
+// completed before an abrupt termination of the run are also found.
+func CompletedResults(resDir string, m *Manifest) ([]*resultsjson.Result, error) {
+	f, err := os.Open(filepath.Join(resDir, reporting.StreamedResultsFilename))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// Later records of the same test take precedence over earlier ones.
+	latest := make(map[string]*resultsjson.Result)
+	var order []string
+	dec := json.NewDecoder(bufio.NewReader(f))
+	for dec.More() {
+		var r resultsjson.Result
+		if err := dec.Decode(&r); err != nil {
+			// The last record may be truncated if the run was aborted.
+			break
+		}
+		if _, ok := latest[r.Name]; !ok {
+			order = append(order, r.Name)
+		}
+		latest[r.Name] = &r
+	}
+
+	planned := make(map[string]struct{})
+	for _, name := range m.Planned {
+		planned[name] = struct{}{}
+	}
+	var results []*resultsjson.Result
+	for _, name := range order {
+		r := latest[name]
+		if _, ok := planned[name]; !ok || r.End.IsZero() || len(r.Errors) > 0 {
+			continue
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
@@ -158,7 +158,13 @@ func buildAllRemoteBundlesInChroot(ctx context.Context, cfg *config.Config) erro
 func prepareDUT(ctx context.Context, cfg *config.Config, drv *driver.Driver) (
 	*protocol.DUTInfo, map[string]string, error) {
 	var pushedExcutables map[string]string
-	if cfg.Build() {
+	if cfg.Build() && cfg.TargetMode() == config.TargetLocal {
+		// Local executables are built for the host and run in place, so
+		// there is nothing to push.
+		if err := buildLocalBundles(ctx, cfg, build.ArchHost); err != nil {
+			return nil, nil, err
+		}
+	} else if cfg.Build() {
 		targetArch, err := getTargetArch(ctx, cfg, drv.SSHConn())
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to get architecture information: %v", err)
@@ -199,6 +205,10 @@ func getDUTInfo(ctx context.Context, cfg *config.Config, drv *driver.Driver) (*p
 
 	// TODO(crbug.com/982181): Consider downloading external data files here.
 
+	if cfg.TargetMode() == config.TargetLocal {
+		return dutInfo, nil
+	}
+
 	// After writing files to the DUT, run sync to make sure the written files are persisted
 	// even if the DUT crashes later. This is important especially when we push local_test_runner
 	// because it can appear as zero-byte binary after a crash and subsequent sysinfo phase fails.
@@ -251,6 +261,7 @@ func buildLocalBundles(ctx context.Context, cfg *config.Config, targetArch strin
 			Workspaces: cfg.CommonWorkspaces(),
 			Out:        filepath.Join(cfg.BuildOutDir(), targetArch, path.Base(build.LocalRunnerPkg)),
 			Debug:      cfg.DebuggerPorts()[debugger.LocalTestRunner] != 0,
+			Static:     cfg.BuildStatic(),
 		},
 		{
 			Pkg:        path.Join(localBundlePrefix(cfg.BuildBundle()), cfg.BuildBundle()),
@@ -258,6 +269,7 @@ func buildLocalBundles(ctx context.Context, cfg *config.Config, targetArch strin
 			Workspaces: cfg.BundleWorkspaces(),
 			Out:        filepath.Join(cfg.BuildOutDir(), targetArch, build.LocalBundleBuildSubdir, cfg.BuildBundle()),
 			Debug:      cfg.DebuggerPorts()[debugger.LocalBundle] != 0,
+			Static:     cfg.BuildStatic(),
 		},
 	}
 
@@ -293,6 +305,10 @@ func getTargetArch(ctx context.Context, cfg *config.Config, hst *ssh.Conn) (targ
 	defer st.End()
 	logging.Debug(ctx, "Getting architecture from target")
 
+	if cfg.BuildStatic() {
+		return getStaticTargetArch(ctx, hst)
+	}
+
 	// Get the userland architecture by inspecting an arbitrary binary on the target.
 	out, err := hst.CommandContext(ctx, "file", "-b", "-L", "/sbin/init").CombinedOutput()
 	if err != nil {
@@ -312,6 +328,36 @@ func getTargetArch(ctx context.Context, cfg *config.Config, hst *ssh.Conn) (targ
 	return targetArch, nil
 }
 
+// getStaticTargetArch queries hst for an architecture to build static
+// executables for. Unlike getTargetArch, it does not rely on the file command
+// which may be missing on non-ChromeOS targets. Since static executables do not
+// depend on the userland, the kernel architecture is used, except that 32-bit
+// ARM executables are built for ARMv8 kernels as the userland can not be
+// inspected reliably.
+func getStaticTargetArch(ctx context.Context, hst *ssh.Conn) (string, error) {
+	out, err := hst.CommandContext(ctx, "uname", "-s", "-m").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("uname command failed: %v (output: %q)", err, string(out))
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) != 2 {
+		return "", fmt.Errorf("unexpected uname output %q", string(out))
+	}
+	if fields[0] != "Linux" {
+		return "", fmt.Errorf("unsupported target OS %q", fields[0])
+	}
+	switch fields[1] {
+	case "x86_64":
+		return "x86_64", nil
+	case "aarch64", "arm64":
+		return "aarch64", nil
+	case "armv7l", "armv8l":
+		return "armv7l", nil
+	default:
+		return "", fmt.Errorf("unsupported target architecture %q", fields[1])
+	}
+}
+
 // pushAll pushes the freshly built local test runner, local test bundle executable
 // and local test data files to the DUT if necessary. If cfg.mode is
 // ListTestsMode data files are not pushed since they are not needed to build
@@ -337,7 +383,7 @@ func pushAll(ctx context.Context, cfg *config.Config, drv *driver.Driver, target
 		return nil, fmt.Errorf("failed to get data file list: %v", err)
 	}
 	if len(paths) > 0 {
-		if err := pushDataFiles(ctx, cfg, drv.SSHConn(), cfg.LocalDataDir(), paths); err != nil {
+		if err := pushDataFiles(ctx, cfg, drv, cfg.LocalDataDir(), paths); err != nil {
 			return nil, fmt.Errorf("failed to push data files: %v", err)
 		}
 	}
@@ -360,14 +406,46 @@ func pushExecutables(ctx context.Context, cfg *config.Config, hst *ssh.Conn, tar
 	ctx, st := timing.Start(ctx, "push_executables")
 	defer st.End()
 
+	var srcs []string
+	for src := range files {
+		srcs = append(srcs, src)
+	}
+	sums, err := sha256Files(srcs)
+	if err != nil {
+		return nil, err
+	}
+	bootID, err := linuxssh.ReadBootID(ctx, hst)
+	if err != nil {
+		logging.Debugf(ctx, "Failed to read boot ID: %v", err)
+	}
+	recPath := pushRecordPath(cfg.BuildOutDir(), cfg.Target())
+	changed := readPushRecord(recPath).changedFiles(bootID, files, sums)
+	if len(changed) == 0 {
+		logging.Info(ctx, "Executables on target are up-to-date")
+		return files, nil
+	}
+
 	logging.Info(ctx, "Pushing executables to target")
 	start := time.Now()
-	bytes, err := linuxssh.PutFiles(ctx, hst, files, linuxssh.DereferenceSymlinks)
+	bytes, err := linuxssh.PutFilesWithOptions(ctx, hst, changed, &linuxssh.PutFilesOptions{
+		SymlinkPolicy:  linuxssh.DereferenceSymlinks,
+		BandwidthLimit: int64(cfg.PushBWLimit()) * 1024,
+	})
 	if err != nil {
 		return nil, err
 	}
 	logging.Infof(ctx, "Pushed executables in %v (sent %s)",
 		time.Since(start).Round(time.Millisecond), formatBytes(bytes))
+
+	if bootID != "" {
+		rec := &pushRecord{BootID: bootID, Files: make(map[string]string)}
+		for src, dst := range files {
+			rec.Files[dst] = sums[src]
+		}
+		if err := rec.write(recPath); err != nil {
+			logging.Debugf(ctx, "Failed to write push record: %v", err)
+		}
+	}
 	return files, nil
 }
 
@@ -456,12 +534,23 @@ func dataSrcPath(searchPath []string, dataFilePath string) string {
 	return ""
 }
 
-// pushDataFiles copies the listed entity data files to destDir on hst.
+const (
+	// dataDeltaThreshold is the minimum size of data files sent by delta
+	// transfer, which sends only changed blocks and resumes interrupted
+	// transfers.
+	dataDeltaThreshold = 16 * 1024 * 1024
+
+	// pushDataAttempts is the number of attempts to push data files. Failed
+	// pushes are resumed after reconnecting to the DUT.
+	pushDataAttempts = 3
+)
+
+// pushDataFiles copies the listed entity data files to destDir on the DUT.
 // destDir is the data directory for Tast, e.g. "/usr/share/tast/data/local".
 // The file paths are relative to the package root, i.e. paths take the form
 // "go.chromium.org/tast-tests/cros/local/bundle/cros/<category>/data/<filename>".
 // Otherwise, files will be copied from cfg.BuildWorkspace.
-func pushDataFiles(ctx context.Context, cfg *config.Config, hst *ssh.Conn, destDir string, paths []string) error {
+func pushDataFiles(ctx context.Context, cfg *config.Config, drv *driver.Driver, destDir string, paths []string) error {
 	ctx, st := timing.Start(ctx, "push_data")
 	defer st.End()
 
@@ -494,13 +583,29 @@ func pushDataFiles(ctx context.Context, cfg *config.Config, hst *ssh.Conn, destD
 		return fmt.Errorf("not found: %v", missingPaths)
 	}
 
+	opts := &linuxssh.PutFilesOptions{
+		SymlinkPolicy:  linuxssh.DereferenceSymlinks,
+		BandwidthLimit: int64(cfg.PushBWLimit()) * 1024,
+		DeltaThreshold: dataDeltaThreshold,
+	}
 	start := time.Now()
-	wsBytes, err := linuxssh.PutFiles(ctx, hst, files, linuxssh.DereferenceSymlinks)
-	if err != nil {
-		return err
+	var wsBytes int64
+	for attempt := 1; ; attempt++ {
+		n, err := linuxssh.PutFilesWithOptions(ctx, drv.SSHConn(), files, opts)
+		wsBytes += n
+		if err == nil {
+			break
+		}
+		if attempt == pushDataAttempts || ctx.Err() != nil {
+			return err
+		}
+		logging.Infof(ctx, "Failed to push data files (sent %s so far); resuming after reconnecting: %v", formatBytes(wsBytes), err)
+		if err := drv.ReconnectIfNeeded(ctx, false, false); err != nil {
+			return fmt.Errorf("failed to reconnect: %v", err)
+		}
 	}
 	if len(delPaths) > 0 {
-		if err = linuxssh.DeleteTree(ctx, hst, destDir, delPaths); err != nil {
+		if err := linuxssh.DeleteTree(ctx, drv.SSHConn(), destDir, delPaths); err != nil {
 			return err
 		}
 	}
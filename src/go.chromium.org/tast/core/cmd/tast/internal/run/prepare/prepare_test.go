@@ -147,7 +147,7 @@ func TestPushDataFiles(t *gotesting.T) {
 	}
 
 	// pushDataFiles should copy the required files to the DUT.
-	if err = pushDataFiles(ctx, cfg, drv.SSHConn(), cfg.LocalDataDir(), paths); err != nil {
+	if err = pushDataFiles(ctx, cfg, drv, cfg.LocalDataDir(), paths); err != nil {
 		t.Fatal("pushDataFiles() failed: ", err)
 	}
 	expData := map[string]string{
@@ -256,3 +256,46 @@ func TestSetupPrivateBundles(t *gotesting.T) {
 		})
 	}
 }
+
+func TestPushRecordChangedFiles(t *gotesting.T) {
+	td := testutil.TempDir(t)
+	defer os.RemoveAll(td)
+
+	files := map[string]string{
+		"/local/runner": "/remote/runner",
+		"/local/bundle": "/remote/bundle",
+	}
+	sums := map[string]string{
+		"/local/runner": "runner-sum",
+		"/local/bundle": "bundle-sum",
+	}
+
+	path := pushRecordPath(td, "root@dut:22")
+	rec := readPushRecord(path)
+	if got := rec.changedFiles("boot1", files, sums); !cmp.Equal(got, files) {
+		t.Errorf("changedFiles with no record = %v; want %v", got, files)
+	}
+
+	rec = &pushRecord{BootID: "boot1", Files: map[string]string{
+		"/remote/runner": "runner-sum",
+		"/remote/bundle": "old-bundle-sum",
+	}}
+	if err := rec.write(path); err != nil {
+		t.Fatal(err)
+	}
+	rec = readPushRecord(path)
+
+	for _, tc := range []struct {
+		name   string
+		bootID string
+		want   map[string]string
+	}{
+		{"same boot", "boot1", map[string]string{"/local/bundle": "/remote/bundle"}},
+		{"rebooted", "boot2", files},
+		{"unknown boot", "", files},
+	} {
+		if got := rec.changedFiles(tc.bootID, files, sums); !cmp.Equal(got, tc.want) {
+			t.Errorf("%s: changedFiles = %v; want %v", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package prepare
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// pushRecordsDir is a directory in BuildOutDir where push records are saved.
+const pushRecordsDir = "pushed"
+
+// pushRecord records executables pushed to a DUT during a boot. Executables
+// whose contents are unchanged since the last push in the same boot are not
+// pushed again, which saves hashing them on the DUT.
+type pushRecord struct {
+	// BootID is the boot ID of the DUT at the time of the push.
+	BootID string `json:"bootId"`
+	// Files maps remote paths of pushed executables to SHA256 of their
+	// contents.
+	Files map[string]string `json:"files"`
+}
+
+// unsafeFileNameChars matches characters not to be used in file names.
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// pushRecordPath returns the path to the push record for target in
+// buildOutDir.
+func pushRecordPath(buildOutDir, target string) string {
+	return filepath.Join(buildOutDir, pushRecordsDir, unsafeFileNameChars.ReplaceAllString(target, "_")+".json")
+}
+
+// readPushRecord reads a push record at path. It returns an empty record if
+// the file does not exist or is broken.
+func readPushRecord(path string) *pushRecord {
+	rec := &pushRecord{}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return rec
+	}
+	if err := json.Unmarshal(b, rec); err != nil {
+		return &pushRecord{}
+	}
+	return rec
+}
+
+// write writes rec to path.
+func (rec *pushRecord) write(path string) error {
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0644)
+}
+
+// changedFiles returns a subset of files, a mapping from local paths to
+// remote paths, that have not been pushed during the boot bootID according
+// to rec. sums maps local paths to SHA256 of their contents.
+func (rec *pushRecord) changedFiles(bootID string, files, sums map[string]string) map[string]string {
+	changed := make(map[string]string)
+	for src, dst := range files {
+		if bootID == "" || rec.BootID != bootID || rec.Files[dst] != sums[src] {
+			changed[src] = dst
+		}
+	}
+	return changed
+}
+
+// sha256Files returns SHA256 of files at paths.
+func sha256Files(paths []string) (map[string]string, error) {
+	sums := make(map[string]string)
+	for _, p := range paths {
+		f, err := os.Open(p)
+		if err != nil {
+			return nil, err
+		}
+		h := sha256.New()
+		_, err = io.Copy(h, f)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+		sums[p] = hex.EncodeToString(h.Sum(nil))
+	}
+	return sums, nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package run
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"go.chromium.org/tast/core/internal/logging"
+	"go.chromium.org/tast/core/internal/run/resultsjson"
+)
+
+// RepeatSummaryFile is a file name of the summary of a run with -repeat or
+// -repeat-until-fail, which is directly under ResDir.
+const RepeatSummaryFile = "repeat_summary.json"
+
+// RepeatSummary summarizes results of tests run repeatedly.
+type RepeatSummary struct {
+	// Iterations is the number of iterations tests were run for.
+	Iterations int `json:"iterations"`
+	// Tests is the summary of each test, in the order of their first run.
+	Tests []*RepeatTestSummary `json:"tests"`
+}
+
+// RepeatTestSummary summarizes results of a test run repeatedly.
+type RepeatTestSummary struct {
+	// Name is the name of the test.
+	Name string `json:"name"`
+	// Runs is the number of times the test ran.
+	Runs int `json:"runs"`
+	// Passes is the number of times the test passed.
+	Passes int `json:"passes"`
+	// FirstFailure is the 1-based iteration at which the test failed first,
+	// or 0 if it never failed.
+	FirstFailure int `json:"firstFailure,omitempty"`
+}
+
+// PassRate returns the ratio of passing runs of the test.
+func (s *RepeatTestSummary) PassRate() float64 {
+	if s.Runs == 0 {
+		return 0
+	}
+	return float64(s.Passes) / float64(s.Runs)
+}
+
+// newRepeatSummary summarizes results of tests run repeatedly. Since each
+// iteration runs every test once, the n-th result of a test is regarded as
+// its result in the n-th iteration.
+func newRepeatSummary(results []*resultsjson.Result) *RepeatSummary {
+	sum := &RepeatSummary{}
+	tests := make(map[string]*RepeatTestSummary)
+	for _, r := range results {
+		ts, ok := tests[r.Name]
+		if !ok {
+			ts = &RepeatTestSummary{Name: r.Name}
+			tests[r.Name] = ts
+			sum.Tests = append(sum.Tests, ts)
+		}
+		ts.Runs++
+		if len(r.Errors) == 0 {
+			ts.Passes++
+		} else if ts.FirstFailure == 0 {
+			ts.FirstFailure = ts.Runs
+		}
+		if ts.Runs > sum.Iterations {
+			sum.Iterations = ts.Runs
+		}
+	}
+	return sum
+}
+
+// writeRepeatSummary writes the summary of tests run repeatedly to path and
+// logs pass rates of tests.
+func writeRepeatSummary(ctx context.Context, path string, results []*resultsjson.Result) error {
+	sum := newRepeatSummary(results)
+
+	logging.Infof(ctx, "Pass rates over %d iteration(s):", sum.Iterations)
+	for _, ts := range sum.Tests {
+		logging.Infof(ctx, "  %-60s %d/%d (%.1f%%)", ts.Name, ts.Passes, ts.Runs, 100*ts.PassRate())
+	}
+
+	b, err := json.MarshalIndent(sum, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0644)
+}
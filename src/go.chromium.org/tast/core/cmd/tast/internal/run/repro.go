@@ -0,0 +1,216 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package run
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v2"
+
+	"go.chromium.org/tast/core/cmd/tast/internal/run/config"
+	"go.chromium.org/tast/core/cmd/tast/internal/run/driver"
+	"go.chromium.org/tast/core/internal/protocol"
+	"go.chromium.org/tast/core/internal/run/resultsjson"
+	"go.chromium.org/tast/core/internal/testing"
+	"go.chromium.org/tast/core/shutil"
+)
+
+const (
+	// ReproFile is a file name of a tarball containing files needed to
+	// reproduce a failed run, which is directly under ResDir.
+	ReproFile = "repro.tar.gz"
+
+	// redactedValue replaces values of runtime variables that look secret.
+	redactedValue = "<redacted>"
+)
+
+// secretVarPattern matches names of runtime variables whose values should not
+// be included in reproducibility bundles.
+var secretVarPattern = regexp.MustCompile(`(?i)pass|secret|token|cred|key|auth|cookie`)
+
+// hasFailures returns whether any of results has errors.
+func hasFailures(results []*resultsjson.Result) bool {
+	for _, r := range results {
+		if len(r.Errors) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// redactVars returns a copy of vars with values of secret-looking variables
+// and variables named in secretNames replaced, along with names of the
+// replaced variables.
+func redactVars(vars map[string]string, secretNames []string) (redactedVars map[string]string, redacted []string) {
+	secrets := make(map[string]bool)
+	for _, name := range secretNames {
+		secrets[name] = true
+	}
+	redactedVars = make(map[string]string)
+	for k, v := range vars {
+		if secrets[k] || secretVarPattern.MatchString(k) {
+			v = redactedValue
+			redacted = append(redacted, k)
+		}
+		redactedVars[k] = v
+	}
+	sort.Strings(redacted)
+	return redactedVars, redacted
+}
+
+// redactScopedVars is similar to redactVars but for scoped variables given as
+// "<pattern>:<name>=<value>". Names of replaced variables are returned as
+// "<pattern>:<name>".
+func redactScopedVars(scoped, secretNames []string) (redactedScoped, redacted []string) {
+	for _, s := range scoped {
+		v, err := testing.ParseScopedVar(s)
+		if err != nil {
+			continue
+		}
+		vars, names := redactVars(map[string]string{v.Name: v.Value}, secretNames)
+		if len(names) > 0 {
+			redacted = append(redacted, v.Pattern+":"+v.Name)
+		}
+		redactedScoped = append(redactedScoped, fmt.Sprintf("%s:%s=%s", v.Pattern, v.Name, vars[v.Name]))
+	}
+	return redactedScoped, redacted
+}
+
+// reproScript returns a shell script to re-run tests with the same
+// configuration as cfg. redacted is names of runtime variables that must be
+// supplied by the user.
+func reproScript(cfg *config.Config, tests []string, redacted []string) string {
+	var sb strings.Builder
+	sb.WriteString("#!/bin/sh\n")
+	fmt.Fprintf(&sb, "# Re-runs tests of a failed Tast run (tast version %s).\n", cfg.TastVersion())
+	sb.WriteString("# Usage: repro.sh [target] [extra flags]...\n")
+	if len(redacted) > 0 {
+		sb.WriteString("#\n# The following secret variables were redacted and must be supplied with\n# -var, -varsfile or -varsprovider:\n")
+		for _, name := range redacted {
+			fmt.Fprintf(&sb, "#   %s\n", name)
+		}
+	}
+	sb.WriteString("set -e\n")
+	sb.WriteString(`dir="$(cd "$(dirname "$0")" && pwd)"` + "\n")
+	fmt.Fprintf(&sb, "target=%s\n", shutil.Escape(cfg.Target()))
+	sb.WriteString("if [ $# -gt 0 ]; then target=\"$1\"; shift; fi\n")
+
+	args := []string{
+		fmt.Sprintf("-build=%v", cfg.Build()),
+		fmt.Sprintf("-checktestdeps=%v", cfg.CheckTestDeps()),
+	}
+	if cfg.Build() {
+		args = append(args, "-buildbundle="+cfg.BuildBundle())
+	}
+	if cfg.MaybeMissingVars() != "" {
+		args = append(args, "-maybemissingvars="+cfg.MaybeMissingVars())
+	}
+	scoped, _ := redactScopedVars(cfg.ScopedTestVars(), cfg.SecretVarNames())
+	for _, s := range scoped {
+		if !strings.HasSuffix(s, "="+redactedValue) {
+			args = append(args, "-var="+s)
+		}
+	}
+	roles := make([]string, 0, len(cfg.CompanionDUTs()))
+	for role := range cfg.CompanionDUTs() {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	for _, role := range roles {
+		args = append(args, fmt.Sprintf("-companiondut=%s:%s", role, cfg.CompanionDUTs()[role]))
+	}
+	fmt.Fprintf(&sb, "exec \"${TAST:-tast}\" run %s -varsfile=\"$dir/vars.yaml\" \"$@\" \"$target\" %s\n",
+		shutil.EscapeSlice(args), shutil.EscapeSlice(tests))
+	return sb.String()
+}
+
+// writeRepro writes a tarball to path containing files needed to reproduce a
+// run of tests: resolved runner arguments, bundle and run configs, the tast
+// version, a snapshot of DUT features, the list of tests, runtime variables
+// with secrets redacted, and a shell script to re-run the tests.
+func writeRepro(ctx context.Context, path string, cfg *config.Config, drv *driver.Driver, dutInfos map[string]*protocol.DUTInfo,
+	tests []*driver.BundleEntity, remoteDevservers []string, pushedFilesInfo []*protocol.PushedFilesInfoForDUT) error {
+	vars, redacted := redactVars(cfg.TestVars(), cfg.SecretVarNames())
+	scoped, redactedScoped := redactScopedVars(cfg.ScopedTestVars(), cfg.SecretVarNames())
+	redacted = append(redacted, redactedScoped...)
+
+	bcfg, rcfg, err := drv.ResolvedConfigs(ctx, tests, dutInfos, remoteDevservers, pushedFilesInfo)
+	if err != nil {
+		return err
+	}
+	rcfg.Features.Infra.Vars = vars
+	rcfg.Features.Infra.ScopedVars = scoped
+
+	var names []string
+	for _, t := range tests {
+		names = append(names, t.Resolved.GetEntity().GetName())
+	}
+
+	nonSecretVars := make(map[string]string)
+	for k, v := range vars {
+		if v != redactedValue {
+			nonSecretVars[k] = v
+		}
+	}
+	varsYAML, err := yaml.Marshal(nonSecretVars)
+	if err != nil {
+		return err
+	}
+
+	textFile := func(m proto.Message) []byte {
+		return []byte(prototext.Format(m))
+	}
+	files := []struct {
+		name string
+		mode int64
+		data []byte
+	}{
+		{"VERSION", 0644, []byte(cfg.TastVersion() + "\n")},
+		{"local_runner_args.txt", 0644, textFile(drv.LocalRunnerInitParams())},
+		{"remote_runner_args.txt", 0644, textFile(drv.RemoteRunnerInitParams())},
+		{"bundle_args.txt", 0644, textFile(&protocol.BundleInitParams{BundleConfig: bcfg, Vars: vars})},
+		{"run_config.txt", 0644, textFile(rcfg)},
+		{"features.txt", 0644, textFile(rcfg.GetFeatures())},
+		{"tests.txt", 0644, []byte(strings.Join(names, "\n") + "\n")},
+		{"vars.yaml", 0644, varsYAML},
+		{"repro.sh", 0755, []byte(reproScript(cfg, names, redacted))},
+	}
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	now := time.Now()
+	for _, f := range files {
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    "repro/" + f.name,
+			Mode:    f.mode,
+			Size:    int64(len(f.data)),
+			ModTime: now,
+		}); err != nil {
+			return err
+		}
+		if _, err := tw.Write(f.data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
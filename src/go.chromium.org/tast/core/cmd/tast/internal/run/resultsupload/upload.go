@@ -0,0 +1,270 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package resultsupload uploads a results directory to Google Cloud Storage.
+package resultsupload
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.chromium.org/tast/core/errors"
+)
+
+const (
+	// uploadEndpoint is the base URL of the Cloud Storage JSON API for uploads.
+	uploadEndpoint = "https://storage.googleapis.com/upload/storage/v1"
+
+	// browserURL is the base URL to browse Cloud Storage objects in Cloud Console.
+	browserURL = "https://console.cloud.google.com/storage/browser"
+
+	// ArchiveFile is the name of the object containing the results directory.
+	ArchiveFile = "results.tar.gz"
+
+	// IndexFile is the name of the object listing files in ArchiveFile.
+	IndexFile = "index.json"
+
+	defaultAttempts       = 5
+	defaultInitialBackoff = time.Second
+)
+
+// IndexEntry describes a file in the uploaded results directory.
+type IndexEntry struct {
+	// Path is the path of the file relative to the results directory.
+	Path string `json:"path"`
+	// Size is the size of the file in bytes.
+	Size int64 `json:"size"`
+	// Link is the target of the file if it is a symlink, e.g. a link to a
+	// blob created by the dedup package.
+	Link string `json:"link,omitempty"`
+}
+
+// Uploader uploads results directories to a Cloud Storage location.
+//
+// Credentials are obtained from the gcloud command.
+type Uploader struct {
+	bucket   string
+	prefix   string
+	endpoint string
+	client   *http.Client
+	token    func(ctx context.Context) (string, error)
+
+	attempts       int
+	initialBackoff time.Duration
+}
+
+// New creates an Uploader uploading to dest, a URL in the form of
+// "gs://bucket/prefix".
+func New(dest string) (*Uploader, error) {
+	bucket, prefix, err := ParseURL(dest)
+	if err != nil {
+		return nil, err
+	}
+	return &Uploader{
+		bucket:         bucket,
+		prefix:         prefix,
+		endpoint:       uploadEndpoint,
+		client:         http.DefaultClient,
+		token:          gcloudAccessToken,
+		attempts:       defaultAttempts,
+		initialBackoff: defaultInitialBackoff,
+	}, nil
+}
+
+// ParseURL parses a Cloud Storage URL in the form of "gs://bucket/prefix" and
+// returns the bucket and the prefix without leading and trailing slashes.
+func ParseURL(dest string) (bucket, prefix string, err error) {
+	u, err := url.Parse(dest)
+	if err != nil {
+		return "", "", err
+	}
+	if u.Scheme != "gs" {
+		return "", "", errors.Errorf("%s is not a gs:// URL", dest)
+	}
+	if u.Host == "" {
+		return "", "", errors.Errorf("%s lacks a bucket name", dest)
+	}
+	return u.Host, strings.Trim(u.Path, "/"), nil
+}
+
+// Upload uploads the results directory resDir as a gzipped tarball along with
+// an index of its files, and returns a URL to browse the uploaded objects.
+// Objects are saved under a directory named after resDir, so results of
+// multiple runs can share the same destination.
+func (u *Uploader) Upload(ctx context.Context, resDir string) (string, error) {
+	dir := path.Join(u.prefix, filepath.Base(resDir))
+
+	f, err := os.CreateTemp("", "tast_results.*.tar.gz")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	index, err := writeArchive(f, resDir)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to archive results")
+	}
+	indexJSON, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	token, err := u.token(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get access token")
+	}
+
+	if err := u.upload(ctx, path.Join(dir, ArchiveFile), "application/gzip", f, token); err != nil {
+		return "", errors.Wrapf(err, "failed to upload %s", ArchiveFile)
+	}
+	if err := u.upload(ctx, path.Join(dir, IndexFile), "application/json", bytes.NewReader(indexJSON), token); err != nil {
+		return "", errors.Wrapf(err, "failed to upload %s", IndexFile)
+	}
+	return fmt.Sprintf("%s/%s/%s", browserURL, u.bucket, dir), nil
+}
+
+// writeArchive writes the files under resDir to w as a gzipped tarball and
+// returns an index of the written files.
+func writeArchive(w io.Writer, resDir string) ([]IndexEntry, error) {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	index := []IndexEntry{}
+	if err := filepath.Walk(resDir, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(resDir, p)
+		if err != nil {
+			return err
+		}
+		isLink := fi.Mode()&os.ModeSymlink != 0
+		if rel == "." || !(fi.Mode().IsRegular() || fi.IsDir() || isLink) {
+			return nil
+		}
+		var link string
+		if isLink {
+			if link, err = os.Readlink(p); err != nil {
+				return err
+			}
+		}
+		hdr, err := tar.FileInfoHeader(fi, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if fi.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if fi.IsDir() {
+			return nil
+		}
+		if isLink {
+			index = append(index, IndexEntry{Path: hdr.Name, Link: link})
+			return nil
+		}
+		src, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		if _, err := io.CopyN(tw, src, fi.Size()); err != nil {
+			return err
+		}
+		index = append(index, IndexEntry{Path: hdr.Name, Size: fi.Size()})
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+// upload uploads the content of r to the object name, retrying with
+// exponential backoff on transient errors.
+func (u *Uploader) upload(ctx context.Context, name, contentType string, r io.ReadSeeker, token string) error {
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	backoff := u.initialBackoff
+	for i := 0; i < u.attempts; i++ {
+		if i > 0 {
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return errors.Wrapf(ctx.Err(), "gave up retrying after %v", err)
+			}
+			backoff *= 2
+		}
+		if _, err = r.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		var retry bool
+		if retry, err = u.uploadOnce(ctx, name, contentType, r, size, token); err == nil || !retry {
+			return err
+		}
+	}
+	return errors.Wrapf(err, "gave up after %d attempts", u.attempts)
+}
+
+// uploadOnce sends a single upload request. It returns whether the request
+// may succeed if retried on failure.
+func (u *Uploader) uploadOnce(ctx context.Context, name, contentType string, r io.Reader, size int64, token string) (retry bool, err error) {
+	q := url.Values{}
+	q.Set("uploadType", "media")
+	q.Set("name", name)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		fmt.Sprintf("%s/b/%s/o?%s", u.endpoint, url.PathEscape(u.bucket), q.Encode()), io.NopCloser(r))
+	if err != nil {
+		return false, err
+	}
+	req.ContentLength = size
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", contentType)
+	res, err := u.client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		retry := res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500
+		return retry, errors.Errorf("%s: %s", res.Status, strings.TrimSpace(string(b)))
+	}
+	return false, nil
+}
+
+// gcloudAccessToken returns an access token of the active gcloud account.
+func gcloudAccessToken(ctx context.Context) (string, error) {
+	out, err := exec.CommandContext(ctx, "gcloud", "auth", "print-access-token").Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok {
+			return "", errors.Errorf("gcloud: %v: %s", err, strings.TrimSpace(string(ee.Stderr)))
+		}
+		return "", errors.Wrap(err, "gcloud")
+	}
+	return strings.TrimSpace(string(out)), nil
+}
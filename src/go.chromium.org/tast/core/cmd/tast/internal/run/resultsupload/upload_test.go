@@ -0,0 +1,185 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package resultsupload
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go.chromium.org/tast/core/testutil"
+)
+
+func TestParseURL(t *testing.T) {
+	for _, tc := range []struct {
+		dest       string
+		wantBucket string
+		wantPrefix string
+		wantErr    bool
+	}{
+		{dest: "gs://bucket", wantBucket: "bucket"},
+		{dest: "gs://bucket/a/b/", wantBucket: "bucket", wantPrefix: "a/b"},
+		{dest: "https://bucket/a", wantErr: true},
+		{dest: "gs:///a", wantErr: true},
+	} {
+		bucket, prefix, err := ParseURL(tc.dest)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("ParseURL(%q) unexpectedly succeeded", tc.dest)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseURL(%q) failed: %v", tc.dest, err)
+		} else if bucket != tc.wantBucket || prefix != tc.wantPrefix {
+			t.Errorf("ParseURL(%q) = (%q, %q); want (%q, %q)", tc.dest, bucket, prefix, tc.wantBucket, tc.wantPrefix)
+		}
+	}
+}
+
+func TestUpload(t *testing.T) {
+	const token = "token"
+
+	td := testutil.TempDir(t)
+	defer os.RemoveAll(td)
+	resDir := filepath.Join(td, "20240301-120000")
+	files := map[string]string{
+		"full.txt":            "log",
+		"tests/foo.Bar/a.txt": "hello",
+	}
+	if err := testutil.WriteFiles(resDir, files); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("a.txt", filepath.Join(resDir, "tests/foo.Bar/b.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	objects := make(map[string][]byte)
+	failures := 2 // the first requests fail with a transient error
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/b/bucket/o" || r.URL.Query().Get("uploadType") != "media" {
+			http.NotFound(w, r)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if failures > 0 {
+			failures--
+			http.Error(w, "try again", http.StatusServiceUnavailable)
+			return
+		}
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		objects[r.URL.Query().Get("name")] = b
+		io.WriteString(w, "{}")
+	}))
+	defer srv.Close()
+
+	u, err := New("gs://bucket/prefix/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u.endpoint = srv.URL
+	u.token = func(ctx context.Context) (string, error) { return token, nil }
+	u.initialBackoff = time.Millisecond
+
+	got, err := u.Upload(context.Background(), resDir)
+	if err != nil {
+		t.Fatal("Upload failed: ", err)
+	}
+	if want := "https://console.cloud.google.com/storage/browser/bucket/prefix/20240301-120000"; got != want {
+		t.Errorf("Upload returned %q; want %q", got, want)
+	}
+
+	archive, ok := objects["prefix/20240301-120000/"+ArchiveFile]
+	if !ok {
+		t.Fatalf("%s was not uploaded", ArchiveFile)
+	}
+	gotFiles := make(map[string]string)
+	gr, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		b, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gotFiles[hdr.Name] = string(b)
+	}
+	if diff := cmp.Diff(gotFiles, files); diff != "" {
+		t.Errorf("Uploaded archive mismatch (-got +want):\n%s", diff)
+	}
+
+	var index []IndexEntry
+	if err := json.Unmarshal(objects["prefix/20240301-120000/"+IndexFile], &index); err != nil {
+		t.Fatalf("Failed to unmarshal %s: %v", IndexFile, err)
+	}
+	wantIndex := []IndexEntry{
+		{Path: "full.txt", Size: 3},
+		{Path: "tests/foo.Bar/a.txt", Size: 5},
+		{Path: "tests/foo.Bar/b.txt", Link: "a.txt"},
+	}
+	if diff := cmp.Diff(index, wantIndex); diff != "" {
+		t.Errorf("Uploaded index mismatch (-got +want):\n%s", diff)
+	}
+}
+
+func TestUploadPermanentError(t *testing.T) {
+	td := testutil.TempDir(t)
+	defer os.RemoveAll(td)
+
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.Error(w, "forbidden", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	u, err := New("gs://bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u.endpoint = srv.URL
+	u.token = func(ctx context.Context) (string, error) { return "token", nil }
+	u.initialBackoff = time.Millisecond
+
+	if _, err := u.Upload(context.Background(), td); err == nil {
+		t.Error("Upload unexpectedly succeeded")
+	}
+	if requests != 1 {
+		t.Errorf("Upload sent %d requests; want 1 as a permanent error should not be retried", requests)
+	}
+}
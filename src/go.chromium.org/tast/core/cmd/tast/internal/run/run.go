@@ -20,11 +20,16 @@ import (
 	"go.chromium.org/tast/core/ctxutil"
 	"go.chromium.org/tast/core/errors"
 
+	"go.chromium.org/tast/core/cmd/tast/internal/flakes"
 	"go.chromium.org/tast/core/cmd/tast/internal/run/config"
+	"go.chromium.org/tast/core/cmd/tast/internal/run/crashtriage"
+	"go.chromium.org/tast/core/cmd/tast/internal/run/dedup"
 	"go.chromium.org/tast/core/cmd/tast/internal/run/driver"
 	"go.chromium.org/tast/core/cmd/tast/internal/run/prepare"
+	"go.chromium.org/tast/core/cmd/tast/internal/run/resultsupload"
 	"go.chromium.org/tast/core/cmd/tast/internal/run/sharding"
 	"go.chromium.org/tast/core/internal/logging"
+	"go.chromium.org/tast/core/internal/minidriver/failfast"
 	"go.chromium.org/tast/core/internal/protocol"
 	"go.chromium.org/tast/core/internal/run/devserver"
 	"go.chromium.org/tast/core/internal/run/reporting"
@@ -48,9 +53,23 @@ const (
 // Run executes or lists tests per cfg and returns the results.
 // Messages are logged via ctx as the run progresses.
 func Run(ctx context.Context, cfg *config.Config, state *config.DeprecatedState) ([]*resultsjson.Result, error) {
+	if cfg.Via() != "" {
+		return runVia(ctx, cfg, state)
+	}
+	if cfg.DUTProvider() != "" {
+		return runWithDUTProvider(ctx, cfg, state)
+	}
+	return runOnTarget(ctx, cfg, state)
+}
+
+// runOnTarget executes or lists tests on cfg.Target.
+func runOnTarget(ctx context.Context, cfg *config.Config, state *config.DeprecatedState) ([]*resultsjson.Result, error) {
 	if !config.ShouldConnect(cfg.Target()) {
 		logging.Info(ctx, "Tast will not make any connection to the target '-'.")
 	}
+	if cfg.TargetMode() == config.TargetLocal {
+		logging.Info(ctx, "Running local tests on this machine without a DUT")
+	}
 
 	reportClient, err := reporting.NewRPCClient(ctx, cfg.ReportsServer())
 	if err != nil {
@@ -60,7 +79,7 @@ func Run(ctx context.Context, cfg *config.Config, state *config.DeprecatedState)
 
 	state.RemoteDevservers = cfg.Devservers()
 	// Always start an ephemeral devserver for remote tests if TLWServer is not specified, and allowed.
-	if cfg.TLWServer() == "" && cfg.UseEphemeralDevserver() && config.ShouldConnect(cfg.Target()) {
+	if cfg.TLWServer() == "" && cfg.UseEphemeralDevserver() && config.ShouldConnect(cfg.Target()) && cfg.TargetMode() != config.TargetLocal {
 		es, esAddr, err := startEphemeralDevserverForRemoteTests(ctx, cfg, state)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to start ephemeral devserver for remote tests")
@@ -88,6 +107,13 @@ func Run(ctx context.Context, cfg *config.Config, state *config.DeprecatedState)
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to list tests")
 		}
+		if cfg.ListFixtures() {
+			fixtures, err := listFixtures(ctx, drv)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to list fixtures")
+			}
+			state.Fixtures = fixtures
+		}
 		return results, nil
 	case config.RunTestsMode:
 		results, err := runTests(ctx, cfg, state, drv, reportClient, dutInfo, pushedFilesInfo)
@@ -104,10 +130,24 @@ func prepareEnv(ctx context.Context, cfg *config.Config, drv *driver.Driver) (
 	map[string]*protocol.DUTInfo, []*protocol.PushedFilesInfoForDUT, error) {
 	var pushedFilesInfo []*protocol.PushedFilesInfoForDUT
 	dutInfo := make(map[string]*protocol.DUTInfo)
-	if err := prepare.SetUpRemotePrivateBundle(ctx, cfg, drv); err != nil {
-		return nil, nil, errors.Wrap(err, "failed to prepare Host")
+
+	// With -build=true, remote bundles are built on the host without
+	// accessing the DUT, so build them in parallel with local bundles.
+	remoteDone := make(chan error, 1)
+	if cfg.Build() {
+		go func() {
+			remoteDone <- prepare.SetUpRemotePrivateBundle(ctx, cfg, drv)
+		}()
+	} else {
+		if err := prepare.SetUpRemotePrivateBundle(ctx, cfg, drv); err != nil {
+			return nil, nil, errors.Wrap(err, "failed to prepare Host")
+		}
+		remoteDone <- nil
 	}
 	primaryDutInfo, pushedExecutables, err := prepare.Prepare(ctx, cfg, drv)
+	if remoteErr := <-remoteDone; remoteErr != nil {
+		return nil, nil, errors.Wrap(remoteErr, "failed to prepare Host")
+	}
 	dutInfo[""] = primaryDutInfo
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "failed to build and push primary DUT")
@@ -210,6 +250,9 @@ func listTests(ctx context.Context, cfg *config.Config,
 	if err != nil {
 		return nil, err
 	}
+	if tests, err = filterTestList(cfg, tests); err != nil {
+		return nil, err
+	}
 
 	var shard *sharding.Shard
 	if cfg.ShardMethod() == "hash" {
@@ -233,13 +276,31 @@ func listTests(ctx context.Context, cfg *config.Config,
 			return nil, err
 		}
 		results[i] = &resultsjson.Result{
-			Test:       *test,
-			SkipReason: strings.Join(re.Resolved.GetSkip().GetReasons(), ", "),
+			Test:        *test,
+			SkipReason:  strings.Join(re.Resolved.GetSkip().GetReasons(), ", "),
+			SkipReasons: resultsjson.NewSkipReasons(re.Resolved.GetSkip()),
 		}
 	}
 	return results, nil
 }
 
+// listFixtures returns all local and remote fixtures.
+func listFixtures(ctx context.Context, drv *driver.Driver) ([]*protocol.Entity, error) {
+	local, err := drv.ListLocalFixtures(ctx)
+	if err != nil {
+		return nil, err
+	}
+	remote, err := drv.ListRemoteFixtures(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var fixtures []*protocol.Entity
+	for _, f := range append(local, remote...) {
+		fixtures = append(fixtures, f.Resolved.GetEntity())
+	}
+	return fixtures, nil
+}
+
 // verifyTestNames returns nil if all given test names have a match.
 func verifyTestNames(patterns []string, tests []*driver.BundleEntity) error {
 	// Make a map of given test names (NOT patterns).
@@ -258,6 +319,77 @@ func verifyTestNames(patterns []string, tests []*driver.BundleEntity) error {
 	return nil
 }
 
+// filterTestList returns tests matching any of names or globs in
+// cfg.TestList, if it is non-empty, and matching none of cfg.ExcludeList.
+// It is applied to tests already selected by patterns, including attribute
+// expressions.
+func filterTestList(cfg *config.Config, tests []*driver.BundleEntity) ([]*driver.BundleEntity, error) {
+	var include, exclude *testing.Matcher
+	if pats := cfg.TestList(); len(pats) > 0 {
+		m, err := testing.NewMatcher(pats)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed parsing test list")
+		}
+		include = m
+	}
+	if pats := cfg.ExcludeList(); len(pats) > 0 {
+		m, err := testing.NewMatcher(pats)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed parsing exclude list")
+		}
+		exclude = m
+	}
+	if include == nil && exclude == nil {
+		return tests, nil
+	}
+
+	var filtered []*driver.BundleEntity
+	for _, t := range tests {
+		e := t.Resolved.GetEntity()
+		if include != nil && !include.Match(e.GetName(), e.GetAttributes()) {
+			continue
+		}
+		if exclude != nil && exclude.Match(e.GetName(), e.GetAttributes()) {
+			continue
+		}
+		filtered = append(filtered, t)
+	}
+	return filtered, nil
+}
+
+// verifyScopedVars returns nil if each of scoped variables given as
+// "<pattern>:<name>=<value>" is declared by any of tests matching its pattern,
+// so that typos in patterns or variable names do not go unnoticed.
+func verifyScopedVars(scoped []string, tests []*driver.BundleEntity) error {
+	for _, s := range scoped {
+		v, err := testing.ParseScopedVar(s)
+		if err != nil {
+			return err
+		}
+		matched := false
+		declared := false
+		for _, t := range tests {
+			e := t.Resolved.GetEntity()
+			if !v.Match(e.GetName()) {
+				continue
+			}
+			matched = true
+			for _, name := range append(e.GetLegacyData().GetVariables(), e.GetLegacyData().GetVariableDeps()...) {
+				if name == v.Name {
+					declared = true
+				}
+			}
+		}
+		if !matched {
+			return errors.Errorf("-var %s: no tests matched by pattern %s", s, v.Pattern)
+		}
+		if !declared {
+			return errors.Errorf("-var %s: no tests matched by pattern %s declare variable %s", s, v.Pattern, v.Name)
+		}
+	}
+	return nil
+}
+
 func runTests(ctx context.Context, cfg *config.Config,
 	state *config.DeprecatedState,
 	drv *driver.Driver, client *reporting.RPCClient,
@@ -265,6 +397,15 @@ func runTests(ctx context.Context, cfg *config.Config,
 	pushedFilesInfo []*protocol.PushedFilesInfoForDUT) (results []*resultsjson.Result,
 	retErr error) {
 
+	reporter, err := reporting.NewReporters(cfg.Reporters())
+	if err != nil {
+		return nil, err
+	}
+	if dest := cfg.FlakeHistory(); dest != "" {
+		image := dutInfos[""].GetOsVersion()
+		reporter = reporting.AppendReporter(reporter, "flakehistory", flakes.NewWriter(dest, flakes.BoardFromImage(image), image))
+	}
+
 	var roles []string
 	for role := range dutInfos {
 		roles = append(roles, role)
@@ -336,6 +477,16 @@ func runTests(ctx context.Context, cfg *config.Config,
 	if err := verifyTestNames(cfg.Patterns(), tests); err != nil {
 		return nil, err
 	}
+	if err := verifyScopedVars(cfg.ScopedTestVars(), tests); err != nil {
+		return nil, err
+	}
+	if len(cfg.TestList()) > 0 || len(cfg.ExcludeList()) > 0 {
+		n := len(tests)
+		if tests, err = filterTestList(cfg, tests); err != nil {
+			return nil, err
+		}
+		logging.Infof(ctx, "Selected %d/%d tests with test list files", len(tests), n)
+	}
 
 	var shard *sharding.Shard
 	if cfg.ShardMethod() == "hash" {
@@ -360,6 +511,11 @@ func runTests(ctx context.Context, cfg *config.Config,
 		state.TestNamesToSkip = append(state.TestNamesToSkip, t.Resolved.GetEntity().GetName())
 	}
 
+	manifest, err := startManifest(cfg, shard.Included)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to write run manifest")
+	}
+
 	if cfg.TotalShards() > 1 {
 		logging.Infof(ctx, "Running shard %d/%d (tests %d/%d)", cfg.ShardIndex()+1, cfg.TotalShards(),
 			len(testsToRun), len(testsToRun)+len(state.TestNamesToSkip))
@@ -382,6 +538,11 @@ func runTests(ctx context.Context, cfg *config.Config,
 		defer cancel(context.Canceled)
 	}
 
+	runInfo := &reporting.RunInfo{ResDir: cfg.ResDir(), Metadata: cfg.RunMetadata()}
+	for _, t := range shard.Included {
+		runInfo.Tests = append(runInfo.Tests, t.Resolved.GetEntity().GetName())
+	}
+
 	// Write results and collect system info after testing.
 	defer func() {
 		cmdTimeoutPast := ctxutil.DeadlineBefore(ctx, time.Now())
@@ -394,24 +555,161 @@ func runTests(ctx context.Context, cfg *config.Config,
 
 		collectSystemLog(ctx)
 
-		if err := reporting.WriteLegacyResults(filepath.Join(cfg.ResDir(), reporting.LegacyResultsFilename), results); err != nil {
-			logging.Infof(ctx, "Failed writing %s: %v", reporting.LegacyResultsFilename, err)
+		results = append(cfg.PreviousResults(), results...)
+		if cfg.CollectSysInfo() {
+			if err := crashtriage.Triage(ctx, cfg.ResDir(), filepath.Join(cfg.ResDir(), driver.CrashesDir), results, cfg.SymbolDir()); err != nil {
+				logging.Infof(ctx, "Failed triaging crashes: %v", err)
+			}
+		}
+		if err := finishManifest(cfg.ResDir(), manifest, results); err != nil {
+			logging.Infof(ctx, "Failed writing %s: %v", ManifestFile, err)
 		}
 
-		if err := reporting.WriteJUnitXMLResults(filepath.Join(cfg.ResDir(), reporting.JUnitXMLFilename), results); err != nil {
-			logging.Infof(ctx, "Failed writing %s: %v", reporting.JUnitXMLFilename, err)
+		if cfg.RepeatIterations() {
+			if err := writeRepeatSummary(ctx, filepath.Join(cfg.ResDir(), RepeatSummaryFile), results); err != nil {
+				logging.Infof(ctx, "Failed writing %s: %v", RepeatSummaryFile, err)
+			}
 		}
 
 		if err := drv.CollectServoLogs(ctx); err != nil {
 			logging.Infof(ctx, "Failed writing servod logs: %v", err)
 		}
 
-		complete := retErr == nil
+		if cfg.Repro() && (retErr != nil || hasFailures(results)) {
+			if err := writeRepro(ctx, filepath.Join(cfg.ResDir(), ReproFile), cfg, drv, dutInfos, shard.Included, state.RemoteDevservers, pushedFilesInfo); err != nil {
+				logging.Infof(ctx, "Failed writing %s: %v", ReproFile, err)
+			} else {
+				logging.Infof(ctx, "Wrote files to reproduce the run to %s", ReproFile)
+			}
+		}
+
+		if cfg.UpdateGolden() {
+			if updated, err := updateGoldens(ctx, cfg, results); err != nil {
+				logging.Infof(ctx, "Failed updating golden files: %v", err)
+			} else if len(updated) > 0 {
+				logging.Infof(ctx, "Updated %d golden file(s); review and commit them", len(updated))
+			}
+		}
 
 		logging.Info(ctx, "Done collecting logs")
 
-		reporting.WriteResultsToLogs(ctx, results, cfg.ResDir(), complete, cmdTimeoutPast)
+		summary := &reporting.RunSummary{
+			Results:  results,
+			Complete: retErr == nil,
+			TimedOut: cmdTimeoutPast,
+		}
+		if err := reporter.RunFinished(ctx, runInfo, summary); err != nil {
+			logging.Infof(ctx, "Failed reporting results: %v", err)
+		}
+
+		if cfg.DedupResults() {
+			if stats, err := dedup.Dedup(cfg.ResDir(), dedup.DefaultMinSize); err != nil {
+				logging.Infof(ctx, "Failed deduplicating results: %v", err)
+			} else if stats.Replaced > 0 {
+				logging.Infof(ctx, "Replaced %d duplicated file(s) with links to %s, saving %d bytes", stats.Replaced, dedup.BlobsDir, stats.SavedBytes)
+			}
+		}
+
+		if cfg.UploadResults() != "" {
+			uploadResults(ctx, cfg.UploadResults(), cfg.ResDir())
+		}
 	}()
 
-	return drv.RunTests(ctx, shard.Included, dutInfos, client, state.RemoteDevservers, pushedFilesInfo)
+	if err := reporter.RunStarted(ctx, runInfo); err != nil {
+		logging.Infof(ctx, "Failed reporting run start: %v", err)
+	}
+
+	results, err = drv.RunTests(ctx, shard.Included, dutInfos, client, reporter, state.RemoteDevservers, pushedFilesInfo)
+	if errors.Is(err, failfast.ErrTooManyFailures) {
+		results = appendNotRunResults(results, shard.Included, cfg.MaxTestFailures())
+	}
+	if cfg.ReportShardedOut() {
+		results = appendShardedOutResults(ctx, results, shard.Excluded, cfg.ShardIndex(), cfg.TotalShards(), cfg.ResDir())
+	}
+	return results, err
+}
+
+// uploadResults uploads the results directory resDir to the Cloud Storage
+// location dest. Failures are logged but do not fail the run.
+func uploadResults(ctx context.Context, dest, resDir string) {
+	u, err := resultsupload.New(dest)
+	if err != nil {
+		logging.Infof(ctx, "Failed uploading results: %v", err)
+		return
+	}
+	logging.Infof(ctx, "Uploading results to %s", dest)
+	browseURL, err := u.Upload(ctx, resDir)
+	if err != nil {
+		logging.Infof(ctx, "Failed uploading results: %v", err)
+		return
+	}
+	logging.Infof(ctx, "Uploaded results; browse them at %s", browseURL)
+}
+
+// appendNotRunResults appends results for tests that have no result yet after
+// test execution was aborted due to too many failures, so that they are
+// reported as not run rather than silently missing from results.
+func appendNotRunResults(results []*resultsjson.Result, tests []*driver.BundleEntity, maxFailures int) []*resultsjson.Result {
+	seen := make(map[string]struct{})
+	for _, r := range results {
+		seen[r.Name] = struct{}{}
+	}
+	now := time.Now()
+	for _, t := range tests {
+		if _, ok := seen[t.Resolved.GetEntity().GetName()]; ok {
+			continue
+		}
+		test, err := resultsjson.NewTest(t.Resolved.GetEntity())
+		if err != nil {
+			continue
+		}
+		results = append(results, &resultsjson.Result{
+			Test: *test,
+			Errors: []resultsjson.Error{
+				{Time: now, Reason: testing.TestDidNotRunMsg},
+				{Time: now, Reason: fmt.Sprintf("Run aborted after %d test failures (-maxfailures)", maxFailures)},
+			},
+			Start: now,
+			End:   now,
+		})
+	}
+	return results
+}
+
+// appendShardedOutResults appends results for tests excluded from the current
+// shard, so that they are reported as skipped with the SHARDED_OUT code rather
+// than silently missing from results. The results are also written to streamed
+// results in resDir so that they agree with results.json.
+func appendShardedOutResults(ctx context.Context, results []*resultsjson.Result, tests []*driver.BundleEntity, shardIndex, totalShards int, resDir string) []*resultsjson.Result {
+	reason := fmt.Sprintf("assigned to another shard than %d/%d", shardIndex+1, totalShards)
+	now := time.Now()
+	w, err := reporting.NewStreamedWriter(filepath.Join(resDir, reporting.StreamedResultsFilename))
+	if err != nil {
+		logging.Infof(ctx, "Failed to open streamed results: %v", err)
+	} else {
+		defer w.Close()
+	}
+	for _, t := range tests {
+		test, err := resultsjson.NewTest(t.Resolved.GetEntity())
+		if err != nil {
+			continue
+		}
+		res := &resultsjson.Result{
+			Test:       *test,
+			SkipReason: reason,
+			SkipReasons: []resultsjson.SkipReason{{
+				Code:    protocol.SkipReason_SHARDED_OUT.String(),
+				Details: []string{reason},
+			}},
+			Start: now,
+			End:   now,
+		}
+		results = append(results, res)
+		if w != nil {
+			if err := w.Write(res, false); err != nil {
+				logging.Infof(ctx, "Failed to write streamed result of %s: %v", res.Name, err)
+			}
+		}
+	}
+	return results
 }
@@ -5,13 +5,18 @@
 package run_test
 
 import (
+	"archive/tar"
 	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	gotesting "testing"
 	"time"
 
@@ -30,6 +35,7 @@ import (
 	"go.chromium.org/tast/core/internal/run/reporting"
 	"go.chromium.org/tast/core/internal/run/resultsjson"
 	"go.chromium.org/tast/core/internal/testing"
+	"go.chromium.org/tast/core/testing/golden"
 
 	frameworkprotocol "go.chromium.org/tast/core/framework/protocol"
 )
@@ -230,8 +236,9 @@ func TestRunResults(t *gotesting.T) {
 				Timeout:      time.Minute,
 				Bundle:       "bundle",
 			},
-			OutDir:     filepath.Join(cfg.ResDir(), "tests/local.Skip"),
-			SkipReason: "missing SoftwareDeps: missing",
+			OutDir:      filepath.Join(cfg.ResDir(), "tests/local.Skip"),
+			SkipReason:  "missing SoftwareDeps: missing",
+			SkipReasons: []resultsjson.SkipReason{{Code: "UNSATISFIED_SWDEP", Details: []string{"missing SoftwareDeps: missing"}}},
 		},
 		{
 			Test: resultsjson.Test{
@@ -239,8 +246,9 @@ func TestRunResults(t *gotesting.T) {
 				Timeout: time.Minute,
 				Bundle:  "bundle",
 			},
-			OutDir:     filepath.Join(cfg.ResDir(), "tests/local.SkipForCompanion"),
-			SkipReason: "missing SoftwareDeps: missing1",
+			OutDir:      filepath.Join(cfg.ResDir(), "tests/local.SkipForCompanion"),
+			SkipReason:  "missing SoftwareDeps: missing1",
+			SkipReasons: []resultsjson.SkipReason{{Code: "UNSATISFIED_SWDEP", Details: []string{"missing SoftwareDeps: missing1"}}},
 		},
 		{
 			Test: resultsjson.Test{
@@ -266,8 +274,9 @@ func TestRunResults(t *gotesting.T) {
 				Timeout:      time.Minute,
 				Bundle:       "bundle",
 			},
-			SkipReason: "missing SoftwareDeps: missing",
-			OutDir:     filepath.Join(cfg.ResDir(), "tests/remote.Skip"),
+			SkipReason:  "missing SoftwareDeps: missing",
+			SkipReasons: []resultsjson.SkipReason{{Code: "UNSATISFIED_SWDEP", Details: []string{"missing SoftwareDeps: missing"}}},
+			OutDir:      filepath.Join(cfg.ResDir(), "tests/remote.Skip"),
 		},
 		{
 			Test: resultsjson.Test{
@@ -275,8 +284,9 @@ func TestRunResults(t *gotesting.T) {
 				Timeout: time.Minute,
 				Bundle:  "bundle",
 			},
-			OutDir:     filepath.Join(cfg.ResDir(), "tests/remote.SkipForCompanion"),
-			SkipReason: "missing SoftwareDeps: missing1",
+			OutDir:      filepath.Join(cfg.ResDir(), "tests/remote.SkipForCompanion"),
+			SkipReason:  "missing SoftwareDeps: missing1",
+			SkipReasons: []resultsjson.SkipReason{{Code: "UNSATISFIED_SWDEP", Details: []string{"missing SoftwareDeps: missing1"}}},
 		},
 		{
 			Test: resultsjson.Test{
@@ -515,6 +525,95 @@ func TestRunLogs(t *gotesting.T) {
 	}
 }
 
+func TestRunCompressControl(t *gotesting.T) {
+	const n = 1000
+	localReg := testing.NewRegistry("bundle")
+	localReg.AddTestInstance(&testing.TestInstance{
+		Name:    "pkg.Chatty",
+		Timeout: time.Minute,
+		Func: func(ctx context.Context, s *testing.State) {
+			for i := 0; i < n; i++ {
+				s.Logf("Message %d", i)
+			}
+		},
+	})
+
+	env := runtest.SetUp(t, runtest.WithLocalBundles(localReg))
+	ctx := env.Context()
+	cfg := env.Config(func(cfg *config.MutableConfig) {
+		cfg.Patterns = []string{"pkg.Chatty"}
+		cfg.CompressControl = true
+	})
+
+	results, err := run.Run(ctx, cfg, env.State())
+	if err != nil {
+		t.Fatal("Run failed: ", err)
+	}
+	if len(results) != 1 || len(results[0].Errors) > 0 {
+		t.Fatalf("Run returned unexpected results: %+v", results)
+	}
+	b, err := os.ReadFile(filepath.Join(results[0].OutDir, "log.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := fmt.Sprintf("Message %d", n-1); !strings.Contains(string(b), want) {
+		t.Errorf("Test log does not contain %q", want)
+	}
+}
+
+func TestRunScopedVars(t *gotesting.T) {
+	var mu sync.Mutex
+	got := make(map[string]string)
+	newTest := func(name string) *testing.TestInstance {
+		return &testing.TestInstance{
+			Name:    name,
+			Vars:    []string{"pkg.v", "pkg.unused"},
+			Timeout: time.Minute,
+			Func: func(ctx context.Context, s *testing.State) {
+				v, _ := s.Var("pkg.v")
+				mu.Lock()
+				defer mu.Unlock()
+				got[name] = v
+			},
+		}
+	}
+	localReg := testing.NewRegistry("bundle")
+	localReg.AddTestInstance(newTest("pkg.Scoped"))
+	localReg.AddTestInstance(newTest("other.Unscoped"))
+
+	env := runtest.SetUp(t, runtest.WithLocalBundles(localReg))
+	ctx := env.Context()
+	cfg := env.Config(func(cfg *config.MutableConfig) {
+		cfg.Patterns = []string{"pkg.Scoped", "other.Unscoped"}
+		cfg.TestVars = map[string]string{"pkg.v": "global", "pkg.unused": "x"}
+		cfg.ScopedTestVars = []string{"pkg.*:pkg.v=scoped"}
+	})
+
+	results, err := run.Run(ctx, cfg, env.State())
+	if err != nil {
+		t.Fatal("Run failed: ", err)
+	}
+	if diff := cmp.Diff(got, map[string]string{"pkg.Scoped": "scoped", "other.Unscoped": "global"}); diff != "" {
+		t.Errorf("Tests got unexpected variables (-got +want):\n%s", diff)
+	}
+	for _, r := range results {
+		if diff := cmp.Diff(r.ConsumedVars, []string{"pkg.v"}); diff != "" {
+			t.Errorf("ConsumedVars of %s mismatch (-got +want):\n%s", r.Name, diff)
+		}
+	}
+
+	// Scoped variables not declared by any matching tests are rejected.
+	for _, v := range []string{"pkg.*:pkg.typo=x", "typo.*:pkg.v=x"} {
+		cfg := env.Config(func(cfg *config.MutableConfig) {
+			cfg.Patterns = []string{"pkg.Scoped"}
+			cfg.ScopedTestVars = []string{v}
+		})
+		if _, err := run.Run(ctx, cfg, env.State()); err == nil {
+			t.Errorf("Run unexpectedly succeeded with -var=%s", v)
+		}
+	}
+}
+
 func TestRunOutputFiles(t *gotesting.T) {
 	localOut := &testing.TestInstance{
 		Name:    "local.Out",
@@ -907,6 +1006,568 @@ func TestRunWithSkippedTests(t *gotesting.T) {
 	}
 }
 
+func TestRunMaxFailures(t *gotesting.T) {
+	localReg := testing.NewRegistry("bundle")
+	for _, name := range []string{"pkg.Local1", "pkg.Local2", "pkg.Local3"} {
+		localReg.AddTestInstance(&testing.TestInstance{
+			Name:    name,
+			Timeout: time.Minute,
+			Func: func(ctx context.Context, s *testing.State) {
+				s.Error("Failure")
+			},
+		})
+	}
+	remoteReg := testing.NewRegistry("bundle")
+	remoteReg.AddTestInstance(&testing.TestInstance{
+		Name:    "pkg.Remote",
+		Timeout: time.Minute,
+		Func: func(ctx context.Context, s *testing.State) {
+			t.Error("pkg.Remote was run after the failure budget was exhausted")
+		},
+	})
+
+	env := runtest.SetUp(t, runtest.WithLocalBundles(localReg), runtest.WithRemoteBundles(remoteReg))
+	ctx := env.Context()
+	cfg := env.Config(func(cfg *config.MutableConfig) {
+		cfg.MaxTestFailures = 2
+	})
+	state := env.State()
+
+	results, err := run.Run(ctx, cfg, state)
+	if err == nil {
+		t.Error("Run unexpectedly succeeded despite too many failures")
+	}
+
+	notRun := []resultsjson.Error{
+		{Reason: testing.TestDidNotRunMsg},
+		{Reason: "Run aborted after 2 test failures (-maxfailures)"},
+	}
+	want := []*resultsjson.Result{
+		{Test: resultsjson.Test{Name: "pkg.Local1", Bundle: "bundle"}, Errors: []resultsjson.Error{{Reason: "Failure"}}},
+		{Test: resultsjson.Test{Name: "pkg.Local2", Bundle: "bundle"}, Errors: []resultsjson.Error{{Reason: "Failure"}}},
+		{Test: resultsjson.Test{Name: "pkg.Local3", Bundle: "bundle"}, Errors: notRun},
+		{Test: resultsjson.Test{Name: "pkg.Remote", Bundle: "bundle"}, Errors: notRun},
+	}
+	if diff := cmp.Diff(results, want, append(resultsCmpOpts, cmpopts.IgnoreFields(resultsjson.Result{}, "OutDir"))...); diff != "" {
+		t.Errorf("Results mismatch (-got +want):\n%s", diff)
+	}
+}
+
+func TestRunShardedOut(t *gotesting.T) {
+	localReg := testing.NewRegistry("bundle")
+	for _, name := range []string{"pkg.Test1", "pkg.Test2"} {
+		localReg.AddTestInstance(&testing.TestInstance{
+			Name:    name,
+			Timeout: time.Minute,
+			Func:    func(ctx context.Context, s *testing.State) {},
+		})
+	}
+
+	remoteReg := testing.NewRegistry("bundle")
+
+	const reason = "assigned to another shard than 1/2"
+	pass := &resultsjson.Result{Test: resultsjson.Test{Name: "pkg.Test1", Bundle: "bundle"}}
+	shardedOut := &resultsjson.Result{
+		Test:        resultsjson.Test{Name: "pkg.Test2", Bundle: "bundle"},
+		SkipReason:  reason,
+		SkipReasons: []resultsjson.SkipReason{{Code: "SHARDED_OUT", Details: []string{reason}}},
+	}
+
+	for _, tc := range []struct {
+		name   string
+		report bool
+		want   []*resultsjson.Result
+	}{
+		// Tests assigned to other shards are not reported by default so that
+		// they do not appear in merged results of shards repeatedly.
+		{"Default", false, []*resultsjson.Result{pass}},
+		{"Report", true, []*resultsjson.Result{pass, shardedOut}},
+	} {
+		t.Run(tc.name, func(t *gotesting.T) {
+			env := runtest.SetUp(t, runtest.WithLocalBundles(localReg), runtest.WithRemoteBundles(remoteReg))
+			ctx := env.Context()
+			cfg := env.Config(func(cfg *config.MutableConfig) {
+				cfg.TotalShards = 2
+				cfg.ShardIndex = 0
+				cfg.ShardMethod = "alpha"
+				cfg.ReportShardedOut = tc.report
+			})
+			state := env.State()
+
+			results, err := run.Run(ctx, cfg, state)
+			if err != nil {
+				t.Fatal("Run failed: ", err)
+			}
+			opts := append(resultsCmpOpts, cmpopts.IgnoreFields(resultsjson.Result{}, "OutDir"))
+			if diff := cmp.Diff(results, tc.want, opts...); diff != "" {
+				t.Errorf("Results mismatch (-got +want):\n%s", diff)
+			}
+
+			if b, err := os.ReadFile(filepath.Join(cfg.ResDir(), reporting.StreamedResultsFilename)); err != nil {
+				t.Errorf("Failed to read %s: %v", reporting.StreamedResultsFilename, err)
+			} else if results, err := unmarshalStreamedResults(b); err != nil {
+				t.Errorf("Failed to parse %s: %v", reporting.StreamedResultsFilename, err)
+			} else if diff := cmp.Diff(results, tc.want, opts...); diff != "" {
+				t.Errorf("%s mismatch (-got +want):\n%s", reporting.StreamedResultsFilename, diff)
+			}
+		})
+	}
+}
+
+func TestRunRepro(t *gotesting.T) {
+	localReg := testing.NewRegistry("bundle")
+	localReg.AddTestInstance(&testing.TestInstance{
+		Name:    "pkg.Fail",
+		Timeout: time.Minute,
+		Func: func(ctx context.Context, s *testing.State) {
+			s.Error("Failure")
+		},
+	})
+	localReg.AddTestInstance(&testing.TestInstance{
+		Name:    "pkg.Pass",
+		Timeout: time.Minute,
+		Func:    func(ctx context.Context, s *testing.State) {},
+	})
+
+	env := runtest.SetUp(t, runtest.WithLocalBundles(localReg))
+	ctx := env.Context()
+	cfg := env.Config(func(cfg *config.MutableConfig) {
+		cfg.Repro = true
+		cfg.TastVersion = "1.2.3"
+		cfg.TestVars = map[string]string{
+			"pkg.user":     "alice",
+			"pkg.password": "hunter2",
+		}
+	})
+	state := env.State()
+
+	if _, err := run.Run(ctx, cfg, state); err != nil {
+		t.Fatal("Run failed: ", err)
+	}
+
+	f, err := os.Open(filepath.Join(cfg.ResDir(), run.ReproFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]string)
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[hdr.Name] = string(b)
+	}
+
+	for _, name := range []string{
+		"repro/VERSION",
+		"repro/local_runner_args.txt",
+		"repro/remote_runner_args.txt",
+		"repro/bundle_args.txt",
+		"repro/run_config.txt",
+		"repro/features.txt",
+		"repro/tests.txt",
+		"repro/vars.yaml",
+		"repro/repro.sh",
+	} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("%s not found in %s", name, run.ReproFile)
+		}
+	}
+	for name, content := range files {
+		if strings.Contains(content, "hunter2") {
+			t.Errorf("%s contains a secret variable value:\n%s", name, content)
+		}
+	}
+	if got, want := files["repro/VERSION"], "1.2.3\n"; got != want {
+		t.Errorf("VERSION = %q; want %q", got, want)
+	}
+	if got, want := files["repro/tests.txt"], "pkg.Fail\npkg.Pass\nexample.Remote\n"; got != want {
+		t.Errorf("tests.txt = %q; want %q", got, want)
+	}
+	for name, wants := range map[string][]string{
+		// The resolved bundle config is included.
+		"repro/bundle_args.txt": {"meta_test_config", "pkg.user"},
+		// The resolved run config is included.
+		"repro/run_config.txt": {`"pkg.Fail"`, "pkg.user", "target"},
+	} {
+		for _, want := range wants {
+			if !strings.Contains(files[name], want) {
+				t.Errorf("%s does not contain %q:\n%s", name, want, files[name])
+			}
+		}
+	}
+	if got, want := files["repro/vars.yaml"], "pkg.user: alice\n"; got != want {
+		t.Errorf("vars.yaml = %q; want %q", got, want)
+	}
+	script := files["repro/repro.sh"]
+	for _, want := range []string{"pkg.password", "pkg.Fail pkg.Pass example.Remote", "-varsfile="} {
+		if !strings.Contains(script, want) {
+			t.Errorf("repro.sh does not contain %q:\n%s", want, script)
+		}
+	}
+}
+
+func TestRunReproNoFailures(t *gotesting.T) {
+	localReg := testing.NewRegistry("bundle")
+	localReg.AddTestInstance(&testing.TestInstance{
+		Name:    "pkg.Pass",
+		Timeout: time.Minute,
+		Func:    func(ctx context.Context, s *testing.State) {},
+	})
+
+	env := runtest.SetUp(t, runtest.WithLocalBundles(localReg))
+	ctx := env.Context()
+	cfg := env.Config(func(cfg *config.MutableConfig) {
+		cfg.Repro = true
+	})
+	state := env.State()
+
+	if _, err := run.Run(ctx, cfg, state); err != nil {
+		t.Fatal("Run failed: ", err)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.ResDir(), run.ReproFile)); !os.IsNotExist(err) {
+		t.Errorf("%s was written for a successful run (err=%v)", run.ReproFile, err)
+	}
+}
+
+func TestRunUpdateGolden(t *gotesting.T) {
+	// Tests in the "crosint" bundle live in the tast-tests-private workspace.
+	const pkg = "go.chromium.org/tast-tests-private/crosint/local/bundles/crosint/pkg"
+	localReg := testing.NewRegistry("crosint")
+	localReg.AddTestInstance(&testing.TestInstance{
+		Name:    "pkg.Golden",
+		Pkg:     pkg,
+		Timeout: time.Minute,
+		Func: func(ctx context.Context, s *testing.State) {
+			dir := filepath.Join(s.OutDir(), golden.OutSubdir, "sub")
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				s.Fatal("Failed to create directory: ", err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, "out.golden"), []byte("actual"), 0644); err != nil {
+				s.Fatal("Failed to write actual output: ", err)
+			}
+			s.Error("Output mismatches golden file")
+		},
+	})
+
+	// The local bundle is found by the name of the remote bundle.
+	env := runtest.SetUp(t, runtest.WithLocalBundles(localReg), runtest.WithRemoteBundles(testing.NewRegistry("crosint")))
+	ctx := env.Context()
+	trunkDir := filepath.Join(env.TempDir(), "trunk")
+	pkgDir := filepath.Join(trunkDir, "src/platform/tast-tests-private/src", pkg)
+	if err := os.MkdirAll(pkgDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := env.Config(func(cfg *config.MutableConfig) {
+		cfg.TrunkDir = trunkDir
+		cfg.BuildWorkspace = filepath.Join(env.TempDir(), "workspace")
+		cfg.UpdateGolden = true
+	})
+	state := env.State()
+
+	if _, err := run.Run(ctx, cfg, state); err != nil {
+		t.Fatal("Run failed: ", err)
+	}
+
+	b, err := os.ReadFile(filepath.Join(pkgDir, "data/sub/out.golden"))
+	if err != nil {
+		t.Fatal("Golden file not updated: ", err)
+	}
+	if got, want := string(b), "actual"; got != want {
+		t.Errorf("Updated golden file = %q; want %q", got, want)
+	}
+}
+
+// recordingReporter is a reporting.Reporter recording names of finished tests.
+type recordingReporter struct {
+	reporting.BaseReporter
+	started  []string
+	finished []string
+	complete bool
+}
+
+func (r *recordingReporter) RunStarted(ctx context.Context, run *reporting.RunInfo) error {
+	r.started = run.Tests
+	return nil
+}
+
+func (r *recordingReporter) TestFinished(ctx context.Context, result *resultsjson.Result) error {
+	r.finished = append(r.finished, result.Name)
+	return nil
+}
+
+func (r *recordingReporter) RunFinished(ctx context.Context, run *reporting.RunInfo, summary *reporting.RunSummary) error {
+	r.complete = summary.Complete
+	return nil
+}
+
+func TestRunCustomReporter(t *gotesting.T) {
+	reporter := &recordingReporter{}
+	reporting.RegisterReporter("test_recording", func() reporting.Reporter { return reporter })
+
+	localReg := testing.NewRegistry("bundle")
+	for _, name := range []string{"pkg.Test1", "pkg.Test2"} {
+		localReg.AddTestInstance(&testing.TestInstance{
+			Name:    name,
+			Timeout: time.Minute,
+			Func:    func(ctx context.Context, s *testing.State) {},
+		})
+	}
+
+	env := runtest.SetUp(t, runtest.WithLocalBundles(localReg))
+	ctx := env.Context()
+	cfg := env.Config(func(cfg *config.MutableConfig) {
+		cfg.Patterns = []string{"pkg.*"}
+		cfg.Reporters = []string{"test_recording"}
+	})
+	state := env.State()
+
+	if _, err := run.Run(ctx, cfg, state); err != nil {
+		t.Fatal("Run failed: ", err)
+	}
+
+	want := []string{"pkg.Test1", "pkg.Test2"}
+	if diff := cmp.Diff(reporter.started, want); diff != "" {
+		t.Errorf("Tests given to RunStarted mismatch (-got +want):\n%s", diff)
+	}
+	if diff := cmp.Diff(reporter.finished, want); diff != "" {
+		t.Errorf("Tests given to TestFinished mismatch (-got +want):\n%s", diff)
+	}
+	if !reporter.complete {
+		t.Error("RunFinished was not called for a complete run")
+	}
+	// Default reporters are disabled.
+	if _, err := os.Stat(filepath.Join(cfg.ResDir(), reporting.LegacyResultsFilename)); err == nil {
+		t.Errorf("%s was written by a disabled reporter", reporting.LegacyResultsFilename)
+	}
+}
+
+func TestRunUnknownReporter(t *gotesting.T) {
+	env := runtest.SetUp(t)
+	ctx := env.Context()
+	cfg := env.Config(func(cfg *config.MutableConfig) {
+		cfg.Reporters = []string{"unknown"}
+	})
+	state := env.State()
+
+	if _, err := run.Run(ctx, cfg, state); err == nil {
+		t.Error("Run unexpectedly succeeded with an unknown reporter")
+	}
+}
+
+func TestRunRepeatUntilFail(t *gotesting.T) {
+	runs := 0
+	localReg := testing.NewRegistry("bundle")
+	localReg.AddTestInstance(&testing.TestInstance{
+		Name:    "pkg.Flaky",
+		Timeout: time.Minute,
+		Func: func(ctx context.Context, s *testing.State) {
+			runs++
+			if runs == 3 {
+				s.Error("Failure")
+			}
+		},
+	})
+
+	env := runtest.SetUp(t, runtest.WithLocalBundles(localReg))
+	ctx := env.Context()
+	cfg := env.Config(func(cfg *config.MutableConfig) {
+		cfg.Patterns = []string{"pkg.Flaky"}
+		cfg.Repeat = 5
+		cfg.RepeatUntilFail = true
+	})
+	state := env.State()
+
+	results, err := run.Run(ctx, cfg, state)
+	if err != nil {
+		t.Fatal("Run failed: ", err)
+	}
+	if len(results) != 3 {
+		t.Errorf("Run returned %d results; want 3", len(results))
+	}
+
+	for i := 1; i <= 3; i++ {
+		if _, err := os.Stat(filepath.Join(cfg.ResDir(), fmt.Sprintf("iteration_%d", i), "tests", "pkg.Flaky")); err != nil {
+			t.Errorf("Output directory of iteration %d not found: %v", i, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(cfg.ResDir(), "iteration_4")); !os.IsNotExist(err) {
+		t.Errorf("Iteration 4 was run after a failure: %v", err)
+	}
+
+	b, err := os.ReadFile(filepath.Join(cfg.ResDir(), run.RepeatSummaryFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sum run.RepeatSummary
+	if err := json.Unmarshal(b, &sum); err != nil {
+		t.Fatal(err)
+	}
+	want := run.RepeatSummary{
+		Iterations: 3,
+		Tests:      []*run.RepeatTestSummary{{Name: "pkg.Flaky", Runs: 3, Passes: 2, FirstFailure: 3}},
+	}
+	if diff := cmp.Diff(sum, want); diff != "" {
+		t.Errorf("%s mismatch (-got +want):\n%s", run.RepeatSummaryFile, diff)
+	}
+}
+
+func TestRunManifestResume(t *gotesting.T) {
+	localReg := testing.NewRegistry("bundle")
+	fail := true
+	localReg.AddTestInstance(&testing.TestInstance{
+		Name:    "pkg.Flaky",
+		Timeout: time.Minute,
+		Func: func(ctx context.Context, s *testing.State) {
+			if fail {
+				s.Error("Failure")
+			}
+		},
+	})
+	localReg.AddTestInstance(&testing.TestInstance{
+		Name:    "pkg.Pass",
+		Timeout: time.Minute,
+		Func:    func(ctx context.Context, s *testing.State) {},
+	})
+
+	env := runtest.SetUp(t, runtest.WithLocalBundles(localReg))
+	ctx := env.Context()
+	cfg := env.Config(func(cfg *config.MutableConfig) {
+		cfg.Args = []string{"-build=false"}
+	})
+
+	if _, err := run.Run(ctx, cfg, env.State()); err != nil {
+		t.Fatal("Run failed: ", err)
+	}
+
+	m, err := run.ReadManifest(cfg.ResDir())
+	if err != nil {
+		t.Fatal("ReadManifest failed: ", err)
+	}
+	wantPlanned := []string{"pkg.Flaky", "pkg.Pass", "example.Remote"}
+	if diff := cmp.Diff(m.Planned, wantPlanned); diff != "" {
+		t.Errorf("Planned tests mismatch (-got +want):\n%s", diff)
+	}
+	if diff := cmp.Diff(m.Args, []string{"-build=false"}); diff != "" {
+		t.Errorf("Args mismatch (-got +want):\n%s", diff)
+	}
+	if diff := cmp.Diff(m.Completed, []string{"example.Remote", "pkg.Pass"}); diff != "" {
+		t.Errorf("Completed tests mismatch (-got +want):\n%s", diff)
+	}
+
+	prev, err := run.CompletedResults(cfg.ResDir(), m)
+	if err != nil {
+		t.Fatal("CompletedResults failed: ", err)
+	}
+	var prevNames []string
+	for _, r := range prev {
+		prevNames = append(prevNames, r.Name)
+	}
+	if diff := cmp.Diff(prevNames, []string{"pkg.Pass", "example.Remote"}); diff != "" {
+		t.Errorf("CompletedResults mismatch (-got +want):\n%s", diff)
+	}
+
+	// Resume the run, which should only run the failed test.
+	fail = false
+	cfg = env.Config(func(cfg *config.MutableConfig) {
+		cfg.Patterns = []string{"pkg.Flaky"}
+		cfg.Resume = true
+		cfg.PreviousResults = prev
+	})
+	results, err := run.Run(ctx, cfg, env.State())
+	if err != nil {
+		t.Fatal("Run failed: ", err)
+	}
+	var names []string
+	for _, r := range results {
+		if len(r.Errors) > 0 {
+			t.Errorf("%s failed unexpectedly: %v", r.Name, r.Errors)
+		}
+		names = append(names, r.Name)
+	}
+	if diff := cmp.Diff(names, []string{"pkg.Pass", "example.Remote", "pkg.Flaky"}); diff != "" {
+		t.Errorf("Results mismatch (-got +want):\n%s", diff)
+	}
+
+	m, err = run.ReadManifest(cfg.ResDir())
+	if err != nil {
+		t.Fatal("ReadManifest failed: ", err)
+	}
+	if diff := cmp.Diff(m.Planned, wantPlanned); diff != "" {
+		t.Errorf("Planned tests mismatch after resume (-got +want):\n%s", diff)
+	}
+	if diff := cmp.Diff(m.Completed, []string{"example.Remote", "pkg.Flaky", "pkg.Pass"}); diff != "" {
+		t.Errorf("Completed tests mismatch after resume (-got +want):\n%s", diff)
+	}
+}
+
+func TestRunDUTProviderReplacesUnhealthyDUT(t *gotesting.T) {
+	localReg := testing.NewRegistry("bundle")
+	localReg.AddTestInstance(&testing.TestInstance{
+		Name:    "pkg.Pass",
+		Timeout: time.Minute,
+		Func:    func(ctx context.Context, s *testing.State) {},
+	})
+
+	env := runtest.SetUp(t, runtest.WithLocalBundles(localReg))
+	ctx := env.Context()
+	good := env.Config(nil).Target()
+	// The first DUT in the pool has a malformed target, so connecting to it
+	// fails quickly.
+	const bad = "root@bad@dut"
+	pool := filepath.Join(env.TempDir(), "pool")
+	if err := os.WriteFile(pool, []byte(bad+"\n"+good+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := env.Config(func(cfg *config.MutableConfig) {
+		cfg.Target = ""
+		cfg.DUTProvider = "file:" + pool
+		cfg.DUTReplacements = 1
+	})
+
+	results, err := run.Run(ctx, cfg, env.State())
+	if err != nil {
+		t.Fatal("Run failed: ", err)
+	}
+	var names []string
+	for _, r := range results {
+		if len(r.Errors) > 0 {
+			t.Errorf("%s failed unexpectedly: %v", r.Name, r.Errors)
+		}
+		names = append(names, r.Name)
+	}
+	if diff := cmp.Diff(names, []string{"pkg.Pass", "example.Remote"}); diff != "" {
+		t.Errorf("Results mismatch (-got +want):\n%s", diff)
+	}
+
+	// The unhealthy DUT should be kept out of the pool, and the healthy one
+	// should be released.
+	leases, err := os.ReadDir(pool + ".leases")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var leaseNames []string
+	for _, fi := range leases {
+		leaseNames = append(leaseNames, fi.Name())
+	}
+	if diff := cmp.Diff(leaseNames, []string{bad + ".unhealthy"}); diff != "" {
+		t.Errorf("Lease files mismatch (-got +want):\n%s", diff)
+	}
+}
+
 func TestRunGetGlobalRuntimeVars(t *gotesting.T) {
 	localReg := testing.NewRegistry("bundle")
 	var1 := testing.NewVarString("var1", "", "description")
@@ -1009,7 +1670,7 @@ func TestRunListTests(t *gotesting.T) {
 	expected := []*resultsjson.Result{
 		{Test: *localTestMeta},
 		{Test: *remoteTestMeta},
-		{Test: *skippedTestMeta, SkipReason: "missing SoftwareDeps: missing"},
+		{Test: *skippedTestMeta, SkipReason: "missing SoftwareDeps: missing", SkipReasons: []resultsjson.SkipReason{{Code: "UNSATISFIED_SWDEP", Details: []string{"missing SoftwareDeps: missing"}}}},
 	}
 	if diff := cmp.Diff(results, expected); diff != "" {
 		t.Errorf("Unexpected results (-got +want):\n%s", diff)
@@ -1078,7 +1739,7 @@ func TestRunListTestsWithSharding(t *gotesting.T) {
 	for shardIndex, expected := range [][]*resultsjson.Result{
 		{
 			{Test: *localTestMeta},
-			{Test: *skippedTestMeta, SkipReason: "missing SoftwareDeps: missing"},
+			{Test: *skippedTestMeta, SkipReason: "missing SoftwareDeps: missing", SkipReasons: []resultsjson.SkipReason{{Code: "UNSATISFIED_SWDEP", Details: []string{"missing SoftwareDeps: missing"}}}},
 		},
 		{
 			{Test: *remoteTestMeta},
@@ -1110,7 +1771,7 @@ func TestRunListTestsWithSharding(t *gotesting.T) {
 			{Test: *remoteTestMeta},
 		},
 		{
-			{Test: *skippedTestMeta, SkipReason: "missing SoftwareDeps: missing"},
+			{Test: *skippedTestMeta, SkipReason: "missing SoftwareDeps: missing", SkipReasons: []resultsjson.SkipReason{{Code: "UNSATISFIED_SWDEP", Details: []string{"missing SoftwareDeps: missing"}}}},
 		},
 	} {
 		t.Run(fmt.Sprintf("shard%d", shardIndex), func(t *gotesting.T) {
@@ -1318,6 +1979,47 @@ func TestRunWithVerifyTestPatternRuns(t *gotesting.T) {
 	}
 }
 
+func TestRunTestList(t *gotesting.T) {
+	localReg := testing.NewRegistry("bundle")
+	for _, name := range []string{"pkg.A", "pkg.B", "other.C"} {
+		localReg.AddTestInstance(&testing.TestInstance{
+			Name:    name,
+			Attr:    []string{"group:x"},
+			Timeout: time.Minute,
+			Func:    func(ctx context.Context, s *testing.State) {},
+		})
+	}
+	localReg.AddTestInstance(&testing.TestInstance{
+		Name:    "pkg.D",
+		Timeout: time.Minute,
+		Func: func(ctx context.Context, s *testing.State) {
+			t.Error("pkg.D was run despite not matching the attribute expression")
+		},
+	})
+
+	env := runtest.SetUp(t, runtest.WithLocalBundles(localReg))
+	ctx := env.Context()
+	cfg := env.Config(func(cfg *config.MutableConfig) {
+		cfg.Patterns = []string{`("group:x")`}
+		cfg.TestList = []string{"pkg.*", "other.C"}
+		cfg.ExcludeList = []string{"pkg.B"}
+	})
+	state := env.State()
+
+	results, err := run.Run(ctx, cfg, state)
+	if err != nil {
+		t.Fatal("Run failed: ", err)
+	}
+	var names []string
+	for _, r := range results {
+		names = append(names, r.Name)
+	}
+	sort.Strings(names)
+	if diff := cmp.Diff(names, []string{"other.C", "pkg.A"}); diff != "" {
+		t.Errorf("Tests run mismatch (-got +want):\n%s", diff)
+	}
+}
+
 func TestRunDisable(t *gotesting.T) {
 	localPass := &testing.TestInstance{
 		Name:    "local.Pass",
@@ -1403,8 +2105,9 @@ func TestRunDisable(t *gotesting.T) {
 				Timeout: time.Minute,
 				Bundle:  "bundle",
 			},
-			OutDir:     filepath.Join(cfg.ResDir(), "tests/local.Disabled"),
-			SkipReason: reason1,
+			OutDir:      filepath.Join(cfg.ResDir(), "tests/local.Disabled"),
+			SkipReason:  reason1,
+			SkipReasons: []resultsjson.SkipReason{{Code: "MANUAL", Details: []string{reason1}}},
 		},
 		{
 			Test: resultsjson.Test{
@@ -1429,8 +2132,9 @@ func TestRunDisable(t *gotesting.T) {
 				Timeout: time.Minute,
 				Bundle:  "bundle",
 			},
-			OutDir:     filepath.Join(cfg.ResDir(), "tests/remote.Disabled"),
-			SkipReason: reason2,
+			OutDir:      filepath.Join(cfg.ResDir(), "tests/remote.Disabled"),
+			SkipReason:  reason2,
+			SkipReasons: []resultsjson.SkipReason{{Code: "MANUAL", Details: []string{reason2}}},
 		},
 		{
 			Test: resultsjson.Test{
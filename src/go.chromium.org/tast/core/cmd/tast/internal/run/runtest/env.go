@@ -20,6 +20,7 @@ import (
 	"go.chromium.org/tast/core/internal/fakesshserver"
 	"go.chromium.org/tast/core/internal/logging"
 	"go.chromium.org/tast/core/internal/logging/loggingtest"
+	"go.chromium.org/tast/core/internal/run/reporting"
 	"go.chromium.org/tast/core/internal/runner"
 )
 
@@ -200,6 +201,8 @@ func (e *Env) Config(mod func(cfg *config.MutableConfig)) *config.Config {
 		// Enable typical pre-flight checks.
 		CheckTestDeps:  true,
 		CollectSysInfo: true,
+		// Write results as usual.
+		Reporters: reporting.DefaultReporters,
 		// This is the only shard.
 		TotalShards: 1,
 		// Fill info to access fake SSH servers.
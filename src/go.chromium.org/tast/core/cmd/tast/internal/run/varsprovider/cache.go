@@ -0,0 +1,94 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package varsprovider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// cacheEntry is the content of a cache file.
+type cacheEntry struct {
+	FetchTime time.Time         `json:"fetchTime"`
+	Vars      map[string]string `json:"vars"`
+}
+
+// cachePath returns the path to the cache file for a provider spec in dir.
+func cachePath(dir, spec string) string {
+	h := sha256.Sum256([]byte(spec))
+	return filepath.Join(dir, hex.EncodeToString(h[:])+".json")
+}
+
+// cachedProvider is a Provider that caches variables of another Provider in a
+// file only readable by the current user.
+type cachedProvider struct {
+	p    Provider
+	path string
+	ttl  time.Duration
+	now  func() time.Time
+}
+
+func newCachedProvider(p Provider, path string, ttl time.Duration) *cachedProvider {
+	return &cachedProvider{p: p, path: path, ttl: ttl, now: time.Now}
+}
+
+func (c *cachedProvider) String() string {
+	return c.p.String()
+}
+
+// Vars returns cached variables if they are fresh enough. Otherwise it fetches
+// variables from the underlying provider and updates the cache.
+func (c *cachedProvider) Vars(ctx context.Context) (map[string]string, error) {
+	if e, err := c.read(); err == nil && c.now().Sub(e.FetchTime) < c.ttl {
+		return e.Vars, nil
+	}
+	vars, err := c.p.Vars(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Failing to update the cache is not fatal.
+	c.write(&cacheEntry{FetchTime: c.now(), Vars: vars})
+	return vars, nil
+}
+
+func (c *cachedProvider) read() (*cacheEntry, error) {
+	b, err := os.ReadFile(c.path)
+	if err != nil {
+		return nil, err
+	}
+	var e cacheEntry
+	if err := json.Unmarshal(b, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (c *cachedProvider) write(e *cacheEntry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0700); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(c.path), "."+filepath.Base(c.path)+".")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), c.path)
+}
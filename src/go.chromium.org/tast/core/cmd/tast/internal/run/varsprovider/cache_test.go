@@ -0,0 +1,80 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package varsprovider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type fakeProvider struct {
+	vars  map[string]string
+	calls int
+}
+
+func (p *fakeProvider) String() string { return "fake" }
+
+func (p *fakeProvider) Vars(ctx context.Context) (map[string]string, error) {
+	p.calls++
+	return p.vars, nil
+}
+
+func TestCachedProvider(t *testing.T) {
+	td := t.TempDir()
+	path := cachePath(filepath.Join(td, "cache"), "fake:x")
+
+	fp := &fakeProvider{vars: map[string]string{"a": "1"}}
+	now := time.Unix(1000, 0)
+	c := newCachedProvider(fp, path, time.Hour)
+	c.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	for i, tc := range []struct {
+		elapsed   time.Duration
+		wantCalls int
+	}{
+		{0, 1},                       // cache miss
+		{30 * time.Minute, 1},        // cache hit
+		{time.Hour + time.Minute, 2}, // cache expired
+	} {
+		now = time.Unix(1000, 0).Add(tc.elapsed)
+		vars, err := c.Vars(ctx)
+		if err != nil {
+			t.Fatalf("#%d: Vars failed: %v", i, err)
+		}
+		if vars["a"] != "1" {
+			t.Errorf("#%d: Vars returned %v; want a=1", i, vars)
+		}
+		if fp.calls != tc.wantCalls {
+			t.Errorf("#%d: underlying provider called %d time(s); want %d", i, fp.calls, tc.wantCalls)
+		}
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := fi.Mode().Perm(); perm != 0600 {
+		t.Errorf("Cache file has permission %o; want 600", perm)
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, spec := range []string{"gsm", "gsm:", "foo:bar"} {
+		if _, err := New(spec, t.TempDir(), time.Hour); err == nil {
+			t.Errorf("New(%q) unexpectedly succeeded", spec)
+		}
+	}
+	p, err := New("gsm:myproj", t.TempDir(), 0)
+	if err != nil {
+		t.Fatal("New failed: ", err)
+	}
+	if s := p.String(); s != "gsm:myproj" {
+		t.Errorf("String() = %q; want %q", s, "gsm:myproj")
+	}
+}
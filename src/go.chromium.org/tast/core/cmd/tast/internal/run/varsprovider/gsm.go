@@ -0,0 +1,147 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package varsprovider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+
+	"go.chromium.org/tast/core/errors"
+)
+
+const (
+	// secretManagerEndpoint is the base URL of the Secret Manager REST API.
+	secretManagerEndpoint = "https://secretmanager.googleapis.com/v1"
+
+	// VarAnnotation is the name of the secret annotation holding the name of
+	// the runtime variable a secret provides.
+	VarAnnotation = "tast-var"
+)
+
+// SecretManager is a Provider that fetches secret variables from Google Secret
+// Manager. Every secret in the project annotated with VarAnnotation provides
+// the runtime variable named by the annotation, and its latest version is
+// used as the value.
+//
+// Credentials are obtained from the gcloud command.
+type SecretManager struct {
+	project  string
+	endpoint string
+	client   *http.Client
+	token    func(ctx context.Context) (string, error)
+}
+
+// NewSecretManager creates a SecretManager fetching secrets in project.
+func NewSecretManager(project string) *SecretManager {
+	return &SecretManager{
+		project:  project,
+		endpoint: secretManagerEndpoint,
+		client:   http.DefaultClient,
+		token:    gcloudAccessToken,
+	}
+}
+
+// String returns a description of the provider.
+func (s *SecretManager) String() string {
+	return "gsm:" + s.project
+}
+
+// Vars fetches secret variables from Secret Manager.
+func (s *SecretManager) Vars(ctx context.Context) (map[string]string, error) {
+	token, err := s.token(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get access token")
+	}
+
+	names := make(map[string]string) // var name -> secret resource name
+	pageToken := ""
+	for {
+		q := url.Values{}
+		if pageToken != "" {
+			q.Set("pageToken", pageToken)
+		}
+		var res struct {
+			Secrets []struct {
+				Name        string            `json:"name"`
+				Annotations map[string]string `json:"annotations"`
+			} `json:"secrets"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		u := fmt.Sprintf("%s/projects/%s/secrets?%s", s.endpoint, url.PathEscape(s.project), q.Encode())
+		if err := s.get(ctx, u, token, &res); err != nil {
+			return nil, errors.Wrap(err, "failed to list secrets")
+		}
+		for _, sec := range res.Secrets {
+			v := sec.Annotations[VarAnnotation]
+			if v == "" {
+				continue
+			}
+			if prev, ok := names[v]; ok {
+				return nil, errors.Errorf("variable %s is provided by both %s and %s", v, prev, sec.Name)
+			}
+			names[v] = sec.Name
+		}
+		if res.NextPageToken == "" {
+			break
+		}
+		pageToken = res.NextPageToken
+	}
+
+	vars := make(map[string]string)
+	for v, name := range names {
+		var res struct {
+			Payload struct {
+				Data string `json:"data"`
+			} `json:"payload"`
+		}
+		if err := s.get(ctx, fmt.Sprintf("%s/%s/versions/latest:access", s.endpoint, name), token, &res); err != nil {
+			return nil, errors.Wrapf(err, "failed to access %s", name)
+		}
+		data, err := base64.StdEncoding.DecodeString(res.Payload.Data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode %s", name)
+		}
+		vars[v] = string(data)
+	}
+	return vars, nil
+}
+
+// get sends a GET request to u and decodes a JSON response into out.
+func (s *SecretManager) get(ctx context.Context, u, token string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return errors.Errorf("%s: %s", res.Status, strings.TrimSpace(string(b)))
+	}
+	return json.NewDecoder(res.Body).Decode(out)
+}
+
+// gcloudAccessToken returns an access token of the active gcloud account.
+func gcloudAccessToken(ctx context.Context) (string, error) {
+	out, err := exec.CommandContext(ctx, "gcloud", "auth", "print-access-token").Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok {
+			return "", errors.Errorf("gcloud: %v: %s", err, strings.TrimSpace(string(ee.Stderr)))
+		}
+		return "", errors.Wrap(err, "gcloud")
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package varsprovider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSecretManagerVars(t *testing.T) {
+	const token = "token"
+	payloads := map[string]string{
+		"projects/123/secrets/password": "hunter2",
+		"projects/123/secrets/apikey":   "xyzzy",
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/projects/myproj/secrets", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("pageToken") {
+		case "":
+			fmt.Fprint(w, `{"secrets": [
+				{"name": "projects/123/secrets/password", "annotations": {"tast-var": "example.password"}},
+				{"name": "projects/123/secrets/unrelated"}
+			], "nextPageToken": "next"}`)
+		case "next":
+			fmt.Fprint(w, `{"secrets": [
+				{"name": "projects/123/secrets/apikey", "annotations": {"tast-var": "example.apiKey"}}
+			]}`)
+		default:
+			http.Error(w, "bad page token", http.StatusBadRequest)
+		}
+	})
+	mux.HandleFunc("/projects/123/secrets/", func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Path[1 : len(r.URL.Path)-len("/versions/latest:access")]
+		p, ok := payloads[name]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"name": %q, "payload": {"data": %q}}`, name, base64.StdEncoding.EncodeToString([]byte(p)))
+	})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	s := NewSecretManager("myproj")
+	s.endpoint = srv.URL
+	s.token = func(ctx context.Context) (string, error) { return token, nil }
+
+	got, err := s.Vars(context.Background())
+	if err != nil {
+		t.Fatal("Vars failed: ", err)
+	}
+	want := map[string]string{
+		"example.password": "hunter2",
+		"example.apiKey":   "xyzzy",
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Vars returned unexpected vars (-got +want):\n%s", diff)
+	}
+
+	s.token = func(ctx context.Context) (string, error) { return "wrong", nil }
+	if _, err := s.Vars(context.Background()); err == nil {
+		t.Error("Vars unexpectedly succeeded with a wrong token")
+	}
+}
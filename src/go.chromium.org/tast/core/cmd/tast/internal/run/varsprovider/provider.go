@@ -0,0 +1,47 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package varsprovider provides runtime variables to tests from sources other
+// than YAML files, e.g. secret stores.
+package varsprovider
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.chromium.org/tast/core/errors"
+)
+
+// Provider provides runtime variables from an external source.
+type Provider interface {
+	// String returns a description of the provider used in logs and errors.
+	String() string
+	// Vars returns runtime variables provided by the provider.
+	Vars(ctx context.Context) (map[string]string, error)
+}
+
+// New creates a Provider from spec of the form "<kind>:<arg>". Supported
+// kinds are:
+//
+//	gsm:<project>  Google Secret Manager secrets in the GCP project
+//
+// If ttl is positive, variables are cached under cacheDir for the duration.
+func New(spec, cacheDir string, ttl time.Duration) (Provider, error) {
+	parts := strings.SplitN(spec, ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return nil, errors.Errorf("invalid vars provider %q: want \"<kind>:<arg>\"", spec)
+	}
+	var p Provider
+	switch kind, arg := parts[0], parts[1]; kind {
+	case "gsm":
+		p = NewSecretManager(arg)
+	default:
+		return nil, errors.Errorf("invalid vars provider %q: unknown kind %q", spec, kind)
+	}
+	if ttl > 0 {
+		p = newCachedProvider(p, cachePath(cacheDir, spec), ttl)
+	}
+	return p, nil
+}
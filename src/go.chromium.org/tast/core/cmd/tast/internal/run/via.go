@@ -0,0 +1,407 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package run
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"go.chromium.org/tast/core/ctxutil"
+	"go.chromium.org/tast/core/errors"
+
+	"go.chromium.org/tast/core/cmd/tast/internal/run/config"
+	"go.chromium.org/tast/core/internal/linuxssh"
+	"go.chromium.org/tast/core/internal/logging"
+	"go.chromium.org/tast/core/internal/run/reporting"
+	"go.chromium.org/tast/core/internal/run/resultsjson"
+	"go.chromium.org/tast/core/ssh"
+)
+
+const (
+	// viaCacheDir is a directory on the -via host, relative to the home
+	// directory, where the tast command and test bundles are deployed. It is
+	// kept across runs so that unchanged files are not sent again.
+	viaCacheDir = ".cache/tast/via"
+
+	// fullLogName is a file name under ResDir to which the run subcommand
+	// writes its full log.
+	fullLogName = "full.txt"
+
+	// viaFullLogName is a file name under ResDir to which the full log of the
+	// tast command run on the -via host is saved.
+	viaFullLogName = "via_full.txt"
+
+	// viaCleanupTimeout is the time reserved for pulling results from the
+	// -via host and cleaning up there.
+	viaCleanupTimeout = time.Minute
+)
+
+// viaDirs is directories on the -via host.
+type viaDirs struct {
+	cache string // directory where executables and data files are deployed
+	run   string // directory for files specific to the current run
+}
+
+func (d *viaDirs) tast() string            { return path.Join(d.cache, "tast") }
+func (d *viaDirs) remoteRunner() string    { return path.Join(d.cache, "remote_test_runner") }
+func (d *viaDirs) remoteBundleDir() string { return path.Join(d.cache, "bundles") }
+func (d *viaDirs) remoteDataDir() string   { return path.Join(d.cache, "data") }
+func (d *viaDirs) varsDir() string         { return path.Join(d.run, "vars") }
+func (d *viaDirs) listsDir() string        { return path.Join(d.run, "lists") }
+func (d *viaDirs) resDir() string          { return path.Join(d.run, "results") }
+
+// runVia runs tests per cfg by the tast command run on cfg.Via, for DUTs
+// reachable only from there. The tast command itself and remote test bundles
+// with their data files are deployed to the host before running it, and
+// results are moved to cfg.ResDir afterwards.
+//
+// The SSH key to connect to DUTs is never copied to the host. It is served to
+// the tast command there by an SSH agent forwarded from this process.
+func runVia(ctx context.Context, cfg *config.Config, state *config.DeprecatedState) ([]*resultsjson.Result, error) {
+	if cfg.Resume() {
+		return nil, errors.New("runs with -via can not be resumed")
+	}
+
+	conn, err := connectVia(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close(ctx)
+
+	dirs, err := prepareViaDirs(ctx, conn)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to prepare directories on %s", cfg.Via())
+	}
+
+	cleanupCtx := ctx
+	ctx, cancel := ctxutil.Shorten(ctx, viaCleanupTimeout)
+	defer cancel()
+	defer func() {
+		if err := conn.CommandContext(cleanupCtx, "rm", "-rf", "--", dirs.run).Run(); err != nil {
+			logging.Infof(cleanupCtx, "Failed to clean up %s on %s: %v", dirs.run, cfg.Via(), err)
+		}
+	}()
+
+	args, lists, err := viaRunArgs(cfg.Args(), dirs)
+	if err != nil {
+		return nil, err
+	}
+	if err := deployVia(ctx, cfg, conn, dirs, lists); err != nil {
+		return nil, errors.Wrapf(err, "failed to deploy tast to %s", cfg.Via())
+	}
+
+	args = append(args, cfg.Target())
+	args = append(args, cfg.Patterns()...)
+	logging.Infof(ctx, "Running tast on %s", cfg.Via())
+	runErr := runViaCommand(ctx, conn, dirs, args)
+
+	// Results are pulled even if the run failed in the middle.
+	if err := pullViaResults(cleanupCtx, cfg, conn, dirs); err != nil {
+		if runErr != nil {
+			return nil, errors.Wrapf(runErr, "tast failed on %s (failed to pull results: %v)", cfg.Via(), err)
+		}
+		return nil, errors.Wrapf(err, "failed to pull results from %s", cfg.Via())
+	}
+	results, err := readViaResults(cfg.ResDir())
+	if err != nil {
+		return nil, err
+	}
+	if runErr != nil {
+		return results, errors.Wrapf(runErr, "tast failed on %s", cfg.Via())
+	}
+	return results, nil
+}
+
+// connectVia connects to cfg.Via and forwards an SSH agent holding the key
+// to connect to DUTs.
+func connectVia(ctx context.Context, cfg *config.Config) (*ssh.Conn, error) {
+	var o ssh.Options
+	if err := ssh.ParseTarget(cfg.Via(), &o); err != nil {
+		return nil, err
+	}
+	o.KeyFile = cfg.KeyFile()
+	o.KeyDir = cfg.KeyDir()
+	o.ConnectRetries = cfg.SSHRetries()
+	o.WarnFunc = func(msg string) { logging.Info(ctx, msg) }
+	conn, err := ssh.New(ctx, &o)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to %s", cfg.Via())
+	}
+	keyring, err := ssh.NewKeyring(cfg.KeyFile())
+	if err != nil {
+		conn.Close(ctx)
+		return nil, err
+	}
+	if err := conn.ForwardAgent(keyring); err != nil {
+		conn.Close(ctx)
+		return nil, errors.Wrapf(err, "failed to forward SSH agent to %s", cfg.Via())
+	}
+	return conn, nil
+}
+
+// prepareViaDirs creates directories on the -via host. Both directories are
+// accessible only by the user since runtime variables are saved there.
+func prepareViaDirs(ctx context.Context, conn *ssh.Conn) (*viaDirs, error) {
+	out, err := conn.CommandContext(ctx, "sh", "-c",
+		`d="$HOME/$1" && mkdir -p -m 0700 "$d" && echo "$d" && mktemp -d`,
+		"sh", viaCacheDir).Output()
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(lines) != 2 {
+		return nil, errors.Errorf("unexpected output %q", out)
+	}
+	return &viaDirs{cache: lines[0], run: lines[1]}, nil
+}
+
+// deployVia pushes the tast command, remote test bundles, their data files,
+// test list files and runtime variables to the -via host. lists maps paths of
+// test list files on this machine to paths on the host.
+func deployVia(ctx context.Context, cfg *config.Config, conn *ssh.Conn, dirs *viaDirs, lists map[string]string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	files := map[string]string{
+		// The tast command itself is the agent run on the host.
+		exe:                dirs.tast(),
+		cfg.RemoteRunner(): dirs.remoteRunner(),
+	}
+	if err := addViaDirFiles(files, cfg.RemoteBundleDir(), dirs.remoteBundleDir()); err != nil {
+		return err
+	}
+	if err := addViaDirFiles(files, cfg.RemoteDataDir(), dirs.remoteDataDir()); err != nil {
+		return err
+	}
+	for src, dst := range lists {
+		files[src] = dst
+	}
+	logging.Infof(ctx, "Deploying tast to %s", cfg.Via())
+	n, err := linuxssh.PutFiles(ctx, conn, files, linuxssh.DereferenceSymlinks)
+	if err != nil {
+		return err
+	}
+	logging.Debugf(ctx, "Deployed %d bytes to %s", n, cfg.Via())
+
+	// Variables are resolved here, including ones from -varsprovider, since
+	// files and credentials they come from are not on the host.
+	vars, err := yaml.Marshal(cfg.TestVars())
+	if err != nil {
+		return err
+	}
+	cmd := conn.CommandContext(ctx, "sh", "-c", `umask 077 && mkdir -p "$1" && cat > "$1/vars.yaml"`, "sh", dirs.varsDir())
+	cmd.Stdin = bytes.NewReader(vars)
+	return cmd.Run()
+}
+
+// addViaDirFiles adds regular files under the directory src to files, to be
+// pushed under dst. Files are added one by one rather than the directory as a
+// whole so that unchanged files are not sent again. It does nothing if src
+// does not exist.
+func addViaDirFiles(files map[string]string, src, dst string) error {
+	err := filepath.Walk(src, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		files[p] = path.Join(dst, filepath.ToSlash(rel))
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// runViaCommand runs the tast command on the -via host with args given to the
+// run subcommand. Its output is logged as it is.
+func runViaCommand(ctx context.Context, conn *ssh.Conn, dirs *viaDirs, args []string) error {
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		sc := bufio.NewScanner(pr)
+		for sc.Scan() {
+			logging.Info(ctx, sc.Text())
+		}
+		io.Copy(io.Discard, pr)
+	}()
+
+	cmd := conn.CommandContext(ctx, dirs.tast(), append([]string{"-logtime=false", "run"}, args...)...)
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+	err := cmd.Run()
+	pw.Close()
+	<-done
+	return err
+}
+
+// pullViaResults moves results written by the tast command on the -via host
+// to cfg.ResDir. The full log is saved as viaFullLogName not to overwrite the
+// one of this command.
+func pullViaResults(ctx context.Context, cfg *config.Config, conn *ssh.Conn, dirs *viaDirs) error {
+	if err := conn.CommandContext(ctx, "sh", "-c", `[ ! -e "$1/$2" ] || mv -f "$1/$2" "$1/$3"`,
+		"sh", dirs.resDir(), fullLogName, viaFullLogName).Run(); err != nil {
+		return err
+	}
+	if err := linuxssh.GetAndDeleteFilesInDir(ctx, conn, dirs.resDir(), cfg.ResDir(), linuxssh.PreserveSymlinks); err != nil {
+		return err
+	}
+
+	// Record the flags given to this command in the manifest, so that the
+	// run is reproduced with -via.
+	m, err := ReadManifest(cfg.ResDir())
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	m.Args = cfg.Args()
+	m.Target = cfg.Target()
+	return writeManifest(cfg.ResDir(), m)
+}
+
+// readViaResults reads results of tests written to resDir by the tast command
+// on the -via host. It returns no result if the command failed before writing
+// results.
+func readViaResults(resDir string) ([]*resultsjson.Result, error) {
+	b, err := os.ReadFile(filepath.Join(resDir, reporting.LegacyResultsFilename))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var results []*resultsjson.Result
+	if err := json.Unmarshal(b, &results); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", reporting.LegacyResultsFilename)
+	}
+	return results, nil
+}
+
+// viaRunArgs translates args given to the run subcommand to ones given to the
+// run subcommand of the tast command on the -via host, excluding the target
+// and patterns. Flags referring to files on this machine are replaced with
+// ones referring to files deployed under dirs, and test list files to push
+// are returned as a map from paths on this machine to ones on the host.
+func viaRunArgs(args []string, dirs *viaDirs) ([]string, map[string]string, error) {
+	flags, err := splitFlagArgs(args, runFlagSet())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var out []string
+	lists := make(map[string]string)
+	for _, f := range flags {
+		switch f.name {
+		case "via", "failfortests", "resultsdir", "remoteoutdir", "build", "keyfile", "keydir",
+			"remoterunner", "remotebundledir", "remotedatadir",
+			"varsfile", "defaultvarsdir", "varsprovider", "varsprovidercachettl":
+			// These are replaced below, or not applicable to the host.
+			// Exit status is checked by this command for -failfortests.
+			continue
+		case "var":
+			// Variables except scoped ones are passed with others in a file.
+			if name, _, _ := strings.Cut(f.value, "="); !strings.Contains(name, ":") {
+				continue
+			}
+		case "testlist", "excludelist", "testfilterfile":
+			dst, ok := lists[f.value]
+			if !ok {
+				dst = path.Join(dirs.listsDir(), fmt.Sprintf("%d_%s", len(lists), filepath.Base(f.value)))
+				lists[f.value] = dst
+			}
+			f.value = dst
+		}
+		out = append(out, f.String())
+	}
+	out = append(out,
+		"-build=false",
+		"-keyfile=",
+		"-keydir=",
+		"-remoterunner="+dirs.remoteRunner(),
+		"-remotebundledir="+dirs.remoteBundleDir(),
+		"-remotedatadir="+dirs.remoteDataDir(),
+		"-defaultvarsdir="+dirs.varsDir(),
+		"-resultsdir="+dirs.resDir(),
+	)
+	return out, lists, nil
+}
+
+// flagArg is a flag given on the command line.
+type flagArg struct {
+	name     string
+	value    string
+	hasValue bool // false for a boolean flag given without a value
+}
+
+// String returns f in the form of a command-line argument.
+func (f *flagArg) String() string {
+	if !f.hasValue {
+		return "-" + f.name
+	}
+	return fmt.Sprintf("-%s=%s", f.name, f.value)
+}
+
+// splitFlagArgs splits args consisting of flags defined in fs into flags.
+// Unlike fs.Parse, it preserves values of flags as they are given.
+func splitFlagArgs(args []string, fs *flag.FlagSet) ([]*flagArg, error) {
+	var flags []*flagArg
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			return nil, errors.Errorf("unexpected argument %q", arg)
+		}
+		name := strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-")
+		f := &flagArg{}
+		f.name, f.value, f.hasValue = strings.Cut(name, "=")
+		fl := fs.Lookup(f.name)
+		if fl == nil {
+			return nil, errors.Errorf("unknown flag %q", arg)
+		}
+		if bf, ok := fl.Value.(interface{ IsBoolFlag() bool }); !f.hasValue && !(ok && bf.IsBoolFlag()) {
+			if i+1 >= len(args) {
+				return nil, errors.Errorf("flag %q needs a value", arg)
+			}
+			i++
+			f.value, f.hasValue = args[i], true
+		}
+		flags = append(flags, f)
+	}
+	return flags, nil
+}
+
+// runFlagSet returns a flag set having flags of the run subcommand.
+func runFlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	config.NewMutableConfig(config.RunTestsMode, "", "").SetFlags(fs)
+	// Flags defined by the run subcommand itself.
+	fs.Bool("failfortests", false, "")
+	fs.String("timeout", "", "")
+	return fs
+}
@@ -0,0 +1,71 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package run
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestViaRunArgs(t *testing.T) {
+	dirs := &viaDirs{cache: "/home/user/.cache/tast/via", run: "/tmp/tmp.123"}
+	args := []string{
+		"-via=proxy",
+		"-build=false",
+		"-keyfile", "/home/user/.ssh/testing_rsa",
+		"-failfortests",
+		"-resultsdir=/tmp/results",
+		"-var=foo=bar",
+		"-var", "ui.*:timeout=30s",
+		"-varsfile=/home/user/vars.yaml",
+		"-testlist", "/home/user/tests.txt",
+		"--excludelist=/home/user/tests.txt",
+		"-timeout", "3600",
+		"-checktestdeps=false",
+		"-maxfailures=3",
+	}
+	got, lists, err := viaRunArgs(args, dirs)
+	if err != nil {
+		t.Fatal("viaRunArgs failed: ", err)
+	}
+
+	want := []string{
+		"-var=ui.*:timeout=30s",
+		"-testlist=/tmp/tmp.123/lists/0_tests.txt",
+		"-excludelist=/tmp/tmp.123/lists/0_tests.txt",
+		"-timeout=3600",
+		"-checktestdeps=false",
+		"-maxfailures=3",
+		"-build=false",
+		"-keyfile=",
+		"-keydir=",
+		"-remoterunner=/home/user/.cache/tast/via/remote_test_runner",
+		"-remotebundledir=/home/user/.cache/tast/via/bundles",
+		"-remotedatadir=/home/user/.cache/tast/via/data",
+		"-defaultvarsdir=/tmp/tmp.123/vars",
+		"-resultsdir=/tmp/tmp.123/results",
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("viaRunArgs returned unexpected args (-got +want):\n%s", diff)
+	}
+	wantLists := map[string]string{"/home/user/tests.txt": "/tmp/tmp.123/lists/0_tests.txt"}
+	if diff := cmp.Diff(lists, wantLists); diff != "" {
+		t.Errorf("viaRunArgs returned unexpected lists (-got +want):\n%s", diff)
+	}
+}
+
+func TestViaRunArgsInvalid(t *testing.T) {
+	dirs := &viaDirs{cache: "/cache", run: "/run"}
+	for _, args := range [][]string{
+		{"-nosuchflag"},
+		{"-keyfile"},
+		{"pattern"},
+	} {
+		if _, _, err := viaRunArgs(args, dirs); err == nil {
+			t.Errorf("viaRunArgs(%q) succeeded unexpectedly", args)
+		}
+	}
+}
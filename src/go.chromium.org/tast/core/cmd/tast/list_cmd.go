@@ -15,17 +15,20 @@ import (
 
 	"github.com/google/subcommands"
 
+	"go.chromium.org/tast/core/cmd/tast/internal/depgraph"
 	"go.chromium.org/tast/core/cmd/tast/internal/run/config"
 	"go.chromium.org/tast/core/internal/logging"
+	"go.chromium.org/tast/core/internal/protocol"
 	"go.chromium.org/tast/core/internal/run/resultsjson"
 )
 
 // listCmd implements subcommands.Command to support listing tests.
 type listCmd struct {
-	json    bool                  // marshal tests to JSON instead of just printing names
-	cfg     *config.MutableConfig // shared config for listing tests
-	wrapper runWrapper            // wraps calls to run package
-	stdout  io.Writer             // where to write tests
+	json     bool                  // marshal tests to JSON instead of just printing names
+	depGraph string                // format of dependency graph to print instead of tests; "dot" or "json"
+	cfg      *config.MutableConfig // shared config for listing tests
+	wrapper  runWrapper            // wraps calls to run package
+	stdout   io.Writer             // where to write tests
 }
 
 var _ = subcommands.Command(&runCmd{})
@@ -49,6 +52,7 @@ Description:
 
 Target:
     The target is an SSH connection spec of the form "[user@]host[:port]".
+    It is omitted with -target=local, which lists local tests on this machine.
 
 Pattern:
     Patterns are either globs matching test names or a single test attribute
@@ -66,6 +70,12 @@ Pattern:
 
         $ tast list <target> 'ui*' 'wilco*'
 
+    To print the dependency graph of matched tests, including fixture parent
+    chains, services, data files and runtime variables, in the DOT language.
+    Example:
+
+        $ tast list -depgraph=dot <target> '("group:mainline")' | dot -Tsvg > deps.svg
+
 Flag:
 `
 }
@@ -73,11 +83,12 @@ Flag:
 func (lc *listCmd) SetFlags(f *flag.FlagSet) {
 	// TODO(derat): Add -listtype: https://crbug.com/831849
 	f.BoolVar(&lc.json, "json", false, "print full test details as JSON")
+	f.StringVar(&lc.depGraph, "depgraph", "", `print dependency graph of tests in the given format ("dot" or "json")`)
 	lc.cfg.SetFlags(f)
 }
 
 func (lc *listCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
-	if len(f.Args()) == 0 {
+	if len(f.Args()) == 0 && lc.cfg.TargetMode != config.TargetLocal {
 		logging.Info(ctx, "Missing target.\n\n"+lc.Usage())
 		return subcommands.ExitUsageError
 	}
@@ -85,8 +96,23 @@ func (lc *listCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{
 		logging.Info(ctx, "Failed to derive defaults: ", err)
 		return subcommands.ExitUsageError
 	}
-	lc.cfg.Target = f.Args()[0]
-	lc.cfg.Patterns = f.Args()[1:]
+	switch lc.depGraph {
+	case "", "dot", "json":
+	default:
+		logging.Infof(ctx, "Invalid -depgraph format %q; must be \"dot\" or \"json\"", lc.depGraph)
+		return subcommands.ExitUsageError
+	}
+	if lc.json && lc.depGraph != "" {
+		logging.Info(ctx, "-json and -depgraph cannot be specified at the same time")
+		return subcommands.ExitUsageError
+	}
+	if lc.cfg.TargetMode == config.TargetLocal {
+		lc.cfg.Patterns = f.Args()
+	} else {
+		lc.cfg.Target = f.Args()[0]
+		lc.cfg.Patterns = f.Args()[1:]
+	}
+	lc.cfg.ListFixtures = lc.depGraph != ""
 
 	var logInMemory bytes.Buffer
 	logger := logging.NewSinkLogger(logging.LevelDebug, true, logging.NewWriterSink(&logInMemory))
@@ -103,6 +129,14 @@ func (lc *listCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{
 		tests[i] = &results[i].Test
 	}
 
+	if lc.depGraph != "" {
+		if err := lc.printDepGraph(tests, state.Fixtures); err != nil {
+			logging.Info(ctx, "Failed to write dependency graph: ", err)
+			return subcommands.ExitFailure
+		}
+		return subcommands.ExitSuccess
+	}
+
 	if err := lc.printTests(tests); err != nil {
 		logging.Info(ctx, "Failed to write tests: ", err)
 		return subcommands.ExitFailure
@@ -126,3 +160,13 @@ func (lc *listCmd) printTests(tests []*resultsjson.Test) error {
 	}
 	return nil
 }
+
+// printDepGraph writes the dependency graph of the supplied tests to
+// lc.stdout.
+func (lc *listCmd) printDepGraph(tests []*resultsjson.Test, fixtures []*protocol.Entity) error {
+	g := depgraph.New(tests, fixtures)
+	if lc.depGraph == "json" {
+		return g.WriteJSON(lc.stdout)
+	}
+	return g.WriteDOT(lc.stdout)
+}
@@ -17,6 +17,8 @@ import (
 
 	"github.com/google/subcommands"
 
+	"go.chromium.org/tast/core/cmd/tast/internal/depgraph"
+	"go.chromium.org/tast/core/internal/protocol"
 	"go.chromium.org/tast/core/internal/run/resultsjson"
 	"go.chromium.org/tast/core/testutil"
 )
@@ -68,3 +70,35 @@ func TestListTests(t *gotesting.T) {
 		t.Errorf("listCmd.Execute(%v) printed %+v; want %+v", args, act, exp)
 	}
 }
+
+func TestListTestsDepGraph(t *gotesting.T) {
+	wrapper := stubRunWrapper{
+		runRes:      []*resultsjson.Result{{Test: resultsjson.Test{Name: "pkg.Test", Fixture: "fixt"}}},
+		runFixtures: []*protocol.Entity{{Type: protocol.EntityType_FIXTURE, Name: "fixt"}},
+	}
+
+	stdout := bytes.Buffer{}
+	args := []string{"-depgraph=json", "root@example.net"}
+	if status := executeListCmd(t, &stdout, args, &wrapper); status != subcommands.ExitSuccess {
+		t.Fatalf("listCmd.Execute(%v) returned status %v; want %v", args, status, subcommands.ExitSuccess)
+	}
+	if !wrapper.runCfg.ListFixtures() {
+		t.Errorf("listCmd.Execute(%v) did not request fixtures", args)
+	}
+	var g depgraph.Graph
+	if err := json.Unmarshal(stdout.Bytes(), &g); err != nil {
+		t.Fatalf("Failed to unmarshal output from listCmd.Execute(%v): %v", args, err)
+	}
+	if exp := []*depgraph.Edge{{From: "test:pkg.Test", To: "fixture:fixt"}}; !reflect.DeepEqual(g.Edges, exp) {
+		t.Errorf("listCmd.Execute(%v) printed edges %+v; want %+v", args, g.Edges, exp)
+	}
+
+	for _, args := range [][]string{
+		{"-depgraph=svg", "root@example.net"},
+		{"-depgraph=dot", "-json", "root@example.net"},
+	} {
+		if status := executeListCmd(t, &stdout, args, &wrapper); status != subcommands.ExitUsageError {
+			t.Errorf("listCmd.Execute(%v) returned status %v; want %v", args, status, subcommands.ExitUsageError)
+		}
+	}
+}
@@ -59,8 +59,13 @@ func doMain() int {
 	subcommands.Register(subcommands.CommandsCommand(), "")
 	subcommands.Register(newListCmd(os.Stdout, trunkDir()), "")
 	subcommands.Register(newRunCmd(trunkDir(), Version), "")
+	subcommands.Register(newResumeCmd(trunkDir(), Version), "")
 	subcommands.Register(&symbolizeCmd{}, "")
+	subcommands.Register(newDoctorCmd(os.Stdout, trunkDir()), "")
+	subcommands.Register(newCleanCmd(os.Stdout, trunkDir()), "")
+	subcommands.Register(newFlakesCmd(os.Stdout), "")
 	subcommands.Register(newGlobalRuntimeVarsCmd(os.Stdout, trunkDir()), "")
+	subcommands.Register(newWatchCmd(os.Stdout, trunkDir()), "")
 
 	version := flag.Bool("version", false, "print version and exit")
 	verbose := flag.Bool("verbose", false, "use verbose logging")
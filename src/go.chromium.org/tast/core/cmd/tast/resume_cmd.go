@@ -0,0 +1,104 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/google/subcommands"
+
+	"go.chromium.org/tast/core/cmd/tast/internal/run"
+	"go.chromium.org/tast/core/internal/logging"
+)
+
+// resumeCmd implements subcommands.Command to support resuming an
+// interrupted run.
+type resumeCmd struct {
+	trunkDir string
+	version  string
+	wrapper  runWrapper // can be set by tests to stub out calls to run package
+}
+
+var _ = subcommands.Command(&resumeCmd{})
+
+func newResumeCmd(trunkDir, version string) *resumeCmd {
+	return &resumeCmd{
+		trunkDir: trunkDir,
+		version:  version,
+		wrapper:  &realRunWrapper{},
+	}
+}
+
+func (*resumeCmd) Name() string     { return "resume" }
+func (*resumeCmd) Synopsis() string { return "resume an interrupted run" }
+func (*resumeCmd) Usage() string {
+	return `Usage: resume <resultsdir>
+
+Description:
+    Continues an interrupted "tast run" whose results are in resultsdir.
+    Tests planned in the run are executed with the same flags and target,
+    except ones that have already completed without errors. Results are
+    written to the same results directory.
+`
+}
+
+func (*resumeCmd) SetFlags(f *flag.FlagSet) {}
+
+func (c *resumeCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	if len(f.Args()) != 1 {
+		logging.Info(ctx, "Missing results directory.\n\n"+c.Usage())
+		return subcommands.ExitUsageError
+	}
+	resDir := f.Arg(0)
+
+	m, err := run.ReadManifest(resDir)
+	if err != nil {
+		logging.Infof(ctx, "Failed to read run manifest: %v", err)
+		return subcommands.ExitFailure
+	}
+	prev, err := run.CompletedResults(resDir, m)
+	if err != nil {
+		logging.Infof(ctx, "Failed to read results of the interrupted run: %v", err)
+		return subcommands.ExitFailure
+	}
+
+	completed := make(map[string]struct{})
+	for _, r := range prev {
+		completed[r.Name] = struct{}{}
+	}
+	var remaining []string
+	for _, name := range m.Planned {
+		if _, ok := completed[name]; !ok {
+			remaining = append(remaining, name)
+		}
+	}
+	if len(remaining) == 0 {
+		logging.Infof(ctx, "All %d planned tests have already completed", len(m.Planned))
+		return subcommands.ExitSuccess
+	}
+	logging.Infof(ctx, "Resuming run in %s: %d of %d planned tests remaining", resDir, len(remaining), len(m.Planned))
+
+	rc := newRunCmd(c.trunkDir, c.version)
+	rc.wrapper = c.wrapper
+	rf := flag.NewFlagSet(rc.Name(), flag.ContinueOnError)
+	rc.SetFlags(rf)
+
+	// Tests to run are already sharded, so sharding flags are overridden.
+	args := append(append([]string(nil), m.Args...), "-resultsdir="+resDir, "-totalshards=1", "-shardindex=0", "--")
+	// The target is empty if the run was started with -target=local or
+	// -dutprovider.
+	if m.Target != "" {
+		args = append(args, m.Target)
+	}
+	if err := rf.Parse(append(args, remaining...)); err != nil {
+		logging.Infof(ctx, "Failed to parse flags of the interrupted run: %v", err)
+		return subcommands.ExitFailure
+	}
+	rc.cfg.Args = m.Args
+	rc.cfg.Resume = true
+	rc.cfg.PreviousResults = prev
+	return rc.Execute(ctx, rf)
+}
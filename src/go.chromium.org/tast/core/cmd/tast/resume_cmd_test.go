@@ -0,0 +1,140 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	gotesting "testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/subcommands"
+
+	"go.chromium.org/tast/core/cmd/tast/internal/run"
+	"go.chromium.org/tast/core/internal/run/reporting"
+	"go.chromium.org/tast/core/internal/run/resultsjson"
+	"go.chromium.org/tast/core/testutil"
+)
+
+// setUpResumableRun writes files of an interrupted run to resDir.
+func setUpResumableRun(t *gotesting.T, resDir string, planned []string, results []*resultsjson.Result) {
+	t.Helper()
+	b, err := json.Marshal(&run.Manifest{
+		Args:    []string{"-build=false", "-var=foo=bar"},
+		Target:  "root@example.net",
+		Planned: planned,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(resDir, run.ManifestFile), b, 0644); err != nil {
+		t.Fatal(err)
+	}
+	w, err := reporting.NewStreamedWriter(filepath.Join(resDir, reporting.StreamedResultsFilename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	for _, r := range results {
+		if err := w.Write(r, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// executeResumeCmd creates a resumeCmd and executes it for resDir.
+func executeResumeCmd(t *gotesting.T, resDir string, wrapper *stubRunWrapper) subcommands.ExitStatus {
+	t.Helper()
+	td := testutil.TempDir(t)
+	defer os.RemoveAll(td)
+
+	cmd := newResumeCmd(td, "")
+	cmd.wrapper = wrapper
+	flags := flag.NewFlagSet("", flag.ContinueOnError)
+	cmd.SetFlags(flags)
+	if err := flags.Parse([]string{resDir}); err != nil {
+		t.Fatal(err)
+	}
+	return cmd.Execute(context.Background(), flags)
+}
+
+func TestResume(t *gotesting.T) {
+	resDir := testutil.TempDir(t)
+	defer os.RemoveAll(resDir)
+
+	now := time.Now()
+	setUpResumableRun(t, resDir, []string{"pkg.Pass", "pkg.Fail", "pkg.Aborted", "pkg.NotRun"}, []*resultsjson.Result{
+		{Test: resultsjson.Test{Name: "pkg.Pass"}, Start: now, End: now},
+		{Test: resultsjson.Test{Name: "pkg.Fail"}, Start: now, End: now, Errors: []resultsjson.Error{{Reason: "failed"}}},
+		{Test: resultsjson.Test{Name: "pkg.Aborted"}, Start: now},
+	})
+
+	wrapper := stubRunWrapper{runRes: []*resultsjson.Result{{Test: resultsjson.Test{Name: "pkg.Fail"}}}}
+	if status := executeResumeCmd(t, resDir, &wrapper); status != subcommands.ExitSuccess {
+		t.Fatalf("resumeCmd.Execute returned status %v; want %v", status, subcommands.ExitSuccess)
+	}
+	if wrapper.runCfg == nil {
+		t.Fatal("resumeCmd.Execute didn't run tests")
+	}
+
+	cfg := wrapper.runCfg
+	if diff := cmp.Diff(cfg.Patterns(), []string{"pkg.Fail", "pkg.Aborted", "pkg.NotRun"}); diff != "" {
+		t.Errorf("Patterns mismatch (-got +want):\n%s", diff)
+	}
+	if got, want := cfg.Target(), "root@example.net"; got != want {
+		t.Errorf("Target = %q; want %q", got, want)
+	}
+	if got := cfg.ResDir(); got != resDir {
+		t.Errorf("ResDir = %q; want %q", got, resDir)
+	}
+	if got := cfg.TestVars()["foo"]; got != "bar" {
+		t.Errorf("Var foo = %q; want %q", got, "bar")
+	}
+	if !cfg.Resume() {
+		t.Error("Resume = false; want true")
+	}
+	if diff := cmp.Diff(cfg.Args(), []string{"-build=false", "-var=foo=bar"}); diff != "" {
+		t.Errorf("Args mismatch (-got +want):\n%s", diff)
+	}
+	var prev []string
+	for _, r := range cfg.PreviousResults() {
+		prev = append(prev, r.Name)
+	}
+	if diff := cmp.Diff(prev, []string{"pkg.Pass"}); diff != "" {
+		t.Errorf("PreviousResults mismatch (-got +want):\n%s", diff)
+	}
+}
+
+func TestResumeAllCompleted(t *gotesting.T) {
+	resDir := testutil.TempDir(t)
+	defer os.RemoveAll(resDir)
+
+	now := time.Now()
+	setUpResumableRun(t, resDir, []string{"pkg.Pass"}, []*resultsjson.Result{
+		{Test: resultsjson.Test{Name: "pkg.Pass"}, Start: now, End: now},
+	})
+
+	var wrapper stubRunWrapper
+	if status := executeResumeCmd(t, resDir, &wrapper); status != subcommands.ExitSuccess {
+		t.Fatalf("resumeCmd.Execute returned status %v; want %v", status, subcommands.ExitSuccess)
+	}
+	if wrapper.runCfg != nil {
+		t.Error("resumeCmd.Execute unexpectedly ran tests")
+	}
+}
+
+func TestResumeNoManifest(t *gotesting.T) {
+	resDir := testutil.TempDir(t)
+	defer os.RemoveAll(resDir)
+
+	var wrapper stubRunWrapper
+	if status := executeResumeCmd(t, resDir, &wrapper); status != subcommands.ExitFailure {
+		t.Fatalf("resumeCmd.Execute returned status %v; want %v", status, subcommands.ExitFailure)
+	}
+}
@@ -7,6 +7,7 @@ package main
 import (
 	"context"
 	"flag"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -28,6 +29,7 @@ import (
 const (
 	fullLogName   = "full.txt"    // file in runConfig.resDir containing full output
 	timingLogName = "timing.json" // file in runConfig.resDir containing timing information
+	traceLogName  = "trace.json"  // file in runConfig.resDir containing timing information in the Chrome trace event format
 )
 
 // runCmd implements subcommands.Command to support running tests.
@@ -63,6 +65,9 @@ Description:
 
 Target:
     The target is an SSH connection spec of the form "[user@]host[:port]".
+    It is omitted with -target=local, which runs local tests directly on this
+    machine without a DUT, and with -dutprovider, which leases a DUT from a
+    pool for the duration of the run.
 
 Pattern:
     Patterns are either globs matching test names or a single test attribute
@@ -101,7 +106,7 @@ func (r *runCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{})
 	ctx = timing.NewContext(ctx, tl)
 	ctx, st := timing.Start(ctx, "exec")
 
-	if len(f.Args()) == 0 {
+	if len(f.Args()) == 0 && r.cfg.TargetMode != config.TargetLocal && r.cfg.DUTProvider == "" {
 		logging.Info(ctx, "Missing target.\n\n"+r.Usage())
 		return subcommands.ExitUsageError
 	}
@@ -130,19 +135,21 @@ func (r *runCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{})
 	// Write the timing log after the command finishes.
 	defer func() {
 		st.End()
-		f, err := os.Create(filepath.Join(r.cfg.ResDir, timingLogName))
-		if err != nil {
+		if err := writeTimingLog(filepath.Join(r.cfg.ResDir, timingLogName), tl.WritePretty); err != nil {
 			logging.Info(ctx, err)
-			return
 		}
-		defer f.Close()
-		if err := tl.WritePretty(f); err != nil {
+		if err := writeTimingLog(filepath.Join(r.cfg.ResDir, traceLogName), tl.WriteTrace); err != nil {
 			logging.Info(ctx, err)
 		}
 	}()
 
-	// Log the full output of the command to disk.
-	fullLog, err := os.Create(filepath.Join(r.cfg.ResDir, fullLogName))
+	// Log the full output of the command to disk. When resuming a run, the
+	// log of the interrupted run is preserved.
+	logFlags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if r.cfg.Resume {
+		logFlags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+	fullLog, err := os.OpenFile(filepath.Join(r.cfg.ResDir, fullLogName), logFlags, 0666)
 	if err != nil {
 		logging.Info(ctx, err)
 		return subcommands.ExitFailure
@@ -152,10 +159,25 @@ func (r *runCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{})
 	logger := logging.NewSinkLogger(logging.LevelDebug, true, logging.NewWriterSink(fullLog))
 	ctx = logging.AttachLogger(ctx, logger)
 
+	// Keep values of variables from -varsprovider out of logs.
+	var secrets []string
+	for _, name := range r.cfg.SecretVarNames {
+		secrets = append(secrets, r.cfg.TestVars[name])
+	}
+	ctx = logging.AttachRedactor(ctx, secrets)
+
 	logging.Info(ctx, "Command line: ", strings.Join(os.Args, " "))
 	logging.Info(ctx, "Tast version: ", r.version)
-	r.cfg.Target = f.Args()[0]
-	r.cfg.Patterns = f.Args()[1:]
+	if r.cfg.TargetMode == config.TargetLocal || r.cfg.DUTProvider != "" {
+		r.cfg.Patterns = f.Args()
+	} else {
+		r.cfg.Target = f.Args()[0]
+		r.cfg.Patterns = f.Args()[1:]
+	}
+	r.cfg.TastVersion = r.version
+	if !r.cfg.Resume {
+		r.cfg.Args = flagArgs(r.Name(), f)
+	}
 
 	if r.cfg.KeyFile != "" {
 		logging.Debug(ctx, "Using SSH key ", r.cfg.KeyFile)
@@ -170,6 +192,15 @@ func (r *runCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{})
 		return subcommands.ExitFailure
 	}
 
+	if r.cfg.Repeat < 0 {
+		logging.Infof(ctx, "-repeat must not be negative")
+		return subcommands.ExitUsageError
+	}
+	if (r.cfg.Repeat > 0 || r.cfg.RepeatUntilFail) && (r.cfg.Retries > 0 || r.cfg.Repeats > 0) {
+		logging.Infof(ctx, "-repeat and -repeat-until-fail cannot be set together with -repeats or -retries")
+		return subcommands.ExitFailure
+	}
+
 	ctx = telemetry.SetPhase(ctx, "", "", "")
 
 	results, runErr := r.wrapper.run(ctx, r.cfg.Freeze(), &state)
@@ -196,3 +227,29 @@ func (r *runCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{})
 
 	return subcommands.ExitSuccess
 }
+
+// flagArgs returns flags given to the subcommand name on the command line,
+// excluding positional arguments left in f after parsing. It returns nil if
+// the subcommand was not invoked from the command line, e.g. in unit tests.
+func flagArgs(name string, f *flag.FlagSet) []string {
+	args := flag.Args()
+	if len(args) == 0 || args[0] != name || len(args)-1 < f.NArg() {
+		return nil
+	}
+	args = args[1:]
+	return append([]string(nil), args[:len(args)-f.NArg()]...)
+}
+
+// writeTimingLog creates a file at path and writes a timing log to it with
+// write.
+func writeTimingLog(path string, write func(io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -145,3 +145,24 @@ func TestRunRejectBothRepeatsAndRetriesSet(t *gotesting.T) {
 		t.Errorf("runCmd.Execute(%v) logged last line %q; wanted line containing error %q", args, last, msg)
 	}
 }
+
+func TestRunRejectRepeatWithRetries(t *gotesting.T) {
+	args := []string{
+		"-retries", "2",
+		"-repeat-until-fail",
+		"pkg.LocalTest",
+	}
+	wrapper := stubRunWrapper{}
+	logger := loggingtest.NewLogger(t, logging.LevelDebug)
+	if status := executeRunCmd(t, args, &wrapper, logger); status != subcommands.ExitFailure {
+		t.Fatalf("runCmd.Execute(%v) returned status %v; want %v", args, status, subcommands.ExitFailure)
+	}
+
+	const msg = "-repeat and -repeat-until-fail cannot be set together with -repeats or -retries"
+	lines := logger.Logs()
+	if len(lines) == 0 {
+		t.Errorf("runCmd.Execute(%v) didn't log any output", args)
+	} else if last := lines[len(lines)-1]; !strings.Contains(last, msg) {
+		t.Errorf("runCmd.Execute(%v) logged last line %q; wanted line containing error %q", args, last, msg)
+	}
+}
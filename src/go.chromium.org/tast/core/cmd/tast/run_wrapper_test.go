@@ -8,6 +8,7 @@ import (
 	"context"
 
 	"go.chromium.org/tast/core/cmd/tast/internal/run/config"
+	"go.chromium.org/tast/core/internal/protocol"
 	"go.chromium.org/tast/core/internal/run/resultsjson"
 )
 
@@ -18,12 +19,14 @@ type stubRunWrapper struct {
 	runState *config.DeprecatedState // state passed to run
 
 	runRes               []*resultsjson.Result // results to return from run
+	runFixtures          []*protocol.Entity    // fixtures to store to state in run
 	runGlobalRuntimeVars []string              //results to return from GlobalRuntimeVars
 	runErr               error                 // error to return from run
 }
 
 func (w *stubRunWrapper) run(ctx context.Context, cfg *config.Config, state *config.DeprecatedState) ([]*resultsjson.Result, error) {
 	w.runCtx, w.runCfg, w.runState = ctx, cfg, state
+	state.Fixtures = w.runFixtures
 	return w.runRes, w.runErr
 }
 
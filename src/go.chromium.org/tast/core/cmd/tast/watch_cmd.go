@@ -0,0 +1,254 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/subcommands"
+
+	"go.chromium.org/tast/core/cmd/tast/internal/run/config"
+	"go.chromium.org/tast/core/internal/logging"
+	"go.chromium.org/tast/core/internal/run/resultsjson"
+)
+
+// watchCmd implements subcommands.Command to rerun tests whenever their source
+// files change.
+type watchCmd struct {
+	cfg      *config.MutableConfig // shared config for running tests
+	wrapper  runWrapper            // can be set by tests to stub out calls to run package
+	out      io.Writer             // where condensed results are written
+	interval time.Duration         // interval to poll source files
+}
+
+var _ = subcommands.Command(&watchCmd{})
+
+func newWatchCmd(w io.Writer, trunkDir string) *watchCmd {
+	return &watchCmd{
+		cfg:     config.NewMutableConfig(config.RunTestsMode, tastDir, trunkDir),
+		wrapper: &realRunWrapper{},
+		out:     w,
+	}
+}
+
+func (*watchCmd) Name() string     { return "watch" }
+func (*watchCmd) Synopsis() string { return "rerun tests on source changes" }
+func (*watchCmd) Usage() string {
+	return `Usage: watch [flag]... <target> <pattern>...
+
+Description:
+    Runs tests matched by the patterns on the target, and reruns them whenever
+    a file in the source tree of the test bundle (-buildworkspace) changes.
+    Changed bundles are rebuilt before tests are rerun. A line summarizing
+    results is printed after each run, and full logs are written to the
+    results directory of each run. Press Ctrl-C to stop watching.
+
+    The target and patterns are the same as those of the run command.
+
+Flag:
+`
+}
+
+func (w *watchCmd) SetFlags(f *flag.FlagSet) {
+	f.DurationVar(&w.interval, "watchinterval", time.Second, "interval to check source files for changes")
+	w.cfg.SetFlags(f)
+}
+
+func (w *watchCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	minArgs := 2
+	if w.cfg.TargetMode == config.TargetLocal || w.cfg.DUTProvider != "" {
+		minArgs = 1
+	}
+	if len(f.Args()) < minArgs {
+		logging.Info(ctx, "Missing target or patterns.\n\n"+w.Usage())
+		return subcommands.ExitUsageError
+	}
+	if !w.cfg.Build {
+		logging.Info(ctx, "watch requires -build=true")
+		return subcommands.ExitUsageError
+	}
+	if w.interval <= 0 {
+		logging.Info(ctx, "-watchinterval must be positive")
+		return subcommands.ExitUsageError
+	}
+
+	baseResDir := w.cfg.ResDir
+	if err := w.cfg.DeriveDefaults(); err != nil {
+		logging.Info(ctx, "Failed to derive defaults: ", err)
+		return subcommands.ExitUsageError
+	}
+	if baseResDir == "" {
+		baseResDir = filepath.Join(w.cfg.TastDir, "results", "watch-"+time.Now().Format("20060102-150405"))
+	}
+	if w.cfg.TargetMode == config.TargetLocal || w.cfg.DUTProvider != "" {
+		w.cfg.Patterns = f.Args()
+	} else {
+		w.cfg.Target = f.Args()[0]
+		w.cfg.Patterns = f.Args()[1:]
+	}
+
+	srcDir := filepath.Join(w.cfg.BuildWorkspace, "src")
+	snap, err := snapshotTree(srcDir)
+	if err != nil {
+		logging.Infof(ctx, "Failed to scan %s: %v", srcDir, err)
+		return subcommands.ExitFailure
+	}
+	logging.Infof(ctx, "Watching %s; writing results under %s", srcDir, baseResDir)
+
+	for i := 1; ; i++ {
+		w.runOnce(ctx, filepath.Join(baseResDir, fmt.Sprintf("%03d", i)))
+
+		fmt.Fprintln(w.out, "Waiting for changes...")
+		snap, err = waitForChange(ctx, srcDir, snap, w.interval)
+		if err != nil {
+			if ctx.Err() != nil {
+				return subcommands.ExitSuccess
+			}
+			logging.Infof(ctx, "Failed to scan %s: %v", srcDir, err)
+			return subcommands.ExitFailure
+		}
+	}
+}
+
+// runOnce runs tests once with results written to resDir, and prints a line
+// summarizing results.
+func (w *watchCmd) runOnce(ctx context.Context, resDir string) {
+	// Each run gets a fresh copy of the config pointing to its own results
+	// directory.
+	cfg := *w.cfg
+	cfg.ResDir = resDir
+	cfg.RemoteOutDir = filepath.Join(resDir, "tast_out")
+	if cfg.TargetMode == config.TargetLocal {
+		cfg.LocalOutDir = filepath.Join(resDir, "tast_local_out")
+	}
+	if err := os.MkdirAll(resDir, 0755); err != nil {
+		fmt.Fprintf(w.out, "%s ERROR %v\n", time.Now().Format("15:04:05"), err)
+		return
+	}
+
+	// Logs of runs are too verbose for the inner loop, so they are only
+	// written to the results directory.
+	fullLog, err := os.Create(filepath.Join(resDir, fullLogName))
+	if err != nil {
+		fmt.Fprintf(w.out, "%s ERROR %v\n", time.Now().Format("15:04:05"), err)
+		return
+	}
+	defer fullLog.Close()
+	logger := logging.NewSinkLogger(logging.LevelDebug, true, logging.NewWriterSink(fullLog))
+	runCtx := logging.AttachLoggerNoPropagation(ctx, logger)
+
+	fmt.Fprintf(w.out, "Running %s...\n", strings.Join(cfg.Patterns, " "))
+	start := time.Now()
+	var state config.DeprecatedState
+	results, err := w.wrapper.run(runCtx, cfg.Freeze(), &state)
+	fmt.Fprintln(w.out, summarizeWatchRun(start, time.Since(start), results, err, filepath.Join(resDir, fullLogName)))
+}
+
+// summarizeWatchRun returns a line summarizing results of a run started at
+// start, e.g. "15:04:05 FAIL 2 passed, 1 failed (example.Fail), 0 skipped in 12.3s".
+// logPath is shown in case the run failed.
+func summarizeWatchRun(start time.Time, elapsed time.Duration, results []*resultsjson.Result, runErr error, logPath string) string {
+	ts := start.Format("15:04:05")
+	if runErr != nil {
+		return fmt.Sprintf("%s ERROR %v (see %s)", ts, runErr, logPath)
+	}
+	var passed, skipped int
+	var failed []string
+	for _, r := range results {
+		switch {
+		case r.SkipReason != "":
+			skipped++
+		case len(r.Errors) > 0:
+			failed = append(failed, r.Name)
+		default:
+			passed++
+		}
+	}
+	status := "PASS"
+	failures := fmt.Sprintf("%d failed", len(failed))
+	if len(failed) > 0 {
+		status = "FAIL"
+		failures += fmt.Sprintf(" (%s)", strings.Join(failed, ", "))
+	} else if passed == 0 {
+		status = "NONE"
+	}
+	return fmt.Sprintf("%s %s %d passed, %s, %d skipped in %v",
+		ts, status, passed, failures, skipped, elapsed.Round(100*time.Millisecond))
+}
+
+// fileStamp identifies a version of a file.
+type fileStamp struct {
+	size    int64
+	modTime time.Time
+}
+
+// snapshotTree returns stamps of regular files under dir keyed by their
+// paths. Hidden files and directories, e.g. .git, are ignored.
+func snapshotTree(dir string) (map[string]fileStamp, error) {
+	snap := make(map[string]fileStamp)
+	err := filepath.Walk(dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != dir && strings.HasPrefix(fi.Name(), ".") {
+			if fi.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if fi.Mode().IsRegular() {
+			snap[path] = fileStamp{fi.Size(), fi.ModTime()}
+		}
+		return nil
+	})
+	return snap, err
+}
+
+// sameSnapshots returns true if a and b contain the same files with the same
+// stamps.
+func sameSnapshots(a, b map[string]fileStamp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for path, s := range a {
+		if t, ok := b[path]; !ok || t.size != s.size || !t.modTime.Equal(s.modTime) {
+			return false
+		}
+	}
+	return true
+}
+
+// waitForChange polls files under dir every interval until they differ from
+// prev, and returns the new snapshot of dir. Since editors often write files
+// in several steps, it waits until files stop changing for interval.
+func waitForChange(ctx context.Context, dir string, prev map[string]fileStamp, interval time.Duration) (map[string]fileStamp, error) {
+	changed := false
+	for {
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		snap, err := snapshotTree(dir)
+		if err != nil {
+			return nil, err
+		}
+		same := sameSnapshots(prev, snap)
+		if changed && same {
+			return snap, nil
+		}
+		if !same {
+			changed = true
+		}
+		prev = snap
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	gotesting "testing"
+	"time"
+
+	"github.com/google/subcommands"
+
+	"go.chromium.org/tast/core/cmd/tast/internal/run/config"
+	"go.chromium.org/tast/core/errors"
+	"go.chromium.org/tast/core/internal/run/resultsjson"
+	"go.chromium.org/tast/core/testutil"
+)
+
+// cancelingRunWrapper is a runWrapper canceling a context after running tests.
+type cancelingRunWrapper struct {
+	stubRunWrapper
+	cancel context.CancelFunc
+}
+
+func (w *cancelingRunWrapper) run(ctx context.Context, cfg *config.Config, state *config.DeprecatedState) ([]*resultsjson.Result, error) {
+	defer w.cancel()
+	return w.stubRunWrapper.run(ctx, cfg, state)
+}
+
+func TestWatchRunsTests(t *gotesting.T) {
+	td := testutil.TempDir(t)
+	defer os.RemoveAll(td)
+
+	if err := testutil.WriteFiles(td, map[string]string{
+		"ws/src/foo.go": "package foo",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var out bytes.Buffer
+	cmd := newWatchCmd(&out, td)
+	wrapper := &cancelingRunWrapper{
+		stubRunWrapper: stubRunWrapper{runRes: []*resultsjson.Result{
+			{Test: resultsjson.Test{Name: "pkg.Pass"}},
+			{Test: resultsjson.Test{Name: "pkg.Fail"}, Errors: []resultsjson.Error{{}}},
+		}},
+		cancel: cancel,
+	}
+	cmd.wrapper = wrapper
+	flags := flag.NewFlagSet("", flag.ContinueOnError)
+	cmd.SetFlags(flags)
+	resDir := filepath.Join(td, "results")
+	args := []string{
+		"-buildworkspace=" + filepath.Join(td, "ws"),
+		"-resultsdir=" + resDir,
+		"-watchinterval=10ms",
+		"root@example.net", "pkg.*",
+	}
+	if err := flags.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+
+	if status := cmd.Execute(ctx, flags); status != subcommands.ExitSuccess {
+		t.Fatalf("Execute(%q) = %v; want %v", args, status, subcommands.ExitSuccess)
+	}
+
+	if wrapper.runCfg == nil {
+		t.Fatal("Tests were not run")
+	}
+	if got, want := wrapper.runCfg.ResDir(), filepath.Join(resDir, "001"); got != want {
+		t.Errorf("ResDir = %q; want %q", got, want)
+	}
+	if got, want := wrapper.runCfg.Target(), "root@example.net"; got != want {
+		t.Errorf("Target = %q; want %q", got, want)
+	}
+	if want := "FAIL 1 passed, 1 failed (pkg.Fail), 0 skipped"; !strings.Contains(out.String(), want) {
+		t.Errorf("Output %q does not contain %q", out.String(), want)
+	}
+}
+
+func TestSummarizeWatchRun(t *gotesting.T) {
+	start := time.Date(2024, 1, 2, 15, 4, 5, 0, time.Local)
+	for _, tc := range []struct {
+		name    string
+		results []*resultsjson.Result
+		err     error
+		want    string
+	}{
+		{
+			name: "pass",
+			results: []*resultsjson.Result{
+				{Test: resultsjson.Test{Name: "pkg.Pass"}},
+				{Test: resultsjson.Test{Name: "pkg.Skip"}, SkipReason: "missing deps"},
+			},
+			want: "15:04:05 PASS 1 passed, 0 failed, 1 skipped in 1.5s",
+		},
+		{
+			name: "fail",
+			results: []*resultsjson.Result{
+				{Test: resultsjson.Test{Name: "pkg.Fail1"}, Errors: []resultsjson.Error{{}}},
+				{Test: resultsjson.Test{Name: "pkg.Fail2"}, Errors: []resultsjson.Error{{}}},
+			},
+			want: "15:04:05 FAIL 0 passed, 2 failed (pkg.Fail1, pkg.Fail2), 0 skipped in 1.5s",
+		},
+		{
+			name: "none",
+			want: "15:04:05 NONE 0 passed, 0 failed, 0 skipped in 1.5s",
+		},
+		{
+			name: "error",
+			err:  errors.New("build failed"),
+			want: "15:04:05 ERROR build failed (see full.txt)",
+		},
+	} {
+		if got := summarizeWatchRun(start, 1500*time.Millisecond, tc.results, tc.err, "full.txt"); got != tc.want {
+			t.Errorf("%s: summarizeWatchRun = %q; want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestWaitForChange(t *gotesting.T) {
+	td := testutil.TempDir(t)
+	defer os.RemoveAll(td)
+
+	if err := testutil.WriteFiles(td, map[string]string{
+		"a.go":         "package a",
+		".git/HEAD":    "ref",
+		"sub/data.txt": "data",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	snap, err := snapshotTree(td)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := snap[filepath.Join(td, ".git/HEAD")]; ok {
+		t.Error("snapshotTree included a hidden file")
+	}
+
+	ctx := context.Background()
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		testutil.WriteFiles(td, map[string]string{"sub/new.go": "package sub"})
+	}()
+	tctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	newSnap, err := waitForChange(tctx, td, snap, 10*time.Millisecond)
+	if err != nil {
+		t.Fatal("waitForChange failed: ", err)
+	}
+	if _, ok := newSnap[filepath.Join(td, "sub/new.go")]; !ok {
+		t.Error("New snapshot does not contain the added file")
+	}
+
+	// Changes to hidden files are ignored.
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		testutil.WriteFiles(td, map[string]string{".git/HEAD": "ref2"})
+	}()
+	tctx, cancel = context.WithTimeout(ctx, 200*time.Millisecond)
+	defer cancel()
+	if _, err := waitForChange(tctx, td, newSnap, 10*time.Millisecond); err == nil {
+		t.Error("waitForChange returned for a change to a hidden file")
+	}
+}
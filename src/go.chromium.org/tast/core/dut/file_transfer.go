@@ -0,0 +1,341 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package dut
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.chromium.org/tast/core/errors"
+	"go.chromium.org/tast/core/ssh"
+)
+
+// ProgressFunc is called back during file transfers with the number of bytes
+// transferred so far and the total number of bytes to transfer.
+type ProgressFunc func(transferred, total int64)
+
+// progressWriter accumulates the number of bytes transferred in possibly
+// multiple file transfers and reports it to a ProgressFunc.
+type progressWriter struct {
+	f           ProgressFunc
+	transferred int64
+	total       int64
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	p.transferred += int64(len(b))
+	if p.f != nil {
+		p.f(p.transferred, p.total)
+	}
+	return len(b), nil
+}
+
+// remoteFile describes a regular file on the DUT.
+type remoteFile struct {
+	sha256 string
+	size   int64
+	mode   os.FileMode
+}
+
+// PutFileWithSHA256 copies a regular file src on the local machine to dst on
+// the DUT, creating the parent directory of dst if needed. The file is
+// verified by comparing SHA-256 checksums before it atomically replaces dst,
+// so dst is left as it is on failure. progress is called back during the
+// transfer if it is not nil.
+func (d *DUT) PutFileWithSHA256(ctx context.Context, src, dst string, progress ProgressFunc) error {
+	if err := d.checkConnected(); err != nil {
+		return err
+	}
+	fi, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	return d.putFile(ctx, src, dst, fi, &progressWriter{f: progress, total: fi.Size()})
+}
+
+// GetFileWithSHA256 copies a regular file src on the DUT to dst on the local
+// machine, creating the parent directory of dst if needed. The file is
+// verified by comparing SHA-256 checksums before it atomically replaces dst,
+// so dst is left as it is on failure. progress is called back during the
+// transfer if it is not nil.
+func (d *DUT) GetFileWithSHA256(ctx context.Context, src, dst string, progress ProgressFunc) error {
+	if err := d.checkConnected(); err != nil {
+		return err
+	}
+	out, err := d.hst.CommandContext(ctx, "stat", "-L", "-c", "%s %a", src).Output(ssh.DumpLogOnError)
+	if err != nil {
+		return errors.Wrapf(err, "failed to stat %s", src)
+	}
+	var size int64
+	var mode uint32
+	if _, err := fmt.Sscanf(string(out), "%d %o", &size, &mode); err != nil {
+		return errors.Wrapf(err, "failed to parse stat output %q", out)
+	}
+	return d.getFile(ctx, src, dst, os.FileMode(mode), &progressWriter{f: progress, total: size})
+}
+
+// SyncDirToDUT copies regular files in the directory src on the local machine
+// to the directory dst on the DUT, creating directories as needed. Files whose
+// SHA-256 checksums match those of files already in dst are skipped, so that
+// only new or updated files are transferred. Files in dst missing in src are
+// left as they are. Symbolic links and other non-regular files are ignored.
+// Each file is verified as with PutFileWithSHA256. progress is called back
+// with the total size of files to transfer if it is not nil.
+func (d *DUT) SyncDirToDUT(ctx context.Context, src, dst string, progress ProgressFunc) error {
+	if err := d.checkConnected(); err != nil {
+		return err
+	}
+	remote, err := d.listRemoteDir(ctx, dst)
+	if err != nil {
+		return err
+	}
+
+	type localFile struct {
+		rel string
+		fi  fs.FileInfo
+	}
+	var files []localFile
+	p := &progressWriter{f: progress}
+	if err := filepath.WalkDir(src, func(local string, e fs.DirEntry, err error) error {
+		if err != nil || !e.Type().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(src, local)
+		if err != nil {
+			return err
+		}
+		fi, err := e.Info()
+		if err != nil {
+			return err
+		}
+		if rf, ok := remote[filepath.ToSlash(rel)]; ok && rf.size == fi.Size() {
+			sum, err := localSHA256(local)
+			if err != nil {
+				return err
+			}
+			if sum == rf.sha256 {
+				return nil
+			}
+		}
+		files = append(files, localFile{rel, fi})
+		p.total += fi.Size()
+		return nil
+	}); err != nil {
+		return errors.Wrapf(err, "failed to list files in %s", src)
+	}
+
+	for _, f := range files {
+		if err := d.putFile(ctx, filepath.Join(src, f.rel), path.Join(dst, filepath.ToSlash(f.rel)), f.fi, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SyncDirFromDUT copies regular files in the directory src on the DUT to the
+// directory dst on the local machine, creating directories as needed. Files
+// whose SHA-256 checksums match those of files already in dst are skipped, so
+// that only new or updated files are transferred. Files in dst missing in src
+// are left as they are. Symbolic links and other non-regular files are
+// ignored. Each file is verified as with GetFileWithSHA256. progress is called
+// back with the total size of files to transfer if it is not nil.
+func (d *DUT) SyncDirFromDUT(ctx context.Context, src, dst string, progress ProgressFunc) error {
+	if err := d.checkConnected(); err != nil {
+		return err
+	}
+	remote, err := d.listRemoteDir(ctx, src)
+	if err != nil {
+		return err
+	}
+
+	var rels []string
+	p := &progressWriter{f: progress}
+	for rel, rf := range remote {
+		local := filepath.Join(dst, filepath.FromSlash(rel))
+		if fi, err := os.Stat(local); err == nil && fi.Size() == rf.size {
+			if sum, err := localSHA256(local); err == nil && sum == rf.sha256 {
+				continue
+			}
+		}
+		rels = append(rels, rel)
+		p.total += rf.size
+	}
+	sort.Strings(rels)
+
+	for _, rel := range rels {
+		if err := d.getFile(ctx, path.Join(src, rel), filepath.Join(dst, filepath.FromSlash(rel)), remote[rel].mode, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *DUT) checkConnected() error {
+	if d == nil || d.hst == nil {
+		return errors.New("DUT is not connected")
+	}
+	return nil
+}
+
+// putFile copies a local file src whose info is fi to dst on the DUT via a
+// temporary file verified with its SHA-256 checksum.
+func (d *DUT) putFile(ctx context.Context, src, dst string, fi fs.FileInfo, p *progressWriter) error {
+	if !fi.Mode().IsRegular() {
+		return errors.Errorf("%s is not a regular file", src)
+	}
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	tmp := dst + ".tast-tmp"
+	h := sha256.New()
+	cmd := d.hst.CommandContext(ctx, "sh", "-c", `mkdir -p "$(dirname "$0")" && cat > "$0" && chmod "$1" "$0"`, tmp, fmt.Sprintf("%o", fi.Mode().Perm()))
+	cmd.Stdin = io.TeeReader(f, io.MultiWriter(h, p))
+	if err := cmd.Run(ssh.DumpLogOnError); err != nil {
+		d.removeRemote(ctx, tmp)
+		return errors.Wrapf(err, "failed to copy %s to %s", src, dst)
+	}
+
+	want := hex.EncodeToString(h.Sum(nil))
+	got, err := d.remoteSHA256(ctx, tmp)
+	if err != nil {
+		d.removeRemote(ctx, tmp)
+		return err
+	}
+	if got != want {
+		d.removeRemote(ctx, tmp)
+		return errors.Errorf("SHA-256 checksum of %s on the DUT is %s; want %s", dst, got, want)
+	}
+	if err := d.hst.CommandContext(ctx, "mv", "-f", tmp, dst).Run(ssh.DumpLogOnError); err != nil {
+		d.removeRemote(ctx, tmp)
+		return errors.Wrapf(err, "failed to rename %s to %s", tmp, dst)
+	}
+	return nil
+}
+
+// getFile copies a file src on the DUT to dst on the local machine via a
+// temporary file verified with its SHA-256 checksum. mode is the permission
+// bits of the copied file.
+func (d *DUT) getFile(ctx context.Context, src, dst string, mode os.FileMode, p *progressWriter) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	defer os.Remove(tmp)
+	defer f.Close()
+
+	h := sha256.New()
+	cmd := d.hst.CommandContext(ctx, "cat", src)
+	cmd.Stdout = io.MultiWriter(f, h, p)
+	if err := cmd.Run(ssh.DumpLogOnError); err != nil {
+		return errors.Wrapf(err, "failed to copy %s to %s", src, dst)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	got := hex.EncodeToString(h.Sum(nil))
+	want, err := d.remoteSHA256(ctx, src)
+	if err != nil {
+		return err
+	}
+	if got != want {
+		return errors.Errorf("SHA-256 checksum of %s copied from the DUT is %s; want %s", dst, got, want)
+	}
+	if err := os.Chmod(tmp, mode.Perm()); err != nil {
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
+
+// remoteSHA256 returns the SHA-256 checksum of a file on the DUT as a
+// hexadecimal string.
+func (d *DUT) remoteSHA256(ctx context.Context, path string) (string, error) {
+	out, err := d.hst.CommandContext(ctx, "sh", "-c", `sha256sum < "$0"`, path).Output(ssh.DumpLogOnError)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to compute SHA-256 checksum of %s", path)
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) == 0 {
+		return "", errors.Errorf("failed to parse sha256sum output %q", out)
+	}
+	return fields[0], nil
+}
+
+// listRemoteDir returns regular files under the directory dir on the DUT,
+// keyed by their slash-separated paths relative to dir. It returns an empty
+// map if dir does not exist.
+func (d *DUT) listRemoteDir(ctx context.Context, dir string) (map[string]*remoteFile, error) {
+	// Each file is reported as "<sha256> <size> <mode> <path>\0", where <path>
+	// starts with "./".
+	const script = `cd "$0" 2>/dev/null || exit 0
+find . -type f -exec sh -c 'for f; do
+  h=$(sha256sum < "$f") || exit 1
+  s=$(stat -c "%s %a" "$f") || exit 1
+  printf "%s %s %s\0" "${h%% *}" "$s" "$f"
+done' sh {} +`
+	out, err := d.hst.CommandContext(ctx, "sh", "-c", script, dir).Output(ssh.DumpLogOnError)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list files in %s", dir)
+	}
+
+	files := make(map[string]*remoteFile)
+	for _, rec := range bytes.Split(out, []byte{0}) {
+		if len(rec) == 0 {
+			continue
+		}
+		fields := strings.SplitN(string(rec), " ", 4)
+		if len(fields) != 4 || !strings.HasPrefix(fields[3], "./") {
+			return nil, errors.Errorf("failed to parse file list entry %q", rec)
+		}
+		size, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse file list entry %q", rec)
+		}
+		mode, err := strconv.ParseUint(fields[2], 8, 32)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse file list entry %q", rec)
+		}
+		files[strings.TrimPrefix(fields[3], "./")] = &remoteFile{sha256: fields[0], size: size, mode: os.FileMode(mode)}
+	}
+	return files, nil
+}
+
+// removeRemote removes a file on the DUT, ignoring errors.
+func (d *DUT) removeRemote(ctx context.Context, path string) {
+	d.hst.CommandContext(ctx, "rm", "-f", path).Run()
+}
+
+// localSHA256 returns the SHA-256 checksum of a local file as a hexadecimal
+// string.
+func localSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
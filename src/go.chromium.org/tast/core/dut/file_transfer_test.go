@@ -0,0 +1,147 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package dut
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go.chromium.org/tast/core/internal/sshtest"
+	"go.chromium.org/tast/core/testutil"
+)
+
+// newTestDUT returns a DUT connected to a local SSH server running commands
+// on the local machine.
+func newTestDUT(t *testing.T) *DUT {
+	td := sshtest.NewTestDataConn(t)
+	t.Cleanup(td.Close)
+	return &DUT{hst: td.Hst}
+}
+
+func TestPutGetFileWithSHA256(t *testing.T) {
+	d := newTestDUT(t)
+	ctx := context.Background()
+
+	dir := testutil.TempDir(t)
+	defer os.RemoveAll(dir)
+	if err := testutil.WriteFiles(dir, map[string]string{"src.txt": "hello"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(filepath.Join(dir, "src.txt"), 0640); err != nil {
+		t.Fatal(err)
+	}
+
+	var last, total int64
+	progress := func(transferred, tot int64) { last, total = transferred, tot }
+
+	put := filepath.Join(dir, "dut/sub/put.txt")
+	if err := d.PutFileWithSHA256(ctx, filepath.Join(dir, "src.txt"), put, progress); err != nil {
+		t.Fatal("PutFileWithSHA256 failed: ", err)
+	}
+	if last != 5 || total != 5 {
+		t.Errorf("PutFileWithSHA256 reported progress %d/%d; want 5/5", last, total)
+	}
+
+	get := filepath.Join(dir, "host/get.txt")
+	if err := d.GetFileWithSHA256(ctx, put, get, nil); err != nil {
+		t.Fatal("GetFileWithSHA256 failed: ", err)
+	}
+
+	for _, p := range []string{put, get} {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != "hello" {
+			t.Errorf("%s has %q; want %q", p, b, "hello")
+		}
+		fi, err := os.Stat(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := fi.Mode().Perm(); perm != 0640 {
+			t.Errorf("%s has mode %o; want 640", p, perm)
+		}
+	}
+
+	if err := d.GetFileWithSHA256(ctx, filepath.Join(dir, "missing.txt"), get, nil); err == nil {
+		t.Error("GetFileWithSHA256 unexpectedly succeeded for a missing file")
+	}
+}
+
+func TestSyncDir(t *testing.T) {
+	d := newTestDUT(t)
+	ctx := context.Background()
+
+	dir := testutil.TempDir(t)
+	defer os.RemoveAll(dir)
+	src := filepath.Join(dir, "src")
+	dutDir := filepath.Join(dir, "dut")
+	dst := filepath.Join(dir, "dst")
+
+	files := map[string]string{
+		"a.txt":         "aaa",
+		"sub/b.txt":     "bb",
+		"sub/sub/c.txt": "c",
+		"strange $()'":  "x",
+	}
+	if err := testutil.WriteFiles(src, files); err != nil {
+		t.Fatal(err)
+	}
+
+	var total int64
+	progress := func(transferred, tot int64) { total = tot }
+
+	if err := d.SyncDirToDUT(ctx, src, dutDir, progress); err != nil {
+		t.Fatal("SyncDirToDUT failed: ", err)
+	}
+	if total != 7 {
+		t.Errorf("SyncDirToDUT reported total %d; want 7", total)
+	}
+
+	// Only the updated file should be transferred.
+	files["sub/b.txt"] = "BBBB"
+	if err := testutil.WriteFiles(src, map[string]string{"sub/b.txt": "BBBB"}); err != nil {
+		t.Fatal(err)
+	}
+	total = 0
+	if err := d.SyncDirToDUT(ctx, src, dutDir, progress); err != nil {
+		t.Fatal("SyncDirToDUT failed: ", err)
+	}
+	if total != 4 {
+		t.Errorf("SyncDirToDUT reported total %d; want 4", total)
+	}
+
+	got, err := testutil.ReadFiles(dutDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got, files); diff != "" {
+		t.Errorf("Files on the DUT mismatch (-got +want):\n%s", diff)
+	}
+
+	if err := d.SyncDirFromDUT(ctx, dutDir, dst, nil); err != nil {
+		t.Fatal("SyncDirFromDUT failed: ", err)
+	}
+	got, err = testutil.ReadFiles(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got, files); diff != "" {
+		t.Errorf("Files copied from the DUT mismatch (-got +want):\n%s", diff)
+	}
+
+	total = -1
+	if err := d.SyncDirFromDUT(ctx, dutDir, dst, progress); err != nil {
+		t.Fatal("SyncDirFromDUT failed: ", err)
+	}
+	if total != -1 {
+		t.Errorf("SyncDirFromDUT transferred files with total %d; want no transfer", total)
+	}
+}
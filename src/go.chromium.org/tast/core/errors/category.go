@@ -0,0 +1,82 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package errors
+
+import (
+	"fmt"
+	"io"
+)
+
+// Category classifies the cause of an error. Categories of errors reported by
+// tests are saved to results, so that failures can be routed automatically,
+// e.g. infrastructure errors can be excluded from test health metrics.
+type Category string
+
+const (
+	// CategoryInfra indicates that the test infrastructure failed, e.g. the
+	// lab network or a servo.
+	CategoryInfra Category = "INFRA"
+	// CategoryProduct indicates that the product under test misbehaved.
+	CategoryProduct Category = "PRODUCT"
+	// CategoryTestBug indicates that the test itself is broken.
+	CategoryTestBug Category = "TEST_BUG"
+	// CategoryEnvironment indicates that the environment of the DUT is
+	// unexpected, e.g. a peripheral is missing.
+	CategoryEnvironment Category = "ENVIRONMENT"
+)
+
+// categorized is an error annotated with a Category.
+type categorized struct {
+	cause    error
+	category Category
+}
+
+// Error implements the error interface.
+func (e *categorized) Error() string {
+	return e.cause.Error()
+}
+
+// Unwrap implements the error Unwrap interface introduced in go1.13.
+func (e *categorized) Unwrap() error {
+	return e.cause
+}
+
+// Format implements the fmt.Formatter interface.
+func (e *categorized) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		io.WriteString(s, formatChain(e))
+	} else {
+		io.WriteString(s, e.Error())
+	}
+}
+
+// WithCategory returns an error annotating err with the category c. The
+// message of the returned error is the same as err. If err is nil, it returns
+// nil.
+//
+// Errors passed to error-reporting methods in testing.State have their
+// categories saved to results.
+//
+//	if err := servo.Connect(ctx); err != nil {
+//	    s.Fatal("Failed to connect to servo: ", errors.WithCategory(err, errors.CategoryInfra))
+//	}
+func WithCategory(err error, c Category) error {
+	if err == nil {
+		return nil
+	}
+	return &categorized{cause: err, category: c}
+}
+
+// CategoryOf returns the category of err. If errors in err's chain are
+// annotated with multiple categories, the outermost one is returned, so that
+// callers can reclassify errors returned by functions they call. If err has
+// no category, it returns an empty string.
+func CategoryOf(err error) Category {
+	var c *categorized
+	if As(err, &c) {
+		return c.category
+	}
+	return ""
+}
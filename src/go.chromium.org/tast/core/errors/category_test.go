@@ -0,0 +1,50 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package errors_test
+
+import (
+	stderrors "errors"
+	"regexp"
+	"testing"
+
+	"go.chromium.org/tast/core/errors"
+)
+
+func TestWithCategory(t *testing.T) {
+	const msg = "meow: woof"
+	traceRegexp := regexp.MustCompile(`(?s)^meow
+	at go.chromium.org/tast/core/errors_test\.TestWithCategory \(category_test.go:\d+\)
+.*
+woof
+	at go.chromium.org/tast/core/errors_test\.TestWithCategory \(category_test.go:\d+\)`)
+
+	base := errors.New("woof")
+	err := errors.Wrap(errors.WithCategory(base, errors.CategoryInfra), "meow")
+
+	check(t, err, msg, traceRegexp)
+	if c := errors.CategoryOf(err); c != errors.CategoryInfra {
+		t.Errorf("CategoryOf(err) = %q; want %q", c, errors.CategoryInfra)
+	}
+	if !errors.Is(err, base) {
+		t.Error("Is(err, base) should be true")
+	}
+}
+
+func TestWithCategoryOutermost(t *testing.T) {
+	inner := errors.WithCategory(stderrors.New("woof"), errors.CategoryProduct)
+	err := errors.WithCategory(errors.Wrap(inner, "meow"), errors.CategoryTestBug)
+	if c := errors.CategoryOf(err); c != errors.CategoryTestBug {
+		t.Errorf("CategoryOf(err) = %q; want %q", c, errors.CategoryTestBug)
+	}
+}
+
+func TestWithCategoryNil(t *testing.T) {
+	if err := errors.WithCategory(nil, errors.CategoryInfra); err != nil {
+		t.Errorf("WithCategory(nil, CategoryInfra) = %v; want nil", err)
+	}
+	if c := errors.CategoryOf(errors.New("meow")); c != "" {
+		t.Errorf("CategoryOf(uncategorized) = %q; want empty", c)
+	}
+}
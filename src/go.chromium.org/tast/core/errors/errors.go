@@ -91,6 +91,10 @@ func (e *E) unwrap() (msg string, stk stack.Stack, cause error) {
 func formatChain(err error) string {
 	var chain []string
 	for err != nil {
+		if c, ok := err.(*categorized); ok {
+			err = c.cause
+			continue
+		}
 		if e, ok := err.(unwrapper); ok {
 			msg, stk, cause := e.unwrap()
 			chain = append(chain, fmt.Sprintf("%s\n%v", msg, stk))
@@ -599,6 +599,90 @@ type HardwareFeatures struct {
 	HardwareFeatures       *api.HardwareFeatures    `protobuf:"bytes,1,opt,name=hardware_features,json=hardwareFeatures,proto3" json:"hardware_features,omitempty"`
 	DeprecatedDeviceConfig *DeprecatedDeviceConfig  `protobuf:"bytes,3,opt,name=deprecated_device_config,json=deprecatedDeviceConfig,proto3" json:"deprecated_device_config,omitempty"`
 	SoftwareConfig         *software.SoftwareConfig `protobuf:"bytes,4,opt,name=software_config,json=softwareConfig,proto3" json:"software_config,omitempty"`
+	// GpuMemoryMegabytes is the size of dedicated VRAM of the GPU in
+	// megabytes. It is 0 if unknown, e.g. for integrated GPUs sharing the
+	// system memory.
+	GpuMemoryMegabytes int32 `protobuf:"varint,5,opt,name=gpu_memory_megabytes,json=gpuMemoryMegabytes,proto3" json:"gpu_memory_megabytes,omitempty"`
+	// ThunderboltDockAttached is true if a Thunderbolt dock is attached to the
+	// device and connected.
+	ThunderboltDockAttached bool `protobuf:"varint,6,opt,name=thunderbolt_dock_attached,json=thunderboltDockAttached,proto3" json:"thunderbolt_dock_attached,omitempty"`
+	// ExternalGpuAttached is true if a GPU is attached to the device via an
+	// external (e.g. Thunderbolt) PCI link.
+	ExternalGpuAttached bool `protobuf:"varint,7,opt,name=external_gpu_attached,json=externalGpuAttached,proto3" json:"external_gpu_attached,omitempty"`
+	// KernelModules contains names of kernel modules loaded on the device, as
+	// listed in /proc/modules.
+	KernelModules []string `protobuf:"bytes,8,rep,name=kernel_modules,json=kernelModules,proto3" json:"kernel_modules,omitempty"`
+	// DeviceTreeCompatible contains compatible strings of the root node of the
+	// device tree, e.g. "google,kukui". It is empty on devices without a
+	// device tree.
+	DeviceTreeCompatible []string `protobuf:"bytes,9,rep,name=device_tree_compatible,json=deviceTreeCompatible,proto3" json:"device_tree_compatible,omitempty"`
+	// ExternalDisplayConnectors contains types of connectors of external
+	// displays connected to the device, one per display: "HDMI", "DP",
+	// "USB-C" (DisplayPort over USB Type-C) or "DVI".
+	ExternalDisplayConnectors []string `protobuf:"bytes,10,rep,name=external_display_connectors,json=externalDisplayConnectors,proto3" json:"external_display_connectors,omitempty"`
+	// InstalledDlcs maps IDs of DLCs (downloadable content) installed on the
+	// device to their versions, e.g. "1.0.0-r10". A version is empty if
+	// unknown.
+	InstalledDlcs map[string]string `protobuf:"bytes,11,rep,name=installed_dlcs,json=installedDlcs,proto3" json:"installed_dlcs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// EthernetPresent is true if the device has a wired Ethernet interface,
+	// either built-in or attached via USB.
+	EthernetPresent bool `protobuf:"varint,12,opt,name=ethernet_present,json=ethernetPresent,proto3" json:"ethernet_present,omitempty"`
+	// MaxNicSpeedMbps is the maximum link speed in Mbps of wired network
+	// interfaces whose links are up. It is 0 if unknown.
+	MaxNicSpeedMbps int32 `protobuf:"varint,13,opt,name=max_nic_speed_mbps,json=maxNicSpeedMbps,proto3" json:"max_nic_speed_mbps,omitempty"`
+	// WifiPhyBands contains frequency bands supported by Wi-Fi PHYs of the
+	// device: "2.4GHz", "5GHz", "6GHz" or "60GHz".
+	WifiPhyBands []string `protobuf:"bytes,14,rep,name=wifi_phy_bands,json=wifiPhyBands,proto3" json:"wifi_phy_bands,omitempty"`
+	// VmType is the type of the virtual machine the device runs on: "betty",
+	// "tast_vm" or "crosvm". It is empty if the device is not a virtual
+	// machine.
+	VmType string `protobuf:"bytes,15,opt,name=vm_type,json=vmType,proto3" json:"vm_type,omitempty"`
+	// FpmcuRwVersion is the version of the RW firmware running on the
+	// fingerprint MCU, e.g. "nocturne_fp_v2.2.110-b936c0a3c". It is empty if
+	// the device has no fingerprint MCU or the version is unknown.
+	FpmcuRwVersion string `protobuf:"bytes,16,opt,name=fpmcu_rw_version,json=fpmcuRwVersion,proto3" json:"fpmcu_rw_version,omitempty"`
+	// FpmcuRollbackSupported is true if the firmware of the fingerprint MCU
+	// supports rollback protection.
+	FpmcuRollbackSupported bool `protobuf:"varint,17,opt,name=fpmcu_rollback_supported,json=fpmcuRollbackSupported,proto3" json:"fpmcu_rollback_supported,omitempty"`
+	// SdCardReaderPresent is true if the device has an SD or microSD card
+	// reader attached to an MMC host controller.
+	SdCardReaderPresent bool `protobuf:"varint,18,opt,name=sd_card_reader_present,json=sdCardReaderPresent,proto3" json:"sd_card_reader_present,omitempty"`
+	// SdCardInserted is true if an SD or microSD card is inserted to a card
+	// reader of the device.
+	SdCardInserted bool `protobuf:"varint,19,opt,name=sd_card_inserted,json=sdCardInserted,proto3" json:"sd_card_inserted,omitempty"`
+	// BluetoothDevice is the ID of the Bluetooth controller of the device
+	// defined in go.chromium.org/tast/core/testing/bluetooth. It is 0 if the
+	// controller is unknown.
+	BluetoothDevice int32 `protobuf:"varint,20,opt,name=bluetooth_device,json=bluetoothDevice,proto3" json:"bluetooth_device,omitempty"`
+	// BluetoothVersion is the Bluetooth Core Specification version supported
+	// by the Bluetooth controller of the device, e.g. "5.2". It is empty if
+	// unknown.
+	BluetoothVersion string `protobuf:"bytes,21,opt,name=bluetooth_version,json=bluetoothVersion,proto3" json:"bluetooth_version,omitempty"`
+	// HasTypecDpAltMode indicates whether a USB Type-C port of the device
+	// supports DisplayPort alternate mode.
+	HasTypecDpAltMode bool `protobuf:"varint,22,opt,name=has_typec_dp_alt_mode,json=hasTypecDpAltMode,proto3" json:"has_typec_dp_alt_mode,omitempty"`
+	// HasTypecTbtAltMode indicates whether a USB Type-C port of the device
+	// supports Thunderbolt alternate mode.
+	HasTypecTbtAltMode bool `protobuf:"varint,23,opt,name=has_typec_tbt_alt_mode,json=hasTypecTbtAltMode,proto3" json:"has_typec_tbt_alt_mode,omitempty"`
+	// EcProtocolVersion is the highest host command protocol version supported
+	// by the EC, as reported by "ectool protocolinfo". Zero if unknown.
+	EcProtocolVersion uint32 `protobuf:"varint,24,opt,name=ec_protocol_version,json=ecProtocolVersion,proto3" json:"ec_protocol_version,omitempty"`
+	// HasWidevineL1 indicates whether the device supports Widevine security
+	// level 1, i.e. a hardware-backed OEMCrypto implementation is available to
+	// the content decryption module.
+	HasWidevineL1 bool `protobuf:"varint,25,opt,name=has_widevine_l1,json=hasWidevineL1,proto3" json:"has_widevine_l1,omitempty"`
+	// InternalDisplayRefreshRateHz is the highest refresh rate of the internal
+	// display in Hz, rounded to the nearest integer, as advertised in its EDID.
+	// Zero if the device has no internal display or the rate is unknown.
+	InternalDisplayRefreshRateHz uint32 `protobuf:"varint,26,opt,name=internal_display_refresh_rate_hz,json=internalDisplayRefreshRateHz,proto3" json:"internal_display_refresh_rate_hz,omitempty"`
+	// ModemFirmwareVersion is the firmware revision of the cellular modem as
+	// reported by ModemManager. Empty if the device has no modem or the version
+	// is unknown.
+	ModemFirmwareVersion string `protobuf:"bytes,27,opt,name=modem_firmware_version,json=modemFirmwareVersion,proto3" json:"modem_firmware_version,omitempty"`
+	// CameraIsp is the version of the camera image signal processor of the
+	// device, e.g. "IPU6EP" for Intel IPU6 on Alder Lake. Empty if the device
+	// has no known ISP.
+	CameraIsp string `protobuf:"bytes,28,opt,name=camera_isp,json=cameraIsp,proto3" json:"camera_isp,omitempty"`
 }
 
 func (x *HardwareFeatures) Reset() {
@@ -654,6 +738,174 @@ func (x *HardwareFeatures) GetSoftwareConfig() *software.SoftwareConfig {
 	return nil
 }
 
+func (x *HardwareFeatures) GetGpuMemoryMegabytes() int32 {
+	if x != nil {
+		return x.GpuMemoryMegabytes
+	}
+	return 0
+}
+
+func (x *HardwareFeatures) GetThunderboltDockAttached() bool {
+	if x != nil {
+		return x.ThunderboltDockAttached
+	}
+	return false
+}
+
+func (x *HardwareFeatures) GetExternalGpuAttached() bool {
+	if x != nil {
+		return x.ExternalGpuAttached
+	}
+	return false
+}
+
+func (x *HardwareFeatures) GetKernelModules() []string {
+	if x != nil {
+		return x.KernelModules
+	}
+	return nil
+}
+
+func (x *HardwareFeatures) GetDeviceTreeCompatible() []string {
+	if x != nil {
+		return x.DeviceTreeCompatible
+	}
+	return nil
+}
+
+func (x *HardwareFeatures) GetExternalDisplayConnectors() []string {
+	if x != nil {
+		return x.ExternalDisplayConnectors
+	}
+	return nil
+}
+
+func (x *HardwareFeatures) GetInstalledDlcs() map[string]string {
+	if x != nil {
+		return x.InstalledDlcs
+	}
+	return nil
+}
+
+func (x *HardwareFeatures) GetEthernetPresent() bool {
+	if x != nil {
+		return x.EthernetPresent
+	}
+	return false
+}
+
+func (x *HardwareFeatures) GetMaxNicSpeedMbps() int32 {
+	if x != nil {
+		return x.MaxNicSpeedMbps
+	}
+	return 0
+}
+
+func (x *HardwareFeatures) GetWifiPhyBands() []string {
+	if x != nil {
+		return x.WifiPhyBands
+	}
+	return nil
+}
+
+func (x *HardwareFeatures) GetVmType() string {
+	if x != nil {
+		return x.VmType
+	}
+	return ""
+}
+
+func (x *HardwareFeatures) GetFpmcuRwVersion() string {
+	if x != nil {
+		return x.FpmcuRwVersion
+	}
+	return ""
+}
+
+func (x *HardwareFeatures) GetFpmcuRollbackSupported() bool {
+	if x != nil {
+		return x.FpmcuRollbackSupported
+	}
+	return false
+}
+
+func (x *HardwareFeatures) GetSdCardReaderPresent() bool {
+	if x != nil {
+		return x.SdCardReaderPresent
+	}
+	return false
+}
+
+func (x *HardwareFeatures) GetSdCardInserted() bool {
+	if x != nil {
+		return x.SdCardInserted
+	}
+	return false
+}
+
+func (x *HardwareFeatures) GetBluetoothDevice() int32 {
+	if x != nil {
+		return x.BluetoothDevice
+	}
+	return 0
+}
+
+func (x *HardwareFeatures) GetBluetoothVersion() string {
+	if x != nil {
+		return x.BluetoothVersion
+	}
+	return ""
+}
+
+func (x *HardwareFeatures) GetHasTypecDpAltMode() bool {
+	if x != nil {
+		return x.HasTypecDpAltMode
+	}
+	return false
+}
+
+func (x *HardwareFeatures) GetHasTypecTbtAltMode() bool {
+	if x != nil {
+		return x.HasTypecTbtAltMode
+	}
+	return false
+}
+
+func (x *HardwareFeatures) GetEcProtocolVersion() uint32 {
+	if x != nil {
+		return x.EcProtocolVersion
+	}
+	return 0
+}
+
+func (x *HardwareFeatures) GetHasWidevineL1() bool {
+	if x != nil {
+		return x.HasWidevineL1
+	}
+	return false
+}
+
+func (x *HardwareFeatures) GetInternalDisplayRefreshRateHz() uint32 {
+	if x != nil {
+		return x.InternalDisplayRefreshRateHz
+	}
+	return 0
+}
+
+func (x *HardwareFeatures) GetModemFirmwareVersion() string {
+	if x != nil {
+		return x.ModemFirmwareVersion
+	}
+	return ""
+}
+
+func (x *HardwareFeatures) GetCameraIsp() string {
+	if x != nil {
+		return x.CameraIsp
+	}
+	return ""
+}
+
 var File_dutfeatures_proto protoreflect.FileDescriptor
 
 var file_dutfeatures_proto_rawDesc = []byte{
@@ -773,7 +1025,7 @@ var file_dutfeatures_proto_rawDesc = []byte{
 	0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x18, 0x0a, 0x14, 0x50, 0x4f, 0x57, 0x45, 0x52, 0x5f, 0x53,
 	0x55, 0x50, 0x50, 0x4c, 0x59, 0x5f, 0x42, 0x41, 0x54, 0x54, 0x45, 0x52, 0x59, 0x10, 0x01, 0x12,
 	0x18, 0x0a, 0x14, 0x50, 0x4f, 0x57, 0x45, 0x52, 0x5f, 0x53, 0x55, 0x50, 0x50, 0x4c, 0x59, 0x5f,
-	0x41, 0x43, 0x5f, 0x4f, 0x4e, 0x4c, 0x59, 0x10, 0x02, 0x22, 0xa4, 0x02, 0x0a, 0x10, 0x48, 0x61,
+	0x41, 0x43, 0x5f, 0x4f, 0x4e, 0x4c, 0x59, 0x10, 0x02, 0x22, 0x89, 0x0c, 0x0a, 0x10, 0x48, 0x61,
 	0x72, 0x64, 0x77, 0x61, 0x72, 0x65, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x12, 0x54,
 	0x0a, 0x11, 0x68, 0x61, 0x72, 0x64, 0x77, 0x61, 0x72, 0x65, 0x5f, 0x66, 0x65, 0x61, 0x74, 0x75,
 	0x72, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x63, 0x68, 0x72, 0x6f,
@@ -791,11 +1043,89 @@ var file_dutfeatures_proto_rawDesc = []byte{
 	0x6f, 0x6d, 0x69, 0x75, 0x6d, 0x6f, 0x73, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x61,
 	0x70, 0x69, 0x2e, 0x73, 0x6f, 0x66, 0x74, 0x77, 0x61, 0x72, 0x65, 0x2e, 0x53, 0x6f, 0x66, 0x74,
 	0x77, 0x61, 0x72, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x0e, 0x73, 0x6f, 0x66, 0x74,
-	0x77, 0x61, 0x72, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x4a, 0x04, 0x08, 0x02, 0x10, 0x03,
-	0x42, 0x2e, 0x5a, 0x2c, 0x67, 0x6f, 0x2e, 0x63, 0x68, 0x72, 0x6f, 0x6d, 0x69, 0x75, 0x6d, 0x2e,
-	0x6f, 0x72, 0x67, 0x2f, 0x74, 0x61, 0x73, 0x74, 0x2f, 0x63, 0x6f, 0x72, 0x65, 0x2f, 0x66, 0x72,
-	0x61, 0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x77, 0x61, 0x72, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x30, 0x0a, 0x14, 0x67, 0x70,
+	0x75, 0x5f, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x5f, 0x6d, 0x65, 0x67, 0x61, 0x62, 0x79, 0x74,
+	0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x12, 0x67, 0x70, 0x75, 0x4d, 0x65, 0x6d,
+	0x6f, 0x72, 0x79, 0x4d, 0x65, 0x67, 0x61, 0x62, 0x79, 0x74, 0x65, 0x73, 0x12, 0x3a, 0x0a, 0x19,
+	0x74, 0x68, 0x75, 0x6e, 0x64, 0x65, 0x72, 0x62, 0x6f, 0x6c, 0x74, 0x5f, 0x64, 0x6f, 0x63, 0x6b,
+	0x5f, 0x61, 0x74, 0x74, 0x61, 0x63, 0x68, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x17, 0x74, 0x68, 0x75, 0x6e, 0x64, 0x65, 0x72, 0x62, 0x6f, 0x6c, 0x74, 0x44, 0x6f, 0x63, 0x6b,
+	0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x65, 0x64, 0x12, 0x32, 0x0a, 0x15, 0x65, 0x78, 0x74, 0x65,
+	0x72, 0x6e, 0x61, 0x6c, 0x5f, 0x67, 0x70, 0x75, 0x5f, 0x61, 0x74, 0x74, 0x61, 0x63, 0x68, 0x65,
+	0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x13, 0x65, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61,
+	0x6c, 0x47, 0x70, 0x75, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x65, 0x64, 0x12, 0x25, 0x0a, 0x0e,
+	0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x5f, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x08,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x0d, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x4d, 0x6f, 0x64, 0x75,
+	0x6c, 0x65, 0x73, 0x12, 0x34, 0x0a, 0x16, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x74, 0x72,
+	0x65, 0x65, 0x5f, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x74, 0x69, 0x62, 0x6c, 0x65, 0x18, 0x09, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x14, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x54, 0x72, 0x65, 0x65, 0x43,
+	0x6f, 0x6d, 0x70, 0x61, 0x74, 0x69, 0x62, 0x6c, 0x65, 0x12, 0x3e, 0x0a, 0x1b, 0x65, 0x78, 0x74,
+	0x65, 0x72, 0x6e, 0x61, 0x6c, 0x5f, 0x64, 0x69, 0x73, 0x70, 0x6c, 0x61, 0x79, 0x5f, 0x63, 0x6f,
+	0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x09, 0x52, 0x19,
+	0x65, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x44, 0x69, 0x73, 0x70, 0x6c, 0x61, 0x79, 0x43,
+	0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x12, 0x55, 0x0a, 0x0e, 0x69, 0x6e, 0x73,
+	0x74, 0x61, 0x6c, 0x6c, 0x65, 0x64, 0x5f, 0x64, 0x6c, 0x63, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x2e, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x48, 0x61,
+	0x72, 0x64, 0x77, 0x61, 0x72, 0x65, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x2e, 0x49,
+	0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x65, 0x64, 0x44, 0x6c, 0x63, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x52, 0x0d, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x65, 0x64, 0x44, 0x6c, 0x63, 0x73,
+	0x12, 0x29, 0x0a, 0x10, 0x65, 0x74, 0x68, 0x65, 0x72, 0x6e, 0x65, 0x74, 0x5f, 0x70, 0x72, 0x65,
+	0x73, 0x65, 0x6e, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x65, 0x74, 0x68, 0x65,
+	0x72, 0x6e, 0x65, 0x74, 0x50, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x74, 0x12, 0x2b, 0x0a, 0x12, 0x6d,
+	0x61, 0x78, 0x5f, 0x6e, 0x69, 0x63, 0x5f, 0x73, 0x70, 0x65, 0x65, 0x64, 0x5f, 0x6d, 0x62, 0x70,
+	0x73, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0f, 0x6d, 0x61, 0x78, 0x4e, 0x69, 0x63, 0x53,
+	0x70, 0x65, 0x65, 0x64, 0x4d, 0x62, 0x70, 0x73, 0x12, 0x24, 0x0a, 0x0e, 0x77, 0x69, 0x66, 0x69,
+	0x5f, 0x70, 0x68, 0x79, 0x5f, 0x62, 0x61, 0x6e, 0x64, 0x73, 0x18, 0x0e, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0c, 0x77, 0x69, 0x66, 0x69, 0x50, 0x68, 0x79, 0x42, 0x61, 0x6e, 0x64, 0x73, 0x12, 0x17,
+	0x0a, 0x07, 0x76, 0x6d, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x76, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x12, 0x28, 0x0a, 0x10, 0x66, 0x70, 0x6d, 0x63, 0x75,
+	0x5f, 0x72, 0x77, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x10, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0e, 0x66, 0x70, 0x6d, 0x63, 0x75, 0x52, 0x77, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x12, 0x38, 0x0a, 0x18, 0x66, 0x70, 0x6d, 0x63, 0x75, 0x5f, 0x72, 0x6f, 0x6c, 0x6c, 0x62,
+	0x61, 0x63, 0x6b, 0x5f, 0x73, 0x75, 0x70, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x18, 0x11, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x16, 0x66, 0x70, 0x6d, 0x63, 0x75, 0x52, 0x6f, 0x6c, 0x6c, 0x62, 0x61,
+	0x63, 0x6b, 0x53, 0x75, 0x70, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x12, 0x33, 0x0a, 0x16, 0x73,
+	0x64, 0x5f, 0x63, 0x61, 0x72, 0x64, 0x5f, 0x72, 0x65, 0x61, 0x64, 0x65, 0x72, 0x5f, 0x70, 0x72,
+	0x65, 0x73, 0x65, 0x6e, 0x74, 0x18, 0x12, 0x20, 0x01, 0x28, 0x08, 0x52, 0x13, 0x73, 0x64, 0x43,
+	0x61, 0x72, 0x64, 0x52, 0x65, 0x61, 0x64, 0x65, 0x72, 0x50, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x74,
+	0x12, 0x28, 0x0a, 0x10, 0x73, 0x64, 0x5f, 0x63, 0x61, 0x72, 0x64, 0x5f, 0x69, 0x6e, 0x73, 0x65,
+	0x72, 0x74, 0x65, 0x64, 0x18, 0x13, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x73, 0x64, 0x43, 0x61,
+	0x72, 0x64, 0x49, 0x6e, 0x73, 0x65, 0x72, 0x74, 0x65, 0x64, 0x12, 0x29, 0x0a, 0x10, 0x62, 0x6c,
+	0x75, 0x65, 0x74, 0x6f, 0x6f, 0x74, 0x68, 0x5f, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x18, 0x14,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x0f, 0x62, 0x6c, 0x75, 0x65, 0x74, 0x6f, 0x6f, 0x74, 0x68, 0x44,
+	0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x2b, 0x0a, 0x11, 0x62, 0x6c, 0x75, 0x65, 0x74, 0x6f, 0x6f,
+	0x74, 0x68, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x15, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x10, 0x62, 0x6c, 0x75, 0x65, 0x74, 0x6f, 0x6f, 0x74, 0x68, 0x56, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x12, 0x30, 0x0a, 0x15, 0x68, 0x61, 0x73, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x63, 0x5f,
+	0x64, 0x70, 0x5f, 0x61, 0x6c, 0x74, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x16, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x11, 0x68, 0x61, 0x73, 0x54, 0x79, 0x70, 0x65, 0x63, 0x44, 0x70, 0x41, 0x6c, 0x74,
+	0x4d, 0x6f, 0x64, 0x65, 0x12, 0x32, 0x0a, 0x16, 0x68, 0x61, 0x73, 0x5f, 0x74, 0x79, 0x70, 0x65,
+	0x63, 0x5f, 0x74, 0x62, 0x74, 0x5f, 0x61, 0x6c, 0x74, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x17,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x12, 0x68, 0x61, 0x73, 0x54, 0x79, 0x70, 0x65, 0x63, 0x54, 0x62,
+	0x74, 0x41, 0x6c, 0x74, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x2e, 0x0a, 0x13, 0x65, 0x63, 0x5f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x18, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x11, 0x65, 0x63, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f,
+	0x6c, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x26, 0x0a, 0x0f, 0x68, 0x61, 0x73, 0x5f,
+	0x77, 0x69, 0x64, 0x65, 0x76, 0x69, 0x6e, 0x65, 0x5f, 0x6c, 0x31, 0x18, 0x19, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0d, 0x68, 0x61, 0x73, 0x57, 0x69, 0x64, 0x65, 0x76, 0x69, 0x6e, 0x65, 0x4c, 0x31,
+	0x12, 0x46, 0x0a, 0x20, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x5f, 0x64, 0x69, 0x73,
+	0x70, 0x6c, 0x61, 0x79, 0x5f, 0x72, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x5f, 0x72, 0x61, 0x74,
+	0x65, 0x5f, 0x68, 0x7a, 0x18, 0x1a, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x1c, 0x69, 0x6e, 0x74, 0x65,
+	0x72, 0x6e, 0x61, 0x6c, 0x44, 0x69, 0x73, 0x70, 0x6c, 0x61, 0x79, 0x52, 0x65, 0x66, 0x72, 0x65,
+	0x73, 0x68, 0x52, 0x61, 0x74, 0x65, 0x48, 0x7a, 0x12, 0x34, 0x0a, 0x16, 0x6d, 0x6f, 0x64, 0x65,
+	0x6d, 0x5f, 0x66, 0x69, 0x72, 0x6d, 0x77, 0x61, 0x72, 0x65, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x18, 0x1b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x14, 0x6d, 0x6f, 0x64, 0x65, 0x6d, 0x46,
+	0x69, 0x72, 0x6d, 0x77, 0x61, 0x72, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1d,
+	0x0a, 0x0a, 0x63, 0x61, 0x6d, 0x65, 0x72, 0x61, 0x5f, 0x69, 0x73, 0x70, 0x18, 0x1c, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x63, 0x61, 0x6d, 0x65, 0x72, 0x61, 0x49, 0x73, 0x70, 0x1a, 0x40, 0x0a,
+	0x12, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x65, 0x64, 0x44, 0x6c, 0x63, 0x73, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x4a,
+	0x04, 0x08, 0x02, 0x10, 0x03, 0x42, 0x2e, 0x5a, 0x2c, 0x67, 0x6f, 0x2e, 0x63, 0x68, 0x72, 0x6f,
+	0x6d, 0x69, 0x75, 0x6d, 0x2e, 0x6f, 0x72, 0x67, 0x2f, 0x74, 0x61, 0x73, 0x74, 0x2f, 0x63, 0x6f,
+	0x72, 0x65, 0x2f, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x77, 0x6f, 0x72, 0x6b, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -811,7 +1141,7 @@ func file_dutfeatures_proto_rawDescGZIP() []byte {
 }
 
 var file_dutfeatures_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
-var file_dutfeatures_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_dutfeatures_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
 var file_dutfeatures_proto_goTypes = []interface{}{
 	(DeprecatedDeviceConfig_SOC)(0),          // 0: tast.core.DeprecatedDeviceConfig.SOC
 	(DeprecatedDeviceConfig_Architecture)(0), // 1: tast.core.DeprecatedDeviceConfig.Architecture
@@ -821,24 +1151,26 @@ var file_dutfeatures_proto_goTypes = []interface{}{
 	(*DeprecatedConfigId)(nil),               // 5: tast.core.DeprecatedConfigId
 	(*DeprecatedDeviceConfig)(nil),           // 6: tast.core.DeprecatedDeviceConfig
 	(*HardwareFeatures)(nil),                 // 7: tast.core.HardwareFeatures
-	(*api.HardwareFeatures)(nil),             // 8: chromiumos.config.api.HardwareFeatures
-	(*software.SoftwareConfig)(nil),          // 9: chromiumos.config.api.software.SoftwareConfig
+	nil,                                      // 8: tast.core.HardwareFeatures.InstalledDlcsEntry
+	(*api.HardwareFeatures)(nil),             // 9: chromiumos.config.api.HardwareFeatures
+	(*software.SoftwareConfig)(nil),          // 10: chromiumos.config.api.software.SoftwareConfig
 }
 var file_dutfeatures_proto_depIdxs = []int32{
-	4, // 0: tast.core.DUTFeatures.software:type_name -> tast.core.SoftwareFeatures
-	7, // 1: tast.core.DUTFeatures.hardware:type_name -> tast.core.HardwareFeatures
-	5, // 2: tast.core.DeprecatedDeviceConfig.id:type_name -> tast.core.DeprecatedConfigId
-	0, // 3: tast.core.DeprecatedDeviceConfig.soc:type_name -> tast.core.DeprecatedDeviceConfig.SOC
-	1, // 4: tast.core.DeprecatedDeviceConfig.cpu:type_name -> tast.core.DeprecatedDeviceConfig.Architecture
-	2, // 5: tast.core.DeprecatedDeviceConfig.power:type_name -> tast.core.DeprecatedDeviceConfig.PowerSupply
-	8, // 6: tast.core.HardwareFeatures.hardware_features:type_name -> chromiumos.config.api.HardwareFeatures
-	6, // 7: tast.core.HardwareFeatures.deprecated_device_config:type_name -> tast.core.DeprecatedDeviceConfig
-	9, // 8: tast.core.HardwareFeatures.software_config:type_name -> chromiumos.config.api.software.SoftwareConfig
-	9, // [9:9] is the sub-list for method output_type
-	9, // [9:9] is the sub-list for method input_type
-	9, // [9:9] is the sub-list for extension type_name
-	9, // [9:9] is the sub-list for extension extendee
-	0, // [0:9] is the sub-list for field type_name
+	4,  // 0: tast.core.DUTFeatures.software:type_name -> tast.core.SoftwareFeatures
+	7,  // 1: tast.core.DUTFeatures.hardware:type_name -> tast.core.HardwareFeatures
+	5,  // 2: tast.core.DeprecatedDeviceConfig.id:type_name -> tast.core.DeprecatedConfigId
+	0,  // 3: tast.core.DeprecatedDeviceConfig.soc:type_name -> tast.core.DeprecatedDeviceConfig.SOC
+	1,  // 4: tast.core.DeprecatedDeviceConfig.cpu:type_name -> tast.core.DeprecatedDeviceConfig.Architecture
+	2,  // 5: tast.core.DeprecatedDeviceConfig.power:type_name -> tast.core.DeprecatedDeviceConfig.PowerSupply
+	9,  // 6: tast.core.HardwareFeatures.hardware_features:type_name -> chromiumos.config.api.HardwareFeatures
+	6,  // 7: tast.core.HardwareFeatures.deprecated_device_config:type_name -> tast.core.DeprecatedDeviceConfig
+	10, // 8: tast.core.HardwareFeatures.software_config:type_name -> chromiumos.config.api.software.SoftwareConfig
+	8,  // 9: tast.core.HardwareFeatures.installed_dlcs:type_name -> tast.core.HardwareFeatures.InstalledDlcsEntry
+	10, // [10:10] is the sub-list for method output_type
+	10, // [10:10] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_dutfeatures_proto_init() }
@@ -914,7 +1246,7 @@ func file_dutfeatures_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_dutfeatures_proto_rawDesc,
 			NumEnums:      3,
-			NumMessages:   5,
+			NumMessages:   6,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
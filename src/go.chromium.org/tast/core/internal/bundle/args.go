@@ -26,6 +26,7 @@ const (
 	modeDumpTests
 	modeRPCTCP
 	modeDumpFixtures
+	modeRPCUDS
 )
 
 type dumpFormat int
@@ -39,7 +40,10 @@ type parsedArgs struct {
 	mode       mode
 	dumpFormat dumpFormat
 	// rpctcp mode only
-	port      int
+	port int
+	// rpcuds mode only
+	socket string
+	// rpctcp and rpcuds modes only
 	handshake *protocol.HandshakeRequest
 }
 
@@ -63,7 +67,10 @@ func readArgs(clArgs []string, stderr io.Writer) (*parsedArgs, error) {
 	rpctcp := flags.Bool("rpctcp", false, "run gRPC server listening on TCP. Sample usage:\n"+
 		"  cros -rpctcp -port 4444 -handshake [HANDSHAKE_BASE64]")
 	port := flags.Int("port", 4444, "port number for gRPC server. Only applicable for rpctcp mode")
-	handshakeUsage := "Handshake request for setting up gRPC server. Only applicable for rpctcp mode.\n" +
+	rpcuds := flags.Bool("rpcuds", false, "run gRPC server listening on a Unix domain socket accessible only by the current user. Sample usage:\n"+
+		"  cros -rpcuds -socket /run/tast/cros.sock -handshake [HANDSHAKE_BASE64]")
+	socket := flags.String("socket", "", "path of the Unix domain socket for gRPC server. Only applicable for rpcuds mode")
+	handshakeUsage := "Handshake request for setting up gRPC server. Only applicable for rpctcp and rpcuds modes.\n" +
 		"Request should adhere to handshake.proto and be encoded in base64. Example in golang:\n" +
 		"  var req *HandshakeRequest = ...\n" +
 		"  raw, _ := proto.Marshal(req)\n" +
@@ -96,6 +103,20 @@ func readArgs(clArgs []string, stderr io.Writer) (*parsedArgs, error) {
 			handshake: &handshakeReq,
 		}, nil
 	}
+	if *rpcuds {
+		if *socket == "" {
+			return nil, command.NewStatusErrorf(statusBadArgs, "-socket is required for rpcuds mode")
+		}
+		var handshakeReq protocol.HandshakeRequest
+		if err := decodeBase64Proto(*handshakeBase64, &handshakeReq); err != nil {
+			return nil, command.NewStatusErrorf(statusBadArgs, "failed to decode handshake into proto: %v", err)
+		}
+		return &parsedArgs{
+			mode:      modeRPCUDS,
+			socket:    *socket,
+			handshake: &handshakeReq,
+		}, nil
+	}
 	flags.Usage()
 	return nil, errors.New("no mode flag is set")
 }
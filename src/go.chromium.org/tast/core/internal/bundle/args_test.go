@@ -58,3 +58,31 @@ func TestReadArgsRpcTcpServer(t *testing.T) {
 		t.Errorf("BundleArgs mismatch (-got +want):\n%s", diff)
 	}
 }
+
+func TestReadArgsRpcUdsServer(t *testing.T) {
+	const socket = "/run/tast/cros.sock"
+	req := &protocol.HandshakeRequest{NeedUserServices: true}
+	raw, err := proto.Marshal(req)
+	if err != nil {
+		t.Fatal("Fail to serialize proto: ", err)
+	}
+	args := []string{"-rpcuds", "-socket", socket, "-handshake", base64.StdEncoding.EncodeToString(raw)}
+
+	got, err := readArgs(args, io.Discard)
+	if err != nil {
+		t.Fatal("readArgs failed: ", err)
+	}
+	if got.mode != modeRPCUDS {
+		t.Errorf("mode = %v, want %v", got.mode, modeRPCUDS)
+	}
+	if got.socket != socket {
+		t.Errorf("socket = %q, want %q", got.socket, socket)
+	}
+	if diff := cmp.Diff(got.handshake, req, protocmp.Transform()); diff != "" {
+		t.Errorf("BundleArgs mismatch (-got +want):\n%s", diff)
+	}
+
+	if _, err := readArgs([]string{"-rpcuds"}, io.Discard); err == nil {
+		t.Error("readArgs unexpectedly succeeded without -socket")
+	}
+}
@@ -69,6 +69,13 @@ type Delegate struct {
 	// reasonable timeout at the beginning of the hook to avoid blocking
 	// for long time.
 	BeforeDownload func(ctx context.Context)
+
+	// Namespaced specifies whether tests in the test bundle are named
+	// "<bundle>/<category>.<Name>" instead of "<category>.<Name>", where
+	// <bundle> is the file name of the test bundle executable.
+	// Out-of-tree bundles, e.g. private and partner bundles, should set it
+	// to avoid collisions of test names with other bundles.
+	Namespaced bool
 }
 
 // run reads a JSON-marshaled BundleArgs struct from stdin and performs the requested action.
@@ -132,6 +139,8 @@ func run(ctx context.Context, clArgs []string, stdin io.Reader, stdout, stderr i
 			return command.WriteError(stderr, errors.Errorf("invalid dump format %v", args.dumpFormat))
 		}
 	case modeRPC:
+		// Let the tast command request stack traces when the bundle stalls.
+		defer command.InstallGoroutineDumpHandler(stderr)()
 		if err := RunRPCServer(stdin, stdout, scfg); err != nil {
 			return command.WriteError(stderr, err)
 		}
@@ -143,6 +152,11 @@ func run(ctx context.Context, clArgs []string, stdin io.Reader, stdout, stderr i
 			return command.WriteError(stderr, err)
 		}
 		return statusSuccess
+	case modeRPCUDS:
+		if err := RunRPCServerUDS(args.socket, args.handshake, stdout, stderr, scfg); err != nil {
+			return command.WriteError(stderr, err)
+		}
+		return statusSuccess
 	default:
 		return command.WriteError(stderr, command.NewStatusErrorf(statusBadArgs, "invalid mode %v", args.mode))
 	}
@@ -239,7 +239,11 @@ func (l *fixtureServiceLogger) EntityError(ei *protocol.Entity, e *protocol.Erro
 	return l.Error(e)
 }
 
-func (l *fixtureServiceLogger) EntityEnd(ei *protocol.Entity, skipReasons []string, timingLog *timing.Log) error {
+func (l *fixtureServiceLogger) FixtureError(ei *protocol.Entity, phase protocol.FixturePhase, e *protocol.Error) error {
+	return l.Error(e)
+}
+
+func (l *fixtureServiceLogger) EntityEnd(ei *protocol.Entity, skip *protocol.Skip, consumedVars []string, timingLog *timing.Log) error {
 	return nil
 }
 
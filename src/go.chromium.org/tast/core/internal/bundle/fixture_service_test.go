@@ -505,8 +505,16 @@ func TestFixtureServiceTimeout(t *gotesting.T) {
 		t.Fatal(err)
 	}
 
+	// The timeout is reported as an error of the fixture, then the call fails.
+	res, err := rfcl.Recv()
+	if err != nil {
+		t.Fatal("rfcl.Recv() failed: ", err)
+	}
+	if res.GetError() == nil {
+		t.Errorf("rfcl.Recv() = %v, want fixture timeout error", res)
+	}
 	if _, err := rfcl.Recv(); err == nil || err == io.EOF {
-		t.Errorf("rfcl.Recv() = %v, want fixture timeout error", err)
+		t.Errorf("rfcl.Recv() = %v, want RPC error", err)
 	}
 }
 
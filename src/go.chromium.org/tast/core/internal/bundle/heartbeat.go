@@ -8,15 +8,22 @@ import (
 	"time"
 )
 
+// defaultHeartbeatInterval is the interval of heartbeat messages used if it
+// is not specified in RunConfig.
+const defaultHeartbeatInterval = time.Second
+
 // heartbeatWriter writes heartbeat messages periodically to eventWriter.
 type heartbeatWriter struct {
 	fin chan struct{} // sending a message to this channel stops the background goroutine
 }
 
-// newHeartbeatWriter constructs a new heartbeatWriter for ew.
-// Stop must be called after use to stop the background goroutine.
-func newHeartbeatWriter(ew *eventWriter) *heartbeatWriter {
-	const interval = time.Second
+// newHeartbeatWriter constructs a new heartbeatWriter for ew writing messages
+// every interval. If interval is not positive, defaultHeartbeatInterval is
+// used. Stop must be called after use to stop the background goroutine.
+func newHeartbeatWriter(ew *eventWriter, interval time.Duration) *heartbeatWriter {
+	if interval <= 0 {
+		interval = defaultHeartbeatInterval
+	}
 
 	fin := make(chan struct{})
 
@@ -20,6 +20,9 @@ const (
 //
 // Main function of remote test bundles should call RemoteDefault instead.
 func Remote(clArgs []string, stdin io.Reader, stdout, stderr io.Writer, reg *testing.Registry, d Delegate) int {
+	if d.Namespaced {
+		reg.NamespaceTests()
+	}
 	cfg := NewStaticConfig(reg, remoteTestTimeout, d)
 	return run(context.Background(), clArgs, stdin, stdout, stderr, cfg)
 }
@@ -50,6 +50,23 @@ func RunRPCServerTCP(port int, handshakeReq *protocol.HandshakeRequest, stdin io
 	})
 }
 
+// RunRPCServerUDS runs the bundle as an RPC server listening on a Unix domain
+// socket at socketPath. Unlike RunRPCServerTCP, fixture services are also
+// available so that harnesses on the DUT can set up and tear down fixtures.
+func RunRPCServerUDS(socketPath string, handshakeReq *protocol.HandshakeRequest, stdout, stderr io.Writer, scfg *StaticConfig) error {
+	reg := scfg.registry
+	return rpc.RunUnixServer(socketPath, handshakeReq, stdout, stderr, reg.AllServices(), func(srv *grpc.Server, req *protocol.HandshakeRequest) error {
+		if err := checkRegistrationErrors(reg); err != nil {
+			return err
+		}
+		registerFixtureService(srv, reg)
+		if err := reg.InitializeVars(req.GetBundleInitParams().GetVars()); err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
 func checkRegistrationErrors(reg *testing.Registry) error {
 	if errs := reg.Errors(); len(errs) > 0 {
 		msgs := make([]string, len(errs))
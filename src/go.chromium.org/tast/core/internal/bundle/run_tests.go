@@ -14,6 +14,7 @@ import (
 	"sync"
 	"time"
 
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"go.chromium.org/tast/core/dut"
@@ -25,6 +26,8 @@ import (
 	"go.chromium.org/tast/core/internal/testcontext"
 	"go.chromium.org/tast/core/internal/testing"
 	"go.chromium.org/tast/core/internal/timing"
+	"go.chromium.org/tast/core/phone"
+	"go.chromium.org/tast/core/servo"
 )
 
 // testsToRun returns a sorted list of tests to run for the given patterns.
@@ -62,7 +65,7 @@ func runTests(ctx context.Context, srv protocol.TestService_RunTestsServer, cfg
 
 	ew := newEventWriter(srv)
 
-	hbw := newHeartbeatWriter(ew)
+	hbw := newHeartbeatWriter(ew, cfg.GetHeartbeatInterval().AsDuration())
 	defer hbw.Stop()
 
 	logger := logging.NewFuncLogger(ew.RunLog)
@@ -101,6 +104,14 @@ func runTests(ctx context.Context, srv protocol.TestService_RunTestsServer, cfg
 		StartFixtureName: cfg.GetStartFixtureState().GetName(),
 		StartFixtureImpl: &stubFixture{setUpErrors: cfg.GetStartFixtureState().GetErrors()},
 		MaxSysMsgLogSize: cfg.GetMaxSysMsgLogSize(),
+		MaxParallelTests: int(cfg.GetParallel()),
+		LogRateLimit:     int(cfg.GetLogRateLimit()),
+		LogBurst:         int(cfg.GetLogBurst()),
+		DiscardDebugLogs: cfg.GetDiscardDebugLogs(),
+		ScopedLogLevels:  cfg.GetScopedLogLevels(),
+		Seed:             cfg.GetSeed(),
+		TestOrder:        cfg.GetTestOrder(),
+		RunMetadata:      protocol.StringPairsToMap(cfg.GetRunMetadata()),
 	}
 
 	if err := planner.RunTestsLegacy(ctx, tests, ew, pcfg); err != nil {
@@ -117,6 +128,25 @@ type eventWriter struct {
 	srv protocol.TestService_RunTestsServer
 	lg  *syslog.Writer
 	mu  sync.Mutex // used to synchronize Send calls to srv
+
+	// Instance IDs are assigned to entity runs so that the consumer can
+	// demultiplex events of entities running concurrently.
+	lastID int64
+	// ids maps names of running entities to their instance IDs. Entities
+	// running concurrently always have distinct names.
+	ids map[string]int64
+	// externalIDs maps instance IDs given by external test bundles to
+	// instance IDs assigned by eventWriter.
+	externalIDs map[int64]int64
+	// running lists entities currently running, including ones in external
+	// test bundles, in the order they started. It is reported in heartbeats.
+	running []runningEntity
+}
+
+// runningEntity is an entity currently running.
+type runningEntity struct {
+	name  string
+	start time.Time
 }
 
 var _ planner.OutputStream = (*eventWriter)(nil)
@@ -124,7 +154,12 @@ var _ planner.OutputStream = (*eventWriter)(nil)
 func newEventWriter(srv protocol.TestService_RunTestsServer) *eventWriter {
 	// Continue even if we fail to connect to syslog.
 	lg, _ := syslog.New(syslog.LOG_INFO, "tast")
-	return &eventWriter{srv: srv, lg: lg}
+	return &eventWriter{
+		srv:         srv,
+		lg:          lg,
+		ids:         make(map[string]int64),
+		externalIDs: make(map[int64]int64),
+	}
 }
 
 func (ew *eventWriter) RunLog(level logging.Level, ts time.Time, msg string) {
@@ -146,10 +181,14 @@ func (ew *eventWriter) EntityStart(ei *protocol.Entity, outDir string) error {
 	if ew.lg != nil {
 		ew.lg.Info(fmt.Sprintf("%s: ======== start", ei.Name))
 	}
+	ew.lastID++
+	ew.ids[ei.GetName()] = ew.lastID
+	ew.entityStarted(ei.GetName())
 	return ew.srv.Send(&protocol.RunTestsResponse{Type: &protocol.RunTestsResponse_EntityStart{EntityStart: &protocol.EntityStartEvent{
-		Time:   timestamppb.Now(),
-		Entity: ei,
-		OutDir: outDir,
+		Time:       timestamppb.Now(),
+		Entity:     ei,
+		OutDir:     outDir,
+		InstanceId: ew.lastID,
 	}}})
 }
 
@@ -164,10 +203,19 @@ func (ew *eventWriter) EntityLog(ei *protocol.Entity, level logging.Level, ts ti
 		EntityName: ei.GetName(),
 		Text:       msg,
 		Level:      protocol.LevelToProto(level),
+		InstanceId: ew.ids[ei.GetName()],
 	}}})
 }
 
 func (ew *eventWriter) EntityError(ei *protocol.Entity, e *protocol.Error) error {
+	return ew.entityError(ei, protocol.FixturePhase_FIXTURE_PHASE_UNSPECIFIED, e)
+}
+
+func (ew *eventWriter) FixtureError(ei *protocol.Entity, phase protocol.FixturePhase, e *protocol.Error) error {
+	return ew.entityError(ei, phase, e)
+}
+
+func (ew *eventWriter) entityError(ei *protocol.Entity, phase protocol.FixturePhase, e *protocol.Error) error {
 	ew.mu.Lock()
 	defer ew.mu.Unlock()
 	if ew.lg != nil {
@@ -175,32 +223,34 @@ func (ew *eventWriter) EntityError(ei *protocol.Entity, e *protocol.Error) error
 		ew.lg.Info(fmt.Sprintf("%s: Error at %s:%d: %s", ei.GetName(), filepath.Base(loc.GetFile()), loc.GetLine(), e.GetReason()))
 	}
 	return ew.srv.Send(&protocol.RunTestsResponse{Type: &protocol.RunTestsResponse_EntityError{EntityError: &protocol.EntityErrorEvent{
-		Time:       timestamppb.Now(),
-		EntityName: ei.GetName(),
-		Error:      e,
+		Time:         timestamppb.Now(),
+		EntityName:   ei.GetName(),
+		Error:        e,
+		InstanceId:   ew.ids[ei.GetName()],
+		FixturePhase: phase,
 	}}})
 }
 
-func (ew *eventWriter) EntityEnd(ei *protocol.Entity, skipReasons []string, timingLog *timing.Log) error {
+func (ew *eventWriter) EntityEnd(ei *protocol.Entity, skip *protocol.Skip, consumedVars []string, timingLog *timing.Log) error {
 	ew.mu.Lock()
 	defer ew.mu.Unlock()
 	if ew.lg != nil {
 		ew.lg.Info(fmt.Sprintf("%s: ======== end", ei.GetName()))
 	}
-	var skip *protocol.Skip
-	if len(skipReasons) > 0 {
-		skip = &protocol.Skip{Reasons: skipReasons}
-	}
 	tlpb, err := timingLog.Proto()
 	if err != nil {
 		return err
 	}
 	firstErr := ew.srv.Send(&protocol.RunTestsResponse{Type: &protocol.RunTestsResponse_EntityEnd{EntityEnd: &protocol.EntityEndEvent{
-		Time:       timestamppb.Now(),
-		EntityName: ei.GetName(),
-		Skip:       skip,
-		TimingLog:  tlpb,
+		Time:         timestamppb.Now(),
+		EntityName:   ei.GetName(),
+		Skip:         skip,
+		TimingLog:    tlpb,
+		InstanceId:   ew.ids[ei.GetName()],
+		ConsumedVars: consumedVars,
 	}}})
+	delete(ew.ids, ei.GetName())
+	ew.entityEnded(ei.GetName())
 	// An entity in the current bundle is run. It means the output files are
 	// already in the local directory, ready to be copied.
 	if err := ew.srv.Send(&protocol.RunTestsResponse{Type: &protocol.RunTestsResponse_EntityCopyEnd{EntityCopyEnd: &protocol.EntityCopyEndEvent{
@@ -214,9 +264,49 @@ func (ew *eventWriter) EntityEnd(ei *protocol.Entity, skipReasons []string, timi
 func (ew *eventWriter) ExternalEvent(req *protocol.RunTestsResponse) error {
 	ew.mu.Lock()
 	defer ew.mu.Unlock()
+	ew.translateExternalID(req)
 	return ew.srv.Send(req)
 }
 
+// translateExternalID replaces an instance ID in req given by an external
+// test bundle with one assigned by ew, so that it does not conflict with
+// instance IDs of entities run in this test bundle. ew.mu must be held.
+func (ew *eventWriter) translateExternalID(req *protocol.RunTestsResponse) {
+	switch t := req.GetType().(type) {
+	case *protocol.RunTestsResponse_EntityStart:
+		ew.entityStarted(t.EntityStart.GetEntity().GetName())
+		if id := t.EntityStart.GetInstanceId(); id != 0 {
+			ew.lastID++
+			ew.externalIDs[id] = ew.lastID
+			t.EntityStart.InstanceId = ew.lastID
+		}
+	case *protocol.RunTestsResponse_EntityLog:
+		t.EntityLog.InstanceId = ew.externalIDs[t.EntityLog.GetInstanceId()]
+	case *protocol.RunTestsResponse_EntityError:
+		t.EntityError.InstanceId = ew.externalIDs[t.EntityError.GetInstanceId()]
+	case *protocol.RunTestsResponse_EntityEnd:
+		ew.entityEnded(t.EntityEnd.GetEntityName())
+		id := t.EntityEnd.GetInstanceId()
+		t.EntityEnd.InstanceId = ew.externalIDs[id]
+		delete(ew.externalIDs, id)
+	}
+}
+
+// entityStarted records that an entity has started. ew.mu must be held.
+func (ew *eventWriter) entityStarted(name string) {
+	ew.running = append(ew.running, runningEntity{name: name, start: time.Now()})
+}
+
+// entityEnded records that an entity has ended. ew.mu must be held.
+func (ew *eventWriter) entityEnded(name string) {
+	for i, e := range ew.running {
+		if e.name == name {
+			ew.running = append(ew.running[:i], ew.running[i+1:]...)
+			return
+		}
+	}
+}
+
 func (ew *eventWriter) StackOperation(ctx context.Context, req *protocol.StackOperationRequest) (*protocol.StackOperationResponse, error) {
 	ew.mu.Lock()
 	defer ew.mu.Unlock()
@@ -240,9 +330,14 @@ func (ew *eventWriter) StackOperation(ctx context.Context, req *protocol.StackOp
 func (ew *eventWriter) Heartbeat() error {
 	ew.mu.Lock()
 	defer ew.mu.Unlock()
-	return ew.srv.Send(&protocol.RunTestsResponse{Type: &protocol.RunTestsResponse_Heartbeat{Heartbeat: &protocol.HeartbeatEvent{
-		Time: timestamppb.Now(),
-	}}})
+	now := time.Now()
+	ev := &protocol.HeartbeatEvent{Time: timestamppb.New(now)}
+	if n := len(ew.running); n > 0 {
+		e := ew.running[n-1]
+		ev.EntityName = e.name
+		ev.Elapsed = durationpb.New(now.Sub(e.start))
+	}
+	return ew.srv.Send(&protocol.RunTestsResponse{Type: &protocol.RunTestsResponse_Heartbeat{Heartbeat: ev}})
 }
 
 // connectToTarget connects to the target DUT and returns its connection.
@@ -339,6 +434,18 @@ func (c *connectionEnv) close(ctx context.Context) {
 			d.Close(ctx)
 		}
 	}
+
+	if c.rd.Servo != nil {
+		if err := c.rd.Servo.Close(ctx); err != nil {
+			logging.Infof(ctx, "Failed to close servo: %v", err)
+		}
+	}
+
+	for role, p := range c.rd.Phones {
+		if err := p.Close(ctx); err != nil {
+			logging.Infof(ctx, "Failed to disconnect from phone %q: %v", role, err)
+		}
+	}
 }
 
 // setUpConnection sets up a connection to a test bundle in another device bcfg
@@ -371,6 +478,30 @@ func setUpConnection(ctx context.Context, scfg *StaticConfig, cfg *protocol.RunC
 		}()
 		companionDUTs[role] = d
 	}
+	var svo *servo.Servo
+	if spec := bcfg.GetServoSpec(); spec != "" {
+		svo, err = servo.New(spec)
+		if err != nil {
+			return nil, command.NewStatusErrorf(statusError, "failed to set up servo: %v", err)
+		}
+	}
+	phones := make(map[string]*phone.Device)
+	for role, spec := range bcfg.GetPhones() {
+		p, err := phone.New(spec)
+		if err != nil {
+			return nil, command.NewStatusErrorf(statusError, "failed to set up phone %q: %v", role, err)
+		}
+		logging.Infof(ctx, "Connecting to phone %s", p.Serial())
+		if err := p.Connect(ctx); err != nil {
+			return nil, command.NewStatusErrorf(statusError, "failed to connect to phone %q: %v", role, err)
+		}
+		defer func() {
+			if retErr != nil {
+				p.Close(ctx)
+			}
+		}()
+		phones[role] = p
+	}
 	// Copy information on files pushed by Tast to DUTs.
 	var pushedFilesPaths = make(map[string]map[string]string)
 	for _, pathsInfo := range cfg.GetPushedFilesInfo() {
@@ -393,6 +524,8 @@ func setUpConnection(ctx context.Context, scfg *StaticConfig, cfg *protocol.RunC
 			RPCHint:       testing.NewRPCHint(pt.GetBundleDir(), cfg.GetFeatures().GetInfra().GetVars()),
 			DUT:           dt,
 			CompanionDUTs: companionDUTs,
+			Servo:         svo,
+			Phones:        phones,
 		},
 	}, nil
 }
@@ -60,7 +60,7 @@ func runTestsRecursive(ctx context.Context, srv protocol.TestService_RunTestsSer
 
 	ew := newEventWriter(srv)
 
-	hbw := newHeartbeatWriter(ew)
+	hbw := newHeartbeatWriter(ew, rcfg.GetHeartbeatInterval().AsDuration())
 	defer hbw.Stop()
 
 	ctx = logging.AttachLoggerNoPropagation(ctx, logging.NewFuncLogger(ew.RunLog))
@@ -141,6 +141,13 @@ func runTestsRecursive(ctx context.Context, srv protocol.TestService_RunTestsSer
 			Bundle: scfg.registry.Name(),
 		},
 		MaxSysMsgLogSize: rcfg.GetMaxSysMsgLogSize(),
+		LogRateLimit:     int(rcfg.GetLogRateLimit()),
+		LogBurst:         int(rcfg.GetLogBurst()),
+		DiscardDebugLogs: rcfg.GetDiscardDebugLogs(),
+		ScopedLogLevels:  rcfg.GetScopedLogLevels(),
+		Seed:             rcfg.GetSeed(),
+		TestOrder:        rcfg.GetTestOrder(),
+		RunMetadata:      protocol.StringPairsToMap(rcfg.GetRunMetadata()),
 	}
 
 	var internal []*protocol.ResolvedEntity
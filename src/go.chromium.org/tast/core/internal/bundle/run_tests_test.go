@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"strings"
+	"sync"
 	gotesting "testing"
 	"time"
 
@@ -26,6 +27,7 @@ import (
 	"go.chromium.org/tast/core/internal/rpc"
 	"go.chromium.org/tast/core/internal/sshtest"
 	"go.chromium.org/tast/core/internal/testing"
+	"go.chromium.org/tast/core/internal/timing"
 	"go.chromium.org/tast/core/testutil"
 )
 
@@ -167,6 +169,85 @@ func TestRunTests(t *gotesting.T) {
 	}
 }
 
+func TestRunTestsParallel(t *gotesting.T) {
+	const (
+		name1 = "foo.Test1"
+		name2 = "foo.Test2"
+	)
+
+	// Tests wait for each other to start, so they succeed only if they run
+	// concurrently.
+	var started sync.WaitGroup
+	started.Add(2)
+	testFunc := func(ctx context.Context, s *testing.State) {
+		started.Done()
+		done := make(chan struct{})
+		go func() {
+			started.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-ctx.Done():
+			s.Fatal("Other test did not start")
+		}
+		s.Log("Log from ", s.TestName())
+	}
+
+	reg := testing.NewRegistry("bundle")
+	reg.AddTestInstance(&testing.TestInstance{Name: name1, Func: testFunc, Resources: []string{"a"}, Timeout: 10 * time.Second})
+	reg.AddTestInstance(&testing.TestInstance{Name: name2, Func: testFunc, Resources: []string{"b"}, Timeout: 10 * time.Second})
+
+	tmpDir := testutil.TempDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	cfg := &protocol.RunConfig{
+		Tests: []string{name1, name2},
+		Dirs: &protocol.RunDirectories{
+			OutDir:  tmpDir,
+			DataDir: tmpDir,
+			TempDir: tmpDir,
+		},
+		Parallel: 2,
+	}
+	cl := startTestServer(t, NewStaticConfig(reg, 0, Delegate{}), &protocol.HandshakeRequest{})
+
+	events, err := protocoltest.RunTestsForEvents(context.Background(), cl, cfg, protocoltest.WithEntityLogs())
+	if err != nil {
+		t.Fatalf("RunTests failed: %v", err)
+	}
+
+	// Events of each test carry the instance ID given at its start.
+	ids := make(map[string]int64)
+	for _, ev := range events {
+		var name string
+		var id int64
+		switch ev := ev.(type) {
+		case *protocol.EntityStartEvent:
+			if ev.GetInstanceId() == 0 {
+				t.Errorf("EntityStart for %s has no instance ID", ev.GetEntity().GetName())
+			}
+			ids[ev.GetEntity().GetName()] = ev.GetInstanceId()
+			continue
+		case *protocol.EntityLogEvent:
+			name, id = ev.GetEntityName(), ev.GetInstanceId()
+		case *protocol.EntityErrorEvent:
+			t.Errorf("%s failed: %s", ev.GetEntityName(), ev.GetError().GetReason())
+			continue
+		case *protocol.EntityEndEvent:
+			name, id = ev.GetEntityName(), ev.GetInstanceId()
+		default:
+			continue
+		}
+		if id != ids[name] {
+			t.Errorf("%T for %s has instance ID %d; want %d", ev, name, id, ids[name])
+		}
+	}
+	if ids[name1] == ids[name2] {
+		t.Errorf("Tests have the same instance ID %d", ids[name1])
+	}
+}
+
 func TestRunTestsNoTests(t *gotesting.T) {
 	// RunTests should report success when no test is executed.
 	cl := startTestServer(t, NewStaticConfig(testing.NewRegistry("bundle"), 0, Delegate{}), &protocol.HandshakeRequest{})
@@ -933,3 +1014,45 @@ func TestRunTestsRemotepushedFilesPaths(t *gotesting.T) {
 		t.Errorf("Companion pushed pathss mismatch (-got +want):\n%s", diff)
 	}
 }
+
+// recordingRunTestsServer is a fake TestService_RunTestsServer recording sent
+// messages.
+type recordingRunTestsServer struct {
+	protocol.TestService_RunTestsServer
+	sent []*protocol.RunTestsResponse
+}
+
+func (s *recordingRunTestsServer) Send(res *protocol.RunTestsResponse) error {
+	s.sent = append(s.sent, res)
+	return nil
+}
+
+func TestEventWriterHeartbeat(t *gotesting.T) {
+	srv := &recordingRunTestsServer{}
+	ew := newEventWriter(srv)
+
+	lastHeartbeat := func() *protocol.HeartbeatEvent {
+		t.Helper()
+		if err := ew.Heartbeat(); err != nil {
+			t.Fatal("Heartbeat failed: ", err)
+		}
+		return srv.sent[len(srv.sent)-1].GetHeartbeat()
+	}
+
+	if hb := lastHeartbeat(); hb.GetEntityName() != "" {
+		t.Errorf("Heartbeat reported %q running before any entity starts", hb.GetEntityName())
+	}
+
+	fixt := &protocol.Entity{Name: "fixt", Type: protocol.EntityType_FIXTURE}
+	test := &protocol.Entity{Name: "pkg.Test"}
+	ew.EntityStart(fixt, "")
+	ew.EntityStart(test, "")
+	if hb := lastHeartbeat(); hb.GetEntityName() != "pkg.Test" || hb.GetElapsed() == nil {
+		t.Errorf("Heartbeat reported %q running for %v; want pkg.Test", hb.GetEntityName(), hb.GetElapsed())
+	}
+
+	ew.EntityEnd(test, nil, nil, timing.NewLog())
+	if hb := lastHeartbeat(); hb.GetEntityName() != "fixt" {
+		t.Errorf("Heartbeat reported %q running; want fixt", hb.GetEntityName())
+	}
+}
@@ -135,9 +135,9 @@ func listEntities(reg *testing.Registry, features *protocol.Features) []*protoco
 		// If we encounter errors while checking test dependencies,
 		// treat the test as not skipped. When we actually try to
 		// run the test later, it will fail with errors.
-		var skip *protocol.Skip
-		if reasons, err := t.Deps().Check(features); err == nil && len(reasons) > 0 {
-			skip = &protocol.Skip{Reasons: reasons}
+		skip, err := t.Deps().CheckSkip(features)
+		if err != nil {
+			skip = nil
 		}
 		start, ok := starts[t.Fixture]
 		if !ok {
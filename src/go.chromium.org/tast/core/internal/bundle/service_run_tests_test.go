@@ -547,7 +547,7 @@ func TestTestServiceRunTests_LocalTestRemoteFixtureFailures(t *gotesting.T) {
 		&protocol.EntityEndEvent{EntityName: localNoSuchFixture.Name},
 
 		&protocol.EntityStartEvent{Entity: failSetUpRemoteFixture.EntityProto()},
-		&protocol.EntityErrorEvent{EntityName: failSetUpRemoteFixture.Name, Error: &protocol.Error{Reason: "SetUp fail"}},
+		&protocol.EntityErrorEvent{EntityName: failSetUpRemoteFixture.Name, Error: &protocol.Error{Reason: "SetUp fail"}, FixturePhase: protocol.FixturePhase_SET_UP},
 		&protocol.EntityEndEvent{EntityName: failSetUpRemoteFixture.Name},
 		&protocol.EntityStartEvent{Entity: localFailSetUpRemoteFixture.EntityProto()},
 		&protocol.EntityErrorEvent{EntityName: localFailSetUpRemoteFixture.Name, Error: &protocol.Error{Reason: "[Fixture failure] failSetUpRemoteFixture: SetUp fail"}},
@@ -557,7 +557,7 @@ func TestTestServiceRunTests_LocalTestRemoteFixtureFailures(t *gotesting.T) {
 		&protocol.EntityStartEvent{Entity: localFailTearDownRemoteFixture.EntityProto()},
 		&protocol.EntityLogEvent{EntityName: localFailTearDownRemoteFixture.Name, Text: "Test run", Level: protocol.LogLevel_INFO},
 		&protocol.EntityEndEvent{EntityName: localFailTearDownRemoteFixture.Name},
-		&protocol.EntityErrorEvent{EntityName: failTearDownRemoteFixture.Name, Error: &protocol.Error{Reason: "TearDown fail"}},
+		&protocol.EntityErrorEvent{EntityName: failTearDownRemoteFixture.Name, Error: &protocol.Error{Reason: "TearDown fail"}, FixturePhase: protocol.FixturePhase_TEAR_DOWN},
 		&protocol.EntityEndEvent{EntityName: failTearDownRemoteFixture.Name},
 	}
 	if diff := cmp.Diff(events, wantEvents, protocoltest.EventCmpOpts...); diff != "" {
@@ -792,7 +792,7 @@ func TestTestServiceRunTests_ExternalResetSetUpFailure(t *gotesting.T) {
 
 		&protocol.EntityStartEvent{Entity: fixture.EntityProto()},
 		&protocol.EntityLogEvent{EntityName: fixture.Name, Text: "SetUp called", Level: protocol.LogLevel_INFO},
-		&protocol.EntityErrorEvent{EntityName: fixture.Name, Error: &protocol.Error{Reason: "SetUp failed"}},
+		&protocol.EntityErrorEvent{EntityName: fixture.Name, Error: &protocol.Error{Reason: "SetUp failed"}, FixturePhase: protocol.FixturePhase_SET_UP},
 		&protocol.EntityEndEvent{EntityName: fixture.Name},
 
 		&protocol.EntityStartEvent{Entity: localTest2.EntityProto()},
@@ -117,7 +117,7 @@ func TestTestServerListEntitiesTestSkips(t *gotesting.T) {
 			// Test1 is not skipped.
 			{Entity: t1.EntityProto()},
 			// Test2 is skipped due to unavailable dep2.
-			{Entity: t2.EntityProto(), Skip: &protocol.Skip{Reasons: []string{"missing SoftwareDeps: dep2"}}},
+			{Entity: t2.EntityProto(), Skip: &protocol.Skip{Reasons: []string{"missing SoftwareDeps: dep2"}, TypedReasons: []*protocol.SkipReason{{Code: protocol.SkipReason_UNSATISFIED_SWDEP, Details: []string{"missing SoftwareDeps: dep2"}}}}},
 			// Test3 is not skipped due to a dependency check failure.
 			// It fails later when we actually attempt to run it.
 			{Entity: t3.EntityProto()},
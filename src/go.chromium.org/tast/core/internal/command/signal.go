@@ -35,14 +35,37 @@ func InstallSignalHandler(out io.Writer, callback func(sig os.Signal)) {
 	signal.Notify(ch, unix.SIGINT, unix.SIGTERM)
 }
 
+// GoroutineDumpSignal is the signal to request a process to dump stack traces
+// of all goroutines without exiting. See InstallGoroutineDumpHandler.
+const GoroutineDumpSignal = unix.SIGUSR1
+
+// InstallGoroutineDumpHandler installs a signal handler that dumps stack
+// traces of all goroutines to out on GoroutineDumpSignal. It is used to
+// diagnose stalled processes. The returned function uninstalls the handler.
+func InstallGoroutineDumpHandler(out io.Writer) (uninstall func()) {
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ch:
+				dumpGoroutines(out)
+			case <-done:
+				return
+			}
+		}
+	}()
+	signal.Notify(ch, GoroutineDumpSignal)
+	return func() {
+		signal.Stop(ch)
+		close(done)
+	}
+}
+
 func handleSIGTERM(out io.Writer) {
 	// SIGTERM is often sent by the parent process on timeout. In this
 	// case, print stack traces to help debugging.
-	fmt.Fprintf(out, "\n%s: Dumping all goroutines...\n\n", selfName)
-	if p := pprof.Lookup("goroutine"); p != nil {
-		p.WriteTo(out, 2)
-	}
-	fmt.Fprintf(out, "\n%s: Finished dumping goroutines\n", selfName)
+	dumpGoroutines(out)
 
 	// Also terminate all child processes with SIGTERM. This can recursively
 	// print stack traces.
@@ -64,3 +87,12 @@ func handleSIGTERM(out io.Writer) {
 		}
 	}
 }
+
+// dumpGoroutines writes stack traces of all goroutines to out.
+func dumpGoroutines(out io.Writer) {
+	fmt.Fprintf(out, "\n%s: Dumping all goroutines...\n\n", selfName)
+	if p := pprof.Lookup("goroutine"); p != nil {
+		p.WriteTo(out, 2)
+	}
+	fmt.Fprintf(out, "\n%s: Finished dumping goroutines\n", selfName)
+}
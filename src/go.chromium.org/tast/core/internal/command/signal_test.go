@@ -0,0 +1,55 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package command_test
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"go.chromium.org/tast/core/internal/command"
+)
+
+// syncBuffer is a goroutine-safe bytes.Buffer.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestInstallGoroutineDumpHandler(t *testing.T) {
+	var out syncBuffer
+	uninstall := command.InstallGoroutineDumpHandler(&out)
+	defer uninstall()
+
+	if err := unix.Kill(unix.Getpid(), command.GoroutineDumpSignal); err != nil {
+		t.Fatal("Failed to send signal: ", err)
+	}
+
+	const want = "Finished dumping goroutines"
+	for start := time.Now(); time.Since(start) < 10*time.Second; time.Sleep(10 * time.Millisecond) {
+		if strings.Contains(out.String(), want) {
+			break
+		}
+	}
+	if s := out.String(); !strings.Contains(s, want) || !strings.Contains(s, "TestInstallGoroutineDumpHandler") {
+		t.Errorf("Goroutine dump not written; got %q", s)
+	}
+}
@@ -106,6 +106,8 @@ func GetCrashes(dirs ...string) ([]string, error) {
 }
 
 // CopyNewFiles copies paths that are present in newPaths but not in oldPaths into dstDir.
+// Modification times of the files are preserved so that crashes can be attributed
+// to tests running at the time.
 // If maxPerExec is positive, it limits the maximum number of files that will be copied
 // for each base executable. The returned warnings map contains non-fatal errors keyed by
 // crash file paths.
@@ -125,8 +127,15 @@ func CopyNewFiles(ctx context.Context, dstDir string, newPaths, oldPaths []strin
 			continue
 		}
 
-		if err := fsutil.CopyFile(sp, filepath.Join(dstDir, filepath.Base(sp))); err != nil {
+		dp := filepath.Join(dstDir, filepath.Base(sp))
+		if err := fsutil.CopyFile(sp, dp); err != nil {
 			logging.Infof(ctx, "%s: %v", sp, err)
+			continue
+		}
+		if fi, err := os.Stat(sp); err == nil {
+			if err := os.Chtimes(dp, fi.ModTime(), fi.ModTime()); err != nil {
+				logging.Infof(ctx, "%s: %v", dp, err)
+			}
 		}
 	}
 	return nil
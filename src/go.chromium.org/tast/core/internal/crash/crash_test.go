@@ -11,6 +11,7 @@ import (
 	"reflect"
 	"sort"
 	"testing"
+	"time"
 
 	"go.chromium.org/tast/core/internal/crash"
 	"go.chromium.org/tast/core/testutil"
@@ -75,6 +76,11 @@ func TestCopyNewFiles(t *testing.T) {
 	d1 := writeCrashFile(t, sd, "d.meta", "d1")
 	d2 := writeCrashFile(t, sd, "d.core", "d2")
 
+	crashTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := os.Chtimes(c0.abs, crashTime, crashTime); err != nil {
+		t.Fatal(err)
+	}
+
 	dd := filepath.Join(td, "dst")
 	if err := os.MkdirAll(dd, 0755); err != nil {
 		t.Fatal(err)
@@ -99,4 +105,10 @@ func TestCopyNewFiles(t *testing.T) {
 	}; !reflect.DeepEqual(fs, exp) {
 		t.Errorf("CopyNewFiles(%v, %v, %v) wrote %v; want %v", dd, np, op, fs, exp)
 	}
+
+	if fi, err := os.Stat(filepath.Join(dd, c0.rel)); err != nil {
+		t.Error(err)
+	} else if !fi.ModTime().Equal(crashTime) {
+		t.Errorf("CopyNewFiles did not preserve the modification time of %s: got %v; want %v", c0.rel, fi.ModTime(), crashTime)
+	}
 }
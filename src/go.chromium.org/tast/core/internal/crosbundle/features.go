@@ -50,7 +50,7 @@ func GetDUTInfo(ctx context.Context, req *protocol.GetDUTInfoRequest) (*protocol
 		if err != nil {
 			return nil, err
 		}
-		hardwareFeatures, err = detectHardwareFeatures(ctx)
+		hardwareFeatures, err = detectHardwareFeatures(ctx, req.GetExtraUseFlags())
 		if err != nil {
 			return nil, err
 		}
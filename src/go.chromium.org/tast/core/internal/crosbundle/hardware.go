@@ -10,6 +10,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
 	"os/exec"
 	"path"
@@ -25,6 +26,8 @@ import (
 
 	"go.chromium.org/tast/core/errors"
 	"go.chromium.org/tast/core/internal/logging"
+	"go.chromium.org/tast/core/lsbrelease"
+	"go.chromium.org/tast/core/testing/bluetooth"
 	"go.chromium.org/tast/core/testing/wlan"
 
 	"go.chromium.org/tast/core/framework/protocol"
@@ -51,7 +54,9 @@ func crosConfig(path, prop string) (string, error) {
 
 // detectHardwareFeatures returns a device.Config and api.HardwareFeatures instances
 // some of whose members are filled based on runtime information.
-func detectHardwareFeatures(ctx context.Context) (*protocol.HardwareFeatures, error) {
+// extraUseFlags are USE flags given by -extrauseflags, which are used to detect
+// the type of the virtual machine the DUT runs on.
+func detectHardwareFeatures(ctx context.Context, extraUseFlags []string) (*protocol.HardwareFeatures, error) {
 	platform, err := func() (string, error) {
 		out, err := crosConfig("/identity", "platform-name")
 		if err != nil {
@@ -110,6 +115,16 @@ func detectHardwareFeatures(ctx context.Context) (*protocol.HardwareFeatures, er
 		logging.Infof(ctx, "Unknown has-side-volume-button: %v", err)
 	}
 
+	typecDPAltMode, typecTBTAltMode, err := typecAltModes()
+	if err != nil {
+		logging.Infof(ctx, "Unknown USB Type-C alternate modes: %v", err)
+	}
+
+	widevineL1, err := widevineL1Supported()
+	if err != nil {
+		logging.Infof(ctx, "Unknown Widevine L1 support: %v", err)
+	}
+
 	config := &protocol.DeprecatedDeviceConfig{
 		Id: &protocol.DeprecatedConfigId{
 			Platform: platform,
@@ -122,6 +137,10 @@ func detectHardwareFeatures(ctx context.Context) (*protocol.HardwareFeatures, er
 		HasVboot2:           vboot2,
 		HasSideVolumeButton: hasSideVolumeButton,
 	}
+	// These are filled in below and set to the top-level fields of the
+	// returned protocol.HardwareFeatures.
+	var internalDisplayRefreshRateHz, ecProtocolVersion uint32
+	var modemFirmwareVersion string
 	features := &configpb.HardwareFeatures{
 		Screen:                  &configpb.HardwareFeatures_Screen{},
 		Fingerprint:             &configpb.HardwareFeatures_Fingerprint{},
@@ -357,6 +376,13 @@ func detectHardwareFeatures(ctx context.Context) (*protocol.HardwareFeatures, er
 	hasInternalDisplay := checkForConnector(internalDisplayRegexp)
 	if hasInternalDisplay {
 		features.Screen.PanelProperties = &configpb.Component_DisplayPanel_Properties{}
+		if mode, err := internalDisplayMode(internalDisplayRegexp); err != nil {
+			logging.Infof(ctx, "Unknown internal display mode: %v", err)
+		} else {
+			features.Screen.PanelProperties.WidthPx = mode.widthPx
+			features.Screen.PanelProperties.HeightPx = mode.heightPx
+			internalDisplayRefreshRateHz = mode.refreshRateHz
+		}
 	}
 
 	// Display ports show up as card*-DP-[0-9]
@@ -535,6 +561,13 @@ func detectHardwareFeatures(ctx context.Context) (*protocol.HardwareFeatures, er
 				features.EmbeddedController.FeatureMemoryDumpCommands = configpb.HardwareFeatures_NOT_PRESENT
 			}
 		}
+		if output, err := exec.Command("ectool", "protocolinfo").Output(); err != nil {
+			logging.Infof(ctx, "Failed to get EC protocol info: %v", err)
+		} else if v, err := parseECProtocolVersion(string(output)); err != nil {
+			logging.Infof(ctx, "Failed to parse EC protocol info: %v", err)
+		} else {
+			ecProtocolVersion = v
+		}
 		// Check if the detachable base is attached.
 		output, err = exec.Command("ectool", "mkbpget", "switches").Output()
 		if err != nil {
@@ -618,6 +651,15 @@ func detectHardwareFeatures(ctx context.Context) (*protocol.HardwareFeatures, er
 		features.UsbC = &configpb.HardwareFeatures_UsbC{Count: &configpb.HardwareFeatures_Count{Value: uint32(count + 1)}}
 	}
 
+	if usb4, err := hasUSB4HostRouter(); err != nil {
+		logging.Infof(ctx, "Failed to detect USB4 host router: %v", err)
+	} else if usb4 {
+		if features.UsbC == nil {
+			features.UsbC = &configpb.HardwareFeatures_UsbC{}
+		}
+		features.UsbC.Usb4 = true
+	}
+
 	// Device has GSC with production RW KeyId if gsctool -a -I -M
 	// returns RW KeyID with value 0x87b73b67 or 0xde88588d
 	func() {
@@ -721,6 +763,13 @@ func detectHardwareFeatures(ctx context.Context) (*protocol.HardwareFeatures, er
 		}
 		features.Cellular.DynamicPowerReductionConfig = &configpb.HardwareFeatures_Cellular_DynamicPowerReductionConfig{
 			DynamicPowerReductionConfig: &configpb.HardwareFeatures_Cellular_DynamicPowerReductionConfig_ModemManager{ModemManager: swDynamicSar}}
+		if out, err := exec.CommandContext(ctx, "mmcli", "-m", "any", "-J").Output(); err != nil {
+			logging.Infof(ctx, "Failed to get modem info: %v", err)
+		} else if v, err := parseModemFirmwareVersion(out); err != nil {
+			logging.Infof(ctx, "Failed to parse modem info: %v", err)
+		} else {
+			modemFirmwareVersion = v
+		}
 	}
 
 	// bluetoothctl hangs when bluetoothd is not built with asan enabled or
@@ -983,6 +1032,99 @@ func detectHardwareFeatures(ctx context.Context) (*protocol.HardwareFeatures, er
 		SizeMegabytes: int32(memoryBytes >> 20),
 	}
 
+	gpuMemoryBytes, err := findGPUMemorySize("/sys/class/drm")
+	if err != nil {
+		logging.Infof(ctx, "Failed to get GPU memory size: %v", err)
+	}
+
+	var thunderboltDockAttached bool
+	if out, err := exec.Command("boltctl", "list").Output(); err != nil {
+		logging.Infof(ctx, "Failed to list Thunderbolt devices: %v", err)
+	} else {
+		thunderboltDockAttached = hasConnectedThunderboltPeripheral(out)
+	}
+
+	var externalGPUAttached bool
+	lspciOut, err := exec.Command("lspci", "-Dmmn").Output()
+	if err != nil {
+		logging.Infof(ctx, "Failed to list PCI devices: %v", err)
+	} else if externalGPUAttached, err = hasExternalGPU(lspciOut, "/sys/bus/pci/devices"); err != nil {
+		logging.Infof(ctx, "Failed to check external GPUs: %v", err)
+	}
+
+	var kernelModules []string
+	if b, err := os.ReadFile("/proc/modules"); err != nil {
+		logging.Infof(ctx, "Failed to read /proc/modules: %v", err)
+	} else {
+		kernelModules = parseLoadedKernelModules(b)
+	}
+
+	externalDisplayConnectors, err := connectedExternalDisplays("/sys/class/drm")
+	if err != nil {
+		logging.Infof(ctx, "Failed to list external displays: %v", err)
+	}
+
+	var deviceTreeCompatible []string
+	// The device tree is absent on most x86 devices.
+	if b, err := os.ReadFile("/proc/device-tree/compatible"); err != nil && !os.IsNotExist(err) {
+		logging.Infof(ctx, "Failed to read device tree compatible strings: %v", err)
+	} else {
+		deviceTreeCompatible = parseDeviceTreeCompatible(b)
+	}
+
+	var installedDLCs map[string]string
+	if out, err := exec.CommandContext(ctx, "dlcservice_util", "--list").Output(); err != nil {
+		logging.Infof(ctx, "Failed to list installed DLCs: %v", err)
+	} else if installedDLCs, err = parseInstalledDLCs(out); err != nil {
+		logging.Infof(ctx, "Failed to parse installed DLCs: %v", err)
+	}
+
+	ethernetPresent, maxNICSpeedMbps, err := wiredNetworkInterfaces("/sys/class/net")
+	if err != nil {
+		logging.Infof(ctx, "Failed to list wired network interfaces: %v", err)
+	}
+
+	var wifiPhyBands []string
+	if out, err := exec.CommandContext(ctx, "iw", "phy").Output(); err != nil {
+		logging.Infof(ctx, "Failed to list Wi-Fi PHYs: %v", err)
+	} else {
+		wifiPhyBands = parseWifiPhyBands(out)
+	}
+
+	var board string
+	if kvs, err := lsbrelease.Load(); err != nil {
+		logging.Infof(ctx, "Failed to load lsb-release: %v", err)
+	} else {
+		board = kvs[lsbrelease.Board]
+	}
+	vmType := detectVMType(board, extraUseFlags, "/sys/class/dmi/id")
+
+	var fpmcuRWVersion string
+	var fpmcuRollbackSupported bool
+	if hasFingerprint {
+		if out, err := exec.CommandContext(ctx, "ectool", "--name=cros_fp", "version").Output(); err != nil {
+			logging.Infof(ctx, "Failed to get FPMCU firmware version: %v", err)
+		} else {
+			fpmcuRWVersion = parseFPMCURWVersion(out)
+		}
+		// rollbackinfo fails if the firmware does not support rollback
+		// protection.
+		fpmcuRollbackSupported = exec.CommandContext(ctx, "ectool", "--name=cros_fp", "rollbackinfo").Run() == nil
+	}
+
+	sdCardReaderPresent, sdCardInserted := detectSDCardReader("/sys/class/mmc_host")
+
+	var bluetoothDevice int32
+	var bluetoothVersion string
+	if features.Bluetooth.Present == configpb.HardwareFeatures_PRESENT {
+		if dev, err := bluetooth.DeviceInfo(); err != nil {
+			logging.Infof(ctx, "Failed to get Bluetooth controller: %v", err)
+		} else {
+			bluetoothDevice = int32(dev.ID)
+			bluetoothVersion = dev.Version
+		}
+	}
+
 	lidMicrophone, err := matchCrasDeviceType(`(INTERNAL|FRONT)_MIC`)
 	if err != nil {
 		logging.Infof(ctx, "Failed to get lid microphone: %v", err)
@@ -1016,6 +1158,12 @@ func detectHardwareFeatures(ctx context.Context) (*protocol.HardwareFeatures, er
 		features.Audio.SpeakerAmplifier = amp
 	}
 
+	codec, err := findHeadphoneCodec()
+	if err != nil {
+		logging.Infof(ctx, "Failed to get headphone codec: %v", err)
+	}
+	features.Audio.HeadphoneCodec = codec
+
 	sofAudioDsp, err := func() (configpb.HardwareFeatures_Present, error) {
 		if _, err := os.Stat("/sys/kernel/debug/sof"); err != nil {
 			if os.IsNotExist(err) {
@@ -1156,6 +1304,7 @@ func detectHardwareFeatures(ctx context.Context) (*protocol.HardwareFeatures, er
 	}
 	features.Camera.Enumerated = camEnumerated
 	features.Camera.EnumeratedUsbIds = camEnumeratedUsbIds
+	cameraISPVersion := cameraISP(lspciOut)
 
 	if err := parseKConfigs(ctx, features); err != nil {
 		logging.Info(ctx, "Failed to parse BIOS kConfig: ", err)
@@ -1191,55 +1340,36 @@ func detectHardwareFeatures(ctx context.Context) (*protocol.HardwareFeatures, er
 		features.RuntimeProbeConfig.EncryptedConfigPresent = configpb.HardwareFeatures_NOT_PRESENT
 	}
 
-	gpuFamily, gpuVendor, cpuSocFamily, dmiProductName, err := func() (gpuFamily, gpuVendor, cpuSocFamily, dmiProductName string, fetchErr error) {
+	probe, err := func() (*hardwareProbeResult, error) {
 		outBin, err := os.CreateTemp("/tmp", "hardware_probe")
 		if err != nil {
-			return "", "", "", "", errors.Wrap(err, "failed to create temp file")
+			return nil, errors.Wrap(err, "failed to create temp file")
 		}
 		outBin.Close()
 		defer os.Remove(outBin.Name())
 		out, err := exec.Command("/usr/local/graphics/hardware_probe", "-output", outBin.Name()).CombinedOutput()
 		if err != nil {
-			return "", "", "", "", errors.Wrapf(err, "failed to run hardware_probe, output: %v", string(out))
-		}
-		type gpuInfo struct {
-			Family string `json:"Family"`
-			Vendor string `json:"GPUVendor"`
-		}
-		type dmi struct {
-			ProductName string `json:"ProductName"`
-		}
-		type hardwareProbeResult struct {
-			GPUInfo   []gpuInfo `json:"GPU_Family"`
-			CPUFamily string    `json:"CPU_SOC_Family"`
-			DMI       dmi       `json:"DMI"`
+			return nil, errors.Wrapf(err, "failed to run hardware_probe, output: %v", string(out))
 		}
 		b, err := os.ReadFile(outBin.Name())
 		if err != nil {
-			return "", "", "", "", errors.Wrap(err, "failed to read hardware_probe.json")
-		}
-		var result hardwareProbeResult
-		if err := json.Unmarshal(b, &result); err != nil {
-			return "", "", "", "", err
-		}
-		if len(result.GPUInfo) > 0 {
-			gpuFamily = result.GPUInfo[0].Family
-			gpuVendor = result.GPUInfo[0].Vendor
-			if len(result.GPUInfo) > 1 {
-				logging.Infof(ctx, "Found multiple GPUInfo(%v), only use the first one detected.", result.GPUInfo)
-			}
+			return nil, errors.Wrap(err, "failed to read hardware_probe.json")
 		}
-		cpuSocFamily = result.CPUFamily
-		dmiProductName = result.DMI.ProductName
-		return
+		return parseHardwareProbeResult(b)
 	}()
 	if err != nil {
 		logging.Infof(ctx, "failed to parse hardware_probe output: %v", err)
+		probe = &hardwareProbeResult{}
 	}
-	features.HardwareProbeConfig.GpuFamily = gpuFamily
-	features.HardwareProbeConfig.GpuVendor = gpuVendor
-	features.HardwareProbeConfig.CpuSocFamily = cpuSocFamily
-	features.HardwareProbeConfig.DmiProductName = dmiProductName
+	if len(probe.GPUInfo) > 0 {
+		features.HardwareProbeConfig.GpuFamily = probe.GPUInfo[0].Family
+		features.HardwareProbeConfig.GpuVendor = probe.GPUInfo[0].Vendor
+		if len(probe.GPUInfo) > 1 {
+			logging.Infof(ctx, "Found multiple GPUInfo(%v), only use the first one detected.", probe.GPUInfo)
+		}
+	}
+	features.HardwareProbeConfig.CpuSocFamily = probe.CPUFamily
+	features.HardwareProbeConfig.DmiProductName = probe.DMI.ProductName
 
 	hevcSupport, err := func() (configpb.HardwareFeatures_Present, error) {
 		out, err := crosConfig("/ui", "serialized-ash-switches")
@@ -1382,9 +1512,33 @@ func detectHardwareFeatures(ctx context.Context) (*protocol.HardwareFeatures, er
 	}()
 
 	return &protocol.HardwareFeatures{
-		HardwareFeatures:       features,
-		DeprecatedDeviceConfig: config,
-		SoftwareConfig:         swConfig,
+		HardwareFeatures:             features,
+		DeprecatedDeviceConfig:       config,
+		SoftwareConfig:               swConfig,
+		GpuMemoryMegabytes:           int32(gpuMemoryBytes >> 20),
+		ThunderboltDockAttached:      thunderboltDockAttached,
+		ExternalGpuAttached:          externalGPUAttached,
+		KernelModules:                kernelModules,
+		DeviceTreeCompatible:         deviceTreeCompatible,
+		ExternalDisplayConnectors:    externalDisplayConnectors,
+		InstalledDlcs:                installedDLCs,
+		EthernetPresent:              ethernetPresent,
+		MaxNicSpeedMbps:              maxNICSpeedMbps,
+		WifiPhyBands:                 wifiPhyBands,
+		VmType:                       vmType,
+		FpmcuRwVersion:               fpmcuRWVersion,
+		FpmcuRollbackSupported:       fpmcuRollbackSupported,
+		SdCardReaderPresent:          sdCardReaderPresent,
+		SdCardInserted:               sdCardInserted,
+		BluetoothDevice:              bluetoothDevice,
+		BluetoothVersion:             bluetoothVersion,
+		HasTypecDpAltMode:            typecDPAltMode,
+		HasTypecTbtAltMode:           typecTBTAltMode,
+		EcProtocolVersion:            ecProtocolVersion,
+		HasWidevineL1:                widevineL1,
+		InternalDisplayRefreshRateHz: internalDisplayRefreshRateHz,
+		ModemFirmwareVersion:         modemFirmwareVersion,
+		CameraIsp:                    cameraISPVersion,
 	}, nil
 }
 
@@ -1821,6 +1975,386 @@ func findMemorySize(meminfo []byte) (int64, error) {
 	return 0, fmt.Errorf("MemTotal not found; input=%q", string(meminfo))
 }
 
+// findGPUMemorySize returns the size of dedicated VRAM of the GPU in bytes.
+// It is the largest size reported by DRM devices under drmDir, e.g.
+// /sys/class/drm. It returns 0 if no device reports dedicated VRAM, e.g. for
+// integrated GPUs sharing the system memory.
+func findGPUMemorySize(drmDir string) (int64, error) {
+	paths, err := filepath.Glob(filepath.Join(drmDir, "card*", "device", "mem_info_vram_total"))
+	if err != nil {
+		return 0, err
+	}
+	var vramBytes int64
+	for _, path := range paths {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return 0, err
+		}
+		size, err := strconv.ParseInt(strings.TrimSpace(string(b)), 10, 64)
+		if err != nil {
+			return 0, errors.Wrapf(err, "failed to parse %s", path)
+		}
+		if size > vramBytes {
+			vramBytes = size
+		}
+	}
+	return vramBytes, nil
+}
+
+// hardwareProbeResult is the output of hardware_probe.
+type hardwareProbeResult struct {
+	GPUInfo []struct {
+		Family string `json:"Family"`
+		Vendor string `json:"GPUVendor"`
+	} `json:"GPU_Family"`
+	CPUFamily string `json:"CPU_SOC_Family"`
+	DMI       struct {
+		ProductName string `json:"ProductName"`
+	} `json:"DMI"`
+}
+
+// parseHardwareProbeResult parses the JSON output of hardware_probe.
+func parseHardwareProbeResult(b []byte) (*hardwareProbeResult, error) {
+	var result hardwareProbeResult
+	if err := json.Unmarshal(b, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// hasConnectedThunderboltPeripheral returns true if the output of
+// "boltctl list" contains a connected Thunderbolt peripheral, e.g. a dock.
+// Hosts are listed with the type "host" and are ignored.
+func hasConnectedThunderboltPeripheral(boltctlOut []byte) bool {
+	var peripheral bool
+	for _, line := range strings.Split(string(boltctlOut), "\n") {
+		// Strip tree drawing characters, e.g. "   ├─ type:          peripheral".
+		line = strings.TrimLeft(line, " │├└─")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		switch value = strings.TrimSpace(value); key {
+		case "type":
+			peripheral = value == "peripheral"
+		case "status":
+			if peripheral && value != "disconnected" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasExternalGPU returns true if the output of "lspci -Dmmn" contains a
+// display controller which is marked removable, i.e. attached via an
+// external PCI link, under pciDir, e.g. /sys/bus/pci/devices.
+func hasExternalGPU(lspciOut []byte, pciDir string) (bool, error) {
+	for _, line := range strings.Split(strings.TrimSpace(string(lspciOut)), "\n") {
+		// Lines look like: 0000:00:02.0 "0300" "8086" "9a49" ...
+		fields := strings.Fields(line)
+		if len(fields) < 2 || !strings.HasPrefix(fields[1], `"03`) {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(pciDir, fields[0], "removable"))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		if strings.TrimSpace(string(b)) == "removable" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// cameraISPDeviceIDs maps PCI device IDs of Intel IPUs to their versions.
+var cameraISPDeviceIDs = map[string]string{
+	"9a19": "IPU6",      // Tiger Lake
+	"4e19": "IPU6SE",    // Jasper Lake
+	"465d": "IPU6EP",    // Alder Lake P
+	"462e": "IPU6EP",    // Alder Lake N
+	"a75d": "IPU6EP",    // Raptor Lake P
+	"7d19": "IPU6EPMTL", // Meteor Lake
+	"645d": "IPU7",      // Lunar Lake
+	"b05d": "IPU7",      // Panther Lake
+}
+
+// cameraISP returns the version of the camera ISP listed in the output of
+// "lspci -Dmmn", e.g. "IPU6EP". It returns an empty string if no known ISP is
+// found.
+func cameraISP(lspciOut []byte) string {
+	for _, line := range strings.Split(strings.TrimSpace(string(lspciOut)), "\n") {
+		// Lines look like: 0000:00:05.0 "0480" "8086" "465d" ...
+		fields := strings.Fields(line)
+		if len(fields) < 4 || fields[2] != `"8086"` {
+			continue
+		}
+		if v, ok := cameraISPDeviceIDs[strings.Trim(fields[3], `"`)]; ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// parseLoadedKernelModules returns names of kernel modules listed in the
+// content of /proc/modules.
+func parseLoadedKernelModules(procModules []byte) []string {
+	var mods []string
+	for _, line := range strings.Split(string(procModules), "\n") {
+		// Lines look like: "snd_hda_intel 57344 3 - Live 0x0000000000000000"
+		if fields := strings.Fields(line); len(fields) > 0 {
+			mods = append(mods, fields[0])
+		}
+	}
+	return mods
+}
+
+// externalConnectorRegexp matches names of DRM connectors of external
+// displays under /sys/class/drm, e.g. "card0-HDMI-A-1". The first submatch
+// is the type of the connector.
+var externalConnectorRegexp = regexp.MustCompile(`^card[0-9]+-(DP|HDMI-A|DVI-I)-[0-9]+$`)
+
+// connectedExternalDisplays returns types of connectors of external displays
+// connected to the DUT, one per display, by inspecting DRM connectors under
+// drmDir, e.g. /sys/class/drm. DisplayPort connectors linked to a USB Type-C
+// port are reported as "USB-C".
+func connectedExternalDisplays(drmDir string) ([]string, error) {
+	files, err := os.ReadDir(drmDir)
+	if err != nil {
+		return nil, err
+	}
+	var connectors []string
+	for _, file := range files {
+		m := externalConnectorRegexp.FindStringSubmatch(file.Name())
+		if m == nil {
+			continue
+		}
+		status, err := os.ReadFile(filepath.Join(drmDir, file.Name(), "status"))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(string(status), "connected") {
+			continue
+		}
+		switch m[1] {
+		case "HDMI-A":
+			connectors = append(connectors, "HDMI")
+		case "DVI-I":
+			connectors = append(connectors, "DVI")
+		case "DP":
+			// The kernel links DRM connectors to Type-C ports they are
+			// routed to with a typec_connector symlink.
+			if _, err := os.Lstat(filepath.Join(drmDir, file.Name(), "typec_connector")); err == nil {
+				connectors = append(connectors, "USB-C")
+			} else {
+				connectors = append(connectors, "DP")
+			}
+		}
+	}
+	return connectors, nil
+}
+
+// parseDeviceTreeCompatible returns compatible strings in the content of
+// /proc/device-tree/compatible, which are separated by NUL characters.
+func parseDeviceTreeCompatible(compatible []byte) []string {
+	var strs []string
+	for _, s := range strings.Split(string(compatible), "\x00") {
+		if s != "" {
+			strs = append(strs, s)
+		}
+	}
+	return strs
+}
+
+// parseInstalledDLCs returns versions of installed DLCs keyed by their IDs,
+// given the output of "dlcservice_util --list".
+func parseInstalledDLCs(out []byte) (map[string]string, error) {
+	// The output maps DLC IDs to lists of their packages, e.g.
+	// {"sane-backends-pfu": [{"id": "sane-backends-pfu", "version": "1.0.0-r10", ...}]}
+	var list map[string][]struct {
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(out, &list); err != nil {
+		return nil, err
+	}
+	dlcs := make(map[string]string)
+	for id, pkgs := range list {
+		dlcs[id] = ""
+		if len(pkgs) > 0 {
+			dlcs[id] = pkgs[0].Version
+		}
+	}
+	return dlcs, nil
+}
+
+// wiredNetworkInterfaces returns whether there is a wired Ethernet interface
+// in netDir, a sysfs directory containing network interfaces such as
+// /sys/class/net, and the maximum link speed in Mbps of such interfaces whose
+// links are up.
+func wiredNetworkInterfaces(netDir string) (present bool, maxSpeedMbps int32, err error) {
+	files, err := os.ReadDir(netDir)
+	if err != nil {
+		return false, 0, err
+	}
+	for _, file := range files {
+		dir := filepath.Join(netDir, file.Name())
+		// Virtual interfaces, e.g. lo and bridges, have no backing device.
+		if _, err := os.Stat(filepath.Join(dir, "device")); err != nil {
+			continue
+		}
+		// Wi-Fi interfaces also have the Ethernet type (ARPHRD_ETHER = 1).
+		if _, err := os.Stat(filepath.Join(dir, "phy80211")); err == nil {
+			continue
+		}
+		if b, err := os.ReadFile(filepath.Join(dir, "type")); err != nil || strings.TrimSpace(string(b)) != "1" {
+			continue
+		}
+		present = true
+		// Reading speed fails if the link is down.
+		b, err := os.ReadFile(filepath.Join(dir, "speed"))
+		if err != nil {
+			continue
+		}
+		if speed, err := strconv.Atoi(strings.TrimSpace(string(b))); err == nil && int32(speed) > maxSpeedMbps {
+			maxSpeedMbps = int32(speed)
+		}
+	}
+	return present, maxSpeedMbps, nil
+}
+
+// wifiBandRegexp matches lines of "iw phy" output starting descriptions of
+// bands supported by a PHY, e.g. "\tBand 1:". The first submatch is the
+// nl80211 band number.
+var wifiBandRegexp = regexp.MustCompile(`(?m)^\s+Band ([0-9]+):`)
+
+// wifiBandNames maps nl80211 band numbers to names of frequency bands.
+var wifiBandNames = map[string]string{
+	"1": "2.4GHz",
+	"2": "5GHz",
+	"3": "60GHz",
+	"4": "6GHz",
+}
+
+// parseWifiPhyBands returns frequency bands supported by Wi-Fi PHYs, e.g.
+// "2.4GHz", given the output of "iw phy".
+func parseWifiPhyBands(out []byte) []string {
+	var bands []string
+	seen := make(map[string]bool)
+	for _, m := range wifiBandRegexp.FindAllStringSubmatch(string(out), -1) {
+		name, ok := wifiBandNames[m[1]]
+		if !ok || seen[name] {
+			continue
+		}
+		seen[name] = true
+		bands = append(bands, name)
+	}
+	return bands
+}
+
+// fpmcuRWVersionRegexp matches the line of the RW firmware version in the
+// output of "ectool --name=cros_fp version".
+var fpmcuRWVersionRegexp = regexp.MustCompile(`(?m)^RW version:\s*(\S+)\s*$`)
+
+// parseFPMCURWVersion parses the output of "ectool --name=cros_fp version" and
+// returns the version of the RW firmware of the fingerprint MCU, or an empty
+// string if it is not found.
+func parseFPMCURWVersion(out []byte) string {
+	m := fpmcuRWVersionRegexp.FindSubmatch(out)
+	if m == nil {
+		return ""
+	}
+	return string(m[1])
+}
+
+// VM types reported in HardwareFeatures.VmType.
+const (
+	vmTypeBetty  = "betty"
+	vmTypeTastVM = "tast_vm"
+	vmTypeCrosvm = "crosvm"
+)
+
+// tastVMBoards are boards of generic images run as virtual machines by VM test
+// builders.
+var tastVMBoards = []string{"amd64-generic", "reven-vmtest"}
+
+// detectVMType returns the type of the virtual machine the DUT runs on, or an
+// empty string if the DUT is not a virtual machine. board is the board name in
+// /etc/lsb-release, extraUseFlags are USE flags given by -extrauseflags, and
+// dmiDir is a sysfs directory containing DMI information such as
+// /sys/class/dmi/id.
+func detectVMType(board string, extraUseFlags []string, dmiDir string) string {
+	// betty and tast_vm both run on QEMU, so they are told apart by the board
+	// and the tast_vm USE flag inserted by VM test builders.
+	if board == "betty" || strings.HasPrefix(board, "betty-") {
+		return vmTypeBetty
+	}
+	for _, f := range extraUseFlags {
+		if f == "tast_vm" {
+			return vmTypeTastVM
+		}
+	}
+	for _, b := range tastVMBoards {
+		if board == b {
+			return vmTypeTastVM
+		}
+	}
+
+	readDMI := func(name string) string {
+		b, err := os.ReadFile(filepath.Join(dmiDir, name))
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(b))
+	}
+	switch {
+	case readDMI("product_name") == "crosvm":
+		return vmTypeCrosvm
+	case readDMI("sys_vendor") == "QEMU":
+		return vmTypeTastVM
+	}
+	return ""
+}
+
+// detectSDCardReader returns whether the DUT has an SD card reader and whether
+// an SD card is inserted to it. mmcHostDir is a sysfs directory containing MMC
+// host controllers, such as /sys/class/mmc_host. A host controller is
+// considered an SD card reader if its slot is empty or holds an SD card, since
+// ones for eMMC storage and SDIO devices always hold a card of another type.
+func detectSDCardReader(mmcHostDir string) (present, inserted bool) {
+	hosts, err := filepath.Glob(filepath.Join(mmcHostDir, "mmc*"))
+	if err != nil {
+		return false, false
+	}
+	for _, host := range hosts {
+		// Cards are named after their host, e.g. "mmc0:0001".
+		cards, err := filepath.Glob(filepath.Join(host, filepath.Base(host)+":*"))
+		if err != nil {
+			continue
+		}
+		if len(cards) == 0 {
+			present = true
+			continue
+		}
+		for _, card := range cards {
+			b, err := os.ReadFile(filepath.Join(card, "type"))
+			if err != nil {
+				continue
+			}
+			if strings.TrimSpace(string(b)) == "SD" {
+				present = true
+				inserted = true
+			}
+		}
+	}
+	return present, inserted
+}
+
 func matchCrasDeviceType(pattern string) (*configpb.HardwareFeatures_Count, error) {
 	b, err := exec.Command("cras_test_client").Output()
 	if err != nil {
@@ -1876,6 +2410,42 @@ func matchSpeakerAmplifier(line string) (*configpb.Component_Amplifier, bool) {
 	return nil, false
 }
 
+// findHeadphoneCodec parses a content of in "/sys/kernel/debug/asoc/components"
+// and returns the audio codec used for the headphones.
+func findHeadphoneCodec() (configpb.HardwareFeatures_Audio_AudioCodec, error) {
+	f, err := os.Open("/sys/kernel/debug/asoc/components")
+	if err != nil {
+		return configpb.HardwareFeatures_Audio_AUDIO_CODEC_UNKNOWN, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if codec, found := matchHeadphoneCodec(scanner.Text()); found {
+			return codec, nil
+		}
+	}
+	return configpb.HardwareFeatures_Audio_AUDIO_CODEC_UNKNOWN, scanner.Err()
+}
+
+var codecsRegexp = map[configpb.HardwareFeatures_Audio_AudioCodec]*regexp.Regexp{
+	configpb.HardwareFeatures_Audio_RT5682:     regexp.MustCompile(`^(i2c-)?(10ec|rt)5682((:\d*)|([_-]?\d*))?$`),
+	configpb.HardwareFeatures_Audio_ALC5682IVS: regexp.MustCompile(`^(i2c-)?rtl5682((:\d*)|([_-]?\d*))?$`),
+	configpb.HardwareFeatures_Audio_DA7219:     regexp.MustCompile(`^(i2c-)?(dlgs)?7219((:\d*)|([_-]?\d*))?$`),
+	configpb.HardwareFeatures_Audio_NAU88L25B:  regexp.MustCompile(`^(i2c-)?((1050)|(nau))8825((:\d*)|([_-]?\d*))?$`),
+	configpb.HardwareFeatures_Audio_CS42L42:    regexp.MustCompile(`^(i2c-)?((10134242)|(cs42l42))((:\d*)|([_-]?\d*))?$`),
+	configpb.HardwareFeatures_Audio_ES8326:     regexp.MustCompile(`^(i2c-)?(essx)?8326((:\d*)|([_-]?\d*))?$`),
+	configpb.HardwareFeatures_Audio_WCD9385:    regexp.MustCompile(`^wcd938(\d|x)(_codec)?((:\d*)|([_.-]?\d*))?$`),
+}
+
+func matchHeadphoneCodec(line string) (configpb.HardwareFeatures_Audio_AudioCodec, bool) {
+	for codec, re := range codecsRegexp {
+		if re.MatchString(strings.ToLower(line)) {
+			return codec, true
+		}
+	}
+	return configpb.HardwareFeatures_Audio_AUDIO_CODEC_UNKNOWN, false
+}
+
 // bootTimeCalibration returns whether the boot time calibration is
 // enabled by parsing the sound_card_init config.
 func bootTimeCalibration() (bool, error) {
@@ -2044,6 +2614,219 @@ func cameraEnumerated() (bool, []string, error) {
 	return usbCams+mipiCams == count, usbCamIds, nil
 }
 
+// ecProtocolVersionsRe matches the line listing supported protocol versions in
+// "ectool protocolinfo" output, e.g. "  protocol versions: 2 3".
+var ecProtocolVersionsRe = regexp.MustCompile(`(?m)^\s*protocol versions:((?:\s+\d+)+)\s*$`)
+
+// parseECProtocolVersion returns the highest EC host command protocol version
+// listed in out, the output of "ectool protocolinfo".
+func parseECProtocolVersion(out string) (uint32, error) {
+	m := ecProtocolVersionsRe.FindStringSubmatch(out)
+	if m == nil {
+		return 0, errors.Errorf("protocol versions not found in %q", out)
+	}
+	var max uint32
+	for _, f := range strings.Fields(m[1]) {
+		v, err := strconv.ParseUint(f, 10, 32)
+		if err != nil {
+			return 0, err
+		}
+		if uint32(v) > max {
+			max = uint32(v)
+		}
+	}
+	return max, nil
+}
+
+// parseModemFirmwareVersion returns the firmware revision of the modem in out,
+// the JSON output of "mmcli -m any -J".
+func parseModemFirmwareVersion(out []byte) (string, error) {
+	var info struct {
+		Modem struct {
+			Generic struct {
+				Revision string `json:"revision"`
+			} `json:"generic"`
+		} `json:"modem"`
+	}
+	if err := json.Unmarshal(out, &info); err != nil {
+		return "", err
+	}
+	rev := strings.TrimSpace(info.Modem.Generic.Revision)
+	if rev == "" || rev == "--" {
+		return "", errors.New("firmware revision not found")
+	}
+	return rev, nil
+}
+
+// USB Type-C alternate mode SVIDs exposed under /sys/class/typec.
+const (
+	typecDPSVID  = "ff01"
+	typecTBTSVID = "8087"
+)
+
+// typecAltModes returns whether any USB Type-C port of the DUT supports
+// DisplayPort and Thunderbolt alternate modes respectively.
+func typecAltModes() (dp, tbt bool, err error) {
+	// Alternate modes supported by a port are listed as
+	// /sys/class/typec/port<N>/port<N>.<M>/svid.
+	paths, err := filepath.Glob("/sys/class/typec/port[0-9]*/port[0-9]*.[0-9]*/svid")
+	if err != nil {
+		return false, false, err
+	}
+	for _, p := range paths {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return false, false, err
+		}
+		switch strings.ToLower(strings.TrimSpace(string(b))) {
+		case typecDPSVID:
+			dp = true
+		case typecTBTSVID:
+			tbt = true
+		}
+	}
+	return dp, tbt, nil
+}
+
+// hasUSB4HostRouter returns whether the DUT has a USB4 host router.
+func hasUSB4HostRouter() (bool, error) {
+	// Host routers are named <domain>-0 and report their USB4/Thunderbolt
+	// generation. Generation 4 and above are USB4.
+	paths, err := filepath.Glob("/sys/bus/thunderbolt/devices/[0-9]*-0/generation")
+	if err != nil {
+		return false, err
+	}
+	for _, p := range paths {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return false, err
+		}
+		gen, err := strconv.Atoi(strings.TrimSpace(string(b)))
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to parse %s", p)
+		}
+		if gen >= 4 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// displayMode describes a video mode supported by a display.
+type displayMode struct {
+	widthPx       int32
+	heightPx      int32
+	refreshRateHz uint32
+}
+
+// internalDisplayMode returns the mode with the highest refresh rate supported
+// by the connected internal display whose DRM connector name matches
+// connectorRegexp.
+func internalDisplayMode(connectorRegexp string) (*displayMode, error) {
+	const drmSysFS = "/sys/class/drm"
+
+	drmFiles, err := os.ReadDir(drmSysFS)
+	if err != nil {
+		return nil, err
+	}
+	re := regexp.MustCompile(connectorRegexp)
+	for _, file := range drmFiles {
+		if !re.MatchString(file.Name()) {
+			continue
+		}
+		status, err := os.ReadFile(filepath.Join(drmSysFS, file.Name(), "status"))
+		if err != nil || !strings.HasPrefix(string(status), "connected") {
+			continue
+		}
+		edid, err := os.ReadFile(filepath.Join(drmSysFS, file.Name(), "edid"))
+		if err != nil {
+			return nil, err
+		}
+		return parseEDIDMaxMode(edid)
+	}
+	return nil, errors.New("no connected internal display")
+}
+
+// edidHeader is the fixed pattern at the beginning of an EDID base block.
+var edidHeader = []byte{0x00, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x00}
+
+// parseEDIDMaxMode parses an EDID blob and returns the mode with the highest
+// refresh rate among the detailed timing descriptors in its base block.
+func parseEDIDMaxMode(edid []byte) (*displayMode, error) {
+	const (
+		blockSize      = 128
+		descStart      = 54
+		descSize       = 18
+		numDescriptors = 4
+	)
+	if len(edid) < blockSize {
+		return nil, errors.Errorf("EDID is too short (%d bytes)", len(edid))
+	}
+	if !bytes.Equal(edid[:len(edidHeader)], edidHeader) {
+		return nil, errors.New("EDID has invalid header")
+	}
+
+	var best *displayMode
+	for i := 0; i < numDescriptors; i++ {
+		d := edid[descStart+i*descSize : descStart+(i+1)*descSize]
+		// Pixel clock in units of 10 kHz. Zero means that this is a
+		// display descriptor rather than a detailed timing descriptor.
+		clock := int(d[0]) | int(d[1])<<8
+		if clock == 0 {
+			continue
+		}
+		hActive := int(d[2]) | int(d[4]&0xf0)<<4
+		hBlank := int(d[3]) | int(d[4]&0x0f)<<8
+		vActive := int(d[5]) | int(d[7]&0xf0)<<4
+		vBlank := int(d[6]) | int(d[7]&0x0f)<<8
+		total := (hActive + hBlank) * (vActive + vBlank)
+		if total == 0 {
+			continue
+		}
+		hz := uint32(math.Round(float64(clock) * 10000 / float64(total)))
+		if best == nil || hz > best.refreshRateHz {
+			best = &displayMode{
+				widthPx:       int32(hActive),
+				heightPx:      int32(vActive),
+				refreshRateHz: hz,
+			}
+		}
+	}
+	if best == nil {
+		return nil, errors.New("EDID has no detailed timing descriptor")
+	}
+	return best, nil
+}
+
+// Paths used to detect Widevine L1 support.
+var (
+	// oemCryptoLibGlob matches the hardware OEMCrypto library that talks to
+	// the trusted execution environment.
+	oemCryptoLibGlob = "/usr/lib*/liboemcrypto.so"
+	// cdmOEMCryptoDaemonPath is the daemon exposing OEMCrypto to the content
+	// decryption module in Chrome.
+	cdmOEMCryptoDaemonPath = "/usr/bin/cdm-oemcrypto"
+)
+
+// widevineL1Supported returns whether the DUT supports Widevine security
+// level 1, which requires both a hardware OEMCrypto library and the daemon
+// exposing it to the content decryption module.
+func widevineL1Supported() (bool, error) {
+	libs, err := filepath.Glob(oemCryptoLibGlob)
+	if err != nil {
+		return false, err
+	}
+	if len(libs) == 0 {
+		return false, nil
+	}
+	if _, err := os.Stat(cdmOEMCryptoDaemonPath); os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // findGSCKeyID parses a content of "gsctool -a -f -M" and return a required key
 func findGSCKeyID(str, keyIDType string) (string, error) {
 	re := regexp.MustCompile(`(?m)^keyids: RO (0x.+), RW (0x.+)$`)
@@ -6,7 +6,9 @@ package crosbundle
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -224,6 +226,426 @@ func TestFindMemorySize(t *testing.T) {
 	}
 }
 
+func TestParseHardwareProbeResult(t *testing.T) {
+	// Output of hardware_probe on a Tiger Lake device.
+	const input = `{
+  "CPU_SOC_Family": "intel",
+  "DMI": {
+    "ProductName": "Volteer"
+  },
+  "GPU_Family": [
+    {
+      "Family": "tigerlake",
+      "GPUVendor": "intel"
+    }
+  ]
+}`
+	got, err := parseHardwareProbeResult([]byte(input))
+	if err != nil {
+		t.Fatal("parseHardwareProbeResult failed: ", err)
+	}
+	if len(got.GPUInfo) != 1 {
+		t.Fatalf("parseHardwareProbeResult returned %d GPUs; want 1", len(got.GPUInfo))
+	}
+	if g := got.GPUInfo[0]; g.Family != "tigerlake" || g.Vendor != "intel" {
+		t.Errorf("parseHardwareProbeResult returned GPU %+v; want family tigerlake and vendor intel", g)
+	}
+	if got.CPUFamily != "intel" || got.DMI.ProductName != "Volteer" {
+		t.Errorf("parseHardwareProbeResult returned CPU family %q and product name %q; want intel and Volteer", got.CPUFamily, got.DMI.ProductName)
+	}
+}
+
+func TestFindGPUMemorySize(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		files map[string]string
+		want  int64
+	}{
+		{"NoDevice", nil, 0},
+		// Integrated GPUs do not report dedicated VRAM.
+		{"Integrated", map[string]string{"card0/device/vendor": "0x8086\n"}, 0},
+		{"Dedicated", map[string]string{
+			"card0/device/mem_info_vram_total": "536870912\n",
+			"card1/device/mem_info_vram_total": "4294967296\n",
+		}, 4 << 30},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tc.files {
+				path := filepath.Join(dir, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got, err := findGPUMemorySize(dir)
+			if err != nil {
+				t.Fatal("findGPUMemorySize failed: ", err)
+			}
+			if got != tc.want {
+				t.Errorf("findGPUMemorySize = %d; want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestHasConnectedThunderboltPeripheral(t *testing.T) {
+	const dock = ` ● Dell WD19TB Thunderbolt Dock
+   ├─ type:          peripheral
+   ├─ name:          WD19TB Thunderbolt Dock
+   ├─ vendor:        Dell
+   ├─ generation:    Thunderbolt 3
+   ├─ status:        %s
+   │  └─ rx speed:   40 Gb/s = 2 lanes * 20 Gb/s
+   └─ stored:        no
+`
+	const host = ` ● Google Host
+   ├─ type:          host
+   ├─ status:        authorized
+   └─ stored:        no
+`
+	for _, tc := range []struct {
+		name string
+		out  string
+		want bool
+	}{
+		{"Empty", "", false},
+		{"HostOnly", host, false},
+		{"Authorized", host + fmt.Sprintf(dock, "authorized"), true},
+		{"Connected", fmt.Sprintf(dock, "connected"), true},
+		{"Disconnected", fmt.Sprintf(dock, "disconnected") + host, false},
+	} {
+		if got := hasConnectedThunderboltPeripheral([]byte(tc.out)); got != tc.want {
+			t.Errorf("%s: hasConnectedThunderboltPeripheral = %v; want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestCameraISP(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		lspciOut string
+		want     string
+	}{
+		{"None", `0000:00:02.0 "0300" "8086" "46a8" -r0c "1028" "0b19"
+0000:00:14.0 "0c03" "8086" "51ed" -r01 "1028" "0b19"
+`, ""},
+		{"IPU6EP", `0000:00:02.0 "0300" "8086" "46a8" -r0c "1028" "0b19"
+0000:00:05.0 "0480" "8086" "465d" -r02 "1028" "0b19"
+`, "IPU6EP"},
+		{"OtherVendor", `0000:01:00.0 "0480" "10de" "465d" -ra1 "10de" "139f"
+`, ""},
+		{"Empty", "", ""},
+	} {
+		if got := cameraISP([]byte(tc.lspciOut)); got != tc.want {
+			t.Errorf("%s: cameraISP = %q; want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestHasExternalGPU(t *testing.T) {
+	const lspciOut = `0000:00:02.0 "0300" "8086" "9a49" -r01 "1028" "0a21"
+0000:00:14.0 "0c03" "8086" "a0ed" -r20 "1028" "0a21"
+0000:2c:00.0 "0300" "10de" "1e84" -ra1 "10de" "139f"
+`
+	for _, tc := range []struct {
+		name  string
+		files map[string]string
+		want  bool
+	}{
+		{"NoAttribute", nil, false},
+		{"Fixed", map[string]string{
+			"0000:00:02.0/removable": "fixed\n",
+			"0000:2c:00.0/removable": "fixed\n",
+		}, false},
+		{"NonDisplayRemovable", map[string]string{
+			"0000:00:02.0/removable": "fixed\n",
+			"0000:00:14.0/removable": "removable\n",
+		}, false},
+		{"Removable", map[string]string{
+			"0000:00:02.0/removable": "fixed\n",
+			"0000:2c:00.0/removable": "removable\n",
+		}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tc.files {
+				path := filepath.Join(dir, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got, err := hasExternalGPU([]byte(lspciOut), dir)
+			if err != nil {
+				t.Fatal("hasExternalGPU failed: ", err)
+			}
+			if got != tc.want {
+				t.Errorf("hasExternalGPU = %v; want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestConnectedExternalDisplays(t *testing.T) {
+	dir := t.TempDir()
+	for name, status := range map[string]string{
+		"card0-eDP-1":    "connected\n",
+		"card0-DP-1":     "connected\n",
+		"card0-DP-2":     "connected\n",
+		"card0-DP-3":     "disconnected\n",
+		"card0-HDMI-A-1": "connected\n",
+		"card1-DVI-I-1":  "disconnected\n",
+	} {
+		if err := os.MkdirAll(filepath.Join(dir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name, "status"), []byte(status), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// card0-DP-2 is routed to a USB Type-C port.
+	if err := os.Symlink("../../typec/port0", filepath.Join(dir, "card0-DP-2", "typec_connector")); err != nil {
+		t.Fatal(err)
+	}
+	// card0 itself is not a connector.
+	if err := os.MkdirAll(filepath.Join(dir, "card0"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := connectedExternalDisplays(dir)
+	if err != nil {
+		t.Fatal("connectedExternalDisplays failed: ", err)
+	}
+	want := []string{"DP", "USB-C", "HDMI"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("connectedExternalDisplays = %q; want %q", got, want)
+	}
+}
+
+func TestWiredNetworkInterfaces(t *testing.T) {
+	dir := t.TempDir()
+	for name, files := range map[string]map[string]string{
+		// Built-in Ethernet whose link is down.
+		"eth0": {"type": "1\n", "device/vendor": "0x10ec\n"},
+		// USB Ethernet adapter whose link is up.
+		"eth1": {"type": "1\n", "device/vendor": "0x0bda\n", "speed": "1000\n"},
+		// Wi-Fi interfaces and virtual interfaces are ignored.
+		"wlan0":   {"type": "1\n", "device/vendor": "0x8086\n", "phy80211/name": "phy0\n"},
+		"lo":      {"type": "772\n"},
+		"arcbr0":  {"type": "1\n", "speed": "10000\n"},
+		"wwan0":   {"type": "65534\n", "device/vendor": "0x2cb7\n"},
+		"usb0dev": {"type": "1\n", "device/vendor": "0x0bda\n", "speed": "-1\n"},
+	} {
+		for fn, content := range files {
+			p := filepath.Join(dir, name, fn)
+			if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	present, speed, err := wiredNetworkInterfaces(dir)
+	if err != nil {
+		t.Fatal("wiredNetworkInterfaces failed: ", err)
+	}
+	if !present || speed != 1000 {
+		t.Errorf("wiredNetworkInterfaces = (%v, %d); want (true, 1000)", present, speed)
+	}
+
+	if err := os.RemoveAll(filepath.Join(dir, "eth0")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(filepath.Join(dir, "eth1")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(filepath.Join(dir, "usb0dev")); err != nil {
+		t.Fatal(err)
+	}
+	present, speed, err = wiredNetworkInterfaces(dir)
+	if err != nil {
+		t.Fatal("wiredNetworkInterfaces failed: ", err)
+	}
+	if present || speed != 0 {
+		t.Errorf("wiredNetworkInterfaces = (%v, %d); want (false, 0)", present, speed)
+	}
+}
+
+func TestParseWifiPhyBands(t *testing.T) {
+	const out = `Wiphy phy0
+	max # scan SSIDs: 20
+	Band 1:
+		Capabilities: 0x1ff2
+		Frequencies:
+			* 2412 MHz [1] (22.0 dBm)
+	Band 2:
+		Capabilities: 0x1ff2
+	Band 4:
+		Frequencies:
+			* 5955 MHz [1] (22.0 dBm)
+Wiphy phy1
+	Band 1:
+		Capabilities: 0x1ff2
+`
+	got := parseWifiPhyBands([]byte(out))
+	want := []string{"2.4GHz", "5GHz", "6GHz"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseWifiPhyBands = %q; want %q", got, want)
+	}
+	if got := parseWifiPhyBands(nil); len(got) != 0 {
+		t.Errorf("parseWifiPhyBands(nil) = %q; want empty", got)
+	}
+}
+
+func TestParseFPMCURWVersion(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		out  string
+		want string
+	}{
+		{"RW", `RO version:    nocturne_fp_v2.2.64-58cf5974e
+RW version:    nocturne_fp_v2.2.110-b936c0a3c
+Firmware copy: RW
+Build info:    nocturne_fp_v2.2.110-b936c0a3c 2019-12-11 01:28:51 @chromeos-ci-legacy
+`, "nocturne_fp_v2.2.110-b936c0a3c"},
+		{"NoRW", "RO version:    bloonchipper_v2.0.4277-9f652bb3\n", ""},
+		{"Empty", "", ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := parseFPMCURWVersion([]byte(tc.out)); got != tc.want {
+				t.Errorf("parseFPMCURWVersion(%q) = %q; want %q", tc.out, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDetectVMType(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		board         string
+		extraUseFlags []string
+		dmi           map[string]string
+		want          string
+	}{
+		{"Physical", "brya", nil, map[string]string{"sys_vendor": "Google\n", "product_name": "Brya\n"}, ""},
+		{"NoDMI", "trogdor", nil, nil, ""},
+		{"Betty", "betty-arc-r", nil, map[string]string{"sys_vendor": "QEMU\n"}, "betty"},
+		{"TastVMUseFlag", "octopus", []string{"tast_vm"}, map[string]string{"sys_vendor": "QEMU\n"}, "tast_vm"},
+		{"TastVMBoard", "amd64-generic", nil, nil, "tast_vm"},
+		{"QEMU", "reven", nil, map[string]string{"sys_vendor": "QEMU\n"}, "tast_vm"},
+		{"Crosvm", "hatch", nil, map[string]string{"sys_vendor": "ChromiumOS\n", "product_name": "crosvm\n"}, "crosvm"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tc.dmi {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := detectVMType(tc.board, tc.extraUseFlags, dir); got != tc.want {
+				t.Errorf("detectVMType(%q, %q) = %q; want %q", tc.board, tc.extraUseFlags, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDetectSDCardReader(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		cards        map[string]string // card directory path -> type
+		hosts        []string          // hosts without cards
+		wantPresent  bool
+		wantInserted bool
+	}{
+		{"NoHost", nil, nil, false, false},
+		{"EMMCOnly", map[string]string{"mmc0/mmc0:0001": "MMC\n"}, nil, false, false},
+		{"SDIOOnly", map[string]string{"mmc1/mmc1:0001": "SDIO\n"}, nil, false, false},
+		{"EmptyReader", map[string]string{"mmc0/mmc0:0001": "MMC\n"}, []string{"mmc1"}, true, false},
+		{"InsertedCard", map[string]string{"mmc0/mmc0:0001": "MMC\n", "mmc1/mmc1:aaaa": "SD\n"}, nil, true, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, host := range tc.hosts {
+				if err := os.MkdirAll(filepath.Join(dir, host), 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for card, typ := range tc.cards {
+				if err := os.MkdirAll(filepath.Join(dir, card), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(dir, card, "type"), []byte(typ), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			present, inserted := detectSDCardReader(dir)
+			if present != tc.wantPresent || inserted != tc.wantInserted {
+				t.Errorf("detectSDCardReader() = (%v, %v); want (%v, %v)", present, inserted, tc.wantPresent, tc.wantInserted)
+			}
+		})
+	}
+}
+
+func TestParseLoadedKernelModules(t *testing.T) {
+	const procModules = `snd_hda_intel 57344 3 - Live 0x0000000000000000
+btusb 61440 0 - Live 0x0000000000000000
+bluetooth 724992 6 btusb, Live 0x0000000000000000
+`
+	got := parseLoadedKernelModules([]byte(procModules))
+	want := []string{"snd_hda_intel", "btusb", "bluetooth"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseLoadedKernelModules = %q; want %q", got, want)
+	}
+	if got := parseLoadedKernelModules(nil); len(got) != 0 {
+		t.Errorf("parseLoadedKernelModules(nil) = %q; want empty", got)
+	}
+}
+
+func TestParseInstalledDLCs(t *testing.T) {
+	const out = `{
+   "sane-backends-pfu": [ {
+      "fs-type": "squashfs",
+      "id": "sane-backends-pfu",
+      "image_type": "dlc",
+      "root_mount": "/run/imageloader/sane-backends-pfu/package",
+      "version": "1.0.0-r10"
+   } ],
+   "termina-dlc": [ {
+      "id": "termina-dlc"
+   } ]
+}
+`
+	got, err := parseInstalledDLCs([]byte(out))
+	if err != nil {
+		t.Fatal("parseInstalledDLCs failed: ", err)
+	}
+	want := map[string]string{"sane-backends-pfu": "1.0.0-r10", "termina-dlc": ""}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseInstalledDLCs = %q; want %q", got, want)
+	}
+	if _, err := parseInstalledDLCs([]byte("dlcservice is not running")); err == nil {
+		t.Error("parseInstalledDLCs unexpectedly succeeded for malformed output")
+	}
+}
+
+func TestParseDeviceTreeCompatible(t *testing.T) {
+	got := parseDeviceTreeCompatible([]byte("google,krane-sku176\x00google,krane\x00mediatek,mt8183\x00"))
+	want := []string{"google,krane-sku176", "google,krane", "mediatek,mt8183"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseDeviceTreeCompatible = %q; want %q", got, want)
+	}
+	if got := parseDeviceTreeCompatible(nil); len(got) != 0 {
+		t.Errorf("parseDeviceTreeCompatible(nil) = %q; want empty", got)
+	}
+}
+
 func TestIsBootTimeCalibrationEnabled(t *testing.T) {
 	testCases := []struct {
 		input  string
@@ -267,6 +689,208 @@ func TestIsBootTimeCalibrationEnabled(t *testing.T) {
 	}
 }
 
+func TestParseECProtocolVersion(t *testing.T) {
+	for _, tc := range []struct {
+		input   string
+		want    uint32
+		wantErr bool
+	}{
+		{
+			input: `Protocol info:
+  protocol versions: 3
+  max request:  0x0218 = 536 bytes
+  max response: 0x0218 = 536 bytes
+  flags: 0x00000001
+    0x00000001: in_progress
+`,
+			want: 3,
+		},
+		{
+			input: "Protocol info:\n  protocol versions: 2 3\n",
+			want:  3,
+		},
+		{
+			input:   "EC result 1 (INVALID_COMMAND)\n",
+			wantErr: true,
+		},
+	} {
+		got, err := parseECProtocolVersion(tc.input)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("parseECProtocolVersion(%q) unexpectedly succeeded", tc.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseECProtocolVersion(%q) failed: %v", tc.input, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("parseECProtocolVersion(%q) = %d; want %d", tc.input, got, tc.want)
+		}
+	}
+}
+
+func TestParseModemFirmwareVersion(t *testing.T) {
+	for _, tc := range []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{
+			input: `{"modem":{"generic":{"manufacturer":"Fibocom","revision":"18500.5001.00.05.27.12_5000.00.00.00"}}}`,
+			want:  "18500.5001.00.05.27.12_5000.00.00.00",
+		},
+		{
+			input:   `{"modem":{"generic":{"revision":"--"}}}`,
+			wantErr: true,
+		},
+		{
+			input:   `{"modem":{}}`,
+			wantErr: true,
+		},
+		{
+			input:   "error: couldn't find modem",
+			wantErr: true,
+		},
+	} {
+		got, err := parseModemFirmwareVersion([]byte(tc.input))
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("parseModemFirmwareVersion(%q) unexpectedly succeeded", tc.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseModemFirmwareVersion(%q) failed: %v", tc.input, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("parseModemFirmwareVersion(%q) = %q; want %q", tc.input, got, tc.want)
+		}
+	}
+}
+
+func TestWidevineL1Supported(t *testing.T) {
+	origLibGlob, origDaemonPath := oemCryptoLibGlob, cdmOEMCryptoDaemonPath
+	defer func() {
+		oemCryptoLibGlob, cdmOEMCryptoDaemonPath = origLibGlob, origDaemonPath
+	}()
+
+	for _, tc := range []struct {
+		name      string
+		hasLib    bool
+		hasDaemon bool
+		want      bool
+	}{
+		{"both", true, true, true},
+		{"no library", false, true, false},
+		{"no daemon", true, false, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			oemCryptoLibGlob = filepath.Join(dir, "usr/lib*/liboemcrypto.so")
+			cdmOEMCryptoDaemonPath = filepath.Join(dir, "usr/bin/cdm-oemcrypto")
+			var files []string
+			if tc.hasLib {
+				files = append(files, filepath.Join(dir, "usr/lib64/liboemcrypto.so"))
+			}
+			if tc.hasDaemon {
+				files = append(files, cdmOEMCryptoDaemonPath)
+			}
+			for _, f := range files {
+				if err := os.MkdirAll(filepath.Dir(f), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(f, nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := widevineL1Supported()
+			if err != nil {
+				t.Fatal("widevineL1Supported failed: ", err)
+			}
+			if got != tc.want {
+				t.Errorf("widevineL1Supported() = %v; want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// edidTiming returns an 18-byte EDID detailed timing descriptor.
+func edidTiming(clock10kHz, hActive, hBlank, vActive, vBlank int) []byte {
+	d := make([]byte, 18)
+	d[0], d[1] = byte(clock10kHz), byte(clock10kHz>>8)
+	d[2], d[3], d[4] = byte(hActive), byte(hBlank), byte(hActive>>8<<4|hBlank>>8)
+	d[5], d[6], d[7] = byte(vActive), byte(vBlank), byte(vActive>>8<<4|vBlank>>8)
+	return d
+}
+
+// fakeEDID returns an EDID base block containing the given descriptors.
+func fakeEDID(descs ...[]byte) []byte {
+	edid := make([]byte, 128)
+	copy(edid, edidHeader)
+	for i, d := range descs {
+		copy(edid[54+i*18:], d)
+	}
+	return edid
+}
+
+func TestParseEDIDMaxMode(t *testing.T) {
+	// A display descriptor with the monitor name.
+	name := append([]byte{0, 0, 0, 0xfc, 0}, []byte("PANEL\n       ")...)
+
+	for _, tc := range []struct {
+		name    string
+		edid    []byte
+		want    *displayMode
+		wantErr bool
+	}{
+		{
+			name: "60Hz FHD",
+			edid: fakeEDID(edidTiming(14850, 1920, 280, 1080, 45), name),
+			want: &displayMode{widthPx: 1920, heightPx: 1080, refreshRateHz: 60},
+		},
+		{
+			name: "60Hz and 120Hz QHD+",
+			edid: fakeEDID(edidTiming(26863, 2560, 160, 1600, 46), edidTiming(53725, 2560, 160, 1600, 46), name),
+			want: &displayMode{widthPx: 2560, heightPx: 1600, refreshRateHz: 120},
+		},
+		{
+			name:    "no timing",
+			edid:    fakeEDID(name),
+			wantErr: true,
+		},
+		{
+			name:    "bad header",
+			edid:    make([]byte, 128),
+			wantErr: true,
+		},
+		{
+			name:    "truncated",
+			edid:    edidHeader,
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseEDIDMaxMode(tc.edid)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("parseEDIDMaxMode unexpectedly succeeded: %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("parseEDIDMaxMode failed: ", err)
+			}
+			if *got != *tc.want {
+				t.Errorf("parseEDIDMaxMode = %+v; want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
 func TestFindSpeakerAmplifier(t *testing.T) {
 	testCases := []struct {
 		input  string
@@ -349,6 +973,37 @@ func TestFindSpeakerAmplifier(t *testing.T) {
 	}
 }
 
+func TestFindHeadphoneCodec(t *testing.T) {
+	testCases := []struct {
+		input  string
+		expect configpb.HardwareFeatures_Audio_AudioCodec
+	}{
+		{"i2c-10EC5682:00", configpb.HardwareFeatures_Audio_RT5682},
+		{"i2c-RTL5682:00", configpb.HardwareFeatures_Audio_ALC5682IVS},
+		{"i2c-DLGS7219:00", configpb.HardwareFeatures_Audio_DA7219},
+		{"i2c-10508825:00", configpb.HardwareFeatures_Audio_NAU88L25B},
+		{"i2c-10134242:00", configpb.HardwareFeatures_Audio_CS42L42},
+		{"i2c-ESSX8326:00", configpb.HardwareFeatures_Audio_ES8326},
+		{"wcd938x_codec", configpb.HardwareFeatures_Audio_WCD9385},
+	}
+	for _, tc := range testCases {
+		codec, match := matchHeadphoneCodec(tc.input)
+		if !match {
+			t.Errorf("Failed to match codec; input=%s", tc.input)
+			continue
+		}
+		if codec != tc.expect {
+			t.Errorf("Got %s, expect %s: input=%s", codec, tc.expect, tc.input)
+		}
+	}
+
+	for _, input := range []string{"MX98357A:00", "snd-soc-dummy", "i2c-10EC1015:00"} {
+		if codec, match := matchHeadphoneCodec(input); match {
+			t.Errorf("Unexpectedly matched codec %s; input=%s", codec, input)
+		}
+	}
+}
+
 func TestParseKConfigs(t *testing.T) {
 	flashromExtractCoreBootCmd = func(ctx context.Context, corebootBinName string) error {
 		return nil
@@ -42,6 +42,8 @@ func GetSysInfoState(ctx context.Context, req *protocol.GetSysInfoStateRequest)
 		logging.Infof(ctx, "Failed to pause log cleanup: %v", err)
 	}
 
+	startSysInfoCollectors(ctx, req.GetCollectors())
+
 	logInodeSizes, err := logs.GetLogInodeSizes(ctx, systemLogDir, systemLogExcludes)
 	if err != nil {
 		return nil, err
@@ -94,6 +96,7 @@ func CollectSysInfo(ctx context.Context, req *protocol.CollectSysInfoRequest) (*
 	if err := writeSystemInfo(ctx, logDir); err != nil {
 		logging.Infof(ctx, "Failed to collect additional system info: %v", err)
 	}
+	runSysInfoCollectors(ctx, logDir, req.GetCollectors())
 
 	// Collect crashes.
 	dumps, err := getCrashFilePaths()
@@ -172,21 +175,6 @@ func writeUnifiedLog(ctx context.Context, path, cursor string, fm logs.UnifiedLo
 
 // writeSystemInfo writes additional system information from the DUT to files within dir.
 func writeSystemInfo(ctx context.Context, dir string) error {
-	runCmd := func(cmd *exec.Cmd, fn string) error {
-		f, err := os.Create(filepath.Join(dir, fn))
-		if err != nil {
-			return err
-		}
-		defer f.Close()
-
-		if _, err := fmt.Fprintf(f, "%q at end of testing:\n\n", shutil.EscapeSlice(cmd.Args)); err != nil {
-			return err
-		}
-		cmd.Stdout = f
-		cmd.Stderr = f
-		return cmd.Run()
-	}
-
 	var errs []string
 	cmds := map[string][]string{
 		"upstart_jobs.txt": {"initctl", "list"},
@@ -217,7 +205,7 @@ func writeSystemInfo(ctx context.Context, dir string) error {
 		// Set timeout in case some commands take long time unexpectedly. (crbug.com/1147723)
 		cmdCtx, cancel := context.WithTimeout(ctx, 1*time.Minute)
 		cmd := exec.CommandContext(cmdCtx, cmd[0], cmd[1:]...)
-		if err := runCmd(cmd, fn); err != nil {
+		if err := runCmdToFile(cmd, filepath.Join(dir, fn)); err != nil {
 			errs = append(errs, fmt.Sprintf("failed running %q: %v", shutil.EscapeSlice(cmd.Args), err))
 		}
 		cancel()
@@ -247,6 +235,23 @@ func writeSystemInfo(ctx context.Context, dir string) error {
 	return nil
 }
 
+// runCmdToFile runs cmd and writes its output to a file at path, preceded by
+// the command line.
+func runCmdToFile(cmd *exec.Cmd, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := fmt.Fprintf(f, "%q at end of testing:\n\n", shutil.EscapeSlice(cmd.Args)); err != nil {
+		return err
+	}
+	cmd.Stdout = f
+	cmd.Stderr = f
+	return cmd.Run()
+}
+
 func suspendLogCleanup() error {
 	return os.WriteFile(cleanupLogsPausedPath, nil, 0666)
 }
@@ -0,0 +1,191 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package crosbundle
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"go.chromium.org/tast/core/errors"
+	"go.chromium.org/tast/core/fsutil"
+	"go.chromium.org/tast/core/internal/logging"
+)
+
+const (
+	ecDevicePath  = "/dev/cros_ec"
+	ishDevicePath = "/dev/cros_ish"
+
+	// btmonTracePath and btmonPIDPath are paths to the Bluetooth HCI trace
+	// being captured by btmon and the PID file of btmon. They are under
+	// /var/tmp so that they survive test runner restarts between
+	// GetSysInfoState and CollectSysInfo.
+	btmonTracePath = "/var/tmp/tast_btmon.btsnoop"
+	btmonPIDPath   = "/var/tmp/tast_btmon.pid"
+
+	// btmonStopTimeout is the time to wait for btmon to flush the trace and
+	// exit after it is interrupted.
+	btmonStopTimeout = 5 * time.Second
+
+	// sysInfoCollectorTimeout is the maximum duration of a collector.
+	sysInfoCollectorTimeout = time.Minute
+)
+
+// sysInfoCollector collects optional system information, typically
+// firmware-side logs of hardware-adjacent components, which is not collected
+// by default because it is not available on all DUTs or is expensive.
+type sysInfoCollector struct {
+	// start is called before tests run to start capturing information. It
+	// may be nil.
+	start func(ctx context.Context) error
+	// collect is called after tests run to write information to files in
+	// dir.
+	collect func(ctx context.Context, dir string) error
+}
+
+// sysInfoCollectors maps names of optional system info collectors to their
+// implementations. Collectors are enabled by their names in
+// GetSysInfoStateRequest and CollectSysInfoRequest.
+var sysInfoCollectors = map[string]*sysInfoCollector{
+	"ec_console": {collect: collectECConsole},
+	"ish":        {collect: collectISHConsole},
+	"bt_hci":     {start: startBTHCITrace, collect: collectBTHCITrace},
+}
+
+// startSysInfoCollectors starts the named optional system info collectors.
+// Errors are logged and not returned so that they do not prevent testing.
+func startSysInfoCollectors(ctx context.Context, names []string) {
+	for _, name := range names {
+		c, ok := sysInfoCollectors[name]
+		if !ok {
+			logging.Infof(ctx, "Unknown system info collector %q", name)
+			continue
+		}
+		if c.start == nil {
+			continue
+		}
+		if err := c.start(ctx); err != nil {
+			logging.Infof(ctx, "Failed to start system info collector %s: %v", name, err)
+		}
+	}
+}
+
+// runSysInfoCollectors runs the named optional system info collectors to
+// write information to dir. Errors are logged and not returned.
+func runSysInfoCollectors(ctx context.Context, dir string, names []string) {
+	for _, name := range names {
+		c, ok := sysInfoCollectors[name]
+		if !ok {
+			logging.Infof(ctx, "Unknown system info collector %q", name)
+			continue
+		}
+		cctx, cancel := context.WithTimeout(ctx, sysInfoCollectorTimeout)
+		if err := c.collect(cctx, dir); err != nil {
+			logging.Infof(ctx, "Failed to run system info collector %s: %v", name, err)
+		}
+		cancel()
+	}
+}
+
+// collectECConsole saves the console log of the EC.
+func collectECConsole(ctx context.Context, dir string) error {
+	if _, err := os.Stat(ecDevicePath); os.IsNotExist(err) {
+		logging.Debug(ctx, "Skipping EC console collection as the DUT has no EC")
+		return nil
+	}
+	return runCmdToFile(exec.CommandContext(ctx, "ectool", "console"), filepath.Join(dir, "ec_console.txt"))
+}
+
+// collectISHConsole saves the console log and the firmware version of the
+// Intel Integrated Sensor Hub, which exposes an EC-compatible interface.
+func collectISHConsole(ctx context.Context, dir string) error {
+	if _, err := os.Stat(ishDevicePath); os.IsNotExist(err) {
+		logging.Debug(ctx, "Skipping ISH log collection as the DUT has no ISH")
+		return nil
+	}
+	if err := runCmdToFile(exec.CommandContext(ctx, "ectool", "--name=cros_ish", "version"), filepath.Join(dir, "ish_version.txt")); err != nil {
+		return err
+	}
+	return runCmdToFile(exec.CommandContext(ctx, "ectool", "--name=cros_ish", "console"), filepath.Join(dir, "ish_console.txt"))
+}
+
+// startBTHCITrace starts btmon in background to capture Bluetooth HCI
+// traffic to btmonTracePath.
+func startBTHCITrace(ctx context.Context) error {
+	// Stop btmon left by a previous run, if any.
+	if err := stopBTHCITrace(ctx); err != nil {
+		logging.Infof(ctx, "Failed to stop stale btmon: %v", err)
+	}
+	os.Remove(btmonTracePath)
+
+	cmd := exec.Command("btmon", "-w", btmonTracePath)
+	// Detach btmon from the test runner so that it keeps running until
+	// CollectSysInfo is called.
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	pid := cmd.Process.Pid
+	cmd.Process.Release()
+	return os.WriteFile(btmonPIDPath, []byte(strconv.Itoa(pid)), 0644)
+}
+
+// stopBTHCITrace interrupts btmon started by startBTHCITrace and waits for it
+// to exit. It does nothing if btmon is not running.
+func stopBTHCITrace(ctx context.Context) error {
+	b, err := os.ReadFile(btmonPIDPath)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	os.Remove(btmonPIDPath)
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(b)))
+	if err != nil {
+		return errors.Wrapf(err, "broken PID file %s", btmonPIDPath)
+	}
+	// Make sure that the process is btmon to avoid killing an unrelated
+	// process reusing the PID.
+	comm, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "comm"))
+	if err != nil || strings.TrimSpace(string(comm)) != "btmon" {
+		return nil
+	}
+	// btmon flushes the trace on SIGINT.
+	if err := syscall.Kill(pid, syscall.SIGINT); err != nil {
+		return err
+	}
+	deadline := time.Now().Add(btmonStopTimeout)
+	for syscall.Kill(pid, 0) == nil {
+		if time.Now().After(deadline) {
+			syscall.Kill(pid, syscall.SIGKILL)
+			return errors.New("btmon did not exit on SIGINT")
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	return nil
+}
+
+// collectBTHCITrace stops btmon started by startBTHCITrace and saves the
+// Bluetooth HCI trace in the btsnoop format.
+func collectBTHCITrace(ctx context.Context, dir string) error {
+	if err := stopBTHCITrace(ctx); err != nil {
+		return err
+	}
+	if _, err := os.Stat(btmonTracePath); os.IsNotExist(err) {
+		return errors.New("Bluetooth HCI trace was not captured")
+	}
+	defer os.Remove(btmonTracePath)
+	return fsutil.CopyFile(btmonTracePath, filepath.Join(dir, "bt_hci.btsnoop"))
+}
@@ -0,0 +1,58 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package crosbundle
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSysInfoCollectors(t *testing.T) {
+	var calls []string
+	orig := sysInfoCollectors
+	defer func() { sysInfoCollectors = orig }()
+	sysInfoCollectors = map[string]*sysInfoCollector{
+		"foo": {
+			start: func(ctx context.Context) error {
+				calls = append(calls, "start foo")
+				return nil
+			},
+			collect: func(ctx context.Context, dir string) error {
+				calls = append(calls, "collect foo")
+				return os.WriteFile(filepath.Join(dir, "foo.txt"), []byte("foo"), 0644)
+			},
+		},
+		"bar": {
+			collect: func(ctx context.Context, dir string) error {
+				calls = append(calls, "collect bar")
+				return errors.New("failure")
+			},
+		},
+		"baz": {
+			collect: func(ctx context.Context, dir string) error {
+				calls = append(calls, "collect baz")
+				return nil
+			},
+		},
+	}
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	// Unknown collectors and failures are ignored.
+	names := []string{"bar", "foo", "unknown"}
+	startSysInfoCollectors(ctx, names)
+	runSysInfoCollectors(ctx, dir, names)
+
+	if want := []string{"start foo", "collect bar", "collect foo"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("Calls = %q; want %q", calls, want)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "foo.txt")); err != nil {
+		t.Error("Collected file not found: ", err)
+	}
+}
@@ -28,14 +28,28 @@ type Deps struct {
 // On success, it returns a list of reasons for which a test should be skipped.
 // If reasons is empty, a test should be run.
 func (d *Deps) Check(f *protocol.Features) (reasons []string, err error) {
+	skip, err := d.CheckSkip(f)
+	if err != nil {
+		return nil, err
+	}
+	return skip.GetReasons(), nil
+}
+
+// CheckSkip is similar to Check, but returns machine-readable reasons for
+// which a test should be skipped. It returns nil if a test should be run.
+func (d *Deps) CheckSkip(f *protocol.Features) (*protocol.Skip, error) {
 	if reason, skip := f.GetForceSkips()[d.Test]; skip {
-		return []string{reason.Reason}, nil
+		return NewSkip([]*protocol.SkipReason{{
+			Code:    protocol.SkipReason_MANUAL,
+			Details: []string{reason.Reason},
+		}}), nil
 	}
 
 	if !f.GetCheckDeps() {
 		return nil, nil
 	}
 
+	var reasons []*protocol.SkipReason
 	for role, swDep := range d.Software {
 		var dut *frameworkprotocol.DUTFeatures
 		if role != "" {
@@ -53,7 +67,10 @@ func (d *Deps) Check(f *protocol.Features) (reasons []string, err error) {
 			return nil, errors.Errorf("unknown SoftwareDeps: %v", strings.Join(unknown, ", "))
 		}
 		if len(missing) > 0 {
-			reasons = append(reasons, fmt.Sprintf("missing SoftwareDeps: %s", strings.Join(missing, ", ")))
+			reasons = append(reasons, &protocol.SkipReason{
+				Code:    protocol.SkipReason_UNSATISFIED_SWDEP,
+				Details: []string{fmt.Sprintf("missing SoftwareDeps: %s", strings.Join(missing, ", "))},
+			})
 		}
 	}
 
@@ -73,13 +90,16 @@ func (d *Deps) Check(f *protocol.Features) (reasons []string, err error) {
 		if err != nil {
 			return nil, err
 		}
-		for _, r := range sat {
-			reasons = append(reasons, r)
+		if len(sat) > 0 {
+			reasons = append(reasons, &protocol.SkipReason{
+				Code:    protocol.SkipReason_UNSATISFIED_HWDEP,
+				Details: sat,
+			})
 		}
 	}
 
 	if len(reasons) != 0 {
-		return reasons, nil
+		return NewSkip(reasons), nil
 	}
 
 	// If f.MaybeMissingVars is empty, no variables are considered as missing.
@@ -94,7 +114,10 @@ func (d *Deps) Check(f *protocol.Features) (reasons []string, err error) {
 			continue
 		}
 		if maybeMissingVars.MatchString(v) {
-			reasons = append(reasons, fmt.Sprintf("runtime variable %v is missing and matches with %v", v, maybeMissingVars))
+			reasons = append(reasons, &protocol.SkipReason{
+				Code:    protocol.SkipReason_MISSING_VAR,
+				Details: []string{fmt.Sprintf("runtime variable %v is missing and matches with %v", v, maybeMissingVars)},
+			})
 			continue
 		}
 		if f.GetInfra().GetMaybeMissingVars() == "" {
@@ -103,5 +126,19 @@ func (d *Deps) Check(f *protocol.Features) (reasons []string, err error) {
 		return nil, errors.Errorf("runtime variable %v is missing and doesn't match with %v", v, maybeMissingVars)
 	}
 
-	return reasons, nil
+	return NewSkip(reasons), nil
+}
+
+// NewSkip returns a protocol.Skip containing machine-readable reasons along
+// with their flattened human-readable details. It returns nil if reasons is
+// empty.
+func NewSkip(reasons []*protocol.SkipReason) *protocol.Skip {
+	if len(reasons) == 0 {
+		return nil
+	}
+	skip := &protocol.Skip{TypedReasons: reasons}
+	for _, r := range reasons {
+		skip.Reasons = append(skip.Reasons, r.GetDetails()...)
+	}
+	return skip
 }
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
 
 	"go.chromium.org/tast/core/internal/dep"
 	"go.chromium.org/tast/core/internal/protocol"
@@ -269,3 +270,84 @@ func TestCheckHardwareDepsCompanionFailed(t *testing.T) {
 		t.Errorf("Reasons unmatch (-got +want):\n%v", diff)
 	}
 }
+
+func TestCheckSkipCodes(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		d    *dep.Deps
+		f    *protocol.Features
+		want []*protocol.SkipReason
+	}{
+		{
+			name: "satisfied",
+			d:    &dep.Deps{Test: "pkg.Test", Software: map[string]dep.SoftwareDeps{"": {"sw1"}}},
+			f: &protocol.Features{
+				CheckDeps: true,
+				Dut: &frameworkprotocol.DUTFeatures{
+					Software: &frameworkprotocol.SoftwareFeatures{Available: []string{"sw1"}},
+				},
+			},
+		},
+		{
+			name: "manual",
+			d:    &dep.Deps{Test: "pkg.Test", Software: map[string]dep.SoftwareDeps{"": {"sw1"}}},
+			f: &protocol.Features{
+				CheckDeps:  true,
+				ForceSkips: map[string]*protocol.ForceSkip{"pkg.Test": {Reason: "disabled"}},
+			},
+			want: []*protocol.SkipReason{{Code: protocol.SkipReason_MANUAL, Details: []string{"disabled"}}},
+		},
+		{
+			name: "software and hardware",
+			d: &dep.Deps{
+				Test:     "pkg.Test",
+				Software: map[string]dep.SoftwareDeps{"": {"sw1"}},
+				Hardware: map[string]dep.HardwareDeps{"": hwdep.D(hwdep.Model("samus"))},
+			},
+			f: &protocol.Features{
+				CheckDeps: true,
+				Dut: &frameworkprotocol.DUTFeatures{
+					Software: &frameworkprotocol.SoftwareFeatures{Unavailable: []string{"sw1"}},
+					Hardware: &frameworkprotocol.HardwareFeatures{
+						DeprecatedDeviceConfig: &frameworkprotocol.DeprecatedDeviceConfig{
+							Id: &frameworkprotocol.DeprecatedConfigId{Model: "eve"},
+						},
+					},
+				},
+			},
+			want: []*protocol.SkipReason{
+				{Code: protocol.SkipReason_UNSATISFIED_SWDEP, Details: []string{"missing SoftwareDeps: sw1"}},
+				{Code: protocol.SkipReason_UNSATISFIED_HWDEP, Details: []string{"ModelId did not match"}},
+			},
+		},
+		{
+			name: "missing var",
+			d:    &dep.Deps{Test: "pkg.Test", Var: []string{"foo.bar"}},
+			f: &protocol.Features{
+				CheckDeps: true,
+				Infra:     &protocol.InfraFeatures{MaybeMissingVars: `foo\..*`},
+			},
+			want: []*protocol.SkipReason{{
+				Code:    protocol.SkipReason_MISSING_VAR,
+				Details: []string{`runtime variable foo.bar is missing and matches with ^foo\..*$`},
+			}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			skip, err := tc.d.CheckSkip(tc.f)
+			if err != nil {
+				t.Fatal("CheckSkip failed: ", err)
+			}
+			if diff := cmp.Diff(skip.GetTypedReasons(), tc.want, protocmp.Transform()); diff != "" {
+				t.Errorf("Typed reasons mismatch (-got +want):\n%v", diff)
+			}
+			var wantReasons []string
+			for _, r := range tc.want {
+				wantReasons = append(wantReasons, r.GetDetails()...)
+			}
+			if diff := cmp.Diff(skip.GetReasons(), wantReasons); diff != "" {
+				t.Errorf("Reasons mismatch (-got +want):\n%v", diff)
+			}
+		})
+	}
+}
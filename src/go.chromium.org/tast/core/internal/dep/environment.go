@@ -0,0 +1,69 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package dep
+
+import (
+	"fmt"
+	"strings"
+
+	"go.chromium.org/tast/core/internal/protocol"
+)
+
+// EnvDeps represents requirements on the environment a test runs in, which
+// are checked on the machine running the test just before it starts.
+//
+// Each requirement is either "name", requiring name to be present with any
+// value, or "name=value", requiring name to have exactly value.
+type EnvDeps struct {
+	// KernelCmdline lists required kernel command line parameters.
+	KernelCmdline []string
+	// Env lists required environment variables.
+	Env []string
+}
+
+// Empty returns true if d has no requirement.
+func (d *EnvDeps) Empty() bool {
+	return len(d.KernelCmdline) == 0 && len(d.Env) == 0
+}
+
+// CheckSkip checks d against the kernel command line cmdline and environ, a
+// list of "name=value" strings as returned by os.Environ. It returns
+// machine-readable reasons for which a test should be skipped, or nil if a
+// test should be run.
+func (d *EnvDeps) CheckSkip(cmdline string, environ []string) *protocol.Skip {
+	var reasons []*protocol.SkipReason
+	if missing := missingRequirements(d.KernelCmdline, strings.Fields(cmdline)); len(missing) > 0 {
+		reasons = append(reasons, &protocol.SkipReason{
+			Code:    protocol.SkipReason_UNSATISFIED_KERNEL_CMDLINE,
+			Details: []string{fmt.Sprintf("missing kernel command line parameters: %s", strings.Join(missing, ", "))},
+		})
+	}
+	if missing := missingRequirements(d.Env, environ); len(missing) > 0 {
+		reasons = append(reasons, &protocol.SkipReason{
+			Code:    protocol.SkipReason_UNSATISFIED_ENV,
+			Details: []string{fmt.Sprintf("missing environment variables: %s", strings.Join(missing, ", "))},
+		})
+	}
+	return NewSkip(reasons)
+}
+
+// missingRequirements returns requirements in reqs not satisfied by params,
+// a list of "name" or "name=value" strings.
+func missingRequirements(reqs, params []string) []string {
+	var missing []string
+ReqLoop:
+	for _, req := range reqs {
+		for _, p := range params {
+			if p == req {
+				continue ReqLoop
+			}
+			if !strings.Contains(req, "=") && strings.HasPrefix(p, req+"=") {
+				continue ReqLoop
+			}
+		}
+		missing = append(missing, req)
+	}
+	return missing
+}
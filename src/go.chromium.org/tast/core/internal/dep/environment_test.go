@@ -0,0 +1,67 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package dep_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	"go.chromium.org/tast/core/internal/dep"
+	"go.chromium.org/tast/core/internal/protocol"
+)
+
+func TestEnvDepsCheckSkip(t *testing.T) {
+	const cmdline = "cros_debug lsm.module_locking=0 console=ttyS0,115200n8\n"
+	environ := []string{"HOME=/root", "EMPTY="}
+
+	for _, tc := range []struct {
+		name string
+		deps dep.EnvDeps
+		want *protocol.Skip
+	}{
+		{
+			name: "none",
+		},
+		{
+			name: "satisfied",
+			deps: dep.EnvDeps{
+				KernelCmdline: []string{"cros_debug", "lsm.module_locking", "lsm.module_locking=0"},
+				Env:           []string{"HOME", "HOME=/root", "EMPTY", "EMPTY="},
+			},
+		},
+		{
+			name: "unsatisfied",
+			deps: dep.EnvDeps{
+				KernelCmdline: []string{"cros", "lsm.module_locking=1", "cros_debug"},
+				Env:           []string{"PATH", "HOME=/home"},
+			},
+			want: &protocol.Skip{
+				Reasons: []string{
+					"missing kernel command line parameters: cros, lsm.module_locking=1",
+					"missing environment variables: PATH, HOME=/home",
+				},
+				TypedReasons: []*protocol.SkipReason{
+					{
+						Code:    protocol.SkipReason_UNSATISFIED_KERNEL_CMDLINE,
+						Details: []string{"missing kernel command line parameters: cros, lsm.module_locking=1"},
+					},
+					{
+						Code:    protocol.SkipReason_UNSATISFIED_ENV,
+						Details: []string{"missing environment variables: PATH, HOME=/home"},
+					},
+				},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.deps.CheckSkip(cmdline, environ)
+			if diff := cmp.Diff(got, tc.want, protocmp.Transform()); diff != "" {
+				t.Errorf("CheckSkip mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
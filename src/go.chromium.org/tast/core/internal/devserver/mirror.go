@@ -0,0 +1,301 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package devserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.chromium.org/tast/core/internal/logging"
+)
+
+// errQuotaExceeded is returned by clients when a server refuses a request
+// because its quota for us is exhausted.
+var errQuotaExceeded = errors.New("quota exceeded")
+
+// Mirror is a source of files on Google Cloud Storage used by MirrorClient.
+type Mirror struct {
+	// Name identifies the mirror in logs and in the persisted state.
+	Name string
+	// Client is a client to download files from the mirror.
+	Client Client
+}
+
+// mirrorState is the backoff state of a mirror persisted across runs.
+type mirrorState struct {
+	// Failures is the number of consecutive failures of the mirror.
+	Failures int `json:"failures"`
+	// BackoffUntil is the time until which the mirror should not be used.
+	BackoffUntil time.Time `json:"backoffUntil"`
+}
+
+// MirrorClientOptions contains options used by MirrorClient.
+type MirrorClientOptions struct {
+	// StatePath is a path to a file to persist backoff states of mirrors
+	// across runs. If empty, states are kept in memory only.
+	StatePath string
+
+	// InitialBackoff is the duration for which a mirror is skipped after its
+	// first failure. It doubles on every consecutive failure up to MaxBackoff.
+	InitialBackoff time.Duration
+	// MaxBackoff is the maximum duration for which a failing mirror is skipped.
+	MaxBackoff time.Duration
+	// QuotaBackoff is the duration for which a mirror is skipped after it
+	// reports that its quota is exceeded.
+	QuotaBackoff time.Duration
+
+	// Now returns the current time. If nil, time.Now is used.
+	Now func() time.Time
+}
+
+var defaultMirrorOptions = &MirrorClientOptions{
+	InitialBackoff: time.Minute,
+	MaxBackoff:     time.Hour,
+	QuotaBackoff:   time.Hour,
+	Now:            time.Now,
+}
+
+// MirrorClient is an implementation of Client which downloads files from the
+// first available mirror in an ordered list.
+//
+// A mirror failing to serve a file is skipped for a while with exponential
+// backoff, and a mirror reporting that its quota is exceeded is skipped until
+// the quota is likely to be refilled. Mirrors being backed off are still tried
+// if all mirrors are being backed off.
+type MirrorClient struct {
+	mirrors []*Mirror
+	opts    MirrorClientOptions
+
+	mu     sync.Mutex
+	states map[string]*mirrorState
+}
+
+var _ Client = &MirrorClient{}
+
+// NewMirrorClient creates a MirrorClient trying mirrors in order.
+// If o is nil, default options are used. If o is partially nil, defaults are
+// used for them.
+func NewMirrorClient(ctx context.Context, mirrors []*Mirror, o *MirrorClientOptions) *MirrorClient {
+	if o == nil {
+		o = &MirrorClientOptions{}
+	}
+	opts := *o
+	if opts.InitialBackoff == 0 {
+		opts.InitialBackoff = defaultMirrorOptions.InitialBackoff
+	}
+	if opts.MaxBackoff == 0 {
+		opts.MaxBackoff = defaultMirrorOptions.MaxBackoff
+	}
+	if opts.QuotaBackoff == 0 {
+		opts.QuotaBackoff = defaultMirrorOptions.QuotaBackoff
+	}
+	if opts.Now == nil {
+		opts.Now = defaultMirrorOptions.Now
+	}
+
+	c := &MirrorClient{
+		mirrors: mirrors,
+		opts:    opts,
+		states:  make(map[string]*mirrorState),
+	}
+	if opts.StatePath != "" {
+		if err := c.loadStates(); err != nil && !os.IsNotExist(err) {
+			logging.Infof(ctx, "Failed to load data mirror states from %s: %v", opts.StatePath, err)
+		}
+	}
+	return c
+}
+
+// NewFallbackClient returns a Client which downloads files with primary,
+// falling back to Google Cloud Storage and then to HTTP(S) mirrors at
+// mirrorURLs in order. A file gs://bucket/path is downloaded from
+// <mirror URL>/bucket/path. If mirrorURLs is empty, primary is returned as is.
+// Backoff states of the mirrors are persisted to statePath.
+func NewFallbackClient(ctx context.Context, primary Client, mirrorURLs []string, statePath string) Client {
+	if len(mirrorURLs) == 0 {
+		return primary
+	}
+	var mirrors []*Mirror
+	if _, ok := primary.(*PseudoClient); ok {
+		mirrors = append(mirrors, &Mirror{Name: "gs", Client: primary})
+	} else {
+		mirrors = append(mirrors,
+			&Mirror{Name: "devserver", Client: primary},
+			&Mirror{Name: "gs", Client: NewPseudoClient()})
+	}
+	for _, u := range mirrorURLs {
+		mirrors = append(mirrors, &Mirror{Name: u, Client: NewPseudoClient(WithBaseURL(u))})
+	}
+	return NewMirrorClient(ctx, mirrors, &MirrorClientOptions{StatePath: statePath})
+}
+
+// TearDown tears down all mirror clients.
+func (c *MirrorClient) TearDown() error {
+	var firstErr error
+	for _, m := range c.mirrors {
+		if err := m.Client.TearDown(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Open opens a file on Google Cloud Storage at gsURL from the first mirror
+// serving it.
+func (c *MirrorClient) Open(ctx context.Context, gsURL string) (io.ReadCloser, error) {
+	var r io.ReadCloser
+	err := c.try(ctx, gsURL, func(cl Client) error {
+		var err error
+		r, err = cl.Open(ctx, gsURL)
+		return err
+	})
+	return r, err
+}
+
+// Stage stages a file on Google Cloud Storage at gsURL on the first mirror
+// serving it.
+func (c *MirrorClient) Stage(ctx context.Context, gsURL string) (*url.URL, error) {
+	var u *url.URL
+	err := c.try(ctx, gsURL, func(cl Client) error {
+		var err error
+		u, err = cl.Stage(ctx, gsURL)
+		return err
+	})
+	return u, err
+}
+
+// try calls f with mirror clients in order until it succeeds.
+// If all mirrors report that the file does not exist, os.ErrNotExist is
+// returned.
+func (c *MirrorClient) try(ctx context.Context, gsURL string, f func(cl Client) error) error {
+	mirrors := c.available()
+	var errs []error
+	notExist := true
+	for _, m := range mirrors {
+		err := f(m.Client)
+		if err == nil {
+			c.recordSuccess(m)
+			return nil
+		}
+		if errors.Is(err, os.ErrNotExist) {
+			// The mirror is healthy, but may not have the file.
+			continue
+		}
+		notExist = false
+		logging.Infof(ctx, "Failed to download %s from mirror %s: %v", gsURL, m.Name, err)
+		c.recordFailure(ctx, m, err)
+		errs = append(errs, fmt.Errorf("%s: %v", m.Name, err))
+	}
+	if notExist {
+		return os.ErrNotExist
+	}
+	return fmt.Errorf("all mirrors failed: %v", errs)
+}
+
+// available returns mirrors not being backed off, or all mirrors if all of
+// them are being backed off.
+func (c *MirrorClient) available() []*Mirror {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.opts.Now()
+	var mirrors []*Mirror
+	for _, m := range c.mirrors {
+		if s, ok := c.states[m.Name]; ok && now.Before(s.BackoffUntil) {
+			continue
+		}
+		mirrors = append(mirrors, m)
+	}
+	if len(mirrors) == 0 {
+		return c.mirrors
+	}
+	return mirrors
+}
+
+func (c *MirrorClient) recordSuccess(m *Mirror) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.states[m.Name]; !ok {
+		return
+	}
+	delete(c.states, m.Name)
+	c.saveStates()
+}
+
+func (c *MirrorClient) recordFailure(ctx context.Context, m *Mirror, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, ok := c.states[m.Name]
+	if !ok {
+		s = &mirrorState{}
+		c.states[m.Name] = s
+	}
+	s.Failures++
+
+	backoff := c.opts.QuotaBackoff
+	if !errors.Is(err, errQuotaExceeded) {
+		backoff = c.opts.InitialBackoff
+		for i := 1; i < s.Failures && backoff < c.opts.MaxBackoff; i++ {
+			backoff *= 2
+		}
+		if backoff > c.opts.MaxBackoff {
+			backoff = c.opts.MaxBackoff
+		}
+	}
+	s.BackoffUntil = c.opts.Now().Add(backoff)
+	logging.Infof(ctx, "Not using mirror %s until %s", m.Name, s.BackoffUntil.Format(time.RFC3339))
+	c.saveStates()
+}
+
+// loadStates loads mirror states from the state file.
+func (c *MirrorClient) loadStates() error {
+	b, err := os.ReadFile(c.opts.StatePath)
+	if err != nil {
+		return err
+	}
+	states := make(map[string]*mirrorState)
+	if err := json.Unmarshal(b, &states); err != nil {
+		return err
+	}
+	c.states = states
+	return nil
+}
+
+// saveStates saves mirror states to the state file. c.mu must be held.
+// Errors are ignored since states are just hints to skip unhealthy mirrors.
+func (c *MirrorClient) saveStates() {
+	if c.opts.StatePath == "" {
+		return
+	}
+	b, err := json.Marshal(c.states)
+	if err != nil {
+		return
+	}
+	// Write to a temporary file first so that concurrent runs never see a
+	// partially written file.
+	f, err := os.CreateTemp(filepath.Dir(c.opts.StatePath), filepath.Base(c.opts.StatePath)+".*")
+	if err != nil {
+		return
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return
+	}
+	if err := f.Close(); err != nil {
+		return
+	}
+	os.Rename(f.Name(), c.opts.StatePath)
+}
@@ -0,0 +1,199 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package devserver_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.chromium.org/tast/core/internal/devserver"
+	"go.chromium.org/tast/core/testutil"
+)
+
+const mirrorTestURL = "gs://bucket/path/file"
+
+// failingClient is a devserver.Client which always fails, counting calls.
+type failingClient struct {
+	err   error
+	calls int
+}
+
+func (c *failingClient) Open(ctx context.Context, gsURL string) (io.ReadCloser, error) {
+	c.calls++
+	return nil, c.err
+}
+
+func (c *failingClient) Stage(ctx context.Context, gsURL string) (*url.URL, error) {
+	c.calls++
+	return nil, c.err
+}
+
+func (c *failingClient) TearDown() error { return nil }
+
+// fakeClock is a clock whose current time can be advanced manually.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func readMirrorTestURL(t *testing.T, cl devserver.Client) string {
+	t.Helper()
+	r, err := cl.Open(context.Background(), mirrorTestURL)
+	if err != nil {
+		t.Fatal("Open failed: ", err)
+	}
+	defer r.Close()
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal("ReadAll failed: ", err)
+	}
+	return string(b)
+}
+
+func TestMirrorClientFallback(t *testing.T) {
+	bad := &failingClient{err: errors.New("server down")}
+	good := devserver.NewFakeClient(map[string][]byte{mirrorTestURL: []byte("data")})
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+
+	cl := devserver.NewMirrorClient(context.Background(), []*devserver.Mirror{
+		{Name: "bad", Client: bad},
+		{Name: "good", Client: good},
+	}, &devserver.MirrorClientOptions{
+		InitialBackoff: time.Minute,
+		MaxBackoff:     time.Hour,
+		Now:            clock.Now,
+	})
+
+	if got := readMirrorTestURL(t, cl); got != "data" {
+		t.Errorf("Open returned %q; want %q", got, "data")
+	}
+	if bad.calls != 1 {
+		t.Errorf("Bad mirror was called %d times; want 1", bad.calls)
+	}
+
+	// The bad mirror is backed off now.
+	readMirrorTestURL(t, cl)
+	if bad.calls != 1 {
+		t.Errorf("Bad mirror was called %d times during backoff; want 1", bad.calls)
+	}
+
+	// The bad mirror is retried after the backoff, and fails again.
+	clock.now = clock.now.Add(time.Minute)
+	readMirrorTestURL(t, cl)
+	if bad.calls != 2 {
+		t.Errorf("Bad mirror was called %d times after backoff; want 2", bad.calls)
+	}
+
+	// The backoff doubles after the second failure.
+	clock.now = clock.now.Add(time.Minute)
+	readMirrorTestURL(t, cl)
+	if bad.calls != 2 {
+		t.Errorf("Bad mirror was called %d times during doubled backoff; want 2", bad.calls)
+	}
+	clock.now = clock.now.Add(time.Minute)
+	readMirrorTestURL(t, cl)
+	if bad.calls != 3 {
+		t.Errorf("Bad mirror was called %d times after doubled backoff; want 3", bad.calls)
+	}
+}
+
+func TestMirrorClientAllBackedOff(t *testing.T) {
+	bad := &failingClient{err: errors.New("server down")}
+	cl := devserver.NewMirrorClient(context.Background(), []*devserver.Mirror{
+		{Name: "bad", Client: bad},
+	}, nil)
+
+	for i := 0; i < 2; i++ {
+		if _, err := cl.Open(context.Background(), mirrorTestURL); err == nil {
+			t.Error("Open succeeded unexpectedly")
+		}
+	}
+	// Mirrors are tried even if all of them are backed off.
+	if bad.calls != 2 {
+		t.Errorf("Bad mirror was called %d times; want 2", bad.calls)
+	}
+}
+
+func TestMirrorClientNotExist(t *testing.T) {
+	empty1 := devserver.NewFakeClient(nil)
+	empty2 := devserver.NewFakeClient(nil)
+	cl := devserver.NewMirrorClient(context.Background(), []*devserver.Mirror{
+		{Name: "empty1", Client: empty1},
+		{Name: "empty2", Client: empty2},
+	}, nil)
+
+	if _, err := cl.Open(context.Background(), mirrorTestURL); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Open returned %v; want %v", err, os.ErrNotExist)
+	}
+}
+
+func TestMirrorClientQuota(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		http.Error(w, "slow down", http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	good := devserver.NewFakeClient(map[string][]byte{mirrorTestURL: []byte("data")})
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	cl := devserver.NewMirrorClient(context.Background(), []*devserver.Mirror{
+		{Name: "quota", Client: devserver.NewPseudoClient(devserver.WithBaseURL(server.URL))},
+		{Name: "good", Client: good},
+	}, &devserver.MirrorClientOptions{
+		InitialBackoff: time.Minute,
+		QuotaBackoff:   time.Hour,
+		Now:            clock.Now,
+	})
+
+	readMirrorTestURL(t, cl)
+
+	// The mirror exceeding its quota is skipped longer than InitialBackoff.
+	clock.now = clock.now.Add(30 * time.Minute)
+	readMirrorTestURL(t, cl)
+	if calls != 1 {
+		t.Errorf("Mirror exceeding quota was called %d times; want 1", calls)
+	}
+}
+
+func TestMirrorClientPersistState(t *testing.T) {
+	td := testutil.TempDir(t)
+	defer os.RemoveAll(td)
+	statePath := filepath.Join(td, "state.json")
+
+	good := devserver.NewFakeClient(map[string][]byte{mirrorTestURL: []byte("data")})
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	newClient := func(bad devserver.Client) devserver.Client {
+		return devserver.NewMirrorClient(context.Background(), []*devserver.Mirror{
+			{Name: "bad", Client: bad},
+			{Name: "good", Client: good},
+		}, &devserver.MirrorClientOptions{
+			StatePath: statePath,
+			Now:       clock.Now,
+		})
+	}
+
+	bad1 := &failingClient{err: errors.New("server down")}
+	readMirrorTestURL(t, newClient(bad1))
+	if bad1.calls != 1 {
+		t.Errorf("Bad mirror was called %d times; want 1", bad1.calls)
+	}
+
+	// A new client loads the backoff state of the previous client.
+	bad2 := &failingClient{err: errors.New("server down")}
+	readMirrorTestURL(t, newClient(bad2))
+	if bad2.calls != 0 {
+		t.Errorf("Bad mirror was called %d times in a new client; want 0", bad2.calls)
+	}
+}
@@ -96,6 +96,9 @@ func (c *PseudoClient) Open(ctx context.Context, gsURL string) (io.ReadCloser, e
 	case http.StatusNotFound:
 		res.Body.Close()
 		return nil, os.ErrNotExist
+	case http.StatusTooManyRequests:
+		res.Body.Close()
+		return nil, fmt.Errorf("got status %d: %w", res.StatusCode, errQuotaExceeded)
 	default:
 		res.Body.Close()
 		return nil, fmt.Errorf("got status %d", res.StatusCode)
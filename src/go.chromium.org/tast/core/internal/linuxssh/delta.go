@@ -0,0 +1,226 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package linuxssh
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.chromium.org/tast/core/errors"
+	"go.chromium.org/tast/core/shutil"
+	"go.chromium.org/tast/core/ssh"
+)
+
+const (
+	// deltaBlockSize is the size of blocks compared in delta transfer.
+	deltaBlockSize = 1 << 20
+
+	// partialSuffix is appended to the destination path of a file being
+	// transferred by delta transfer. The partial file is left on the host
+	// when the transfer is interrupted, and is reused to resume the transfer.
+	partialSuffix = ".tast-partial"
+)
+
+// PutFilesOptions contains options for PutFilesWithOptions.
+type PutFilesOptions struct {
+	// SymlinkPolicy specifies how symbolic links should be handled.
+	SymlinkPolicy SymlinkPolicy
+
+	// BandwidthLimit is the maximum rate of data sent to the host in bytes
+	// per second. Zero means unlimited.
+	BandwidthLimit int64
+
+	// DeltaThreshold is the minimum size of regular files sent by delta
+	// transfer. Such files are compared with the host in blocks and only
+	// differing blocks are sent. Interrupted transfers of such files are
+	// resumed by the next call. Zero disables delta transfer.
+	DeltaThreshold int64
+}
+
+// rateLimiter limits the rate of data transfer.
+type rateLimiter struct {
+	rate  int64 // bytes per second; zero means unlimited
+	start time.Time
+	bytes int64
+}
+
+func newRateLimiter(rate int64) *rateLimiter {
+	return &rateLimiter{rate: rate, start: time.Now()}
+}
+
+// wait records that n bytes have been transferred, and blocks until the
+// transfer rate goes below the limit.
+func (l *rateLimiter) wait(ctx context.Context, n int) error {
+	l.bytes += int64(n)
+	if l.rate <= 0 {
+		return nil
+	}
+	due := time.Duration(float64(l.bytes) / float64(l.rate) * float64(time.Second))
+	d := due - time.Since(l.start)
+	if d <= 0 {
+		return nil
+	}
+	select {
+	case <-time.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// limitedReader is an io.Reader wrapper that limits the transfer rate.
+type limitedReader struct {
+	ctx context.Context
+	r   io.Reader
+	l   *rateLimiter
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	// Read in small chunks so that data is sent smoothly.
+	if chunk := int(r.l.rate / 10); r.l.rate > 0 && chunk > 0 && len(p) > chunk {
+		p = p[:chunk]
+	}
+	n, err := r.r.Read(p)
+	if werr := r.l.wait(r.ctx, n); werr != nil && err == nil {
+		err = werr
+	}
+	return n, err
+}
+
+// isDeltaCandidate returns whether a local file at src should be sent by delta
+// transfer.
+func isDeltaCandidate(src string, opts *PutFilesOptions) bool {
+	if opts.DeltaThreshold <= 0 {
+		return false
+	}
+	stat := os.Stat
+	if opts.SymlinkPolicy == PreserveSymlinks {
+		stat = os.Lstat
+	}
+	fi, err := stat(src)
+	if err != nil {
+		return false
+	}
+	return fi.Mode().IsRegular() && fi.Size() >= opts.DeltaThreshold
+}
+
+// getLocalBlockSHA1s returns SHA1s of blocks of deltaBlockSize bytes in the
+// local file at p.
+func getLocalBlockSHA1s(p string) ([]string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var sums []string
+	for {
+		h := sha1.New()
+		n, err := io.CopyN(h, f, deltaBlockSize)
+		if n > 0 {
+			sums = append(sums, hex.EncodeToString(h.Sum(nil)))
+		}
+		if err == io.EOF {
+			return sums, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// prepareRemotePartial creates the partial file for dst on the host if it
+// does not exist yet, seeding it with the current content of dst, and returns
+// SHA1s of its blocks of deltaBlockSize bytes.
+func prepareRemotePartial(ctx context.Context, s *ssh.Conn, dst string) ([]string, error) {
+	partial := shutil.Escape(dst + partialSuffix)
+	script := fmt.Sprintf(
+		`mkdir -p %s && { [ -f %s ] || { [ -f %s ] && cp %s %s; } || : > %s; } && split -b %d --filter=sha1sum %s`,
+		shutil.Escape(filepath.Dir(dst)), partial, shutil.Escape(dst), shutil.Escape(dst), partial, partial,
+		deltaBlockSize, partial)
+	out, err := s.CommandContext(ctx, "sh", "-c", script).Output(ssh.DumpLogOnError)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to hash blocks of %s", dst)
+	}
+	var sums []string
+	for _, l := range strings.Split(string(out), "\n") {
+		if l == "" {
+			continue
+		}
+		f := strings.Fields(l)
+		if len(f) != 2 || len(f[0]) != 40 {
+			return nil, errors.Errorf("unexpected line %q from sha1sum", l)
+		}
+		sums = append(sums, f[0])
+	}
+	return sums, nil
+}
+
+// putFileDelta copies a local regular file at src to dst on the host, sending
+// only blocks that differ from the partial file on the host. It returns the
+// number of bytes sent.
+func putFileDelta(ctx context.Context, s *ssh.Conn, src, dst string, l *rateLimiter) (int64, error) {
+	fi, err := os.Stat(src)
+	if err != nil {
+		return 0, err
+	}
+	lsums, err := getLocalBlockSHA1s(src)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to hash blocks of %s", src)
+	}
+	rsums, err := prepareRemotePartial(ctx, s, dst)
+	if err != nil {
+		return 0, err
+	}
+
+	f, err := os.Open(src)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	partial := dst + partialSuffix
+	var sent int64
+	// Send each run of consecutive differing blocks with a single command.
+	for i := 0; i < len(lsums); {
+		if i < len(rsums) && lsums[i] == rsums[i] {
+			i++
+			continue
+		}
+		j := i + 1
+		for j < len(lsums) && (j >= len(rsums) || lsums[j] != rsums[j]) {
+			j++
+		}
+		cr := &countingReader{r: &limitedReader{
+			ctx: ctx,
+			r:   io.NewSectionReader(f, int64(i)*deltaBlockSize, int64(j-i)*deltaBlockSize),
+			l:   l,
+		}}
+		cmd := s.CommandContext(ctx, "dd", "of="+partial, fmt.Sprintf("bs=%d", deltaBlockSize),
+			fmt.Sprintf("seek=%d", i), "conv=notrunc", "iflag=fullblock", "status=none")
+		cmd.Stdin = cr
+		err := cmd.Run(ssh.DumpLogOnError)
+		sent += cr.bytes
+		if err != nil {
+			return sent, errors.Wrapf(err, "failed to write blocks of %s", dst)
+		}
+		i = j
+	}
+
+	script := fmt.Sprintf(`truncate -s %d %s && chmod %o %s && { [ ! -d %s ] || rm -rf %s; } && mv -f %s %s`,
+		fi.Size(), shutil.Escape(partial), fi.Mode().Perm(), shutil.Escape(partial),
+		shutil.Escape(dst), shutil.Escape(dst), shutil.Escape(partial), shutil.Escape(dst))
+	if err := s.CommandContext(ctx, "sh", "-c", script).Run(ssh.DumpLogOnError); err != nil {
+		return sent, errors.Wrapf(err, "failed to finish writing %s", dst)
+	}
+	return sent, nil
+}
@@ -344,6 +344,14 @@ func (r *countingReader) Read(p []byte) (int, error) {
 // bytes is the amount of data sent over the wire (possibly after compression).
 func PutFiles(ctx context.Context, s *ssh.Conn, files map[string]string,
 	symlinkPolicy SymlinkPolicy) (bytes int64, err error) {
+	return PutFilesWithOptions(ctx, s, files, &PutFilesOptions{SymlinkPolicy: symlinkPolicy})
+}
+
+// PutFilesWithOptions is similar to PutFiles, but takes opts to customize
+// the transfer, e.g. to limit the bandwidth or to send large files by delta
+// transfer.
+func PutFilesWithOptions(ctx context.Context, s *ssh.Conn, files map[string]string,
+	opts *PutFilesOptions) (bytes int64, err error) {
 	af := make(map[string]string)
 	for src, dst := range files {
 		if !filepath.IsAbs(src) {
@@ -369,14 +377,45 @@ func PutFiles(ctx context.Context, s *ssh.Conn, files map[string]string,
 		return 0, nil
 	}
 
+	// Delta transfer relies on GNU coreutils, which are not available on
+	// Android devices.
+	df := make(map[string]string)
+	if s.Type() != ssh.ADB {
+		for l, r := range cf {
+			if isDeltaCandidate(l, opts) {
+				df[l] = r
+				delete(cf, l)
+			}
+		}
+	}
+
+	l := newRateLimiter(opts.BandwidthLimit)
+	if len(cf) > 0 {
+		if bytes, err = putFilesTar(ctx, s, cf, opts.SymlinkPolicy, l); err != nil {
+			return bytes, err
+		}
+	}
+	for src, dst := range df {
+		n, err := putFileDelta(ctx, s, src, dst, l)
+		bytes += n
+		if err != nil {
+			return bytes, err
+		}
+	}
+	return bytes, nil
+}
+
+// putFilesTar copies files on the local machine to the host by tar.
+func putFilesTar(ctx context.Context, s *ssh.Conn, files map[string]string,
+	symlinkPolicy SymlinkPolicy, l *rateLimiter) (int64, error) {
 	args := []string{"-c", "--gzip", "-C", "/"}
 	if symlinkPolicy == DereferenceSymlinks {
 		args = append(args, "--dereference")
 	}
-	for l, r := range cf {
+	for l, r := range files {
 		args = append(args, tarTransformFlag(strings.TrimPrefix(l, "/"), strings.TrimPrefix(r, "/")))
 	}
-	for l := range cf {
+	for l := range files {
 		args = append(args, strings.TrimPrefix(l, "/"))
 	}
 	cmd := exec.CommandContext(ctx, "/bin/tar", args...)
@@ -397,7 +436,7 @@ func PutFiles(ctx context.Context, s *ssh.Conn, files map[string]string,
 		rcmd = s.CommandContext(ctx, "tar", "-x", "--gzip", "--no-same-owner", "--recursive-unlink", "-p", "-C", "/")
 	}
 
-	cr := &countingReader{r: p}
+	cr := &countingReader{r: &limitedReader{ctx: ctx, r: p, l: l}}
 	rcmd.Stdin = cr
 	if err := rcmd.Run(ssh.DumpLogOnError); err != nil {
 		return 0, fmt.Errorf("remote tar failed: %v", err)
@@ -6,10 +6,13 @@ package linuxssh_test
 
 import (
 	"fmt"
+	"math/rand"
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 
@@ -581,6 +584,113 @@ func TestPutFilesPerm(t *testing.T) {
 	}
 }
 
+// randomData returns n bytes of pseudo-random data that does not compress.
+func randomData(n int) string {
+	b := make([]byte, n)
+	rand.New(rand.NewSource(1)).Read(b)
+	return string(b)
+}
+
+func TestPutFilesDelta(t *testing.T) {
+	t.Parallel()
+	td := sshtest.NewTestDataConn(t)
+	defer td.Close()
+
+	const blockSize = 1 << 20
+	data := randomData(3*blockSize + 100)
+	tmpDir, srcDir := initFileTest(t, map[string]string{"file": data})
+	defer os.RemoveAll(tmpDir)
+	if err := os.Chmod(filepath.Join(srcDir, "file"), 0640); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the second block differs from the old file on the host.
+	old := data[:blockSize] + strings.Repeat("x", blockSize) + data[2*blockSize:]
+	dstDir := filepath.Join(tmpDir, "dst")
+	if err := testutil.WriteFiles(dstDir, map[string]string{"file": old + "trailing garbage"}); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := linuxssh.PutFilesWithOptions(td.Ctx, td.Hst, map[string]string{
+		filepath.Join(srcDir, "file"): filepath.Join(dstDir, "file"),
+	}, &linuxssh.PutFilesOptions{SymlinkPolicy: linuxssh.PreserveSymlinks, DeltaThreshold: blockSize})
+	if err != nil {
+		t.Fatal("PutFilesWithOptions failed: ", err)
+	}
+	// The second and the last blocks are sent.
+	if want := int64(blockSize + 100); n != want {
+		t.Errorf("PutFilesWithOptions sent %d bytes; want %d", n, want)
+	}
+	if err := checkDir(dstDir, map[string]string{"file": data}); err != nil {
+		t.Error(err)
+	}
+	if fi, err := os.Stat(filepath.Join(dstDir, "file")); err != nil {
+		t.Error(err)
+	} else if perm := fi.Mode().Perm(); perm != 0640 {
+		t.Errorf("File has perms %#o; want %#o", perm, 0640)
+	}
+}
+
+func TestPutFilesDeltaResume(t *testing.T) {
+	t.Parallel()
+	td := sshtest.NewTestDataConn(t)
+	defer td.Close()
+
+	const blockSize = 1 << 20
+	data := randomData(4 * blockSize)
+	tmpDir, srcDir := initFileTest(t, map[string]string{"file": data})
+	defer os.RemoveAll(tmpDir)
+
+	// Simulate an interrupted transfer which has written the first 2.5 blocks.
+	dstDir := filepath.Join(tmpDir, "dst")
+	if err := testutil.WriteFiles(dstDir, map[string]string{
+		"dir/file.tast-partial": data[:5*blockSize/2],
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := linuxssh.PutFilesWithOptions(td.Ctx, td.Hst, map[string]string{
+		filepath.Join(srcDir, "file"): filepath.Join(dstDir, "dir/file"),
+	}, &linuxssh.PutFilesOptions{SymlinkPolicy: linuxssh.PreserveSymlinks, DeltaThreshold: 1})
+	if err != nil {
+		t.Fatal("PutFilesWithOptions failed: ", err)
+	}
+	if want := int64(2 * blockSize); n != want {
+		t.Errorf("PutFilesWithOptions sent %d bytes; want %d", n, want)
+	}
+	if err := checkDir(dstDir, map[string]string{"dir/file": data}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPutFilesBandwidthLimit(t *testing.T) {
+	t.Parallel()
+	td := sshtest.NewTestDataConn(t)
+	defer td.Close()
+
+	const (
+		size  = 64 * 1024
+		limit = 256 * 1024
+	)
+	data := randomData(size)
+	tmpDir, srcDir := initFileTest(t, map[string]string{"file": data})
+	defer os.RemoveAll(tmpDir)
+
+	dstDir := filepath.Join(tmpDir, "dst")
+	start := time.Now()
+	if _, err := linuxssh.PutFilesWithOptions(td.Ctx, td.Hst, map[string]string{
+		filepath.Join(srcDir, "file"): filepath.Join(dstDir, "file"),
+	}, &linuxssh.PutFilesOptions{SymlinkPolicy: linuxssh.PreserveSymlinks, BandwidthLimit: limit}); err != nil {
+		t.Fatal("PutFilesWithOptions failed: ", err)
+	}
+	if elapsed, min := time.Since(start), time.Second*size/limit; elapsed < min {
+		t.Errorf("PutFilesWithOptions took %v; want at least %v", elapsed, min)
+	}
+	if err := checkDir(dstDir, map[string]string{"file": data}); err != nil {
+		t.Error(err)
+	}
+}
+
 func TestGetAndDeleteFile(t *testing.T) {
 	t.Parallel()
 	td := sshtest.NewTestDataConn(t)
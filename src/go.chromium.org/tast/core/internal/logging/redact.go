@@ -0,0 +1,63 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package logging
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// redactedValue replaces secret values in logs.
+	redactedValue = "<redacted>"
+
+	// minRedactLen is the minimum length of secret values to be redacted.
+	// Shorter values are too likely to appear in logs by coincidence.
+	minRedactLen = 4
+)
+
+// RedactingLogger is a Logger that replaces secret values in logs before
+// passing them to another Logger.
+type RedactingLogger struct {
+	logger Logger
+	r      *strings.Replacer
+}
+
+// NewRedactingLogger creates a new RedactingLogger that redacts secrets in
+// logs passed to logger. Secrets shorter than a few characters are not
+// redacted.
+func NewRedactingLogger(logger Logger, secrets []string) *RedactingLogger {
+	var ss []string
+	for _, s := range secrets {
+		if len(s) >= minRedactLen {
+			ss = append(ss, s)
+		}
+	}
+	// Replace longer secrets first so that a secret containing another one
+	// is redacted entirely.
+	sort.Slice(ss, func(i, j int) bool { return len(ss[i]) > len(ss[j]) })
+	var args []string
+	for _, s := range ss {
+		args = append(args, s, redactedValue)
+	}
+	return &RedactingLogger{logger: logger, r: strings.NewReplacer(args...)}
+}
+
+// Log redacts secrets in msg and passes it to the underlying logger.
+func (l *RedactingLogger) Log(level Level, ts time.Time, msg string) {
+	l.logger.Log(level, ts, l.r.Replace(msg))
+}
+
+// AttachRedactor creates a new context where secrets in logs emitted via the
+// new context are redacted before they reach the logger attached to ctx.
+func AttachRedactor(ctx context.Context, secrets []string) context.Context {
+	logger, ok := loggerFromContext(ctx)
+	if !ok || len(secrets) == 0 {
+		return ctx
+	}
+	return AttachLoggerNoPropagation(ctx, NewRedactingLogger(logger, secrets))
+}
@@ -0,0 +1,34 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package logging_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go.chromium.org/tast/core/internal/logging"
+	"go.chromium.org/tast/core/internal/logging/loggingtest"
+)
+
+func TestAttachRedactor(t *testing.T) {
+	logger := loggingtest.NewLogger(t, logging.LevelInfo)
+	ctx := logging.AttachLogger(context.Background(), logger)
+	ctx = logging.AttachRedactor(ctx, []string{"hunter2", "hunter22", "abc", ""})
+
+	logging.Info(ctx, "password is hunter22")
+	logging.Info(ctx, "password is hunter2")
+	logging.Info(ctx, "abc is too short to redact")
+
+	want := []string{
+		"password is <redacted>",
+		"password is <redacted>",
+		"abc is too short to redact",
+	}
+	if diff := cmp.Diff(logger.Logs(), want); diff != "" {
+		t.Errorf("Logs mismatch (-got +want):\n%s", diff)
+	}
+}
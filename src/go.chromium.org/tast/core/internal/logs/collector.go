@@ -0,0 +1,123 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package logs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// defaultSystemLogFile is a plain-text system log file used when journald is
+// unavailable.
+const defaultSystemLogFile = "/var/log/messages"
+
+// SystemLogCollector collects system log entries recorded in a period, e.g.
+// while a test is running.
+type SystemLogCollector interface {
+	// Bookmark returns an opaque string pointing to the current tip of
+	// system logs.
+	Bookmark(ctx context.Context) (string, error)
+	// Collect writes system log entries recorded after bookmark to w.
+	// bookmark should have been returned by an earlier call to Bookmark.
+	Collect(ctx context.Context, bookmark string, w io.Writer) error
+}
+
+// JournaldCollector is a SystemLogCollector using journald cursors.
+type JournaldCollector struct{}
+
+var _ SystemLogCollector = JournaldCollector{}
+
+// Bookmark returns a journald cursor to the current tip of unified system
+// logs.
+func (JournaldCollector) Bookmark(ctx context.Context) (string, error) {
+	return GetUnifiedLogCursor(ctx)
+}
+
+// Collect writes human-readable unified system log entries recorded after
+// the cursor bookmark to w.
+func (JournaldCollector) Collect(ctx context.Context, bookmark string, w io.Writer) error {
+	return ExportUnifiedLogs(ctx, w, bookmark, CompactLogFormat)
+}
+
+// FileOffsetCollector is a SystemLogCollector reading a plain-text log file
+// from an offset. It is meant for boards without journald.
+type FileOffsetCollector struct {
+	// Path is the path to the log file.
+	Path string
+}
+
+var _ SystemLogCollector = FileOffsetCollector{}
+
+// Bookmark returns the inode and the size of the log file.
+func (c FileOffsetCollector) Bookmark(ctx context.Context) (string, error) {
+	ino, size, err := statLogFile(c.Path)
+	if os.IsNotExist(err) {
+		return "0:0", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d:%d", ino, size), nil
+}
+
+// Collect writes contents appended to the log file after bookmark to w. If
+// the log file has been rotated since bookmark, its whole contents are
+// written.
+func (c FileOffsetCollector) Collect(ctx context.Context, bookmark string, w io.Writer) error {
+	var origIno uint64
+	var offset int64
+	if _, err := fmt.Sscanf(bookmark, "%d:%d", &origIno, &offset); err != nil {
+		return fmt.Errorf("invalid bookmark %q: %v", bookmark, err)
+	}
+
+	f, err := os.Open(c.Path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	ino, size, err := statLogFile(c.Path)
+	if err != nil {
+		return err
+	}
+	if ino != origIno || size < offset {
+		offset = 0
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// statLogFile returns the inode and the size of the file at path.
+func statLogFile(path string) (ino uint64, size int64, err error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	stat, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, fmt.Errorf("can't get inode for %s", path)
+	}
+	return stat.Ino, fi.Size(), nil
+}
+
+// NewSystemLogCollector returns a SystemLogCollector suitable for the current
+// device. It uses journald cursors if croslog is available, and otherwise
+// falls back to reading /var/log/messages from offsets.
+func NewSystemLogCollector() SystemLogCollector {
+	if _, err := exec.LookPath("croslog"); err == nil {
+		return JournaldCollector{}
+	}
+	return FileOffsetCollector{Path: defaultSystemLogFile}
+}
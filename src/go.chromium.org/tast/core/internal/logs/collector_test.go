@@ -0,0 +1,76 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package logs_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.chromium.org/tast/core/internal/logs"
+	"go.chromium.org/tast/core/testutil"
+)
+
+func TestFileOffsetCollector(t *testing.T) {
+	ctx := context.Background()
+	td := testutil.TempDir(t)
+	defer os.RemoveAll(td)
+
+	path := filepath.Join(td, "messages")
+	c := logs.FileOffsetCollector{Path: path}
+
+	collect := func(bookmark string) string {
+		t.Helper()
+		var buf bytes.Buffer
+		if err := c.Collect(ctx, bookmark, &buf); err != nil {
+			t.Fatal("Collect failed: ", err)
+		}
+		return buf.String()
+	}
+	appendLog := func(s string) {
+		t.Helper()
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if _, err := f.WriteString(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The log file does not exist yet.
+	bm, err := c.Bookmark(ctx)
+	if err != nil {
+		t.Fatal("Bookmark failed: ", err)
+	}
+	appendLog("a\n")
+	if got, want := collect(bm), "a\n"; got != want {
+		t.Errorf("Collect after creation = %q; want %q", got, want)
+	}
+
+	// Only entries appended after the bookmark are collected.
+	if bm, err = c.Bookmark(ctx); err != nil {
+		t.Fatal("Bookmark failed: ", err)
+	}
+	appendLog("b\n")
+	if got, want := collect(bm), "b\n"; got != want {
+		t.Errorf("Collect after append = %q; want %q", got, want)
+	}
+
+	// The whole file is collected after rotation.
+	if bm, err = c.Bookmark(ctx); err != nil {
+		t.Fatal("Bookmark failed: ", err)
+	}
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	appendLog("c\n")
+	if got, want := collect(bm), "c\n"; got != want {
+		t.Errorf("Collect after rotation = %q; want %q", got, want)
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"go.chromium.org/tast/core/internal/protocol"
 	"go.chromium.org/tast/core/internal/rpc"
 	"go.chromium.org/tast/core/internal/run/genericexec"
+	"go.chromium.org/tast/core/ssh"
 )
 
 // rpcConn represents a gRPC connection to a test bundle.
@@ -50,14 +51,19 @@ func (c *rpcConn) Conn() *grpc.ClientConn {
 // Client is a gRPC protocol client to a test bundle.
 type Client struct {
 	cmd        genericexec.Cmd
+	pkillCmd   genericexec.Cmd
 	msgTimeout time.Duration
 	bundlePath string
 }
 
-// New creates a new Client.
-func New(cmd genericexec.Cmd, msgTimeOut time.Duration, bundlePath string) *Client {
+// New creates a new Client. pkillCmd runs pkill on the machine running the
+// test bundle to request it to dump goroutines when it stalls; it can be nil.
+// If no message is received from the test bundle for msgTimeOut, it is
+// considered stalled and test execution is aborted.
+func New(cmd, pkillCmd genericexec.Cmd, msgTimeOut time.Duration, bundlePath string) *Client {
 	return &Client{
 		cmd:        cmd,
+		pkillCmd:   pkillCmd,
 		msgTimeout: msgTimeOut,
 		bundlePath: bundlePath, // bundlePath is used for debugging purpose.
 	}
@@ -119,13 +125,35 @@ func LocalCommand(exec string, proxy bool, cc *target.ConnCache) *genericexec.SS
 	}
 	args = append(args, exec)
 
-	cmd := genericexec.CommandSSH(cc.Conn().SSHConn(), "env", args...)
-	return cmd
+	return genericexec.CommandSSH(cc.Conn().SSHConn(), "env", args...).WithRetry(retryPolicy(cc))
+}
+
+// retryPolicy returns a policy to retry commands on the target specified by cc
+// on transient SSH failures, reconnecting to the target if needed.
+func retryPolicy(cc *target.ConnCache) genericexec.RetryPolicy {
+	p := genericexec.DefaultRetryPolicy
+	p.Reconnect = func(ctx context.Context) (*ssh.Conn, error) {
+		if err := cc.EnsureConn(ctx, false, true); err != nil {
+			return nil, err
+		}
+		return cc.Conn().SSHConn(), nil
+	}
+	return p
 }
 
 // NewLocal creates a bundle client to the local bundle.
 func NewLocal(bundle, bundleDir string, proxy bool, cc *target.ConnCache, msgTimeout time.Duration) *Client {
 	bundlePath := filepath.Join(bundleDir, bundle)
 	cmd := LocalCommand(bundlePath, proxy, cc)
-	return New(cmd, msgTimeout, filepath.Join(bundleDir, bundle))
+	pkillCmd := genericexec.CommandSSH(cc.Conn().SSHConn(), "pkill").WithRetry(retryPolicy(cc))
+	return New(cmd, pkillCmd, msgTimeout, filepath.Join(bundleDir, bundle))
+}
+
+// NewHost creates a bundle client to the local bundle installed on the host,
+// which is run directly without SSH.
+func NewHost(bundle, bundleDir string, msgTimeout time.Duration) *Client {
+	bundlePath := filepath.Join(bundleDir, bundle)
+	cmd := genericexec.CommandExec(bundlePath)
+	pkillCmd := genericexec.CommandExec("pkill")
+	return New(cmd, pkillCmd, msgTimeout, bundlePath)
 }
@@ -12,6 +12,7 @@ import (
 	"google.golang.org/grpc/status"
 
 	"go.chromium.org/tast/core/errors"
+	"go.chromium.org/tast/core/internal/logging"
 	"go.chromium.org/tast/core/internal/protocol"
 )
 
@@ -42,6 +43,9 @@ type RunTestsOutput interface {
 	// EntityCopyEnd is called when copy of output files completes after an entity
 	// finishes.
 	EntityCopyEnd(ctx context.Context, ev *protocol.EntityCopyEndEvent) error
+	// EntityQuarantine is called when a test is not run since it has been
+	// quarantined.
+	EntityQuarantine(ctx context.Context, ev *protocol.EntityQuarantineEvent) error
 	// RunLog is called with a log not associated with an entity.
 	RunLog(ctx context.Context, ev *protocol.RunLogEvent) error
 	// StackOperation is called to request remote fixture stack operation.
@@ -101,6 +105,24 @@ func (c *Client) RunTests(ctx context.Context, bcfg *protocol.BundleConfig, rcfg
 			return errors.Wrap(err, "initializing test run")
 		}
 
+		// Abort test execution if the test bundle stalls. Canceling ctx
+		// aborts the stream and kills the test bundle, and tests not run
+		// yet are retried by the caller.
+		var stall *stallDetector
+		if c.msgTimeout > 0 {
+			stall = newStallDetector(c.msgTimeout, func(err error) {
+				logging.Infof(ctx, "Detected stall of %s: %v", c.bundlePath, err)
+				c.requestGoroutineDump(ctx)
+				cancel()
+			})
+			defer stall.Stop()
+			defer func() {
+				if err := stall.Err(); err != nil {
+					runErr = err
+				}
+			}()
+		}
+
 		for {
 			res, err := stream.Recv()
 			if err == io.EOF {
@@ -113,9 +135,15 @@ func (c *Client) RunTests(ctx context.Context, bcfg *protocol.BundleConfig, rcfg
 				}
 				return errors.Wrapf(err, "connection to test bundle %s broken", c.BundlePath())
 			}
+			if stall != nil {
+				stall.Received(res)
+			}
 			if err := handleEvent(ctx, res, out, stream); err != nil {
 				return err
 			}
+			if stall != nil {
+				stall.Handled()
+			}
 		}
 	}())
 }
@@ -134,6 +162,8 @@ func handleEvent(ctx context.Context, res *protocol.RunTestsResponse, out RunTes
 		return out.EntityEnd(ctx, t.EntityEnd)
 	case *protocol.RunTestsResponse_EntityCopyEnd:
 		return out.EntityCopyEnd(ctx, t.EntityCopyEnd)
+	case *protocol.RunTestsResponse_EntityQuarantine:
+		return out.EntityQuarantine(ctx, t.EntityQuarantine)
 	case *protocol.RunTestsResponse_StackOperation:
 		resp := out.StackOperation(ctx, t.StackOperation)
 		return stream.Send(&protocol.RunTestsRequest{
@@ -0,0 +1,134 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package bundleclient
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.chromium.org/tast/core/errors"
+	"go.chromium.org/tast/core/internal/command"
+	"go.chromium.org/tast/core/internal/logging"
+	"go.chromium.org/tast/core/internal/protocol"
+)
+
+// goroutineDumpWait is the time to wait for a stalled test bundle to dump
+// goroutines before it is killed.
+var goroutineDumpWait = 3 * time.Second
+
+// stallDetector detects stalls of a test bundle, e.g. due to a hung SSH
+// connection or a deadlock in the bundle, from missing messages. Test bundles
+// send heartbeat messages periodically, so no message is received for a while
+// only if something goes wrong.
+type stallDetector struct {
+	timeout time.Duration
+	timer   *time.Timer
+
+	mu        sync.Mutex
+	last      time.Time                // time the last message was received or handled
+	handling  bool                     // true while a message is being handled
+	heartbeat *protocol.HeartbeatEvent // last heartbeat message received
+	err       error                    // non-nil after a stall is detected
+}
+
+// newStallDetector starts a stallDetector calling onStall in a separate
+// goroutine if no message is received for timeout. Stop must be called after
+// use.
+func newStallDetector(timeout time.Duration, onStall func(err error)) *stallDetector {
+	d := &stallDetector{timeout: timeout, last: time.Now()}
+	d.timer = time.AfterFunc(timeout, func() {
+		d.mu.Lock()
+		// Handling a message, e.g. copying output files of a test, can
+		// take long. A message might also be received just before the
+		// timer fires.
+		if d.handling {
+			d.timer.Reset(d.timeout)
+			d.mu.Unlock()
+			return
+		}
+		if idle := time.Since(d.last); idle < d.timeout {
+			d.timer.Reset(d.timeout - idle)
+			d.mu.Unlock()
+			return
+		}
+		d.err = d.describe()
+		err := d.err
+		d.mu.Unlock()
+		onStall(err)
+	})
+	return d
+}
+
+// Received is called on receiving a message from the test bundle. Stalls are
+// not detected until Handled is called.
+func (d *stallDetector) Received(res *protocol.RunTestsResponse) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.last = time.Now()
+	d.handling = true
+	if hb := res.GetHeartbeat(); hb != nil {
+		d.heartbeat = hb
+	}
+}
+
+// Handled is called after handling a message received from the test bundle.
+func (d *stallDetector) Handled() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.last = time.Now()
+	d.handling = false
+}
+
+// Err returns an error describing a stall if it has been detected.
+func (d *stallDetector) Err() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.err
+}
+
+// Stop stops detecting stalls.
+func (d *stallDetector) Stop() {
+	d.timer.Stop()
+}
+
+// describe returns an error describing the stall. d.mu must be held.
+func (d *stallDetector) describe() error {
+	msg := fmt.Sprintf("test bundle stalled: no message received for %v", time.Since(d.last).Round(time.Second))
+	if hb := d.heartbeat; hb.GetEntityName() != "" {
+		msg += fmt.Sprintf(" (last heartbeat at %s: %s running for %v)",
+			hb.GetTime().AsTime().Local().Format("15:04:05"), hb.GetEntityName(), hb.GetElapsed().AsDuration().Round(time.Second))
+	}
+	return errors.New(msg)
+}
+
+// requestGoroutineDump asks the stalled test bundle to dump stack traces of
+// its goroutines to stderr, which is passed through to the tast command.
+func (c *Client) requestGoroutineDump(ctx context.Context) {
+	if c.pkillCmd == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, goroutineDumpWait)
+	defer cancel()
+	if err := c.pkillCmd.Run(ctx, goroutineDumpArgs(c.bundlePath), nil, nil, nil); err != nil {
+		logging.Infof(ctx, "Failed to request %s to dump goroutines: %v", c.bundlePath, err)
+		return
+	}
+	logging.Infof(ctx, "Requested %s to dump goroutines", c.bundlePath)
+	// Give the bundle a chance to write stack traces.
+	<-ctx.Done()
+}
+
+// goroutineDumpArgs returns arguments to pkill to request a test bundle
+// running at bundlePath to dump goroutines.
+func goroutineDumpArgs(bundlePath string) []string {
+	return []string{
+		"--signal", strconv.Itoa(int(command.GoroutineDumpSignal)),
+		"-f", "-x", regexp.QuoteMeta(bundlePath) + " -rpc",
+	}
+}
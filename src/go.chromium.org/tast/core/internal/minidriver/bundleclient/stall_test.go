@@ -0,0 +1,61 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package bundleclient
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go.chromium.org/tast/core/internal/protocol"
+)
+
+func TestStallDetector(t *testing.T) {
+	const timeout = 100 * time.Millisecond
+
+	stalled := make(chan error, 1)
+	d := newStallDetector(timeout, func(err error) { stalled <- err })
+	defer d.Stop()
+
+	// Keep receiving messages for a while longer than timeout.
+	heartbeat := &protocol.RunTestsResponse{Type: &protocol.RunTestsResponse_Heartbeat{Heartbeat: &protocol.HeartbeatEvent{
+		Time:       timestamppb.Now(),
+		EntityName: "pkg.Test",
+		Elapsed:    durationpb.New(time.Minute),
+	}}}
+	for i := 0; i < 5; i++ {
+		d.Received(heartbeat)
+		d.Handled()
+		time.Sleep(timeout / 2)
+	}
+
+	// Handling a message for long is not a stall.
+	d.Received(heartbeat)
+	time.Sleep(timeout * 2)
+	select {
+	case err := <-stalled:
+		t.Fatal("Stall detected while receiving messages: ", err)
+	default:
+	}
+	if err := d.Err(); err != nil {
+		t.Fatal("Err() = ", err)
+	}
+	d.Handled()
+
+	select {
+	case err := <-stalled:
+		if !strings.Contains(err.Error(), "pkg.Test running for 1m0s") {
+			t.Errorf("Stall error %q does not describe the last heartbeat", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Stall not detected")
+	}
+	if d.Err() == nil {
+		t.Error("Err() = nil after a stall")
+	}
+}
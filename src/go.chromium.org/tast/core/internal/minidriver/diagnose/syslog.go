@@ -0,0 +1,46 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package diagnose
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"go.chromium.org/tast/core/errors"
+	"go.chromium.org/tast/core/internal/logging"
+	"go.chromium.org/tast/core/internal/minidriver/target"
+)
+
+// syslogTimeFormat is the format of timestamps passed to croslog and
+// journalctl. Both commands interpret it in the local time zone, so they are
+// run with TZ=UTC.
+const syslogTimeFormat = "2006-01-02 15:04:05"
+
+// SyslogWindow saves unified system log entries recorded on the target
+// between since and until to dst.
+func SyslogWindow(ctx context.Context, cc *target.ConnCache, since, until time.Time, dst string) error {
+	if err := cc.EnsureConn(ctx, false, true); err != nil {
+		return err
+	}
+	conn := cc.Conn().SSHConn()
+
+	args := []string{
+		"--quiet",
+		"--no-pager",
+		"--since=" + since.UTC().Format(syslogTimeFormat),
+		// Round up so that entries in the last second are not lost.
+		"--until=" + until.UTC().Add(time.Second).Format(syslogTimeFormat),
+	}
+	out, err := conn.CommandContext(ctx, "env", append([]string{"TZ=UTC", "croslog"}, args...)...).Output()
+	if err != nil {
+		logging.Debug(ctx, "Failed to execute croslog command: ", err)
+		out, err = conn.CommandContext(ctx, "env", append([]string{"TZ=UTC", "journalctl"}, args...)...).Output()
+		if err != nil {
+			return errors.Wrap(err, "failed to read unified system log")
+		}
+	}
+	return os.WriteFile(dst, out, 0666)
+}
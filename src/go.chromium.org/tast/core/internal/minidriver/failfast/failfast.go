@@ -9,6 +9,10 @@ import (
 	"go.chromium.org/tast/core/errors"
 )
 
+// ErrTooManyFailures is wrapped by errors returned by Counter.Check when test
+// execution should be aborted.
+var ErrTooManyFailures = errors.New("too many test failures")
+
 // Counter counts test failures and aborts test execution if it passes a given
 // threshold.
 // nil is a valid Counter that never aborts test execution just as if it has
@@ -43,7 +47,7 @@ func (c *Counter) Check() error {
 		return nil
 	}
 	if c.fails >= c.threshold {
-		return errors.Errorf("aborting due to too many failures (%d)", c.fails)
+		return errors.Wrapf(ErrTooManyFailures, "aborting due to too many failures (%d)", c.fails)
 	}
 	return nil
 }
@@ -6,6 +6,7 @@ package processor
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"time"
 
@@ -13,13 +14,25 @@ import (
 	"go.chromium.org/tast/core/internal/logging"
 	"go.chromium.org/tast/core/internal/protocol"
 	"go.chromium.org/tast/core/internal/run/resultsjson"
+	"go.chromium.org/tast/core/internal/testing"
 )
 
 type entityInfo struct {
-	Entity             *protocol.Entity
+	Entity *protocol.Entity
+	// InstanceID identifies the entity run. It is unique among entities run
+	// by a processor, and is used to tell apart events of entities running
+	// concurrently.
+	InstanceID         int64
 	Start              time.Time
 	IntermediateOutDir string
 	FinalOutDir        string
+	// Quarantined is true if the entity is a test not run since it has been
+	// quarantined. Such a test has no events other than EntityStart, EntityEnd
+	// and EntityCopyEnd.
+	Quarantined bool
+	// Crashes is the number of times a quarantined test crashed the test
+	// bundle or rebooted the DUT.
+	Crashes int
 }
 
 type logEntry struct {
@@ -31,6 +44,9 @@ type logEntry struct {
 type errorEntry struct {
 	Time  time.Time
 	Error *protocol.Error
+	// FixturePhase is the phase of the fixture in which the error was
+	// reported. It is unspecified for errors of tests.
+	FixturePhase protocol.FixturePhase
 }
 
 type entityResult struct {
@@ -39,6 +55,11 @@ type entityResult struct {
 	Skip      *protocol.Skip
 	Errors    []*errorEntry
 	TimingLog *protocol.TimingLog
+	// Incomplete is true if the entity did not finish because the test bundle
+	// exited unexpectedly, e.g. due to a crash or a DUT reboot.
+	Incomplete bool
+	// ConsumedVars contains sorted names of runtime variables the entity read.
+	ConsumedVars []string
 }
 
 func newResult(ei *entityInfo, r *entityResult) (*resultsjson.Result, error) {
@@ -54,24 +75,102 @@ func newResult(ei *entityInfo, r *entityResult) (*resultsjson.Result, error) {
 	var es []resultsjson.Error
 	for _, e := range r.Errors {
 		es = append(es, resultsjson.Error{
-			Time:   e.Time,
-			Reason: e.Error.GetReason(),
-			File:   e.Error.GetLocation().GetFile(),
-			Line:   int(e.Error.GetLocation().GetLine()),
-			Stack:  e.Error.GetLocation().GetStack(),
+			Time:     e.Time,
+			Reason:   e.Error.GetReason(),
+			File:     e.Error.GetLocation().GetFile(),
+			Line:     int(e.Error.GetLocation().GetLine()),
+			Stack:    e.Error.GetLocation().GetStack(),
+			Category: resultsjson.NewErrorCategory(e.Error),
 		})
 	}
 
+	var warnings []string
+	if w := overrunWarning(test, r); w != "" {
+		warnings = append(warnings, w)
+	}
+
+	skipReason := strings.Join(r.Skip.GetReasons(), ", ")
+	if ei.Quarantined {
+		skipReason = fmt.Sprintf("Quarantined after %d crashes", ei.Crashes)
+	}
+
 	return &resultsjson.Result{
-		Test:       *test,
-		Errors:     es,
-		Start:      r.Start,
-		End:        r.End,
-		OutDir:     ei.FinalOutDir,
-		SkipReason: strings.Join(r.Skip.GetReasons(), ", "),
+		Test:                      *test,
+		Errors:                    es,
+		Start:                     r.Start,
+		End:                       r.End,
+		OutDir:                    ei.FinalOutDir,
+		SkipReason:                skipReason,
+		SkipReasons:               resultsjson.NewSkipReasons(r.Skip),
+		Warnings:                  warnings,
+		AvailableSoftSoftwareDeps: ei.Entity.GetLegacyData().GetAvailableSoftSoftwareDeps(),
+		Steps:                     newStepResults(ei.Entity.GetLegacyData().GetScenarioSteps(), r.Errors, es, r.TimingLog),
+		Seed:                      ei.Entity.GetLegacyData().GetSeed(),
+		ConsumedVars:              r.ConsumedVars,
+		RunMetadata:               protocol.StringPairsToMap(ei.Entity.GetLegacyData().GetRunMetadata()),
+		Quarantined:               ei.Quarantined,
 	}, nil
 }
 
+// newStepResults returns results of scenario steps named names. errs and
+// converted contain errors of the test before and after conversion to results
+// respectively. Errors reported in a step are marked with its name, and its
+// start and end times are recorded in a timing stage named with
+// testing.ScenarioStepStagePrefix.
+func newStepResults(names []string, errs []*errorEntry, converted []resultsjson.Error, timingLog *protocol.TimingLog) []resultsjson.StepResult {
+	if len(names) == 0 {
+		return nil
+	}
+	stages := make(map[string]*protocol.TimingStage)
+	var walk func(stage *protocol.TimingStage)
+	walk = func(stage *protocol.TimingStage) {
+		if name := strings.TrimPrefix(stage.GetName(), testing.ScenarioStepStagePrefix); name != stage.GetName() {
+			stages[name] = stage
+		}
+		for _, child := range stage.GetChildren() {
+			walk(child)
+		}
+	}
+	walk(timingLog.GetRoot())
+
+	var steps []resultsjson.StepResult
+	for _, name := range names {
+		step := resultsjson.StepResult{Name: name, Skipped: true}
+		if stage, ok := stages[name]; ok {
+			step.Start = stage.GetStartTime().AsTime()
+			if stage.GetEndTime() != nil {
+				step.End = stage.GetEndTime().AsTime()
+			}
+			step.Skipped = false
+		}
+		for i, e := range errs {
+			if e.Error.GetScenarioStep() == name {
+				step.Errors = append(step.Errors, converted[i])
+			}
+		}
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+// overrunFactor is the ratio of the actual duration of a test to its expected
+// duration above which a warning is reported.
+const overrunFactor = 2
+
+// overrunWarning returns a warning message if test took more than
+// overrunFactor times as long as its expected duration. Otherwise it returns
+// an empty string.
+func overrunWarning(test *resultsjson.Test, r *entityResult) string {
+	if test.ExpectedDuration <= 0 || r.Skip != nil || r.End.IsZero() {
+		return ""
+	}
+	d := r.End.Sub(r.Start)
+	if d <= overrunFactor*test.ExpectedDuration {
+		return ""
+	}
+	return fmt.Sprintf("Test took %v, more than %d times its expected duration %v", d.Round(time.Millisecond), overrunFactor, test.ExpectedDuration)
+}
+
 // fatalError is an error returned by handler when it saw a fatal error and the
 // caller should not retry test execution.
 type fatalError struct {
@@ -0,0 +1,58 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package processor
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"go.chromium.org/tast/core/internal/logging"
+	"go.chromium.org/tast/core/internal/protocol"
+)
+
+// FailureSyslogFileName is the name of the file in a test output directory
+// that receives system log entries recorded while a failed test was running.
+const FailureSyslogFileName = "syslog_window.txt"
+
+// fetchSyslogTimeout is the maximum time to spend on saving system log
+// entries of a failed test.
+const fetchSyslogTimeout = time.Minute
+
+// FetchSyslogFunc is a function that saves system log entries on the target
+// recorded between since and until to dst.
+type FetchSyslogFunc func(ctx context.Context, since, until time.Time, dst string) error
+
+// failureSyslogHandler saves system log entries spanning a failed test to
+// the test's output directory.
+type failureSyslogHandler struct {
+	baseHandler
+	preRoll time.Duration
+	fetch   FetchSyslogFunc
+}
+
+var _ Handler = &failureSyslogHandler{}
+
+// NewFailureSyslogHandler creates a handler which saves system log entries
+// recorded from preRoll before the start to the end of a failed test to its
+// output directory. If preRoll is negative, the handler does nothing.
+func NewFailureSyslogHandler(preRoll time.Duration, fetch FetchSyslogFunc) *failureSyslogHandler {
+	return &failureSyslogHandler{preRoll: preRoll, fetch: fetch}
+}
+
+func (h *failureSyslogHandler) EntityEnd(ctx context.Context, ei *entityInfo, r *entityResult) error {
+	if h.preRoll < 0 || ei.Entity.GetType() != protocol.EntityType_TEST || len(r.Errors) == 0 || ei.FinalOutDir == "" {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, fetchSyslogTimeout)
+	defer cancel()
+
+	dst := filepath.Join(ei.FinalOutDir, FailureSyslogFileName)
+	if err := h.fetch(ctx, r.Start.Add(-h.preRoll), r.End, dst); err != nil {
+		// The target may be unreachable after a crash. This is not an error.
+		logging.Infof(ctx, "Failed to save system log of %s: %v", ei.Entity.GetName(), err)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package processor_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go.chromium.org/tast/core/internal/logging"
+	"go.chromium.org/tast/core/internal/minidriver/processor"
+	"go.chromium.org/tast/core/internal/protocol"
+)
+
+func TestFailureSyslogHandler(t *testing.T) {
+	const preRoll = 30 * time.Second
+
+	type window struct {
+		Since, Until time.Time
+	}
+	var got []window
+	fetch := func(ctx context.Context, since, until time.Time, dst string) error {
+		got = append(got, window{since, until})
+		return os.WriteFile(dst, []byte("syslog"), 0666)
+	}
+
+	resDir := t.TempDir()
+	events := []protocol.Event{
+		&protocol.EntityStartEvent{Time: epochpb, Entity: &protocol.Entity{Name: "pkg.Pass"}},
+		&protocol.EntityEndEvent{Time: epochpb, EntityName: "pkg.Pass"},
+		&protocol.EntityStartEvent{Time: epochpb, Entity: &protocol.Entity{Name: "pkg.Fail"}},
+		&protocol.EntityErrorEvent{Time: epochpb, EntityName: "pkg.Fail", Error: &protocol.Error{Reason: "failed"}},
+		&protocol.EntityEndEvent{Time: epochpb, EntityName: "pkg.Fail"},
+	}
+	hs := append(newHandlers(resDir, logging.NewMultiLogger(), nopPull, nil, nil), processor.NewFailureSyslogHandler(preRoll, fetch))
+	proc := processor.New(resDir, nopDiagnose, hs, "cros")
+	runProcessor(context.Background(), proc, events, nil)
+
+	want := []window{{epoch.Add(-preRoll), epoch}}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Fetched windows mismatch (-got +want):\n%s", diff)
+	}
+	if _, err := os.Stat(filepath.Join(resDir, "tests", "pkg.Fail", processor.FailureSyslogFileName)); err != nil {
+		t.Error("System log of a failed test was not saved: ", err)
+	}
+	if _, err := os.Stat(filepath.Join(resDir, "tests", "pkg.Pass", processor.FailureSyslogFileName)); err == nil {
+		t.Error("System log of a passed test was saved unexpectedly")
+	}
+}
+
+func TestFailureSyslogHandlerDisabled(t *testing.T) {
+	fetch := func(ctx context.Context, since, until time.Time, dst string) error {
+		t.Error("fetch was called unexpectedly")
+		return nil
+	}
+
+	resDir := t.TempDir()
+	events := []protocol.Event{
+		&protocol.EntityStartEvent{Time: epochpb, Entity: &protocol.Entity{Name: "pkg.Fail"}},
+		&protocol.EntityErrorEvent{Time: epochpb, EntityName: "pkg.Fail", Error: &protocol.Error{Reason: "failed"}},
+		&protocol.EntityEndEvent{Time: epochpb, EntityName: "pkg.Fail"},
+	}
+	hs := append(newHandlers(resDir, logging.NewMultiLogger(), nopPull, nil, nil), processor.NewFailureSyslogHandler(-1, fetch))
+	proc := processor.New(resDir, nopDiagnose, hs, "cros")
+	runProcessor(context.Background(), proc, events, nil)
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package processor
+
+import (
+	"context"
+	"path/filepath"
+
+	"go.chromium.org/tast/core/internal/protocol"
+	"go.chromium.org/tast/core/internal/run/reporting"
+	"go.chromium.org/tast/core/internal/run/resultsjson"
+)
+
+// fixtureResultsHandler saves results of failed fixtures to a file.
+//
+// Errors of a fixture are also reported for tests depending on it, but this
+// handler reports them as the fixture's own results so that SetUp and
+// TearDown failures can be told apart from failures of the tests.
+type fixtureResultsHandler struct {
+	baseHandler
+	resDir string
+
+	writer *reporting.FixtureResultsWriter
+}
+
+var _ Handler = &fixtureResultsHandler{}
+
+// NewFixtureResultsHandler creates a handler which saves results of failed
+// fixtures to a file.
+func NewFixtureResultsHandler(resDir string) *fixtureResultsHandler {
+	return &fixtureResultsHandler{resDir: resDir}
+}
+
+func (h *fixtureResultsHandler) RunStart(ctx context.Context) error {
+	writer, err := reporting.NewFixtureResultsWriter(filepath.Join(h.resDir, reporting.FixtureResultsFilename))
+	if err != nil {
+		return err
+	}
+	h.writer = writer
+	return nil
+}
+
+func (h *fixtureResultsHandler) EntityEnd(ctx context.Context, ei *entityInfo, r *entityResult) error {
+	if ei.Entity.GetType() != protocol.EntityType_FIXTURE || len(r.Errors) == 0 {
+		return nil
+	}
+
+	var es []resultsjson.FixtureError
+	for _, e := range r.Errors {
+		es = append(es, resultsjson.FixtureError{
+			Error: resultsjson.Error{
+				Time:     e.Time,
+				Reason:   e.Error.GetReason(),
+				File:     e.Error.GetLocation().GetFile(),
+				Line:     int(e.Error.GetLocation().GetLine()),
+				Stack:    e.Error.GetLocation().GetStack(),
+				Category: resultsjson.NewErrorCategory(e.Error),
+			},
+			Phase: fixturePhaseName(e.FixturePhase),
+		})
+	}
+	return h.writer.Write(&resultsjson.FixtureResult{
+		Name:   ei.Entity.GetName(),
+		Errors: es,
+		Start:  r.Start,
+		End:    r.End,
+		OutDir: ei.FinalOutDir,
+	})
+}
+
+func (h *fixtureResultsHandler) RunEnd(ctx context.Context) {
+	h.writer.Close()
+	h.writer = nil
+}
+
+// fixturePhaseName returns the name of the fixture method called in phase.
+func fixturePhaseName(phase protocol.FixturePhase) string {
+	switch phase {
+	case protocol.FixturePhase_SET_UP:
+		return "SetUp"
+	case protocol.FixturePhase_TEAR_DOWN:
+		return "TearDown"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package processor_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go.chromium.org/tast/core/internal/logging"
+	"go.chromium.org/tast/core/internal/minidriver/processor"
+	"go.chromium.org/tast/core/internal/protocol"
+	"go.chromium.org/tast/core/internal/run/reporting"
+	"go.chromium.org/tast/core/internal/run/resultsjson"
+)
+
+func TestFixtureResultsHandler(t *testing.T) {
+	resDir := t.TempDir()
+
+	events := []protocol.Event{
+		// fixture1 fails to set up.
+		&protocol.EntityStartEvent{Time: epochpb, Entity: &protocol.Entity{Name: "fixture1", Type: protocol.EntityType_FIXTURE}},
+		&protocol.EntityErrorEvent{Time: epochpb, EntityName: "fixture1", Error: &protocol.Error{Reason: "SetUp failed", Location: &protocol.ErrorLocation{File: "fixture.go", Line: 12}}, FixturePhase: protocol.FixturePhase_SET_UP},
+		&protocol.EntityEndEvent{Time: epochpb, EntityName: "fixture1"},
+		&protocol.EntityStartEvent{Time: epochpb, Entity: &protocol.Entity{Name: "pkg.Test1"}},
+		&protocol.EntityErrorEvent{Time: epochpb, EntityName: "pkg.Test1", Error: &protocol.Error{Reason: "[Fixture failure] fixture1: SetUp failed"}},
+		&protocol.EntityEndEvent{Time: epochpb, EntityName: "pkg.Test1"},
+		// fixture2 succeeds.
+		&protocol.EntityStartEvent{Time: epochpb, Entity: &protocol.Entity{Name: "fixture2", Type: protocol.EntityType_FIXTURE}},
+		&protocol.EntityEndEvent{Time: epochpb, EntityName: "fixture2"},
+		// fixture3 fails to tear down.
+		&protocol.EntityStartEvent{Time: epochpb, Entity: &protocol.Entity{Name: "fixture3", Type: protocol.EntityType_FIXTURE}},
+		&protocol.EntityStartEvent{Time: epochpb, Entity: &protocol.Entity{Name: "pkg.Test2"}},
+		&protocol.EntityEndEvent{Time: epochpb, EntityName: "pkg.Test2"},
+		&protocol.EntityErrorEvent{Time: epochpb, EntityName: "fixture3", Error: &protocol.Error{Reason: "TearDown failed"}, FixturePhase: protocol.FixturePhase_TEAR_DOWN},
+		&protocol.EntityEndEvent{Time: epochpb, EntityName: "fixture3"},
+	}
+
+	hs := newHandlers(resDir, logging.NewMultiLogger(), nopPull, nil, nil)
+	proc := processor.New(resDir, nopDiagnose, hs, "cros")
+	runProcessor(context.Background(), proc, events, nil)
+
+	if err := proc.FatalError(); err != nil {
+		t.Errorf("Processor had a fatal error: %v", err)
+	}
+
+	b, err := os.ReadFile(filepath.Join(resDir, reporting.FixtureResultsFilename))
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", reporting.FixtureResultsFilename, err)
+	}
+
+	var got []*resultsjson.FixtureResult
+	decoder := json.NewDecoder(bytes.NewBuffer(b))
+	for decoder.More() {
+		var r resultsjson.FixtureResult
+		if err := decoder.Decode(&r); err != nil {
+			t.Fatalf("Failed to decode %s: %v", reporting.FixtureResultsFilename, err)
+		}
+		got = append(got, &r)
+	}
+
+	want := []*resultsjson.FixtureResult{
+		{
+			Name: "fixture1",
+			Errors: []resultsjson.FixtureError{{
+				Error: resultsjson.Error{Time: epoch, Reason: "SetUp failed", File: "fixture.go", Line: 12},
+				Phase: "SetUp",
+			}},
+			Start:  epoch,
+			End:    epoch,
+			OutDir: filepath.Join(resDir, "fixtures", "fixture1"),
+		},
+		{
+			Name: "fixture3",
+			Errors: []resultsjson.FixtureError{{
+				Error: resultsjson.Error{Time: epoch, Reason: "TearDown failed"},
+				Phase: "TearDown",
+			}},
+			Start:  epoch,
+			End:    epoch,
+			OutDir: filepath.Join(resDir, "fixtures", "fixture3"),
+		},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Fatalf("Results mismatch (-got +want):\n%s", diff)
+	}
+}
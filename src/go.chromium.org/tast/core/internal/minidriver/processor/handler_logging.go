@@ -11,6 +11,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"go.chromium.org/tast/core/internal/logging"
@@ -28,11 +29,17 @@ type loggingHandler struct {
 	client      *reporting.RPCClient
 
 	loggers []*entityLogger
+	// focus is the instance ID of the test whose event is being processed,
+	// or 0 if none. While it is set, logs are not saved to logs of other
+	// tests running concurrently. It is accessed atomically since logs can be
+	// emitted from other goroutines.
+	focus int64
 }
 
 type entityLogger struct {
-	Logger *logging.SinkLogger
-	File   *os.File
+	InstanceID int64
+	Logger     logging.Logger
+	File       *os.File
 }
 
 var _ Handler = &loggingHandler{}
@@ -70,12 +77,24 @@ func (h *loggingHandler) EntityStart(ctx context.Context, ei *entityInfo) error
 		writers = append(writers, h.client.NewTestLogWriter(ei.Entity.GetName(), relPath))
 	}
 
-	logger := &entityLogger{
-		Logger: logging.NewSinkLogger(logging.LevelDebug, true, logging.NewWriterSink(io.MultiWriter(writers...))),
-		File:   f,
+	var logger logging.Logger = logging.NewSinkLogger(logging.LevelDebug, true, logging.NewWriterSink(io.MultiWriter(writers...)))
+	if ei.Entity.GetType() == protocol.EntityType_TEST {
+		// Tests may run concurrently. Save logs only if they are not for
+		// another test.
+		sink := logger
+		logger = logging.NewFuncLogger(func(level logging.Level, ts time.Time, msg string) {
+			if focus := atomic.LoadInt64(&h.focus); focus == 0 || focus == ei.InstanceID {
+				sink.Log(level, ts, msg)
+			}
+		})
 	}
-	h.loggers = append(h.loggers, logger)
-	h.multiplexer.AddLogger(logger.Logger)
+	h.loggers = append(h.loggers, &entityLogger{
+		InstanceID: ei.InstanceID,
+		Logger:     logger,
+		File:       f,
+	})
+	h.multiplexer.AddLogger(logger)
+	defer h.focusOn(ei)()
 
 	logging.Debugf(ctx, "Started %s %s", entityTypeName(ei.Entity.GetType()), ei.Entity.GetName())
 	fmt.Printf("%v%v Started %s %s %v\n", timeStr, BLUE, entityTypeName(ei.Entity.GetType()), ei.Entity.GetName(), RESET)
@@ -83,6 +102,7 @@ func (h *loggingHandler) EntityStart(ctx context.Context, ei *entityInfo) error
 }
 
 func (h *loggingHandler) EntityLog(ctx context.Context, ei *entityInfo, l *logEntry) error {
+	defer h.focusOn(ei)()
 	switch l.Level {
 	case logging.LevelInfo:
 		logging.Infof(ctx, "[%s] %s", l.Time.Format(testOutputTimeFmt), l.Text)
@@ -95,6 +115,7 @@ func (h *loggingHandler) EntityLog(ctx context.Context, ei *entityInfo, l *logEn
 }
 
 func (h *loggingHandler) EntityError(ctx context.Context, ei *entityInfo, e *errorEntry) error {
+	defer h.focusOn(ei)()
 	ts := e.Time.Format(testOutputTimeFmt)
 	loc := e.Error.GetLocation()
 	if loc == nil {
@@ -113,6 +134,12 @@ func (h *loggingHandler) EntityEnd(ctx context.Context, ei *entityInfo, r *entit
 	const RESET = "\033[0m"
 	t := time.Now()
 	timeStr := t.UTC().Format("2006-01-02T15:04:05.000000Z")
+	defer h.focusOn(ei)()
+	if ei.Quarantined {
+		logging.Debugf(ctx, "Quarantined test %s after %d crashes", ei.Entity.GetName(), ei.Crashes)
+		fmt.Printf("%v%v Quarantined test %s%v after %d crashes\n", timeStr, BLUE, ei.Entity.GetName(), RESET, ei.Crashes)
+		return nil
+	}
 	if reasons := r.Skip.GetReasons(); len(reasons) > 0 {
 		logging.Debugf(ctx, "Skipped test %s due to missing dependencies: %s", ei.Entity.GetName(), strings.Join(reasons, ", "))
 		fmt.Printf("%v%v Skipped test %s%v due to missing dependencies: %s\n", timeStr, BLUE, ei.Entity.GetName(), RESET, strings.Join(reasons, ", "))
@@ -131,13 +158,29 @@ func (h *loggingHandler) EntityEnd(ctx context.Context, ei *entityInfo, r *entit
 		r.End.Sub(r.Start).Round(time.Millisecond),
 		len(r.Errors))
 
-	logger := h.loggers[len(h.loggers)-1]
-	h.multiplexer.RemoveLogger(logger.Logger)
-	logger.File.Close()
-	h.loggers = h.loggers[:len(h.loggers)-1]
+	for i := len(h.loggers) - 1; i >= 0; i-- {
+		logger := h.loggers[i]
+		if logger.InstanceID != ei.InstanceID {
+			continue
+		}
+		h.multiplexer.RemoveLogger(logger.Logger)
+		logger.File.Close()
+		h.loggers = append(h.loggers[:i], h.loggers[i+1:]...)
+		break
+	}
 	return nil
 }
 
+// focusOn makes logs saved only to logs of ei and entities it depends on
+// if ei is a test. It returns a function to restore the previous state.
+func (h *loggingHandler) focusOn(ei *entityInfo) func() {
+	if ei.Entity.GetType() != protocol.EntityType_TEST {
+		return func() {}
+	}
+	prev := atomic.SwapInt64(&h.focus, ei.InstanceID)
+	return func() { atomic.StoreInt64(&h.focus, prev) }
+}
+
 func (h *loggingHandler) RunLog(ctx context.Context, l *logEntry) error {
 	switch l.Level {
 	case logging.LevelInfo:
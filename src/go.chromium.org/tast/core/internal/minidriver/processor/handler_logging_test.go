@@ -150,6 +150,81 @@ Completed test pkg.Test2 in 0s with 0 error(s)
 	}
 }
 
+func TestLoggingHandler_ConcurrentTests(t *testing.T) {
+	resDir := t.TempDir()
+
+	events := []protocol.Event{
+		&protocol.EntityStartEvent{Time: epochpb, Entity: &protocol.Entity{Name: "fixture", Type: protocol.EntityType_FIXTURE}, InstanceId: 1},
+		&protocol.EntityStartEvent{Time: epochpb, Entity: &protocol.Entity{Name: "pkg.Test1"}, InstanceId: 2},
+		&protocol.EntityStartEvent{Time: epochpb, Entity: &protocol.Entity{Name: "pkg.Test2"}, InstanceId: 3},
+		&protocol.EntityLogEvent{Time: epochpb, EntityName: "pkg.Test1", Text: "This is a log from the first test", Level: protocol.LogLevel_INFO, InstanceId: 2},
+		&protocol.EntityLogEvent{Time: epochpb, EntityName: "pkg.Test2", Text: "This is a log from the second test", Level: protocol.LogLevel_INFO, InstanceId: 3},
+		&protocol.EntityErrorEvent{Time: epochpb, EntityName: "pkg.Test2", Error: &protocol.Error{Reason: "Failed"}, InstanceId: 3},
+		// The first test ends before the second test.
+		&protocol.EntityEndEvent{Time: epochpb, EntityName: "pkg.Test1", InstanceId: 2},
+		&protocol.EntityLogEvent{Time: epochpb, EntityName: "fixture", Text: "This is a log from the fixture", Level: protocol.LogLevel_INFO, InstanceId: 1},
+		&protocol.EntityEndEvent{Time: epochpb, EntityName: "pkg.Test2", InstanceId: 3},
+		&protocol.EntityEndEvent{Time: epochpb, EntityName: "fixture", InstanceId: 1},
+	}
+
+	multiplexer := logging.NewMultiLogger()
+	ctx := logging.AttachLogger(context.Background(), multiplexer)
+
+	hs := newHandlers(resDir, multiplexer, nopPull, nil, nil)
+	proc := processor.New(resDir, nopDiagnose, hs, "cros")
+	runProcessor(ctx, proc, events, nil)
+
+	if err := proc.FatalError(); err != nil {
+		t.Errorf("Processor had a fatal error: %v", err)
+	}
+
+	files, err := testutil.ReadFiles(resDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		path string
+		want string
+	}{
+		{
+			path: "fixtures/fixture/log.txt",
+			want: `Started fixture fixture
+Started test pkg.Test1
+Started test pkg.Test2
+[00:00:00.000] This is a log from the first test
+[00:00:00.000] This is a log from the second test
+[00:00:00.000] Error: Failed
+Completed test pkg.Test1 in 0s with 0 error(s)
+[00:00:00.000] This is a log from the fixture
+Completed test pkg.Test2 in 0s with 1 error(s)
+Completed fixture fixture in 0s with 0 error(s)
+`,
+		},
+		{
+			path: "tests/pkg.Test1/log.txt",
+			want: `Started test pkg.Test1
+[00:00:00.000] This is a log from the first test
+Completed test pkg.Test1 in 0s with 0 error(s)
+`,
+		},
+		{
+			path: "tests/pkg.Test2/log.txt",
+			want: `Started test pkg.Test2
+[00:00:00.000] This is a log from the second test
+[00:00:00.000] Error: Failed
+[00:00:00.000] This is a log from the fixture
+Completed test pkg.Test2 in 0s with 1 error(s)
+`,
+		},
+	} {
+		got := stripTimestamps(files[tc.path])
+		if diff := cmp.Diff(got, tc.want); diff != "" {
+			t.Errorf("%s mismatch (-got +want)\n:%s", tc.path, diff)
+		}
+	}
+}
+
 func TestLoggingHandler_RPCLogs(t *testing.T) {
 	resDir := t.TempDir()
 
@@ -11,6 +11,7 @@ import (
 	"sync"
 
 	"go.chromium.org/tast/core/internal/logging"
+	"go.chromium.org/tast/core/internal/timing"
 )
 
 // copyOutputHandler copies test output files to the host system when a test
@@ -43,8 +44,10 @@ func (h *copyOutputHandler) EntityCopyEnd(ctx context.Context, ei *entityInfo) e
 
 	// Pull finished test output files in a separate goroutine.
 	h.pullers.Add(1)
+	_, st := timing.Start(ctx, "pull_outputs:"+ei.Entity.GetName())
 	go func() {
 		defer h.pullers.Done()
+		defer st.End()
 		if err := moveTestOutputData(h.pull, ei.IntermediateOutDir, ei.FinalOutDir); err != nil {
 			// This may be written to a log of an irrelevant test.
 			logging.Infof(ctx, "Failed to copy output data of %s: %v", ei.Entity.GetName(), err)
@@ -41,12 +41,25 @@ func (h *passThroughHandler) EntityStart(ctx context.Context, ei *entityInfo) er
 	ts := timestamppb.New(ei.Start)
 	h.mu.Lock()
 	defer h.mu.Unlock()
+	if ei.Quarantined {
+		// Pass the quarantine itself rather than events synthesized from it.
+		return h.pass(&protocol.RunTestsResponse{
+			Type: &protocol.RunTestsResponse_EntityQuarantine{
+				EntityQuarantine: &protocol.EntityQuarantineEvent{
+					Time:    ts,
+					Entity:  ei.Entity,
+					Crashes: int32(ei.Crashes),
+				},
+			},
+		})
+	}
 	return h.pass(&protocol.RunTestsResponse{
 		Type: &protocol.RunTestsResponse_EntityStart{
 			EntityStart: &protocol.EntityStartEvent{
-				Time:   ts,
-				Entity: ei.Entity,
-				OutDir: ei.FinalOutDir,
+				Time:       ts,
+				Entity:     ei.Entity,
+				OutDir:     ei.FinalOutDir,
+				InstanceId: ei.InstanceID,
 			},
 		},
 	})
@@ -63,6 +76,7 @@ func (h *passThroughHandler) EntityLog(ctx context.Context, ei *entityInfo, l *l
 				EntityName: ei.Entity.GetName(),
 				Text:       l.Text,
 				Level:      protocol.LevelToProto(l.Level),
+				InstanceId: ei.InstanceID,
 			},
 		},
 	})
@@ -75,31 +89,41 @@ func (h *passThroughHandler) EntityError(ctx context.Context, ei *entityInfo, e
 	return h.pass(&protocol.RunTestsResponse{
 		Type: &protocol.RunTestsResponse_EntityError{
 			EntityError: &protocol.EntityErrorEvent{
-				Time:       ts,
-				EntityName: ei.Entity.GetName(),
-				Error:      e.Error,
+				Time:         ts,
+				EntityName:   ei.Entity.GetName(),
+				Error:        e.Error,
+				InstanceId:   ei.InstanceID,
+				FixturePhase: e.FixturePhase,
 			},
 		},
 	})
 }
 
 func (h *passThroughHandler) EntityEnd(ctx context.Context, ei *entityInfo, r *entityResult) error {
+	if ei.Quarantined {
+		return nil
+	}
 	ts := timestamppb.New(r.End)
 	h.mu.Lock()
 	defer h.mu.Unlock()
 	return h.pass(&protocol.RunTestsResponse{
 		Type: &protocol.RunTestsResponse_EntityEnd{
 			EntityEnd: &protocol.EntityEndEvent{
-				Time:       ts,
-				EntityName: ei.Entity.GetName(),
-				Skip:       r.Skip,
-				TimingLog:  r.TimingLog,
+				Time:         ts,
+				EntityName:   ei.Entity.GetName(),
+				Skip:         r.Skip,
+				TimingLog:    r.TimingLog,
+				InstanceId:   ei.InstanceID,
+				ConsumedVars: r.ConsumedVars,
 			},
 		},
 	})
 }
 
 func (h *passThroughHandler) EntityCopyEnd(ctx context.Context, ei *entityInfo) error {
+	if ei.Quarantined {
+		return nil
+	}
 	h.pullers.Add(1)
 	go func() {
 		// Pull finished test output files in a separate goroutine.
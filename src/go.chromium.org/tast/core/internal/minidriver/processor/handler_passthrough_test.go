@@ -0,0 +1,40 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package processor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go.chromium.org/tast/core/internal/minidriver/processor"
+	"go.chromium.org/tast/core/internal/protocol"
+	"go.chromium.org/tast/core/internal/protocol/protocoltest"
+)
+
+func TestPassThroughHandlerQuarantine(t *testing.T) {
+	resDir := t.TempDir()
+
+	var got []protocol.Event
+	pass := func(res *protocol.RunTestsResponse) error {
+		if ev, ok := protocoltest.ExtractEvent(res); ok {
+			got = append(got, ev)
+		}
+		return nil
+	}
+	hs := []processor.Handler{processor.NewCopyAndPassThroughHandler(pass, nopPull)}
+	proc := processor.New(resDir, nopDiagnose, hs, "cros")
+
+	// A quarantined test is passed through as is rather than as events
+	// synthesized from it.
+	quarantine := &protocol.EntityQuarantineEvent{Time: epochpb, Entity: &protocol.Entity{Name: "pkg.Crash"}, Crashes: 2}
+	runProcessor(context.Background(), proc, []protocol.Event{quarantine}, nil)
+
+	want := []protocol.Event{quarantine}
+	if diff := cmp.Diff(got, want, protocoltest.EventCmpOpts...); diff != "" {
+		t.Errorf("Passed events mismatch (-got +want):\n%s", diff)
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package processor
+
+import (
+	"context"
+
+	"go.chromium.org/tast/core/internal/logging"
+	"go.chromium.org/tast/core/internal/minidriver/progress"
+	"go.chromium.org/tast/core/internal/protocol"
+	"go.chromium.org/tast/core/internal/run/resultsjson"
+)
+
+// progressHandler records finished tests to a progress tracker and logs the
+// progress of the run. It also logs a warning when a test takes much longer
+// than its expected duration.
+type progressHandler struct {
+	baseHandler
+	tracker *progress.Tracker
+}
+
+var _ Handler = &progressHandler{}
+
+// NewProgressHandler creates a handler which records finished tests to
+// tracker and logs the estimated remaining time of the run.
+func NewProgressHandler(tracker *progress.Tracker) *progressHandler {
+	return &progressHandler{tracker: tracker}
+}
+
+func (h *progressHandler) EntityEnd(ctx context.Context, ei *entityInfo, r *entityResult) error {
+	if ei.Entity.GetType() != protocol.EntityType_TEST {
+		return nil
+	}
+	test, err := resultsjson.NewTest(ei.Entity)
+	if err != nil {
+		return err
+	}
+	if w := overrunWarning(test, r); w != "" {
+		logging.Infof(ctx, "Warning: %s: %s", test.Name, w)
+	}
+	if h.tracker == nil {
+		return nil
+	}
+	if r.Skip != nil || r.Incomplete || ei.Quarantined {
+		h.tracker.Skip(test.ExpectedDuration)
+	} else {
+		h.tracker.Finish(test.ExpectedDuration, r.End.Sub(r.Start))
+	}
+	logging.Infof(ctx, "Progress: %v", h.tracker.Status())
+	return nil
+}
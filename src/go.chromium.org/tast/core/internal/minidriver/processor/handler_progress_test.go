@@ -0,0 +1,62 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package processor_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go.chromium.org/tast/core/internal/logging"
+	"go.chromium.org/tast/core/internal/minidriver/processor"
+	"go.chromium.org/tast/core/internal/minidriver/progress"
+	"go.chromium.org/tast/core/internal/protocol"
+)
+
+func TestProgressHandler(t *testing.T) {
+	resDir := t.TempDir()
+	tracker := progress.NewTracker([]time.Duration{10 * time.Second, 10 * time.Second, 10 * time.Second})
+
+	newEntity := func(name string) *protocol.Entity {
+		return &protocol.Entity{
+			Name:       name,
+			LegacyData: &protocol.EntityLegacyData{ExpectedDuration: durationpb.New(10 * time.Second)},
+		}
+	}
+	at := func(d time.Duration) *timestamppb.Timestamp {
+		return timestamppb.New(epoch.Add(d))
+	}
+
+	// pkg.Fast finishes in time, pkg.Slow takes more than twice as long as
+	// expected, and pkg.Skipped is skipped.
+	events := []protocol.Event{
+		&protocol.EntityStartEvent{Time: at(0), Entity: newEntity("pkg.Fast")},
+		&protocol.EntityEndEvent{Time: at(15 * time.Second), EntityName: "pkg.Fast"},
+		&protocol.EntityStartEvent{Time: at(15 * time.Second), Entity: newEntity("pkg.Slow")},
+		&protocol.EntityEndEvent{Time: at(40 * time.Second), EntityName: "pkg.Slow"},
+		&protocol.EntityStartEvent{Time: at(40 * time.Second), Entity: newEntity("pkg.Skipped")},
+		&protocol.EntityEndEvent{Time: at(40 * time.Second), EntityName: "pkg.Skipped", Skip: &protocol.Skip{Reasons: []string{"missing deps"}}},
+	}
+	hs := append(newHandlers(resDir, logging.NewMultiLogger(), nopPull, nil, nil), processor.NewProgressHandler(tracker))
+	proc := processor.New(resDir, nopDiagnose, hs, "cros")
+	runProcessor(context.Background(), proc, events, nil)
+
+	if s := tracker.Status(); s.Done != 3 || s.Total != 3 {
+		t.Errorf("Status() = %+v; want 3/3", s)
+	}
+
+	warned := make(map[string]bool)
+	for _, r := range proc.Results() {
+		warned[r.Name] = len(r.Warnings) > 0
+	}
+	for name, want := range map[string]bool{"pkg.Fast": false, "pkg.Slow": true, "pkg.Skipped": false} {
+		if warned[name] != want {
+			t.Errorf("%s has warnings = %v; want %v", name, warned[name], want)
+		}
+	}
+}
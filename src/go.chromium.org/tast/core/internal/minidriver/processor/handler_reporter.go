@@ -0,0 +1,44 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package processor
+
+import (
+	"context"
+
+	"go.chromium.org/tast/core/internal/logging"
+	"go.chromium.org/tast/core/internal/protocol"
+	"go.chromium.org/tast/core/internal/run/reporting"
+)
+
+// reporterHandler passes test results to a reporting.Reporter.
+type reporterHandler struct {
+	baseHandler
+	reporter reporting.Reporter
+}
+
+var _ Handler = &reporterHandler{}
+
+// NewReporterHandler creates a handler which passes test results to reporter.
+// reporter can be nil, in which case the handler does nothing.
+func NewReporterHandler(reporter reporting.Reporter) *reporterHandler {
+	return &reporterHandler{reporter: reporter}
+}
+
+func (h *reporterHandler) EntityEnd(ctx context.Context, ei *entityInfo, r *entityResult) error {
+	if h.reporter == nil || ei.Entity.GetType() != protocol.EntityType_TEST {
+		return nil
+	}
+
+	result, err := newResult(ei, r)
+	if err != nil {
+		return err
+	}
+
+	// Failures of reporters should not affect test execution.
+	if err := h.reporter.TestFinished(ctx, result); err != nil {
+		logging.Infof(ctx, "Failed to report result of %s: %v", result.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package processor_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go.chromium.org/tast/core/internal/minidriver/processor"
+	"go.chromium.org/tast/core/internal/protocol"
+	"go.chromium.org/tast/core/internal/run/reporting"
+	"go.chromium.org/tast/core/internal/run/resultsjson"
+)
+
+// fakeReporter records names of finished tests and fails on each call.
+type fakeReporter struct {
+	reporting.BaseReporter
+	finished []string
+}
+
+func (r *fakeReporter) TestFinished(ctx context.Context, result *resultsjson.Result) error {
+	r.finished = append(r.finished, result.Name)
+	return errors.New("failure")
+}
+
+func TestReporterHandler(t *testing.T) {
+	resDir := t.TempDir()
+
+	events := []protocol.Event{
+		&protocol.EntityStartEvent{Time: epochpb, Entity: &protocol.Entity{Name: "fixture", Type: protocol.EntityType_FIXTURE}},
+		&protocol.EntityStartEvent{Time: epochpb, Entity: &protocol.Entity{Name: "pkg.Test1"}},
+		&protocol.EntityEndEvent{Time: epochpb, EntityName: "pkg.Test1"},
+		&protocol.EntityStartEvent{Time: epochpb, Entity: &protocol.Entity{Name: "pkg.Test2"}},
+		&protocol.EntityEndEvent{Time: epochpb, EntityName: "pkg.Test2"},
+		&protocol.EntityEndEvent{Time: epochpb, EntityName: "fixture"},
+	}
+
+	reporter := &fakeReporter{}
+	hs := []processor.Handler{
+		processor.NewReporterHandler(reporter),
+		processor.NewCopyOutputHandler(nopPull),
+	}
+	proc := processor.New(resDir, nopDiagnose, hs, "cros")
+	runProcessor(context.Background(), proc, events, nil)
+
+	// Errors of reporters should not be fatal.
+	if err := proc.FatalError(); err != nil {
+		t.Errorf("Processor had a fatal error: %v", err)
+	}
+	if diff := cmp.Diff(reporter.finished, []string{"pkg.Test1", "pkg.Test2"}); diff != "" {
+		t.Errorf("Finished tests mismatch (-got +want):\n%s", diff)
+	}
+	if got := len(proc.Results()); got != 2 {
+		t.Errorf("Got %d results; want 2", got)
+	}
+}
@@ -8,8 +8,10 @@ import (
 	"context"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"go.chromium.org/tast/core/internal/logging"
 	"go.chromium.org/tast/core/internal/minidriver/processor"
@@ -25,7 +27,7 @@ func TestResultsHandler(t *testing.T) {
 		&protocol.EntityStartEvent{Time: epochpb, Entity: &protocol.Entity{Name: "fixture", Type: protocol.EntityType_FIXTURE}},
 		// First test runs with 1 error.
 		&protocol.EntityStartEvent{Time: epochpb, Entity: &protocol.Entity{Name: "pkg.Test1", Description: "This is test 1"}},
-		&protocol.EntityErrorEvent{Time: epochpb, EntityName: "pkg.Test1", Error: &protocol.Error{Reason: "Failed", Location: &protocol.ErrorLocation{File: "file.go", Line: 123, Stack: "stacktrace"}}},
+		&protocol.EntityErrorEvent{Time: epochpb, EntityName: "pkg.Test1", Error: &protocol.Error{Reason: "Failed", Location: &protocol.ErrorLocation{File: "file.go", Line: 123, Stack: "stacktrace"}, Category: protocol.Error_PRODUCT}},
 		&protocol.EntityEndEvent{Time: epochpb, EntityName: "pkg.Test1"},
 		// Fixture reports an error.
 		&protocol.EntityErrorEvent{Time: epochpb, EntityName: "fixture", Error: &protocol.Error{Reason: "Failed", Location: &protocol.ErrorLocation{File: "fixture.go", Line: 456, Stack: "stacktrace"}}},
@@ -51,7 +53,7 @@ func TestResultsHandler(t *testing.T) {
 			End:    epoch,
 			OutDir: filepath.Join(resDir, "tests", "pkg.Test1"),
 			Errors: []resultsjson.Error{
-				{Time: epoch, Reason: "Failed", File: "file.go", Line: 123, Stack: "stacktrace"},
+				{Time: epoch, Reason: "Failed", File: "file.go", Line: 123, Stack: "stacktrace", Category: "PRODUCT"},
 			},
 		},
 		{
@@ -65,3 +67,160 @@ func TestResultsHandler(t *testing.T) {
 		t.Fatalf("Results mismatch (-got +want):\n%s", diff)
 	}
 }
+
+func TestResultsHandlerQuarantine(t *testing.T) {
+	resDir := t.TempDir()
+
+	events := []protocol.Event{
+		&protocol.EntityQuarantineEvent{Time: epochpb, Entity: &protocol.Entity{Name: "pkg.Crash"}, Crashes: 2},
+		&protocol.EntityStartEvent{Time: epochpb, Entity: &protocol.Entity{Name: "pkg.Pass"}},
+		&protocol.EntityEndEvent{Time: epochpb, EntityName: "pkg.Pass"},
+	}
+	hs := newHandlers(resDir, logging.NewMultiLogger(), nopPull, nil, nil)
+	proc := processor.New(resDir, nopDiagnose, hs, "cros")
+	runProcessor(context.Background(), proc, events, nil)
+
+	if err := proc.FatalError(); err != nil {
+		t.Errorf("Processor had a fatal error: %v", err)
+	}
+
+	got := proc.Results()
+	want := []*resultsjson.Result{
+		{
+			Test:        resultsjson.Test{Name: "pkg.Crash"},
+			Start:       epoch,
+			End:         epoch,
+			OutDir:      filepath.Join(resDir, "tests", "pkg.Crash"),
+			SkipReason:  "Quarantined after 2 crashes",
+			Quarantined: true,
+		},
+		{
+			Test:   resultsjson.Test{Name: "pkg.Pass"},
+			Start:  epoch,
+			End:    epoch,
+			OutDir: filepath.Join(resDir, "tests", "pkg.Pass"),
+		},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Fatalf("Results mismatch (-got +want):\n%s", diff)
+	}
+}
+
+func TestResultsHandlerScenarioSteps(t *testing.T) {
+	resDir := t.TempDir()
+
+	time1 := epoch.Add(time.Second)
+	time2 := epoch.Add(2 * time.Second)
+	// Steps are run in a stage of the test, which also has a stage named the
+	// same as a step.
+	timingLog := &protocol.TimingLog{Root: &protocol.TimingStage{Children: []*protocol.TimingStage{
+		{Name: "verify", StartTime: epochpb, EndTime: epochpb},
+		{Name: "flow", StartTime: epochpb, EndTime: timestamppb.New(time2), Children: []*protocol.TimingStage{
+			{Name: "scenario step: enroll", StartTime: epochpb, EndTime: timestamppb.New(time1)},
+			{Name: "scenario step: configure", StartTime: timestamppb.New(time1), EndTime: timestamppb.New(time2)},
+		}},
+	}}}
+
+	events := []protocol.Event{
+		&protocol.EntityStartEvent{Time: epochpb, Entity: &protocol.Entity{
+			Name:       "pkg.Scenario",
+			LegacyData: &protocol.EntityLegacyData{ScenarioSteps: []string{"enroll", "configure", "verify"}},
+		}},
+		// An error reported outside steps is not attributed to a step even if
+		// its reason looks like one.
+		&protocol.EntityErrorEvent{Time: epochpb, EntityName: "pkg.Scenario", Error: &protocol.Error{Reason: "enroll: Unrelated"}},
+		&protocol.EntityErrorEvent{Time: epochpb, EntityName: "pkg.Scenario", Error: &protocol.Error{Reason: "configure: Failed", ScenarioStep: "configure"}},
+		&protocol.EntityEndEvent{Time: timestamppb.New(time2), EntityName: "pkg.Scenario", TimingLog: timingLog},
+	}
+	hs := newHandlers(resDir, logging.NewMultiLogger(), nopPull, nil, nil)
+	proc := processor.New(resDir, nopDiagnose, hs, "cros")
+	runProcessor(context.Background(), proc, events, nil)
+
+	if err := proc.FatalError(); err != nil {
+		t.Errorf("Processor had a fatal error: %v", err)
+	}
+
+	otherErr := resultsjson.Error{Time: epoch, Reason: "enroll: Unrelated"}
+	stepErr := resultsjson.Error{Time: epoch, Reason: "configure: Failed"}
+	got := proc.Results()
+	want := []*resultsjson.Result{{
+		Test:   resultsjson.Test{Name: "pkg.Scenario"},
+		Start:  epoch,
+		End:    time2,
+		OutDir: filepath.Join(resDir, "tests", "pkg.Scenario"),
+		Errors: []resultsjson.Error{otherErr, stepErr},
+		Steps: []resultsjson.StepResult{
+			{Name: "enroll", Start: epoch, End: time1},
+			{Name: "configure", Start: time1, End: time2, Errors: []resultsjson.Error{stepErr}},
+			{Name: "verify", Skipped: true},
+		},
+	}}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Fatalf("Results mismatch (-got +want):\n%s", diff)
+	}
+}
+
+func TestResultsHandlerSeed(t *testing.T) {
+	resDir := t.TempDir()
+
+	events := []protocol.Event{
+		&protocol.EntityStartEvent{Time: epochpb, Entity: &protocol.Entity{
+			Name:       "pkg.Test",
+			LegacyData: &protocol.EntityLegacyData{Seed: 12345},
+		}},
+		&protocol.EntityEndEvent{Time: epochpb, EntityName: "pkg.Test"},
+	}
+	hs := newHandlers(resDir, logging.NewMultiLogger(), nopPull, nil, nil)
+	proc := processor.New(resDir, nopDiagnose, hs, "cros")
+	runProcessor(context.Background(), proc, events, nil)
+
+	if err := proc.FatalError(); err != nil {
+		t.Errorf("Processor had a fatal error: %v", err)
+	}
+
+	got := proc.Results()
+	want := []*resultsjson.Result{{
+		Test:   resultsjson.Test{Name: "pkg.Test"},
+		Start:  epoch,
+		End:    epoch,
+		OutDir: filepath.Join(resDir, "tests", "pkg.Test"),
+		Seed:   12345,
+	}}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Fatalf("Results mismatch (-got +want):\n%s", diff)
+	}
+}
+
+func TestResultsHandlerRunMetadata(t *testing.T) {
+	resDir := t.TempDir()
+
+	events := []protocol.Event{
+		&protocol.EntityStartEvent{Time: epochpb, Entity: &protocol.Entity{
+			Name: "pkg.Test",
+			LegacyData: &protocol.EntityLegacyData{RunMetadata: []*protocol.StringPair{
+				{Key: "builder", Value: "amd64-generic-cq"},
+				{Key: "cl", Value: "crrev.com/c/123"},
+			}},
+		}},
+		&protocol.EntityEndEvent{Time: epochpb, EntityName: "pkg.Test"},
+	}
+	hs := newHandlers(resDir, logging.NewMultiLogger(), nopPull, nil, nil)
+	proc := processor.New(resDir, nopDiagnose, hs, "cros")
+	runProcessor(context.Background(), proc, events, nil)
+
+	if err := proc.FatalError(); err != nil {
+		t.Errorf("Processor had a fatal error: %v", err)
+	}
+
+	got := proc.Results()
+	want := []*resultsjson.Result{{
+		Test:        resultsjson.Test{Name: "pkg.Test"},
+		Start:       epoch,
+		End:         epoch,
+		OutDir:      filepath.Join(resDir, "tests", "pkg.Test"),
+		RunMetadata: map[string]string{"builder": "amd64-generic-cq", "cl": "crrev.com/c/123"},
+	}}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Fatalf("Results mismatch (-got +want):\n%s", diff)
+	}
+}
@@ -27,7 +27,7 @@ func TestRPCResultsHandler_Results(t *testing.T) {
 		// Fixture starts.
 		&protocol.EntityStartEvent{Time: epochpb, Entity: &protocol.Entity{Name: "fixture", Type: protocol.EntityType_FIXTURE}},
 		// First test runs with 1 error.
-		&protocol.EntityStartEvent{Time: epochpb, Entity: &protocol.Entity{Name: "pkg.Test1", Description: "This is test 1"}},
+		&protocol.EntityStartEvent{Time: epochpb, Entity: &protocol.Entity{Name: "pkg.Test1", Description: "This is test 1", Requirements: []string{"req-1", "req-2"}}},
 		&protocol.EntityErrorEvent{Time: epochpb, EntityName: "pkg.Test1", Error: &protocol.Error{Reason: "Failed", Location: &protocol.ErrorLocation{File: "file.go", Line: 123, Stack: "stacktrace"}}},
 		&protocol.EntityEndEvent{Time: epochpb, EntityName: "pkg.Test1"},
 		// Fixture reports an error.
@@ -66,6 +66,7 @@ func TestRPCResultsHandler_Results(t *testing.T) {
 				Line:   123,
 				Stack:  "stacktrace",
 			}},
+			Requirements: []string{"req-1", "req-2"},
 		},
 		{
 			Test: "pkg.Test2",
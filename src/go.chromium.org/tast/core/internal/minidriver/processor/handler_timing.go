@@ -7,7 +7,6 @@ package processor
 import (
 	"context"
 
-	"go.chromium.org/tast/core/errors"
 	"go.chromium.org/tast/core/internal/logging"
 	"go.chromium.org/tast/core/internal/protocol"
 	"go.chromium.org/tast/core/internal/timing"
@@ -16,7 +15,9 @@ import (
 // timingHandler records timing information via context.Context.
 type timingHandler struct {
 	baseHandler
-	stage *timing.Stage
+	// stages maps instance IDs of running tests and fixtures to their timing
+	// stages.
+	stages map[int64]*timing.Stage
 }
 
 var _ Handler = &timingHandler{}
@@ -24,30 +25,30 @@ var _ Handler = &timingHandler{}
 // NewTimingHandler creates a handler which records timing information via
 // context.Context.
 func NewTimingHandler() *timingHandler {
-	return &timingHandler{}
+	return &timingHandler{stages: make(map[int64]*timing.Stage)}
 }
 
 func (h *timingHandler) EntityStart(ctx context.Context, ei *entityInfo) error {
-	if ei.Entity.GetType() != protocol.EntityType_TEST {
-		return nil
-	}
-	if h.stage != nil {
-		return errors.New("two tests started concurrently")
+	switch ei.Entity.GetType() {
+	case protocol.EntityType_TEST:
+		_, h.stages[ei.InstanceID] = timing.Start(ctx, ei.Entity.GetName())
+	case protocol.EntityType_FIXTURE:
+		_, h.stages[ei.InstanceID] = timing.Start(ctx, "fixture:"+ei.Entity.GetName())
 	}
-	_, h.stage = timing.Start(ctx, ei.Entity.GetName())
 	return nil
 }
 
 func (h *timingHandler) EntityEnd(ctx context.Context, ei *entityInfo, r *entityResult) error {
-	if h.stage == nil {
+	stage := h.stages[ei.InstanceID]
+	delete(h.stages, ei.InstanceID)
+	if stage == nil {
 		return nil
 	}
 	if log, err := timing.LogFromProto(r.TimingLog); err != nil {
 		logging.Infof(ctx, "Failed importing timing log for %v: %v", ei.Entity.GetName(), err)
-	} else if err := h.stage.Import(log); err != nil {
+	} else if err := stage.Import(log); err != nil {
 		logging.Infof(ctx, "Failed importing timing log for %v: %v", ei.Entity.GetName(), err)
 	}
-	h.stage.End()
-	h.stage = nil
+	stage.End()
 	return nil
 }
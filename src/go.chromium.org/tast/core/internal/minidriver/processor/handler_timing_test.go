@@ -58,13 +58,19 @@ func TestTimingHandler(t *testing.T) {
 	}
 
 	want := &protocol.TimingLog{Root: &protocol.TimingStage{Children: []*protocol.TimingStage{
+		{
+			Name: "fixture:fixture",
+			Children: []*protocol.TimingStage{
+				{Name: "SetUp"},
+				{Name: "TearDown"},
+			},
+		},
 		{
 			Name: "test",
 			Children: []*protocol.TimingStage{
 				{Name: "login"},
 			},
 		},
-		// Fixture's timing info is currently not recorded.
 	}}}
 	if diff := cmp.Diff(got, want, protocmp.Transform(), protocmp.IgnoreFields(&protocol.TimingStage{}, "start_time", "end_time")); diff != "" {
 		t.Errorf("Timing logs mismatch (-got +want):\n%s", diff)
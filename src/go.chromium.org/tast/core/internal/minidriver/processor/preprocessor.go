@@ -24,20 +24,38 @@ import (
 
 // entityState is used by preprocessor to track the state of a single entity.
 type entityState struct {
-	Entity             *protocol.Entity
+	Entity *protocol.Entity
+	// InstanceID identifies the entity run in the processor. It is unique
+	// among entities run by the processor.
+	InstanceID int64
+	// BundleInstanceID is the instance ID given by the test bundle, or 0 if
+	// the test bundle does not assign instance IDs.
+	BundleInstanceID   int64
 	Start              time.Time
 	IntermediateOutDir string
 	FinalOutDir        string
 
 	Errors []*errorEntry
+	// Incomplete is set when the entity is finished by RunEnd rather than by
+	// an EntityEnd event from the test bundle.
+	Incomplete bool
+	// Quarantined is set when the entity is a test not run since it has been
+	// quarantined.
+	Quarantined bool
+	// Crashes is the number of times a quarantined test crashed the test
+	// bundle or rebooted the DUT.
+	Crashes int
 }
 
 func (s *entityState) EntityInfo() *entityInfo {
 	return &entityInfo{
 		Entity:             s.Entity,
+		InstanceID:         s.InstanceID,
 		Start:              s.Start,
 		IntermediateOutDir: s.IntermediateOutDir,
 		FinalOutDir:        s.FinalOutDir,
+		Quarantined:        s.Quarantined,
+		Crashes:            s.Crashes,
 	}
 }
 
@@ -49,6 +67,7 @@ type preprocessor struct {
 	handlers []Handler
 
 	stack      []*entityState
+	lastID     int64
 	copying    map[string]*entityState
 	seenTimes  map[string]int
 	fatalError *fatalError
@@ -81,6 +100,12 @@ func (p *preprocessor) RunStart(ctx context.Context) error {
 }
 
 func (p *preprocessor) EntityStart(ctx context.Context, ev *protocol.EntityStartEvent) error {
+	return p.entityStart(ctx, ev, nil)
+}
+
+// entityStart starts an entity. q is non-nil if the entity is a test not run
+// since it has been quarantined.
+func (p *preprocessor) entityStart(ctx context.Context, ev *protocol.EntityStartEvent, q *protocol.EntityQuarantineEvent) error {
 	outDir, err := p.createOutDir(ev.GetEntity())
 	if err != nil {
 		return errors.Wrapf(err, "processing EntityStart: failed to create an output directory for %s", ev.GetEntity().GetName())
@@ -91,11 +116,16 @@ func (p *preprocessor) EntityStart(ctx context.Context, ev *protocol.EntityStart
 		return errors.Wrap(err, "processing EntityStart")
 	}
 	ts := ev.GetTime().AsTime()
+	p.lastID++
 	state := &entityState{
 		Entity:             ev.GetEntity(),
+		InstanceID:         p.lastID,
+		BundleInstanceID:   ev.GetInstanceId(),
 		Start:              ts,
 		IntermediateOutDir: ev.GetOutDir(),
 		FinalOutDir:        outDir,
+		Quarantined:        q != nil,
+		Crashes:            int(q.GetCrashes()),
 	}
 	p.stack = append(p.stack, state)
 	ei := state.EntityInfo()
@@ -110,7 +140,7 @@ func (p *preprocessor) EntityStart(ctx context.Context, ev *protocol.EntityStart
 }
 
 func (p *preprocessor) EntityLog(ctx context.Context, ev *protocol.EntityLogEvent) error {
-	state, err := p.stateOf(ev.GetEntityName())
+	state, err := p.stateOf(ev.GetEntityName(), ev.GetInstanceId())
 	if err != nil {
 		// Address b/389879153: We have seen something we will get EntityLog request from
 		// previous test after connection to DUT is reestablished. Since this function
@@ -133,7 +163,7 @@ func (p *preprocessor) EntityLog(ctx context.Context, ev *protocol.EntityLogEven
 }
 
 func (p *preprocessor) EntityError(ctx context.Context, ev *protocol.EntityErrorEvent) error {
-	state, err := p.stateOf(ev.GetEntityName())
+	state, err := p.stateOf(ev.GetEntityName(), ev.GetInstanceId())
 	if err != nil {
 		return errors.Wrap(err, "processing EntityError")
 	}
@@ -143,7 +173,7 @@ func (p *preprocessor) EntityError(ctx context.Context, ev *protocol.EntityError
 		return errors.Wrap(err, "processing EntityError")
 	}
 	ts := ev.GetTime().AsTime()
-	e := &errorEntry{Time: ts, Error: ev.GetError()}
+	e := &errorEntry{Time: ts, Error: ev.GetError(), FixturePhase: ev.GetFixturePhase()}
 	state.Errors = append(state.Errors, e)
 
 	ei := state.EntityInfo()
@@ -158,15 +188,13 @@ func (p *preprocessor) EntityError(ctx context.Context, ev *protocol.EntityError
 }
 
 func (p *preprocessor) EntityEnd(ctx context.Context, ev *protocol.EntityEndEvent) error {
-	state, err := p.stateOf(ev.GetEntityName())
+	state, err := p.stateOf(ev.GetEntityName(), ev.GetInstanceId())
 	if err != nil {
 		return errors.Wrap(err, "processing EntityEnd")
 	}
-	if stateTop := p.stateTop(); state != stateTop {
-		return errors.Errorf("unexpected EntityEnd: got %q, want %q", state.Entity.GetName(), stateTop.Entity.GetName())
+	if err := p.remove(state); err != nil {
+		return errors.Wrap(err, "processing EntityEnd")
 	}
-
-	p.stack = p.stack[:len(p.stack)-1]
 	p.copying[ev.GetEntityName()] = state
 
 	err = ev.GetTime().CheckValid()
@@ -176,11 +204,13 @@ func (p *preprocessor) EntityEnd(ctx context.Context, ev *protocol.EntityEndEven
 	ts := ev.GetTime().AsTime()
 	ei := state.EntityInfo()
 	result := &entityResult{
-		Start:     state.Start,
-		End:       ts,
-		Skip:      ev.GetSkip(),
-		Errors:    state.Errors,
-		TimingLog: ev.GetTimingLog(),
+		Start:        state.Start,
+		End:          ts,
+		Skip:         ev.GetSkip(),
+		Errors:       state.Errors,
+		TimingLog:    ev.GetTimingLog(),
+		Incomplete:   state.Incomplete,
+		ConsumedVars: ev.GetConsumedVars(),
 	}
 
 	var firstErr error
@@ -192,6 +222,19 @@ func (p *preprocessor) EntityEnd(ctx context.Context, ev *protocol.EntityEndEven
 	return firstErr
 }
 
+// EntityQuarantine handles a test not run since it has been quarantined as if
+// it started and finished immediately.
+func (p *preprocessor) EntityQuarantine(ctx context.Context, ev *protocol.EntityQuarantineEvent) error {
+	name := ev.GetEntity().GetName()
+	if err := p.entityStart(ctx, &protocol.EntityStartEvent{Time: ev.GetTime(), Entity: ev.GetEntity()}, ev); err != nil {
+		return err
+	}
+	if err := p.EntityEnd(ctx, &protocol.EntityEndEvent{Time: ev.GetTime(), EntityName: name}); err != nil {
+		return err
+	}
+	return p.EntityCopyEnd(ctx, &protocol.EntityCopyEndEvent{EntityName: name})
+}
+
 func (p *preprocessor) EntityCopyEnd(ctx context.Context, ev *protocol.EntityCopyEndEvent) error {
 	state, ok := p.copying[ev.GetEntityName()]
 	if !ok {
@@ -269,6 +312,7 @@ func (p *preprocessor) RunEnd(ctx context.Context, runErr error) {
 				Time:       timestamppb.Now(),
 				EntityName: stateTop.Entity.GetName(),
 				Error:      &protocol.Error{Reason: msg},
+				InstanceId: stateTop.BundleInstanceID,
 			})
 		}
 	}
@@ -278,16 +322,19 @@ func (p *preprocessor) RunEnd(ctx context.Context, runErr error) {
 	// from the stack.
 	for len(p.stack) > 0 {
 		stateTop := p.stateTop()
+		stateTop.Incomplete = true
 		if err := p.EntityError(ctx, &protocol.EntityErrorEvent{
 			Time:       timestamppb.Now(),
 			EntityName: stateTop.Entity.GetName(),
 			Error:      &protocol.Error{Reason: "Test did not finish"},
+			InstanceId: stateTop.BundleInstanceID,
 		}); err != nil && runErr == nil {
 			runErr = err
 		}
 		if err := p.EntityEnd(ctx, &protocol.EntityEndEvent{
 			Time:       timestamppb.Now(),
 			EntityName: stateTop.Entity.GetName(),
+			InstanceId: stateTop.BundleInstanceID,
 		}); err != nil && runErr == nil {
 			runErr = err
 		}
@@ -329,16 +376,37 @@ func (p *preprocessor) stateTop() *entityState {
 	return p.stack[len(p.stack)-1]
 }
 
-// stateOf returns entityState of a named running entity.
-func (p *preprocessor) stateOf(name string) (*entityState, error) {
+// stateOf returns entityState of a named running entity. If id is non-zero,
+// the entity must also have the instance ID given by the test bundle.
+func (p *preprocessor) stateOf(name string, id int64) (*entityState, error) {
 	for _, s := range p.stack {
-		if s.Entity.GetName() == name {
+		if s.Entity.GetName() == name && (id == 0 || s.BundleInstanceID == id) {
 			return s, nil
 		}
 	}
 	return nil, errors.Errorf("no such entity running: %s", name)
 }
 
+// remove removes state from the stack of running entities.
+//
+// Usually the state must be at the top of the stack. A test may end before
+// tests started after it since tests can run concurrently, but a fixture
+// never ends before entities depending on it.
+func (p *preprocessor) remove(state *entityState) error {
+	for i := len(p.stack) - 1; i >= 0; i-- {
+		s := p.stack[i]
+		if s == state {
+			p.stack = append(p.stack[:i], p.stack[i+1:]...)
+			return nil
+		}
+		if s.Entity.GetType() != protocol.EntityType_TEST || state.Entity.GetType() != protocol.EntityType_TEST {
+			break
+		}
+	}
+	stateTop := p.stateTop()
+	return errors.Errorf("unexpected EntityEnd: got %q, want %q", state.Entity.GetName(), stateTop.Entity.GetName())
+}
+
 // createOutDir creates an output directory for e, taking care of
 // duplicated paths.
 func (p *preprocessor) createOutDir(e *protocol.Entity) (string, error) {
@@ -195,3 +195,68 @@ func TestPreprocessor_TimeoutReached(t *testing.T) {
 		t.Errorf("Log doesn't contain an expected message: got %q, want %q", got, want)
 	}
 }
+
+// TestPreprocessor_ConcurrentTests checks that preprocessor attributes events
+// of tests running concurrently by their instance IDs.
+func TestPreprocessor_ConcurrentTests(t *testing.T) {
+	resDir := t.TempDir()
+
+	events := []protocol.Event{
+		&protocol.EntityStartEvent{Time: epochpb, Entity: &protocol.Entity{Name: "test1"}, InstanceId: 1},
+		&protocol.EntityStartEvent{Time: epochpb, Entity: &protocol.Entity{Name: "test2"}, InstanceId: 2},
+		&protocol.EntityErrorEvent{Time: epochpb, EntityName: "test1", Error: &protocol.Error{Reason: "error1"}, InstanceId: 1},
+		&protocol.EntityEndEvent{Time: epochpb, EntityName: "test1", InstanceId: 1},
+		&protocol.EntityErrorEvent{Time: epochpb, EntityName: "test2", Error: &protocol.Error{Reason: "error2"}, InstanceId: 2},
+		&protocol.EntityEndEvent{Time: epochpb, EntityName: "test2", InstanceId: 2},
+	}
+
+	hs := newHandlers(resDir, logging.NewMultiLogger(), nopPull, nil, nil)
+	proc := processor.New(resDir, nopDiagnose, hs, "cros")
+	runProcessor(context.Background(), proc, events, nil)
+
+	if err := proc.FatalError(); err != nil {
+		t.Errorf("Processor had a fatal error: %v", err)
+	}
+
+	got := proc.Results()
+	want := []*resultsjson.Result{
+		{
+			Test:   resultsjson.Test{Name: "test1"},
+			OutDir: filepath.Join(resDir, "tests", "test1"),
+			Errors: []resultsjson.Error{{Reason: "error1"}},
+		},
+		{
+			Test:   resultsjson.Test{Name: "test2"},
+			OutDir: filepath.Join(resDir, "tests", "test2"),
+			Errors: []resultsjson.Error{{Reason: "error2"}},
+		},
+	}
+	resultCmpOpts := []cmp.Option{
+		cmpopts.IgnoreFields(resultsjson.Result{}, "Start", "End"),
+		cmpopts.IgnoreFields(resultsjson.Error{}, "Time"),
+	}
+	if diff := cmp.Diff(got, want, resultCmpOpts...); diff != "" {
+		t.Fatalf("Results mismatch (-got +want):\n%s", diff)
+	}
+}
+
+// TestPreprocessor_FixtureEndsEarly checks that a fixture cannot end before
+// tests depending on it.
+func TestPreprocessor_FixtureEndsEarly(t *testing.T) {
+	resDir := t.TempDir()
+
+	events := []protocol.Event{
+		&protocol.EntityStartEvent{Time: epochpb, Entity: &protocol.Entity{Name: "fixture", Type: protocol.EntityType_FIXTURE}, InstanceId: 1},
+		&protocol.EntityStartEvent{Time: epochpb, Entity: &protocol.Entity{Name: "test"}, InstanceId: 2},
+		&protocol.EntityEndEvent{Time: epochpb, EntityName: "fixture", InstanceId: 1},
+	}
+
+	hs := newHandlers(resDir, logging.NewMultiLogger(), nopPull, nil, nil)
+	proc := processor.New(resDir, nopDiagnose, hs, "cros")
+	runProcessor(context.Background(), proc, events, nil)
+
+	got := proc.Results()
+	if len(got) != 1 || len(got[0].Errors) == 0 || !strings.Contains(got[0].Errors[0].Reason, "unexpected EntityEnd") {
+		t.Errorf("Results do not contain an error for unexpected EntityEnd: %+v", got)
+	}
+}
@@ -44,6 +44,8 @@ func runProcessor(ctx context.Context, proc *processor.Processor, events []proto
 				err = proc.EntityEnd(ctx, ev)
 			case *protocol.EntityCopyEndEvent:
 				err = proc.EntityCopyEnd(ctx, ev)
+			case *protocol.EntityQuarantineEvent:
+				err = proc.EntityQuarantine(ctx, ev)
 			case *protocol.RunLogEvent:
 				err = proc.RunLog(ctx, ev)
 			}
@@ -74,6 +76,7 @@ func newHandlers(resDir string, multiplexer *logging.MultiLogger, pull processor
 		processor.NewLoggingHandler(resDir, multiplexer, client),
 		processor.NewTimingHandler(),
 		processor.NewStreamedResultsHandler(resDir),
+		processor.NewFixtureResultsHandler(resDir),
 		processor.NewRPCResultsHandler(client),
 		processor.NewFailFastHandler(counter),
 		// copyOutputHandler should come last as it can block RunEnd for a while.
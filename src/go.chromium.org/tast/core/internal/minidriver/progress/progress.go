@@ -0,0 +1,176 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package progress provides a utility to track the progress of a run and to
+// estimate its remaining time from expected and actual durations of tests.
+package progress
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// barWidth is the number of characters in a progress bar.
+const barWidth = 30
+
+// Tracker tracks the number of finished tests and estimates the remaining time
+// of a run.
+// nil is a valid Tracker that tracks nothing.
+type Tracker struct {
+	mu    sync.Mutex
+	total int
+	done  int
+
+	// remainingExpected is the sum of expected durations of tests not
+	// finished yet.
+	remainingExpected time.Duration
+	// remainingUnknown is the number of tests not finished yet whose expected
+	// durations are unknown.
+	remainingUnknown int
+
+	// finishedExpected and finishedActual are the sums of expected and actual
+	// durations of finished tests whose expected durations are known.
+	finishedExpected time.Duration
+	finishedActual   time.Duration
+	// unknownActual and unknownCount are the sum of actual durations and the
+	// number of finished tests whose expected durations are unknown.
+	unknownActual time.Duration
+	unknownCount  int
+}
+
+// NewTracker constructs a Tracker for a run of tests whose expected durations
+// are expected. A zero expected duration means that it is unknown. If
+// expected is empty, it returns nil, which is a valid Tracker that tracks
+// nothing.
+func NewTracker(expected []time.Duration) *Tracker {
+	if len(expected) == 0 {
+		return nil
+	}
+	t := &Tracker{total: len(expected)}
+	for _, d := range expected {
+		if d > 0 {
+			t.remainingExpected += d
+		} else {
+			t.remainingUnknown++
+		}
+	}
+	return t
+}
+
+// Finish records that a test whose expected duration is expected ran for
+// actual.
+func (t *Tracker) Finish(expected, actual time.Duration) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.markDone(expected)
+	if expected > 0 {
+		t.finishedExpected += expected
+		t.finishedActual += actual
+	} else {
+		t.unknownActual += actual
+		t.unknownCount++
+	}
+}
+
+// Skip records that a test whose expected duration is expected was skipped or
+// did not complete. Its duration is not used for estimation.
+func (t *Tracker) Skip(expected time.Duration) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.markDone(expected)
+}
+
+// markDone updates counters for a finished test. Tests may finish more than
+// the planned number of times when they are retried, so counters are clamped.
+func (t *Tracker) markDone(expected time.Duration) {
+	if t.done < t.total {
+		t.done++
+	}
+	if expected > 0 {
+		t.remainingExpected -= expected
+		if t.remainingExpected < 0 {
+			t.remainingExpected = 0
+		}
+	} else if t.remainingUnknown > 0 {
+		t.remainingUnknown--
+	}
+}
+
+// Status returns the current progress of the run.
+func (t *Tracker) Status() *Status {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := &Status{Done: t.done, Total: t.total}
+
+	// Scale expected durations by how long tests actually took compared to
+	// their expectations so far.
+	ratio := 1.0
+	if t.finishedExpected > 0 {
+		ratio = float64(t.finishedActual) / float64(t.finishedExpected)
+	}
+	eta := time.Duration(float64(t.remainingExpected) * ratio)
+
+	if t.remainingUnknown > 0 {
+		// Estimate durations of tests without expectations by the average
+		// duration of finished tests.
+		var avg time.Duration
+		switch {
+		case t.unknownCount > 0:
+			avg = t.unknownActual / time.Duration(t.unknownCount)
+		case t.done-t.unknownCount > 0 && t.finishedActual > 0:
+			avg = t.finishedActual / time.Duration(t.done-t.unknownCount)
+		default:
+			return s
+		}
+		eta += avg * time.Duration(t.remainingUnknown)
+	}
+	s.ETA = eta.Round(time.Second)
+	s.HasETA = true
+	return s
+}
+
+// Status represents the progress of a run.
+type Status struct {
+	// Done is the number of finished tests.
+	Done int
+	// Total is the number of tests to run.
+	Total int
+	// ETA is the estimated remaining time of the run. It is valid only if
+	// HasETA is true.
+	ETA time.Duration
+	// HasETA is true if the remaining time could be estimated. It is false
+	// e.g. when no test has an expected duration and no test has finished.
+	HasETA bool
+}
+
+// String returns a one-line representation of s including a progress bar,
+// e.g. "[=========>           ] 3/10 (30%), ETA 5m0s".
+func (s *Status) String() string {
+	pct := 100
+	if s.Total > 0 {
+		pct = 100 * s.Done / s.Total
+	}
+	filled := barWidth * pct / 100
+	bar := strings.Repeat("=", filled)
+	if filled < barWidth {
+		bar += ">" + strings.Repeat(" ", barWidth-filled-1)
+	}
+	eta := "unknown"
+	if s.HasETA {
+		eta = s.ETA.String()
+	}
+	return fmt.Sprintf("[%s] %d/%d (%d%%), ETA %s", bar, s.Done, s.Total, pct, eta)
+}
@@ -0,0 +1,74 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package progress_test
+
+import (
+	"testing"
+	"time"
+
+	"go.chromium.org/tast/core/internal/minidriver/progress"
+)
+
+func TestTracker(t *testing.T) {
+	tr := progress.NewTracker([]time.Duration{10 * time.Second, 20 * time.Second, 30 * time.Second, 0})
+
+	// The remaining time is unknown until any test finishes since a test
+	// has no expected duration.
+	if s := tr.Status(); s.Done != 0 || s.Total != 4 || s.HasETA {
+		t.Errorf("Initial Status() = %+v; want 0/4 without ETA", s)
+	}
+
+	// The first test took twice as long as expected, so the remaining
+	// expected durations are doubled. The test without an expected duration
+	// is estimated by the average actual duration.
+	tr.Finish(10*time.Second, 20*time.Second)
+	if s := tr.Status(); s.Done != 1 || !s.HasETA || s.ETA != 120*time.Second {
+		t.Errorf("Status() after 1 test = %+v; want 1/4 with ETA 2m0s", s)
+	}
+
+	tr.Finish(0, 5*time.Second)
+	if s := tr.Status(); s.Done != 2 || !s.HasETA || s.ETA != 100*time.Second {
+		t.Errorf("Status() after 2 tests = %+v; want 2/4 with ETA 1m40s", s)
+	}
+
+	tr.Skip(20 * time.Second)
+	tr.Finish(30*time.Second, 30*time.Second)
+	if s := tr.Status(); s.Done != 4 || !s.HasETA || s.ETA != 0 {
+		t.Errorf("Status() after all tests = %+v; want 4/4 with ETA 0s", s)
+	}
+
+	// Retried tests do not make the progress exceed the total.
+	tr.Finish(10*time.Second, 10*time.Second)
+	if s := tr.Status(); s.Done != 4 || s.ETA != 0 {
+		t.Errorf("Status() after a retry = %+v; want 4/4 with ETA 0s", s)
+	}
+}
+
+func TestTrackerNil(t *testing.T) {
+	tr := progress.NewTracker(nil)
+	if tr != nil {
+		t.Fatalf("NewTracker(nil) = %v; want nil", tr)
+	}
+	tr.Finish(time.Second, time.Second)
+	tr.Skip(time.Second)
+	if s := tr.Status(); s != nil {
+		t.Errorf("Status() = %v; want nil", s)
+	}
+}
+
+func TestStatusString(t *testing.T) {
+	for _, tc := range []struct {
+		s    progress.Status
+		want string
+	}{
+		{progress.Status{Done: 0, Total: 10}, "[>                             ] 0/10 (0%), ETA unknown"},
+		{progress.Status{Done: 5, Total: 10, ETA: 90 * time.Second, HasETA: true}, "[===============>              ] 5/10 (50%), ETA 1m30s"},
+		{progress.Status{Done: 10, Total: 10, HasETA: true}, "[==============================] 10/10 (100%), ETA 0s"},
+	} {
+		if got := tc.s.String(); got != tc.want {
+			t.Errorf("%+v.String() = %q; want %q", tc.s, got, tc.want)
+		}
+	}
+}
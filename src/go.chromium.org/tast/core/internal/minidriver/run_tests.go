@@ -8,6 +8,7 @@ package minidriver
 import (
 	"context"
 	"errors"
+	"os"
 	"time"
 
 	"google.golang.org/protobuf/types/known/durationpb"
@@ -19,6 +20,7 @@ import (
 	"go.chromium.org/tast/core/internal/minidriver/diagnose"
 	"go.chromium.org/tast/core/internal/minidriver/failfast"
 	"go.chromium.org/tast/core/internal/minidriver/processor"
+	"go.chromium.org/tast/core/internal/minidriver/progress"
 	"go.chromium.org/tast/core/internal/minidriver/target"
 	"go.chromium.org/tast/core/internal/protocol"
 	"go.chromium.org/tast/core/internal/run/reporting"
@@ -51,6 +53,7 @@ type Config struct {
 	Retries               int
 	ResDir                string
 	Devservers            []string
+	DataMirrors           []string
 	Target                string
 	LocalDataDir          string
 	LocalOutDir           string
@@ -62,8 +65,18 @@ type Config struct {
 	WaitUntilReadyTimeout time.Duration
 	CheckTestDeps         bool
 	TestVars              map[string]string
+	ScopedTestVars        []string
 	MaybeMissingVars      string
 	MsgTimeout            time.Duration
+	Parallel              int
+	LogRateLimit          int
+	LogBurst              int
+	DiscardDebugLogs      bool
+	ScopedLogLevels       []string
+	Seed                  int64
+	TestOrder             []string
+	RunMetadata           map[string]string
+	QuarantineThreshold   int
 	SwarmingTaskID        string
 	BuildBucketID         string
 	DUTLabConfig          *frameworkprotocol.DUTLabConfig
@@ -71,6 +84,11 @@ type Config struct {
 	DebuggerPort int
 	Proxy        bool
 
+	// RunOnHost specifies whether local bundles are run directly on the host
+	// instead of on the DUT over SSH. The Driver has no connection cache in
+	// this case.
+	RunOnHost bool
+
 	// DUTFeatures contains software/hardware features each DUT has, and runtime variables.
 	// Its key for the map is the role of the DUT such as "cd1".
 	// The role for primary DUT should be "".
@@ -97,20 +115,33 @@ func (d *Driver) RunLocalTests(ctx context.Context, bundle string, tests []strin
 type HandlersFactory func(ctx context.Context, cc *target.ConnCache) (context.Context, []processor.Handler)
 
 // NewRootHandlersFactory creates a new factory for CLI.
-func NewRootHandlersFactory(resDir string, counter *failfast.Counter, client *reporting.RPCClient) HandlersFactory {
+// System log entries spanning a failed test are saved to its output directory
+// with syslogPreRoll extra time before the test start, unless syslogPreRoll is
+// negative. Finished tests are recorded to progressTracker to log the
+// progress of the run.
+func NewRootHandlersFactory(resDir string, counter *failfast.Counter, progressTracker *progress.Tracker, syslogPreRoll time.Duration, client *reporting.RPCClient, reporter reporting.Reporter) HandlersFactory {
 	return func(ctx context.Context, cc *target.ConnCache) (context.Context, []processor.Handler) {
 		multiplexer := logging.NewMultiLogger()
 		ctx = logging.AttachLogger(ctx, multiplexer)
 
-		pull := func(src, dst string) error {
-			return linuxssh.GetAndDeleteFile(ctx, cc.Conn().SSHConn(), src, dst, linuxssh.PreserveSymlinks)
+		pull := newPullFunc(ctx, cc)
+		preRoll := syslogPreRoll
+		if cc == nil {
+			// System logs of the host are not collected.
+			preRoll = -1
 		}
 		return ctx, []processor.Handler{
 			processor.NewLoggingHandler(resDir, multiplexer, client),
 			processor.NewTimingHandler(),
 			processor.NewStreamedResultsHandler(resDir),
+			processor.NewFixtureResultsHandler(resDir),
 			processor.NewRPCResultsHandler(client),
+			processor.NewReporterHandler(reporter),
 			processor.NewFailFastHandler(counter),
+			processor.NewProgressHandler(progressTracker),
+			processor.NewFailureSyslogHandler(preRoll, func(ctx context.Context, since, until time.Time, dst string) error {
+				return diagnose.SyslogWindow(ctx, cc, since, until, dst)
+			}),
 			// copyOutputHandler should come last as it can block RunEnd for a while.
 			processor.NewCopyOutputHandler(pull),
 		}
@@ -124,9 +155,7 @@ func NewIntermediateHandlersFactory(resDir string, counter *failfast.Counter, pa
 	return func(ctx context.Context, cc *target.ConnCache) (context.Context, []processor.Handler) {
 		multiplexer := logging.NewMultiLogger()
 		ctx = logging.AttachLogger(ctx, multiplexer)
-		pull := func(src, dst string) error {
-			return linuxssh.GetAndDeleteFile(ctx, cc.Conn().SSHConn(), src, dst, linuxssh.PreserveSymlinks)
-		}
+		pull := newPullFunc(ctx, cc)
 		return ctx, []processor.Handler{
 			processor.NewFailFastHandler(counter),
 			processor.NewStackOperationHandler(handle),
@@ -136,7 +165,22 @@ func NewIntermediateHandlersFactory(resDir string, counter *failfast.Counter, pa
 	}
 }
 
+// newPullFunc returns a function to move a file or directory at src on the
+// target to dst on the host. If cc is nil, local bundles run on the host, so
+// files are moved locally.
+func newPullFunc(ctx context.Context, cc *target.ConnCache) func(src, dst string) error {
+	if cc == nil {
+		return os.Rename
+	}
+	return func(src, dst string) error {
+		return linuxssh.GetAndDeleteFile(ctx, cc.Conn().SSHConn(), src, dst, linuxssh.PreserveSymlinks)
+	}
+}
+
 func (d *Driver) runLocalTestsOnce(ctx context.Context, bundle string, tests []string, state *protocol.StartFixtureState) ([]*resultsjson.Result, error) {
+	if d.cfg.RunOnHost {
+		return d.runHostTestsOnce(ctx, bundle, tests, state)
+	}
 	if d.cc == nil {
 		return nil, nil
 	}
@@ -165,21 +209,41 @@ func (d *Driver) runLocalTestsOnce(ctx context.Context, bundle string, tests []s
 	return proc.Results(), proc.FatalError()
 }
 
-func (d *Driver) newConfigsForLocalTests(tests []string, state *protocol.StartFixtureState) (*protocol.BundleConfig, *protocol.RunConfig) {
-	devservers := append([]string(nil), d.cfg.Devservers...)
-	if url, ok := d.cc.Conn().Services().EphemeralDevserverURL(); ok {
-		devservers = append(devservers, url)
-	}
+// runHostTestsOnce runs local tests by executing the local bundle directly
+// on the host.
+func (d *Driver) runHostTestsOnce(ctx context.Context, bundle string, tests []string, state *protocol.StartFixtureState) ([]*resultsjson.Result, error) {
+	bcfg, rcfg := d.newConfigsForLocalTests(tests, state)
 
-	var tlwServer, tlwSelfName string
-	if addr, ok := d.cc.Conn().Services().TLWAddr(); ok {
-		tlwServer = addr.String()
-		tlwSelfName = d.cfg.Target
-	}
+	ctx, hs := d.cfg.Factory(ctx, nil)
 
-	var dutServer string
-	if addr, ok := d.cc.Conn().Services().DUTServerAddr(); ok {
-		dutServer = addr.String()
+	proc := processor.New(d.cfg.ResDir, nopDiagnose, hs, bundle)
+	cl := bundleclient.NewHost(bundle, d.cfg.LocalBundleDir, d.cfg.MsgTimeout)
+	cl.RunTests(ctx, bcfg, rcfg, proc, d.cfg.Recursive)
+	return proc.Results(), proc.FatalError()
+}
+
+// nopDiagnose is a DiagnoseFunc that does nothing.
+func nopDiagnose(ctx context.Context, outDir string) string {
+	return ""
+}
+
+func (d *Driver) newConfigsForLocalTests(tests []string, state *protocol.StartFixtureState) (*protocol.BundleConfig, *protocol.RunConfig) {
+	devservers := append([]string(nil), d.cfg.Devservers...)
+	var tlwServer, tlwSelfName, dutServer string
+	// Services are exposed to the DUT by SSH port forwarding, so they are
+	// unavailable when local bundles run on the host.
+	if d.cc != nil {
+		svcs := d.cc.Conn().Services()
+		if url, ok := svcs.EphemeralDevserverURL(); ok {
+			devservers = append(devservers, url)
+		}
+		if addr, ok := svcs.TLWAddr(); ok {
+			tlwServer = addr.String()
+			tlwSelfName = d.cfg.Target
+		}
+		if addr, ok := svcs.DUTServerAddr(); ok {
+			dutServer = addr.String()
+		}
 	}
 
 	var dutFeature *frameworkprotocol.DUTFeatures
@@ -207,6 +271,7 @@ func (d *Driver) newConfigsForLocalTests(tests []string, state *protocol.StartFi
 			CheckDeps: d.cfg.CheckTestDeps,
 			Infra: &protocol.InfraFeatures{
 				Vars:             d.cfg.TestVars,
+				ScopedVars:       d.cfg.ScopedTestVars,
 				MaybeMissingVars: d.cfg.MaybeMissingVars,
 				DUTLabConfig:     d.cfg.DUTLabConfig,
 			},
@@ -216,6 +281,7 @@ func (d *Driver) newConfigsForLocalTests(tests []string, state *protocol.StartFi
 		},
 		ServiceConfig: &protocol.ServiceConfig{
 			Devservers:     devservers,
+			DataMirrors:    d.cfg.DataMirrors,
 			DutServer:      dutServer, // Only pass in DUT server for local tests.
 			TlwServer:      tlwServer,
 			TlwSelfName:    tlwSelfName,
@@ -233,6 +299,15 @@ func (d *Driver) newConfigsForLocalTests(tests []string, state *protocol.StartFi
 		WaitUntilReadyTimeout: durationpb.New(d.cfg.WaitUntilReadyTimeout),
 		MsgTimeout:            durationpb.New(d.cfg.MsgTimeout),
 		DebugPort:             uint32(d.cfg.DebuggerPort),
+		Parallel:              int32(d.cfg.Parallel),
+		LogRateLimit:          int32(d.cfg.LogRateLimit),
+		LogBurst:              int32(d.cfg.LogBurst),
+		DiscardDebugLogs:      d.cfg.DiscardDebugLogs,
+		ScopedLogLevels:       d.cfg.ScopedLogLevels,
+		Seed:                  d.cfg.Seed,
+		TestOrder:             d.cfg.TestOrder,
+		RunMetadata:           protocol.MapToStringPairs(d.cfg.RunMetadata),
+		QuarantineThreshold:   int32(d.cfg.QuarantineThreshold),
 	}
 	return bcfg, rcfg
 }
@@ -21,7 +21,7 @@ import (
 	"time"
 
 	"go.chromium.org/tast/core/errors"
-	"go.chromium.org/tast/core/testing"
+	"go.chromium.org/tast/core/internal/logging"
 )
 
 const defaultRPCTimeout = 10 * time.Second
@@ -285,13 +285,13 @@ func getTimeout(ctx context.Context, cl Call) time.Duration {
 	timeout := cl.timeout
 	if timeout > maxRPCTimeout {
 		timeout = maxRPCTimeout
-		testing.ContextLogf(ctx, "Using max timeout %v", timeout)
+		logging.Infof(ctx, "Using max timeout %v", timeout)
 	}
 	if dl, ok := ctx.Deadline(); ok {
 		newTimeout := time.Until(dl)
 		if newTimeout < timeout {
 			timeout = newTimeout
-			testing.ContextLogf(ctx, "Using context timeout %v", timeout)
+			logging.Infof(ctx, "Using context timeout %v", timeout)
 		}
 	}
 	return timeout
@@ -484,7 +484,7 @@ func (r *XMLRpc) Run(ctx context.Context, cl Call, out ...interface{}) error {
 	// Otherwise, return without unpacking.
 	if len(out) > 0 {
 		if err := res.unpack(out); err != nil {
-			testing.ContextLogf(ctx, "Failed to unpack XML-RPC response for request %v: %s", cl, string(bodyBytes))
+			logging.Infof(ctx, "Failed to unpack XML-RPC response for request %v: %s", cl, string(bodyBytes))
 			return err
 		}
 	}
@@ -6,16 +6,24 @@ package planner
 
 import (
 	"context"
+	"path/filepath"
+	"sync"
 
 	"go.chromium.org/tast/core/errors"
 	"go.chromium.org/tast/core/internal/devserver"
 	"go.chromium.org/tast/core/internal/extdata"
 	"go.chromium.org/tast/core/internal/protocol"
+	"go.chromium.org/tast/core/internal/timing"
 )
 
+// mirrorStateFileName is the name of a file in the data directory to persist
+// backoff states of data mirrors across runs.
+const mirrorStateFileName = ".data_mirrors.json"
+
 // downloader encapsulates the logic to download external data files.
 type downloader struct {
-	m *extdata.Manager
+	mu sync.Mutex // protects m, which may be used by tests running concurrently
+	m  *extdata.Manager
 
 	pcfg           *Config
 	cl             devserver.Client
@@ -36,6 +44,8 @@ func newDownloader(ctx context.Context, pcfg *Config) (*downloader, error) {
 		return nil, errors.Wrapf(err, "failed to create new client [devservers=%v, TLWServer=%s]",
 			pcfg.Service.GetDevservers(), pcfg.Service.GetTlwServer())
 	}
+	cl = devserver.NewFallbackClient(ctx, cl, pcfg.Service.GetDataMirrors(),
+		filepath.Join(pcfg.Dirs.GetDataDir(), mirrorStateFileName))
 	m, err := extdata.NewManager(ctx, pcfg.Dirs.GetDataDir(), pcfg.DataFile.GetBuildArtifactsUrl())
 	if err != nil {
 		return nil, err
@@ -74,12 +84,29 @@ func (d *downloader) BeforeEntity(ctx context.Context, entity *protocol.Entity)
 }
 
 func (d *downloader) download(ctx context.Context, entities []*protocol.Entity) (release func()) {
-	jobs, release := d.m.PrepareDownloads(ctx, entities)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	jobs, releaseJobs := d.m.PrepareDownloads(ctx, entities)
 	if len(jobs) > 0 {
 		if d.beforeDownload != nil {
 			d.beforeDownload(ctx)
 		}
+		ctx, st := timing.Start(ctx, "download_data")
 		extdata.RunDownloads(ctx, d.pcfg.Dirs.GetDataDir(), jobs, d.cl)
+		st.End()
+	}
+	return func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		releaseJobs()
 	}
-	return release
+}
+
+// Purgeable returns a list of external data file paths not needed by the
+// currently running entities.
+func (d *downloader) Purgeable() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.m.Purgeable()
 }
@@ -82,6 +82,7 @@ func runExternalTests(ctx context.Context, names []string, stack *fixture.Combin
 		Retries:        int(pcfg.ExternalTarget.Config.GetRetries()),
 		ResDir:         pcfg.Dirs.GetOutDir(),
 		Devservers:     pcfg.ExternalTarget.Config.GetDevservers(),
+		DataMirrors:    pcfg.Service.GetDataMirrors(),
 		Target:         pcfg.ExternalTarget.Device.GetDutConfig().GetSshConfig().GetConnectionSpec(),
 		LocalDataDir:   pcfg.ExternalTarget.Config.GetDirs().GetDataDir(),
 		LocalOutDir:    pcfg.ExternalTarget.Config.GetDirs().GetOutDir(),
@@ -94,11 +95,21 @@ func runExternalTests(ctx context.Context, names []string, stack *fixture.Combin
 		WaitUntilReady:        pcfg.ExternalTarget.Config.GetWaitUntilReady(),
 		CheckTestDeps:         pcfg.Features.GetCheckDeps(),
 		TestVars:              pcfg.Features.GetInfra().GetVars(),
+		ScopedTestVars:        pcfg.Features.GetInfra().GetScopedVars(),
 		MaybeMissingVars:      pcfg.Features.GetInfra().GetMaybeMissingVars(),
 		DUTLabConfig:          pcfg.Features.GetInfra().GetDUTLabConfig(),
 		MsgTimeout:            pcfg.ExternalTarget.Config.GetMsgTimeout().AsDuration(),
 		SystemServicesTimeout: pcfg.ExternalTarget.Config.GetSystemServicesTimeout().AsDuration(),
 		WaitUntilReadyTimeout: pcfg.ExternalTarget.Config.GetWaitUntilReadyTimeout().AsDuration(),
+		Parallel:              int(pcfg.ExternalTarget.Config.GetParallel()),
+		LogRateLimit:          int(pcfg.ExternalTarget.Config.GetLogRateLimit()),
+		LogBurst:              int(pcfg.ExternalTarget.Config.GetLogBurst()),
+		DiscardDebugLogs:      pcfg.ExternalTarget.Config.GetDiscardDebugLogs(),
+		ScopedLogLevels:       pcfg.ExternalTarget.Config.GetScopedLogLevels(),
+		Seed:                  pcfg.ExternalTarget.Config.GetSeed(),
+		TestOrder:             pcfg.ExternalTarget.Config.GetTestOrder(),
+		RunMetadata:           protocol.StringPairsToMap(pcfg.ExternalTarget.Config.GetRunMetadata()),
+		QuarantineThreshold:   int(pcfg.ExternalTarget.Config.GetQuarantineThreshold()),
 
 		DebuggerPort: int(pcfg.ExternalTarget.Config.GetDebugPort()),
 		Proxy:        pcfg.ExternalTarget.Config.GetProxy(),
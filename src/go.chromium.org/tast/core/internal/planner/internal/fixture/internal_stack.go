@@ -6,7 +6,6 @@ package fixture
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"path/filepath"
@@ -388,9 +387,10 @@ type statefulFixture struct {
 	root *testing.EntityRoot
 	fout *output.EntityStream
 
-	status Status
-	errs   []*protocol.Error
-	val    interface{} // val returned by SetUp
+	status    Status
+	errs      []*protocol.Error
+	val       interface{} // val returned by SetUp
+	timingLog *timing.Log // timing log reported when the fixture ends
 }
 
 // newStatefulFixture creates a new statefulFixture.
@@ -433,7 +433,7 @@ func (f *statefulFixture) Val() interface{} {
 
 // SerializedVal serializes the value of the fixture.
 func (f *statefulFixture) SerializedVal() ([]byte, error) {
-	serializedValue, err := json.Marshal(f.val)
+	serializedValue, err := testing.SerializeFixtValue(f.val)
 	if err != nil {
 		return nil, fmt.Errorf("failed to serialize value from fixture %s: %v", f.fixt.Name, err)
 	}
@@ -451,16 +451,26 @@ func (f *statefulFixture) RunSetUp(ctx context.Context) error {
 	name := fmt.Sprintf("%s:SetUp", f.fixt.Name)
 
 	f.fout.Start(s.OutDir())
+	f.fout.SetFixturePhase(protocol.FixturePhase_SET_UP)
+	defer f.fout.SetFixturePhase(protocol.FixturePhase_FIXTURE_PHASE_UNSPECIFIED)
+
+	f.timingLog = timing.NewLog()
+	ctx, st := timing.Start(timing.NewContext(ctx, f.timingLog), "SetUp")
 
 	var val interface{}
-	if err := usercode.SafeCall(ctx, name, f.fixt.SetUpTimeout, f.cfg.GracePeriod, usercode.ErrorOnPanic(s), func(ctx context.Context) {
+	err := usercode.SafeCall(ctx, name, f.fixt.SetUpTimeout, f.cfg.GracePeriod, usercode.ErrorOnPanic(s), func(ctx context.Context) {
 		entity.PreCheck(f.fixt.Data, s)
 		if s.HasError() {
 			return
 		}
 
 		val = f.fixt.Impl.SetUp(ctx, s)
-	}); err != nil {
+	})
+	st.End()
+	if err != nil {
+		// Report the error as the fixture's so that it is not attributed to
+		// a test.
+		f.fout.Error(&protocol.Error{Reason: err.Error()})
 		return err
 	}
 	fixtName := f.fixt.Name
@@ -469,8 +479,7 @@ func (f *statefulFixture) RunSetUp(ctx context.Context) error {
 	}
 	f.errs = rewriteErrorsForTest(f.fout.Errors(), fixtName)
 	if len(f.errs) > 0 {
-		// TODO(crbug.com/1127169): Support timing log.
-		f.fout.End(nil, timing.NewLog())
+		f.fout.End(nil, f.timingLog)
 		return nil
 	}
 
@@ -488,15 +497,19 @@ func (f *statefulFixture) RunTearDown(ctx context.Context) error {
 	ctx = f.root.NewContext(ctx)
 	s := f.root.NewFixtState(f.fixt)
 	name := fmt.Sprintf("%s:TearDown", f.fixt.Name)
+	f.fout.SetFixturePhase(protocol.FixturePhase_TEAR_DOWN)
+	ctx, st := timing.Start(timing.NewContext(ctx, f.timingLog), "TearDown")
 
-	if err := usercode.SafeCall(ctx, name, f.fixt.TearDownTimeout, f.cfg.GracePeriod, usercode.ErrorOnPanic(s), func(ctx context.Context) {
+	err := usercode.SafeCall(ctx, name, f.fixt.TearDownTimeout, f.cfg.GracePeriod, usercode.ErrorOnPanic(s), func(ctx context.Context) {
 		f.fixt.Impl.TearDown(ctx, s)
-	}); err != nil {
+	})
+	st.End()
+	if err != nil {
+		f.fout.Error(&protocol.Error{Reason: err.Error()})
 		return err
 	}
 
-	// TODO(crbug.com/1127169): Support timing log.
-	f.fout.End(nil, timing.NewLog())
+	f.fout.End(nil, f.timingLog)
 
 	f.status = StatusRed
 	f.val = nil
@@ -606,6 +619,7 @@ func rewriteErrorsForTest(errs []*protocol.Error, fixtureName string) []*protoco
 		newErrs[i] = &protocol.Error{
 			Reason:   reason,
 			Location: e.GetLocation(),
+			Category: e.GetCategory(),
 		}
 	}
 	return newErrs
@@ -653,7 +653,7 @@ func TestInternalStackOutputRed(t *gotesting.T) {
 		&protocol.EntityLogEvent{EntityName: "fixt1", Text: "SetUp 1", Level: protocol.LogLevel_INFO},
 		&protocol.EntityStartEvent{Entity: fixt2.EntityProto()},
 		&protocol.EntityLogEvent{EntityName: "fixt2", Text: "SetUp 2", Level: protocol.LogLevel_INFO},
-		&protocol.EntityErrorEvent{EntityName: "fixt2", Error: &protocol.Error{Reason: "SetUp 2 failure"}},
+		&protocol.EntityErrorEvent{EntityName: "fixt2", Error: &protocol.Error{Reason: "SetUp 2 failure"}, FixturePhase: protocol.FixturePhase_SET_UP},
 		&protocol.EntityEndEvent{EntityName: "fixt2"},
 		&protocol.EntityLogEvent{EntityName: "fixt1", Text: "TearDown 1", Level: protocol.LogLevel_INFO},
 		&protocol.EntityEndEvent{EntityName: "fixt1"},
@@ -0,0 +1,152 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package output
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"go.chromium.org/tast/core/internal/logging"
+	"go.chromium.org/tast/core/internal/protocol"
+	"go.chromium.org/tast/core/internal/testing"
+	"go.chromium.org/tast/core/internal/timing"
+)
+
+// LogLimit specifies limits on logs reported by each entity.
+type LogLimit struct {
+	// MinLevel is the minimum level of logs to report. Logs of lower levels
+	// are discarded.
+	MinLevel logging.Level
+	// ScopedMinLevels overrides MinLevel for entities matching their
+	// patterns. Later ones take precedence over earlier ones.
+	ScopedMinLevels []*testing.ScopedLogLevel
+	// Rate is the maximum number of log lines per second an entity may
+	// report. If it is 0 or less, logs are not rate limited.
+	Rate int
+	// Burst is the maximum number of log lines an entity may report at once
+	// without being limited by Rate. If it is less than Rate, Rate is used.
+	Burst int
+}
+
+// logBucket is a token bucket to rate limit logs of an entity.
+type logBucket struct {
+	minLevel logging.Level
+	tokens   float64
+	last     time.Time
+	dropped  int
+}
+
+// take returns whether a log line reported at ts is within the limit.
+func (b *logBucket) take(ts time.Time, rate, burst int) bool {
+	if b.last.IsZero() {
+		b.tokens = float64(burst)
+	} else if d := ts.Sub(b.last); d > 0 {
+		b.tokens += d.Seconds() * float64(rate)
+		if b.tokens > float64(burst) {
+			b.tokens = float64(burst)
+		}
+	}
+	if ts.After(b.last) {
+		b.last = ts
+	}
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// limitedStream wraps Stream to enforce LogLimit on logs of each entity.
+type limitedStream struct {
+	Stream
+	lim *LogLimit
+
+	mu      sync.Mutex
+	buckets map[string]*logBucket // keyed by entity names
+}
+
+// NewLimitedStream returns a Stream that forwards outputs to out while
+// enforcing lim on logs reported by each entity. Logs dropped by the rate limit
+// are replaced with a truncation marker reported when the entity reports its
+// next log or ends. Errors are never limited.
+func NewLimitedStream(out Stream, lim *LogLimit) Stream {
+	if lim.MinLevel <= logging.LevelDebug && len(lim.ScopedMinLevels) == 0 && lim.Rate <= 0 {
+		return out
+	}
+	return &limitedStream{
+		Stream:  out,
+		lim:     lim,
+		buckets: make(map[string]*logBucket),
+	}
+}
+
+func (s *limitedStream) burst() int {
+	if s.lim.Burst < s.lim.Rate {
+		return s.lim.Rate
+	}
+	return s.lim.Burst
+}
+
+// bucket returns the bucket of ei, creating it if it does not exist yet.
+// s.mu must be held.
+func (s *limitedStream) bucket(ei *protocol.Entity) *logBucket {
+	b := s.buckets[ei.GetName()]
+	if b == nil {
+		b = &logBucket{minLevel: testing.LogLevelForTest(ei.GetName(), s.lim.MinLevel, s.lim.ScopedMinLevels)}
+		s.buckets[ei.GetName()] = b
+	}
+	return b
+}
+
+// flushDropped reports a truncation marker if any log of ei has been dropped
+// since the last marker. s.mu must be held.
+func (s *limitedStream) flushDropped(ei *protocol.Entity, ts time.Time) error {
+	b := s.buckets[ei.GetName()]
+	if b == nil || b.dropped == 0 {
+		return nil
+	}
+	msg := fmt.Sprintf("[%d log line(s) truncated: exceeded the limit of %d lines per second]", b.dropped, s.lim.Rate)
+	b.dropped = 0
+	return s.Stream.EntityLog(ei, logging.LevelInfo, ts, msg)
+}
+
+func (s *limitedStream) EntityStart(ei *protocol.Entity, outDir string) error {
+	s.mu.Lock()
+	delete(s.buckets, ei.GetName())
+	s.mu.Unlock()
+	return s.Stream.EntityStart(ei, outDir)
+}
+
+func (s *limitedStream) EntityLog(ei *protocol.Entity, level logging.Level, ts time.Time, msg string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b := s.bucket(ei)
+	if level < b.minLevel {
+		return nil
+	}
+	if s.lim.Rate <= 0 {
+		return s.Stream.EntityLog(ei, level, ts, msg)
+	}
+	if !b.take(ts, s.lim.Rate, s.burst()) {
+		b.dropped++
+		return nil
+	}
+	if err := s.flushDropped(ei, ts); err != nil {
+		return err
+	}
+	return s.Stream.EntityLog(ei, level, ts, msg)
+}
+
+func (s *limitedStream) EntityEnd(ei *protocol.Entity, skip *protocol.Skip, consumedVars []string, timingLog *timing.Log) error {
+	s.mu.Lock()
+	err := s.flushDropped(ei, time.Now())
+	delete(s.buckets, ei.GetName())
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return s.Stream.EntityEnd(ei, skip, consumedVars, timingLog)
+}
@@ -0,0 +1,125 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package output_test
+
+import (
+	gotesting "testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	"go.chromium.org/tast/core/internal/logging"
+	"go.chromium.org/tast/core/internal/planner/internal/output"
+	"go.chromium.org/tast/core/internal/planner/internal/output/outputtest"
+	"go.chromium.org/tast/core/internal/protocol"
+	"go.chromium.org/tast/core/internal/testing"
+	"go.chromium.org/tast/core/internal/timing"
+)
+
+func TestLimitedStream(t *gotesting.T) {
+	sink := outputtest.NewSink()
+	out := output.NewLimitedStream(sink, &output.LogLimit{MinLevel: logging.LevelInfo, Rate: 2, Burst: 3})
+	test := &protocol.Entity{Name: "pkg.Test"}
+	tout := output.NewEntityStream(out, test)
+
+	base := time.Unix(1000, 0)
+	tout.Start("/tmp/out")
+	tout.Log(logging.LevelDebug, base, "debug")
+	for i := 0; i < 5; i++ {
+		tout.Log(logging.LevelInfo, base, "burst")
+	}
+	tout.Error(&protocol.Error{Reason: "faulty"})
+	// One second later, two more lines are allowed.
+	for i := 0; i < 3; i++ {
+		tout.Log(logging.LevelInfo, base.Add(time.Second), "later")
+	}
+	tout.End(nil, timing.NewLog())
+
+	got := sink.ReadAll()
+
+	want := []protocol.Event{
+		&protocol.EntityStartEvent{Entity: test, OutDir: "/tmp/out"},
+		&protocol.EntityLogEvent{EntityName: "pkg.Test", Text: "burst", Level: protocol.LogLevel_INFO},
+		&protocol.EntityLogEvent{EntityName: "pkg.Test", Text: "burst", Level: protocol.LogLevel_INFO},
+		&protocol.EntityLogEvent{EntityName: "pkg.Test", Text: "burst", Level: protocol.LogLevel_INFO},
+		&protocol.EntityErrorEvent{EntityName: "pkg.Test", Error: &protocol.Error{Reason: "faulty"}},
+		&protocol.EntityLogEvent{EntityName: "pkg.Test", Text: "[2 log line(s) truncated: exceeded the limit of 2 lines per second]", Level: protocol.LogLevel_INFO},
+		&protocol.EntityLogEvent{EntityName: "pkg.Test", Text: "later", Level: protocol.LogLevel_INFO},
+		&protocol.EntityLogEvent{EntityName: "pkg.Test", Text: "later", Level: protocol.LogLevel_INFO},
+		&protocol.EntityLogEvent{EntityName: "pkg.Test", Text: "[1 log line(s) truncated: exceeded the limit of 2 lines per second]", Level: protocol.LogLevel_INFO},
+		&protocol.EntityEndEvent{EntityName: "pkg.Test"},
+	}
+	if diff := cmp.Diff(got, want, protocmp.Transform()); diff != "" {
+		t.Error("Output mismatch (-got +want):\n", diff)
+	}
+}
+
+func TestLimitedStreamPerEntity(t *gotesting.T) {
+	sink := outputtest.NewSink()
+	out := output.NewLimitedStream(sink, &output.LogLimit{Rate: 1})
+	test1 := &protocol.Entity{Name: "pkg.Test1"}
+	test2 := &protocol.Entity{Name: "pkg.Test2"}
+	tout1 := output.NewEntityStream(out, test1)
+	tout2 := output.NewEntityStream(out, test2)
+
+	ts := time.Unix(1000, 0)
+	tout1.Start("/tmp/out1")
+	tout1.Log(logging.LevelDebug, ts, "test1")
+	tout1.End(nil, timing.NewLog())
+	tout2.Start("/tmp/out2")
+	tout2.Log(logging.LevelDebug, ts, "test2")
+	tout2.End(nil, timing.NewLog())
+
+	got := sink.ReadAll()
+
+	want := []protocol.Event{
+		&protocol.EntityStartEvent{Entity: test1, OutDir: "/tmp/out1"},
+		&protocol.EntityLogEvent{EntityName: "pkg.Test1", Text: "test1", Level: protocol.LogLevel_DEBUG},
+		&protocol.EntityEndEvent{EntityName: "pkg.Test1"},
+		&protocol.EntityStartEvent{Entity: test2, OutDir: "/tmp/out2"},
+		&protocol.EntityLogEvent{EntityName: "pkg.Test2", Text: "test2", Level: protocol.LogLevel_DEBUG},
+		&protocol.EntityEndEvent{EntityName: "pkg.Test2"},
+	}
+	if diff := cmp.Diff(got, want, protocmp.Transform()); diff != "" {
+		t.Error("Output mismatch (-got +want):\n", diff)
+	}
+}
+
+func TestLimitedStreamScopedMinLevels(t *gotesting.T) {
+	quiet, err := testing.ParseScopedLogLevel("pkg.Chatty:info")
+	if err != nil {
+		t.Fatal("ParseScopedLogLevel failed: ", err)
+	}
+	sink := outputtest.NewSink()
+	out := output.NewLimitedStream(sink, &output.LogLimit{MinLevel: logging.LevelDebug, ScopedMinLevels: []*testing.ScopedLogLevel{quiet}})
+	chatty := &protocol.Entity{Name: "pkg.Chatty"}
+	other := &protocol.Entity{Name: "pkg.Other"}
+	cout := output.NewEntityStream(out, chatty)
+	oout := output.NewEntityStream(out, other)
+
+	ts := time.Unix(1000, 0)
+	cout.Start("/tmp/out1")
+	cout.Log(logging.LevelDebug, ts, "debug")
+	cout.Log(logging.LevelInfo, ts, "info")
+	cout.End(nil, timing.NewLog())
+	oout.Start("/tmp/out2")
+	oout.Log(logging.LevelDebug, ts, "debug")
+	oout.End(nil, timing.NewLog())
+
+	got := sink.ReadAll()
+
+	want := []protocol.Event{
+		&protocol.EntityStartEvent{Entity: chatty, OutDir: "/tmp/out1"},
+		&protocol.EntityLogEvent{EntityName: "pkg.Chatty", Text: "info", Level: protocol.LogLevel_INFO},
+		&protocol.EntityEndEvent{EntityName: "pkg.Chatty"},
+		&protocol.EntityStartEvent{Entity: other, OutDir: "/tmp/out2"},
+		&protocol.EntityLogEvent{EntityName: "pkg.Other", Text: "debug", Level: protocol.LogLevel_DEBUG},
+		&protocol.EntityEndEvent{EntityName: "pkg.Other"},
+	}
+	if diff := cmp.Diff(got, want, protocmp.Transform()); diff != "" {
+		t.Error("Output mismatch (-got +want):\n", diff)
+	}
+}
@@ -26,8 +26,11 @@ type Stream interface {
 	EntityLog(ei *protocol.Entity, level logging.Level, ts time.Time, msg string) error
 	// EntityError reports an error from an entity. An entity that reported one or more errors should be considered failure.
 	EntityError(ei *protocol.Entity, e *protocol.Error) error
-	// EntityEnd reports that an entity has ended. If skipReasons is not empty it is considered skipped.
-	EntityEnd(ei *protocol.Entity, skipReasons []string, timingLog *timing.Log) error
+	// FixtureError reports an error from a fixture in the phase. A fixture that reported one or more errors should be considered failure.
+	FixtureError(ei *protocol.Entity, phase protocol.FixturePhase, e *protocol.Error) error
+	// EntityEnd reports that an entity has ended. If skip is not nil it is considered skipped.
+	// consumedVars contains sorted names of runtime variables the entity read.
+	EntityEnd(ei *protocol.Entity, skip *protocol.Skip, consumedVars []string, timingLog *timing.Log) error
 	// ExternalEvent reports events happened in external bundles.
 	ExternalEvent(res *protocol.RunTestsResponse) error
 	// StackOperation reports stack operation request.
@@ -42,9 +45,11 @@ type EntityStream struct {
 	out Stream
 	ei  *protocol.Entity
 
-	mu    sync.Mutex
-	errs  []*protocol.Error
-	ended bool
+	mu           sync.Mutex
+	errs         []*protocol.Error
+	ended        bool
+	phase        protocol.FixturePhase
+	consumedVars []string
 }
 
 var _ testing.OutputStream = &EntityStream{}
@@ -95,12 +100,31 @@ func (w *EntityStream) Error(e *protocol.Error) error {
 		// TODO(crbug.com/1035940): Consider emitting RunError.
 		return nil
 	}
+	if w.phase != protocol.FixturePhase_FIXTURE_PHASE_UNSPECIFIED {
+		return w.out.FixtureError(w.ei, w.phase, e)
+	}
 	return w.out.EntityError(w.ei, e)
 }
 
-// End reports that the entity has ended. After End is called, all methods will
-// fail with an error.
-func (w *EntityStream) End(skipReasons []string, timingLog *timing.Log) error {
+// SetFixturePhase sets the phase of the fixture. Errors reported while the
+// phase is set are reported as fixture errors in the phase.
+func (w *EntityStream) SetFixturePhase(phase protocol.FixturePhase) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.phase = phase
+}
+
+// SetConsumedVars sets names of runtime variables the entity read, to be
+// reported on End.
+func (w *EntityStream) SetConsumedVars(names []string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.consumedVars = names
+}
+
+// End reports that the entity has ended. If skip is not nil the entity is
+// considered skipped. After End is called, all methods will fail with an error.
+func (w *EntityStream) End(skip *protocol.Skip, timingLog *timing.Log) error {
 	if timingLog == nil {
 		panic("BUG: entityOutputStream.End: nil timing log")
 	}
@@ -113,7 +137,7 @@ func (w *EntityStream) End(skipReasons []string, timingLog *timing.Log) error {
 		return nil
 	}
 	w.ended = true
-	return w.out.EntityEnd(w.ei, skipReasons, timingLog)
+	return w.out.EntityEnd(w.ei, skip, w.consumedVars, timingLog)
 }
 
 // Errors returns errors reported so far.
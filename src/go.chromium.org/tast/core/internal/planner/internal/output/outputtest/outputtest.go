@@ -7,6 +7,7 @@ package outputtest
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"go.chromium.org/tast/core/internal/logging"
@@ -17,8 +18,9 @@ import (
 )
 
 // Sink is fake output sink for unit testing.
-// It implements output.Stream .
+// It implements output.Stream . It is safe to call its methods concurrently.
 type Sink struct {
+	mu   sync.Mutex
 	msgs []protocol.Event
 }
 
@@ -31,6 +33,8 @@ func NewSink() *Sink {
 
 // RunLog implements output.Stream.
 func (s *Sink) RunLog(level logging.Level, ts time.Time, msg string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.msgs = append(s.msgs, &protocol.RunLogEvent{
 		Text:  msg,
 		Time:  timestamppb.New(ts),
@@ -41,6 +45,8 @@ func (s *Sink) RunLog(level logging.Level, ts time.Time, msg string) error {
 
 // EntityStart implements output.Stream.
 func (s *Sink) EntityStart(ei *protocol.Entity, outDir string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.msgs = append(s.msgs, &protocol.EntityStartEvent{
 		Entity: ei,
 		OutDir: outDir,
@@ -50,6 +56,8 @@ func (s *Sink) EntityStart(ei *protocol.Entity, outDir string) error {
 
 // EntityLog implements output.Stream.
 func (s *Sink) EntityLog(ei *protocol.Entity, level logging.Level, ts time.Time, msg string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.msgs = append(s.msgs, &protocol.EntityLogEvent{
 		EntityName: ei.GetName(),
 		Text:       msg,
@@ -60,6 +68,8 @@ func (s *Sink) EntityLog(ei *protocol.Entity, level logging.Level, ts time.Time,
 
 // EntityError implements output.Stream.
 func (s *Sink) EntityError(ei *protocol.Entity, e *protocol.Error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.msgs = append(s.msgs, &protocol.EntityErrorEvent{
 		EntityName: ei.GetName(),
 		// Clear Error fields except for Reason.
@@ -68,14 +78,25 @@ func (s *Sink) EntityError(ei *protocol.Entity, e *protocol.Error) error {
 	return nil
 }
 
+// FixtureError implements output.Stream.
+func (s *Sink) FixtureError(ei *protocol.Entity, phase protocol.FixturePhase, e *protocol.Error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.msgs = append(s.msgs, &protocol.EntityErrorEvent{
+		EntityName: ei.GetName(),
+		// Clear Error fields except for Reason.
+		Error:        &protocol.Error{Reason: e.GetReason()},
+		FixturePhase: phase,
+	})
+	return nil
+}
+
 // EntityEnd implements output.Stream.
-func (s *Sink) EntityEnd(ei *protocol.Entity, skipReasons []string, timingLog *timing.Log) error {
+func (s *Sink) EntityEnd(ei *protocol.Entity, skip *protocol.Skip, consumedVars []string, timingLog *timing.Log) error {
 	// Drop timingLog.
-	var skip *protocol.Skip
-	if len(skipReasons) > 0 {
-		skip = &protocol.Skip{Reasons: skipReasons}
-	}
-	s.msgs = append(s.msgs, &protocol.EntityEndEvent{EntityName: ei.GetName(), Skip: skip})
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.msgs = append(s.msgs, &protocol.EntityEndEvent{EntityName: ei.GetName(), Skip: skip, ConsumedVars: consumedVars})
 	return nil
 }
 
@@ -91,5 +112,7 @@ func (s *Sink) StackOperation(ctx context.Context, req *protocol.StackOperationR
 
 // ReadAll reads all control messages written to the sink.
 func (s *Sink) ReadAll() []protocol.Event {
-	return s.msgs
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]protocol.Event(nil), s.msgs...)
 }
@@ -9,13 +9,15 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"os"
 	"path/filepath"
 	"runtime"
-	"runtime/pprof"
 	"sort"
 	"sync"
 	"time"
 
+	"golang.org/x/sync/errgroup"
+
 	"go.chromium.org/tast/core/errors"
 	"go.chromium.org/tast/core/internal/logging"
 	"go.chromium.org/tast/core/internal/planner/internal/entity"
@@ -44,6 +46,7 @@ func NewFixtureStack(cfg *Config, out OutputStream) *FixtureStack {
 const (
 	preTestTimeout  = 3 * time.Minute // timeout for RuntimeConfig.TestHook
 	postTestTimeout = 3 * time.Minute // timeout for a closure returned by RuntimeConfig.TestHook
+	cleanupTimeout  = 3 * time.Minute // default timeout for a function added by testing.State.AddCleanup
 
 	// DefaultGracePeriod is default recommended grace period for SafeCall.
 	DefaultGracePeriod = 30 * time.Second
@@ -100,6 +103,43 @@ type Config struct {
 
 	//MaxSysMsgLogSize is a size of flag for truncate log file.
 	MaxSysMsgLogSize int64
+
+	// MaxParallelTests is the maximum number of tests to run concurrently.
+	// Only tests depending on the same fixture and declaring disjoint sets of
+	// resources in testing.Test.Resources are run concurrently. If it is 1 or
+	// less, tests are run one by one.
+	MaxParallelTests int
+
+	// LogRateLimit is the maximum number of log lines per second each entity
+	// may report. Excess lines are replaced with a truncation marker. If it is
+	// 0 or less, logs are not rate limited.
+	LogRateLimit int
+	// LogBurst is the maximum number of log lines each entity may report at
+	// once without being limited by LogRateLimit.
+	LogBurst int
+	// DiscardDebugLogs specifies whether to discard debug logs reported by
+	// entities, e.g. with State.VLog.
+	DiscardDebugLogs bool
+	// ScopedLogLevels contains minimum levels of logs reported by tests
+	// matching patterns, as "<pattern>:<level>". They override
+	// DiscardDebugLogs for matching tests.
+	ScopedLogLevels []string
+
+	// Seed is the run-level seed from which random number generators of tests
+	// returned by State.Rand are derived.
+	Seed int64
+
+	// TestOrder lists names of tests in the order they should be run.
+	// Consecutive tests depending on the same fixture share a single fixture
+	// setup, but the fixture may be set up again later for tests ordered
+	// after tests depending on other fixtures. Tests not listed are run after
+	// listed ones. If it is empty, tests are grouped by fixtures to minimize
+	// fixture setups.
+	TestOrder []string
+
+	// RunMetadata contains run-level metadata, e.g. the name of the CI
+	// builder, available to tests via testing.ContextRunMetadata.
+	RunMetadata map[string]string
 }
 
 // GracePeriod returns grace period after entity timeout.
@@ -110,6 +150,26 @@ func (c *Config) GracePeriod() time.Duration {
 	return DefaultGracePeriod
 }
 
+// logLimit returns limits on logs reported by entities.
+func (c *Config) logLimit() (*output.LogLimit, error) {
+	lim := &output.LogLimit{
+		MinLevel: logging.LevelDebug,
+		Rate:     c.LogRateLimit,
+		Burst:    c.LogBurst,
+	}
+	if c.DiscardDebugLogs {
+		lim.MinLevel = logging.LevelInfo
+	}
+	for _, s := range c.ScopedLogLevels {
+		l, err := testing.ParseScopedLogLevel(s)
+		if err != nil {
+			return nil, err
+		}
+		lim.ScopedMinLevels = append(lim.ScopedMinLevels, l)
+	}
+	return lim, nil
+}
+
 // FixtureConfig returns a fixture config derived from c.
 func (c *Config) FixtureConfig() *fixture.Config {
 	// Features contains software/hardware features each DUT has, and runtime variables.
@@ -178,24 +238,29 @@ func RunTests(ctx context.Context, tests []*protocol.ResolvedEntity, out OutputS
 	if err != nil {
 		return err
 	}
-	return plan.run(ctx, out)
+	lim, err := pcfg.logLimit()
+	if err != nil {
+		return err
+	}
+	return plan.run(ctx, output.NewLimitedStream(out, lim))
 }
 
 // plan holds a top-level plan of test execution.
 type plan struct {
-	skips    []*skippedTest
-	fixtPlan *fixtPlan
-	prePlans []*prePlan
-	pcfg     *Config
+	skips     []*skippedTest
+	fixtPlans []*fixtPlan
+	prePlans  []*prePlan
+	pcfg      *Config
 }
 
 type skippedTest struct {
-	test    *testing.TestInstance
-	reasons []string
-	err     error
+	test *testing.TestInstance
+	skip *protocol.Skip
+	err  error
 }
 
 func buildPlan(tests []*protocol.ResolvedEntity, pcfg *Config) (*plan, error) {
+	order := newTestOrder(pcfg.TestOrder)
 	var testsWithFixture []*protocol.ResolvedEntity
 	preMap := make(map[string][]*testing.TestInstance)
 	var skips []*skippedTest
@@ -208,9 +273,12 @@ func buildPlan(tests []*protocol.ResolvedEntity, pcfg *Config) (*plan, error) {
 		if !ok {
 			return nil, fmt.Errorf("BUG: test %v does not exist", t.GetEntity().GetName())
 		}
-		reasons, err := ti.Deps().Check(pcfg.Features)
-		if err != nil || len(reasons) > 0 {
-			skips = append(skips, &skippedTest{test: ti, reasons: reasons, err: err})
+		skip, err := ti.Deps().CheckSkip(pcfg.Features)
+		if err == nil && skip == nil {
+			skip, err = checkEnvDeps(ti, pcfg)
+		}
+		if err != nil || skip != nil {
+			skips = append(skips, &skippedTest{test: ti, skip: skip, err: err})
 			continue
 		}
 		if ti.Pre != nil {
@@ -234,14 +302,93 @@ func buildPlan(tests []*protocol.ResolvedEntity, pcfg *Config) (*plan, error) {
 
 	prePlans := make([]*prePlan, len(preNames))
 	for i, preName := range preNames {
-		prePlans[i] = buildPrePlan(preMap[preName], pcfg)
+		prePlans[i] = buildPrePlan(preMap[preName], order, pcfg)
 	}
 
-	fixtPlan, err := buildFixtPlan(testsWithFixture, pcfg)
-	if err != nil {
-		return nil, err
+	var fixtPlans []*fixtPlan
+	for _, seg := range fixtSegments(testsWithFixture, order, pcfg) {
+		fp, err := buildFixtPlan(seg, order, pcfg)
+		if err != nil {
+			return nil, err
+		}
+		fixtPlans = append(fixtPlans, fp)
+	}
+	return &plan{skips, fixtPlans, prePlans, pcfg}, nil
+}
+
+// testOrder maps names of tests to their positions in Config.TestOrder.
+type testOrder map[string]int
+
+func newTestOrder(names []string) testOrder {
+	order := make(testOrder)
+	for i, name := range names {
+		if _, ok := order[name]; !ok {
+			order[name] = i
+		}
 	}
-	return &plan{skips, fixtPlan, prePlans, pcfg}, nil
+	return order
+}
+
+// less returns whether a test named a should be run before a test named b.
+// Tests not in the order are run after ones in it, in alphabetical order.
+func (o testOrder) less(a, b string) bool {
+	ia, aok := o[a]
+	ib, bok := o[b]
+	if aok != bok {
+		return aok
+	}
+	if aok && ia != ib {
+		return ia < ib
+	}
+	return a < b
+}
+
+// fixtSegments splits fixture-ready tests into segments to be planned
+// separately. If order is empty, all tests are in a single segment so that
+// tests are grouped by fixtures. Otherwise tests are sorted by order, and a
+// new segment is started whenever a test does not depend on the fixture of
+// the previous test directly or indirectly, since a fixture tree runs tests
+// of a fixture before ones of its descendants.
+func fixtSegments(tests []*protocol.ResolvedEntity, order testOrder, pcfg *Config) [][]*protocol.ResolvedEntity {
+	if len(order) == 0 {
+		return [][]*protocol.ResolvedEntity{tests}
+	}
+	tests = append([]*protocol.ResolvedEntity(nil), tests...)
+	sort.SliceStable(tests, func(i, j int) bool {
+		return order.less(tests[i].GetEntity().GetName(), tests[j].GetEntity().GetName())
+	})
+	var segs [][]*protocol.ResolvedEntity
+	for i, t := range tests {
+		if i == 0 || !dependsOnFixture(fixtTreeParent(t), fixtTreeParent(tests[i-1]), pcfg) {
+			segs = append(segs, nil)
+		}
+		segs[len(segs)-1] = append(segs[len(segs)-1], t)
+	}
+	return segs
+}
+
+// kernelCmdlinePath is the path of the file containing the kernel command line.
+var kernelCmdlinePath = "/proc/cmdline"
+
+// checkEnvDeps checks requirements of ti on the environment of the current
+// process. It returns machine-readable reasons for which ti should be skipped,
+// or nil if it should be run.
+func checkEnvDeps(ti *testing.TestInstance, pcfg *Config) (*protocol.Skip, error) {
+	d := ti.EnvDeps()
+	if d.Empty() || !pcfg.Features.GetCheckDeps() {
+		return nil, nil
+	}
+	if pcfg.RemoteData != nil {
+		return nil, errors.New("RequiresKernelCmdline and RequiresEnv are supported only by local tests")
+	}
+	var cmdline []byte
+	if len(d.KernelCmdline) > 0 {
+		var err error
+		if cmdline, err = os.ReadFile(kernelCmdlinePath); err != nil {
+			return nil, errors.Wrap(err, "failed to read kernel command line")
+		}
+	}
+	return d.CheckSkip(string(cmdline), os.Environ()), nil
 }
 
 func (p *plan) run(ctx context.Context, out output.Stream) error {
@@ -254,11 +401,13 @@ func (p *plan) run(ctx context.Context, out output.Stream) error {
 
 	for _, s := range p.skips {
 		tout := output.NewEntityStream(out, s.test.EntityProto())
-		reportSkippedTest(tout, s.reasons, s.err)
+		reportSkippedTest(tout, s.skip, s.err)
 	}
 
-	if err := p.fixtPlan.run(ctx, out, dl); err != nil {
-		return err
+	for _, fp := range p.fixtPlans {
+		if err := fp.run(ctx, out, dl); err != nil {
+			return err
+		}
 	}
 
 	for _, pp := range p.prePlans {
@@ -270,7 +419,10 @@ func (p *plan) run(ctx context.Context, out output.Stream) error {
 }
 
 func (p *plan) entitiesToRun() []*protocol.Entity {
-	var res = p.fixtPlan.entitiesToRun()
+	var res []*protocol.Entity
+	for _, fp := range p.fixtPlans {
+		res = append(res, fp.entitiesToRun()...)
+	}
 	for _, pp := range p.prePlans {
 		for _, t := range pp.testsToRun() {
 			res = append(res, t.EntityProto())
@@ -331,8 +483,9 @@ type fixtPlan struct {
 }
 
 // buildFixtPlan builds an execution plan of fixture-ready tests. Tests passed
-// to this function must not depend on preconditions.
-func buildFixtPlan(tests []*protocol.ResolvedEntity, pcfg *Config) (*fixtPlan, error) {
+// to this function must not depend on preconditions. Tests depending on the
+// same fixture are run in order.
+func buildFixtPlan(tests []*protocol.ResolvedEntity, order testOrder, pcfg *Config) (*fixtPlan, error) {
 	var orphans []*orphanTest
 	testsToRun := make(map[string][]*testing.TestInstance) // keyed by fixture
 	externalTestsToRun := make(map[string][]string)
@@ -388,11 +541,13 @@ func buildFixtPlan(tests []*protocol.ResolvedEntity, pcfg *Config) (*fixtPlan, e
 	})
 	for _, ts := range testsToRun {
 		sort.Slice(ts, func(i, j int) bool {
-			return ts[i].Name < ts[j].Name
+			return order.less(ts[i].Name, ts[j].Name)
 		})
 	}
 	for _, ts := range externalTestsToRun {
-		sort.Strings(ts)
+		sort.Slice(ts, func(i, j int) bool {
+			return order.less(ts[i], ts[j])
+		})
 	}
 	for _, children := range graph {
 		sort.Strings(children)
@@ -445,6 +600,24 @@ func buildFixtPlan(tests []*protocol.ResolvedEntity, pcfg *Config) (*fixtPlan, e
 	return &fixtPlan{pcfg: pcfg, tree: tree, orphans: orphans}, nil
 }
 
+// dependsOnFixture returns whether the fixture fixt is ancestor or its
+// descendant.
+func dependsOnFixture(fixt, ancestor string, pcfg *Config) bool {
+	for {
+		if fixt == ancestor {
+			return true
+		}
+		if fixt == pcfg.StartFixtureName {
+			return false
+		}
+		f, ok := pcfg.Fixtures[fixt]
+		if !ok {
+			return false
+		}
+		fixt = f.Parent
+	}
+}
+
 func fixtTreeParent(test *protocol.ResolvedEntity) string {
 	if test.Hops > 0 {
 		return test.StartFixtureName
@@ -531,10 +704,9 @@ func runFixtTree(ctx context.Context, tree *fixtTree, stack *internalOrCombinedS
 
 			// Run direct child tests first.
 			for stack.Status() != fixture.StatusYellow && len(tree.tests) > 0 {
-				t := tree.tests[0]
-				tree.tests = tree.tests[1:]
-				tout := output.NewEntityStream(out, t.EntityProto())
-				if err := runTest(ctx, t, tout, pcfg, &preConfig{}, stack, dl); err != nil {
+				var batch []*testing.TestInstance
+				batch, tree.tests = nextTestBatch(tree.tests, pcfg.MaxParallelTests)
+				if err := runTestBatch(ctx, batch, pcfg, stack, out, dl); err != nil {
 					return err
 				}
 				if !tree.Empty() {
@@ -593,6 +765,73 @@ func runFixtTree(ctx context.Context, tree *fixtTree, stack *internalOrCombinedS
 	return nil
 }
 
+// nextTestBatch picks a batch of tests to run concurrently from tests and
+// returns it with the remaining tests in the original order.
+//
+// The first test is always picked. Following tests are picked while the batch
+// has less than max tests if they declare resources disjoint from ones
+// declared by tests already picked. Tests declaring no resources are always
+// run alone.
+func nextTestBatch(tests []*testing.TestInstance, max int) (batch, rest []*testing.TestInstance) {
+	first := tests[0]
+	if max <= 1 || len(first.Resources) == 0 {
+		return tests[:1], tests[1:]
+	}
+
+	used := make(map[string]struct{})
+	for _, r := range first.Resources {
+		used[r] = struct{}{}
+	}
+	batch = []*testing.TestInstance{first}
+	for _, t := range tests[1:] {
+		if len(batch) < max && len(t.Resources) > 0 && !usesAny(t, used) {
+			for _, r := range t.Resources {
+				used[r] = struct{}{}
+			}
+			batch = append(batch, t)
+		} else {
+			rest = append(rest, t)
+		}
+	}
+	return batch, rest
+}
+
+// usesAny returns whether t declares any of resources.
+func usesAny(t *testing.TestInstance, resources map[string]struct{}) bool {
+	for _, r := range t.Resources {
+		if _, ok := resources[r]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// runTestBatch runs a batch of tests returned by nextTestBatch concurrently.
+// Tests in a batch share the fixture stack, which is marked dirty once for the
+// whole batch.
+func runTestBatch(ctx context.Context, batch []*testing.TestInstance, pcfg *Config, stack *internalOrCombinedStack, out output.Stream, dl *downloader) error {
+	if len(batch) == 1 {
+		t := batch[0]
+		tout := output.NewEntityStream(out, testEntityProto(t, pcfg))
+		return runTest(ctx, t, tout, pcfg, &preConfig{}, stack, dl)
+	}
+
+	if err := stack.MarkDirty(ctx); err != nil {
+		return err
+	}
+	shared := newSharedStack(stack)
+
+	var g errgroup.Group
+	for _, t := range batch {
+		t := t
+		g.Go(func() error {
+			tout := output.NewEntityStream(out, testEntityProto(t, pcfg))
+			return runTest(ctx, t, tout, pcfg, &preConfig{}, shared, dl)
+		})
+	}
+	return g.Wait()
+}
+
 // prePlan holds execution plan of tests using the same precondition.
 type prePlan struct {
 	pre   testing.Precondition
@@ -600,9 +839,9 @@ type prePlan struct {
 	pcfg  *Config
 }
 
-func buildPrePlan(tests []*testing.TestInstance, pcfg *Config) *prePlan {
+func buildPrePlan(tests []*testing.TestInstance, order testOrder, pcfg *Config) *prePlan {
 	sort.Slice(tests, func(i, j int) bool {
-		return tests[i].Name < tests[j].Name
+		return order.less(tests[i].Name, tests[j].Name)
 	})
 	return &prePlan{tests[0].Pre, tests, pcfg}
 }
@@ -626,7 +865,7 @@ func (p *prePlan) run(ctx context.Context, out output.Stream, dl *downloader) er
 	stack := &internalOrCombinedStack{internal: internalStack}
 
 	for i, t := range p.tests {
-		ti := t.EntityProto()
+		ti := testEntityProto(t, p.pcfg)
 		plog.SetCurrentTest(ti)
 		tout := output.NewEntityStream(out, ti)
 		precfg := &preConfig{
@@ -693,7 +932,7 @@ type preConfig struct {
 //
 // runTest runs a test on a goroutine. If a test does not finish after reaching
 // its timeout, this function returns with an error without waiting for its finish.
-func runTest(ctx context.Context, t *testing.TestInstance, tout *output.EntityStream, pcfg *Config, precfg *preConfig, stack *internalOrCombinedStack, dl *downloader) error {
+func runTest(ctx context.Context, t *testing.TestInstance, tout *output.EntityStream, pcfg *Config, precfg *preConfig, stack testStack, dl *downloader) error {
 	fixtCtx := ctx
 
 	// Attach a log that the test can use to report timing events.
@@ -732,13 +971,11 @@ func runTest(ctx context.Context, t *testing.TestInstance, tout *output.EntitySt
 		fixtCtx: fixtCtx,
 		// TODO(crbug.com/1106218): Make sure this approach is scalable.
 		// Recomputing purgeable on each test costs O(|purgeable| * |tests|) overall.
-		purgeable: dl.m.Purgeable(),
+		purgeable: dl.Purgeable(),
 	}
 	if err := runTestWithConfig(ctx, tcfg, pcfg, stack, precfg, tout); err != nil {
 		// If runTestWithRoot reported that the test didn't finish, print diagnostic messages.
-		msg := fmt.Sprintf("%v (see log for goroutine dump)", err)
-		tout.Error(testing.NewError(nil, msg, msg, 0))
-		dumpGoroutines(tout)
+		reportHang(tout, outDir, err)
 		return err
 	}
 
@@ -755,8 +992,10 @@ type testConfig struct {
 // runTestWithConfig runs a test on the given configs.
 //
 // The time allotted to the test is generally the sum of t.Timeout and t.ExitTimeout, but
-// additional time may be allotted for preconditions and pre/post-test hooks.
-func runTestWithConfig(ctx context.Context, tcfg *testConfig, pcfg *Config, stack *internalOrCombinedStack, precfg *preConfig, out testing.OutputStream) error {
+// additional time may be allotted for preconditions, pre/post-test hooks and cleanup
+// functions. The setup stage before the test function and the cleanup stage after it
+// are limited by t.SetupTimeout and t.CleanupTimeout respectively if they are set.
+func runTestWithConfig(ctx context.Context, tcfg *testConfig, pcfg *Config, stack testStack, precfg *preConfig, out *output.EntityStream) error {
 	// codeName is included in error messages if the user code ignores the timeout.
 	// For compatibility, the same fixed name is used for tests, preconditions and test hooks.
 	const codeName = "Test"
@@ -770,10 +1009,14 @@ func runTestWithConfig(ctx context.Context, tcfg *testConfig, pcfg *Config, stac
 	for role, dutFeatures := range pcfg.Features.GetCompanionFeatures() {
 		features[role] = dutFeatures
 	}
+	vars, err := testing.VarsForTest(tcfg.test.Name, pcfg.Features.GetInfra().GetVars(), pcfg.Features.GetInfra().GetScopedVars())
+	if err != nil {
+		return err
+	}
 	rcfg := &testing.RuntimeConfig{
 		DataDir:      filepath.Join(pcfg.Dirs.GetDataDir(), testing.RelativeDataDir(tcfg.test.Pkg)),
 		OutDir:       tcfg.outDir,
-		Vars:         pcfg.Features.GetInfra().GetVars(),
+		Vars:         vars,
 		Features:     features,
 		DUTLabConfig: pcfg.Features.GetInfra().GetDUTLabConfig(),
 		CloudStorage: testing.NewCloudStorage(
@@ -794,13 +1037,24 @@ func runTestWithConfig(ctx context.Context, tcfg *testConfig, pcfg *Config, stac
 		PreCtx:           precfg.ctx,
 		Purgeable:        tcfg.purgeable,
 		MaxSysMsgLogSize: pcfg.MaxSysMsgLogSize,
+		Seed:             pcfg.Seed,
+		RunMetadata:      pcfg.RunMetadata,
 	}
 	troot := testing.NewTestEntityRoot(tcfg.test, rcfg, out, condition)
+	// Report variables the test read even if it did not finish.
+	defer func() { out.SetConsumedVars(troot.ConsumedVars()) }()
 	ctx = troot.NewContext(ctx)
 	testState := troot.NewTestState()
 
+	// The setup stage consists of the pre-test function and the precondition
+	// preparation. The cleanup stage consists of the cleanup functions, the
+	// precondition closure and the post-test function, and starts when the test
+	// function returns or is skipped due to setup errors.
+	setupDeadline := stageDeadline(tcfg.test.SetupTimeout)
+	var cleanupDeadline time.Time
+
 	// First, perform setup and run the pre-test function.
-	if err := usercode.SafeCall(ctx, codeName, preTestTimeout, pcfg.GracePeriod(), usercode.ErrorOnPanic(testState), func(ctx context.Context) {
+	if err := usercode.SafeCall(ctx, codeName, stageTimeout(setupDeadline, preTestTimeout), pcfg.GracePeriod(), usercode.ErrorOnPanic(testState), func(ctx context.Context) {
 		// The test bundle is responsible for ensuring t.Timeout is nonzero before calling Run,
 		// but we call s.Fatal instead of panicking since it's arguably nicer to report individual
 		// test failures instead of aborting the entire run.
@@ -835,7 +1089,7 @@ func runTestWithConfig(ctx context.Context, tcfg *testConfig, pcfg *Config, stac
 	// Prepare the test's precondition (if any) if setup was successful.
 	if !condition.HasError() && tcfg.test.Pre != nil {
 		preState := troot.NewPreState()
-		if err := usercode.SafeCall(ctx, codeName, tcfg.test.Pre.Timeout(), pcfg.GracePeriod(), usercode.ErrorOnPanic(preState), func(ctx context.Context) {
+		if err := usercode.SafeCall(ctx, codeName, stageTimeout(setupDeadline, tcfg.test.Pre.Timeout()), pcfg.GracePeriod(), usercode.ErrorOnPanic(preState), func(ctx context.Context) {
 			preState.Logf("Preparing precondition %q", tcfg.test.Pre)
 			troot.SetPreValue(tcfg.test.Pre.Prepare(ctx, preState))
 		}); err != nil {
@@ -854,10 +1108,24 @@ func runTestWithConfig(ctx context.Context, tcfg *testConfig, pcfg *Config, stac
 		}
 
 		if !condition.HasError() {
+			// Capture the screen on failure if the test opts in.
+			capture := startFailureCapture(ctx, tcfg.test, testState, tcfg.outDir, pcfg, out)
+
 			// Run the test function itself.
 			if err := usercode.SafeCall(ctx, codeName, tcfg.test.Timeout, timeoutOrDefault(tcfg.test.ExitTimeout, pcfg.GracePeriod()), usercode.ErrorOnPanic(testState), func(ctx context.Context) {
 				tcfg.test.Func(ctx, testState)
 			}); err != nil {
+				capture.finish(true)
+				return err
+			}
+			capture.finish(condition.HasError())
+		}
+
+		cleanupDeadline = stageDeadline(tcfg.test.CleanupTimeout)
+
+		// Run cleanup functions added by the test.
+		for _, f := range troot.TakeCleanups() {
+			if err := usercode.SafeCall(ctx, codeName, stageTimeout(cleanupDeadline, cleanupTimeout), pcfg.GracePeriod(), usercode.ErrorOnPanic(testState), f); err != nil {
 				return err
 			}
 		}
@@ -875,7 +1143,7 @@ func runTestWithConfig(ctx context.Context, tcfg *testConfig, pcfg *Config, stac
 	// (even if setup, tcfg.test.Pre.Prepare, or tcfg.test.Func failed).
 	if precfg.close {
 		preState := troot.NewPreState()
-		if err := usercode.SafeCall(ctx, codeName, tcfg.test.Pre.Timeout(), pcfg.GracePeriod(), usercode.ErrorOnPanic(preState), func(ctx context.Context) {
+		if err := usercode.SafeCall(ctx, codeName, stageTimeout(cleanupDeadline, tcfg.test.Pre.Timeout()), pcfg.GracePeriod(), usercode.ErrorOnPanic(preState), func(ctx context.Context) {
 			preState.Logf("Closing precondition %q", tcfg.test.Pre.String())
 			tcfg.test.Pre.Close(ctx, preState)
 		}); err != nil {
@@ -885,16 +1153,55 @@ func runTestWithConfig(ctx context.Context, tcfg *testConfig, pcfg *Config, stac
 
 	// Finally, run the post-test functions unconditionally.
 	if postTestFunc != nil {
-		if err := usercode.SafeCall(ctx, codeName, postTestTimeout, pcfg.GracePeriod(), usercode.ErrorOnPanic(testState), func(ctx context.Context) {
+		if err := usercode.SafeCall(ctx, codeName, stageTimeout(cleanupDeadline, postTestTimeout), pcfg.GracePeriod(), usercode.ErrorOnPanic(testState), func(ctx context.Context) {
 			postTestFunc(ctx, troot.NewTestHookState())
 		}); err != nil {
 			return err
 		}
 	}
 
+	// Remove temporary directories allocated by the test. Leaked directories
+	// are reported as warnings rather than errors since the test itself ran
+	// successfully.
+	for _, err := range troot.CleanUpTempDirs() {
+		out.Log(logging.LevelInfo, time.Now(), fmt.Sprintf("Warning: Leaked temporary directory: %v", err))
+	}
+
 	return nil
 }
 
+// testEntityProto returns t as protocol.Entity to be reported on running it.
+// Unlike t.EntityProto, it also records soft software dependencies available
+// on the DUT, and the seed and the metadata of the run.
+func testEntityProto(t *testing.TestInstance, pcfg *Config) *protocol.Entity {
+	ei := t.EntityProto()
+	ei.LegacyData.AvailableSoftSoftwareDeps = t.AvailableSoftSoftwareDeps(pcfg.Features.GetDut().GetSoftware())
+	ei.LegacyData.Seed = pcfg.Seed
+	ei.LegacyData.RunMetadata = protocol.MapToStringPairs(pcfg.RunMetadata)
+	return ei
+}
+
+// stageDeadline returns the deadline of a stage of running a test starting
+// now and limited by timeout. It returns the zero time if timeout is zero.
+func stageDeadline(timeout time.Duration) time.Time {
+	if timeout <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(timeout)
+}
+
+// stageTimeout returns the timeout of a step in a stage ending at deadline,
+// which is def or the time remaining in the stage if it is shorter.
+func stageTimeout(deadline time.Time, def time.Duration) time.Duration {
+	if deadline.IsZero() {
+		return def
+	}
+	if remaining := time.Until(deadline); remaining < def {
+		return remaining
+	}
+	return def
+}
+
 // timeoutOrDefault returns timeout if positive or def otherwise.
 func timeoutOrDefault(timeout, def time.Duration) time.Duration {
 	if timeout > 0 {
@@ -920,10 +1227,10 @@ func reportOrphanTest(tout *output.EntityStream, missingFixtName string) {
 
 // reportSkippedTest is called instead of runTest for a test that is skipped due to
 // having unsatisfied dependencies.
-func reportSkippedTest(tout *output.EntityStream, reasons []string, err error) {
+func reportSkippedTest(tout *output.EntityStream, skip *protocol.Skip, err error) {
 	tout.Start("")
 	if err == nil {
-		tout.End(reasons, timing.NewLog())
+		tout.End(skip, timing.NewLog())
 		return
 	}
 
@@ -942,19 +1249,32 @@ func reportSkippedTest(tout *output.EntityStream, reasons []string, err error) {
 	tout.End(nil, timing.NewLog())
 }
 
+// reportHang reports err returned when a test did not finish. A goroutine
+// dump is saved to outDir and its trimmed version is attached to the error.
+// If outDir is empty, the goroutine dump is written to the log instead.
+func reportHang(tout *output.EntityStream, outDir string, err error) {
+	if outDir != "" {
+		msg := fmt.Sprintf("%v (see %s for goroutine dump)", err, testing.GoroutineDumpFile)
+		e := testing.NewError(nil, msg, msg, 1)
+		if err := testing.AttachGoroutineDump(e, outDir, msg); err == nil {
+			tout.Error(e)
+			return
+		}
+	}
+	msg := fmt.Sprintf("%v (see log for goroutine dump)", err)
+	tout.Error(testing.NewError(nil, msg, msg, 1))
+	dumpGoroutines(tout)
+}
+
 // dumpGoroutines dumps all goroutines to tout.
 func dumpGoroutines(tout *output.EntityStream) {
 	tout.Log(logging.LevelInfo, time.Now(), "Dumping all goroutines")
 	if err := func() error {
-		p := pprof.Lookup("goroutine")
-		if p == nil {
-			return errors.New("goroutine pprof not found")
-		}
-		var buf bytes.Buffer
-		if err := p.WriteTo(&buf, 2); err != nil {
+		dump, err := testing.DumpGoroutines()
+		if err != nil {
 			return err
 		}
-		sc := bufio.NewScanner(&buf)
+		sc := bufio.NewScanner(bytes.NewReader(dump))
 		for sc.Scan() {
 			tout.Log(logging.LevelInfo, time.Now(), sc.Text())
 		}
@@ -16,6 +16,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"sync"
 	gotesting "testing"
 	"time"
@@ -29,6 +30,7 @@ import (
 	"go.chromium.org/tast/core/internal/logging"
 	"go.chromium.org/tast/core/internal/planner/internal/output/outputtest"
 	"go.chromium.org/tast/core/internal/protocol"
+	"go.chromium.org/tast/core/internal/screencapturetest"
 	"go.chromium.org/tast/core/internal/testcontext"
 	"go.chromium.org/tast/core/internal/testing"
 	"go.chromium.org/tast/core/internal/testing/testfixture"
@@ -141,6 +143,95 @@ func TestRunDeadline(t *gotesting.T) {
 	}
 }
 
+func TestRunCleanup(t *gotesting.T) {
+	tests := []*testing.TestInstance{{
+		Name: "pkg.Test",
+		Func: func(ctx context.Context, s *testing.State) {
+			s.AddCleanup(func(ctx context.Context) {
+				if _, ok := ctx.Deadline(); !ok {
+					s.Error("Cleanup 1 has no deadline")
+				}
+				s.Log("Cleanup 1")
+			})
+			s.AddCleanup(func(ctx context.Context) {
+				s.Log("Cleanup 2")
+			})
+			// Use up the test's time; cleanups should still run.
+			<-ctx.Done()
+			s.Error("Saw timeout within test")
+		},
+		Timeout:        time.Millisecond,
+		CleanupTimeout: time.Minute,
+	}}
+	gracePeriod := 10 * time.Second
+	msgs := runTestsAndReadAll(t, tests, &Config{CustomGracePeriod: &gracePeriod})
+	want := []protocol.Event{
+		&protocol.EntityStartEvent{Entity: tests[0].EntityProto()},
+		&protocol.EntityErrorEvent{EntityName: "pkg.Test", Error: &protocol.Error{Reason: "Saw timeout within test"}},
+		&protocol.EntityLogEvent{EntityName: "pkg.Test", Text: "Cleanup 2", Level: protocol.LogLevel_INFO},
+		&protocol.EntityLogEvent{EntityName: "pkg.Test", Text: "Cleanup 1", Level: protocol.LogLevel_INFO},
+		&protocol.EntityEndEvent{EntityName: "pkg.Test"},
+	}
+	if diff := cmp.Diff(msgs, want, protocmp.Transform()); diff != "" {
+		t.Error("Output mismatch (-got +want):\n", diff)
+	}
+}
+
+func TestRunCleanupTimeout(t *gotesting.T) {
+	tests := []*testing.TestInstance{{
+		Name: "pkg.Test",
+		Func: func(ctx context.Context, s *testing.State) {
+			s.AddCleanup(func(ctx context.Context) {
+				<-ctx.Done()
+				s.Error("Saw timeout within cleanup")
+			})
+		},
+		Timeout:        time.Minute,
+		CleanupTimeout: time.Millisecond,
+	}}
+	gracePeriod := 10 * time.Second
+	msgs := runTestsAndReadAll(t, tests, &Config{CustomGracePeriod: &gracePeriod})
+	want := []protocol.Event{
+		&protocol.EntityStartEvent{Entity: tests[0].EntityProto()},
+		&protocol.EntityErrorEvent{EntityName: "pkg.Test", Error: &protocol.Error{Reason: "Saw timeout within cleanup"}},
+		&protocol.EntityEndEvent{EntityName: "pkg.Test"},
+	}
+	if diff := cmp.Diff(msgs, want, protocmp.Transform()); diff != "" {
+		t.Error("Output mismatch (-got +want):\n", diff)
+	}
+}
+
+func TestRunSetupTimeout(t *gotesting.T) {
+	tests := []*testing.TestInstance{{
+		Name: "pkg.Test",
+		Func: func(ctx context.Context, s *testing.State) {
+			if _, ok := ctx.Deadline(); !ok {
+				s.Error("Test has no deadline")
+			}
+		},
+		Timeout:      time.Minute,
+		SetupTimeout: time.Millisecond,
+	}}
+	gracePeriod := 10 * time.Second
+	cfg := &Config{
+		CustomGracePeriod: &gracePeriod,
+		TestHook: func(ctx context.Context, s *testing.TestHookState) func(context.Context, *testing.TestHookState) {
+			<-ctx.Done()
+			s.Error("Saw timeout within setup")
+			return nil
+		},
+	}
+	msgs := runTestsAndReadAll(t, tests, cfg)
+	want := []protocol.Event{
+		&protocol.EntityStartEvent{Entity: tests[0].EntityProto()},
+		&protocol.EntityErrorEvent{EntityName: "pkg.Test", Error: &protocol.Error{Reason: "Saw timeout within setup"}},
+		&protocol.EntityEndEvent{EntityName: "pkg.Test"},
+	}
+	if diff := cmp.Diff(msgs, want, protocmp.Transform()); diff != "" {
+		t.Error("Output mismatch (-got +want):\n", diff)
+	}
+}
+
 func TestRunLogAfterTimeout(t *gotesting.T) {
 	cont := make(chan bool)
 	done := make(chan bool)
@@ -183,6 +274,42 @@ func TestRunLogAfterTimeout(t *gotesting.T) {
 	}
 }
 
+func TestRunGoroutineDumpOnTimeout(t *gotesting.T) {
+	cont := make(chan struct{})
+	defer close(cont)
+	tests := []*testing.TestInstance{{
+		Name: "pkg.Test",
+		Func: func(ctx context.Context, s *testing.State) {
+			// Ignore the deadline and wait until we're told to continue.
+			<-cont
+		},
+		Timeout: time.Millisecond,
+	}}
+
+	od := testutil.TempDir(t)
+	defer os.RemoveAll(od)
+
+	gracePeriod := time.Millisecond
+	msgs := runTestsAndReadAll(t, tests, &Config{CustomGracePeriod: &gracePeriod, Dirs: &protocol.RunDirectories{OutDir: od}})
+
+	// An error is written and a goroutine dump is saved to the output directory.
+	want := []protocol.Event{
+		&protocol.EntityStartEvent{Entity: tests[0].EntityProto(), OutDir: filepath.Join(od, "pkg.Test")},
+		&protocol.EntityErrorEvent{EntityName: "pkg.Test", Error: &protocol.Error{Reason: "Test did not return on timeout (see goroutines.txt for goroutine dump)"}},
+		&protocol.EntityEndEvent{EntityName: "pkg.Test"},
+	}
+	if diff := cmp.Diff(msgs, want, protocmp.Transform()); diff != "" {
+		t.Error("Output mismatch (-got +want):\n", diff)
+	}
+	b, err := os.ReadFile(filepath.Join(od, "pkg.Test", testing.GoroutineDumpFile))
+	if err != nil {
+		t.Fatal("Failed to read goroutine dump: ", err)
+	}
+	if dump := string(b); !strings.Contains(dump, "Test did not return on timeout") || !strings.Contains(dump, "goroutine ") {
+		t.Errorf("Unexpected goroutine dump:\n%s", dump)
+	}
+}
+
 func TestRunLateWriteFromGoroutine(t *gotesting.T) {
 	// Run a test that calls s.Log from a goroutine after the test has finished.
 	start := make(chan struct{}) // tells goroutine to start
@@ -244,7 +371,7 @@ func TestRunSoftwareDeps(t *gotesting.T) {
 
 	want := []protocol.Event{
 		&protocol.EntityStartEvent{Entity: test2.EntityProto()},
-		&protocol.EntityEndEvent{EntityName: test2.Name, Skip: &protocol.Skip{Reasons: []string{"missing SoftwareDeps: missing"}}},
+		&protocol.EntityEndEvent{EntityName: test2.Name, Skip: &protocol.Skip{Reasons: []string{"missing SoftwareDeps: missing"}, TypedReasons: []*protocol.SkipReason{{Code: protocol.SkipReason_UNSATISFIED_SWDEP, Details: []string{"missing SoftwareDeps: missing"}}}}},
 		&protocol.EntityStartEvent{Entity: test3.EntityProto()},
 		&protocol.EntityErrorEvent{EntityName: test3.Name, Error: &protocol.Error{Reason: "unknown SoftwareDeps: unreg"}},
 		&protocol.EntityEndEvent{EntityName: test3.Name},
@@ -256,6 +383,84 @@ func TestRunSoftwareDeps(t *gotesting.T) {
 	}
 }
 
+func TestRunSoftSoftwareDeps(t *gotesting.T) {
+	var got []bool
+	tests := []*testing.TestInstance{{
+		Name:             "pkg.Test",
+		SoftSoftwareDeps: []string{"yes", "no"},
+		Func: func(ctx context.Context, s *testing.State) {
+			got = append(got, s.HasSoftwareFeature("yes"), s.HasSoftwareFeature("no"))
+		},
+		Timeout: time.Minute,
+	}}
+
+	cfg := &Config{
+		Features: &protocol.Features{
+			CheckDeps: true,
+			Dut: &frameworkprotocol.DUTFeatures{
+				Software: &frameworkprotocol.SoftwareFeatures{
+					Available:   []string{"yes"},
+					Unavailable: []string{"no"},
+				},
+			},
+		},
+	}
+
+	msgs := runTestsAndReadAll(t, tests, cfg)
+
+	// Available features are recorded in EntityStartEvent.
+	ei := tests[0].EntityProto()
+	ei.LegacyData.AvailableSoftSoftwareDeps = []string{"yes"}
+	want := []protocol.Event{
+		&protocol.EntityStartEvent{Entity: ei},
+		&protocol.EntityEndEvent{EntityName: tests[0].Name},
+	}
+	if diff := cmp.Diff(msgs, want, protocmp.Transform()); diff != "" {
+		t.Error("Output mismatch (-got +want):\n", diff)
+	}
+	if diff := cmp.Diff(got, []bool{true, false}); diff != "" {
+		t.Error("HasSoftwareFeature mismatch (-got +want):\n", diff)
+	}
+}
+
+func TestRunEnvDeps(t *gotesting.T) {
+	cmdlinePath := filepath.Join(t.TempDir(), "cmdline")
+	if err := os.WriteFile(cmdlinePath, []byte("cros_debug console=ttyS0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer func(orig string) { kernelCmdlinePath = orig }(kernelCmdlinePath)
+	kernelCmdlinePath = cmdlinePath
+	t.Setenv("TAST_PLANNER_TEST_ENV", "1")
+
+	nopFunc := func(context.Context, *testing.State) {}
+	test1 := &testing.TestInstance{Name: "pkg.Test1", RequiresKernelCmdline: []string{"cros_debug"}, RequiresEnv: []string{"TAST_PLANNER_TEST_ENV=1"}, Func: nopFunc, Timeout: time.Minute}
+	test2 := &testing.TestInstance{Name: "pkg.Test2", RequiresKernelCmdline: []string{"console=tty1"}, Func: nopFunc, Timeout: time.Minute}
+	test3 := &testing.TestInstance{Name: "pkg.Test3", RequiresEnv: []string{"TAST_PLANNER_TEST_MISSING"}, Func: nopFunc, Timeout: time.Minute}
+	tests := []*testing.TestInstance{test1, test2, test3}
+
+	cfg := &Config{
+		Features: &protocol.Features{CheckDeps: true},
+	}
+
+	msgs := runTestsAndReadAll(t, tests, cfg)
+
+	const (
+		cmdlineReason = "missing kernel command line parameters: console=tty1"
+		envReason     = "missing environment variables: TAST_PLANNER_TEST_MISSING"
+	)
+	want := []protocol.Event{
+		&protocol.EntityStartEvent{Entity: test2.EntityProto()},
+		&protocol.EntityEndEvent{EntityName: test2.Name, Skip: &protocol.Skip{Reasons: []string{cmdlineReason}, TypedReasons: []*protocol.SkipReason{{Code: protocol.SkipReason_UNSATISFIED_KERNEL_CMDLINE, Details: []string{cmdlineReason}}}}},
+		&protocol.EntityStartEvent{Entity: test3.EntityProto()},
+		&protocol.EntityEndEvent{EntityName: test3.Name, Skip: &protocol.Skip{Reasons: []string{envReason}, TypedReasons: []*protocol.SkipReason{{Code: protocol.SkipReason_UNSATISFIED_ENV, Details: []string{envReason}}}}},
+		&protocol.EntityStartEvent{Entity: test1.EntityProto()},
+		&protocol.EntityEndEvent{EntityName: test1.Name},
+	}
+	if diff := cmp.Diff(msgs, want, protocmp.Transform()); diff != "" {
+		t.Error("Output mismatch (-got +want):\n", diff)
+	}
+}
+
 func TestRunVarDeps(t *gotesting.T) {
 	tmpDir := testutil.TempDir(t)
 	defer os.RemoveAll(tmpDir)
@@ -814,7 +1019,7 @@ func TestRunExternalData(t *gotesting.T) {
 
 			want := []protocol.Event{
 				&protocol.EntityStartEvent{Entity: tests[0].EntityProto()},
-				&protocol.EntityEndEvent{EntityName: tests[0].Name, Skip: &protocol.Skip{Reasons: []string{"missing SoftwareDeps: dep1"}}},
+				&protocol.EntityEndEvent{EntityName: tests[0].Name, Skip: &protocol.Skip{Reasons: []string{"missing SoftwareDeps: dep1"}, TypedReasons: []*protocol.SkipReason{{Code: protocol.SkipReason_UNSATISFIED_SWDEP, Details: []string{"missing SoftwareDeps: dep1"}}}}},
 				&protocol.EntityStartEvent{Entity: fixt.EntityProto()},
 				&protocol.EntityStartEvent{Entity: tests[1].EntityProto()},
 				&protocol.EntityEndEvent{EntityName: tests[1].Name},
@@ -824,7 +1029,7 @@ func TestRunExternalData(t *gotesting.T) {
 				&protocol.EntityEndEvent{EntityName: fixt.Name},
 
 				&protocol.EntityStartEvent{Entity: fixt2.EntityProto()},
-				&protocol.EntityErrorEvent{EntityName: fixt2.Name, Error: &protocol.Error{Reason: "Required data file fail.txt missing: failed to download gs://bucket/fail.txt: file does not exist"}},
+				&protocol.EntityErrorEvent{EntityName: fixt2.Name, Error: &protocol.Error{Reason: "Required data file fail.txt missing: failed to download gs://bucket/fail.txt: file does not exist"}, FixturePhase: protocol.FixturePhase_SET_UP},
 				&protocol.EntityEndEvent{EntityName: fixt2.Name},
 				&protocol.EntityStartEvent{Entity: tests[3].EntityProto()},
 				&protocol.EntityErrorEvent{EntityName: tests[3].Name, Error: &protocol.Error{Reason: "[Fixture failure] fixt2: Required data file fail.txt missing: failed to download gs://bucket/fail.txt: file does not exist"}},
@@ -1273,8 +1478,8 @@ func TestRunFixtureSetUpFailure(t *gotesting.T) {
 		&protocol.EntityEndEvent{EntityName: tests[0].Name},
 		// fixt1 fails to set up.
 		&protocol.EntityStartEvent{Entity: fixt1.EntityProto()},
-		&protocol.EntityErrorEvent{EntityName: fixt1.Name, Error: &protocol.Error{Reason: "Setup failure 1"}},
-		&protocol.EntityErrorEvent{EntityName: fixt1.Name, Error: &protocol.Error{Reason: "Setup failure 2"}},
+		&protocol.EntityErrorEvent{EntityName: fixt1.Name, Error: &protocol.Error{Reason: "Setup failure 1"}, FixturePhase: protocol.FixturePhase_SET_UP},
+		&protocol.EntityErrorEvent{EntityName: fixt1.Name, Error: &protocol.Error{Reason: "Setup failure 2"}, FixturePhase: protocol.FixturePhase_SET_UP},
 		&protocol.EntityEndEvent{EntityName: fixt1.Name},
 		// All tests depending on fixt1 fail.
 		&protocol.EntityStartEvent{Entity: tests[1].EntityProto()},
@@ -2108,6 +2313,115 @@ func TestAttachStateToContext(t *gotesting.T) {
 	}
 }
 
+func TestRunTempDir(t *gotesting.T) {
+	td := testutil.TempDir(t)
+	defer os.RemoveAll(td)
+	t.Setenv("TMPDIR", td)
+
+	var dirs []string
+	tests := []*testing.TestInstance{{
+		Name: "pkg.Test",
+		Func: func(ctx context.Context, s *testing.State) {
+			dirs = append(dirs, s.TempDir())
+			dir, err := testcontext.TempDir(ctx)
+			if err != nil {
+				s.Fatal("TempDir failed: ", err)
+			}
+			dirs = append(dirs, dir)
+			// Make the directory hard to remove.
+			if err := os.Mkdir(filepath.Join(dir, "sub"), 0755); err != nil {
+				s.Fatal("Mkdir failed: ", err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, "sub", "file"), nil, 0644); err != nil {
+				s.Fatal("WriteFile failed: ", err)
+			}
+			if err := os.Chmod(filepath.Join(dir, "sub"), 0500); err != nil {
+				s.Fatal("Chmod failed: ", err)
+			}
+		},
+		Timeout: time.Minute,
+	}}
+
+	msgs := runTestsAndReadAll(t, tests, &Config{})
+
+	want := []protocol.Event{
+		&protocol.EntityStartEvent{Entity: tests[0].EntityProto()},
+		&protocol.EntityEndEvent{EntityName: tests[0].Name},
+	}
+	if diff := cmp.Diff(msgs, want, protocmp.Transform()); diff != "" {
+		t.Error("Output mismatch (-got +want):\n", diff)
+	}
+
+	if len(dirs) != 2 || dirs[0] == dirs[1] {
+		t.Fatalf("Temporary directories = %q; want 2 distinct directories", dirs)
+	}
+	for _, dir := range dirs {
+		if filepath.Dir(dir) != td || !strings.HasPrefix(filepath.Base(dir), "pkg.Test.") {
+			t.Errorf("Temporary directory %s is not pkg.Test.* in %s", dir, td)
+		}
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			t.Errorf("Temporary directory %s was not removed: %v", dir, err)
+		}
+	}
+}
+
+func TestRunScreenCaptureOnFailure(t *gotesting.T) {
+	td := testutil.TempDir(t)
+	defer os.RemoveAll(td)
+	od := filepath.Join(td, "out")
+
+	screencapturetest.InstallFakeScreenshot(t)
+
+	privateAttr := []string{testing.ScreenshotOnFailurePrivateAttr, testing.ScreenRecordOnFailurePrivateAttr}
+	tests := []*testing.TestInstance{
+		{
+			Name: "pkg.Fatal",
+			Func: func(ctx context.Context, s *testing.State) {
+				path := filepath.Join(s.OutDir(), "screenshot_on_failure.png")
+				defer func() {
+					// The screenshot should have been taken before the test
+					// function cleans up.
+					if _, err := os.Stat(path); err != nil {
+						t.Errorf("Screenshot was not taken on the fatal error: %v", err)
+					}
+				}()
+				s.Fatal("Failed")
+			},
+			PrivateAttr: privateAttr,
+			Timeout:     time.Minute,
+		},
+		{
+			Name:        "pkg.Pass",
+			Func:        func(ctx context.Context, s *testing.State) {},
+			PrivateAttr: privateAttr,
+			Timeout:     time.Minute,
+		},
+		{
+			Name:    "pkg.NoCapture",
+			Func:    func(ctx context.Context, s *testing.State) { s.Error("Failed") },
+			Timeout: time.Minute,
+		},
+	}
+	runTestsAndReadAll(t, tests, &Config{Dirs: &protocol.RunDirectories{OutDir: od}})
+
+	for _, tc := range []struct {
+		path string
+		want bool
+	}{
+		{"pkg.Fatal/screenshot_on_failure.png", true},
+		{"pkg.Fatal/screenrecord_on_failure.gif", true},
+		{"pkg.Pass/screenshot_on_failure.png", false},
+		{"pkg.Pass/screenrecord_on_failure.gif", false},
+		{"pkg.NoCapture/screenshot_on_failure.png", false},
+		{"pkg.NoCapture/screenrecord_on_failure.gif", false},
+	} {
+		_, err := os.Stat(filepath.Join(od, tc.path))
+		if got := err == nil; got != tc.want {
+			t.Errorf("%s exists: got %v, want %v", tc.path, got, tc.want)
+		}
+	}
+}
+
 func TestRunPlan(t *gotesting.T) {
 	pre1 := &testPre{name: "pre1"}
 	pre2 := &testPre{name: "pre2"}
@@ -2221,6 +2535,68 @@ func TestRunPlan(t *gotesting.T) {
 	}
 }
 
+func TestRunPlanTestOrder(t *gotesting.T) {
+	pre1 := &testPre{name: "pre1"}
+	fixt1 := &testing.FixtureInstance{Name: "fixt1", Impl: testfixture.New()}
+	fixt2 := &testing.FixtureInstance{Name: "fixt2", Impl: testfixture.New(), Parent: "fixt1"}
+	cfg := &Config{
+		Fixtures: map[string]*testing.FixtureInstance{
+			fixt1.Name: fixt1,
+			fixt2.Name: fixt2,
+		},
+		TestOrder: []string{
+			"pkg.Test5",
+			"pkg.Test2",
+			"pkg.Test6",
+			"pkg.Test1",
+			"pkg.Test4",
+			"pkg.Test9",
+			"pkg.Test8",
+			"pkg.Test7",
+		},
+	}
+	tests := []*testing.TestInstance{
+		{Name: "pkg.Test1", Fixture: "fixt2"},
+		{Name: "pkg.Test2", Fixture: "fixt1"},
+		{Name: "pkg.Test3"},
+		{Name: "pkg.Test4"},
+		{Name: "pkg.Test5", Fixture: "fixt1"},
+		{Name: "pkg.Test6", Fixture: "fixt2"},
+		{Name: "pkg.Test7", Pre: pre1},
+		{Name: "pkg.Test8", Pre: pre1},
+		{Name: "pkg.Test9", Fixture: "fixt1"},
+	}
+	wantOrder := []string{
+		// Consecutive tests depending on the same fixture share a setup.
+		"fixt1",
+		"pkg.Test5",
+		"pkg.Test2",
+		"fixt2",
+		"pkg.Test6",
+		"pkg.Test1",
+		"pkg.Test4",
+		// fixt1 is set up again for tests ordered after tests not depending on it.
+		"fixt1",
+		"pkg.Test9",
+		// Tests not in the order come last.
+		"pkg.Test3",
+		// Tests depending on preconditions are still run after others.
+		"pkg.Test8",
+		"pkg.Test7",
+	}
+
+	msgs := runTestsAndReadAll(t, tests, cfg)
+	var order []string
+	for _, msg := range msgs {
+		if msg, ok := msg.(*protocol.EntityStartEvent); ok {
+			order = append(order, msg.GetEntity().GetName())
+		}
+	}
+	if diff := cmp.Diff(order, wantOrder); diff != "" {
+		t.Error("Test order mismatch (-got +want):\n", diff)
+	}
+}
+
 type fixtureSteps string
 
 const (
@@ -2404,6 +2780,12 @@ func TestRunDisabled(t *gotesting.T) {
 				Reasons: []string{
 					fmt.Sprintf("Test %s is disabled by test filter file filter.txt", tests[1].Name),
 				},
+				TypedReasons: []*protocol.SkipReason{{
+					Code: protocol.SkipReason_MANUAL,
+					Details: []string{
+						fmt.Sprintf("Test %s is disabled by test filter file filter.txt", tests[1].Name),
+					},
+				}},
 			},
 		},
 		&protocol.EntityStartEvent{Entity: tests[0].EntityProto(), OutDir: filepath.Join(od, "pkg.Test")},
@@ -2419,3 +2801,208 @@ func TestRunDisabled(t *gotesting.T) {
 		t.Errorf("Out dir %v has mode 0%o; want 0%o", od, mode, 0777|os.ModeSticky)
 	}
 }
+
+func TestNextTestBatch(t *gotesting.T) {
+	newTest := func(name string, resources ...string) *testing.TestInstance {
+		return &testing.TestInstance{Name: name, Resources: resources}
+	}
+	names := func(ts []*testing.TestInstance) []string {
+		var res []string
+		for _, t := range ts {
+			res = append(res, t.Name)
+		}
+		return res
+	}
+
+	tests := []*testing.TestInstance{
+		newTest("pkg.Audio", "audio"),
+		newTest("pkg.AudioNetwork", "audio", "network"),
+		newTest("pkg.GPU", "gpu"),
+		newTest("pkg.None"),
+		newTest("pkg.Network", "network"),
+		newTest("pkg.Camera", "camera"),
+	}
+
+	for _, tc := range []struct {
+		name      string
+		tests     []*testing.TestInstance
+		max       int
+		wantBatch []string
+		wantRest  []string
+	}{
+		{
+			name:      "sequential",
+			tests:     tests,
+			max:       1,
+			wantBatch: []string{"pkg.Audio"},
+			wantRest:  []string{"pkg.AudioNetwork", "pkg.GPU", "pkg.None", "pkg.Network", "pkg.Camera"},
+		},
+		{
+			name:      "disjoint",
+			tests:     tests,
+			max:       10,
+			wantBatch: []string{"pkg.Audio", "pkg.GPU", "pkg.Network", "pkg.Camera"},
+			wantRest:  []string{"pkg.AudioNetwork", "pkg.None"},
+		},
+		{
+			name:      "limited",
+			tests:     tests,
+			max:       2,
+			wantBatch: []string{"pkg.Audio", "pkg.GPU"},
+			wantRest:  []string{"pkg.AudioNetwork", "pkg.None", "pkg.Network", "pkg.Camera"},
+		},
+		{
+			name:      "no resources",
+			tests:     tests[3:],
+			max:       10,
+			wantBatch: []string{"pkg.None"},
+			wantRest:  []string{"pkg.Network", "pkg.Camera"},
+		},
+	} {
+		t.Run(tc.name, func(t *gotesting.T) {
+			batch, rest := nextTestBatch(tc.tests, tc.max)
+			if diff := cmp.Diff(names(batch), tc.wantBatch); diff != "" {
+				t.Errorf("Batch mismatch (-got +want):\n%s", diff)
+			}
+			if diff := cmp.Diff(names(rest), tc.wantRest); diff != "" {
+				t.Errorf("Rest mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRunParallelResources(t *gotesting.T) {
+	td := testutil.TempDir(t)
+	defer os.RemoveAll(td)
+	od := filepath.Join(td, "out")
+
+	var mu sync.Mutex
+	running := make(map[string]bool)
+	var overlaps [][]string
+
+	// enter records that the named test started, and returns a function to
+	// record that it finished.
+	enter := func(name string) (exit func()) {
+		mu.Lock()
+		defer mu.Unlock()
+		for other := range running {
+			overlaps = append(overlaps, []string{other, name})
+		}
+		running[name] = true
+		return func() {
+			mu.Lock()
+			defer mu.Unlock()
+			delete(running, name)
+		}
+	}
+
+	// pkg.Audio and pkg.GPU wait for each other to start so that the test
+	// would time out if they were not run concurrently.
+	audioStarted := make(chan struct{})
+	gpuStarted := make(chan struct{})
+	rendezvous := func(s *testing.State, self, other chan struct{}) {
+		close(self)
+		select {
+		case <-other:
+		case <-time.After(10 * time.Second):
+			s.Error("Timed out waiting for a concurrent test")
+		}
+	}
+
+	tests := []*testing.TestInstance{
+		{
+			Name:      "pkg.Audio",
+			Resources: []string{"audio"},
+			Func: func(ctx context.Context, s *testing.State) {
+				defer enter(s.TestName())()
+				rendezvous(s, audioStarted, gpuStarted)
+			},
+			Timeout: time.Minute,
+		},
+		{
+			Name:      "pkg.AudioNetwork",
+			Resources: []string{"audio", "network"},
+			Func: func(ctx context.Context, s *testing.State) {
+				defer enter(s.TestName())()
+			},
+			Timeout: time.Minute,
+		},
+		{
+			Name:      "pkg.GPU",
+			Resources: []string{"gpu"},
+			Func: func(ctx context.Context, s *testing.State) {
+				defer enter(s.TestName())()
+				rendezvous(s, gpuStarted, audioStarted)
+			},
+			Timeout: time.Minute,
+		},
+		{
+			Name: "pkg.None",
+			Func: func(ctx context.Context, s *testing.State) {
+				defer enter(s.TestName())()
+			},
+			Timeout: time.Minute,
+		},
+	}
+
+	msgs := runTestsAndReadAll(t, tests, &Config{
+		Dirs:             &protocol.RunDirectories{OutDir: od},
+		MaxParallelTests: 4,
+	})
+
+	for _, msg := range msgs {
+		if e, ok := msg.(*protocol.EntityErrorEvent); ok {
+			t.Errorf("%s: %s", e.GetEntityName(), e.GetError().GetReason())
+		}
+	}
+	want := [][]string{{"pkg.Audio", "pkg.GPU"}}
+	if overlaps != nil && overlaps[0][0] == "pkg.GPU" {
+		want = [][]string{{"pkg.GPU", "pkg.Audio"}}
+	}
+	if diff := cmp.Diff(overlaps, want); diff != "" {
+		t.Errorf("Tests ran concurrently mismatch (-got +want):\n%s", diff)
+	}
+}
+
+func TestRunLogLimit(t *gotesting.T) {
+	td := testutil.TempDir(t)
+	defer os.RemoveAll(td)
+	od := filepath.Join(td, "out")
+
+	tests := []*testing.TestInstance{{
+		Name: "pkg.Test",
+		Func: func(ctx context.Context, s *testing.State) {
+			s.VLog("verbose")
+			for i := 0; i < 5; i++ {
+				s.Log("chatty")
+			}
+		},
+		Timeout: time.Minute,
+	}, {
+		Name:    "pkg.Verbose",
+		Func:    func(ctx context.Context, s *testing.State) { s.VLog("verbose") },
+		Timeout: time.Minute,
+	}}
+
+	msgs := runTestsAndReadAll(t, tests, &Config{
+		Dirs:             &protocol.RunDirectories{OutDir: od},
+		LogRateLimit:     1,
+		LogBurst:         2,
+		DiscardDebugLogs: true,
+		ScopedLogLevels:  []string{"pkg.Verb*:debug"},
+	})
+
+	want := []protocol.Event{
+		&protocol.EntityStartEvent{Entity: tests[0].EntityProto(), OutDir: filepath.Join(od, "pkg.Test")},
+		&protocol.EntityLogEvent{EntityName: "pkg.Test", Text: "chatty", Level: protocol.LogLevel_INFO},
+		&protocol.EntityLogEvent{EntityName: "pkg.Test", Text: "chatty", Level: protocol.LogLevel_INFO},
+		&protocol.EntityLogEvent{EntityName: "pkg.Test", Text: "[3 log line(s) truncated: exceeded the limit of 1 lines per second]", Level: protocol.LogLevel_INFO},
+		&protocol.EntityEndEvent{EntityName: tests[0].Name},
+		&protocol.EntityStartEvent{Entity: tests[1].EntityProto(), OutDir: filepath.Join(od, "pkg.Verbose")},
+		&protocol.EntityLogEvent{EntityName: "pkg.Verbose", Text: "verbose", Level: protocol.LogLevel_DEBUG},
+		&protocol.EntityEndEvent{EntityName: tests[1].Name},
+	}
+	if diff := cmp.Diff(msgs, want, protocmp.Transform()); diff != "" {
+		t.Error("Output mismatch (-got +want):\n", diff)
+	}
+}
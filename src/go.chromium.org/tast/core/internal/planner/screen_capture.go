@@ -0,0 +1,130 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package planner
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.chromium.org/tast/core/internal/logging"
+	"go.chromium.org/tast/core/internal/planner/internal/output"
+	"go.chromium.org/tast/core/internal/testing"
+	"go.chromium.org/tast/core/screencapture"
+)
+
+const (
+	failureScreenshotName    = "screenshot_on_failure.png"
+	failureScreenRecordName  = "screenrecord_on_failure.gif"
+	failureScreenRecordRate  = time.Second // interval between frames of recordings
+	failureScreenRecordLimit = 30          // number of the last frames kept in recordings
+	failureScreenshotTimeout = 30 * time.Second
+)
+
+// failureCapture captures the DUT's screen for a test opting in with private
+// attributes, so that the screen on failure can be inspected later.
+//
+// The screen is captured when the test reports its first error, before the
+// test function returns and cleans up the screen, e.g. by closing windows.
+// Capturing the screen is best-effort; errors are logged to the test log
+// rather than reported as test errors.
+type failureCapture struct {
+	ctx        context.Context
+	outDir     string
+	out        *output.EntityStream
+	screenshot bool
+	rec        *screencapture.Recorder
+
+	once   sync.Once
+	failed bool // true if the screen was captured on failure; set in once
+}
+
+// startFailureCapture starts capturing the screen for t if it opts in, and
+// attaches error handlers to s to capture the screen on its first error. It
+// returns nil if t does not opt in or runs in a remote test bundle, where the
+// screen of the DUT is not available.
+func startFailureCapture(ctx context.Context, t *testing.TestInstance, s *testing.State, outDir string, pcfg *Config, out *output.EntityStream) *failureCapture {
+	if pcfg.RemoteData != nil {
+		return nil
+	}
+	c := &failureCapture{ctx: ctx, outDir: outDir, out: out}
+	record := false
+	for _, a := range t.PrivateAttr {
+		switch a {
+		case testing.ScreenshotOnFailurePrivateAttr:
+			c.screenshot = true
+		case testing.ScreenRecordOnFailurePrivateAttr:
+			record = true
+		}
+	}
+	if !c.screenshot && !record {
+		return nil
+	}
+	if record {
+		rec, err := screencapture.StartRecording(ctx, failureScreenRecordRate, failureScreenRecordLimit)
+		if err != nil {
+			c.logf("Failed to start recording the screen: %v", err)
+		} else {
+			c.rec = rec
+		}
+	}
+	onError := func(string) { c.capture() }
+	s.AttachErrorHandlers(onError, onError)
+	return c
+}
+
+// capture takes a screenshot and stops the recording on the first call. It is
+// called synchronously from error handlers so that the screen is captured
+// before a fatal error ends the test function.
+func (c *failureCapture) capture() {
+	c.once.Do(func() {
+		c.failed = true
+		if c.rec != nil {
+			if _, err := c.rec.Stop(); err != nil {
+				c.logf("Failed to record the screen: %v", err)
+			}
+		}
+		if !c.screenshot {
+			return
+		}
+		ctx, cancel := context.WithTimeout(c.ctx, failureScreenshotTimeout)
+		defer cancel()
+		if err := screencapture.Screenshot(ctx, filepath.Join(c.outDir, failureScreenshotName)); err != nil {
+			c.logf("Failed to take a screenshot on failure: %v", err)
+		} else {
+			c.logf("Saved a screenshot on failure to %s", failureScreenshotName)
+		}
+	})
+}
+
+// finish saves the recording to the output directory if the test failed. The
+// screen is captured now if failed is true but no error handler captured it,
+// e.g. if the test timed out.
+func (c *failureCapture) finish(failed bool) {
+	if c == nil {
+		return
+	}
+	if failed {
+		c.capture()
+	}
+	if c.rec == nil {
+		return
+	}
+	defer c.rec.Close()
+	if !c.failed {
+		return
+	}
+	if err := c.rec.Save(filepath.Join(c.outDir, failureScreenRecordName)); err != nil {
+		c.logf("Failed to save the screen recording on failure: %v", err)
+		return
+	}
+	c.logf("Saved the screen recording on failure to %s", failureScreenRecordName)
+}
+
+func (c *failureCapture) logf(format string, args ...interface{}) {
+	c.out.Log(logging.LevelInfo, time.Now(), fmt.Sprintf(format, args...))
+}
@@ -6,12 +6,25 @@ package planner
 
 import (
 	"context"
+	"sync"
 
 	"go.chromium.org/tast/core/internal/planner/internal/fixture"
 	"go.chromium.org/tast/core/internal/protocol"
 	"go.chromium.org/tast/core/internal/testing"
 )
 
+// testStack is a fixture stack on which a test runs.
+type testStack interface {
+	Status() fixture.Status
+	Errors() []*protocol.Error
+	Val() interface{}
+	SerializedVal(ctx context.Context) ([]byte, error)
+	PreTest(ctx context.Context, test *protocol.Entity, outDir string, out testing.OutputStream, condition *testing.EntityCondition) (func(ctx context.Context) error, error)
+	MarkDirty(ctx context.Context) error
+}
+
+var _ testStack = &internalOrCombinedStack{}
+
 type internalOrCombinedStack struct {
 	internal *fixture.InternalStack
 	combined *fixture.CombinedStack
@@ -79,3 +92,61 @@ func (s *internalOrCombinedStack) MarkDirty(ctx context.Context) error {
 	}
 	return s.combined.SetDirty(ctx, true)
 }
+
+// sharedStack is a testStack shared by tests running concurrently.
+//
+// The underlying stack must be marked dirty before running tests, and
+// MarkDirty does nothing. Calls to fixture methods are serialized so that
+// fixtures do not have to care about concurrency.
+type sharedStack struct {
+	mu    sync.Mutex
+	stack *internalOrCombinedStack
+}
+
+var _ testStack = &sharedStack{}
+
+func newSharedStack(stack *internalOrCombinedStack) *sharedStack {
+	return &sharedStack{stack: stack}
+}
+
+func (s *sharedStack) Status() fixture.Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stack.Status()
+}
+
+func (s *sharedStack) Errors() []*protocol.Error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stack.Errors()
+}
+
+func (s *sharedStack) Val() interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stack.Val()
+}
+
+func (s *sharedStack) SerializedVal(ctx context.Context) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stack.SerializedVal(ctx)
+}
+
+func (s *sharedStack) PreTest(ctx context.Context, test *protocol.Entity, outDir string, out testing.OutputStream, condition *testing.EntityCondition) (func(ctx context.Context) error, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	postTest, err := s.stack.PreTest(ctx, test, outDir, out, condition)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context) error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return postTest(ctx)
+	}, nil
+}
+
+func (s *sharedStack) MarkDirty(ctx context.Context) error {
+	return nil
+}
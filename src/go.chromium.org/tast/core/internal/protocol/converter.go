@@ -5,6 +5,8 @@
 package protocol
 
 import (
+	"sort"
+
 	"go.chromium.org/tast/core/internal/logging"
 )
 
@@ -32,3 +34,27 @@ func ProtoToLevel(level LogLevel) logging.Level {
 		return logging.LevelInfo
 	}
 }
+
+// MapToStringPairs converts a map to a list of StringPair sorted by keys.
+func MapToStringPairs(m map[string]string) []*StringPair {
+	var ps []*StringPair
+	for k, v := range m {
+		ps = append(ps, &StringPair{Key: k, Value: v})
+	}
+	sort.Slice(ps, func(i, j int) bool { return ps[i].Key < ps[j].Key })
+	return ps
+}
+
+// StringPairsToMap converts a list of StringPair to a map. Later pairs take
+// precedence over earlier ones with the same key. It returns nil if ps is
+// empty.
+func StringPairsToMap(ps []*StringPair) map[string]string {
+	if len(ps) == 0 {
+		return nil
+	}
+	m := make(map[string]string)
+	for _, p := range ps {
+		m[p.GetKey()] = p.GetValue()
+	}
+	return m
+}
@@ -36,6 +36,12 @@ type HandshakeRequest struct {
 	NeedUserServices bool              `protobuf:"varint,1,opt,name=need_user_services,json=needUserServices,proto3" json:"need_user_services,omitempty"`
 	BundleInitParams *BundleInitParams `protobuf:"bytes,2,opt,name=bundle_init_params,json=bundleInitParams,proto3" json:"bundle_init_params,omitempty"`
 	RunnerInitParams *RunnerInitParams `protobuf:"bytes,3,opt,name=runner_init_params,json=runnerInitParams,proto3" json:"runner_init_params,omitempty"`
+	// Whether the client accepts messages sent from the server after the
+	// handshake to be compressed with zstd.
+	AcceptZstdCompression bool `protobuf:"varint,4,opt,name=accept_zstd_compression,json=acceptZstdCompression,proto3" json:"accept_zstd_compression,omitempty"`
+	// Maximum size in bytes of gRPC messages the server sends and receives.
+	// The default size is used if it is zero.
+	MaxMessageSize int64 `protobuf:"varint,5,opt,name=max_message_size,json=maxMessageSize,proto3" json:"max_message_size,omitempty"`
 }
 
 func (x *HandshakeRequest) Reset() {
@@ -91,6 +97,20 @@ func (x *HandshakeRequest) GetRunnerInitParams() *RunnerInitParams {
 	return nil
 }
 
+func (x *HandshakeRequest) GetAcceptZstdCompression() bool {
+	if x != nil {
+		return x.AcceptZstdCompression
+	}
+	return false
+}
+
+func (x *HandshakeRequest) GetMaxMessageSize() int64 {
+	if x != nil {
+		return x.MaxMessageSize
+	}
+	return 0
+}
+
 // HandshakeResponse is a response to an HandshakeRequest message.
 // The message is sent in a raw format since gRPC connection is not ready before
 // handshake.
@@ -101,6 +121,9 @@ type HandshakeResponse struct {
 
 	// Set if an error occurred.
 	Error *HandshakeError `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	// Whether messages sent from the server after this response are compressed
+	// with zstd. It is set only if the client accepts it.
+	ZstdCompression bool `protobuf:"varint,2,opt,name=zstd_compression,json=zstdCompression,proto3" json:"zstd_compression,omitempty"`
 }
 
 func (x *HandshakeResponse) Reset() {
@@ -142,6 +165,13 @@ func (x *HandshakeResponse) GetError() *HandshakeError {
 	return nil
 }
 
+func (x *HandshakeResponse) GetZstdCompression() bool {
+	if x != nil {
+		return x.ZstdCompression
+	}
+	return false
+}
+
 // HandshakeError describes a failed handshake result.
 type HandshakeError struct {
 	state         protoimpl.MessageState
@@ -257,6 +287,13 @@ type RunnerInitParams struct {
 	// A file path glob that matches test bundle executables.
 	// Example: "/usr/local/libexec/tast/bundles/local/*"
 	BundleGlob string `protobuf:"bytes,1,opt,name=bundle_glob,json=bundleGlob,proto3" json:"bundle_glob,omitempty"`
+	// Power management policy applied by the test runner around test runs.
+	// It is set only for local test runners.
+	PowerPolicy *PowerPolicy `protobuf:"bytes,2,opt,name=power_policy,json=powerPolicy,proto3" json:"power_policy,omitempty"`
+	// Actions taken by the test runner to quiesce the DUT before running
+	// tests. They are reverted after running tests. It is set only for local
+	// test runners.
+	QuiescePolicy *QuiescePolicy `protobuf:"bytes,3,opt,name=quiesce_policy,json=quiescePolicy,proto3" json:"quiesce_policy,omitempty"`
 }
 
 func (x *RunnerInitParams) Reset() {
@@ -298,6 +335,101 @@ func (x *RunnerInitParams) GetBundleGlob() string {
 	return ""
 }
 
+func (x *RunnerInitParams) GetPowerPolicy() *PowerPolicy {
+	if x != nil {
+		return x.PowerPolicy
+	}
+	return nil
+}
+
+func (x *RunnerInitParams) GetQuiescePolicy() *QuiescePolicy {
+	if x != nil {
+		return x.QuiescePolicy
+	}
+	return nil
+}
+
+// PowerPolicy describes how a test runner manages power of the DUT around
+// test runs.
+type PowerPolicy struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Shell commands to run on the DUT before running tests, e.g. to force
+	// battery discharge with ectool.
+	PreRunCommands []string `protobuf:"bytes,1,rep,name=pre_run_commands,json=preRunCommands,proto3" json:"pre_run_commands,omitempty"`
+	// Shell commands to run on the DUT after running tests, e.g. to restore
+	// charging.
+	PostRunCommands []string `protobuf:"bytes,2,rep,name=post_run_commands,json=postRunCommands,proto3" json:"post_run_commands,omitempty"`
+	// Minimum battery level in percent required to start power-sensitive
+	// tests. Power-sensitive tests are skipped if the battery level is below
+	// it. 0 disables the check.
+	MinBatteryPercent int32 `protobuf:"varint,3,opt,name=min_battery_percent,json=minBatteryPercent,proto3" json:"min_battery_percent,omitempty"`
+	// Attributes identifying power-sensitive tests, e.g. "graphics_power".
+	SensitiveAttributes []string `protobuf:"bytes,4,rep,name=sensitive_attributes,json=sensitiveAttributes,proto3" json:"sensitive_attributes,omitempty"`
+}
+
+func (x *PowerPolicy) Reset() {
+	*x = PowerPolicy{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_handshake_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PowerPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PowerPolicy) ProtoMessage() {}
+
+func (x *PowerPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_handshake_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PowerPolicy.ProtoReflect.Descriptor instead.
+func (*PowerPolicy) Descriptor() ([]byte, []int) {
+	return file_handshake_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PowerPolicy) GetPreRunCommands() []string {
+	if x != nil {
+		return x.PreRunCommands
+	}
+	return nil
+}
+
+func (x *PowerPolicy) GetPostRunCommands() []string {
+	if x != nil {
+		return x.PostRunCommands
+	}
+	return nil
+}
+
+func (x *PowerPolicy) GetMinBatteryPercent() int32 {
+	if x != nil {
+		return x.MinBatteryPercent
+	}
+	return 0
+}
+
+func (x *PowerPolicy) GetSensitiveAttributes() []string {
+	if x != nil {
+		return x.SensitiveAttributes
+	}
+	return nil
+}
+
 type BundleConfig struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -307,12 +439,18 @@ type BundleConfig struct {
 	PrimaryTarget  *TargetDevice         `protobuf:"bytes,1,opt,name=primary_target,json=primaryTarget,proto3" json:"primary_target,omitempty"`
 	CompanionDuts  map[string]*DUTConfig `protobuf:"bytes,2,rep,name=companion_duts,json=companionDuts,proto3" json:"companion_duts,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 	MetaTestConfig *MetaTestConfig       `protobuf:"bytes,3,opt,name=meta_test_config,json=metaTestConfig,proto3" json:"meta_test_config,omitempty"`
+	// ServoSpec is the address of servod for the primary DUT as "host:port".
+	// It is empty if servo is unavailable.
+	ServoSpec string `protobuf:"bytes,4,opt,name=servo_spec,json=servoSpec,proto3" json:"servo_spec,omitempty"`
+	// Phones maps roles of Android phones attached to the run to their adb
+	// addresses as "host:port".
+	Phones map[string]string `protobuf:"bytes,5,rep,name=phones,proto3" json:"phones,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
 func (x *BundleConfig) Reset() {
 	*x = BundleConfig{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_handshake_proto_msgTypes[5]
+		mi := &file_handshake_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -325,7 +463,7 @@ func (x *BundleConfig) String() string {
 func (*BundleConfig) ProtoMessage() {}
 
 func (x *BundleConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_handshake_proto_msgTypes[5]
+	mi := &file_handshake_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -338,7 +476,7 @@ func (x *BundleConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BundleConfig.ProtoReflect.Descriptor instead.
 func (*BundleConfig) Descriptor() ([]byte, []int) {
-	return file_handshake_proto_rawDescGZIP(), []int{5}
+	return file_handshake_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *BundleConfig) GetPrimaryTarget() *TargetDevice {
@@ -362,6 +500,20 @@ func (x *BundleConfig) GetMetaTestConfig() *MetaTestConfig {
 	return nil
 }
 
+func (x *BundleConfig) GetServoSpec() string {
+	if x != nil {
+		return x.ServoSpec
+	}
+	return ""
+}
+
+func (x *BundleConfig) GetPhones() map[string]string {
+	if x != nil {
+		return x.Phones
+	}
+	return nil
+}
+
 // TargetDevice represents a local bundle on which remote tests invoke services.
 type TargetDevice struct {
 	state         protoimpl.MessageState
@@ -377,7 +529,7 @@ type TargetDevice struct {
 func (x *TargetDevice) Reset() {
 	*x = TargetDevice{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_handshake_proto_msgTypes[6]
+		mi := &file_handshake_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -390,7 +542,7 @@ func (x *TargetDevice) String() string {
 func (*TargetDevice) ProtoMessage() {}
 
 func (x *TargetDevice) ProtoReflect() protoreflect.Message {
-	mi := &file_handshake_proto_msgTypes[6]
+	mi := &file_handshake_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -403,7 +555,7 @@ func (x *TargetDevice) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TargetDevice.ProtoReflect.Descriptor instead.
 func (*TargetDevice) Descriptor() ([]byte, []int) {
-	return file_handshake_proto_rawDescGZIP(), []int{6}
+	return file_handshake_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *TargetDevice) GetDutConfig() *DUTConfig {
@@ -436,7 +588,7 @@ type DUTConfig struct {
 func (x *DUTConfig) Reset() {
 	*x = DUTConfig{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_handshake_proto_msgTypes[7]
+		mi := &file_handshake_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -449,7 +601,7 @@ func (x *DUTConfig) String() string {
 func (*DUTConfig) ProtoMessage() {}
 
 func (x *DUTConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_handshake_proto_msgTypes[7]
+	mi := &file_handshake_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -462,7 +614,7 @@ func (x *DUTConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DUTConfig.ProtoReflect.Descriptor instead.
 func (*DUTConfig) Descriptor() ([]byte, []int) {
-	return file_handshake_proto_rawDescGZIP(), []int{7}
+	return file_handshake_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *DUTConfig) GetSshConfig() *SSHConfig {
@@ -499,7 +651,7 @@ type SSHConfig struct {
 func (x *SSHConfig) Reset() {
 	*x = SSHConfig{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_handshake_proto_msgTypes[8]
+		mi := &file_handshake_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -512,7 +664,7 @@ func (x *SSHConfig) String() string {
 func (*SSHConfig) ProtoMessage() {}
 
 func (x *SSHConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_handshake_proto_msgTypes[8]
+	mi := &file_handshake_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -525,7 +677,7 @@ func (x *SSHConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SSHConfig.ProtoReflect.Descriptor instead.
 func (*SSHConfig) Descriptor() ([]byte, []int) {
-	return file_handshake_proto_rawDescGZIP(), []int{8}
+	return file_handshake_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *SSHConfig) GetConnectionSpec() string {
@@ -581,7 +733,7 @@ type MetaTestConfig struct {
 func (x *MetaTestConfig) Reset() {
 	*x = MetaTestConfig{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_handshake_proto_msgTypes[9]
+		mi := &file_handshake_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -594,7 +746,7 @@ func (x *MetaTestConfig) String() string {
 func (*MetaTestConfig) ProtoMessage() {}
 
 func (x *MetaTestConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_handshake_proto_msgTypes[9]
+	mi := &file_handshake_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -607,7 +759,7 @@ func (x *MetaTestConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MetaTestConfig.ProtoReflect.Descriptor instead.
 func (*MetaTestConfig) Descriptor() ([]byte, []int) {
-	return file_handshake_proto_rawDescGZIP(), []int{9}
+	return file_handshake_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *MetaTestConfig) GetTastPath() string {
@@ -631,11 +783,90 @@ func (x *MetaTestConfig) GetListFlags() []string {
 	return nil
 }
 
+// QuiescePolicy describes actions a test runner takes to quiesce the DUT
+// before running tests, e.g. to reduce noise in performance tests.
+type QuiescePolicy struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Whether to stop update_engine while running tests.
+	StopUpdateEngine bool `protobuf:"varint,1,opt,name=stop_update_engine,json=stopUpdateEngine,proto3" json:"stop_update_engine,omitempty"`
+	// Whether to disable background ARC provisioning tasks, e.g. app sync and
+	// Play auto install, while running tests.
+	DisableArcProvisioning bool `protobuf:"varint,2,opt,name=disable_arc_provisioning,json=disableArcProvisioning,proto3" json:"disable_arc_provisioning,omitempty"`
+	// CPU frequency governor to pin all CPUs to while running tests, e.g.
+	// "performance". Governors are left unchanged if it is empty.
+	CpuGovernor string `protobuf:"bytes,3,opt,name=cpu_governor,json=cpuGovernor,proto3" json:"cpu_governor,omitempty"`
+	// Whether to clear crash spool directories before running tests.
+	ClearCrashSpool bool `protobuf:"varint,4,opt,name=clear_crash_spool,json=clearCrashSpool,proto3" json:"clear_crash_spool,omitempty"`
+}
+
+func (x *QuiescePolicy) Reset() {
+	*x = QuiescePolicy{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_handshake_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *QuiescePolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuiescePolicy) ProtoMessage() {}
+
+func (x *QuiescePolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_handshake_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuiescePolicy.ProtoReflect.Descriptor instead.
+func (*QuiescePolicy) Descriptor() ([]byte, []int) {
+	return file_handshake_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *QuiescePolicy) GetStopUpdateEngine() bool {
+	if x != nil {
+		return x.StopUpdateEngine
+	}
+	return false
+}
+
+func (x *QuiescePolicy) GetDisableArcProvisioning() bool {
+	if x != nil {
+		return x.DisableArcProvisioning
+	}
+	return false
+}
+
+func (x *QuiescePolicy) GetCpuGovernor() string {
+	if x != nil {
+		return x.CpuGovernor
+	}
+	return ""
+}
+
+func (x *QuiescePolicy) GetClearCrashSpool() bool {
+	if x != nil {
+		return x.ClearCrashSpool
+	}
+	return false
+}
+
 var File_handshake_proto protoreflect.FileDescriptor
 
 var file_handshake_proto_rawDesc = []byte{
 	0x0a, 0x0f, 0x68, 0x61, 0x6e, 0x64, 0x73, 0x68, 0x61, 0x6b, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x12, 0x09, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x22, 0xd6, 0x01, 0x0a,
+	0x6f, 0x12, 0x09, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x22, 0xb8, 0x02, 0x0a,
 	0x10, 0x48, 0x61, 0x6e, 0x64, 0x73, 0x68, 0x61, 0x6b, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
 	0x74, 0x12, 0x2c, 0x0a, 0x12, 0x6e, 0x65, 0x65, 0x64, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x73,
 	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x6e,
@@ -649,81 +880,132 @@ var file_handshake_proto_rawDesc = []byte{
 	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f,
 	0x72, 0x65, 0x2e, 0x52, 0x75, 0x6e, 0x6e, 0x65, 0x72, 0x49, 0x6e, 0x69, 0x74, 0x50, 0x61, 0x72,
 	0x61, 0x6d, 0x73, 0x52, 0x10, 0x72, 0x75, 0x6e, 0x6e, 0x65, 0x72, 0x49, 0x6e, 0x69, 0x74, 0x50,
-	0x61, 0x72, 0x61, 0x6d, 0x73, 0x22, 0x44, 0x0a, 0x11, 0x48, 0x61, 0x6e, 0x64, 0x73, 0x68, 0x61,
-	0x6b, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2f, 0x0a, 0x05, 0x65, 0x72,
-	0x72, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x74, 0x61, 0x73, 0x74,
-	0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x48, 0x61, 0x6e, 0x64, 0x73, 0x68, 0x61, 0x6b, 0x65, 0x45,
-	0x72, 0x72, 0x6f, 0x72, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x28, 0x0a, 0x0e, 0x48,
-	0x61, 0x6e, 0x64, 0x73, 0x68, 0x61, 0x6b, 0x65, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x16, 0x0a,
-	0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72,
-	0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22, 0xc4, 0x01, 0x0a, 0x10, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65,
-	0x49, 0x6e, 0x69, 0x74, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x12, 0x39, 0x0a, 0x04, 0x76, 0x61,
-	0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e,
-	0x63, 0x6f, 0x72, 0x65, 0x2e, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x49, 0x6e, 0x69, 0x74, 0x50,
-	0x61, 0x72, 0x61, 0x6d, 0x73, 0x2e, 0x56, 0x61, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
-	0x04, 0x76, 0x61, 0x72, 0x73, 0x12, 0x3c, 0x0a, 0x0d, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x5f,
-	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x74,
+	0x61, 0x72, 0x61, 0x6d, 0x73, 0x12, 0x36, 0x0a, 0x17, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x5f,
+	0x7a, 0x73, 0x74, 0x64, 0x5f, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x15, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x5a, 0x73,
+	0x74, 0x64, 0x43, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x28, 0x0a,
+	0x10, 0x6d, 0x61, 0x78, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x5f, 0x73, 0x69, 0x7a,
+	0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x6d, 0x61, 0x78, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x22, 0x6f, 0x0a, 0x11, 0x48, 0x61, 0x6e, 0x64, 0x73,
+	0x68, 0x61, 0x6b, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2f, 0x0a, 0x05,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x74, 0x61,
+	0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x48, 0x61, 0x6e, 0x64, 0x73, 0x68, 0x61, 0x6b,
+	0x65, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x29, 0x0a,
+	0x10, 0x7a, 0x73, 0x74, 0x64, 0x5f, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x7a, 0x73, 0x74, 0x64, 0x43, 0x6f, 0x6d,
+	0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x28, 0x0a, 0x0e, 0x48, 0x61, 0x6e, 0x64,
+	0x73, 0x68, 0x61, 0x6b, 0x65, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65,
+	0x61, 0x73, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73,
+	0x6f, 0x6e, 0x22, 0xc4, 0x01, 0x0a, 0x10, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x49, 0x6e, 0x69,
+	0x74, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x12, 0x39, 0x0a, 0x04, 0x76, 0x61, 0x72, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72,
+	0x65, 0x2e, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x49, 0x6e, 0x69, 0x74, 0x50, 0x61, 0x72, 0x61,
+	0x6d, 0x73, 0x2e, 0x56, 0x61, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x04, 0x76, 0x61,
+	0x72, 0x73, 0x12, 0x3c, 0x0a, 0x0d, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x5f, 0x63, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x74, 0x61, 0x73, 0x74,
+	0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x52, 0x0c, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x1a, 0x37, 0x0a, 0x09, 0x56, 0x61, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xaf, 0x01, 0x0a, 0x10, 0x52, 0x75,
+	0x6e, 0x6e, 0x65, 0x72, 0x49, 0x6e, 0x69, 0x74, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x12, 0x1f,
+	0x0a, 0x0b, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x5f, 0x67, 0x6c, 0x6f, 0x62, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x47, 0x6c, 0x6f, 0x62, 0x12,
+	0x39, 0x0a, 0x0c, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72,
+	0x65, 0x2e, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x0b, 0x70,
+	0x6f, 0x77, 0x65, 0x72, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x3f, 0x0a, 0x0e, 0x71, 0x75,
+	0x69, 0x65, 0x73, 0x63, 0x65, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x51,
+	0x75, 0x69, 0x65, 0x73, 0x63, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x0d, 0x71, 0x75,
+	0x69, 0x65, 0x73, 0x63, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x22, 0xc6, 0x01, 0x0a, 0x0b,
+	0x50, 0x6f, 0x77, 0x65, 0x72, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x28, 0x0a, 0x10, 0x70,
+	0x72, 0x65, 0x5f, 0x72, 0x75, 0x6e, 0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x70, 0x72, 0x65, 0x52, 0x75, 0x6e, 0x43, 0x6f, 0x6d,
+	0x6d, 0x61, 0x6e, 0x64, 0x73, 0x12, 0x2a, 0x0a, 0x11, 0x70, 0x6f, 0x73, 0x74, 0x5f, 0x72, 0x75,
+	0x6e, 0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0f, 0x70, 0x6f, 0x73, 0x74, 0x52, 0x75, 0x6e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
+	0x73, 0x12, 0x2e, 0x0a, 0x13, 0x6d, 0x69, 0x6e, 0x5f, 0x62, 0x61, 0x74, 0x74, 0x65, 0x72, 0x79,
+	0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x11,
+	0x6d, 0x69, 0x6e, 0x42, 0x61, 0x74, 0x74, 0x65, 0x72, 0x79, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e,
+	0x74, 0x12, 0x31, 0x0a, 0x14, 0x73, 0x65, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x61,
+	0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x13, 0x73, 0x65, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x76, 0x65, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62,
+	0x75, 0x74, 0x65, 0x73, 0x22, 0xd5, 0x03, 0x0a, 0x0c, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x3e, 0x0a, 0x0e, 0x70, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x79,
+	0x5f, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e,
+	0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74,
+	0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x0d, 0x70, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x79, 0x54,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x51, 0x0a, 0x0e, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x6e, 0x69,
+	0x6f, 0x6e, 0x5f, 0x64, 0x75, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2a, 0x2e,
+	0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x6e, 0x69, 0x6f, 0x6e,
+	0x44, 0x75, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0d, 0x63, 0x6f, 0x6d, 0x70, 0x61,
+	0x6e, 0x69, 0x6f, 0x6e, 0x44, 0x75, 0x74, 0x73, 0x12, 0x43, 0x0a, 0x10, 0x6d, 0x65, 0x74, 0x61,
+	0x5f, 0x74, 0x65, 0x73, 0x74, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x19, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x4d,
+	0x65, 0x74, 0x61, 0x54, 0x65, 0x73, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x0e, 0x6d,
+	0x65, 0x74, 0x61, 0x54, 0x65, 0x73, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x1d, 0x0a,
+	0x0a, 0x73, 0x65, 0x72, 0x76, 0x6f, 0x5f, 0x73, 0x70, 0x65, 0x63, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x73, 0x65, 0x72, 0x76, 0x6f, 0x53, 0x70, 0x65, 0x63, 0x12, 0x3b, 0x0a, 0x06,
+	0x70, 0x68, 0x6f, 0x6e, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x74,
 	0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x43,
-	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x0c, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x43, 0x6f, 0x6e,
-	0x66, 0x69, 0x67, 0x1a, 0x37, 0x0a, 0x09, 0x56, 0x61, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x50, 0x68, 0x6f, 0x6e, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x52, 0x06, 0x70, 0x68, 0x6f, 0x6e, 0x65, 0x73, 0x1a, 0x56, 0x0a, 0x12, 0x43, 0x6f, 0x6d,
+	0x70, 0x61, 0x6e, 0x69, 0x6f, 0x6e, 0x44, 0x75, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x2a, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x14, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x44, 0x55, 0x54,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38,
+	0x01, 0x1a, 0x39, 0x0a, 0x0b, 0x50, 0x68, 0x6f, 0x6e, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
 	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
 	0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x33, 0x0a, 0x10,
-	0x52, 0x75, 0x6e, 0x6e, 0x65, 0x72, 0x49, 0x6e, 0x69, 0x74, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73,
-	0x12, 0x1f, 0x0a, 0x0b, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x5f, 0x67, 0x6c, 0x6f, 0x62, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x47, 0x6c, 0x6f,
-	0x62, 0x22, 0xbe, 0x02, 0x0a, 0x0c, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x43, 0x6f, 0x6e, 0x66,
-	0x69, 0x67, 0x12, 0x3e, 0x0a, 0x0e, 0x70, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x79, 0x5f, 0x74, 0x61,
-	0x72, 0x67, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x74, 0x61, 0x73,
-	0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x44, 0x65, 0x76,
-	0x69, 0x63, 0x65, 0x52, 0x0d, 0x70, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x79, 0x54, 0x61, 0x72, 0x67,
-	0x65, 0x74, 0x12, 0x51, 0x0a, 0x0e, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x6e, 0x69, 0x6f, 0x6e, 0x5f,
-	0x64, 0x75, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x74, 0x61, 0x73,
-	0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x43, 0x6f, 0x6e,
-	0x66, 0x69, 0x67, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x6e, 0x69, 0x6f, 0x6e, 0x44, 0x75, 0x74,
-	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0d, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x6e, 0x69, 0x6f,
-	0x6e, 0x44, 0x75, 0x74, 0x73, 0x12, 0x43, 0x0a, 0x10, 0x6d, 0x65, 0x74, 0x61, 0x5f, 0x74, 0x65,
-	0x73, 0x74, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x19, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x4d, 0x65, 0x74, 0x61,
-	0x54, 0x65, 0x73, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x0e, 0x6d, 0x65, 0x74, 0x61,
-	0x54, 0x65, 0x73, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x1a, 0x56, 0x0a, 0x12, 0x43, 0x6f,
-	0x6d, 0x70, 0x61, 0x6e, 0x69, 0x6f, 0x6e, 0x44, 0x75, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
-	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
-	0x65, 0x79, 0x12, 0x2a, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x14, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x44, 0x55,
-	0x54, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02,
-	0x38, 0x01, 0x22, 0x62, 0x0a, 0x0c, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x44, 0x65, 0x76, 0x69,
-	0x63, 0x65, 0x12, 0x33, 0x0a, 0x0a, 0x64, 0x75, 0x74, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f,
-	0x72, 0x65, 0x2e, 0x44, 0x55, 0x54, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x09, 0x64, 0x75,
-	0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x75, 0x6e, 0x64, 0x6c,
-	0x65, 0x5f, 0x64, 0x69, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x62, 0x75, 0x6e,
-	0x64, 0x6c, 0x65, 0x44, 0x69, 0x72, 0x22, 0x5b, 0x0a, 0x09, 0x44, 0x55, 0x54, 0x43, 0x6f, 0x6e,
-	0x66, 0x69, 0x67, 0x12, 0x33, 0x0a, 0x0a, 0x73, 0x73, 0x68, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69,
-	0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63,
-	0x6f, 0x72, 0x65, 0x2e, 0x53, 0x53, 0x48, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x09, 0x73,
-	0x73, 0x68, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x19, 0x0a, 0x08, 0x74, 0x6c, 0x77, 0x5f,
-	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x74, 0x6c, 0x77, 0x4e,
-	0x61, 0x6d, 0x65, 0x22, 0x8d, 0x01, 0x0a, 0x09, 0x53, 0x53, 0x48, 0x43, 0x6f, 0x6e, 0x66, 0x69,
-	0x67, 0x12, 0x27, 0x0a, 0x0f, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f,
-	0x73, 0x70, 0x65, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x63, 0x6f, 0x6e, 0x6e,
-	0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x70, 0x65, 0x63, 0x12, 0x19, 0x0a, 0x08, 0x6b, 0x65,
-	0x79, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6b, 0x65,
-	0x79, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x6b, 0x65, 0x79, 0x5f, 0x64, 0x69, 0x72,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6b, 0x65, 0x79, 0x44, 0x69, 0x72, 0x12, 0x23,
-	0x0a, 0x0d, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x18,
-	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x43, 0x6f, 0x6d, 0x6d,
-	0x61, 0x6e, 0x64, 0x22, 0x69, 0x0a, 0x0e, 0x4d, 0x65, 0x74, 0x61, 0x54, 0x65, 0x73, 0x74, 0x43,
-	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x61, 0x73, 0x74, 0x5f, 0x70, 0x61,
-	0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x61, 0x73, 0x74, 0x50, 0x61,
-	0x74, 0x68, 0x12, 0x1b, 0x0a, 0x09, 0x72, 0x75, 0x6e, 0x5f, 0x66, 0x6c, 0x61, 0x67, 0x73, 0x18,
-	0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x72, 0x75, 0x6e, 0x46, 0x6c, 0x61, 0x67, 0x73, 0x12,
-	0x1d, 0x0a, 0x0a, 0x6c, 0x69, 0x73, 0x74, 0x5f, 0x66, 0x6c, 0x61, 0x67, 0x73, 0x18, 0x03, 0x20,
-	0x03, 0x28, 0x09, 0x52, 0x09, 0x6c, 0x69, 0x73, 0x74, 0x46, 0x6c, 0x61, 0x67, 0x73, 0x42, 0x2d,
-	0x5a, 0x2b, 0x67, 0x6f, 0x2e, 0x63, 0x68, 0x72, 0x6f, 0x6d, 0x69, 0x75, 0x6d, 0x2e, 0x6f, 0x72,
-	0x67, 0x2f, 0x74, 0x61, 0x73, 0x74, 0x2f, 0x63, 0x6f, 0x72, 0x65, 0x2f, 0x69, 0x6e, 0x74, 0x65,
-	0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x62, 0x06, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x62, 0x0a, 0x0c,
+	0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x33, 0x0a, 0x0a,
+	0x64, 0x75, 0x74, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x14, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x44, 0x55, 0x54,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x09, 0x64, 0x75, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x5f, 0x64, 0x69, 0x72, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x44, 0x69, 0x72,
+	0x22, 0x5b, 0x0a, 0x09, 0x44, 0x55, 0x54, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x33, 0x0a,
+	0x0a, 0x73, 0x73, 0x68, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x14, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53, 0x53,
+	0x48, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x09, 0x73, 0x73, 0x68, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x12, 0x19, 0x0a, 0x08, 0x74, 0x6c, 0x77, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x74, 0x6c, 0x77, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x8d, 0x01,
+	0x0a, 0x09, 0x53, 0x53, 0x48, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x27, 0x0a, 0x0f, 0x63,
+	0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x70, 0x65, 0x63, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x53, 0x70, 0x65, 0x63, 0x12, 0x19, 0x0a, 0x08, 0x6b, 0x65, 0x79, 0x5f, 0x66, 0x69, 0x6c, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6b, 0x65, 0x79, 0x46, 0x69, 0x6c, 0x65, 0x12,
+	0x17, 0x0a, 0x07, 0x6b, 0x65, 0x79, 0x5f, 0x64, 0x69, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x6b, 0x65, 0x79, 0x44, 0x69, 0x72, 0x12, 0x23, 0x0a, 0x0d, 0x70, 0x72, 0x6f, 0x78,
+	0x79, 0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0c, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x22, 0x69, 0x0a,
+	0x0e, 0x4d, 0x65, 0x74, 0x61, 0x54, 0x65, 0x73, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12,
+	0x1b, 0x0a, 0x09, 0x74, 0x61, 0x73, 0x74, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x74, 0x61, 0x73, 0x74, 0x50, 0x61, 0x74, 0x68, 0x12, 0x1b, 0x0a, 0x09,
+	0x72, 0x75, 0x6e, 0x5f, 0x66, 0x6c, 0x61, 0x67, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x08, 0x72, 0x75, 0x6e, 0x46, 0x6c, 0x61, 0x67, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x69, 0x73,
+	0x74, 0x5f, 0x66, 0x6c, 0x61, 0x67, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x6c,
+	0x69, 0x73, 0x74, 0x46, 0x6c, 0x61, 0x67, 0x73, 0x22, 0xc6, 0x01, 0x0a, 0x0d, 0x51, 0x75, 0x69,
+	0x65, 0x73, 0x63, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x2c, 0x0a, 0x12, 0x73, 0x74,
+	0x6f, 0x70, 0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x65, 0x6e, 0x67, 0x69, 0x6e, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x73, 0x74, 0x6f, 0x70, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x45, 0x6e, 0x67, 0x69, 0x6e, 0x65, 0x12, 0x38, 0x0a, 0x18, 0x64, 0x69, 0x73, 0x61,
+	0x62, 0x6c, 0x65, 0x5f, 0x61, 0x72, 0x63, 0x5f, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x73, 0x69, 0x6f,
+	0x6e, 0x69, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x16, 0x64, 0x69, 0x73, 0x61,
+	0x62, 0x6c, 0x65, 0x41, 0x72, 0x63, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x69,
+	0x6e, 0x67, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x70, 0x75, 0x5f, 0x67, 0x6f, 0x76, 0x65, 0x72, 0x6e,
+	0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x70, 0x75, 0x47, 0x6f, 0x76,
+	0x65, 0x72, 0x6e, 0x6f, 0x72, 0x12, 0x2a, 0x0a, 0x11, 0x63, 0x6c, 0x65, 0x61, 0x72, 0x5f, 0x63,
+	0x72, 0x61, 0x73, 0x68, 0x5f, 0x73, 0x70, 0x6f, 0x6f, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0f, 0x63, 0x6c, 0x65, 0x61, 0x72, 0x43, 0x72, 0x61, 0x73, 0x68, 0x53, 0x70, 0x6f, 0x6f,
+	0x6c, 0x42, 0x2d, 0x5a, 0x2b, 0x67, 0x6f, 0x2e, 0x63, 0x68, 0x72, 0x6f, 0x6d, 0x69, 0x75, 0x6d,
+	0x2e, 0x6f, 0x72, 0x67, 0x2f, 0x74, 0x61, 0x73, 0x74, 0x2f, 0x63, 0x6f, 0x72, 0x65, 0x2f, 0x69,
+	0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -738,38 +1020,44 @@ func file_handshake_proto_rawDescGZIP() []byte {
 	return file_handshake_proto_rawDescData
 }
 
-var file_handshake_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_handshake_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
 var file_handshake_proto_goTypes = []interface{}{
 	(*HandshakeRequest)(nil),  // 0: tast.core.HandshakeRequest
 	(*HandshakeResponse)(nil), // 1: tast.core.HandshakeResponse
 	(*HandshakeError)(nil),    // 2: tast.core.HandshakeError
 	(*BundleInitParams)(nil),  // 3: tast.core.BundleInitParams
 	(*RunnerInitParams)(nil),  // 4: tast.core.RunnerInitParams
-	(*BundleConfig)(nil),      // 5: tast.core.BundleConfig
-	(*TargetDevice)(nil),      // 6: tast.core.TargetDevice
-	(*DUTConfig)(nil),         // 7: tast.core.DUTConfig
-	(*SSHConfig)(nil),         // 8: tast.core.SSHConfig
-	(*MetaTestConfig)(nil),    // 9: tast.core.MetaTestConfig
-	nil,                       // 10: tast.core.BundleInitParams.VarsEntry
-	nil,                       // 11: tast.core.BundleConfig.CompanionDutsEntry
+	(*PowerPolicy)(nil),       // 5: tast.core.PowerPolicy
+	(*BundleConfig)(nil),      // 6: tast.core.BundleConfig
+	(*TargetDevice)(nil),      // 7: tast.core.TargetDevice
+	(*DUTConfig)(nil),         // 8: tast.core.DUTConfig
+	(*SSHConfig)(nil),         // 9: tast.core.SSHConfig
+	(*MetaTestConfig)(nil),    // 10: tast.core.MetaTestConfig
+	(*QuiescePolicy)(nil),     // 11: tast.core.QuiescePolicy
+	nil,                       // 12: tast.core.BundleInitParams.VarsEntry
+	nil,                       // 13: tast.core.BundleConfig.CompanionDutsEntry
+	nil,                       // 14: tast.core.BundleConfig.PhonesEntry
 }
 var file_handshake_proto_depIdxs = []int32{
 	3,  // 0: tast.core.HandshakeRequest.bundle_init_params:type_name -> tast.core.BundleInitParams
 	4,  // 1: tast.core.HandshakeRequest.runner_init_params:type_name -> tast.core.RunnerInitParams
 	2,  // 2: tast.core.HandshakeResponse.error:type_name -> tast.core.HandshakeError
-	10, // 3: tast.core.BundleInitParams.vars:type_name -> tast.core.BundleInitParams.VarsEntry
-	5,  // 4: tast.core.BundleInitParams.bundle_config:type_name -> tast.core.BundleConfig
-	6,  // 5: tast.core.BundleConfig.primary_target:type_name -> tast.core.TargetDevice
-	11, // 6: tast.core.BundleConfig.companion_duts:type_name -> tast.core.BundleConfig.CompanionDutsEntry
-	9,  // 7: tast.core.BundleConfig.meta_test_config:type_name -> tast.core.MetaTestConfig
-	7,  // 8: tast.core.TargetDevice.dut_config:type_name -> tast.core.DUTConfig
-	8,  // 9: tast.core.DUTConfig.ssh_config:type_name -> tast.core.SSHConfig
-	7,  // 10: tast.core.BundleConfig.CompanionDutsEntry.value:type_name -> tast.core.DUTConfig
-	11, // [11:11] is the sub-list for method output_type
-	11, // [11:11] is the sub-list for method input_type
-	11, // [11:11] is the sub-list for extension type_name
-	11, // [11:11] is the sub-list for extension extendee
-	0,  // [0:11] is the sub-list for field type_name
+	12, // 3: tast.core.BundleInitParams.vars:type_name -> tast.core.BundleInitParams.VarsEntry
+	6,  // 4: tast.core.BundleInitParams.bundle_config:type_name -> tast.core.BundleConfig
+	5,  // 5: tast.core.RunnerInitParams.power_policy:type_name -> tast.core.PowerPolicy
+	11, // 6: tast.core.RunnerInitParams.quiesce_policy:type_name -> tast.core.QuiescePolicy
+	7,  // 7: tast.core.BundleConfig.primary_target:type_name -> tast.core.TargetDevice
+	13, // 8: tast.core.BundleConfig.companion_duts:type_name -> tast.core.BundleConfig.CompanionDutsEntry
+	10, // 9: tast.core.BundleConfig.meta_test_config:type_name -> tast.core.MetaTestConfig
+	14, // 10: tast.core.BundleConfig.phones:type_name -> tast.core.BundleConfig.PhonesEntry
+	8,  // 11: tast.core.TargetDevice.dut_config:type_name -> tast.core.DUTConfig
+	9,  // 12: tast.core.DUTConfig.ssh_config:type_name -> tast.core.SSHConfig
+	8,  // 13: tast.core.BundleConfig.CompanionDutsEntry.value:type_name -> tast.core.DUTConfig
+	14, // [14:14] is the sub-list for method output_type
+	14, // [14:14] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
 }
 
 func init() { file_handshake_proto_init() }
@@ -839,7 +1127,7 @@ func file_handshake_proto_init() {
 			}
 		}
 		file_handshake_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BundleConfig); i {
+			switch v := v.(*PowerPolicy); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -851,7 +1139,7 @@ func file_handshake_proto_init() {
 			}
 		}
 		file_handshake_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*TargetDevice); i {
+			switch v := v.(*BundleConfig); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -863,7 +1151,7 @@ func file_handshake_proto_init() {
 			}
 		}
 		file_handshake_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DUTConfig); i {
+			switch v := v.(*TargetDevice); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -875,7 +1163,7 @@ func file_handshake_proto_init() {
 			}
 		}
 		file_handshake_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SSHConfig); i {
+			switch v := v.(*DUTConfig); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -887,6 +1175,18 @@ func file_handshake_proto_init() {
 			}
 		}
 		file_handshake_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SSHConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_handshake_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*MetaTestConfig); i {
 			case 0:
 				return &v.state
@@ -898,6 +1198,18 @@ func file_handshake_proto_init() {
 				return nil
 			}
 		}
+		file_handshake_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QuiescePolicy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -905,7 +1217,7 @@ func file_handshake_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_handshake_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   12,
+			NumMessages:   15,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
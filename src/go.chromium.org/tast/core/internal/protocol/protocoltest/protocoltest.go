@@ -23,8 +23,10 @@ import (
 var EventCmpOpts = []cmp.Option{
 	protocmp.Transform(),
 	protocmp.IgnoreMessages(&timestamppb.Timestamp{}),
-	protocmp.IgnoreFields(&protocol.EntityStartEvent{}, "out_dir"),
-	protocmp.IgnoreFields(&protocol.EntityEndEvent{}, "timing_log"),
+	protocmp.IgnoreFields(&protocol.EntityStartEvent{}, "out_dir", "instance_id"),
+	protocmp.IgnoreFields(&protocol.EntityLogEvent{}, "instance_id"),
+	protocmp.IgnoreFields(&protocol.EntityErrorEvent{}, "instance_id"),
+	protocmp.IgnoreFields(&protocol.EntityEndEvent{}, "timing_log", "instance_id"),
 	protocmp.IgnoreFields(&protocol.Error{}, "location"),
 }
 
@@ -148,6 +150,8 @@ func ExtractEvent(res *protocol.RunTestsResponse) (protocol.Event, bool) {
 		return res.EntityError, true
 	case *protocol.RunTestsResponse_EntityEnd:
 		return res.EntityEnd, true
+	case *protocol.RunTestsResponse_EntityQuarantine:
+		return res.EntityQuarantine, true
 	default:
 		return nil, false
 	}
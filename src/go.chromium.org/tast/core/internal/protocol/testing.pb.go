@@ -129,6 +129,58 @@ func (DownloadMode) EnumDescriptor() ([]byte, []int) {
 	return file_testing_proto_rawDescGZIP(), []int{1}
 }
 
+// FixturePhase represents a phase of a fixture run.
+type FixturePhase int32
+
+const (
+	FixturePhase_FIXTURE_PHASE_UNSPECIFIED FixturePhase = 0
+	// SET_UP is the phase in which SetUp of the fixture is called.
+	FixturePhase_SET_UP FixturePhase = 1
+	// TEAR_DOWN is the phase in which TearDown of the fixture is called.
+	FixturePhase_TEAR_DOWN FixturePhase = 2
+)
+
+// Enum value maps for FixturePhase.
+var (
+	FixturePhase_name = map[int32]string{
+		0: "FIXTURE_PHASE_UNSPECIFIED",
+		1: "SET_UP",
+		2: "TEAR_DOWN",
+	}
+	FixturePhase_value = map[string]int32{
+		"FIXTURE_PHASE_UNSPECIFIED": 0,
+		"SET_UP":                    1,
+		"TEAR_DOWN":                 2,
+	}
+)
+
+func (x FixturePhase) Enum() *FixturePhase {
+	p := new(FixturePhase)
+	*p = x
+	return p
+}
+
+func (x FixturePhase) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FixturePhase) Descriptor() protoreflect.EnumDescriptor {
+	return file_testing_proto_enumTypes[2].Descriptor()
+}
+
+func (FixturePhase) Type() protoreflect.EnumType {
+	return &file_testing_proto_enumTypes[2]
+}
+
+func (x FixturePhase) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FixturePhase.Descriptor instead.
+func (FixturePhase) EnumDescriptor() ([]byte, []int) {
+	return file_testing_proto_rawDescGZIP(), []int{2}
+}
+
 type StackStatus int32
 
 const (
@@ -162,11 +214,11 @@ func (x StackStatus) String() string {
 }
 
 func (StackStatus) Descriptor() protoreflect.EnumDescriptor {
-	return file_testing_proto_enumTypes[2].Descriptor()
+	return file_testing_proto_enumTypes[3].Descriptor()
 }
 
 func (StackStatus) Type() protoreflect.EnumType {
-	return &file_testing_proto_enumTypes[2]
+	return &file_testing_proto_enumTypes[3]
 }
 
 func (x StackStatus) Number() protoreflect.EnumNumber {
@@ -175,7 +227,139 @@ func (x StackStatus) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use StackStatus.Descriptor instead.
 func (StackStatus) EnumDescriptor() ([]byte, []int) {
-	return file_testing_proto_rawDescGZIP(), []int{2}
+	return file_testing_proto_rawDescGZIP(), []int{3}
+}
+
+// Category classifies the cause of an error so that failures can be routed
+// automatically. See errors.Category for details.
+type Error_Category int32
+
+const (
+	Error_CATEGORY_UNSPECIFIED Error_Category = 0
+	// The test infrastructure failed, e.g. the lab network or a servo.
+	Error_INFRA Error_Category = 1
+	// The product under test misbehaved.
+	Error_PRODUCT Error_Category = 2
+	// The test itself is broken.
+	Error_TEST_BUG Error_Category = 3
+	// The environment of the DUT is unexpected, e.g. a missing peripheral.
+	Error_ENVIRONMENT Error_Category = 4
+)
+
+// Enum value maps for Error_Category.
+var (
+	Error_Category_name = map[int32]string{
+		0: "CATEGORY_UNSPECIFIED",
+		1: "INFRA",
+		2: "PRODUCT",
+		3: "TEST_BUG",
+		4: "ENVIRONMENT",
+	}
+	Error_Category_value = map[string]int32{
+		"CATEGORY_UNSPECIFIED": 0,
+		"INFRA":                1,
+		"PRODUCT":              2,
+		"TEST_BUG":             3,
+		"ENVIRONMENT":          4,
+	}
+)
+
+func (x Error_Category) Enum() *Error_Category {
+	p := new(Error_Category)
+	*p = x
+	return p
+}
+
+func (x Error_Category) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Error_Category) Descriptor() protoreflect.EnumDescriptor {
+	return file_testing_proto_enumTypes[4].Descriptor()
+}
+
+func (Error_Category) Type() protoreflect.EnumType {
+	return &file_testing_proto_enumTypes[4]
+}
+
+func (x Error_Category) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Error_Category.Descriptor instead.
+func (Error_Category) EnumDescriptor() ([]byte, []int) {
+	return file_testing_proto_rawDescGZIP(), []int{29, 0}
+}
+
+type SkipReason_Code int32
+
+const (
+	SkipReason_CODE_UNSPECIFIED SkipReason_Code = 0
+	// Software dependencies of the entity are not satisfied.
+	SkipReason_UNSATISFIED_SWDEP SkipReason_Code = 1
+	// Hardware dependencies of the entity are not satisfied.
+	SkipReason_UNSATISFIED_HWDEP SkipReason_Code = 2
+	// The entity is assigned to another shard.
+	SkipReason_SHARDED_OUT SkipReason_Code = 3
+	// The entity is skipped manually, e.g. by a test filter file.
+	SkipReason_MANUAL SkipReason_Code = 5
+	// Runtime variables required by the entity are missing.
+	SkipReason_MISSING_VAR SkipReason_Code = 6
+	// Kernel command line parameters required by the entity are missing.
+	SkipReason_UNSATISFIED_KERNEL_CMDLINE SkipReason_Code = 7
+	// Environment variables required by the entity are missing.
+	SkipReason_UNSATISFIED_ENV SkipReason_Code = 8
+)
+
+// Enum value maps for SkipReason_Code.
+var (
+	SkipReason_Code_name = map[int32]string{
+		0: "CODE_UNSPECIFIED",
+		1: "UNSATISFIED_SWDEP",
+		2: "UNSATISFIED_HWDEP",
+		3: "SHARDED_OUT",
+		5: "MANUAL",
+		6: "MISSING_VAR",
+		7: "UNSATISFIED_KERNEL_CMDLINE",
+		8: "UNSATISFIED_ENV",
+	}
+	SkipReason_Code_value = map[string]int32{
+		"CODE_UNSPECIFIED":           0,
+		"UNSATISFIED_SWDEP":          1,
+		"UNSATISFIED_HWDEP":          2,
+		"SHARDED_OUT":                3,
+		"MANUAL":                     5,
+		"MISSING_VAR":                6,
+		"UNSATISFIED_KERNEL_CMDLINE": 7,
+		"UNSATISFIED_ENV":            8,
+	}
+)
+
+func (x SkipReason_Code) Enum() *SkipReason_Code {
+	p := new(SkipReason_Code)
+	*p = x
+	return p
+}
+
+func (x SkipReason_Code) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SkipReason_Code) Descriptor() protoreflect.EnumDescriptor {
+	return file_testing_proto_enumTypes[5].Descriptor()
+}
+
+func (SkipReason_Code) Type() protoreflect.EnumType {
+	return &file_testing_proto_enumTypes[5]
+}
+
+func (x SkipReason_Code) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SkipReason_Code.Descriptor instead.
+func (SkipReason_Code) EnumDescriptor() ([]byte, []int) {
+	return file_testing_proto_rawDescGZIP(), []int{42, 0}
 }
 
 type ListEntitiesRequest struct {
@@ -511,6 +695,7 @@ type RunTestsResponse struct {
 	//	*RunTestsResponse_EntityCopyEnd
 	//	*RunTestsResponse_StackOperation
 	//	*RunTestsResponse_Heartbeat
+	//	*RunTestsResponse_EntityQuarantine
 	Type isRunTestsResponse_Type `protobuf_oneof:"type"`
 }
 
@@ -609,6 +794,13 @@ func (x *RunTestsResponse) GetHeartbeat() *HeartbeatEvent {
 	return nil
 }
 
+func (x *RunTestsResponse) GetEntityQuarantine() *EntityQuarantineEvent {
+	if x, ok := x.GetType().(*RunTestsResponse_EntityQuarantine); ok {
+		return x.EntityQuarantine
+	}
+	return nil
+}
+
 type isRunTestsResponse_Type interface {
 	isRunTestsResponse_Type()
 }
@@ -645,6 +837,10 @@ type RunTestsResponse_Heartbeat struct {
 	Heartbeat *HeartbeatEvent `protobuf:"bytes,7,opt,name=heartbeat,proto3,oneof"`
 }
 
+type RunTestsResponse_EntityQuarantine struct {
+	EntityQuarantine *EntityQuarantineEvent `protobuf:"bytes,9,opt,name=entity_quarantine,json=entityQuarantine,proto3,oneof"`
+}
+
 func (*RunTestsResponse_RunLog) isRunTestsResponse_Type() {}
 
 func (*RunTestsResponse_EntityStart) isRunTestsResponse_Type() {}
@@ -661,6 +857,8 @@ func (*RunTestsResponse_StackOperation) isRunTestsResponse_Type() {}
 
 func (*RunTestsResponse_Heartbeat) isRunTestsResponse_Type() {}
 
+func (*RunTestsResponse_EntityQuarantine) isRunTestsResponse_Type() {}
+
 type GetDUTInfoRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -771,6 +969,10 @@ type GetSysInfoStateRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	// Collectors lists names of optional system info collectors to start, e.g.
+	// "bt_hci". Unknown names are ignored with warnings.
+	Collectors []string `protobuf:"bytes,1,rep,name=collectors,proto3" json:"collectors,omitempty"`
 }
 
 func (x *GetSysInfoStateRequest) Reset() {
@@ -805,6 +1007,13 @@ func (*GetSysInfoStateRequest) Descriptor() ([]byte, []int) {
 	return file_testing_proto_rawDescGZIP(), []int{9}
 }
 
+func (x *GetSysInfoStateRequest) GetCollectors() []string {
+	if x != nil {
+		return x.Collectors
+	}
+	return nil
+}
+
 type GetSysInfoStateResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -861,6 +1070,11 @@ type CollectSysInfoRequest struct {
 	// InitialState describes the pre-testing state of the DUT. It should be
 	// generated by the GetSysInfoState method executed before tests are run.
 	InitialState *SysInfoState `protobuf:"bytes,1,opt,name=initial_state,json=initialState,proto3" json:"initial_state,omitempty"`
+	// Collectors lists names of optional system info collectors to run in
+	// addition to the default ones, e.g. "ec_console". It should be the same as
+	// the one passed to GetSysInfoState. Unknown names are ignored with
+	// warnings.
+	Collectors []string `protobuf:"bytes,2,rep,name=collectors,proto3" json:"collectors,omitempty"`
 }
 
 func (x *CollectSysInfoRequest) Reset() {
@@ -902,6 +1116,13 @@ func (x *CollectSysInfoRequest) GetInitialState() *SysInfoState {
 	return nil
 }
 
+func (x *CollectSysInfoRequest) GetCollectors() []string {
+	if x != nil {
+		return x.Collectors
+	}
+	return nil
+}
+
 type CollectSysInfoResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1442,11 +1663,29 @@ type EntityLegacyData struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Timeout      *durationpb.Duration `protobuf:"bytes,1,opt,name=timeout,proto3" json:"timeout,omitempty"`
-	Variables    []string             `protobuf:"bytes,2,rep,name=variables,proto3" json:"variables,omitempty"`
-	VariableDeps []string             `protobuf:"bytes,3,rep,name=variable_deps,json=variableDeps,proto3" json:"variable_deps,omitempty"`
-	SoftwareDeps []string             `protobuf:"bytes,4,rep,name=software_deps,json=softwareDeps,proto3" json:"software_deps,omitempty"`
-	Bundle       string               `protobuf:"bytes,5,opt,name=bundle,proto3" json:"bundle,omitempty"`
+	Timeout          *durationpb.Duration `protobuf:"bytes,1,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	Variables        []string             `protobuf:"bytes,2,rep,name=variables,proto3" json:"variables,omitempty"`
+	VariableDeps     []string             `protobuf:"bytes,3,rep,name=variable_deps,json=variableDeps,proto3" json:"variable_deps,omitempty"`
+	SoftwareDeps     []string             `protobuf:"bytes,4,rep,name=software_deps,json=softwareDeps,proto3" json:"software_deps,omitempty"`
+	Bundle           string               `protobuf:"bytes,5,opt,name=bundle,proto3" json:"bundle,omitempty"`
+	ExpectedDuration *durationpb.Duration `protobuf:"bytes,6,opt,name=expected_duration,json=expectedDuration,proto3" json:"expected_duration,omitempty"`
+	// SoftSoftwareDeps lists software features the entity can make use of but
+	// does not require.
+	SoftSoftwareDeps []string `protobuf:"bytes,7,rep,name=soft_software_deps,json=softSoftwareDeps,proto3" json:"soft_software_deps,omitempty"`
+	// AvailableSoftSoftwareDeps lists features in soft_software_deps available
+	// on the DUT. It is set only in EntityStartEvent.
+	AvailableSoftSoftwareDeps []string `protobuf:"bytes,8,rep,name=available_soft_software_deps,json=availableSoftSoftwareDeps,proto3" json:"available_soft_software_deps,omitempty"`
+	// ScenarioSteps lists names of steps of a scenario test in the order they
+	// are run.
+	ScenarioSteps []string `protobuf:"bytes,9,rep,name=scenario_steps,json=scenarioSteps,proto3" json:"scenario_steps,omitempty"`
+	// Seed is the run-level seed from which the random number generator
+	// available to the test via State.Rand is derived. It is set only in
+	// EntityStartEvent.
+	Seed int64 `protobuf:"varint,10,opt,name=seed,proto3" json:"seed,omitempty"`
+	// RunMetadata contains run-level metadata given by the -metadata flag of
+	// tast run, e.g. the name of the CI builder. It is set only in
+	// EntityStartEvent.
+	RunMetadata []*StringPair `protobuf:"bytes,11,rep,name=run_metadata,json=runMetadata,proto3" json:"run_metadata,omitempty"`
 }
 
 func (x *EntityLegacyData) Reset() {
@@ -1516,6 +1755,48 @@ func (x *EntityLegacyData) GetBundle() string {
 	return ""
 }
 
+func (x *EntityLegacyData) GetExpectedDuration() *durationpb.Duration {
+	if x != nil {
+		return x.ExpectedDuration
+	}
+	return nil
+}
+
+func (x *EntityLegacyData) GetSoftSoftwareDeps() []string {
+	if x != nil {
+		return x.SoftSoftwareDeps
+	}
+	return nil
+}
+
+func (x *EntityLegacyData) GetAvailableSoftSoftwareDeps() []string {
+	if x != nil {
+		return x.AvailableSoftSoftwareDeps
+	}
+	return nil
+}
+
+func (x *EntityLegacyData) GetScenarioSteps() []string {
+	if x != nil {
+		return x.ScenarioSteps
+	}
+	return nil
+}
+
+func (x *EntityLegacyData) GetSeed() int64 {
+	if x != nil {
+		return x.Seed
+	}
+	return 0
+}
+
+func (x *EntityLegacyData) GetRunMetadata() []*StringPair {
+	if x != nil {
+		return x.RunMetadata
+	}
+	return nil
+}
+
 type RunTestsInit struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1597,8 +1878,7 @@ type RunConfig struct {
 	StartFixtureState *StartFixtureState `protobuf:"bytes,7,opt,name=start_fixture_state,json=startFixtureState,proto3" json:"start_fixture_state,omitempty"`
 	// HeartbeatInterval is the interval in seconds at which heartbeat messages
 	// are sent back periodically from runners (before running bundles) and
-	// bundles. If this value is not positive, heartbeat messages are not sent.
-	// TODO(crbug.com/1128259): Remove this field once we fully migrate to gRPC.
+	// bundles. If this value is not positive, a second is used.
 	HeartbeatInterval *durationpb.Duration `protobuf:"bytes,8,opt,name=heartbeat_interval,json=heartbeatInterval,proto3" json:"heartbeat_interval,omitempty"`
 	// WaitUntilReady indicates that the test bundle's "ready" function (see
 	// ReadyFunc) should be executed before any tests are executed.
@@ -1624,6 +1904,44 @@ type RunConfig struct {
 	// PushedFilesInfo stores the source and the destination of files
 	// that Tast push from host to all DUTs in a session.
 	PushedFilesInfo []*PushedFilesInfoForDUT `protobuf:"bytes,16,rep,name=pushed_files_info,json=pushedFilesInfo,proto3" json:"pushed_files_info,omitempty"`
+	// Parallel is the maximum number of tests to run concurrently. Only tests
+	// declaring disjoint resources are run concurrently. If it is 1 or less,
+	// tests are run one by one.
+	Parallel int32 `protobuf:"varint,17,opt,name=parallel,proto3" json:"parallel,omitempty"`
+	// LogRateLimit is the maximum number of log lines per second a test may
+	// report. Excess lines are dropped and a marker is reported instead.
+	// If it is 0, logs are not limited.
+	LogRateLimit int32 `protobuf:"varint,18,opt,name=log_rate_limit,json=logRateLimit,proto3" json:"log_rate_limit,omitempty"`
+	// LogBurst is the maximum number of log lines a test may report at once
+	// without being limited by LogRateLimit. If it is less than
+	// LogRateLimit, LogRateLimit is used.
+	LogBurst int32 `protobuf:"varint,19,opt,name=log_burst,json=logBurst,proto3" json:"log_burst,omitempty"`
+	// DiscardDebugLogs if true indicates that debug logs reported by tests,
+	// e.g. with State.VLog, are discarded.
+	DiscardDebugLogs bool `protobuf:"varint,20,opt,name=discard_debug_logs,json=discardDebugLogs,proto3" json:"discard_debug_logs,omitempty"`
+	// Seed is the run-level seed from which random number generators of tests
+	// are derived. A test gets the same sequence of random numbers from
+	// State.Rand whenever it is run with the same seed.
+	Seed int64 `protobuf:"varint,21,opt,name=seed,proto3" json:"seed,omitempty"`
+	// TestOrder lists names of tests in the order they should be run. Tests
+	// are run in this order as long as consecutive tests depending on the same
+	// fixture share a single fixture setup. Tests not listed are run after
+	// listed ones. If it is empty, tests are grouped by fixtures to minimize
+	// fixture setups.
+	TestOrder []string `protobuf:"bytes,22,rep,name=test_order,json=testOrder,proto3" json:"test_order,omitempty"`
+	// RunMetadata contains run-level metadata, e.g. the name of the CI builder
+	// and the CL under test, which tests can get with
+	// testing.ContextRunMetadata.
+	RunMetadata []*StringPair `protobuf:"bytes,23,rep,name=run_metadata,json=runMetadata,proto3" json:"run_metadata,omitempty"`
+	// QuarantineThreshold is the number of times a test may crash the test
+	// bundle or reboot the DUT before it is quarantined and not run for the
+	// rest of the run. If it is 0, tests are never quarantined.
+	QuarantineThreshold int32 `protobuf:"varint,24,opt,name=quarantine_threshold,json=quarantineThreshold,proto3" json:"quarantine_threshold,omitempty"`
+	// ScopedLogLevels contains minimum levels of logs reported by tests
+	// matching patterns, as "<pattern>:<level>" where level is "debug" or
+	// "info". They override discard_debug_logs for matching tests, and later
+	// ones take precedence over earlier ones.
+	ScopedLogLevels []string `protobuf:"bytes,25,rep,name=scoped_log_levels,json=scopedLogLevels,proto3" json:"scoped_log_levels,omitempty"`
 }
 
 func (x *RunConfig) Reset() {
@@ -1763,6 +2081,69 @@ func (x *RunConfig) GetPushedFilesInfo() []*PushedFilesInfoForDUT {
 	return nil
 }
 
+func (x *RunConfig) GetParallel() int32 {
+	if x != nil {
+		return x.Parallel
+	}
+	return 0
+}
+
+func (x *RunConfig) GetLogRateLimit() int32 {
+	if x != nil {
+		return x.LogRateLimit
+	}
+	return 0
+}
+
+func (x *RunConfig) GetLogBurst() int32 {
+	if x != nil {
+		return x.LogBurst
+	}
+	return 0
+}
+
+func (x *RunConfig) GetDiscardDebugLogs() bool {
+	if x != nil {
+		return x.DiscardDebugLogs
+	}
+	return false
+}
+
+func (x *RunConfig) GetSeed() int64 {
+	if x != nil {
+		return x.Seed
+	}
+	return 0
+}
+
+func (x *RunConfig) GetTestOrder() []string {
+	if x != nil {
+		return x.TestOrder
+	}
+	return nil
+}
+
+func (x *RunConfig) GetRunMetadata() []*StringPair {
+	if x != nil {
+		return x.RunMetadata
+	}
+	return nil
+}
+
+func (x *RunConfig) GetQuarantineThreshold() int32 {
+	if x != nil {
+		return x.QuarantineThreshold
+	}
+	return 0
+}
+
+func (x *RunConfig) GetScopedLogLevels() []string {
+	if x != nil {
+		return x.ScopedLogLevels
+	}
+	return nil
+}
+
 // RunTargetConfig contains parameters for the primary target bundle to run.
 type RunTargetConfig struct {
 	state         protoimpl.MessageState
@@ -1801,6 +2182,24 @@ type RunTargetConfig struct {
 	// BuildBucketID specifies the build bucket ID of the scheduled
 	// job that run Tast tests.
 	BuildBucketID string `protobuf:"bytes,12,opt,name=BuildBucketID,proto3" json:"BuildBucketID,omitempty"`
+	// Parallel is the maximum number of tests to run concurrently.
+	Parallel int32 `protobuf:"varint,13,opt,name=parallel,proto3" json:"parallel,omitempty"`
+	// LogRateLimit corresponds to RunConfig.log_rate_limit.
+	LogRateLimit int32 `protobuf:"varint,14,opt,name=log_rate_limit,json=logRateLimit,proto3" json:"log_rate_limit,omitempty"`
+	// LogBurst corresponds to RunConfig.log_burst.
+	LogBurst int32 `protobuf:"varint,15,opt,name=log_burst,json=logBurst,proto3" json:"log_burst,omitempty"`
+	// DiscardDebugLogs corresponds to RunConfig.discard_debug_logs.
+	DiscardDebugLogs bool `protobuf:"varint,16,opt,name=discard_debug_logs,json=discardDebugLogs,proto3" json:"discard_debug_logs,omitempty"`
+	// Seed corresponds to RunConfig.seed.
+	Seed int64 `protobuf:"varint,17,opt,name=seed,proto3" json:"seed,omitempty"`
+	// TestOrder corresponds to RunConfig.test_order.
+	TestOrder []string `protobuf:"bytes,18,rep,name=test_order,json=testOrder,proto3" json:"test_order,omitempty"`
+	// RunMetadata corresponds to RunConfig.run_metadata.
+	RunMetadata []*StringPair `protobuf:"bytes,19,rep,name=run_metadata,json=runMetadata,proto3" json:"run_metadata,omitempty"`
+	// QuarantineThreshold corresponds to RunConfig.quarantine_threshold.
+	QuarantineThreshold int32 `protobuf:"varint,20,opt,name=quarantine_threshold,json=quarantineThreshold,proto3" json:"quarantine_threshold,omitempty"`
+	// ScopedLogLevels corresponds to RunConfig.scoped_log_levels.
+	ScopedLogLevels []string `protobuf:"bytes,21,rep,name=scoped_log_levels,json=scopedLogLevels,proto3" json:"scoped_log_levels,omitempty"`
 }
 
 func (x *RunTargetConfig) Reset() {
@@ -1919,6 +2318,69 @@ func (x *RunTargetConfig) GetBuildBucketID() string {
 	return ""
 }
 
+func (x *RunTargetConfig) GetParallel() int32 {
+	if x != nil {
+		return x.Parallel
+	}
+	return 0
+}
+
+func (x *RunTargetConfig) GetLogRateLimit() int32 {
+	if x != nil {
+		return x.LogRateLimit
+	}
+	return 0
+}
+
+func (x *RunTargetConfig) GetLogBurst() int32 {
+	if x != nil {
+		return x.LogBurst
+	}
+	return 0
+}
+
+func (x *RunTargetConfig) GetDiscardDebugLogs() bool {
+	if x != nil {
+		return x.DiscardDebugLogs
+	}
+	return false
+}
+
+func (x *RunTargetConfig) GetSeed() int64 {
+	if x != nil {
+		return x.Seed
+	}
+	return 0
+}
+
+func (x *RunTargetConfig) GetTestOrder() []string {
+	if x != nil {
+		return x.TestOrder
+	}
+	return nil
+}
+
+func (x *RunTargetConfig) GetRunMetadata() []*StringPair {
+	if x != nil {
+		return x.RunMetadata
+	}
+	return nil
+}
+
+func (x *RunTargetConfig) GetQuarantineThreshold() int32 {
+	if x != nil {
+		return x.QuarantineThreshold
+	}
+	return 0
+}
+
+func (x *RunTargetConfig) GetScopedLogLevels() []string {
+	if x != nil {
+		return x.ScopedLogLevels
+	}
+	return nil
+}
+
 // RunDirectories holds several directory paths important for running tests.
 type RunDirectories struct {
 	state         protoimpl.MessageState
@@ -2026,6 +2488,11 @@ type ServiceConfig struct {
 	// BuildBucketID specifies the build bucket ID of the scheduled
 	// job that run Tast tests.
 	BuildBucketID string `protobuf:"bytes,10,opt,name=BuildBucketID,proto3" json:"BuildBucketID,omitempty"`
+	// DataMirrors is an ordered list of HTTP(S) mirrors of Google Cloud Storage
+	// (e.g. "https://mirror.example.com") used to download external data files
+	// when devservers and Google Cloud Storage are unavailable. A file at
+	// gs://bucket/path is downloaded from <mirror>/bucket/path.
+	DataMirrors []string `protobuf:"bytes,11,rep,name=data_mirrors,json=dataMirrors,proto3" json:"data_mirrors,omitempty"`
 }
 
 func (x *ServiceConfig) Reset() {
@@ -2130,6 +2597,13 @@ func (x *ServiceConfig) GetBuildBucketID() string {
 	return ""
 }
 
+func (x *ServiceConfig) GetDataMirrors() []string {
+	if x != nil {
+		return x.DataMirrors
+	}
+	return nil
+}
+
 // DataFileConfig contains configurations about data files.
 type DataFileConfig struct {
 	state         protoimpl.MessageState
@@ -2316,6 +2790,10 @@ type Error struct {
 
 	Reason   string         `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
 	Location *ErrorLocation `protobuf:"bytes,2,opt,name=location,proto3" json:"location,omitempty"`
+	Category Error_Category `protobuf:"varint,3,opt,name=category,proto3,enum=tast.core.Error_Category" json:"category,omitempty"`
+	// ScenarioStep is the name of the step of a scenario test in which the
+	// error was reported. It is empty if the error was reported outside steps.
+	ScenarioStep string `protobuf:"bytes,4,opt,name=scenario_step,json=scenarioStep,proto3" json:"scenario_step,omitempty"`
 }
 
 func (x *Error) Reset() {
@@ -2364,6 +2842,20 @@ func (x *Error) GetLocation() *ErrorLocation {
 	return nil
 }
 
+func (x *Error) GetCategory() Error_Category {
+	if x != nil {
+		return x.Category
+	}
+	return Error_CATEGORY_UNSPECIFIED
+}
+
+func (x *Error) GetScenarioStep() string {
+	if x != nil {
+		return x.ScenarioStep
+	}
+	return ""
+}
+
 // ErrorLocation represents a code location where an error was reported.
 type ErrorLocation struct {
 	state         protoimpl.MessageState
@@ -2705,6 +3197,11 @@ type EntityStartEvent struct {
 	Time   *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
 	Entity *Entity                `protobuf:"bytes,2,opt,name=entity,proto3" json:"entity,omitempty"`
 	OutDir string                 `protobuf:"bytes,3,opt,name=out_dir,json=outDir,proto3" json:"out_dir,omitempty"`
+	// InstanceID identifies a run of an entity among entities running
+	// concurrently in a test bundle. It is assigned at EntityStartEvent and
+	// carried by following events of the entity so that events of concurrent
+	// runs can be demultiplexed. It is 0 if unset, e.g. by older test bundles.
+	InstanceId int64 `protobuf:"varint,4,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
 }
 
 func (x *EntityStartEvent) Reset() {
@@ -2760,6 +3257,13 @@ func (x *EntityStartEvent) GetOutDir() string {
 	return ""
 }
 
+func (x *EntityStartEvent) GetInstanceId() int64 {
+	if x != nil {
+		return x.InstanceId
+	}
+	return 0
+}
+
 // EntityLogEvent indicates that an informational log message was produced by
 // an entity.
 type EntityLogEvent struct {
@@ -2771,6 +3275,8 @@ type EntityLogEvent struct {
 	EntityName string                 `protobuf:"bytes,2,opt,name=entity_name,json=entityName,proto3" json:"entity_name,omitempty"`
 	Text       string                 `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
 	Level      LogLevel               `protobuf:"varint,4,opt,name=level,proto3,enum=tast.core.LogLevel" json:"level,omitempty"`
+	// InstanceID is the instance ID of the entity given at EntityStartEvent.
+	InstanceId int64 `protobuf:"varint,5,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
 }
 
 func (x *EntityLogEvent) Reset() {
@@ -2833,6 +3339,13 @@ func (x *EntityLogEvent) GetLevel() LogLevel {
 	return LogLevel_LOGLEVEL_UNSPECIFIED
 }
 
+func (x *EntityLogEvent) GetInstanceId() int64 {
+	if x != nil {
+		return x.InstanceId
+	}
+	return 0
+}
+
 // EntityErrorEvent indicates that an error was produced by an entity.
 // A consumer should treat an entity as failed when it sees one or more errors
 // reported for it.
@@ -2844,6 +3357,11 @@ type EntityErrorEvent struct {
 	Time       *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
 	EntityName string                 `protobuf:"bytes,2,opt,name=entity_name,json=entityName,proto3" json:"entity_name,omitempty"`
 	Error      *Error                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	// InstanceID is the instance ID of the entity given at EntityStartEvent.
+	InstanceId int64 `protobuf:"varint,4,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	// FixturePhase is the phase of the fixture in which the error was reported.
+	// It is unspecified for errors reported by tests.
+	FixturePhase FixturePhase `protobuf:"varint,5,opt,name=fixture_phase,json=fixturePhase,proto3,enum=tast.core.FixturePhase" json:"fixture_phase,omitempty"`
 }
 
 func (x *EntityErrorEvent) Reset() {
@@ -2899,6 +3417,20 @@ func (x *EntityErrorEvent) GetError() *Error {
 	return nil
 }
 
+func (x *EntityErrorEvent) GetInstanceId() int64 {
+	if x != nil {
+		return x.InstanceId
+	}
+	return 0
+}
+
+func (x *EntityErrorEvent) GetFixturePhase() FixturePhase {
+	if x != nil {
+		return x.FixturePhase
+	}
+	return FixturePhase_FIXTURE_PHASE_UNSPECIFIED
+}
+
 // EntityEndEvent marks the end of an entity run.
 type EntityEndEvent struct {
 	state         protoimpl.MessageState
@@ -2909,6 +3441,11 @@ type EntityEndEvent struct {
 	EntityName string                 `protobuf:"bytes,2,opt,name=entity_name,json=entityName,proto3" json:"entity_name,omitempty"`
 	Skip       *Skip                  `protobuf:"bytes,3,opt,name=skip,proto3" json:"skip,omitempty"`
 	TimingLog  *TimingLog             `protobuf:"bytes,4,opt,name=timing_log,json=timingLog,proto3" json:"timing_log,omitempty"`
+	// InstanceID is the instance ID of the entity given at EntityStartEvent.
+	InstanceId int64 `protobuf:"varint,6,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	// ConsumedVars contains sorted names of runtime variables the entity read
+	// and was given values for.
+	ConsumedVars []string `protobuf:"bytes,7,rep,name=consumed_vars,json=consumedVars,proto3" json:"consumed_vars,omitempty"`
 }
 
 func (x *EntityEndEvent) Reset() {
@@ -2971,10 +3508,92 @@ func (x *EntityEndEvent) GetTimingLog() *TimingLog {
 	return nil
 }
 
-// EntityCopyEndEvent marks the end of an file copies after entity ends.
-type EntityCopyEndEvent struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+func (x *EntityEndEvent) GetInstanceId() int64 {
+	if x != nil {
+		return x.InstanceId
+	}
+	return 0
+}
+
+func (x *EntityEndEvent) GetConsumedVars() []string {
+	if x != nil {
+		return x.ConsumedVars
+	}
+	return nil
+}
+
+// EntityQuarantineEvent is sent by test runners in place of running a test
+// that has been quarantined for the rest of the run since it crashed the test
+// bundle or rebooted the DUT too many times.
+type EntityQuarantineEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Time   *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	Entity *Entity                `protobuf:"bytes,2,opt,name=entity,proto3" json:"entity,omitempty"`
+	// Crashes is the number of times the test crashed the test bundle or
+	// rebooted the DUT.
+	Crashes int32 `protobuf:"varint,3,opt,name=crashes,proto3" json:"crashes,omitempty"`
+}
+
+func (x *EntityQuarantineEvent) Reset() {
+	*x = EntityQuarantineEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_testing_proto_msgTypes[39]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EntityQuarantineEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EntityQuarantineEvent) ProtoMessage() {}
+
+func (x *EntityQuarantineEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_testing_proto_msgTypes[39]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EntityQuarantineEvent.ProtoReflect.Descriptor instead.
+func (*EntityQuarantineEvent) Descriptor() ([]byte, []int) {
+	return file_testing_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *EntityQuarantineEvent) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *EntityQuarantineEvent) GetEntity() *Entity {
+	if x != nil {
+		return x.Entity
+	}
+	return nil
+}
+
+func (x *EntityQuarantineEvent) GetCrashes() int32 {
+	if x != nil {
+		return x.Crashes
+	}
+	return 0
+}
+
+// EntityCopyEndEvent marks the end of an file copies after entity ends.
+type EntityCopyEndEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	EntityName string `protobuf:"bytes,1,opt,name=entity_name,json=entityName,proto3" json:"entity_name,omitempty"`
@@ -2983,7 +3602,7 @@ type EntityCopyEndEvent struct {
 func (x *EntityCopyEndEvent) Reset() {
 	*x = EntityCopyEndEvent{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_testing_proto_msgTypes[39]
+		mi := &file_testing_proto_msgTypes[40]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2996,7 +3615,7 @@ func (x *EntityCopyEndEvent) String() string {
 func (*EntityCopyEndEvent) ProtoMessage() {}
 
 func (x *EntityCopyEndEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_testing_proto_msgTypes[39]
+	mi := &file_testing_proto_msgTypes[40]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3009,7 +3628,7 @@ func (x *EntityCopyEndEvent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EntityCopyEndEvent.ProtoReflect.Descriptor instead.
 func (*EntityCopyEndEvent) Descriptor() ([]byte, []int) {
-	return file_testing_proto_rawDescGZIP(), []int{39}
+	return file_testing_proto_rawDescGZIP(), []int{40}
 }
 
 func (x *EntityCopyEndEvent) GetEntityName() string {
@@ -3025,13 +3644,17 @@ type Skip struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
+	// Reasons contains human-readable reasons. It is a flattened form of
+	// typed_reasons kept for compatibility.
 	Reasons []string `protobuf:"bytes,1,rep,name=reasons,proto3" json:"reasons,omitempty"`
+	// TypedReasons contains machine-readable reasons.
+	TypedReasons []*SkipReason `protobuf:"bytes,2,rep,name=typed_reasons,json=typedReasons,proto3" json:"typed_reasons,omitempty"`
 }
 
 func (x *Skip) Reset() {
 	*x = Skip{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_testing_proto_msgTypes[40]
+		mi := &file_testing_proto_msgTypes[41]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -3044,7 +3667,7 @@ func (x *Skip) String() string {
 func (*Skip) ProtoMessage() {}
 
 func (x *Skip) ProtoReflect() protoreflect.Message {
-	mi := &file_testing_proto_msgTypes[40]
+	mi := &file_testing_proto_msgTypes[41]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3057,7 +3680,7 @@ func (x *Skip) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Skip.ProtoReflect.Descriptor instead.
 func (*Skip) Descriptor() ([]byte, []int) {
-	return file_testing_proto_rawDescGZIP(), []int{40}
+	return file_testing_proto_rawDescGZIP(), []int{41}
 }
 
 func (x *Skip) GetReasons() []string {
@@ -3067,6 +3690,70 @@ func (x *Skip) GetReasons() []string {
 	return nil
 }
 
+func (x *Skip) GetTypedReasons() []*SkipReason {
+	if x != nil {
+		return x.TypedReasons
+	}
+	return nil
+}
+
+// SkipReason is a machine-readable reason why an entity is skipped.
+type SkipReason struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code SkipReason_Code `protobuf:"varint,1,opt,name=code,proto3,enum=tast.core.SkipReason_Code" json:"code,omitempty"`
+	// Details contains human-readable strings describing the reason.
+	Details []string `protobuf:"bytes,2,rep,name=details,proto3" json:"details,omitempty"`
+}
+
+func (x *SkipReason) Reset() {
+	*x = SkipReason{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_testing_proto_msgTypes[42]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SkipReason) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SkipReason) ProtoMessage() {}
+
+func (x *SkipReason) ProtoReflect() protoreflect.Message {
+	mi := &file_testing_proto_msgTypes[42]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SkipReason.ProtoReflect.Descriptor instead.
+func (*SkipReason) Descriptor() ([]byte, []int) {
+	return file_testing_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *SkipReason) GetCode() SkipReason_Code {
+	if x != nil {
+		return x.Code
+	}
+	return SkipReason_CODE_UNSPECIFIED
+}
+
+func (x *SkipReason) GetDetails() []string {
+	if x != nil {
+		return x.Details
+	}
+	return nil
+}
+
 // DUTInfo holds DUT system information.
 type DUTInfo struct {
 	state         protoimpl.MessageState
@@ -3084,7 +3771,7 @@ type DUTInfo struct {
 func (x *DUTInfo) Reset() {
 	*x = DUTInfo{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_testing_proto_msgTypes[41]
+		mi := &file_testing_proto_msgTypes[43]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -3097,7 +3784,7 @@ func (x *DUTInfo) String() string {
 func (*DUTInfo) ProtoMessage() {}
 
 func (x *DUTInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_testing_proto_msgTypes[41]
+	mi := &file_testing_proto_msgTypes[43]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3110,7 +3797,7 @@ func (x *DUTInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DUTInfo.ProtoReflect.Descriptor instead.
 func (*DUTInfo) Descriptor() ([]byte, []int) {
-	return file_testing_proto_rawDescGZIP(), []int{41}
+	return file_testing_proto_rawDescGZIP(), []int{43}
 }
 
 func (x *DUTInfo) GetFeatures() *protocol.DUTFeatures {
@@ -3152,7 +3839,7 @@ type SysInfoState struct {
 func (x *SysInfoState) Reset() {
 	*x = SysInfoState{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_testing_proto_msgTypes[42]
+		mi := &file_testing_proto_msgTypes[44]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -3165,7 +3852,7 @@ func (x *SysInfoState) String() string {
 func (*SysInfoState) ProtoMessage() {}
 
 func (x *SysInfoState) ProtoReflect() protoreflect.Message {
-	mi := &file_testing_proto_msgTypes[42]
+	mi := &file_testing_proto_msgTypes[44]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3178,7 +3865,7 @@ func (x *SysInfoState) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SysInfoState.ProtoReflect.Descriptor instead.
 func (*SysInfoState) Descriptor() ([]byte, []int) {
-	return file_testing_proto_rawDescGZIP(), []int{42}
+	return file_testing_proto_rawDescGZIP(), []int{44}
 }
 
 func (x *SysInfoState) GetLogInodeSizes() map[uint64]int64 {
@@ -3222,7 +3909,7 @@ type StackOperationRequest struct {
 func (x *StackOperationRequest) Reset() {
 	*x = StackOperationRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_testing_proto_msgTypes[43]
+		mi := &file_testing_proto_msgTypes[45]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -3235,7 +3922,7 @@ func (x *StackOperationRequest) String() string {
 func (*StackOperationRequest) ProtoMessage() {}
 
 func (x *StackOperationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_testing_proto_msgTypes[43]
+	mi := &file_testing_proto_msgTypes[45]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3248,7 +3935,7 @@ func (x *StackOperationRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StackOperationRequest.ProtoReflect.Descriptor instead.
 func (*StackOperationRequest) Descriptor() ([]byte, []int) {
-	return file_testing_proto_rawDescGZIP(), []int{43}
+	return file_testing_proto_rawDescGZIP(), []int{45}
 }
 
 func (m *StackOperationRequest) GetType() isStackOperationRequest_Type {
@@ -3362,7 +4049,7 @@ type StackReset struct {
 func (x *StackReset) Reset() {
 	*x = StackReset{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_testing_proto_msgTypes[44]
+		mi := &file_testing_proto_msgTypes[46]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -3375,7 +4062,7 @@ func (x *StackReset) String() string {
 func (*StackReset) ProtoMessage() {}
 
 func (x *StackReset) ProtoReflect() protoreflect.Message {
-	mi := &file_testing_proto_msgTypes[44]
+	mi := &file_testing_proto_msgTypes[46]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3388,7 +4075,7 @@ func (x *StackReset) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StackReset.ProtoReflect.Descriptor instead.
 func (*StackReset) Descriptor() ([]byte, []int) {
-	return file_testing_proto_rawDescGZIP(), []int{44}
+	return file_testing_proto_rawDescGZIP(), []int{46}
 }
 
 type StackPreTest struct {
@@ -3403,7 +4090,7 @@ type StackPreTest struct {
 func (x *StackPreTest) Reset() {
 	*x = StackPreTest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_testing_proto_msgTypes[45]
+		mi := &file_testing_proto_msgTypes[47]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -3416,7 +4103,7 @@ func (x *StackPreTest) String() string {
 func (*StackPreTest) ProtoMessage() {}
 
 func (x *StackPreTest) ProtoReflect() protoreflect.Message {
-	mi := &file_testing_proto_msgTypes[45]
+	mi := &file_testing_proto_msgTypes[47]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3429,7 +4116,7 @@ func (x *StackPreTest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StackPreTest.ProtoReflect.Descriptor instead.
 func (*StackPreTest) Descriptor() ([]byte, []int) {
-	return file_testing_proto_rawDescGZIP(), []int{45}
+	return file_testing_proto_rawDescGZIP(), []int{47}
 }
 
 func (x *StackPreTest) GetEntity() *Entity {
@@ -3458,7 +4145,7 @@ type StackPostTest struct {
 func (x *StackPostTest) Reset() {
 	*x = StackPostTest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_testing_proto_msgTypes[46]
+		mi := &file_testing_proto_msgTypes[48]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -3471,7 +4158,7 @@ func (x *StackPostTest) String() string {
 func (*StackPostTest) ProtoMessage() {}
 
 func (x *StackPostTest) ProtoReflect() protoreflect.Message {
-	mi := &file_testing_proto_msgTypes[46]
+	mi := &file_testing_proto_msgTypes[48]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3484,7 +4171,7 @@ func (x *StackPostTest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StackPostTest.ProtoReflect.Descriptor instead.
 func (*StackPostTest) Descriptor() ([]byte, []int) {
-	return file_testing_proto_rawDescGZIP(), []int{46}
+	return file_testing_proto_rawDescGZIP(), []int{48}
 }
 
 func (x *StackPostTest) GetEntity() *Entity {
@@ -3510,7 +4197,7 @@ type StackGetStatus struct {
 func (x *StackGetStatus) Reset() {
 	*x = StackGetStatus{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_testing_proto_msgTypes[47]
+		mi := &file_testing_proto_msgTypes[49]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -3523,7 +4210,7 @@ func (x *StackGetStatus) String() string {
 func (*StackGetStatus) ProtoMessage() {}
 
 func (x *StackGetStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_testing_proto_msgTypes[47]
+	mi := &file_testing_proto_msgTypes[49]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3536,7 +4223,7 @@ func (x *StackGetStatus) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StackGetStatus.ProtoReflect.Descriptor instead.
 func (*StackGetStatus) Descriptor() ([]byte, []int) {
-	return file_testing_proto_rawDescGZIP(), []int{47}
+	return file_testing_proto_rawDescGZIP(), []int{49}
 }
 
 type StackSetDirty struct {
@@ -3550,7 +4237,7 @@ type StackSetDirty struct {
 func (x *StackSetDirty) Reset() {
 	*x = StackSetDirty{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_testing_proto_msgTypes[48]
+		mi := &file_testing_proto_msgTypes[50]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -3563,7 +4250,7 @@ func (x *StackSetDirty) String() string {
 func (*StackSetDirty) ProtoMessage() {}
 
 func (x *StackSetDirty) ProtoReflect() protoreflect.Message {
-	mi := &file_testing_proto_msgTypes[48]
+	mi := &file_testing_proto_msgTypes[50]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3576,7 +4263,7 @@ func (x *StackSetDirty) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StackSetDirty.ProtoReflect.Descriptor instead.
 func (*StackSetDirty) Descriptor() ([]byte, []int) {
-	return file_testing_proto_rawDescGZIP(), []int{48}
+	return file_testing_proto_rawDescGZIP(), []int{50}
 }
 
 func (x *StackSetDirty) GetDirty() bool {
@@ -3595,7 +4282,7 @@ type StackGetErrors struct {
 func (x *StackGetErrors) Reset() {
 	*x = StackGetErrors{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_testing_proto_msgTypes[49]
+		mi := &file_testing_proto_msgTypes[51]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -3608,7 +4295,7 @@ func (x *StackGetErrors) String() string {
 func (*StackGetErrors) ProtoMessage() {}
 
 func (x *StackGetErrors) ProtoReflect() protoreflect.Message {
-	mi := &file_testing_proto_msgTypes[49]
+	mi := &file_testing_proto_msgTypes[51]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3621,7 +4308,7 @@ func (x *StackGetErrors) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StackGetErrors.ProtoReflect.Descriptor instead.
 func (*StackGetErrors) Descriptor() ([]byte, []int) {
-	return file_testing_proto_rawDescGZIP(), []int{49}
+	return file_testing_proto_rawDescGZIP(), []int{51}
 }
 
 type StackValue struct {
@@ -3633,7 +4320,7 @@ type StackValue struct {
 func (x *StackValue) Reset() {
 	*x = StackValue{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_testing_proto_msgTypes[50]
+		mi := &file_testing_proto_msgTypes[52]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -3646,7 +4333,7 @@ func (x *StackValue) String() string {
 func (*StackValue) ProtoMessage() {}
 
 func (x *StackValue) ProtoReflect() protoreflect.Message {
-	mi := &file_testing_proto_msgTypes[50]
+	mi := &file_testing_proto_msgTypes[52]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3659,7 +4346,7 @@ func (x *StackValue) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StackValue.ProtoReflect.Descriptor instead.
 func (*StackValue) Descriptor() ([]byte, []int) {
-	return file_testing_proto_rawDescGZIP(), []int{50}
+	return file_testing_proto_rawDescGZIP(), []int{52}
 }
 
 type StackOperationResponse struct {
@@ -3683,7 +4370,7 @@ type StackOperationResponse struct {
 func (x *StackOperationResponse) Reset() {
 	*x = StackOperationResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_testing_proto_msgTypes[51]
+		mi := &file_testing_proto_msgTypes[53]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -3696,7 +4383,7 @@ func (x *StackOperationResponse) String() string {
 func (*StackOperationResponse) ProtoMessage() {}
 
 func (x *StackOperationResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_testing_proto_msgTypes[51]
+	mi := &file_testing_proto_msgTypes[53]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3709,7 +4396,7 @@ func (x *StackOperationResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StackOperationResponse.ProtoReflect.Descriptor instead.
 func (*StackOperationResponse) Descriptor() ([]byte, []int) {
-	return file_testing_proto_rawDescGZIP(), []int{51}
+	return file_testing_proto_rawDescGZIP(), []int{53}
 }
 
 func (x *StackOperationResponse) GetFatalError() string {
@@ -3753,12 +4440,17 @@ type HeartbeatEvent struct {
 	unknownFields protoimpl.UnknownFields
 
 	Time *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	// EntityName is the name of the entity started most recently among
+	// running ones. It is empty if no entity is running.
+	EntityName string `protobuf:"bytes,2,opt,name=entity_name,json=entityName,proto3" json:"entity_name,omitempty"`
+	// Elapsed is the time elapsed since the entity started.
+	Elapsed *durationpb.Duration `protobuf:"bytes,3,opt,name=elapsed,proto3" json:"elapsed,omitempty"`
 }
 
 func (x *HeartbeatEvent) Reset() {
 	*x = HeartbeatEvent{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_testing_proto_msgTypes[52]
+		mi := &file_testing_proto_msgTypes[54]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -3771,7 +4463,7 @@ func (x *HeartbeatEvent) String() string {
 func (*HeartbeatEvent) ProtoMessage() {}
 
 func (x *HeartbeatEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_testing_proto_msgTypes[52]
+	mi := &file_testing_proto_msgTypes[54]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3784,7 +4476,7 @@ func (x *HeartbeatEvent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HeartbeatEvent.ProtoReflect.Descriptor instead.
 func (*HeartbeatEvent) Descriptor() ([]byte, []int) {
-	return file_testing_proto_rawDescGZIP(), []int{52}
+	return file_testing_proto_rawDescGZIP(), []int{54}
 }
 
 func (x *HeartbeatEvent) GetTime() *timestamppb.Timestamp {
@@ -3794,6 +4486,20 @@ func (x *HeartbeatEvent) GetTime() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *HeartbeatEvent) GetEntityName() string {
+	if x != nil {
+		return x.EntityName
+	}
+	return ""
+}
+
+func (x *HeartbeatEvent) GetElapsed() *durationpb.Duration {
+	if x != nil {
+		return x.Elapsed
+	}
+	return nil
+}
+
 // A string key-value pair.
 type StringPair struct {
 	state         protoimpl.MessageState
@@ -3810,7 +4516,7 @@ type StringPair struct {
 func (x *StringPair) Reset() {
 	*x = StringPair{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_testing_proto_msgTypes[53]
+		mi := &file_testing_proto_msgTypes[55]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -3823,7 +4529,7 @@ func (x *StringPair) String() string {
 func (*StringPair) ProtoMessage() {}
 
 func (x *StringPair) ProtoReflect() protoreflect.Message {
-	mi := &file_testing_proto_msgTypes[53]
+	mi := &file_testing_proto_msgTypes[55]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3836,7 +4542,7 @@ func (x *StringPair) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StringPair.ProtoReflect.Descriptor instead.
 func (*StringPair) Descriptor() ([]byte, []int) {
-	return file_testing_proto_rawDescGZIP(), []int{53}
+	return file_testing_proto_rawDescGZIP(), []int{55}
 }
 
 func (x *StringPair) GetKey() string {
@@ -3900,7 +4606,7 @@ var file_testing_proto_rawDesc = []byte{
 	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00, 0x52, 0x16, 0x73,
 	0x74, 0x61, 0x63, 0x6b, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73,
 	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x06, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x4a, 0x04, 0x08,
-	0x02, 0x10, 0x03, 0x22, 0x9a, 0x04, 0x0a, 0x10, 0x52, 0x75, 0x6e, 0x54, 0x65, 0x73, 0x74, 0x73,
+	0x02, 0x10, 0x03, 0x22, 0xeb, 0x04, 0x0a, 0x10, 0x52, 0x75, 0x6e, 0x54, 0x65, 0x73, 0x74, 0x73,
 	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x07, 0x72, 0x75, 0x6e, 0x5f,
 	0x6c, 0x6f, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x74, 0x61, 0x73, 0x74,
 	0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x52, 0x75, 0x6e, 0x4c, 0x6f, 0x67, 0x45, 0x76, 0x65, 0x6e,
@@ -3933,505 +4639,645 @@ var file_testing_proto_rawDesc = []byte{
 	0x09, 0x68, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b,
 	0x32, 0x19, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x48, 0x65, 0x61,
 	0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x09, 0x68,
-	0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x42, 0x06, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65,
-	0x22, 0x57, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x44, 0x55, 0x54, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x26, 0x0a, 0x0f, 0x65, 0x78, 0x74, 0x72, 0x61, 0x5f, 0x75,
-	0x73, 0x65, 0x5f, 0x66, 0x6c, 0x61, 0x67, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0d,
-	0x65, 0x78, 0x74, 0x72, 0x61, 0x55, 0x73, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x73, 0x12, 0x1a, 0x0a,
-	0x08, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52,
-	0x08, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x22, 0x43, 0x0a, 0x12, 0x47, 0x65, 0x74,
-	0x44, 0x55, 0x54, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x2d, 0x0a, 0x08, 0x64, 0x75, 0x74, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x12, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x44, 0x55,
-	0x54, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x07, 0x64, 0x75, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x22, 0x18,
-	0x0a, 0x16, 0x47, 0x65, 0x74, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x53, 0x74, 0x61, 0x74,
-	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x48, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x53,
-	0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53,
-	0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61,
-	0x74, 0x65, 0x22, 0x55, 0x0a, 0x15, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x53, 0x79, 0x73,
-	0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3c, 0x0a, 0x0d, 0x69,
-	0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53,
-	0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x0c, 0x69, 0x6e, 0x69,
-	0x74, 0x69, 0x61, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x65, 0x22, 0x4e, 0x0a, 0x16, 0x43, 0x6f, 0x6c,
-	0x6c, 0x65, 0x63, 0x74, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x6c, 0x6f, 0x67, 0x5f, 0x64, 0x69, 0x72, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6c, 0x6f, 0x67, 0x44, 0x69, 0x72, 0x12, 0x1b, 0x0a, 0x09,
-	0x63, 0x72, 0x61, 0x73, 0x68, 0x5f, 0x64, 0x69, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x08, 0x63, 0x72, 0x61, 0x73, 0x68, 0x44, 0x69, 0x72, 0x22, 0xba, 0x01, 0x0a, 0x1d, 0x44, 0x6f,
-	0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x50, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x42, 0x75, 0x6e,
-	0x64, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3f, 0x0a, 0x0e, 0x73,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e,
-	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x0d, 0x73,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x2c, 0x0a, 0x12,
-	0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x5f, 0x75,
-	0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x41,
-	0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x55, 0x72, 0x6c, 0x12, 0x2a, 0x0a, 0x11, 0x72, 0x65,
-	0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x5f, 0x64, 0x69, 0x72, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x42, 0x75, 0x6e,
-	0x64, 0x6c, 0x65, 0x44, 0x69, 0x72, 0x22, 0x20, 0x0a, 0x1e, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f,
-	0x61, 0x64, 0x50, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x73,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x3f, 0x0a, 0x11, 0x53, 0x74, 0x72, 0x65,
-	0x61, 0x6d, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a,
-	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
-	0x65, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x03, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x22, 0x40, 0x0a, 0x12, 0x53, 0x74, 0x72,
-	0x65, 0x61, 0x6d, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64,
-	0x61, 0x74, 0x61, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x03, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x22, 0xa2, 0x04, 0x0a, 0x06,
-	0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x29, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65,
-	0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70,
-	0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x12,
-	0x1e, 0x0a, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x18, 0x04, 0x20,
-	0x03, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x12,
-	0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f,
-	0x6e, 0x12, 0x18, 0x0a, 0x07, 0x66, 0x69, 0x78, 0x74, 0x75, 0x72, 0x65, 0x18, 0x06, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x07, 0x66, 0x69, 0x78, 0x74, 0x75, 0x72, 0x65, 0x12, 0x41, 0x0a, 0x0c, 0x64,
-	0x65, 0x70, 0x65, 0x6e, 0x64, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x1d, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x45, 0x6e,
-	0x74, 0x69, 0x74, 0x79, 0x44, 0x65, 0x70, 0x65, 0x6e, 0x64, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73,
-	0x52, 0x0c, 0x64, 0x65, 0x70, 0x65, 0x6e, 0x64, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x12, 0x35,
-	0x0a, 0x08, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x19, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x45, 0x6e, 0x74,
-	0x69, 0x74, 0x79, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x73, 0x52, 0x08, 0x63, 0x6f, 0x6e,
-	0x74, 0x61, 0x63, 0x74, 0x73, 0x12, 0x3c, 0x0a, 0x0b, 0x6c, 0x65, 0x67, 0x61, 0x63, 0x79, 0x5f,
-	0x64, 0x61, 0x74, 0x61, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x74, 0x61, 0x73,
-	0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x4c, 0x65, 0x67,
-	0x61, 0x63, 0x79, 0x44, 0x61, 0x74, 0x61, 0x52, 0x0a, 0x6c, 0x65, 0x67, 0x61, 0x63, 0x79, 0x44,
-	0x61, 0x74, 0x61, 0x12, 0x38, 0x0a, 0x0c, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x5f, 0x66, 0x6c,
-	0x61, 0x67, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x61, 0x73, 0x74,
-	0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x50, 0x61, 0x69, 0x72,
-	0x52, 0x0b, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x46, 0x6c, 0x61, 0x67, 0x73, 0x12, 0x22, 0x0a,
-	0x0d, 0x74, 0x65, 0x73, 0x74, 0x5f, 0x62, 0x65, 0x64, 0x5f, 0x64, 0x65, 0x70, 0x73, 0x18, 0x0b,
-	0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x74, 0x65, 0x73, 0x74, 0x42, 0x65, 0x64, 0x44, 0x65, 0x70,
-	0x73, 0x12, 0x22, 0x0a, 0x0c, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x6d, 0x65, 0x6e, 0x74,
-	0x73, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65,
-	0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x62, 0x75, 0x67, 0x5f, 0x63, 0x6f, 0x6d,
-	0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x62, 0x75,
-	0x67, 0x43, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x4a, 0x04, 0x08, 0x0e, 0x10, 0x0f,
-	0x22, 0x28, 0x0a, 0x0e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63,
-	0x74, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x03,
-	0x28, 0x09, 0x52, 0x06, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x73, 0x22, 0x4f, 0x0a, 0x12, 0x45, 0x6e,
-	0x74, 0x69, 0x74, 0x79, 0x44, 0x65, 0x70, 0x65, 0x6e, 0x64, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73,
-	0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x01,
-	0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x64, 0x61, 0x74, 0x61, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x12,
-	0x1a, 0x0a, 0x08, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
-	0x09, 0x52, 0x08, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x22, 0xc7, 0x01, 0x0a, 0x10,
-	0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x4c, 0x65, 0x67, 0x61, 0x63, 0x79, 0x44, 0x61, 0x74, 0x61,
-	0x12, 0x33, 0x0a, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x74, 0x69,
-	0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x76, 0x61, 0x72, 0x69, 0x61, 0x62, 0x6c,
-	0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x76, 0x61, 0x72, 0x69, 0x61, 0x62,
-	0x6c, 0x65, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x76, 0x61, 0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x5f,
-	0x64, 0x65, 0x70, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x76, 0x61, 0x72, 0x69,
-	0x61, 0x62, 0x6c, 0x65, 0x44, 0x65, 0x70, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x6f, 0x66, 0x74,
-	0x77, 0x61, 0x72, 0x65, 0x5f, 0x64, 0x65, 0x70, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52,
-	0x0c, 0x73, 0x6f, 0x66, 0x74, 0x77, 0x61, 0x72, 0x65, 0x44, 0x65, 0x70, 0x73, 0x12, 0x16, 0x0a,
-	0x06, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x62,
-	0x75, 0x6e, 0x64, 0x6c, 0x65, 0x22, 0x80, 0x01, 0x0a, 0x0c, 0x52, 0x75, 0x6e, 0x54, 0x65, 0x73,
-	0x74, 0x73, 0x49, 0x6e, 0x69, 0x74, 0x12, 0x33, 0x0a, 0x0a, 0x72, 0x75, 0x6e, 0x5f, 0x63, 0x6f,
-	0x6e, 0x66, 0x69, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x74, 0x61, 0x73,
-	0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x52, 0x75, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
-	0x52, 0x09, 0x72, 0x75, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x1c, 0x0a, 0x09, 0x72,
-	0x65, 0x63, 0x75, 0x72, 0x73, 0x69, 0x76, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09,
-	0x72, 0x65, 0x63, 0x75, 0x72, 0x73, 0x69, 0x76, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x65, 0x62,
-	0x75, 0x67, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x64,
-	0x65, 0x62, 0x75, 0x67, 0x50, 0x6f, 0x72, 0x74, 0x22, 0x83, 0x07, 0x0a, 0x09, 0x52, 0x75, 0x6e,
-	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x65, 0x73, 0x74, 0x73, 0x18,
-	0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x74, 0x65, 0x73, 0x74, 0x73, 0x12, 0x2d, 0x0a, 0x04,
-	0x64, 0x69, 0x72, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x74, 0x61, 0x73,
-	0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x52, 0x75, 0x6e, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74,
-	0x6f, 0x72, 0x69, 0x65, 0x73, 0x52, 0x04, 0x64, 0x69, 0x72, 0x73, 0x12, 0x2f, 0x0a, 0x08, 0x66,
-	0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e,
-	0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72,
-	0x65, 0x73, 0x52, 0x08, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x12, 0x3f, 0x0a, 0x0e,
-	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x04,
+	0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x12, 0x4f, 0x0a, 0x11, 0x65, 0x6e, 0x74, 0x69,
+	0x74, 0x79, 0x5f, 0x71, 0x75, 0x61, 0x72, 0x61, 0x6e, 0x74, 0x69, 0x6e, 0x65, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e,
+	0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x51, 0x75, 0x61, 0x72, 0x61, 0x6e, 0x74, 0x69, 0x6e, 0x65,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x10, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x51,
+	0x75, 0x61, 0x72, 0x61, 0x6e, 0x74, 0x69, 0x6e, 0x65, 0x42, 0x06, 0x0a, 0x04, 0x74, 0x79, 0x70,
+	0x65, 0x22, 0x57, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x44, 0x55, 0x54, 0x49, 0x6e, 0x66, 0x6f, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x26, 0x0a, 0x0f, 0x65, 0x78, 0x74, 0x72, 0x61, 0x5f,
+	0x75, 0x73, 0x65, 0x5f, 0x66, 0x6c, 0x61, 0x67, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x0d, 0x65, 0x78, 0x74, 0x72, 0x61, 0x55, 0x73, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x73, 0x12, 0x1a,
+	0x0a, 0x08, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x08, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x22, 0x43, 0x0a, 0x12, 0x47, 0x65,
+	0x74, 0x44, 0x55, 0x54, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x2d, 0x0a, 0x08, 0x64, 0x75, 0x74, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x44,
+	0x55, 0x54, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x07, 0x64, 0x75, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x22,
+	0x38, 0x0a, 0x16, 0x47, 0x65, 0x74, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6c,
+	0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x63,
+	0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x22, 0x48, 0x0a, 0x17, 0x47, 0x65, 0x74,
+	0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e,
+	0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74,
+	0x61, 0x74, 0x65, 0x22, 0x75, 0x0a, 0x15, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x53, 0x79,
+	0x73, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3c, 0x0a, 0x0d,
+	0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e,
+	0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x0c, 0x69, 0x6e,
+	0x69, 0x74, 0x69, 0x61, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f,
+	0x6c, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a,
+	0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x22, 0x4e, 0x0a, 0x16, 0x43, 0x6f,
+	0x6c, 0x6c, 0x65, 0x63, 0x74, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x6c, 0x6f, 0x67, 0x5f, 0x64, 0x69, 0x72, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6c, 0x6f, 0x67, 0x44, 0x69, 0x72, 0x12, 0x1b, 0x0a,
+	0x09, 0x63, 0x72, 0x61, 0x73, 0x68, 0x5f, 0x64, 0x69, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x63, 0x72, 0x61, 0x73, 0x68, 0x44, 0x69, 0x72, 0x22, 0xba, 0x01, 0x0a, 0x1d, 0x44,
+	0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x50, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x42, 0x75,
+	0x6e, 0x64, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3f, 0x0a, 0x0e,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x01,
 	0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65,
 	0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x0d,
-	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x43, 0x0a,
-	0x10, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69,
-	0x67, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63,
-	0x6f, 0x72, 0x65, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x6f, 0x6e, 0x66,
-	0x69, 0x67, 0x52, 0x0e, 0x64, 0x61, 0x74, 0x61, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x6f, 0x6e, 0x66,
-	0x69, 0x67, 0x12, 0x4c, 0x0a, 0x13, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x66, 0x69, 0x78, 0x74,
-	0x75, 0x72, 0x65, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x1c, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x72,
-	0x74, 0x46, 0x69, 0x78, 0x74, 0x75, 0x72, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x11, 0x73,
-	0x74, 0x61, 0x72, 0x74, 0x46, 0x69, 0x78, 0x74, 0x75, 0x72, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65,
-	0x12, 0x48, 0x0a, 0x12, 0x68, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x5f, 0x69, 0x6e,
-	0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x2c, 0x0a,
+	0x12, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x5f,
+	0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x55, 0x72, 0x6c, 0x12, 0x2a, 0x0a, 0x11, 0x72,
+	0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x5f, 0x64, 0x69, 0x72,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x42, 0x75,
+	0x6e, 0x64, 0x6c, 0x65, 0x44, 0x69, 0x72, 0x22, 0x20, 0x0a, 0x1e, 0x44, 0x6f, 0x77, 0x6e, 0x6c,
+	0x6f, 0x61, 0x64, 0x50, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x3f, 0x0a, 0x11, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x22, 0x40, 0x0a, 0x12, 0x53, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04,
+	0x64, 0x61, 0x74, 0x61, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x22, 0xa2, 0x04, 0x0a,
+	0x06, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x29, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72,
+	0x65, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65,
+	0x12, 0x1e, 0x0a, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x18, 0x04,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73,
+	0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x66, 0x69, 0x78, 0x74, 0x75, 0x72, 0x65, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x66, 0x69, 0x78, 0x74, 0x75, 0x72, 0x65, 0x12, 0x41, 0x0a, 0x0c,
+	0x64, 0x65, 0x70, 0x65, 0x6e, 0x64, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x45,
+	0x6e, 0x74, 0x69, 0x74, 0x79, 0x44, 0x65, 0x70, 0x65, 0x6e, 0x64, 0x65, 0x6e, 0x63, 0x69, 0x65,
+	0x73, 0x52, 0x0c, 0x64, 0x65, 0x70, 0x65, 0x6e, 0x64, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x12,
+	0x35, 0x0a, 0x08, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x19, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x45, 0x6e,
+	0x74, 0x69, 0x74, 0x79, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x73, 0x52, 0x08, 0x63, 0x6f,
+	0x6e, 0x74, 0x61, 0x63, 0x74, 0x73, 0x12, 0x3c, 0x0a, 0x0b, 0x6c, 0x65, 0x67, 0x61, 0x63, 0x79,
+	0x5f, 0x64, 0x61, 0x74, 0x61, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x74, 0x61,
+	0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x4c, 0x65,
+	0x67, 0x61, 0x63, 0x79, 0x44, 0x61, 0x74, 0x61, 0x52, 0x0a, 0x6c, 0x65, 0x67, 0x61, 0x63, 0x79,
+	0x44, 0x61, 0x74, 0x61, 0x12, 0x38, 0x0a, 0x0c, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x5f, 0x66,
+	0x6c, 0x61, 0x67, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x61, 0x73,
+	0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x50, 0x61, 0x69,
+	0x72, 0x52, 0x0b, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x46, 0x6c, 0x61, 0x67, 0x73, 0x12, 0x22,
+	0x0a, 0x0d, 0x74, 0x65, 0x73, 0x74, 0x5f, 0x62, 0x65, 0x64, 0x5f, 0x64, 0x65, 0x70, 0x73, 0x18,
+	0x0b, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x74, 0x65, 0x73, 0x74, 0x42, 0x65, 0x64, 0x44, 0x65,
+	0x70, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x6d, 0x65, 0x6e,
+	0x74, 0x73, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72,
+	0x65, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x62, 0x75, 0x67, 0x5f, 0x63, 0x6f,
+	0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x62,
+	0x75, 0x67, 0x43, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x4a, 0x04, 0x08, 0x0e, 0x10,
+	0x0f, 0x22, 0x28, 0x0a, 0x0e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x43, 0x6f, 0x6e, 0x74, 0x61,
+	0x63, 0x74, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x06, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x73, 0x22, 0x4f, 0x0a, 0x12, 0x45,
+	0x6e, 0x74, 0x69, 0x74, 0x79, 0x44, 0x65, 0x70, 0x65, 0x6e, 0x64, 0x65, 0x6e, 0x63, 0x69, 0x65,
+	0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x64, 0x61, 0x74, 0x61, 0x46, 0x69, 0x6c, 0x65, 0x73,
+	0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x08, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x22, 0xf3, 0x03, 0x0a,
+	0x10, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x4c, 0x65, 0x67, 0x61, 0x63, 0x79, 0x44, 0x61, 0x74,
+	0x61, 0x12, 0x33, 0x0a, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x74,
+	0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x76, 0x61, 0x72, 0x69, 0x61, 0x62,
+	0x6c, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x76, 0x61, 0x72, 0x69, 0x61,
+	0x62, 0x6c, 0x65, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x76, 0x61, 0x72, 0x69, 0x61, 0x62, 0x6c, 0x65,
+	0x5f, 0x64, 0x65, 0x70, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x76, 0x61, 0x72,
+	0x69, 0x61, 0x62, 0x6c, 0x65, 0x44, 0x65, 0x70, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x6f, 0x66,
+	0x74, 0x77, 0x61, 0x72, 0x65, 0x5f, 0x64, 0x65, 0x70, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0c, 0x73, 0x6f, 0x66, 0x74, 0x77, 0x61, 0x72, 0x65, 0x44, 0x65, 0x70, 0x73, 0x12, 0x16,
+	0x0a, 0x06, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x12, 0x46, 0x0a, 0x11, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74,
+	0x65, 0x64, 0x5f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x10, 0x65, 0x78,
+	0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2c,
+	0x0a, 0x12, 0x73, 0x6f, 0x66, 0x74, 0x5f, 0x73, 0x6f, 0x66, 0x74, 0x77, 0x61, 0x72, 0x65, 0x5f,
+	0x64, 0x65, 0x70, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x10, 0x73, 0x6f, 0x66, 0x74,
+	0x53, 0x6f, 0x66, 0x74, 0x77, 0x61, 0x72, 0x65, 0x44, 0x65, 0x70, 0x73, 0x12, 0x3f, 0x0a, 0x1c,
+	0x61, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x73, 0x6f, 0x66, 0x74, 0x5f, 0x73,
+	0x6f, 0x66, 0x74, 0x77, 0x61, 0x72, 0x65, 0x5f, 0x64, 0x65, 0x70, 0x73, 0x18, 0x08, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x19, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x53, 0x6f, 0x66,
+	0x74, 0x53, 0x6f, 0x66, 0x74, 0x77, 0x61, 0x72, 0x65, 0x44, 0x65, 0x70, 0x73, 0x12, 0x25, 0x0a,
+	0x0e, 0x73, 0x63, 0x65, 0x6e, 0x61, 0x72, 0x69, 0x6f, 0x5f, 0x73, 0x74, 0x65, 0x70, 0x73, 0x18,
+	0x09, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x63, 0x65, 0x6e, 0x61, 0x72, 0x69, 0x6f, 0x53,
+	0x74, 0x65, 0x70, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x65, 0x65, 0x64, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x04, 0x73, 0x65, 0x65, 0x64, 0x12, 0x38, 0x0a, 0x0c, 0x72, 0x75, 0x6e, 0x5f,
+	0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15,
+	0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e,
+	0x67, 0x50, 0x61, 0x69, 0x72, 0x52, 0x0b, 0x72, 0x75, 0x6e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61,
+	0x74, 0x61, 0x22, 0x80, 0x01, 0x0a, 0x0c, 0x52, 0x75, 0x6e, 0x54, 0x65, 0x73, 0x74, 0x73, 0x49,
+	0x6e, 0x69, 0x74, 0x12, 0x33, 0x0a, 0x0a, 0x72, 0x75, 0x6e, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63,
+	0x6f, 0x72, 0x65, 0x2e, 0x52, 0x75, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x09, 0x72,
+	0x75, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x63, 0x75,
+	0x72, 0x73, 0x69, 0x76, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x72, 0x65, 0x63,
+	0x75, 0x72, 0x73, 0x69, 0x76, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x65, 0x62, 0x75, 0x67, 0x5f,
+	0x70, 0x6f, 0x72, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x64, 0x65, 0x62, 0x75,
+	0x67, 0x50, 0x6f, 0x72, 0x74, 0x22, 0xdc, 0x09, 0x0a, 0x09, 0x52, 0x75, 0x6e, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x65, 0x73, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x05, 0x74, 0x65, 0x73, 0x74, 0x73, 0x12, 0x2d, 0x0a, 0x04, 0x64, 0x69, 0x72,
+	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63,
+	0x6f, 0x72, 0x65, 0x2e, 0x52, 0x75, 0x6e, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69,
+	0x65, 0x73, 0x52, 0x04, 0x64, 0x69, 0x72, 0x73, 0x12, 0x2f, 0x0a, 0x08, 0x66, 0x65, 0x61, 0x74,
+	0x75, 0x72, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x74, 0x61, 0x73,
+	0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x52,
+	0x08, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x12, 0x3f, 0x0a, 0x0e, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x18, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x0d, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x43, 0x0a, 0x10, 0x64, 0x61,
+	0x74, 0x61, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65,
+	0x2e, 0x44, 0x61, 0x74, 0x61, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52,
+	0x0e, 0x64, 0x61, 0x74, 0x61, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12,
+	0x4c, 0x0a, 0x13, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x66, 0x69, 0x78, 0x74, 0x75, 0x72, 0x65,
+	0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x74,
+	0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x46, 0x69,
+	0x78, 0x74, 0x75, 0x72, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x11, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x46, 0x69, 0x78, 0x74, 0x75, 0x72, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x48, 0x0a,
+	0x12, 0x68, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x76, 0x61, 0x6c, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x11, 0x68, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x49,
+	0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x12, 0x28, 0x0a, 0x10, 0x77, 0x61, 0x69, 0x74, 0x5f,
+	0x75, 0x6e, 0x74, 0x69, 0x6c, 0x5f, 0x72, 0x65, 0x61, 0x64, 0x79, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0e, 0x77, 0x61, 0x69, 0x74, 0x55, 0x6e, 0x74, 0x69, 0x6c, 0x52, 0x65, 0x61, 0x64,
+	0x79, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x65, 0x62, 0x75, 0x67, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x18,
+	0x0a, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x64, 0x65, 0x62, 0x75, 0x67, 0x50, 0x6f, 0x72, 0x74,
+	0x12, 0x51, 0x0a, 0x17, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x73, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x15, 0x73, 0x79,
+	0x73, 0x74, 0x65, 0x6d, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x54, 0x69, 0x6d, 0x65,
+	0x6f, 0x75, 0x74, 0x12, 0x32, 0x0a, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x0c, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e,
+	0x52, 0x75, 0x6e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52,
+	0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x3a, 0x0a, 0x0b, 0x6d, 0x73, 0x67, 0x5f, 0x74,
+	0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67,
 	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44,
-	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x11, 0x68, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65,
-	0x61, 0x74, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x12, 0x28, 0x0a, 0x10, 0x77, 0x61,
-	0x69, 0x74, 0x5f, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x5f, 0x72, 0x65, 0x61, 0x64, 0x79, 0x18, 0x09,
-	0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x77, 0x61, 0x69, 0x74, 0x55, 0x6e, 0x74, 0x69, 0x6c, 0x52,
-	0x65, 0x61, 0x64, 0x79, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x65, 0x62, 0x75, 0x67, 0x5f, 0x70, 0x6f,
-	0x72, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x64, 0x65, 0x62, 0x75, 0x67, 0x50,
-	0x6f, 0x72, 0x74, 0x12, 0x51, 0x0a, 0x17, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x5f, 0x73, 0x65,
-	0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x0b,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
-	0x15, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x54,
-	0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x32, 0x0a, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74,
-	0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f,
-	0x72, 0x65, 0x2e, 0x52, 0x75, 0x6e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x43, 0x6f, 0x6e, 0x66,
-	0x69, 0x67, 0x52, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x3a, 0x0a, 0x0b, 0x6d, 0x73,
-	0x67, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
-	0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x6d, 0x73, 0x67, 0x54,
-	0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x2e, 0x0a, 0x14, 0x6d, 0x61, 0x78, 0x5f, 0x73, 0x79,
-	0x73, 0x5f, 0x6d, 0x73, 0x67, 0x5f, 0x6c, 0x6f, 0x67, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x0e,
-	0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x6d, 0x61, 0x78, 0x53, 0x79, 0x73, 0x4d, 0x73, 0x67, 0x4c,
-	0x6f, 0x67, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x52, 0x0a, 0x18, 0x77, 0x61, 0x69, 0x74, 0x5f, 0x75,
-	0x6e, 0x74, 0x69, 0x6c, 0x5f, 0x72, 0x65, 0x61, 0x64, 0x79, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6f,
-	0x75, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
-	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x52, 0x15, 0x77, 0x61, 0x69, 0x74, 0x55, 0x6e, 0x74, 0x69, 0x6c, 0x52, 0x65,
-	0x61, 0x64, 0x79, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x4c, 0x0a, 0x11, 0x70, 0x75,
-	0x73, 0x68, 0x65, 0x64, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18,
-	0x10, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72,
-	0x65, 0x2e, 0x50, 0x75, 0x73, 0x68, 0x65, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x49, 0x6e, 0x66,
-	0x6f, 0x46, 0x6f, 0x72, 0x44, 0x55, 0x54, 0x52, 0x0f, 0x70, 0x75, 0x73, 0x68, 0x65, 0x64, 0x46,
-	0x69, 0x6c, 0x65, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x4a, 0x04, 0x08, 0x06, 0x10, 0x07, 0x22, 0xb6,
-	0x04, 0x0a, 0x0f, 0x52, 0x75, 0x6e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x43, 0x6f, 0x6e, 0x66,
-	0x69, 0x67, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x65, 0x76, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x73,
-	0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x65, 0x76, 0x73, 0x65, 0x72, 0x76, 0x65,
-	0x72, 0x73, 0x12, 0x2d, 0x0a, 0x04, 0x64, 0x69, 0x72, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x19, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x52, 0x75, 0x6e,
-	0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x52, 0x04, 0x64, 0x69, 0x72,
-	0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x65, 0x62, 0x75, 0x67, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x64, 0x65, 0x62, 0x75, 0x67, 0x50, 0x6f, 0x72, 0x74,
-	0x12, 0x2a, 0x0a, 0x11, 0x6d, 0x61, 0x78, 0x5f, 0x74, 0x65, 0x73, 0x74, 0x5f, 0x66, 0x61, 0x69,
-	0x6c, 0x75, 0x72, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0f, 0x6d, 0x61, 0x78,
-	0x54, 0x65, 0x73, 0x74, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07,
-	0x72, 0x65, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x72,
-	0x65, 0x74, 0x72, 0x69, 0x65, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x18,
-	0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x12, 0x28, 0x0a, 0x10,
-	0x77, 0x61, 0x69, 0x74, 0x5f, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x5f, 0x72, 0x65, 0x61, 0x64, 0x79,
-	0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x77, 0x61, 0x69, 0x74, 0x55, 0x6e, 0x74, 0x69,
-	0x6c, 0x52, 0x65, 0x61, 0x64, 0x79, 0x12, 0x3a, 0x0a, 0x0b, 0x6d, 0x73, 0x67, 0x5f, 0x74, 0x69,
-	0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f,
-	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75,
-	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x6d, 0x73, 0x67, 0x54, 0x69, 0x6d, 0x65, 0x6f,
-	0x75, 0x74, 0x12, 0x51, 0x0a, 0x17, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x5f, 0x73, 0x65, 0x72,
-	0x76, 0x69, 0x63, 0x65, 0x73, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x09, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x15,
-	0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x54, 0x69,
-	0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x52, 0x0a, 0x18, 0x77, 0x61, 0x69, 0x74, 0x5f, 0x75, 0x6e,
-	0x74, 0x69, 0x6c, 0x5f, 0x72, 0x65, 0x61, 0x64, 0x79, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75,
-	0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69,
-	0x6f, 0x6e, 0x52, 0x15, 0x77, 0x61, 0x69, 0x74, 0x55, 0x6e, 0x74, 0x69, 0x6c, 0x52, 0x65, 0x61,
-	0x64, 0x79, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x26, 0x0a, 0x0e, 0x53, 0x77, 0x61,
-	0x72, 0x6d, 0x69, 0x6e, 0x67, 0x54, 0x61, 0x73, 0x6b, 0x49, 0x44, 0x18, 0x0b, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x0e, 0x53, 0x77, 0x61, 0x72, 0x6d, 0x69, 0x6e, 0x67, 0x54, 0x61, 0x73, 0x6b, 0x49,
-	0x44, 0x12, 0x24, 0x0a, 0x0d, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74,
-	0x49, 0x44, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x42,
-	0x75, 0x63, 0x6b, 0x65, 0x74, 0x49, 0x44, 0x22, 0x5f, 0x0a, 0x0e, 0x52, 0x75, 0x6e, 0x44, 0x69,
-	0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x12, 0x19, 0x0a, 0x08, 0x64, 0x61, 0x74,
-	0x61, 0x5f, 0x64, 0x69, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x64, 0x61, 0x74,
-	0x61, 0x44, 0x69, 0x72, 0x12, 0x17, 0x0a, 0x07, 0x6f, 0x75, 0x74, 0x5f, 0x64, 0x69, 0x72, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6f, 0x75, 0x74, 0x44, 0x69, 0x72, 0x12, 0x19, 0x0a,
-	0x08, 0x74, 0x65, 0x6d, 0x70, 0x5f, 0x64, 0x69, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x07, 0x74, 0x65, 0x6d, 0x70, 0x44, 0x69, 0x72, 0x22, 0x9f, 0x03, 0x0a, 0x0d, 0x53, 0x65, 0x72,
-	0x76, 0x69, 0x63, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x65,
-	0x76, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a,
-	0x64, 0x65, 0x76, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x6c,
-	0x77, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
-	0x74, 0x6c, 0x77, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x12, 0x22, 0x0a, 0x0d, 0x74, 0x6c, 0x77,
-	0x5f, 0x73, 0x65, 0x6c, 0x66, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x0b, 0x74, 0x6c, 0x77, 0x53, 0x65, 0x6c, 0x66, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x35, 0x0a,
-	0x17, 0x74, 0x6c, 0x77, 0x5f, 0x70, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x79, 0x5f, 0x74, 0x61, 0x72,
-	0x67, 0x65, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x14,
-	0x74, 0x6c, 0x77, 0x50, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x79, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74,
-	0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76,
-	0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x64, 0x75, 0x74, 0x53, 0x65, 0x72,
-	0x76, 0x65, 0x72, 0x12, 0x38, 0x0a, 0x18, 0x75, 0x73, 0x65, 0x5f, 0x65, 0x70, 0x68, 0x65, 0x6d,
-	0x65, 0x72, 0x61, 0x6c, 0x5f, 0x64, 0x65, 0x76, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x73, 0x18,
-	0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x16, 0x75, 0x73, 0x65, 0x45, 0x70, 0x68, 0x65, 0x6d, 0x65,
-	0x72, 0x61, 0x6c, 0x44, 0x65, 0x76, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x73, 0x12, 0x19, 0x0a,
-	0x08, 0x74, 0x61, 0x73, 0x74, 0x5f, 0x64, 0x69, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x07, 0x74, 0x61, 0x73, 0x74, 0x44, 0x69, 0x72, 0x12, 0x32, 0x0a, 0x15, 0x65, 0x78, 0x74, 0x72,
-	0x61, 0x5f, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x5f, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74,
-	0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x09, 0x52, 0x13, 0x65, 0x78, 0x74, 0x72, 0x61, 0x41, 0x6c,
-	0x6c, 0x6f, 0x77, 0x65, 0x64, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x12, 0x26, 0x0a, 0x0e,
-	0x53, 0x77, 0x61, 0x72, 0x6d, 0x69, 0x6e, 0x67, 0x54, 0x61, 0x73, 0x6b, 0x49, 0x44, 0x18, 0x09,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x53, 0x77, 0x61, 0x72, 0x6d, 0x69, 0x6e, 0x67, 0x54, 0x61,
-	0x73, 0x6b, 0x49, 0x44, 0x12, 0x24, 0x0a, 0x0d, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x42, 0x75, 0x63,
-	0x6b, 0x65, 0x74, 0x49, 0x44, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x42, 0x75, 0x69,
-	0x6c, 0x64, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x49, 0x44, 0x22, 0x7e, 0x0a, 0x0e, 0x44, 0x61,
-	0x74, 0x61, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x3c, 0x0a, 0x0d,
-	0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e,
-	0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x0c, 0x64, 0x6f,
-	0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x2e, 0x0a, 0x13, 0x62, 0x75,
-	0x69, 0x6c, 0x64, 0x5f, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x73, 0x5f, 0x75, 0x72,
-	0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x41, 0x72,
-	0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x73, 0x55, 0x72, 0x6c, 0x22, 0xc2, 0x01, 0x0a, 0x15, 0x50,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x6d, 0x73, 0x67, 0x54, 0x69, 0x6d, 0x65,
+	0x6f, 0x75, 0x74, 0x12, 0x2e, 0x0a, 0x14, 0x6d, 0x61, 0x78, 0x5f, 0x73, 0x79, 0x73, 0x5f, 0x6d,
+	0x73, 0x67, 0x5f, 0x6c, 0x6f, 0x67, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x10, 0x6d, 0x61, 0x78, 0x53, 0x79, 0x73, 0x4d, 0x73, 0x67, 0x4c, 0x6f, 0x67, 0x53,
+	0x69, 0x7a, 0x65, 0x12, 0x52, 0x0a, 0x18, 0x77, 0x61, 0x69, 0x74, 0x5f, 0x75, 0x6e, 0x74, 0x69,
+	0x6c, 0x5f, 0x72, 0x65, 0x61, 0x64, 0x79, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18,
+	0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x15, 0x77, 0x61, 0x69, 0x74, 0x55, 0x6e, 0x74, 0x69, 0x6c, 0x52, 0x65, 0x61, 0x64, 0x79,
+	0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x4c, 0x0a, 0x11, 0x70, 0x75, 0x73, 0x68, 0x65,
+	0x64, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x10, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x20, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x50,
 	0x75, 0x73, 0x68, 0x65, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x46, 0x6f,
-	0x72, 0x44, 0x55, 0x54, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x12, 0x55, 0x0a, 0x0d, 0x73, 0x72, 0x63, 0x5f,
-	0x64, 0x73, 0x74, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x31, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x50, 0x75, 0x73, 0x68,
-	0x65, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x46, 0x6f, 0x72, 0x44, 0x55,
-	0x54, 0x2e, 0x53, 0x72, 0x63, 0x44, 0x73, 0x74, 0x50, 0x61, 0x74, 0x68, 0x73, 0x45, 0x6e, 0x74,
-	0x72, 0x79, 0x52, 0x0b, 0x73, 0x72, 0x63, 0x44, 0x73, 0x74, 0x50, 0x61, 0x74, 0x68, 0x73, 0x1a,
-	0x3e, 0x0a, 0x10, 0x53, 0x72, 0x63, 0x44, 0x73, 0x74, 0x50, 0x61, 0x74, 0x68, 0x73, 0x45, 0x6e,
-	0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22,
-	0x51, 0x0a, 0x11, 0x53, 0x74, 0x61, 0x72, 0x74, 0x46, 0x69, 0x78, 0x74, 0x75, 0x72, 0x65, 0x53,
-	0x74, 0x61, 0x74, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x28, 0x0a, 0x06, 0x65, 0x72, 0x72, 0x6f,
-	0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e,
-	0x63, 0x6f, 0x72, 0x65, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x06, 0x65, 0x72, 0x72, 0x6f,
-	0x72, 0x73, 0x22, 0x55, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x72,
-	0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61,
-	0x73, 0x6f, 0x6e, 0x12, 0x34, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72,
-	0x65, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
-	0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x4d, 0x0a, 0x0d, 0x45, 0x72, 0x72,
-	0x6f, 0x72, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x69,
-	0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x12,
-	0x0a, 0x04, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x6c, 0x69,
-	0x6e, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x22, 0xa2, 0x01, 0x0a, 0x0e, 0x52, 0x65, 0x73,
-	0x6f, 0x6c, 0x76, 0x65, 0x64, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x29, 0x0a, 0x06, 0x65,
-	0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x61,
-	0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x06,
-	0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x23, 0x0a, 0x04, 0x73, 0x6b, 0x69, 0x70, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65,
-	0x2e, 0x53, 0x6b, 0x69, 0x70, 0x52, 0x04, 0x73, 0x6b, 0x69, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x68,
-	0x6f, 0x70, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x68, 0x6f, 0x70, 0x73, 0x12,
-	0x2c, 0x0a, 0x12, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x66, 0x69, 0x78, 0x74, 0x75, 0x72, 0x65,
-	0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x73, 0x74, 0x61,
-	0x72, 0x74, 0x46, 0x69, 0x78, 0x74, 0x75, 0x72, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x37, 0x0a,
-	0x09, 0x54, 0x69, 0x6d, 0x69, 0x6e, 0x67, 0x4c, 0x6f, 0x67, 0x12, 0x2a, 0x0a, 0x04, 0x72, 0x6f,
-	0x6f, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e,
-	0x63, 0x6f, 0x72, 0x65, 0x2e, 0x54, 0x69, 0x6d, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x67, 0x65,
-	0x52, 0x04, 0x72, 0x6f, 0x6f, 0x74, 0x22, 0xc7, 0x01, 0x0a, 0x0b, 0x54, 0x69, 0x6d, 0x69, 0x6e,
-	0x67, 0x53, 0x74, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x39, 0x0a, 0x0a, 0x73, 0x74,
-	0x61, 0x72, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
-	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
-	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72,
-	0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x35, 0x0a, 0x08, 0x65, 0x6e, 0x64, 0x5f, 0x74, 0x69, 0x6d,
-	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
-	0x61, 0x6d, 0x70, 0x52, 0x07, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x32, 0x0a, 0x08,
-	0x63, 0x68, 0x69, 0x6c, 0x64, 0x72, 0x65, 0x6e, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16,
-	0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x54, 0x69, 0x6d, 0x69, 0x6e,
-	0x67, 0x53, 0x74, 0x61, 0x67, 0x65, 0x52, 0x08, 0x63, 0x68, 0x69, 0x6c, 0x64, 0x72, 0x65, 0x6e,
-	0x22, 0x7c, 0x0a, 0x0b, 0x52, 0x75, 0x6e, 0x4c, 0x6f, 0x67, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12,
-	0x2e, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x72, 0x44, 0x55, 0x54, 0x52, 0x0f, 0x70, 0x75, 0x73, 0x68, 0x65, 0x64, 0x46, 0x69, 0x6c, 0x65,
+	0x73, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x72, 0x61, 0x6c, 0x6c, 0x65,
+	0x6c, 0x18, 0x11, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x61, 0x72, 0x61, 0x6c, 0x6c, 0x65,
+	0x6c, 0x12, 0x24, 0x0a, 0x0e, 0x6c, 0x6f, 0x67, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x6c, 0x69,
+	0x6d, 0x69, 0x74, 0x18, 0x12, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x6c, 0x6f, 0x67, 0x52, 0x61,
+	0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x6f, 0x67, 0x5f, 0x62,
+	0x75, 0x72, 0x73, 0x74, 0x18, 0x13, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x6c, 0x6f, 0x67, 0x42,
+	0x75, 0x72, 0x73, 0x74, 0x12, 0x2c, 0x0a, 0x12, 0x64, 0x69, 0x73, 0x63, 0x61, 0x72, 0x64, 0x5f,
+	0x64, 0x65, 0x62, 0x75, 0x67, 0x5f, 0x6c, 0x6f, 0x67, 0x73, 0x18, 0x14, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x10, 0x64, 0x69, 0x73, 0x63, 0x61, 0x72, 0x64, 0x44, 0x65, 0x62, 0x75, 0x67, 0x4c, 0x6f,
+	0x67, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x65, 0x65, 0x64, 0x18, 0x15, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x04, 0x73, 0x65, 0x65, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x65, 0x73, 0x74, 0x5f, 0x6f,
+	0x72, 0x64, 0x65, 0x72, 0x18, 0x16, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x74, 0x65, 0x73, 0x74,
+	0x4f, 0x72, 0x64, 0x65, 0x72, 0x12, 0x38, 0x0a, 0x0c, 0x72, 0x75, 0x6e, 0x5f, 0x6d, 0x65, 0x74,
+	0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x17, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x61,
+	0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x50, 0x61,
+	0x69, 0x72, 0x52, 0x0b, 0x72, 0x75, 0x6e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12,
+	0x31, 0x0a, 0x14, 0x71, 0x75, 0x61, 0x72, 0x61, 0x6e, 0x74, 0x69, 0x6e, 0x65, 0x5f, 0x74, 0x68,
+	0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x18, 0x20, 0x01, 0x28, 0x05, 0x52, 0x13, 0x71,
+	0x75, 0x61, 0x72, 0x61, 0x6e, 0x74, 0x69, 0x6e, 0x65, 0x54, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f,
+	0x6c, 0x64, 0x12, 0x2a, 0x0a, 0x11, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x64, 0x5f, 0x6c, 0x6f, 0x67,
+	0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x73, 0x18, 0x19, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0f, 0x73,
+	0x63, 0x6f, 0x70, 0x65, 0x64, 0x4c, 0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x73, 0x4a, 0x04,
+	0x08, 0x06, 0x10, 0x07, 0x22, 0x8f, 0x07, 0x0a, 0x0f, 0x52, 0x75, 0x6e, 0x54, 0x61, 0x72, 0x67,
+	0x65, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x65, 0x76, 0x73,
+	0x65, 0x72, 0x76, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x65,
+	0x76, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x73, 0x12, 0x2d, 0x0a, 0x04, 0x64, 0x69, 0x72, 0x73,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f,
+	0x72, 0x65, 0x2e, 0x52, 0x75, 0x6e, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65,
+	0x73, 0x52, 0x04, 0x64, 0x69, 0x72, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x65, 0x62, 0x75, 0x67,
+	0x5f, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x64, 0x65, 0x62,
+	0x75, 0x67, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x2a, 0x0a, 0x11, 0x6d, 0x61, 0x78, 0x5f, 0x74, 0x65,
+	0x73, 0x74, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x0f, 0x6d, 0x61, 0x78, 0x54, 0x65, 0x73, 0x74, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72,
+	0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x07, 0x72, 0x65, 0x74, 0x72, 0x69, 0x65, 0x73, 0x12, 0x14, 0x0a, 0x05,
+	0x70, 0x72, 0x6f, 0x78, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x70, 0x72, 0x6f,
+	0x78, 0x79, 0x12, 0x28, 0x0a, 0x10, 0x77, 0x61, 0x69, 0x74, 0x5f, 0x75, 0x6e, 0x74, 0x69, 0x6c,
+	0x5f, 0x72, 0x65, 0x61, 0x64, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x77, 0x61,
+	0x69, 0x74, 0x55, 0x6e, 0x74, 0x69, 0x6c, 0x52, 0x65, 0x61, 0x64, 0x79, 0x12, 0x3a, 0x0a, 0x0b,
+	0x6d, 0x73, 0x67, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x6d, 0x73,
+	0x67, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x51, 0x0a, 0x17, 0x73, 0x79, 0x73, 0x74,
+	0x65, 0x6d, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x5f, 0x74, 0x69, 0x6d, 0x65,
+	0x6f, 0x75, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x15, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x73, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x52, 0x0a, 0x18, 0x77,
+	0x61, 0x69, 0x74, 0x5f, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x5f, 0x72, 0x65, 0x61, 0x64, 0x79, 0x5f,
+	0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e,
 	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
-	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12,
-	0x12, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74,
-	0x65, 0x78, 0x74, 0x12, 0x29, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x0e, 0x32, 0x13, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x4c,
-	0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x22, 0x86,
-	0x01, 0x0a, 0x10, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x53, 0x74, 0x61, 0x72, 0x74, 0x45, 0x76,
-	0x65, 0x6e, 0x74, 0x12, 0x2e, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x74,
-	0x69, 0x6d, 0x65, 0x12, 0x29, 0x0a, 0x06, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e,
-	0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x06, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x17,
-	0x0a, 0x07, 0x6f, 0x75, 0x74, 0x5f, 0x64, 0x69, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x06, 0x6f, 0x75, 0x74, 0x44, 0x69, 0x72, 0x22, 0xa0, 0x01, 0x0a, 0x0e, 0x45, 0x6e, 0x74, 0x69,
-	0x74, 0x79, 0x4c, 0x6f, 0x67, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x2e, 0x0a, 0x04, 0x74, 0x69,
-	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
-	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
-	0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x65, 0x6e,
-	0x74, 0x69, 0x74, 0x79, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0a, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74,
-	0x65, 0x78, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x12,
-	0x29, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13,
+	0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x15, 0x77, 0x61, 0x69, 0x74, 0x55, 0x6e,
+	0x74, 0x69, 0x6c, 0x52, 0x65, 0x61, 0x64, 0x79, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12,
+	0x26, 0x0a, 0x0e, 0x53, 0x77, 0x61, 0x72, 0x6d, 0x69, 0x6e, 0x67, 0x54, 0x61, 0x73, 0x6b, 0x49,
+	0x44, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x53, 0x77, 0x61, 0x72, 0x6d, 0x69, 0x6e,
+	0x67, 0x54, 0x61, 0x73, 0x6b, 0x49, 0x44, 0x12, 0x24, 0x0a, 0x0d, 0x42, 0x75, 0x69, 0x6c, 0x64,
+	0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x49, 0x44, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d,
+	0x42, 0x75, 0x69, 0x6c, 0x64, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x49, 0x44, 0x12, 0x1a, 0x0a,
+	0x08, 0x70, 0x61, 0x72, 0x61, 0x6c, 0x6c, 0x65, 0x6c, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x08, 0x70, 0x61, 0x72, 0x61, 0x6c, 0x6c, 0x65, 0x6c, 0x12, 0x24, 0x0a, 0x0e, 0x6c, 0x6f, 0x67,
+	0x5f, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x0e, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x0c, 0x6c, 0x6f, 0x67, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12,
+	0x1b, 0x0a, 0x09, 0x6c, 0x6f, 0x67, 0x5f, 0x62, 0x75, 0x72, 0x73, 0x74, 0x18, 0x0f, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x08, 0x6c, 0x6f, 0x67, 0x42, 0x75, 0x72, 0x73, 0x74, 0x12, 0x2c, 0x0a, 0x12,
+	0x64, 0x69, 0x73, 0x63, 0x61, 0x72, 0x64, 0x5f, 0x64, 0x65, 0x62, 0x75, 0x67, 0x5f, 0x6c, 0x6f,
+	0x67, 0x73, 0x18, 0x10, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x64, 0x69, 0x73, 0x63, 0x61, 0x72,
+	0x64, 0x44, 0x65, 0x62, 0x75, 0x67, 0x4c, 0x6f, 0x67, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x65,
+	0x65, 0x64, 0x18, 0x11, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x73, 0x65, 0x65, 0x64, 0x12, 0x1d,
+	0x0a, 0x0a, 0x74, 0x65, 0x73, 0x74, 0x5f, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x18, 0x12, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x09, 0x74, 0x65, 0x73, 0x74, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x12, 0x38, 0x0a,
+	0x0c, 0x72, 0x75, 0x6e, 0x5f, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x13, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e,
+	0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x50, 0x61, 0x69, 0x72, 0x52, 0x0b, 0x72, 0x75, 0x6e, 0x4d,
+	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x31, 0x0a, 0x14, 0x71, 0x75, 0x61, 0x72, 0x61,
+	0x6e, 0x74, 0x69, 0x6e, 0x65, 0x5f, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x18,
+	0x14, 0x20, 0x01, 0x28, 0x05, 0x52, 0x13, 0x71, 0x75, 0x61, 0x72, 0x61, 0x6e, 0x74, 0x69, 0x6e,
+	0x65, 0x54, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x12, 0x2a, 0x0a, 0x11, 0x73, 0x63,
+	0x6f, 0x70, 0x65, 0x64, 0x5f, 0x6c, 0x6f, 0x67, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x73, 0x18,
+	0x15, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0f, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x64, 0x4c, 0x6f, 0x67,
+	0x4c, 0x65, 0x76, 0x65, 0x6c, 0x73, 0x22, 0x5f, 0x0a, 0x0e, 0x52, 0x75, 0x6e, 0x44, 0x69, 0x72,
+	0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x12, 0x19, 0x0a, 0x08, 0x64, 0x61, 0x74, 0x61,
+	0x5f, 0x64, 0x69, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x64, 0x61, 0x74, 0x61,
+	0x44, 0x69, 0x72, 0x12, 0x17, 0x0a, 0x07, 0x6f, 0x75, 0x74, 0x5f, 0x64, 0x69, 0x72, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6f, 0x75, 0x74, 0x44, 0x69, 0x72, 0x12, 0x19, 0x0a, 0x08,
+	0x74, 0x65, 0x6d, 0x70, 0x5f, 0x64, 0x69, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x74, 0x65, 0x6d, 0x70, 0x44, 0x69, 0x72, 0x22, 0xc2, 0x03, 0x0a, 0x0d, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x65, 0x76,
+	0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x64,
+	0x65, 0x76, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x6c, 0x77,
+	0x5f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x74,
+	0x6c, 0x77, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x12, 0x22, 0x0a, 0x0d, 0x74, 0x6c, 0x77, 0x5f,
+	0x73, 0x65, 0x6c, 0x66, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x74, 0x6c, 0x77, 0x53, 0x65, 0x6c, 0x66, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x35, 0x0a, 0x17,
+	0x74, 0x6c, 0x77, 0x5f, 0x70, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x79, 0x5f, 0x74, 0x61, 0x72, 0x67,
+	0x65, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x14, 0x74,
+	0x6c, 0x77, 0x50, 0x72, 0x69, 0x6d, 0x61, 0x72, 0x79, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x4e,
+	0x61, 0x6d, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x64, 0x75, 0x74, 0x53, 0x65, 0x72, 0x76,
+	0x65, 0x72, 0x12, 0x38, 0x0a, 0x18, 0x75, 0x73, 0x65, 0x5f, 0x65, 0x70, 0x68, 0x65, 0x6d, 0x65,
+	0x72, 0x61, 0x6c, 0x5f, 0x64, 0x65, 0x76, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x73, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x16, 0x75, 0x73, 0x65, 0x45, 0x70, 0x68, 0x65, 0x6d, 0x65, 0x72,
+	0x61, 0x6c, 0x44, 0x65, 0x76, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x73, 0x12, 0x19, 0x0a, 0x08,
+	0x74, 0x61, 0x73, 0x74, 0x5f, 0x64, 0x69, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x74, 0x61, 0x73, 0x74, 0x44, 0x69, 0x72, 0x12, 0x32, 0x0a, 0x15, 0x65, 0x78, 0x74, 0x72, 0x61,
+	0x5f, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x5f, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x73,
+	0x18, 0x08, 0x20, 0x03, 0x28, 0x09, 0x52, 0x13, 0x65, 0x78, 0x74, 0x72, 0x61, 0x41, 0x6c, 0x6c,
+	0x6f, 0x77, 0x65, 0x64, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x53,
+	0x77, 0x61, 0x72, 0x6d, 0x69, 0x6e, 0x67, 0x54, 0x61, 0x73, 0x6b, 0x49, 0x44, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0e, 0x53, 0x77, 0x61, 0x72, 0x6d, 0x69, 0x6e, 0x67, 0x54, 0x61, 0x73,
+	0x6b, 0x49, 0x44, 0x12, 0x24, 0x0a, 0x0d, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x42, 0x75, 0x63, 0x6b,
+	0x65, 0x74, 0x49, 0x44, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x42, 0x75, 0x69, 0x6c,
+	0x64, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x49, 0x44, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x61, 0x74,
+	0x61, 0x5f, 0x6d, 0x69, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x0b, 0x64, 0x61, 0x74, 0x61, 0x4d, 0x69, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x22, 0x7e, 0x0a, 0x0e,
+	0x44, 0x61, 0x74, 0x61, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x3c,
+	0x0a, 0x0d, 0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72,
+	0x65, 0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x0c,
+	0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x2e, 0x0a, 0x13,
+	0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x73, 0x5f,
+	0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x73, 0x55, 0x72, 0x6c, 0x22, 0xc2, 0x01, 0x0a,
+	0x15, 0x50, 0x75, 0x73, 0x68, 0x65, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x49, 0x6e, 0x66, 0x6f,
+	0x46, 0x6f, 0x72, 0x44, 0x55, 0x54, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x12, 0x55, 0x0a, 0x0d, 0x73, 0x72,
+	0x63, 0x5f, 0x64, 0x73, 0x74, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x31, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x50, 0x75,
+	0x73, 0x68, 0x65, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x46, 0x6f, 0x72,
+	0x44, 0x55, 0x54, 0x2e, 0x53, 0x72, 0x63, 0x44, 0x73, 0x74, 0x50, 0x61, 0x74, 0x68, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x0b, 0x73, 0x72, 0x63, 0x44, 0x73, 0x74, 0x50, 0x61, 0x74, 0x68,
+	0x73, 0x1a, 0x3e, 0x0a, 0x10, 0x53, 0x72, 0x63, 0x44, 0x73, 0x74, 0x50, 0x61, 0x74, 0x68, 0x73,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38,
+	0x01, 0x22, 0x51, 0x0a, 0x11, 0x53, 0x74, 0x61, 0x72, 0x74, 0x46, 0x69, 0x78, 0x74, 0x75, 0x72,
+	0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x28, 0x0a, 0x06, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x74, 0x61, 0x73,
+	0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x06, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x73, 0x22, 0x8e, 0x02, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x16,
+	0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x34, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e,
+	0x63, 0x6f, 0x72, 0x65, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x35, 0x0a, 0x08,
+	0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19,
+	0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72,
+	0x2e, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x52, 0x08, 0x63, 0x61, 0x74, 0x65, 0x67,
+	0x6f, 0x72, 0x79, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x63, 0x65, 0x6e, 0x61, 0x72, 0x69, 0x6f, 0x5f,
+	0x73, 0x74, 0x65, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x63, 0x65, 0x6e,
+	0x61, 0x72, 0x69, 0x6f, 0x53, 0x74, 0x65, 0x70, 0x22, 0x5b, 0x0a, 0x08, 0x43, 0x61, 0x74, 0x65,
+	0x67, 0x6f, 0x72, 0x79, 0x12, 0x18, 0x0a, 0x14, 0x43, 0x41, 0x54, 0x45, 0x47, 0x4f, 0x52, 0x59,
+	0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x09,
+	0x0a, 0x05, 0x49, 0x4e, 0x46, 0x52, 0x41, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07, 0x50, 0x52, 0x4f,
+	0x44, 0x55, 0x43, 0x54, 0x10, 0x02, 0x12, 0x0c, 0x0a, 0x08, 0x54, 0x45, 0x53, 0x54, 0x5f, 0x42,
+	0x55, 0x47, 0x10, 0x03, 0x12, 0x0f, 0x0a, 0x0b, 0x45, 0x4e, 0x56, 0x49, 0x52, 0x4f, 0x4e, 0x4d,
+	0x45, 0x4e, 0x54, 0x10, 0x04, 0x22, 0x4d, 0x0a, 0x0d, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x4c, 0x6f,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x69,
+	0x6e, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x6c, 0x69, 0x6e, 0x65, 0x12, 0x14,
+	0x0a, 0x05, 0x73, 0x74, 0x61, 0x63, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73,
+	0x74, 0x61, 0x63, 0x6b, 0x22, 0xa2, 0x01, 0x0a, 0x0e, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65,
+	0x64, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x29, 0x0a, 0x06, 0x65, 0x6e, 0x74, 0x69, 0x74,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63,
+	0x6f, 0x72, 0x65, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x06, 0x65, 0x6e, 0x74, 0x69,
+	0x74, 0x79, 0x12, 0x23, 0x0a, 0x04, 0x73, 0x6b, 0x69, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0f, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53, 0x6b, 0x69,
+	0x70, 0x52, 0x04, 0x73, 0x6b, 0x69, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x6f, 0x70, 0x73, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x68, 0x6f, 0x70, 0x73, 0x12, 0x2c, 0x0a, 0x12, 0x73,
+	0x74, 0x61, 0x72, 0x74, 0x5f, 0x66, 0x69, 0x78, 0x74, 0x75, 0x72, 0x65, 0x5f, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x73, 0x74, 0x61, 0x72, 0x74, 0x46, 0x69,
+	0x78, 0x74, 0x75, 0x72, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x37, 0x0a, 0x09, 0x54, 0x69, 0x6d,
+	0x69, 0x6e, 0x67, 0x4c, 0x6f, 0x67, 0x12, 0x2a, 0x0a, 0x04, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65,
+	0x2e, 0x54, 0x69, 0x6d, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x67, 0x65, 0x52, 0x04, 0x72, 0x6f,
+	0x6f, 0x74, 0x22, 0xc7, 0x01, 0x0a, 0x0b, 0x54, 0x69, 0x6d, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61,
+	0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x39, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f,
+	0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d,
+	0x65, 0x12, 0x35, 0x0a, 0x08, 0x65, 0x6e, 0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x07, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x32, 0x0a, 0x08, 0x63, 0x68, 0x69, 0x6c,
+	0x64, 0x72, 0x65, 0x6e, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x74, 0x61, 0x73,
+	0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x54, 0x69, 0x6d, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61,
+	0x67, 0x65, 0x52, 0x08, 0x63, 0x68, 0x69, 0x6c, 0x64, 0x72, 0x65, 0x6e, 0x22, 0x7c, 0x0a, 0x0b,
+	0x52, 0x75, 0x6e, 0x4c, 0x6f, 0x67, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x2e, 0x0a, 0x04, 0x74,
+	0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74,
+	0x65, 0x78, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x12,
+	0x29, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13,
 	0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x4c, 0x6f, 0x67, 0x4c, 0x65,
-	0x76, 0x65, 0x6c, 0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x22, 0x8b, 0x01, 0x0a, 0x10, 0x45,
-	0x6e, 0x74, 0x69, 0x74, 0x79, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12,
+	0x76, 0x65, 0x6c, 0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x22, 0xa7, 0x01, 0x0a, 0x10, 0x45,
+	0x6e, 0x74, 0x69, 0x74, 0x79, 0x53, 0x74, 0x61, 0x72, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12,
 	0x2e, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
 	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
 	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12,
-	0x1f, 0x0a, 0x0b, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x4e, 0x61, 0x6d, 0x65,
-	0x12, 0x26, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x10, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x45, 0x72, 0x72, 0x6f,
-	0x72, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0xbb, 0x01, 0x0a, 0x0e, 0x45, 0x6e, 0x74,
-	0x69, 0x74, 0x79, 0x45, 0x6e, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x2e, 0x0a, 0x04, 0x74,
-	0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
-	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
-	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x65,
-	0x6e, 0x74, 0x69, 0x74, 0x79, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x0a, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x23, 0x0a, 0x04,
-	0x73, 0x6b, 0x69, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x74, 0x61, 0x73,
-	0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53, 0x6b, 0x69, 0x70, 0x52, 0x04, 0x73, 0x6b, 0x69,
-	0x70, 0x12, 0x33, 0x0a, 0x0a, 0x74, 0x69, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x6c, 0x6f, 0x67, 0x18,
-	0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72,
-	0x65, 0x2e, 0x54, 0x69, 0x6d, 0x69, 0x6e, 0x67, 0x4c, 0x6f, 0x67, 0x52, 0x09, 0x74, 0x69, 0x6d,
-	0x69, 0x6e, 0x67, 0x4c, 0x6f, 0x67, 0x22, 0x35, 0x0a, 0x12, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79,
-	0x43, 0x6f, 0x70, 0x79, 0x45, 0x6e, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1f, 0x0a, 0x0b,
-	0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x0a, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x20, 0x0a,
-	0x04, 0x53, 0x6b, 0x69, 0x70, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x73,
-	0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x73, 0x22,
-	0xa1, 0x01, 0x0a, 0x07, 0x44, 0x55, 0x54, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x32, 0x0a, 0x08, 0x66,
-	0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e,
-	0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x44, 0x55, 0x54, 0x46, 0x65, 0x61,
-	0x74, 0x75, 0x72, 0x65, 0x73, 0x52, 0x08, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x12,
-	0x1d, 0x0a, 0x0a, 0x6f, 0x73, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x09, 0x6f, 0x73, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x3d,
-	0x0a, 0x1b, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x5f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f,
-	0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x73, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x18, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x42, 0x75, 0x69, 0x6c,
-	0x64, 0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x73, 0x55, 0x72, 0x6c, 0x4a, 0x04, 0x08,
-	0x01, 0x10, 0x02, 0x22, 0xfc, 0x01, 0x0a, 0x0c, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x53,
-	0x74, 0x61, 0x74, 0x65, 0x12, 0x52, 0x0a, 0x0f, 0x6c, 0x6f, 0x67, 0x5f, 0x69, 0x6e, 0x6f, 0x64,
-	0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2a, 0x2e,
-	0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66,
-	0x6f, 0x53, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x4c, 0x6f, 0x67, 0x49, 0x6e, 0x6f, 0x64, 0x65, 0x53,
-	0x69, 0x7a, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0d, 0x6c, 0x6f, 0x67, 0x49, 0x6e,
-	0x6f, 0x64, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x73, 0x12, 0x2c, 0x0a, 0x12, 0x75, 0x6e, 0x69, 0x66,
-	0x69, 0x65, 0x64, 0x5f, 0x6c, 0x6f, 0x67, 0x5f, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x75, 0x6e, 0x69, 0x66, 0x69, 0x65, 0x64, 0x4c, 0x6f, 0x67,
-	0x43, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x12, 0x28, 0x0a, 0x10, 0x63, 0x72, 0x61, 0x73, 0x68, 0x5f,
-	0x66, 0x69, 0x6c, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09,
-	0x52, 0x0e, 0x63, 0x72, 0x61, 0x73, 0x68, 0x46, 0x69, 0x6c, 0x65, 0x50, 0x61, 0x74, 0x68, 0x73,
-	0x1a, 0x40, 0x0a, 0x12, 0x4c, 0x6f, 0x67, 0x49, 0x6e, 0x6f, 0x64, 0x65, 0x53, 0x69, 0x7a, 0x65,
-	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x04, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
-	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02,
-	0x38, 0x01, 0x22, 0x8f, 0x03, 0x0a, 0x15, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x4f, 0x70, 0x65, 0x72,
-	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2d, 0x0a, 0x05,
-	0x72, 0x65, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x61,
-	0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x52, 0x65, 0x73,
-	0x65, 0x74, 0x48, 0x00, 0x52, 0x05, 0x72, 0x65, 0x73, 0x65, 0x74, 0x12, 0x34, 0x0a, 0x08, 0x70,
-	0x72, 0x65, 0x5f, 0x74, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e,
-	0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x50,
-	0x72, 0x65, 0x54, 0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x07, 0x70, 0x72, 0x65, 0x54, 0x65, 0x73,
-	0x74, 0x12, 0x37, 0x0a, 0x09, 0x70, 0x6f, 0x73, 0x74, 0x5f, 0x74, 0x65, 0x73, 0x74, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65,
-	0x2e, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x50, 0x6f, 0x73, 0x74, 0x54, 0x65, 0x73, 0x74, 0x48, 0x00,
-	0x52, 0x08, 0x70, 0x6f, 0x73, 0x74, 0x54, 0x65, 0x73, 0x74, 0x12, 0x33, 0x0a, 0x06, 0x73, 0x74,
-	0x61, 0x74, 0x75, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x74, 0x61, 0x73,
-	0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x47, 0x65, 0x74, 0x53,
-	0x74, 0x61, 0x74, 0x75, 0x73, 0x48, 0x00, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12,
-	0x37, 0x0a, 0x09, 0x73, 0x65, 0x74, 0x5f, 0x64, 0x69, 0x72, 0x74, 0x79, 0x18, 0x05, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53,
-	0x74, 0x61, 0x63, 0x6b, 0x53, 0x65, 0x74, 0x44, 0x69, 0x72, 0x74, 0x79, 0x48, 0x00, 0x52, 0x08,
-	0x73, 0x65, 0x74, 0x44, 0x69, 0x72, 0x74, 0x79, 0x12, 0x33, 0x0a, 0x06, 0x65, 0x72, 0x72, 0x6f,
-	0x72, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e,
-	0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x47, 0x65, 0x74, 0x45, 0x72, 0x72,
-	0x6f, 0x72, 0x73, 0x48, 0x00, 0x52, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x12, 0x2d, 0x0a,
-	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74,
-	0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x56, 0x61,
-	0x6c, 0x75, 0x65, 0x48, 0x00, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x42, 0x06, 0x0a, 0x04,
-	0x74, 0x79, 0x70, 0x65, 0x22, 0x0c, 0x0a, 0x0a, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x52, 0x65, 0x73,
-	0x65, 0x74, 0x22, 0x56, 0x0a, 0x0c, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x50, 0x72, 0x65, 0x54, 0x65,
-	0x73, 0x74, 0x12, 0x29, 0x0a, 0x06, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x45,
-	0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x06, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x1b, 0x0a,
-	0x09, 0x68, 0x61, 0x73, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08,
-	0x52, 0x08, 0x68, 0x61, 0x73, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x57, 0x0a, 0x0d, 0x53, 0x74,
-	0x61, 0x63, 0x6b, 0x50, 0x6f, 0x73, 0x74, 0x54, 0x65, 0x73, 0x74, 0x12, 0x29, 0x0a, 0x06, 0x65,
-	0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x61,
-	0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x06,
-	0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x68, 0x61, 0x73, 0x5f, 0x65, 0x72,
-	0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x68, 0x61, 0x73, 0x45, 0x72,
-	0x72, 0x6f, 0x72, 0x22, 0x10, 0x0a, 0x0e, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x47, 0x65, 0x74, 0x53,
-	0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x25, 0x0a, 0x0d, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x53, 0x65,
-	0x74, 0x44, 0x69, 0x72, 0x74, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x64, 0x69, 0x72, 0x74, 0x79, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x64, 0x69, 0x72, 0x74, 0x79, 0x22, 0x10, 0x0a, 0x0e,
-	0x53, 0x74, 0x61, 0x63, 0x6b, 0x47, 0x65, 0x74, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x22, 0x0c,
-	0x0a, 0x0a, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x22, 0xd8, 0x01, 0x0a,
-	0x16, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x66, 0x61, 0x74, 0x61, 0x6c,
-	0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x66, 0x61,
-	0x74, 0x61, 0x6c, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x2e, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74,
-	0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e,
-	0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
-	0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x28, 0x0a, 0x06, 0x65, 0x72, 0x72, 0x6f,
-	0x72, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e,
-	0x63, 0x6f, 0x72, 0x65, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x06, 0x65, 0x72, 0x72, 0x6f,
-	0x72, 0x73, 0x12, 0x24, 0x0a, 0x0e, 0x74, 0x65, 0x73, 0x74, 0x5f, 0x68, 0x61, 0x73, 0x5f, 0x65,
-	0x72, 0x72, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x74, 0x65, 0x73, 0x74,
-	0x48, 0x61, 0x73, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x69, 0x78, 0x74,
-	0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x66, 0x69,
-	0x78, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x40, 0x0a, 0x0e, 0x48, 0x65, 0x61, 0x72, 0x74,
-	0x62, 0x65, 0x61, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x2e, 0x0a, 0x04, 0x74, 0x69, 0x6d,
-	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
-	0x61, 0x6d, 0x70, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x22, 0x34, 0x0a, 0x0a, 0x53, 0x74, 0x72,
-	0x69, 0x6e, 0x67, 0x50, 0x61, 0x69, 0x72, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
-	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x2a,
-	0x23, 0x0a, 0x0a, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x54, 0x79, 0x70, 0x65, 0x12, 0x08, 0x0a,
-	0x04, 0x54, 0x45, 0x53, 0x54, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x46, 0x49, 0x58, 0x54, 0x55,
-	0x52, 0x45, 0x10, 0x01, 0x2a, 0x23, 0x0a, 0x0c, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64,
-	0x4d, 0x6f, 0x64, 0x65, 0x12, 0x09, 0x0a, 0x05, 0x42, 0x41, 0x54, 0x43, 0x48, 0x10, 0x00, 0x12,
-	0x08, 0x0a, 0x04, 0x4c, 0x41, 0x5a, 0x59, 0x10, 0x01, 0x2a, 0x2d, 0x0a, 0x0b, 0x53, 0x74, 0x61,
-	0x63, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x09, 0x0a, 0x05, 0x47, 0x52, 0x45, 0x45,
-	0x4e, 0x10, 0x00, 0x12, 0x07, 0x0a, 0x03, 0x52, 0x45, 0x44, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06,
-	0x59, 0x45, 0x4c, 0x4c, 0x4f, 0x57, 0x10, 0x02, 0x32, 0xcf, 0x05, 0x0a, 0x0b, 0x54, 0x65, 0x73,
-	0x74, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x51, 0x0a, 0x0c, 0x4c, 0x69, 0x73, 0x74,
-	0x45, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x1e, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e,
-	0x63, 0x6f, 0x72, 0x65, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65,
-	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e,
-	0x63, 0x6f, 0x72, 0x65, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65,
-	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x60, 0x0a, 0x11, 0x47,
-	0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x56, 0x61, 0x72, 0x73,
-	0x12, 0x23, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x47, 0x6c, 0x6f,
-	0x62, 0x61, 0x6c, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x56, 0x61, 0x72, 0x73, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72,
-	0x65, 0x2e, 0x47, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x56,
-	0x61, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x49, 0x0a,
-	0x08, 0x52, 0x75, 0x6e, 0x54, 0x65, 0x73, 0x74, 0x73, 0x12, 0x1a, 0x2e, 0x74, 0x61, 0x73, 0x74,
-	0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x52, 0x75, 0x6e, 0x54, 0x65, 0x73, 0x74, 0x73, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72,
-	0x65, 0x2e, 0x52, 0x75, 0x6e, 0x54, 0x65, 0x73, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x12, 0x4b, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x44,
-	0x55, 0x54, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1c, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f,
-	0x72, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x55, 0x54, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65,
-	0x2e, 0x47, 0x65, 0x74, 0x44, 0x55, 0x54, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x5a, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x53, 0x79, 0x73, 0x49,
-	0x6e, 0x66, 0x6f, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x21, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e,
-	0x63, 0x6f, 0x72, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x53,
-	0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x74, 0x61,
-	0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x79, 0x73, 0x49, 0x6e,
-	0x66, 0x6f, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
-	0x00, 0x12, 0x57, 0x0a, 0x0e, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x53, 0x79, 0x73, 0x49,
-	0x6e, 0x66, 0x6f, 0x12, 0x20, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e,
-	0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72,
-	0x65, 0x2e, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x6f, 0x0a, 0x16, 0x44, 0x6f,
-	0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x50, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x42, 0x75, 0x6e,
-	0x64, 0x6c, 0x65, 0x73, 0x12, 0x28, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65,
-	0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x50, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65,
-	0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x29,
-	0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x6c,
-	0x6f, 0x61, 0x64, 0x50, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65,
-	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4d, 0x0a, 0x0a, 0x53,
-	0x74, 0x72, 0x65, 0x61, 0x6d, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x1c, 0x2e, 0x74, 0x61, 0x73, 0x74,
-	0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x46, 0x69, 0x6c, 0x65,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63,
-	0x6f, 0x72, 0x65, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x42, 0x2d, 0x5a, 0x2b, 0x67, 0x6f,
-	0x2e, 0x63, 0x68, 0x72, 0x6f, 0x6d, 0x69, 0x75, 0x6d, 0x2e, 0x6f, 0x72, 0x67, 0x2f, 0x74, 0x61,
-	0x73, 0x74, 0x2f, 0x63, 0x6f, 0x72, 0x65, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c,
-	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x33,
+	0x29, 0x0a, 0x06, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x11, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x45, 0x6e, 0x74, 0x69,
+	0x74, 0x79, 0x52, 0x06, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x17, 0x0a, 0x07, 0x6f, 0x75,
+	0x74, 0x5f, 0x64, 0x69, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6f, 0x75, 0x74,
+	0x44, 0x69, 0x72, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f,
+	0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e,
+	0x63, 0x65, 0x49, 0x64, 0x22, 0xc1, 0x01, 0x0a, 0x0e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x4c,
+	0x6f, 0x67, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x2e, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x65, 0x6e, 0x74, 0x69, 0x74,
+	0x79, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x65, 0x6e,
+	0x74, 0x69, 0x74, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x12, 0x29, 0x0a, 0x05,
+	0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x74, 0x61,
+	0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x4c, 0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c,
+	0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x6e, 0x73, 0x74, 0x61,
+	0x6e, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x69, 0x6e,
+	0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x22, 0xea, 0x01, 0x0a, 0x10, 0x45, 0x6e, 0x74,
+	0x69, 0x74, 0x79, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x2e, 0x0a,
+	0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x1f, 0x0a,
+	0x0b, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0a, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x26,
+	0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e,
+	0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52,
+	0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e,
+	0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x69, 0x6e, 0x73,
+	0x74, 0x61, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x12, 0x3c, 0x0a, 0x0d, 0x66, 0x69, 0x78, 0x74, 0x75,
+	0x72, 0x65, 0x5f, 0x70, 0x68, 0x61, 0x73, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17,
+	0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x46, 0x69, 0x78, 0x74, 0x75,
+	0x72, 0x65, 0x50, 0x68, 0x61, 0x73, 0x65, 0x52, 0x0c, 0x66, 0x69, 0x78, 0x74, 0x75, 0x72, 0x65,
+	0x50, 0x68, 0x61, 0x73, 0x65, 0x22, 0x93, 0x02, 0x0a, 0x0e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79,
+	0x45, 0x6e, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x2e, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x65, 0x6e, 0x74, 0x69,
+	0x74, 0x79, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x65,
+	0x6e, 0x74, 0x69, 0x74, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x23, 0x0a, 0x04, 0x73, 0x6b, 0x69,
+	0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63,
+	0x6f, 0x72, 0x65, 0x2e, 0x53, 0x6b, 0x69, 0x70, 0x52, 0x04, 0x73, 0x6b, 0x69, 0x70, 0x12, 0x33,
+	0x0a, 0x0a, 0x74, 0x69, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x6c, 0x6f, 0x67, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x14, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x54,
+	0x69, 0x6d, 0x69, 0x6e, 0x67, 0x4c, 0x6f, 0x67, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x69, 0x6e, 0x67,
+	0x4c, 0x6f, 0x67, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f,
+	0x69, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e,
+	0x63, 0x65, 0x49, 0x64, 0x12, 0x23, 0x0a, 0x0d, 0x63, 0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65, 0x64,
+	0x5f, 0x76, 0x61, 0x72, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x63, 0x6f, 0x6e,
+	0x73, 0x75, 0x6d, 0x65, 0x64, 0x56, 0x61, 0x72, 0x73, 0x4a, 0x04, 0x08, 0x05, 0x10, 0x06, 0x52,
+	0x0a, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x22, 0x8c, 0x01, 0x0a, 0x15,
+	0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x51, 0x75, 0x61, 0x72, 0x61, 0x6e, 0x74, 0x69, 0x6e, 0x65,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x2e, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x29, 0x0a, 0x06, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72,
+	0x65, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x06, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79,
+	0x12, 0x18, 0x0a, 0x07, 0x63, 0x72, 0x61, 0x73, 0x68, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x07, 0x63, 0x72, 0x61, 0x73, 0x68, 0x65, 0x73, 0x22, 0x35, 0x0a, 0x12, 0x45, 0x6e,
+	0x74, 0x69, 0x74, 0x79, 0x43, 0x6f, 0x70, 0x79, 0x45, 0x6e, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x12, 0x1f, 0x0a, 0x0b, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x4e, 0x61, 0x6d,
+	0x65, 0x22, 0x5c, 0x0a, 0x04, 0x53, 0x6b, 0x69, 0x70, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x61,
+	0x73, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x72, 0x65, 0x61, 0x73,
+	0x6f, 0x6e, 0x73, 0x12, 0x3a, 0x0a, 0x0d, 0x74, 0x79, 0x70, 0x65, 0x64, 0x5f, 0x72, 0x65, 0x61,
+	0x73, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x61, 0x73,
+	0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53, 0x6b, 0x69, 0x70, 0x52, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x52, 0x0c, 0x74, 0x79, 0x70, 0x65, 0x64, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x73, 0x22,
+	0x93, 0x02, 0x0a, 0x0a, 0x53, 0x6b, 0x69, 0x70, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x2e,
+	0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x74,
+	0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53, 0x6b, 0x69, 0x70, 0x52, 0x65, 0x61,
+	0x73, 0x6f, 0x6e, 0x2e, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x18,
+	0x0a, 0x07, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x07, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x22, 0xba, 0x01, 0x0a, 0x04, 0x43, 0x6f, 0x64,
+	0x65, 0x12, 0x14, 0x0a, 0x10, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43,
+	0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x15, 0x0a, 0x11, 0x55, 0x4e, 0x53, 0x41, 0x54,
+	0x49, 0x53, 0x46, 0x49, 0x45, 0x44, 0x5f, 0x53, 0x57, 0x44, 0x45, 0x50, 0x10, 0x01, 0x12, 0x15,
+	0x0a, 0x11, 0x55, 0x4e, 0x53, 0x41, 0x54, 0x49, 0x53, 0x46, 0x49, 0x45, 0x44, 0x5f, 0x48, 0x57,
+	0x44, 0x45, 0x50, 0x10, 0x02, 0x12, 0x0f, 0x0a, 0x0b, 0x53, 0x48, 0x41, 0x52, 0x44, 0x45, 0x44,
+	0x5f, 0x4f, 0x55, 0x54, 0x10, 0x03, 0x12, 0x0a, 0x0a, 0x06, 0x4d, 0x41, 0x4e, 0x55, 0x41, 0x4c,
+	0x10, 0x05, 0x12, 0x0f, 0x0a, 0x0b, 0x4d, 0x49, 0x53, 0x53, 0x49, 0x4e, 0x47, 0x5f, 0x56, 0x41,
+	0x52, 0x10, 0x06, 0x12, 0x1e, 0x0a, 0x1a, 0x55, 0x4e, 0x53, 0x41, 0x54, 0x49, 0x53, 0x46, 0x49,
+	0x45, 0x44, 0x5f, 0x4b, 0x45, 0x52, 0x4e, 0x45, 0x4c, 0x5f, 0x43, 0x4d, 0x44, 0x4c, 0x49, 0x4e,
+	0x45, 0x10, 0x07, 0x12, 0x13, 0x0a, 0x0f, 0x55, 0x4e, 0x53, 0x41, 0x54, 0x49, 0x53, 0x46, 0x49,
+	0x45, 0x44, 0x5f, 0x45, 0x4e, 0x56, 0x10, 0x08, 0x22, 0x04, 0x08, 0x04, 0x10, 0x04, 0x2a, 0x05,
+	0x51, 0x55, 0x4f, 0x54, 0x41, 0x22, 0xa1, 0x01, 0x0a, 0x07, 0x44, 0x55, 0x54, 0x49, 0x6e, 0x66,
+	0x6f, 0x12, 0x32, 0x0a, 0x08, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e,
+	0x44, 0x55, 0x54, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x52, 0x08, 0x66, 0x65, 0x61,
+	0x74, 0x75, 0x72, 0x65, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x6f, 0x73, 0x5f, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6f, 0x73, 0x56, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x12, 0x3d, 0x0a, 0x1b, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x5f,
+	0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x73, 0x5f,
+	0x75, 0x72, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x18, 0x64, 0x65, 0x66, 0x61, 0x75,
+	0x6c, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x73,
+	0x55, 0x72, 0x6c, 0x4a, 0x04, 0x08, 0x01, 0x10, 0x02, 0x22, 0xfc, 0x01, 0x0a, 0x0c, 0x53, 0x79,
+	0x73, 0x49, 0x6e, 0x66, 0x6f, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x52, 0x0a, 0x0f, 0x6c, 0x6f,
+	0x67, 0x5f, 0x69, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e,
+	0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x53, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x4c, 0x6f, 0x67,
+	0x49, 0x6e, 0x6f, 0x64, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x0d, 0x6c, 0x6f, 0x67, 0x49, 0x6e, 0x6f, 0x64, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x73, 0x12, 0x2c,
+	0x0a, 0x12, 0x75, 0x6e, 0x69, 0x66, 0x69, 0x65, 0x64, 0x5f, 0x6c, 0x6f, 0x67, 0x5f, 0x63, 0x75,
+	0x72, 0x73, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x75, 0x6e, 0x69, 0x66,
+	0x69, 0x65, 0x64, 0x4c, 0x6f, 0x67, 0x43, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x12, 0x28, 0x0a, 0x10,
+	0x63, 0x72, 0x61, 0x73, 0x68, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x73,
+	0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x63, 0x72, 0x61, 0x73, 0x68, 0x46, 0x69, 0x6c,
+	0x65, 0x50, 0x61, 0x74, 0x68, 0x73, 0x1a, 0x40, 0x0a, 0x12, 0x4c, 0x6f, 0x67, 0x49, 0x6e, 0x6f,
+	0x64, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x8f, 0x03, 0x0a, 0x15, 0x53, 0x74, 0x61,
+	0x63, 0x6b, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x2d, 0x0a, 0x05, 0x72, 0x65, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x15, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53, 0x74,
+	0x61, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x65, 0x74, 0x48, 0x00, 0x52, 0x05, 0x72, 0x65, 0x73, 0x65,
+	0x74, 0x12, 0x34, 0x0a, 0x08, 0x70, 0x72, 0x65, 0x5f, 0x74, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e,
+	0x53, 0x74, 0x61, 0x63, 0x6b, 0x50, 0x72, 0x65, 0x54, 0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x07,
+	0x70, 0x72, 0x65, 0x54, 0x65, 0x73, 0x74, 0x12, 0x37, 0x0a, 0x09, 0x70, 0x6f, 0x73, 0x74, 0x5f,
+	0x74, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x74, 0x61, 0x73,
+	0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x50, 0x6f, 0x73, 0x74,
+	0x54, 0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x08, 0x70, 0x6f, 0x73, 0x74, 0x54, 0x65, 0x73, 0x74,
+	0x12, 0x33, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x19, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53, 0x74, 0x61,
+	0x63, 0x6b, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x48, 0x00, 0x52, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x37, 0x0a, 0x09, 0x73, 0x65, 0x74, 0x5f, 0x64, 0x69, 0x72,
+	0x74, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e,
+	0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x53, 0x65, 0x74, 0x44, 0x69, 0x72,
+	0x74, 0x79, 0x48, 0x00, 0x52, 0x08, 0x73, 0x65, 0x74, 0x44, 0x69, 0x72, 0x74, 0x79, 0x12, 0x33,
+	0x0a, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19,
+	0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x63, 0x6b,
+	0x47, 0x65, 0x74, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x48, 0x00, 0x52, 0x06, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x73, 0x12, 0x2d, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53,
+	0x74, 0x61, 0x63, 0x6b, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x48, 0x00, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x42, 0x06, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x22, 0x0c, 0x0a, 0x0a, 0x53, 0x74,
+	0x61, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x65, 0x74, 0x22, 0x56, 0x0a, 0x0c, 0x53, 0x74, 0x61, 0x63,
+	0x6b, 0x50, 0x72, 0x65, 0x54, 0x65, 0x73, 0x74, 0x12, 0x29, 0x0a, 0x06, 0x65, 0x6e, 0x74, 0x69,
+	0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e,
+	0x63, 0x6f, 0x72, 0x65, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x06, 0x65, 0x6e, 0x74,
+	0x69, 0x74, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x68, 0x61, 0x73, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x68, 0x61, 0x73, 0x45, 0x72, 0x72, 0x6f, 0x72,
+	0x22, 0x57, 0x0a, 0x0d, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x50, 0x6f, 0x73, 0x74, 0x54, 0x65, 0x73,
+	0x74, 0x12, 0x29, 0x0a, 0x06, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x11, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x45, 0x6e,
+	0x74, 0x69, 0x74, 0x79, 0x52, 0x06, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x1b, 0x0a, 0x09,
+	0x68, 0x61, 0x73, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x08, 0x68, 0x61, 0x73, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x10, 0x0a, 0x0e, 0x53, 0x74, 0x61,
+	0x63, 0x6b, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x25, 0x0a, 0x0d, 0x53,
+	0x74, 0x61, 0x63, 0x6b, 0x53, 0x65, 0x74, 0x44, 0x69, 0x72, 0x74, 0x79, 0x12, 0x14, 0x0a, 0x05,
+	0x64, 0x69, 0x72, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x64, 0x69, 0x72,
+	0x74, 0x79, 0x22, 0x10, 0x0a, 0x0e, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x47, 0x65, 0x74, 0x45, 0x72,
+	0x72, 0x6f, 0x72, 0x73, 0x22, 0x0c, 0x0a, 0x0a, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x56, 0x61, 0x6c,
+	0x75, 0x65, 0x22, 0xd8, 0x01, 0x0a, 0x16, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x4f, 0x70, 0x65, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f, 0x0a,
+	0x0b, 0x66, 0x61, 0x74, 0x61, 0x6c, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0a, 0x66, 0x61, 0x74, 0x61, 0x6c, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x2e,
+	0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16,
+	0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x63, 0x6b,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x28,
+	0x0a, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10,
+	0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72,
+	0x52, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x12, 0x24, 0x0a, 0x0e, 0x74, 0x65, 0x73, 0x74,
+	0x5f, 0x68, 0x61, 0x73, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0c, 0x74, 0x65, 0x73, 0x74, 0x48, 0x61, 0x73, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x1d,
+	0x0a, 0x0a, 0x66, 0x69, 0x78, 0x74, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x09, 0x66, 0x69, 0x78, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x96, 0x01,
+	0x0a, 0x0e, 0x48, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x12, 0x2e, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65,
+	0x12, 0x1f, 0x0a, 0x0b, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x4e, 0x61, 0x6d,
+	0x65, 0x12, 0x33, 0x0a, 0x07, 0x65, 0x6c, 0x61, 0x70, 0x73, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x65,
+	0x6c, 0x61, 0x70, 0x73, 0x65, 0x64, 0x22, 0x34, 0x0a, 0x0a, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67,
+	0x50, 0x61, 0x69, 0x72, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x2a, 0x23, 0x0a, 0x0a,
+	0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x54, 0x79, 0x70, 0x65, 0x12, 0x08, 0x0a, 0x04, 0x54, 0x45,
+	0x53, 0x54, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x46, 0x49, 0x58, 0x54, 0x55, 0x52, 0x45, 0x10,
+	0x01, 0x2a, 0x23, 0x0a, 0x0c, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x6f, 0x64,
+	0x65, 0x12, 0x09, 0x0a, 0x05, 0x42, 0x41, 0x54, 0x43, 0x48, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04,
+	0x4c, 0x41, 0x5a, 0x59, 0x10, 0x01, 0x2a, 0x48, 0x0a, 0x0c, 0x46, 0x69, 0x78, 0x74, 0x75, 0x72,
+	0x65, 0x50, 0x68, 0x61, 0x73, 0x65, 0x12, 0x1d, 0x0a, 0x19, 0x46, 0x49, 0x58, 0x54, 0x55, 0x52,
+	0x45, 0x5f, 0x50, 0x48, 0x41, 0x53, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46,
+	0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x45, 0x54, 0x5f, 0x55, 0x50, 0x10,
+	0x01, 0x12, 0x0d, 0x0a, 0x09, 0x54, 0x45, 0x41, 0x52, 0x5f, 0x44, 0x4f, 0x57, 0x4e, 0x10, 0x02,
+	0x2a, 0x2d, 0x0a, 0x0b, 0x53, 0x74, 0x61, 0x63, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12,
+	0x09, 0x0a, 0x05, 0x47, 0x52, 0x45, 0x45, 0x4e, 0x10, 0x00, 0x12, 0x07, 0x0a, 0x03, 0x52, 0x45,
+	0x44, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x59, 0x45, 0x4c, 0x4c, 0x4f, 0x57, 0x10, 0x02, 0x32,
+	0xcf, 0x05, 0x0a, 0x0b, 0x54, 0x65, 0x73, 0x74, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12,
+	0x51, 0x0a, 0x0c, 0x4c, 0x69, 0x73, 0x74, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12,
+	0x1e, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x4c, 0x69, 0x73, 0x74,
+	0x45, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1f, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x4c, 0x69, 0x73, 0x74,
+	0x45, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x00, 0x12, 0x60, 0x0a, 0x11, 0x47, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x52, 0x75, 0x6e, 0x74,
+	0x69, 0x6d, 0x65, 0x56, 0x61, 0x72, 0x73, 0x12, 0x23, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63,
+	0x6f, 0x72, 0x65, 0x2e, 0x47, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d,
+	0x65, 0x56, 0x61, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x74,
+	0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x47, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x52,
+	0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x56, 0x61, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x12, 0x49, 0x0a, 0x08, 0x52, 0x75, 0x6e, 0x54, 0x65, 0x73, 0x74, 0x73,
+	0x12, 0x1a, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x52, 0x75, 0x6e,
+	0x54, 0x65, 0x73, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x74,
+	0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x52, 0x75, 0x6e, 0x54, 0x65, 0x73, 0x74,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x12,
+	0x4b, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x44, 0x55, 0x54, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1c, 0x2e,
+	0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x55, 0x54,
+	0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x74, 0x61,
+	0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x55, 0x54, 0x49, 0x6e,
+	0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x5a, 0x0a, 0x0f,
+	0x47, 0x65, 0x74, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12,
+	0x21, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x53,
+	0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x22, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x47,
+	0x65, 0x74, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x57, 0x0a, 0x0e, 0x43, 0x6f, 0x6c, 0x6c,
+	0x65, 0x63, 0x74, 0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x20, 0x2e, 0x74, 0x61, 0x73,
+	0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x53, 0x79,
+	0x73, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x74,
+	0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74,
+	0x53, 0x79, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x12, 0x6f, 0x0a, 0x16, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x50, 0x72, 0x69,
+	0x76, 0x61, 0x74, 0x65, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x73, 0x12, 0x28, 0x2e, 0x74, 0x61,
+	0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64,
+	0x50, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x29, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72,
+	0x65, 0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x50, 0x72, 0x69, 0x76, 0x61, 0x74,
+	0x65, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x00, 0x12, 0x4d, 0x0a, 0x0a, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x46, 0x69, 0x6c, 0x65,
+	0x12, 0x1c, 0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d,
+	0x2e, 0x74, 0x61, 0x73, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30,
+	0x01, 0x42, 0x2d, 0x5a, 0x2b, 0x67, 0x6f, 0x2e, 0x63, 0x68, 0x72, 0x6f, 0x6d, 0x69, 0x75, 0x6d,
+	0x2e, 0x6f, 0x72, 0x67, 0x2f, 0x74, 0x61, 0x73, 0x74, 0x2f, 0x63, 0x6f, 0x72, 0x65, 0x2f, 0x69,
+	0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -4446,170 +5292,187 @@ func file_testing_proto_rawDescGZIP() []byte {
 	return file_testing_proto_rawDescData
 }
 
-var file_testing_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
-var file_testing_proto_msgTypes = make([]protoimpl.MessageInfo, 56)
+var file_testing_proto_enumTypes = make([]protoimpl.EnumInfo, 6)
+var file_testing_proto_msgTypes = make([]protoimpl.MessageInfo, 58)
 var file_testing_proto_goTypes = []interface{}{
 	(EntityType)(0),                        // 0: tast.core.EntityType
 	(DownloadMode)(0),                      // 1: tast.core.DownloadMode
-	(StackStatus)(0),                       // 2: tast.core.StackStatus
-	(*ListEntitiesRequest)(nil),            // 3: tast.core.ListEntitiesRequest
-	(*ListEntitiesResponse)(nil),           // 4: tast.core.ListEntitiesResponse
-	(*GlobalRuntimeVarsRequest)(nil),       // 5: tast.core.GlobalRuntimeVarsRequest
-	(*GlobalRuntimeVar)(nil),               // 6: tast.core.GlobalRuntimeVar
-	(*GlobalRuntimeVarsResponse)(nil),      // 7: tast.core.GlobalRuntimeVarsResponse
-	(*RunTestsRequest)(nil),                // 8: tast.core.RunTestsRequest
-	(*RunTestsResponse)(nil),               // 9: tast.core.RunTestsResponse
-	(*GetDUTInfoRequest)(nil),              // 10: tast.core.GetDUTInfoRequest
-	(*GetDUTInfoResponse)(nil),             // 11: tast.core.GetDUTInfoResponse
-	(*GetSysInfoStateRequest)(nil),         // 12: tast.core.GetSysInfoStateRequest
-	(*GetSysInfoStateResponse)(nil),        // 13: tast.core.GetSysInfoStateResponse
-	(*CollectSysInfoRequest)(nil),          // 14: tast.core.CollectSysInfoRequest
-	(*CollectSysInfoResponse)(nil),         // 15: tast.core.CollectSysInfoResponse
-	(*DownloadPrivateBundlesRequest)(nil),  // 16: tast.core.DownloadPrivateBundlesRequest
-	(*DownloadPrivateBundlesResponse)(nil), // 17: tast.core.DownloadPrivateBundlesResponse
-	(*StreamFileRequest)(nil),              // 18: tast.core.StreamFileRequest
-	(*StreamFileResponse)(nil),             // 19: tast.core.StreamFileResponse
-	(*Entity)(nil),                         // 20: tast.core.Entity
-	(*EntityContacts)(nil),                 // 21: tast.core.EntityContacts
-	(*EntityDependencies)(nil),             // 22: tast.core.EntityDependencies
-	(*EntityLegacyData)(nil),               // 23: tast.core.EntityLegacyData
-	(*RunTestsInit)(nil),                   // 24: tast.core.RunTestsInit
-	(*RunConfig)(nil),                      // 25: tast.core.RunConfig
-	(*RunTargetConfig)(nil),                // 26: tast.core.RunTargetConfig
-	(*RunDirectories)(nil),                 // 27: tast.core.RunDirectories
-	(*ServiceConfig)(nil),                  // 28: tast.core.ServiceConfig
-	(*DataFileConfig)(nil),                 // 29: tast.core.DataFileConfig
-	(*PushedFilesInfoForDUT)(nil),          // 30: tast.core.PushedFilesInfoForDUT
-	(*StartFixtureState)(nil),              // 31: tast.core.StartFixtureState
-	(*Error)(nil),                          // 32: tast.core.Error
-	(*ErrorLocation)(nil),                  // 33: tast.core.ErrorLocation
-	(*ResolvedEntity)(nil),                 // 34: tast.core.ResolvedEntity
-	(*TimingLog)(nil),                      // 35: tast.core.TimingLog
-	(*TimingStage)(nil),                    // 36: tast.core.TimingStage
-	(*RunLogEvent)(nil),                    // 37: tast.core.RunLogEvent
-	(*EntityStartEvent)(nil),               // 38: tast.core.EntityStartEvent
-	(*EntityLogEvent)(nil),                 // 39: tast.core.EntityLogEvent
-	(*EntityErrorEvent)(nil),               // 40: tast.core.EntityErrorEvent
-	(*EntityEndEvent)(nil),                 // 41: tast.core.EntityEndEvent
-	(*EntityCopyEndEvent)(nil),             // 42: tast.core.EntityCopyEndEvent
-	(*Skip)(nil),                           // 43: tast.core.Skip
-	(*DUTInfo)(nil),                        // 44: tast.core.DUTInfo
-	(*SysInfoState)(nil),                   // 45: tast.core.SysInfoState
-	(*StackOperationRequest)(nil),          // 46: tast.core.StackOperationRequest
-	(*StackReset)(nil),                     // 47: tast.core.StackReset
-	(*StackPreTest)(nil),                   // 48: tast.core.StackPreTest
-	(*StackPostTest)(nil),                  // 49: tast.core.StackPostTest
-	(*StackGetStatus)(nil),                 // 50: tast.core.StackGetStatus
-	(*StackSetDirty)(nil),                  // 51: tast.core.StackSetDirty
-	(*StackGetErrors)(nil),                 // 52: tast.core.StackGetErrors
-	(*StackValue)(nil),                     // 53: tast.core.StackValue
-	(*StackOperationResponse)(nil),         // 54: tast.core.StackOperationResponse
-	(*HeartbeatEvent)(nil),                 // 55: tast.core.HeartbeatEvent
-	(*StringPair)(nil),                     // 56: tast.core.StringPair
-	nil,                                    // 57: tast.core.PushedFilesInfoForDUT.SrcDstPathsEntry
-	nil,                                    // 58: tast.core.SysInfoState.LogInodeSizesEntry
-	(*Features)(nil),                       // 59: tast.core.Features
-	(*durationpb.Duration)(nil),            // 60: google.protobuf.Duration
-	(*timestamppb.Timestamp)(nil),          // 61: google.protobuf.Timestamp
-	(LogLevel)(0),                          // 62: tast.core.LogLevel
-	(*protocol.DUTFeatures)(nil),           // 63: tast.core.DUTFeatures
+	(FixturePhase)(0),                      // 2: tast.core.FixturePhase
+	(StackStatus)(0),                       // 3: tast.core.StackStatus
+	(Error_Category)(0),                    // 4: tast.core.Error.Category
+	(SkipReason_Code)(0),                   // 5: tast.core.SkipReason.Code
+	(*ListEntitiesRequest)(nil),            // 6: tast.core.ListEntitiesRequest
+	(*ListEntitiesResponse)(nil),           // 7: tast.core.ListEntitiesResponse
+	(*GlobalRuntimeVarsRequest)(nil),       // 8: tast.core.GlobalRuntimeVarsRequest
+	(*GlobalRuntimeVar)(nil),               // 9: tast.core.GlobalRuntimeVar
+	(*GlobalRuntimeVarsResponse)(nil),      // 10: tast.core.GlobalRuntimeVarsResponse
+	(*RunTestsRequest)(nil),                // 11: tast.core.RunTestsRequest
+	(*RunTestsResponse)(nil),               // 12: tast.core.RunTestsResponse
+	(*GetDUTInfoRequest)(nil),              // 13: tast.core.GetDUTInfoRequest
+	(*GetDUTInfoResponse)(nil),             // 14: tast.core.GetDUTInfoResponse
+	(*GetSysInfoStateRequest)(nil),         // 15: tast.core.GetSysInfoStateRequest
+	(*GetSysInfoStateResponse)(nil),        // 16: tast.core.GetSysInfoStateResponse
+	(*CollectSysInfoRequest)(nil),          // 17: tast.core.CollectSysInfoRequest
+	(*CollectSysInfoResponse)(nil),         // 18: tast.core.CollectSysInfoResponse
+	(*DownloadPrivateBundlesRequest)(nil),  // 19: tast.core.DownloadPrivateBundlesRequest
+	(*DownloadPrivateBundlesResponse)(nil), // 20: tast.core.DownloadPrivateBundlesResponse
+	(*StreamFileRequest)(nil),              // 21: tast.core.StreamFileRequest
+	(*StreamFileResponse)(nil),             // 22: tast.core.StreamFileResponse
+	(*Entity)(nil),                         // 23: tast.core.Entity
+	(*EntityContacts)(nil),                 // 24: tast.core.EntityContacts
+	(*EntityDependencies)(nil),             // 25: tast.core.EntityDependencies
+	(*EntityLegacyData)(nil),               // 26: tast.core.EntityLegacyData
+	(*RunTestsInit)(nil),                   // 27: tast.core.RunTestsInit
+	(*RunConfig)(nil),                      // 28: tast.core.RunConfig
+	(*RunTargetConfig)(nil),                // 29: tast.core.RunTargetConfig
+	(*RunDirectories)(nil),                 // 30: tast.core.RunDirectories
+	(*ServiceConfig)(nil),                  // 31: tast.core.ServiceConfig
+	(*DataFileConfig)(nil),                 // 32: tast.core.DataFileConfig
+	(*PushedFilesInfoForDUT)(nil),          // 33: tast.core.PushedFilesInfoForDUT
+	(*StartFixtureState)(nil),              // 34: tast.core.StartFixtureState
+	(*Error)(nil),                          // 35: tast.core.Error
+	(*ErrorLocation)(nil),                  // 36: tast.core.ErrorLocation
+	(*ResolvedEntity)(nil),                 // 37: tast.core.ResolvedEntity
+	(*TimingLog)(nil),                      // 38: tast.core.TimingLog
+	(*TimingStage)(nil),                    // 39: tast.core.TimingStage
+	(*RunLogEvent)(nil),                    // 40: tast.core.RunLogEvent
+	(*EntityStartEvent)(nil),               // 41: tast.core.EntityStartEvent
+	(*EntityLogEvent)(nil),                 // 42: tast.core.EntityLogEvent
+	(*EntityErrorEvent)(nil),               // 43: tast.core.EntityErrorEvent
+	(*EntityEndEvent)(nil),                 // 44: tast.core.EntityEndEvent
+	(*EntityQuarantineEvent)(nil),          // 45: tast.core.EntityQuarantineEvent
+	(*EntityCopyEndEvent)(nil),             // 46: tast.core.EntityCopyEndEvent
+	(*Skip)(nil),                           // 47: tast.core.Skip
+	(*SkipReason)(nil),                     // 48: tast.core.SkipReason
+	(*DUTInfo)(nil),                        // 49: tast.core.DUTInfo
+	(*SysInfoState)(nil),                   // 50: tast.core.SysInfoState
+	(*StackOperationRequest)(nil),          // 51: tast.core.StackOperationRequest
+	(*StackReset)(nil),                     // 52: tast.core.StackReset
+	(*StackPreTest)(nil),                   // 53: tast.core.StackPreTest
+	(*StackPostTest)(nil),                  // 54: tast.core.StackPostTest
+	(*StackGetStatus)(nil),                 // 55: tast.core.StackGetStatus
+	(*StackSetDirty)(nil),                  // 56: tast.core.StackSetDirty
+	(*StackGetErrors)(nil),                 // 57: tast.core.StackGetErrors
+	(*StackValue)(nil),                     // 58: tast.core.StackValue
+	(*StackOperationResponse)(nil),         // 59: tast.core.StackOperationResponse
+	(*HeartbeatEvent)(nil),                 // 60: tast.core.HeartbeatEvent
+	(*StringPair)(nil),                     // 61: tast.core.StringPair
+	nil,                                    // 62: tast.core.PushedFilesInfoForDUT.SrcDstPathsEntry
+	nil,                                    // 63: tast.core.SysInfoState.LogInodeSizesEntry
+	(*Features)(nil),                       // 64: tast.core.Features
+	(*durationpb.Duration)(nil),            // 65: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil),          // 66: google.protobuf.Timestamp
+	(LogLevel)(0),                          // 67: tast.core.LogLevel
+	(*protocol.DUTFeatures)(nil),           // 68: tast.core.DUTFeatures
 }
 var file_testing_proto_depIdxs = []int32{
-	59, // 0: tast.core.ListEntitiesRequest.features:type_name -> tast.core.Features
-	34, // 1: tast.core.ListEntitiesResponse.entities:type_name -> tast.core.ResolvedEntity
-	6,  // 2: tast.core.GlobalRuntimeVarsResponse.vars:type_name -> tast.core.GlobalRuntimeVar
-	24, // 3: tast.core.RunTestsRequest.run_tests_init:type_name -> tast.core.RunTestsInit
-	54, // 4: tast.core.RunTestsRequest.stack_operation_response:type_name -> tast.core.StackOperationResponse
-	37, // 5: tast.core.RunTestsResponse.run_log:type_name -> tast.core.RunLogEvent
-	38, // 6: tast.core.RunTestsResponse.entity_start:type_name -> tast.core.EntityStartEvent
-	39, // 7: tast.core.RunTestsResponse.entity_log:type_name -> tast.core.EntityLogEvent
-	40, // 8: tast.core.RunTestsResponse.entity_error:type_name -> tast.core.EntityErrorEvent
-	41, // 9: tast.core.RunTestsResponse.entity_end:type_name -> tast.core.EntityEndEvent
-	42, // 10: tast.core.RunTestsResponse.entity_copy_end:type_name -> tast.core.EntityCopyEndEvent
-	46, // 11: tast.core.RunTestsResponse.stack_operation:type_name -> tast.core.StackOperationRequest
-	55, // 12: tast.core.RunTestsResponse.heartbeat:type_name -> tast.core.HeartbeatEvent
-	44, // 13: tast.core.GetDUTInfoResponse.dut_info:type_name -> tast.core.DUTInfo
-	45, // 14: tast.core.GetSysInfoStateResponse.state:type_name -> tast.core.SysInfoState
-	45, // 15: tast.core.CollectSysInfoRequest.initial_state:type_name -> tast.core.SysInfoState
-	28, // 16: tast.core.DownloadPrivateBundlesRequest.service_config:type_name -> tast.core.ServiceConfig
-	0,  // 17: tast.core.Entity.type:type_name -> tast.core.EntityType
-	22, // 18: tast.core.Entity.dependencies:type_name -> tast.core.EntityDependencies
-	21, // 19: tast.core.Entity.contacts:type_name -> tast.core.EntityContacts
-	23, // 20: tast.core.Entity.legacy_data:type_name -> tast.core.EntityLegacyData
-	56, // 21: tast.core.Entity.search_flags:type_name -> tast.core.StringPair
-	60, // 22: tast.core.EntityLegacyData.timeout:type_name -> google.protobuf.Duration
-	25, // 23: tast.core.RunTestsInit.run_config:type_name -> tast.core.RunConfig
-	27, // 24: tast.core.RunConfig.dirs:type_name -> tast.core.RunDirectories
-	59, // 25: tast.core.RunConfig.features:type_name -> tast.core.Features
-	28, // 26: tast.core.RunConfig.service_config:type_name -> tast.core.ServiceConfig
-	29, // 27: tast.core.RunConfig.data_file_config:type_name -> tast.core.DataFileConfig
-	31, // 28: tast.core.RunConfig.start_fixture_state:type_name -> tast.core.StartFixtureState
-	60, // 29: tast.core.RunConfig.heartbeat_interval:type_name -> google.protobuf.Duration
-	60, // 30: tast.core.RunConfig.system_services_timeout:type_name -> google.protobuf.Duration
-	26, // 31: tast.core.RunConfig.target:type_name -> tast.core.RunTargetConfig
-	60, // 32: tast.core.RunConfig.msg_timeout:type_name -> google.protobuf.Duration
-	60, // 33: tast.core.RunConfig.wait_until_ready_timeout:type_name -> google.protobuf.Duration
-	30, // 34: tast.core.RunConfig.pushed_files_info:type_name -> tast.core.PushedFilesInfoForDUT
-	27, // 35: tast.core.RunTargetConfig.dirs:type_name -> tast.core.RunDirectories
-	60, // 36: tast.core.RunTargetConfig.msg_timeout:type_name -> google.protobuf.Duration
-	60, // 37: tast.core.RunTargetConfig.system_services_timeout:type_name -> google.protobuf.Duration
-	60, // 38: tast.core.RunTargetConfig.wait_until_ready_timeout:type_name -> google.protobuf.Duration
-	1,  // 39: tast.core.DataFileConfig.download_mode:type_name -> tast.core.DownloadMode
-	57, // 40: tast.core.PushedFilesInfoForDUT.src_dst_paths:type_name -> tast.core.PushedFilesInfoForDUT.SrcDstPathsEntry
-	32, // 41: tast.core.StartFixtureState.errors:type_name -> tast.core.Error
-	33, // 42: tast.core.Error.location:type_name -> tast.core.ErrorLocation
-	20, // 43: tast.core.ResolvedEntity.entity:type_name -> tast.core.Entity
-	43, // 44: tast.core.ResolvedEntity.skip:type_name -> tast.core.Skip
-	36, // 45: tast.core.TimingLog.root:type_name -> tast.core.TimingStage
-	61, // 46: tast.core.TimingStage.start_time:type_name -> google.protobuf.Timestamp
-	61, // 47: tast.core.TimingStage.end_time:type_name -> google.protobuf.Timestamp
-	36, // 48: tast.core.TimingStage.children:type_name -> tast.core.TimingStage
-	61, // 49: tast.core.RunLogEvent.time:type_name -> google.protobuf.Timestamp
-	62, // 50: tast.core.RunLogEvent.level:type_name -> tast.core.LogLevel
-	61, // 51: tast.core.EntityStartEvent.time:type_name -> google.protobuf.Timestamp
-	20, // 52: tast.core.EntityStartEvent.entity:type_name -> tast.core.Entity
-	61, // 53: tast.core.EntityLogEvent.time:type_name -> google.protobuf.Timestamp
-	62, // 54: tast.core.EntityLogEvent.level:type_name -> tast.core.LogLevel
-	61, // 55: tast.core.EntityErrorEvent.time:type_name -> google.protobuf.Timestamp
-	32, // 56: tast.core.EntityErrorEvent.error:type_name -> tast.core.Error
-	61, // 57: tast.core.EntityEndEvent.time:type_name -> google.protobuf.Timestamp
-	43, // 58: tast.core.EntityEndEvent.skip:type_name -> tast.core.Skip
-	35, // 59: tast.core.EntityEndEvent.timing_log:type_name -> tast.core.TimingLog
-	63, // 60: tast.core.DUTInfo.features:type_name -> tast.core.DUTFeatures
-	58, // 61: tast.core.SysInfoState.log_inode_sizes:type_name -> tast.core.SysInfoState.LogInodeSizesEntry
-	47, // 62: tast.core.StackOperationRequest.reset:type_name -> tast.core.StackReset
-	48, // 63: tast.core.StackOperationRequest.pre_test:type_name -> tast.core.StackPreTest
-	49, // 64: tast.core.StackOperationRequest.post_test:type_name -> tast.core.StackPostTest
-	50, // 65: tast.core.StackOperationRequest.status:type_name -> tast.core.StackGetStatus
-	51, // 66: tast.core.StackOperationRequest.set_dirty:type_name -> tast.core.StackSetDirty
-	52, // 67: tast.core.StackOperationRequest.errors:type_name -> tast.core.StackGetErrors
-	53, // 68: tast.core.StackOperationRequest.value:type_name -> tast.core.StackValue
-	20, // 69: tast.core.StackPreTest.entity:type_name -> tast.core.Entity
-	20, // 70: tast.core.StackPostTest.entity:type_name -> tast.core.Entity
-	2,  // 71: tast.core.StackOperationResponse.status:type_name -> tast.core.StackStatus
-	32, // 72: tast.core.StackOperationResponse.errors:type_name -> tast.core.Error
-	61, // 73: tast.core.HeartbeatEvent.time:type_name -> google.protobuf.Timestamp
-	3,  // 74: tast.core.TestService.ListEntities:input_type -> tast.core.ListEntitiesRequest
-	5,  // 75: tast.core.TestService.GlobalRuntimeVars:input_type -> tast.core.GlobalRuntimeVarsRequest
-	8,  // 76: tast.core.TestService.RunTests:input_type -> tast.core.RunTestsRequest
-	10, // 77: tast.core.TestService.GetDUTInfo:input_type -> tast.core.GetDUTInfoRequest
-	12, // 78: tast.core.TestService.GetSysInfoState:input_type -> tast.core.GetSysInfoStateRequest
-	14, // 79: tast.core.TestService.CollectSysInfo:input_type -> tast.core.CollectSysInfoRequest
-	16, // 80: tast.core.TestService.DownloadPrivateBundles:input_type -> tast.core.DownloadPrivateBundlesRequest
-	18, // 81: tast.core.TestService.StreamFile:input_type -> tast.core.StreamFileRequest
-	4,  // 82: tast.core.TestService.ListEntities:output_type -> tast.core.ListEntitiesResponse
-	7,  // 83: tast.core.TestService.GlobalRuntimeVars:output_type -> tast.core.GlobalRuntimeVarsResponse
-	9,  // 84: tast.core.TestService.RunTests:output_type -> tast.core.RunTestsResponse
-	11, // 85: tast.core.TestService.GetDUTInfo:output_type -> tast.core.GetDUTInfoResponse
-	13, // 86: tast.core.TestService.GetSysInfoState:output_type -> tast.core.GetSysInfoStateResponse
-	15, // 87: tast.core.TestService.CollectSysInfo:output_type -> tast.core.CollectSysInfoResponse
-	17, // 88: tast.core.TestService.DownloadPrivateBundles:output_type -> tast.core.DownloadPrivateBundlesResponse
-	19, // 89: tast.core.TestService.StreamFile:output_type -> tast.core.StreamFileResponse
-	82, // [82:90] is the sub-list for method output_type
-	74, // [74:82] is the sub-list for method input_type
-	74, // [74:74] is the sub-list for extension type_name
-	74, // [74:74] is the sub-list for extension extendee
-	0,  // [0:74] is the sub-list for field type_name
+	64, // 0: tast.core.ListEntitiesRequest.features:type_name -> tast.core.Features
+	37, // 1: tast.core.ListEntitiesResponse.entities:type_name -> tast.core.ResolvedEntity
+	9,  // 2: tast.core.GlobalRuntimeVarsResponse.vars:type_name -> tast.core.GlobalRuntimeVar
+	27, // 3: tast.core.RunTestsRequest.run_tests_init:type_name -> tast.core.RunTestsInit
+	59, // 4: tast.core.RunTestsRequest.stack_operation_response:type_name -> tast.core.StackOperationResponse
+	40, // 5: tast.core.RunTestsResponse.run_log:type_name -> tast.core.RunLogEvent
+	41, // 6: tast.core.RunTestsResponse.entity_start:type_name -> tast.core.EntityStartEvent
+	42, // 7: tast.core.RunTestsResponse.entity_log:type_name -> tast.core.EntityLogEvent
+	43, // 8: tast.core.RunTestsResponse.entity_error:type_name -> tast.core.EntityErrorEvent
+	44, // 9: tast.core.RunTestsResponse.entity_end:type_name -> tast.core.EntityEndEvent
+	46, // 10: tast.core.RunTestsResponse.entity_copy_end:type_name -> tast.core.EntityCopyEndEvent
+	51, // 11: tast.core.RunTestsResponse.stack_operation:type_name -> tast.core.StackOperationRequest
+	60, // 12: tast.core.RunTestsResponse.heartbeat:type_name -> tast.core.HeartbeatEvent
+	45, // 13: tast.core.RunTestsResponse.entity_quarantine:type_name -> tast.core.EntityQuarantineEvent
+	49, // 14: tast.core.GetDUTInfoResponse.dut_info:type_name -> tast.core.DUTInfo
+	50, // 15: tast.core.GetSysInfoStateResponse.state:type_name -> tast.core.SysInfoState
+	50, // 16: tast.core.CollectSysInfoRequest.initial_state:type_name -> tast.core.SysInfoState
+	31, // 17: tast.core.DownloadPrivateBundlesRequest.service_config:type_name -> tast.core.ServiceConfig
+	0,  // 18: tast.core.Entity.type:type_name -> tast.core.EntityType
+	25, // 19: tast.core.Entity.dependencies:type_name -> tast.core.EntityDependencies
+	24, // 20: tast.core.Entity.contacts:type_name -> tast.core.EntityContacts
+	26, // 21: tast.core.Entity.legacy_data:type_name -> tast.core.EntityLegacyData
+	61, // 22: tast.core.Entity.search_flags:type_name -> tast.core.StringPair
+	65, // 23: tast.core.EntityLegacyData.timeout:type_name -> google.protobuf.Duration
+	65, // 24: tast.core.EntityLegacyData.expected_duration:type_name -> google.protobuf.Duration
+	61, // 25: tast.core.EntityLegacyData.run_metadata:type_name -> tast.core.StringPair
+	28, // 26: tast.core.RunTestsInit.run_config:type_name -> tast.core.RunConfig
+	30, // 27: tast.core.RunConfig.dirs:type_name -> tast.core.RunDirectories
+	64, // 28: tast.core.RunConfig.features:type_name -> tast.core.Features
+	31, // 29: tast.core.RunConfig.service_config:type_name -> tast.core.ServiceConfig
+	32, // 30: tast.core.RunConfig.data_file_config:type_name -> tast.core.DataFileConfig
+	34, // 31: tast.core.RunConfig.start_fixture_state:type_name -> tast.core.StartFixtureState
+	65, // 32: tast.core.RunConfig.heartbeat_interval:type_name -> google.protobuf.Duration
+	65, // 33: tast.core.RunConfig.system_services_timeout:type_name -> google.protobuf.Duration
+	29, // 34: tast.core.RunConfig.target:type_name -> tast.core.RunTargetConfig
+	65, // 35: tast.core.RunConfig.msg_timeout:type_name -> google.protobuf.Duration
+	65, // 36: tast.core.RunConfig.wait_until_ready_timeout:type_name -> google.protobuf.Duration
+	33, // 37: tast.core.RunConfig.pushed_files_info:type_name -> tast.core.PushedFilesInfoForDUT
+	61, // 38: tast.core.RunConfig.run_metadata:type_name -> tast.core.StringPair
+	30, // 39: tast.core.RunTargetConfig.dirs:type_name -> tast.core.RunDirectories
+	65, // 40: tast.core.RunTargetConfig.msg_timeout:type_name -> google.protobuf.Duration
+	65, // 41: tast.core.RunTargetConfig.system_services_timeout:type_name -> google.protobuf.Duration
+	65, // 42: tast.core.RunTargetConfig.wait_until_ready_timeout:type_name -> google.protobuf.Duration
+	61, // 43: tast.core.RunTargetConfig.run_metadata:type_name -> tast.core.StringPair
+	1,  // 44: tast.core.DataFileConfig.download_mode:type_name -> tast.core.DownloadMode
+	62, // 45: tast.core.PushedFilesInfoForDUT.src_dst_paths:type_name -> tast.core.PushedFilesInfoForDUT.SrcDstPathsEntry
+	35, // 46: tast.core.StartFixtureState.errors:type_name -> tast.core.Error
+	36, // 47: tast.core.Error.location:type_name -> tast.core.ErrorLocation
+	4,  // 48: tast.core.Error.category:type_name -> tast.core.Error.Category
+	23, // 49: tast.core.ResolvedEntity.entity:type_name -> tast.core.Entity
+	47, // 50: tast.core.ResolvedEntity.skip:type_name -> tast.core.Skip
+	39, // 51: tast.core.TimingLog.root:type_name -> tast.core.TimingStage
+	66, // 52: tast.core.TimingStage.start_time:type_name -> google.protobuf.Timestamp
+	66, // 53: tast.core.TimingStage.end_time:type_name -> google.protobuf.Timestamp
+	39, // 54: tast.core.TimingStage.children:type_name -> tast.core.TimingStage
+	66, // 55: tast.core.RunLogEvent.time:type_name -> google.protobuf.Timestamp
+	67, // 56: tast.core.RunLogEvent.level:type_name -> tast.core.LogLevel
+	66, // 57: tast.core.EntityStartEvent.time:type_name -> google.protobuf.Timestamp
+	23, // 58: tast.core.EntityStartEvent.entity:type_name -> tast.core.Entity
+	66, // 59: tast.core.EntityLogEvent.time:type_name -> google.protobuf.Timestamp
+	67, // 60: tast.core.EntityLogEvent.level:type_name -> tast.core.LogLevel
+	66, // 61: tast.core.EntityErrorEvent.time:type_name -> google.protobuf.Timestamp
+	35, // 62: tast.core.EntityErrorEvent.error:type_name -> tast.core.Error
+	2,  // 63: tast.core.EntityErrorEvent.fixture_phase:type_name -> tast.core.FixturePhase
+	66, // 64: tast.core.EntityEndEvent.time:type_name -> google.protobuf.Timestamp
+	47, // 65: tast.core.EntityEndEvent.skip:type_name -> tast.core.Skip
+	38, // 66: tast.core.EntityEndEvent.timing_log:type_name -> tast.core.TimingLog
+	66, // 67: tast.core.EntityQuarantineEvent.time:type_name -> google.protobuf.Timestamp
+	23, // 68: tast.core.EntityQuarantineEvent.entity:type_name -> tast.core.Entity
+	48, // 69: tast.core.Skip.typed_reasons:type_name -> tast.core.SkipReason
+	5,  // 70: tast.core.SkipReason.code:type_name -> tast.core.SkipReason.Code
+	68, // 71: tast.core.DUTInfo.features:type_name -> tast.core.DUTFeatures
+	63, // 72: tast.core.SysInfoState.log_inode_sizes:type_name -> tast.core.SysInfoState.LogInodeSizesEntry
+	52, // 73: tast.core.StackOperationRequest.reset:type_name -> tast.core.StackReset
+	53, // 74: tast.core.StackOperationRequest.pre_test:type_name -> tast.core.StackPreTest
+	54, // 75: tast.core.StackOperationRequest.post_test:type_name -> tast.core.StackPostTest
+	55, // 76: tast.core.StackOperationRequest.status:type_name -> tast.core.StackGetStatus
+	56, // 77: tast.core.StackOperationRequest.set_dirty:type_name -> tast.core.StackSetDirty
+	57, // 78: tast.core.StackOperationRequest.errors:type_name -> tast.core.StackGetErrors
+	58, // 79: tast.core.StackOperationRequest.value:type_name -> tast.core.StackValue
+	23, // 80: tast.core.StackPreTest.entity:type_name -> tast.core.Entity
+	23, // 81: tast.core.StackPostTest.entity:type_name -> tast.core.Entity
+	3,  // 82: tast.core.StackOperationResponse.status:type_name -> tast.core.StackStatus
+	35, // 83: tast.core.StackOperationResponse.errors:type_name -> tast.core.Error
+	66, // 84: tast.core.HeartbeatEvent.time:type_name -> google.protobuf.Timestamp
+	65, // 85: tast.core.HeartbeatEvent.elapsed:type_name -> google.protobuf.Duration
+	6,  // 86: tast.core.TestService.ListEntities:input_type -> tast.core.ListEntitiesRequest
+	8,  // 87: tast.core.TestService.GlobalRuntimeVars:input_type -> tast.core.GlobalRuntimeVarsRequest
+	11, // 88: tast.core.TestService.RunTests:input_type -> tast.core.RunTestsRequest
+	13, // 89: tast.core.TestService.GetDUTInfo:input_type -> tast.core.GetDUTInfoRequest
+	15, // 90: tast.core.TestService.GetSysInfoState:input_type -> tast.core.GetSysInfoStateRequest
+	17, // 91: tast.core.TestService.CollectSysInfo:input_type -> tast.core.CollectSysInfoRequest
+	19, // 92: tast.core.TestService.DownloadPrivateBundles:input_type -> tast.core.DownloadPrivateBundlesRequest
+	21, // 93: tast.core.TestService.StreamFile:input_type -> tast.core.StreamFileRequest
+	7,  // 94: tast.core.TestService.ListEntities:output_type -> tast.core.ListEntitiesResponse
+	10, // 95: tast.core.TestService.GlobalRuntimeVars:output_type -> tast.core.GlobalRuntimeVarsResponse
+	12, // 96: tast.core.TestService.RunTests:output_type -> tast.core.RunTestsResponse
+	14, // 97: tast.core.TestService.GetDUTInfo:output_type -> tast.core.GetDUTInfoResponse
+	16, // 98: tast.core.TestService.GetSysInfoState:output_type -> tast.core.GetSysInfoStateResponse
+	18, // 99: tast.core.TestService.CollectSysInfo:output_type -> tast.core.CollectSysInfoResponse
+	20, // 100: tast.core.TestService.DownloadPrivateBundles:output_type -> tast.core.DownloadPrivateBundlesResponse
+	22, // 101: tast.core.TestService.StreamFile:output_type -> tast.core.StreamFileResponse
+	94, // [94:102] is the sub-list for method output_type
+	86, // [86:94] is the sub-list for method input_type
+	86, // [86:86] is the sub-list for extension type_name
+	86, // [86:86] is the sub-list for extension extendee
+	0,  // [0:86] is the sub-list for field type_name
 }
 
 func init() { file_testing_proto_init() }
@@ -5089,7 +5952,7 @@ func file_testing_proto_init() {
 			}
 		}
 		file_testing_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*EntityCopyEndEvent); i {
+			switch v := v.(*EntityQuarantineEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5101,7 +5964,7 @@ func file_testing_proto_init() {
 			}
 		}
 		file_testing_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Skip); i {
+			switch v := v.(*EntityCopyEndEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5113,7 +5976,7 @@ func file_testing_proto_init() {
 			}
 		}
 		file_testing_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DUTInfo); i {
+			switch v := v.(*Skip); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5125,7 +5988,7 @@ func file_testing_proto_init() {
 			}
 		}
 		file_testing_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SysInfoState); i {
+			switch v := v.(*SkipReason); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5137,7 +6000,7 @@ func file_testing_proto_init() {
 			}
 		}
 		file_testing_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StackOperationRequest); i {
+			switch v := v.(*DUTInfo); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5149,7 +6012,7 @@ func file_testing_proto_init() {
 			}
 		}
 		file_testing_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StackReset); i {
+			switch v := v.(*SysInfoState); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5161,7 +6024,7 @@ func file_testing_proto_init() {
 			}
 		}
 		file_testing_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StackPreTest); i {
+			switch v := v.(*StackOperationRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5173,7 +6036,7 @@ func file_testing_proto_init() {
 			}
 		}
 		file_testing_proto_msgTypes[46].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StackPostTest); i {
+			switch v := v.(*StackReset); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5185,7 +6048,7 @@ func file_testing_proto_init() {
 			}
 		}
 		file_testing_proto_msgTypes[47].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StackGetStatus); i {
+			switch v := v.(*StackPreTest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5197,7 +6060,7 @@ func file_testing_proto_init() {
 			}
 		}
 		file_testing_proto_msgTypes[48].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StackSetDirty); i {
+			switch v := v.(*StackPostTest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5209,7 +6072,7 @@ func file_testing_proto_init() {
 			}
 		}
 		file_testing_proto_msgTypes[49].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StackGetErrors); i {
+			switch v := v.(*StackGetStatus); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5221,7 +6084,7 @@ func file_testing_proto_init() {
 			}
 		}
 		file_testing_proto_msgTypes[50].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StackValue); i {
+			switch v := v.(*StackSetDirty); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5233,7 +6096,7 @@ func file_testing_proto_init() {
 			}
 		}
 		file_testing_proto_msgTypes[51].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StackOperationResponse); i {
+			switch v := v.(*StackGetErrors); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5245,7 +6108,7 @@ func file_testing_proto_init() {
 			}
 		}
 		file_testing_proto_msgTypes[52].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*HeartbeatEvent); i {
+			switch v := v.(*StackValue); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5257,6 +6120,30 @@ func file_testing_proto_init() {
 			}
 		}
 		file_testing_proto_msgTypes[53].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StackOperationResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_testing_proto_msgTypes[54].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HeartbeatEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_testing_proto_msgTypes[55].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*StringPair); i {
 			case 0:
 				return &v.state
@@ -5282,8 +6169,9 @@ func file_testing_proto_init() {
 		(*RunTestsResponse_EntityCopyEnd)(nil),
 		(*RunTestsResponse_StackOperation)(nil),
 		(*RunTestsResponse_Heartbeat)(nil),
+		(*RunTestsResponse_EntityQuarantine)(nil),
 	}
-	file_testing_proto_msgTypes[43].OneofWrappers = []interface{}{
+	file_testing_proto_msgTypes[45].OneofWrappers = []interface{}{
 		(*StackOperationRequest_Reset_)(nil),
 		(*StackOperationRequest_PreTest)(nil),
 		(*StackOperationRequest_PostTest)(nil),
@@ -5297,8 +6185,8 @@ func file_testing_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_testing_proto_rawDesc,
-			NumEnums:      3,
-			NumMessages:   56,
+			NumEnums:      6,
+			NumMessages:   58,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -14,5 +14,6 @@ func (*EntityStartEvent) isEvent()      {}
 func (*EntityLogEvent) isEvent()        {}
 func (*EntityErrorEvent) isEvent()      {}
 func (*EntityEndEvent) isEvent()        {}
+func (*EntityQuarantineEvent) isEvent() {}
 func (*EntityCopyEndEvent) isEvent()    {}
 func (*StackOperationRequest) isEvent() {}
@@ -9,18 +9,24 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"os"
 	"os/exec"
 	"strconv"
 	"strings"
-	"sync/atomic"
+	"sync"
+	"time"
 
 	"github.com/shirou/gopsutil/v3/process"
 	"golang.org/x/sys/unix"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
 	"go.chromium.org/tast/core/errors"
 	"go.chromium.org/tast/core/internal/protocol"
@@ -32,8 +38,10 @@ import (
 
 // SSHClient is a Tast gRPC client over an SSH connection.
 type SSHClient struct {
-	cl  *GenericClient
-	cmd *ssh.Cmd
+	cl *GenericClient
+
+	mu  sync.Mutex // protects cmd
+	cmd *ssh.Cmd   // command running the gRPC server currently connected
 }
 
 // Conn returns a gRPC connection.
@@ -44,9 +52,12 @@ func (c *SSHClient) Conn() *grpc.ClientConn {
 // Close closes this client.
 func (c *SSHClient) Close(opts ...ssh.RunOption) error {
 	closeErr := c.cl.Close()
-	c.cmd.Abort()
+	c.mu.Lock()
+	cmd := c.cmd
+	c.mu.Unlock()
+	cmd.Abort()
 	// Ignore errors from Wait since Abort above causes it to return context.Canceled.
-	c.cmd.Wait(opts...)
+	cmd.Wait(opts...)
 	return closeErr
 }
 
@@ -56,6 +67,16 @@ func (c *SSHClient) Close(opts ...ssh.RunOption) error {
 // The context passed in must remain valid for as long as the gRPC connection.
 // I.e. Don't use the context from within a testing.Poll function.
 func DialSSH(ctx context.Context, conn *ssh.Conn, path string, req *protocol.HandshakeRequest, proxy bool) (*SSHClient, error) {
+	return DialSSHWithOptions(ctx, conn, path, req, proxy, nil, nil)
+}
+
+// DialSSHWithOptions is similar to DialSSH, but allows customizing the
+// connection with copts.
+// If reconnect is not nil, it is called to obtain an SSH connection when the
+// gRPC connection is lost, and a new executable is started on it to continue
+// serving the client. copts.Redial is ignored in this case.
+func DialSSHWithOptions(ctx context.Context, conn *ssh.Conn, path string, req *protocol.HandshakeRequest, proxy bool,
+	copts *ClientOptions, reconnect func(ctx context.Context) (*ssh.Conn, error)) (*SSHClient, error) {
 	args := []string{path, "-rpc"}
 	if proxy {
 		var envArgs []string
@@ -71,30 +92,67 @@ func DialSSH(ctx context.Context, conn *ssh.Conn, path string, req *protocol.Han
 		}
 		args = append(append([]string{"env"}, envArgs...), args...)
 	}
-	testing.ContextLog(ctx, "Running rpc server: ", args)
-	cmd := conn.CommandContext(ctx, args[0], args[1:]...)
-	stdin, err := cmd.StdinPipe()
+	cmd, stdout, stdin, err := startSSHServer(ctx, conn, args)
 	if err != nil {
 		return nil, err
 	}
-	stdout, err := cmd.StdoutPipe()
-	if err != nil {
-		return nil, err
-	}
-	if err := cmd.Start(); err != nil {
-		return nil, errors.Wrap(err, "failed to connect to RPC service on DUT")
+
+	sc := &SSHClient{cmd: cmd}
+	if reconnect != nil {
+		o := ClientOptions{}
+		if copts != nil {
+			o = *copts
+		}
+		o.Redial = func(ctx context.Context) (io.Reader, io.Writer, func() error, error) {
+			conn, err := reconnect(ctx)
+			if err != nil {
+				return nil, nil, nil, errors.Wrap(err, "failed to reconnect to the remote machine")
+			}
+			cmd, stdout, stdin, err := startSSHServer(ctx, conn, args)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			sc.mu.Lock()
+			old := sc.cmd
+			sc.cmd = cmd
+			sc.mu.Unlock()
+			old.Abort()
+			go old.Wait()
+			return stdout, stdin, func() error {
+				// Closing the pipe lets the server exit.
+				return stdin.Close()
+			}, nil
+		}
+		copts = &o
 	}
 
-	c, err := NewClient(ctx, stdout, stdin, req)
+	c, err := NewClientWithOptions(ctx, stdout, stdin, req, copts)
 	if err != nil {
 		cmd.Abort()
 		cmd.Wait()
 		return nil, err
 	}
-	return &SSHClient{
-		cl:  c,
-		cmd: cmd,
-	}, nil
+	sc.cl = c
+	return sc, nil
+}
+
+// startSSHServer starts a command specified by args on conn to run a gRPC
+// server, and returns its stdout and stdin.
+func startSSHServer(ctx context.Context, conn *ssh.Conn, args []string) (*ssh.Cmd, io.Reader, io.WriteCloser, error) {
+	testing.ContextLog(ctx, "Running rpc server: ", args)
+	cmd := conn.CommandContext(ctx, args[0], args[1:]...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, nil, nil, errors.Wrap(err, "failed to connect to RPC service on DUT")
+	}
+	return cmd, stdout, stdin, nil
 }
 
 // ExecClient is a Tast gRPC client over a locally executed subprocess.
@@ -168,8 +226,9 @@ func DialExec(ctx context.Context, path string, newSession bool, req *protocol.H
 
 // GenericClient is a Tast gRPC client.
 type GenericClient struct {
-	conn *grpc.ClientConn
-	log  *remoteLoggingClient
+	conn   *grpc.ClientConn
+	log    *lazyRemoteLoggingClient
+	resume *resumeGate
 }
 
 // Conn returns a gRPC connection.
@@ -179,6 +238,7 @@ func (c *GenericClient) Conn() *grpc.ClientConn {
 
 // Close closes this client.
 func (c *GenericClient) Close() error {
+	c.resume.Close()
 	var firstErr error
 	if err := c.log.Close(); err != nil && firstErr == nil {
 		firstErr = err
@@ -189,24 +249,113 @@ func (c *GenericClient) Close() error {
 	return firstErr
 }
 
+// ClientOptions contains options to customize a gRPC connection established
+// by NewClientWithOptions.
+type ClientOptions struct {
+	// KeepaliveInterval is the interval of keepalive pings sent to the server
+	// while the connection is idle. Keepalive pings are disabled if it is
+	// zero. It should not be shorter than minKeepaliveInterval since servers
+	// close connections sending pings too often.
+	KeepaliveInterval time.Duration
+
+	// KeepaliveTimeout is the duration to wait for a response to a keepalive
+	// ping before considering the connection to be lost. The gRPC default is
+	// used if it is zero.
+	KeepaliveTimeout time.Duration
+
+	// MaxMessageSize is the maximum size in bytes of messages sent and
+	// received. MaxMessageSize is used if it is zero.
+	MaxMessageSize int
+
+	// Redial, if not nil, is called to establish a new bidirectional pipe to
+	// a gRPC server when the connection is lost. The same HandshakeRequest is
+	// sent to the new server, but states kept in memory by services of the
+	// old server are not carried over; use OnRedial to restore them.
+	// closeFunc is called when the new pipe is no longer used.
+	// Method calls in progress when the connection is lost still fail, but
+	// later method calls are sent to the new server transparently.
+	Redial func(ctx context.Context) (r io.Reader, w io.Writer, closeFunc func() error, err error)
+
+	// OnRedial, if not nil, is called after a new connection is established
+	// with Redial, so that states of services can be restored on the new
+	// server, e.g. by calling methods initializing them again. Other user
+	// method calls wait until OnRedial returns. If it returns an error, they
+	// fail with the error until the next successful redial.
+	OnRedial func(ctx context.Context, conn *grpc.ClientConn) error
+
+	// MaxRedials is the maximum number of times Redial is called. There is no
+	// limit if it is zero.
+	MaxRedials int
+}
+
+// redialTimeout is the minimum timeout for establishing a new connection with
+// ClientOptions.Redial.
+const redialTimeout = time.Minute
+
 // NewClient establishes a gRPC connection to a test bundle executable using r
 // and w.
 // Callers are responsible for closing the underlying connection of r/w after
 // the client is closed.
-func NewClient(ctx context.Context, r io.Reader, w io.Writer, req *protocol.HandshakeRequest, opts ...grpc.DialOption) (_ *GenericClient, retErr error) {
-	if err := sendRawMessage(w, req); err != nil {
-		return nil, err
+func NewClient(ctx context.Context, r io.Reader, w io.Writer, req *protocol.HandshakeRequest, opts ...grpc.DialOption) (*GenericClient, error) {
+	return NewClientWithOptions(ctx, r, w, req, nil, opts...)
+}
+
+// NewClientWithOptions is similar to NewClient, but allows customizing the
+// connection with copts. copts can be nil to use the default options.
+//
+// The context passed in must remain valid for as long as the gRPC connection
+// if copts.Redial is set.
+func NewClientWithOptions(ctx context.Context, r io.Reader, w io.Writer, req *protocol.HandshakeRequest, copts *ClientOptions, opts ...grpc.DialOption) (_ *GenericClient, retErr error) {
+	if copts == nil {
+		copts = &ClientOptions{}
 	}
-	res := &protocol.HandshakeResponse{}
-	if err := receiveRawMessage(r, res); err != nil {
-		return nil, err
+	maxSize := MaxMessageSize
+	if copts.MaxMessageSize > 0 {
+		maxSize = copts.MaxMessageSize
+		req = proto.Clone(req).(*protocol.HandshakeRequest)
+		req.MaxMessageSize = int64(maxSize)
 	}
-	if res.Error != nil {
-		return nil, errors.Errorf("bundle returned error: %s", res.Error.GetReason())
+
+	r, err := handshake(r, w, req)
+	if err != nil {
+		return nil, err
 	}
 
 	lazyLog := newLazyRemoteLoggingClient()
-	conn, err := NewPipeClientConn(ctx, r, w, append(clientOpts(lazyLog), opts...)...)
+	resume := newResumeGate()
+	dopts := clientOpts(lazyLog, resume, maxSize)
+	if copts.KeepaliveInterval > 0 {
+		dopts = append(dopts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                copts.KeepaliveInterval,
+			Timeout:             copts.KeepaliveTimeout,
+			PermitWithoutStream: true,
+		}))
+	}
+
+	var conn *grpc.ClientConn
+	if copts.Redial == nil {
+		conn, err = NewPipeClientConn(ctx, r, w, append(dopts, opts...)...)
+	} else {
+		d := &redialer{
+			ctx:        ctx,
+			req:        req,
+			redial:     copts.Redial,
+			onRedial:   copts.OnRedial,
+			maxRedials: copts.MaxRedials,
+			lazyLog:    lazyLog,
+			resume:     resume,
+			conn:       &pipeConn{r: r, w: w},
+		}
+		dopts = append(dopts,
+			grpc.WithInsecure(),
+			grpc.WithContextDialer(d.Dial),
+			grpc.WithConnectParams(grpc.ConnectParams{
+				Backoff:           backoff.DefaultConfig,
+				MinConnectTimeout: redialTimeout,
+			}))
+		conn, err = grpc.DialContext(ctx, "", append(dopts, opts...)...)
+		d.setClientConn(conn)
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to establish RPC connection")
 	}
@@ -224,19 +373,314 @@ func NewClient(ctx context.Context, r io.Reader, w io.Writer, req *protocol.Hand
 	lazyLog.SetClient(log)
 
 	return &GenericClient{
-		conn: conn,
-		log:  log,
+		conn:   conn,
+		log:    lazyLog,
+		resume: resume,
 	}, nil
 }
 
+// handshake sends req to a gRPC server over w and receives its response from
+// r. It returns io.Reader to be used to read further messages from the server.
+func handshake(r io.Reader, w io.Writer, req *protocol.HandshakeRequest) (io.Reader, error) {
+	if err := sendRawMessage(w, req); err != nil {
+		return nil, err
+	}
+	res := &protocol.HandshakeResponse{}
+	if err := receiveRawMessage(r, res); err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, errors.Errorf("bundle returned error: %s", res.Error.GetReason())
+	}
+
+	// The server compresses messages only if the client accepts it.
+	if res.GetZstdCompression() {
+		if !req.GetAcceptZstdCompression() {
+			return nil, errors.New("server enabled compression not accepted")
+		}
+		zr, err := newZstdReader(r)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to start decompression")
+		}
+		return zr, nil
+	}
+	return r, nil
+}
+
+// redialer is a gRPC dialer that returns a connection over the initial pipe
+// first, and establishes new pipes with ClientOptions.Redial afterwards.
+type redialer struct {
+	ctx        context.Context // context valid for the lifetime of the client
+	req        *protocol.HandshakeRequest
+	redial     func(ctx context.Context) (io.Reader, io.Writer, func() error, error)
+	onRedial   func(ctx context.Context, conn *grpc.ClientConn) error
+	maxRedials int
+	lazyLog    *lazyRemoteLoggingClient
+	resume     *resumeGate
+
+	mu      sync.Mutex
+	conn    *pipeConn        // initial connection; nil after it is used
+	redials int              // number of calls to redial
+	cc      *grpc.ClientConn // set after grpc.DialContext returns
+}
+
+func (d *redialer) setClientConn(cc *grpc.ClientConn) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.cc = cc
+}
+
+// Dial returns a connection to a gRPC server. It is called by gRPC whenever a
+// new transport is needed.
+func (d *redialer) Dial(ctx context.Context, _ string) (net.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.conn != nil {
+		conn := d.conn
+		d.conn = nil
+		return d.wrapConn(conn), nil
+	}
+	if d.maxRedials > 0 && d.redials >= d.maxRedials {
+		err := errors.Errorf("gRPC connection lost after %d reconnects", d.redials)
+		d.failResume(err)
+		return nil, err
+	}
+	d.redials++
+	testing.ContextLogf(d.ctx, "gRPC connection lost; reconnecting (attempt %d)", d.redials)
+
+	// Logs from the old server are no longer available. Make method calls
+	// wait for logs from the new server.
+	d.lazyLog.Reset()
+
+	type result struct {
+		conn net.Conn
+		err  error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		conn, err := d.dialNew()
+		ch <- result{conn, err}
+	}()
+
+	var res result
+	select {
+	case res = <-ch:
+	case <-ctx.Done():
+		go func() {
+			if res := <-ch; res.conn != nil {
+				res.conn.Close()
+			}
+		}()
+		res = result{nil, ctx.Err()}
+	}
+	if res.err != nil {
+		d.lazyLog.SetClient(nil)
+		d.failResume(res.err)
+		testing.ContextLog(d.ctx, "Failed to reconnect gRPC connection: ", res.err)
+		return nil, res.err
+	}
+
+	// Hold user method calls until services are restored on the new server.
+	if d.onRedial != nil {
+		d.resume.Begin()
+	}
+	cc := d.cc
+	go func() {
+		log, err := newRemoteLoggingClient(d.ctx, cc)
+		if err != nil {
+			testing.ContextLog(d.ctx, "Failed to restart remote logging: ", err)
+		}
+		d.lazyLog.SetClient(log)
+
+		if d.onRedial == nil {
+			return
+		}
+		err = d.onRedial(withResuming(d.ctx), cc)
+		if err != nil {
+			testing.ContextLog(d.ctx, "Failed to restore services after reconnect: ", err)
+		}
+		d.resume.Finish(err)
+	}()
+	return d.wrapConn(res.conn), nil
+}
+
+// wrapConn returns a connection that holds user method calls once reading
+// from conn fails, until services are restored on a new server by onRedial.
+// The connection loss is noticed earlier than the next call of Dial, which
+// may be triggered by a user method call itself.
+func (d *redialer) wrapConn(conn net.Conn) net.Conn {
+	if d.onRedial == nil {
+		return conn
+	}
+	return &brokenNotifyingConn{Conn: conn, onBroken: func() {
+		d.resume.Begin()
+		go d.connectWhenIdle()
+	}}
+}
+
+// connectWhenIdle makes the gRPC connection reconnect once it becomes idle.
+// gRPC reconnects idle connections only on method calls, but user method
+// calls are held until onRedial restores services after reconnecting.
+func (d *redialer) connectWhenIdle() {
+	d.mu.Lock()
+	cc := d.cc
+	d.mu.Unlock()
+	if cc == nil {
+		return
+	}
+	for {
+		st := cc.GetState()
+		switch st {
+		case connectivity.Idle:
+			cc.Connect()
+			return
+		case connectivity.Shutdown:
+			return
+		}
+		if !cc.WaitForStateChange(d.ctx, st) {
+			return
+		}
+	}
+}
+
+// failResume releases user method calls held for onRedial with err.
+func (d *redialer) failResume(err error) {
+	if d.onRedial != nil {
+		d.resume.Finish(err)
+	}
+}
+
+// brokenNotifyingConn wraps net.Conn to call onBroken when reading from it
+// fails for the first time.
+type brokenNotifyingConn struct {
+	net.Conn
+	onBroken func()
+	once     sync.Once
+}
+
+func (c *brokenNotifyingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if err != nil {
+		c.once.Do(c.onBroken)
+	}
+	return n, err
+}
+
+// dialNew establishes a new pipe with redial and performs a handshake on it.
+// The long-lived context is used since the pipe outlives the dial context.
+func (d *redialer) dialNew() (net.Conn, error) {
+	r, w, closeFunc, err := d.redial(d.ctx)
+	if err != nil {
+		return nil, err
+	}
+	r, err = handshake(r, w, d.req)
+	if err != nil {
+		if closeFunc != nil {
+			closeFunc()
+		}
+		return nil, errors.Wrap(err, "handshake failed on reconnect")
+	}
+	return &pipeConn{r: r, w: w, c: closeFunc}, nil
+}
+
+// resumingKey is the type of the key used for attaching a marker to a context
+// passed to ClientOptions.OnRedial.
+type resumingKey struct{}
+
+// withResuming returns a context whose method calls bypass resumeGate.
+func withResuming(ctx context.Context) context.Context {
+	return context.WithValue(ctx, resumingKey{}, true)
+}
+
+// resumeGate holds user method calls while ClientOptions.OnRedial restores
+// services after a redial.
+// resumeGate is goroutine-safe.
+type resumeGate struct {
+	mu     sync.Mutex
+	done   chan struct{} // closed when services are restored
+	err    error         // error returned by ClientOptions.OnRedial
+	closed bool          // true after Close is called
+}
+
+func newResumeGate() *resumeGate {
+	done := make(chan struct{})
+	close(done)
+	return &resumeGate{done: done}
+}
+
+// Begin makes Wait block until Finish is called. It does nothing after Close
+// is called.
+func (g *resumeGate) Begin() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closed {
+		return
+	}
+	select {
+	case <-g.done:
+		g.done = make(chan struct{})
+	default:
+	}
+	g.err = nil
+}
+
+// Finish unblocks Wait. err is returned by Wait until Begin is called again.
+func (g *resumeGate) Finish(err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.err = err
+	select {
+	case <-g.done:
+	default:
+		close(g.done)
+	}
+}
+
+// Close unblocks Wait permanently since no more redial happens.
+func (g *resumeGate) Close() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.closed = true
+	g.err = nil
+	select {
+	case <-g.done:
+	default:
+		close(g.done)
+	}
+}
+
+// Wait waits until services are restored after a redial. It returns
+// immediately for method calls made by ClientOptions.OnRedial.
+func (g *resumeGate) Wait(ctx context.Context) error {
+	if ctx.Value(resumingKey{}) != nil {
+		return nil
+	}
+	g.mu.Lock()
+	done := g.done
+	g.mu.Unlock()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.err != nil {
+		return errors.Wrap(g.err, "failed to restore services after reconnect")
+	}
+	return nil
+}
+
 var alwaysAllowedServices = []string{
 	"tast.cros.baserpc.FaillogService",
 	"tast.cros.baserpc.FileSystem",
 }
 
 // clientOpts returns gRPC client-side interceptors to manipulate context and
-// make sure all clients use the same GRPC send/recv message size.
-func clientOpts(lazyLog *lazyRemoteLoggingClient) []grpc.DialOption {
+// make sure all clients use maxSize as GRPC send/recv message size.
+// User method calls wait for resume before being sent.
+func clientOpts(lazyLog *lazyRemoteLoggingClient, resume *resumeGate, maxSize int) []grpc.DialOption {
 	// hook is called on every gRPC method call.
 	// It returns a Context to be passed to a gRPC invocation, a function to be
 	// called on the end of the gRPC method call to process trailers, and
@@ -259,6 +703,9 @@ func clientOpts(lazyLog *lazyRemoteLoggingClient) []grpc.DialOption {
 			if !matched {
 				return nil, nil, status.Errorf(codes.FailedPrecondition, "refusing to call %s because it is not declared in ServiceDeps", method)
 			}
+			if err := resume.Wait(ctx); err != nil {
+				return nil, nil, status.Errorf(codes.Unavailable, "refusing to call %s: %v", method, err)
+			}
 		}
 
 		after := func(trailer metadata.MD) error {
@@ -291,7 +738,13 @@ func clientOpts(lazyLog *lazyRemoteLoggingClient) []grpc.DialOption {
 
 			var trailer metadata.MD
 			opts = append([]grpc.CallOption{grpc.Trailer(&trailer)}, opts...)
+			start := time.Now()
 			retErr := invoker(ctx, method, req, reply, cc, opts...)
+			if isUserMethod(method) {
+				if err := recordRPCTrace(ctx, method, start, retErr, messageSize(req)); err != nil {
+					testing.ContextLog(ctx, "Failed to record RPC trace: ", err)
+				}
+			}
 			if err := after(trailer); err != nil && retErr == nil {
 				retErr = err
 			}
@@ -303,12 +756,24 @@ func clientOpts(lazyLog *lazyRemoteLoggingClient) []grpc.DialOption {
 			if err != nil {
 				return nil, err
 			}
+			start := time.Now()
 			stream, err := streamer(ctx, desc, cc, method, opts...)
-			return &clientStreamWithAfter{ClientStream: stream, after: after}, err
+			cs := &clientStreamWithAfter{ClientStream: stream, after: after}
+			if isUserMethod(method) {
+				cs.trace = func(callErr error) {
+					if err := recordRPCTrace(ctx, method, start, callErr, cs.requestSize); err != nil {
+						testing.ContextLog(ctx, "Failed to record RPC trace: ", err)
+					}
+				}
+				if err != nil {
+					cs.trace(err)
+				}
+			}
+			return cs, err
 		}),
 		grpc.WithDefaultCallOptions(
-			grpc.MaxCallRecvMsgSize(MaxMessageSize),
-			grpc.MaxCallSendMsgSize(MaxMessageSize),
+			grpc.MaxCallRecvMsgSize(maxSize),
+			grpc.MaxCallSendMsgSize(maxSize),
 		),
 	}
 }
@@ -378,6 +843,16 @@ type clientStreamWithAfter struct {
 	grpc.ClientStream
 	after func(trailer metadata.MD) error
 	done  bool
+
+	// trace is called with the final status of the streaming call if it is
+	// not nil. requestSize is the total size of sent messages in bytes.
+	trace       func(err error)
+	requestSize int
+}
+
+func (s *clientStreamWithAfter) SendMsg(m interface{}) error {
+	s.requestSize += messageSize(m)
+	return s.ClientStream.SendMsg(m)
 }
 
 func (s *clientStreamWithAfter) RecvMsg(m interface{}) error {
@@ -391,6 +866,13 @@ func (s *clientStreamWithAfter) RecvMsg(m interface{}) error {
 	}
 	s.done = true
 
+	if s.trace != nil {
+		if retErr == io.EOF {
+			s.trace(nil)
+		} else {
+			s.trace(retErr)
+		}
+	}
 	if err := s.after(s.Trailer()); err != nil && retErr == io.EOF {
 		retErr = err
 	}
@@ -401,28 +883,83 @@ func (s *clientStreamWithAfter) RecvMsg(m interface{}) error {
 // We have to install logging hooks on starting a gRPC connection, but
 // remoteLoggingClient can be started only after a gRPC connection is ready.
 // lazyRemoteLoggingClient allows logging hooks to access remoteLoggingClient
-// after it becomes available.
+// after it becomes available. It also allows replacing remoteLoggingClient
+// when the gRPC connection is reestablished.
 // lazyRemoteLoggingClient is goroutine-safe.
 type lazyRemoteLoggingClient struct {
-	client atomic.Value
+	mu          sync.Mutex
+	client      *remoteLoggingClient
+	ready       chan struct{} // closed when client is set
+	initialized bool          // true after SetClient is called for the first time
 }
 
 func newLazyRemoteLoggingClient() *lazyRemoteLoggingClient {
-	return &lazyRemoteLoggingClient{}
+	return &lazyRemoteLoggingClient{ready: make(chan struct{})}
 }
 
+// SetClient sets client to be used. client can be nil if remote logging is
+// unavailable.
 func (l *lazyRemoteLoggingClient) SetClient(client *remoteLoggingClient) {
-	l.client.Store(client)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.client = client
+	l.initialized = true
+	select {
+	case <-l.ready:
+	default:
+		close(l.ready)
+	}
+}
+
+// Reset discards the current client and makes Wait block until SetClient is
+// called again.
+func (l *lazyRemoteLoggingClient) Reset() {
+	l.mu.Lock()
+	old := l.client
+	l.client = nil
+	l.ready = make(chan struct{})
+	l.mu.Unlock()
+
+	if old != nil {
+		// The old stream is already broken, so closing it does not block
+		// for long. Its error is uninteresting.
+		go old.Close()
+	}
 }
 
 func (l *lazyRemoteLoggingClient) Wait(ctx context.Context, seq uint64) error {
-	client, ok := l.client.Load().(*remoteLoggingClient)
-	if !ok {
+	l.mu.Lock()
+	client, ready, initialized := l.client, l.ready, l.initialized
+	l.mu.Unlock()
+
+	// Wait for a new client while reconnecting.
+	if client == nil && initialized {
+		select {
+		case <-ready:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		l.mu.Lock()
+		client = l.client
+		l.mu.Unlock()
+	}
+	if client == nil {
 		return nil
 	}
 	return client.Wait(ctx, seq)
 }
 
+// Close closes the current client if any.
+func (l *lazyRemoteLoggingClient) Close() error {
+	l.mu.Lock()
+	client := l.client
+	l.mu.Unlock()
+	if client == nil {
+		return nil
+	}
+	return client.Close()
+}
+
 // killSession makes a best-effort attempt to kill all processes in session sid.
 // It makes several passes over the list of running processes, sending sig to any
 // that are part of the session. After it doesn't find any new processes, it returns.
@@ -0,0 +1,66 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package rpc
+
+import (
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// zstdWriter is an io.Writer compressing data written to it with zstd.
+//
+// All data is written to a single zstd frame so that repeated content, e.g.
+// similar log messages, is compressed across messages. Every Write is flushed
+// to the underlying writer so that gRPC messages are delivered without delay.
+type zstdWriter struct {
+	mu  sync.Mutex // guards enc
+	enc *zstd.Encoder
+}
+
+// newZstdWriter returns a zstdWriter writing compressed data to w.
+func newZstdWriter(w io.Writer) (*zstdWriter, error) {
+	enc, err := zstd.NewWriter(w,
+		zstd.WithEncoderLevel(zstd.SpeedFastest),
+		zstd.WithEncoderConcurrency(1),
+		zstd.WithLowerEncoderMem(true))
+	if err != nil {
+		return nil, err
+	}
+	return &zstdWriter{enc: enc}, nil
+}
+
+// Write compresses p and flushes it to the underlying writer.
+func (w *zstdWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	n, err := w.enc.Write(p)
+	if err != nil {
+		return n, err
+	}
+	return n, w.enc.Flush()
+}
+
+// Close ends the zstd frame. It does not close the underlying writer.
+func (w *zstdWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.enc.Close()
+}
+
+// newZstdReader returns an io.Reader decompressing data written by zstdWriter
+// to r.
+func newZstdReader(r io.Reader) (io.Reader, error) {
+	// Decode synchronously so that the decoder does not read ahead of
+	// flushed data, which would block until the next message arrives. In this
+	// mode the decoder runs no goroutines, so it is left to the garbage
+	// collector rather than closed while gRPC may still be reading from it.
+	dec, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+	if err != nil {
+		return nil, err
+	}
+	return dec, nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package rpc
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestZstdRoundTrip(t *testing.T) {
+	pr, pw := io.Pipe()
+	zw, err := newZstdWriter(pw)
+	if err != nil {
+		t.Fatal("newZstdWriter failed: ", err)
+	}
+	zr, err := newZstdReader(pr)
+	if err != nil {
+		t.Fatal("newZstdReader failed: ", err)
+	}
+
+	// Each message should be readable as soon as it is written, without
+	// waiting for subsequent messages.
+	go func() {
+		for i := 0; i < 3; i++ {
+			fmt.Fprintf(zw, "message %d", i)
+		}
+		zw.Close()
+		pw.Close()
+	}()
+	for i := 0; i < 3; i++ {
+		want := fmt.Sprintf("message %d", i)
+		buf := make([]byte, len(want))
+		if _, err := io.ReadFull(zr, buf); err != nil {
+			t.Fatalf("Read #%d failed: %v", i, err)
+		}
+		if got := string(buf); got != want {
+			t.Errorf("Read #%d = %q; want %q", i, got, want)
+		}
+	}
+	if b, err := io.ReadAll(zr); err != nil || len(b) > 0 {
+		t.Errorf("Read after the last message = (%q, %v); want EOF", b, err)
+	}
+}
+
+func TestZstdCompressesAcrossWrites(t *testing.T) {
+	var buf bytes.Buffer
+	zw, err := newZstdWriter(&buf)
+	if err != nil {
+		t.Fatal("newZstdWriter failed: ", err)
+	}
+	const n = 1000
+	var total int
+	for i := 0; i < n; i++ {
+		msg := fmt.Sprintf("[12:34:56.789] Waiting for the service to be ready (attempt %d)", i)
+		total += len(msg)
+		if _, err := io.WriteString(zw, msg); err != nil {
+			t.Fatal("Write failed: ", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal("Close failed: ", err)
+	}
+	// Similar messages should be compressed well even though every message
+	// is flushed separately.
+	if buf.Len() > total/3 {
+		t.Errorf("Compressed %d bytes to %d bytes; want at most %d bytes", total, buf.Len(), total/3)
+	}
+}
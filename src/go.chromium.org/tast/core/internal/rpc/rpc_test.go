@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math/rand"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -23,6 +24,7 @@ import (
 	"github.com/shirou/gopsutil/v3/process"
 	"golang.org/x/sys/unix"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	"go.chromium.org/tast/core/errors"
@@ -378,6 +380,45 @@ func TestRPCForwardLogs(t *gotesting.T) {
 	}
 }
 
+func TestRPCZstdCompression(t *gotesting.T) {
+	ctx := context.Background()
+	logger, logs := newChannelLogger()
+	ctx = logging.AttachLogger(ctx, logger)
+	ctx = testcontext.WithCurrentEntity(ctx, &testcontext.CurrentEntity{})
+	req := &protocol.HandshakeRequest{NeedUserServices: true, AcceptZstdCompression: true}
+
+	const n = 100
+	svc := newPingService(func(ctx context.Context, s *testing.ServiceState) error {
+		for i := 0; i < n; i++ {
+			logging.Infof(ctx, "log %d", i)
+		}
+		return nil
+	})
+
+	pp := newPingPair(ctx, t, req, svc)
+	defer pp.Close()
+
+	callCtx := testcontext.WithCurrentEntity(ctx, &testcontext.CurrentEntity{
+		ServiceDeps: []string{pingUserServiceName},
+	})
+	for i := 0; i < 3; i++ {
+		if _, err := pp.UserClient.Ping(callCtx, &emptypb.Empty{}); err != nil {
+			t.Fatal("Ping failed: ", err)
+		}
+		for j := 0; j < n; j++ {
+			exp := fmt.Sprintf("INFO: log %d", j)
+			select {
+			case msg := <-logs:
+				if msg != exp {
+					t.Errorf("Got log %q; want %q", msg, exp)
+				}
+			default:
+				t.Fatal("Logs unavailable immediately on RPC completion")
+			}
+		}
+	}
+}
+
 // TestRPCForwardLogsAsyncStress is a regression test for b/207577797.
 // It exercises the scenario where a remote server emits a log in parallel to
 // finishing a remote method call and/or the RPC connection is closed.
@@ -504,11 +545,66 @@ func TestRPCPullOutDir(t *gotesting.T) {
 	if err != nil {
 		t.Fatal("Failed to read output dir: ", err)
 	}
+	// The RPC trace is tested separately in TestRPCTrace.
+	delete(got, RPCTraceFile)
 	if diff := cmp.Diff(got, want); diff != "" {
 		t.Errorf("Directory contents mismatch (-got +want):\n%s", diff)
 	}
 }
 
+func TestRPCTrace(t *gotesting.T) {
+	outDir := testutil.TempDir(t)
+	defer os.RemoveAll(outDir)
+
+	ctx := context.Background()
+	ctx = testcontext.WithCurrentEntity(ctx, &testcontext.CurrentEntity{})
+	req := &protocol.HandshakeRequest{NeedUserServices: true}
+
+	fail := false
+	svc := newPingService(func(ctx context.Context, s *testing.ServiceState) error {
+		if fail {
+			return errors.New("failure")
+		}
+		return nil
+	})
+
+	pp := newPingPair(ctx, t, req, svc)
+	defer pp.Close()
+
+	callCtx := testcontext.WithCurrentEntity(ctx, &testcontext.CurrentEntity{
+		ServiceDeps: []string{pingUserServiceName},
+		OutDir:      outDir,
+	})
+	if _, err := pp.UserClient.Ping(callCtx, &emptypb.Empty{}); err != nil {
+		t.Error("Ping failed: ", err)
+	}
+	fail = true
+	if _, err := pp.UserClient.Ping(callCtx, &emptypb.Empty{}); err == nil {
+		t.Error("Ping unexpectedly succeeded")
+	}
+
+	b, err := os.ReadFile(filepath.Join(outDir, RPCTraceFile))
+	if err != nil {
+		t.Fatal("Failed to read RPC trace: ", err)
+	}
+	var entries []RPCTraceEntry
+	if err := json.Unmarshal(b, &entries); err != nil {
+		t.Fatal("Failed to parse RPC trace: ", err)
+	}
+	var got []string
+	for _, e := range entries {
+		if e.Start.IsZero() || e.Duration <= 0 {
+			t.Errorf("Trace entry %+v lacks timing", e)
+		}
+		got = append(got, e.Method+" "+e.Status)
+	}
+	method := "/" + pingUserServiceName + "/Ping"
+	want := []string{method + " OK", method + " Unknown"}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("RPC trace mismatch (-got +want):\n%s", diff)
+	}
+}
+
 func TestRPCSetVars(t *gotesting.T) {
 	ctx := testcontext.WithCurrentEntity(context.Background(), &testcontext.CurrentEntity{})
 	key := "var1"
@@ -1061,3 +1157,358 @@ func TestRPCOverStdioSIGINT(t *gotesting.T) {
 
 	waitSuccess(t)
 }
+
+var unixMain = fakeexec.NewAuxMain("rpc_unix_test", func(socketPath string) {
+	if err := RunUnixServer(socketPath, &protocol.HandshakeRequest{}, os.Stdout, os.Stderr, nil, func(s *grpc.Server, req *protocol.HandshakeRequest) error {
+		protocol.RegisterPingCoreServer(s, &pingCoreServer{})
+		return nil
+	}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+})
+
+func TestRPCOverUnixSocket(t *gotesting.T) {
+	ctx := context.Background()
+
+	td := testutil.TempDir(t)
+	defer os.RemoveAll(td)
+	socketPath := filepath.Join(td, "bundle.sock")
+
+	// Leave a stale socket to make sure it is replaced.
+	if err := unix.Mknod(socketPath, unix.S_IFSOCK|0666, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	params, err := unixMain.Params(socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(params.Executable())
+	cmd.Env = append(os.Environ(), params.Envs()...)
+	cmd.Stderr = os.Stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}()
+
+	// Wait for the server to start listening.
+	var res unixServerResponse
+	if err := json.NewDecoder(stdout).Decode(&res); err != nil {
+		t.Fatal("Failed to read server response: ", err)
+	}
+	if res.Socket != socketPath {
+		t.Errorf("Server listens on %q; want %q", res.Socket, socketPath)
+	}
+
+	fi, err := os.Stat(socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := fi.Mode().Perm(); perm != 0600 {
+		t.Errorf("Socket permission is %#o; want 0600", perm)
+	}
+
+	conn, err := grpc.Dial("unix://"+socketPath, grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err := protocol.NewPingCoreClient(conn).Ping(ctx, &emptypb.Empty{}); err != nil {
+		t.Error("Ping failed: ", err)
+	}
+
+	// The server should stop gracefully on SIGTERM.
+	cmd.Process.Signal(unix.SIGTERM)
+	if err := cmd.Wait(); err != nil {
+		t.Error("Server exited with an error: ", err)
+	}
+}
+
+func TestRPCReconnect(t *gotesting.T) {
+	ctx := context.Background()
+	logger, logs := newChannelLogger()
+	ctx = logging.AttachLogger(ctx, logger)
+	ctx = testcontext.WithCurrentEntity(ctx, &testcontext.CurrentEntity{})
+	req := &protocol.HandshakeRequest{NeedUserServices: true}
+
+	svc := newPingService(func(ctx context.Context, s *testing.ServiceState) error {
+		logging.Info(ctx, "hello")
+		return nil
+	})
+
+	// startServer starts a gRPC server and returns pipes to talk with it.
+	// breakConn closes the pipes to simulate a connection loss.
+	type server struct {
+		r         *io.PipeReader
+		w         *io.PipeWriter
+		breakConn func()
+	}
+	var servers []*server
+	var mu sync.Mutex
+	startServer := func() *server {
+		sr, cw := io.Pipe()
+		cr, sw := io.Pipe()
+		stopped := make(chan struct{})
+		go func() {
+			defer close(stopped)
+			RunServer(sr, sw, []*testing.Service{svc}, func(srv *grpc.Server, req *protocol.HandshakeRequest) error {
+				return nil
+			})
+		}()
+		s := &server{r: cr, w: cw, breakConn: func() {
+			cw.Close()
+			cr.Close()
+			<-stopped
+		}}
+		mu.Lock()
+		servers = append(servers, s)
+		mu.Unlock()
+		return s
+	}
+	defer func() {
+		for _, s := range servers {
+			s.breakConn()
+		}
+	}()
+
+	first := startServer()
+	cl, err := NewClientWithOptions(ctx, first.r, first.w, req, &ClientOptions{
+		Redial: func(ctx context.Context) (io.Reader, io.Writer, func() error, error) {
+			s := startServer()
+			return s.r, s.w, nil, nil
+		},
+		MaxRedials: 1,
+	})
+	if err != nil {
+		t.Fatal("NewClientWithOptions failed: ", err)
+	}
+	defer cl.Close()
+
+	callCtx := testcontext.WithCurrentEntity(ctx, &testcontext.CurrentEntity{
+		ServiceDeps: []string{pingUserServiceName},
+	})
+	ping := func() error {
+		_, err := protocol.NewPingUserClient(cl.Conn()).Ping(callCtx, &emptypb.Empty{})
+		return err
+	}
+	breakConn := func(s *server) {
+		s.breakConn()
+		waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		if !cl.Conn().WaitForStateChange(waitCtx, connectivity.Ready) {
+			t.Fatal("Connection loss was not detected")
+		}
+	}
+	expectLog := func() {
+		t.Helper()
+		for {
+			select {
+			case msg := <-logs:
+				if msg == "INFO: hello" {
+					return
+				}
+			default:
+				t.Fatal("Logs unavailable immediately on RPC completion")
+			}
+		}
+	}
+
+	if err := ping(); err != nil {
+		t.Fatal("Ping failed: ", err)
+	}
+	expectLog()
+
+	breakConn(first)
+	if err := ping(); err != nil {
+		t.Fatal("Ping failed after reconnect: ", err)
+	}
+	expectLog()
+
+	mu.Lock()
+	second := servers[1]
+	mu.Unlock()
+	breakConn(second)
+	if err := ping(); err == nil {
+		t.Error("Ping succeeded after exceeding MaxRedials")
+	}
+}
+
+func TestRPCReconnectOnRedial(t *gotesting.T) {
+	ctx := testcontext.WithCurrentEntity(context.Background(), &testcontext.CurrentEntity{
+		ServiceDeps: []string{pingUserServiceName},
+	})
+	req := &protocol.HandshakeRequest{NeedUserServices: true}
+
+	var mu sync.Mutex
+	var events []string
+	record := func(ev string) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, ev)
+	}
+	svc := newPingService(func(ctx context.Context, s *testing.ServiceState) error {
+		record("ping")
+		return nil
+	})
+
+	var breakFuncs []func()
+	startServer := func() (io.Reader, io.Writer) {
+		sr, cw := io.Pipe()
+		cr, sw := io.Pipe()
+		stopped := make(chan struct{})
+		go func() {
+			defer close(stopped)
+			RunServer(sr, sw, []*testing.Service{svc}, func(srv *grpc.Server, req *protocol.HandshakeRequest) error {
+				return nil
+			})
+		}()
+		mu.Lock()
+		breakFuncs = append(breakFuncs, func() {
+			cw.Close()
+			cr.Close()
+			<-stopped
+		})
+		mu.Unlock()
+		return cr, cw
+	}
+	breakConn := func(i int) {
+		mu.Lock()
+		f := breakFuncs[i]
+		mu.Unlock()
+		f()
+	}
+	defer func() {
+		for i := range breakFuncs {
+			breakConn(i)
+		}
+	}()
+
+	onRedialErr := errors.New("resume failure")
+	failResume := false
+	r, w := startServer()
+	cl, err := NewClientWithOptions(ctx, r, w, req, &ClientOptions{
+		Redial: func(ctx context.Context) (io.Reader, io.Writer, func() error, error) {
+			r, w := startServer()
+			return r, w, nil, nil
+		},
+		OnRedial: func(ctx context.Context, conn *grpc.ClientConn) error {
+			if _, err := protocol.NewPingUserClient(conn).Ping(ctx, &emptypb.Empty{}); err != nil {
+				return err
+			}
+			// Give other method calls a chance to be sent prematurely.
+			time.Sleep(100 * time.Millisecond)
+			record("resumed")
+			mu.Lock()
+			defer mu.Unlock()
+			if failResume {
+				return onRedialErr
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal("NewClientWithOptions failed: ", err)
+	}
+	defer cl.Close()
+
+	ping := func() error {
+		_, err := protocol.NewPingUserClient(cl.Conn()).Ping(ctx, &emptypb.Empty{})
+		return err
+	}
+	reconnect := func(i int) {
+		t.Helper()
+		breakConn(i)
+		waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		if !cl.Conn().WaitForStateChange(waitCtx, connectivity.Ready) {
+			t.Fatal("Connection loss was not detected")
+		}
+		mu.Lock()
+		events = nil
+		mu.Unlock()
+	}
+
+	reconnect(0)
+	if err := ping(); err != nil {
+		t.Fatal("Ping failed after reconnect: ", err)
+	}
+	mu.Lock()
+	got := events
+	mu.Unlock()
+	if want := []string{"ping", "resumed", "ping"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Events after reconnect = %q; want %q", got, want)
+	}
+
+	mu.Lock()
+	failResume = true
+	mu.Unlock()
+	reconnect(1)
+	if err := ping(); err == nil || !strings.Contains(err.Error(), onRedialErr.Error()) {
+		t.Errorf("Ping after failed OnRedial returned %v; want %q", err, onRedialErr)
+	}
+}
+
+func TestRPCMaxMessageSize(t *gotesting.T) {
+	ctx := testcontext.WithCurrentEntity(context.Background(), &testcontext.CurrentEntity{})
+
+	td := testutil.TempDir(t)
+	defer os.RemoveAll(td)
+
+	// Random data is not compressed by tar.
+	data := make([]byte, 65536)
+	rand.New(rand.NewSource(1)).Read(data)
+
+	for _, tc := range []struct {
+		name    string
+		maxSize int
+		wantErr bool
+	}{
+		{"default", 0, false},
+		{"small", 1024, true},
+	} {
+		t.Run(tc.name, func(t *gotesting.T) {
+			sr, cw := io.Pipe()
+			cr, sw := io.Pipe()
+			stopped := make(chan error, 1)
+			go func() {
+				stopped <- RunServer(sr, sw, nil, func(srv *grpc.Server, req *protocol.HandshakeRequest) error {
+					return nil
+				})
+			}()
+			defer func() {
+				cw.Close()
+				cr.Close()
+				<-stopped
+			}()
+
+			cl, err := NewClientWithOptions(ctx, cr, cw, &protocol.HandshakeRequest{}, &ClientOptions{MaxMessageSize: tc.maxSize})
+			if err != nil {
+				t.Fatal("NewClientWithOptions failed: ", err)
+			}
+			defer cl.Close()
+
+			src := filepath.Join(td, tc.name, "src")
+			if err := testutil.WriteFiles(src, map[string]string{"data": string(data)}); err != nil {
+				t.Fatal(err)
+			}
+			dst := filepath.Join(td, tc.name, "dst")
+			if err := os.MkdirAll(dst, 0755); err != nil {
+				t.Fatal(err)
+			}
+			err = pullDirectory(ctx, protocol.NewFileTransferClient(cl.Conn()), src, dst)
+			if err != nil && !tc.wantErr {
+				t.Error("pullDirectory failed: ", err)
+			} else if err == nil && tc.wantErr {
+				t.Error("pullDirectory succeeded unexpectedly")
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package rpc
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	frameworkprotocol "go.chromium.org/tast/core/framework/protocol"
+	"go.chromium.org/tast/core/screencapture"
+)
+
+// defaultRecordInterval is the interval between frames used by RecordScreen
+// if it is not specified.
+const defaultRecordInterval = time.Second
+
+// screenCaptureServer is an implementation of ScreenCapture gRPC service.
+type screenCaptureServer struct {
+	frameworkprotocol.UnimplementedScreenCaptureServer
+}
+
+func newScreenCaptureServer() *screenCaptureServer {
+	return &screenCaptureServer{}
+}
+
+func (s *screenCaptureServer) CaptureScreenshot(ctx context.Context, req *frameworkprotocol.CaptureScreenshotRequest) (*frameworkprotocol.CaptureScreenshotResponse, error) {
+	td, err := os.MkdirTemp("", "tast_screenshot.")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(td)
+
+	path := filepath.Join(td, "screenshot.png")
+	if err := screencapture.Screenshot(ctx, path); err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &frameworkprotocol.CaptureScreenshotResponse{Png: b}, nil
+}
+
+func (s *screenCaptureServer) RecordScreen(ctx context.Context, req *frameworkprotocol.RecordScreenRequest) (*frameworkprotocol.RecordScreenResponse, error) {
+	duration := req.GetDuration().AsDuration()
+	if duration <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid duration %v", duration)
+	}
+	interval := defaultRecordInterval
+	if req.GetInterval() != nil {
+		interval = req.GetInterval().AsDuration()
+	}
+
+	r, err := screencapture.StartRecording(ctx, interval, 0)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	defer r.Close()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(duration):
+	}
+	if _, err := r.Stop(); err != nil {
+		return nil, err
+	}
+
+	td, err := os.MkdirTemp("", "tast_screenrecord.")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(td)
+
+	path := filepath.Join(td, "record.gif")
+	if err := r.Save(path); err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &frameworkprotocol.RecordScreenResponse{Gif: b}, nil
+}
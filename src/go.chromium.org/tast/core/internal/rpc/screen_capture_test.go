@@ -0,0 +1,82 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"image/gif"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	frameworkprotocol "go.chromium.org/tast/core/framework/protocol"
+	"go.chromium.org/tast/core/internal/screencapturetest"
+)
+
+// startScreenCaptureServer starts a gRPC server serving ScreenCapture with a
+// fake screenshot command, and returns its client.
+func startScreenCaptureServer(t *testing.T) frameworkprotocol.ScreenCaptureClient {
+	screencapturetest.InstallFakeScreenshot(t)
+
+	gs := grpc.NewServer()
+	frameworkprotocol.RegisterScreenCaptureServer(gs, newScreenCaptureServer())
+
+	lis, err := net.ListenTCP("tcp", nil)
+	if err != nil {
+		t.Fatal("Failed to listen: ", err)
+	}
+	go gs.Serve(lis)
+	t.Cleanup(gs.Stop)
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal("Failed to dial: ", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return frameworkprotocol.NewScreenCaptureClient(conn)
+}
+
+func TestScreenCaptureServerCaptureScreenshot(t *testing.T) {
+	cl := startScreenCaptureServer(t)
+
+	res, err := cl.CaptureScreenshot(context.Background(), &frameworkprotocol.CaptureScreenshotRequest{})
+	if err != nil {
+		t.Fatal("CaptureScreenshot failed: ", err)
+	}
+	if got := string(res.GetPng()); got != screencapturetest.FakeImage {
+		t.Errorf("CaptureScreenshot returned %q; want %q", got, screencapturetest.FakeImage)
+	}
+}
+
+func TestScreenCaptureServerRecordScreen(t *testing.T) {
+	cl := startScreenCaptureServer(t)
+
+	res, err := cl.RecordScreen(context.Background(), &frameworkprotocol.RecordScreenRequest{
+		Duration: durationpb.New(100 * time.Millisecond),
+		Interval: durationpb.New(10 * time.Millisecond),
+	})
+	if err != nil {
+		t.Fatal("RecordScreen failed: ", err)
+	}
+	anim, err := gif.DecodeAll(bytes.NewReader(res.GetGif()))
+	if err != nil {
+		t.Fatal("Failed to decode the recording: ", err)
+	}
+	if len(anim.Image) == 0 {
+		t.Error("RecordScreen returned no frame")
+	}
+}
+
+func TestScreenCaptureServerRecordScreenInvalidDuration(t *testing.T) {
+	cl := startScreenCaptureServer(t)
+
+	if _, err := cl.RecordScreen(context.Background(), &frameworkprotocol.RecordScreenRequest{}); err == nil {
+		t.Error("RecordScreen unexpectedly succeeded")
+	}
+}
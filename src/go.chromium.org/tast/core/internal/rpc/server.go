@@ -15,15 +15,18 @@ import (
 	"runtime/debug"
 	"strconv"
 	"sync"
+	"time"
 
 	"golang.org/x/sys/unix"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
 
 	"go.chromium.org/tast/core/errors"
+	frameworkprotocol "go.chromium.org/tast/core/framework/protocol"
 	"go.chromium.org/tast/core/internal/logging"
 	"go.chromium.org/tast/core/internal/protocol"
 	"go.chromium.org/tast/core/internal/testcontext"
@@ -34,6 +37,18 @@ import (
 // MaxMessageSize is used to tell Tast's GRPC servers and clients the maximum size of messages.
 const MaxMessageSize = 1024 * 1024 * 8
 
+// minKeepaliveInterval is the minimum interval of keepalive pings servers
+// accept from clients. Clients sending pings more often are disconnected.
+const minKeepaliveInterval = 10 * time.Second
+
+// maxMessageSize returns the maximum size of messages requested by req.
+func maxMessageSize(req *protocol.HandshakeRequest) int {
+	if size := req.GetMaxMessageSize(); size > 0 {
+		return int(size)
+	}
+	return MaxMessageSize
+}
+
 // RunServer runs a gRPC server on r/w channels.
 // register is called back to register core services. svcs is a list of
 // user-defined gRPC services to be registered if the client requests them in
@@ -66,7 +81,7 @@ func RunServer(r io.Reader, w io.Writer, svcs []*testing.Service, register func(
 
 	// Setup logger to encapsulate the underlying logging mechanism.
 	logger := logging.NewFuncLogger(ls.Log)
-	srv := grpc.NewServer(serverOpts(ls, logger, &calls)...)
+	srv := grpc.NewServer(serverOpts(ls, logger, &calls, maxMessageSize(&req))...)
 
 	// Register core services.
 	regErr := registerCoreServices(srv, ls, &req, register)
@@ -91,9 +106,21 @@ func RunServer(r io.Reader, w io.Writer, svcs []*testing.Service, register func(
 		return err
 	}
 
-	if err := sendRawMessage(w, &protocol.HandshakeResponse{}); err != nil {
+	// Compress messages sent to the client if it accepts it. This reduces
+	// bandwidth for chatty clients over slow links, e.g. SSH connections to
+	// DUTs in remote labs.
+	res := &protocol.HandshakeResponse{ZstdCompression: req.GetAcceptZstdCompression()}
+	if err := sendRawMessage(w, res); err != nil {
 		return err
 	}
+	if res.GetZstdCompression() {
+		zw, err := newZstdWriter(w)
+		if err != nil {
+			return errors.Wrap(err, "failed to start compression")
+		}
+		defer zw.Close()
+		w = zw
+	}
 
 	// From now on, catch SIGINT/SIGTERM to stop the server gracefully.
 	sigCh := make(chan os.Signal, 1)
@@ -136,6 +163,81 @@ type tcpServerResponse struct {
 // registered if GuaranteeCompatibility is set.
 func RunTCPServer(port int, handshakeReq *protocol.HandshakeRequest, stdin io.Reader, stdout, stderr io.Writer,
 	svcs []*testing.Service, register func(srv *grpc.Server, req *protocol.HandshakeRequest) error) error {
+	return runListenerServer(handshakeReq, stderr, svcs, true, register, func() (net.Listener, error) {
+		// start gRPC server listening on the tcp port
+		listener, err := net.Listen("tcp4", fmt.Sprintf(":%d", port))
+		if err != nil {
+			return nil, errors.Wrap(err, "server failed to listen")
+		}
+
+		// Return information regarding the server, paving the way for dynamic port assignment.
+		assignedPort := listener.Addr().(*net.TCPAddr).Port
+		response := &tcpServerResponse{Port: assignedPort}
+		responseBytes, err := json.Marshal(response)
+		if err != nil {
+			listener.Close()
+			return nil, errors.Wrapf(err, "failed to marshal json response: %v", response)
+		}
+		fmt.Fprintln(stdout, string(responseBytes))
+		return listener, nil
+	})
+}
+
+// unixServerResponse contains the return value for RunUnixServer.
+type unixServerResponse struct {
+	// Socket is the path of the Unix domain socket the gRPC server is
+	// listening on.
+	Socket string `json:"socket"`
+}
+
+// RunUnixServer runs a gRPC server listening on a Unix domain socket at
+// socketPath, so that programs on the same machine other than Tast can call
+// services directly.
+// The socket is created to be accessible only by the user running the server,
+// so that access to the server is authenticated by filesystem permissions.
+// A stale socket left at socketPath is replaced.
+// HandshakeRequest contains parameters needed to initialize a gRPC server.
+// A line of JSON is written to stdout once the server starts listening.
+// svcs is the candidate list of user-defined gRPC services. All of them are
+// registered if NeedUserServices is set in HandshakeRequest, otherwise only
+// ones with GuaranteeCompatibility set are registered.
+func RunUnixServer(socketPath string, handshakeReq *protocol.HandshakeRequest, stdout, stderr io.Writer,
+	svcs []*testing.Service, register func(srv *grpc.Server, req *protocol.HandshakeRequest) error) error {
+	return runListenerServer(handshakeReq, stderr, svcs, !handshakeReq.GetNeedUserServices(), register, func() (net.Listener, error) {
+		if fi, err := os.Lstat(socketPath); err == nil {
+			if fi.Mode()&os.ModeSocket == 0 {
+				return nil, errors.Errorf("%s exists and is not a socket", socketPath)
+			}
+			if err := os.Remove(socketPath); err != nil {
+				return nil, errors.Wrap(err, "failed to remove stale socket")
+			}
+		}
+
+		// Create the socket with the 0600 permission from the beginning to
+		// avoid a window where other users can connect to it.
+		oldMask := unix.Umask(0177)
+		listener, err := net.Listen("unix", socketPath)
+		unix.Umask(oldMask)
+		if err != nil {
+			return nil, errors.Wrap(err, "server failed to listen")
+		}
+
+		responseBytes, err := json.Marshal(&unixServerResponse{Socket: socketPath})
+		if err != nil {
+			listener.Close()
+			return nil, errors.Wrap(err, "failed to marshal json response")
+		}
+		fmt.Fprintln(stdout, string(responseBytes))
+		return listener, nil
+	})
+}
+
+// runListenerServer runs a gRPC server accepting connections from a listener
+// returned by listen, until SIGINT or SIGTERM is received.
+// guaranteeCompatibilityOnly determines if the user-defined service
+// registration is restricted only to services with GuaranteeCompatibility set.
+func runListenerServer(handshakeReq *protocol.HandshakeRequest, stderr io.Writer, svcs []*testing.Service, guaranteeCompatibilityOnly bool,
+	register func(srv *grpc.Server, req *protocol.HandshakeRequest) error, listen func() (net.Listener, error)) error {
 	// Make sure to return only after all active method calls finish.
 	// Otherwise the process can exit before running deferred function
 	// calls on service goroutines.
@@ -149,7 +251,7 @@ func RunTCPServer(port int, handshakeReq *protocol.HandshakeRequest, stdin io.Re
 
 	// Setup logger to encapsulate the underlying logging mechanism.
 	logger := logging.NewSinkLogger(logging.LevelInfo, false, logging.NewFuncSink(consoleLogFunc))
-	srv := grpc.NewServer(serverOpts(nil, logger, &calls)...)
+	srv := grpc.NewServer(serverOpts(nil, logger, &calls, maxMessageSize(handshakeReq))...)
 
 	// Register core services.
 	if err := registerCoreServices(srv, nil, handshakeReq, register); err != nil {
@@ -160,8 +262,8 @@ func RunTCPServer(port int, handshakeReq *protocol.HandshakeRequest, stdin io.Re
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	// Register user-defined gRPC services intended for public use.
-	registerUserServices(ctx, srv, logger, handshakeReq, svcs, true)
+	// Register user-defined gRPC services.
+	registerUserServices(ctx, srv, logger, handshakeReq, svcs, guaranteeCompatibilityOnly)
 
 	// From now on, catch SIGINT/SIGTERM to stop the server gracefully.
 	sigCh := make(chan os.Signal, 1)
@@ -176,20 +278,10 @@ func RunTCPServer(port int, handshakeReq *protocol.HandshakeRequest, stdin io.Re
 		}
 	}()
 
-	// start gRPC server listening on the tcp port
-	listener, err := net.Listen("tcp4", fmt.Sprintf(":%d", port))
+	listener, err := listen()
 	if err != nil {
-		return errors.Wrap(err, "server failed to listen")
-	}
-
-	// Return information regarding the server, paving the way for dynamic port assignment.
-	assignedPort := listener.Addr().(*net.TCPAddr).Port
-	response := &tcpServerResponse{Port: assignedPort}
-	responseBytes, err := json.Marshal(response)
-	if err != nil {
-		return errors.Wrapf(err, "failed to marshal json response: %v", response)
+		return err
 	}
-	fmt.Fprintln(stdout, string(responseBytes))
 
 	if err := srv.Serve(listener); err != nil && err != io.EOF {
 		// Replace the error if we saw a signal.
@@ -217,7 +309,8 @@ func (s *serverStreamWithContext) Context() context.Context {
 var _ grpc.ServerStream = (*serverStreamWithContext)(nil)
 
 // serverOpts returns gRPC server-side interceptors to manipulate context.
-func serverOpts(ls *remoteLoggingServer, logger logging.Logger, calls *sync.WaitGroup) []grpc.ServerOption {
+// maxSize is the maximum size of messages sent and received.
+func serverOpts(ls *remoteLoggingServer, logger logging.Logger, calls *sync.WaitGroup, maxSize int) []grpc.ServerOption {
 	// hook is called on every gRPC method call.
 	// It returns a Context to be passed to a gRPC method, a function to be
 	// called on the end of the gRPC method call to compute trailers, and
@@ -324,8 +417,14 @@ func serverOpts(ls *remoteLoggingServer, logger logging.Logger, calls *sync.Wait
 			}()
 			return handler(srv, stream)
 		}),
-		grpc.MaxRecvMsgSize(MaxMessageSize),
-		grpc.MaxSendMsgSize(MaxMessageSize),
+		grpc.MaxRecvMsgSize(maxSize),
+		grpc.MaxSendMsgSize(maxSize),
+		// Allow clients to send keepalive pings to detect broken connections
+		// during long method calls.
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             minKeepaliveInterval,
+			PermitWithoutStream: true,
+		}),
 	}
 }
 
@@ -342,6 +441,7 @@ func registerCoreServices(srv *grpc.Server, ls *remoteLoggingServer,
 		protocol.RegisterLoggingServer(srv, ls)
 	}
 	protocol.RegisterFileTransferServer(srv, newFileTransferServer())
+	frameworkprotocol.RegisterScreenCaptureServer(srv, newScreenCaptureServer())
 	return register(srv, handshakeReq)
 }
 
@@ -0,0 +1,84 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"go.chromium.org/tast/core/internal/testcontext"
+)
+
+// RPCTraceFile is a file name under the output directory of a test where gRPC
+// method calls to user services made by the test are recorded.
+const RPCTraceFile = "rpc_trace.json"
+
+// RPCTraceEntry describes a gRPC method call recorded in RPCTraceFile.
+type RPCTraceEntry struct {
+	// Method is the full name of the gRPC method, e.g. "/tast.cros.example.Foo/Bar".
+	Method string `json:"method"`
+	// Start is the time at which the call started.
+	Start time.Time `json:"start"`
+	// Duration is the duration of the call.
+	Duration time.Duration `json:"duration"`
+	// Status is the gRPC status code of the call, e.g. "OK".
+	Status string `json:"status"`
+	// RequestSize is the total size of request messages in bytes.
+	RequestSize int `json:"requestSize"`
+}
+
+// rpcTraceMu serializes updates to RPCTraceFile.
+var rpcTraceMu sync.Mutex
+
+// messageSize returns the size of a gRPC message in bytes.
+func messageSize(m interface{}) int {
+	if pm, ok := m.(proto.Message); ok {
+		return proto.Size(pm)
+	}
+	return 0
+}
+
+// recordRPCTrace appends an entry describing a gRPC method call to
+// RPCTraceFile in the output directory associated with ctx. It does nothing
+// if ctx is not associated with an output directory.
+func recordRPCTrace(ctx context.Context, method string, start time.Time, callErr error, requestSize int) error {
+	outDir, ok := testcontext.OutDir(ctx)
+	if !ok || outDir == "" {
+		return nil
+	}
+	entry := RPCTraceEntry{
+		Method:      method,
+		Start:       start,
+		Duration:    time.Since(start),
+		Status:      status.Code(callErr).String(),
+		RequestSize: requestSize,
+	}
+
+	rpcTraceMu.Lock()
+	defer rpcTraceMu.Unlock()
+
+	path := filepath.Join(outDir, RPCTraceFile)
+	var entries []RPCTraceEntry
+	if b, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(b, &entries); err != nil {
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	entries = append(entries, entry)
+	b, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0644)
+}
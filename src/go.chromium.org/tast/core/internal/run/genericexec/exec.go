@@ -18,6 +18,7 @@ type ExecCmd struct {
 	name      string
 	baseArgs  []string
 	debugPort int
+	retry     *RetryPolicy
 }
 
 var _ Cmd = &ExecCmd{}
@@ -30,6 +31,14 @@ func CommandExec(name string, baseArgs ...string) *ExecCmd {
 	}
 }
 
+// WithRetry returns a copy of the command that is retried on transient
+// failures according to p.
+func (c *ExecCmd) WithRetry(p RetryPolicy) *ExecCmd {
+	cc := *c
+	cc.retry = &p
+	return &cc
+}
+
 // DebugCommand returns a version of this command that will run under the debugger.
 func (c *ExecCmd) DebugCommand(ctx context.Context, debugPort int) (Cmd, error) {
 	if debugPort == 0 {
@@ -43,25 +52,47 @@ func (c *ExecCmd) DebugCommand(ctx context.Context, debugPort int) (Cmd, error)
 		debugEnv = debugger.DlvDUTEnv
 	}
 	name, baseArgs := debugger.RewriteDebugCommand(debugPort, debugEnv, c.name, c.baseArgs...)
-	return &ExecCmd{name: name, baseArgs: baseArgs, debugPort: debugPort}, nil
+	return &ExecCmd{name: name, baseArgs: baseArgs, debugPort: debugPort, retry: c.retry}, nil
 }
 
 // Run runs a local command synchronously. See Cmd.Run for details.
 func (c *ExecCmd) Run(ctx context.Context, extraArgs []string, stdin io.Reader, stdout, stderr io.Writer) error {
-	cmd := exec.CommandContext(ctx, c.name, append(c.baseArgs, extraArgs...)...)
-	cmd.Stdin = stdin
-	cmd.Stdout = stdout
-	cmd.Stderr = stderr
-	// Set FD 3 to the real stderr so that the subprocess can write stack
-	// traces.
-	// TODO(b/189332919): Remove this hack and write stack traces to stderr
-	// once we finish migrating to gRPC-based protocol. This hack is needed
-	// because JSON-based protocol is designed to write messages to stderr
-	// in case of errors and thus Tast CLI consumes stderr.
-	cmd.ExtraFiles = []*os.File{os.Stderr}
-	cmd.Env = append(os.Environ(), "TAST_B189332919_STACK_TRACE_FD=3")
 	debugger.PrintWaitingMessage(ctx, c.debugPort)
-	return cmd.Run()
+	return c.retryPolicy(stdin).retry(ctx, c.name, func(n int) (bool, error) {
+		if n > 0 {
+			rewindStdin(stdin)
+		}
+		cmd := exec.CommandContext(ctx, c.name, append(c.baseArgs, extraArgs...)...)
+		cmd.Stdin = stdin
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+		// Set FD 3 to the real stderr so that the subprocess can write stack
+		// traces.
+		// TODO(b/189332919): Remove this hack and write stack traces to stderr
+		// once we finish migrating to gRPC-based protocol. This hack is needed
+		// because JSON-based protocol is designed to write messages to stderr
+		// in case of errors and thus Tast CLI consumes stderr.
+		cmd.ExtraFiles = []*os.File{os.Stderr}
+		cmd.Env = append(os.Environ(), "TAST_B189332919_STACK_TRACE_FD=3")
+		if err := cmd.Start(); err != nil {
+			return false, err
+		}
+		return true, cmd.Wait()
+	})
+}
+
+// retryPolicy returns the policy to retry the command reading stdin.
+// Commands are not retried after they start if stdin can not be read again.
+func (c *ExecCmd) retryPolicy(stdin io.Reader) *RetryPolicy {
+	if c.retry == nil || !c.retry.Idempotent {
+		return c.retry
+	}
+	if _, ok := stdin.(io.Seeker); stdin != nil && !ok {
+		p := *c.retry
+		p.Idempotent = false
+		return &p
+	}
+	return c.retry
 }
 
 // Interact runs a local command asynchronously. See Cmd.Interact for details.
@@ -73,20 +104,25 @@ func (c *ExecCmd) Interact(ctx context.Context, extraArgs []string) (p Process,
 		}
 	}()
 
-	cmd := exec.CommandContext(ctx, c.name, append(c.baseArgs, extraArgs...)...)
-	stdin, err := cmd.StdinPipe()
-	if err != nil {
-		return nil, err
-	}
-	stdout, err := cmd.StdoutPipe()
-	if err != nil {
-		return nil, err
-	}
-	stderr, err := cmd.StderrPipe()
-	if err != nil {
-		return nil, err
-	}
-	if err := cmd.Start(); err != nil {
+	var cmd *exec.Cmd
+	var stdin io.WriteCloser
+	var stdout, stderr io.ReadCloser
+	// Only failures to start the command are retried since the caller
+	// interacts with the process once it starts.
+	if err := c.retry.retry(ctx, c.name, func(n int) (bool, error) {
+		cmd = exec.CommandContext(ctx, c.name, append(c.baseArgs, extraArgs...)...)
+		var err error
+		if stdin, err = cmd.StdinPipe(); err != nil {
+			return false, err
+		}
+		if stdout, err = cmd.StdoutPipe(); err != nil {
+			return false, err
+		}
+		if stderr, err = cmd.StderrPipe(); err != nil {
+			return false, err
+		}
+		return false, cmd.Start()
+	}); err != nil {
 		return nil, err
 	}
 	debugger.PrintWaitingMessage(ctx, c.debugPort)
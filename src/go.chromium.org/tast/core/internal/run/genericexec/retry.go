@@ -0,0 +1,139 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package genericexec
+
+import (
+	"context"
+	"io"
+	"net"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+
+	cryptossh "golang.org/x/crypto/ssh"
+
+	"go.chromium.org/tast/core/errors"
+	"go.chromium.org/tast/core/internal/logging"
+	"go.chromium.org/tast/core/ssh"
+)
+
+// RetryPolicy specifies how commands failing with transient errors, e.g. a
+// reset SSH connection, are retried.
+//
+// A command that fails before it starts is always safe to retry. A command
+// that fails after it starts is retried only if it is marked Idempotent, since
+// it may have had side effects already. Output written by failed attempts is
+// not discarded, so callers of idempotent commands should be prepared to see
+// partial output repeated. Interact only retries failures to start commands.
+type RetryPolicy struct {
+	// Attempts is the maximum number of attempts including the first one.
+	// Values less than 2 disable retries.
+	Attempts int
+	// InitialBackoff is the delay before the first retry. The delay is
+	// doubled for each subsequent retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between retries if it is positive.
+	MaxBackoff time.Duration
+	// Idempotent indicates that the command can be safely run again even if
+	// an earlier attempt started.
+	Idempotent bool
+	// Reconnect is called before each retry of an SSHCmd to obtain a
+	// connection to use for the retry, e.g. to replace a broken connection.
+	// If it is nil, the original connection is reused.
+	Reconnect func(ctx context.Context) (*ssh.Conn, error)
+}
+
+// DefaultRetryPolicy is a RetryPolicy suitable for most commands that are not
+// idempotent. Copy it and set Idempotent to retry commands after they start.
+var DefaultRetryPolicy = RetryPolicy{
+	Attempts:       3,
+	InitialBackoff: time.Second,
+	MaxBackoff:     10 * time.Second,
+}
+
+// backoff returns the delay before the n-th retry (1-based).
+func (p *RetryPolicy) backoff(n int) time.Duration {
+	d := p.InitialBackoff
+	for i := 1; i < n; i++ {
+		d *= 2
+		if p.MaxBackoff > 0 && d >= p.MaxBackoff {
+			break
+		}
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	return d
+}
+
+// retry calls attempt until it succeeds or fails with a non-transient error
+// as specified by p. attempt is called with the 0-based number of the attempt
+// and should return whether the command started along with an error. name is
+// used to log retries.
+func (p *RetryPolicy) retry(ctx context.Context, name string, attempt func(n int) (started bool, err error)) error {
+	for n := 0; ; n++ {
+		started, err := attempt(n)
+		if err == nil {
+			return nil
+		}
+		if p == nil || n+1 >= p.Attempts || !IsTransient(err) || (started && !p.Idempotent) {
+			return err
+		}
+		d := p.backoff(n + 1)
+		logging.Infof(ctx, "Retrying %s in %v after transient error (attempt %d/%d): %v", name, d, n+2, p.Attempts, err)
+		select {
+		case <-time.After(d):
+		case <-ctx.Done():
+			return errors.Wrapf(ctx.Err(), "gave up retrying %s after %v", name, err)
+		}
+	}
+}
+
+// IsTransient returns whether err looks like a transient failure to run a
+// command, e.g. a reset connection, rather than a failure reported by the
+// command itself.
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	var sshExitErr *cryptossh.ExitError
+	var execExitErr *exec.ExitError
+	if errors.As(err, &sshExitErr) || errors.As(err, &execExitErr) {
+		return false
+	}
+	var missingErr *cryptossh.ExitMissingError
+	var openErr *cryptossh.OpenChannelError
+	if errors.As(err, &missingErr) || errors.As(err, &openErr) {
+		return true
+	}
+	for _, target := range []error{io.EOF, io.ErrUnexpectedEOF, net.ErrClosed, syscall.ECONNRESET, syscall.EPIPE, syscall.ETXTBSY} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	// Some errors are flattened to strings while passed over layers.
+	msg := err.Error()
+	for _, s := range []string{"connection reset by peer", "broken pipe", "text file busy"} {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// rewindStdin prepares stdin to be read again by a retry. It returns false if
+// stdin can not be rewound.
+func rewindStdin(stdin io.Reader) bool {
+	if stdin == nil {
+		return true
+	}
+	s, ok := stdin.(io.Seeker)
+	if !ok {
+		return false
+	}
+	_, err := s.Seek(0, io.SeekStart)
+	return err == nil
+}
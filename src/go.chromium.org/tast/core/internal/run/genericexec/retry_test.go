@@ -0,0 +1,112 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package genericexec_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os/exec"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"go.chromium.org/tast/core/errors"
+	"go.chromium.org/tast/core/internal/run/genericexec"
+	"go.chromium.org/tast/core/internal/sshtest"
+	"go.chromium.org/tast/core/ssh"
+)
+
+// runFlakySSHCmd runs a command with policy on a fake SSH server whose
+// connection for the command drops on the first attempt unless exitStatus is
+// non-zero. It returns the stdout, the number of attempts seen by the server
+// and the error from Run.
+func runFlakySSHCmd(t *testing.T, exitStatus int, policy genericexec.RetryPolicy) (stdout string, calls int32, err error) {
+	td := sshtest.NewTestData(func(req *sshtest.ExecReq) {
+		n := atomic.AddInt32(&calls, 1)
+		req.Start(true)
+		if n == 1 && exitStatus == 0 {
+			// Close the channel without reporting an exit status.
+			return
+		}
+		io.WriteString(req, "ok")
+		req.End(exitStatus)
+	})
+	defer td.Close()
+
+	ctx := context.Background()
+	conn, err := ssh.New(ctx, &ssh.Options{
+		Hostname: td.Srvs[0].Addr().String(),
+		KeyFile:  td.UserKeyFile,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close(ctx)
+
+	cmd := genericexec.CommandSSH(conn, "true").WithRetry(policy)
+	var buf bytes.Buffer
+	err = cmd.Run(ctx, nil, nil, &buf, io.Discard)
+	return buf.String(), atomic.LoadInt32(&calls), err
+}
+
+func TestSSHCmdRunRetryIdempotent(t *testing.T) {
+	policy := genericexec.RetryPolicy{Attempts: 3, InitialBackoff: time.Millisecond, Idempotent: true}
+	stdout, calls, err := runFlakySSHCmd(t, 0, policy)
+	if err != nil {
+		t.Fatal("Run failed: ", err)
+	}
+	if calls != 2 {
+		t.Errorf("Command ran %d time(s); want 2", calls)
+	}
+	if stdout != "ok" {
+		t.Errorf("Stdout = %q; want %q", stdout, "ok")
+	}
+}
+
+func TestSSHCmdRunRetryNotIdempotent(t *testing.T) {
+	// Commands that may have had side effects are not retried.
+	policy := genericexec.RetryPolicy{Attempts: 3, InitialBackoff: time.Millisecond}
+	_, calls, err := runFlakySSHCmd(t, 0, policy)
+	if err == nil {
+		t.Error("Run succeeded unexpectedly")
+	}
+	if calls != 1 {
+		t.Errorf("Command ran %d time(s); want 1", calls)
+	}
+}
+
+func TestSSHCmdRunRetryExitStatus(t *testing.T) {
+	// Failures reported by commands themselves are not retried.
+	policy := genericexec.RetryPolicy{Attempts: 3, InitialBackoff: time.Millisecond, Idempotent: true}
+	_, calls, err := runFlakySSHCmd(t, 1, policy)
+	if err == nil {
+		t.Error("Run succeeded unexpectedly")
+	}
+	if calls != 1 {
+		t.Errorf("Command ran %d time(s); want 1", calls)
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	exitErr := exec.Command("false").Run()
+	for _, tc := range []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"EOF", errors.Wrap(io.EOF, "failed to read"), true},
+		{"ECONNRESET", errors.Wrap(syscall.ECONNRESET, "failed to write"), true},
+		{"flattened", errors.New("read tcp: connection reset by peer"), true},
+		{"exit", exitErr, false},
+		{"other", errors.New("permission denied"), false},
+	} {
+		if got := genericexec.IsTransient(tc.err); got != tc.want {
+			t.Errorf("IsTransient(%s: %v) = %v; want %v", tc.name, tc.err, got, tc.want)
+		}
+	}
+}
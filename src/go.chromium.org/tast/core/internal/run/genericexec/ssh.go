@@ -21,6 +21,7 @@ type SSHCmd struct {
 	name      string
 	baseArgs  []string
 	debugPort int
+	retry     *RetryPolicy
 }
 
 var _ Cmd = &SSHCmd{}
@@ -35,6 +36,14 @@ func CommandSSH(conn *ssh.Conn, name string, baseArgs ...string) *SSHCmd {
 	}
 }
 
+// WithRetry returns a copy of the command that is retried on transient
+// failures according to p.
+func (c *SSHCmd) WithRetry(p RetryPolicy) *SSHCmd {
+	cc := *c
+	cc.retry = &p
+	return &cc
+}
+
 // Keep attempting to kill the debugger. It's possible that kill will fail
 // with "no such process", so just do an || true to ensure it doesn't fail.
 const killDebuggerCommand = `while pid=$(pgrep ^dlv$); do kill "${pid}" || true; done`
@@ -53,17 +62,52 @@ func (c *SSHCmd) DebugCommand(ctx context.Context, debugPort int) (Cmd, error) {
 		return nil, errors.Errorf("Failed to kill the current debugger. stderr: %s. Error: %+v", stderr.String(), err)
 	}
 	name, baseArgs := debugger.RewriteDebugCommand(debugPort, debugger.DlvDUTEnv, c.name, c.baseArgs...)
-	return &SSHCmd{conn: c.conn, name: name, baseArgs: baseArgs, debugPort: debugPort}, nil
+	return &SSHCmd{conn: c.conn, name: name, baseArgs: baseArgs, debugPort: debugPort, retry: c.retry}, nil
 }
 
 // Run runs a remote command synchronously. See Cmd.Run for details.
 func (c *SSHCmd) Run(ctx context.Context, extraArgs []string, stdin io.Reader, stdout, stderr io.Writer) error {
-	cmd := c.conn.CommandContext(ctx, c.name, append(c.baseArgs, extraArgs...)...)
-	cmd.Stdin = stdin
-	cmd.Stdout = stdout
-	cmd.Stderr = stderr
 	debugger.PrintWaitingMessage(ctx, c.debugPort)
-	return cmd.Run()
+	return c.retryPolicy(stdin).retry(ctx, c.name, func(n int) (bool, error) {
+		conn, err := c.connForAttempt(ctx, n)
+		if err != nil {
+			return false, err
+		}
+		if n > 0 {
+			rewindStdin(stdin)
+		}
+		cmd := conn.CommandContext(ctx, c.name, append(c.baseArgs, extraArgs...)...)
+		cmd.Stdin = stdin
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+		if err := cmd.Start(); err != nil {
+			return false, err
+		}
+		return true, cmd.Wait()
+	})
+}
+
+// retryPolicy returns the policy to retry the command reading stdin.
+// Commands are not retried after they start if stdin can not be read again.
+func (c *SSHCmd) retryPolicy(stdin io.Reader) *RetryPolicy {
+	if c.retry == nil || !c.retry.Idempotent {
+		return c.retry
+	}
+	if _, ok := stdin.(io.Seeker); stdin != nil && !ok {
+		p := *c.retry
+		p.Idempotent = false
+		return &p
+	}
+	return c.retry
+}
+
+// connForAttempt returns the connection to use for the n-th attempt (0-based)
+// to run the command.
+func (c *SSHCmd) connForAttempt(ctx context.Context, n int) (*ssh.Conn, error) {
+	if n == 0 || c.retry == nil || c.retry.Reconnect == nil {
+		return c.conn, nil
+	}
+	return c.retry.Reconnect(ctx)
 }
 
 // Interact runs a remote command asynchronously. See Cmd.Interact for details.
@@ -74,20 +118,28 @@ func (c *SSHCmd) Interact(ctx context.Context, extraArgs []string) (p Process, r
 			cancel()
 		}
 	}()
-	cmd := c.conn.CommandContext(ctx, c.name, append(c.baseArgs, extraArgs...)...)
-	stdin, err := cmd.StdinPipe()
-	if err != nil {
-		return nil, err
-	}
-	stdout, err := cmd.StdoutPipe()
-	if err != nil {
-		return nil, err
-	}
-	stderr, err := cmd.StderrPipe()
-	if err != nil {
-		return nil, err
-	}
-	if err := cmd.Start(); err != nil {
+	var cmd *ssh.Cmd
+	var stdin io.WriteCloser
+	var stdout, stderr io.ReadCloser
+	// Only failures to start the command are retried since the caller
+	// interacts with the process once it starts.
+	if err := c.retry.retry(ctx, c.name, func(n int) (bool, error) {
+		conn, err := c.connForAttempt(ctx, n)
+		if err != nil {
+			return false, err
+		}
+		cmd = conn.CommandContext(ctx, c.name, append(c.baseArgs, extraArgs...)...)
+		if stdin, err = cmd.StdinPipe(); err != nil {
+			return false, err
+		}
+		if stdout, err = cmd.StdoutPipe(); err != nil {
+			return false, err
+		}
+		if stderr, err = cmd.StderrPipe(); err != nil {
+			return false, err
+		}
+		return false, cmd.Start()
+	}); err != nil {
 		return nil, err
 	}
 	debugger.PrintWaitingMessage(ctx, c.debugPort)
@@ -0,0 +1,43 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package reporting
+
+import (
+	"encoding/json"
+	"os"
+
+	"go.chromium.org/tast/core/internal/run/resultsjson"
+)
+
+// FixtureResultsFilename is a file name to be used with FixtureResultsWriter.
+const FixtureResultsFilename = "fixture_results.jsonl"
+
+// FixtureResultsWriter writes a stream of JSON-marshaled
+// resultsjson.FixtureResult objects to a file.
+type FixtureResultsWriter struct {
+	f   *os.File
+	enc *json.Encoder
+}
+
+// NewFixtureResultsWriter creates and returns a new FixtureResultsWriter for
+// writing to a file at path.
+// If the file already exists, new results are appended to it.
+func NewFixtureResultsWriter(path string) (*FixtureResultsWriter, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
+	if err != nil {
+		return nil, err
+	}
+	return &FixtureResultsWriter{f: f, enc: json.NewEncoder(f)}, nil
+}
+
+// Close closes the underlying file.
+func (w *FixtureResultsWriter) Close() {
+	w.f.Close()
+}
+
+// Write writes the JSON-marshaled representation of res to the file.
+func (w *FixtureResultsWriter) Write(res *resultsjson.FixtureResult) error {
+	return w.enc.Encode(res)
+}
@@ -0,0 +1,316 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package reporting
+
+import (
+	"context"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"go.chromium.org/tast/core/internal/run/resultsjson"
+)
+
+// HTMLReportFilename is a file name to be used with WriteHTMLReport.
+const HTMLReportFilename = "results.html"
+
+// htmlMaxLogBytes is the maximum number of bytes of a test log embedded in an
+// HTML report. Longer logs are truncated from the beginning, since failures
+// are usually explained at the end of logs.
+const htmlMaxLogBytes = 64 * 1024
+
+// htmlTestLogFilename is the name of the log file in the output directory of
+// a test.
+const htmlTestLogFilename = "log.txt"
+
+// htmlReport is passed to htmlTemplate.
+type htmlReport struct {
+	Generated   time.Time
+	Duration    time.Duration
+	Passed      int
+	Failed      int
+	Skipped     int
+	Tests       []*htmlTest
+	SkipReasons []*htmlSkipReason
+}
+
+// htmlTest describes a test in an HTML report.
+type htmlTest struct {
+	Name     string
+	Status   string // "PASS", "FAIL" or "SKIP"
+	Start    time.Time
+	Duration time.Duration
+	Errors   []resultsjson.Error
+	// SkipReason is the reason why the test was skipped.
+	SkipReason string
+	// OutDir is the output directory of the test relative to the report.
+	OutDir string
+	// Log is the tail of the log of a failed test.
+	Log string
+	// LogTruncated is true if the beginning of Log was dropped.
+	LogTruncated bool
+	// ChartOffset and ChartWidth are the position of the test in the timing
+	// chart in percent of the duration of the run.
+	ChartOffset float64
+	ChartWidth  float64
+}
+
+// htmlSkipReason counts tests skipped for a reason.
+type htmlSkipReason struct {
+	Code  string
+	Count int
+}
+
+// WriteHTMLReport saves a standalone HTML report of results to path. Logs of
+// failed tests are read from their output directories and embedded in the
+// report, and links to output directories are relative to path.
+func WriteHTMLReport(path string, results []*resultsjson.Result) error {
+	rep := newHTMLReport(filepath.Dir(path), results)
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := htmlTemplate.Execute(f, rep); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// newHTMLReport summarizes results for an HTML report saved under dir.
+func newHTMLReport(dir string, results []*resultsjson.Result) *htmlReport {
+	rep := &htmlReport{Generated: time.Now()}
+
+	var runStart, runEnd time.Time
+	for _, r := range results {
+		if r.Start.IsZero() || r.End.IsZero() {
+			continue
+		}
+		if runStart.IsZero() || r.Start.Before(runStart) {
+			runStart = r.Start
+		}
+		if r.End.After(runEnd) {
+			runEnd = r.End
+		}
+	}
+	rep.Duration = runEnd.Sub(runStart)
+
+	skipCounts := make(map[string]int)
+	for _, r := range results {
+		t := &htmlTest{
+			Name:       r.Name,
+			Start:      r.Start,
+			Errors:     r.Errors,
+			SkipReason: r.SkipReason,
+		}
+		if !r.End.IsZero() {
+			t.Duration = r.End.Sub(r.Start)
+		}
+		if r.OutDir != "" {
+			if rel, err := filepath.Rel(dir, r.OutDir); err == nil {
+				t.OutDir = filepath.ToSlash(rel)
+			}
+		}
+
+		switch {
+		case r.SkipReason != "":
+			t.Status = "SKIP"
+			rep.Skipped++
+			if r.Quarantined {
+				skipCounts["QUARANTINED"]++
+			} else if len(r.SkipReasons) == 0 {
+				skipCounts["UNKNOWN"]++
+			}
+			for _, sr := range r.SkipReasons {
+				skipCounts[sr.Code]++
+			}
+		case len(r.Errors) > 0:
+			t.Status = "FAIL"
+			rep.Failed++
+			if r.OutDir != "" {
+				t.Log, t.LogTruncated = readLogTail(filepath.Join(r.OutDir, htmlTestLogFilename), htmlMaxLogBytes)
+			}
+		default:
+			t.Status = "PASS"
+			rep.Passed++
+		}
+
+		if rep.Duration > 0 && !r.Start.IsZero() && !r.End.IsZero() {
+			t.ChartOffset = 100 * float64(r.Start.Sub(runStart)) / float64(rep.Duration)
+			t.ChartWidth = 100 * float64(t.Duration) / float64(rep.Duration)
+		}
+		rep.Tests = append(rep.Tests, t)
+	}
+
+	for code, n := range skipCounts {
+		rep.SkipReasons = append(rep.SkipReasons, &htmlSkipReason{Code: code, Count: n})
+	}
+	sort.Slice(rep.SkipReasons, func(i, j int) bool {
+		a, b := rep.SkipReasons[i], rep.SkipReasons[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Code < b.Code
+	})
+	return rep
+}
+
+// readLogTail returns at most max bytes at the end of the file at path and
+// whether the content was truncated. It returns an empty string if the file
+// can not be read, e.g. since the test did not write a log.
+func readLogTail(path string, max int64) (string, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", false
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return "", false
+	}
+	truncated := fi.Size() > max
+	if truncated {
+		if _, err := f.Seek(-max, io.SeekEnd); err != nil {
+			return "", false
+		}
+	}
+	b, err := io.ReadAll(io.LimitReader(f, max))
+	if err != nil {
+		return "", false
+	}
+	return string(b), truncated
+}
+
+// htmlReporter writes a summary of results to results.html.
+type htmlReporter struct{ BaseReporter }
+
+func (htmlReporter) RunFinished(ctx context.Context, run *RunInfo, summary *RunSummary) error {
+	return WriteHTMLReport(filepath.Join(run.ResDir, HTMLReportFilename), summary.Results)
+}
+
+var htmlTemplate = template.Must(template.New("results.html").Funcs(template.FuncMap{
+	"formatTime": func(t time.Time) string {
+		if t.IsZero() {
+			return "-"
+		}
+		return t.Format("2006-01-02 15:04:05")
+	},
+	"seconds": func(d time.Duration) string {
+		return d.Round(time.Millisecond).String()
+	},
+	"unixMilli": func(t time.Time) int64 {
+		if t.IsZero() {
+			return 0
+		}
+		return t.UnixMilli()
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Tast results</title>
+<style>
+body { font-family: sans-serif; margin: 1em 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 2px 8px; text-align: left; vertical-align: top; }
+th.sortable { cursor: pointer; user-select: none; }
+.PASS { color: #188038; }
+.FAIL { color: #d93025; }
+.SKIP { color: #80868b; }
+.chart { position: relative; width: 600px; height: 10px; background: #f1f3f4; }
+.chart div { position: absolute; height: 10px; min-width: 1px; }
+.chart .PASS { background: #188038; }
+.chart .FAIL { background: #d93025; }
+.chart .SKIP { background: #80868b; }
+pre { background: #f8f9fa; max-height: 30em; overflow: auto; padding: 4px; }
+</style>
+</head>
+<body>
+<h1>Tast results</h1>
+<p>
+{{len .Tests}} tests:
+<span class="PASS">{{.Passed}} passed</span>,
+<span class="FAIL">{{.Failed}} failed</span>,
+<span class="SKIP">{{.Skipped}} skipped</span>
+in {{seconds .Duration}}.
+Generated at {{formatTime .Generated}}.
+</p>
+
+{{if .Skipped}}
+<h2>Skip reasons</h2>
+<table>
+<tr><th>Reason</th><th>Tests</th></tr>
+{{range .SkipReasons}}<tr><td>{{.Code}}</td><td>{{.Count}}</td></tr>
+{{end}}</table>
+{{end}}
+
+<h2>Tests</h2>
+<table id="tests">
+<thead>
+<tr>
+<th class="sortable">Name</th>
+<th class="sortable">Status</th>
+<th class="sortable">Start</th>
+<th class="sortable">Duration</th>
+<th>Timing</th>
+</tr>
+</thead>
+<tbody>
+{{range .Tests}}<tr>
+<td data-sort="{{.Name}}">{{if .OutDir}}<a href="{{.OutDir}}/">{{.Name}}</a>{{else}}{{.Name}}{{end}}</td>
+<td data-sort="{{.Status}}" class="{{.Status}}">{{.Status}}</td>
+<td data-sort="{{unixMilli .Start}}">{{formatTime .Start}}</td>
+<td data-sort="{{.Duration.Milliseconds}}">{{seconds .Duration}}</td>
+<td><div class="chart"><div class="{{.Status}}" style="left: {{printf "%.3f" .ChartOffset}}%; width: {{printf "%.3f" .ChartWidth}}%"></div></div></td>
+</tr>
+{{end}}</tbody>
+</table>
+
+{{range .Tests}}{{if eq .Status "FAIL"}}
+<h2 class="FAIL">{{.Name}}</h2>
+{{range .Errors}}<pre>{{.File}}:{{.Line}}: {{.Reason}}{{if .Category}} [{{.Category}}]{{end}}</pre>
+{{end}}{{if .Log}}<details>
+<summary>Log{{if .LogTruncated}} (truncated){{end}}</summary>
+<pre>{{.Log}}</pre>
+</details>
+{{end}}{{end}}{{end}}
+
+{{if .Skipped}}
+<h2>Skipped tests</h2>
+<table>
+<tr><th>Name</th><th>Reason</th></tr>
+{{range .Tests}}{{if eq .Status "SKIP"}}<tr><td>{{.Name}}</td><td>{{.SkipReason}}</td></tr>
+{{end}}{{end}}</table>
+{{end}}
+
+<script>
+(function() {
+  var table = document.getElementById('tests');
+  var headers = table.tHead.rows[0].cells;
+  var order = {};
+  Array.prototype.forEach.call(headers, function(th, col) {
+    if (!th.classList.contains('sortable')) return;
+    th.addEventListener('click', function() {
+      order[col] = !order[col];
+      var dir = order[col] ? 1 : -1;
+      var body = table.tBodies[0];
+      var rows = Array.prototype.slice.call(body.rows);
+      rows.sort(function(a, b) {
+        var x = a.cells[col].dataset.sort, y = b.cells[col].dataset.sort;
+        var nx = Number(x), ny = Number(y);
+        if (x !== '' && y !== '' && !isNaN(nx) && !isNaN(ny)) return (nx - ny) * dir;
+        return x.localeCompare(y) * dir;
+      });
+      rows.forEach(function(r) { body.appendChild(r); });
+    });
+  });
+})();
+</script>
+</body>
+</html>
+`))
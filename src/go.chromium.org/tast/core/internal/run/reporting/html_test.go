@@ -0,0 +1,88 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package reporting_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	gotesting "testing"
+	"time"
+
+	"go.chromium.org/tast/core/internal/run/reporting"
+	"go.chromium.org/tast/core/internal/run/resultsjson"
+	"go.chromium.org/tast/core/testutil"
+)
+
+func TestWriteHTMLReport(t *gotesting.T) {
+	td := testutil.TempDir(t)
+	defer os.RemoveAll(td)
+
+	if err := testutil.WriteFiles(td, map[string]string{
+		"tests/example.Fail/log.txt": "Log of <example.Fail>\n",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2024, 2, 3, 19, 0, 0, 0, time.UTC)
+	results := []*resultsjson.Result{
+		{
+			Test:   resultsjson.Test{Name: "example.Pass"},
+			Start:  start,
+			End:    start.Add(time.Second),
+			OutDir: filepath.Join(td, "tests/example.Pass"),
+		},
+		{
+			Test: resultsjson.Test{Name: "example.Fail"},
+			Errors: []resultsjson.Error{{
+				Reason:   "Failed <intentionally>",
+				File:     "fail.go",
+				Line:     10,
+				Category: "TEST_BUG",
+			}},
+			Start:  start.Add(time.Second),
+			End:    start.Add(3 * time.Second),
+			OutDir: filepath.Join(td, "tests/example.Fail"),
+		},
+		{
+			Test:        resultsjson.Test{Name: "example.Skip"},
+			Start:       start.Add(3 * time.Second),
+			End:         start.Add(3 * time.Second),
+			SkipReason:  "missing SoftwareDeps: android",
+			SkipReasons: []resultsjson.SkipReason{{Code: "UNSATISFIED_SWDEP"}},
+		},
+	}
+
+	path := filepath.Join(td, reporting.HTMLReportFilename)
+	if err := reporting.WriteHTMLReport(path, results); err != nil {
+		t.Fatal("WriteHTMLReport failed: ", err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(b)
+
+	for _, want := range []string{
+		"3 tests:",
+		"1 passed",
+		"1 failed",
+		"1 skipped",
+		`<a href="tests/example.Pass/">example.Pass</a>`,
+		"fail.go:10: Failed &lt;intentionally&gt; [TEST_BUG]",
+		"Log of &lt;example.Fail&gt;",
+		"<td>UNSATISFIED_SWDEP</td><td>1</td>",
+		"missing SoftwareDeps: android",
+		// example.Fail takes 2/3 of the run.
+		"left: 33.333%; width: 66.667%",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Report does not contain %q", want)
+		}
+	}
+	if strings.Contains(out, "Log of <example.Fail>") {
+		t.Error("Report contains an unescaped log")
+	}
+}
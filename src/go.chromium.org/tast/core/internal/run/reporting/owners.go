@@ -0,0 +1,140 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package reporting
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"go.chromium.org/tast/core/errors"
+	"go.chromium.org/tast/core/internal/logging"
+	"go.chromium.org/tast/core/internal/run/resultsjson"
+)
+
+// OwnersFilename is a file name to be used with WriteOwners.
+const OwnersFilename = "owners.json"
+
+// TestFailure describes a failing test and who owns it.
+type TestFailure struct {
+	// Test is the name of the test.
+	Test string `json:"test"`
+	// Contacts contains email addresses of owners of the test.
+	Contacts []string `json:"contacts"`
+	// BugComponent is the bug component to file bugs of the test to, e.g.
+	// "b:1234". It is empty if the test does not declare one.
+	BugComponent string `json:"bugComponent,omitempty"`
+	// Errors contains reasons of errors reported by the test.
+	Errors []string `json:"errors"`
+	// OutDir is the output directory of the test.
+	OutDir string `json:"outDir,omitempty"`
+}
+
+// owners is the content of owners.json.
+type owners struct {
+	// Failures contains failing tests sorted by name.
+	Failures []*TestFailure `json:"failures"`
+	// Contacts maps email addresses to sorted names of failing tests they own,
+	// e.g. to CC them on failure reports.
+	Contacts map[string][]string `json:"contacts"`
+	// Unowned contains names of failing tests having neither contacts nor a
+	// bug component.
+	Unowned []string `json:"unowned,omitempty"`
+}
+
+// TestFailures returns failing tests in results sorted by name.
+func TestFailures(results []*resultsjson.Result) []*TestFailure {
+	var fs []*TestFailure
+	for _, r := range results {
+		if len(r.Errors) == 0 {
+			continue
+		}
+		f := &TestFailure{
+			Test:         r.Name,
+			Contacts:     append([]string{}, r.Contacts...),
+			BugComponent: r.BugComponent,
+			OutDir:       r.OutDir,
+		}
+		for _, e := range r.Errors {
+			f.Errors = append(f.Errors, e.Reason)
+		}
+		fs = append(fs, f)
+	}
+	sort.SliceStable(fs, func(i, j int) bool { return fs[i].Test < fs[j].Test })
+	return fs
+}
+
+// WriteOwners writes owners of failing tests in results to path in JSON, so
+// that failures can be routed to owners without looking up tests manually.
+func WriteOwners(path string, results []*resultsjson.Result) error {
+	o := owners{
+		Failures: TestFailures(results),
+		Contacts: make(map[string][]string),
+	}
+	if o.Failures == nil {
+		o.Failures = []*TestFailure{}
+	}
+	for _, f := range o.Failures {
+		for _, c := range f.Contacts {
+			o.Contacts[c] = append(o.Contacts[c], f.Test)
+		}
+		if len(f.Contacts) == 0 && f.BugComponent == "" {
+			o.Unowned = append(o.Unowned, f.Test)
+		}
+	}
+
+	b, err := json.MarshalIndent(&o, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0644)
+}
+
+// ownersReporter writes owners of failing tests to owners.json.
+type ownersReporter struct{ BaseReporter }
+
+func (ownersReporter) RunFinished(ctx context.Context, run *RunInfo, summary *RunSummary) error {
+	for _, f := range TestFailures(summary.Results) {
+		if len(f.Contacts) == 0 && f.BugComponent == "" {
+			logging.Infof(ctx, "Failing test %s has no owners; add Contacts or BugComponent to it", f.Test)
+		}
+	}
+	return WriteOwners(filepath.Join(run.ResDir, OwnersFilename), summary.Results)
+}
+
+// BugFiler files bugs for failing tests, or annotates existing ones, e.g. in
+// an issue tracker.
+type BugFiler interface {
+	// FileBug files or annotates a bug for a failing test. Implementations
+	// should route the bug to f.BugComponent if it is set and CC f.Contacts.
+	FileBug(ctx context.Context, f *TestFailure) error
+}
+
+// NewBugFilingReporter returns a Reporter calling f for each failing test
+// when a run finishes. Packages integrating with an issue tracker can
+// register it with RegisterReporter so that it can be enabled with the
+// -reporters flag.
+func NewBugFilingReporter(f BugFiler) Reporter {
+	return &bugFilingReporter{filer: f}
+}
+
+// bugFilingReporter files bugs for failing tests with a BugFiler.
+type bugFilingReporter struct {
+	BaseReporter
+	filer BugFiler
+}
+
+func (r *bugFilingReporter) RunFinished(ctx context.Context, run *RunInfo, summary *RunSummary) error {
+	// Try filing bugs for all failures even if some of them fail.
+	var firstErr error
+	for _, f := range TestFailures(summary.Results) {
+		if err := r.filer.FileBug(ctx, f); err != nil && firstErr == nil {
+			firstErr = errors.Wrapf(err, "failed to file bug for %s", f.Test)
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,118 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package reporting_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go.chromium.org/tast/core/internal/run/reporting"
+	"go.chromium.org/tast/core/internal/run/resultsjson"
+)
+
+var ownersTestResults = []*resultsjson.Result{
+	{
+		Test:   resultsjson.Test{Name: "pkg.Pass", Contacts: []string{"a@example.com"}},
+		OutDir: "/tmp/pkg.Pass",
+	},
+	{
+		Test: resultsjson.Test{
+			Name:         "pkg.FailB",
+			Contacts:     []string{"a@example.com", "b@example.com"},
+			BugComponent: "b:1234",
+		},
+		Errors: []resultsjson.Error{{Reason: "Failed 1"}, {Reason: "Failed 2"}},
+		OutDir: "/tmp/pkg.FailB",
+	},
+	{
+		Test:   resultsjson.Test{Name: "pkg.FailA", Contacts: []string{"b@example.com"}},
+		Errors: []resultsjson.Error{{Reason: "Failed"}},
+		OutDir: "/tmp/pkg.FailA",
+	},
+	{
+		Test:   resultsjson.Test{Name: "pkg.Unowned"},
+		Errors: []resultsjson.Error{{Reason: "Failed"}},
+	},
+}
+
+func TestWriteOwners(t *testing.T) {
+	path := filepath.Join(t.TempDir(), reporting.OwnersFilename)
+	if err := reporting.WriteOwners(path, ownersTestResults); err != nil {
+		t.Fatal("WriteOwners failed: ", err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Failures []*reporting.TestFailure `json:"failures"`
+		Contacts map[string][]string      `json:"contacts"`
+		Unowned  []string                 `json:"unowned"`
+	}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("Failed to unmarshal %s: %v", b, err)
+	}
+
+	wantFailures := []*reporting.TestFailure{
+		{Test: "pkg.FailA", Contacts: []string{"b@example.com"}, Errors: []string{"Failed"}, OutDir: "/tmp/pkg.FailA"},
+		{Test: "pkg.FailB", Contacts: []string{"a@example.com", "b@example.com"}, BugComponent: "b:1234", Errors: []string{"Failed 1", "Failed 2"}, OutDir: "/tmp/pkg.FailB"},
+		{Test: "pkg.Unowned", Contacts: []string{}, Errors: []string{"Failed"}},
+	}
+	if diff := cmp.Diff(got.Failures, wantFailures); diff != "" {
+		t.Errorf("Failures mismatch (-got +want):\n%s", diff)
+	}
+	wantContacts := map[string][]string{
+		"a@example.com": {"pkg.FailB"},
+		"b@example.com": {"pkg.FailA", "pkg.FailB"},
+	}
+	if diff := cmp.Diff(got.Contacts, wantContacts); diff != "" {
+		t.Errorf("Contacts mismatch (-got +want):\n%s", diff)
+	}
+	if diff := cmp.Diff(got.Unowned, []string{"pkg.Unowned"}); diff != "" {
+		t.Errorf("Unowned mismatch (-got +want):\n%s", diff)
+	}
+}
+
+// fakeBugFiler records failures it is asked to file bugs for.
+type fakeBugFiler struct {
+	tests []string
+	err   error
+}
+
+func (f *fakeBugFiler) FileBug(ctx context.Context, tf *reporting.TestFailure) error {
+	f.tests = append(f.tests, tf.Test)
+	return f.err
+}
+
+func TestBugFilingReporter(t *testing.T) {
+	ctx := context.Background()
+	run := &reporting.RunInfo{ResDir: t.TempDir()}
+	summary := &reporting.RunSummary{Results: ownersTestResults, Complete: true}
+
+	filer := &fakeBugFiler{}
+	if err := reporting.NewBugFilingReporter(filer).RunFinished(ctx, run, summary); err != nil {
+		t.Error("RunFinished failed: ", err)
+	}
+	want := []string{"pkg.FailA", "pkg.FailB", "pkg.Unowned"}
+	if diff := cmp.Diff(filer.tests, want); diff != "" {
+		t.Errorf("Bugs filed for unexpected tests (-got +want):\n%s", diff)
+	}
+
+	// All failures should be reported even if filing bugs fails.
+	filer = &fakeBugFiler{err: errors.New("failure")}
+	if err := reporting.NewBugFilingReporter(filer).RunFinished(ctx, run, summary); err == nil {
+		t.Error("RunFinished unexpectedly succeeded")
+	}
+	if diff := cmp.Diff(filer.tests, want); diff != "" {
+		t.Errorf("Bugs filed for unexpected tests (-got +want):\n%s", diff)
+	}
+}
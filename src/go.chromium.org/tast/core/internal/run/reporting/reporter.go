@@ -0,0 +1,197 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package reporting
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"go.chromium.org/tast/core/errors"
+	"go.chromium.org/tast/core/internal/run/resultsjson"
+)
+
+// Reporter receives test results as a run progresses, e.g. to write result
+// files or to send results to external services.
+//
+// Reporters are created by factories registered with RegisterReporter and
+// enabled by name with the -reporters flag of "tast run". Errors returned by
+// a reporter are logged but do not fail the run.
+type Reporter interface {
+	// RunStarted is called before any test starts.
+	RunStarted(ctx context.Context, run *RunInfo) error
+	// TestFinished is called when a test finishes.
+	TestFinished(ctx context.Context, result *resultsjson.Result) error
+	// RunFinished is called after all tests finish, or the run is aborted.
+	RunFinished(ctx context.Context, run *RunInfo, summary *RunSummary) error
+}
+
+// RunInfo describes a test run.
+type RunInfo struct {
+	// ResDir is the directory where test result files are saved.
+	ResDir string
+	// Tests contains names of tests to run.
+	Tests []string
+	// Metadata contains run-level metadata given by the -metadata flag, e.g.
+	// the name of the CI builder and the CL under test.
+	Metadata map[string]string
+}
+
+// RunSummary describes the outcome of a test run.
+type RunSummary struct {
+	// Results contains results of all tests, including ones from previous
+	// runs when resuming a run.
+	Results []*resultsjson.Result
+	// Complete is true if all tests could be run.
+	Complete bool
+	// TimedOut is true if the run was cut short by the tast command timeout.
+	TimedOut bool
+}
+
+// BaseReporter implements Reporter with methods doing nothing. Reporters can
+// embed it to implement only methods they are interested in.
+type BaseReporter struct{}
+
+var _ Reporter = BaseReporter{}
+
+// RunStarted does nothing.
+func (BaseReporter) RunStarted(ctx context.Context, run *RunInfo) error { return nil }
+
+// TestFinished does nothing.
+func (BaseReporter) TestFinished(ctx context.Context, result *resultsjson.Result) error {
+	return nil
+}
+
+// RunFinished does nothing.
+func (BaseReporter) RunFinished(ctx context.Context, run *RunInfo, summary *RunSummary) error {
+	return nil
+}
+
+// ReporterFactory creates a Reporter for a run.
+type ReporterFactory func() Reporter
+
+var (
+	reportersMu       sync.Mutex
+	reporterFactories = map[string]ReporterFactory{
+		"html":   func() Reporter { return htmlReporter{} },
+		"json":   func() Reporter { return jsonReporter{} },
+		"junit":  func() Reporter { return junitReporter{} },
+		"owners": func() Reporter { return ownersReporter{} },
+		"text":   func() Reporter { return textReporter{} },
+	}
+)
+
+// DefaultReporters lists names of reporters enabled by default.
+var DefaultReporters = []string{"json", "junit", "owners", "text"}
+
+// RegisterReporter registers a reporter factory under name so that it can be
+// enabled with the -reporters flag. It is typically called from an init
+// function of a package linked into the tast command. It panics if name is
+// already registered.
+func RegisterReporter(name string, f ReporterFactory) {
+	reportersMu.Lock()
+	defer reportersMu.Unlock()
+	if _, ok := reporterFactories[name]; ok {
+		panic(fmt.Sprintf("reporter %q is already registered", name))
+	}
+	reporterFactories[name] = f
+}
+
+// ReporterNames returns sorted names of registered reporters.
+func ReporterNames() []string {
+	reportersMu.Lock()
+	defer reportersMu.Unlock()
+	var names []string
+	for name := range reporterFactories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewReporters creates reporters registered under names and returns a
+// Reporter dispatching calls to all of them in order.
+func NewReporters(names []string) (Reporter, error) {
+	reportersMu.Lock()
+	defer reportersMu.Unlock()
+	var rs multiReporter
+	for _, name := range names {
+		f, ok := reporterFactories[name]
+		if !ok {
+			return nil, errors.Errorf("unknown reporter %q", name)
+		}
+		rs = append(rs, namedReporter{name, f()})
+	}
+	return rs, nil
+}
+
+// AppendReporter returns a Reporter dispatching calls to rs, a Reporter
+// returned by NewReporters, and then to r. name identifies r in errors. It is
+// used to enable reporters needing parameters that factories can not take.
+func AppendReporter(rs Reporter, name string, r Reporter) Reporter {
+	mr, ok := rs.(multiReporter)
+	if !ok {
+		mr = multiReporter{{"", rs}}
+	}
+	return append(append(multiReporter(nil), mr...), namedReporter{name, r})
+}
+
+type namedReporter struct {
+	name string
+	Reporter
+}
+
+// multiReporter dispatches calls to multiple reporters. All reporters are
+// called even if some of them fail, and the first error is returned.
+type multiReporter []namedReporter
+
+func (rs multiReporter) each(call func(r Reporter) error) error {
+	var firstErr error
+	for _, r := range rs {
+		if err := call(r.Reporter); err != nil && firstErr == nil {
+			firstErr = err
+			if r.name != "" {
+				firstErr = errors.Wrapf(err, "reporter %s", r.name)
+			}
+		}
+	}
+	return firstErr
+}
+
+func (rs multiReporter) RunStarted(ctx context.Context, run *RunInfo) error {
+	return rs.each(func(r Reporter) error { return r.RunStarted(ctx, run) })
+}
+
+func (rs multiReporter) TestFinished(ctx context.Context, result *resultsjson.Result) error {
+	return rs.each(func(r Reporter) error { return r.TestFinished(ctx, result) })
+}
+
+func (rs multiReporter) RunFinished(ctx context.Context, run *RunInfo, summary *RunSummary) error {
+	return rs.each(func(r Reporter) error { return r.RunFinished(ctx, run, summary) })
+}
+
+// jsonReporter writes results to results.json.
+type jsonReporter struct{ BaseReporter }
+
+func (jsonReporter) RunFinished(ctx context.Context, run *RunInfo, summary *RunSummary) error {
+	return WriteLegacyResults(filepath.Join(run.ResDir, LegacyResultsFilename), summary.Results)
+}
+
+// junitReporter writes results to results.xml in the JUnit XML format.
+type junitReporter struct{ BaseReporter }
+
+func (junitReporter) RunFinished(ctx context.Context, run *RunInfo, summary *RunSummary) error {
+	return WriteJUnitXMLResults(filepath.Join(run.ResDir, JUnitXMLFilename), summary.Results)
+}
+
+// textReporter writes a summary of results to the console.
+type textReporter struct{ BaseReporter }
+
+func (textReporter) RunFinished(ctx context.Context, run *RunInfo, summary *RunSummary) error {
+	WriteResultsToLogs(ctx, summary.Results, run.ResDir, summary.Complete, summary.TimedOut)
+	return nil
+}
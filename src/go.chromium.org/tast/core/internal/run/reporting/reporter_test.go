@@ -0,0 +1,123 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package reporting_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go.chromium.org/tast/core/internal/run/reporting"
+	"go.chromium.org/tast/core/internal/run/resultsjson"
+)
+
+// recordingReporter records calls to it.
+type recordingReporter struct {
+	calls *[]string
+	err   error
+}
+
+func (r recordingReporter) RunStarted(ctx context.Context, run *reporting.RunInfo) error {
+	*r.calls = append(*r.calls, "RunStarted")
+	return r.err
+}
+
+func (r recordingReporter) TestFinished(ctx context.Context, result *resultsjson.Result) error {
+	*r.calls = append(*r.calls, "TestFinished:"+result.Name)
+	return r.err
+}
+
+func (r recordingReporter) RunFinished(ctx context.Context, run *reporting.RunInfo, summary *reporting.RunSummary) error {
+	*r.calls = append(*r.calls, "RunFinished")
+	return r.err
+}
+
+func TestReporters(t *testing.T) {
+	var calls []string
+	reporting.RegisterReporter("test_failing", func() reporting.Reporter {
+		return recordingReporter{calls: &calls, err: errors.New("failure")}
+	})
+	reporting.RegisterReporter("test_recording", func() reporting.Reporter {
+		return recordingReporter{calls: &calls}
+	})
+
+	names := reporting.ReporterNames()
+	for _, want := range []string{"json", "junit", "test_failing", "test_recording", "text"} {
+		found := false
+		for _, name := range names {
+			found = found || name == want
+		}
+		if !found {
+			t.Errorf("ReporterNames() = %q; missing %q", names, want)
+		}
+	}
+
+	if _, err := reporting.NewReporters([]string{"json", "unknown"}); err == nil {
+		t.Error("NewReporters unexpectedly succeeded for an unknown reporter")
+	}
+
+	r, err := reporting.NewReporters([]string{"test_failing", "json", "test_recording"})
+	if err != nil {
+		t.Fatal("NewReporters failed: ", err)
+	}
+
+	ctx := context.Background()
+	run := &reporting.RunInfo{ResDir: t.TempDir(), Tests: []string{"pkg.Test"}}
+	result := &resultsjson.Result{Test: resultsjson.Test{Name: "pkg.Test"}}
+	if err := r.RunStarted(ctx, run); err == nil {
+		t.Error("RunStarted unexpectedly succeeded")
+	}
+	if err := r.TestFinished(ctx, result); err == nil {
+		t.Error("TestFinished unexpectedly succeeded")
+	}
+	if err := r.RunFinished(ctx, run, &reporting.RunSummary{Results: []*resultsjson.Result{result}, Complete: true}); err == nil {
+		t.Error("RunFinished unexpectedly succeeded")
+	}
+
+	// All reporters are called even if one of them fails.
+	want := []string{
+		"RunStarted", "RunStarted",
+		"TestFinished:pkg.Test", "TestFinished:pkg.Test",
+		"RunFinished", "RunFinished",
+	}
+	if diff := cmp.Diff(calls, want); diff != "" {
+		t.Errorf("Calls mismatch (-got +want):\n%s", diff)
+	}
+	if _, err := os.Stat(filepath.Join(run.ResDir, reporting.LegacyResultsFilename)); err != nil {
+		t.Errorf("json reporter did not write %s: %v", reporting.LegacyResultsFilename, err)
+	}
+}
+
+func TestRegisterReporterDuplicate(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("RegisterReporter did not panic for a duplicate name")
+		}
+	}()
+	reporting.RegisterReporter("json", func() reporting.Reporter { return reporting.BaseReporter{} })
+}
+
+func TestAppendReporter(t *testing.T) {
+	var calls []string
+	rs, err := reporting.NewReporters(nil)
+	if err != nil {
+		t.Fatal("NewReporters failed: ", err)
+	}
+	r := reporting.AppendReporter(rs, "extra", recordingReporter{calls: &calls, err: errors.New("failure")})
+
+	err = r.TestFinished(context.Background(), &resultsjson.Result{Test: resultsjson.Test{Name: "pkg.Test"}})
+	if err == nil {
+		t.Error("TestFinished unexpectedly succeeded")
+	} else if want := "reporter extra: failure"; err.Error() != want {
+		t.Errorf("TestFinished returned %q; want %q", err.Error(), want)
+	}
+	if diff := cmp.Diff(calls, []string{"TestFinished:pkg.Test"}); diff != "" {
+		t.Errorf("Calls mismatch (-got +want):\n%s", diff)
+	}
+}
@@ -129,10 +129,12 @@ func (c *RPCClient) ReportResult(ctx context.Context, r *resultsjson.Result) err
 	startTime := timestamppb.New(r.Start)
 	duration := durationpb.New(r.End.Sub(r.Start))
 	req := &frameworkprotocol.ReportResultRequest{
-		Test:       r.Name,
-		SkipReason: r.SkipReason,
-		StartTime:  startTime,
-		Duration:   duration,
+		Test:         r.Name,
+		SkipReason:   r.SkipReason,
+		StartTime:    startTime,
+		Duration:     duration,
+		Requirements: r.Requirements,
+		RunMetadata:  r.RunMetadata,
 	}
 	for _, e := range r.Errors {
 		ts := timestamppb.New(e.Time)
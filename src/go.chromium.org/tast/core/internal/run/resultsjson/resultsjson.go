@@ -17,23 +17,25 @@ import (
 // Test represents a test.
 type Test struct {
 	// See testing.TestInstance for details of the fields.
-	Name         string                 `json:"name"`
-	Pkg          string                 `json:"pkg"`
-	Desc         string                 `json:"desc"`
-	Contacts     []string               `json:"contacts"`
-	Attr         []string               `json:"attr"`
-	SearchFlags  []*protocol.StringPair `json:"searchFlags,omitempty"`
-	Data         []string               `json:"data"`
-	Vars         []string               `json:"vars,omitempty"`
-	VarDeps      []string               `json:"varDeps,omitempty"`
-	SoftwareDeps dep.SoftwareDeps       `json:"softwareDeps,omitempty"`
-	ServiceDeps  []string               `json:"serviceDeps,omitempty"`
-	Fixture      string                 `json:"fixture,omitempty"`
-	Timeout      time.Duration          `json:"timeout"`
-	Bundle       string                 `json:"bundle,omitempty"`
-	TestBedDeps  []string               `json:"testBedDeps,omitempty"`
-	Requirements []string               `json:"requirements,omitempty"`
-	BugComponent string                 `json:"bugComponent,omitempty"`
+	Name             string                 `json:"name"`
+	Pkg              string                 `json:"pkg"`
+	Desc             string                 `json:"desc"`
+	Contacts         []string               `json:"contacts"`
+	Attr             []string               `json:"attr"`
+	SearchFlags      []*protocol.StringPair `json:"searchFlags,omitempty"`
+	Data             []string               `json:"data"`
+	Vars             []string               `json:"vars,omitempty"`
+	VarDeps          []string               `json:"varDeps,omitempty"`
+	SoftwareDeps     dep.SoftwareDeps       `json:"softwareDeps,omitempty"`
+	SoftSoftwareDeps []string               `json:"softSoftwareDeps,omitempty"`
+	ServiceDeps      []string               `json:"serviceDeps,omitempty"`
+	Fixture          string                 `json:"fixture,omitempty"`
+	Timeout          time.Duration          `json:"timeout"`
+	ExpectedDuration time.Duration          `json:"expectedDuration,omitempty"`
+	Bundle           string                 `json:"bundle,omitempty"`
+	TestBedDeps      []string               `json:"testBedDeps,omitempty"`
+	Requirements     []string               `json:"requirements,omitempty"`
+	BugComponent     string                 `json:"bugComponent,omitempty"`
 }
 
 // Error describes an error encountered while running a test.
@@ -43,6 +45,19 @@ type Error struct {
 	File   string    `json:"file"`
 	Line   int       `json:"line"`
 	Stack  string    `json:"stack"`
+	// Category classifies the cause of the error, e.g. "INFRA". It is empty
+	// if the error is not categorized. See errors.Category for possible
+	// values.
+	Category string `json:"category,omitempty"`
+}
+
+// SkipReason describes a machine-readable reason why a test was skipped.
+type SkipReason struct {
+	// Code is the category of the reason, e.g. "UNSATISFIED_SWDEP".
+	// See protocol.SkipReason_Code for possible values.
+	Code string `json:"code"`
+	// Details contains human-readable explanations of the reason.
+	Details []string `json:"details,omitempty"`
 }
 
 // Result represents the result of a single test.
@@ -64,6 +79,94 @@ type Result struct {
 	// SkipReason contains a human-readable explanation of why the test was skipped.
 	// It is empty if the test actually ran.
 	SkipReason string `json:"skipReason"`
+	// SkipReasons contains machine-readable reasons why the test was skipped.
+	// It is empty if the test actually ran.
+	SkipReasons []SkipReason `json:"skipReasons,omitempty"`
+	// Quarantined is true if the test was not run because it repeatedly crashed
+	// the test bundle or the DUT earlier in the same run.
+	Quarantined bool `json:"quarantined,omitempty"`
+	// Warnings contains human-readable warnings about the test run that did
+	// not make the test fail, e.g. the test took much longer than expected.
+	Warnings []string `json:"warnings,omitempty"`
+	// AvailableSoftSoftwareDeps contains software features in SoftSoftwareDeps
+	// that were available on the DUT when the test ran.
+	AvailableSoftSoftwareDeps []string `json:"availableSoftSoftwareDeps,omitempty"`
+	// Steps contains results of steps of a scenario test in the order they
+	// are declared. It is empty for other tests.
+	Steps []StepResult `json:"steps,omitempty"`
+	// Seed is the run-level seed the random number generator of the test
+	// returned by State.Rand was derived from. Passing it to the -seed flag
+	// of tast run reproduces the same random numbers.
+	Seed int64 `json:"seed,omitempty"`
+	// ConsumedVars contains sorted names of runtime variables the test read
+	// with State.Var or State.RequiredVar and was given values for.
+	ConsumedVars []string `json:"consumedVars,omitempty"`
+	// RunMetadata contains run-level metadata given by the -metadata flag of
+	// tast run, e.g. the name of the CI builder and the CL under test.
+	RunMetadata map[string]string `json:"runMetadata,omitempty"`
+}
+
+// StepResult represents the result of a step of a scenario test.
+type StepResult struct {
+	// Name is the name of the step.
+	Name string `json:"name"`
+	// Errors contains errors reported in the step. They are also included in
+	// the errors of the test. If it is empty and Skipped is false, the step
+	// passed.
+	Errors []Error `json:"errors"`
+	// Start is the time at which the step started.
+	Start time.Time `json:"start"`
+	// End is the time at which the step completed. It holds the zero value if
+	// the step did not complete.
+	End time.Time `json:"end"`
+	// Skipped is true if the step did not run, e.g. since an earlier step
+	// failed.
+	Skipped bool `json:"skipped,omitempty"`
+}
+
+// FixtureError describes an error reported by a fixture.
+type FixtureError struct {
+	Error
+	// Phase is the phase of the fixture in which the error was reported,
+	// either "SetUp" or "TearDown". It is empty if the phase is unknown, e.g.
+	// when the test bundle crashed.
+	Phase string `json:"phase,omitempty"`
+}
+
+// FixtureResult represents the result of a fixture run that reported errors.
+type FixtureResult struct {
+	// Name is the name of the fixture.
+	Name string `json:"name"`
+	// Errors contains errors reported by the fixture.
+	Errors []FixtureError `json:"errors"`
+	// Start is the time at which the fixture started (as reported by the test bundle).
+	Start time.Time `json:"start"`
+	// End is the time at which the fixture completed (as reported by the test bundle).
+	End time.Time `json:"end"`
+	// OutDir is the directory into which fixture output, including its logs,
+	// is stored.
+	OutDir string `json:"outDir"`
+}
+
+// NewErrorCategory returns the category of e to be saved in Error. It returns
+// an empty string if e is not categorized.
+func NewErrorCategory(e *protocol.Error) string {
+	if e.GetCategory() == protocol.Error_CATEGORY_UNSPECIFIED {
+		return ""
+	}
+	return e.GetCategory().String()
+}
+
+// NewSkipReasons creates a list of SkipReason from protocol.Skip.
+func NewSkipReasons(skip *protocol.Skip) []SkipReason {
+	var reasons []SkipReason
+	for _, r := range skip.GetTypedReasons() {
+		reasons = append(reasons, SkipReason{
+			Code:    r.GetCode().String(),
+			Details: r.GetDetails(),
+		})
+	}
+	return reasons
 }
 
 // NewTest creates Test from protocol.Entity.
@@ -80,23 +183,32 @@ func NewTest(e *protocol.Entity) (*Test, error) {
 		}
 		timeout = topb.AsDuration()
 	}
+	var expectedDuration time.Duration
+	if edpb := e.GetLegacyData().GetExpectedDuration(); edpb != nil {
+		if err := edpb.CheckValid(); err != nil {
+			return nil, err
+		}
+		expectedDuration = edpb.AsDuration()
+	}
 	return &Test{
-		Name:         e.GetName(),
-		Pkg:          e.GetPackage(),
-		Desc:         e.GetDescription(),
-		Contacts:     e.GetContacts().GetEmails(),
-		Attr:         e.GetAttributes(),
-		SearchFlags:  e.GetSearchFlags(),
-		Data:         e.GetDependencies().GetDataFiles(),
-		Vars:         e.GetLegacyData().GetVariables(),
-		VarDeps:      e.GetLegacyData().GetVariableDeps(),
-		SoftwareDeps: e.GetLegacyData().GetSoftwareDeps(),
-		ServiceDeps:  e.GetDependencies().GetServices(),
-		Fixture:      e.GetFixture(),
-		Timeout:      timeout,
-		Bundle:       e.GetLegacyData().GetBundle(),
-		TestBedDeps:  e.GetTestBedDeps(),
-		Requirements: e.GetRequirements(),
-		BugComponent: e.GetBugComponent(),
+		Name:             e.GetName(),
+		Pkg:              e.GetPackage(),
+		Desc:             e.GetDescription(),
+		Contacts:         e.GetContacts().GetEmails(),
+		Attr:             e.GetAttributes(),
+		SearchFlags:      e.GetSearchFlags(),
+		Data:             e.GetDependencies().GetDataFiles(),
+		Vars:             e.GetLegacyData().GetVariables(),
+		VarDeps:          e.GetLegacyData().GetVariableDeps(),
+		SoftwareDeps:     e.GetLegacyData().GetSoftwareDeps(),
+		SoftSoftwareDeps: e.GetLegacyData().GetSoftSoftwareDeps(),
+		ServiceDeps:      e.GetDependencies().GetServices(),
+		Fixture:          e.GetFixture(),
+		Timeout:          timeout,
+		ExpectedDuration: expectedDuration,
+		Bundle:           e.GetLegacyData().GetBundle(),
+		TestBedDeps:      e.GetTestBedDeps(),
+		Requirements:     e.GetRequirements(),
+		BugComponent:     e.GetBugComponent(),
 	}, nil
 }
@@ -11,8 +11,10 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"time"
 
 	"go.chromium.org/tast/core/internal/command"
+	"go.chromium.org/tast/core/internal/logs"
 	"go.chromium.org/tast/core/internal/protocol"
 
 	frameworkprotocol "go.chromium.org/tast/core/framework/protocol"
@@ -68,6 +70,30 @@ type StaticConfig struct {
 	// ignored.
 	DeprecatedDirectRunDefaults DeprecatedDirectRunConfig
 
+	// CrashDirs contains directories to which crash dumps are written.
+	// They are checked to be readable in self-check mode.
+	CrashDirs []string
+
+	// PowerSupplyDir is the sysfs directory containing power supply
+	// information, used to check the battery level before running
+	// power-sensitive tests. If it is empty, /sys/class/power_supply is used.
+	PowerSupplyDir string
+
+	// CPUDir is the sysfs directory containing CPU devices, used to pin CPU
+	// frequency governors to quiesce the DUT. If it is empty,
+	// /sys/devices/system/cpu is used.
+	CPUDir string
+
+	// ChromeDevConfPath is the path of the file containing extra command
+	// line flags of Chrome, used to disable ARC provisioning to quiesce the
+	// DUT. If it is empty, /etc/chrome_dev.conf is used.
+	ChromeDevConfPath string
+
+	// SystemLogCollector is used to save system log entries recorded while
+	// each test runs to the test's output directory. If it is nil, system
+	// logs are not saved per test.
+	SystemLogCollector logs.SystemLogCollector
+
 	// BundleTypes describes the type of runner being
 	// executed by local_test_runner or remote_test_runner.
 	BundleType BundleType
@@ -96,12 +122,33 @@ const (
 	// runner to allow users to run local tests directly on the DUT without
 	// Tast CLI.
 	modeDeprecatedDirectRun
+
+	// modeDaemon is the execution mode of the test runner to start a
+	// long-lived gRPC server on a Unix domain socket, so that consecutive
+	// runs can reuse it without spawning a new runner process.
+	modeDaemon
+
+	// modeSelfCheck is the execution mode of the test runner to exercise its
+	// own subsystems and write a health report to stdout, so that lab
+	// automation can verify that Tast is ready to run on the DUT.
+	modeSelfCheck
 )
 
+// DefaultDaemonSocketPath is the default path of the Unix domain socket the
+// local test runner listens on in daemon mode.
+const DefaultDaemonSocketPath = "/usr/local/tmp/tast/local_test_runner.sock"
+
+// defaultDaemonIdleTimeout is the default duration after which an idle test
+// runner daemon exits.
+const defaultDaemonIdleTimeout = time.Hour
+
 // parsedArgs holds the results of command line parsing.
 type parsedArgs struct {
 	Mode mode
 
+	// DaemonConfig contains configuration values used in daemon mode.
+	DaemonConfig DaemonConfig
+
 	// DeprecatedDirectRunConfig contains configuration values used when
 	// the user executes a test runner directly to run tests.
 	//
@@ -110,6 +157,16 @@ type parsedArgs struct {
 	DeprecatedDirectRunConfig DeprecatedDirectRunConfig
 }
 
+// DaemonConfig contains configuration values used when the test runner is
+// executed in daemon mode.
+type DaemonConfig struct {
+	// SocketPath is the path of the Unix domain socket to listen on.
+	SocketPath string
+	// IdleTimeout is the duration after which the daemon exits if no
+	// client has connected to it.
+	IdleTimeout time.Duration
+}
+
 // DeprecatedDirectRunConfig contains configuration values used when the user
 // executes a test runner directly to run tests.
 //
@@ -150,6 +207,9 @@ type DeprecatedDirectRunConfig struct {
 	AvailableSoftwareFeatures []string
 	// UnavailableSoftwareFeatures contains a list of software features supported by the DUT.
 	UnavailableSoftwareFeatures []string
+	// Parallel is the maximum number of tests declaring disjoint resources
+	// to run concurrently.
+	Parallel int
 }
 
 // RunConfig generates protocol.RunConfig.
@@ -177,6 +237,7 @@ func (c *DeprecatedDirectRunConfig) RunConfig(tests []string) *protocol.RunConfi
 		},
 		DataFileConfig: &protocol.DataFileConfig{},
 		WaitUntilReady: c.WaitUntilReady,
+		Parallel:       int32(c.Parallel),
 	}
 }
 
@@ -208,6 +269,12 @@ errors, including the failure of an individual test.
 		flags.PrintDefaults()
 	}
 	rpc := flags.Bool("rpc", false, "run gRPC server")
+	daemon := flags.Bool("daemon", false, "run gRPC server in background, listening on a Unix domain socket")
+	selfCheck := flags.Bool("selfcheck", false, "check that the runner is ready to run tests and print a JSON health report")
+	flags.StringVar(&args.DaemonConfig.SocketPath, "daemonsocket", DefaultDaemonSocketPath,
+		"path of the Unix domain socket to listen on in daemon mode")
+	flags.DurationVar(&args.DaemonConfig.IdleTimeout, "daemonidletimeout", defaultDaemonIdleTimeout,
+		"duration after which an idle daemon exits")
 	flags.StringVar(&args.DeprecatedDirectRunConfig.BundleGlob, "bundles",
 		args.DeprecatedDirectRunConfig.BundleGlob, "glob matching test bundles")
 	flags.StringVar(&args.DeprecatedDirectRunConfig.DataDir, "datadir",
@@ -220,6 +287,8 @@ errors, including the failure of an individual test.
 		"extrauseflags", "comma-separated list of additional USE flags to inject when checking test dependencies")
 	flags.BoolVar(&args.DeprecatedDirectRunConfig.WaitUntilReady, "waituntilready",
 		true, "wait until DUT is ready before running tests")
+	flags.IntVar(&args.DeprecatedDirectRunConfig.Parallel, "parallel",
+		1, "maximum number of tests declaring disjoint resources to run concurrently")
 
 	if scfg.Type == RemoteRunner {
 		flags.StringVar(&args.DeprecatedDirectRunConfig.ConnectionSpec, "target",
@@ -238,6 +307,14 @@ errors, including the failure of an individual test.
 		args.Mode = modeRPC
 		return args, nil
 	}
+	if *daemon {
+		args.Mode = modeDaemon
+		return args, nil
+	}
+	if *selfCheck {
+		args.Mode = modeSelfCheck
+		return args, nil
+	}
 
 	args.DeprecatedDirectRunConfig.Patterns = flags.Args()
 
@@ -0,0 +1,317 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package runner
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"golang.org/x/sys/unix"
+	"google.golang.org/protobuf/proto"
+
+	"go.chromium.org/tast/core/errors"
+	"go.chromium.org/tast/core/internal/logging"
+	"go.chromium.org/tast/core/internal/protocol"
+)
+
+// daemonChildEnv is the name of the environment variable set for the detached
+// process that actually serves requests in daemon mode.
+const daemonChildEnv = "TAST_RUNNER_DAEMON_CHILD"
+
+// daemonStartTimeout is the maximum duration to wait for a newly started
+// daemon to begin accepting connections.
+const daemonStartTimeout = 10 * time.Second
+
+// runDaemon runs the test runner in daemon mode.
+//
+// When called from a user-invoked process, it starts a detached daemon process
+// (unless one is already listening on dcfg.SocketPath) and returns after the
+// daemon becomes ready to accept connections. The detached process serves
+// gRPC connections on the socket until it becomes idle for
+// dcfg.IdleTimeout, it is signaled, or its executable is replaced.
+func runDaemon(ctx context.Context, scfg *StaticConfig, dcfg *DaemonConfig) error {
+	if os.Getenv(daemonChildEnv) == "" {
+		return startDaemon(ctx, dcfg)
+	}
+	ctx, stop := signal.NotifyContext(ctx, unix.SIGINT, unix.SIGTERM)
+	defer stop()
+	return serveDaemon(ctx, scfg, dcfg)
+}
+
+// daemonAlive returns whether a daemon is accepting connections at path.
+func daemonAlive(path string) bool {
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// startDaemon spawns a detached daemon process serving on dcfg.SocketPath and
+// waits for it to become ready.
+func startDaemon(ctx context.Context, dcfg *DaemonConfig) error {
+	if daemonAlive(dcfg.SocketPath) {
+		logging.Infof(ctx, "Test runner daemon is already listening on %s", dcfg.SocketPath)
+		return nil
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return errors.Wrap(err, "failed to look up current executable")
+	}
+	cmd := exec.Command(exe, "-daemon",
+		"-daemonsocket="+dcfg.SocketPath,
+		fmt.Sprintf("-daemonidletimeout=%v", dcfg.IdleTimeout))
+	cmd.Env = append(os.Environ(), daemonChildEnv+"=1")
+	// Create a new session so that the daemon survives the SSH session
+	// that started it.
+	cmd.SysProcAttr = &unix.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		return errors.Wrap(err, "failed to start test runner daemon")
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	deadline := time.Now().Add(daemonStartTimeout)
+	for !daemonAlive(dcfg.SocketPath) {
+		select {
+		case err := <-exited:
+			return errors.Wrap(err, "test runner daemon exited prematurely")
+		case <-ctx.Done():
+			cmd.Process.Kill()
+			return ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
+		if time.Now().After(deadline) {
+			cmd.Process.Kill()
+			return errors.Errorf("test runner daemon did not start listening on %s in %v", dcfg.SocketPath, daemonStartTimeout)
+		}
+	}
+	logging.Infof(ctx, "Started test runner daemon (pid %d) listening on %s", cmd.Process.Pid, dcfg.SocketPath)
+	return nil
+}
+
+// serveDaemon listens on dcfg.SocketPath and serves the runner gRPC protocol
+// on every accepted connection until ctx is canceled, the daemon is idle for
+// dcfg.IdleTimeout, or the current executable is replaced.
+func serveDaemon(ctx context.Context, scfg *StaticConfig, dcfg *DaemonConfig) error {
+	path := dcfg.SocketPath
+	if daemonAlive(path) {
+		return errors.Errorf("another test runner daemon is already listening on %s", path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	// Remove a socket file left behind by a daemon that did not exit cleanly.
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	lis, err := net.Listen("unix", path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to listen on %s", path)
+	}
+	defer lis.Close()
+	// Only the owner (typically root) may talk to the daemon.
+	if err := os.Chmod(path, 0600); err != nil {
+		return err
+	}
+
+	exe, exeInfo := currentExecutable()
+
+	// Serve requests with a static config that remembers DUT info for a
+	// short while, so that requests in a run do not re-query DUT features.
+	cached := *scfg
+	if scfg.GetDUTInfo != nil {
+		cached.GetDUTInfo = newDUTInfoCache(scfg.GetDUTInfo, dutInfoCacheTTL).GetDUTInfo
+	}
+
+	idle := newIdleTracker(dcfg.IdleTimeout, func() { lis.Close() })
+	defer idle.Stop()
+
+	go func() {
+		<-ctx.Done()
+		lis.Close()
+	}()
+
+	var conns sync.WaitGroup
+	defer conns.Wait()
+
+	for {
+		conn, err := lis.Accept()
+		if err != nil {
+			if ctx.Err() != nil || idle.Expired() {
+				return nil
+			}
+			return errors.Wrap(err, "failed to accept connection")
+		}
+		if exeInfo != nil && executableChanged(exe, exeInfo) {
+			// The runner binary was replaced (e.g. by tast run -build).
+			// Exit so that the client starts a fresh daemon.
+			logging.Infof(ctx, "%s was replaced; exiting test runner daemon", exe)
+			conn.Close()
+			return nil
+		}
+
+		idle.Begin()
+		conns.Add(1)
+		go func() {
+			defer conns.Done()
+			defer idle.End()
+			defer conn.Close()
+			if err := runRPCServer(&cached, conn, conn); err != nil {
+				logging.Infof(ctx, "Daemon connection finished with error: %v", err)
+			}
+		}()
+	}
+}
+
+// currentExecutable returns the path and file info of the current executable.
+// The returned file info is nil if it cannot be determined.
+func currentExecutable() (string, os.FileInfo) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", nil
+	}
+	fi, err := os.Stat(exe)
+	if err != nil {
+		return exe, nil
+	}
+	return exe, fi
+}
+
+// executableChanged returns whether the file at exe differs from orig.
+func executableChanged(exe string, orig os.FileInfo) bool {
+	fi, err := os.Stat(exe)
+	if err != nil {
+		return true
+	}
+	return !os.SameFile(fi, orig) || !fi.ModTime().Equal(orig.ModTime()) || fi.Size() != orig.Size()
+}
+
+// idleTracker calls a function once no connection has been active for a
+// certain duration.
+type idleTracker struct {
+	timeout time.Duration
+	onIdle  func()
+
+	mu      sync.Mutex
+	active  int
+	timer   *time.Timer
+	expired bool
+}
+
+func newIdleTracker(timeout time.Duration, onIdle func()) *idleTracker {
+	t := &idleTracker{timeout: timeout, onIdle: onIdle}
+	if timeout > 0 {
+		t.timer = time.AfterFunc(timeout, t.fire)
+	}
+	return t
+}
+
+func (t *idleTracker) fire() {
+	t.mu.Lock()
+	if t.active > 0 {
+		t.mu.Unlock()
+		return
+	}
+	t.expired = true
+	t.mu.Unlock()
+	t.onIdle()
+}
+
+// Begin records the start of a connection.
+func (t *idleTracker) Begin() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.active++
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+}
+
+// End records the end of a connection.
+func (t *idleTracker) End() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.active--
+	if t.active == 0 && t.timer != nil {
+		t.timer.Reset(t.timeout)
+	}
+}
+
+// Expired returns whether the idle timeout has been reached.
+func (t *idleTracker) Expired() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.expired
+}
+
+// Stop stops the underlying timer.
+func (t *idleTracker) Stop() {
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+}
+
+// dutInfoCacheTTL is the duration for which dutInfoCache keeps a response.
+// It is short because some DUT features, e.g. inserted SD cards, attached
+// docks and installed DLCs, change at runtime. It still avoids querying DUT
+// features repeatedly within a single run.
+const dutInfoCacheTTL = 30 * time.Second
+
+// dutInfoCacheEntry is a response cached by dutInfoCache.
+type dutInfoCacheEntry struct {
+	res     *protocol.GetDUTInfoResponse
+	expires time.Time
+}
+
+// dutInfoCache memoizes responses of a GetDUTInfo function per request for
+// a limited duration.
+type dutInfoCache struct {
+	get func(ctx context.Context, req *protocol.GetDUTInfoRequest) (*protocol.GetDUTInfoResponse, error)
+	ttl time.Duration
+	now func() time.Time // overridden in unit tests
+
+	mu    sync.Mutex
+	cache map[string]*dutInfoCacheEntry
+}
+
+func newDUTInfoCache(get func(ctx context.Context, req *protocol.GetDUTInfoRequest) (*protocol.GetDUTInfoResponse, error), ttl time.Duration) *dutInfoCache {
+	return &dutInfoCache{get: get, ttl: ttl, now: time.Now, cache: make(map[string]*dutInfoCacheEntry)}
+}
+
+// GetDUTInfo returns a cached response for req if available and not expired,
+// and otherwise calls the underlying function and caches a successful
+// response.
+func (c *dutInfoCache) GetDUTInfo(ctx context.Context, req *protocol.GetDUTInfoRequest) (*protocol.GetDUTInfoResponse, error) {
+	key, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.cache[string(key)]; ok && c.now().Before(e.expires) {
+		logging.Debug(ctx, "Returning cached DUT info")
+		return proto.Clone(e.res).(*protocol.GetDUTInfoResponse), nil
+	}
+	res, err := c.get(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	c.cache[string(key)] = &dutInfoCacheEntry{
+		res:     proto.Clone(res).(*protocol.GetDUTInfoResponse),
+		expires: c.now().Add(c.ttl),
+	}
+	return res, nil
+}
@@ -0,0 +1,125 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package runner
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	gotesting "testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	"go.chromium.org/tast/core/internal/protocol"
+	"go.chromium.org/tast/core/internal/rpc"
+	"go.chromium.org/tast/core/testutil"
+)
+
+func TestDaemonServesConsecutiveConnections(t *gotesting.T) {
+	sockPath := filepath.Join(testutil.TempDir(t), "runner.sock")
+
+	calls := 0
+	want := &protocol.GetDUTInfoResponse{
+		DutInfo: &protocol.DUTInfo{DefaultBuildArtifactsUrl: "gs://foo/bar/"},
+	}
+	scfg := &StaticConfig{
+		GetDUTInfo: func(ctx context.Context, req *protocol.GetDUTInfoRequest) (*protocol.GetDUTInfoResponse, error) {
+			calls++
+			return want, nil
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- serveDaemon(ctx, scfg, &DaemonConfig{SocketPath: sockPath, IdleTimeout: time.Minute})
+	}()
+	defer func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("serveDaemon failed: %v", err)
+		}
+	}()
+
+	for !daemonAlive(sockPath) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	for i := 0; i < 3; i++ {
+		func() {
+			conn, err := net.Dial("unix", sockPath)
+			if err != nil {
+				t.Fatal("Dial failed: ", err)
+			}
+			defer conn.Close()
+			cl, err := rpc.NewClient(ctx, conn, conn, &protocol.HandshakeRequest{RunnerInitParams: &protocol.RunnerInitParams{}})
+			if err != nil {
+				t.Fatal("NewClient failed: ", err)
+			}
+			defer cl.Close()
+
+			got, err := protocol.NewTestServiceClient(cl.Conn()).GetDUTInfo(ctx, &protocol.GetDUTInfoRequest{})
+			if err != nil {
+				t.Fatal("GetDUTInfo failed: ", err)
+			}
+			if diff := cmp.Diff(got, want, protocmp.Transform()); diff != "" {
+				t.Errorf("GetDUTInfo mismatch (-got +want):\n%s", diff)
+			}
+		}()
+	}
+
+	if calls != 1 {
+		t.Errorf("GetDUTInfo was called %d times; want 1", calls)
+	}
+}
+
+func TestDaemonIdleTimeout(t *gotesting.T) {
+	sockPath := filepath.Join(testutil.TempDir(t), "runner.sock")
+
+	done := make(chan error, 1)
+	go func() {
+		done <- serveDaemon(context.Background(), &StaticConfig{}, &DaemonConfig{SocketPath: sockPath, IdleTimeout: 100 * time.Millisecond})
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("serveDaemon failed: %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Daemon did not exit after idle timeout")
+	}
+}
+
+func TestDUTInfoCacheExpires(t *gotesting.T) {
+	calls := 0
+	c := newDUTInfoCache(func(ctx context.Context, req *protocol.GetDUTInfoRequest) (*protocol.GetDUTInfoResponse, error) {
+		calls++
+		return &protocol.GetDUTInfoResponse{}, nil
+	}, time.Minute)
+	now := time.Unix(1000, 0)
+	c.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	for _, tc := range []struct {
+		elapsed   time.Duration
+		wantCalls int
+	}{
+		{0, 1},
+		{30 * time.Second, 1},
+		{time.Minute, 2}, // expired
+		{30 * time.Second, 2},
+	} {
+		now = now.Add(tc.elapsed)
+		if _, err := c.GetDUTInfo(ctx, &protocol.GetDUTInfoRequest{}); err != nil {
+			t.Fatal("GetDUTInfo failed: ", err)
+		}
+		if calls != tc.wantCalls {
+			t.Errorf("After %v: GetDUTInfo was called %d times; want %d", tc.elapsed, calls, tc.wantCalls)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/slices"
+
+	"go.chromium.org/tast/core/errors"
+	"go.chromium.org/tast/core/internal/logging"
+	"go.chromium.org/tast/core/internal/protocol"
+)
+
+// defaultPowerSupplyDir is the default sysfs directory containing power
+// supply information.
+const defaultPowerSupplyDir = "/sys/class/power_supply"
+
+// runPowerCommands runs shell commands of a power policy one by one. phase is
+// a human-readable name of the commands used in logs, e.g. "pre-run".
+func runPowerCommands(ctx context.Context, phase string, cmds []string) error {
+	for _, c := range cmds {
+		logging.Infof(ctx, "Running %s power command: %s", phase, c)
+		if out, err := exec.CommandContext(ctx, "sh", "-c", c).CombinedOutput(); err != nil {
+			return errors.Wrapf(err, "%s power command %q failed: %s", phase, c, strings.TrimSpace(string(out)))
+		}
+	}
+	return nil
+}
+
+// batteryPercent returns the capacity in percent of the first battery found
+// in dir, a sysfs directory containing power supply information. ok is false
+// if the device has no battery.
+func batteryPercent(dir string) (percent int, ok bool, err error) {
+	ents, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	for _, ent := range ents {
+		typ, err := os.ReadFile(filepath.Join(dir, ent.Name(), "type"))
+		if err != nil || strings.TrimSpace(string(typ)) != "Battery" {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(dir, ent.Name(), "capacity"))
+		if err != nil {
+			return 0, false, err
+		}
+		percent, err := strconv.Atoi(strings.TrimSpace(string(b)))
+		if err != nil {
+			return 0, false, errors.Wrapf(err, "failed to parse capacity of %s", ent.Name())
+		}
+		return percent, true, nil
+	}
+	return 0, false, nil
+}
+
+// skipPowerSensitiveTests marks power-sensitive tests in cfg to be skipped if
+// the battery level is below the minimum required by policy.
+func (s *testServer) skipPowerSensitiveTests(ctx context.Context, cfg *protocol.RunConfig, policy *protocol.PowerPolicy) error {
+	if cfg == nil || policy.GetMinBatteryPercent() <= 0 || len(policy.GetSensitiveAttributes()) == 0 {
+		return nil
+	}
+
+	dir := s.scfg.PowerSupplyDir
+	if dir == "" {
+		dir = defaultPowerSupplyDir
+	}
+	percent, ok, err := batteryPercent(dir)
+	if err != nil {
+		return errors.Wrap(err, "failed to read battery level")
+	}
+	if !ok {
+		logging.Info(ctx, "No battery found; skipping battery level check")
+		return nil
+	}
+	if percent >= int(policy.GetMinBatteryPercent()) {
+		return nil
+	}
+
+	res, err := s.ListEntities(ctx, &protocol.ListEntitiesRequest{Features: cfg.GetFeatures()})
+	if err != nil {
+		return err
+	}
+	if cfg.Features == nil {
+		cfg.Features = &protocol.Features{}
+	}
+	if cfg.Features.ForceSkips == nil {
+		cfg.Features.ForceSkips = make(map[string]*protocol.ForceSkip)
+	}
+	reason := fmt.Sprintf("Battery level %d%% is below %d%% required by power-sensitive tests", percent, policy.GetMinBatteryPercent())
+	for _, e := range res.GetEntities() {
+		ent := e.GetEntity()
+		if ent.GetType() != protocol.EntityType_TEST {
+			continue
+		}
+		for _, attr := range policy.GetSensitiveAttributes() {
+			if slices.Contains(ent.GetAttributes(), attr) {
+				cfg.Features.ForceSkips[ent.GetName()] = &protocol.ForceSkip{Reason: reason}
+				break
+			}
+		}
+	}
+	logging.Info(ctx, reason)
+	return nil
+}
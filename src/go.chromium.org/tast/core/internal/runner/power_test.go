@@ -0,0 +1,131 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package runner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	gotesting "testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go.chromium.org/tast/core/internal/bundle/fakebundle"
+	"go.chromium.org/tast/core/internal/protocol"
+	"go.chromium.org/tast/core/internal/protocol/protocoltest"
+	"go.chromium.org/tast/core/internal/testing"
+	"go.chromium.org/tast/core/testutil"
+)
+
+func TestBatteryPercent(t *gotesting.T) {
+	for _, tc := range []struct {
+		name    string
+		files   map[string]string
+		percent int
+		ok      bool
+	}{
+		{
+			name: "battery",
+			files: map[string]string{
+				"AC/type":        "Mains\n",
+				"BAT0/type":      "Battery\n",
+				"BAT0/capacity":  "42\n",
+				"other/type":     "USB\n",
+				"other/capacity": "100\n",
+			},
+			percent: 42,
+			ok:      true,
+		},
+		{
+			name:  "no battery",
+			files: map[string]string{"AC/type": "Mains\n"},
+		},
+	} {
+		t.Run(tc.name, func(t *gotesting.T) {
+			td := testutil.TempDir(t)
+			defer os.RemoveAll(td)
+			if err := testutil.WriteFiles(td, tc.files); err != nil {
+				t.Fatal(err)
+			}
+			percent, ok, err := batteryPercent(td)
+			if err != nil {
+				t.Fatal("batteryPercent failed: ", err)
+			}
+			if percent != tc.percent || ok != tc.ok {
+				t.Errorf("batteryPercent = (%d, %v); want (%d, %v)", percent, ok, tc.percent, tc.ok)
+			}
+		})
+	}
+}
+
+func TestTestServerRunTestsPowerPolicy(t *gotesting.T) {
+	test1 := &testing.TestInstance{
+		Name:    "pkg.Normal",
+		Func:    func(ctx context.Context, s *testing.State) {},
+		Timeout: time.Minute,
+	}
+	test2 := &testing.TestInstance{
+		Name:    "pkg.Power",
+		Attr:    []string{"graphics_power"},
+		Func:    func(ctx context.Context, s *testing.State) {},
+		Timeout: time.Minute,
+	}
+	reg := testing.NewRegistry("bundle")
+	reg.AddTestInstance(test1)
+	reg.AddTestInstance(test2)
+	bundleGlob := fakebundle.Install(t, reg)
+
+	td := testutil.TempDir(t)
+	defer os.RemoveAll(td)
+	if err := testutil.WriteFiles(td, map[string]string{
+		"power_supply/BAT0/type":     "Battery\n",
+		"power_supply/BAT0/capacity": "20\n",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	logPath := filepath.Join(td, "log")
+
+	scfg := &StaticConfig{PowerSupplyDir: filepath.Join(td, "power_supply")}
+	cl := startTestServerWithConfig(t, scfg, &protocol.RunnerInitParams{
+		BundleGlob: bundleGlob,
+		PowerPolicy: &protocol.PowerPolicy{
+			PreRunCommands:      []string{"echo discharge >> " + logPath},
+			PostRunCommands:     []string{"echo charge >> " + logPath},
+			MinBatteryPercent:   50,
+			SensitiveAttributes: []string{"graphics_power"},
+		},
+	})
+
+	events, err := protocoltest.RunTestsForEvents(context.Background(), cl, &protocol.RunConfig{})
+	if err != nil {
+		t.Fatalf("RunTests failed: %v", err)
+	}
+
+	const reason = "Battery level 20% is below 50% required by power-sensitive tests"
+	wantEvents := []protocol.Event{
+		&protocol.EntityStartEvent{Entity: test2.EntityProto()},
+		&protocol.EntityEndEvent{EntityName: test2.Name, Skip: &protocol.Skip{
+			Reasons: []string{reason},
+			TypedReasons: []*protocol.SkipReason{{
+				Code:    protocol.SkipReason_MANUAL,
+				Details: []string{reason},
+			}},
+		}},
+		&protocol.EntityStartEvent{Entity: test1.EntityProto()},
+		&protocol.EntityEndEvent{EntityName: test1.Name},
+	}
+	if diff := cmp.Diff(events, wantEvents, protocoltest.EventCmpOpts...); diff != "" {
+		t.Errorf("Events mismatch (-got +want):\n%s", diff)
+	}
+
+	b, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), "discharge\ncharge\n"; got != want {
+		t.Errorf("Power commands log = %q; want %q", got, want)
+	}
+}
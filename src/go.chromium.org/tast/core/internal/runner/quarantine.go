@@ -0,0 +1,176 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go.chromium.org/tast/core/errors"
+	"go.chromium.org/tast/core/internal/logging"
+	"go.chromium.org/tast/core/internal/protocol"
+)
+
+// quarantineFile is a file name under the base output directory where crash
+// records of tests are saved. The base output directory is shared by all
+// RunTests calls of a run and survives DUT reboots.
+const quarantineFile = ".quarantine.json"
+
+// crashRecord is a record of a test saved to quarantineFile.
+type crashRecord struct {
+	// Entity is the test entity serialized in the protocol buffer wire
+	// format. It is reported on quarantining the test.
+	Entity []byte `json:"entity"`
+	// Running is the number of runs of the test started but not finished.
+	Running int `json:"running"`
+	// Crashes is the number of runs of the test interrupted by a crash of the
+	// test bundle or a reboot of the DUT.
+	Crashes int `json:"crashes"`
+}
+
+// testQuarantiner quarantines tests that repeatedly crash the test bundle or
+// reboot the DUT for the rest of a run.
+//
+// It records tests started and finished by observing events relayed from test
+// bundles. A test that was started but not finished by the end of a previous
+// RunTests call was interrupted by a crash of the test bundle or a reboot of
+// the DUT, since the test driver calls RunTests again after such failures.
+type testQuarantiner struct {
+	threshold int
+	path      string
+	records   map[string]*crashRecord
+}
+
+// newTestQuarantiner creates a testQuarantiner for a RunTests call with cfg.
+// It counts tests interrupted in previous RunTests calls as crashed. It
+// returns nil if quarantine is disabled, in which case the quarantiner does
+// nothing.
+func newTestQuarantiner(cfg *protocol.RunConfig) (*testQuarantiner, error) {
+	if cfg.GetQuarantineThreshold() <= 0 || cfg.GetDirs().GetOutDir() == "" {
+		return nil, nil
+	}
+	q := &testQuarantiner{
+		threshold: int(cfg.GetQuarantineThreshold()),
+		path:      filepath.Join(cfg.GetDirs().GetOutDir(), quarantineFile),
+		records:   make(map[string]*crashRecord),
+	}
+	if err := os.MkdirAll(cfg.GetDirs().GetOutDir(), 0755); err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(q.path)
+	if os.IsNotExist(err) {
+		return q, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &q.records); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", q.path)
+	}
+	for _, r := range q.records {
+		r.Crashes += r.Running
+		r.Running = 0
+	}
+	return q, q.save()
+}
+
+// Quarantine removes quarantined tests from cfg.Tests and returns events to
+// be sent in place of running them.
+func (q *testQuarantiner) Quarantine(ctx context.Context, cfg *protocol.RunConfig) ([]*protocol.EntityQuarantineEvent, error) {
+	if q == nil {
+		return nil, nil
+	}
+	var tests []string
+	var evs []*protocol.EntityQuarantineEvent
+	for _, name := range cfg.GetTests() {
+		r, ok := q.records[name]
+		if !ok || r.Crashes < q.threshold {
+			tests = append(tests, name)
+			continue
+		}
+		e := &protocol.Entity{}
+		if err := proto.Unmarshal(r.Entity, e); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse crash record of %s", name)
+		}
+		logging.Infof(ctx, "Not running %s as it is quarantined after %d crashes", name, r.Crashes)
+		evs = append(evs, &protocol.EntityQuarantineEvent{
+			Time:    timestamppb.Now(),
+			Entity:  e,
+			Crashes: int32(r.Crashes),
+		})
+	}
+	cfg.Tests = tests
+	return evs, nil
+}
+
+// HandleEvent records starts and ends of tests. It should be called before
+// res is relayed to the test driver so that a test is recorded as running
+// before the driver may see the test bundle crashing.
+func (q *testQuarantiner) HandleEvent(ctx context.Context, res *protocol.RunTestsResponse) {
+	if q == nil {
+		return
+	}
+	switch t := res.GetType().(type) {
+	case *protocol.RunTestsResponse_EntityStart:
+		e := t.EntityStart.GetEntity()
+		if e.GetType() != protocol.EntityType_TEST {
+			return
+		}
+		b, err := proto.Marshal(e)
+		if err != nil {
+			logging.Infof(ctx, "Failed to record start of %s: %v", e.GetName(), err)
+			return
+		}
+		r, ok := q.records[e.GetName()]
+		if !ok {
+			r = &crashRecord{}
+			q.records[e.GetName()] = r
+		}
+		r.Entity = b
+		r.Running++
+	case *protocol.RunTestsResponse_EntityEnd:
+		r, ok := q.records[t.EntityEnd.GetEntityName()]
+		if !ok || r.Running == 0 {
+			return
+		}
+		r.Running--
+	default:
+		return
+	}
+	if err := q.save(); err != nil {
+		logging.Infof(ctx, "Failed to save crash records: %v", err)
+	}
+}
+
+// save writes crash records to the file atomically. The file is synced so
+// that records survive a DUT reboot right after that.
+func (q *testQuarantiner) save() error {
+	b, err := json.Marshal(q.records)
+	if err != nil {
+		return err
+	}
+	tmp := q.path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, q.path)
+}
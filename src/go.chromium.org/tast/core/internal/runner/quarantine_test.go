@@ -0,0 +1,88 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package runner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	gotesting "testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	"go.chromium.org/tast/core/internal/protocol"
+	"go.chromium.org/tast/core/testutil"
+)
+
+func TestTestQuarantiner(t *gotesting.T) {
+	ctx := context.Background()
+	td := testutil.TempDir(t)
+	defer os.RemoveAll(td)
+
+	crash := &protocol.Entity{Name: "pkg.Crash", Type: protocol.EntityType_TEST}
+	pass := &protocol.Entity{Name: "pkg.Pass", Type: protocol.EntityType_TEST}
+	start := func(e *protocol.Entity) *protocol.RunTestsResponse {
+		return &protocol.RunTestsResponse{Type: &protocol.RunTestsResponse_EntityStart{EntityStart: &protocol.EntityStartEvent{Entity: e}}}
+	}
+	end := func(e *protocol.Entity) *protocol.RunTestsResponse {
+		return &protocol.RunTestsResponse{Type: &protocol.RunTestsResponse_EntityEnd{EntityEnd: &protocol.EntityEndEvent{EntityName: e.GetName()}}}
+	}
+
+	// runTests simulates a RunTests call in which pkg.Crash crashes the test
+	// bundle or reboots the DUT. It returns tests to be run and quarantine
+	// events.
+	runTests := func() ([]string, []*protocol.EntityQuarantineEvent) {
+		cfg := &protocol.RunConfig{
+			Tests:               []string{crash.GetName(), pass.GetName()},
+			Dirs:                &protocol.RunDirectories{OutDir: td},
+			QuarantineThreshold: 2,
+		}
+		q, err := newTestQuarantiner(cfg)
+		if err != nil {
+			t.Fatal("newTestQuarantiner failed: ", err)
+		}
+		evs, err := q.Quarantine(ctx, cfg)
+		if err != nil {
+			t.Fatal("Quarantine failed: ", err)
+		}
+		q.HandleEvent(ctx, start(pass))
+		q.HandleEvent(ctx, end(pass))
+		q.HandleEvent(ctx, start(crash))
+		return cfg.GetTests(), evs
+	}
+
+	for i := 0; i < 2; i++ {
+		tests, evs := runTests()
+		if want := []string{crash.GetName(), pass.GetName()}; !cmp.Equal(tests, want) || len(evs) > 0 {
+			t.Errorf("Run #%d: got tests %q and events %v; want tests %q and no event", i+1, tests, evs, want)
+		}
+	}
+
+	tests, evs := runTests()
+	if want := []string{pass.GetName()}; !cmp.Equal(tests, want) {
+		t.Errorf("Run #3: got tests %q; want %q", tests, want)
+	}
+	want := []*protocol.EntityQuarantineEvent{{Entity: crash, Crashes: 2}}
+	if diff := cmp.Diff(evs, want, protocmp.Transform(), protocmp.IgnoreFields(&protocol.EntityQuarantineEvent{}, "time")); diff != "" {
+		t.Errorf("Run #3: events mismatch (-got +want):\n%s", diff)
+	}
+}
+
+func TestTestQuarantinerDisabled(t *gotesting.T) {
+	td := testutil.TempDir(t)
+	defer os.RemoveAll(td)
+
+	q, err := newTestQuarantiner(&protocol.RunConfig{Dirs: &protocol.RunDirectories{OutDir: td}})
+	if err != nil {
+		t.Fatal("newTestQuarantiner failed: ", err)
+	}
+	if q != nil {
+		t.Fatalf("newTestQuarantiner = %v; want nil for zero threshold", q)
+	}
+	if _, err := os.Stat(filepath.Join(td, quarantineFile)); !os.IsNotExist(err) {
+		t.Errorf("%s exists unexpectedly", quarantineFile)
+	}
+}
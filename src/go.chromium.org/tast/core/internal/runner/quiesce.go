@@ -0,0 +1,227 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package runner
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"go.chromium.org/tast/core/errors"
+	"go.chromium.org/tast/core/internal/logging"
+	"go.chromium.org/tast/core/internal/protocol"
+)
+
+const (
+	// defaultCPUDir is the default sysfs directory containing CPU devices.
+	defaultCPUDir = "/sys/devices/system/cpu"
+
+	// defaultChromeDevConfPath is the default path of the file containing
+	// extra command line flags of Chrome.
+	defaultChromeDevConfPath = "/etc/chrome_dev.conf"
+
+	// updateEngineJob is the name of the upstart job of update_engine.
+	updateEngineJob = "update-engine"
+)
+
+// arcProvisioningFlags are Chrome flags disabling background ARC
+// provisioning tasks.
+var arcProvisioningFlags = []string{
+	"--arc-disable-app-sync",
+	"--arc-disable-play-auto-install",
+	"--arc-disable-locale-sync",
+	"--arc-play-store-auto-update=off",
+}
+
+// revertFunc reverts an action taken to quiesce the DUT.
+type revertFunc func(ctx context.Context) error
+
+// quiesceAction is an action taken to quiesce the DUT before running tests.
+type quiesceAction struct {
+	// desc is a human-readable description of the action used in logs, e.g.
+	// "stop update-engine".
+	desc string
+	// apply takes the action. It returns a function to revert the action, or
+	// nil if the action need not or can not be reverted.
+	apply func(ctx context.Context) (revertFunc, error)
+}
+
+// quiesceActions returns actions to take to quiesce the DUT according to
+// policy.
+func quiesceActions(scfg *StaticConfig, policy *protocol.QuiescePolicy) []*quiesceAction {
+	var actions []*quiesceAction
+	if policy.GetStopUpdateEngine() {
+		actions = append(actions, &quiesceAction{
+			desc:  "stop " + updateEngineJob,
+			apply: func(ctx context.Context) (revertFunc, error) { return stopJob(ctx, updateEngineJob) },
+		})
+	}
+	if policy.GetDisableArcProvisioning() {
+		path := scfg.ChromeDevConfPath
+		if path == "" {
+			path = defaultChromeDevConfPath
+		}
+		actions = append(actions, &quiesceAction{
+			desc:  "disable ARC provisioning",
+			apply: func(ctx context.Context) (revertFunc, error) { return appendChromeFlags(path, arcProvisioningFlags) },
+		})
+	}
+	if gov := policy.GetCpuGovernor(); gov != "" {
+		dir := scfg.CPUDir
+		if dir == "" {
+			dir = defaultCPUDir
+		}
+		actions = append(actions, &quiesceAction{
+			desc:  "pin CPU governor to " + gov,
+			apply: func(ctx context.Context) (revertFunc, error) { return setCPUGovernor(dir, gov) },
+		})
+	}
+	if policy.GetClearCrashSpool() {
+		actions = append(actions, &quiesceAction{
+			desc:  "clear crash spool",
+			apply: func(ctx context.Context) (revertFunc, error) { return nil, clearDirs(scfg.CrashDirs) },
+		})
+	}
+	return actions
+}
+
+// quiesceDUT takes actions to quiesce the DUT according to policy, logging
+// each of them. The returned function reverts the actions in reverse order,
+// and must be called even if quiesceDUT fails, in which case it reverts the
+// actions taken so far.
+func quiesceDUT(ctx context.Context, scfg *StaticConfig, policy *protocol.QuiescePolicy) (revert func(ctx context.Context), err error) {
+	type applied struct {
+		desc   string
+		revert revertFunc
+	}
+	var done []applied
+	revert = func(ctx context.Context) {
+		for i := len(done) - 1; i >= 0; i-- {
+			a := done[i]
+			logging.Infof(ctx, "Reverting quiesce action: %s", a.desc)
+			if err := a.revert(ctx); err != nil {
+				logging.Infof(ctx, "Failed to revert quiesce action %q: %v", a.desc, err)
+			}
+		}
+	}
+
+	for _, a := range quiesceActions(scfg, policy) {
+		logging.Infof(ctx, "Running quiesce action: %s", a.desc)
+		r, err := a.apply(ctx)
+		if err != nil {
+			return revert, errors.Wrapf(err, "quiesce action %q failed", a.desc)
+		}
+		if r != nil {
+			done = append(done, applied{a.desc, r})
+		}
+	}
+	return revert, nil
+}
+
+// stopJob stops the upstart job if it is running. The returned function
+// starts the job again. It returns nil if the job was not running.
+func stopJob(ctx context.Context, job string) (revertFunc, error) {
+	out, err := exec.CommandContext(ctx, "initctl", "status", job).CombinedOutput()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get status of %s: %s", job, strings.TrimSpace(string(out)))
+	}
+	if !strings.Contains(string(out), "start/running") {
+		logging.Infof(ctx, "%s is not running", job)
+		return nil, nil
+	}
+	if out, err := exec.CommandContext(ctx, "initctl", "stop", job).CombinedOutput(); err != nil {
+		return nil, errors.Wrapf(err, "failed to stop %s: %s", job, strings.TrimSpace(string(out)))
+	}
+	return func(ctx context.Context) error {
+		if out, err := exec.CommandContext(ctx, "initctl", "start", job).CombinedOutput(); err != nil {
+			return errors.Wrapf(err, "failed to start %s: %s", job, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}, nil
+}
+
+// appendChromeFlags appends flags to the Chrome flags file at path, so that
+// they are applied whenever Chrome is started. The returned function
+// restores the original content of the file.
+func appendChromeFlags(path string, flags []string) (revertFunc, error) {
+	orig, err := os.ReadFile(path)
+	existed := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	content := string(orig)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	content += strings.Join(flags, "\n") + "\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context) error {
+		if !existed {
+			return os.Remove(path)
+		}
+		return os.WriteFile(path, orig, 0644)
+	}, nil
+}
+
+// setCPUGovernor sets the frequency governor of all CPUs in dir, a sysfs
+// directory containing CPU devices, to gov. The returned function restores
+// original governors.
+func setCPUGovernor(dir, gov string) (revertFunc, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "cpu[0-9]*", "cpufreq", "scaling_governor"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, errors.Errorf("no CPU frequency governors found in %s", dir)
+	}
+
+	orig := make(map[string]string)
+	restore := func(ctx context.Context) error {
+		var firstErr error
+		for path, g := range orig {
+			if err := os.WriteFile(path, []byte(g), 0644); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		return firstErr
+	}
+	for _, path := range paths {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			restore(context.Background())
+			return nil, err
+		}
+		if err := os.WriteFile(path, []byte(gov), 0644); err != nil {
+			restore(context.Background())
+			return nil, errors.Wrapf(err, "failed to set governor of %s", path)
+		}
+		orig[path] = strings.TrimSpace(string(b))
+	}
+	return restore, nil
+}
+
+// clearDirs removes all files in dirs. Missing directories are ignored.
+func clearDirs(dirs []string) error {
+	for _, dir := range dirs {
+		ents, err := os.ReadDir(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		for _, ent := range ents {
+			if err := os.RemoveAll(filepath.Join(dir, ent.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package runner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	gotesting "testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go.chromium.org/tast/core/internal/protocol"
+	"go.chromium.org/tast/core/testutil"
+)
+
+func TestQuiesceDUT(t *gotesting.T) {
+	td := testutil.TempDir(t)
+	defer os.RemoveAll(td)
+
+	// Install a fake initctl reporting update-engine to be running and
+	// logging its arguments.
+	logPath := filepath.Join(td, "initctl.log")
+	if err := testutil.WriteFiles(td, map[string]string{
+		"bin/initctl": "#!/bin/sh\necho \"$@\" >> " + logPath + "\n" +
+			"if [ \"$1\" = status ]; then echo \"$2 start/running, process 123\"; fi\n",
+		"cpu/cpu0/cpufreq/scaling_governor": "schedutil\n",
+		"cpu/cpu1/cpufreq/scaling_governor": "powersave\n",
+		"chrome_dev.conf":                   "--vmodule=foo=1",
+		"crash/foo.dmp":                     "",
+		"crash/sub/bar.dmp":                 "",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(filepath.Join(td, "bin/initctl"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", filepath.Join(td, "bin")+":"+os.Getenv("PATH"))
+
+	scfg := &StaticConfig{
+		CPUDir:            filepath.Join(td, "cpu"),
+		ChromeDevConfPath: filepath.Join(td, "chrome_dev.conf"),
+		CrashDirs:         []string{filepath.Join(td, "crash"), filepath.Join(td, "missing")},
+	}
+	policy := &protocol.QuiescePolicy{
+		StopUpdateEngine:       true,
+		DisableArcProvisioning: true,
+		CpuGovernor:            "performance",
+		ClearCrashSpool:        true,
+	}
+
+	ctx := context.Background()
+	revert, err := quiesceDUT(ctx, scfg, policy)
+	if err != nil {
+		t.Fatal("quiesceDUT failed: ", err)
+	}
+
+	quiesced, err := testutil.ReadFiles(td)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantQuiesced := map[string]string{
+		"bin/initctl":                       quiesced["bin/initctl"],
+		"initctl.log":                       "status update-engine\nstop update-engine\n",
+		"cpu/cpu0/cpufreq/scaling_governor": "performance",
+		"cpu/cpu1/cpufreq/scaling_governor": "performance",
+		"chrome_dev.conf":                   "--vmodule=foo=1\n--arc-disable-app-sync\n--arc-disable-play-auto-install\n--arc-disable-locale-sync\n--arc-play-store-auto-update=off\n",
+	}
+	if diff := cmp.Diff(quiesced, wantQuiesced); diff != "" {
+		t.Errorf("Files mismatch after quiescing (-got +want):\n%s", diff)
+	}
+
+	revert(ctx)
+
+	reverted, err := testutil.ReadFiles(td)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantReverted := map[string]string{
+		"bin/initctl":                       reverted["bin/initctl"],
+		"initctl.log":                       "status update-engine\nstop update-engine\nstart update-engine\n",
+		"cpu/cpu0/cpufreq/scaling_governor": "schedutil",
+		"cpu/cpu1/cpufreq/scaling_governor": "powersave",
+		"chrome_dev.conf":                   "--vmodule=foo=1",
+	}
+	if diff := cmp.Diff(reverted, wantReverted); diff != "" {
+		t.Errorf("Files mismatch after reverting (-got +want):\n%s", diff)
+	}
+}
+
+func TestQuiesceDUTRevertsOnFailure(t *gotesting.T) {
+	td := testutil.TempDir(t)
+	defer os.RemoveAll(td)
+
+	confPath := filepath.Join(td, "chrome_dev.conf")
+	scfg := &StaticConfig{
+		CPUDir:            filepath.Join(td, "cpu"), // no CPUs
+		ChromeDevConfPath: confPath,
+	}
+	policy := &protocol.QuiescePolicy{
+		DisableArcProvisioning: true,
+		CpuGovernor:            "performance",
+	}
+
+	ctx := context.Background()
+	revert, err := quiesceDUT(ctx, scfg, policy)
+	if err == nil {
+		t.Error("quiesceDUT unexpectedly succeeded")
+	}
+	revert(ctx)
+
+	// chrome_dev.conf did not exist, so it should be removed.
+	if _, err := os.Stat(confPath); !os.IsNotExist(err) {
+		t.Errorf("%s was not removed: %v", confPath, err)
+	}
+}
@@ -34,12 +34,18 @@ const (
 	statusTestFailed = 6 // one or more tests failed during manual run
 	_                = 7 // deprecated
 	_                = 8 // deprecated
+
+	statusSelfCheckFailed = 9 // one or more checks failed in self-check mode
 )
 
 // Run reads command-line flags from clArgs and performs the requested action.
 // clArgs should typically be os.Args[1:]. The caller should exit with the
 // returned status code.
 func Run(clArgs []string, stdin io.Reader, stdout, stderr io.Writer, scfg *StaticConfig) int {
+	if isSelfCheckBundle() {
+		return runSelfCheckBundle(clArgs, stdin, stdout, stderr)
+	}
+
 	ctx := context.Background()
 
 	if scfg.EnableSyslog {
@@ -75,6 +81,16 @@ func Run(clArgs []string, stdin io.Reader, stdout, stderr io.Writer, scfg *Stati
 			return command.WriteError(stderr, err)
 		}
 		return statusSuccess
+	case modeDaemon:
+		if err := runDaemon(ctx, scfg, &args.DaemonConfig); err != nil {
+			return command.WriteError(stderr, err)
+		}
+		return statusSuccess
+	case modeSelfCheck:
+		if err := runSelfCheck(ctx, scfg, &args.DeprecatedDirectRunConfig, stdout); err != nil {
+			return command.WriteError(stderr, err)
+		}
+		return statusSuccess
 	default:
 		return command.WriteError(stderr, command.NewStatusErrorf(statusBadArgs, "invalid mode %v", args.Mode))
 	}
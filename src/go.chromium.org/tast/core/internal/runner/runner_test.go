@@ -37,6 +37,10 @@ func init() {
 	if strings.HasPrefix(filepath.Base(os.Args[0]), bundlePrefix) {
 		os.Exit(runFakeBundle())
 	}
+	// Likewise, behave like the dummy bundle used in self-check mode.
+	if isSelfCheckBundle() {
+		os.Exit(runSelfCheckBundle(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+	}
 }
 
 // createBundleSymlinks creates a temporary directory and places symlinks within it
@@ -0,0 +1,299 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"go.chromium.org/tast/core/errors"
+	"go.chromium.org/tast/core/internal/bundle"
+	"go.chromium.org/tast/core/internal/command"
+	"go.chromium.org/tast/core/internal/protocol"
+	"go.chromium.org/tast/core/internal/testing"
+)
+
+const (
+	// selfCheckBundleName is the file name of a symlink to the runner
+	// executable which makes it behave as a dummy test bundle.
+	selfCheckBundleName = "tast_selfcheck_bundle"
+
+	// selfCheckTestName is the name of the test in the dummy test bundle.
+	selfCheckTestName = "selfcheck.Dummy"
+
+	// selfCheckLogText is the message logged by the dummy test.
+	selfCheckLogText = "Self-check dummy test is running"
+
+	// selfCheckMinFreeBytes is the minimum free space required in the
+	// temporary directory.
+	selfCheckMinFreeBytes = 100 * 1024 * 1024
+
+	// selfCheckTimeout is the maximum duration of each self-check.
+	selfCheckTimeout = time.Minute
+)
+
+// SelfCheckReport is a machine-readable health report written to stdout by the
+// test runner in self-check mode.
+type SelfCheckReport struct {
+	// Healthy is true if all checks passed.
+	Healthy bool `json:"healthy"`
+	// Checks contains results of individual checks.
+	Checks []*SelfCheckResult `json:"checks"`
+}
+
+// SelfCheckResult is a result of a single check performed in self-check mode.
+type SelfCheckResult struct {
+	// Name identifies the check, e.g. "bundles".
+	Name string `json:"name"`
+	// OK is true if the check passed.
+	OK bool `json:"ok"`
+	// Details is a human-readable summary of what was checked.
+	Details string `json:"details,omitempty"`
+	// Error describes why the check failed. It is empty if OK is true.
+	Error string `json:"error,omitempty"`
+}
+
+// selfCheck is a single check performed in self-check mode. It returns a
+// human-readable summary on success.
+type selfCheck struct {
+	name string
+	run  func(ctx context.Context, scfg *StaticConfig, drcfg *DeprecatedDirectRunConfig) (string, error)
+}
+
+var selfChecks = []selfCheck{
+	{"bundles", checkBundles},
+	{"plumbing", checkPlumbing},
+	{"logs", checkLogs},
+	{"crash_dirs", checkCrashDirs},
+	{"disk", checkDisk},
+}
+
+// runSelfCheck exercises subsystems of the test runner and writes a
+// SelfCheckReport to stdout. It returns an error with statusSelfCheckFailed if
+// any check fails.
+func runSelfCheck(ctx context.Context, scfg *StaticConfig, drcfg *DeprecatedDirectRunConfig, stdout io.Writer) error {
+	// Self-checks may run while tests are running on the DUT.
+	// Never disturb them.
+	safe := *scfg
+	safe.KillStaleRunners = false
+
+	report := &SelfCheckReport{Healthy: true}
+	for _, c := range selfChecks {
+		res := &SelfCheckResult{Name: c.name}
+		details, err := func() (string, error) {
+			ctx, cancel := context.WithTimeout(ctx, selfCheckTimeout)
+			defer cancel()
+			return c.run(ctx, &safe, drcfg)
+		}()
+		if err != nil {
+			res.Error = err.Error()
+			report.Healthy = false
+		} else {
+			res.OK = true
+			res.Details = details
+		}
+		report.Checks = append(report.Checks, res)
+	}
+
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+	if !report.Healthy {
+		return command.NewStatusErrorf(statusSelfCheckFailed, "self-check failed")
+	}
+	return nil
+}
+
+// checkBundles checks that test bundles are installed and can list entities.
+func checkBundles(ctx context.Context, scfg *StaticConfig, drcfg *DeprecatedDirectRunConfig) (string, error) {
+	paths, err := filepath.Glob(drcfg.BundleGlob)
+	if err != nil {
+		return "", err
+	}
+	if len(paths) == 0 {
+		return "", errors.Errorf("no test bundles found at %s", drcfg.BundleGlob)
+	}
+	entities, err := listEntities(ctx, scfg, drcfg.BundleGlob)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("found %d bundle(s) with %d entities", len(paths), len(entities)), nil
+}
+
+// checkPlumbing checks that the test runner can run a test in a bundle and
+// receive its events, by using the runner executable itself as a dummy bundle.
+func checkPlumbing(ctx context.Context, scfg *StaticConfig, drcfg *DeprecatedDirectRunConfig) (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to look up current executable")
+	}
+	dir, err := os.MkdirTemp("", "tast_selfcheck.")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+
+	bundlePath := filepath.Join(dir, selfCheckBundleName)
+	if err := os.Symlink(exe, bundlePath); err != nil {
+		return "", err
+	}
+	outDir := filepath.Join(dir, "out")
+
+	compat, err := startCompatServer(ctx, scfg, &protocol.HandshakeRequest{
+		RunnerInitParams: &protocol.RunnerInitParams{BundleGlob: bundlePath},
+		BundleInitParams: &protocol.BundleInitParams{},
+	})
+	if err != nil {
+		return "", err
+	}
+	defer compat.Close()
+
+	srv, err := compat.Client().RunTests(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "RunTests: failed to call")
+	}
+	if err := srv.Send(&protocol.RunTestsRequest{Type: &protocol.RunTestsRequest_RunTestsInit{RunTestsInit: &protocol.RunTestsInit{
+		RunConfig: &protocol.RunConfig{
+			Tests:          []string{selfCheckTestName},
+			Dirs:           &protocol.RunDirectories{OutDir: outDir, TempDir: dir},
+			Features:       &protocol.Features{},
+			ServiceConfig:  &protocol.ServiceConfig{},
+			DataFileConfig: &protocol.DataFileConfig{},
+		},
+	}}}); err != nil {
+		return "", errors.Wrap(err, "RunTests: failed to send initial request")
+	}
+
+	var started, logged, ended bool
+	for {
+		res, err := srv.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", errors.Wrap(err, "RunTests: failed to receive events")
+		}
+		switch res := res.GetType().(type) {
+		case *protocol.RunTestsResponse_EntityStart:
+			started = res.EntityStart.GetEntity().GetName() == selfCheckTestName
+		case *protocol.RunTestsResponse_EntityLog:
+			if strings.Contains(res.EntityLog.GetText(), selfCheckLogText) {
+				logged = true
+			}
+		case *protocol.RunTestsResponse_EntityError:
+			return "", errors.Errorf("dummy test failed: %s", res.EntityError.GetError().GetReason())
+		case *protocol.RunTestsResponse_EntityEnd:
+			ended = res.EntityEnd.GetEntityName() == selfCheckTestName
+		}
+	}
+	if !started || !logged || !ended {
+		return "", errors.Errorf("missing events from dummy test (start=%v, log=%v, end=%v)", started, logged, ended)
+	}
+	return "ran " + selfCheckTestName, nil
+}
+
+// checkLogs checks that system logs and crash files can be collected.
+func checkLogs(ctx context.Context, scfg *StaticConfig, drcfg *DeprecatedDirectRunConfig) (string, error) {
+	if scfg.GetSysInfoState == nil {
+		return "not supported by this runner", nil
+	}
+	res, err := scfg.GetSysInfoState(ctx, &protocol.GetSysInfoStateRequest{})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("found %d crash file(s)", len(res.GetState().GetCrashFilePaths())), nil
+}
+
+// checkCrashDirs checks that crash directories are readable.
+// Missing directories are fine since they are created on the first crash.
+func checkCrashDirs(ctx context.Context, scfg *StaticConfig, drcfg *DeprecatedDirectRunConfig) (string, error) {
+	var readable []string
+	for _, dir := range scfg.CrashDirs {
+		if _, err := os.ReadDir(dir); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return "", err
+		}
+		readable = append(readable, dir)
+	}
+	return fmt.Sprintf("readable: %v", readable), nil
+}
+
+// checkDisk checks that the temporary directory is writable and has enough
+// free space.
+func checkDisk(ctx context.Context, scfg *StaticConfig, drcfg *DeprecatedDirectRunConfig) (string, error) {
+	dir := drcfg.TempDir
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp(dir, "selfcheck.")
+	if err != nil {
+		return "", errors.Wrapf(err, "%s is not writable", dir)
+	}
+	f.Close()
+	os.Remove(f.Name())
+
+	var st unix.Statfs_t
+	if err := unix.Statfs(dir, &st); err != nil {
+		return "", err
+	}
+	free := uint64(st.Bavail) * uint64(st.Bsize)
+	if free < selfCheckMinFreeBytes {
+		return "", errors.Errorf("only %d bytes free in %s; want at least %d", free, dir, selfCheckMinFreeBytes)
+	}
+	return fmt.Sprintf("%d bytes free in %s", free, dir), nil
+}
+
+// listEntities lists entities in bundles matching bundleGlob.
+func listEntities(ctx context.Context, scfg *StaticConfig, bundleGlob string) ([]*protocol.ResolvedEntity, error) {
+	compat, err := startCompatServer(ctx, scfg, &protocol.HandshakeRequest{
+		RunnerInitParams: &protocol.RunnerInitParams{BundleGlob: bundleGlob},
+		BundleInitParams: &protocol.BundleInitParams{},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer compat.Close()
+
+	res, err := compat.Client().ListEntities(ctx, &protocol.ListEntitiesRequest{Features: &protocol.Features{}})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to enumerate entities in bundles")
+	}
+	return res.GetEntities(), nil
+}
+
+// isSelfCheckBundle returns whether the current process was executed as the
+// dummy test bundle used in self-check mode.
+func isSelfCheckBundle() bool {
+	return filepath.Base(os.Args[0]) == selfCheckBundleName
+}
+
+// runSelfCheckBundle runs the current process as the dummy test bundle used in
+// self-check mode.
+func runSelfCheckBundle(clArgs []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	reg := testing.NewRegistry(selfCheckBundleName)
+	reg.AddTestInstance(&testing.TestInstance{
+		Name: selfCheckTestName,
+		Func: func(ctx context.Context, s *testing.State) {
+			s.Log(selfCheckLogText)
+		},
+		Timeout: selfCheckTimeout,
+	})
+	return bundle.Local(clArgs, stdin, stdout, stderr, reg, bundle.Delegate{})
+}
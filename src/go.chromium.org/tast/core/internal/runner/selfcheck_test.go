@@ -0,0 +1,100 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	gotesting "testing"
+
+	"go.chromium.org/tast/core/internal/protocol"
+	"go.chromium.org/tast/core/testutil"
+)
+
+func TestRunSelfCheck(t *gotesting.T) {
+	bundleDir := createBundleSymlinks(t, []bool{true, true}, []bool{true})
+	defer os.RemoveAll(bundleDir)
+	td := testutil.TempDir(t)
+	defer os.RemoveAll(td)
+
+	crashDir := filepath.Join(td, "crash")
+	if err := os.Mkdir(crashDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	scfg := &StaticConfig{
+		Type: LocalRunner,
+		GetSysInfoState: func(ctx context.Context, req *protocol.GetSysInfoStateRequest) (*protocol.GetSysInfoStateResponse, error) {
+			return &protocol.GetSysInfoStateResponse{}, nil
+		},
+		CrashDirs: []string{crashDir, filepath.Join(td, "missing")},
+		DeprecatedDirectRunDefaults: DeprecatedDirectRunConfig{
+			BundleGlob: filepath.Join(bundleDir, "*"),
+			TempDir:    filepath.Join(td, "tmp"),
+		},
+	}
+	status, stdout, stderr, sig := callRun([]string{"-selfcheck"}, scfg)
+	if status != statusSuccess {
+		t.Fatalf("%s = %v; want %v; stderr:\n%s", sig, status, statusSuccess, stderr)
+	}
+
+	var report SelfCheckReport
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("%s printed unparsable report: %v", sig, err)
+	}
+	if !report.Healthy {
+		t.Errorf("%s reported unhealthy: %s", sig, stdout)
+	}
+	if len(report.Checks) != len(selfChecks) {
+		t.Errorf("%s reported %d checks; want %d", sig, len(report.Checks), len(selfChecks))
+	}
+}
+
+func TestRunSelfCheckFailure(t *gotesting.T) {
+	td := testutil.TempDir(t)
+	defer os.RemoveAll(td)
+
+	scfg := &StaticConfig{
+		Type: LocalRunner,
+		GetSysInfoState: func(ctx context.Context, req *protocol.GetSysInfoStateRequest) (*protocol.GetSysInfoStateResponse, error) {
+			return nil, errors.New("journald is down")
+		},
+		DeprecatedDirectRunDefaults: DeprecatedDirectRunConfig{
+			BundleGlob: filepath.Join(td, "bundles", "*"),
+			TempDir:    filepath.Join(td, "tmp"),
+		},
+	}
+	status, stdout, _, sig := callRun([]string{"-selfcheck"}, scfg)
+	if status != statusSelfCheckFailed {
+		t.Fatalf("%s = %v; want %v", sig, status, statusSelfCheckFailed)
+	}
+
+	var report SelfCheckReport
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("%s printed unparsable report: %v", sig, err)
+	}
+	if report.Healthy {
+		t.Errorf("%s reported healthy", sig)
+	}
+	failed := make(map[string]bool)
+	for _, c := range report.Checks {
+		if !c.OK {
+			failed[c.Name] = true
+		}
+	}
+	for _, name := range []string{"bundles", "logs"} {
+		if !failed[name] {
+			t.Errorf("%s did not report failure of %q: %s", sig, name, stdout)
+		}
+	}
+	for _, name := range []string{"plumbing", "crash_dirs", "disk"} {
+		if failed[name] {
+			t.Errorf("%s reported failure of %q: %s", sig, name, stdout)
+		}
+	}
+}
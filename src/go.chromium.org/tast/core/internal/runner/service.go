@@ -317,6 +317,44 @@ func (s *testServer) RunTests(srv protocol.TestService_RunTestsServer) error {
 		killStaleRunners(ctx, unix.SIGTERM)
 	}
 
+	policy := s.runnerParams.GetPowerPolicy()
+	defer func() {
+		if err := runPowerCommands(ctx, "post-run", policy.GetPostRunCommands()); err != nil {
+			logging.Infof(ctx, "Failed to restore power state: %v", err)
+		}
+	}()
+	if err := runPowerCommands(ctx, "pre-run", policy.GetPreRunCommands()); err != nil {
+		return err
+	}
+	if err := s.skipPowerSensitiveTests(ctx, initReq.GetRunTestsInit().GetRunConfig(), policy); err != nil {
+		return err
+	}
+
+	revertQuiesce, err := quiesceDUT(ctx, s.scfg, s.runnerParams.GetQuiescePolicy())
+	defer revertQuiesce(ctx)
+	if err != nil {
+		return err
+	}
+
+	rcfg := initReq.GetRunTestsInit().GetRunConfig()
+	quarantiner, err := newTestQuarantiner(rcfg)
+	if err != nil {
+		return errors.Wrap(err, "failed to load crash records")
+	}
+	evs, err := quarantiner.Quarantine(ctx, rcfg)
+	if err != nil {
+		return err
+	}
+	for _, ev := range evs {
+		if err := srv.Send(&protocol.RunTestsResponse{Type: &protocol.RunTestsResponse_EntityQuarantine{EntityQuarantine: ev}}); err != nil {
+			return err
+		}
+	}
+	if len(evs) > 0 && len(rcfg.GetTests()) == 0 {
+		// Do not run bundles since they run all tests if none is given.
+		return nil
+	}
+
 	return s.forEachBundle(ctx, s.bundleParams, func(ctx context.Context, ts protocol.TestServiceClient) error {
 		st, err := ts.RunTests(ctx)
 		if err != nil {
@@ -330,6 +368,7 @@ func (s *testServer) RunTests(srv protocol.TestService_RunTestsServer) error {
 		}
 
 		// Relay responses.
+		syslog := newTestSyslogRecorder(s.scfg.SystemLogCollector)
 		for {
 			res, err := st.Recv()
 			if err == io.EOF {
@@ -338,6 +377,8 @@ func (s *testServer) RunTests(srv protocol.TestService_RunTestsServer) error {
 			if err != nil {
 				return err
 			}
+			syslog.HandleEvent(ctx, res)
+			quarantiner.HandleEvent(ctx, res)
 			if err := srv.Send(res); err != nil {
 				return err
 			}
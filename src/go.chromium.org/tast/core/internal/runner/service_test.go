@@ -27,12 +27,18 @@ import (
 // TestServiceClient. On completion of the current test, resources are released
 // automatically.
 func startTestServer(t *gotesting.T, params *protocol.RunnerInitParams) protocol.TestServiceClient {
+	return startTestServerWithConfig(t, &StaticConfig{}, params)
+}
+
+// startTestServerWithConfig is similar to startTestServer, but allows
+// specifying StaticConfig.
+func startTestServerWithConfig(t *gotesting.T, scfg *StaticConfig, params *protocol.RunnerInitParams) protocol.TestServiceClient {
 	sr, cw := io.Pipe()
 	cr, sw := io.Pipe()
 	done := make(chan struct{})
 	go func() {
 		defer close(done)
-		Run([]string{"-rpc"}, sr, sw, io.Discard, &StaticConfig{})
+		Run([]string{"-rpc"}, sr, sw, io.Discard, scfg)
 	}()
 	t.Cleanup(func() {
 		cw.Close()
@@ -0,0 +1,92 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package runner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"go.chromium.org/tast/core/internal/logging"
+	"go.chromium.org/tast/core/internal/logs"
+	"go.chromium.org/tast/core/internal/protocol"
+)
+
+// SystemLogFile is a file name under the output directory of a test where
+// system log entries recorded while the test was running are saved.
+const SystemLogFile = "system_log.txt"
+
+// entityKey identifies a run of an entity in a test bundle.
+type entityKey struct {
+	name       string
+	instanceID int64
+}
+
+// testBookmark is a system log bookmark taken at the start of a test.
+type testBookmark struct {
+	bookmark string
+	outDir   string
+}
+
+// testSyslogRecorder saves system log entries recorded while each test runs
+// to the test's output directory, by observing events relayed from test
+// bundles. Since bookmarks are taken when events are relayed, entries logged
+// just around the boundary of tests may be attributed to a neighboring test.
+type testSyslogRecorder struct {
+	collector logs.SystemLogCollector
+	bookmarks map[entityKey]testBookmark
+}
+
+// newTestSyslogRecorder creates a testSyslogRecorder. collector can be nil,
+// in which case the recorder does nothing.
+func newTestSyslogRecorder(collector logs.SystemLogCollector) *testSyslogRecorder {
+	return &testSyslogRecorder{
+		collector: collector,
+		bookmarks: make(map[entityKey]testBookmark),
+	}
+}
+
+// HandleEvent takes a bookmark of system logs at the start of a test and
+// saves system log entries recorded after the bookmark at the end of it.
+// It should be called before res is relayed to the test driver so that the
+// saved file is included in the test's output directory.
+func (r *testSyslogRecorder) HandleEvent(ctx context.Context, res *protocol.RunTestsResponse) {
+	if r.collector == nil {
+		return
+	}
+	switch t := res.GetType().(type) {
+	case *protocol.RunTestsResponse_EntityStart:
+		ev := t.EntityStart
+		if ev.GetEntity().GetType() != protocol.EntityType_TEST || ev.GetOutDir() == "" {
+			return
+		}
+		bookmark, err := r.collector.Bookmark(ctx)
+		if err != nil {
+			logging.Debugf(ctx, "Failed to bookmark system log for %s: %v", ev.GetEntity().GetName(), err)
+			return
+		}
+		r.bookmarks[entityKey{ev.GetEntity().GetName(), ev.GetInstanceId()}] = testBookmark{bookmark: bookmark, outDir: ev.GetOutDir()}
+	case *protocol.RunTestsResponse_EntityEnd:
+		ev := t.EntityEnd
+		key := entityKey{ev.GetEntityName(), ev.GetInstanceId()}
+		b, ok := r.bookmarks[key]
+		if !ok {
+			return
+		}
+		delete(r.bookmarks, key)
+		if err := r.save(ctx, b); err != nil {
+			logging.Debugf(ctx, "Failed to save system log for %s: %v", ev.GetEntityName(), err)
+		}
+	}
+}
+
+func (r *testSyslogRecorder) save(ctx context.Context, b testBookmark) error {
+	f, err := os.Create(filepath.Join(b.outDir, SystemLogFile))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return r.collector.Collect(ctx, b.bookmark, f)
+}
@@ -0,0 +1,109 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package runner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	gotesting "testing"
+	"time"
+
+	"go.chromium.org/tast/core/internal/bundle/fakebundle"
+	"go.chromium.org/tast/core/internal/protocol"
+	"go.chromium.org/tast/core/internal/protocol/protocoltest"
+	"go.chromium.org/tast/core/internal/testing"
+	"go.chromium.org/tast/core/testutil"
+)
+
+// fakeSyslogCollector is a fake logs.SystemLogCollector whose log entries are
+// appended by tests.
+type fakeSyslogCollector struct {
+	mu         sync.Mutex
+	entries    []string
+	bookmarked chan struct{} // signaled on every call to Bookmark
+}
+
+func (c *fakeSyslogCollector) Add(entry string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = append(c.entries, entry)
+}
+
+func (c *fakeSyslogCollector) Bookmark(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.bookmarked <- struct{}{}
+	return fmt.Sprint(len(c.entries)), nil
+}
+
+func (c *fakeSyslogCollector) Collect(ctx context.Context, bookmark string, w io.Writer) error {
+	var n int
+	if _, err := fmt.Sscan(bookmark, &n); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, e := range c.entries[n:] {
+		fmt.Fprintln(w, e)
+	}
+	return nil
+}
+
+func TestTestServerRunTestsSystemLog(t *gotesting.T) {
+	collector := &fakeSyslogCollector{bookmarked: make(chan struct{}, 10)}
+	collector.Add("before tests")
+
+	var mu sync.Mutex
+	outDirs := make(map[string]string)
+	newTest := func(name string) *testing.TestInstance {
+		return &testing.TestInstance{
+			Name: name,
+			Func: func(ctx context.Context, s *testing.State) {
+				mu.Lock()
+				outDirs[name] = s.OutDir()
+				mu.Unlock()
+				// Bookmarks are taken when the runner relays EntityStart
+				// events, so wait for it before logging.
+				<-collector.bookmarked
+				collector.Add("in " + name)
+			},
+			Timeout: time.Minute,
+		}
+	}
+	reg := testing.NewRegistry("bundle")
+	reg.AddTestInstance(newTest("pkg.Test1"))
+	reg.AddTestInstance(newTest("pkg.Test2"))
+
+	scfg := &StaticConfig{SystemLogCollector: collector}
+	cl := startTestServerWithConfig(t, scfg, &protocol.RunnerInitParams{BundleGlob: fakebundle.Install(t, reg)})
+
+	td := testutil.TempDir(t)
+	defer os.RemoveAll(td)
+
+	cfg := &protocol.RunConfig{Dirs: &protocol.RunDirectories{OutDir: td}}
+	if _, err := protocoltest.RunTestsForEvents(context.Background(), cl, cfg); err != nil {
+		t.Fatalf("RunTests failed: %v", err)
+	}
+
+	for _, name := range []string{"pkg.Test1", "pkg.Test2"} {
+		outDir, ok := outDirs[name]
+		if !ok {
+			t.Errorf("%s did not run", name)
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(outDir, SystemLogFile))
+		if err != nil {
+			t.Errorf("Failed to read system log of %s: %v", name, err)
+			continue
+		}
+		if got, want := string(b), "in "+name+"\n"; got != want {
+			t.Errorf("System log of %s = %q; want %q", name, got, want)
+		}
+	}
+}
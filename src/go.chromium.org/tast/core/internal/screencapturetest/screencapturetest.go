@@ -0,0 +1,58 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package screencapturetest provides a fake screenshot command for unit tests.
+package screencapturetest
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.chromium.org/tast/core/testutil"
+)
+
+// FakeImage is the content of files saved by the fake screenshot command
+// installed by InstallFakeScreenshot. It is a small image in PNG format.
+var FakeImage = func() string {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 3))
+	for x := 0; x < 4; x++ {
+		img.Set(x, 1, color.White)
+	}
+	var b bytes.Buffer
+	if err := png.Encode(&b, img); err != nil {
+		panic(err)
+	}
+	return b.String()
+}()
+
+// InstallFakeScreenshot installs a fake screenshot command saving FakeImage to
+// the path given as its argument. It is installed to PATH for the duration of
+// the test.
+func InstallFakeScreenshot(t *testing.T) {
+	t.Helper()
+	dir := testutil.TempDir(t)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	src := filepath.Join(dir, "fake.png")
+	if err := os.WriteFile(src, []byte(FakeImage), 0644); err != nil {
+		t.Fatal(err)
+	}
+	InstallFakeScreenshotScript(t, `cp `+src+` "$1"`)
+}
+
+// InstallFakeScreenshotScript installs a fake screenshot command running
+// script with /bin/sh. It is installed to PATH for the duration of the test.
+func InstallFakeScreenshotScript(t *testing.T, script string) {
+	t.Helper()
+	bin := testutil.TempDir(t)
+	t.Cleanup(func() { os.RemoveAll(bin) })
+	if err := os.WriteFile(filepath.Join(bin, "screenshot"), []byte("#!/bin/sh\n"+script+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+":"+os.Getenv("PATH"))
+}
@@ -31,6 +31,13 @@ type CurrentEntity struct {
 	ServiceDeps []string
 	// PrivateAttr is a list of private attributes declared in the current entity.
 	PrivateAttr []string
+	// TempDirs allocates temporary directories removed after the current
+	// entity finishes. It is nil if temporary directories are unavailable,
+	// e.g. for fixtures.
+	TempDirs *TempDirs
+	// RunMetadata contains run-level metadata given by the -metadata flag of
+	// tast run. It is empty for fixtures.
+	RunMetadata map[string]string
 }
 
 // WithCurrentEntity attaches CurrentEntity to context.Context. This function can't
@@ -74,6 +81,22 @@ func ServiceDeps(ctx context.Context) ([]string, bool) {
 	return append([]string(nil), ec.ServiceDeps...), true
 }
 
+// RunMetadata returns run-level metadata, e.g. the name of the CI builder and
+// the CL under test, given by the -metadata flag of tast run. It is intended
+// to be used by packages that need to correlate their artifacts with the CI
+// context. ok is false if ctx is not associated with an entity.
+func RunMetadata(ctx context.Context) (md map[string]string, ok bool) {
+	ec, ok := ctx.Value(currentEntityKey{}).(*CurrentEntity)
+	if !ok {
+		return nil, false
+	}
+	md = make(map[string]string)
+	for k, v := range ec.RunMetadata {
+		md[k] = v
+	}
+	return md, true
+}
+
 // EnsurePrivateAttr ensures the current entity declares a privateAttr in its metadata.
 // Otherwise it will panic.
 func EnsurePrivateAttr(ctx context.Context, name string) {
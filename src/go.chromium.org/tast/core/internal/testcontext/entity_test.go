@@ -85,6 +85,31 @@ func TestServiceDeps(t *testing.T) {
 	}
 }
 
+func TestRunMetadata(t *testing.T) {
+	ctx := context.Background()
+
+	if _, ok := testcontext.RunMetadata(ctx); ok {
+		t.Error("RunMetadata unexpectedly succeeded for context without CurrentEntity")
+	}
+
+	ec := &testcontext.CurrentEntity{RunMetadata: map[string]string{"builder": "foo"}}
+	ctx = testcontext.WithCurrentEntity(ctx, ec)
+
+	md, ok := testcontext.RunMetadata(ctx)
+	if !ok {
+		t.Fatal("RunMetadata failed for context with CurrentEntity")
+	}
+	if diff := cmp.Diff(md, map[string]string{"builder": "foo"}); diff != "" {
+		t.Errorf("RunMetadata mismatch (-got +want):\n%s", diff)
+	}
+
+	// The returned map should be a copy.
+	md["builder"] = "bar"
+	if md, _ := testcontext.RunMetadata(ctx); md["builder"] != "foo" {
+		t.Errorf("RunMetadata was modified via the returned map: %v", md)
+	}
+}
+
 func TestEnsureLable(t *testing.T) {
 	const testPrivateAttr = "test_privateAttr"
 
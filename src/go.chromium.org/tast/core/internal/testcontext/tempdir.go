@@ -0,0 +1,89 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package testcontext
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"go.chromium.org/tast/core/errors"
+)
+
+// TempDirs allocates temporary directories for an entity and keeps track of
+// them so that the framework can remove them after the entity finishes.
+type TempDirs struct {
+	base    string // directory to create temporary directories in; os.TempDir() if empty
+	pattern string // pattern passed to os.MkdirTemp
+
+	mu   sync.Mutex
+	dirs []string
+}
+
+// NewTempDirs creates a new TempDirs that creates temporary directories in
+// base, or os.TempDir() if base is empty. name is used as the prefix of
+// directory names to make it easy to find the owner of leftovers.
+func NewTempDirs(base, name string) *TempDirs {
+	return &TempDirs{base: base, pattern: name + ".*"}
+}
+
+// Create creates a new temporary directory and records it for later cleanup.
+func (t *TempDirs) Create() (string, error) {
+	dir, err := os.MkdirTemp(t.base, t.pattern)
+	if err != nil {
+		return "", err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.dirs = append(t.dirs, dir)
+	return dir, nil
+}
+
+// CleanUp removes all temporary directories created so far. Directories are
+// removed forcibly, e.g. read-only directories are made writable first.
+// It returns an error for each directory that could not be removed, which is
+// leaked on the DUT.
+func (t *TempDirs) CleanUp() []error {
+	t.mu.Lock()
+	dirs := t.dirs
+	t.dirs = nil
+	t.mu.Unlock()
+
+	var errs []error
+	for _, dir := range dirs {
+		if err := forceRemoveAll(dir); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to remove %s", dir))
+		}
+	}
+	return errs
+}
+
+// forceRemoveAll is similar to os.RemoveAll, but it also removes directories
+// whose permission bits prevent their entries from being removed.
+func forceRemoveAll(dir string) error {
+	if err := os.RemoveAll(dir); err == nil {
+		return nil
+	}
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.IsDir() {
+			os.Chmod(path, 0755)
+		}
+		return nil
+	})
+	return os.RemoveAll(dir)
+}
+
+// TempDir is similar to testing.State.TempDir but takes context instead. It is
+// intended to be used by packages providing support for tests that need
+// temporary directories.
+func TempDir(ctx context.Context) (string, error) {
+	ec, ok := ctx.Value(currentEntityKey{}).(*CurrentEntity)
+	if !ok || ec.TempDirs == nil {
+		return "", errors.New("temporary directories are unavailable in this context")
+	}
+	return ec.TempDirs.Create()
+}
@@ -0,0 +1,59 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package testcontext_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.chromium.org/tast/core/internal/testcontext"
+)
+
+func TestTempDir(t *testing.T) {
+	td := t.TempDir()
+
+	ctx := context.Background()
+	if _, err := testcontext.TempDir(ctx); err == nil {
+		t.Error("TempDir unexpectedly succeeded for context without CurrentEntity")
+	}
+	ctx = testcontext.WithCurrentEntity(ctx, &testcontext.CurrentEntity{})
+	if _, err := testcontext.TempDir(ctx); err == nil {
+		t.Error("TempDir unexpectedly succeeded for context without TempDirs")
+	}
+
+	tds := testcontext.NewTempDirs(td, "pkg.Test")
+	ctx = testcontext.WithCurrentEntity(ctx, &testcontext.CurrentEntity{TempDirs: tds})
+	dir, err := testcontext.TempDir(ctx)
+	if err != nil {
+		t.Fatal("TempDir failed: ", err)
+	}
+	if filepath.Dir(dir) != td || !strings.HasPrefix(filepath.Base(dir), "pkg.Test.") {
+		t.Errorf("TempDir = %q; want pkg.Test.* in %s", dir, td)
+	}
+	// A read-only directory should be removed as well.
+	sub := filepath.Join(dir, "sub")
+	if err := os.Mkdir(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sub, "file"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(sub, 0500); err != nil {
+		t.Fatal(err)
+	}
+
+	if errs := tds.CleanUp(); len(errs) > 0 {
+		t.Error("CleanUp failed: ", errs)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("%s was not removed: %v", dir, err)
+	}
+	if errs := tds.CleanUp(); len(errs) > 0 {
+		t.Error("Second CleanUp failed: ", errs)
+	}
+}
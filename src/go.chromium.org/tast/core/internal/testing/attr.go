@@ -2663,6 +2663,25 @@ func checkKnownAttrs(attrs []string) error {
 	return nil
 }
 
+// IsKnownAttr returns whether attr is a valid group attribute "group:<name>"
+// or a subattribute of any valid group. Unlike checkKnownAttrs, it does not
+// check if a subattribute is valid in groups a test belongs to, so it can be
+// used to detect typos in a partial list of attributes.
+func IsKnownAttr(attr string) bool {
+	if strings.HasPrefix(attr, groupPrefix) {
+		_, ok := validGroupMap[strings.TrimPrefix(attr, groupPrefix)]
+		return ok
+	}
+	for _, g := range validGroups {
+		for _, subattr := range g.Subattrs {
+			if attr == subattr.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // modifyAttrsForCompat modifies an attribute list for compatibility.
 func modifyAttrsForCompat(attrs []string) []string {
 	// If no "group:*" attribute is set, append the "disabled" attribute.
@@ -321,6 +321,27 @@ func TestCheckKnownAttrs(t *testing.T) {
 	}
 }
 
+func TestIsKnownAttr(t *testing.T) {
+	for _, tc := range []struct {
+		attr string
+		want bool
+	}{
+		{"group:mainline", true},
+		{"group:graphics_nightly", false},
+		{"group:crosbolt", true},
+		{"informational", true},
+		{"crosbolt_weekly", true},
+		{"crosbolt_weeky", false},
+		{"graphics_nightly", true},
+		{"graphics_nighty", false},
+		{"foo", false},
+	} {
+		if got := IsKnownAttr(tc.attr); got != tc.want {
+			t.Errorf("IsKnownAttr(%q) = %v; want %v", tc.attr, got, tc.want)
+		}
+	}
+}
+
 func TestModifyAttrsForCompat(t *testing.T) {
 	for _, tc := range []struct {
 		orig []string
@@ -8,6 +8,8 @@ import (
 	"context"
 
 	"go.chromium.org/tast/core/dut"
+	"go.chromium.org/tast/core/phone"
+	"go.chromium.org/tast/core/servo"
 
 	"go.chromium.org/tast/core/framework/protocol"
 )
@@ -47,6 +49,11 @@ type RuntimeConfig struct {
 	MaxSysMsgLogSize int64
 	// DUTLabConfig is the lab configuration for all DUTs.
 	DUTLabConfig *protocol.DUTLabConfig
+	// Seed is the run-level seed from which State.Rand is seeded.
+	Seed int64
+	// RunMetadata contains run-level metadata returned by
+	// testing.ContextRunMetadata.
+	RunMetadata map[string]string
 }
 
 // RemoteData contains information relevant to remote entities.
@@ -59,6 +66,11 @@ type RemoteData struct {
 	DUT *dut.DUT
 	// CompanionDUTs are other DUTs that can be used in remote test.
 	CompanionDUTs map[string]*dut.DUT
+	// Servo is a servod client for the primary DUT. It is nil if servo is
+	// unavailable.
+	Servo *servo.Servo
+	// Phones are Android phones attached to the run, keyed by role.
+	Phones map[string]*phone.Device
 }
 
 // Meta contains information about how the "tast" process used to initiate testing was run.
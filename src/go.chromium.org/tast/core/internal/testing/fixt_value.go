@@ -0,0 +1,204 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package testing
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"go.chromium.org/tast/core/errors"
+)
+
+// FixtValueType describes a type of fixture values that can be transported
+// to tests and fixtures running in other processes, e.g. local tests
+// depending on a remote fixture.
+type FixtValueType struct {
+	// Name uniquely identifies the type, e.g. "chrome.LoginInfo".
+	Name string
+	// Version is the version of the serialized form of the type. It should be
+	// incremented whenever the type changes incompatibly, so that bundles
+	// built with different versions of the type can detect the mismatch
+	// instead of silently misinterpreting values.
+	Version int
+	// New returns a pointer to a new zero value of the type, e.g.
+	// func() any { return &LoginInfo{} }. A fixture should return values of
+	// this type from SetUp. Values implementing proto.Message are serialized
+	// with protojson, and others are serialized with encoding/json.
+	New func() any
+}
+
+// fixtValueTypes contains registered fixture value types.
+var fixtValueTypes = struct {
+	mu     sync.Mutex
+	byName map[string]*FixtValueType
+	byType map[reflect.Type]*FixtValueType
+}{
+	byName: make(map[string]*FixtValueType),
+	byType: make(map[reflect.Type]*FixtValueType),
+}
+
+// RegisterFixtValueType registers t so that fixture values of the type are
+// serialized with its name and version.
+func RegisterFixtValueType(t *FixtValueType) error {
+	if t.Name == "" {
+		return errors.New("fixture value type has an empty name")
+	}
+	if t.New == nil {
+		return errors.Errorf("fixture value type %s has no New function", t.Name)
+	}
+	typ := reflect.TypeOf(t.New())
+	if typ == nil || typ.Kind() != reflect.Ptr {
+		return errors.Errorf("New of fixture value type %s does not return a pointer", t.Name)
+	}
+
+	fixtValueTypes.mu.Lock()
+	defer fixtValueTypes.mu.Unlock()
+	if _, ok := fixtValueTypes.byName[t.Name]; ok {
+		return errors.Errorf("fixture value type %s has already been registered", t.Name)
+	}
+	if o, ok := fixtValueTypes.byType[typ]; ok {
+		return errors.Errorf("fixture value type %s has already been registered as %s", typ, o.Name)
+	}
+	fixtValueTypes.byName[t.Name] = t
+	fixtValueTypes.byType[typ] = t
+	return nil
+}
+
+// lookUpFixtValueType returns a registered type of fixture values of type
+// typ or pointers to it. It returns nil if typ is not registered.
+func lookUpFixtValueType(typ reflect.Type) *FixtValueType {
+	fixtValueTypes.mu.Lock()
+	defer fixtValueTypes.mu.Unlock()
+	if t, ok := fixtValueTypes.byType[typ]; ok {
+		return t
+	}
+	if typ != nil && typ.Kind() != reflect.Ptr {
+		return fixtValueTypes.byType[reflect.PtrTo(typ)]
+	}
+	return nil
+}
+
+// lookUpFixtValueTypeByName returns a registered type of fixture values
+// named name. It returns nil if name is not registered.
+func lookUpFixtValueTypeByName(name string) *FixtValueType {
+	fixtValueTypes.mu.Lock()
+	defer fixtValueTypes.mu.Unlock()
+	return fixtValueTypes.byName[name]
+}
+
+// typedFixtValue is the serialized form of a fixture value of a registered
+// type.
+type typedFixtValue struct {
+	Type    string          `json:"@type"`
+	Version int             `json:"@version"`
+	Value   json.RawMessage `json:"value"`
+}
+
+// marshalValue serializes v with protojson if it is a proto.Message, or with
+// encoding/json otherwise.
+func marshalValue(v any) ([]byte, error) {
+	if m, ok := v.(proto.Message); ok {
+		return protojson.Marshal(m)
+	}
+	return json.Marshal(v)
+}
+
+// unmarshalValue is the inverse of marshalValue.
+func unmarshalValue(data []byte, v any) error {
+	if m, ok := v.(proto.Message); ok {
+		return protojson.Unmarshal(data, m)
+	}
+	return json.Unmarshal(data, v)
+}
+
+// SerializeFixtValue serializes a fixture value to be transported to another
+// process. Values of registered types are serialized with their type names
+// and versions. Other values are serialized with encoding/json.
+func SerializeFixtValue(val any) ([]byte, error) {
+	t := lookUpFixtValueType(reflect.TypeOf(val))
+	if t == nil {
+		return json.Marshal(val)
+	}
+	b, err := marshalValue(val)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(&typedFixtValue{Type: t.Name, Version: t.Version, Value: b})
+}
+
+// parseTypedFixtValue returns the typed fixture value in data. It returns nil
+// if data was not serialized from a value of a registered type.
+func parseTypedFixtValue(data []byte) *typedFixtValue {
+	var tv typedFixtValue
+	if err := json.Unmarshal(data, &tv); err != nil || tv.Type == "" {
+		return nil
+	}
+	return &tv
+}
+
+// DeserializeFixtValue deserializes a fixture value serialized with
+// SerializeFixtValue into the value pointed to by v. If the value was of a
+// registered type, v must point to a value of the same type, and versions of
+// the type must match.
+func DeserializeFixtValue(data []byte, v any) error {
+	tv := parseTypedFixtValue(data)
+	if tv == nil {
+		return json.Unmarshal(data, v)
+	}
+	t := lookUpFixtValueTypeByName(tv.Type)
+	if t == nil {
+		return errors.Errorf("fixture value type %s is not registered", tv.Type)
+	}
+	if got, want := reflect.TypeOf(v), reflect.TypeOf(t.New()); got != want {
+		return errors.Errorf("fixture value of type %s can not be stored in %v; want %v", tv.Type, got, want)
+	}
+	if tv.Version != t.Version {
+		return errors.Errorf("fixture value type %s has version %d, but version %d is expected", tv.Type, tv.Version, t.Version)
+	}
+	return unmarshalValue(tv.Value, v)
+}
+
+// decodeFixtValue returns a new value deserialized from data if it was
+// serialized from a value of a registered type. It returns nil if data was not
+// serialized from a value of a registered type.
+func decodeFixtValue(data []byte) (any, error) {
+	tv := parseTypedFixtValue(data)
+	if tv == nil {
+		return nil, nil
+	}
+	t := lookUpFixtValueTypeByName(tv.Type)
+	if t == nil {
+		return nil, errors.Errorf("fixture value type %s is not registered", tv.Type)
+	}
+	v := t.New()
+	if err := DeserializeFixtValue(data, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// fixtValue returns the value of the parent fixture given in cfg. If the
+// parent fixture runs in another process, its value is deserialized only if
+// its type is registered. Errors deserializing the value are reported to r.
+func fixtValue(cfg *RuntimeConfig, r interface{ Errorf(string, ...any) }) any {
+	if cfg.FixtValue != nil || cfg.FixtSerializedValue == nil {
+		return cfg.FixtValue
+	}
+	data, err := cfg.FixtSerializedValue()
+	if err != nil {
+		// The parent fixture value is unavailable.
+		return nil
+	}
+	v, err := decodeFixtValue(data)
+	if err != nil {
+		r.Errorf("Failed to deserialize fixture value: %v", err)
+		return nil
+	}
+	return v
+}
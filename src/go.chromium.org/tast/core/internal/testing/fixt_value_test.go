@@ -0,0 +1,166 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package testing_test
+
+import (
+	"encoding/json"
+	gotesting "testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/proto"
+
+	"go.chromium.org/tast/core/internal/protocol"
+	"go.chromium.org/tast/core/internal/testing"
+)
+
+type loginInfo struct {
+	User string
+	Pass string
+}
+
+type legacyValue struct {
+	Name string
+}
+
+type unregisteredValue struct {
+	Name string
+}
+
+func init() {
+	for _, t := range []*testing.FixtValueType{
+		{Name: "fixtvaluetest.LoginInfo", Version: 2, New: func() any { return &loginInfo{} }},
+		{Name: "fixtvaluetest.QuiescePolicy", Version: 1, New: func() any { return &protocol.QuiescePolicy{} }},
+	} {
+		if err := testing.RegisterFixtValueType(t); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func TestRegisterFixtValueTypeErrors(t *gotesting.T) {
+	type otherValue struct{}
+	for _, tc := range []struct {
+		name string
+		typ  *testing.FixtValueType
+	}{
+		{"EmptyName", &testing.FixtValueType{New: func() any { return &otherValue{} }}},
+		{"NoNew", &testing.FixtValueType{Name: "fixtvaluetest.NoNew"}},
+		{"NotPointer", &testing.FixtValueType{Name: "fixtvaluetest.NotPointer", New: func() any { return otherValue{} }}},
+		{"DuplicateName", &testing.FixtValueType{Name: "fixtvaluetest.LoginInfo", New: func() any { return &otherValue{} }}},
+		{"DuplicateType", &testing.FixtValueType{Name: "fixtvaluetest.Other", New: func() any { return &loginInfo{} }}},
+	} {
+		t.Run(tc.name, func(t *gotesting.T) {
+			if err := testing.RegisterFixtValueType(tc.typ); err == nil {
+				t.Error("RegisterFixtValueType unexpectedly succeeded")
+			}
+		})
+	}
+}
+
+func TestSerializeFixtValue(t *gotesting.T) {
+	for _, val := range []any{&loginInfo{User: "user", Pass: "pass"}, loginInfo{User: "user", Pass: "pass"}} {
+		b, err := testing.SerializeFixtValue(val)
+		if err != nil {
+			t.Fatalf("SerializeFixtValue(%#v) failed: %v", val, err)
+		}
+		var got loginInfo
+		if err := testing.DeserializeFixtValue(b, &got); err != nil {
+			t.Fatalf("DeserializeFixtValue(%s) failed: %v", b, err)
+		}
+		if diff := cmp.Diff(got, loginInfo{User: "user", Pass: "pass"}); diff != "" {
+			t.Errorf("Value mismatch (-got +want):\n%s", diff)
+		}
+	}
+}
+
+func TestSerializeFixtValueProto(t *gotesting.T) {
+	want := &protocol.QuiescePolicy{StopUpdateEngine: true, CpuGovernor: "performance"}
+	b, err := testing.SerializeFixtValue(want)
+	if err != nil {
+		t.Fatal("SerializeFixtValue failed: ", err)
+	}
+	got := &protocol.QuiescePolicy{}
+	if err := testing.DeserializeFixtValue(b, got); err != nil {
+		t.Fatalf("DeserializeFixtValue(%s) failed: %v", b, err)
+	}
+	if !proto.Equal(got, want) {
+		t.Errorf("DeserializeFixtValue(%s) = %v; want %v", b, got, want)
+	}
+}
+
+func TestSerializeFixtValueUnregistered(t *gotesting.T) {
+	want := legacyValue{Name: "foo"}
+	b, err := testing.SerializeFixtValue(&want)
+	if err != nil {
+		t.Fatal("SerializeFixtValue failed: ", err)
+	}
+	// Values of unregistered types are serialized with encoding/json as is.
+	var got legacyValue
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("json.Unmarshal(%s) failed: %v", b, err)
+	}
+	if got != want {
+		t.Errorf("json.Unmarshal(%s) = %#v; want %#v", b, got, want)
+	}
+	got = legacyValue{}
+	if err := testing.DeserializeFixtValue(b, &got); err != nil {
+		t.Fatalf("DeserializeFixtValue(%s) failed: %v", b, err)
+	}
+	if got != want {
+		t.Errorf("DeserializeFixtValue(%s) = %#v; want %#v", b, got, want)
+	}
+}
+
+func TestDeserializeFixtValueErrors(t *gotesting.T) {
+	for _, tc := range []struct {
+		name string
+		data string
+		v    any
+	}{
+		{"VersionMismatch", `{"@type":"fixtvaluetest.LoginInfo","@version":1,"value":{"User":"user"}}`, &loginInfo{}},
+		{"TypeMismatch", `{"@type":"fixtvaluetest.LoginInfo","@version":2,"value":{"User":"user"}}`, &unregisteredValue{}},
+		{"UnknownType", `{"@type":"fixtvaluetest.Unknown","@version":1,"value":{}}`, &unregisteredValue{}},
+	} {
+		t.Run(tc.name, func(t *gotesting.T) {
+			if err := testing.DeserializeFixtValue([]byte(tc.data), tc.v); err == nil {
+				t.Errorf("DeserializeFixtValue(%s) unexpectedly succeeded", tc.data)
+			}
+		})
+	}
+}
+
+func TestStateFixtValueSerialized(t *gotesting.T) {
+	serialize := func(val any) func() ([]byte, error) {
+		return func() ([]byte, error) { return testing.SerializeFixtValue(val) }
+	}
+
+	for _, tc := range []struct {
+		name    string
+		cfg     *testing.RuntimeConfig
+		want    any
+		wantErr bool
+	}{
+		{"InProcess", &testing.RuntimeConfig{FixtValue: &loginInfo{User: "a"}}, &loginInfo{User: "a"}, false},
+		{"Registered", &testing.RuntimeConfig{FixtSerializedValue: serialize(&loginInfo{User: "b"})}, &loginInfo{User: "b"}, false},
+		{"Unregistered", &testing.RuntimeConfig{FixtSerializedValue: serialize(&legacyValue{Name: "c"})}, nil, false},
+		{"BadVersion", &testing.RuntimeConfig{FixtSerializedValue: func() ([]byte, error) {
+			return []byte(`{"@type":"fixtvaluetest.LoginInfo","@version":1,"value":{}}`), nil
+		}}, nil, true},
+	} {
+		t.Run(tc.name, func(t *gotesting.T) {
+			var out outputSink
+			root := testing.NewTestEntityRoot(&testing.TestInstance{Timeout: time.Minute}, tc.cfg, &out, testing.NewEntityCondition())
+			s := root.NewTestState()
+			got := s.FixtValue()
+			if diff := cmp.Diff(got, tc.want); diff != "" {
+				t.Errorf("FixtValue() mismatch (-got +want):\n%s", diff)
+			}
+			if gotErr := len(out.Data.Errs) > 0; gotErr != tc.wantErr {
+				t.Errorf("FixtValue() reported errors %v; want errors: %v", out.Data.Errs, tc.wantErr)
+			}
+		})
+	}
+}
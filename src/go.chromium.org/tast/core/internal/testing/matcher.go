@@ -92,7 +92,7 @@ func ValidateGlob(glob string) (hasWildcard bool, err error) {
 		switch {
 		case ch == '*':
 			hasWildcard = true
-		case unicode.IsLetter(ch), unicode.IsDigit(ch), ch == '.', ch == '_':
+		case unicode.IsLetter(ch), unicode.IsDigit(ch), ch == '.', ch == '_', ch == '/':
 			continue
 		default:
 			return hasWildcard, fmt.Errorf("invalid character %q in pattern %q", ch, glob)
@@ -31,6 +31,7 @@ func TestMatcher(t *gotesting.T) {
 		{[]string{"*.Bar"}, false},
 		{[]string{"*.*"}, true},
 		{[]string{"*.Tes."}, false}, // ensure dots are escaped
+		{[]string{"bundle/pkg.Test"}, false},
 		{[]string{"(attr1)"}, true},
 		{[]string{"(attr2)"}, false},
 		{[]string{"(!attr1)"}, false},
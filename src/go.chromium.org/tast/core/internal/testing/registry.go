@@ -6,6 +6,7 @@ package testing
 
 import (
 	"fmt"
+	"regexp"
 	"runtime"
 	"strings"
 
@@ -19,6 +20,7 @@ import (
 // Registry holds tests and services.
 type Registry struct {
 	name           string
+	namespace      string // prefix of test names; empty if tests are not namespaced
 	errors         []error
 	allTests       []*TestInstance
 	testNames      map[string]struct{} // names of registered tests
@@ -82,6 +84,11 @@ func (r *Registry) AddTest(t *Test) {
 // accessible by user code.
 func (r *Registry) AddTestInstance(t *TestInstance) {
 	r.RecordError(func() error {
+		if r.namespace != "" {
+			if err := t.setNamespace(r.namespace); err != nil {
+				return err
+			}
+		}
 		if _, ok := r.testNames[t.Name]; ok {
 			return fmt.Errorf("test %q already registered", t.Name)
 		}
@@ -104,6 +111,34 @@ func (r *Registry) AddTestInstance(t *TestInstance) {
 	}())
 }
 
+// bundleNameRegexp validates names of bundles used as namespaces of tests.
+var bundleNameRegexp = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// NamespaceTests makes tests in the registry named "<bundle>/<category>.<Name>",
+// where <bundle> is the name of the registry, so that tests in out-of-tree
+// bundles do not collide with tests in other bundles.
+//
+// Tests are usually registered by init functions before the bundle's main
+// function calls this method, so tests registered so far are added again
+// with the namespace applied.
+func (r *Registry) NamespaceTests() {
+	if r.namespace != "" {
+		return
+	}
+	if !bundleNameRegexp.MatchString(r.name) {
+		r.RecordError(fmt.Errorf("bundle name %q cannot be used as a namespace of tests", r.name))
+		return
+	}
+	r.namespace = r.name
+	tests := r.allTests
+	r.allTests = nil
+	r.testNames = make(map[string]struct{})
+	for _, t := range tests {
+		t.Bundle = ""
+		r.AddTestInstance(t)
+	}
+}
+
 // AddService adds s to the registry.
 func (r *Registry) AddService(s *Service) {
 	r.allServices = append(r.allServices, s)
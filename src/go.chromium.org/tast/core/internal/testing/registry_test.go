@@ -86,6 +86,50 @@ func TestAddTestDuplicateName(t *gotesting.T) {
 	}
 }
 
+func TestNamespaceTests(t *gotesting.T) {
+	reg := NewRegistry("partner")
+	reg.AddTestInstance(&TestInstance{Name: "test.Foo", Attr: []string{"name:test.Foo"}, Func: func(context.Context, *State) {}})
+	reg.NamespaceTests()
+	reg.AddTestInstance(&TestInstance{Name: "test.Bar", Attr: []string{"name:test.Bar"}, Func: func(context.Context, *State) {}})
+	if errs := reg.Errors(); len(errs) > 0 {
+		t.Fatal("Registration failed: ", errs)
+	}
+
+	var names, attrs []string
+	for _, test := range reg.AllTests() {
+		names = append(names, test.Name)
+		attrs = append(attrs, test.Attr...)
+	}
+	if want := []string{"partner/test.Foo", "partner/test.Bar"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("AllTests returned names %q; want %q", names, want)
+	}
+	if want := []string{"name:partner/test.Foo", "name:partner/test.Bar"}; !reflect.DeepEqual(attrs, want) {
+		t.Errorf("AllTests returned attributes %q; want %q", attrs, want)
+	}
+
+	reg.AddTestInstance(&TestInstance{Name: "test.Foo", Func: func(context.Context, *State) {}})
+	if errs := reg.Errors(); len(errs) == 0 {
+		t.Error("Duplicate namespaced test name unexpectedly not rejected")
+	}
+}
+
+func TestNamespaceTestsInvalidTestName(t *gotesting.T) {
+	reg := NewRegistry("partner")
+	reg.NamespaceTests()
+	reg.AddTestInstance(&TestInstance{Name: "other/test.Foo", Func: func(context.Context, *State) {}})
+	if errs := reg.Errors(); len(errs) == 0 {
+		t.Error("Invalid test name unexpectedly not rejected")
+	}
+}
+
+func TestNamespaceTestsInvalidBundleName(t *gotesting.T) {
+	reg := NewRegistry("Bad-Bundle")
+	reg.NamespaceTests()
+	if errs := reg.Errors(); len(errs) == 0 {
+		t.Error("Invalid bundle name unexpectedly not rejected")
+	}
+}
+
 func TestAddTestModifyOriginal(t *gotesting.T) {
 	reg := NewRegistry("bundle")
 	const origDep = "olddep"
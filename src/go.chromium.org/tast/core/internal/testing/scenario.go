@@ -0,0 +1,82 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package testing
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"go.chromium.org/tast/core/internal/timing"
+)
+
+// ScenarioStepStagePrefix is the prefix of names of timing stages recorded for
+// steps of scenario tests. It is followed by the name of the step.
+const ScenarioStepStagePrefix = "scenario step: "
+
+// ScenarioStep is a step of a scenario test.
+//
+// Tests should use the type-safe testing.ScenarioStep instead.
+type ScenarioStep struct {
+	// Name is the name of the step. It must be unique in the scenario.
+	Name string
+	// Func performs the step. state is the object passed to RunScenario and
+	// shared by all steps of the scenario.
+	Func func(ctx context.Context, s *State, state interface{})
+}
+
+// SetScenarioSteps makes t a scenario test running steps.
+func SetScenarioSteps(t *Test, steps []ScenarioStep) {
+	t.scenarioSteps = append([]ScenarioStep(nil), steps...)
+}
+
+// scenarioStepNameRegexp validates names of scenario steps.
+var scenarioStepNameRegexp = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+func validateScenarioSteps(steps []ScenarioStep) error {
+	seen := make(map[string]struct{})
+	for _, step := range steps {
+		if !scenarioStepNameRegexp.MatchString(step.Name) {
+			return fmt.Errorf("invalid scenario step name %q", step.Name)
+		}
+		if _, ok := seen[step.Name]; ok {
+			return fmt.Errorf("duplicate scenario step name is found: %s", step.Name)
+		}
+		seen[step.Name] = struct{}{}
+		if step.Func == nil {
+			return fmt.Errorf("scenario step %s has no Func", step.Name)
+		}
+	}
+	return nil
+}
+
+// RunScenario runs steps of the scenario test being run by s in order as
+// subtests, passing state to each of them. Once a step fails, remaining steps
+// are skipped since they usually depend on the outcome of earlier steps.
+// It returns true if all steps passed.
+//
+// Each step is recorded in a timing stage named with ScenarioStepStagePrefix,
+// and errors reported in it are marked with its name, so that results of steps
+// can be told apart from other timing stages and errors of the test.
+func RunScenario(ctx context.Context, s *State, state interface{}) bool {
+	steps := s.testRoot.test.ScenarioSteps
+	if len(steps) == 0 {
+		panic(fmt.Sprintf("Test %s is not registered with testing.AddScenario", s.testRoot.test.Name))
+	}
+	for i, step := range steps {
+		stepCtx, st := timing.Start(ctx, ScenarioStepStagePrefix+step.Name)
+		ok := s.run(stepCtx, step.Name, step.Name, func(ctx context.Context, s *State) {
+			step.Func(ctx, s, state)
+		})
+		st.End()
+		if !ok {
+			for _, skipped := range steps[i+1:] {
+				s.Logf("Skipping step %s since step %s failed", skipped.Name, step.Name)
+			}
+			return false
+		}
+	}
+	return true
+}
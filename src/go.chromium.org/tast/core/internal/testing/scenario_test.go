@@ -0,0 +1,73 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package testing_test
+
+import (
+	"context"
+	gotesting "testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go.chromium.org/tast/core/internal/testing"
+)
+
+func TestRunScenario(t *gotesting.T) {
+	type state struct{ ran []string }
+	step := func(name string, fail bool) testing.ScenarioStep {
+		return testing.ScenarioStep{
+			Name: name,
+			Func: func(ctx context.Context, s *testing.State, st interface{}) {
+				st.(*state).ran = append(st.(*state).ran, name)
+				if fail {
+					s.Fatal("Failed")
+				}
+			},
+		}
+	}
+
+	for _, tc := range []struct {
+		name     string
+		steps    []testing.ScenarioStep
+		wantOK   bool
+		wantRan  []string
+		wantErrs []string
+	}{
+		{
+			name:    "pass",
+			steps:   []testing.ScenarioStep{step("enroll", false), step("configure", false), step("verify", false)},
+			wantOK:  true,
+			wantRan: []string{"enroll", "configure", "verify"},
+		},
+		{
+			name:     "fail",
+			steps:    []testing.ScenarioStep{step("enroll", false), step("configure", true), step("verify", false)},
+			wantRan:  []string{"enroll", "configure"},
+			wantErrs: []string{"configure|configure: Failed"},
+		},
+	} {
+		t.Run(tc.name, func(t *gotesting.T) {
+			var out outputSink
+			test := &testing.TestInstance{Timeout: time.Minute, ScenarioSteps: tc.steps}
+			root := testing.NewTestEntityRoot(test, &testing.RuntimeConfig{}, &out, testing.NewEntityCondition())
+			s := root.NewTestState()
+
+			st := &state{}
+			if ok := testing.RunScenario(context.Background(), s, st); ok != tc.wantOK {
+				t.Errorf("RunScenario = %v; want %v", ok, tc.wantOK)
+			}
+			if diff := cmp.Diff(st.ran, tc.wantRan); diff != "" {
+				t.Errorf("Run steps mismatch (-got +want):\n%s", diff)
+			}
+			var errs []string
+			for _, e := range out.Data.Errs {
+				errs = append(errs, e.GetScenarioStep()+"|"+e.GetReason())
+			}
+			if diff := cmp.Diff(errs, tc.wantErrs); diff != "" {
+				t.Errorf("Errors mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
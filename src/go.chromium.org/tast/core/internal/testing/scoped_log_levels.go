@@ -0,0 +1,78 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package testing
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.chromium.org/tast/core/internal/logging"
+)
+
+// logLevels maps names of log levels to logging levels.
+var logLevels = map[string]logging.Level{
+	"debug": logging.LevelDebug,
+	"info":  logging.LevelInfo,
+}
+
+// ParseLogLevel parses a name of a log level, "debug" or "info".
+func ParseLogLevel(s string) (logging.Level, error) {
+	level, ok := logLevels[s]
+	if !ok {
+		return 0, fmt.Errorf(`log level %q is not "debug" or "info"`, s)
+	}
+	return level, nil
+}
+
+// ScopedLogLevel is the minimum level of logs reported by tests matching a
+// pattern.
+type ScopedLogLevel struct {
+	// Pattern is a glob matching names of tests the level applies to, e.g.
+	// "ui.*".
+	Pattern string
+	// Level is the minimum level of logs reported by matching tests.
+	Level logging.Level
+
+	re *regexp.Regexp
+}
+
+// ParseScopedLogLevel parses s in the form "<pattern>:<level>", e.g.
+// "ui.*:info".
+func ParseScopedLogLevel(s string) (*ScopedLogLevel, error) {
+	pattern, name, ok := strings.Cut(s, ":")
+	if !ok || pattern == "" {
+		return nil, fmt.Errorf(`scoped log level %q is not "<pattern>:<level>"`, s)
+	}
+	level, err := ParseLogLevel(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ValidateGlob(pattern); err != nil {
+		return nil, err
+	}
+	re, err := compileGlob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return &ScopedLogLevel{Pattern: pattern, Level: level, re: re}, nil
+}
+
+// Match returns true if l applies to the test named name.
+func (l *ScopedLogLevel) Match(name string) bool {
+	return l.re.MatchString(name)
+}
+
+// LogLevelForTest returns the minimum level of logs reported by the test named
+// name. scoped overrides level if they match the test. Later ones take
+// precedence over earlier ones.
+func LogLevelForTest(name string, level logging.Level, scoped []*ScopedLogLevel) logging.Level {
+	for _, l := range scoped {
+		if l.Match(name) {
+			level = l.Level
+		}
+	}
+	return level
+}
@@ -0,0 +1,50 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package testing_test
+
+import (
+	"testing"
+
+	"go.chromium.org/tast/core/internal/logging"
+	internaltest "go.chromium.org/tast/core/internal/testing"
+)
+
+func TestParseScopedLogLevel(t *testing.T) {
+	l, err := internaltest.ParseScopedLogLevel("ui.*:info")
+	if err != nil {
+		t.Fatal("ParseScopedLogLevel failed: ", err)
+	}
+	if l.Pattern != "ui.*" || l.Level != logging.LevelInfo {
+		t.Errorf("ParseScopedLogLevel = %+v; want pattern ui.* and level info", l)
+	}
+
+	for _, s := range []string{"info", ":info", "ui.*:", "ui.*:verbose", "(group:mainline):info"} {
+		if _, err := internaltest.ParseScopedLogLevel(s); err == nil {
+			t.Errorf("ParseScopedLogLevel(%q) unexpectedly succeeded", s)
+		}
+	}
+}
+
+func TestLogLevelForTest(t *testing.T) {
+	var scoped []*internaltest.ScopedLogLevel
+	for _, s := range []string{"ui.*:info", "ui.Foo:debug", "arc.*:info"} {
+		l, err := internaltest.ParseScopedLogLevel(s)
+		if err != nil {
+			t.Fatalf("ParseScopedLogLevel(%q) failed: %v", s, err)
+		}
+		scoped = append(scoped, l)
+	}
+
+	for name, want := range map[string]logging.Level{
+		"ui.Foo":       logging.LevelDebug,
+		"ui.Bar":       logging.LevelInfo,
+		"arc.Boot":     logging.LevelInfo,
+		"example.Pass": logging.LevelDebug,
+	} {
+		if got := internaltest.LogLevelForTest(name, logging.LevelDebug, scoped); got != want {
+			t.Errorf("LogLevelForTest(%q) = %v; want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package testing
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ScopedVar is a runtime variable given only to tests matching a pattern.
+type ScopedVar struct {
+	// Pattern is a glob matching names of tests the variable is given to,
+	// e.g. "ui.*".
+	Pattern string
+	// Name is the name of the variable.
+	Name string
+	// Value is the value of the variable.
+	Value string
+
+	re *regexp.Regexp
+}
+
+// ParseScopedVar parses s in the form "<pattern>:<name>=<value>", e.g.
+// "ui.*:ui.timeout=30s".
+func ParseScopedVar(s string) (*ScopedVar, error) {
+	scope, value, ok := strings.Cut(s, "=")
+	if !ok {
+		return nil, fmt.Errorf(`scoped variable %q is not "<pattern>:<name>=<value>"`, s)
+	}
+	pattern, name, ok := strings.Cut(scope, ":")
+	if !ok || pattern == "" || name == "" {
+		return nil, fmt.Errorf(`scoped variable %q is not "<pattern>:<name>=<value>"`, s)
+	}
+	if _, err := ValidateGlob(pattern); err != nil {
+		return nil, err
+	}
+	re, err := compileGlob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return &ScopedVar{Pattern: pattern, Name: name, Value: value, re: re}, nil
+}
+
+// String returns v in the form accepted by ParseScopedVar.
+func (v *ScopedVar) String() string {
+	return fmt.Sprintf("%s:%s=%s", v.Pattern, v.Name, v.Value)
+}
+
+// Match returns true if v is given to the test named name.
+func (v *ScopedVar) Match(name string) bool {
+	return v.re.MatchString(name)
+}
+
+// VarsForTest returns runtime variables given to the test named name. scoped
+// contains variables in the form accepted by ParseScopedVar, which override
+// vars if they match the test. Later ones take precedence over earlier ones.
+// vars is returned as is if no scoped variable matches the test.
+func VarsForTest(name string, vars map[string]string, scoped []string) (map[string]string, error) {
+	var res map[string]string
+	for _, s := range scoped {
+		v, err := ParseScopedVar(s)
+		if err != nil {
+			return nil, err
+		}
+		if !v.Match(name) {
+			continue
+		}
+		if res == nil {
+			res = make(map[string]string)
+			for k, val := range vars {
+				res[k] = val
+			}
+		}
+		res[v.Name] = v.Value
+	}
+	if res == nil {
+		return vars, nil
+	}
+	return res, nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package testing_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	internaltest "go.chromium.org/tast/core/internal/testing"
+)
+
+func TestParseScopedVar(t *testing.T) {
+	v, err := internaltest.ParseScopedVar("ui.*:ui.timeout=30s=x")
+	if err != nil {
+		t.Fatal("ParseScopedVar failed: ", err)
+	}
+	if v.Pattern != "ui.*" || v.Name != "ui.timeout" || v.Value != "30s=x" {
+		t.Errorf("ParseScopedVar = %+v; want pattern ui.*, name ui.timeout and value 30s=x", v)
+	}
+	if s := v.String(); s != "ui.*:ui.timeout=30s=x" {
+		t.Errorf("String() = %q; want %q", s, "ui.*:ui.timeout=30s=x")
+	}
+	for name, want := range map[string]bool{"ui.Foo": true, "ui.Foo.param": true, "uix.Foo": false, "arc.Boot": false} {
+		if got := v.Match(name); got != want {
+			t.Errorf("Match(%q) = %v; want %v", name, got, want)
+		}
+	}
+
+	for _, s := range []string{"ui.*:timeout", "timeout=30s", ":timeout=30s", "ui.*:=30s", "(group:mainline):timeout=30s"} {
+		if _, err := internaltest.ParseScopedVar(s); err == nil {
+			t.Errorf("ParseScopedVar(%q) unexpectedly succeeded", s)
+		}
+	}
+}
+
+func TestVarsForTest(t *testing.T) {
+	vars := map[string]string{"a": "1", "b": "2"}
+	scoped := []string{"ui.*:a=ui", "ui.Foo:a=foo", "ui.Foo:c=3", "arc.*:b=arc"}
+
+	for _, tc := range []struct {
+		name string
+		want map[string]string
+	}{
+		{"ui.Foo", map[string]string{"a": "foo", "b": "2", "c": "3"}},
+		{"ui.Bar", map[string]string{"a": "ui", "b": "2"}},
+		{"arc.Boot", map[string]string{"a": "1", "b": "arc"}},
+		{"example.Pass", map[string]string{"a": "1", "b": "2"}},
+	} {
+		got, err := internaltest.VarsForTest(tc.name, vars, scoped)
+		if err != nil {
+			t.Errorf("VarsForTest(%q) failed: %v", tc.name, err)
+			continue
+		}
+		if diff := cmp.Diff(got, tc.want); diff != "" {
+			t.Errorf("VarsForTest(%q) mismatch (-got +want):\n%s", tc.name, diff)
+		}
+	}
+	if diff := cmp.Diff(vars, map[string]string{"a": "1", "b": "2"}); diff != "" {
+		t.Errorf("VarsForTest modified vars (-got +want):\n%s", diff)
+	}
+
+	if _, err := internaltest.VarsForTest("ui.Foo", vars, []string{"bad"}); err == nil {
+		t.Error("VarsForTest unexpectedly succeeded for a malformed scoped variable")
+	}
+}
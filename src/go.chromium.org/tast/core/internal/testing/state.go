@@ -52,8 +52,9 @@ package testing
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"hash/fnv"
+	"math/rand"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -73,6 +74,8 @@ import (
 	"go.chromium.org/tast/core/internal/testcontext"
 	"go.chromium.org/tast/core/internal/timing"
 	"go.chromium.org/tast/core/internal/usercode"
+	"go.chromium.org/tast/core/phone"
+	"go.chromium.org/tast/core/servo"
 
 	frameworkprotocol "go.chromium.org/tast/core/framework/protocol"
 )
@@ -129,6 +132,9 @@ type EntityRoot struct {
 	cfg       *RuntimeConfig             // details about how to run an entity
 	out       OutputStream               // stream to which logging messages and errors are reported
 	condition *EntityCondition
+
+	varsMu       sync.Mutex
+	consumedVars map[string]struct{} // names of variables read with values; guarded by varsMu
 }
 
 // NewEntityRoot returns a new EntityRoot object.
@@ -183,6 +189,17 @@ func (r *EntityRoot) recordError() {
 	r.condition.RecordError()
 }
 
+// recordConsumedVar records that the entity has read the variable name and
+// was given its value.
+func (r *EntityRoot) recordConsumedVar(name string) {
+	r.varsMu.Lock()
+	defer r.varsMu.Unlock()
+	if r.consumedVars == nil {
+		r.consumedVars = make(map[string]struct{})
+	}
+	r.consumedVars[name] = struct{}{}
+}
+
 // TestEntityRoot is the root of all State objects associated with a test.
 // TestEntityRoot is very similar to EntityRoot, but it contains additional states and
 // immutable test information.
@@ -192,6 +209,12 @@ type TestEntityRoot struct {
 	test       *TestInstance // test being run
 
 	preValue interface{} // value returned by test.Pre.Prepare; may be nil
+
+	randOnce sync.Once
+	rand     *rand.Rand // random number generator returned by State.Rand
+
+	cleanupsMu sync.Mutex
+	cleanups   []func(ctx context.Context) // functions added by State.AddCleanup
 }
 
 // NewTestEntityRoot returns a new TestEntityRoot object.
@@ -202,6 +225,8 @@ func NewTestEntityRoot(test *TestInstance, cfg *RuntimeConfig, out OutputStream,
 		SoftwareDeps:    append([]string(nil), test.SoftwareDeps[""]...),
 		ServiceDeps:     test.ServiceDeps,
 		PrivateAttr:     test.PrivateAttr,
+		TempDirs:        testcontext.NewTempDirs("", test.Name),
+		RunMetadata:     cfg.RunMetadata,
 	}
 	return &TestEntityRoot{
 		entityRoot: NewEntityRoot(ce, test.Constraints(), cfg, out, condition),
@@ -262,6 +287,38 @@ func (r *TestEntityRoot) SetPreValue(val interface{}) {
 	r.preValue = val
 }
 
+// CleanUpTempDirs removes temporary directories allocated by the test. It
+// returns an error for each directory that could not be removed.
+func (r *TestEntityRoot) CleanUpTempDirs() []error {
+	return r.entityRoot.ce.TempDirs.CleanUp()
+}
+
+// TakeCleanups returns functions added by State.AddCleanup in the order they
+// should be called, i.e. the reverse order they were added, and forgets them.
+func (r *TestEntityRoot) TakeCleanups() []func(ctx context.Context) {
+	r.cleanupsMu.Lock()
+	defer r.cleanupsMu.Unlock()
+	fs := make([]func(ctx context.Context), 0, len(r.cleanups))
+	for i := len(r.cleanups) - 1; i >= 0; i-- {
+		fs = append(fs, r.cleanups[i])
+	}
+	r.cleanups = nil
+	return fs
+}
+
+// ConsumedVars returns sorted names of runtime variables the test has read
+// and was given values for.
+func (r *TestEntityRoot) ConsumedVars() []string {
+	r.entityRoot.varsMu.Lock()
+	defer r.entityRoot.varsMu.Unlock()
+	var names []string
+	for name := range r.entityRoot.consumedVars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // Logger returns a logger for the test entity.
 func (r *TestEntityRoot) Logger() logging.Logger {
 	return logging.NewFuncLogger(func(level logging.Level, ts time.Time, msg string) {
@@ -327,6 +384,9 @@ func NewContext(ctx context.Context, ec *testcontext.CurrentEntity, logger loggi
 type globalMixin struct {
 	entityRoot *EntityRoot
 	errPrefix  string // prefix to be added to error messages
+	// scenarioStep is the name of the scenario step the State object runs in.
+	// It is empty outside scenario steps.
+	scenarioStep string
 
 	mu            sync.Mutex       // protects hasError
 	hasError      bool             // true if any error was reported from this State object or subtests' State objects
@@ -358,6 +418,31 @@ func (s *globalMixin) DUT() *dut.DUT {
 	return s.entityRoot.cfg.RemoteData.DUT
 }
 
+// Servo returns a servod client for the primary DUT given by the -servo flag.
+// It can only be called by remote entities.
+func (s *globalMixin) Servo() (*servo.Servo, error) {
+	if s.entityRoot.cfg.RemoteData == nil {
+		panic("Servo unavailable (running non-remote?)")
+	}
+	if s.entityRoot.cfg.RemoteData.Servo == nil {
+		return nil, errors.New("servo is unavailable; pass -servo=host:port to tast run")
+	}
+	return s.entityRoot.cfg.RemoteData.Servo, nil
+}
+
+// Phone returns the Android phone with role given by the -phone flag.
+// It can only be called by remote entities.
+func (s *globalMixin) Phone(role string) (*phone.Device, error) {
+	if s.entityRoot.cfg.RemoteData == nil {
+		panic("Phone unavailable (running non-remote?)")
+	}
+	d, ok := s.entityRoot.cfg.RemoteData.Phones[role]
+	if !ok {
+		return nil, errors.Errorf("phone %q is unavailable; pass -phone=%s:host:port to tast run", role, role)
+	}
+	return d, nil
+}
+
 // CompanionDUT returns a shared SSH connection for a companion DUT.
 // It can only be called by remote entities.
 func (s *globalMixin) CompanionDUT(role string) *dut.DUT {
@@ -485,6 +570,7 @@ func (s *globalMixin) Error(args ...interface{}) {
 	s.recordError()
 	fullMsg, lastMsg, err := s.formatError(args...)
 	e := NewError(err, fullMsg, lastMsg, 1)
+	e.ScenarioStep = s.scenarioStep
 	s.entityRoot.out.Error(e)
 	s.handleError(fullMsg)
 }
@@ -494,6 +580,7 @@ func (s *globalMixin) Errorf(format string, args ...interface{}) {
 	s.recordError()
 	fullMsg, lastMsg, err := s.formatErrorf(format, args...)
 	e := NewError(err, fullMsg, lastMsg, 1)
+	e.ScenarioStep = s.scenarioStep
 	s.entityRoot.out.Error(e)
 	s.handleError(fullMsg)
 }
@@ -503,6 +590,8 @@ func (s *globalMixin) Fatal(args ...interface{}) {
 	s.recordError()
 	fullMsg, lastMsg, err := s.formatError(args...)
 	e := NewError(err, fullMsg, lastMsg, 1)
+	e.ScenarioStep = s.scenarioStep
+	s.attachGoroutineDump(e, fullMsg)
 	s.entityRoot.out.Error(e)
 	s.handleFatal(fullMsg)
 	runtime.Goexit()
@@ -513,11 +602,22 @@ func (s *globalMixin) Fatalf(format string, args ...interface{}) {
 	s.recordError()
 	fullMsg, lastMsg, err := s.formatErrorf(format, args...)
 	e := NewError(err, fullMsg, lastMsg, 1)
+	e.ScenarioStep = s.scenarioStep
+	s.attachGoroutineDump(e, fullMsg)
 	s.entityRoot.out.Error(e)
 	s.handleFatal(fullMsg)
 	runtime.Goexit()
 }
 
+// attachGoroutineDump saves a goroutine dump to the output directory and
+// attaches its trimmed version to e, so that hangs in helper goroutines
+// causing fatal errors are diagnosable.
+func (s *globalMixin) attachGoroutineDump(e *protocol.Error, msg string) {
+	if err := AttachGoroutineDump(e, s.entityRoot.cfg.OutDir, msg); err != nil {
+		s.Logf("Failed to dump goroutines: %v", err)
+	}
+}
+
 // HasError reports whether the entity has already reported errors.
 func (s *globalMixin) HasError() bool {
 	s.mu.Lock()
@@ -650,6 +750,9 @@ func (s *entityMixin) Var(name string) (val string, ok bool) {
 	}
 
 	val, ok = s.entityRoot.cfg.Vars[name]
+	if ok {
+		s.entityRoot.recordConsumedVar(name)
+	}
 	return val, ok
 }
 
@@ -765,6 +868,76 @@ type State struct {
 	subtests []string // subtest names
 }
 
+// TempDir returns a new temporary directory on the DUT for the test to use.
+// Each call returns a unique directory. The directory is removed
+// automatically after the test finishes, so the test does not need to remove
+// it. Call testing.ContextTempDir instead if State is unavailable.
+func (s *State) TempDir() string {
+	dir, err := s.testRoot.entityRoot.ce.TempDirs.Create()
+	if err != nil {
+		s.Fatal("Failed to create temporary directory: ", err)
+	}
+	return dir
+}
+
+// AddCleanup registers f to be called after the test function returns, even
+// if the test failed or its deadline was reached. Functions are called in the
+// reverse order they were added, in the cleanup stage limited by
+// testing.Test.CleanupTimeout rather than Timeout, so they can clean up even
+// if the test function used up its time. f may report errors via s.
+func (s *State) AddCleanup(f func(ctx context.Context)) {
+	s.testRoot.cleanupsMu.Lock()
+	defer s.testRoot.cleanupsMu.Unlock()
+	s.testRoot.cleanups = append(s.testRoot.cleanups, f)
+}
+
+// HasSoftwareFeature returns whether the software feature name is available
+// on the DUT. name must be declared in SoftSoftwareDeps of the test; it panics
+// otherwise. Tests can call it to degrade gracefully instead of being skipped
+// if optional features are unavailable.
+func (s *State) HasSoftwareFeature(name string) bool {
+	declared := false
+	for _, d := range s.testRoot.test.SoftSoftwareDeps {
+		if d == name {
+			declared = true
+			break
+		}
+	}
+	if !declared {
+		panic(fmt.Sprintf("Software feature %q was not declared in testing.Test.SoftSoftwareDeps", name))
+	}
+	for _, f := range s.testRoot.test.AvailableSoftSoftwareDeps(s.testRoot.entityRoot.cfg.Features[""].GetSoftware()) {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Rand returns a random number generator for the test. It is seeded
+// deterministically from the run-level seed given by the -seed flag of tast
+// run and the test name, so the test gets the same sequence of random numbers
+// whenever it is run with the same seed. The seed is recorded in results.json.
+//
+// The returned generator is shared among the test and its subtests, and it is
+// not safe for concurrent use. Goroutines should use their own generators
+// seeded from it, e.g. rand.New(rand.NewSource(s.Rand().Int63())).
+func (s *State) Rand() *rand.Rand {
+	r := s.testRoot
+	r.randOnce.Do(func() {
+		r.rand = rand.New(rand.NewSource(TestSeed(r.entityRoot.cfg.Seed, r.test.Name)))
+	})
+	return r.rand
+}
+
+// TestSeed returns the seed of the random number generator for the test
+// named name in a run with the run-level seed.
+func TestSeed(seed int64, name string) int64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d:%s", seed, name)
+	return int64(h.Sum64())
+}
+
 // Param returns Val specified at the Param struct for the current test case.
 func (s *State) Param() interface{} {
 	return s.testRoot.test.Val
@@ -774,6 +947,12 @@ func (s *State) Param() interface{} {
 // name during its execution. If Fatal/Fatalf is called from inside a subtest, only that subtest
 // is stopped; its parent continues. Returns true if the subtest passed.
 func (s *State) Run(ctx context.Context, name string, run func(context.Context, *State)) bool {
+	return s.run(ctx, name, s.scenarioStep, run)
+}
+
+// run is similar to Run, but errors reported in the subtest are attributed to
+// the scenario step named scenarioStep.
+func (s *State) run(ctx context.Context, name, scenarioStep string, run func(context.Context, *State)) bool {
 	subtests := append([]string(nil), s.subtests...)
 	subtests = append(subtests, name)
 	ns := &State{
@@ -784,6 +963,7 @@ func (s *State) Run(ctx context.Context, name string, run func(context.Context,
 		testRoot:    s.testRoot,
 		subtests:    subtests,
 	}
+	ns.scenarioStep = scenarioStep
 
 	finished := make(chan struct{})
 
@@ -841,10 +1021,11 @@ func (s *State) Meta() *Meta {
 	return s.testRoot.entityRoot.cfg.RemoteData.Meta.clone()
 }
 
-// FixtValue returns the fixture value if the test depends on a fixture in the same process.
+// FixtValue returns the fixture value if the test depends on a fixture in the same process,
+// or on a fixture in another process whose value type is registered with RegisterFixtValueType.
 // FixtValue returns nil otherwise.
 func (s *State) FixtValue() interface{} {
-	return s.testRoot.entityRoot.cfg.FixtValue
+	return fixtValue(s.testRoot.entityRoot.cfg, s)
 }
 
 // FixtFillValue stores the deserialized result in the value pointed to by v.
@@ -853,7 +1034,7 @@ func (s *State) FixtFillValue(v any) error {
 	if err != nil {
 		return errors.Wrap(err, "failed to get serialize fixture value")
 	}
-	if err := json.Unmarshal(data, v); err != nil {
+	if err := DeserializeFixtValue(data, v); err != nil {
 		return errors.Wrap(err, "failed to deserialize fixture value")
 	}
 	return nil
@@ -941,16 +1122,17 @@ func (s *FixtState) ParentFillValue(v any) error {
 	if err != nil {
 		return errors.Wrap(err, "failed to get serialize fixture value")
 	}
-	if err := json.Unmarshal(data, v); err != nil {
+	if err := DeserializeFixtValue(data, v); err != nil {
 		return errors.Wrap(err, "failed to deserialize fixture value")
 	}
 	return nil
 }
 
-// ParentValue returns the parent fixture value if the fixture has a parent in the same process.
+// ParentValue returns the parent fixture value if the fixture has a parent in the same process,
+// or a parent in another process whose value type is registered with RegisterFixtValueType.
 // ParentValue returns nil otherwise.
 func (s *FixtState) ParentValue() interface{} {
-	return s.entityRoot.cfg.FixtValue
+	return fixtValue(s.entityRoot.cfg, s)
 }
 
 // OutDir returns a directory into which the entity may place arbitrary files
@@ -32,6 +32,8 @@ import (
 	"go.chromium.org/tast/core/internal/testing"
 
 	frameworkprotocol "go.chromium.org/tast/core/framework/protocol"
+	"go.chromium.org/tast/core/phone"
+	"go.chromium.org/tast/core/servo"
 	"go.chromium.org/tast/core/testutil"
 )
 
@@ -190,6 +192,66 @@ func TestRunReturn(t *gotesting.T) {
 	}
 }
 
+func TestFatalGoroutineDump(t *gotesting.T) {
+	td := testutil.TempDir(t)
+	defer os.RemoveAll(td)
+
+	var out outputSink
+	root := testing.NewTestEntityRoot(&testing.TestInstance{Timeout: time.Minute}, &testing.RuntimeConfig{OutDir: td}, &out, testing.NewEntityCondition())
+	s := root.NewTestState()
+
+	// Start a helper goroutine whose stack should appear in the dump.
+	stop := make(chan struct{})
+	defer close(stop)
+	started := make(chan struct{})
+	go func() {
+		close(started)
+		helperBlockedForGoroutineDump(stop)
+	}()
+	<-started
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		helperCallingFatalForGoroutineDump(s)
+	}()
+	<-done
+
+	if len(out.Data.Errs) != 1 {
+		t.Fatalf("Got %d error(s); want 1", len(out.Data.Errs))
+	}
+	// The attached dump is trimmed, so only the goroutine calling Fatal is
+	// guaranteed to be in it. It is placed first.
+	const caller = "helperCallingFatalForGoroutineDump"
+	stack := out.Data.Errs[0].GetLocation().GetStack()
+	if _, dump, ok := strings.Cut(stack, "Goroutine dump"); !ok {
+		t.Errorf("Error stack does not contain a goroutine dump:\n%s", stack)
+	} else if first, _, _ := strings.Cut(dump, "\n\n"); !strings.Contains(first, caller) {
+		t.Errorf("First goroutine in the attached dump is not %s:\n%s", caller, stack)
+	}
+
+	const helper = "helperBlockedForGoroutineDump"
+	b, err := os.ReadFile(filepath.Join(td, testing.GoroutineDumpFile))
+	if err != nil {
+		t.Fatal("Failed to read goroutine dump: ", err)
+	}
+	if dump := string(b); !strings.Contains(dump, "fail") || !strings.Contains(dump, helper) {
+		t.Errorf("Unexpected goroutine dump:\n%s", dump)
+	}
+}
+
+// helperCallingFatalForGoroutineDump calls s.Fatal. It is named distinctively
+// to be found in goroutine dumps.
+func helperCallingFatalForGoroutineDump(s *testing.State) {
+	s.Fatal("fail")
+}
+
+// helperBlockedForGoroutineDump blocks until stop is closed. It is named
+// distinctively to be found in goroutine dumps.
+func helperBlockedForGoroutineDump(stop <-chan struct{}) {
+	<-stop
+}
+
 func TestParallelRun(t *gotesting.T) {
 	var out outputSink
 	root := testing.NewTestEntityRoot(&testing.TestInstance{Timeout: time.Minute}, &testing.RuntimeConfig{}, &out, testing.NewEntityCondition())
@@ -733,6 +795,11 @@ func TestVars(t *gotesting.T) {
 			t.Error(funcCall, " succeeded unexpectedly")
 		}
 	}
+
+	// Only variables read with values are reported as consumed.
+	if got, want := root.ConsumedVars(), []string{validName}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ConsumedVars() = %q; want %q", got, want)
+	}
 }
 
 func TestMeta(t *gotesting.T) {
@@ -857,6 +924,54 @@ func TestDUT(t *gotesting.T) {
 	}
 }
 
+func TestServo(t *gotesting.T) {
+	newState := func(rd *testing.RemoteData) *testing.State {
+		var out outputSink
+		root := testing.NewTestEntityRoot(&testing.TestInstance{Name: "do.Remotely"}, &testing.RuntimeConfig{RemoteData: rd}, &out, testing.NewEntityCondition())
+		return root.NewTestState()
+	}
+
+	if _, err := newState(&testing.RemoteData{}).Servo(); err == nil {
+		t.Error("Servo() unexpectedly succeeded without servo")
+	}
+
+	want, err := servo.New("localhost:9999")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := newState(&testing.RemoteData{Servo: want}).Servo()
+	if err != nil {
+		t.Fatal("Servo() failed: ", err)
+	}
+	if got != want {
+		t.Errorf("Servo() = %p; want %p", got, want)
+	}
+}
+
+func TestPhone(t *gotesting.T) {
+	newState := func(rd *testing.RemoteData) *testing.State {
+		var out outputSink
+		root := testing.NewTestEntityRoot(&testing.TestInstance{Name: "do.Remotely"}, &testing.RuntimeConfig{RemoteData: rd}, &out, testing.NewEntityCondition())
+		return root.NewTestState()
+	}
+
+	want, err := phone.New("192.168.0.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newState(&testing.RemoteData{Phones: map[string]*phone.Device{"pixel": want}})
+	if _, err := s.Phone("other"); err == nil {
+		t.Error("Phone(other) unexpectedly succeeded")
+	}
+	got, err := s.Phone("pixel")
+	if err != nil {
+		t.Fatal("Phone(pixel) failed: ", err)
+	}
+	if got != want {
+		t.Errorf("Phone(pixel) = %p; want %p", got, want)
+	}
+}
+
 func TestCloudStorage(t *gotesting.T) {
 	want := testing.NewCloudStorage(nil, "", "", "", "", "", "")
 
@@ -933,6 +1048,7 @@ func TestStateExports(t *gotesting.T) {
 		{
 			testing.State{},
 			[]string{
+				"AddCleanup",
 				"AndroidDUTLabConfig",
 				"AttachErrorHandlers",
 				"ChromeOSDUTLabConfig",
@@ -952,18 +1068,23 @@ func TestStateExports(t *gotesting.T) {
 				"FixtFillValue",
 				"FixtValue",
 				"HasError",
+				"HasSoftwareFeature",
 				"Log",
 				"Logf",
 				"Meta",
 				"OutDir",
 				"Param",
+				"Phone",
 				"PreValue",
 				"PushedFilesToDUT",
 				"RPCHint",
+				"Rand",
 				"RequiredVar",
 				"Run",
 				"ServiceDeps",
+				"Servo",
 				"SoftwareDeps",
+				"TempDir",
 				"TestName",
 				"VLog",
 				"VLogf",
@@ -993,11 +1114,13 @@ func TestStateExports(t *gotesting.T) {
 				"Log",
 				"Logf",
 				"OutDir",
+				"Phone",
 				"PreCtx",
 				"PushedFilesToDUT",
 				"RPCHint",
 				"RequiredVar",
 				"ServiceDeps",
+				"Servo",
 				"SoftwareDeps",
 				"TestName",
 				"VLog",
@@ -1030,11 +1153,13 @@ func TestStateExports(t *gotesting.T) {
 				"Logf",
 				"MaxSysMsgLogSize",
 				"OutDir",
+				"Phone",
 				"Purgeable",
 				"PushedFilesToDUT",
 				"RPCHint",
 				"RequiredVar",
 				"ServiceDeps",
+				"Servo",
 				"SoftwareDeps",
 				"TestName",
 				"VLog",
@@ -1069,9 +1194,11 @@ func TestStateExports(t *gotesting.T) {
 				"Param",
 				"ParentFillValue",
 				"ParentValue",
+				"Phone",
 				"PushedFilesToDUT",
 				"RPCHint",
 				"RequiredVar",
+				"Servo",
 				"VLog",
 				"VLogf",
 				"Var",
@@ -1098,8 +1225,10 @@ func TestStateExports(t *gotesting.T) {
 				"Log",
 				"Logf",
 				"OutDir",
+				"Phone",
 				"PushedFilesToDUT",
 				"RPCHint",
+				"Servo",
 				"TestContext",
 				"TestName",
 				"VLog",
@@ -1167,6 +1296,65 @@ func TestHardwareFeatures(t *gotesting.T) {
 	}
 }
 
+func TestHasSoftwareFeature(t *gotesting.T) {
+	test := &testing.TestInstance{SoftSoftwareDeps: []string{"foo", "bar"}}
+	cfg := &testing.RuntimeConfig{Features: map[string]*frameworkprotocol.DUTFeatures{
+		"": {
+			Software: &frameworkprotocol.SoftwareFeatures{
+				Available:   []string{"foo", "baz"},
+				Unavailable: []string{"bar"},
+			},
+		},
+	}}
+	var out outputSink
+	root := testing.NewTestEntityRoot(test, cfg, &out, testing.NewEntityCondition())
+	s := root.NewTestState()
+
+	if !s.HasSoftwareFeature("foo") {
+		t.Error("HasSoftwareFeature(foo) = false; want true")
+	}
+	if s.HasSoftwareFeature("bar") {
+		t.Error("HasSoftwareFeature(bar) = true; want false")
+	}
+
+	// Undeclared features should cause a panic even if they are available.
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("HasSoftwareFeature(baz) did not panic")
+			}
+		}()
+		s.HasSoftwareFeature("baz")
+	}()
+}
+
+func TestRand(t *gotesting.T) {
+	randInts := func(name string, seed int64) []int64 {
+		var out outputSink
+		root := testing.NewTestEntityRoot(&testing.TestInstance{Name: name}, &testing.RuntimeConfig{Seed: seed}, &out, testing.NewEntityCondition())
+		s := root.NewTestState()
+		var res []int64
+		s.Run(context.Background(), "sub", func(ctx context.Context, s *testing.State) {
+			res = append(res, s.Rand().Int63())
+		})
+		for i := 0; i < 3; i++ {
+			res = append(res, s.Rand().Int63())
+		}
+		return res
+	}
+
+	got := randInts("pkg.Test", 1)
+	if diff := cmp.Diff(randInts("pkg.Test", 1), got); diff != "" {
+		t.Errorf("Rand returned different numbers for the same seed (-got +want):\n%s", diff)
+	}
+	if cmp.Equal(randInts("pkg.Test", 2), got) {
+		t.Error("Rand returned the same numbers for different seeds")
+	}
+	if cmp.Equal(randInts("pkg.Other", 1), got) {
+		t.Error("Rand returned the same numbers for different tests")
+	}
+}
+
 func TestOnError(t *gotesting.T) {
 	var out outputSink
 	root := testing.NewTestEntityRoot(&testing.TestInstance{Timeout: time.Minute}, &testing.RuntimeConfig{}, &out, testing.NewEntityCondition())
@@ -110,6 +110,25 @@ type Test struct {
 	// for more information about dependencies.
 	SoftwareDeps []string
 
+	// SoftSoftwareDeps lists software features that the test can make use of
+	// but does not require. Unlike SoftwareDeps, the test is run even if they
+	// are not satisfied by the DUT, and it can branch on their availability
+	// with State.HasSoftwareFeature. Features available on the DUT are
+	// recorded in test results.
+	SoftSoftwareDeps []string
+
+	// RequiresKernelCmdline lists kernel command line parameters the DUT must
+	// be booted with, either as "name" to require the parameter with any value
+	// or as "name=value". Unlike SoftwareDeps, they are checked by the test
+	// bundle on the DUT just before the test starts, and the test is skipped
+	// if any of them is missing. Only local tests can set this field.
+	RequiresKernelCmdline []string
+
+	// RequiresEnv lists environment variables the local test bundle must be
+	// run with, either as "name" to require the variable to be set or as
+	// "name=value". They are checked like RequiresKernelCmdline.
+	RequiresEnv []string
+
 	// HardwareDeps describes hardware features and setup that are required to run the test.
 	HardwareDeps hwdep.Deps
 
@@ -125,6 +144,29 @@ type Test struct {
 	// This field is serialized as an integer nanosecond count.
 	Timeout time.Duration
 
+	// ExpectedDuration contains the typical duration for which Func runs.
+	// It is used by the tast command to estimate the remaining time of a run,
+	// and a warning is reported if the test takes more than twice as long.
+	// Unlike Timeout, it is never enforced by aborting the test.
+	// This field is serialized as an integer nanosecond count.
+	ExpectedDuration time.Duration
+
+	// SetupTimeout contains the maximum duration of the setup stage run before
+	// Func, i.e. the pre-test hook of the test bundle and the preparation of
+	// Pre. It is not deducted from Timeout. If not specified, each step of the
+	// stage is limited by its default timeout. Pre-test hooks of fixtures are
+	// limited by the timeouts declared by the fixtures instead.
+	SetupTimeout time.Duration
+
+	// CleanupTimeout contains the maximum duration of the cleanup stage run
+	// after Func, i.e. functions added with State.AddCleanup, the closure of Pre
+	// and the post-test hook of the test bundle. It is not deducted from
+	// Timeout, so cleanups get their own window even if Func uses up its time.
+	// If not specified, each step of the stage is limited by its default
+	// timeout. Post-test hooks of fixtures are limited by the timeouts declared
+	// by the fixtures instead.
+	CleanupTimeout time.Duration
+
 	// Params lists the Param structs for parameterized tests.
 	Params []Param
 
@@ -132,6 +174,13 @@ type Test struct {
 	// will access. This field is valid only for remote tests.
 	ServiceDeps []string
 
+	// Resources lists names of DUT resources (e.g. "audio", "network", "gpu")
+	// that the test needs exclusive access to. If the test bundle is allowed
+	// to run tests in parallel, tests declaring disjoint sets of resources may
+	// run concurrently, while tests declaring overlapping resources are
+	// serialized. Tests declaring no resources always run alone.
+	Resources []string
+
 	// SoftwareDepsForAll lists software features of all DUTs that
 	// are required to run the test.
 	// It is a map of companion roles and software features.
@@ -178,6 +227,10 @@ type Test struct {
 	// needs, which can influence the behavior of the test and its outcome.
 	// Not required for the legacy pipeline.
 	VariantCategory string
+
+	// scenarioSteps lists steps of a scenario test. It is set by
+	// testing.AddScenario.
+	scenarioSteps []ScenarioStep
 }
 
 // LifeCycle aligns with the TestCaseMetadata proto value of LifeCycle.
@@ -254,6 +307,10 @@ type Param struct {
 	// in addition to SoftwareDeps in the enclosing Test.
 	ExtraSoftwareDeps []string
 
+	// ExtraSoftSoftwareDeps lists software features that the test case for this
+	// param can make use of, in addition to SoftSoftwareDeps in the enclosing Test.
+	ExtraSoftSoftwareDeps []string
+
 	// ExtraHardwareDeps describes hardware features and setup that are required to run the test for this
 	// param, in addition to HardwareDeps in the enclosing Test.
 	ExtraHardwareDeps hwdep.Deps
@@ -284,6 +341,10 @@ type Param struct {
 	// Can only be set if the enclosing test doesn't have one already set.
 	Timeout time.Duration
 
+	// ExpectedDuration contains the typical duration for which Func runs.
+	// Can only be set if the enclosing test doesn't have one already set.
+	ExpectedDuration time.Duration
+
 	// Val is the value which can be retrieved from testing.State.Param() method.
 	Val interface{}
 
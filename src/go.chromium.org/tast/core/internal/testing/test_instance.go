@@ -24,6 +24,8 @@ import (
 	"go.chromium.org/tast/core/internal/dep"
 	"go.chromium.org/tast/core/internal/packages"
 	"go.chromium.org/tast/core/internal/protocol"
+
+	frameworkprotocol "go.chromium.org/tast/core/framework/protocol"
 )
 
 const (
@@ -82,9 +84,29 @@ type TestInstance struct {
 	// implemented, it is needed.
 	HardwareDeps map[string]dep.HardwareDeps
 	ServiceDeps  []string
+	Resources    []string
 	Pre          Precondition
 	Fixture      string
 	Timeout      time.Duration
+	// ExpectedDuration is the typical duration of the test. It is zero if
+	// unknown.
+	ExpectedDuration time.Duration
+	// SetupTimeout and CleanupTimeout are the maximum durations of the setup
+	// and cleanup stages around Func. They are zero if not limited.
+	SetupTimeout   time.Duration
+	CleanupTimeout time.Duration
+	// SoftSoftwareDeps lists software features of the primary DUT that the
+	// test can make use of but does not require.
+	SoftSoftwareDeps []string
+	// RequiresKernelCmdline lists kernel command line parameters the DUT
+	// must be booted with.
+	RequiresKernelCmdline []string
+	// RequiresEnv lists environment variables the test bundle must be run
+	// with.
+	RequiresEnv []string
+	// ScenarioSteps lists steps of a scenario test registered with
+	// testing.AddScenario. It is empty for other tests.
+	ScenarioSteps []ScenarioStep
 
 	// Bundle is the name of the test bundle this test belongs to.
 	// This field is empty initially, and later set when the test is added
@@ -183,6 +205,23 @@ func newTestInstance(t *Test, p *Param) (*TestInstance, error) {
 		swDeps[key] = append(swDeps[key], element...)
 	}
 
+	softSwDeps := append(append([]string(nil), t.SoftSoftwareDeps...), p.ExtraSoftSoftwareDeps...)
+	for _, d := range softSwDeps {
+		for _, hd := range swDeps[""] {
+			if d == hd {
+				//lint:ignore ST1005 "SoftwareDeps" is a field name and should be capitalized
+				return nil, fmt.Errorf("SoftwareDeps and SoftSoftwareDeps should not contain the same feature %q", d)
+			}
+		}
+	}
+
+	if err := validateEnvDeps("RequiresKernelCmdline", t.RequiresKernelCmdline); err != nil {
+		return nil, err
+	}
+	if err := validateEnvDeps("RequiresEnv", t.RequiresEnv); err != nil {
+		return nil, err
+	}
+
 	hwDeps := make(map[string]dep.HardwareDeps)
 	hwDeps[""] = dep.MergeHardwareDeps(t.HardwareDeps, p.ExtraHardwareDeps)
 
@@ -251,40 +290,77 @@ func newTestInstance(t *Test, p *Param) (*TestInstance, error) {
 		return nil, fmt.Errorf("timeout is negative (%v)", timeout)
 	}
 
+	expectedDuration := t.ExpectedDuration
+	if p.ExpectedDuration != 0 {
+		if t.ExpectedDuration != 0 {
+			return nil, errors.New("Param has ExpectedDuration specified and its enclosing Test also has ExpectedDuration specified, but only one can be specified")
+		}
+		expectedDuration = p.ExpectedDuration
+	}
+	if expectedDuration < 0 {
+		return nil, fmt.Errorf("expected duration is negative (%v)", expectedDuration)
+	}
+	if timeout != 0 && expectedDuration > timeout {
+		return nil, fmt.Errorf("expected duration (%v) exceeds timeout (%v)", expectedDuration, timeout)
+	}
+	if t.SetupTimeout < 0 {
+		return nil, fmt.Errorf("setup timeout is negative (%v)", t.SetupTimeout)
+	}
+	if t.CleanupTimeout < 0 {
+		return nil, fmt.Errorf("cleanup timeout is negative (%v)", t.CleanupTimeout)
+	}
+
 	PrivateAttr := append(append([]string(nil), t.PrivateAttr...), p.ExtraPrivateAttr...)
 	searchFlags := append(append([]*protocol.StringPair(nil), t.SearchFlags...), p.ExtraSearchFlags...)
 	if err := validateSearchFlags(searchFlags); err != nil {
 		return nil, err
 	}
 
+	if err := validateResources(t.Resources); err != nil {
+		return nil, err
+	}
+
+	if err := validateScenarioSteps(t.scenarioSteps); err != nil {
+		return nil, err
+	}
+
 	var testBedDeps []string
 	testBedDeps = append(testBedDeps, t.TestBedDeps...)
 	testBedDeps = append(testBedDeps, p.ExtraTestBedDeps...)
 
 	return &TestInstance{
-		Name:            name,
-		Pkg:             info.pkg,
-		Val:             p.Val,
-		Func:            t.Func,
-		Desc:            t.Desc,
-		Contacts:        append([]string(nil), t.Contacts...),
-		Attr:            attrs,
-		PrivateAttr:     PrivateAttr,
-		SearchFlags:     searchFlags,
-		Data:            data,
-		Vars:            append([]string(nil), t.Vars...),
-		VarDeps:         append([]string(nil), t.VarDeps...),
-		SoftwareDeps:    swDeps,
-		HardwareDeps:    hwDeps,
-		ServiceDeps:     append([]string(nil), t.ServiceDeps...),
-		Pre:             pre,
-		Fixture:         fixt,
-		Timeout:         timeout,
-		TestBedDeps:     testBedDeps,
-		Requirements:    requirements,
-		BugComponent:    bugComponent,
-		LifeCycleStage:  lifeCycleStage,
-		VariantCategory: variantCategory,
+		Name:             name,
+		Pkg:              info.pkg,
+		Val:              p.Val,
+		Func:             t.Func,
+		Desc:             t.Desc,
+		Contacts:         append([]string(nil), t.Contacts...),
+		Attr:             attrs,
+		PrivateAttr:      PrivateAttr,
+		SearchFlags:      searchFlags,
+		Data:             data,
+		Vars:             append([]string(nil), t.Vars...),
+		VarDeps:          append([]string(nil), t.VarDeps...),
+		SoftwareDeps:     swDeps,
+		HardwareDeps:     hwDeps,
+		ServiceDeps:      append([]string(nil), t.ServiceDeps...),
+		Resources:        append([]string(nil), t.Resources...),
+		Pre:              pre,
+		Fixture:          fixt,
+		Timeout:          timeout,
+		ExpectedDuration: expectedDuration,
+		SetupTimeout:     t.SetupTimeout,
+		CleanupTimeout:   t.CleanupTimeout,
+		SoftSoftwareDeps: softSwDeps,
+		TestBedDeps:      testBedDeps,
+		Requirements:     requirements,
+		BugComponent:     bugComponent,
+		LifeCycleStage:   lifeCycleStage,
+		VariantCategory:  variantCategory,
+
+		RequiresKernelCmdline: append([]string(nil), t.RequiresKernelCmdline...),
+		RequiresEnv:           append([]string(nil), t.RequiresEnv...),
+		ScenarioSteps:         append([]ScenarioStep(nil), t.scenarioSteps...),
 	}, nil
 }
 
@@ -348,6 +424,22 @@ func validateName(name string) error {
 	return nil
 }
 
+// setNamespace renames t to "<namespace>/<name>" and updates its
+// automatically-added name attribute accordingly.
+func (t *TestInstance) setNamespace(namespace string) error {
+	name := namespace + "/" + t.Name
+	if err := validateName(t.Name); err != nil {
+		return err
+	}
+	for i, a := range t.Attr {
+		if a == testNameAttrPrefix+t.Name {
+			t.Attr[i] = testNameAttrPrefix + name
+		}
+	}
+	t.Name = name
+	return nil
+}
+
 // testWordRegexp validates an individual word in a test function name.
 // See checkFuncNameAgainstFilename for details.
 var testWordRegexp = regexp.MustCompile("^[A-Z0-9]+[a-z0-9]*[A-Z0-9]*$")
@@ -460,6 +552,37 @@ func validateSearchFlags(searchFlags []*protocol.StringPair) error {
 	return nil
 }
 
+// envDepRegexp validates a requirement declared in Test.RequiresKernelCmdline
+// or Test.RequiresEnv.
+var envDepRegexp = regexp.MustCompile(`^[^\s=]+(=\S*)?$`)
+
+// validateEnvDeps validates requirements declared in field.
+func validateEnvDeps(field string, reqs []string) error {
+	for _, r := range reqs {
+		if !envDepRegexp.MatchString(r) {
+			return fmt.Errorf("%s entry %q should be either \"name\" or \"name=value\" without spaces", field, r)
+		}
+	}
+	return nil
+}
+
+// resourceNameRegexp validates a name of a resource declared in Test.Resources.
+var resourceNameRegexp = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+func validateResources(resources []string) error {
+	seen := make(map[string]struct{})
+	for _, r := range resources {
+		if !resourceNameRegexp.MatchString(r) {
+			return fmt.Errorf("resource name %q should match %s", r, resourceNameRegexp)
+		}
+		if _, ok := seen[r]; ok {
+			return fmt.Errorf("resource %q is declared twice", r)
+		}
+		seen[r] = struct{}{}
+	}
+	return nil
+}
+
 var validVarLastPartRE = regexp.MustCompile("[a-zA-Z][0-9A-Za-z_]*")
 
 func validateVars(category, name string, vars []string) error {
@@ -502,6 +625,10 @@ func (t *TestInstance) clone() *TestInstance {
 		ret.SoftwareDeps[key] = append([]string(nil), element...)
 	}
 	ret.ServiceDeps = append([]string(nil), ret.ServiceDeps...)
+	ret.Resources = append([]string(nil), ret.Resources...)
+	ret.SoftSoftwareDeps = append([]string(nil), ret.SoftSoftwareDeps...)
+	ret.RequiresKernelCmdline = append([]string(nil), ret.RequiresKernelCmdline...)
+	ret.RequiresEnv = append([]string(nil), ret.RequiresEnv...)
 	return ret
 }
 
@@ -509,6 +636,21 @@ func (t *TestInstance) String() string {
 	return t.Name
 }
 
+// AvailableSoftSoftwareDeps returns software features in SoftSoftwareDeps
+// that are available per features of the primary DUT.
+func (t *TestInstance) AvailableSoftSoftwareDeps(features *frameworkprotocol.SoftwareFeatures) []string {
+	var available []string
+	for _, d := range t.SoftSoftwareDeps {
+		for _, f := range features.GetAvailable() {
+			if d == f {
+				available = append(available, d)
+				break
+			}
+		}
+	}
+	return available
+}
+
 // Deps dependencies of this test.
 func (t *TestInstance) Deps() *dep.Deps {
 	swDepsForAll := make(map[string]dep.SoftwareDeps)
@@ -523,6 +665,14 @@ func (t *TestInstance) Deps() *dep.Deps {
 	}
 }
 
+// EnvDeps returns requirements of this test on the environment it runs in.
+func (t *TestInstance) EnvDeps() *dep.EnvDeps {
+	return &dep.EnvDeps{
+		KernelCmdline: append([]string(nil), t.RequiresKernelCmdline...),
+		Env:           append([]string(nil), t.RequiresEnv...),
+	}
+}
+
 // Proto converts test metadata of TestInstance into a protobuf message.
 func (t *TestInstance) Proto() *api.TestCaseMetadata {
 	var tags []*api.TestCase_Tag
@@ -612,6 +762,10 @@ func (t *TestInstance) Constraints() *EntityConstraints {
 
 // EntityProto a protocol buffer message representation of TestInstance.
 func (t *TestInstance) EntityProto() *protocol.Entity {
+	var expectedDuration *durationpb.Duration
+	if t.ExpectedDuration != 0 {
+		expectedDuration = durationpb.New(t.ExpectedDuration)
+	}
 	return &protocol.Entity{
 		Type:        protocol.EntityType_TEST,
 		Name:        t.Name,
@@ -628,11 +782,14 @@ func (t *TestInstance) EntityProto() *protocol.Entity {
 			Emails: append([]string(nil), t.Contacts...),
 		},
 		LegacyData: &protocol.EntityLegacyData{
-			Timeout:      durationpb.New(t.Timeout),
-			Variables:    append([]string(nil), t.Vars...),
-			VariableDeps: append([]string(nil), t.VarDeps...),
-			SoftwareDeps: append([]string(nil), t.SoftwareDeps[""]...),
-			Bundle:       t.Bundle,
+			Timeout:          durationpb.New(t.Timeout),
+			Variables:        append([]string(nil), t.Vars...),
+			VariableDeps:     append([]string(nil), t.VarDeps...),
+			SoftwareDeps:     append([]string(nil), t.SoftwareDeps[""]...),
+			Bundle:           t.Bundle,
+			ExpectedDuration: expectedDuration,
+			SoftSoftwareDeps: append([]string(nil), t.SoftSoftwareDeps...),
+			ScenarioSteps:    t.scenarioStepNames(),
 		},
 		TestBedDeps:  append([]string(nil), t.TestBedDeps...),
 		Requirements: append([]string(nil), t.Requirements...),
@@ -640,6 +797,15 @@ func (t *TestInstance) EntityProto() *protocol.Entity {
 	}
 }
 
+// scenarioStepNames returns names of scenario steps of t.
+func (t *TestInstance) scenarioStepNames() []string {
+	var names []string
+	for _, step := range t.ScenarioSteps {
+		names = append(names, step.Name)
+	}
+	return names
+}
+
 // WriteTestsAsProto exports test metadata in the protobuf format defined by infra.
 func WriteTestsAsProto(w io.Writer, ts []*TestInstance) error {
 	var result api.TestCaseMetadataList
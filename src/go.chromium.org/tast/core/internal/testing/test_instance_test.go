@@ -93,6 +93,7 @@ func TestInstantiate(t *gotesting.T) {
 		HardwareDeps:    hwdep.D(hwdep.Model("model1", "model2")),
 		Timeout:         123 * time.Second,
 		ServiceDeps:     []string{"svc1", "svc2"},
+		Resources:       []string{"audio", "gpu"},
 		TestBedDeps:     []string{"dep:one", "dep:two", "dep:three"},
 		Requirements:    []string{"one", "two"},
 		BugComponent:    "b:123xyz",
@@ -121,6 +122,7 @@ func TestInstantiate(t *gotesting.T) {
 		SoftwareDeps:    map[string][]string{"": {"dep1", "dep2"}},
 		Timeout:         123 * time.Second,
 		ServiceDeps:     []string{"svc1", "svc2"},
+		Resources:       []string{"audio", "gpu"},
 		TestBedDeps:     []string{"dep:one", "dep:two", "dep:three"},
 		Requirements:    []string{"one", "two"},
 		BugComponent:    "b:123xyz",
@@ -760,6 +762,103 @@ func TestInstantiateParamsTimeout(t *gotesting.T) {
 	}
 }
 
+func TestInstantiateParamsExpectedDuration(t *gotesting.T) {
+	const expectedDuration = 30 * time.Second
+
+	// Duplicated fields should be rejected.
+	if _, err := instantiate(&Test{
+		Func:             TESTINSTANCETEST,
+		ExpectedDuration: expectedDuration,
+		Params: []Param{{
+			ExpectedDuration: expectedDuration,
+		}},
+	}); err == nil {
+		t.Error("instantiate succeeded unexpectedly for duplicated ExpectedDuration")
+	}
+
+	// OK if the field in the base test is unset.
+	got, err := instantiate(&Test{
+		Func: TESTINSTANCETEST,
+		Params: []Param{{
+			ExpectedDuration: expectedDuration,
+		}},
+	})
+	if err != nil {
+		t.Fatal("Failed to instantiate test: ", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("Got %d test instances; want 1", len(got))
+	}
+	if got[0].ExpectedDuration != expectedDuration {
+		t.Fatalf("TestInstance.ExpectedDuration = %v; want %v", got[0].ExpectedDuration, expectedDuration)
+	}
+}
+
+func TestInstantiateSoftSoftwareDeps(t *gotesting.T) {
+	got, err := instantiate(&Test{
+		Func:             TESTINSTANCETEST,
+		SoftwareDeps:     []string{"hard"},
+		SoftSoftwareDeps: []string{"soft1"},
+		Params: []Param{{
+			ExtraSoftSoftwareDeps: []string{"soft2"},
+		}},
+	})
+	if err != nil {
+		t.Fatal("Failed to instantiate test: ", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("Got %d test instances; want 1", len(got))
+	}
+	if diff := cmp.Diff(got[0].SoftSoftwareDeps, []string{"soft1", "soft2"}); diff != "" {
+		t.Errorf("TestInstance.SoftSoftwareDeps mismatch (-got +want):\n%s", diff)
+	}
+
+	features := &frameworkprotocol.SoftwareFeatures{Available: []string{"hard", "soft2"}, Unavailable: []string{"soft1"}}
+	if diff := cmp.Diff(got[0].AvailableSoftSoftwareDeps(features), []string{"soft2"}); diff != "" {
+		t.Errorf("AvailableSoftSoftwareDeps mismatch (-got +want):\n%s", diff)
+	}
+
+	// A feature can't be both required and optional.
+	if _, err := instantiate(&Test{
+		Func:             TESTINSTANCETEST,
+		SoftwareDeps:     []string{"dup"},
+		SoftSoftwareDeps: []string{"dup"},
+	}); err == nil {
+		t.Error("instantiate succeeded unexpectedly for a feature in both SoftwareDeps and SoftSoftwareDeps")
+	}
+}
+
+func TestInstantiateEnvDeps(t *gotesting.T) {
+	got, err := instantiate(&Test{
+		Func:                  TESTINSTANCETEST,
+		RequiresKernelCmdline: []string{"cros_debug", "lsm.module_locking=0"},
+		RequiresEnv:           []string{"HOME"},
+	})
+	if err != nil {
+		t.Fatal("Failed to instantiate test: ", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("Got %d test instances; want 1", len(got))
+	}
+	want := &dep.EnvDeps{
+		KernelCmdline: []string{"cros_debug", "lsm.module_locking=0"},
+		Env:           []string{"HOME"},
+	}
+	if diff := cmp.Diff(got[0].EnvDeps(), want); diff != "" {
+		t.Errorf("EnvDeps mismatch (-got +want):\n%s", diff)
+	}
+
+	for _, tc := range []*Test{
+		{Func: TESTINSTANCETEST, RequiresKernelCmdline: []string{""}},
+		{Func: TESTINSTANCETEST, RequiresKernelCmdline: []string{"a b"}},
+		{Func: TESTINSTANCETEST, RequiresEnv: []string{"=value"}},
+	} {
+		if _, err := instantiate(tc); err == nil {
+			t.Errorf("instantiate succeeded unexpectedly for RequiresKernelCmdline %q and RequiresEnv %q", tc.RequiresKernelCmdline, tc.RequiresEnv)
+		}
+	}
+}
+
 func TestRelativeDataDir(t *gotesting.T) {
 	const pkg = "a/b/c"
 	got := RelativeDataDir(pkg)
@@ -957,6 +1056,84 @@ func TestInstantiateNegativeTimeout(t *gotesting.T) {
 	}
 }
 
+func TestInstantiateNegativeStageTimeouts(t *gotesting.T) {
+	if _, err := instantiate(&Test{
+		Func:         TESTINSTANCETEST,
+		SetupTimeout: -1 * time.Second,
+	}); err == nil {
+		t.Error("Didn't get error with negative setup timeout")
+	}
+	if _, err := instantiate(&Test{
+		Func:           TESTINSTANCETEST,
+		CleanupTimeout: -1 * time.Second,
+	}); err == nil {
+		t.Error("Didn't get error with negative cleanup timeout")
+	}
+}
+
+func TestInstantiateInvalidExpectedDuration(t *gotesting.T) {
+	if _, err := instantiate(&Test{
+		Func:             TESTINSTANCETEST,
+		ExpectedDuration: -1 * time.Second,
+	}); err == nil {
+		t.Error("Didn't get error with negative expected duration")
+	}
+	if _, err := instantiate(&Test{
+		Func:             TESTINSTANCETEST,
+		Timeout:          time.Minute,
+		ExpectedDuration: 2 * time.Minute,
+	}); err == nil {
+		t.Error("Didn't get error with expected duration exceeding timeout")
+	}
+}
+
+func TestInstantiateInvalidResources(t *gotesting.T) {
+	for _, resources := range [][]string{
+		{""},
+		{"Audio"},
+		{"audio-out"},
+		{"audio", "audio"},
+	} {
+		if _, err := instantiate(&Test{
+			Func:      TESTINSTANCETEST,
+			Resources: resources,
+		}); err == nil {
+			t.Errorf("Didn't get error with resources %q", resources)
+		}
+	}
+}
+
+func TestInstantiateScenario(t *gotesting.T) {
+	step := func(ctx context.Context, s *State, state interface{}) {}
+	test := &Test{Func: TESTINSTANCETEST}
+	SetScenarioSteps(test, []ScenarioStep{{Name: "enroll", Func: step}, {Name: "verify", Func: step}})
+	got, err := instantiate(test)
+	if err != nil {
+		t.Fatal("Failed to instantiate test: ", err)
+	}
+	want := []string{"enroll", "verify"}
+	if diff := cmp.Diff(got[0].EntityProto().GetLegacyData().GetScenarioSteps(), want); diff != "" {
+		t.Errorf("Scenario steps mismatch (-got +want):\n%s", diff)
+	}
+
+	for _, names := range [][]string{
+		{""},
+		{"Enroll"},
+		{"enroll-device"},
+		{"enroll", "enroll"},
+	} {
+		var steps []ScenarioStep
+		for _, name := range names {
+			steps = append(steps, ScenarioStep{Name: name, Func: step})
+		}
+		test := &Test{Func: TESTINSTANCETEST}
+		SetScenarioSteps(test, steps)
+		if _, err := instantiate(test); err == nil {
+			t.Errorf("Didn't get error with scenario steps %q", names)
+		}
+	}
+}
+
 func TestInstantiateDuplicatedParamName(t *gotesting.T) {
 	if _, err := instantiate(&Test{
 		Func: TESTINSTANCETEST,
@@ -1267,21 +1444,22 @@ func TestVarDeps(t *gotesting.T) {
 
 func TestTestInstanceEntityProto(t *gotesting.T) {
 	test := &TestInstance{
-		Name:         "pkg.Test",
-		Pkg:          "chromiumos/foo/bar",
-		Val:          "somevalue",
-		Func:         TESTINSTANCETEST,
-		Desc:         "Description",
-		Contacts:     []string{"me@example.com"},
-		Attr:         []string{"attr1", "attr2"},
-		Data:         []string{"foo.txt"},
-		Vars:         []string{"var1", "var2"},
-		VarDeps:      []string{"vardep1"},
-		SoftwareDeps: map[string]dep.SoftwareDeps{"": []string{"dep1", "dep2"}},
-		ServiceDeps:  []string{"svc1", "svc2"},
-		Fixture:      "fixt",
-		Timeout:      time.Hour,
-		Bundle:       "bundle",
+		Name:             "pkg.Test",
+		Pkg:              "chromiumos/foo/bar",
+		Val:              "somevalue",
+		Func:             TESTINSTANCETEST,
+		Desc:             "Description",
+		Contacts:         []string{"me@example.com"},
+		Attr:             []string{"attr1", "attr2"},
+		Data:             []string{"foo.txt"},
+		Vars:             []string{"var1", "var2"},
+		VarDeps:          []string{"vardep1"},
+		SoftwareDeps:     map[string]dep.SoftwareDeps{"": []string{"dep1", "dep2"}},
+		ServiceDeps:      []string{"svc1", "svc2"},
+		Fixture:          "fixt",
+		Timeout:          time.Hour,
+		ExpectedDuration: 10 * time.Minute,
+		Bundle:           "bundle",
 	}
 
 	got := test.EntityProto()
@@ -1299,11 +1477,12 @@ func TestTestInstanceEntityProto(t *gotesting.T) {
 			Emails: []string{"me@example.com"},
 		},
 		LegacyData: &protocol.EntityLegacyData{
-			Variables:    []string{"var1", "var2"},
-			VariableDeps: []string{"vardep1"},
-			SoftwareDeps: []string{"dep1", "dep2"},
-			Timeout:      durationpb.New(time.Hour),
-			Bundle:       "bundle",
+			Variables:        []string{"var1", "var2"},
+			VariableDeps:     []string{"vardep1"},
+			SoftwareDeps:     []string{"dep1", "dep2"},
+			Timeout:          durationpb.New(time.Hour),
+			Bundle:           "bundle",
+			ExpectedDuration: durationpb.New(10 * time.Minute),
 		},
 	}
 	if diff := cmp.Diff(got, want, protocmp.Transform()); diff != "" {
@@ -5,15 +5,22 @@
 package testing
 
 import (
+	"bytes"
 	"fmt"
+	"os"
+	"path/filepath"
 	"runtime"
+	"runtime/pprof"
 	"strings"
+	"time"
 
+	"go.chromium.org/tast/core/errors"
 	"go.chromium.org/tast/core/errors/stack"
 	"go.chromium.org/tast/core/internal/protocol"
 )
 
-// NewError returns a new Error object containing reason rsn.
+// NewError returns a new Error object containing reason rsn. The category of
+// err, if any, is set to the returned Error.
 // skipFrames contains the number of frames to skip to get the code that's reporting
 // the error: the caller should pass 0 to report its own frame, 1 to skip just its own frame,
 // 2 to additionally skip the frame that called it, and so on.
@@ -37,5 +44,89 @@ func NewError(err error, fullMsg, lastMsg string, skipFrames int) *protocol.Erro
 			Line:  int64(ln),
 			Stack: strings.ToValidUTF8(trace, ""),
 		},
+		Category: protocol.Error_Category(protocol.Error_Category_value[string(errors.CategoryOf(err))]),
 	}
 }
+
+// GoroutineDumpFile is the name of a file in an entity's output directory to
+// which goroutine dumps are saved on fatal errors and timeouts.
+const GoroutineDumpFile = "goroutines.txt"
+
+// maxAttachedGoroutines is the maximum number of goroutines included in
+// goroutine dumps attached to errors.
+const maxAttachedGoroutines = 10
+
+// DumpGoroutines returns stack traces of all goroutines in the current process.
+func DumpGoroutines() ([]byte, error) {
+	p := pprof.Lookup("goroutine")
+	if p == nil {
+		return nil, errors.New("goroutine pprof not found")
+	}
+	var buf bytes.Buffer
+	if err := p.WriteTo(&buf, 2); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// currentGoroutineHeader returns the prefix of the header of the current
+// goroutine in goroutine dumps, e.g. "goroutine 123 ".
+func currentGoroutineHeader() string {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	header, _, _ := strings.Cut(string(buf), "[")
+	return header
+}
+
+// TrimGoroutineDump returns max goroutines in dump, which should be returned
+// by DumpGoroutines. The goroutine whose header starts with first, e.g.
+// "goroutine 123 ", is placed first if it exists, followed by the others in
+// the original order. A note is appended if some goroutines are omitted.
+func TrimGoroutineDump(dump []byte, max int, first string) string {
+	gs := strings.Split(strings.TrimSpace(string(dump)), "\n\n")
+	for i, g := range gs {
+		if first != "" && strings.HasPrefix(g, first) {
+			gs = append(append([]string{g}, gs[:i]...), gs[i+1:]...)
+			break
+		}
+	}
+	if len(gs) <= max {
+		return strings.Join(gs, "\n\n")
+	}
+	return fmt.Sprintf("%s\n\n... %d more goroutine(s) omitted", strings.Join(gs[:max], "\n\n"), len(gs)-max)
+}
+
+// AttachGoroutineDump captures stack traces of all goroutines, appends them to
+// GoroutineDumpFile in outDir with a header containing reason, and attaches
+// their trimmed version to the stack of e, starting with the goroutine calling
+// this function. The file is not written if outDir is empty.
+func AttachGoroutineDump(e *protocol.Error, outDir, reason string) error {
+	dump, err := DumpGoroutines()
+	if err != nil {
+		return err
+	}
+
+	if outDir != "" {
+		f, err := os.OpenFile(filepath.Join(outDir, GoroutineDumpFile), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if _, err := fmt.Fprintf(f, "=== %s: %s\n\n%s\n", time.Now().Format(time.RFC3339Nano), reason, dump); err != nil {
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+
+	if e.Location == nil {
+		e.Location = &protocol.ErrorLocation{}
+	}
+	header := "Goroutine dump"
+	if outDir != "" {
+		header = fmt.Sprintf("Goroutine dump (full dump in %s)", GoroutineDumpFile)
+	}
+	e.Location.Stack += fmt.Sprintf("\n\n%s:\n%s", header, strings.ToValidUTF8(TrimGoroutineDump(dump, maxAttachedGoroutines, currentGoroutineHeader()), ""))
+	return nil
+}
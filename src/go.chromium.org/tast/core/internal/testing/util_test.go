@@ -5,9 +5,11 @@
 package testing
 
 import (
-	"errors"
 	"strings"
 	"testing"
+
+	"go.chromium.org/tast/core/errors"
+	"go.chromium.org/tast/core/internal/protocol"
 )
 
 func TestNewErrorInvalidUTF8(t *testing.T) {
@@ -35,3 +37,53 @@ func TestNewErrorInvalidUTF8(t *testing.T) {
 		}
 	}
 }
+
+func TestNewErrorCategory(t *testing.T) {
+	for _, tc := range []struct {
+		err    error
+		expect protocol.Error_Category
+	}{
+		{nil, protocol.Error_CATEGORY_UNSPECIFIED},
+		{errors.New("meow"), protocol.Error_CATEGORY_UNSPECIFIED},
+		{errors.WithCategory(errors.New("meow"), errors.CategoryInfra), protocol.Error_INFRA},
+		{errors.Wrap(errors.WithCategory(errors.New("meow"), errors.CategoryTestBug), "woof"), protocol.Error_TEST_BUG},
+		{errors.WithCategory(errors.New("meow"), "UNKNOWN"), protocol.Error_CATEGORY_UNSPECIFIED},
+	} {
+		if got := NewError(tc.err, "msg", "msg", 0).GetCategory(); got != tc.expect {
+			t.Errorf("NewError(%v) returned category %v; want %v", tc.err, got, tc.expect)
+		}
+	}
+}
+
+func TestTrimGoroutineDump(t *testing.T) {
+	const dump = "goroutine 1 [running]:\nmain.a()\n\ngoroutine 2 [chan receive]:\nmain.b()\n\ngoroutine 3 [select]:\nmain.c()\n"
+	for _, tc := range []struct {
+		max   int
+		first string
+		want  string
+	}{
+		{3, "", "goroutine 1 [running]:\nmain.a()\n\ngoroutine 2 [chan receive]:\nmain.b()\n\ngoroutine 3 [select]:\nmain.c()"},
+		{1, "", "goroutine 1 [running]:\nmain.a()\n\n... 2 more goroutine(s) omitted"},
+		{2, "goroutine 3 ", "goroutine 3 [select]:\nmain.c()\n\ngoroutine 1 [running]:\nmain.a()\n\n... 1 more goroutine(s) omitted"},
+		// A goroutine whose ID merely starts with the same digits is not moved.
+		{1, "goroutine 30 ", "goroutine 1 [running]:\nmain.a()\n\n... 2 more goroutine(s) omitted"},
+	} {
+		if got := TrimGoroutineDump([]byte(dump), tc.max, tc.first); got != tc.want {
+			t.Errorf("TrimGoroutineDump(dump, %d, %q) = %q; want %q", tc.max, tc.first, got, tc.want)
+		}
+	}
+}
+
+func TestAttachGoroutineDumpWithoutOutDir(t *testing.T) {
+	e := &protocol.Error{Reason: "fail"}
+	if err := AttachGoroutineDump(e, "", "fail"); err != nil {
+		t.Fatal("AttachGoroutineDump failed: ", err)
+	}
+	stack := e.GetLocation().GetStack()
+	if !strings.Contains(stack, "Goroutine dump:") {
+		t.Errorf("Stack does not contain goroutine dump:\n%s", stack)
+	}
+	if strings.Contains(stack, GoroutineDumpFile) {
+		t.Errorf("Stack mentions %s not written:\n%s", GoroutineDumpFile, stack)
+	}
+}
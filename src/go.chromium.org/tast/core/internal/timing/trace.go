@@ -0,0 +1,124 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package timing
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// traceEvent is a complete event in the Chrome trace event format.
+// See https://docs.google.com/document/d/1CvAClvFfyA5R-PhYUmn5OOQtYMH4h6I0nSsKchNAySU
+// for details.
+type traceEvent struct {
+	Name string `json:"name"`
+	Ph   string `json:"ph"`
+	Ts   int64  `json:"ts"`  // start time in microseconds
+	Dur  int64  `json:"dur"` // duration in microseconds
+	Pid  int    `json:"pid"`
+	Tid  int    `json:"tid"`
+}
+
+// traceFile represents the JSON schema of a trace file.
+type traceFile struct {
+	TraceEvents     []*traceEvent `json:"traceEvents"`
+	DisplayTimeUnit string        `json:"displayTimeUnit"`
+}
+
+// traceWriter converts stages to trace events.
+type traceWriter struct {
+	base   time.Time // time corresponding to timestamp 0
+	now    time.Time // end time of stages not ended yet
+	events []*traceEvent
+	lanes  []time.Time // lanes[i] is the end time of the last stage on thread i+1
+}
+
+// WriteTrace writes timing information to w in the Chrome trace event format,
+// which can be viewed as a flame chart in chrome://tracing or Perfetto UI.
+// Each stage is written as a complete event. Stages are placed on the thread
+// of their parent, and stages overlapping with their earlier siblings, e.g.
+// ones run concurrently, are placed on other threads.
+func (l *Log) WriteTrace(w io.Writer) error {
+	l.Root.mu.Lock()
+	children := append([]*Stage(nil), l.Root.Children...)
+	l.Root.mu.Unlock()
+
+	tw := &traceWriter{now: now()}
+	for _, c := range children {
+		if st, _ := c.times(tw.now); tw.base.IsZero() || st.Before(tw.base) {
+			tw.base = st
+		}
+	}
+	tw.lanes = []time.Time{{}}
+	tw.addChildren(children, 1, time.Time{})
+
+	b, err := json.Marshal(&traceFile{TraceEvents: tw.events, DisplayTimeUnit: "ms"})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// addChildren adds sibling stages whose parent is on thread tid and started
+// at start.
+func (tw *traceWriter) addChildren(children []*Stage, tid int, start time.Time) {
+	lastEnd := start
+	for _, c := range children {
+		st, _ := c.times(tw.now)
+		ctid := tid
+		if st.Before(lastEnd) {
+			ctid = tw.freeLane(st)
+		} else {
+			_, lastEnd = c.times(tw.now)
+		}
+		tw.add(c, ctid)
+	}
+}
+
+// add adds s and its descendants placing s on thread tid.
+func (tw *traceWriter) add(s *Stage, tid int) {
+	s.mu.Lock()
+	children := append([]*Stage(nil), s.Children...)
+	s.mu.Unlock()
+
+	st, et := s.times(tw.now)
+	if et.After(tw.lanes[tid-1]) {
+		tw.lanes[tid-1] = et
+	}
+	tw.events = append(tw.events, &traceEvent{
+		Name: s.Name,
+		Ph:   "X",
+		Ts:   st.Sub(tw.base).Microseconds(),
+		Dur:  et.Sub(st).Microseconds(),
+		Pid:  1,
+		Tid:  tid,
+	})
+	tw.addChildren(children, tid, st)
+}
+
+// freeLane returns a thread having no stage running at t, adding a new thread
+// if needed.
+func (tw *traceWriter) freeLane(t time.Time) int {
+	for i, end := range tw.lanes {
+		if !end.After(t) {
+			return i + 1
+		}
+	}
+	tw.lanes = append(tw.lanes, time.Time{})
+	return len(tw.lanes)
+}
+
+// times returns the start and end time of s. now is returned as the end time
+// if s has not ended yet.
+func (s *Stage) times(now time.Time) (start, end time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.EndTime.IsZero() {
+		return s.StartTime, now
+	}
+	return s.StartTime, s.EndTime
+}
@@ -0,0 +1,79 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package timing
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWriteTrace(t *testing.T) {
+	at := func(sec int64) time.Time { return time.Unix(1000+sec, 0) }
+	stage := func(name string, start, end int64, children ...*Stage) *Stage {
+		return &Stage{Name: name, StartTime: at(start), EndTime: at(end), Children: children}
+	}
+
+	l := &Log{Root: &Stage{Children: []*Stage{
+		stage("exec", 0, 10,
+			stage("connect", 0, 1),
+			stage("pull_a", 2, 5),
+			// Overlaps with pull_a, so it is placed on another thread.
+			stage("pull_b", 3, 6,
+				stage("copy", 3, 4)),
+			stage("collect_sys_info", 7, 9)),
+	}}}
+
+	var b bytes.Buffer
+	if err := l.WriteTrace(&b); err != nil {
+		t.Fatal("WriteTrace failed: ", err)
+	}
+	var got traceFile
+	if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+		t.Fatalf("Failed to unmarshal %q: %v", b.String(), err)
+	}
+
+	const sec = 1000000
+	want := traceFile{
+		TraceEvents: []*traceEvent{
+			{Name: "exec", Ph: "X", Ts: 0, Dur: 10 * sec, Pid: 1, Tid: 1},
+			{Name: "connect", Ph: "X", Ts: 0, Dur: 1 * sec, Pid: 1, Tid: 1},
+			{Name: "pull_a", Ph: "X", Ts: 2 * sec, Dur: 3 * sec, Pid: 1, Tid: 1},
+			{Name: "pull_b", Ph: "X", Ts: 3 * sec, Dur: 3 * sec, Pid: 1, Tid: 2},
+			{Name: "copy", Ph: "X", Ts: 3 * sec, Dur: 1 * sec, Pid: 1, Tid: 2},
+			{Name: "collect_sys_info", Ph: "X", Ts: 7 * sec, Dur: 2 * sec, Pid: 1, Tid: 1},
+		},
+		DisplayTimeUnit: "ms",
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("WriteTrace wrote unexpected trace (-got +want):\n%s", diff)
+	}
+}
+
+func TestWriteTraceUnfinished(t *testing.T) {
+	var fc fakeClock
+	fc.install()
+	defer fc.uninstall()
+
+	l := NewLog()
+	l.StartTop("stage") // started at 0 and never ended
+
+	var b bytes.Buffer
+	if err := l.WriteTrace(&b); err != nil {
+		t.Fatal("WriteTrace failed: ", err)
+	}
+	var got traceFile
+	if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+		t.Fatalf("Failed to unmarshal %q: %v", b.String(), err)
+	}
+	// An unfinished stage ends at the time WriteTrace is called.
+	want := []*traceEvent{{Name: "stage", Ph: "X", Ts: 0, Dur: 1000000, Pid: 1, Tid: 1}}
+	if diff := cmp.Diff(got.TraceEvents, want); diff != "" {
+		t.Errorf("WriteTrace wrote unexpected trace (-got +want):\n%s", diff)
+	}
+}
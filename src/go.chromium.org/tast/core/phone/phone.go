@@ -0,0 +1,150 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package phone provides access to Android phones attached to a test run as
+// companion devices. Phones are controlled with adb over the network from the
+// host running remote tests.
+package phone
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"go.chromium.org/tast/core/errors"
+	"go.chromium.org/tast/core/shutil"
+)
+
+// DefaultPort is the port adbd listens on by default in TCP/IP mode.
+const DefaultPort = 5555
+
+// adbPath is the adb executable. It is looked up in PATH.
+const adbPath = "adb"
+
+// Device is an Android phone reachable with adb over the network.
+type Device struct {
+	serial string // adb serial of the device, "host:port"
+}
+
+// New returns a Device for the phone at spec, either "host:port" or "host" to
+// use DefaultPort. It does not connect to the phone until Connect is called.
+func New(spec string) (*Device, error) {
+	serial, err := parseSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	return &Device{serial: serial}, nil
+}
+
+// parseSpec validates a phone spec and returns it as an adb serial.
+func parseSpec(spec string) (string, error) {
+	if spec == "" {
+		return "", errors.New("empty phone spec")
+	}
+	if !strings.Contains(spec, ":") || strings.HasSuffix(spec, "]") {
+		return net.JoinHostPort(strings.Trim(spec, "[]"), strconv.Itoa(DefaultPort)), nil
+	}
+	host, port, err := net.SplitHostPort(spec)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid phone spec %q", spec)
+	}
+	if _, err := strconv.Atoi(port); err != nil {
+		return "", errors.Errorf("invalid phone port in %q", spec)
+	}
+	return net.JoinHostPort(host, port), nil
+}
+
+// Serial returns the adb serial of the phone, e.g. "192.168.0.2:5555".
+func (d *Device) Serial() string { return d.serial }
+
+// Connect connects adb to the phone and waits until it is ready.
+func (d *Device) Connect(ctx context.Context) error {
+	out, err := exec.CommandContext(ctx, adbPath, "connect", d.serial).CombinedOutput()
+	if err != nil {
+		return errors.Wrapf(err, "adb connect %s: %s", d.serial, bytes.TrimSpace(out))
+	}
+	// adb connect exits with 0 even if it fails to connect.
+	if !bytes.Contains(out, []byte("connected to")) {
+		return errors.Errorf("adb connect %s: %s", d.serial, bytes.TrimSpace(out))
+	}
+	if err := d.Command(ctx, "wait-for-device").Run(); err != nil {
+		return errors.Wrapf(err, "waiting for %s", d.serial)
+	}
+	return nil
+}
+
+// Close disconnects adb from the phone.
+func (d *Device) Close(ctx context.Context) error {
+	if out, err := exec.CommandContext(ctx, adbPath, "disconnect", d.serial).CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "adb disconnect %s: %s", d.serial, bytes.TrimSpace(out))
+	}
+	return nil
+}
+
+// Command returns a command running adb with args against the phone.
+func (d *Device) Command(ctx context.Context, args ...string) *exec.Cmd {
+	return exec.CommandContext(ctx, adbPath, append([]string{"-s", d.serial}, args...)...)
+}
+
+// Shell runs a shell command made of args on the phone and returns its
+// standard output. args are quoted so that they are passed to the command on
+// the phone as is.
+func (d *Device) Shell(ctx context.Context, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := d.Command(ctx, "shell", shutil.EscapeSlice(args))
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return out, errors.Wrapf(err, "%s on %s: %s", shutil.EscapeSlice(args), d.serial, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return out, nil
+}
+
+// Push copies the local file or directory src to dst on the phone.
+func (d *Device) Push(ctx context.Context, src, dst string) error {
+	if out, err := d.Command(ctx, "push", src, dst).CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "pushing %s to %s on %s: %s", src, dst, d.serial, bytes.TrimSpace(out))
+	}
+	return nil
+}
+
+// Pull copies the file or directory src on the phone to the local dst.
+func (d *Device) Pull(ctx context.Context, src, dst string) error {
+	if out, err := d.Command(ctx, "pull", src, dst).CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "pulling %s on %s to %s: %s", src, d.serial, dst, bytes.TrimSpace(out))
+	}
+	return nil
+}
+
+// CaptureLogcat clears the logcat buffer of the phone and starts saving logcat
+// messages to path, typically a file under the output directory of a test so
+// that it is attached to the test results. The returned function stops the
+// capture.
+func (d *Device) CaptureLogcat(ctx context.Context, path string) (stop func() error, err error) {
+	if out, err := d.Command(ctx, "logcat", "-c").CombinedOutput(); err != nil {
+		return nil, errors.Wrapf(err, "clearing logcat on %s: %s", d.serial, bytes.TrimSpace(out))
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	// The capture outlives ctx of the caller if it is shorter, e.g. in
+	// fixtures, so it is stopped only by the returned function.
+	cmd := d.Command(context.Background(), "logcat", "-v", "threadtime")
+	cmd.Stdout = f
+	cmd.Stderr = f
+	if err := cmd.Start(); err != nil {
+		f.Close()
+		return nil, errors.Wrapf(err, "starting logcat on %s", d.serial)
+	}
+	return func() error {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return f.Close()
+	}, nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package phone_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go.chromium.org/tast/core/phone"
+)
+
+// fakeADBScript is a fake adb that records its arguments to $ADB_LOG.
+const fakeADBScript = `#!/bin/sh
+echo "$*" >> "$ADB_LOG"
+case "$*" in
+connect\ *) echo "connected to $2" ;;
+*shell\ echo\ *) echo hello ;;
+*logcat\ -v\ *) echo "I/tag: message"; exec sleep 60 ;;
+esac
+`
+
+// setUpFakeADB installs a fake adb to PATH and returns the path of the file
+// its invocations are recorded to.
+func setUpFakeADB(t *testing.T) string {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "adb"), []byte(fakeADBScript), 0755); err != nil {
+		t.Fatal(err)
+	}
+	logPath := filepath.Join(dir, "log")
+	t.Setenv("PATH", dir+":"+os.Getenv("PATH"))
+	t.Setenv("ADB_LOG", logPath)
+	return logPath
+}
+
+func readLog(t *testing.T, logPath string) []string {
+	b, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSpace(string(b)), "\n")
+}
+
+func TestNew(t *testing.T) {
+	for _, tc := range []struct {
+		spec string
+		want string
+	}{
+		{"192.168.0.2", "192.168.0.2:5555"},
+		{"192.168.0.2:1234", "192.168.0.2:1234"},
+		{"[::1]", "[::1]:5555"},
+		{"[::1]:1234", "[::1]:1234"},
+	} {
+		d, err := phone.New(tc.spec)
+		if err != nil {
+			t.Errorf("New(%q) failed: %v", tc.spec, err)
+			continue
+		}
+		if got := d.Serial(); got != tc.want {
+			t.Errorf("New(%q).Serial() = %q; want %q", tc.spec, got, tc.want)
+		}
+	}
+
+	for _, spec := range []string{"", "host:port", "host:1:2"} {
+		if _, err := phone.New(spec); err == nil {
+			t.Errorf("New(%q) unexpectedly succeeded", spec)
+		}
+	}
+}
+
+func TestCommands(t *testing.T) {
+	logPath := setUpFakeADB(t)
+	ctx := context.Background()
+
+	d, err := phone.New("phone")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Connect(ctx); err != nil {
+		t.Fatal("Connect failed: ", err)
+	}
+	out, err := d.Shell(ctx, "echo", "a b")
+	if err != nil {
+		t.Fatal("Shell failed: ", err)
+	}
+	if got, want := string(out), "hello\n"; got != want {
+		t.Errorf("Shell returned %q; want %q", got, want)
+	}
+	if err := d.Push(ctx, "/local/src", "/sdcard/dst"); err != nil {
+		t.Error("Push failed: ", err)
+	}
+	if err := d.Pull(ctx, "/sdcard/src", "/local/dst"); err != nil {
+		t.Error("Pull failed: ", err)
+	}
+	if err := d.Close(ctx); err != nil {
+		t.Error("Close failed: ", err)
+	}
+
+	want := []string{
+		"connect phone:5555",
+		"-s phone:5555 wait-for-device",
+		"-s phone:5555 shell echo 'a b'",
+		"-s phone:5555 push /local/src /sdcard/dst",
+		"-s phone:5555 pull /sdcard/src /local/dst",
+		"disconnect phone:5555",
+	}
+	if diff := cmp.Diff(readLog(t, logPath), want); diff != "" {
+		t.Errorf("adb invocations mismatch (-got +want):\n%s", diff)
+	}
+}
+
+func TestCaptureLogcat(t *testing.T) {
+	setUpFakeADB(t)
+	ctx := context.Background()
+
+	d, err := phone.New("phone")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "logcat.txt")
+	stop, err := d.CaptureLogcat(ctx, path)
+	if err != nil {
+		t.Fatal("CaptureLogcat failed: ", err)
+	}
+
+	// Wait for logcat to write a message before stopping it.
+	for start := time.Now(); time.Since(start) < 10*time.Second; time.Sleep(10 * time.Millisecond) {
+		if b, err := os.ReadFile(path); err == nil && len(b) > 0 {
+			break
+		}
+	}
+	if err := stop(); err != nil {
+		t.Error("Stopping logcat failed: ", err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), "I/tag: message\n"; got != want {
+		t.Errorf("Captured logcat = %q; want %q", got, want)
+	}
+}
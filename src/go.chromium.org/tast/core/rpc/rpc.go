@@ -9,6 +9,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"time"
 
 	"google.golang.org/grpc"
 
@@ -55,6 +56,52 @@ func (c *Client) Close(ctx context.Context, opts ...ssh.RunOption) error {
 //		return err
 //	}
 func Dial(ctx context.Context, d *dut.DUT, h *testing.RPCHint) (*Client, error) {
+	return DialWithOptions(ctx, d, h, nil)
+}
+
+// DialOptions contains options for DialWithOptions.
+type DialOptions struct {
+	// KeepaliveInterval is the interval of keepalive pings sent to the DUT
+	// to detect broken connections. Keepalive pings are disabled if it is
+	// zero. It must not be shorter than 10 seconds.
+	KeepaliveInterval time.Duration
+
+	// KeepaliveTimeout is the duration to wait for a response to a keepalive
+	// ping before considering the connection to be lost. A default value is
+	// used if it is zero.
+	KeepaliveTimeout time.Duration
+
+	// MaxMessageSize is the maximum size in bytes of gRPC messages. A default
+	// value is used if it is zero.
+	MaxMessageSize int
+
+	// Reconnect specifies whether to reconnect to the DUT and restart the
+	// test bundle executable automatically when the connection is lost, e.g.
+	// on transient SSH failures during long tests.
+	// Method calls in progress when the connection is lost still fail, but
+	// later method calls on the same Conn are sent to the new executable.
+	// Note that states kept in memory by gRPC services are lost on reconnect
+	// unless they are restored by OnReconnect.
+	Reconnect bool
+
+	// OnReconnect, if not nil, is called with the gRPC connection after
+	// reconnecting to the DUT, so that states of gRPC services can be
+	// restored, e.g. by calling methods initializing them again. Other
+	// method calls wait until it returns, and fail if it returns an error.
+	// It is ignored unless Reconnect is true.
+	OnReconnect func(ctx context.Context, conn *grpc.ClientConn) error
+
+	// MaxReconnects is the maximum number of reconnects. There is no limit if
+	// it is zero. It is ignored unless Reconnect is true.
+	MaxReconnects int
+}
+
+// DialWithOptions is similar to Dial, but allows customizing the connection
+// with opts. opts can be nil to use the default options.
+//
+// If opts.Reconnect is true, d is reconnected as needed when the gRPC
+// connection is lost.
+func DialWithOptions(ctx context.Context, d *dut.DUT, h *testing.RPCHint, opts *DialOptions) (*Client, error) {
 	exe, err := os.Executable()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get self bundle name")
@@ -69,7 +116,30 @@ func Dial(ctx context.Context, d *dut.DUT, h *testing.RPCHint) (*Client, error)
 			Vars: testing.ExtractTestVars(h),
 		},
 	}
-	cl, err := rpc.DialSSH(ctx, d.Conn(), bundlePath, req, false)
+
+	if opts == nil {
+		opts = &DialOptions{}
+	}
+	copts := &rpc.ClientOptions{
+		KeepaliveInterval: opts.KeepaliveInterval,
+		KeepaliveTimeout:  opts.KeepaliveTimeout,
+		MaxMessageSize:    opts.MaxMessageSize,
+		MaxRedials:        opts.MaxReconnects,
+		OnRedial:          opts.OnReconnect,
+	}
+	var reconnect func(ctx context.Context) (*ssh.Conn, error)
+	if opts.Reconnect {
+		reconnect = func(ctx context.Context) (*ssh.Conn, error) {
+			if !d.Connected(ctx) {
+				if err := d.Connect(ctx); err != nil {
+					return nil, err
+				}
+			}
+			return d.Conn(), nil
+		}
+	}
+
+	cl, err := rpc.DialSSHWithOptions(ctx, d.Conn(), bundlePath, req, false, copts, reconnect)
 	if err != nil {
 		return nil, err
 	}
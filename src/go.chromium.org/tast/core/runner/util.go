@@ -8,7 +8,9 @@ package runner
 import (
 	"os"
 
+	"go.chromium.org/tast/core/internal/crash"
 	"go.chromium.org/tast/core/internal/crosbundle"
+	"go.chromium.org/tast/core/internal/logs"
 	"go.chromium.org/tast/core/internal/runner"
 )
 
@@ -21,6 +23,8 @@ func RunLocal() int {
 		GetDUTInfo:              crosbundle.GetDUTInfo,
 		GetSysInfoState:         crosbundle.GetSysInfoState,
 		CollectSysInfo:          crosbundle.CollectSysInfo,
+		CrashDirs:               crash.DefaultDirs(),
+		SystemLogCollector:      logs.NewSystemLogCollector(),
 		BundleType:              runner.Local,
 		PrivateBundlesStampPath: "/usr/local/share/tast/.private-bundles-downloaded",
 		DeprecatedDirectRunDefaults: runner.DeprecatedDirectRunConfig{
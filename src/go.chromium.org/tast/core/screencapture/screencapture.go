@@ -0,0 +1,185 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package screencapture captures the screen of the DUT.
+//
+// Functions in this package run the screenshot command available on test
+// images, so they can be called only on the DUT, e.g. in local tests. Remote
+// tests can capture the screen with the ScreenCapture gRPC service in
+// go.chromium.org/tast/core/framework/protocol instead.
+package screencapture
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go.chromium.org/tast/core/errors"
+)
+
+// screenshotCmd is the name of the command to take screenshots, provided by
+// screen-capture-utils.
+const screenshotCmd = "screenshot"
+
+// Screenshot saves a screenshot of the DUT's screen to path in PNG format.
+func Screenshot(ctx context.Context, path string) error {
+	cmd := exec.CommandContext(ctx, screenshotCmd, path)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return errors.Wrapf(err, "%s failed: %s", screenshotCmd, msg)
+		}
+		return errors.Wrapf(err, "%s failed", screenshotCmd)
+	}
+	return nil
+}
+
+// Recorder records the DUT's screen into an animated GIF.
+//
+// Frames are taken periodically with the screenshot command and kept in a
+// temporary directory until the recording is saved with Save. Close must be
+// called to remove them after use.
+type Recorder struct {
+	dir      string
+	interval time.Duration
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+
+	// The following fields are written by the recording goroutine, and can be
+	// read only after done is closed.
+	frames []string
+	err    error
+}
+
+// StartRecording starts recording the DUT's screen. A frame is taken every
+// interval. If maxFrames is positive, older frames are discarded so that the
+// recording keeps at most maxFrames latest frames.
+//
+// The recording continues until Recorder.Stop is called, ctx is canceled, or
+// taking a frame fails. A frame being taken when Stop is called is completed
+// so that the recording ends with the screen at that time.
+func StartRecording(ctx context.Context, interval time.Duration, maxFrames int) (*Recorder, error) {
+	if interval <= 0 {
+		return nil, errors.Errorf("invalid interval %v", interval)
+	}
+	dir, err := os.MkdirTemp("", "tast_screenrecord.")
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Recorder{dir: dir, interval: interval, stop: make(chan struct{}), done: make(chan struct{})}
+	go func() {
+		defer close(r.done)
+		r.record(ctx, maxFrames)
+	}()
+	return r, nil
+}
+
+// record takes frames until the recording is stopped, ctx is canceled or
+// taking a frame fails.
+func (r *Recorder) record(ctx context.Context, maxFrames int) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for i := 0; ; i++ {
+		path := filepath.Join(r.dir, fmt.Sprintf("frame-%05d.png", i))
+		if err := Screenshot(ctx, path); err != nil {
+			// Errors caused by canceling ctx are not errors.
+			if ctx.Err() == nil {
+				r.err = errors.Wrapf(err, "failed to take frame %d", i)
+			}
+			os.Remove(path)
+			return
+		}
+		r.frames = append(r.frames, path)
+		if maxFrames > 0 && len(r.frames) > maxFrames {
+			os.Remove(r.frames[0])
+			r.frames = r.frames[1:]
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop stops the recording and returns the number of frames recorded. If
+// taking a frame failed, it returns the number of frames taken before the
+// failure together with the error. It is safe to call Stop multiple times.
+func (r *Recorder) Stop() (int, error) {
+	r.stopOnce.Do(func() { close(r.stop) })
+	<-r.done
+	return len(r.frames), r.err
+}
+
+// Save stops the recording and saves it to path as an animated GIF. Frames
+// taken before a failure of the recording are saved even if Stop returns an
+// error.
+func (r *Recorder) Save(path string) error {
+	if n, _ := r.Stop(); n == 0 {
+		return errors.New("no frame recorded")
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := encodeGIF(f, r.frames, r.interval); err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+	return f.Close()
+}
+
+// Close stops the recording and removes frames kept for it.
+func (r *Recorder) Close() error {
+	r.Stop()
+	return os.RemoveAll(r.dir)
+}
+
+// encodeGIF encodes PNG images at paths into an animated GIF written to w,
+// showing each frame for delay.
+func encodeGIF(w io.Writer, paths []string, delay time.Duration) error {
+	anim := &gif.GIF{}
+	for _, p := range paths {
+		img, err := decodePNG(p)
+		if err != nil {
+			return err
+		}
+		pimg := image.NewPaletted(img.Bounds(), palette.Plan9)
+		draw.FloydSteinberg.Draw(pimg, img.Bounds(), img, img.Bounds().Min)
+		anim.Image = append(anim.Image, pimg)
+		// GIF delays are in hundredths of a second.
+		anim.Delay = append(anim.Delay, int(delay/(10*time.Millisecond)))
+	}
+	return gif.EncodeAll(w, anim)
+}
+
+func decodePNG(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode %s", filepath.Base(path))
+	}
+	return img, nil
+}
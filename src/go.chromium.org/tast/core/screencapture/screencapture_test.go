@@ -0,0 +1,113 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package screencapture_test
+
+import (
+	"context"
+	"image/gif"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.chromium.org/tast/core/internal/screencapturetest"
+	"go.chromium.org/tast/core/screencapture"
+	"go.chromium.org/tast/core/testutil"
+)
+
+func TestScreenshot(t *testing.T) {
+	screencapturetest.InstallFakeScreenshot(t)
+
+	td := testutil.TempDir(t)
+	defer os.RemoveAll(td)
+
+	path := filepath.Join(td, "screenshot.png")
+	if err := screencapture.Screenshot(context.Background(), path); err != nil {
+		t.Fatal("Screenshot failed: ", err)
+	}
+	if b, err := os.ReadFile(path); err != nil {
+		t.Error(err)
+	} else if string(b) != screencapturetest.FakeImage {
+		t.Errorf("Screenshot saved %q; want %q", string(b), screencapturetest.FakeImage)
+	}
+}
+
+func TestScreenshotError(t *testing.T) {
+	screencapturetest.InstallFakeScreenshotScript(t, "echo no display >&2; exit 1")
+
+	td := testutil.TempDir(t)
+	defer os.RemoveAll(td)
+
+	if err := screencapture.Screenshot(context.Background(), filepath.Join(td, "screenshot.png")); err == nil {
+		t.Error("Screenshot unexpectedly succeeded")
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	screencapturetest.InstallFakeScreenshot(t)
+
+	td := testutil.TempDir(t)
+	defer os.RemoveAll(td)
+
+	const (
+		interval  = 20 * time.Millisecond
+		maxFrames = 3
+	)
+	r, err := screencapture.StartRecording(context.Background(), interval, maxFrames)
+	if err != nil {
+		t.Fatal("StartRecording failed: ", err)
+	}
+	defer r.Close()
+	// Wait until older frames are discarded.
+	time.Sleep(10 * interval)
+	if n, err := r.Stop(); err != nil {
+		t.Fatal("Stop failed: ", err)
+	} else if n != maxFrames {
+		t.Errorf("Stop returned %d frames; want %d", n, maxFrames)
+	}
+
+	path := filepath.Join(td, "record.gif")
+	if err := r.Save(path); err != nil {
+		t.Fatal("Save failed: ", err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	anim, err := gif.DecodeAll(f)
+	if err != nil {
+		t.Fatal("Failed to decode the recording: ", err)
+	}
+	if len(anim.Image) != maxFrames {
+		t.Errorf("Recording has %d frames; want %d", len(anim.Image), maxFrames)
+	}
+	for i, d := range anim.Delay {
+		if want := int(interval / (10 * time.Millisecond)); d != want {
+			t.Errorf("Frame %d has delay %d; want %d", i, d, want)
+		}
+	}
+}
+
+func TestRecorderError(t *testing.T) {
+	screencapturetest.InstallFakeScreenshotScript(t, "exit 1")
+
+	td := testutil.TempDir(t)
+	defer os.RemoveAll(td)
+
+	r, err := screencapture.StartRecording(context.Background(), time.Millisecond, 0)
+	if err != nil {
+		t.Fatal("StartRecording failed: ", err)
+	}
+	defer r.Close()
+	// Give the recorder time to fail on the first frame.
+	time.Sleep(100 * time.Millisecond)
+	if n, err := r.Stop(); err == nil {
+		t.Errorf("Stop unexpectedly succeeded with %d frames", n)
+	}
+	if err := r.Save(filepath.Join(td, "record.gif")); err == nil {
+		t.Error("Save unexpectedly succeeded without frames")
+	}
+}
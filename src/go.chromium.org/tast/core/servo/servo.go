@@ -0,0 +1,219 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package servo provides a client of servod, the daemon controlling a servo
+// board attached to a DUT, for use by remote tests.
+// More details on servo: https://www.chromium.org/chromium-os/servo
+package servo
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.chromium.org/tast/core/errors"
+	"go.chromium.org/tast/core/internal/logging"
+	"go.chromium.org/tast/core/internal/minidriver/servo/xmlrpc"
+)
+
+// DefaultPort is the port servod listens on by default.
+const DefaultPort = 9999
+
+// powerStateTimeout is the timeout of setting power_state. It can be slow
+// because some boards hold down the power button for several seconds.
+const powerStateTimeout = 30 * time.Second
+
+// UART identifies a UART console exposed by servod.
+type UART string
+
+// UART consoles commonly exposed by servod.
+const (
+	CPUUART UART = "cpu"
+	ECUART  UART = "ec"
+	GSCUART UART = "cr50"
+)
+
+// Servo is a client of servod.
+type Servo struct {
+	rpc *xmlrpc.XMLRpc
+
+	removedWatchdogs []string
+}
+
+// New creates a client of servod at spec, either "host:port" or "host" to use
+// DefaultPort. It does not connect to servod until a method is called.
+func New(spec string) (*Servo, error) {
+	host, port, err := parseSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	return &Servo{rpc: xmlrpc.New(host, port)}, nil
+}
+
+// parseSpec splits a servod spec into a host and a port.
+func parseSpec(spec string) (host string, port int, err error) {
+	if spec == "" {
+		return "", 0, errors.New("empty servo spec")
+	}
+	if !strings.Contains(spec, ":") {
+		return spec, DefaultPort, nil
+	}
+	host, portStr, err := net.SplitHostPort(spec)
+	if err != nil {
+		return "", 0, errors.Wrapf(err, "invalid servo spec %q", spec)
+	}
+	port, err = strconv.Atoi(portStr)
+	if err != nil {
+		return "", 0, errors.Errorf("invalid servo port in %q", spec)
+	}
+	return host, port, nil
+}
+
+// Close restores servod watchdogs removed by the client.
+func (s *Servo) Close(ctx context.Context) error {
+	var firstErr error
+	for _, w := range s.removedWatchdogs {
+		if err := s.Set(ctx, "watchdog_add", w); err != nil && firstErr == nil {
+			firstErr = errors.Wrapf(err, "restoring watchdog %q", w)
+		}
+	}
+	s.removedWatchdogs = nil
+	return firstErr
+}
+
+// Get returns the value of a servod control.
+func (s *Servo) Get(ctx context.Context, ctrl string) (string, error) {
+	var value string
+	if err := s.rpc.Run(ctx, xmlrpc.NewCall("get", ctrl), &value); err != nil {
+		return "", errors.Wrapf(err, "getting servo control %q", ctrl)
+	}
+	return value, nil
+}
+
+// Set sets a servod control to value.
+func (s *Servo) Set(ctx context.Context, ctrl, value string) error {
+	return s.setTimeout(ctx, ctrl, value, 0)
+}
+
+func (s *Servo) setTimeout(ctx context.Context, ctrl, value string, timeout time.Duration) error {
+	cl := xmlrpc.NewCall("set", ctrl, value)
+	if timeout > 0 {
+		cl = xmlrpc.NewCallTimeout("set", timeout, ctrl, value)
+	}
+	if err := s.rpc.Run(ctx, cl); err != nil {
+		return errors.Wrapf(err, "setting servo control %q to %q", ctrl, value)
+	}
+	return nil
+}
+
+// Version returns the version of the servo board, e.g. "servo_v4_with_servo_micro".
+func (s *Servo) Version(ctx context.Context) (string, error) {
+	var version string
+	if err := s.rpc.Run(ctx, xmlrpc.NewCall("get_version"), &version); err != nil {
+		return "", errors.Wrap(err, "getting servo version")
+	}
+	return version, nil
+}
+
+// setPowerState sets the power_state control. Because this is disruptive, it
+// is always logged.
+func (s *Servo) setPowerState(ctx context.Context, state string) error {
+	logging.Infof(ctx, "Setting servo power_state to %q", state)
+	// Resetting the EC makes servod fail if the CCD watchdog is enabled.
+	if err := s.removeCCDWatchdog(ctx); err != nil {
+		return err
+	}
+	return s.setTimeout(ctx, "power_state", state, powerStateTimeout)
+}
+
+// removeCCDWatchdog removes the CCD watchdog of servod if the servo is
+// connected via CCD. The watchdog is restored by Close.
+func (s *Servo) removeCCDWatchdog(ctx context.Context) error {
+	servoType, err := s.Get(ctx, "servo_type")
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(servoType, "ccd") {
+		return nil
+	}
+	w := "ccd"
+	if servoType == "ccd_cr50" {
+		// SuzyQ reports as ccd_cr50 and doesn't have a watchdog named ccd.
+		w = "main"
+	}
+	for _, r := range s.removedWatchdogs {
+		if r == w {
+			return nil
+		}
+	}
+	if err := s.Set(ctx, "watchdog_remove", w); err != nil {
+		return err
+	}
+	s.removedWatchdogs = append(s.removedWatchdogs, w)
+	return nil
+}
+
+// PowerCycle powers the DUT off and on again.
+func (s *Servo) PowerCycle(ctx context.Context) error {
+	if err := s.setPowerState(ctx, "off"); err != nil {
+		return err
+	}
+	return s.setPowerState(ctx, "on")
+}
+
+// ColdReset performs a cold reset of the DUT.
+func (s *Servo) ColdReset(ctx context.Context) error {
+	return s.setPowerState(ctx, "reset")
+}
+
+// StartUARTCapture starts capturing the output of the UART console u.
+func (s *Servo) StartUARTCapture(ctx context.Context, u UART) error {
+	return s.Set(ctx, string(u)+"_uart_capture", "on")
+}
+
+// ReadUART returns the output of the UART console u captured since the last
+// call.
+func (s *Servo) ReadUART(ctx context.Context, u UART) (string, error) {
+	return s.Get(ctx, string(u)+"_uart_stream")
+}
+
+// StopUARTCapture stops capturing the output of the UART console u.
+func (s *Servo) StopUARTCapture(ctx context.Context, u UART) error {
+	return s.Set(ctx, string(u)+"_uart_capture", "off")
+}
+
+// CaptureUART starts capturing the output of UART consoles us and returns a
+// function that stops capturing and saves the output of each console to
+// "<uart>_uart.txt" under dir, typically the output directory of a test so
+// that the output is attached to the test results.
+func (s *Servo) CaptureUART(ctx context.Context, dir string, us ...UART) (stop func(ctx context.Context) error, err error) {
+	for i, u := range us {
+		if err := s.StartUARTCapture(ctx, u); err != nil {
+			for _, started := range us[:i] {
+				s.StopUARTCapture(ctx, started)
+			}
+			return nil, err
+		}
+	}
+	return func(ctx context.Context) error {
+		var firstErr error
+		for _, u := range us {
+			out, err := s.ReadUART(ctx, u)
+			if err == nil {
+				err = os.WriteFile(filepath.Join(dir, string(u)+"_uart.txt"), []byte(out), 0644)
+			}
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+			if err := s.StopUARTCapture(ctx, u); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		return firstErr
+	}, nil
+}
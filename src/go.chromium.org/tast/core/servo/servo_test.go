@@ -0,0 +1,142 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package servo_test
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go.chromium.org/tast/core/servo"
+)
+
+// fakeServod is a fake servod XML-RPC server that records set calls.
+type fakeServod struct {
+	mu       sync.Mutex
+	controls map[string]string
+	sets     []string
+}
+
+func (f *fakeServod) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var call struct {
+		MethodName string   `xml:"methodName"`
+		Params     []string `xml:"params>param>value>string"`
+	}
+	if err := xml.NewDecoder(r.Body).Decode(&call); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var ret string
+	switch call.MethodName {
+	case "get":
+		ret = fmt.Sprintf("<string>%s</string>", f.controls[call.Params[0]])
+	case "set":
+		f.sets = append(f.sets, strings.Join(call.Params, "="))
+		f.controls[call.Params[0]] = call.Params[1]
+		ret = "<boolean>1</boolean>"
+	default:
+		ret = "<string>servo_v4</string>"
+	}
+	fmt.Fprintf(w, `<?xml version="1.0"?><methodResponse><params><param><value>%s</value></param></params></methodResponse>`, ret)
+}
+
+func newFakeServo(t *testing.T, controls map[string]string) (*servo.Servo, *fakeServod) {
+	f := &fakeServod{controls: controls}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	s, err := servo.New(strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatal("New failed: ", err)
+	}
+	return s, f
+}
+
+func TestNewInvalidSpec(t *testing.T) {
+	for _, spec := range []string{"", "host:port", "host:1:2"} {
+		if _, err := servo.New(spec); err == nil {
+			t.Errorf("New(%q) unexpectedly succeeded", spec)
+		}
+	}
+	if _, err := servo.New("localhost"); err != nil {
+		t.Errorf("New(%q) failed: %v", "localhost", err)
+	}
+}
+
+func TestPowerCycleCCD(t *testing.T) {
+	ctx := context.Background()
+	s, f := newFakeServo(t, map[string]string{"servo_type": "servo_v4_with_ccd_cr50"})
+
+	if err := s.PowerCycle(ctx); err != nil {
+		t.Fatal("PowerCycle failed: ", err)
+	}
+	if err := s.ColdReset(ctx); err != nil {
+		t.Fatal("ColdReset failed: ", err)
+	}
+	if err := s.Close(ctx); err != nil {
+		t.Fatal("Close failed: ", err)
+	}
+
+	want := []string{
+		"watchdog_remove=ccd",
+		"power_state=off",
+		"power_state=on",
+		"power_state=reset",
+		"watchdog_add=ccd",
+	}
+	if diff := cmp.Diff(f.sets, want); diff != "" {
+		t.Errorf("Unexpected servo controls set (-got +want):\n%s", diff)
+	}
+}
+
+func TestCaptureUART(t *testing.T) {
+	ctx := context.Background()
+	s, f := newFakeServo(t, map[string]string{
+		"cpu_uart_stream": "kernel log",
+		"ec_uart_stream":  "ec log",
+	})
+
+	dir := t.TempDir()
+	stop, err := s.CaptureUART(ctx, dir, servo.CPUUART, servo.ECUART)
+	if err != nil {
+		t.Fatal("CaptureUART failed: ", err)
+	}
+	if err := stop(ctx); err != nil {
+		t.Fatal("Stopping UART capture failed: ", err)
+	}
+
+	want := []string{
+		"cpu_uart_capture=on",
+		"ec_uart_capture=on",
+		"cpu_uart_capture=off",
+		"ec_uart_capture=off",
+	}
+	if diff := cmp.Diff(f.sets, want); diff != "" {
+		t.Errorf("Unexpected servo controls set (-got +want):\n%s", diff)
+	}
+	for name, want := range map[string]string{
+		"cpu_uart.txt": "kernel log",
+		"ec_uart.txt":  "ec log",
+	} {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if got := string(b); got != want {
+			t.Errorf("%s = %q; want %q", name, got, want)
+		}
+	}
+}
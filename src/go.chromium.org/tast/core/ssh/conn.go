@@ -51,6 +51,10 @@ type Conn struct {
 	platform *Platform
 
 	adbDevice *gadb.Device
+
+	// forwardAgent is true if sessions request forwarding of the SSH agent
+	// set by ForwardAgent.
+	forwardAgent bool
 }
 
 // Options contains options used when connecting to an SSH server.
@@ -89,6 +93,42 @@ type Options struct {
 	Platform *Platform
 }
 
+// ForwardAgent makes commands started on s afterwards see keys in keyring
+// through an SSH agent forwarded from this process, so that they can
+// authenticate to other hosts without the private keys being copied to the
+// remote computer. The remote SSH server must allow agent forwarding.
+func (s *Conn) ForwardAgent(keyring agent.Agent) error {
+	if s.cl == nil {
+		return errors.New("agent forwarding is available only over SSH")
+	}
+	if err := agent.ForwardToAgent(s.cl, keyring); err != nil {
+		return err
+	}
+	s.forwardAgent = true
+	return nil
+}
+
+// NewKeyring returns an in-memory SSH agent holding the private key at
+// keyFile, to be passed to ForwardAgent. The agent is empty if keyFile does
+// not exist.
+func NewKeyring(keyFile string) (agent.Agent, error) {
+	keyring := agent.NewKeyring()
+	b, err := os.ReadFile(keyFile)
+	if os.IsNotExist(err) {
+		return keyring, nil
+	} else if err != nil {
+		return nil, err
+	}
+	key, err := ssh.ParseRawPrivateKey(b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", keyFile, err)
+	}
+	if err := keyring.Add(agent.AddedKey{PrivateKey: key}); err != nil {
+		return nil, err
+	}
+	return keyring, nil
+}
+
 // ConnectionType indicates the type of connection to the DUT.
 type ConnectionType int
 
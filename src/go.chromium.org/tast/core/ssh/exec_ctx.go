@@ -12,6 +12,7 @@ import (
 	"sync"
 
 	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
 
 	"go.chromium.org/tast/core/errors"
 	"go.chromium.org/tast/core/exec"
@@ -356,6 +357,11 @@ func (c *Cmd) startSession(ctx context.Context) error {
 				return err
 			}
 			sess = sshSess
+			if c.ssh.forwardAgent {
+				if err := agent.RequestAgentForwarding(sshSess); err != nil {
+					return err
+				}
+			}
 			ioIn, ioOut, ioErr, err := c.setupSession(sshSess)
 			if ioIn != nil {
 				sshSess.Stdin = ioIn
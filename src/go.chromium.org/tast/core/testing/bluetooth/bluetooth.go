@@ -0,0 +1,202 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package bluetooth provides the information of the Bluetooth controller.
+package bluetooth
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.chromium.org/tast/core/errors"
+)
+
+// DeviceID is used as a Device ID type.
+type DeviceID int32
+
+// DevInfo contains the information of the Bluetooth controller.
+type DevInfo struct {
+	// Vendor is the vendor ID seen in the modalias of the controller, e.g. "0x8087".
+	Vendor string
+	// Device is the product ID seen in the modalias of the controller, e.g. "0x0033".
+	Device string
+	// Compatible is the compatible property of controllers attached via UART.
+	// See https://www.kernel.org/doc/Documentation/devicetree/usage-model.txt.
+	Compatible string
+	// Device (enum) ID
+	ID DeviceID
+	// The device name.
+	Name string
+	// Version is the Bluetooth Core Specification version supported by the
+	// controller, e.g. "5.2". It is empty if unknown.
+	Version string
+}
+
+// Bluetooth Device IDs.
+const (
+	UnknownDevice DeviceID = iota
+	Intel7265
+	Intel8265
+	Intel9260
+	Intel9560
+	IntelAX200
+	IntelAX201
+	IntelAX210
+	IntelAX211
+	IntelBE200
+	Realtek8822C
+	Realtek8852A
+	Realtek8852B
+	MediaTekMT7921
+	QualcommQCA6174
+	QualcommWCN3990
+	QualcommWCN6750
+	QualcommWCN6855
+	Marvell88w8897
+)
+
+// DeviceNames map contains Bluetooth device names.
+var DeviceNames = map[DeviceID]string{
+	Intel7265:       "Intel 7265",
+	Intel8265:       "Intel 8265",
+	Intel9260:       "Intel 9260",
+	Intel9560:       "Intel 9560",
+	IntelAX200:      "Intel AX200",
+	IntelAX201:      "Intel AX201",
+	IntelAX210:      "Intel AX210",
+	IntelAX211:      "Intel AX211",
+	IntelBE200:      "Intel BE200",
+	Realtek8822C:    "Realtek 8822C",
+	Realtek8852A:    "Realtek 8852A",
+	Realtek8852B:    "Realtek 8852B",
+	MediaTekMT7921:  "MediaTek MT7921",
+	QualcommQCA6174: "Qualcomm Atheros QCA6174",
+	QualcommWCN3990: "Qualcomm WCN3990",
+	QualcommWCN6750: "Qualcomm WCN6750",
+	QualcommWCN6855: "Qualcomm WCN6855",
+	Marvell88w8897:  "Marvell 88W8897",
+}
+
+// LookupBTDev maps DevInfo to DeviceID. Vendor and Device are given for
+// controllers attached via USB or SDIO, and Compatible is given for ones
+// attached via UART.
+var LookupBTDev = map[DevInfo]DeviceID{
+	{Vendor: "0x8087", Device: "0x0a2a"}: Intel7265,
+	{Vendor: "0x8087", Device: "0x0a2b"}: Intel8265,
+	{Vendor: "0x8087", Device: "0x0025"}: Intel9260,
+	{Vendor: "0x8087", Device: "0x0aaa"}: Intel9560,
+	{Vendor: "0x8087", Device: "0x0029"}: IntelAX200,
+	{Vendor: "0x8087", Device: "0x0026"}: IntelAX201,
+	{Vendor: "0x8087", Device: "0x0032"}: IntelAX210,
+	{Vendor: "0x8087", Device: "0x0033"}: IntelAX211,
+	{Vendor: "0x8087", Device: "0x0036"}: IntelBE200,
+	{Vendor: "0x0bda", Device: "0xc822"}: Realtek8822C,
+	{Vendor: "0x0bda", Device: "0x2852"}: Realtek8852A,
+	{Vendor: "0x0bda", Device: "0x385a"}: Realtek8852A,
+	{Vendor: "0x0bda", Device: "0x4852"}: Realtek8852A,
+	{Vendor: "0x0bda", Device: "0x887b"}: Realtek8852B,
+	{Vendor: "0x0bda", Device: "0xb85b"}: Realtek8852B,
+	{Vendor: "0x0e8d", Device: "0x0608"}: MediaTekMT7921,
+	{Vendor: "0x0cf3", Device: "0xe300"}: QualcommQCA6174,
+	{Vendor: "0x0cf3", Device: "0xe600"}: QualcommWCN6855,
+	{Vendor: "0x02df", Device: "0x912e"}: Marvell88w8897,
+	{Compatible: "qcom,wcn3990-bt"}:      QualcommWCN3990,
+	{Compatible: "qcom,wcn6750-bt"}:      QualcommWCN6750,
+}
+
+// hciVersions maps HCI versions reported by controllers to Bluetooth Core
+// Specification versions.
+var hciVersions = map[int]string{
+	6:  "4.0",
+	7:  "4.1",
+	8:  "4.2",
+	9:  "5.0",
+	10: "5.1",
+	11: "5.2",
+	12: "5.3",
+	13: "5.4",
+	14: "6.0",
+}
+
+// usbModaliasRE and sdioModaliasRE match modaliases of USB and SDIO devices,
+// e.g. "usb:v8087p0033d0000dcE0dsc01dp01icE0isc01ip01in00" and
+// "sdio:c00v02DFp912E".
+var usbModaliasRE = regexp.MustCompile(`^usb:v([0-9A-Fa-f]{4})p([0-9A-Fa-f]{4})`)
+var sdioModaliasRE = regexp.MustCompile(`^sdio:c[0-9A-Fa-f]{2}v([0-9A-Fa-f]{4})p([0-9A-Fa-f]{4})`)
+
+// ofModaliasRE matches modaliases of devices described in the device tree,
+// e.g. "of:NbluetoothT(null)Cqcom,wcn3990-bt".
+var ofModaliasRE = regexp.MustCompile(`^of:N[^C]*T[^C]*C([^C]+)`)
+
+// parseModalias returns DevInfo filled with IDs found in modalias.
+func parseModalias(modalias string) (*DevInfo, error) {
+	if m := usbModaliasRE.FindStringSubmatch(modalias); m != nil {
+		return &DevInfo{Vendor: "0x" + strings.ToLower(m[1]), Device: "0x" + strings.ToLower(m[2])}, nil
+	}
+	if m := sdioModaliasRE.FindStringSubmatch(modalias); m != nil {
+		return &DevInfo{Vendor: "0x" + strings.ToLower(m[1]), Device: "0x" + strings.ToLower(m[2])}, nil
+	}
+	if m := ofModaliasRE.FindStringSubmatch(modalias); m != nil {
+		return &DevInfo{Compatible: m[1]}, nil
+	}
+	return nil, errors.Errorf("unsupported modalias %q", modalias)
+}
+
+// parseHCIVersion returns the Bluetooth Core Specification version for
+// the HCI version read from debugfs.
+func parseHCIVersion(s string) (string, error) {
+	v, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse HCI version %q", s)
+	}
+	version, ok := hciVersions[v]
+	if !ok {
+		return "", errors.Errorf("unknown HCI version %d", v)
+	}
+	return version, nil
+}
+
+// deviceInfo returns DevInfo of the controller hci.
+// sysfsDir and debugfsDir are usually /sys/class/bluetooth and
+// /sys/kernel/debug/bluetooth respectively.
+func deviceInfo(sysfsDir, debugfsDir, hci string) (*DevInfo, error) {
+	bs, err := os.ReadFile(filepath.Join(sysfsDir, hci, "device", "modalias"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get modalias of %s", hci)
+	}
+	dev, err := parseModalias(strings.TrimSpace(string(bs)))
+	if err != nil {
+		return nil, err
+	}
+	if d, ok := LookupBTDev[*dev]; ok {
+		dev.ID = d
+		dev.Name = DeviceNames[d]
+	}
+
+	// The HCI version is available only when debugfs is mounted. It is not
+	// an error since the device ID may still be useful.
+	if bs, err := os.ReadFile(filepath.Join(debugfsDir, hci, "hci_version")); err == nil {
+		if v, err := parseHCIVersion(string(bs)); err == nil {
+			dev.Version = v
+		}
+	}
+
+	if dev.ID == UnknownDevice && dev.Version == "" {
+		return nil, errors.Errorf("unknown %s device with vendorID=%s, productID=%s, compatible=%s",
+			hci, dev.Vendor, dev.Device, dev.Compatible)
+	}
+	return dev, nil
+}
+
+// DeviceInfo returns a public struct (DevInfo) containing the Bluetooth
+// controller information. ChromeOS supports only one Bluetooth controller,
+// thus hci0 is examined.
+// ID is UnknownDevice if the controller is not listed in LookupBTDev, but
+// Version is still set if it is known.
+func DeviceInfo() (*DevInfo, error) {
+	return deviceInfo("/sys/class/bluetooth", "/sys/kernel/debug/bluetooth", "hci0")
+}
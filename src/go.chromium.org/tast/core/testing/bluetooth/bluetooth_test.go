@@ -0,0 +1,88 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package bluetooth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDeviceInfo(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		modalias   string
+		hciVersion string // empty if debugfs is not available
+		want       *DevInfo
+	}{
+		{
+			name:       "USB",
+			modalias:   "usb:v8087p0033d0000dcE0dsc01dp01icE0isc01ip01in00\n",
+			hciVersion: "12\n",
+			want:       &DevInfo{Vendor: "0x8087", Device: "0x0033", ID: IntelAX211, Name: "Intel AX211", Version: "5.3"},
+		},
+		{
+			name:     "SDIO",
+			modalias: "sdio:c00v02DFp912E\n",
+			want:     &DevInfo{Vendor: "0x02df", Device: "0x912e", ID: Marvell88w8897, Name: "Marvell 88W8897"},
+		},
+		{
+			name:       "UART",
+			modalias:   "of:NbluetoothT(null)Cqcom,wcn3990-bt\n",
+			hciVersion: "10\n",
+			want:       &DevInfo{Compatible: "qcom,wcn3990-bt", ID: QualcommWCN3990, Name: "Qualcomm WCN3990", Version: "5.1"},
+		},
+		{
+			name:       "UnknownDevice",
+			modalias:   "usb:v1234p5678d0000\n",
+			hciVersion: "11\n",
+			want:       &DevInfo{Vendor: "0x1234", Device: "0x5678", Version: "5.2"},
+		},
+		{
+			name:     "UnknownDeviceAndVersion",
+			modalias: "usb:v1234p5678d0000\n",
+		},
+		{
+			name:     "UnsupportedBus",
+			modalias: "pci:v00008086d0000A0F0\n",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			td := t.TempDir()
+			sysfsDir := filepath.Join(td, "sys")
+			debugfsDir := filepath.Join(td, "debug")
+			if err := os.MkdirAll(filepath.Join(sysfsDir, "hci0", "device"), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(sysfsDir, "hci0", "device", "modalias"), []byte(tc.modalias), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if tc.hciVersion != "" {
+				if err := os.MkdirAll(filepath.Join(debugfsDir, "hci0"), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(debugfsDir, "hci0", "hci_version"), []byte(tc.hciVersion), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := deviceInfo(sysfsDir, debugfsDir, "hci0")
+			if tc.want == nil {
+				if err == nil {
+					t.Errorf("deviceInfo unexpectedly succeeded: %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("deviceInfo failed: ", err)
+			}
+			if diff := cmp.Diff(got, tc.want); diff != "" {
+				t.Errorf("deviceInfo returned unexpected DevInfo (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
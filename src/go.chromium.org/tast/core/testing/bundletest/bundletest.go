@@ -0,0 +1,345 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package bundletest provides a harness to run Tast test functions in
+// ordinary Go unit tests, without a DUT.
+//
+// Remote tests calling gRPC services on the DUT can be run against a fake DUT
+// created by NewFakeDUT, which serves given services in the unit test
+// process. Fixtures are not run; use WithFixtValue to substitute the value
+// returned by s.FixtValue instead. Other DUT facilities, e.g. running
+// arbitrary commands, rebooting, servo and companion DUTs, are not faked.
+//
+// Typical usage in a bundle package's _test.go file looks like:
+//
+//	func TestMyTest(t *testing.T) {
+//		res := bundletest.Run(t, "example.MyTest",
+//			bundletest.WithVars(map[string]string{"example.user": "foo"}))
+//		bundletest.CheckGolden(t, res, "testdata/my_test.golden")
+//	}
+//
+// Golden files compared by CheckGolden or by golden.Compare in test functions
+// are updated by running unit tests with "go test -updategolden".
+package bundletest
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	gotesting "testing"
+	"time"
+
+	"go.chromium.org/tast/core/dut"
+	"go.chromium.org/tast/core/errors"
+	frameworkprotocol "go.chromium.org/tast/core/framework/protocol"
+	"go.chromium.org/tast/core/internal/logging"
+	"go.chromium.org/tast/core/internal/protocol"
+	"go.chromium.org/tast/core/internal/testing"
+	"go.chromium.org/tast/core/internal/usercode"
+	"go.chromium.org/tast/core/testing/golden"
+)
+
+const (
+	// defaultTimeout is the timeout used for test instances that do not
+	// declare one.
+	defaultTimeout = time.Minute
+
+	// gracePeriod is the time to wait for a test function to return after
+	// its timeout is reached.
+	gracePeriod = 10 * time.Second
+)
+
+// updateGolden is set by "go test -updategolden" to copy actual outputs
+// mismatching golden files back to the source tree, like
+// "tast run -updategolden".
+var updateGolden = flag.Bool("updategolden", false, "copy actual outputs mismatching golden files back to the source tree")
+
+// config holds options to run a test instance.
+type config struct {
+	dataDir   string
+	outDir    string
+	vars      map[string]string
+	features  *frameworkprotocol.DUTFeatures
+	fixtValue interface{}
+	dut       *dut.DUT
+}
+
+// Option customizes the behavior of Run and RunTest.
+type Option func(cfg *config)
+
+// WithDataDir returns an option to set the directory containing data files of
+// the test. By default, the "data" subdirectory of the current directory is
+// used, which is where data files of a bundle package are usually located.
+func WithDataDir(dir string) Option {
+	return func(cfg *config) {
+		cfg.dataDir = dir
+	}
+}
+
+// WithOutDir returns an option to set the directory where the test writes
+// output files. By default, a temporary directory is used.
+func WithOutDir(dir string) Option {
+	return func(cfg *config) {
+		cfg.outDir = dir
+	}
+}
+
+// WithVars returns an option to set runtime variables passed to the test.
+func WithVars(vars map[string]string) Option {
+	return func(cfg *config) {
+		cfg.vars = vars
+	}
+}
+
+// WithFeatures returns an option to set DUT features visible to the test via
+// s.Features.
+func WithFeatures(features *frameworkprotocol.DUTFeatures) Option {
+	return func(cfg *config) {
+		cfg.features = features
+	}
+}
+
+// WithFixtValue returns an option to set a value returned by s.FixtValue,
+// which substitutes for a fixture the test depends on.
+func WithFixtValue(val interface{}) Option {
+	return func(cfg *config) {
+		cfg.fixtValue = val
+	}
+}
+
+// WithDUT returns an option to run the test as a remote test connected to d,
+// which is typically created by NewFakeDUT. s.DUT returns d, and s.RPCHint
+// returns a hint to connect to gRPC services served by d.
+func WithDUT(d *dut.DUT) Option {
+	return func(cfg *config) {
+		cfg.dut = d
+	}
+}
+
+// Error is an error reported by a test.
+type Error struct {
+	// Reason is the error message.
+	Reason string
+	// File and Line are the location where the error was reported.
+	File string
+	Line int
+}
+
+// Result is the outcome of running a test function.
+type Result struct {
+	// Logs contains messages logged by the test at the informational level.
+	Logs []string
+	// Errors contains errors reported by the test.
+	Errors []*Error
+	// OutDir is the directory where the test wrote output files.
+	OutDir string
+
+	transcript string
+}
+
+// Failed returns whether the test reported any error.
+func (r *Result) Failed() bool {
+	return len(r.Errors) > 0
+}
+
+// Transcript returns a textual representation of messages reported by the
+// test, in the order they were reported. It is suitable for comparison with
+// golden files.
+func (r *Result) Transcript() string {
+	return r.transcript
+}
+
+// Run runs the test instance named name registered to the global registry,
+// typically by testing.AddTest in init functions of the package under test.
+func Run(t *gotesting.T, name string, opts ...Option) *Result {
+	t.Helper()
+	for _, ti := range testing.GlobalRegistry().AllTests() {
+		if ti.Name == name {
+			return runInstance(t, ti, opts...)
+		}
+	}
+	t.Fatalf("Test %s is not registered", name)
+	return nil
+}
+
+// RunTest runs the instance of test, a testing.Test in
+// go.chromium.org/tast/core/testing, for the parameter named param. param
+// should be empty if test is not parameterized. Unlike Run, test does not
+// have to be registered, so it can be declared in a _test.go file. As usual,
+// the name of test.Func should match the name of the file declaring it.
+func RunTest(t *gotesting.T, test *testing.Test, param string, opts ...Option) *Result {
+	t.Helper()
+	reg := testing.NewRegistry("bundletest")
+	reg.AddTest(test)
+	if errs := reg.Errors(); len(errs) > 0 {
+		t.Fatal("Invalid test: ", errs[0])
+	}
+	for _, ti := range reg.AllTests() {
+		// Test names are in the form of "<category>.<Func>[.<param>]".
+		if parts := strings.SplitN(ti.Name, ".", 3); (len(parts) == 2 && param == "") || (len(parts) == 3 && parts[2] == param) {
+			return runInstance(t, ti, opts...)
+		}
+	}
+	t.Fatalf("Test does not have parameter %q", param)
+	return nil
+}
+
+// runInstance runs the function of ti with a fake testing.State and returns
+// messages it reported. Fatal errors of ti are reported in the result, not to
+// t. t is failed only if ti does not return in time.
+func runInstance(t *gotesting.T, ti *testing.TestInstance, opts ...Option) *Result {
+	t.Helper()
+
+	cfg := &config{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.dataDir == "" {
+		dir, err := filepath.Abs("data")
+		if err != nil {
+			t.Fatal("Failed to locate data directory: ", err)
+		}
+		cfg.dataDir = dir
+	}
+	if cfg.outDir == "" {
+		cfg.outDir = t.TempDir()
+	}
+	features := map[string]*frameworkprotocol.DUTFeatures{"": cfg.features}
+	if cfg.features == nil {
+		features[""] = &frameworkprotocol.DUTFeatures{}
+	}
+
+	timeout := ti.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	out := &recorder{}
+	rcfg := &testing.RuntimeConfig{
+		DataDir:   cfg.dataDir,
+		OutDir:    cfg.outDir,
+		Vars:      cfg.vars,
+		Features:  features,
+		FixtValue: cfg.fixtValue,
+		FixtSerializedValue: func() ([]byte, error) {
+			return nil, errors.Errorf("serialized fixture values are not supported in %s", ti.Name)
+		},
+	}
+	if cfg.dut != nil {
+		rcfg.RemoteData = &testing.RemoteData{
+			DUT:     cfg.dut,
+			RPCHint: testing.NewRPCHint(fakeBundleDir, cfg.vars),
+		}
+	}
+	root := testing.NewTestEntityRoot(ti, rcfg, out, testing.NewEntityCondition())
+	ctx := root.NewContext(context.Background())
+	s := root.NewTestState()
+
+	if err := usercode.SafeCall(ctx, ti.Name, timeout, gracePeriod, usercode.ErrorOnPanic(s), func(ctx context.Context) {
+		ti.Func(ctx, s)
+	}); err != nil {
+		t.Fatalf("%s did not return: %v", ti.Name, err)
+	}
+	for _, f := range root.TakeCleanups() {
+		if err := usercode.SafeCall(ctx, ti.Name, timeout, gracePeriod, usercode.ErrorOnPanic(s), f); err != nil {
+			t.Fatalf("Cleanup of %s did not return: %v", ti.Name, err)
+		}
+	}
+	if *updateGolden {
+		updateGoldens(t, cfg.outDir, cfg.dataDir)
+	}
+	return out.result(cfg.outDir)
+}
+
+// CheckGolden compares the transcript of res with the content of the golden
+// file at path with golden.Compare, and fails t if they differ. If the test is
+// run with -updategolden, the golden file is rewritten instead.
+func CheckGolden(t *gotesting.T, res *Result, path string) {
+	t.Helper()
+	s := &goldenState{t: t, dataDir: filepath.Dir(path), outDir: t.TempDir()}
+	if golden.Compare(s, filepath.Base(path), []byte(res.Transcript())) || !*updateGolden {
+		return
+	}
+	updateGoldens(t, s.outDir, s.dataDir)
+}
+
+// updateGoldens copies actual outputs saved by golden.Compare under outDir
+// to dataDir with golden.Update.
+func updateGoldens(t *gotesting.T, outDir, dataDir string) {
+	t.Helper()
+	updated, err := golden.Update(outDir, dataDir)
+	for _, p := range updated {
+		t.Log("Updated golden file ", p)
+	}
+	if err != nil {
+		t.Fatal("Failed to update golden files: ", err)
+	}
+}
+
+// goldenState is an implementation of golden.State reporting mismatches to a
+// Go unit test.
+type goldenState struct {
+	t       *gotesting.T
+	dataDir string
+	outDir  string
+}
+
+func (s *goldenState) DataPath(p string) string { return filepath.Join(s.dataDir, p) }
+func (s *goldenState) OutDir() string           { return s.outDir }
+
+func (s *goldenState) Errorf(format string, args ...interface{}) {
+	s.t.Helper()
+	if *updateGolden {
+		// Mismatches are fixed by updating golden files.
+		s.t.Logf(format, args...)
+		return
+	}
+	s.t.Errorf(format+" (run with -updategolden to update)", args...)
+}
+
+// recorder is an implementation of testing.OutputStream recording messages
+// reported by a test.
+type recorder struct {
+	mu         sync.Mutex
+	logs       []string
+	errs       []*Error
+	transcript strings.Builder
+}
+
+func (r *recorder) Log(level logging.Level, ts time.Time, msg string) error {
+	if level < logging.LevelInfo {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.logs = append(r.logs, msg)
+	fmt.Fprintf(&r.transcript, "LOG %s\n", msg)
+	return nil
+}
+
+func (r *recorder) Error(e *protocol.Error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errs = append(r.errs, &Error{
+		Reason: e.GetReason(),
+		File:   e.GetLocation().GetFile(),
+		Line:   int(e.GetLocation().GetLine()),
+	})
+	fmt.Fprintf(&r.transcript, "ERROR %s\n", e.GetReason())
+	return nil
+}
+
+func (r *recorder) result(outDir string) *Result {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &Result{
+		Logs:       append([]string(nil), r.logs...),
+		Errors:     append([]*Error(nil), r.errs...),
+		OutDir:     outDir,
+		transcript: r.transcript.String(),
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package bundletest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	gotesting "testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"go.chromium.org/tast/core/internal/protocol"
+	"go.chromium.org/tast/core/internal/testing"
+	"go.chromium.org/tast/core/rpc"
+	"go.chromium.org/tast/core/testutil"
+)
+
+func TestRunInstance(t *gotesting.T) {
+	ti := &testing.TestInstance{
+		Name: "pkg.Test",
+		Vars: []string{"pkg.var"},
+		Func: func(ctx context.Context, s *testing.State) {
+			s.Log("var is ", s.RequiredVar("pkg.var"))
+			s.Logf("fixture value is %v", s.FixtValue())
+			if err := os.WriteFile(filepath.Join(s.OutDir(), "out.txt"), []byte("hello"), 0644); err != nil {
+				s.Fatal("Failed to write output: ", err)
+			}
+			s.Error("Something went wrong")
+			s.Fatal("Fatal error")
+			s.Log("Not reached")
+		},
+	}
+
+	res := runInstance(t, ti,
+		WithVars(map[string]string{"pkg.var": "foo"}),
+		WithFixtValue(42))
+
+	const want = `LOG var is foo
+LOG fixture value is 42
+ERROR Something went wrong
+ERROR Fatal error
+`
+	if got := res.Transcript(); got != want {
+		t.Errorf("Transcript mismatch:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+	if !res.Failed() {
+		t.Error("Failed() = false; want true")
+	}
+	if b, err := os.ReadFile(filepath.Join(res.OutDir, "out.txt")); err != nil {
+		t.Error("Failed to read output file: ", err)
+	} else if string(b) != "hello" {
+		t.Errorf("Output file content = %q; want %q", string(b), "hello")
+	}
+}
+
+// BUNDLETESTTEST is a test function with a name matching this file's name
+// (bundletest_test.go), which is required to instantiate tests.
+func BUNDLETESTTEST(ctx context.Context, s *testing.State) {
+	s.Log("param is ", s.Param())
+}
+
+func TestRunTest(t *gotesting.T) {
+	test := &testing.Test{
+		Func: BUNDLETESTTEST,
+		Params: []testing.Param{
+			{Val: "default"},
+			{Name: "foo", Val: "foo"},
+		},
+	}
+	for _, tc := range []struct {
+		param string
+		want  string
+	}{
+		{"", "LOG param is default\n"},
+		{"foo", "LOG param is foo\n"},
+	} {
+		res := RunTest(t, test, tc.param)
+		if got := res.Transcript(); got != tc.want {
+			t.Errorf("Transcript for param %q = %q; want %q", tc.param, got, tc.want)
+		}
+	}
+}
+
+func TestRunInstancePanic(t *gotesting.T) {
+	ti := &testing.TestInstance{
+		Name: "pkg.Panic",
+		Func: func(ctx context.Context, s *testing.State) {
+			panic("oops")
+		},
+	}
+
+	res := runInstance(t, ti)
+	if len(res.Errors) != 1 || res.Errors[0].Reason != "Panic: oops" {
+		t.Errorf("Errors = %+v; want a single panic error", res.Errors)
+	}
+}
+
+func TestCheckGolden(t *gotesting.T) {
+	ti := &testing.TestInstance{
+		Name: "pkg.Golden",
+		Func: func(ctx context.Context, s *testing.State) {
+			s.Log("Hello")
+		},
+	}
+	res := runInstance(t, ti)
+
+	td := testutil.TempDir(t)
+	defer os.RemoveAll(td)
+
+	path := filepath.Join(td, "test.golden")
+	if err := os.WriteFile(path, []byte("LOG Hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	CheckGolden(t, res, path)
+}
+
+// fakePingServer is a fake of the PingUser gRPC service.
+type fakePingServer struct{}
+
+func (fakePingServer) Ping(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, nil
+}
+
+func TestRunInstanceFakeDUT(t *gotesting.T) {
+	svc := &testing.Service{
+		Register: func(srv *grpc.Server, s *testing.ServiceState) {
+			protocol.RegisterPingUserServer(srv, fakePingServer{})
+		},
+	}
+	ti := &testing.TestInstance{
+		Name:        "pkg.Remote",
+		ServiceDeps: []string{"tast.coretest.PingUser"},
+		Func: func(ctx context.Context, s *testing.State) {
+			cl, err := rpc.Dial(ctx, s.DUT(), s.RPCHint())
+			if err != nil {
+				s.Fatal("Failed to connect to the RPC service on the DUT: ", err)
+			}
+			defer cl.Close(ctx)
+
+			if _, err := protocol.NewPingUserClient(cl.Conn).Ping(ctx, &emptypb.Empty{}); err != nil {
+				s.Fatal("Ping failed: ", err)
+			}
+			s.Log("Pinged")
+
+			if err := s.DUT().Conn().CommandContext(ctx, "true").Run(); err == nil {
+				s.Error("Running a command on the fake DUT succeeded unexpectedly")
+			}
+		},
+	}
+
+	res := runInstance(t, ti, WithDUT(NewFakeDUT(t, svc)))
+	if res.Failed() {
+		t.Errorf("Test failed: %+v", res.Errors[0])
+	}
+	if got := res.Transcript(); !strings.Contains(got, "LOG Pinged\n") {
+		t.Errorf("Transcript = %q; want to contain a log after pinging", got)
+	}
+}
+
+func TestCheckGoldenUpdate(t *gotesting.T) {
+	defer func(v bool) { *updateGolden = v }(*updateGolden)
+	*updateGolden = true
+
+	ti := &testing.TestInstance{
+		Name: "pkg.Golden",
+		Func: func(ctx context.Context, s *testing.State) {
+			s.Log("Hello")
+		},
+	}
+	res := runInstance(t, ti)
+
+	td := testutil.TempDir(t)
+	defer os.RemoveAll(td)
+
+	path := filepath.Join(td, "test.golden")
+	if err := os.WriteFile(path, []byte("LOG Goodbye\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	CheckGolden(t, res, path)
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), "LOG Hello\n"; got != want {
+		t.Errorf("Updated golden file = %q; want %q", got, want)
+	}
+}
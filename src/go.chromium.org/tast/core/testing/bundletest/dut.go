@@ -0,0 +1,86 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package bundletest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	gotesting "testing"
+
+	"google.golang.org/grpc"
+
+	"go.chromium.org/tast/core/dut"
+	"go.chromium.org/tast/core/internal/protocol"
+	"go.chromium.org/tast/core/internal/rpc"
+	"go.chromium.org/tast/core/internal/sshtest"
+	"go.chromium.org/tast/core/internal/testing"
+)
+
+// fakeBundleDir is the directory where local test bundles pretend to be
+// installed on fake DUTs. It is passed to tests via s.RPCHint.
+const fakeBundleDir = "/usr/local/libexec/tast/bundles/local"
+
+// NewFakeDUT starts a fake SSH server standing in for a DUT and returns a
+// DUT connected to it. Pass it to WithDUT to run remote tests against it.
+//
+// svcs are gRPC services served to clients created by rpc.Dial, in place of
+// services registered by a local test bundle on a real DUT. They are
+// typically the same *testing.Service values passed to testing.AddService,
+// or fakes of them. Any other command run on the fake DUT fails.
+//
+// The server and the connection are closed when t finishes.
+func NewFakeDUT(t *gotesting.T, svcs ...*testing.Service) *dut.DUT {
+	t.Helper()
+
+	userKey, hostKey := sshtest.MustGenerateKeys()
+	srv, err := sshtest.NewSSHServer(&userKey.PublicKey, hostKey, func(req *sshtest.ExecReq) {
+		handleFakeDUTExec(req, svcs)
+	})
+	if err != nil {
+		t.Fatal("Failed to start fake SSH server: ", err)
+	}
+	t.Cleanup(func() { srv.Close() })
+
+	keyFile, err := sshtest.WriteKey(userKey)
+	if err != nil {
+		t.Fatal("Failed to write SSH key: ", err)
+	}
+	t.Cleanup(func() { os.Remove(keyFile) })
+
+	d, err := dut.New(srv.Addr().String(), keyFile, "", "", nil)
+	if err != nil {
+		t.Fatal("Failed to create fake DUT: ", err)
+	}
+	ctx := context.Background()
+	if err := d.Connect(ctx); err != nil {
+		t.Fatal("Failed to connect to fake DUT: ", err)
+	}
+	t.Cleanup(func() { d.Close(ctx) })
+	return d
+}
+
+// handleFakeDUTExec handles an SSH "exec" request sent to a fake DUT.
+// Requests to start a test bundle as a gRPC server are served with svcs.
+func handleFakeDUTExec(req *sshtest.ExecReq, svcs []*testing.Service) {
+	if !strings.HasSuffix(req.Cmd, " -rpc") {
+		req.Start(true)
+		fmt.Fprintf(req.Stderr(), "command not supported by fake DUT: %s\n", req.Cmd)
+		req.End(127)
+		return
+	}
+
+	req.Start(true)
+	status := 0
+	if err := rpc.RunServer(req, req, svcs, func(srv *grpc.Server, req *protocol.HandshakeRequest) error {
+		return nil
+	}); err != nil {
+		fmt.Fprintf(req.Stderr(), "gRPC server failed: %v\n", err)
+		status = 1
+	}
+	req.CloseOutput()
+	req.End(status)
+}
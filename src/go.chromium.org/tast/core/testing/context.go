@@ -82,6 +82,14 @@ func ContextOutDir(ctx context.Context) (dir string, ok bool) {
 	return testcontext.OutDir(ctx)
 }
 
+// ContextTempDir is similar to State.TempDir but takes context instead. It is
+// intended to be used by packages providing support for tests that need
+// temporary directories, instead of os.MkdirTemp whose directories are easily
+// leaked on the DUT. It returns an error if ctx is not associated with a test.
+func ContextTempDir(ctx context.Context) (string, error) {
+	return testcontext.TempDir(ctx)
+}
+
 // ContextSoftwareDeps is similar to SoftwareDeps but takes context instead.
 // It is intended to be used by packages providing support for tests that want to
 // make sure tests declare proper dependencies.
@@ -89,6 +97,15 @@ func ContextSoftwareDeps(ctx context.Context) ([]string, bool) {
 	return testcontext.SoftwareDeps(ctx)
 }
 
+// ContextRunMetadata returns run-level metadata given by the -metadata flag of
+// tast run, e.g. the name of the CI builder and the CL under test. It is
+// intended to be used by tests and support packages that need to correlate
+// their artifacts, e.g. uploaded traces, with the CI context. ok is false if
+// ctx is not associated with an entity.
+func ContextRunMetadata(ctx context.Context) (md map[string]string, ok bool) {
+	return testcontext.RunMetadata(ctx)
+}
+
 // ContextEnsurePrivateAttr ensures the current entity declares a privateAttr in its metadata.
 // Otherwise it will panic.
 func ContextEnsurePrivateAttr(ctx context.Context, name string) {
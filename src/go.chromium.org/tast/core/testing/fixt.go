@@ -16,3 +16,19 @@ type FixtureParam = testing.FixtureParam
 
 // FixtureImpl is an interface fixtures should implement.
 type FixtureImpl = testing.FixtureImpl
+
+// FixtValueType describes a type of fixture values that can be transported to
+// tests and fixtures running in other processes.
+type FixtValueType = testing.FixtValueType
+
+// RegisterFixtValueType registers a type of fixture values, so that tests and
+// fixtures depending on a fixture in another process, e.g. local tests
+// depending on a remote fixture, can get its value with State.FixtValue or
+// FixtState.ParentValue. Values are serialized with the name and the version
+// of the type, and deserializing a value of a different version fails.
+// It should be called in init() of the package defining the type.
+func RegisterFixtValueType(t *FixtValueType) {
+	if err := testing.RegisterFixtValueType(t); err != nil {
+		testing.GlobalRegistry().RecordError(err)
+	}
+}
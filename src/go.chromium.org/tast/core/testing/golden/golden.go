@@ -0,0 +1,99 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+// Package golden provides comparison of test outputs against golden files.
+//
+// A golden file is an external or internal data file of a test containing the
+// expected output. On mismatch, Compare writes the actual output to the test
+// output directory so that it is included in test results. Update copies actual
+// outputs back to the data directories in the source tree, so that they can be
+// reviewed and committed. It is called by "tast run -updategolden", and by
+// "go test -updategolden" for tests run with the bundletest package.
+package golden
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+
+	"go.chromium.org/tast/core/fsutil"
+)
+
+// OutSubdir is the subdirectory of a test output directory into which actual
+// outputs mismatching golden files are written. Files in the directory have
+// the same relative paths as the golden files in the data directory.
+const OutSubdir = "golden"
+
+// State is a subset of testing.State needed to compare golden files.
+type State interface {
+	DataPath(p string) string
+	OutDir() string
+	Errorf(format string, args ...interface{})
+}
+
+// Compare compares got with the golden file name, which must be declared in
+// Data of the test. On mismatch, it reports an error with a diff and writes
+// got to the output directory. got is also written if the golden file cannot
+// be read, so that "tast run -updategolden" can create a missing golden file.
+// It returns true if got matches the golden file.
+func Compare(s State, name string, got []byte) bool {
+	want, err := os.ReadFile(s.DataPath(name))
+	if err != nil {
+		s.Errorf("Failed to read golden file %s: %v", name, err)
+	} else if bytes.Equal(got, want) {
+		return true
+	} else {
+		diff := cmp.Diff(strings.Split(string(want), "\n"), strings.Split(string(got), "\n"))
+		s.Errorf("Output mismatches golden file %s (-want +got):\n%s", name, diff)
+	}
+
+	if err := writeActual(s.OutDir(), name, got); err != nil {
+		s.Errorf("Failed to save actual output for golden file %s: %v", name, err)
+	}
+	return false
+}
+
+// writeActual writes got to the path of the golden file name under
+// OutSubdir of outDir.
+func writeActual(outDir, name string, got []byte) error {
+	path := filepath.Join(outDir, OutSubdir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, got, 0644)
+}
+
+// Update copies actual outputs written by Compare under outDir, the output
+// directory of a test, to dataDir, the data directory of the test in the
+// source tree. It returns paths of updated golden files.
+func Update(outDir, dataDir string) ([]string, error) {
+	srcDir := filepath.Join(outDir, OutSubdir)
+	if _, err := os.Stat(srcDir); os.IsNotExist(err) {
+		return nil, nil
+	}
+	var updated []string
+	err := filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+		dst := filepath.Join(dataDir, rel)
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return err
+		}
+		if err := fsutil.CopyFile(path, dst); err != nil {
+			return err
+		}
+		updated = append(updated, dst)
+		return nil
+	})
+	return updated, err
+}
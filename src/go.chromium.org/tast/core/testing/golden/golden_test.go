@@ -0,0 +1,114 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package golden_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.chromium.org/tast/core/testing/golden"
+)
+
+// fakeState implements golden.State.
+type fakeState struct {
+	dataDir string
+	outDir  string
+	errs    []string
+}
+
+func (s *fakeState) DataPath(p string) string { return filepath.Join(s.dataDir, p) }
+func (s *fakeState) OutDir() string           { return s.outDir }
+func (s *fakeState) Errorf(format string, args ...interface{}) {
+	s.errs = append(s.errs, fmt.Sprintf(format, args...))
+}
+
+func TestCompare(t *testing.T) {
+	td := t.TempDir()
+	s := &fakeState{dataDir: filepath.Join(td, "data"), outDir: filepath.Join(td, "out")}
+	if err := os.MkdirAll(filepath.Join(s.dataDir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(s.dataDir, "sub", "foo.golden"), []byte("a\nb\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if !golden.Compare(s, "sub/foo.golden", []byte("a\nb\n")) {
+		t.Error("Compare returned false for matching output")
+	}
+	if len(s.errs) > 0 {
+		t.Error("Compare reported errors for matching output: ", s.errs)
+	}
+	if _, err := os.Stat(filepath.Join(s.outDir, golden.OutSubdir)); !os.IsNotExist(err) {
+		t.Error("Compare wrote actual output for matching output")
+	}
+
+	if golden.Compare(s, "sub/foo.golden", []byte("a\nc\n")) {
+		t.Error("Compare returned true for mismatching output")
+	}
+	if len(s.errs) != 1 || !strings.Contains(s.errs[0], `"c"`) {
+		t.Errorf("Compare reported %q; want an error with a diff", s.errs)
+	}
+	b, err := os.ReadFile(filepath.Join(s.outDir, golden.OutSubdir, "sub", "foo.golden"))
+	if err != nil {
+		t.Fatal("Actual output not saved: ", err)
+	}
+	if string(b) != "a\nc\n" {
+		t.Errorf("Saved actual output = %q; want %q", b, "a\nc\n")
+	}
+}
+
+func TestCompareMissingGolden(t *testing.T) {
+	td := t.TempDir()
+	s := &fakeState{dataDir: filepath.Join(td, "data"), outDir: filepath.Join(td, "out")}
+	if golden.Compare(s, "missing.golden", []byte("a\n")) {
+		t.Error("Compare returned true for missing golden file")
+	}
+	if len(s.errs) != 1 {
+		t.Errorf("Compare reported %q; want 1 error", s.errs)
+	}
+	b, err := os.ReadFile(filepath.Join(s.outDir, golden.OutSubdir, "missing.golden"))
+	if err != nil {
+		t.Fatal("Actual output not saved: ", err)
+	}
+	if string(b) != "a\n" {
+		t.Errorf("Saved actual output = %q; want %q", b, "a\n")
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	td := t.TempDir()
+	s := &fakeState{dataDir: filepath.Join(td, "data"), outDir: filepath.Join(td, "out")}
+	golden.Compare(s, "sub/foo.golden", []byte("new\n"))
+
+	updated, err := golden.Update(s.outDir, s.dataDir)
+	if err != nil {
+		t.Fatal("Update failed: ", err)
+	}
+	path := filepath.Join(s.dataDir, "sub", "foo.golden")
+	if len(updated) != 1 || updated[0] != path {
+		t.Errorf("Update returned %q; want [%q]", updated, path)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal("Golden file not updated: ", err)
+	}
+	if string(b) != "new\n" {
+		t.Errorf("Updated golden file = %q; want %q", b, "new\n")
+	}
+}
+
+func TestUpdateNoMismatch(t *testing.T) {
+	td := t.TempDir()
+	updated, err := golden.Update(filepath.Join(td, "out"), filepath.Join(td, "data"))
+	if err != nil {
+		t.Fatal("Update failed: ", err)
+	}
+	if len(updated) > 0 {
+		t.Errorf("Update returned %q; want none", updated)
+	}
+}
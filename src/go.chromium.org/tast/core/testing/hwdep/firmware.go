@@ -0,0 +1,165 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package hwdep
+
+import (
+	"fmt"
+	"strings"
+
+	"go.chromium.org/tast/core/errors"
+	"go.chromium.org/tast/core/framework/protocol"
+)
+
+// FirmwareImage identifies an AP firmware image whose version is compared by
+// FirmwareVersionAtLeast.
+type FirmwareImage int
+
+// These are firmware images that can be passed to FirmwareVersionAtLeast.
+const (
+	// FirmwareRO is the read-only firmware image.
+	FirmwareRO FirmwareImage = iota
+	// FirmwareRW is the active read-write firmware image.
+	FirmwareRW
+)
+
+func (i FirmwareImage) String() string {
+	switch i {
+	case FirmwareRO:
+		return "RO"
+	case FirmwareRW:
+		return "RW"
+	default:
+		return fmt.Sprintf("FirmwareImage(%d)", int(i))
+	}
+}
+
+// FirmwareBranch specifies the first version containing a feature on a
+// firmware branch, for features cherry-picked to branches cut before the
+// feature landed in the main branch.
+type FirmwareBranch struct {
+	// Major is the major version the branch was cut at, e.g. 13434 for
+	// firmware-zork-13434.B.
+	Major uint32
+	// Minor is the first minor version on the branch containing the feature.
+	Minor uint32
+	// Models limits the rule to the listed models if non-empty.
+	Models []string
+}
+
+// firmwareVersion is a version of a firmware image.
+type firmwareVersion struct {
+	major, minor uint32
+}
+
+func (v firmwareVersion) String() string {
+	return fmt.Sprintf("%d.%d", v.major, v.minor)
+}
+
+// firmwareVersionSatisfied returns whether v is major or later, or satisfies
+// any of branches. model is the model name of the DUT.
+func firmwareVersionSatisfied(v firmwareVersion, model string, major uint32, branches []FirmwareBranch) bool {
+	if v.major >= major {
+		return true
+	}
+	for _, b := range branches {
+		if v.major != b.Major || v.minor < b.Minor {
+			continue
+		}
+		if len(b.Models) == 0 {
+			return true
+		}
+		for _, m := range b.Models {
+			if m == model {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FirmwareVersionAtLeast returns a hardware dependency condition that is
+// satisfied if and only if the version of the AP firmware image is major.0 or
+// later, or it is on a branch listed in branches and is its minor version or
+// later. It is useful to require a firmware feature which landed in major and
+// was cherry-picked to some earlier branches, e.g.:
+//
+//	// CL:2617391 landed in 13727.0.0, and was cherry-picked to
+//	// firmware-zork-13434.B in 13434.267.0 (13434.106.0 for dirinboz)
+//	// and firmware-dedede-13606.B in 13606.99.0.
+//	hwdep.FirmwareVersionAtLeast(hwdep.FirmwareRW, 13727,
+//		hwdep.FirmwareBranch{Major: 13434, Minor: 267},
+//		hwdep.FirmwareBranch{Major: 13434, Minor: 106, Models: []string{"dirinboz"}},
+//		hwdep.FirmwareBranch{Major: 13606, Minor: 99},
+//	)
+//
+// EC features built from the same firmware branches are also checked against
+// AP firmware versions.
+func FirmwareVersionAtLeast(image FirmwareImage, major uint32, branches ...FirmwareBranch) Condition {
+	if image != FirmwareRO && image != FirmwareRW {
+		return Condition{Err: errors.Errorf("invalid firmware image %v", image)}
+	}
+	needModel := false
+	for _, b := range branches {
+		if b.Major >= major {
+			return Condition{Err: errors.Errorf("firmware branch %d is not older than %d", b.Major, major)}
+		}
+		for _, m := range b.Models {
+			if !idRegexp.MatchString(m) {
+				return Condition{Err: errors.Errorf("ModelId should match with %v: %q", idRegexp, m)}
+			}
+			needModel = true
+		}
+	}
+	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
+		hf := f.GetHardwareFeatures()
+		if hf == nil {
+			return withErrorStr("HardwareFeatures is not given")
+		}
+		fw := hf.GetFwConfig().GetFwRoVersion()
+		if image == FirmwareRW {
+			fw = hf.GetFwConfig().GetFwRwVersion()
+		}
+		v := firmwareVersion{major: fw.GetMajorVersion(), minor: fw.GetMinorVersion()}
+		if v.major == 0 {
+			return unsatisfied(fmt.Sprintf("Could not determine %v firmware version", image))
+		}
+		var model string
+		if needModel {
+			dc := f.GetDeprecatedDeviceConfig()
+			if dc == nil || dc.Id == nil || dc.Id.Model == "" {
+				return withErrorStr("DeprecatedDeviceConfig does not have model ID")
+			}
+			// Remove the suffix _signed as done in modelListed.
+			model = strings.TrimSuffix(strings.ToLower(dc.Id.Model), "_signed")
+		}
+		if firmwareVersionSatisfied(v, model, major, branches) {
+			return satisfied()
+		}
+		return unsatisfied(fmt.Sprintf("DUT %v firmware version %v is older than required versions", image, v))
+	}}
+}
+
+// firmwareFeature returns a hardware dependency condition that is satisfied if
+// and only if all of conds checking firmware versions are satisfied. reason is
+// reported if any of them is unsatisfied.
+func firmwareFeature(reason string, conds ...Condition) Condition {
+	for _, c := range conds {
+		if c.Err != nil {
+			return Condition{Err: c.Err}
+		}
+	}
+	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
+		for _, c := range conds {
+			ok, _, err := c.Satisfied(f)
+			if err != nil {
+				return withError(err)
+			}
+			if !ok {
+				return unsatisfied(reason)
+			}
+		}
+		return satisfied()
+	}}
+}
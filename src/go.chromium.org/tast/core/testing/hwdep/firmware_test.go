@@ -0,0 +1,89 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package hwdep_test
+
+import (
+	"testing"
+
+	configpb "go.chromium.org/chromiumos/config/go/api"
+
+	"go.chromium.org/tast/core/testing/hwdep"
+
+	frameworkprotocol "go.chromium.org/tast/core/framework/protocol"
+)
+
+func TestFirmwareVersionAtLeast(t *testing.T) {
+	c := hwdep.FirmwareVersionAtLeast(hwdep.FirmwareRO, 13727,
+		hwdep.FirmwareBranch{Major: 13434, Minor: 267},
+		hwdep.FirmwareBranch{Major: 13434, Minor: 106, Models: []string{"dirinboz"}},
+		hwdep.FirmwareBranch{Major: 13606, Minor: 99},
+	)
+	if c.Err != nil {
+		t.Fatal("FirmwareVersionAtLeast failed: ", c.Err)
+	}
+
+	for _, tc := range []struct {
+		model        string
+		major, minor uint32
+		want         bool
+	}{
+		{"eve", 13727, 0, true},
+		{"eve", 14000, 0, true},
+		{"eve", 13726, 999, false},
+		{"eve", 13434, 267, true},
+		{"eve", 13434, 266, false},
+		{"eve", 13434, 106, false},
+		{"dirinboz", 13434, 106, true},
+		{"dirinboz_signed", 13434, 106, true},
+		{"dirinboz", 13434, 105, false},
+		{"eve", 13606, 99, true},
+		{"eve", 13606, 98, false},
+		{"eve", 13500, 999, false},
+		{"eve", 0, 0, false},
+	} {
+		dc := &frameworkprotocol.DeprecatedDeviceConfig{
+			Id: &frameworkprotocol.DeprecatedConfigId{Model: tc.model},
+		}
+		features := &configpb.HardwareFeatures{
+			FwConfig: &configpb.HardwareFeatures_FirmwareConfiguration{
+				FwRoVersion: &configpb.HardwareFeatures_FirmwareConfiguration_SemVer{
+					MajorVersion: tc.major,
+					MinorVersion: tc.minor,
+				},
+			},
+		}
+		t.Logf("Model %s, RO firmware %d.%d", tc.model, tc.major, tc.minor)
+		verifyCondition(t, c, dc, features, tc.want)
+	}
+}
+
+func TestFirmwareVersionAtLeastRW(t *testing.T) {
+	c := hwdep.FirmwareVersionAtLeast(hwdep.FirmwareRW, 15904, hwdep.FirmwareBranch{Major: 14505, Minor: 769})
+	for _, tc := range []struct {
+		ro, rw uint32
+		want   bool
+	}{
+		{15904, 14505, false},
+		{14505, 15904, true},
+	} {
+		features := &configpb.HardwareFeatures{
+			FwConfig: &configpb.HardwareFeatures_FirmwareConfiguration{
+				FwRoVersion: &configpb.HardwareFeatures_FirmwareConfiguration_SemVer{MajorVersion: tc.ro},
+				FwRwVersion: &configpb.HardwareFeatures_FirmwareConfiguration_SemVer{MajorVersion: tc.rw},
+			},
+		}
+		t.Logf("RO firmware %d, RW firmware %d", tc.ro, tc.rw)
+		verifyCondition(t, c, nil, features, tc.want)
+	}
+}
+
+func TestFirmwareVersionAtLeastInvalid(t *testing.T) {
+	if c := hwdep.FirmwareVersionAtLeast(hwdep.FirmwareRO, 13727, hwdep.FirmwareBranch{Major: 13727, Minor: 1}); c.Err == nil {
+		t.Error("FirmwareVersionAtLeast unexpectedly succeeded with a branch not older than the main version")
+	}
+	if c := hwdep.FirmwareVersionAtLeast(hwdep.FirmwareRO, 13727, hwdep.FirmwareBranch{Major: 13434, Models: []string{"Bad Model"}}); c.Err == nil {
+		t.Error("FirmwareVersionAtLeast unexpectedly succeeded with an invalid model name")
+	}
+}
@@ -10,12 +10,14 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
 
 	configpb "go.chromium.org/chromiumos/config/go/api"
 
 	"go.chromium.org/tast/core/errors"
 	"go.chromium.org/tast/core/internal/dep"
+	"go.chromium.org/tast/core/testing/bluetooth"
 	"go.chromium.org/tast/core/testing/cellularconst"
 	"go.chromium.org/tast/core/testing/wlan"
 
@@ -294,6 +296,109 @@ func SkipOnWifiDevice(devices ...wlan.DeviceID) Condition {
 	}}
 }
 
+// bluetoothDeviceListed returns whether the Bluetooth controller given in HardwareFeatures is listed in the given list of devices or not.
+func bluetoothDeviceListed(f *protocol.HardwareFeatures, devices ...bluetooth.DeviceID) (bool, error) {
+	if f.GetHardwareFeatures() == nil {
+		return false, errors.New("HardwareFeatures is not given")
+	}
+	id := bluetooth.DeviceID(f.GetBluetoothDevice())
+	if id == bluetooth.UnknownDevice {
+		return false, errors.New("Bluetooth controller data has not been passed from DUT")
+	}
+	for _, d := range devices {
+		if d == id {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// BluetoothDevice returns a hardware dependency condition that is satisfied
+// if and only if the DUT's Bluetooth controller is one of the given devices.
+// This should be preferred to listing boards or models for tests depending on
+// behavior specific to Bluetooth chipsets.
+func BluetoothDevice(devices ...bluetooth.DeviceID) Condition {
+	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
+		listed, err := bluetoothDeviceListed(f, devices...)
+		if err != nil {
+			// Fail-open. Assumption is that if the device is not recognized, it doesn't match.
+			return unsatisfied(fmt.Sprintf("Unrecognized device. Assume not matching. Err %v", err))
+		}
+		if !listed {
+			return unsatisfied("Bluetooth device did not match")
+		}
+		return satisfied()
+	}}
+}
+
+// SkipOnBluetoothDevice returns a hardware dependency condition that is
+// satisfied if and only if the DUT's Bluetooth controller is none of the given
+// devices.
+func SkipOnBluetoothDevice(devices ...bluetooth.DeviceID) Condition {
+	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
+		listed, err := bluetoothDeviceListed(f, devices...)
+		if err != nil {
+			// Failed to get the device id.
+			// Run the test to report error if it fails on this device.
+			return satisfied()
+		}
+		if listed {
+			return unsatisfied("Bluetooth device matched with skip-on list")
+		}
+		return satisfied()
+	}}
+}
+
+// bluetoothVersionRegexp matches Bluetooth Core Specification versions, e.g. "5.2".
+var bluetoothVersionRegexp = regexp.MustCompile(`^(\d+)\.(\d+)$`)
+
+// parseBluetoothVersion parses a Bluetooth Core Specification version into
+// its major and minor numbers.
+func parseBluetoothVersion(v string) (major, minor int, err error) {
+	m := bluetoothVersionRegexp.FindStringSubmatch(v)
+	if m == nil {
+		return 0, 0, errors.Errorf("Bluetooth version should match with %v: %q", bluetoothVersionRegexp, v)
+	}
+	if major, err = strconv.Atoi(m[1]); err != nil {
+		return 0, 0, err
+	}
+	if minor, err = strconv.Atoi(m[2]); err != nil {
+		return 0, 0, err
+	}
+	return major, minor, nil
+}
+
+// BluetoothVersionAtLeast returns a hardware dependency condition that is
+// satisfied if and only if the DUT's Bluetooth controller supports the
+// Bluetooth Core Specification version v or later, e.g. "5.2".
+func BluetoothVersionAtLeast(v string) Condition {
+	wantMajor, wantMinor, err := parseBluetoothVersion(v)
+	if err != nil {
+		return Condition{Err: err}
+	}
+	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
+		hf := f.GetHardwareFeatures()
+		if hf == nil {
+			return withErrorStr("HardwareFeatures is not given")
+		}
+		if hf.GetBluetooth().GetPresent() != configpb.HardwareFeatures_PRESENT {
+			return unsatisfied("DUT does not have Bluetooth controller")
+		}
+		got := f.GetBluetoothVersion()
+		if got == "" {
+			return unsatisfied("Could not determine Bluetooth version")
+		}
+		major, minor, err := parseBluetoothVersion(got)
+		if err != nil {
+			return unsatisfied(fmt.Sprintf("Could not parse Bluetooth version: %v", err))
+		}
+		if major < wantMajor || (major == wantMajor && minor < wantMinor) {
+			return unsatisfied(fmt.Sprintf("DUT Bluetooth version %s is older than %s", got, v))
+		}
+		return satisfied()
+	}}
+}
+
 // TouchScreen returns a hardware dependency condition that is satisfied
 // if and only if the DUT has touchscreen.
 func TouchScreen() Condition {
@@ -344,6 +449,26 @@ func ChromeEC() Condition {
 	}
 }
 
+// ECProtocolVersionAtLeast returns a hardware dependency condition that is
+// satisfied if and only if the DUT has an EC which supports host command
+// protocol version v or later.
+func ECProtocolVersionAtLeast(v uint32) Condition {
+	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
+		if f.GetHardwareFeatures() == nil {
+			return withErrorStr("HardwareFeatures is not given")
+		}
+		got := f.GetEcProtocolVersion()
+		if got == 0 {
+			return unsatisfied("Could not determine EC protocol version")
+		}
+		if got < v {
+			return unsatisfied(fmt.Sprintf("DUT EC protocol version %d is older than %d", got, v))
+		}
+		return satisfied()
+	},
+	}
+}
+
 // ECFeatureTypecCmd returns a hardware dependency condition that is satisfied
 // if and only if the DUT has an EC which supports the EC_FEATURE_TYPEC_CMD feature flag.
 func ECFeatureTypecCmd() Condition {
@@ -621,6 +746,66 @@ func CellularSoftwareDynamicSar() Condition {
 	}
 }
 
+// modemFirmwareVersionRegexp matches a number in a modem firmware version.
+var modemFirmwareVersionRegexp = regexp.MustCompile(`\d+`)
+
+// parseModemFirmwareVersion splits a modem firmware version, e.g.
+// "18500.5001.00.05.27.12_5000.00.00.00", into its numeric components.
+func parseModemFirmwareVersion(v string) ([]int, error) {
+	var nums []int
+	for _, s := range modemFirmwareVersionRegexp.FindAllString(v, -1) {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, err
+		}
+		nums = append(nums, n)
+	}
+	if len(nums) == 0 {
+		return nil, errors.Errorf("no numeric component in modem firmware version %q", v)
+	}
+	return nums, nil
+}
+
+// ModemFirmwareAtLeast returns a hardware dependency condition that is
+// satisfied if and only if the DUT has a cellular modem whose firmware version
+// is v or newer. Versions are compared component by component numerically,
+// ignoring separators. The condition is unsatisfied if the firmware version of
+// the DUT is unknown.
+func ModemFirmwareAtLeast(v string) Condition {
+	want, err := parseModemFirmwareVersion(v)
+	if err != nil {
+		return Condition{Err: err}
+	}
+	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
+		hf := f.GetHardwareFeatures()
+		if hf == nil {
+			return withErrorStr("Did not find hardware features")
+		}
+		if status := hf.GetCellular().Present; status == configpb.HardwareFeatures_NOT_PRESENT {
+			return unsatisfied("DUT does not have a cellular modem")
+		} else if status == configpb.HardwareFeatures_PRESENT_UNKNOWN {
+			return unsatisfied("Could not determine if cellular model is present")
+		}
+		fw := f.GetModemFirmwareVersion()
+		if fw == "" {
+			return unsatisfied("Could not determine modem firmware version")
+		}
+		got, err := parseModemFirmwareVersion(fw)
+		if err != nil {
+			return unsatisfied(fmt.Sprintf("Could not parse modem firmware version: %v", err))
+		}
+		for i := 0; i < len(want); i++ {
+			if i >= len(got) || got[i] < want[i] {
+				return unsatisfied(fmt.Sprintf("DUT modem firmware version %s is older than %s", fw, v))
+			}
+			if got[i] > want[i] {
+				break
+			}
+		}
+		return satisfied()
+	}}
+}
+
 // NoCellular returns a hardware dependency condition that
 // is satisfied if and only if the DUT does not have a cellular modem.
 func NoCellular() Condition {
@@ -1007,6 +1192,122 @@ func FingerprintDiagSupported() Condition {
 	}
 }
 
+// fpmcuVersionRegexp matches the numeric part of a fingerprint MCU firmware
+// version, e.g. "2.2.110" in "nocturne_fp_v2.2.110-b936c0a3c".
+var fpmcuVersionRegexp = regexp.MustCompile(`_v(\d+(?:\.\d+)*)`)
+
+// fpmcuWantVersionRegexp matches versions given to FPMCUFirmwareAtLeast.
+var fpmcuWantVersionRegexp = regexp.MustCompile(`^\d+(?:\.\d+)*$`)
+
+// parseFPMCUVersion splits the numeric part of a fingerprint MCU firmware
+// version into its components.
+func parseFPMCUVersion(v string) ([]int, error) {
+	var nums []int
+	for _, s := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, err
+		}
+		nums = append(nums, n)
+	}
+	return nums, nil
+}
+
+// FPMCUFirmwareAtLeast returns a hardware dependency condition that is
+// satisfied if and only if the DUT has a fingerprint sensor whose MCU runs RW
+// firmware of version v or newer. v is the numeric part of a version, e.g.
+// "2.2.110" for "nocturne_fp_v2.2.110-b936c0a3c". The condition is
+// unsatisfied if the firmware version of the DUT is unknown.
+func FPMCUFirmwareAtLeast(v string) Condition {
+	if !fpmcuWantVersionRegexp.MatchString(v) {
+		return Condition{Err: errors.Errorf("FPMCU firmware version should match with %v: %q", fpmcuWantVersionRegexp, v)}
+	}
+	want, err := parseFPMCUVersion(v)
+	if err != nil {
+		return Condition{Err: err}
+	}
+	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
+		hf := f.GetHardwareFeatures()
+		if hf == nil {
+			return withErrorStr("HardwareFeatures is not given")
+		}
+		if !hf.GetFingerprint().GetPresent() {
+			return unsatisfied("DUT does not have fingerprint sensor")
+		}
+		fw := f.GetFpmcuRwVersion()
+		if fw == "" {
+			return unsatisfied("Could not determine FPMCU firmware version")
+		}
+		m := fpmcuVersionRegexp.FindStringSubmatch(fw)
+		if m == nil {
+			return unsatisfied(fmt.Sprintf("Could not parse FPMCU firmware version %s", fw))
+		}
+		got, err := parseFPMCUVersion(m[1])
+		if err != nil {
+			return unsatisfied(fmt.Sprintf("Could not parse FPMCU firmware version %s: %v", fw, err))
+		}
+		for i := 0; i < len(want); i++ {
+			if i >= len(got) || got[i] < want[i] {
+				return unsatisfied(fmt.Sprintf("DUT FPMCU firmware version %s is older than %s", fw, v))
+			}
+			if got[i] > want[i] {
+				break
+			}
+		}
+		return satisfied()
+	}}
+}
+
+// FPMCUHasRollbackSupport returns a hardware dependency condition that is
+// satisfied if and only if the DUT has a fingerprint sensor whose MCU firmware
+// supports rollback protection.
+func FPMCUHasRollbackSupport() Condition {
+	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
+		hf := f.GetHardwareFeatures()
+		if hf == nil {
+			return withErrorStr("HardwareFeatures is not given")
+		}
+		if !hf.GetFingerprint().GetPresent() {
+			return unsatisfied("DUT does not have fingerprint sensor")
+		}
+		if !f.GetFpmcuRollbackSupported() {
+			return unsatisfied("DUT FPMCU firmware does not support rollback protection")
+		}
+		return satisfied()
+	}}
+}
+
+// SDCardReader returns a hardware dependency condition that is satisfied if
+// and only if the DUT has an SD or microSD card reader.
+func SDCardReader() Condition {
+	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
+		if f.GetHardwareFeatures() == nil {
+			return withErrorStr("HardwareFeatures is not given")
+		}
+		if !f.GetSdCardReaderPresent() {
+			return unsatisfied("DUT does not have SD card reader")
+		}
+		return satisfied()
+	}}
+}
+
+// SDCardInserted returns a hardware dependency condition that is satisfied if
+// and only if an SD or microSD card is inserted to a card reader of the DUT.
+func SDCardInserted() Condition {
+	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
+		if f.GetHardwareFeatures() == nil {
+			return withErrorStr("HardwareFeatures is not given")
+		}
+		if !f.GetSdCardReaderPresent() {
+			return unsatisfied("DUT does not have SD card reader")
+		}
+		if !f.GetSdCardInserted() {
+			return unsatisfied("DUT does not have SD card inserted")
+		}
+		return satisfied()
+	}}
+}
+
 // VRR returns a hardware dependency condition that is satisfied if and only if
 // the DUT is VRR Capable (has vrr_capable value set to 1 in modetest).
 func VRR() Condition {
@@ -1095,6 +1396,55 @@ func NoExternalDisplay() Condition {
 	}
 }
 
+// ExternalDisplayCount returns a hardware dependency condition that is
+// satisfied if and only if at least min external displays are connected to
+// the DUT.
+func ExternalDisplayCount(min int) Condition {
+	if min < 1 {
+		return Condition{Err: errors.Errorf("ExternalDisplayCount requires a positive number of displays; got %d", min)}
+	}
+	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
+		if n := len(f.GetExternalDisplayConnectors()); n < min {
+			return unsatisfied(fmt.Sprintf("DUT has %d external display(s) connected; want %d or more", n, min))
+		}
+		return satisfied()
+	},
+	}
+}
+
+// DisplayConnector is a type of connector an external display is connected
+// to the DUT with.
+type DisplayConnector string
+
+// These are types of connectors of external displays.
+const (
+	HDMIConnector DisplayConnector = "HDMI"
+	DPConnector   DisplayConnector = "DP"
+	// USBCConnector is a USB Type-C port in DisplayPort alternate mode.
+	USBCConnector DisplayConnector = "USB-C"
+	DVIConnector  DisplayConnector = "DVI"
+)
+
+// ExternalDisplayConnector returns a hardware dependency condition that is
+// satisfied if and only if an external display is connected to the DUT with
+// any of the given types of connectors.
+func ExternalDisplayConnector(types ...DisplayConnector) Condition {
+	if len(types) == 0 {
+		return Condition{Err: errors.New("ExternalDisplayConnector requires at least one connector type")}
+	}
+	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
+		for _, c := range f.GetExternalDisplayConnectors() {
+			for _, t := range types {
+				if c == string(t) {
+					return satisfied()
+				}
+			}
+		}
+		return unsatisfied(fmt.Sprintf("DUT has no external display connected with any of %q", types))
+	},
+	}
+}
+
 // HdmiConnected returns a hardware dependency condition that is satisfied
 // if and only if the DUT has an external display with HDMI connected.
 func HdmiConnected() Condition {
@@ -1144,6 +1494,64 @@ func InternalDisplayWithHeightPx(heightPx int32) Condition {
 	}
 }
 
+// MinInternalDisplayResolution returns a hardware dependency condition that is
+// satisfied if and only if the DUT has an internal display whose resolution is
+// at least widthPx x heightPx. The orientation of the panel is ignored, e.g. a
+// 1600x2560 portrait panel satisfies MinInternalDisplayResolution(2560, 1600).
+func MinInternalDisplayResolution(widthPx, heightPx int32) Condition {
+	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
+		hf := f.GetHardwareFeatures()
+		if hf == nil {
+			return withErrorStr("HardwareFeatures is not given")
+		}
+		panel := hf.GetScreen().GetPanelProperties()
+		if panel == nil {
+			return unsatisfied("DUT does not have an internal display")
+		}
+		gotLong, gotShort := panel.GetWidthPx(), panel.GetHeightPx()
+		if gotLong == 0 || gotShort == 0 {
+			return unsatisfied("Could not determine internal display resolution")
+		}
+		if gotLong < gotShort {
+			gotLong, gotShort = gotShort, gotLong
+		}
+		wantLong, wantShort := widthPx, heightPx
+		if wantLong < wantShort {
+			wantLong, wantShort = wantShort, wantLong
+		}
+		if gotLong < wantLong || gotShort < wantShort {
+			return unsatisfied(fmt.Sprintf("DUT internal display resolution %dx%d is lower than %dx%d",
+				panel.GetWidthPx(), panel.GetHeightPx(), widthPx, heightPx))
+		}
+		return satisfied()
+	},
+	}
+}
+
+// InternalDisplayRefreshRateAtLeast returns a hardware dependency condition
+// that is satisfied if and only if the DUT has an internal display supporting
+// a refresh rate of hz or higher.
+func InternalDisplayRefreshRateAtLeast(hz uint32) Condition {
+	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
+		hf := f.GetHardwareFeatures()
+		if hf == nil {
+			return withErrorStr("HardwareFeatures is not given")
+		}
+		if hf.GetScreen().GetPanelProperties() == nil {
+			return unsatisfied("DUT does not have an internal display")
+		}
+		got := f.GetInternalDisplayRefreshRateHz()
+		if got == 0 {
+			return unsatisfied("Could not determine internal display refresh rate")
+		}
+		if got < hz {
+			return unsatisfied(fmt.Sprintf("DUT internal display refresh rate %dHz is lower than %dHz", got, hz))
+		}
+		return satisfied()
+	},
+	}
+}
+
 // NoInternalDisplay returns a hardware dependency condition that is satisfied
 // if and only if the DUT does not have an internal display.
 func NoInternalDisplay() Condition {
@@ -1990,6 +2398,255 @@ func MaxMemory(reqMegabytes int) Condition {
 	}}
 }
 
+// MinGPUMemoryMB returns a hardware dependency condition requiring the minimum
+// size of dedicated VRAM of the GPU in megabytes. The condition is not
+// satisfied if the size is unknown, e.g. for integrated GPUs sharing the system
+// memory. Heavyweight graphics tests can use it to avoid running out of memory
+// on low-end SKUs without maintaining model lists.
+func MinGPUMemoryMB(reqMegabytes int) Condition {
+	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
+		s := f.GetGpuMemoryMegabytes()
+		if s == 0 {
+			return unsatisfied("GPU memory size is unknown")
+		}
+		if s < int32(reqMegabytes) {
+			return unsatisfied(fmt.Sprintf("The GPU memory size is smaller than required; got %dMB, need %dMB", s, reqMegabytes))
+		}
+		return satisfied()
+	}}
+}
+
+// ThunderboltDockAttached returns a hardware dependency condition that is
+// satisfied if and only if a Thunderbolt dock is attached to the DUT and
+// connected. Dock certification tests can use it to run only on testbeds
+// cabled with a dock.
+func ThunderboltDockAttached() Condition {
+	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
+		if !f.GetThunderboltDockAttached() {
+			return unsatisfied("No Thunderbolt dock is attached")
+		}
+		return satisfied()
+	}}
+}
+
+// ExternalGPUAttached returns a hardware dependency condition that is
+// satisfied if and only if a GPU is attached to the DUT via an external
+// (e.g. Thunderbolt) PCI link.
+func ExternalGPUAttached() Condition {
+	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
+		if !f.GetExternalGpuAttached() {
+			return unsatisfied("No external GPU is attached")
+		}
+		return satisfied()
+	}}
+}
+
+// KernelModuleLoaded returns a hardware dependency condition that is satisfied
+// if and only if all of the given kernel modules are loaded on the DUT. Dashes
+// and underscores in module names are interchangeable, as with modprobe.
+func KernelModuleLoaded(names ...string) Condition {
+	if len(names) == 0 {
+		return Condition{Err: errors.New("KernelModuleLoaded requires at least one module name")}
+	}
+	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
+		loaded := make(map[string]bool)
+		for _, m := range f.GetKernelModules() {
+			loaded[normalizeKernelModuleName(m)] = true
+		}
+		for _, n := range names {
+			if !loaded[normalizeKernelModuleName(n)] {
+				return unsatisfied(fmt.Sprintf("Kernel module %s is not loaded", n))
+			}
+		}
+		return satisfied()
+	}}
+}
+
+// normalizeKernelModuleName returns a kernel module name as listed in
+// /proc/modules, where dashes are replaced with underscores.
+func normalizeKernelModuleName(name string) string {
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+// DeviceTreeCompatible returns a hardware dependency condition that is
+// satisfied if and only if the root node of the DUT's device tree is
+// compatible with any of the given strings, e.g. "mediatek,mt8183".
+// The condition is unsatisfied on DUTs without a device tree.
+func DeviceTreeCompatible(compatibles ...string) Condition {
+	if len(compatibles) == 0 {
+		return Condition{Err: errors.New("DeviceTreeCompatible requires at least one compatible string")}
+	}
+	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
+		for _, dc := range f.GetDeviceTreeCompatible() {
+			for _, c := range compatibles {
+				if dc == c {
+					return satisfied()
+				}
+			}
+		}
+		return unsatisfied(fmt.Sprintf("Device tree is not compatible with any of %q", compatibles))
+	}}
+}
+
+// DLCInstalled returns a hardware dependency condition that is satisfied if
+// and only if all of the given DLCs (downloadable content), e.g.
+// "sane-backends-pfu", are installed on the DUT.
+func DLCInstalled(ids ...string) Condition {
+	if len(ids) == 0 {
+		return Condition{Err: errors.New("DLCInstalled requires at least one DLC ID")}
+	}
+	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
+		dlcs := f.GetInstalledDlcs()
+		for _, id := range ids {
+			if _, ok := dlcs[id]; !ok {
+				return unsatisfied(fmt.Sprintf("DLC %s is not installed", id))
+			}
+		}
+		return satisfied()
+	}}
+}
+
+// DLCVersion returns a hardware dependency condition that is satisfied if and
+// only if the DLC with the given ID is installed on the DUT and its version is
+// one of the given versions, e.g. "1.0.0-r10".
+func DLCVersion(id string, versions ...string) Condition {
+	if len(versions) == 0 {
+		return Condition{Err: errors.New("DLCVersion requires at least one version")}
+	}
+	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
+		v, ok := f.GetInstalledDlcs()[id]
+		if !ok {
+			return unsatisfied(fmt.Sprintf("DLC %s is not installed", id))
+		}
+		for _, want := range versions {
+			if v == want {
+				return satisfied()
+			}
+		}
+		return unsatisfied(fmt.Sprintf("DLC %s has version %q, not any of %q", id, v, versions))
+	}}
+}
+
+// Ethernet returns a hardware dependency condition that is satisfied if and
+// only if the DUT has a wired Ethernet interface, either built-in or attached
+// via USB.
+func Ethernet() Condition {
+	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
+		if !f.GetEthernetPresent() {
+			return unsatisfied("DUT has no Ethernet interface")
+		}
+		return satisfied()
+	}}
+}
+
+// MinNICSpeedMbps returns a hardware dependency condition that is satisfied
+// if and only if the DUT has a wired network interface whose link is up at
+// the given speed in Mbps or faster.
+func MinNICSpeedMbps(mbps int) Condition {
+	if mbps < 1 {
+		return Condition{Err: errors.Errorf("MinNICSpeedMbps requires a positive speed; got %d", mbps)}
+	}
+	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
+		if speed := int(f.GetMaxNicSpeedMbps()); speed < mbps {
+			return unsatisfied(fmt.Sprintf("DUT network interfaces are up to %d Mbps; want %d Mbps or faster", speed, mbps))
+		}
+		return satisfied()
+	}}
+}
+
+// WifiBand is a frequency band of Wi-Fi.
+type WifiBand string
+
+// These are frequency bands of Wi-Fi.
+const (
+	WifiBand2_4GHz WifiBand = "2.4GHz"
+	WifiBand5GHz   WifiBand = "5GHz"
+	WifiBand6GHz   WifiBand = "6GHz"
+	WifiBand60GHz  WifiBand = "60GHz"
+)
+
+// WifiPhyBands returns a hardware dependency condition that is satisfied if
+// and only if Wi-Fi PHYs of the DUT support all of the given frequency bands.
+func WifiPhyBands(bands ...WifiBand) Condition {
+	if len(bands) == 0 {
+		return Condition{Err: errors.New("WifiPhyBands requires at least one band")}
+	}
+	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
+		supported := f.GetWifiPhyBands()
+		for _, b := range bands {
+			found := false
+			for _, s := range supported {
+				if s == string(b) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return unsatisfied(fmt.Sprintf("DUT Wi-Fi does not support %s band", b))
+			}
+		}
+		return satisfied()
+	}}
+}
+
+// VMType is a type of virtual machines DUTs run on.
+type VMType string
+
+// These are types of virtual machines DUTs run on.
+const (
+	// VMBetty is the betty board running on QEMU.
+	VMBetty VMType = "betty"
+	// VMTastVM is a generic image, e.g. amd64-generic, run on QEMU by VM
+	// test builders.
+	VMTastVM VMType = "tast_vm"
+	// VMCrosvm is ChromeOS running as a crosvm guest, e.g. in Crostini.
+	VMCrosvm VMType = "crosvm"
+)
+
+// OnVM returns a hardware dependency condition that is satisfied if and only
+// if the DUT is a virtual machine of one of the given types. If no type is
+// given, it is satisfied on any virtual machine.
+func OnVM(types ...VMType) Condition {
+	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
+		vm := VMType(f.GetVmType())
+		if vm == "" {
+			return unsatisfied("DUT is not a virtual machine")
+		}
+		if len(types) > 0 && !vmTypeListed(vm, types) {
+			return unsatisfied(fmt.Sprintf("DUT is a virtual machine of type %s", vm))
+		}
+		return satisfied()
+	}}
+}
+
+// SkipOnVM returns a hardware dependency condition that is satisfied if and
+// only if the DUT is not a virtual machine of any of the given types. If no
+// type is given, it is satisfied only on physical devices.
+//
+// Prefer this to software dependencies on USE flags like "!betty && !tast_vm"
+// to skip tests on virtual machines.
+func SkipOnVM(types ...VMType) Condition {
+	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
+		vm := VMType(f.GetVmType())
+		if vm == "" {
+			return satisfied()
+		}
+		if len(types) == 0 || vmTypeListed(vm, types) {
+			return unsatisfied(fmt.Sprintf("DUT is a virtual machine of type %s", vm))
+		}
+		return satisfied()
+	}}
+}
+
+func vmTypeListed(vm VMType, types []VMType) bool {
+	for _, t := range types {
+		if vm == t {
+			return true
+		}
+	}
+	return false
+}
+
 // Speaker returns a hardware dependency condition that is satisfied if and only if the DUT has a speaker.
 func Speaker() Condition {
 	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
@@ -2148,6 +2805,112 @@ func SOFAudioDSP() Condition {
 	}}
 }
 
+// headphoneCodec returns the name of the audio codec used for the headphones of the DUT.
+// It falls back to the deprecated audio codec field if the headphone codec is unknown.
+func headphoneCodec(audio *configpb.HardwareFeatures_Audio) string {
+	if codec := audio.GetHeadphoneCodec(); codec != configpb.HardwareFeatures_Audio_AUDIO_CODEC_UNKNOWN {
+		return codec.String()
+	}
+	return audio.GetAudioCodec().String()
+}
+
+// AudioCodec returns a hardware dependency condition that is satisfied if and only if the DUT's
+// headphone audio codec is one of the given names, e.g. "RT5682" or "DA7219".
+// The names are those of configpb.HardwareFeatures_Audio_AudioCodec.
+func AudioCodec(names ...string) Condition {
+	for _, n := range names {
+		if _, ok := configpb.HardwareFeatures_Audio_AudioCodec_value[n]; !ok {
+			return Condition{Err: errors.Errorf("unknown audio codec: %q", n)}
+		}
+	}
+	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
+		hf := f.GetHardwareFeatures()
+		if hf == nil {
+			return withErrorStr("Did not find hardware features")
+		}
+		codec := headphoneCodec(hf.GetAudio())
+		for _, n := range names {
+			if n == codec {
+				return satisfied()
+			}
+		}
+		return unsatisfied("Audio codec did not match: " + codec)
+	}}
+}
+
+// SkipOnAudioCodec returns a hardware dependency condition that is satisfied if and only if the
+// DUT's headphone audio codec is none of the given names.
+// Please find the doc of AudioCodec(), too, for details about the expected names.
+func SkipOnAudioCodec(names ...string) Condition {
+	for _, n := range names {
+		if _, ok := configpb.HardwareFeatures_Audio_AudioCodec_value[n]; !ok {
+			return Condition{Err: errors.Errorf("unknown audio codec: %q", n)}
+		}
+	}
+	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
+		hf := f.GetHardwareFeatures()
+		if hf == nil {
+			return withErrorStr("Did not find hardware features")
+		}
+		codec := headphoneCodec(hf.GetAudio())
+		for _, n := range names {
+			if n == codec {
+				return unsatisfied("Audio codec matched with skip-on list: " + codec)
+			}
+		}
+		return satisfied()
+	}}
+}
+
+// SpeakerAmplifier returns a hardware dependency condition that is satisfied if and only if the
+// DUT's speaker amplifier is one of the given names, e.g. "MAX98357" or "RT1015P".
+// Unlike SmartAmp(), it matches any amplifier including ones that are not smart amps.
+// The names are those of configpb.HardwareFeatures_Audio_Amplifier.
+func SpeakerAmplifier(names ...string) Condition {
+	for _, n := range names {
+		if _, ok := configpb.HardwareFeatures_Audio_Amplifier_value[n]; !ok {
+			return Condition{Err: errors.Errorf("unknown speaker amplifier: %q", n)}
+		}
+	}
+	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
+		hf := f.GetHardwareFeatures()
+		if hf == nil {
+			return withErrorStr("Did not find hardware features")
+		}
+		amp := hf.GetAudio().GetSpeakerAmplifier().GetName()
+		for _, n := range names {
+			if n == amp {
+				return satisfied()
+			}
+		}
+		return unsatisfied("Speaker amplifier did not match: " + amp)
+	}}
+}
+
+// SkipOnSpeakerAmplifier returns a hardware dependency condition that is satisfied if and only if
+// the DUT's speaker amplifier is none of the given names.
+// Please find the doc of SpeakerAmplifier(), too, for details about the expected names.
+func SkipOnSpeakerAmplifier(names ...string) Condition {
+	for _, n := range names {
+		if _, ok := configpb.HardwareFeatures_Audio_Amplifier_value[n]; !ok {
+			return Condition{Err: errors.Errorf("unknown speaker amplifier: %q", n)}
+		}
+	}
+	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
+		hf := f.GetHardwareFeatures()
+		if hf == nil {
+			return withErrorStr("Did not find hardware features")
+		}
+		amp := hf.GetAudio().GetSpeakerAmplifier().GetName()
+		for _, n := range names {
+			if n == amp {
+				return unsatisfied("Speaker amplifier matched with skip-on list: " + amp)
+			}
+		}
+		return satisfied()
+	}}
+}
+
 // formFactorListed returns whether the form factor represented by a configpb.HardwareFeatures
 // is listed in the given list of form factor values.
 func formFactorListed(hf *configpb.HardwareFeatures, ffList ...configpb.HardwareFeatures_FormFactor_FormFactorType) bool {
@@ -2610,6 +3373,43 @@ func SkipOnCameraUSBModule(modules ...string) Condition {
 	}}
 }
 
+// cameraISPVersions lists versions of camera ISPs accepted by CameraISP.
+var cameraISPVersions = map[string]struct{}{
+	"IPU6":      {},
+	"IPU6SE":    {},
+	"IPU6EP":    {},
+	"IPU6EPMTL": {},
+	"IPU7":      {},
+}
+
+// CameraISP returns a hardware dependency condition that is satisfied if and
+// only if the DUT's camera image signal processor is one of the given versions,
+// e.g. "IPU6" or "IPU7". A version also matches its variants, e.g. "IPU6"
+// matches "IPU6EP" and "IPU6SE", so that tests supporting a pipeline do not
+// have to list SoCs using it.
+func CameraISP(versions ...string) Condition {
+	for _, v := range versions {
+		if _, ok := cameraISPVersions[v]; !ok {
+			return Condition{Err: errors.Errorf("unknown camera ISP version: %q", v)}
+		}
+	}
+	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
+		if f.GetHardwareFeatures() == nil {
+			return withErrorStr("HardwareFeatures is not given")
+		}
+		isp := f.GetCameraIsp()
+		if isp == "" {
+			return unsatisfied("DUT does not have a known camera ISP")
+		}
+		for _, v := range versions {
+			if strings.HasPrefix(isp, v) {
+				return satisfied()
+			}
+		}
+		return unsatisfied("Camera ISP did not match: " + isp)
+	}}
+}
+
 // ECBuildConfigOptions is satisfied if any of the provided options are enabled.
 // This can be used to check alternative option names,
 // e.g. CONFIG_DEBUG_ASSERT, CONFIG_PLATFORM_EC_DEBUG_ASSERT
@@ -3005,6 +3805,66 @@ func HasPDPort(port uint32) Condition {
 	}
 }
 
+// TypeCSupportsDPAltMode returns a hardware dependency condition that is
+// satisfied if and only if a USB Type-C port of the DUT supports DisplayPort
+// alternate mode.
+func TypeCSupportsDPAltMode() Condition {
+	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
+		if f.GetHardwareFeatures() == nil {
+			return withErrorStr("HardwareFeatures is not given")
+		}
+		if f.GetHasTypecDpAltMode() {
+			return satisfied()
+		}
+		return unsatisfied("DUT does not support USB Type-C DisplayPort alternate mode")
+	}}
+}
+
+// TypeCSupportsTBT returns a hardware dependency condition that is satisfied
+// if and only if a USB Type-C port of the DUT supports Thunderbolt alternate
+// mode.
+func TypeCSupportsTBT() Condition {
+	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
+		if f.GetHardwareFeatures() == nil {
+			return withErrorStr("HardwareFeatures is not given")
+		}
+		if f.GetHasTypecTbtAltMode() {
+			return satisfied()
+		}
+		return unsatisfied("DUT does not support USB Type-C Thunderbolt alternate mode")
+	}}
+}
+
+// TypeCSupportsUSB4 returns a hardware dependency condition that is satisfied
+// if and only if the USB Type-C ports of the DUT support USB4.
+func TypeCSupportsUSB4() Condition {
+	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
+		hf := f.GetHardwareFeatures()
+		if hf == nil {
+			return withErrorStr("Did not find hardware features")
+		}
+		if hf.GetUsbC().GetUsb4() {
+			return satisfied()
+		}
+		return unsatisfied("DUT does not support USB4")
+	}}
+}
+
+// WidevineL1Supported returns a hardware dependency condition that is
+// satisfied if and only if the DUT supports Widevine security level 1, i.e.
+// hardware-backed OEMCrypto is available to the content decryption module.
+func WidevineL1Supported() Condition {
+	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
+		if f.GetHardwareFeatures() == nil {
+			return withErrorStr("HardwareFeatures is not given")
+		}
+		if f.GetHasWidevineL1() {
+			return satisfied()
+		}
+		return unsatisfied("DUT does not support Widevine L1")
+	}}
+}
+
 // AlternativeFirmware returns a hardware dependency condition that is satisfied if and only if the DUT has altfw.
 func AlternativeFirmware() Condition {
 	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
@@ -3193,44 +4053,31 @@ func FirmwareSplashScreen() Condition {
 // MiniDiag returns a hardware dependency condition that is satisfied if and
 // only if the DUT supports minidiag.
 func MiniDiag() Condition {
-	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
-		hf := f.GetHardwareFeatures()
-		if hf == nil {
-			return withErrorStr("HardwareFeatures is not given")
-		}
-		roMajorVersion := hf.GetFwConfig().GetFwRoVersion().MajorVersion
-		roMinorVersion := hf.GetFwConfig().GetFwRoVersion().MinorVersion
-		rwMajorVersion := hf.GetFwConfig().GetFwRwVersion().MajorVersion
-		rwMinorVersion := hf.GetFwConfig().GetFwRwVersion().MinorVersion
-		// Dirinboz launch MiniDiag earlier (crrev/c/2525502) than other
-		// zork variants (crrev/c/2677619).
-		isDirinboz, err := modelListed(f.GetDeprecatedDeviceConfig(), "dirinboz")
-		if err != nil {
-			return withError(err)
-		}
-		/*
-			RO: CL:2282867 landed in 13396.0.0 for most of the boards except:
-				- puff: CL:2353773 landed in firmware-puff-13324.B 13324.35.0
-
-			RW: CL:2617391 landed in 13727.0.0 for most of the boards except:
-				- zork (dirinboz):    CL:2525502 landed in firmware-zork-13434.B 13434.106.0
-				- zork:               CL:2677619 landed in firmware-zork-13434.B 13434.267.0
-				- trogdor:            CL:2677612 landed in firmware-trogdor-13577.B 13577.106.0
-				- dedede:             CL:2677618 landed in firmware-dedede-13606.B 13606.99.0
-				- volteer:            CL:2677615 landed in firmware-volteer-13672.B 13672.109.0
-		*/
-		roCheck := (roMajorVersion >= 13396 || (roMajorVersion == 13324 && roMinorVersion >= 35))
-		rwCheck := (rwMajorVersion >= 13727 ||
-			(rwMajorVersion == 13434 && (rwMinorVersion >= 267 || (isDirinboz && rwMinorVersion >= 106))) ||
-			(rwMajorVersion == 13577 && rwMinorVersion >= 106) ||
-			(rwMajorVersion == 13606 && rwMinorVersion >= 99) ||
-			(rwMajorVersion == 13672 && rwMinorVersion >= 109))
-
-		if roCheck && rwCheck {
-			return satisfied()
-		}
-		return unsatisfied("DUT does not support minidiag")
-	}}
+	/*
+		RO: CL:2282867 landed in 13396.0.0 for most of the boards except:
+			- puff: CL:2353773 landed in firmware-puff-13324.B 13324.35.0
+
+		RW: CL:2617391 landed in 13727.0.0 for most of the boards except:
+			- zork (dirinboz):    CL:2525502 landed in firmware-zork-13434.B 13434.106.0
+			- zork:               CL:2677619 landed in firmware-zork-13434.B 13434.267.0
+			- trogdor:            CL:2677612 landed in firmware-trogdor-13577.B 13577.106.0
+			- dedede:             CL:2677618 landed in firmware-dedede-13606.B 13606.99.0
+			- volteer:            CL:2677615 landed in firmware-volteer-13672.B 13672.109.0
+	*/
+	return firmwareFeature("DUT does not support minidiag",
+		FirmwareVersionAtLeast(FirmwareRO, 13396,
+			FirmwareBranch{Major: 13324, Minor: 35},
+		),
+		FirmwareVersionAtLeast(FirmwareRW, 13727,
+			// Dirinboz launch MiniDiag earlier (crrev/c/2525502) than other
+			// zork variants (crrev/c/2677619).
+			FirmwareBranch{Major: 13434, Minor: 106, Models: []string{"dirinboz"}},
+			FirmwareBranch{Major: 13434, Minor: 267},
+			FirmwareBranch{Major: 13577, Minor: 106},
+			FirmwareBranch{Major: 13606, Minor: 99},
+			FirmwareBranch{Major: 13672, Minor: 109},
+		),
+	)
 }
 
 // intelUarchTable contains intel uarch names.
@@ -3456,34 +4303,25 @@ func IsIntelUarchEqualOrNewerThan(intelUarchs IntelUarchs) Condition {
 // ECFeatureCbibin returns a hardware dependency condition that is satisfied if and only
 // if the DUT supports `ectool cbibin`.
 func ECFeatureCbibin() Condition {
-	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
-		hf := f.GetHardwareFeatures()
-		if hf == nil {
-			return withErrorStr("HardwareFeatures is not given")
-		}
-		rwMajorVersion := hf.GetFwConfig().GetFwRwVersion().MajorVersion
-		rwMinorVersion := hf.GetFwConfig().GetFwRwVersion().MinorVersion
-
-		/*
-			CL:4936551 landed in 15904.0.0 for most of the boards except:
-				- brya:    landed in firmware-brya-14505.B-main 14505.769.0
-				- nissa:   landed in firmware-nissa-15217.B-main 15217.575.0
-				- dedede:  landed in firmware-dedede-13606.B-master 13606.646.0
-				- corsola: landed in firmware-corsola-15194.B-main 15194.207.0
-				- rex:     landed in firmware-rex-15709.B-main 15709.173.0
-				- geralt:  landed in firmware-geralt-15842.B-main 15842.56.0
-		*/
-		if rwMajorVersion >= 15904 ||
-			(rwMajorVersion == 14505 && rwMinorVersion >= 769) ||
-			(rwMajorVersion == 15217 && rwMinorVersion >= 575) ||
-			(rwMajorVersion == 13606 && rwMinorVersion >= 646) ||
-			(rwMajorVersion == 15194 && rwMinorVersion >= 207) ||
-			(rwMajorVersion == 15709 && rwMinorVersion >= 173) ||
-			(rwMajorVersion == 15842 && rwMinorVersion >= 56) {
-			return satisfied()
-		}
-		return unsatisfied("DUT does not support Cbibin")
-	}}
+	/*
+		CL:4936551 landed in 15904.0.0 for most of the boards except:
+			- brya:    landed in firmware-brya-14505.B-main 14505.769.0
+			- nissa:   landed in firmware-nissa-15217.B-main 15217.575.0
+			- dedede:  landed in firmware-dedede-13606.B-master 13606.646.0
+			- corsola: landed in firmware-corsola-15194.B-main 15194.207.0
+			- rex:     landed in firmware-rex-15709.B-main 15709.173.0
+			- geralt:  landed in firmware-geralt-15842.B-main 15842.56.0
+	*/
+	return firmwareFeature("DUT does not support Cbibin",
+		FirmwareVersionAtLeast(FirmwareRW, 15904,
+			FirmwareBranch{Major: 14505, Minor: 769},
+			FirmwareBranch{Major: 15217, Minor: 575},
+			FirmwareBranch{Major: 13606, Minor: 646},
+			FirmwareBranch{Major: 15194, Minor: 207},
+			FirmwareBranch{Major: 15709, Minor: 173},
+			FirmwareBranch{Major: 15842, Minor: 56},
+		),
+	)
 }
 
 // BackgroundScanning returns a hardware dependency condition that is satisfied if and only
@@ -3534,17 +4372,6 @@ func Usb3Pendrive() Condition {
 
 // MKBPEvent is satisfied if the DUT supports the host command EC_MKBP_EVENT_DP_ALT_MODE_ENTERED.
 func MKBPEvent() Condition {
-	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
-		hf := f.GetHardwareFeatures()
-		if hf == nil {
-			return withErrorStr("HardwareFeatures is not given")
-		}
-		roMajorVersion := hf.GetFwConfig().GetFwRoVersion().MajorVersion
-		// CL:1685787 laned in 12351.0.0
-		if roMajorVersion >= 12351 {
-			return satisfied()
-		}
-
-		return unsatisfied("DUT does not support MKBP event")
-	}}
+	// CL:1685787 laned in 12351.0.0
+	return firmwareFeature("DUT does not support MKBP event", FirmwareVersionAtLeast(FirmwareRO, 12351))
 }
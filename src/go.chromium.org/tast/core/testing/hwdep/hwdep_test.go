@@ -9,6 +9,7 @@ import (
 
 	configpb "go.chromium.org/chromiumos/config/go/api"
 
+	"go.chromium.org/tast/core/testing/bluetooth"
 	"go.chromium.org/tast/core/testing/hwdep"
 	"go.chromium.org/tast/core/testing/wlan"
 
@@ -33,6 +34,26 @@ func verifyCondition(t *testing.T, c hwdep.Condition, dc *frameworkprotocol.Depr
 	}
 }
 
+// verifyFeatures is similar to verifyCondition, but takes f including
+// features outside configpb.HardwareFeatures.
+func verifyFeatures(t *testing.T, c hwdep.Condition, f *frameworkprotocol.HardwareFeatures, expectSatisfied bool) {
+	t.Helper()
+
+	satisfied, reason, err := c.Satisfied(f)
+	if err != nil {
+		t.Error("Error while evaluating condition: ", err)
+	}
+	if expectSatisfied {
+		if !satisfied {
+			t.Error("Unexpectedly unsatisfied: ", reason)
+		}
+	} else {
+		if satisfied {
+			t.Error("Unexpectedly satisfied")
+		}
+	}
+}
+
 func expectError(t *testing.T, c hwdep.Condition, dc *frameworkprotocol.DeprecatedDeviceConfig, features *configpb.HardwareFeatures) {
 	t.Helper()
 	_, _, err := c.Satisfied(&frameworkprotocol.HardwareFeatures{HardwareFeatures: features, DeprecatedDeviceConfig: dc})
@@ -355,6 +376,215 @@ func TestFingerprintDiagSupported(t *testing.T) {
 		nil)
 }
 
+func TestFPMCUFirmwareAtLeast(t *testing.T) {
+	c := hwdep.FPMCUFirmwareAtLeast("2.2.110")
+
+	for _, tc := range []struct {
+		present bool
+		version string
+		want    bool
+	}{
+		{false, "nocturne_fp_v2.2.110-b936c0a3c", false},
+		{true, "", false},
+		{true, "nocturne_fp_v2.2.64-58cf5974e", false},
+		{true, "nocturne_fp_v2.2.110-b936c0a3c", true},
+		{true, "nocturne_fp_v2.3.0-0123456789", true},
+		{true, "nocturne_fp_v2.2-0123456789", false},
+		{true, "bloonchipper_v10.0.1-0123456789", true},
+		{true, "unparsable", false},
+	} {
+		hf := &frameworkprotocol.HardwareFeatures{
+			HardwareFeatures: &configpb.HardwareFeatures{
+				Fingerprint: &configpb.HardwareFeatures_Fingerprint{Present: tc.present},
+			},
+			FpmcuRwVersion: tc.version,
+		}
+		satisfied, _, err := c.Satisfied(hf)
+		if err != nil {
+			t.Errorf("Error while evaluating condition for %q: %v", tc.version, err)
+		} else if satisfied != tc.want {
+			t.Errorf("Satisfied for (%v, %q) = %v; want %v", tc.present, tc.version, satisfied, tc.want)
+		}
+	}
+	expectError(
+		t, c,
+		&frameworkprotocol.DeprecatedDeviceConfig{},
+		nil)
+
+	for _, v := range []string{"", "v2.2.110", "2.2.x"} {
+		if c := hwdep.FPMCUFirmwareAtLeast(v); c.Err == nil {
+			t.Errorf("FPMCUFirmwareAtLeast(%q) unexpectedly succeeded", v)
+		}
+	}
+}
+
+func TestFPMCUHasRollbackSupport(t *testing.T) {
+	c := hwdep.FPMCUHasRollbackSupport()
+
+	for _, tc := range []struct {
+		present  bool
+		rollback bool
+		want     bool
+	}{
+		{false, false, false},
+		{false, true, false},
+		{true, false, false},
+		{true, true, true},
+	} {
+		hf := &frameworkprotocol.HardwareFeatures{
+			HardwareFeatures: &configpb.HardwareFeatures{
+				Fingerprint: &configpb.HardwareFeatures_Fingerprint{Present: tc.present},
+			},
+			FpmcuRollbackSupported: tc.rollback,
+		}
+		satisfied, _, err := c.Satisfied(hf)
+		if err != nil {
+			t.Errorf("Error while evaluating condition for (%v, %v): %v", tc.present, tc.rollback, err)
+		} else if satisfied != tc.want {
+			t.Errorf("Satisfied for (%v, %v) = %v; want %v", tc.present, tc.rollback, satisfied, tc.want)
+		}
+	}
+	expectError(
+		t, c,
+		&frameworkprotocol.DeprecatedDeviceConfig{},
+		nil)
+}
+
+func TestSDCardReader(t *testing.T) {
+	c := hwdep.SDCardReader()
+
+	for _, tc := range []struct {
+		present bool
+		want    bool
+	}{
+		{false, false},
+		{true, true},
+	} {
+		hf := &frameworkprotocol.HardwareFeatures{
+			HardwareFeatures:    &configpb.HardwareFeatures{},
+			SdCardReaderPresent: tc.present,
+		}
+		satisfied, _, err := c.Satisfied(hf)
+		if err != nil {
+			t.Errorf("Error while evaluating condition for %v: %v", tc.present, err)
+		} else if satisfied != tc.want {
+			t.Errorf("Satisfied for %v = %v; want %v", tc.present, satisfied, tc.want)
+		}
+	}
+	expectError(
+		t, c,
+		&frameworkprotocol.DeprecatedDeviceConfig{},
+		nil)
+}
+
+func TestSDCardInserted(t *testing.T) {
+	c := hwdep.SDCardInserted()
+
+	for _, tc := range []struct {
+		present  bool
+		inserted bool
+		want     bool
+	}{
+		{false, false, false},
+		{true, false, false},
+		{true, true, true},
+	} {
+		hf := &frameworkprotocol.HardwareFeatures{
+			HardwareFeatures:    &configpb.HardwareFeatures{},
+			SdCardReaderPresent: tc.present,
+			SdCardInserted:      tc.inserted,
+		}
+		satisfied, _, err := c.Satisfied(hf)
+		if err != nil {
+			t.Errorf("Error while evaluating condition for (%v, %v): %v", tc.present, tc.inserted, err)
+		} else if satisfied != tc.want {
+			t.Errorf("Satisfied for (%v, %v) = %v; want %v", tc.present, tc.inserted, satisfied, tc.want)
+		}
+	}
+	expectError(
+		t, c,
+		&frameworkprotocol.DeprecatedDeviceConfig{},
+		nil)
+}
+
+func TestBluetoothDevice(t *testing.T) {
+	c := hwdep.BluetoothDevice(bluetooth.IntelAX211, bluetooth.Realtek8852B)
+	s := hwdep.SkipOnBluetoothDevice(bluetooth.IntelAX211, bluetooth.Realtek8852B)
+
+	for _, tc := range []struct {
+		device   bluetooth.DeviceID
+		wantC    bool
+		wantSkip bool
+	}{
+		{bluetooth.IntelAX211, true, false},
+		{bluetooth.Realtek8852B, true, false},
+		{bluetooth.MediaTekMT7921, false, true},
+		// Unknown devices never match either condition.
+		{bluetooth.UnknownDevice, false, true},
+	} {
+		hf := &frameworkprotocol.HardwareFeatures{
+			HardwareFeatures: &configpb.HardwareFeatures{},
+			BluetoothDevice:  int32(tc.device),
+		}
+		for _, cc := range []struct {
+			name string
+			c    hwdep.Condition
+			want bool
+		}{
+			{"BluetoothDevice", c, tc.wantC},
+			{"SkipOnBluetoothDevice", s, tc.wantSkip},
+		} {
+			satisfied, _, err := cc.c.Satisfied(hf)
+			if err != nil {
+				t.Errorf("Error while evaluating %s for %v: %v", cc.name, tc.device, err)
+			} else if satisfied != cc.want {
+				t.Errorf("%s satisfied for %v = %v; want %v", cc.name, tc.device, satisfied, cc.want)
+			}
+		}
+	}
+}
+
+func TestBluetoothVersionAtLeast(t *testing.T) {
+	c := hwdep.BluetoothVersionAtLeast("5.2")
+
+	for _, tc := range []struct {
+		present configpb.HardwareFeatures_Present
+		version string
+		want    bool
+	}{
+		{configpb.HardwareFeatures_NOT_PRESENT, "", false},
+		{configpb.HardwareFeatures_PRESENT, "", false},
+		{configpb.HardwareFeatures_PRESENT, "5.1", false},
+		{configpb.HardwareFeatures_PRESENT, "4.2", false},
+		{configpb.HardwareFeatures_PRESENT, "5.2", true},
+		{configpb.HardwareFeatures_PRESENT, "5.10", true},
+		{configpb.HardwareFeatures_PRESENT, "6.0", true},
+	} {
+		hf := &frameworkprotocol.HardwareFeatures{
+			HardwareFeatures: &configpb.HardwareFeatures{
+				Bluetooth: &configpb.HardwareFeatures_Bluetooth{Present: tc.present},
+			},
+			BluetoothVersion: tc.version,
+		}
+		satisfied, _, err := c.Satisfied(hf)
+		if err != nil {
+			t.Errorf("Error while evaluating condition for (%v, %q): %v", tc.present, tc.version, err)
+		} else if satisfied != tc.want {
+			t.Errorf("Satisfied for (%v, %q) = %v; want %v", tc.present, tc.version, satisfied, tc.want)
+		}
+	}
+	expectError(
+		t, c,
+		&frameworkprotocol.DeprecatedDeviceConfig{},
+		nil)
+
+	for _, v := range []string{"", "5", "5.2.1", "v5.2"} {
+		if c := hwdep.BluetoothVersionAtLeast(v); c.Err == nil {
+			t.Errorf("BluetoothVersionAtLeast(%q) unexpectedly succeeded", v)
+		}
+	}
+}
+
 func TestInternalDisplay(t *testing.T) {
 	c := hwdep.InternalDisplay()
 
@@ -377,6 +607,69 @@ func TestInternalDisplay(t *testing.T) {
 	}
 }
 
+func TestMinInternalDisplayResolution(t *testing.T) {
+	c := hwdep.MinInternalDisplayResolution(3840, 2160)
+
+	for _, tc := range []struct {
+		PanelProperties *configpb.Component_DisplayPanel_Properties
+		expectSatisfied bool
+	}{
+		{nil, false},
+		{&configpb.Component_DisplayPanel_Properties{}, false},
+		{&configpb.Component_DisplayPanel_Properties{WidthPx: 1920, HeightPx: 1080}, false},
+		{&configpb.Component_DisplayPanel_Properties{WidthPx: 3840, HeightPx: 1600}, false},
+		{&configpb.Component_DisplayPanel_Properties{WidthPx: 3840, HeightPx: 2160}, true},
+		{&configpb.Component_DisplayPanel_Properties{WidthPx: 2160, HeightPx: 3840}, true},
+		{&configpb.Component_DisplayPanel_Properties{WidthPx: 3840, HeightPx: 2400}, true},
+	} {
+		verifyCondition(
+			t, c,
+			&frameworkprotocol.DeprecatedDeviceConfig{},
+			&configpb.HardwareFeatures{
+				Screen: &configpb.HardwareFeatures_Screen{
+					PanelProperties: tc.PanelProperties,
+				},
+			},
+			tc.expectSatisfied)
+	}
+	expectError(
+		t, c,
+		&frameworkprotocol.DeprecatedDeviceConfig{},
+		nil)
+}
+
+func TestInternalDisplayRefreshRateAtLeast(t *testing.T) {
+	c := hwdep.InternalDisplayRefreshRateAtLeast(120)
+
+	for _, tc := range []struct {
+		PanelProperties *configpb.Component_DisplayPanel_Properties
+		refreshRateHz   uint32
+		expectSatisfied bool
+	}{
+		{nil, 120, false},
+		{&configpb.Component_DisplayPanel_Properties{}, 0, false},
+		{&configpb.Component_DisplayPanel_Properties{}, 60, false},
+		{&configpb.Component_DisplayPanel_Properties{}, 120, true},
+		{&configpb.Component_DisplayPanel_Properties{}, 144, true},
+	} {
+		verifyFeatures(
+			t, c,
+			&frameworkprotocol.HardwareFeatures{
+				HardwareFeatures: &configpb.HardwareFeatures{
+					Screen: &configpb.HardwareFeatures_Screen{
+						PanelProperties: tc.PanelProperties,
+					},
+				},
+				InternalDisplayRefreshRateHz: tc.refreshRateHz,
+			},
+			tc.expectSatisfied)
+	}
+	expectError(
+		t, c,
+		&frameworkprotocol.DeprecatedDeviceConfig{},
+		nil)
+}
+
 func TestEmmcStorage(t *testing.T) {
 	c := hwdep.Emmc()
 
@@ -749,6 +1042,297 @@ func TestMinMemory(t *testing.T) {
 		nil)
 }
 
+func TestMinGPUMemoryMB(t *testing.T) {
+	c := hwdep.MinGPUMemoryMB(4096)
+	for _, tc := range []struct {
+		sizeMb          int32
+		expectSatisfied bool
+	}{
+		{0, false}, // The size is unknown.
+		{2048, false},
+		{4096, true},
+		{8192, true},
+	} {
+		satisfied, _, err := c.Satisfied(&frameworkprotocol.HardwareFeatures{GpuMemoryMegabytes: tc.sizeMb})
+		if err != nil {
+			t.Errorf("Error while evaluating condition for %dMB: %v", tc.sizeMb, err)
+		} else if satisfied != tc.expectSatisfied {
+			t.Errorf("Satisfied for %dMB = %v; want %v", tc.sizeMb, satisfied, tc.expectSatisfied)
+		}
+	}
+}
+
+func TestThunderboltDockAttached(t *testing.T) {
+	c := hwdep.ThunderboltDockAttached()
+	for _, attached := range []bool{false, true} {
+		satisfied, _, err := c.Satisfied(&frameworkprotocol.HardwareFeatures{ThunderboltDockAttached: attached})
+		if err != nil {
+			t.Errorf("Error while evaluating condition for attached=%v: %v", attached, err)
+		} else if satisfied != attached {
+			t.Errorf("Satisfied for attached=%v = %v; want %v", attached, satisfied, attached)
+		}
+	}
+}
+
+func TestExternalGPUAttached(t *testing.T) {
+	c := hwdep.ExternalGPUAttached()
+	for _, attached := range []bool{false, true} {
+		satisfied, _, err := c.Satisfied(&frameworkprotocol.HardwareFeatures{ExternalGpuAttached: attached})
+		if err != nil {
+			t.Errorf("Error while evaluating condition for attached=%v: %v", attached, err)
+		} else if satisfied != attached {
+			t.Errorf("Satisfied for attached=%v = %v; want %v", attached, satisfied, attached)
+		}
+	}
+}
+
+func TestKernelModuleLoaded(t *testing.T) {
+	c := hwdep.KernelModuleLoaded("snd-hda-intel", "btusb")
+	for _, tc := range []struct {
+		modules []string
+		want    bool
+	}{
+		{nil, false},
+		{[]string{"btusb"}, false},
+		{[]string{"snd_hda_intel", "bluetooth", "btusb"}, true},
+	} {
+		satisfied, _, err := c.Satisfied(&frameworkprotocol.HardwareFeatures{KernelModules: tc.modules})
+		if err != nil {
+			t.Errorf("Error while evaluating condition for %q: %v", tc.modules, err)
+		} else if satisfied != tc.want {
+			t.Errorf("Satisfied for %q = %v; want %v", tc.modules, satisfied, tc.want)
+		}
+	}
+
+	if c := hwdep.KernelModuleLoaded(); c.Err == nil {
+		t.Error("KernelModuleLoaded() unexpectedly succeeded")
+	}
+}
+
+func TestDeviceTreeCompatible(t *testing.T) {
+	c := hwdep.DeviceTreeCompatible("mediatek,mt8183", "qcom,sc7180")
+	for _, tc := range []struct {
+		compatible []string
+		want       bool
+	}{
+		{nil, false},
+		{[]string{"google,trogdor", "qcom,sc7280"}, false},
+		{[]string{"google,krane", "mediatek,mt8183"}, true},
+	} {
+		satisfied, _, err := c.Satisfied(&frameworkprotocol.HardwareFeatures{DeviceTreeCompatible: tc.compatible})
+		if err != nil {
+			t.Errorf("Error while evaluating condition for %q: %v", tc.compatible, err)
+		} else if satisfied != tc.want {
+			t.Errorf("Satisfied for %q = %v; want %v", tc.compatible, satisfied, tc.want)
+		}
+	}
+
+	if c := hwdep.DeviceTreeCompatible(); c.Err == nil {
+		t.Error("DeviceTreeCompatible() unexpectedly succeeded")
+	}
+}
+
+func TestDLCInstalled(t *testing.T) {
+	c := hwdep.DLCInstalled("sane-backends-pfu", "termina-dlc")
+	for _, tc := range []struct {
+		dlcs map[string]string
+		want bool
+	}{
+		{nil, false},
+		{map[string]string{"sane-backends-pfu": "1.0.0-r10"}, false},
+		{map[string]string{"sane-backends-pfu": "1.0.0-r10", "termina-dlc": ""}, true},
+	} {
+		satisfied, _, err := c.Satisfied(&frameworkprotocol.HardwareFeatures{InstalledDlcs: tc.dlcs})
+		if err != nil {
+			t.Errorf("Error while evaluating condition for %q: %v", tc.dlcs, err)
+		} else if satisfied != tc.want {
+			t.Errorf("Satisfied for %q = %v; want %v", tc.dlcs, satisfied, tc.want)
+		}
+	}
+
+	if c := hwdep.DLCInstalled(); c.Err == nil {
+		t.Error("DLCInstalled() unexpectedly succeeded")
+	}
+}
+
+func TestDLCVersion(t *testing.T) {
+	c := hwdep.DLCVersion("sane-backends-pfu", "1.0.0-r10", "1.0.0-r11")
+	for _, tc := range []struct {
+		dlcs map[string]string
+		want bool
+	}{
+		{nil, false},
+		{map[string]string{"sane-backends-pfu": "1.0.0-r9"}, false},
+		{map[string]string{"termina-dlc": "1.0.0-r10"}, false},
+		{map[string]string{"sane-backends-pfu": "1.0.0-r11"}, true},
+	} {
+		satisfied, _, err := c.Satisfied(&frameworkprotocol.HardwareFeatures{InstalledDlcs: tc.dlcs})
+		if err != nil {
+			t.Errorf("Error while evaluating condition for %q: %v", tc.dlcs, err)
+		} else if satisfied != tc.want {
+			t.Errorf("Satisfied for %q = %v; want %v", tc.dlcs, satisfied, tc.want)
+		}
+	}
+
+	if c := hwdep.DLCVersion("sane-backends-pfu"); c.Err == nil {
+		t.Error("DLCVersion without versions unexpectedly succeeded")
+	}
+}
+
+func TestEthernet(t *testing.T) {
+	c := hwdep.Ethernet()
+	for _, tc := range []struct {
+		present bool
+		want    bool
+	}{
+		{false, false},
+		{true, true},
+	} {
+		satisfied, _, err := c.Satisfied(&frameworkprotocol.HardwareFeatures{EthernetPresent: tc.present})
+		if err != nil {
+			t.Errorf("Error while evaluating condition for %v: %v", tc.present, err)
+		} else if satisfied != tc.want {
+			t.Errorf("Satisfied for %v = %v; want %v", tc.present, satisfied, tc.want)
+		}
+	}
+}
+
+func TestMinNICSpeedMbps(t *testing.T) {
+	c := hwdep.MinNICSpeedMbps(1000)
+	for _, tc := range []struct {
+		speed int32
+		want  bool
+	}{
+		{0, false},
+		{100, false},
+		{1000, true},
+		{2500, true},
+	} {
+		satisfied, _, err := c.Satisfied(&frameworkprotocol.HardwareFeatures{MaxNicSpeedMbps: tc.speed})
+		if err != nil {
+			t.Errorf("Error while evaluating condition for %d: %v", tc.speed, err)
+		} else if satisfied != tc.want {
+			t.Errorf("Satisfied for %d = %v; want %v", tc.speed, satisfied, tc.want)
+		}
+	}
+
+	if c := hwdep.MinNICSpeedMbps(0); c.Err == nil {
+		t.Error("MinNICSpeedMbps(0) unexpectedly succeeded")
+	}
+}
+
+func TestWifiPhyBands(t *testing.T) {
+	c := hwdep.WifiPhyBands(hwdep.WifiBand5GHz, hwdep.WifiBand6GHz)
+	for _, tc := range []struct {
+		bands []string
+		want  bool
+	}{
+		{nil, false},
+		{[]string{"2.4GHz", "5GHz"}, false},
+		{[]string{"2.4GHz", "5GHz", "6GHz"}, true},
+	} {
+		satisfied, _, err := c.Satisfied(&frameworkprotocol.HardwareFeatures{WifiPhyBands: tc.bands})
+		if err != nil {
+			t.Errorf("Error while evaluating condition for %q: %v", tc.bands, err)
+		} else if satisfied != tc.want {
+			t.Errorf("Satisfied for %q = %v; want %v", tc.bands, satisfied, tc.want)
+		}
+	}
+
+	if c := hwdep.WifiPhyBands(); c.Err == nil {
+		t.Error("WifiPhyBands without bands unexpectedly succeeded")
+	}
+}
+
+func TestOnVM(t *testing.T) {
+	for _, tc := range []struct {
+		c    hwdep.Condition
+		vm   string
+		want bool
+	}{
+		{hwdep.OnVM(), "", false},
+		{hwdep.OnVM(), "betty", true},
+		{hwdep.OnVM(hwdep.VMBetty, hwdep.VMTastVM), "tast_vm", true},
+		{hwdep.OnVM(hwdep.VMBetty, hwdep.VMTastVM), "crosvm", false},
+		{hwdep.OnVM(hwdep.VMCrosvm), "", false},
+	} {
+		satisfied, _, err := tc.c.Satisfied(&frameworkprotocol.HardwareFeatures{VmType: tc.vm})
+		if err != nil {
+			t.Errorf("Error while evaluating condition for %q: %v", tc.vm, err)
+		} else if satisfied != tc.want {
+			t.Errorf("Satisfied for %q = %v; want %v", tc.vm, satisfied, tc.want)
+		}
+	}
+}
+
+func TestSkipOnVM(t *testing.T) {
+	for _, tc := range []struct {
+		c    hwdep.Condition
+		vm   string
+		want bool
+	}{
+		{hwdep.SkipOnVM(), "", true},
+		{hwdep.SkipOnVM(), "crosvm", false},
+		{hwdep.SkipOnVM(hwdep.VMBetty, hwdep.VMTastVM), "betty", false},
+		{hwdep.SkipOnVM(hwdep.VMBetty, hwdep.VMTastVM), "crosvm", true},
+		{hwdep.SkipOnVM(hwdep.VMCrosvm), "", true},
+	} {
+		satisfied, _, err := tc.c.Satisfied(&frameworkprotocol.HardwareFeatures{VmType: tc.vm})
+		if err != nil {
+			t.Errorf("Error while evaluating condition for %q: %v", tc.vm, err)
+		} else if satisfied != tc.want {
+			t.Errorf("Satisfied for %q = %v; want %v", tc.vm, satisfied, tc.want)
+		}
+	}
+}
+
+func TestExternalDisplayCount(t *testing.T) {
+	c := hwdep.ExternalDisplayCount(2)
+	for _, tc := range []struct {
+		connectors []string
+		want       bool
+	}{
+		{nil, false},
+		{[]string{"HDMI"}, false},
+		{[]string{"HDMI", "USB-C"}, true},
+	} {
+		satisfied, _, err := c.Satisfied(&frameworkprotocol.HardwareFeatures{ExternalDisplayConnectors: tc.connectors})
+		if err != nil {
+			t.Errorf("Error while evaluating condition for %q: %v", tc.connectors, err)
+		} else if satisfied != tc.want {
+			t.Errorf("Satisfied for %q = %v; want %v", tc.connectors, satisfied, tc.want)
+		}
+	}
+
+	if c := hwdep.ExternalDisplayCount(0); c.Err == nil {
+		t.Error("ExternalDisplayCount(0) unexpectedly succeeded")
+	}
+}
+
+func TestExternalDisplayConnector(t *testing.T) {
+	c := hwdep.ExternalDisplayConnector(hwdep.DPConnector, hwdep.USBCConnector)
+	for _, tc := range []struct {
+		connectors []string
+		want       bool
+	}{
+		{nil, false},
+		{[]string{"HDMI"}, false},
+		{[]string{"HDMI", "USB-C"}, true},
+		{[]string{"DP"}, true},
+	} {
+		satisfied, _, err := c.Satisfied(&frameworkprotocol.HardwareFeatures{ExternalDisplayConnectors: tc.connectors})
+		if err != nil {
+			t.Errorf("Error while evaluating condition for %q: %v", tc.connectors, err)
+		} else if satisfied != tc.want {
+			t.Errorf("Satisfied for %q = %v; want %v", tc.connectors, satisfied, tc.want)
+		}
+	}
+
+	if c := hwdep.ExternalDisplayConnector(); c.Err == nil {
+		t.Error("ExternalDisplayConnector() unexpectedly succeeded")
+	}
+}
+
 func TestMicrophone(t *testing.T) {
 	c := hwdep.Microphone()
 
@@ -902,6 +1486,123 @@ func TestSmartAmpBootTimeCalibration(t *testing.T) {
 		nil)
 }
 
+func TestAudioCodec(t *testing.T) {
+	c := hwdep.AudioCodec("RT5682", "DA7219")
+
+	for _, tc := range []struct {
+		features        *configpb.HardwareFeatures
+		expectSatisfied bool
+	}{
+		{&configpb.HardwareFeatures{}, false},
+		{&configpb.HardwareFeatures{
+			Audio: &configpb.HardwareFeatures_Audio{
+				HeadphoneCodec: configpb.HardwareFeatures_Audio_RT5682,
+			},
+		}, true},
+		{&configpb.HardwareFeatures{
+			Audio: &configpb.HardwareFeatures_Audio{
+				AudioCodec: configpb.HardwareFeatures_Audio_DA7219,
+			},
+		}, true},
+		{&configpb.HardwareFeatures{
+			Audio: &configpb.HardwareFeatures_Audio{
+				HeadphoneCodec: configpb.HardwareFeatures_Audio_CS42L42,
+				AudioCodec:     configpb.HardwareFeatures_Audio_DA7219,
+			},
+		}, false},
+	} {
+		verifyCondition(
+			t, c,
+			&frameworkprotocol.DeprecatedDeviceConfig{},
+			tc.features,
+			tc.expectSatisfied)
+	}
+	expectError(
+		t, c,
+		&frameworkprotocol.DeprecatedDeviceConfig{},
+		nil)
+
+	if c := hwdep.AudioCodec("rt5682"); c.Err == nil {
+		t.Error("AudioCodec unexpectedly accepted an unknown codec")
+	}
+}
+
+func TestSkipOnAudioCodec(t *testing.T) {
+	c := hwdep.SkipOnAudioCodec("RT5682")
+
+	for _, tc := range []struct {
+		features        *configpb.HardwareFeatures
+		expectSatisfied bool
+	}{
+		{&configpb.HardwareFeatures{}, true},
+		{&configpb.HardwareFeatures{
+			Audio: &configpb.HardwareFeatures_Audio{
+				HeadphoneCodec: configpb.HardwareFeatures_Audio_RT5682,
+			},
+		}, false},
+		{&configpb.HardwareFeatures{
+			Audio: &configpb.HardwareFeatures_Audio{
+				HeadphoneCodec: configpb.HardwareFeatures_Audio_DA7219,
+			},
+		}, true},
+	} {
+		verifyCondition(
+			t, c,
+			&frameworkprotocol.DeprecatedDeviceConfig{},
+			tc.features,
+			tc.expectSatisfied)
+	}
+	expectError(
+		t, c,
+		&frameworkprotocol.DeprecatedDeviceConfig{},
+		nil)
+}
+
+func TestSpeakerAmplifier(t *testing.T) {
+	c := hwdep.SpeakerAmplifier("MAX98357")
+	skip := hwdep.SkipOnSpeakerAmplifier("MAX98357")
+
+	for _, tc := range []struct {
+		features        *configpb.HardwareFeatures
+		expectSatisfied bool
+	}{
+		{&configpb.HardwareFeatures{}, false},
+		{&configpb.HardwareFeatures{
+			Audio: &configpb.HardwareFeatures_Audio{
+				SpeakerAmplifier: &configpb.Component_Amplifier{
+					Name: configpb.HardwareFeatures_Audio_MAX98357.String(),
+				},
+			},
+		}, true},
+		{&configpb.HardwareFeatures{
+			Audio: &configpb.HardwareFeatures_Audio{
+				SpeakerAmplifier: &configpb.Component_Amplifier{
+					Name: configpb.HardwareFeatures_Audio_RT1015P.String(),
+				},
+			},
+		}, false},
+	} {
+		verifyCondition(
+			t, c,
+			&frameworkprotocol.DeprecatedDeviceConfig{},
+			tc.features,
+			tc.expectSatisfied)
+		verifyCondition(
+			t, skip,
+			&frameworkprotocol.DeprecatedDeviceConfig{},
+			tc.features,
+			!tc.expectSatisfied)
+	}
+	expectError(
+		t, c,
+		&frameworkprotocol.DeprecatedDeviceConfig{},
+		nil)
+
+	if c := hwdep.SkipOnSpeakerAmplifier("max98357"); c.Err == nil {
+		t.Error("SkipOnSpeakerAmplifier unexpectedly accepted an unknown amplifier")
+	}
+}
+
 func TestDisplayPortConverter(t *testing.T) {
 	c := hwdep.DisplayPortConverter("PS175", "RTD2142")
 
@@ -1579,6 +2280,205 @@ func TestHasSideVolumeButton(t *testing.T) {
 		nil)
 }
 
+func TestECProtocolVersionAtLeast(t *testing.T) {
+	c := hwdep.ECProtocolVersionAtLeast(3)
+
+	for _, tc := range []struct {
+		version         uint32
+		expectSatisfied bool
+	}{
+		{0, false},
+		{2, false},
+		{3, true},
+		{4, true},
+	} {
+		verifyFeatures(
+			t, c,
+			&frameworkprotocol.HardwareFeatures{
+				HardwareFeatures:  &configpb.HardwareFeatures{},
+				EcProtocolVersion: tc.version,
+			},
+			tc.expectSatisfied)
+	}
+	expectError(
+		t, c,
+		nil,
+		nil)
+}
+
+func TestModemFirmwareAtLeast(t *testing.T) {
+	c := hwdep.ModemFirmwareAtLeast("18500.5001.00.05.27.12_5000.00.00.00")
+
+	for _, tc := range []struct {
+		present         configpb.HardwareFeatures_Present
+		version         string
+		expectSatisfied bool
+	}{
+		{configpb.HardwareFeatures_NOT_PRESENT, "", false},
+		{configpb.HardwareFeatures_PRESENT, "", false},
+		{configpb.HardwareFeatures_PRESENT, "18500.5001.00.05.27.11_5000.00.00.00", false},
+		{configpb.HardwareFeatures_PRESENT, "18500.5001.00.05.27.12_5000.00.00.00", true},
+		{configpb.HardwareFeatures_PRESENT, "18500.5001.00.05.27.13_5000.00.00.00", true},
+		{configpb.HardwareFeatures_PRESENT, "18500.5001.00.05.27.12", false},
+		{configpb.HardwareFeatures_PRESENT, "18500.5001.00.06.10.01_5000.00.00.00", true},
+		{configpb.HardwareFeatures_PRESENT, "18600.5001.00.01.01.01_5000.00.00.00", true},
+	} {
+		verifyFeatures(
+			t, c,
+			&frameworkprotocol.HardwareFeatures{
+				HardwareFeatures: &configpb.HardwareFeatures{
+					Cellular: &configpb.HardwareFeatures_Cellular{
+						Present: tc.present,
+					},
+				},
+				ModemFirmwareVersion: tc.version,
+			},
+			tc.expectSatisfied)
+	}
+	expectError(
+		t, c,
+		nil,
+		nil)
+
+	if c := hwdep.ModemFirmwareAtLeast("abc"); c.Err == nil {
+		t.Error("ModemFirmwareAtLeast unexpectedly accepted a version without numbers")
+	}
+}
+
+func TestCameraISP(t *testing.T) {
+	c := hwdep.CameraISP("IPU6", "IPU7")
+
+	for _, tc := range []struct {
+		isp             string
+		expectSatisfied bool
+	}{
+		{"", false},
+		{"IPU6", true},
+		{"IPU6EP", true},
+		{"IPU7", true},
+	} {
+		verifyFeatures(
+			t, c,
+			&frameworkprotocol.HardwareFeatures{
+				HardwareFeatures: &configpb.HardwareFeatures{},
+				CameraIsp:        tc.isp,
+			},
+			tc.expectSatisfied)
+	}
+	verifyFeatures(
+		t, hwdep.CameraISP("IPU6EP"),
+		&frameworkprotocol.HardwareFeatures{
+			HardwareFeatures: &configpb.HardwareFeatures{},
+			CameraIsp:        "IPU6SE",
+		},
+		false)
+	expectError(
+		t, c,
+		nil,
+		nil)
+
+	if c := hwdep.CameraISP("IPU5"); c.Err == nil {
+		t.Error("CameraISP unexpectedly accepted an unknown version")
+	}
+}
+
+func TestTypeCSupportsDPAltMode(t *testing.T) {
+	c := hwdep.TypeCSupportsDPAltMode()
+
+	for _, tc := range []struct {
+		hasDPAltMode    bool
+		expectSatisfied bool
+	}{
+		{true, true},
+		{false, false},
+	} {
+		verifyFeatures(
+			t, c,
+			&frameworkprotocol.HardwareFeatures{
+				HardwareFeatures:  &configpb.HardwareFeatures{},
+				HasTypecDpAltMode: tc.hasDPAltMode,
+			},
+			tc.expectSatisfied)
+	}
+	expectError(
+		t, c,
+		nil,
+		nil)
+}
+
+func TestTypeCSupportsTBT(t *testing.T) {
+	c := hwdep.TypeCSupportsTBT()
+
+	for _, tc := range []struct {
+		hasTBTAltMode   bool
+		expectSatisfied bool
+	}{
+		{true, true},
+		{false, false},
+	} {
+		verifyFeatures(
+			t, c,
+			&frameworkprotocol.HardwareFeatures{
+				HardwareFeatures:   &configpb.HardwareFeatures{},
+				HasTypecTbtAltMode: tc.hasTBTAltMode,
+			},
+			tc.expectSatisfied)
+	}
+	expectError(
+		t, c,
+		nil,
+		nil)
+}
+
+func TestTypeCSupportsUSB4(t *testing.T) {
+	c := hwdep.TypeCSupportsUSB4()
+
+	for _, tc := range []struct {
+		usbC            *configpb.HardwareFeatures_UsbC
+		expectSatisfied bool
+	}{
+		{nil, false},
+		{&configpb.HardwareFeatures_UsbC{}, false},
+		{&configpb.HardwareFeatures_UsbC{Usb4: true}, true},
+	} {
+		verifyCondition(
+			t, c,
+			nil,
+			&configpb.HardwareFeatures{
+				UsbC: tc.usbC,
+			},
+			tc.expectSatisfied)
+	}
+	expectError(
+		t, c,
+		nil,
+		nil)
+}
+
+func TestWidevineL1Supported(t *testing.T) {
+	c := hwdep.WidevineL1Supported()
+
+	for _, tc := range []struct {
+		hasWidevineL1   bool
+		expectSatisfied bool
+	}{
+		{true, true},
+		{false, false},
+	} {
+		verifyFeatures(
+			t, c,
+			&frameworkprotocol.HardwareFeatures{
+				HardwareFeatures: &configpb.HardwareFeatures{},
+				HasWidevineL1:    tc.hasWidevineL1,
+			},
+			tc.expectSatisfied)
+	}
+	expectError(
+		t, c,
+		nil,
+		nil)
+}
+
 func TestHasBaseAccelerometer(t *testing.T) {
 	c := hwdep.BaseAccelerometer()
 
@@ -0,0 +1,572 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package hwdep
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	configpb "go.chromium.org/chromiumos/config/go/api"
+
+	"go.chromium.org/tast/core/errors"
+	"go.chromium.org/tast/core/framework/protocol"
+)
+
+// FromConfigQuery returns a hardware dependency condition that is satisfied
+// if and only if query evaluates to true against the HardwareFeatures proto
+// of the DUT (see go.chromium.org/chromiumos/config/go/api).
+//
+// query is an expression in a small subset of CEL. It allows expressing
+// one-off conditions without adding a dedicated function to this package.
+// Prefer dedicated functions if available, since they give more descriptive
+// reasons when tests are skipped.
+//
+// Fields are referenced by dot-separated paths of proto field names relative
+// to HardwareFeatures, e.g. "screen.panel_properties.width_px". Enum fields
+// are compared with strings of enum value names. The following operators and
+// functions are supported:
+//
+//	== != < <= > >=   comparison of numbers, strings, booleans and enums
+//	&& || !           logical operators
+//	x in [a, b, ...]  membership in a list literal
+//	x in path         membership in a repeated field
+//	size(path)        number of elements of a repeated field
+//	has(path)         presence of a message field
+//
+// Example:
+//
+//	hwdep.FromConfigQuery(`screen.panel_properties.width_px >= 2560 && keyboard.backlight == "PRESENT"`)
+func FromConfigQuery(query string) Condition {
+	root := (&configpb.HardwareFeatures{}).ProtoReflect().Descriptor()
+	n, err := parseQuery(query, root)
+	if err != nil {
+		return Condition{Err: errors.Wrapf(err, "invalid config query %q", query)}
+	}
+	return Condition{Satisfied: func(f *protocol.HardwareFeatures) (bool, string, error) {
+		hf := f.GetHardwareFeatures()
+		if hf == nil {
+			return withErrorStr("HardwareFeatures is not given")
+		}
+		v, err := n.eval(hf.ProtoReflect())
+		if err != nil {
+			return withError(errors.Wrapf(err, "failed to evaluate config query %q", query))
+		}
+		if v.kind != queryBool {
+			return withErrorStr(fmt.Sprintf("config query %q evaluated to a non-boolean value", query))
+		}
+		if !v.b {
+			return unsatisfied(fmt.Sprintf("Config query %q is not satisfied", query))
+		}
+		return satisfied()
+	}}
+}
+
+// queryKind is the type of a value in a config query.
+type queryKind int
+
+const (
+	queryBool queryKind = iota
+	queryNumber
+	queryString
+	queryEnum
+	queryList
+)
+
+// queryValue is a value in a config query.
+type queryValue struct {
+	kind queryKind
+	b    bool
+	n    float64 // also holds the number of an enum value
+	s    string
+	enum protoreflect.EnumDescriptor
+	list []queryValue
+}
+
+func boolValue(b bool) queryValue { return queryValue{kind: queryBool, b: b} }
+
+// newQueryValue converts a singular proto field value to queryValue.
+func newQueryValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) (queryValue, error) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return boolValue(v.Bool()), nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return queryValue{kind: queryNumber, n: float64(v.Int())}, nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return queryValue{kind: queryNumber, n: float64(v.Uint())}, nil
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return queryValue{kind: queryNumber, n: v.Float()}, nil
+	case protoreflect.StringKind:
+		return queryValue{kind: queryString, s: v.String()}, nil
+	case protoreflect.EnumKind:
+		return queryValue{kind: queryEnum, n: float64(v.Enum()), enum: fd.Enum()}, nil
+	default:
+		return queryValue{}, errors.Errorf("field %s of kind %v cannot be used as a value", fd.Name(), fd.Kind())
+	}
+}
+
+// equal returns whether v and o are equal.
+func (v queryValue) equal(o queryValue) (bool, error) {
+	if v.kind == queryEnum && o.kind == queryString {
+		v, o = o, v
+	}
+	switch {
+	case v.kind == queryString && o.kind == queryEnum:
+		ev := o.enum.Values().ByName(protoreflect.Name(v.s))
+		if ev == nil {
+			return false, errors.Errorf("%s is not a value of enum %s", v.s, o.enum.FullName())
+		}
+		return float64(ev.Number()) == o.n, nil
+	case (v.kind == queryNumber || v.kind == queryEnum) && (o.kind == queryNumber || o.kind == queryEnum):
+		return v.n == o.n, nil
+	case v.kind != o.kind:
+		return false, errors.New("cannot compare values of different types")
+	case v.kind == queryBool:
+		return v.b == o.b, nil
+	case v.kind == queryString:
+		return v.s == o.s, nil
+	default:
+		return false, errors.New("cannot compare lists")
+	}
+}
+
+// compare returns a negative number, 0 or a positive number if v is less
+// than, equal to or greater than o, respectively.
+func (v queryValue) compare(o queryValue) (int, error) {
+	switch {
+	case v.kind == queryNumber && o.kind == queryNumber:
+		switch {
+		case v.n < o.n:
+			return -1, nil
+		case v.n > o.n:
+			return 1, nil
+		}
+		return 0, nil
+	case v.kind == queryString && o.kind == queryString:
+		return strings.Compare(v.s, o.s), nil
+	default:
+		return 0, errors.New("only numbers and strings can be ordered")
+	}
+}
+
+// queryNode is a node of a parsed config query.
+type queryNode interface {
+	eval(m protoreflect.Message) (queryValue, error)
+}
+
+type literalNode struct{ v queryValue }
+
+func (n *literalNode) eval(m protoreflect.Message) (queryValue, error) { return n.v, nil }
+
+type listNode struct{ elems []queryNode }
+
+func (n *listNode) eval(m protoreflect.Message) (queryValue, error) {
+	l := queryValue{kind: queryList}
+	for _, e := range n.elems {
+		v, err := e.eval(m)
+		if err != nil {
+			return queryValue{}, err
+		}
+		l.list = append(l.list, v)
+	}
+	return l, nil
+}
+
+// pathNode refers to a field of HardwareFeatures.
+type pathNode struct {
+	fields []protoreflect.FieldDescriptor
+}
+
+// parent returns the message containing the last field of the path.
+func (n *pathNode) parent(m protoreflect.Message) protoreflect.Message {
+	for _, fd := range n.fields[:len(n.fields)-1] {
+		m = m.Get(fd).Message()
+	}
+	return m
+}
+
+func (n *pathNode) eval(m protoreflect.Message) (queryValue, error) {
+	fd := n.fields[len(n.fields)-1]
+	v := n.parent(m).Get(fd)
+	if !fd.IsList() {
+		return newQueryValue(fd, v)
+	}
+	l := queryValue{kind: queryList}
+	for i := 0; i < v.List().Len(); i++ {
+		e, err := newQueryValue(fd, v.List().Get(i))
+		if err != nil {
+			return queryValue{}, err
+		}
+		l.list = append(l.list, e)
+	}
+	return l, nil
+}
+
+type hasNode struct{ path *pathNode }
+
+func (n *hasNode) eval(m protoreflect.Message) (queryValue, error) {
+	return boolValue(n.path.parent(m).Has(n.path.fields[len(n.path.fields)-1])), nil
+}
+
+type sizeNode struct{ path *pathNode }
+
+func (n *sizeNode) eval(m protoreflect.Message) (queryValue, error) {
+	fd := n.path.fields[len(n.path.fields)-1]
+	return queryValue{kind: queryNumber, n: float64(n.path.parent(m).Get(fd).List().Len())}, nil
+}
+
+type notNode struct{ x queryNode }
+
+func (n *notNode) eval(m protoreflect.Message) (queryValue, error) {
+	v, err := n.x.eval(m)
+	if err != nil {
+		return queryValue{}, err
+	}
+	if v.kind != queryBool {
+		return queryValue{}, errors.New("operand of ! is not a boolean")
+	}
+	return boolValue(!v.b), nil
+}
+
+// logicalNode is a node of && or ||, which are evaluated with
+// short-circuiting.
+type logicalNode struct {
+	and  bool
+	l, r queryNode
+}
+
+func (n *logicalNode) eval(m protoreflect.Message) (queryValue, error) {
+	for _, x := range []queryNode{n.l, n.r} {
+		v, err := x.eval(m)
+		if err != nil {
+			return queryValue{}, err
+		}
+		if v.kind != queryBool {
+			return queryValue{}, errors.New("operand of a logical operator is not a boolean")
+		}
+		if v.b != n.and {
+			return v, nil
+		}
+	}
+	return boolValue(n.and), nil
+}
+
+type binaryNode struct {
+	op   string
+	l, r queryNode
+}
+
+func (n *binaryNode) eval(m protoreflect.Message) (queryValue, error) {
+	l, err := n.l.eval(m)
+	if err != nil {
+		return queryValue{}, err
+	}
+	r, err := n.r.eval(m)
+	if err != nil {
+		return queryValue{}, err
+	}
+	switch n.op {
+	case "==", "!=":
+		eq, err := l.equal(r)
+		if err != nil {
+			return queryValue{}, err
+		}
+		return boolValue(eq == (n.op == "==")), nil
+	case "in":
+		if r.kind != queryList {
+			return queryValue{}, errors.New("right operand of in is not a list")
+		}
+		for _, e := range r.list {
+			eq, err := l.equal(e)
+			if err != nil {
+				return queryValue{}, err
+			}
+			if eq {
+				return boolValue(true), nil
+			}
+		}
+		return boolValue(false), nil
+	}
+	c, err := l.compare(r)
+	if err != nil {
+		return queryValue{}, err
+	}
+	switch n.op {
+	case "<":
+		return boolValue(c < 0), nil
+	case "<=":
+		return boolValue(c <= 0), nil
+	case ">":
+		return boolValue(c > 0), nil
+	default: // ">="
+		return boolValue(c >= 0), nil
+	}
+}
+
+// queryParser is a recursive descent parser of config queries.
+type queryParser struct {
+	toks []string
+	pos  int
+	root protoreflect.MessageDescriptor
+}
+
+// parseQuery parses a config query whose field paths are relative to root.
+func parseQuery(query string, root protoreflect.MessageDescriptor) (queryNode, error) {
+	toks, err := tokenizeQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	p := &queryParser{toks: toks, root: root}
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.toks) {
+		return nil, errors.Errorf("unexpected %q", p.toks[p.pos])
+	}
+	return n, nil
+}
+
+// tokenizeQuery splits a config query into tokens.
+func tokenizeQuery(query string) ([]string, error) {
+	var toks []string
+	for i := 0; i < len(query); {
+		c := rune(query[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '"' || c == '\'':
+			j := i + 1
+			for j < len(query) && rune(query[j]) != c {
+				if query[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(query) {
+				return nil, errors.New("unterminated string literal")
+			}
+			toks = append(toks, query[i:j+1])
+			i = j + 1
+		case c == '_' || unicode.IsLetter(c) || unicode.IsDigit(c):
+			j := i
+			for j < len(query) && (query[j] == '_' || query[j] == '.' && unicode.IsDigit(c) ||
+				unicode.IsLetter(rune(query[j])) || unicode.IsDigit(rune(query[j]))) {
+				j++
+			}
+			toks = append(toks, query[i:j])
+			i = j
+		default:
+			if i+1 < len(query) {
+				if op := query[i : i+2]; op == "==" || op == "!=" || op == "<=" || op == ">=" || op == "&&" || op == "||" {
+					toks = append(toks, op)
+					i += 2
+					continue
+				}
+			}
+			if !strings.ContainsRune("<>!()[],.-", c) {
+				return nil, errors.Errorf("unexpected character %q", c)
+			}
+			toks = append(toks, string(c))
+			i++
+		}
+	}
+	return toks, nil
+}
+
+func (p *queryParser) peek() string {
+	if p.pos < len(p.toks) {
+		return p.toks[p.pos]
+	}
+	return ""
+}
+
+func (p *queryParser) next() string {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *queryParser) expect(tok string) error {
+	if t := p.next(); t != tok {
+		return errors.Errorf("expected %q, got %q", tok, t)
+	}
+	return nil
+}
+
+func (p *queryParser) parseOr() (queryNode, error) {
+	l, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "||" {
+		p.next()
+		r, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l = &logicalNode{and: false, l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *queryParser) parseAnd() (queryNode, error) {
+	l, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "&&" {
+		p.next()
+		r, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l = &logicalNode{and: true, l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *queryParser) parseUnary() (queryNode, error) {
+	if p.peek() == "!" {
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{x: x}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *queryParser) parseComparison() (queryNode, error) {
+	l, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	switch op := p.peek(); op {
+	case "==", "!=", "<", "<=", ">", ">=", "in":
+		p.next()
+		r, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		return &binaryNode{op: op, l: l, r: r}, nil
+	}
+	return l, nil
+}
+
+func (p *queryParser) parsePrimary() (queryNode, error) {
+	tok := p.next()
+	switch {
+	case tok == "":
+		return nil, errors.New("unexpected end of query")
+	case tok == "(":
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return n, nil
+	case tok == "[":
+		l := &listNode{}
+		for p.peek() != "]" {
+			if len(l.elems) > 0 {
+				if err := p.expect(","); err != nil {
+					return nil, err
+				}
+			}
+			e, err := p.parsePrimary()
+			if err != nil {
+				return nil, err
+			}
+			l.elems = append(l.elems, e)
+		}
+		p.next()
+		return l, nil
+	case tok == "-":
+		n, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		lit, ok := n.(*literalNode)
+		if !ok || lit.v.kind != queryNumber {
+			return nil, errors.New("- must be followed by a number")
+		}
+		return &literalNode{queryValue{kind: queryNumber, n: -lit.v.n}}, nil
+	case tok == "true" || tok == "false":
+		return &literalNode{boolValue(tok == "true")}, nil
+	case tok[0] == '"' || tok[0] == '\'':
+		if tok[0] == '\'' {
+			tok = `"` + strings.ReplaceAll(tok[1:len(tok)-1], `"`, `\"`) + `"`
+		}
+		s, err := strconv.Unquote(tok)
+		if err != nil {
+			return nil, errors.Errorf("invalid string literal %s", tok)
+		}
+		return &literalNode{queryValue{kind: queryString, s: s}}, nil
+	case unicode.IsDigit(rune(tok[0])):
+		f, err := strconv.ParseFloat(tok, 64)
+		if err != nil {
+			return nil, errors.Errorf("invalid number %s", tok)
+		}
+		return &literalNode{queryValue{kind: queryNumber, n: f}}, nil
+	case (tok == "has" || tok == "size") && p.peek() == "(":
+		p.next()
+		path, err := p.parsePath(p.next())
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		fd := path.fields[len(path.fields)-1]
+		if tok == "has" {
+			if fd.IsList() || fd.Message() == nil {
+				return nil, errors.Errorf("has() requires a singular message field, but %s is not", fd.Name())
+			}
+			return &hasNode{path: path}, nil
+		}
+		if !fd.IsList() {
+			return nil, errors.Errorf("size() requires a repeated field, but %s is not", fd.Name())
+		}
+		return &sizeNode{path: path}, nil
+	default:
+		return p.parsePath(tok)
+	}
+}
+
+// parsePath parses a field path starting with the identifier tok.
+func (p *queryParser) parsePath(tok string) (*pathNode, error) {
+	path := &pathNode{}
+	md := p.root
+	for {
+		if md == nil {
+			return nil, errors.Errorf("%s is not a message field", path.fields[len(path.fields)-1].Name())
+		}
+		fd := md.Fields().ByName(protoreflect.Name(tok))
+		if fd == nil {
+			return nil, errors.Errorf("%s has no field %q", md.FullName(), tok)
+		}
+		if len(path.fields) > 0 && path.fields[len(path.fields)-1].IsList() {
+			return nil, errors.Errorf("cannot access fields of repeated field %s", path.fields[len(path.fields)-1].Name())
+		}
+		if fd.IsMap() {
+			return nil, errors.Errorf("map field %s is not supported", fd.Name())
+		}
+		path.fields = append(path.fields, fd)
+		if p.peek() != "." {
+			return path, nil
+		}
+		p.next()
+		md = fd.Message()
+		tok = p.next()
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package hwdep_test
+
+import (
+	"testing"
+
+	configpb "go.chromium.org/chromiumos/config/go/api"
+
+	"go.chromium.org/tast/core/testing/hwdep"
+)
+
+func TestFromConfigQuery(t *testing.T) {
+	features := &configpb.HardwareFeatures{
+		Screen: &configpb.HardwareFeatures_Screen{
+			PanelProperties: &configpb.Component_DisplayPanel_Properties{
+				WidthPx:  2560,
+				HeightPx: 1600,
+			},
+		},
+		Keyboard: &configpb.HardwareFeatures_Keyboard{
+			Backlight: configpb.HardwareFeatures_PRESENT,
+		},
+		Camera: &configpb.HardwareFeatures_Camera{
+			Features: []string{"hdrnet", "face_detection"},
+		},
+		Wifi: &configpb.HardwareFeatures_Wifi{
+			SupportedWlanProtocols: []configpb.Component_Wifi_WLANProtocol{
+				configpb.Component_Wifi_IEEE_802_11_N,
+				configpb.Component_Wifi_IEEE_802_11_AC,
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		query           string
+		expectSatisfied bool
+	}{
+		{`screen.panel_properties.width_px >= 2560`, true},
+		{`screen.panel_properties.width_px > 2560`, false},
+		{`screen.panel_properties.width_px >= 2560 && keyboard.backlight == "PRESENT"`, true},
+		{`keyboard.backlight != 'PRESENT' || screen.panel_properties.height_px < 1000`, false},
+		{`!(keyboard.power_button == "PRESENT")`, true},
+		{`keyboard.backlight in ["NOT_PRESENT", "PRESENT"]`, true},
+		{`"hdrnet" in camera.features`, true},
+		{`"auto_framing" in camera.features`, false},
+		{`size(camera.features) == 2`, true},
+		{`"IEEE_802_11_AX" in wifi.supported_wlan_protocols`, false},
+		{`"IEEE_802_11_AC" in wifi.supported_wlan_protocols`, true},
+		{`has(screen.panel_properties)`, true},
+		{`has(stylus)`, false},
+		{`screen.panel_properties.width_px > -1 && true`, true},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			verifyCondition(t, hwdep.FromConfigQuery(tc.query), nil, features, tc.expectSatisfied)
+		})
+	}
+}
+
+func TestFromConfigQueryInvalid(t *testing.T) {
+	for _, query := range []string{
+		``,
+		`no_such_field == 1`,
+		`screen.no_such_field == 1`,
+		`screen.panel_properties.width_px >=`,
+		`(keyboard.backlight == "PRESENT"`,
+		`camera.devices.id == "a"`,
+		`size(screen)`,
+		`has(camera.features)`,
+		`"unterminated`,
+		`screen.panel_properties.width_px @ 1`,
+	} {
+		if c := hwdep.FromConfigQuery(query); c.Err == nil {
+			t.Errorf("FromConfigQuery(%q) unexpectedly succeeded", query)
+		}
+	}
+}
+
+func TestFromConfigQueryEvalError(t *testing.T) {
+	features := &configpb.HardwareFeatures{
+		Keyboard: &configpb.HardwareFeatures_Keyboard{
+			Backlight: configpb.HardwareFeatures_PRESENT,
+		},
+	}
+	for _, query := range []string{
+		`keyboard.backlight == "NO_SUCH_VALUE"`,
+		`keyboard.backlight`,
+		`keyboard.backlight == "PRESENT" && 1`,
+		`keyboard.backlight < "PRESENT"`,
+	} {
+		expectError(t, hwdep.FromConfigQuery(query), nil, features)
+	}
+	expectError(t, hwdep.FromConfigQuery(`has(keyboard)`), nil, nil)
+}
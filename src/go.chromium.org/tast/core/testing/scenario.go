@@ -0,0 +1,66 @@
+// Copyright 2024 The ChromiumOS Authors
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package testing
+
+import (
+	"context"
+
+	"go.chromium.org/tast/core/internal/caller"
+	"go.chromium.org/tast/core/internal/testing"
+)
+
+// ScenarioStep is a step of a scenario test registered with AddScenario.
+// T is the type of the state object shared by all steps of the scenario.
+type ScenarioStep[T any] struct {
+	// Name is the name of the step. It should consist of lowercase letters,
+	// digits and underscores, and be unique in the scenario.
+	Name string
+	// Func performs the step. state points to the object passed to
+	// RunScenario, which carries results of earlier steps.
+	Func func(ctx context.Context, s *State, state *T)
+}
+
+// AddScenario adds scenario test t to the global registry.
+//
+// A scenario test runs steps in order as a single test, and results of steps
+// are reported separately. It suits end-to-end flows consisting of multiple
+// stages (e.g. enroll, configure and verify) where later stages depend on
+// earlier ones. t.Func should initialize the state object shared by steps and
+// call RunScenario with it, and can clean up the state after RunScenario
+// returns.
+//
+// Like AddTest, this should be called only once in a test main file's init(),
+// and it should be the top level statement of the init()'s body.
+func AddScenario[T any](t *Test, steps ...ScenarioStep[T]) {
+	if err := verifier.verifyAndRegister(caller.Func(2)); err != nil {
+		testing.GlobalRegistry().RecordError(err)
+		return
+	}
+	var internalSteps []testing.ScenarioStep
+	for _, step := range steps {
+		step := step
+		internalSteps = append(internalSteps, testing.ScenarioStep{
+			Name: step.Name,
+			Func: func(ctx context.Context, s *State, state interface{}) {
+				st, ok := state.(*T)
+				if !ok {
+					s.Fatalf("Scenario state is %T; want %T", state, st)
+				}
+				step.Func(ctx, s, st)
+			},
+		})
+	}
+	testing.SetScenarioSteps(t, internalSteps)
+	testing.GlobalRegistry().AddTest(t)
+}
+
+// RunScenario runs steps of the scenario test being run by s in order, passing
+// state to each of them. Once a step fails, remaining steps are skipped. It
+// returns true if all steps passed.
+//
+// It panics if the test was not registered with AddScenario.
+func RunScenario[T any](ctx context.Context, s *State, state *T) bool {
+	return testing.RunScenario(ctx, s, state)
+}
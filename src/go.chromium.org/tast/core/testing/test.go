@@ -65,3 +65,14 @@ type StringPair = protocol.StringPair
 // TastRootRemoteFixtureName is the name of the root remote fixture which will be run
 // when a bundle start.
 const TastRootRemoteFixtureName = testing.TastRootRemoteFixtureName
+
+const (
+	// ScreenshotOnFailurePrivateAttr is a private attribute to take a
+	// screenshot of the DUT's screen into the output directory when a local
+	// test reports its first error.
+	ScreenshotOnFailurePrivateAttr = testing.ScreenshotOnFailurePrivateAttr
+	// ScreenRecordOnFailurePrivateAttr is a private attribute to record the
+	// DUT's screen while a local test runs, and save the recording up to its
+	// first error as an animated GIF in the output directory.
+	ScreenRecordOnFailurePrivateAttr = testing.ScreenRecordOnFailurePrivateAttr
+)
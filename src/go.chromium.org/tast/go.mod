@@ -8,6 +8,7 @@ require (
 	github.com/golang/protobuf v1.5.3
 	github.com/google/go-cmp v0.5.9
 	github.com/google/subcommands v1.2.0
+	github.com/klauspost/compress v1.17.11
 	github.com/shirou/gopsutil/v3 v3.23.5
 	go.chromium.org/chromiumos/config/go v0.0.0-20240117201416-64f45f1e62a6
 	golang.org/x/crypto v0.12.0
@@ -24,4 +24,7 @@ fi
 
 popd
 
-exec "${tast_root}/bin/tast-lint" "$@"
+# Cache issues across runs so that only changed packages are checked again.
+readonly cache_file="${XDG_CACHE_HOME:-${HOME}/.cache}/tast/tast-lint-cache"
+
+exec "${tast_root}/bin/tast-lint" -cache="${cache_file}" "$@"